@@ -3,6 +3,8 @@ package api
 import (
 	"sync"
 	"time"
+
+	"github.com/ravinald/wifimgr/internal/runstats"
 )
 
 // cacheItem represents an item in the cache with expiration
@@ -46,6 +48,7 @@ func (c *cache[T]) Get(key string) (T, bool) {
 	c.mutex.RUnlock()
 
 	if !found {
+		runstats.CacheMiss("Mist in-memory")
 		var zero T
 		return zero, false
 	}
@@ -55,10 +58,12 @@ func (c *cache[T]) Get(key string) (T, bool) {
 		c.mutex.Lock()
 		delete(c.items, key)
 		c.mutex.Unlock()
+		runstats.CacheMiss("Mist in-memory")
 		var zero T
 		return zero, false
 	}
 
+	runstats.CacheHit("Mist in-memory")
 	return item.data, true
 }
 
@@ -2,12 +2,14 @@ package api
 
 import (
 	"context"
+	"encoding/json"
 	"net"
 	"net/http"
 	"sync"
 	"time"
 
 	"github.com/ravinald/wifimgr/internal/logging"
+	"github.com/ravinald/wifimgr/internal/runstats"
 )
 
 // Client is the interface for Mist API operations
@@ -66,11 +68,17 @@ type Client interface {
 	GetDeviceProfileByName(ctx context.Context, orgID string, name string, profileType string) (*DeviceProfile, error)
 	AssignDeviceProfile(ctx context.Context, orgID string, profileID string, macs []string) (*DeviceProfileAssignResult, error)
 	UnassignDeviceProfiles(ctx context.Context, orgID string, profileID string, macs []string) error
+	DeleteDeviceProfile(ctx context.Context, orgID string, profileID string) error
 
 	// Templates and Networks
 	GetRFTemplates(ctx context.Context, orgID string) ([]MistRFTemplate, error)
+	DeleteRFTemplate(ctx context.Context, orgID string, templateID string) error
 	GetGatewayTemplates(ctx context.Context, orgID string) ([]MistGatewayTemplate, error)
+	GetGatewayTemplatesRaw(ctx context.Context, orgID string) ([]map[string]interface{}, error)
+	CreateGatewayTemplate(ctx context.Context, orgID string, template map[string]interface{}) (map[string]interface{}, error)
+	UpdateGatewayTemplate(ctx context.Context, orgID, templateID string, template map[string]interface{}) (map[string]interface{}, error)
 	GetWLANTemplates(ctx context.Context, orgID string) ([]MistWLANTemplate, error)
+	DeleteWLANTemplate(ctx context.Context, orgID string, templateID string) error
 	GetNetworks(ctx context.Context, orgID string) ([]MistNetwork, error)
 	GetWLANs(ctx context.Context, orgID string) ([]MistWLAN, error)
 	GetSiteWLANs(ctx context.Context, siteID string) ([]MistWLAN, error)
@@ -101,10 +109,16 @@ type Client interface {
 	// Cache operations
 	// Note: GetCacheAccessor() removed. Use vendors.GetGlobalCacheAccessor() instead.
 	GetDeviceCache() *DeviceCache
+	ClearCache(cacheType string)
+	ClearCacheForSite(siteID string, cacheType string)
 
 	// Raw data operations for detail view
 	GetRawDeviceJSON(ctx context.Context, siteID, deviceID string) (string, error)
 
+	// Unmodeled endpoints, for the api command
+	GetRaw(ctx context.Context, path string) (json.RawMessage, error)
+	PutRaw(ctx context.Context, path string, body json.RawMessage) (json.RawMessage, error)
+
 	// Device extensive information query
 	QueryDeviceExtensive(ctx context.Context, siteID, deviceID string) error
 
@@ -113,10 +127,35 @@ type Client interface {
 
 	// Stats API
 	GetAPStats(ctx context.Context, siteID string) ([]map[string]interface{}, error)
+	GetSwitchStats(ctx context.Context, siteID string) ([]map[string]interface{}, error)
+
+	// Firmware upgrade for devices at a site
+	UpgradeDevices(ctx context.Context, siteID string, deviceIDs []string, version string) error
+
+	// Site floor plans
+	GetSiteMaps(ctx context.Context, siteID string) ([]map[string]interface{}, error)
+
+	// Virtual chassis status for a switch
+	GetVirtualChassis(ctx context.Context, siteID, deviceID string) (map[string]interface{}, error)
+
+	// EVPN topology (campus fabric) API; scope is EVPNScopeSite or EVPNScopeOrg
+	GetEVPNTopologies(ctx context.Context, scope, ownerID string) ([]map[string]interface{}, error)
+	CreateEVPNTopology(ctx context.Context, scope, ownerID string, topology map[string]interface{}) (map[string]interface{}, error)
+	UpdateEVPNTopology(ctx context.Context, scope, ownerID, topologyID string, topology map[string]interface{}) (map[string]interface{}, error)
 
 	// Search API
 	SearchWiredClients(ctx context.Context, orgID string, text string) (*MistWiredClientResponse, error)
 	SearchWirelessClients(ctx context.Context, orgID string, text string) (*MistWirelessClientResponse, error)
+	SearchClientEvents(ctx context.Context, orgID, mac string, start, end time.Time) ([]map[string]interface{}, error)
+	SearchSiteDeviceEvents(ctx context.Context, siteID string, types []string, start, end time.Time) ([]map[string]interface{}, error)
+
+	// Security events API
+	SearchSiteRogueEvents(ctx context.Context, siteID string, start, end time.Time) ([]map[string]interface{}, error)
+	SearchSiteAlarms(ctx context.Context, siteID, group string, start, end time.Time) ([]map[string]interface{}, error)
+
+	// Usage API
+	SearchSiteClientSessions(ctx context.Context, siteID string, start, end time.Time) ([]map[string]interface{}, error)
+	GetSiteInsightMetric(ctx context.Context, siteID, metric string, start, end time.Time, interval int) (map[string]interface{}, error)
 
 	// Device Configuration API
 	GetDeviceConfig(ctx context.Context, siteID, deviceID string) (*DeviceConfigResponse, error)
@@ -133,17 +172,19 @@ type Config struct {
 	Organization string
 	Timeout      time.Duration
 	Debug        bool
-	RateLimit    int           // Requests per minute (0 = no limit)
-	RateDuration time.Duration // Duration for rate limiting
-	CacheTTL     time.Duration // Cache TTL (0 = default 5 minutes)
-	ResultsLimit int           // Maximum results per API call
-	HTTPClient   *http.Client  // Custom HTTP client
-	MaxRetries   int           // Maximum number of retry attempts (0 = no retries)
-	RetryBackoff time.Duration // Initial backoff duration for retries (0 = default 250ms)
-	LocalCache   string        // Path to local cache file for ID mappings
-	OrgID        string        // Organization ID for cache operations
-	Inventory    string        // Path to inventory file
-	DryRun       bool          // When true, don't make actual changes via API
+	RateLimit    int            // Requests per minute (0 = no limit)
+	RateDuration time.Duration  // Duration for rate limiting
+	CacheTTL     time.Duration  // Cache TTL (0 = default 5 minutes)
+	ResultsLimit int            // Maximum results per API call
+	PageSizes    map[string]int // Per-endpoint page sizes (keys: Endpoint* constants)
+	Pagination   string         // Pagination strategy (Pagination* constants; empty = auto)
+	HTTPClient   *http.Client   // Custom HTTP client
+	MaxRetries   int            // Maximum number of retry attempts (0 = no retries)
+	RetryBackoff time.Duration  // Initial backoff duration for retries (0 = default 250ms)
+	LocalCache   string         // Path to local cache file for ID mappings
+	OrgID        string         // Organization ID for cache operations
+	Inventory    string         // Path to inventory file
+	DryRun       bool           // When true, don't make actual changes via API
 }
 
 // mistClient implements the Client interface for the Mist API
@@ -156,6 +197,7 @@ type mistClient struct {
 	deviceCache        *cache[[]Device] // Universal device cache for all device types
 	inventoryCache     *cache[[]InventoryItem]
 	deviceProfileCache *cache[[]DeviceProfile]
+	devices            *DeviceCache // in-memory device configs; owned by this client
 	cacheDirectory     string
 	debug              bool
 	dryRun             bool
@@ -255,6 +297,17 @@ func WithCacheTTL(ttl time.Duration) ClientOption {
 	}
 }
 
+// WithDeviceCache sets the client's in-memory device cache, e.g. one built
+// with WithSourceGeneration so it yields to a newer file cache. Without it
+// each client gets a fresh, untracked cache.
+func WithDeviceCache(dc *DeviceCache) ClientOption {
+	return func(c *mistClient) {
+		if dc != nil {
+			c.devices = dc
+		}
+	}
+}
+
 // WithResultsLimit sets the results limit for pagination
 func WithResultsLimit(limit int) ClientOption {
 	return func(c *mistClient) {
@@ -325,6 +378,7 @@ func NewClient(config Config) Client {
 	client.deviceCache = newCache[[]Device](cacheTTL)
 	client.inventoryCache = newCache[[]InventoryItem](cacheTTL)
 	client.deviceProfileCache = newCache[[]DeviceProfile](cacheTTL)
+	client.devices = NewDeviceCache()
 
 	// Legacy cache operations disabled - cache system modernized
 
@@ -349,6 +403,7 @@ func NewClientWithOptions(apiToken, baseURL, orgID string, options ...ClientOpti
 		deviceCache:        newCache[[]Device](5 * time.Minute),
 		inventoryCache:     newCache[[]InventoryItem](5 * time.Minute),
 		deviceProfileCache: newCache[[]DeviceProfile](5 * time.Minute),
+		devices:            NewDeviceCache(),
 		debug:              false,
 		dryRun:             false,
 		maxRetries:         3,
@@ -359,6 +414,8 @@ func NewClientWithOptions(apiToken, baseURL, orgID string, options ...ClientOpti
 	for _, option := range options {
 		option(client)
 	}
+	// Instrument last: WithConnectTimeout type-asserts the transport
+	client.httpClient = runstats.InstrumentClient(client.httpClient)
 
 	return client
 }
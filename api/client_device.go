@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 
 	"github.com/ravinald/wifimgr/internal/logging"
 	"github.com/ravinald/wifimgr/internal/macaddr"
@@ -22,15 +23,15 @@ import (
 // GetDevices retrieves all devices of a specific type for a site using the new bidirectional pattern
 func (c *mistClient) GetDevices(ctx context.Context, siteID string, deviceType string) ([]UnifiedDevice, error) {
 	// Check in-memory device cache first if it's initialized
-	if deviceCache != nil {
+	if c.devices != nil {
 		var cachedDevices []UnifiedDevice
 
 		if deviceType == "" || deviceType == "all" {
 			// Get all devices for the site
-			cachedDevices = deviceCache.GetDevicesBySite(siteID)
+			cachedDevices = c.devices.GetDevicesBySite(siteID)
 		} else {
 			// Get devices of specific type for the site
-			cachedDevices = deviceCache.GetDevicesBySiteAndType(siteID, deviceType)
+			cachedDevices = c.devices.GetDevicesBySiteAndType(siteID, deviceType)
 		}
 
 		if len(cachedDevices) > 0 {
@@ -50,12 +51,7 @@ func (c *mistClient) GetDevices(ctx context.Context, siteID string, deviceType s
 
 	c.logDebug("Getting %s devices for site %s from API using new bidirectional pattern", deviceType, siteID)
 
-	// Determine the results limit to use
-	limit := 100 // Default value
-	if c.config.ResultsLimit > 0 {
-		limit = c.config.ResultsLimit
-		c.logDebug("Using configured results limit: %d", limit)
-	}
+	limit := c.resultsLimit(EndpointDevices)
 
 	var devices []UnifiedDevice
 	page := 1
@@ -117,9 +113,9 @@ func (c *mistClient) GetDevices(ctx context.Context, siteID string, deviceType s
 	c.logDebug("Total devices retrieved: %d", len(devices))
 
 	// Populate device cache with the fetched devices
-	if deviceCache != nil {
+	if c.devices != nil {
 		for _, device := range devices {
-			deviceCache.AddDevice(device)
+			c.devices.AddDevice(device)
 		}
 		c.logDebug("Added %d devices to device cache", len(devices))
 	}
@@ -136,9 +132,9 @@ func (c *mistClient) GetDeviceByMAC(_ context.Context, mac string) (*UnifiedDevi
 	}
 	c.logDebug("Getting device by MAC: %s (normalized: %s)", mac, normalizedMAC)
 
-	// OPTIMIZATION: Check in-memory deviceCache FIRST (most likely to have current data)
-	if deviceCache != nil {
-		if cachedDevice, found := deviceCache.GetDeviceByMAC(normalizedMAC); found {
+	// OPTIMIZATION: Check the in-memory device cache FIRST (most likely to have current data)
+	if c.devices != nil {
+		if cachedDevice, found := c.devices.GetDeviceByMAC(normalizedMAC); found {
 			c.logDebug("Found device in memory cache for MAC %s", normalizedMAC)
 			return &cachedDevice, nil
 		}
@@ -198,8 +194,8 @@ func (c *mistClient) UpdateDevice(ctx context.Context, siteID string, deviceID s
 	}
 
 	// Update the in-memory device cache with the complete device config
-	if deviceCache != nil && updatedDevice.MAC != nil {
-		deviceCache.AddDevice(*updatedDevice)
+	if c.devices != nil && updatedDevice.MAC != nil {
+		c.devices.AddDevice(*updatedDevice)
 		c.logDebug("Updated device %s in in-memory cache", *updatedDevice.MAC)
 	}
 
@@ -428,8 +424,8 @@ func (c *mistClient) DeleteDevicesFromSite(ctx context.Context, macs []string) e
 	}
 
 	// Invalidate cache for all device types
-	if deviceCache != nil {
-		deviceCache.Clear()
+	if c.devices != nil {
+		c.devices.Clear()
 	}
 
 	return nil
@@ -467,7 +463,9 @@ func (c *mistClient) GetRawDeviceJSON(ctx context.Context, siteID, deviceID stri
 	req.Header.Set("Authorization", fmt.Sprintf("Token %s", c.config.APIToken))
 
 	if c.rateLimiter != nil {
-		c.rateLimiter.wait()
+		if err := c.rateLimiter.Wait(ctx); err != nil {
+			return "", err
+		}
 	}
 
 	resp, err := c.httpClient.Do(req) // #nosec G704 -- URL from trusted config, not user input
@@ -489,22 +487,9 @@ func (c *mistClient) GetRawDeviceJSON(ctx context.Context, siteID, deviceID stri
 	return buf.String(), nil
 }
 
-// Global device cache instance
-var deviceCache *DeviceCache
-
-// InitializeDeviceCache initializes the global device cache
-func InitializeDeviceCache() {
-	if deviceCache == nil {
-		deviceCache = NewDeviceCache()
-	}
-}
-
-// GetDeviceCache returns the global device cache instance
+// GetDeviceCache returns the client's in-memory device cache
 func (c *mistClient) GetDeviceCache() *DeviceCache {
-	if deviceCache == nil {
-		InitializeDeviceCache()
-	}
-	return deviceCache
+	return c.devices
 }
 
 // ClearCache clears specific cache types or all caches
@@ -515,53 +500,49 @@ func (c *mistClient) GetDeviceCache() *DeviceCache {
 //   - "configs" - clears all device config caches
 //   - "configs-ap", "configs-switch", "configs-gateway" - clears specific device config type
 //   - "devices" - clears device cache (alias for configs)
-func ClearCache(cacheType string) {
+func (c *mistClient) ClearCache(cacheType string) {
 	switch cacheType {
 	case "all":
 		// Clear all cache types - both inventory and configs
-		if deviceCache != nil {
-			deviceCache.Clear()
+		if c.devices != nil {
+			c.devices.Clear()
 		}
-		// Clear inventory caches for all clients
-		if client := GetClient(); client != nil {
-			if mc, ok := client.(*mistClient); ok {
-				if mc.inventoryCache != nil {
-					mc.inventoryCache.Clear()
-				}
-			}
+		// Clear inventory caches
+		if c.inventoryCache != nil {
+			c.inventoryCache.Clear()
 		}
 
 	// Device configs (from /sites/{site_id}/devices API)
 	case "configs", "devices", "deviceconfigs":
 		// Clear device config cache (all types)
-		if deviceCache != nil {
-			deviceCache.Clear()
+		if c.devices != nil {
+			c.devices.Clear()
 		}
 	case "configs-ap":
 		// Clear only AP configs from device cache
-		if deviceCache != nil {
+		if c.devices != nil {
 			// Remove all APs from cache
-			for _, device := range deviceCache.GetDevicesByType("ap") {
+			for _, device := range c.devices.GetDevicesByType("ap") {
 				if device.MAC != nil {
-					deviceCache.RemoveDevice(*device.MAC)
+					c.devices.RemoveDevice(*device.MAC)
 				}
 			}
 		}
 	case "configs-switch":
 		// Clear only switch configs from device cache
-		if deviceCache != nil {
-			for _, device := range deviceCache.GetDevicesByType("switch") {
+		if c.devices != nil {
+			for _, device := range c.devices.GetDevicesByType("switch") {
 				if device.MAC != nil {
-					deviceCache.RemoveDevice(*device.MAC)
+					c.devices.RemoveDevice(*device.MAC)
 				}
 			}
 		}
 	case "configs-gateway":
 		// Clear only gateway configs from device cache
-		if deviceCache != nil {
-			for _, device := range deviceCache.GetDevicesByType("gateway") {
+		if c.devices != nil {
+			for _, device := range c.devices.GetDevicesByType("gateway") {
 				if device.MAC != nil {
-					deviceCache.RemoveDevice(*device.MAC)
+					c.devices.RemoveDevice(*device.MAC)
 				}
 			}
 		}
@@ -569,74 +550,67 @@ func ClearCache(cacheType string) {
 	// Inventory (from /orgs/{org_id}/inventory API)
 	case "inventory":
 		// Clear all inventory caches
-		if client := GetClient(); client != nil {
-			if mc, ok := client.(*mistClient); ok {
-				if mc.inventoryCache != nil {
-					mc.inventoryCache.Clear()
-				}
-			}
+		if c.inventoryCache != nil {
+			c.inventoryCache.Clear()
 		}
 	case "inventory-ap", "inventory-switch", "inventory-gateway":
-		// Clear specific inventory type from cache
-		if client := GetClient(); client != nil {
-			if mc, ok := client.(*mistClient); ok && mc.inventoryCache != nil {
-				// Since we don't have a method to remove specific keys, clear all inventory
-				// This is acceptable since inventory is relatively small
-				mc.inventoryCache.Clear()
-			}
+		// Since we don't have a method to remove specific keys, clear all inventory
+		// This is acceptable since inventory is relatively small
+		if c.inventoryCache != nil {
+			c.inventoryCache.Clear()
 		}
 	}
 }
 
 // ClearCacheForSite clears cache entries for a specific site
-func ClearCacheForSite(siteID string, cacheType string) {
+func (c *mistClient) ClearCacheForSite(siteID string, cacheType string) {
 	switch cacheType {
 	case "all":
 		// Clear both configs and inventory for the site
-		ClearCacheForSite(siteID, "configs")
+		c.ClearCacheForSite(siteID, "configs")
 		// Inventory is org-level but we clear it for completeness
-		ClearCache("inventory")
+		c.ClearCache("inventory")
 
 	case "configs", "devices", "deviceconfigs":
 		// Clear all device configs for the site
-		if deviceCache != nil {
-			devices := deviceCache.GetDevicesBySite(siteID)
+		if c.devices != nil {
+			devices := c.devices.GetDevicesBySite(siteID)
 			for _, device := range devices {
 				if device.MAC != nil {
-					deviceCache.RemoveDevice(*device.MAC)
+					c.devices.RemoveDevice(*device.MAC)
 				}
 			}
 		}
 
 	case "configs-ap":
 		// Clear only AP configs for the site
-		if deviceCache != nil {
-			devices := deviceCache.GetDevicesBySiteAndType(siteID, "ap")
+		if c.devices != nil {
+			devices := c.devices.GetDevicesBySiteAndType(siteID, "ap")
 			for _, device := range devices {
 				if device.MAC != nil {
-					deviceCache.RemoveDevice(*device.MAC)
+					c.devices.RemoveDevice(*device.MAC)
 				}
 			}
 		}
 
 	case "configs-switch":
 		// Clear only switch configs for the site
-		if deviceCache != nil {
-			devices := deviceCache.GetDevicesBySiteAndType(siteID, "switch")
+		if c.devices != nil {
+			devices := c.devices.GetDevicesBySiteAndType(siteID, "switch")
 			for _, device := range devices {
 				if device.MAC != nil {
-					deviceCache.RemoveDevice(*device.MAC)
+					c.devices.RemoveDevice(*device.MAC)
 				}
 			}
 		}
 
 	case "configs-gateway":
 		// Clear only gateway configs for the site
-		if deviceCache != nil {
-			devices := deviceCache.GetDevicesBySiteAndType(siteID, "gateway")
+		if c.devices != nil {
+			devices := c.devices.GetDevicesBySiteAndType(siteID, "gateway")
 			for _, device := range devices {
 				if device.MAC != nil {
-					deviceCache.RemoveDevice(*device.MAC)
+					c.devices.RemoveDevice(*device.MAC)
 				}
 			}
 		}
@@ -644,11 +618,18 @@ func ClearCacheForSite(siteID string, cacheType string) {
 	// Note: Inventory is org-level, not site-level, so we can't clear it per site
 	case "inventory", "inventory-ap", "inventory-switch", "inventory-gateway":
 		// Inventory is managed at org level, so we clear all inventory
-		ClearCache(cacheType)
+		c.ClearCache(cacheType)
 	}
 }
 
-// ForceRebuildCache forces a complete rebuild of the cache
+// forceRebuildConcurrency caps how many data types ForceRebuildCache fetches
+// at once. Every request still passes through the client's rate limiter, so
+// this bounds open requests, not the request rate.
+const forceRebuildConcurrency = 4
+
+// ForceRebuildCache forces a complete rebuild of the cache. Sites are fetched
+// first, since several data types iterate them; the rest are fetched
+// concurrently.
 func (c *mistClient) ForceRebuildCache(ctx context.Context) error {
 	// Get organization ID
 	orgID := c.config.Organization
@@ -662,74 +643,128 @@ func (c *mistClient) ForceRebuildCache(ctx context.Context) error {
 	// This avoids import cycles while still providing the functionality
 	dataTypes := []string{"sites", "sitesettings", "inventory-ap", "inventory-switch", "inventory-gateway", "deviceprofiles", "rftemplates", "gatewaytemplates", "wlantemplates", "networks", "wlans", "device-configs"}
 
-	for _, dataType := range dataTypes {
-		c.logDebug("Refreshing %s data...", dataType)
-
-		var err error
-		switch dataType {
-		case "sites":
-			_, err = c.GetSites(ctx, orgID)
-		case "sitesettings":
-			// Site settings require iterating through sites
-			sites, siteErr := c.GetSites(ctx, orgID)
-			if siteErr != nil {
-				err = fmt.Errorf("failed to get sites for settings refresh: %w", siteErr)
-			} else {
-				for _, site := range sites {
-					if site.ID != nil {
-						_, settingErr := c.GetSiteSetting(ctx, *site.ID)
-						if settingErr != nil {
-							c.logDebug("Warning: failed to refresh settings for site %s: %v", *site.ID, settingErr)
-						}
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("cache rebuild interrupted after 0/%d data types (not refreshed: %s): %w",
+			len(dataTypes), strings.Join(dataTypes, ", "), err)
+	}
+	c.refreshDataType(ctx, orgID, dataTypes[0])
+
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		done = map[string]bool{dataTypes[0]: ctx.Err() == nil}
+		sem  = make(chan struct{}, forceRebuildConcurrency)
+	)
+	for _, dataType := range dataTypes[1:] {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(dataType string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			c.refreshDataType(ctx, orgID, dataType)
+			// A cancellation mid-type leaves it partial.
+			if ctx.Err() == nil {
+				mu.Lock()
+				done[dataType] = true
+				mu.Unlock()
+			}
+		}(dataType)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		var pending []string
+		for _, dataType := range dataTypes {
+			if !done[dataType] {
+				pending = append(pending, dataType)
+			}
+		}
+		return fmt.Errorf("cache rebuild interrupted after %d/%d data types (not refreshed: %s): %w",
+			len(dataTypes)-len(pending), len(dataTypes), strings.Join(pending, ", "), err)
+	}
+
+	c.logDebug("Cache force rebuild completed successfully")
+	return nil
+}
+
+// refreshDataType fetches one ForceRebuildCache data type. Failures are
+// logged and not returned, so one data type cannot stop the others.
+func (c *mistClient) refreshDataType(ctx context.Context, orgID, dataType string) {
+	c.logDebug("Refreshing %s data...", dataType)
+
+	var err error
+	switch dataType {
+	case "sites":
+		_, err = c.GetSites(ctx, orgID)
+	case "sitesettings":
+		// Site settings require iterating through sites
+		sites, siteErr := c.GetSites(ctx, orgID)
+		if siteErr != nil {
+			err = fmt.Errorf("failed to get sites for settings refresh: %w", siteErr)
+		} else {
+			for _, site := range sites {
+				if ctx.Err() != nil {
+					break
+				}
+				if site.ID != nil {
+					_, settingErr := c.GetSiteSetting(ctx, *site.ID)
+					if settingErr != nil {
+						c.logDebug("Warning: failed to refresh settings for site %s: %v", *site.ID, settingErr)
 					}
 				}
 			}
-		case "inventory-ap":
-			_, err = c.GetInventory(ctx, orgID, "ap")
-		case "inventory-switch":
-			_, err = c.GetInventory(ctx, orgID, "switch")
-		case "inventory-gateway":
-			_, err = c.GetInventory(ctx, orgID, "gateway")
-		case "deviceprofiles":
-			_, err = c.GetDeviceProfiles(ctx, orgID, "")
-		case "rftemplates":
-			_, err = c.GetRFTemplates(ctx, orgID)
-		case "gatewaytemplates":
-			_, err = c.GetGatewayTemplates(ctx, orgID)
-		case "wlantemplates":
-			_, err = c.GetWLANTemplates(ctx, orgID)
-		case "networks":
-			_, err = c.GetNetworks(ctx, orgID)
-		case "wlans":
-			_, err = c.GetWLANs(ctx, orgID)
-		case "device-configs":
-			// Refresh device configurations for all sites
-			sites, siteErr := c.GetSites(ctx, orgID)
-			if siteErr != nil {
-				err = fmt.Errorf("failed to get sites for device config refresh: %w", siteErr)
-			} else {
-				for _, site := range sites {
-					if site.ID != nil {
-						// Get all device types for each site
-						for _, devType := range []string{"ap", "switch", "gateway"} {
-							_, devErr := c.GetDevices(ctx, *site.ID, devType)
-							if devErr != nil {
-								c.logDebug("Warning: failed to refresh %s configs for site %s: %v", devType, *site.ID, devErr)
-							}
+		}
+	case "inventory-ap":
+		_, err = c.GetInventory(ctx, orgID, "ap")
+	case "inventory-switch":
+		_, err = c.GetInventory(ctx, orgID, "switch")
+	case "inventory-gateway":
+		_, err = c.GetInventory(ctx, orgID, "gateway")
+	case "deviceprofiles":
+		_, err = c.GetDeviceProfiles(ctx, orgID, "")
+	case "rftemplates":
+		_, err = c.GetRFTemplates(ctx, orgID)
+	case "gatewaytemplates":
+		_, err = c.GetGatewayTemplates(ctx, orgID)
+	case "wlantemplates":
+		_, err = c.GetWLANTemplates(ctx, orgID)
+	case "networks":
+		_, err = c.GetNetworks(ctx, orgID)
+	case "wlans":
+		_, err = c.GetWLANs(ctx, orgID)
+	case "device-configs":
+		// Refresh device configurations for all sites
+		sites, siteErr := c.GetSites(ctx, orgID)
+		if siteErr != nil {
+			err = fmt.Errorf("failed to get sites for device config refresh: %w", siteErr)
+		} else {
+			for _, site := range sites {
+				if ctx.Err() != nil {
+					break
+				}
+				if site.ID != nil {
+					// Get all device types for each site
+					for _, devType := range []string{"ap", "switch", "gateway"} {
+						_, devErr := c.GetDevices(ctx, *site.ID, devType)
+						if devErr != nil {
+							c.logDebug("Warning: failed to refresh %s configs for site %s: %v", devType, *site.ID, devErr)
 						}
 					}
 				}
 			}
 		}
-
-		if err != nil {
-			c.logDebug("Warning: failed to refresh %s: %v", dataType, err)
-			// Continue with other data types even if one fails
-		}
 	}
 
-	c.logDebug("Cache force rebuild completed successfully")
-	return nil
+	if err != nil {
+		c.logDebug("Warning: failed to refresh %s: %v", dataType, err)
+		// Continue with other data types even if one fails
+	}
 }
 
 // UpdateCacheForTypes logs that cache update was requested
@@ -755,9 +790,9 @@ func (c *mistClient) PopulateDeviceCacheForSite(ctx context.Context, siteID stri
 	// Clear the configs cache for this site and device type to force fresh API fetch
 	// Note: We do NOT clear inventory - only configs since apply only updates configs
 	if deviceType == "" || deviceType == "all" {
-		ClearCacheForSite(siteID, "configs")
+		c.ClearCacheForSite(siteID, "configs")
 	} else {
-		ClearCacheForSite(siteID, fmt.Sprintf("configs-%s", deviceType))
+		c.ClearCacheForSite(siteID, fmt.Sprintf("configs-%s", deviceType))
 	}
 
 	// Get device configs from API using GET /sites/<site_id>/devices?type=<device_type>
@@ -767,14 +802,9 @@ func (c *mistClient) PopulateDeviceCacheForSite(ctx context.Context, siteID stri
 		return fmt.Errorf("failed to get device configs: %w", err)
 	}
 
-	// Ensure in-memory device cache is initialized
-	if deviceCache == nil {
-		InitializeDeviceCache()
-	}
-
 	// Add devices to in-memory cache
 	for _, device := range devices {
-		deviceCache.AddDevice(device)
+		c.devices.AddDevice(device)
 	}
 
 	c.logDebug("Added %d %s device configs to in-memory cache for site %s", len(devices), deviceType, siteID)
@@ -24,12 +24,7 @@ func (c *mistClient) GetDeviceProfiles(ctx context.Context, orgID string, profil
 
 	c.logDebug("Cache miss for device profiles (type: %s)", profileType)
 
-	// Determine the results limit to use
-	limit := 100 // Default value
-	if c.config.ResultsLimit > 0 {
-		limit = c.config.ResultsLimit
-		c.logDebug("Using configured results limit: %d", limit)
-	}
+	limit := c.resultsLimit(EndpointDeviceProfiles)
 
 	var allProfiles []DeviceProfile
 	page := 1
@@ -182,3 +177,18 @@ func (c *mistClient) UnassignDeviceProfiles(ctx context.Context, orgID string, p
 	c.logDebug("Successfully unassigned device profile from %d devices", len(macs))
 	return nil
 }
+
+// DeleteDeviceProfile deletes a device profile
+func (c *mistClient) DeleteDeviceProfile(ctx context.Context, orgID string, profileID string) error {
+	if c.dryRun {
+		c.logDebug("[DRY RUN] Would delete device profile: %s", profileID)
+		return nil
+	}
+
+	path := fmt.Sprintf("/orgs/%s/deviceprofiles/%s", orgID, profileID)
+	if err := c.do(ctx, http.MethodDelete, path, nil, nil); err != nil {
+		return fmt.Errorf("failed to delete device profile: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,28 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// SearchClientEvents retrieves the events recorded for one client MAC across
+// the organization between start and end, following cursor pages.
+func (c *mistClient) SearchClientEvents(ctx context.Context, orgID, mac string, start, end time.Time) ([]map[string]interface{}, error) {
+	endpoint := fmt.Sprintf("/orgs/%s/clients/events/search?mac=%s&start=%d&end=%d&limit=%d",
+		orgID, url.QueryEscape(mac), start.Unix(), end.Unix(), c.resultsLimit(EndpointClientsSearch))
+
+	rawData, err := c.searchAllPages(ctx, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search client events: %w", err)
+	}
+	results, _ := rawData["results"].([]interface{})
+	events := make([]map[string]interface{}, 0, len(results))
+	for _, r := range results {
+		if ev, ok := r.(map[string]interface{}); ok {
+			events = append(events, ev)
+		}
+	}
+	return events, nil
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// EVPN topology scopes. A campus fabric confined to one site lives under the
+// site; a fabric spanning sites lives under the org.
+const (
+	EVPNScopeSite = "site"
+	EVPNScopeOrg  = "org"
+)
+
+// evpnTopologiesPath returns the collection path for scope, owned by ownerID
+// (a site ID or org ID).
+func evpnTopologiesPath(scope, ownerID string) (string, error) {
+	switch scope {
+	case EVPNScopeSite:
+		return fmt.Sprintf("/sites/%s/evpn_topologies", ownerID), nil
+	case EVPNScopeOrg:
+		return fmt.Sprintf("/orgs/%s/evpn_topologies", ownerID), nil
+	}
+	return "", fmt.Errorf("invalid EVPN topology scope %q: must be %q or %q", scope, EVPNScopeSite, EVPNScopeOrg)
+}
+
+// GetEVPNTopologies lists the EVPN (campus fabric) topologies at scope.
+// Returns raw JSON maps; the topology object is passed through as intent.
+func (c *mistClient) GetEVPNTopologies(ctx context.Context, scope, ownerID string) ([]map[string]interface{}, error) {
+	path, err := evpnTopologiesPath(scope, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	var result []map[string]interface{}
+	if err := c.do(ctx, http.MethodGet, path, nil, &result); err != nil {
+		return nil, fmt.Errorf("failed to get EVPN topologies: %w", err)
+	}
+	return result, nil
+}
+
+// CreateEVPNTopology creates an EVPN topology at scope.
+func (c *mistClient) CreateEVPNTopology(ctx context.Context, scope, ownerID string, topology map[string]interface{}) (map[string]interface{}, error) {
+	path, err := evpnTopologiesPath(scope, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	var result map[string]interface{}
+	if err := c.do(ctx, http.MethodPost, path, topology, &result); err != nil {
+		return nil, fmt.Errorf("failed to create EVPN topology: %w", err)
+	}
+	return result, nil
+}
+
+// UpdateEVPNTopology replaces the fields given in topology on an existing
+// EVPN topology at scope.
+func (c *mistClient) UpdateEVPNTopology(ctx context.Context, scope, ownerID, topologyID string, topology map[string]interface{}) (map[string]interface{}, error) {
+	path, err := evpnTopologiesPath(scope, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	var result map[string]interface{}
+	if err := c.do(ctx, http.MethodPut, path+"/"+topologyID, topology, &result); err != nil {
+		return nil, fmt.Errorf("failed to update EVPN topology: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,22 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// UpgradeDevices asks Mist to upgrade the given devices at a site to version
+// and reboot them into it. Mist runs the upgrade asynchronously.
+func (c *mistClient) UpgradeDevices(ctx context.Context, siteID string, deviceIDs []string, version string) error {
+	path := fmt.Sprintf("/sites/%s/devices/upgrade", siteID)
+	body := map[string]interface{}{
+		"device_ids": deviceIDs,
+		"version":    version,
+		"reboot":     true,
+	}
+	if err := c.do(ctx, http.MethodPost, path, body, nil); err != nil {
+		return fmt.Errorf("failed to upgrade devices: %w", err)
+	}
+	return nil
+}
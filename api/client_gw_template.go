@@ -20,3 +20,39 @@ func (c *mistClient) GetGatewayTemplates(ctx context.Context, orgID string) ([]M
 	c.logDebug("Retrieved %d gateway templates", len(templates))
 	return templates, nil
 }
+
+// GetGatewayTemplatesRaw lists the organization's gateway templates as raw
+// JSON maps, so callers can compare keys MistGatewayTemplate does not model
+// (service_policies, path_preferences, ...).
+func (c *mistClient) GetGatewayTemplatesRaw(ctx context.Context, orgID string) ([]map[string]interface{}, error) {
+	var templates []map[string]interface{}
+	path := fmt.Sprintf("/orgs/%s/gatewaytemplates", orgID)
+
+	if err := c.do(ctx, http.MethodGet, path, nil, &templates); err != nil {
+		return nil, fmt.Errorf("failed to get gateway templates: %w", err)
+	}
+	return templates, nil
+}
+
+// CreateGatewayTemplate creates a gateway template in the organization.
+func (c *mistClient) CreateGatewayTemplate(ctx context.Context, orgID string, template map[string]interface{}) (map[string]interface{}, error) {
+	var result map[string]interface{}
+	path := fmt.Sprintf("/orgs/%s/gatewaytemplates", orgID)
+
+	if err := c.do(ctx, http.MethodPost, path, template, &result); err != nil {
+		return nil, fmt.Errorf("failed to create gateway template: %w", err)
+	}
+	return result, nil
+}
+
+// UpdateGatewayTemplate replaces the top-level keys given in template on an
+// existing gateway template; keys not sent are left as they are.
+func (c *mistClient) UpdateGatewayTemplate(ctx context.Context, orgID, templateID string, template map[string]interface{}) (map[string]interface{}, error) {
+	var result map[string]interface{}
+	path := fmt.Sprintf("/orgs/%s/gatewaytemplates/%s", orgID, templateID)
+
+	if err := c.do(ctx, http.MethodPut, path, template, &result); err != nil {
+		return nil, fmt.Errorf("failed to update gateway template: %w", err)
+	}
+	return result, nil
+}
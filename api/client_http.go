@@ -74,7 +74,9 @@ func (c *mistClient) do(ctx context.Context, method, path string, body interface
 		err = c.retryRequest(ctx, func() (int, error) {
 			// Apply rate limiting if configured
 			if c.rateLimiter != nil {
-				c.rateLimiter.wait()
+				if err := c.rateLimiter.Wait(ctx); err != nil {
+					return 0, err
+				}
 			}
 
 			// Execute the request
@@ -108,7 +110,9 @@ func (c *mistClient) do(ctx context.Context, method, path string, body interface
 		// No retry, just execute the request once
 		// Apply rate limiting if configured
 		if c.rateLimiter != nil {
-			c.rateLimiter.wait()
+			if err := c.rateLimiter.Wait(ctx); err != nil {
+				return err
+			}
 		}
 
 		// Execute the request
@@ -451,72 +455,6 @@ func (c *mistClient) extractRetryAfterDuration(err error, resp *http.Response) t
 
 // logDebug is defined in client.go
 
-// sensitiveFields lists JSON field names that should be redacted in debug logs
-var sensitiveFields = map[string]bool{
-	"password":      true,
-	"secret":        true,
-	"token":         true,
-	"api_token":     true,
-	"apitoken":      true,
-	"api_key":       true,
-	"apikey":        true,
-	"access_token":  true,
-	"refresh_token": true,
-	"psk":           true,
-	"passphrase":    true,
-	"credentials":   true,
-	"private_key":   true,
-	"auth":          true,
-}
-
-// redactSensitiveJSON redacts sensitive fields from JSON content for safe logging
-func redactSensitiveJSON(data []byte) string {
-	if len(data) == 0 {
-		return ""
-	}
-
-	// Try to parse as JSON
-	var parsed interface{}
-	if err := json.Unmarshal(data, &parsed); err != nil {
-		// Not valid JSON, return as-is (could be form data or plain text)
-		return string(data)
-	}
-
-	// Recursively redact sensitive fields
-	redacted := redactValue(parsed)
-
-	// Re-marshal for logging
-	result, err := json.Marshal(redacted)
-	if err != nil {
-		return "[redaction failed]"
-	}
-	return string(result)
-}
-
-// redactValue recursively redacts sensitive fields from a parsed JSON value
-func redactValue(v interface{}) interface{} {
-	switch val := v.(type) {
-	case map[string]interface{}:
-		result := make(map[string]interface{})
-		for k, v := range val {
-			if sensitiveFields[strings.ToLower(k)] {
-				result[k] = "[REDACTED]"
-			} else {
-				result[k] = redactValue(v)
-			}
-		}
-		return result
-	case []interface{}:
-		result := make([]interface{}, len(val))
-		for i, v := range val {
-			result[i] = redactValue(v)
-		}
-		return result
-	default:
-		return v
-	}
-}
-
 // debugTransport is a custom http.RoundTripper that logs HTTP requests and responses
 type debugTransport struct {
 	transport http.RoundTripper
@@ -552,7 +490,7 @@ func (t *debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 			// Log the body with sensitive fields redacted
 			if len(body) > 0 {
 				t.client.logDebug("Request Body:")
-				t.client.logDebug("%s", redactSensitiveJSON(body))
+				t.client.logDebug("%s", common.RedactJSON(body))
 			}
 		}
 	}
@@ -590,7 +528,7 @@ func (t *debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 					} else {
 						t.client.logDebug("Response Body (sensitive fields redacted):")
 					}
-					t.client.logDebug("%s", redactSensitiveJSON(bodyToLog))
+					t.client.logDebug("%s", common.RedactJSON(bodyToLog))
 					if len(bodyBytes) > maxLogSize {
 						t.client.logDebug("...and %d more bytes", len(bodyBytes)-maxLogSize)
 					}
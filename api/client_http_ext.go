@@ -4,15 +4,18 @@ import (
 	"context"
 )
 
-// Wait extends the rateLimiter to accept a context for backward compatibility
-// with the existing mock client implementation
+// Wait blocks until a token is available or ctx is done, so a cancelled run
+// doesn't sit behind the rate limiter.
 func (r *rateLimiter) Wait(ctx context.Context) error {
 	// If context is done, return error
 	if err := ctx.Err(); err != nil {
 		return err
 	}
 
-	// Call the underlying wait method
-	r.wait()
-	return nil
+	select {
+	case <-r.tokens:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
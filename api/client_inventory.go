@@ -48,12 +48,7 @@ func (c *mistClient) GetInventory(ctx context.Context, orgID string, deviceType
 	// Note: Legacy file cache fallback removed. Use vendors.GetGlobalCacheAccessor() for cache lookups.
 	c.logDebug("In-memory cache miss for inventory type %s, fetching from API", displayType)
 
-	// Determine the results limit to use
-	limit := 100 // Default value
-	if c.config.ResultsLimit > 0 {
-		limit = c.config.ResultsLimit
-		c.logDebug("Using configured results limit: %d", limit)
-	}
+	limit := c.resultsLimit(EndpointInventory)
 
 	var allItems []*MistInventoryItem
 	page := 1
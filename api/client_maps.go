@@ -0,0 +1,17 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// GetSiteMaps retrieves the floor plans defined for a site.
+func (c *mistClient) GetSiteMaps(ctx context.Context, siteID string) ([]map[string]interface{}, error) {
+	path := fmt.Sprintf("/sites/%s/maps", siteID)
+	var result []map[string]interface{}
+	if err := c.do(ctx, http.MethodGet, path, nil, &result); err != nil {
+		return nil, fmt.Errorf("failed to get site maps: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,124 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// GetRaw GETs an API path the client does not model and returns the JSON
+// response as-is. Unless the path names a page, a list response is fetched
+// page by page and returned as one array, and a search response follows its
+// next cursor and returns the merged results.
+func (c *mistClient) GetRaw(ctx context.Context, path string) (json.RawMessage, error) {
+	u, err := url.Parse(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid path %q: %w", path, err)
+	}
+	query := u.Query()
+	if query.Has("page") {
+		var raw json.RawMessage
+		if err := c.do(ctx, http.MethodGet, path, nil, &raw); err != nil {
+			return nil, err
+		}
+		return raw, nil
+	}
+
+	limit := c.resultsLimit("")
+	if s := query.Get("limit"); s != "" {
+		if limit, err = strconv.Atoi(s); err != nil || limit <= 0 {
+			return nil, fmt.Errorf("invalid limit %q", s)
+		}
+	}
+	query.Set("limit", strconv.Itoa(limit))
+
+	var all []json.RawMessage
+	for page := 1; ; page++ {
+		if page > 1 {
+			query.Set("page", strconv.Itoa(page))
+		}
+		u.RawQuery = query.Encode()
+
+		var raw json.RawMessage
+		if err := c.do(ctx, http.MethodGet, u.String(), nil, &raw); err != nil {
+			return nil, err
+		}
+		trimmed := bytes.TrimSpace(raw)
+		if page == 1 && (len(trimmed) == 0 || trimmed[0] != '[') {
+			return c.followRawCursor(ctx, raw)
+		}
+
+		var items []json.RawMessage
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return nil, fmt.Errorf("failed to parse page %d: %w", page, err)
+		}
+		all = append(all, items...)
+		if len(items) < limit {
+			break
+		}
+	}
+	if all == nil {
+		all = []json.RawMessage{}
+	}
+	return json.Marshal(all)
+}
+
+// PutRaw PUTs body to an API path the client does not model and returns the
+// JSON response as-is.
+func (c *mistClient) PutRaw(ctx context.Context, path string, body json.RawMessage) (json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := c.do(ctx, http.MethodPut, path, body, &raw); err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
+
+// followRawCursor completes a search response by following its next links
+// and appending each page's results. Other objects are returned unchanged.
+func (c *mistClient) followRawCursor(ctx context.Context, raw json.RawMessage) (json.RawMessage, error) {
+	var obj map[string]json.RawMessage
+	if json.Unmarshal(raw, &obj) != nil || !c.followCursor() {
+		return raw, nil
+	}
+	var results []json.RawMessage
+	if json.Unmarshal(obj["results"], &results) != nil {
+		return raw, nil
+	}
+
+	for pages := 1; pages < maxCursorPages; pages++ {
+		var cursor map[string]interface{}
+		if err := json.Unmarshal(raw, &cursor); err != nil {
+			return nil, err
+		}
+		next, err := nextPath(cursor)
+		if err != nil {
+			return nil, err
+		}
+		if next == "" {
+			break
+		}
+		raw = nil
+		if err := c.do(ctx, http.MethodGet, next, nil, &raw); err != nil {
+			return nil, err
+		}
+		var page struct {
+			Results []json.RawMessage `json:"results"`
+		}
+		if err := json.Unmarshal(raw, &page); err != nil {
+			return nil, fmt.Errorf("failed to parse cursor page: %w", err)
+		}
+		results = append(results, page.Results...)
+	}
+
+	merged, err := json.Marshal(results)
+	if err != nil {
+		return nil, err
+	}
+	obj["results"] = merged
+	delete(obj, "next")
+	return json.Marshal(obj)
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetRawPagesLists(t *testing.T) {
+	var pages []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pages = append(pages, r.URL.Query().Get("page"))
+		switch r.URL.Query().Get("page") {
+		case "":
+			_, _ = fmt.Fprint(w, `[{"id":"a"},{"id":"b"}]`)
+		case "2":
+			_, _ = fmt.Fprint(w, `[{"id":"c"}]`)
+		default:
+			t.Errorf("unexpected page %q", r.URL.Query().Get("page"))
+		}
+	}))
+	defer srv.Close()
+	c := NewClientWithOptions("key", srv.URL+"/api/v1", "o", WithResultsLimit(2))
+
+	raw, err := c.GetRaw(context.Background(), "/orgs/o/alarmtemplates")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var items []map[string]string
+	if err := json.Unmarshal(raw, &items); err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 3 || items[2]["id"] != "c" || len(pages) != 2 {
+		t.Errorf("items = %v after %d request(s)", items, len(pages))
+	}
+
+	// A page named in the path is fetched alone.
+	pages = nil
+	if _, err := c.GetRaw(context.Background(), "/orgs/o/alarmtemplates?page=2"); err != nil {
+		t.Fatal(err)
+	}
+	if len(pages) != 1 {
+		t.Errorf("explicit page made %d request(s), want 1", len(pages))
+	}
+}
+
+func TestGetRawFollowsSearchCursor(t *testing.T) {
+	var calls int
+	srv := searchServer(t, &calls)
+	c := NewClientWithOptions("key", srv.URL+"/api/v1", "o", WithResultsLimit(2))
+
+	raw, err := c.GetRaw(context.Background(), "/orgs/o/clients/search?text=")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var resp map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		t.Fatal(err)
+	}
+	var results []json.RawMessage
+	_ = json.Unmarshal(resp["results"], &results)
+	if calls != 3 || len(results) != 3 {
+		t.Errorf("calls = %d, results = %d; want 3 each", calls, len(results))
+	}
+	if _, ok := resp["next"]; ok {
+		t.Error("merged response still carries a next link")
+	}
+}
+
+func TestPutRawSendsBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/api/v1/sites/s1/setting" {
+			t.Errorf("got %s %s", r.Method, r.URL.Path)
+		}
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body["vars"] == nil {
+			t.Errorf("body = %v, %v", body, err)
+		}
+		_, _ = fmt.Fprint(w, `{"id":"set-1"}`)
+	}))
+	defer srv.Close()
+	c := NewClientWithOptions("key", srv.URL+"/api/v1", "o")
+
+	raw, err := c.PutRaw(context.Background(), "/sites/s1/setting", json.RawMessage(`{"vars":{"a":"b"}}`))
+	if err != nil || string(raw) != `{"id":"set-1"}` {
+		t.Errorf("PutRaw = %s, %v", raw, err)
+	}
+}
@@ -20,3 +20,18 @@ func (c *mistClient) GetRFTemplates(ctx context.Context, orgID string) ([]MistRF
 	c.logDebug("Retrieved %d RF templates", len(templates))
 	return templates, nil
 }
+
+// DeleteRFTemplate deletes an RF template
+func (c *mistClient) DeleteRFTemplate(ctx context.Context, orgID string, templateID string) error {
+	if c.dryRun {
+		c.logDebug("[DRY RUN] Would delete RF template: %s", templateID)
+		return nil
+	}
+
+	path := fmt.Sprintf("/orgs/%s/rftemplates/%s", orgID, templateID)
+	if err := c.do(ctx, http.MethodDelete, path, nil, nil); err != nil {
+		return fmt.Errorf("failed to delete RF template: %w", err)
+	}
+
+	return nil
+}
@@ -9,23 +9,55 @@ import (
 
 // Search-related methods using the new bidirectional data handling
 
+// searchAllPages runs a Mist search and, unless pagination is "page", follows
+// its next cursor links, returning the first page's response with the results
+// of every page merged into "results".
+func (c *mistClient) searchAllPages(ctx context.Context, endpoint string) (map[string]interface{}, error) {
+	var first map[string]interface{}
+	var results []interface{}
+	for page := 1; endpoint != ""; page++ {
+		// Use raw JSON unmarshaling to preserve all data
+		var rawResponse json.RawMessage
+		if err := c.do(ctx, http.MethodGet, endpoint, nil, &rawResponse); err != nil {
+			return nil, err
+		}
+		var rawData map[string]interface{}
+		if err := json.Unmarshal(rawResponse, &rawData); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal search response: %w", err)
+		}
+		if first == nil {
+			first = rawData
+		}
+		pageResults, _ := rawData["results"].([]interface{})
+		results = append(results, pageResults...)
+
+		endpoint = ""
+		if !c.followCursor() || len(pageResults) == 0 {
+			break
+		}
+		if page == maxCursorPages {
+			c.logDebug("Stopping search after %d pages; narrow the search to see more", maxCursorPages)
+			break
+		}
+		next, err := nextPath(rawData)
+		if err != nil {
+			return nil, err
+		}
+		endpoint = next
+	}
+	first["results"] = results
+	return first, nil
+}
+
 // SearchWiredClients searches for wired clients in an organization using raw JSON unmarshaling
 func (c *mistClient) SearchWiredClients(ctx context.Context, orgID string, text string) (*MistWiredClientResponse, error) {
-	endpoint := fmt.Sprintf("/orgs/%s/wired_clients/search?text=%s", orgID, text)
+	endpoint := fmt.Sprintf("/orgs/%s/wired_clients/search?text=%s&limit=%d", orgID, text, c.resultsLimit(EndpointClientsSearch))
 
-	// Use raw JSON unmarshaling to preserve all data
-	var rawResponse json.RawMessage
-	err := c.do(ctx, http.MethodGet, endpoint, nil, &rawResponse)
+	rawData, err := c.searchAllPages(ctx, endpoint)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search wired clients: %w", err)
 	}
 
-	// Parse the raw JSON to map
-	var rawData map[string]interface{}
-	if err := json.Unmarshal(rawResponse, &rawData); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal wired clients search response: %w", err)
-	}
-
 	// Create response with complete data preservation
 	response := &MistWiredClientResponse{
 		Raw: make(map[string]interface{}),
@@ -81,21 +113,13 @@ func (c *mistClient) SearchWiredClients(ctx context.Context, orgID string, text
 
 // SearchWirelessClients searches for wireless clients in an organization using raw JSON unmarshaling
 func (c *mistClient) SearchWirelessClients(ctx context.Context, orgID string, text string) (*MistWirelessClientResponse, error) {
-	endpoint := fmt.Sprintf("/orgs/%s/clients/search?text=%s", orgID, text)
+	endpoint := fmt.Sprintf("/orgs/%s/clients/search?text=%s&limit=%d", orgID, text, c.resultsLimit(EndpointClientsSearch))
 
-	// Use raw JSON unmarshaling to preserve all data
-	var rawResponse json.RawMessage
-	err := c.do(ctx, http.MethodGet, endpoint, nil, &rawResponse)
+	rawData, err := c.searchAllPages(ctx, endpoint)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search wireless clients: %w", err)
 	}
 
-	// Parse the raw JSON to map
-	var rawData map[string]interface{}
-	if err := json.Unmarshal(rawResponse, &rawData); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal wireless clients search response: %w", err)
-	}
-
 	// Create response with complete data preservation
 	response := &MistWirelessClientResponse{
 		Raw: make(map[string]interface{}),
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// SearchSiteRogueEvents retrieves the rogue, honeypot, and spoofed AP events
+// a site recorded between start and end, following cursor pages.
+func (c *mistClient) SearchSiteRogueEvents(ctx context.Context, siteID string, start, end time.Time) ([]map[string]interface{}, error) {
+	endpoint := fmt.Sprintf("/sites/%s/rogues/events/search?start=%d&end=%d&limit=%d",
+		siteID, start.Unix(), end.Unix(), c.resultsLimit(EndpointClientsSearch))
+
+	rawData, err := c.searchAllPages(ctx, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search rogue events: %w", err)
+	}
+	return searchResults(rawData), nil
+}
+
+// SearchSiteAlarms retrieves the alarms of one group ("security",
+// "infrastructure", "marvis") a site raised between start and end, following
+// cursor pages.
+func (c *mistClient) SearchSiteAlarms(ctx context.Context, siteID, group string, start, end time.Time) ([]map[string]interface{}, error) {
+	endpoint := fmt.Sprintf("/sites/%s/alarms/search?group=%s&start=%d&end=%d&limit=%d",
+		siteID, url.QueryEscape(group), start.Unix(), end.Unix(), c.resultsLimit(EndpointClientsSearch))
+
+	rawData, err := c.searchAllPages(ctx, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search alarms: %w", err)
+	}
+	return searchResults(rawData), nil
+}
+
+// searchResults returns the object entries of a search response's results.
+func searchResults(rawData map[string]interface{}) []map[string]interface{} {
+	results, _ := rawData["results"].([]interface{})
+	out := make([]map[string]interface{}, 0, len(results))
+	for _, r := range results {
+		if m, ok := r.(map[string]interface{}); ok {
+			out = append(out, m)
+		}
+	}
+	return out
+}
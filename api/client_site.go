@@ -53,12 +53,7 @@ func (c *mistClient) GetSites(ctx context.Context, orgID string) ([]*MistSite, e
 	// Note: Legacy cache fallback removed. Use vendors.GetGlobalCacheAccessor() for cache lookups.
 	c.logDebug("Fetching sites from API")
 
-	// Determine the results limit to use
-	limit := 100 // Default value
-	if c.config.ResultsLimit > 0 {
-		limit = c.config.ResultsLimit
-		c.logDebug("Using configured results limit: %d", limit)
-	}
+	limit := c.resultsLimit(EndpointSites)
 
 	var allSites []*MistSite
 	page := 1
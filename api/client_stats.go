@@ -16,3 +16,13 @@ func (c *mistClient) GetAPStats(ctx context.Context, siteID string) ([]map[strin
 	}
 	return result, nil
 }
+
+// GetSwitchStats retrieves switch statistics (including module_stat PoE totals) for a site.
+func (c *mistClient) GetSwitchStats(ctx context.Context, siteID string) ([]map[string]interface{}, error) {
+	path := fmt.Sprintf("/sites/%s/stats/devices?type=switch", siteID)
+	var result []map[string]interface{}
+	if err := c.do(ctx, http.MethodGet, path, nil, &result); err != nil {
+		return nil, fmt.Errorf("failed to get switch stats: %w", err)
+	}
+	return result, nil
+}
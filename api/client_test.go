@@ -3,6 +3,8 @@ package api
 import (
 	"testing"
 	"time"
+
+	"github.com/ravinald/wifimgr/internal/common"
 )
 
 // TestNewClient tests the creation of a new client
@@ -147,7 +149,7 @@ func TestRedactSensitiveJSON(t *testing.T) {
 		{
 			name:     "handles invalid JSON",
 			input:    "not json",
-			expected: "not json",
+			expected: "[non-JSON body redacted]",
 		},
 		{
 			name:     "handles arrays with sensitive data",
@@ -158,9 +160,9 @@ func TestRedactSensitiveJSON(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := redactSensitiveJSON([]byte(tt.input))
+			result := common.RedactJSON([]byte(tt.input))
 			if result != tt.expected {
-				t.Errorf("redactSensitiveJSON(%q) = %q, want %q", tt.input, result, tt.expected)
+				t.Errorf("common.RedactJSON(%q) = %q, want %q", tt.input, result, tt.expected)
 			}
 		})
 	}
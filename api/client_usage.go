@@ -0,0 +1,43 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// SearchSiteClientSessions retrieves the wireless client sessions a site
+// recorded between start and end, following cursor pages.
+func (c *mistClient) SearchSiteClientSessions(ctx context.Context, siteID string, start, end time.Time) ([]map[string]interface{}, error) {
+	endpoint := fmt.Sprintf("/sites/%s/clients/sessions/search?start=%d&end=%d&limit=%d",
+		siteID, start.Unix(), end.Unix(), c.resultsLimit(EndpointClientsSearch))
+
+	rawData, err := c.searchAllPages(ctx, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search client sessions: %w", err)
+	}
+	results, _ := rawData["results"].([]interface{})
+	sessions := make([]map[string]interface{}, 0, len(results))
+	for _, r := range results {
+		if s, ok := r.(map[string]interface{}); ok {
+			sessions = append(sessions, s)
+		}
+	}
+	return sessions, nil
+}
+
+// GetSiteInsightMetric retrieves one site insight metric (e.g. "bytes")
+// between start and end, bucketed by interval seconds. The response holds an
+// "rt" timestamp series and one series per value the metric reports.
+func (c *mistClient) GetSiteInsightMetric(ctx context.Context, siteID, metric string, start, end time.Time, interval int) (map[string]interface{}, error) {
+	endpoint := fmt.Sprintf("/sites/%s/insights/%s?start=%d&end=%d&interval=%d",
+		siteID, url.PathEscape(metric), start.Unix(), end.Unix(), interval)
+
+	var result map[string]interface{}
+	if err := c.do(ctx, http.MethodGet, endpoint, nil, &result); err != nil {
+		return nil, fmt.Errorf("failed to get site insight %s: %w", metric, err)
+	}
+	return result, nil
+}
@@ -0,0 +1,20 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// GetVirtualChassis returns the virtual chassis status of a switch: the
+// members Mist sees (mac, member, vc_role, vc_state, model). Returns the raw
+// JSON map; a standalone switch reports no members.
+func (c *mistClient) GetVirtualChassis(ctx context.Context, siteID, deviceID string) (map[string]interface{}, error) {
+	var result map[string]interface{}
+	path := fmt.Sprintf("/sites/%s/devices/%s/vc", siteID, deviceID)
+
+	if err := c.do(ctx, http.MethodGet, path, nil, &result); err != nil {
+		return nil, fmt.Errorf("failed to get virtual chassis: %w", err)
+	}
+	return result, nil
+}
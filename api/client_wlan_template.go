@@ -20,3 +20,18 @@ func (c *mistClient) GetWLANTemplates(ctx context.Context, orgID string) ([]Mist
 	c.logDebug("Retrieved %d WLAN templates", len(templates))
 	return templates, nil
 }
+
+// DeleteWLANTemplate deletes a WLAN template
+func (c *mistClient) DeleteWLANTemplate(ctx context.Context, orgID string, templateID string) error {
+	if c.dryRun {
+		c.logDebug("[DRY RUN] Would delete WLAN template: %s", templateID)
+		return nil
+	}
+
+	path := fmt.Sprintf("/orgs/%s/templates/%s", orgID, templateID)
+	if err := c.do(ctx, http.MethodDelete, path, nil, nil); err != nil {
+		return fmt.Errorf("failed to delete WLAN template: %w", err)
+	}
+
+	return nil
+}
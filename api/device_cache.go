@@ -2,43 +2,119 @@ package api
 
 import (
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ravinald/wifimgr/internal/logging"
 	"github.com/ravinald/wifimgr/internal/macaddr"
+	"github.com/ravinald/wifimgr/internal/runstats"
 )
 
-// DeviceCache structure with proper indexing using new bidirectional data handling
+// DeviceCache is an in-memory index of device configs fetched from the Mist
+// API. Each client owns its own (see WithDeviceCache), and every method is
+// safe for concurrent use.
+//
+// The cache counts its own writes (Generation) and can follow a source
+// generation: the write stamp of the file cache it sits in front of. When the
+// source moves past what the cache last saw — a refresh in this or another
+// process rewrote the file cache — everything held in memory predates it and
+// is dropped, so stale in-memory data never shadows newer file-cache data.
 type DeviceCache struct {
-	// Primary storage: all devices by MAC address
-	Devices map[string]UnifiedDevice // MAC -> UnifiedDevice
+	mu sync.RWMutex
+
+	// Primary storage: all devices by normalized MAC address
+	devices map[string]UnifiedDevice
 
 	// Indexes for quick lookups
-	SiteIndex map[string][]string // SiteID -> []MAC
-	TypeIndex map[string][]string // DeviceType -> []MAC
-	NameIndex map[string]string   // Name -> MAC
+	siteIndex map[string][]string // SiteID -> []MAC
+	typeIndex map[string][]string // DeviceType -> []MAC
+	nameIndex map[string]string   // Name -> MAC
+
+	generation  uint64    // bumped on every write
+	lastUpdated time.Time // time of the last write
 
-	// Cache management
-	LastUpdated time.Time
-	mu          sync.RWMutex
+	source    func() uint64 // current source generation; nil when not tracked
+	sourceGen uint64        // source generation the contents belong to
+
+	hits   atomic.Int64
+	misses atomic.Int64
+}
 
-	// Performance metrics
-	stats struct {
-		hits   int64
-		misses int64
-		mu     sync.Mutex
+// DeviceCacheOption configures a DeviceCache.
+type DeviceCacheOption func(*DeviceCache)
+
+// WithSourceGeneration ties the cache to a source generation, typically the
+// file cache's write stamp. fn must be cheap and never decrease; it is called
+// once per cache operation.
+func WithSourceGeneration(fn func() uint64) DeviceCacheOption {
+	return func(c *DeviceCache) {
+		c.source = fn
+		if fn != nil {
+			c.sourceGen = fn()
+		}
 	}
 }
 
-// NewDeviceCache creates a new device cache
-func NewDeviceCache() *DeviceCache {
-	return &DeviceCache{
-		Devices:     make(map[string]UnifiedDevice),
-		SiteIndex:   make(map[string][]string),
-		TypeIndex:   make(map[string][]string),
-		NameIndex:   make(map[string]string),
-		LastUpdated: time.Now(),
+// NewDeviceCache creates an empty device cache
+func NewDeviceCache(opts ...DeviceCacheOption) *DeviceCache {
+	c := &DeviceCache{lastUpdated: time.Now()}
+	c.reset()
+	for _, opt := range opts {
+		opt(c)
 	}
+	return c
+}
+
+// reset empties storage and indexes. Callers hold c.mu (or own c exclusively).
+func (c *DeviceCache) reset() {
+	c.devices = make(map[string]UnifiedDevice)
+	c.siteIndex = make(map[string][]string)
+	c.typeIndex = make(map[string][]string)
+	c.nameIndex = make(map[string]string)
+}
+
+// syncSource drops the contents when the source generation has moved on
+// since they were stored.
+func (c *DeviceCache) syncSource() {
+	if c.source == nil {
+		return
+	}
+	gen := c.source()
+
+	c.mu.RLock()
+	current := gen <= c.sourceGen
+	c.mu.RUnlock()
+	if current {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if gen <= c.sourceGen {
+		return
+	}
+	if len(c.devices) > 0 {
+		logging.Debugf("Device cache: file cache advanced (generation %d -> %d), dropping %d in-memory devices", c.sourceGen, gen, len(c.devices))
+		c.reset()
+		c.generation++
+		c.lastUpdated = time.Now()
+	}
+	c.sourceGen = gen
+}
+
+// Generation returns the number of writes the cache has seen. Callers can
+// compare two readings to tell whether anything changed in between.
+func (c *DeviceCache) Generation() uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.generation
+}
+
+// LastUpdated returns the time of the last write.
+func (c *DeviceCache) LastUpdated() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.lastUpdated
 }
 
 // Clear removes all devices from the cache
@@ -46,29 +122,28 @@ func (c *DeviceCache) Clear() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	c.Devices = make(map[string]UnifiedDevice)
-	c.SiteIndex = make(map[string][]string)
-	c.TypeIndex = make(map[string][]string)
-	c.NameIndex = make(map[string]string)
-
-	c.LastUpdated = time.Now()
+	c.reset()
+	c.generation++
+	c.lastUpdated = time.Now()
 }
 
 // Count returns the total number of devices in the cache
 func (c *DeviceCache) Count() int {
+	c.syncSource()
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
-	return len(c.Devices)
+	return len(c.devices)
 }
 
 // CountByType returns the number of devices of each type
 func (c *DeviceCache) CountByType() map[string]int {
+	c.syncSource()
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
 	counts := make(map[string]int)
-	for deviceType, macs := range c.TypeIndex {
+	for deviceType, macs := range c.typeIndex {
 		counts[deviceType] = len(macs)
 	}
 
@@ -77,11 +152,12 @@ func (c *DeviceCache) CountByType() map[string]int {
 
 // CountBySite returns the number of devices for each site
 func (c *DeviceCache) CountBySite() map[string]int {
+	c.syncSource()
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
 	counts := make(map[string]int)
-	for siteID, macs := range c.SiteIndex {
+	for siteID, macs := range c.siteIndex {
 		counts[siteID] = len(macs)
 	}
 
@@ -257,14 +333,15 @@ func (c *DeviceCache) AddDevice(device UnifiedDevice) {
 		return
 	}
 
+	c.syncSource()
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	// Check if the device already exists to update indexes properly
-	existing, exists := c.Devices[normalizedMAC]
+	existing, exists := c.devices[normalizedMAC]
 
 	// Add to primary storage
-	c.Devices[normalizedMAC] = device
+	c.devices[normalizedMAC] = device
 
 	// Update SiteIndex
 	if device.SiteID != nil {
@@ -272,14 +349,14 @@ func (c *DeviceCache) AddDevice(device UnifiedDevice) {
 
 		// If existing, remove from old site index if site changed
 		if exists && existing.SiteID != nil && *existing.SiteID != siteID {
-			c.removeMACFromSlice(*existing.SiteID, normalizedMAC, c.SiteIndex)
+			c.removeMACFromSlice(*existing.SiteID, normalizedMAC, c.siteIndex)
 		}
 
 		// Add to new site index
-		c.SiteIndex[siteID] = appendUniqueString(c.SiteIndex[siteID], normalizedMAC)
+		c.siteIndex[siteID] = appendUniqueString(c.siteIndex[siteID], normalizedMAC)
 	} else if exists && existing.SiteID != nil {
 		// Remove from old site index if site ID is now nil
-		c.removeMACFromSlice(*existing.SiteID, normalizedMAC, c.SiteIndex)
+		c.removeMACFromSlice(*existing.SiteID, normalizedMAC, c.siteIndex)
 	}
 
 	// Update TypeIndex
@@ -291,14 +368,14 @@ func (c *DeviceCache) AddDevice(device UnifiedDevice) {
 	if deviceType != "" {
 		// If existing, remove from old type index if type changed
 		if exists && existing.DeviceType != "" && existing.DeviceType != deviceType {
-			c.removeMACFromSlice(existing.DeviceType, normalizedMAC, c.TypeIndex)
+			c.removeMACFromSlice(existing.DeviceType, normalizedMAC, c.typeIndex)
 		}
 
 		// Add to new type index
-		c.TypeIndex[deviceType] = appendUniqueString(c.TypeIndex[deviceType], normalizedMAC)
+		c.typeIndex[deviceType] = appendUniqueString(c.typeIndex[deviceType], normalizedMAC)
 	} else if exists && existing.DeviceType != "" {
 		// Remove from old type index if type is now empty
-		c.removeMACFromSlice(existing.DeviceType, normalizedMAC, c.TypeIndex)
+		c.removeMACFromSlice(existing.DeviceType, normalizedMAC, c.typeIndex)
 	}
 
 	// Update NameIndex
@@ -307,17 +384,18 @@ func (c *DeviceCache) AddDevice(device UnifiedDevice) {
 
 		// If an existing device had a different name, remove the old mapping
 		if exists && existing.Name != nil && *existing.Name != deviceName {
-			delete(c.NameIndex, *existing.Name)
+			delete(c.nameIndex, *existing.Name)
 		}
 
 		// Add the new name mapping
-		c.NameIndex[deviceName] = normalizedMAC
+		c.nameIndex[deviceName] = normalizedMAC
 	} else if exists && existing.Name != nil {
 		// If the device no longer has a name, remove the old mapping
-		delete(c.NameIndex, *existing.Name)
+		delete(c.nameIndex, *existing.Name)
 	}
 
-	c.LastUpdated = time.Now()
+	c.generation++
+	c.lastUpdated = time.Now()
 }
 
 // GetDeviceByMAC retrieves a device by MAC address
@@ -328,10 +406,11 @@ func (c *DeviceCache) GetDeviceByMAC(mac string) (UnifiedDevice, bool) {
 		return UnifiedDevice{}, false
 	}
 
+	c.syncSource()
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
-	device, found := c.Devices[normalizedMAC]
+	device, found := c.devices[normalizedMAC]
 	if found {
 		c.recordHit()
 	} else {
@@ -342,31 +421,33 @@ func (c *DeviceCache) GetDeviceByMAC(mac string) (UnifiedDevice, bool) {
 
 // GetDeviceByName retrieves a device by name
 func (c *DeviceCache) GetDeviceByName(name string) (UnifiedDevice, bool) {
+	c.syncSource()
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
-	mac, found := c.NameIndex[name]
+	mac, found := c.nameIndex[name]
 	if !found {
 		return UnifiedDevice{}, false
 	}
 
-	device, found := c.Devices[mac]
+	device, found := c.devices[mac]
 	return device, found
 }
 
 // GetDevicesBySite retrieves all devices for a site
 func (c *DeviceCache) GetDevicesBySite(siteID string) []UnifiedDevice {
+	c.syncSource()
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
-	macs, found := c.SiteIndex[siteID]
+	macs, found := c.siteIndex[siteID]
 	if !found {
 		return []UnifiedDevice{}
 	}
 
 	devices := make([]UnifiedDevice, 0, len(macs))
 	for _, mac := range macs {
-		if device, found := c.Devices[mac]; found {
+		if device, found := c.devices[mac]; found {
 			devices = append(devices, device)
 		}
 	}
@@ -376,17 +457,18 @@ func (c *DeviceCache) GetDevicesBySite(siteID string) []UnifiedDevice {
 
 // GetDevicesByType retrieves all devices of a specific type
 func (c *DeviceCache) GetDevicesByType(deviceType string) []UnifiedDevice {
+	c.syncSource()
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
-	macs, found := c.TypeIndex[deviceType]
+	macs, found := c.typeIndex[deviceType]
 	if !found {
 		return []UnifiedDevice{}
 	}
 
 	devices := make([]UnifiedDevice, 0, len(macs))
 	for _, mac := range macs {
-		if device, found := c.Devices[mac]; found {
+		if device, found := c.devices[mac]; found {
 			devices = append(devices, device)
 		}
 	}
@@ -396,18 +478,19 @@ func (c *DeviceCache) GetDevicesByType(deviceType string) []UnifiedDevice {
 
 // GetDevicesBySiteAndType retrieves all devices for a site of a specific type
 func (c *DeviceCache) GetDevicesBySiteAndType(siteID, deviceType string) []UnifiedDevice {
+	c.syncSource()
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
 	// Get all devices for the site
-	macs, found := c.SiteIndex[siteID]
+	macs, found := c.siteIndex[siteID]
 	if !found {
 		return []UnifiedDevice{}
 	}
 
 	devices := make([]UnifiedDevice, 0)
 	for _, mac := range macs {
-		if device, found := c.Devices[mac]; found {
+		if device, found := c.devices[mac]; found {
 			// Check if the device is of the requested type
 			if (device.DeviceType == deviceType) ||
 				(device.Type != nil && *device.Type == deviceType) {
@@ -426,18 +509,19 @@ func (c *DeviceCache) RemoveDevice(mac string) {
 		return
 	}
 
+	c.syncSource()
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	// Get the existing device to update indexes
-	existing, exists := c.Devices[normalizedMAC]
+	existing, exists := c.devices[normalizedMAC]
 	if !exists {
 		return
 	}
 
 	// Remove from SiteIndex
 	if existing.SiteID != nil {
-		c.removeMACFromSlice(*existing.SiteID, normalizedMAC, c.SiteIndex)
+		c.removeMACFromSlice(*existing.SiteID, normalizedMAC, c.siteIndex)
 	}
 
 	// Remove from TypeIndex
@@ -447,27 +531,29 @@ func (c *DeviceCache) RemoveDevice(mac string) {
 	}
 
 	if deviceType != "" {
-		c.removeMACFromSlice(deviceType, normalizedMAC, c.TypeIndex)
+		c.removeMACFromSlice(deviceType, normalizedMAC, c.typeIndex)
 	}
 
 	// Remove from NameIndex
 	if existing.Name != nil && *existing.Name != "" {
-		delete(c.NameIndex, *existing.Name)
+		delete(c.nameIndex, *existing.Name)
 	}
 
 	// Remove from primary storage
-	delete(c.Devices, normalizedMAC)
+	delete(c.devices, normalizedMAC)
 
-	c.LastUpdated = time.Now()
+	c.generation++
+	c.lastUpdated = time.Now()
 }
 
 // GetAllDevices returns all devices in the cache
 func (c *DeviceCache) GetAllDevices() []UnifiedDevice {
+	c.syncSource()
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
-	devices := make([]UnifiedDevice, 0, len(c.Devices))
-	for _, device := range c.Devices {
+	devices := make([]UnifiedDevice, 0, len(c.devices))
+	for _, device := range c.devices {
 		devices = append(devices, device)
 	}
 
@@ -485,19 +571,20 @@ func (c *DeviceCache) MergeDeviceInfo(device UnifiedDevice) {
 		return
 	}
 
+	c.syncSource()
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	// Get existing device if it exists
-	existing, found := c.Devices[normalizedMAC]
+	existing, found := c.devices[normalizedMAC]
 	if !found {
 		// If device doesn't exist yet, just add it
-		c.Devices[normalizedMAC] = device
+		c.devices[normalizedMAC] = device
 
 		// Update indexes
 		if device.SiteID != nil {
 			siteID := *device.SiteID
-			c.SiteIndex[siteID] = appendUniqueString(c.SiteIndex[siteID], normalizedMAC)
+			c.siteIndex[siteID] = appendUniqueString(c.siteIndex[siteID], normalizedMAC)
 		}
 
 		deviceType := device.DeviceType
@@ -506,16 +593,16 @@ func (c *DeviceCache) MergeDeviceInfo(device UnifiedDevice) {
 		}
 
 		if deviceType != "" {
-			c.TypeIndex[deviceType] = appendUniqueString(c.TypeIndex[deviceType], normalizedMAC)
+			c.typeIndex[deviceType] = appendUniqueString(c.typeIndex[deviceType], normalizedMAC)
 		}
 
 		if device.Name != nil && *device.Name != "" {
-			c.NameIndex[*device.Name] = normalizedMAC
+			c.nameIndex[*device.Name] = normalizedMAC
 		}
 	} else {
 		// Merge the devices and update
 		merged := MergeDeviceData(existing, device)
-		c.Devices[normalizedMAC] = merged
+		c.devices[normalizedMAC] = merged
 
 		// Update indexes if needed
 
@@ -527,11 +614,11 @@ func (c *DeviceCache) MergeDeviceInfo(device UnifiedDevice) {
 			if existing.SiteID == nil || *existing.SiteID != newSiteID {
 				// Remove from old site index
 				if existing.SiteID != nil {
-					c.removeMACFromSlice(*existing.SiteID, normalizedMAC, c.SiteIndex)
+					c.removeMACFromSlice(*existing.SiteID, normalizedMAC, c.siteIndex)
 				}
 
 				// Add to new site index
-				c.SiteIndex[newSiteID] = appendUniqueString(c.SiteIndex[newSiteID], normalizedMAC)
+				c.siteIndex[newSiteID] = appendUniqueString(c.siteIndex[newSiteID], normalizedMAC)
 			}
 		}
 
@@ -549,11 +636,11 @@ func (c *DeviceCache) MergeDeviceInfo(device UnifiedDevice) {
 		if deviceType != "" && deviceType != existingType {
 			// Remove from old type index
 			if existingType != "" {
-				c.removeMACFromSlice(existingType, normalizedMAC, c.TypeIndex)
+				c.removeMACFromSlice(existingType, normalizedMAC, c.typeIndex)
 			}
 
 			// Add to new type index
-			c.TypeIndex[deviceType] = appendUniqueString(c.TypeIndex[deviceType], normalizedMAC)
+			c.typeIndex[deviceType] = appendUniqueString(c.typeIndex[deviceType], normalizedMAC)
 		}
 
 		// NameIndex
@@ -564,16 +651,17 @@ func (c *DeviceCache) MergeDeviceInfo(device UnifiedDevice) {
 			if existing.Name == nil || *existing.Name != newName {
 				// Remove old name mapping
 				if existing.Name != nil && *existing.Name != "" {
-					delete(c.NameIndex, *existing.Name)
+					delete(c.nameIndex, *existing.Name)
 				}
 
 				// Add new name mapping
-				c.NameIndex[newName] = normalizedMAC
+				c.nameIndex[newName] = normalizedMAC
 			}
 		}
 	}
 
-	c.LastUpdated = time.Now()
+	c.generation++
+	c.lastUpdated = time.Now()
 }
 
 // Helper function to convert a map to RadioConfig
@@ -955,25 +1043,20 @@ func convertMapToRadioConfig(m map[string]interface{}) RadioConfig {
 
 // recordHit records a cache hit
 func (c *DeviceCache) recordHit() {
-	c.stats.mu.Lock()
-	c.stats.hits++
-	c.stats.mu.Unlock()
+	c.hits.Add(1)
+	runstats.CacheHit("Mist device cache")
 }
 
 // recordMiss records a cache miss
 func (c *DeviceCache) recordMiss() {
-	c.stats.mu.Lock()
-	c.stats.misses++
-	c.stats.mu.Unlock()
+	c.misses.Add(1)
+	runstats.CacheMiss("Mist device cache")
 }
 
 // GetCacheStats returns cache performance statistics
 func (c *DeviceCache) GetCacheStats() (hits, misses int64, hitRate float64) {
-	c.stats.mu.Lock()
-	defer c.stats.mu.Unlock()
-
-	hits = c.stats.hits
-	misses = c.stats.misses
+	hits = c.hits.Load()
+	misses = c.misses.Load()
 	total := hits + misses
 	if total > 0 {
 		hitRate = float64(hits) / float64(total) * 100
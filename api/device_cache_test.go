@@ -0,0 +1,96 @@
+package api
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func testDevice(mac, siteID, deviceType, name string) UnifiedDevice {
+	var d UnifiedDevice
+	d.MAC, d.SiteID, d.Type, d.Name = &mac, &siteID, &deviceType, &name
+	return d
+}
+
+func TestDeviceCacheGeneration(t *testing.T) {
+	c := NewDeviceCache()
+	if c.Generation() != 0 {
+		t.Fatalf("new cache generation = %d", c.Generation())
+	}
+
+	c.AddDevice(testDevice("aa:bb:cc:00:00:01", "site-1", "ap", "ap-1"))
+	c.AddDevice(testDevice("aa:bb:cc:00:00:02", "site-1", "switch", "sw-1"))
+	c.RemoveDevice("aabbcc000002")
+	if got := c.Generation(); got != 3 {
+		t.Errorf("generation after 3 writes = %d", got)
+	}
+
+	before := c.Generation()
+	_ = c.GetDevicesBySite("site-1")
+	if c.Generation() != before {
+		t.Error("reads must not advance the generation")
+	}
+
+	c.Clear()
+	if c.Generation() != before+1 || c.Count() != 0 {
+		t.Errorf("clear: generation %d, count %d", c.Generation(), c.Count())
+	}
+}
+
+func TestDeviceCacheYieldsToNewerSource(t *testing.T) {
+	var source atomic.Uint64
+	source.Store(100)
+	c := NewDeviceCache(WithSourceGeneration(source.Load))
+
+	c.AddDevice(testDevice("aa:bb:cc:00:00:01", "site-1", "ap", "ap-1"))
+	if _, ok := c.GetDeviceByMAC("aabbcc000001"); !ok {
+		t.Fatal("device should be cached while the source is unchanged")
+	}
+
+	// The file cache is rewritten: what is in memory predates it.
+	source.Store(200)
+	if _, ok := c.GetDeviceByMAC("aabbcc000001"); ok {
+		t.Error("stale device returned after the source advanced")
+	}
+	if n := len(c.GetDevicesBySite("site-1")); n != 0 {
+		t.Errorf("stale site index returned %d devices", n)
+	}
+
+	// Data stored after the advance belongs to the new generation.
+	c.AddDevice(testDevice("aa:bb:cc:00:00:02", "site-1", "ap", "ap-2"))
+	if _, ok := c.GetDeviceByName("ap-2"); !ok {
+		t.Error("device stored after the advance should be kept")
+	}
+}
+
+func TestDeviceCacheConcurrentAccess(t *testing.T) {
+	var source atomic.Uint64
+	c := NewDeviceCache(WithSourceGeneration(source.Load))
+
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				mac := fmt.Sprintf("aa:bb:cc:00:%02x:%02x", w, i%256)
+				c.AddDevice(testDevice(mac, fmt.Sprintf("site-%d", w), "ap", fmt.Sprintf("ap-%d-%d", w, i)))
+				_ = c.GetDevicesBySiteAndType(fmt.Sprintf("site-%d", w), "ap")
+				_, _ = c.GetDeviceByMAC(mac)
+				if i%50 == 0 {
+					source.Add(1)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	if c.Generation() == 0 {
+		t.Error("generation should count concurrent writes")
+	}
+	hits, misses, _ := c.GetCacheStats()
+	if hits+misses != 8*200 {
+		t.Errorf("lookups recorded = %d, want %d", hits+misses, 8*200)
+	}
+}
@@ -0,0 +1,33 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// SearchSiteDeviceEvents retrieves the device events of the given types (e.g.
+// "AP_RESTARTED") a site recorded between start and end, following cursor
+// pages. An empty types list returns every device event.
+func (c *mistClient) SearchSiteDeviceEvents(ctx context.Context, siteID string, types []string, start, end time.Time) ([]map[string]interface{}, error) {
+	endpoint := fmt.Sprintf("/sites/%s/devices/events/search?start=%d&end=%d&limit=%d",
+		siteID, start.Unix(), end.Unix(), c.resultsLimit(EndpointDevices))
+	if len(types) > 0 {
+		endpoint += "&type=" + url.QueryEscape(strings.Join(types, ","))
+	}
+
+	rawData, err := c.searchAllPages(ctx, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search device events: %w", err)
+	}
+	results, _ := rawData["results"].([]interface{})
+	events := make([]map[string]interface{}, 0, len(results))
+	for _, r := range results {
+		if ev, ok := r.(map[string]interface{}); ok {
+			events = append(events, ev)
+		}
+	}
+	return events, nil
+}
@@ -27,6 +27,8 @@
 //
 // This package contains legacy cache files (cache_*.go) that read from
 // ~/.cache/wifimgr/cache.json. This cache file is NO LONGER POPULATED.
+// Startup migrates a leftover one into the per-API cache and removes it; see
+// "wifimgr cache migrate-legacy".
 //
 // For cache operations, use the multi-vendor cache system:
 //
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestForceRebuildCacheFetchesInventoryConcurrently(t *testing.T) {
+	var (
+		mu               sync.Mutex
+		inFlight, peak   int
+		inventoryFetched = map[string]bool{}
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/inventory") {
+			mu.Lock()
+			inventoryFetched[r.URL.Query().Get("type")] = true
+			inFlight++
+			peak = max(peak, inFlight)
+			mu.Unlock()
+			time.Sleep(50 * time.Millisecond)
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+		}
+		_, _ = w.Write([]byte("[]"))
+	}))
+	t.Cleanup(srv.Close)
+
+	c := NewClientWithOptions("key", srv.URL+"/api/v1", "o")
+	if err := c.ForceRebuildCache(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for _, typ := range []string{"ap", "switch", "gateway"} {
+		if !inventoryFetched[typ] {
+			t.Errorf("inventory type %s not fetched", typ)
+		}
+	}
+	if peak < 2 {
+		t.Errorf("peak concurrent inventory requests = %d, want at least 2", peak)
+	}
+}
+
+func TestForceRebuildCacheCancelled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("[]"))
+	}))
+	t.Cleanup(srv.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	c := NewClientWithOptions("key", srv.URL+"/api/v1", "o")
+	err := c.ForceRebuildCache(ctx)
+	if err == nil || !strings.Contains(err.Error(), "0/12 data types") {
+		t.Errorf("err = %v, want interrupted after 0/12", err)
+	}
+}
@@ -135,8 +135,19 @@ type MistWLANTemplate struct {
 	Enabled *bool   `json:"enabled,omitempty"`
 	Hidden  *bool   `json:"hidden,omitempty"`
 
+	// Applies is where the template is assigned: the whole org, sites, or
+	// site groups. A template with none of these applies nowhere.
+	Applies *MistTemplateApplies `json:"applies,omitempty"`
+
 	CreatedTime  *int64 `json:"created_time,omitempty"`
 	ModifiedTime *int64 `json:"modified_time,omitempty"`
 
 	AdditionalConfig map[string]any `json:"-"`
 }
+
+// MistTemplateApplies is the assignment scope of a WLAN template
+type MistTemplateApplies struct {
+	OrgID        *string  `json:"org_id,omitempty"`
+	SiteIDs      []string `json:"site_ids,omitempty"`
+	SiteGroupIDs []string `json:"sitegroup_ids,omitempty"`
+}
@@ -62,7 +62,7 @@ func NewMockClient(config Config) Client {
 		apsCache:           newMockCache[[]AP](5 * time.Minute),
 		inventoryCache:     newMockCache[[]InventoryItem](5 * time.Minute),
 		deviceProfileCache: newMockCache[[]DeviceProfile](5 * time.Minute),
-		deviceCache:        nil, // Mock client doesn't need real device cache
+		deviceCache:        NewDeviceCache(),
 		debug:              config.Debug,
 
 		// Initialize mock data stores
@@ -135,6 +135,71 @@ func (m *MockClient) GetAPStats(_ context.Context, _ string) ([]map[string]inter
 	return nil, nil
 }
 
+// GetSwitchStats retrieves switch statistics for a site (mock implementation)
+func (m *MockClient) GetSwitchStats(_ context.Context, _ string) ([]map[string]interface{}, error) {
+	return nil, nil
+}
+
+// SearchClientEvents retrieves client events (mock implementation)
+func (m *MockClient) SearchClientEvents(_ context.Context, _, _ string, _, _ time.Time) ([]map[string]interface{}, error) {
+	return nil, nil
+}
+
+// SearchSiteDeviceEvents retrieves site device events (mock implementation)
+func (m *MockClient) SearchSiteDeviceEvents(_ context.Context, _ string, _ []string, _, _ time.Time) ([]map[string]interface{}, error) {
+	return nil, nil
+}
+
+// SearchSiteRogueEvents retrieves site rogue events (mock implementation)
+func (m *MockClient) SearchSiteRogueEvents(_ context.Context, _ string, _, _ time.Time) ([]map[string]interface{}, error) {
+	return nil, nil
+}
+
+// SearchSiteAlarms retrieves site alarms (mock implementation)
+func (m *MockClient) SearchSiteAlarms(_ context.Context, _, _ string, _, _ time.Time) ([]map[string]interface{}, error) {
+	return nil, nil
+}
+
+// SearchSiteClientSessions retrieves site client sessions (mock implementation)
+func (m *MockClient) SearchSiteClientSessions(_ context.Context, _ string, _, _ time.Time) ([]map[string]interface{}, error) {
+	return nil, nil
+}
+
+// GetSiteInsightMetric retrieves a site insight metric (mock implementation)
+func (m *MockClient) GetSiteInsightMetric(_ context.Context, _, _ string, _, _ time.Time, _ int) (map[string]interface{}, error) {
+	return nil, nil
+}
+
+// UpgradeDevices requests a firmware upgrade (mock implementation)
+func (m *MockClient) UpgradeDevices(_ context.Context, _ string, _ []string, _ string) error {
+	return nil
+}
+
+// GetSiteMaps retrieves the floor plans for a site (mock implementation)
+func (m *MockClient) GetSiteMaps(_ context.Context, _ string) ([]map[string]interface{}, error) {
+	return nil, nil
+}
+
+// GetVirtualChassis returns virtual chassis status (mock implementation)
+func (m *MockClient) GetVirtualChassis(_ context.Context, _, _ string) (map[string]interface{}, error) {
+	return nil, nil
+}
+
+// GetEVPNTopologies lists EVPN topologies (mock implementation)
+func (m *MockClient) GetEVPNTopologies(_ context.Context, _, _ string) ([]map[string]interface{}, error) {
+	return nil, nil
+}
+
+// CreateEVPNTopology creates an EVPN topology (mock implementation)
+func (m *MockClient) CreateEVPNTopology(_ context.Context, _, _ string, topology map[string]interface{}) (map[string]interface{}, error) {
+	return topology, nil
+}
+
+// UpdateEVPNTopology updates an EVPN topology (mock implementation)
+func (m *MockClient) UpdateEVPNTopology(_ context.Context, _, _, _ string, topology map[string]interface{}) (map[string]interface{}, error) {
+	return topology, nil
+}
+
 // GetDeviceConfig retrieves the configuration for a specific device (mock implementation)
 func (m *MockClient) GetDeviceConfig(ctx context.Context, siteID, deviceID string) (*DeviceConfigResponse, error) {
 	// Mock implementation - return empty config
@@ -30,11 +30,17 @@ func (m *MockClient) PopulateDeviceCacheForSite(_ context.Context, _ string, _ s
 
 // GetDeviceCache returns the device cache instance
 func (m *MockClient) GetDeviceCache() *DeviceCache {
-	// Initialize device cache if needed
-	if deviceCache == nil {
-		InitializeDeviceCache()
-	}
-	return deviceCache
+	return m.deviceCache
+}
+
+// ClearCache clears the mock's device cache; inventory is not cached
+func (m *MockClient) ClearCache(_ string) {
+	m.deviceCache.Clear()
+}
+
+// ClearCacheForSite clears the mock's device cache
+func (m *MockClient) ClearCacheForSite(_ string, _ string) {
+	m.deviceCache.Clear()
 }
 
 // GetConfigDirectory returns the configuration directory
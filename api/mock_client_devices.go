@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 )
 
@@ -585,6 +586,18 @@ func (m *MockClient) GetRawDeviceJSON(_ context.Context, siteID, deviceID string
 	return fmt.Sprintf(`{"id": "%s", "site_id": "%s", "mock": true}`, deviceID, siteID), nil
 }
 
+// GetRaw mocks a GET of an unmodeled endpoint with an empty object.
+func (m *MockClient) GetRaw(_ context.Context, path string) (json.RawMessage, error) {
+	m.logRequest("GET", path, nil)
+	return json.RawMessage(`{}`), nil
+}
+
+// PutRaw mocks a PUT to an unmodeled endpoint by echoing the body.
+func (m *MockClient) PutRaw(_ context.Context, path string, body json.RawMessage) (json.RawMessage, error) {
+	m.logRequest("PUT", path, body)
+	return body, nil
+}
+
 // RestartDevice mocks the device restart endpoint.
 func (m *MockClient) RestartDevice(_ context.Context, siteID, deviceID string) error {
 	m.logRequest("POST", fmt.Sprintf("/sites/%s/devices/%s/restart", siteID, deviceID), nil)
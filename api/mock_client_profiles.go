@@ -118,3 +118,26 @@ func (m *MockClient) UnassignDeviceProfiles(ctx context.Context, orgID string, p
 	// Mock successful unassignment
 	return nil
 }
+
+// DeleteDeviceProfile removes a device profile
+func (m *MockClient) DeleteDeviceProfile(ctx context.Context, orgID string, profileID string) error {
+	m.logRequest("DELETE", fmt.Sprintf("/orgs/%s/deviceprofiles/%s", orgID, profileID), nil)
+
+	// Mock API call with rate limiting
+	if m.rateLimiter != nil {
+		if err := m.rateLimiter.Wait(ctx); err != nil {
+			return err
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, profile := range m.deviceProfiles {
+		if profile.ID != nil && *profile.ID == profileID {
+			m.deviceProfiles = append(m.deviceProfiles[:i], m.deviceProfiles[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("device profile not found: %s", profileID)
+}
@@ -19,6 +19,15 @@ func (m *MockClient) GetRFTemplates(_ context.Context, orgID string) ([]MistRFTe
 	}, nil
 }
 
+// DeleteRFTemplate deletes an RF template (mock implementation)
+func (m *MockClient) DeleteRFTemplate(_ context.Context, orgID string, templateID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Mock implementation - always succeed
+	return nil
+}
+
 // GetGatewayTemplates returns mock gateway templates
 func (m *MockClient) GetGatewayTemplates(_ context.Context, orgID string) ([]MistGatewayTemplate, error) {
 	m.mu.RLock()
@@ -34,6 +43,21 @@ func (m *MockClient) GetGatewayTemplates(_ context.Context, orgID string) ([]Mis
 	}, nil
 }
 
+// GetGatewayTemplatesRaw returns gateway templates as raw maps (mock implementation)
+func (m *MockClient) GetGatewayTemplatesRaw(_ context.Context, _ string) ([]map[string]interface{}, error) {
+	return nil, nil
+}
+
+// CreateGatewayTemplate creates a gateway template (mock implementation)
+func (m *MockClient) CreateGatewayTemplate(_ context.Context, _ string, template map[string]interface{}) (map[string]interface{}, error) {
+	return template, nil
+}
+
+// UpdateGatewayTemplate updates a gateway template (mock implementation)
+func (m *MockClient) UpdateGatewayTemplate(_ context.Context, _, _ string, template map[string]interface{}) (map[string]interface{}, error) {
+	return template, nil
+}
+
 // GetWLANTemplates returns mock WLAN templates
 func (m *MockClient) GetWLANTemplates(_ context.Context, orgID string) ([]MistWLANTemplate, error) {
 	m.mu.RLock()
@@ -49,6 +73,15 @@ func (m *MockClient) GetWLANTemplates(_ context.Context, orgID string) ([]MistWL
 	}, nil
 }
 
+// DeleteWLANTemplate deletes a WLAN template (mock implementation)
+func (m *MockClient) DeleteWLANTemplate(_ context.Context, orgID string, templateID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Mock implementation - always succeed
+	return nil
+}
+
 // GetNetworks returns mock networks
 func (m *MockClient) GetNetworks(_ context.Context, orgID string) ([]MistNetwork, error) {
 	m.mu.RLock()
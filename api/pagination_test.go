@@ -0,0 +1,73 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResultsLimit(t *testing.T) {
+	c := &mistClient{}
+	if got := c.resultsLimit(EndpointInventory); got != DefaultResultsLimit {
+		t.Errorf("unconfigured = %d, want %d", got, DefaultResultsLimit)
+	}
+	c.config.ResultsLimit = 250
+	c.config.PageSizes = map[string]int{EndpointInventory: 1000}
+	if got := c.resultsLimit(EndpointInventory); got != 1000 {
+		t.Errorf("inventory = %d, want 1000", got)
+	}
+	if got := c.resultsLimit(EndpointDevices); got != 250 {
+		t.Errorf("devices = %d, want results limit 250", got)
+	}
+}
+
+// searchServer serves three pages of one result each, linked by "next".
+func searchServer(t *testing.T, calls *int) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		page := r.URL.Query().Get("search_after")
+		if page == "" {
+			if r.URL.Query().Get("limit") != "2" {
+				t.Errorf("first request limit = %q, want 2", r.URL.Query().Get("limit"))
+			}
+			page = "0"
+		}
+		var n int
+		_, _ = fmt.Sscan(page, &n)
+		next := ""
+		if n < 2 {
+			next = fmt.Sprintf(`,"next":"/api/v1/orgs/o/clients/search?text=&limit=2&search_after=%d"`, n+1)
+		}
+		_, _ = fmt.Fprintf(w, `{"limit":2,"total":3,"results":[{"mac":"00000000000%d"}]%s}`, n, next)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestSearchFollowsCursor(t *testing.T) {
+	for _, tc := range []struct {
+		pagination string
+		wantCalls  int
+	}{
+		{PaginationAuto, 3},
+		{PaginationPage, 1},
+	} {
+		t.Run(tc.pagination, func(t *testing.T) {
+			var calls int
+			srv := searchServer(t, &calls)
+			c := NewClientWithOptions("key", srv.URL+"/api/v1", "o",
+				WithPageSizes(map[string]int{EndpointClientsSearch: 2}), WithPagination(tc.pagination))
+
+			resp, err := c.SearchWirelessClients(context.Background(), "o", "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if calls != tc.wantCalls || len(resp.Results) != tc.wantCalls {
+				t.Errorf("calls = %d, results = %d; want %d each", calls, len(resp.Results), tc.wantCalls)
+			}
+		})
+	}
+}
@@ -23,7 +23,7 @@ import (
 
 // apiApCmd represents the "show ap" command.
 var apiApCmd = &cobra.Command{
-	Use:   "ap [name-or-mac] [site site-name] [target api-label] [all] [detail|extensive] [format json|csv] [no-resolve]",
+	Use:   "ap [name-or-mac] [site site-name] [target api-label] [filter expr|@name] [all] [detail|extensive] [format json|csv] [no-resolve] [uptime]",
 	Short: "Show access points wifimgr manages (add 'all' for every AP the API knows)",
 	Long: `Show access point data from the local API cache.
 
@@ -36,11 +36,13 @@ Arguments:
   name-or-mac  - Optional AP name or MAC address filter
   site         - Keyword followed by site name for filtering
   target       - Keyword followed by API label to target specific API
+  filter       - Keyword followed by a filter expression or @saved-filter name
   all          - Show every AP the API has, not just managed
   detail       - Reserved verbosity level (field set unchanged for now)
   extensive    - Show all cache fields
   format       - Output format: "json" or "csv" (default: table)
   no-resolve   - Disable field ID to name resolution
+  uptime       - Add live uptime and last-reboot columns (queries the API per site)
 
 Examples:
   wifimgr show ap                          - Managed APs
@@ -48,7 +50,10 @@ Examples:
   wifimgr show ap site US-LAB-01           - Managed APs in a site
   wifimgr show ap AP-NAME                  - A managed AP by name
   wifimgr show ap format json extensive    - Managed APs, all fields, JSON
-  wifimgr show ap target mist-prod         - Managed APs from mist-prod only`,
+  wifimgr show ap target mist-prod         - Managed APs from mist-prod only
+  wifimgr show ap all filter "status!=online"  - Every AP that is not online
+  wifimgr show ap filter @offline-aps      - Apply the saved filter "offline-aps"
+  wifimgr show ap site US-LAB-01 uptime    - Managed APs with uptime and last reboot`,
 	Args: cmdutils.ValidateShowAPArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if cmdutils.ContainsHelp(args) {
@@ -23,7 +23,7 @@ import (
 
 // apiGatewayCmd represents the "show gateway" command
 var apiGatewayCmd = &cobra.Command{
-	Use:   "gateway [name-or-mac] [site site-name] [target api-label] [all] [detail|extensive] [format json|csv] [no-resolve]",
+	Use:   "gateway [name-or-mac] [site site-name] [target api-label] [filter expr|@name] [all] [detail|extensive] [format json|csv] [no-resolve] [uptime]",
 	Short: "Show gateways wifimgr manages (add 'all' for every gateway the API knows)",
 	Long: `Show gateway data from the local API cache.
 
@@ -35,11 +35,13 @@ Arguments:
   name-or-mac  - Optional gateway name or MAC address filter
   site         - Keyword followed by site name for filtering
   target       - Keyword followed by API label to target specific API
+  filter       - Keyword followed by a filter expression or @saved-filter name
   all          - Show every gateway the API has, not just managed
   detail       - Reserved verbosity level (field set unchanged for now)
   extensive    - Show all cache fields
   format       - Output format: "json" or "csv" (default: table)
   no-resolve   - Disable field ID to name resolution
+  uptime       - Add live uptime and last-reboot columns (queries the API per site)
 
 Examples:
   wifimgr show gateway                          - Managed gateways
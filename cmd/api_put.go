@@ -0,0 +1,310 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/ravinald/jsondiff/pkg/jsondiff"
+	"github.com/spf13/cobra"
+
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/common"
+	"github.com/ravinald/wifimgr/internal/history"
+	"github.com/ravinald/wifimgr/internal/i18n"
+	"github.com/ravinald/wifimgr/internal/logging"
+	"github.com/ravinald/wifimgr/internal/supportbundle"
+	"github.com/ravinald/wifimgr/internal/symbols"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+var (
+	apiPutFile    string
+	apiPutConfirm bool
+)
+
+// apiPutCmd represents the "api put" command
+var apiPutCmd = &cobra.Command{
+	Use:   "put <path> --file <body.json> [--confirm] [target <api-label>]",
+	Short: "PUT a JSON body to an API path, after a diff and typed confirmation",
+	Long: `PUT a JSON body to a path on a configured API, for emergencies where no
+wifimgr command covers the change yet. The body is sent as written: none of
+apply's validation, managed keys, or secret handling applies.
+
+The current object is read with a GET first and the diff against the body is
+shown. Secrets are redacted on both sides of the diff and in the echoed
+response; a secret the body changes shows as [REDACTED] (changed). Without --confirm the command stops there. With --confirm the path must
+be typed back before anything is written; there is no way to skip that, so
+the command cannot run with --no-input.
+
+Every write, successful or not, is recorded in CHANGELOG.jsonl in the config
+directory and shows up in 'wifimgr history site' when the path names a site.
+
+Arguments:
+  path              Required. API path, e.g. /sites/<site-id>/setting
+  target <label>    Optional. API to call (required when more than one is configured)`,
+	Example: `  wifimgr api put /sites/<site-id>/setting --file setting.json
+  wifimgr api put /sites/<site-id>/setting --file setting.json --confirm
+  wifimgr api put /networks/<network-id>/wireless/settings --file settings.json --confirm target meraki-corp`,
+	Args: func(_ *cobra.Command, args []string) error {
+		if cmdutils.ContainsHelp(args) {
+			return nil
+		}
+		if len(args) < 1 {
+			return fmt.Errorf("requires an API path")
+		}
+		return nil
+	},
+	RunE: runAPIPut,
+}
+
+func init() {
+	apiPutCmd.Flags().StringVar(&apiPutFile, "file", "", "JSON file holding the request body")
+	apiPutCmd.Flags().BoolVar(&apiPutConfirm, "confirm", false, "Write the change after showing the diff")
+	_ = apiPutCmd.MarkFlagRequired("file")
+	apiCmd.AddCommand(apiPutCmd)
+}
+
+func runAPIPut(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+	path, target, err := parseRawAPIArgs(args)
+	if err != nil {
+		return err
+	}
+	body, err := readRawBody(apiPutFile)
+	if err != nil {
+		return err
+	}
+	api, err := rawAPIClient(target)
+	if err != nil {
+		return err
+	}
+	path = expandRawPath(path, api.client.OrgID())
+
+	current, err := api.raw.RawGet(globalContext, path)
+	if err != nil {
+		return fmt.Errorf("GET %s for the diff: %w", path, err)
+	}
+	diff, err := rawJSONDiff(current, body)
+	if err != nil {
+		return err
+	}
+	if diff == "" {
+		fmt.Printf("%s %s already matches %s; nothing to write\n", symbols.SuccessPrefix(), path, apiPutFile)
+		return nil
+	}
+	fmt.Printf("\nChanges to %s on %s:\n%s\n", path, api.label, diff)
+
+	if !apiPutConfirm {
+		fmt.Println("Nothing written; rerun with --confirm to write this change")
+		return nil
+	}
+	if !confirmRawPut(path) {
+		fmt.Println("Nothing written")
+		return nil
+	}
+
+	start := time.Now()
+	resp, putErr := api.raw.RawPut(globalContext, path, body)
+	recordRawPut(api.label, path, time.Since(start), putErr)
+	if putErr != nil {
+		return fmt.Errorf("PUT %s: %w", path, putErr)
+	}
+	fmt.Printf("%s Wrote %s on %s\n", symbols.SuccessPrefix(), path, api.label)
+	return printRawJSON(redactRawJSON(resp))
+}
+
+// readRawBody reads the request body file and checks that it is JSON.
+func readRawBody(path string) (json.RawMessage, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- operator-supplied body file
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if !json.Valid(data) {
+		return nil, fmt.Errorf("%s is not valid JSON", path)
+	}
+	return json.RawMessage(data), nil
+}
+
+// rawJSONDiff renders the diff from the current object to the body, or ""
+// when they match. Both sides are redacted first; a secret that differs is
+// shown as "[REDACTED] (changed)" so the change is visible but not its value.
+func rawJSONDiff(current, body json.RawMessage) (string, error) {
+	current, body, err := redactForDiff(current, body)
+	if err != nil {
+		return "", err
+	}
+	diffs, err := jsondiff.Diff(current, body, jsondiff.DiffOptions{
+		ContextLines: 3,
+		SortJSON:     true,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to generate diff: %w", err)
+	}
+	if len(diffs) == 0 {
+		return "", nil
+	}
+	diffs = jsondiff.EnhanceDiffsWithInlineChanges(diffs)
+	formatter := jsondiff.NewFormatter(nil)
+	formatter.SetMarkers("API", "File", "Both")
+	return formatter.Format(diffs), nil
+}
+
+// redactedChanged marks a redacted value that differs between the two sides.
+const redactedChanged = common.Redacted + " (changed)"
+
+// redactForDiff returns current and body with secrets redacted for display.
+func redactForDiff(current, body json.RawMessage) (json.RawMessage, json.RawMessage, error) {
+	var cur, next any
+	if err := decodeJSONNumbers(current, &cur); err != nil {
+		return nil, nil, fmt.Errorf("current object is not JSON: %w", err)
+	}
+	if err := decodeJSONNumbers(body, &next); err != nil {
+		return nil, nil, fmt.Errorf("body is not JSON: %w", err)
+	}
+	redactedCur := common.RedactValue(cur)
+	redactedNext := markChangedSecrets(cur, next, common.RedactValue(next))
+	curOut, err := json.Marshal(redactedCur)
+	if err != nil {
+		return nil, nil, err
+	}
+	nextOut, err := json.Marshal(redactedNext)
+	if err != nil {
+		return nil, nil, err
+	}
+	return curOut, nextOut, nil
+}
+
+// decodeJSONNumbers decodes data into v, keeping numbers exact.
+func decodeJSONNumbers(data json.RawMessage, v *any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+// markChangedSecrets walks redacted, the redacted copy of next, and replaces
+// each redacted value whose original differs from the one in cur with
+// redactedChanged.
+func markChangedSecrets(cur, next, redacted any) any {
+	switch r := redacted.(type) {
+	case string:
+		if r == common.Redacted && !reflect.DeepEqual(cur, next) {
+			return redactedChanged
+		}
+	case map[string]any:
+		curMap, _ := cur.(map[string]any)
+		nextMap, _ := next.(map[string]any)
+		for k, v := range r {
+			r[k] = markChangedSecrets(curMap[k], nextMap[k], v)
+		}
+	case []any:
+		curList, _ := cur.([]any)
+		nextList, _ := next.([]any)
+		for i, v := range r {
+			var c, n any
+			if i < len(curList) {
+				c = curList[i]
+			}
+			if i < len(nextList) {
+				n = nextList[i]
+			}
+			r[i] = markChangedSecrets(c, n, v)
+		}
+	}
+	return redacted
+}
+
+// confirmRawPut asks for the path to be typed back. --yes does not count,
+// and it fails closed under --no-input.
+func confirmRawPut(path string) bool {
+	if cmdutils.NoInput() {
+		fmt.Println(i18n.T("api_put.no_input"))
+		return false
+	}
+	fmt.Printf("%s %s\n", symbols.WarningPrefix(), i18n.T("api_put.warning"))
+	fmt.Print(i18n.T("api_put.type_path", path))
+	input, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false
+	}
+	return strings.TrimSpace(input) == path
+}
+
+// recordRawPut appends the write to the changelog. Like apply's history, a
+// failure to record is logged, never returned: the write already happened.
+func recordRawPut(apiLabel, path string, elapsed time.Duration, putErr error) {
+	entry := history.Entry{
+		Site:       rawPathSiteName(path),
+		API:        apiLabel,
+		DeviceType: history.DeviceTypeRawAPI,
+		Method:     "PUT",
+		Path:       path,
+		DurationMS: elapsed.Milliseconds(),
+		Result:     history.ResultSuccess,
+	}
+	if putErr != nil {
+		// API errors can echo the request body back.
+		entry.Result = history.ResultFailed
+		entry.Error = supportbundle.ScrubLogLine(putErr.Error())
+	}
+	configDir, err := changelogDir()
+	if err != nil {
+		logging.Warnf("Raw API write not recorded: %v", err)
+		return
+	}
+	if err := history.Append(configDir, entry); err != nil {
+		logging.Warnf("Failed to record raw API write: %v", err)
+	}
+}
+
+// rawPathSiteName names the cached site a path is under (/sites/<id> on
+// Mist, /networks/<id> on Meraki), or returns "" for other paths.
+func rawPathSiteName(path string) string {
+	id := rawPathSiteID(path)
+	if id == "" {
+		return ""
+	}
+	accessor := vendors.GetGlobalCacheAccessor()
+	if accessor == nil {
+		return ""
+	}
+	site, err := accessor.GetSiteByID(id)
+	if err != nil {
+		return ""
+	}
+	return site.Name
+}
+
+// rawPathSiteID returns the ID following a sites or networks segment.
+func rawPathSiteID(path string) string {
+	path, _, _ = strings.Cut(path, "?")
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i := 0; i+1 < len(segments); i++ {
+		if segments[i] == "sites" || segments[i] == "networks" {
+			return segments[i+1]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,199 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/common"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+// apiCmd groups direct calls to a vendor API.
+var apiCmd = &cobra.Command{
+	Use:   "api",
+	Short: "Call vendor API endpoints wifimgr does not model",
+	Long: `Call a vendor API endpoint directly, with the credentials of a configured API
+label, for endpoints no wifimgr command covers yet. No curl, and no token on
+the command line or in shell history.`,
+	Example: `  wifimgr api get /orgs/{org_id}/alarmtemplates
+  wifimgr api put /sites/<site-id>/setting --file setting.json --confirm`,
+}
+
+// apiGetCmd represents the "api get" command
+var apiGetCmd = &cobra.Command{
+	Use:   "get <path> [target <api-label>]",
+	Short: "GET an API path and print the JSON response",
+	Long: `GET a path on a configured API and pretty-print the JSON response. Read-only:
+nothing is written to the API or the cache.
+
+Secrets in the response (PSKs, RADIUS secrets, tokens, and the other fields
+listed under redaction in the configuration guide) are shown as [REDACTED];
+--show-secrets prints the response as the API returned it.
+
+The path is relative to the API root; {org_id} is replaced with the API's
+org. A list response is paged through and printed as one array. A Mist
+search response follows its next links and prints the merged results. To
+fetch a single Mist page, give a page parameter in the path.
+
+Supported for Mist and Meraki APIs.
+
+Arguments:
+  path              Required. API path, e.g. /orgs/{org_id}/sites
+  target <label>    Optional. API to call (required when more than one is configured)`,
+	Example: `  wifimgr api get /orgs/{org_id}/alarmtemplates
+  wifimgr api get /sites/<site-id>/stats/devices target mist-prod
+  wifimgr api get /organizations/{org_id}/networks target meraki-corp
+  wifimgr api get /sites/<site-id>/wlans --show-secrets`,
+	Args: func(_ *cobra.Command, args []string) error {
+		if cmdutils.ContainsHelp(args) {
+			return nil
+		}
+		if len(args) < 1 {
+			return fmt.Errorf("requires an API path")
+		}
+		return nil
+	},
+	RunE: runAPIGet,
+}
+
+var apiGetShowSecrets bool
+
+func init() {
+	apiGetCmd.Flags().BoolVar(&apiGetShowSecrets, "show-secrets", false, "Print secrets in the response instead of [REDACTED]")
+	apiCmd.AddCommand(apiGetCmd)
+	rootCmd.AddCommand(apiCmd)
+}
+
+func runAPIGet(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+	path, target, err := parseRawAPIArgs(args)
+	if err != nil {
+		return err
+	}
+	api, err := rawAPIClient(target)
+	if err != nil {
+		return err
+	}
+
+	body, err := api.raw.RawGet(globalContext, expandRawPath(path, api.client.OrgID()))
+	if err != nil {
+		return fmt.Errorf("GET %s: %w", path, err)
+	}
+	if !apiGetShowSecrets {
+		body = redactRawJSON(body)
+	}
+	return printRawJSON(body)
+}
+
+// parseRawAPIArgs splits the api subcommand arguments into the path and the
+// optional target label.
+func parseRawAPIArgs(args []string) (path, target string, err error) {
+	for i := 0; i < len(args); i++ {
+		switch {
+		case strings.EqualFold(args[i], "target"):
+			if i+1 >= len(args) {
+				return "", "", fmt.Errorf("'target' requires an API label")
+			}
+			target = cmdutils.StripQuotes(args[i+1])
+			i++
+		case path == "":
+			path = cmdutils.StripQuotes(args[i])
+		default:
+			return "", "", fmt.Errorf("unknown argument %q", args[i])
+		}
+	}
+	if path == "" {
+		return "", "", fmt.Errorf("requires an API path")
+	}
+	return path, target, nil
+}
+
+// rawAPI is the API an api subcommand calls.
+type rawAPI struct {
+	label  string
+	client vendors.Client
+	raw    vendors.RawAPI
+}
+
+// rawAPIClient returns the API for target, or the only configured API when
+// target is empty, along with its raw request capability.
+func rawAPIClient(target string) (*rawAPI, error) {
+	registry := GetAPIRegistry()
+	if registry == nil || registry.Count() == 0 {
+		return nil, fmt.Errorf("no API configured")
+	}
+	if target == "" {
+		labels := registry.GetAllLabels()
+		if len(labels) > 1 {
+			return nil, fmt.Errorf("more than one API is configured; add 'target <api-label>' (one of: %s)", strings.Join(labels, ", "))
+		}
+		target = labels[0]
+	}
+	if !registry.HasAPI(target) {
+		return nil, FormatAPINotFoundError(target)
+	}
+	client, err := registry.GetClient(target)
+	if err != nil {
+		return nil, err
+	}
+	raw, ok := client.(vendors.RawAPI)
+	if !ok {
+		return nil, &vendors.CapabilityNotSupportedError{
+			Capability:  "raw API calls",
+			APILabel:    target,
+			VendorName:  client.VendorName(),
+			SupportedBy: []string{"mist", "meraki"},
+		}
+	}
+	return &rawAPI{label: target, client: client, raw: raw}, nil
+}
+
+// expandRawPath roots path at "/" and fills in the {org_id} placeholder.
+func expandRawPath(path, orgID string) string {
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return strings.ReplaceAll(path, "{org_id}", orgID)
+}
+
+// redactRawJSON returns body with sensitive fields replaced by [REDACTED]. A
+// body that is not JSON is replaced by a placeholder.
+func redactRawJSON(body json.RawMessage) json.RawMessage {
+	return json.RawMessage(common.RedactJSON(body))
+}
+
+// printRawJSON pretty-prints a JSON response to stdout.
+func printRawJSON(body json.RawMessage) error {
+	var out bytes.Buffer
+	if err := json.Indent(&out, body, "", "  "); err != nil {
+		// Not JSON after all; show what came back rather than nothing.
+		_, err = os.Stdout.Write(append(body, '\n'))
+		return err
+	}
+	out.WriteByte('\n')
+	_, err := out.WriteTo(os.Stdout)
+	return err
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseRawAPIArgs(t *testing.T) {
+	path, target, err := parseRawAPIArgs([]string{"/orgs/{org_id}/sites", "target", `"mist-prod"`})
+	if err != nil || path != "/orgs/{org_id}/sites" || target != "mist-prod" {
+		t.Errorf("got %q, %q, %v", path, target, err)
+	}
+	if _, _, err := parseRawAPIArgs([]string{"target", "mist-prod"}); err == nil {
+		t.Error("missing path accepted")
+	}
+	if _, _, err := parseRawAPIArgs([]string{"/orgs", "/sites"}); err == nil {
+		t.Error("second path accepted")
+	}
+}
+
+func TestExpandRawPath(t *testing.T) {
+	tests := map[string]string{
+		"/orgs/{org_id}/sites":    "/orgs/org-1/sites",
+		"orgs/{org_id}/wlans":     "/orgs/org-1/wlans",
+		"/sites/s1/stats?limit=5": "/sites/s1/stats?limit=5",
+	}
+	for in, want := range tests {
+		if got := expandRawPath(in, "org-1"); got != want {
+			t.Errorf("expandRawPath(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestRawPathSiteID(t *testing.T) {
+	tests := map[string]string{
+		"/sites/s1/setting":                  "s1",
+		"/api/v1/networks/N_1/wireless/ssid": "N_1",
+		"/sites/s2?page=1":                   "s2",
+		"/orgs/org-1/alarmtemplates":         "",
+		"/sites":                             "",
+	}
+	for in, want := range tests {
+		if got := rawPathSiteID(in); got != want {
+			t.Errorf("rawPathSiteID(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestRawJSONDiff(t *testing.T) {
+	diff, err := rawJSONDiff([]byte(`{"b":1,"a":true}`), []byte(`{"a":true,"b":1}`))
+	if err != nil || diff != "" {
+		t.Errorf("reordered keys diff = %q, %v", diff, err)
+	}
+	diff, err = rawJSONDiff([]byte(`{"a":true}`), []byte(`{"a":false}`))
+	if err != nil || diff == "" {
+		t.Errorf("changed value diff = %q, %v", diff, err)
+	}
+
+	// A secret-only change still shows, without either value.
+	diff, err = rawJSONDiff([]byte(`{"auth":{"type":"psk","psk":"old-secret"}}`), []byte(`{"auth":{"type":"psk","psk":"new-secret"}}`))
+	if err != nil || !strings.Contains(diff, redactedChanged) || strings.Contains(diff, "secret\"") {
+		t.Errorf("changed secret diff = %q, %v", diff, err)
+	}
+	diff, err = rawJSONDiff([]byte(`{"psk":"same","name":"a"}`), []byte(`{"psk":"same","name":"b"}`))
+	if err != nil || strings.Contains(diff, redactedChanged) || strings.Contains(diff, "same") {
+		t.Errorf("unchanged secret diff = %q, %v", diff, err)
+	}
+}
+
+func TestRedactRawJSON(t *testing.T) {
+	got := string(redactRawJSON([]byte(`[{"ssid":"corp","auth":{"type":"psk","psk":"hunter22"}}]`)))
+	if strings.Contains(got, "hunter22") || !strings.Contains(got, `"ssid":"corp"`) {
+		t.Errorf("redactRawJSON = %s", got)
+	}
+}
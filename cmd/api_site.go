@@ -23,7 +23,7 @@ import (
 
 // apiSiteCmd represents the "show site" command
 var apiSiteCmd = &cobra.Command{
-	Use:     "site [site-name] [target api-label] [all] [detail|extensive] [format json|csv]",
+	Use:     "site [site-name] [target api-label] [filter expr|@name] [all] [detail|extensive] [format json|csv]",
 	Aliases: []string{"sites"},
 	Short:   "Show sites wifimgr manages (add 'all' for every site the API knows)",
 	Long: `Show site data from the local API cache.
@@ -35,6 +35,7 @@ knows about.
 Arguments:
   site-name  - Optional site name filter
   target     - Keyword followed by API label to target specific API
+  filter     - Keyword followed by a filter expression or @saved-filter name
   all        - Show every site the API has, not just managed
   detail     - Reserved verbosity level (field set unchanged for now)
   extensive  - Show all cache fields
@@ -23,7 +23,7 @@ import (
 
 // apiSwitchCmd represents the "show switch" command
 var apiSwitchCmd = &cobra.Command{
-	Use:   "switch [name-or-mac] [site site-name] [target api-label] [all] [detail|extensive] [format json|csv] [no-resolve]",
+	Use:   "switch [name-or-mac] [site site-name] [target api-label] [filter expr|@name] [all] [detail|extensive] [format json|csv] [no-resolve] [uptime]",
 	Short: "Show switches wifimgr manages (add 'all' for every switch the API knows)",
 	Long: `Show switch data from the local API cache.
 
@@ -35,11 +35,13 @@ Arguments:
   name-or-mac  - Optional switch name or MAC address filter
   site         - Keyword followed by site name for filtering
   target       - Keyword followed by API label to target specific API
+  filter       - Keyword followed by a filter expression or @saved-filter name
   all          - Show every switch the API has, not just managed
   detail       - Reserved verbosity level (field set unchanged for now)
   extensive    - Show all cache fields
   format       - Output format: "json" or "csv" (default: table)
   no-resolve   - Disable field ID to name resolution
+  uptime       - Add live uptime and last-reboot columns (queries the API per site)
 
 Examples:
   wifimgr show switch                          - Managed switches
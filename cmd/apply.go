@@ -17,6 +17,7 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 
@@ -24,6 +25,9 @@ import (
 	"github.com/ravinald/wifimgr/internal/cmdutils"
 )
 
+// applyExplain prints the apply step order instead of applying.
+var applyExplain bool
+
 // applyCmd represents the apply command
 var applyCmd = &cobra.Command{
 	Use:   "apply",
@@ -46,8 +50,15 @@ Use 'wifimgr apply <subcommand> --help' for detailed information about each oper
   # Rollback to previous configuration
   wifimgr apply rollback US-LAB-01
 
+  # Restore one site from a whole-file backup, keeping other sites' edits
+  wifimgr apply restore-site US-LAB-01 from us-sites.json.3
+
   # List available backups
-  wifimgr apply list-backups US-LAB-01`,
+  wifimgr apply list-backups US-LAB-01
+
+  # Show the order apply pushes resources in
+  wifimgr apply --explain
+  wifimgr apply ap US-LAB-01 --explain`,
 	// Handle legacy positional arguments for backward compatibility
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// If called with legacy format: apply <site-name> <operation>
@@ -62,7 +73,7 @@ Use 'wifimgr apply <subcommand> --help' for detailed information about each oper
 
 			// Route to appropriate subcommand based on operation
 			switch parsed.Operation {
-			case "rollback", "list-backups", "cleanup-backups", "validate-backup":
+			case "rollback", "restore-site", "list-backups", "cleanup-backups", "validate-backup":
 				// These are backup operations, pass through
 				return apply.HandleCommand(globalContext, vendorClientForApply(""), globalConfig, args, "", false)
 			default:
@@ -71,11 +82,27 @@ Use 'wifimgr apply <subcommand> --help' for detailed information about each oper
 			}
 		}
 
+		if applyExplain {
+			return explainApplyOrder("")
+		}
+
 		// No args provided, show help
 		return cmd.Help()
 	},
 }
 
+// explainApplyOrder prints the apply dependency graph for deviceType, or the
+// whole graph when deviceType is empty. The bulk "all" run applies APs only, so
+// it explains as ap.
+func explainApplyOrder(deviceType string) error {
+	if deviceType == "all" {
+		deviceType = "ap"
+	}
+	return apply.ExplainOrder(os.Stdout, deviceType)
+}
+
 func init() {
 	rootCmd.AddCommand(applyCmd)
+	applyCmd.PersistentFlags().BoolVar(&applyExplain, "explain", false,
+		"Show the order apply pushes resources in and why, without applying")
 }
@@ -16,15 +16,29 @@ import (
 
 	"github.com/ravinald/wifimgr/internal/cmdutils"
 	"github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/helpers"
+	"github.com/ravinald/wifimgr/internal/i18n"
 	"github.com/ravinald/wifimgr/internal/logging"
 	"github.com/ravinald/wifimgr/internal/macaddr"
 	"github.com/ravinald/wifimgr/internal/symbols"
 	"github.com/ravinald/wifimgr/internal/vendors"
+	"github.com/ravinald/wifimgr/internal/warnings"
 	"github.com/ravinald/wifimgr/internal/xdg"
 )
 
-// HandleCommand processes apply-related subcommands
+// HandleCommand processes apply-related subcommands. What the run changed and
+// the warnings it raised are collected and printed as grouped sections at the
+// end: the change summary, then the warnings.
 func HandleCommand(ctx context.Context, client vendors.Client, cfg *config.Config, args []string, apiLabel string, force bool) error {
+	collector := warnings.New()
+	defer collector.Render(os.Stdout)
+	summary := newApplySummary()
+	defer summary.Render(os.Stdout)
+	ctx = withApplySummary(warnings.NewContext(ctx, collector), summary)
+	return handleCommand(ctx, client, cfg, args, apiLabel, force)
+}
+
+func handleCommand(ctx context.Context, client vendors.Client, cfg *config.Config, args []string, apiLabel string, force bool) error {
 	if len(args) < 2 {
 		logging.Error("Not enough parameters provided for apply command")
 		return fmt.Errorf("apply command requires at least 2 parameters: <site_name> <device_type|all>")
@@ -56,6 +70,37 @@ func HandleCommand(ctx context.Context, client vendors.Client, cfg *config.Confi
 		viper.Set("split_diff", true)
 	}
 
+	// Pushes to a site under approvals.sites are held as pending change
+	// bundles until a second operator approves them. Rollback and restore-site
+	// only rewrite local intent files, so they are not held.
+	switch command {
+	case "list-backups", "cleanup-backups", "validate-backup", "rollback", "restore-site":
+	default:
+		if !diffMode {
+			if held, err := requestApproval(ctx, client, cfg, args, apiLabel, force); held || err != nil {
+				return err
+			}
+		}
+	}
+
+	// Pushes (device applies, device profiles, rollback) honor the change-freeze
+	// calendar and hold the site's apply lock; diff and the read-only backup
+	// commands do neither.
+	switch command {
+	case "list-backups", "cleanup-backups", "validate-backup":
+	default:
+		if !diffMode {
+			if err := enforceChangeFreeze(ctx, cfg, siteName, force); err != nil {
+				return err
+			}
+			release, err := acquireSiteLock(ctx, siteName, command)
+			if err != nil {
+				return err
+			}
+			defer release()
+		}
+	}
+
 	// Handle backup management commands
 	switch command {
 	case "rollback":
@@ -66,6 +111,8 @@ func HandleCommand(ctx context.Context, client vendors.Client, cfg *config.Confi
 		return handleCleanupBackupsCommand(cfg, args[2:])
 	case "validate-backup":
 		return handleValidateBackupCommand(args[2:])
+	case "restore-site":
+		return handleRestoreSiteCommand(cfg, siteName, args[2:])
 	case "device-profile":
 		// Handle device-profile apply command
 		deviceFilter := "all"
@@ -73,6 +120,10 @@ func HandleCommand(ctx context.Context, client vendors.Client, cfg *config.Confi
 			deviceFilter = args[2]
 		}
 		return applyDeviceProfiles(ctx, client, cfg, siteName, deviceFilter, force, diffMode)
+	case "fabric":
+		return applyFabric(ctx, client, cfg, siteName, force, diffMode)
+	case "wan-edge":
+		return applyWANEdge(ctx, client, cfg, siteName, force, diffMode)
 	}
 
 	// Standard device type apply command
@@ -124,7 +175,18 @@ func applyDeviceToSite(ctx context.Context, client vendors.Client, cfg *config.C
 	}
 
 	var errs []error
-	for _, api := range apis {
+	for i, api := range apis {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, &vendors.InterruptedError{
+				Op:      fmt.Sprintf("apply %s to %s", deviceType, siteName),
+				Done:    i,
+				Total:   len(apis),
+				Pending: apis[i:],
+				Outcome: "the remaining APIs were not applied",
+				Err:     err,
+			})
+			break
+		}
 		allowed := make(map[string]bool, len(groups[api]))
 		for _, mac := range groups[api] {
 			allowed[mac] = true
@@ -208,13 +270,19 @@ type SiteConfig struct {
 		Radio  []string `json:"radio,omitempty"`  // Radio template labels
 		Device []string `json:"device,omitempty"` // Device template labels
 	} `json:"profiles,omitempty"`
-	WLAN    []string `json:"wlan,omitempty"` // WLANs to APPLY to all APs (site-wide default)
-	Devices struct {
+	WLAN          []string                  `json:"wlan,omitempty"`           // WLANs to APPLY to all APs (site-wide default)
+	WLANDisabled  []string                  `json:"wlan_disabled,omitempty"`  // WLAN labels held disabled at this site
+	WLANOverrides map[string]map[string]any `json:"wlan_overrides,omitempty"` // WLAN label -> fields that differ at this site
+	Vars          map[string]any            `json:"vars,omitempty"`           // site variables templates reference as ${vars.<name>}
+	Firmware      map[string]string         `json:"firmware,omitempty"`       // model -> pinned firmware version
+	Devices       struct {
 		APs      map[string]map[string]any `json:"ap"`      // AP is a map of MAC -> config
 		Switches map[string]map[string]any `json:"switch"`  // Switch is a map of MAC -> config
 		WanEdge  map[string]map[string]any `json:"gateway"` // Gateway is a map of MAC -> config
 	} `json:"devices"`
-	LastModified string `json:"last_modified,omitempty"` // UTC timestamp when config was last modified
+	Fabric       *FabricConfig  `json:"fabric,omitempty"`        // Campus fabric (EVPN) topologies, Mist only
+	WANEdge      *WANEdgeConfig `json:"wan_edge,omitempty"`      // Gateway app policy / traffic steering, Mist only
+	LastModified string         `json:"last_modified,omitempty"` // UTC timestamp when config was last modified
 }
 
 // ConfigFileStructure represents the structure of a site config file
@@ -360,12 +428,19 @@ func cleanupOldBackups(cfg *config.Config, _ int) error {
 // handleRollbackCommand handles file-based rollback of intent configuration
 // This does NOT send anything to the API - it only manipulates config files.
 // The operator can then review, edit, diff, and explicitly apply when ready.
+// Args are an optional backup index and "force", which takes the backup's
+// side of every merge conflict without asking.
 func handleRollbackCommand(_ context.Context, _ vendors.Client, cfg *config.Config, siteName string, args []string) error {
 	// Parse backup index (default 0 = most recent backup)
 	backupIndex := 0
-	if len(args) > 0 {
-		if _, err := fmt.Sscanf(args[0], "%d", &backupIndex); err != nil {
-			return fmt.Errorf("invalid backup index: %s (expected a number)", args[0])
+	force := false
+	for _, arg := range args {
+		if arg == "force" {
+			force = true
+			continue
+		}
+		if _, err := fmt.Sscanf(arg, "%d", &backupIndex); err != nil {
+			return fmt.Errorf("invalid backup index: %s (expected a number)", arg)
 		}
 	}
 
@@ -375,8 +450,18 @@ func handleRollbackCommand(_ context.Context, _ vendors.Client, cfg *config.Conf
 		return err
 	}
 
-	// Perform file-based rollback
-	return rollbackConfigFile(cfg, siteName, configFilePath, backupIndex)
+	// Site-scoped backups restore only the site's block
+	if siteScopedBackups() {
+		err = rollbackSiteBlock(cfg, siteName, configFilePath, backupIndex, force)
+	} else {
+		// Perform file-based rollback
+		err = rollbackConfigFile(cfg, siteName, configFilePath, backupIndex, force)
+	}
+	if errors.Is(err, errRollbackCancelled) {
+		fmt.Println(i18n.T("rollback.cancelled"))
+		return nil
+	}
+	return err
 }
 
 // findConfigFileForSite finds the config file path that contains the specified site
@@ -414,7 +499,11 @@ func findConfigFileForSite(cfg *config.Config, siteName string) (string, error)
 // 1. Rotates existing backups (increment indices)
 // 2. Copies current intent config to new .0 backup
 // 3. Copies selected backup to become current intent config
-func rollbackConfigFile(cfg *config.Config, siteName string, configFilePath string, backupIndex int) error {
+//
+// When an older backup is restored and the config was edited since the most
+// recent one, step 3 writes a three-way merge instead (see rollback_merge.go),
+// so those edits are not lost.
+func rollbackConfigFile(cfg *config.Config, siteName string, configFilePath string, backupIndex int, force bool) error {
 	backupDir := filepath.Join(cfg.Files.ConfigDir, "backups")
 	baseFileName := filepath.Base(configFilePath)
 
@@ -434,6 +523,23 @@ func rollbackConfigFile(cfg *config.Config, siteName string, configFilePath stri
 	fmt.Printf("  Config file: %s\n", configFilePath)
 	fmt.Printf("  Backup file: %s\n", backupFilePath)
 
+	// Settle the merge before anything is rotated or written, so declining
+	// a conflict leaves every file as it was.
+	var merged map[string]any
+	if backupIndex > 0 {
+		plan, err := planRollbackFileMerge(configFilePath, filepath.Join(backupDir, baseFileName+".0"), backupFilePath)
+		if err != nil {
+			return err
+		}
+		if plan != nil {
+			printRollbackMerge(plan)
+			if err := resolveRollbackConflicts(plan, force); err != nil {
+				return err
+			}
+			merged = plan.Merged
+		}
+	}
+
 	// Step 1: Rotate existing backups to make room for new .0
 	logging.Debugf("Rotating existing backups")
 	if err := rotateConfigFileBackups(backupDir, baseFileName, maxBackups); err != nil {
@@ -460,7 +566,7 @@ func rollbackConfigFile(cfg *config.Config, siteName string, configFilePath stri
 	}
 
 	newBackupPath := filepath.Join(backupDir, fmt.Sprintf("%s.0", baseFileName))
-	if err := os.WriteFile(newBackupPath, backupData, 0600); err != nil {
+	if err := helpers.WriteFileAtomic(newBackupPath, backupData, 0600); err != nil {
 		return fmt.Errorf("failed to create backup: %w", err)
 	}
 	fmt.Printf("  Created backup: %s.0 (previous config)\n", baseFileName)
@@ -470,16 +576,29 @@ func rollbackConfigFile(cfg *config.Config, siteName string, configFilePath stri
 	shiftedBackupPath := filepath.Join(backupDir, fmt.Sprintf("%s.%d", baseFileName, backupIndex+1))
 	logging.Debugf("Restoring from %s to %s", shiftedBackupPath, configFilePath)
 
-	restoreData, err := os.ReadFile(shiftedBackupPath) // #nosec G304 -- path from operator-controlled config
-	if err != nil {
-		return fmt.Errorf("failed to read backup file: %w", err)
+	var restoreData []byte
+	if merged != nil {
+		out, err := json.MarshalIndent(merged, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal merged config: %w", err)
+		}
+		restoreData = append(out, '\n')
+	} else {
+		restoreData, err = os.ReadFile(shiftedBackupPath) // #nosec G304 -- path from operator-controlled config
+		if err != nil {
+			return fmt.Errorf("failed to read backup file: %w", err)
+		}
 	}
 
 	if err := os.WriteFile(configFilePath, restoreData, 0600); err != nil { // #nosec G304 G703 -- path from operator-controlled config
 		return fmt.Errorf("failed to restore config: %w", err)
 	}
 
-	fmt.Printf("  Restored: %s.%d -> %s\n", baseFileName, backupIndex+1, baseFileName)
+	if merged != nil {
+		fmt.Printf("  Restored: %s.%d -> %s, merged with newer edits\n", baseFileName, backupIndex+1, baseFileName)
+	} else {
+		fmt.Printf("  Restored: %s.%d -> %s\n", baseFileName, backupIndex+1, baseFileName)
+	}
 	fmt.Printf("\nRollback complete. The configuration has NOT been applied to the API.\n")
 	fmt.Printf("To review changes: wifimgr apply site %s ap diff\n", siteName)
 	fmt.Printf("To apply changes:  wifimgr apply site %s ap\n", siteName)
@@ -501,7 +620,7 @@ func handleListBackupsCommand(cfg *config.Config, siteName string) error {
 
 	fmt.Printf("Configuration backups for site %s:\n\n", siteName)
 	fmt.Printf("%-20s %-10s %-15s %s\n", "Timestamp", "Devices", "Operation", "File")
-	fmt.Printf("%-20s %-10s %-15s %s\n", "────────────────────", "───────", "──────────────", "────")
+	fmt.Printf("%-20s %-10s %-15s %s\n", symbols.Rule(20), symbols.Rule(7), symbols.Rule(14), symbols.Rule(4))
 
 	for _, backup := range backups {
 		timestamp := time.Unix(backup.Timestamp, 0).Format("2006-01-02 15:04:05")
@@ -533,19 +652,25 @@ func handleCleanupBackupsCommand(cfg *config.Config, args []string) error {
 	return cleanupOldBackups(cfg, maxAgeDays)
 }
 
+// resolveBackupPath finds a backup named on the command line: as given when
+// absolute or present in the current directory, otherwise in the XDG backups
+// directory.
+func resolveBackupPath(backupFile string) string {
+	if !filepath.IsAbs(backupFile) {
+		if _, err := os.Stat(backupFile); os.IsNotExist(err) {
+			return filepath.Join(config.BackupDir(), backupFile)
+		}
+	}
+	return backupFile
+}
+
 // handleValidateBackupCommand validates the integrity of a config backup file
 func handleValidateBackupCommand(args []string) error {
 	if len(args) == 0 {
 		return fmt.Errorf("backup file path required for validation")
 	}
 
-	backupFile := args[0]
-	if !filepath.IsAbs(backupFile) {
-		// Try current directory first, then assume relative to XDG backups
-		if _, err := os.Stat(backupFile); os.IsNotExist(err) {
-			backupFile = filepath.Join(xdg.GetBackupsDir(), backupFile)
-		}
-	}
+	backupFile := resolveBackupPath(args[0])
 
 	fmt.Printf("Validating backup file: %s\n", backupFile)
 
@@ -556,6 +681,10 @@ func handleValidateBackupCommand(args []string) error {
 		return err
 	}
 
+	if siteBackup, ok := readSiteBackup(backupData); ok {
+		return validateSiteBackup(siteBackup)
+	}
+
 	// Parse as config file structure
 	var configData ConfigFileStructure
 	if err := json.Unmarshal(backupData, &configData); err != nil {
@@ -0,0 +1,412 @@
+package apply
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+
+	"github.com/ravinald/wifimgr/api"
+	"github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/history"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+// These tests drive applySiteGeneric end to end against a fixture org: the
+// site, template and inventory files in testdata/e2e, a cache seeded with the
+// org's live state, and a vendor client that records every write. They pin
+// what the apply engine decides (assign, unassign, update, WLAN create and
+// update, managed_keys filtering) and what diff mode prints, so a refactor of
+// the engine that changes either shows up here.
+//
+// Run with -update to rewrite the diff output snapshots after an intended
+// change to the output.
+
+var updateSnapshots = flag.Bool("update", false, "rewrite the apply e2e output snapshots")
+
+const (
+	e2eAPI    = "mist-lab"
+	e2eSite   = "US-LAB-01"
+	e2eSiteID = "site-001"
+)
+
+// fixtureOrg is the vendor client apply talks to. Reads come from the mock
+// services and the seeded cache; writes are recorded in calls.
+type fixtureOrg struct {
+	*vendors.MockClient
+	legacy *fixtureLegacy
+	calls  []string
+}
+
+func (o *fixtureOrg) LegacyClient() any { return o.legacy }
+
+func (o *fixtureOrg) record(format string, args ...any) {
+	o.calls = append(o.calls, fmt.Sprintf(format, args...))
+}
+
+// fixtureInventory records site assignments. A set assignErr fails every
+// assignment without recording it.
+type fixtureInventory struct {
+	vendors.InventoryService
+	org       *fixtureOrg
+	assignErr error
+}
+
+func (i *fixtureInventory) AssignToSite(_ context.Context, siteID string, macs []string) error {
+	if i.assignErr != nil {
+		return i.assignErr
+	}
+	i.org.record("assign %s %s", siteID, strings.Join(macs, ","))
+	return nil
+}
+
+func (i *fixtureInventory) UnassignFromSite(_ context.Context, macs []string) error {
+	i.org.record("unassign %s", strings.Join(macs, ","))
+	return nil
+}
+
+// fixtureLegacy stands in for the Mist API client, which apply uses for site
+// WLANs and device pushes. Methods apply does not reach are left to the nil
+// embedded interface and panic if called.
+type fixtureLegacy struct {
+	api.Client
+	org     *fixtureOrg
+	wlans   []api.MistWLAN
+	devices map[string]*api.UnifiedDevice
+}
+
+func (l *fixtureLegacy) GetSiteWLANs(_ context.Context, _ string) ([]api.MistWLAN, error) {
+	return l.wlans, nil
+}
+
+func (l *fixtureLegacy) CreateSiteWLAN(_ context.Context, siteID string, wlan *api.MistWLAN) (*api.MistWLAN, error) {
+	l.org.record("create wlan %s in %s: %s", *wlan.SSID, siteID, wlanSummary(wlan))
+	wlan.ID = api.StringPtr("wlan-" + strings.ToLower(*wlan.SSID))
+	l.wlans = append(l.wlans, *wlan)
+	return wlan, nil
+}
+
+func (l *fixtureLegacy) UpdateSiteWLAN(_ context.Context, siteID, wlanID string, wlan *api.MistWLAN) (*api.MistWLAN, error) {
+	l.org.record("update wlan %s (%s) in %s: %s", *wlan.SSID, wlanID, siteID, wlanSummary(wlan))
+	wlan.ID = api.StringPtr(wlanID)
+	for i := range l.wlans {
+		if *l.wlans[i].ID == wlanID {
+			l.wlans[i] = *wlan
+		}
+	}
+	return wlan, nil
+}
+
+func (l *fixtureLegacy) GetDeviceProfiles(_ context.Context, _, _ string) ([]api.DeviceProfile, error) {
+	return nil, nil
+}
+
+func (l *fixtureLegacy) UpdateDevice(_ context.Context, siteID, deviceID string, device *api.UnifiedDevice) (*api.UnifiedDevice, error) {
+	l.devices[deviceID] = device
+	l.org.record("update device %s in %s", deviceID, siteID)
+	return device, nil
+}
+
+func (l *fixtureLegacy) GetDeviceCache() *api.DeviceCache { return nil }
+
+func wlanSummary(w *api.MistWLAN) string {
+	parts := []string{}
+	if w.VlanID != nil {
+		parts = append(parts, fmt.Sprintf("vlan_id=%d", *w.VlanID))
+	}
+	if w.ApplyTo != nil {
+		parts = append(parts, "apply_to="+*w.ApplyTo)
+	}
+	if w.ApIDs != nil {
+		ids := slices.Clone(*w.ApIDs)
+		slices.Sort(ids)
+		parts = append(parts, "ap_ids="+strings.Join(ids, ","))
+	}
+	return strings.Join(parts, " ")
+}
+
+// e2eFixture is one run's copy of the fixture org.
+type e2eFixture struct {
+	cfg *config.Config
+	org *fixtureOrg
+}
+
+// newE2EFixture copies testdata/e2e into a temporary config directory, seeds
+// the cache with the org's state and installs it as the global accessor.
+//
+// Live state: aa..01 and aa..02 are assigned to the site, aa..01 with stale
+// notes and an unmanaged height that differs from intent; aa..03 is in the
+// org but unassigned; aa..04 is assigned but no longer in the site config.
+// The site carries the Corp WLAN on the wrong VLAN and no Guest WLAN.
+func newE2EFixture(t *testing.T) *e2eFixture {
+	t.Helper()
+
+	dir := t.TempDir()
+	for _, name := range []string{"sites.json", "templates.json", "inventory.json"} {
+		data, err := os.ReadFile(filepath.Join("testdata", "e2e", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("CONFIG_DIR", dir)
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	cfg := &config.Config{}
+	cfg.Files.ConfigDir = dir
+	cfg.Files.SiteConfigs = []string{"sites.json"}
+	cfg.Files.Templates = []string{"templates.json"}
+	cfg.Files.Inventory = filepath.Join(dir, "inventory.json")
+
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	viper.Set("api."+e2eAPI+".managed_keys.ap", []string{"name", "notes"})
+	viper.Set("api."+e2eAPI+".apply_verify", false)
+
+	org := &fixtureOrg{MockClient: vendors.NewMockClientWithAllServices("mist", "org-1")}
+	org.legacy = &fixtureLegacy{
+		org:     org,
+		devices: map[string]*api.UnifiedDevice{},
+		wlans: []api.MistWLAN{{
+			ID:      api.StringPtr("wlan-corp"),
+			SSID:    api.StringPtr("Corp"),
+			Enabled: api.BoolPtr(true),
+			VlanID:  api.IntPtr(20),
+			ApplyTo: api.StringPtr("aps"),
+			ApIDs:   &[]string{"d1", "d2"},
+		}},
+	}
+	org.legacy.wlans[0].Auth.Type = api.StringPtr("eap")
+	org.SetInventoryService(&fixtureInventory{InventoryService: org.Inventory(), org: org})
+
+	registry := vendors.NewAPIClientRegistry()
+	registry.RegisterClient(e2eAPI, org, nil)
+	cm := vendors.NewCacheManager(t.TempDir(), registry)
+	if err := cm.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	cache := vendors.NewAPICache(e2eAPI, "mist", "org-1")
+	cache.Sites.Info = []vendors.SiteInfo{{ID: e2eSiteID, Name: e2eSite}}
+	aps := []struct {
+		mac, id, name, siteID string
+		config                map[string]any
+	}{
+		{"aa0000000001", "d1", "lab-ap-01", e2eSiteID, map[string]any{"notes": "old", "height": float64(5)}},
+		{"aa0000000002", "d2", "lab-ap-02", e2eSiteID, map[string]any{"notes": "hallway"}},
+		{"aa0000000003", "d3", "lab-ap-03", "", map[string]any{}},
+		{"aa0000000004", "d4", "lab-ap-04", e2eSiteID, map[string]any{}},
+	}
+	for _, ap := range aps {
+		cache.Inventory.AP[ap.mac] = &vendors.InventoryItem{ID: ap.id, MAC: ap.mac, Name: ap.name, Model: "AP43", Type: "ap", SiteID: ap.siteID}
+		cache.Configs.AP[ap.mac] = &vendors.APConfig{ID: ap.id, MAC: ap.mac, Name: ap.name, SiteID: ap.siteID, Config: ap.config}
+	}
+	if err := cm.SaveAPICache(cache); err != nil {
+		t.Fatal(err)
+	}
+	if err := cm.RebuildIndex(); err != nil {
+		t.Fatal(err)
+	}
+
+	prev := vendors.GetGlobalCacheAccessor()
+	vendors.SetGlobalCacheAccessor(vendors.NewCacheAccessor(cm))
+	invalidateInventorySnapshot()
+	t.Cleanup(func() {
+		vendors.SetGlobalCacheAccessor(prev)
+		invalidateInventorySnapshot()
+		setTemplateStore(nil, "")
+	})
+
+	return &e2eFixture{cfg: cfg, org: org}
+}
+
+// apply runs applySiteGeneric for the site's APs and returns what it printed.
+func (f *e2eFixture) apply(t *testing.T, force, diffMode bool) string {
+	t.Helper()
+	out, err := f.run(t, force, diffMode)
+	if err != nil {
+		t.Fatalf("applySiteGeneric: %v\noutput:\n%s", err, out)
+	}
+	return out
+}
+
+// run is apply for runs expected to fail: it returns the apply error.
+func (f *e2eFixture) run(t *testing.T, force, diffMode bool) (string, error) {
+	t.Helper()
+	stdout := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout = w
+	done := make(chan string)
+	go func() {
+		var buf bytes.Buffer
+		_, _ = io.Copy(&buf, r)
+		done <- buf.String()
+	}()
+
+	applyErr := applySiteGeneric(context.Background(), f.org, f.cfg, e2eSite, "ap", e2eAPI, force, diffMode, false, nil)
+
+	_ = w.Close()
+	os.Stdout = stdout
+	return <-done, applyErr
+}
+
+// checkSnapshot compares out with testdata/e2e/<name>, or rewrites it under -update.
+func checkSnapshot(t *testing.T, name, out string) {
+	t.Helper()
+	path := filepath.Join("testdata", "e2e", name)
+	if *updateSnapshots {
+		if err := os.WriteFile(path, []byte(out), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read snapshot (run with -update to create it): %v", err)
+	}
+	if out != string(want) {
+		t.Errorf("output differs from %s (run with -update if intended)\ngot:\n%s\nwant:\n%s", path, out, want)
+	}
+}
+
+func TestApplyE2E_DiffModeWritesNothing(t *testing.T) {
+	f := newE2EFixture(t)
+	out := f.apply(t, false, true)
+
+	if len(f.org.calls) != 0 {
+		t.Errorf("diff mode wrote to the API: %v", f.org.calls)
+	}
+	checkSnapshot(t, "diff.golden", out)
+}
+
+func TestApplyE2E_Apply(t *testing.T) {
+	f := newE2EFixture(t)
+	f.apply(t, false, false)
+
+	want := []string{
+		"update wlan Corp (wlan-corp) in site-001: vlan_id=10 apply_to=aps ap_ids=d1,d2,d3",
+		"create wlan Guest in site-001: vlan_id=99 apply_to=site",
+		"unassign aa0000000004",
+		"assign site-001 aa0000000003",
+		"update device d1 in site-001",
+	}
+	if !slices.Equal(f.org.calls, want) {
+		t.Errorf("calls:\n  %s\nwant:\n  %s", strings.Join(f.org.calls, "\n  "), strings.Join(want, "\n  "))
+	}
+
+	// Only managed keys are pushed: the notes change goes out, the unmanaged
+	// height keeps its live value.
+	pushed := f.org.legacy.devices["d1"]
+	if pushed == nil {
+		t.Fatal("aa0000000001 was not pushed")
+	}
+	if pushed.Notes == nil || *pushed.Notes != "lobby" {
+		t.Errorf("pushed notes = %v, want lobby", pushed.Notes)
+	}
+	if _, ok := pushed.DeviceConfig["height"]; ok {
+		t.Errorf("unmanaged height was pushed: %v", pushed.DeviceConfig)
+	}
+}
+
+func TestApplyE2E_UnmanagedDriftIsIgnored(t *testing.T) {
+	f := newE2EFixture(t)
+	// With notes unmanaged, aa..01 differs from intent only in fields apply
+	// does not own, so no device is updated.
+	viper.Set("api."+e2eAPI+".managed_keys.ap", []string{"name"})
+	f.apply(t, false, false)
+
+	for _, call := range f.org.calls {
+		if strings.HasPrefix(call, "update device") {
+			t.Errorf("unexpected device push: %s", call)
+		}
+	}
+}
+
+func TestApplyE2E_ForcePushesEveryDevice(t *testing.T) {
+	f := newE2EFixture(t)
+	f.apply(t, true, false)
+
+	var updated []string
+	for _, call := range f.org.calls {
+		if strings.HasPrefix(call, "update device") {
+			updated = append(updated, call)
+		}
+	}
+	slices.Sort(updated)
+	// aa..03 is assigned in the same run but is not in the cache for this
+	// site yet, so force has nothing to push it against.
+	want := []string{"update device d1 in site-001", "update device d2 in site-001"}
+	if !slices.Equal(updated, want) {
+		t.Errorf("force updated %v, want %v", updated, want)
+	}
+}
+
+func TestApplyE2E_AssignedAPJoinsWLANAfterAssignment(t *testing.T) {
+	f := newE2EFixture(t)
+	// aa..03 has no device ID until it is assigned, so the Corp push before
+	// assignment can only name d1 and d2. The site's device list, read after
+	// the assignment, supplies d3 and Corp is patched in the same run.
+	ap, err := vendors.GetGlobalCacheAccessor().GetAPByMAC("aa0000000003")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ap.ID = ""
+	devices := vendors.NewMockDevicesService()
+	devices.Devices = append(devices.Devices, &vendors.DeviceInfo{ID: "d3", MAC: "aa0000000003", Type: "ap", SiteID: e2eSiteID})
+	f.org.SetDevicesService(devices)
+
+	f.apply(t, false, false)
+
+	want := []string{
+		"update wlan Corp (wlan-corp) in site-001: vlan_id=10 apply_to=aps ap_ids=d1,d2",
+		"create wlan Guest in site-001: vlan_id=99 apply_to=site",
+		"unassign aa0000000004",
+		"assign site-001 aa0000000003",
+		"update wlan Corp (wlan-corp) in site-001: vlan_id=10 apply_to=aps ap_ids=d1,d2,d3",
+		"update device d1 in site-001",
+	}
+	if !slices.Equal(f.org.calls, want) {
+		t.Errorf("calls:\n  %s\nwant:\n  %s", strings.Join(f.org.calls, "\n  "), strings.Join(want, "\n  "))
+	}
+}
+
+func TestApplyE2E_FailedRunRecordsOnlyAppliedDevices(t *testing.T) {
+	f := newE2EFixture(t)
+	f.org.SetInventoryService(&fixtureInventory{
+		InventoryService: f.org.Inventory(),
+		org:              f.org,
+		assignErr:        errors.New("claim rejected"),
+	})
+	if _, err := f.run(t, false, false); err == nil {
+		t.Fatal("expected the failed assignment to fail the apply")
+	}
+
+	entries, err := history.Read(history.Path(f.cfg.Files.ConfigDir), e2eSite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("history entries = %+v, want one", entries)
+	}
+	got := entries[0]
+	// The unassign went out before the assignment failed; nothing was
+	// assigned, and the update step never ran.
+	if got.Result != history.ResultFailed || !slices.Equal(got.Unassigned, []string{"aa0000000004"}) ||
+		len(got.Assigned) != 0 || len(got.Updated) != 0 {
+		t.Errorf("history entry = %+v, want failed with only aa0000000004 unassigned", got)
+	}
+}
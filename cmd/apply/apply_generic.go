@@ -19,10 +19,15 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/ravinald/wifimgr/api"
+	"github.com/ravinald/wifimgr/internal/canonical"
+	"github.com/ravinald/wifimgr/internal/common"
 	configPkg "github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/history"
 	"github.com/ravinald/wifimgr/internal/logging"
+	"github.com/ravinald/wifimgr/internal/notify"
 	"github.com/ravinald/wifimgr/internal/symbols"
 	"github.com/ravinald/wifimgr/internal/vendors"
+	"github.com/ravinald/wifimgr/internal/warnings"
 )
 
 // FileHashCache stores file hashes and modification times for change detection
@@ -71,12 +76,39 @@ func getManagedKeysForDevice(apiLabel, deviceType string) []string {
 	return nil
 }
 
+// getDiffRulesForDevice returns the canonicalization rules for a device type
+// from api.<apiLabel>.managed_keys.ignore.<deviceType>. Invalid rules are
+// warned about and none are applied, so a typo never hides real drift.
+func getDiffRulesForDevice(apiLabel, deviceType string) []canonical.Rule {
+	if apiLabel == "" {
+		return nil
+	}
+	keyPath := fmt.Sprintf("api.%s.managed_keys.ignore.%s", apiLabel, deviceType)
+	rules, err := canonical.ParseRules(viper.Get(keyPath))
+	if err != nil {
+		logging.Warnf("Ignoring %s: %v", keyPath, err)
+		return nil
+	}
+	return rules
+}
+
 // isManagedKeysConfigured checks if managed keys are configured for a device type
 func isManagedKeysConfigured(apiLabel, deviceType string) bool {
 	keys := getManagedKeysForDevice(apiLabel, deviceType)
 	return len(keys) > 0
 }
 
+// isReadOnlyAPI reports whether the API is marked read-only, by its preset or
+// api.<label>.read_only.
+func isReadOnlyAPI(apiLabel string) bool {
+	registry := vendors.GetGlobalRegistry()
+	if registry == nil {
+		return false
+	}
+	cfg, err := registry.GetConfig(apiLabel)
+	return err == nil && cfg != nil && cfg.ReadOnly
+}
+
 // applySiteGeneric applies device configuration to a site using the generic framework.
 // When refreshAPI is true, the cache is refreshed from the API before applying changes.
 // When refreshAPI is false (default), the existing cache is used for efficiency.
@@ -84,7 +116,12 @@ func isManagedKeysConfigured(apiLabel, deviceType string) bool {
 // client. allowedMACs, when non-nil, restricts the run to that set of
 // (normalized) MACs — the per-device-API grouping in applyDeviceToSite uses it
 // to hand each vendor only its own devices. nil means every configured device.
-func applySiteGeneric(ctx context.Context, client vendors.Client, cfg *configPkg.Config, siteName string, deviceType string, apiLabel string, force bool, diffMode bool, refreshAPI bool, allowedMACs map[string]bool) error {
+func applySiteGeneric(ctx context.Context, client vendors.Client, cfg *configPkg.Config, siteName string, deviceType string, apiLabel string, force bool, diffMode bool, refreshAPI bool, allowedMACs map[string]bool) (retErr error) {
+	start := time.Now()
+	warn := warnings.FromContext(ctx)
+	warnMark := warn.Len()
+	summary := summaryFromContext(ctx)
+
 	// Get the appropriate device updater
 	updater, err := getDeviceUpdater(deviceType)
 	if err != nil {
@@ -93,10 +130,16 @@ func applySiteGeneric(ctx context.Context, client vendors.Client, cfg *configPkg
 
 	logging.Infof("Applying %s configuration to site: %s (API: %s)", deviceType, siteName, apiLabel)
 
+	// Read only this site's slice of the cache. A no-op when something earlier
+	// in the run already loaded every API cache.
+	if accessor := vendors.GetGlobalCacheAccessor(); accessor != nil {
+		accessor.LoadSite(siteName)
+	}
+
 	// Load templates if configured
 	templates, err := loadTemplatesFromConfig(cfg)
 	if err != nil {
-		logging.Warnf("Failed to load templates: %v - continuing without template expansion", err)
+		warn.Add("Templates", "", "failed to load templates (%v); continuing without template expansion", err)
 		templates = configPkg.NewTemplateStore()
 	} else if !templates.IsEmpty() {
 		templateList := templates.ListTemplates()
@@ -109,15 +152,19 @@ func applySiteGeneric(ctx context.Context, client vendors.Client, cfg *configPkg
 
 	// Check if managed keys are configured for this device type
 	if !isManagedKeysConfigured(apiLabel, deviceType) {
-		logging.Warnf("WARNING: api.%s.managed_keys.%s is not configured", apiLabel, deviceType)
-		fmt.Printf("\nWARNING: No managed keys configured for %s devices.\n", deviceType)
-		fmt.Printf("   Configuration differences will be shown, but NO changes will be applied.\n")
-		fmt.Printf("   Please configure api.%s.managed_keys.%s in your wifimgr-configPkg.json file.\n\n", apiLabel, deviceType)
+		warn.Add("Managed keys", "", "api.%s.managed_keys.%s is not configured; differences are shown but no %s changes are applied", apiLabel, deviceType, deviceType)
 
 		// Force diff mode to show what would be changed
 		diffMode = true
 	}
 
+	// Sandbox and demo environments reject writes; show the diff instead of
+	// failing part way through the push.
+	if isReadOnlyAPI(apiLabel) && !diffMode {
+		warn.Add("Read-only API", "", "api.%s is read-only; differences are shown but not applied", apiLabel)
+		diffMode = true
+	}
+
 	if diffMode {
 		fmt.Println("Diff mode enabled - showing changes without applying them")
 		viper.Set("show_diff", true)
@@ -149,6 +196,8 @@ func applySiteGeneric(ctx context.Context, client vendors.Client, cfg *configPkg
 	if err != nil {
 		return err
 	}
+	templates = templatesForSite(templates, siteName, siteConfig)
+	setTemplateStore(templates, apiLabel)
 
 	// Step 3: Get site ID
 	siteID, err := getSiteIDByName(client, siteName)
@@ -179,6 +228,8 @@ func applySiteGeneric(ctx context.Context, client vendors.Client, cfg *configPkg
 
 	// Step 5: Get configured and assigned devices
 	configuredDevices := updater.GetConfiguredDevices(siteConfig)
+	// Sorted so diffs and pushes run in the same order every time
+	slices.Sort(configuredDevices)
 
 	// Restrict to this vendor's devices when grouping by per-device API. MACs
 	// from GetConfiguredDevices are already normalized, matching the filter set.
@@ -207,7 +258,7 @@ func applySiteGeneric(ctx context.Context, client vendors.Client, cfg *configPkg
 		if errors.Is(err, configPkg.ErrLegacyInventorySchema) {
 			return err
 		}
-		logging.Warnf("Could not create inventory checker: %v - some safety checks may be skipped", err)
+		warn.Add("Inventory", "", "could not create inventory checker (%v); some safety checks may be skipped", err)
 	} else {
 		// Store on updater for reuse in FindDevicesInventoryStatus and UpdateDeviceConfigurations
 		updater.SetInventoryChecker(inventoryChecker)
@@ -239,8 +290,7 @@ func applySiteGeneric(ctx context.Context, client vendors.Client, cfg *configPkg
 		if !status.InCache {
 			hasWarnings = true
 			devicesNotInInventory = append(devicesNotInInventory, status.MAC)
-			logging.Warnf("%s %s: not found in API inventory", deviceType, status.MAC)
-			fmt.Printf("→ %s %s: not found in API inventory\n", deviceType, status.MAC)
+			warn.Add("Inventory", deviceType+" "+status.MAC, "not found in API inventory")
 			continue // Skip other checks if not in cache
 		}
 
@@ -248,8 +298,7 @@ func applySiteGeneric(ctx context.Context, client vendors.Client, cfg *configPkg
 		if !status.InInventory {
 			hasWarnings = true
 			devicesNotInInventory = append(devicesNotInInventory, status.MAC)
-			logging.Warnf("%s %s: not in local inventory file", deviceType, status.MAC)
-			fmt.Printf("→ %s %s: not in local inventory file\n", deviceType, status.MAC)
+			warn.Add("Inventory", deviceType+" "+status.MAC, "not in local inventory file")
 		}
 
 		// Device is assigned to a different site
@@ -259,8 +308,7 @@ func applySiteGeneric(ctx context.Context, client vendors.Client, cfg *configPkg
 			if status.CurrentSiteName != "" {
 				siteInfo = fmt.Sprintf("%s (%s)", status.CurrentSiteName, status.CurrentSiteID)
 			}
-			logging.Warnf("%s %s: assigned to different site %s", deviceType, status.MAC, siteInfo)
-			fmt.Printf("→ %s %s: assigned to different site %s\n", deviceType, status.MAC, siteInfo)
+			warn.Add("Inventory", deviceType+" "+status.MAC, "assigned to different site %s", siteInfo)
 		}
 	}
 
@@ -311,129 +359,199 @@ func applySiteGeneric(ctx context.Context, client vendors.Client, cfg *configPkg
 		logging.Infof("Found %d %ss to assign to site %s", len(devicesToAssign), deviceType, siteName)
 	}
 
-	// Step 8.5: Apply WLANs BEFORE device updates (WLANs must exist for device WLAN assignments)
-	// WLANs are site-level resources that devices reference
-	wlanChanges := 0
-	if deviceType == "ap" {
-		wlanChangeCount, err := applyWLANs(ctx, client, cfg, siteConfig, siteID, apiLabel, diffMode, force)
-		if err != nil {
-			logging.Errorf("Error applying WLANs: %v", err)
-			// Don't fail the whole apply, just warn
-			fmt.Fprintf(os.Stderr, "Warning: Failed to apply WLANs: %v\n", err)
-		} else {
-			wlanChanges = wlanChangeCount
-		}
-	}
-
-	// Step 9: Find devices to update. FindDevicesToUpdate also populates the
-	// updater's batch loader and renders the diff, so it runs even under force.
-	devicesToUpdate, err := updater.FindDevicesToUpdate(ctx, client, cfg, siteConfig, configuredDevicesFiltered, siteID, apiLabel)
-	if err != nil {
-		logging.Errorf("Error finding %ss to update: %v", deviceType, err)
-		return fmt.Errorf("error finding %ss to update: %v", deviceType, err)
-	}
-
-	// Filter to only update devices that are in inventory
-	if inventoryCheckerForFilter != nil {
-		devicesToUpdate = inventoryCheckerForFilter.FilterByInventory(devicesToUpdate)
-	}
-
-	// force re-pushes intent to every configured (in-inventory) device, even
-	// where the comparison found no difference — a deliberate "apply anyway".
-	if force {
-		devicesToUpdate = configuredDevicesFiltered
-		if inventoryCheckerForFilter != nil {
-			devicesToUpdate = inventoryCheckerForFilter.FilterByInventory(devicesToUpdate)
-		}
-	}
-
-	if len(devicesToUpdate) > 0 {
-		logging.Infof("Found %d %ss to update in site %s", len(devicesToUpdate), deviceType, siteName)
-	}
-
+	var devicesToUpdate []string
+	// assigned, updated and unassigned hold only the devices each step actually
+	// changed, so the history entry and hooks reflect a failed or partial run.
+	var assigned, updated, unassigned []string
 	// divergentDevices collects MACs whose running config did not match intent after a
 	// successful push (verify mode) — apply fails if any remain.
 	var divergentDevices []string
+	wlanChanges := 0
 
-	// Step 9.5: Apply all changes (unassign, assign, update)
-	// Note: API state backup is not created by default. The intent config backup (created after apply)
-	// is sufficient for most rollback scenarios. Use "refresh-api" positional argument to refresh
-	// cache from API before apply if drift detection is needed.
-	if diffMode {
-		// Show what would be changed
-		if len(devicesToUnassign) > 0 {
-			fmt.Printf("Would unassign the following %ss from site %s:\n", deviceType, siteName)
-			for _, device := range devicesToUnassign {
-				fmt.Printf("  - %s\n", device)
+	// Record the run in the config-dir changelog, and tell the integrations,
+	// once anything may have been pushed. WLAN changes go live below, so a later failure still records.
+	if !diffMode {
+		preHealth := preApplyHealth(ctx, client, siteName, siteID)
+		defer func() {
+			entry := history.Entry{
+				Site:         siteName,
+				API:          apiLabel,
+				DeviceType:   deviceType,
+				Assigned:     assigned,
+				Updated:      updated,
+				Unassigned:   unassigned,
+				WLANsChanged: wlanChanges,
+				DurationMS:   time.Since(start).Milliseconds(),
+				Warnings:     warn.Since(warnMark),
 			}
-		}
-		if len(devicesToAssign) > 0 {
-			fmt.Printf("Would assign the following %ss to site %s:\n", deviceType, siteName)
-			for _, device := range devicesToAssign {
-				fmt.Printf("  - %s\n", device)
+			// Health is only compared when the run changed something
+			if entry.DevicesChanged() > 0 || entry.WLANsChanged > 0 {
+				entry.Health = postApplyHealth(ctx, client, siteName, siteID, preHealth)
+				reportApplyHealth(ctx, cfg, siteName, deviceType, entry.Health)
 			}
-		}
-		if len(devicesToUpdate) > 0 {
-			fmt.Printf("Would update the following %ss in site %s:\n", deviceType, siteName)
-			for _, device := range devicesToUpdate {
-				fmt.Printf("  - %s\n", device)
-			}
-		}
+			recordApplyHistory(cfg, entry, divergentDevices, retErr)
+			runPostApplyHooks(ctx, cfg, entry, retErr)
+		}()
+	}
 
-		// Show device summary
-		totalDevices := len(configuredDevicesFiltered)
-		upToDate := totalDevices - len(devicesToUpdate) - len(devicesToAssign)
-		if totalDevices > 0 {
-			if len(devicesToUpdate) == 0 && len(devicesToAssign) == 0 {
-				fmt.Printf("Devices: %d %s(s) checked, all up to date\n", totalDevices, deviceType)
+	// Step 8.5: Push each resource type in dependency order (see applySteps),
+	// so a resource exists before anything that references it: WLANs go
+	// before devices because AP WLAN assignments reference them.
+	steps, err := applyOrder(deviceType)
+	if err != nil {
+		return err
+	}
+	for _, step := range steps {
+		switch step.Name {
+		case stepWLANs:
+			wlanChangeCount, announcements, err := applyWLANs(ctx, client, cfg, siteConfig, siteID, apiLabel, diffMode, force, devicesToAssign)
+			if err != nil {
+				// Don't fail the whole apply, just warn
+				warn.Add("WLANs", "", "failed to apply WLANs: %v", err)
 			} else {
-				fmt.Printf("Devices: %d %s(s) checked, %d need updates, %d up to date\n",
-					totalDevices, deviceType, len(devicesToUpdate)+len(devicesToAssign), upToDate)
+				wlanChanges = wlanChangeCount
 			}
-		}
-	} else {
-		// Apply changes in order: unassign, assign, update
-		if len(devicesToUnassign) > 0 {
-			if err := updater.UnassignDevices(ctx, client, cfg, devicesToUnassign); err != nil {
-				logging.Errorf("Error unassigning %ss: %v", deviceType, err)
-				return fmt.Errorf("error unassigning %ss: %v", deviceType, err)
+			notifyWLANChanges(ctx, cfg, siteName, announcements)
+		case stepDevices:
+			// Step 9: Find devices to update. FindDevicesToUpdate also populates the
+			// updater's batch loader and renders the diff, so it runs even under force.
+			devicesToUpdate, err = updater.FindDevicesToUpdate(ctx, client, cfg, siteConfig, configuredDevicesFiltered, siteID, apiLabel)
+			if err != nil {
+				logging.Errorf("Error finding %ss to update: %v", deviceType, err)
+				return fmt.Errorf("error finding %ss to update: %v", deviceType, err)
 			}
-		}
-		if len(devicesToAssign) > 0 {
-			if err := updater.AssignDevices(ctx, client, cfg, devicesToAssign, siteID); err != nil {
-				logging.Errorf("Error assigning %ss: %v", deviceType, err)
-				return fmt.Errorf("error assigning %ss: %v", deviceType, err)
+
+			// Filter to only update devices that are in inventory
+			if inventoryCheckerForFilter != nil {
+				devicesToUpdate = inventoryCheckerForFilter.FilterByInventory(devicesToUpdate)
 			}
-		}
-		if len(devicesToUpdate) > 0 {
-			succeeded, upErr := updater.UpdateDeviceConfigurations(ctx, client, cfg, siteConfig, devicesToUpdate, siteID, apiLabel)
-			// Verify (or trust) the devices that pushed: record per-object state, cache
-			// the running config, and collect any that did not realize intent.
-			if len(succeeded) > 0 {
-				diverged, vErr := recordApplyOutcome(ctx, client, updater, cfg, siteConfig, deviceType, siteID, apiLabel, succeeded)
-				if vErr != nil {
-					logging.Warnf("post-apply verify for %s: %v", deviceType, vErr)
+
+			// force re-pushes intent to every configured (in-inventory) device, even
+			// where the comparison found no difference — a deliberate "apply anyway".
+			if force {
+				devicesToUpdate = configuredDevicesFiltered
+				if inventoryCheckerForFilter != nil {
+					devicesToUpdate = inventoryCheckerForFilter.FilterByInventory(devicesToUpdate)
 				}
-				divergentDevices = append(divergentDevices, diverged...)
 			}
-			if upErr != nil {
-				logging.Errorf("Error updating %s configurations: %v", deviceType, upErr)
-				return fmt.Errorf("error updating %s configurations: %w", deviceType, upErr)
+
+			if len(devicesToUpdate) > 0 {
+				logging.Infof("Found %d %ss to update in site %s", len(devicesToUpdate), deviceType, siteName)
+			}
+
+			// Step 9.5: Apply all changes (unassign, assign, update)
+			// Note: API state backup is not created by default. The intent config backup (created after apply)
+			// is sufficient for most rollback scenarios. Use "refresh-api" positional argument to refresh
+			// cache from API before apply if drift detection is needed.
+			if diffMode {
+				// Record what would be changed; the summary lists it at the end
+				if summary == nil && len(devicesToUnassign) > 0 {
+					fmt.Printf("Would unassign the following %ss from site %s:\n", deviceType, siteName)
+				}
+				summary.addDevices(sectionUnassign, siteName, deviceType, resultPending, devicesToUnassign)
+				if summary == nil && len(devicesToAssign) > 0 {
+					fmt.Printf("Would assign the following %ss to site %s:\n", deviceType, siteName)
+				}
+				summary.addDevices(sectionAssign, siteName, deviceType, resultPending, devicesToAssign)
+				if len(devicesToUpdate) > 0 {
+					if summary == nil {
+						fmt.Printf("Would update the following %ss in site %s:\n", deviceType, siteName)
+					}
+					summary.addDevices(sectionUpdate, siteName, deviceType, resultPending, devicesToUpdate)
+					reportIPConfigChanges(updater, deviceType, devicesToUpdate)
+				}
+				reportRadioRegulatory(updater, deviceType)
+			} else {
+				if err := ctx.Err(); err != nil {
+					return fmt.Errorf("apply interrupted before any %s was changed in site %s: %w", deviceType, siteName, err)
+				}
+				// Radio settings the site's country does not permit fail the apply
+				// before anything is changed
+				if err := checkRadioRegulatory(updater, deviceType, siteName); err != nil {
+					return err
+				}
+				// Apply changes in order: unassign, assign, update
+				if len(devicesToUnassign) > 0 {
+					stepStart := time.Now()
+					err := updater.UnassignDevices(ctx, client, cfg, devicesToUnassign)
+					summary.timed(sectionUnassign, stepStart)
+					if err != nil {
+						summary.addDevices(sectionUnassign, siteName, deviceType, resultFailed, devicesToUnassign)
+						logging.Errorf("Error unassigning %ss: %v", deviceType, err)
+						return fmt.Errorf("error unassigning %ss: %v", deviceType, err)
+					}
+					summary.addDevices(sectionUnassign, siteName, deviceType, resultDone, devicesToUnassign)
+					unassigned = devicesToUnassign
+				}
+				if len(devicesToAssign) > 0 {
+					stepStart := time.Now()
+					err := updater.AssignDevices(ctx, client, cfg, devicesToAssign, siteID)
+					summary.timed(sectionAssign, stepStart)
+					if err != nil {
+						summary.addDevices(sectionAssign, siteName, deviceType, resultFailed, devicesToAssign)
+						logging.Errorf("Error assigning %ss: %v", deviceType, err)
+						return fmt.Errorf("error assigning %ss: %v", deviceType, err)
+					}
+					summary.addDevices(sectionAssign, siteName, deviceType, resultDone, devicesToAssign)
+					assigned = devicesToAssign
+					// WLANs pushed above could not name the new APs yet
+					if deviceType == "ap" {
+						wlanChanges += reconcileWLANAPIDs(ctx, client, siteConfig, siteID, apiLabel, devicesToAssign)
+					}
+				}
+				// Management-IP changes get their own confirmation
+				devicesToUpdate = guardIPConfigChanges(updater, deviceType, devicesToUpdate, force)
+				if len(devicesToUpdate) > 0 {
+					stepStart := time.Now()
+					succeeded, upErr := updater.UpdateDeviceConfigurations(ctx, client, cfg, siteConfig, devicesToUpdate, siteID, apiLabel)
+					summary.timed(sectionUpdate, stepStart)
+					summary.addDevices(sectionUpdate, siteName, deviceType, resultDone, succeeded)
+					updated = succeeded
+					if upErr != nil {
+						summary.addDevices(sectionUpdate, siteName, deviceType, resultFailed, withoutMACs(devicesToUpdate, succeeded))
+					}
+					// Verify (or trust) the devices that pushed: record per-object state, cache
+					// the running config, and collect any that did not realize intent.
+					if len(succeeded) > 0 {
+						diverged, vErr := recordApplyOutcome(ctx, client, updater, cfg, siteConfig, deviceType, siteID, apiLabel, succeeded)
+						if vErr != nil {
+							warn.Add("Verify", "", "post-apply verify for %s: %v", deviceType, vErr)
+						}
+						divergentDevices = append(divergentDevices, diverged...)
+					}
+					if upErr != nil {
+						var intErr *vendors.InterruptedError
+						if errors.As(upErr, &intErr) {
+							fmt.Println(intErr.UserMessage())
+							return upErr
+						}
+						logging.Errorf("Error updating %s configurations: %v", deviceType, upErr)
+						return fmt.Errorf("error updating %s configurations: %w", deviceType, upErr)
+					}
+				}
 			}
+		default:
+			logging.Debugf("Apply step %s has no runner for %s; skipped", step.Name, deviceType)
 		}
 	}
 
-	// Step 10: Check if any changes were made
+	// Step 10: Check if any changes were made. The summary reports the
+	// outcome at the end of the run; without one, say it here.
+	summary.finishRun(len(configuredDevicesFiltered)-len(devicesToUpdate)-len(devicesToAssign), diffMode)
 	if len(devicesToAssign) == 0 && len(devicesToUpdate) == 0 && len(devicesToUnassign) == 0 && wlanChanges == 0 {
 		if hasWarnings {
 			fmt.Println("No changes applied due to warnings in the configuration.")
-		} else {
+		} else if summary == nil {
 			fmt.Println("No changes needed - all devices and WLANs are already configured correctly.")
 		}
 	} else if diffMode {
-		fmt.Println("Diff mode completed - no changes have been applied")
+		if summary == nil {
+			fmt.Println("Diff mode completed - no changes have been applied")
+		}
 	} else {
-		fmt.Printf("Successfully applied %s configuration to site %s\n", deviceType, siteName)
+		if summary == nil {
+			fmt.Printf("Successfully applied %s configuration to site %s\n", deviceType, siteName)
+		}
 
 		// Create backup of the applied configuration
 		for _, configFile := range configFiles {
@@ -446,7 +564,7 @@ func applySiteGeneric(ctx context.Context, client vendors.Client, cfg *configPkg
 				if _, found := siteConfigs[siteName]; found {
 					// This config file contains the site, create a backup
 					if err := createConfigBackupAfterApply(cfg, siteName, configFilePath); err != nil {
-						logging.Warnf("Failed to create configuration backup: %v", err)
+						warn.Add("Backup", "", "failed to create configuration backup: %v", err)
 					} else {
 						logging.Debugf("Created backup for site %s from config file %s", siteName, configFile)
 					}
@@ -457,7 +575,7 @@ func applySiteGeneric(ctx context.Context, client vendors.Client, cfg *configPkg
 
 		// Update file hashes after successful apply
 		if err := updateFileHashes(cfg, configFiles); err != nil {
-			logging.Warnf("Failed to update file hashes: %v", err)
+			warn.Add("Backup", "", "failed to update file hashes: %v", err)
 		}
 	}
 
@@ -650,8 +768,15 @@ func getSiteConfiguration(cfg *configPkg.Config, configFiles []string, siteName
 // WLANs are created/updated at the site level.
 // Collects WLANs from both site profiles AND device configs to ensure all referenced WLANs exist.
 // For Mist: sets ap_ids and apply_to based on which devices reference the WLAN.
-// Returns the number of WLANs created or updated.
-func applyWLANs(ctx context.Context, client vendors.Client, cfg *configPkg.Config, siteConfig SiteConfig, siteID string, apiLabel string, diffMode bool, force bool) (int, error) {
+// Returns the number of WLANs created or updated, and an announcement for each
+// pushed change that makes users rejoin (new SSID, rename, new passphrase).
+// APs in assigning are about to be assigned to the site and may have no ID
+// yet; they are left out of ap_ids here and added by reconcileWLANAPIDs once
+// the assignment lands.
+func applyWLANs(ctx context.Context, client vendors.Client, cfg *configPkg.Config, siteConfig SiteConfig, siteID string, apiLabel string, diffMode bool, force bool, assigning []string) (int, []notify.Announcement, error) {
+	warn := warnings.FromContext(ctx)
+	summary := summaryFromContext(ctx)
+	siteName := siteNameFromConfig(siteConfig)
 	// Collect ALL WLAN labels from both site profiles and device configs
 	wlanLabels := collectAllWLANLabels(siteConfig)
 
@@ -659,7 +784,7 @@ func applyWLANs(ctx context.Context, client vendors.Client, cfg *configPkg.Confi
 	wlanToDevices := collectWLANDeviceMapping(siteConfig)
 	if len(wlanLabels) == 0 {
 		logging.Debugf("No WLANs configured for site or devices")
-		return 0, nil
+		return 0, nil, nil
 	}
 
 	logging.Infof("Processing %d WLAN(s) for site: %v", len(wlanLabels), wlanLabels)
@@ -668,12 +793,12 @@ func applyWLANs(ctx context.Context, client vendors.Client, cfg *configPkg.Confi
 	templates, templateAPILabel := getTemplateStore()
 	if templates == nil || templates.IsEmpty() {
 		logging.Warnf("No templates loaded, cannot expand WLAN labels")
-		return 0, fmt.Errorf("no templates loaded for WLAN expansion")
+		return 0, nil, fmt.Errorf("no templates loaded for WLAN expansion")
 	}
 
 	// Validate WLAN assignments before proceeding
 	if err := validateWLANAssignments(siteConfig, templates); err != nil {
-		return 0, err
+		return 0, nil, err
 	}
 
 	// Determine vendor from API label
@@ -685,22 +810,23 @@ func applyWLANs(ctx context.Context, client vendors.Client, cfg *configPkg.Confi
 	// Expand WLAN templates
 	var desiredWLANs []map[string]any
 	for _, label := range wlanLabels {
-		template, found := templates.GetWLANTemplate(label)
+		expanded, found := expandSiteWLAN(templates, siteConfig, label, vendor)
 		if !found {
-			logging.Warnf("WLAN template '%s' not found", label)
+			warn.Add("WLANs", label, "WLAN template not found")
 			continue
 		}
-		// Expand for vendor (handles mist:/meraki: blocks)
-		expanded := configPkg.ExpandForVendor(template, vendor)
 		// Add the template label for reference
 		expanded["_template_label"] = label
 		desiredWLANs = append(desiredWLANs, expanded)
 		logging.Debugf("Expanded WLAN template '%s': ssid=%v", label, expanded["ssid"])
 	}
+	if err := templates.Err(); err != nil {
+		return 0, nil, err
+	}
 
 	if len(desiredWLANs) == 0 {
-		logging.Warnf("No WLAN templates could be expanded")
-		return 0, nil
+		warn.Add("WLANs", "", "no WLAN templates could be expanded")
+		return 0, nil, nil
 	}
 
 	// Meraki: SSIDs are network-wide; availability rides in each WLAN's vendor
@@ -714,14 +840,14 @@ func applyWLANs(ctx context.Context, client vendors.Client, cfg *configPkg.Confi
 	// WLAN apply is skipped rather than failing the device apply.
 	lc := legacyClient(client)
 	if lc == nil {
-		logging.Warnf("WLAN apply is not supported for this vendor; skipping")
-		return 0, nil
+		warn.Add("WLANs", "", "WLAN apply is not supported for API %s; skipping", apiLabel)
+		return 0, nil, nil
 	}
 
 	// Get existing WLANs for this site from API
 	existingWLANs, err := lc.GetSiteWLANs(ctx, siteID)
 	if err != nil {
-		logging.Warnf("Failed to get existing WLANs for site %s: %v", siteID, err)
+		warn.Add("WLANs", "", "failed to get existing WLANs for site %s: %v", siteID, err)
 		existingWLANs = nil // Treat as empty
 	}
 
@@ -737,12 +863,17 @@ func applyWLANs(ctx context.Context, client vendors.Client, cfg *configPkg.Confi
 	logging.Debugf("Found %d existing WLANs in site", len(existingWLANs))
 
 	changeCount := 0
+	var announcements []notify.Announcement
+	pending := make(map[string]bool, len(assigning))
+	for _, mac := range assigning {
+		pending[mac] = true
+	}
 
 	// Process each desired WLAN
 	for _, desired := range desiredWLANs {
 		ssid, ok := desired["ssid"].(string)
 		if !ok || ssid == "" {
-			logging.Warnf("WLAN template has no ssid field, skipping")
+			warn.Add("WLANs", "", "WLAN template has no ssid field, skipping")
 			continue
 		}
 
@@ -754,22 +885,7 @@ func applyWLANs(ctx context.Context, client vendors.Client, cfg *configPkg.Confi
 
 		// For Mist: Set ap_ids and apply_to based on which devices reference this WLAN
 		if deviceMACs, hasDevices := wlanToDevices[templateLabel]; hasDevices && len(deviceMACs) > 0 {
-			// Get AP IDs from the multi-vendor cache accessor
-			vendorsCacheAccessor := vendors.GetGlobalCacheAccessor()
-			var apIDs []string
-			if vendorsCacheAccessor != nil {
-				for _, mac := range deviceMACs {
-					ap, err := vendorsCacheAccessor.GetAPByMAC(mac)
-					if err == nil && ap != nil && ap.ID != "" {
-						apIDs = append(apIDs, ap.ID)
-						logging.Debugf("WLAN '%s': resolved MAC %s to AP ID %s", templateLabel, mac, ap.ID)
-					} else {
-						logging.Warnf("WLAN '%s': could not resolve MAC %s to AP ID: %v", templateLabel, mac, err)
-					}
-				}
-			} else {
-				logging.Warnf("WLAN '%s': cache accessor not available, cannot resolve MACs to AP IDs", templateLabel)
-			}
+			apIDs := resolveWLANAPIDs(ctx, templateLabel, deviceMACs, nil, pending)
 			if len(apIDs) > 0 {
 				desired["ap_ids"] = apIDs
 				desired["apply_to"] = "aps"
@@ -796,9 +912,11 @@ func applyWLANs(ctx context.Context, client vendors.Client, cfg *configPkg.Confi
 			if needsUpdate || force {
 				if diffMode {
 					if force && !needsUpdate {
-						fmt.Printf("Would force update WLAN '%s' (template: %s) - no changes detected\n", ssid, templateLabel)
+						summary.add(sectionWLANs, wlanEntry(siteName, ssid, templateLabel, resultPending, "force update, no changes",
+							fmt.Sprintf("Would force update WLAN '%s' (template: %s) - no changes detected", ssid, templateLabel)))
 					} else {
-						fmt.Printf("Would update WLAN '%s' (template: %s)\n", ssid, templateLabel)
+						summary.add(sectionWLANs, wlanEntry(siteName, ssid, templateLabel, resultPending, "update",
+							fmt.Sprintf("Would update WLAN '%s' (template: %s)", ssid, templateLabel)))
 						showWLANDiff(existing, desired)
 					}
 				} else {
@@ -807,12 +925,22 @@ func applyWLANs(ctx context.Context, client vendors.Client, cfg *configPkg.Confi
 					} else {
 						logging.Infof("Updating WLAN '%s' (template: %s)", ssid, templateLabel)
 					}
-					if err := updateWLAN(ctx, lc, siteID, *existing.ID, desired); err != nil {
+					stepStart := time.Now()
+					err := updateWLAN(ctx, lc, siteID, *existing.ID, desired)
+					summary.timed(sectionWLANs, stepStart)
+					if err != nil {
 						logging.Errorf("Failed to update WLAN '%s': %v", ssid, err)
 						printWLANError("update", ssid, templateLabel, desired, err)
+						summary.add(sectionWLANs, wlanEntry(siteName, ssid, templateLabel, resultFailed, "update", ""))
 						continue
 					}
-					fmt.Printf("%s Updated WLAN '%s'\n", symbols.SuccessPrefix(), ssid)
+					summary.add(sectionWLANs, wlanEntry(siteName, ssid, templateLabel, resultDone, "update",
+						fmt.Sprintf("%s Updated WLAN '%s'", symbols.SuccessPrefix(), ssid)))
+					if existing.Auth.PSK != nil && pskChanged(*existing.Auth.PSK, desiredPSK(desired)) {
+						if a, ok := wlanAnnouncement(notify.KindPSK, "", desired); ok {
+							announcements = append(announcements, a)
+						}
+					}
 				}
 				changeCount++
 			} else {
@@ -821,16 +949,25 @@ func applyWLANs(ctx context.Context, client vendors.Client, cfg *configPkg.Confi
 		} else {
 			// WLAN doesn't exist - create it
 			if diffMode {
-				fmt.Printf("Would create WLAN '%s' (template: %s)\n", ssid, templateLabel)
+				summary.add(sectionWLANs, wlanEntry(siteName, ssid, templateLabel, resultPending, "create",
+					fmt.Sprintf("Would create WLAN '%s' (template: %s)", ssid, templateLabel)))
 				showWLANConfig(desired)
 			} else {
 				logging.Infof("Creating WLAN '%s' (template: %s)", ssid, templateLabel)
-				if err := createWLAN(ctx, lc, siteID, desired); err != nil {
+				stepStart := time.Now()
+				err := createWLAN(ctx, lc, siteID, desired)
+				summary.timed(sectionWLANs, stepStart)
+				if err != nil {
 					logging.Errorf("Failed to create WLAN '%s': %v", ssid, err)
 					printWLANError("create", ssid, templateLabel, desired, err)
+					summary.add(sectionWLANs, wlanEntry(siteName, ssid, templateLabel, resultFailed, "create", ""))
 					continue
 				}
-				fmt.Printf("%s Created WLAN '%s'\n", symbols.SuccessPrefix(), ssid)
+				summary.add(sectionWLANs, wlanEntry(siteName, ssid, templateLabel, resultDone, "create",
+					fmt.Sprintf("%s Created WLAN '%s'", symbols.SuccessPrefix(), ssid)))
+				if a, ok := wlanAnnouncement(notify.KindNewSSID, "", desired); ok {
+					announcements = append(announcements, a)
+				}
 			}
 			changeCount++
 		}
@@ -840,11 +977,11 @@ func applyWLANs(ctx context.Context, client vendors.Client, cfg *configPkg.Confi
 		logging.Infof("Applied %d WLAN change(s)", changeCount)
 	}
 
-	return changeCount, nil
+	return changeCount, announcements, nil
 }
 
 // wlanNeedsUpdate checks if a WLAN configuration differs from desired state.
-// Compares key fields: enabled, band, bands, vlan_id, auth type, auth pairwise, apply_to, ap_ids.
+// Compares key fields: enabled, band, bands, vlan_id, auth type, auth pairwise, psk, apply_to, ap_ids.
 func wlanNeedsUpdate(existing *api.MistWLAN, desired map[string]any) bool {
 	// Check enabled
 	if desiredEnabled, ok := desired["enabled"].(bool); ok {
@@ -917,6 +1054,11 @@ func wlanNeedsUpdate(existing *api.MistWLAN, desired map[string]any) bool {
 		}
 	}
 
+	// Check the passphrase, when the API returned the live one
+	if existing.Auth.PSK != nil && pskChanged(*existing.Auth.PSK, desiredPSK(desired)) {
+		return true
+	}
+
 	// Check apply_to (site vs aps)
 	if desiredApplyTo, ok := desired["apply_to"].(string); ok {
 		existingApplyTo := ""
@@ -1128,7 +1270,7 @@ func showWLANDiff(existing *api.MistWLAN, desired map[string]any) {
 	if existing.Auth.Type != nil {
 		auth := map[string]any{"type": *existing.Auth.Type}
 		if existing.Auth.PSK != nil {
-			auth["psk"] = "********" // Mask existing PSK
+			auth["psk"] = *existing.Auth.PSK
 		}
 		if existing.Auth.Pairwise != nil {
 			auth["pairwise"] = *existing.Auth.Pairwise
@@ -1144,48 +1286,22 @@ func showWLANDiff(existing *api.MistWLAN, desired map[string]any) {
 		existingMap["ap_ids"] = *existing.ApIDs
 	}
 
-	// Mask PSK in desired config for display
-	desiredDisplay := maskPSKInConfig(desired)
-
-	showJSONDiff(existingMap, desiredDisplay, "API", "Config")
+	showJSONDiff(existingMap, desired, "API", "Config")
 }
 
 // showWLANConfig shows the WLAN configuration that would be created using jsondiff.
 func showWLANConfig(config map[string]any) {
 	// For new WLANs, show diff from empty to desired (all additions)
 	emptyConfig := make(map[string]any)
-	desiredDisplay := maskPSKInConfig(config)
 
-	showJSONDiff(emptyConfig, desiredDisplay, "API", "Config")
+	showJSONDiff(emptyConfig, config, "API", "Config")
 }
 
-// maskPSKInConfig creates a copy of config with PSK values masked.
-func maskPSKInConfig(config map[string]any) map[string]any {
-	displayConfig := make(map[string]any)
-	for k, v := range config {
-		if k == "auth" {
-			if authMap, ok := v.(map[string]any); ok {
-				cleanAuth := make(map[string]any)
-				for ak, av := range authMap {
-					if ak == "psk" {
-						cleanAuth[ak] = "********"
-					} else {
-						cleanAuth[ak] = av
-					}
-				}
-				displayConfig[k] = cleanAuth
-			}
-		} else {
-			displayConfig[k] = v
-		}
-	}
-	return displayConfig
-}
-
-// showJSONDiff displays a colorized JSON diff using jsondiff library.
+// showJSONDiff displays a colorized JSON diff using jsondiff library. Both
+// sides are redacted first, so a changed secret shows no diff line.
 func showJSONDiff(existing, desired map[string]any, existingLabel, desiredLabel string) {
-	existingJSON, err1 := json.MarshalIndent(existing, "", "  ")
-	desiredJSON, err2 := json.MarshalIndent(desired, "", "  ")
+	existingJSON, err1 := json.MarshalIndent(common.RedactMap(existing), "", "  ")
+	desiredJSON, err2 := json.MarshalIndent(common.RedactMap(desired), "", "  ")
 
 	if err1 != nil || err2 != nil {
 		logging.Warnf("Could not generate JSON diff")
@@ -1239,6 +1355,8 @@ func collectWLANDeviceMapping(siteConfig SiteConfig) map[string][]string {
 			devicesWithExplicitWLAN[mac] = true
 		}
 	}
+	// Map order would otherwise reorder ap_ids from run to run
+	slices.Sort(allAPMACs)
 
 	// 1. Map site-level WLANs to APs that don't have explicit WLAN config
 	for _, label := range siteConfig.WLAN {
@@ -1254,8 +1372,8 @@ func collectWLANDeviceMapping(siteConfig SiteConfig) map[string][]string {
 	}
 
 	// 2. Map device-level WLANs (these override site-level for that device)
-	for mac, deviceMap := range siteConfig.Devices.APs {
-		if deviceWLANs, ok := deviceMap["wlan"].([]any); ok {
+	for _, mac := range allAPMACs {
+		if deviceWLANs, ok := siteConfig.Devices.APs[mac]["wlan"].([]any); ok {
 			for _, w := range deviceWLANs {
 				if label, ok := w.(string); ok {
 					wlanToDevices[label] = append(wlanToDevices[label], mac)
@@ -1373,6 +1491,12 @@ var (
 	currentAPILabel      string
 )
 
+// LoadTemplateStore returns the templates apply would expand against, for
+// commands outside this package that inspect them.
+func LoadTemplateStore(cfg *configPkg.Config) (*configPkg.TemplateStore, error) {
+	return loadTemplatesFromConfig(cfg)
+}
+
 // loadTemplatesFromConfig builds the TemplateStore from every source
 // configured in the main config: hand-authored `files.templates` first,
 // then `files.imports` (whose Templates sections merge into the same
@@ -1400,11 +1524,18 @@ func loadTemplatesFromConfig(cfg *configPkg.Config) (*configPkg.TemplateStore, e
 			return nil, fmt.Errorf("load import file %s: %w", rel, err)
 		}
 		configPkg.MergeImportTemplates(store, imp)
+		configPkg.RecordImportSources(store, imp, path)
 	}
 
 	return store, nil
 }
 
+// templatesForSite returns a view of templates rendered with the site's
+// attributes: its site_config fields, vars, and site groups.
+func templatesForSite(templates *configPkg.TemplateStore, siteName string, siteConfig SiteConfig) *configPkg.TemplateStore {
+	return templates.ForSite(configPkg.NewSiteAttributes(siteName, siteConfig.SiteConfig, siteConfig.Vars))
+}
+
 // setTemplateStore sets the current template store for use by device updaters
 func setTemplateStore(store *configPkg.TemplateStore, apiLabel string) {
 	currentTemplateStore = store
@@ -1417,8 +1548,9 @@ func getTemplateStore() (*configPkg.TemplateStore, string) {
 }
 
 // expandDeviceConfigWithTemplates expands template references in a device config
-// using the current template store. Returns the original config if templates are empty.
-func expandDeviceConfigWithTemplates(deviceConfig map[string]any, siteConfig SiteConfig) (map[string]any, error) {
+// using the current template store, starting from the defaults for the device's
+// model. Returns the original config if templates are empty.
+func expandDeviceConfigWithTemplates(deviceConfig map[string]any, siteConfig SiteConfig, mac string) (map[string]any, error) {
 	templates, apiLabel := getTemplateStore()
 	if templates == nil || templates.IsEmpty() {
 		return deviceConfig, nil
@@ -1427,7 +1559,21 @@ func expandDeviceConfigWithTemplates(deviceConfig map[string]any, siteConfig Sit
 	// Extract site-level WLAN labels from siteConfig
 	siteWLANs := configPkg.GetSiteWLANLabels(siteConfig.SiteConfig)
 
-	return configPkg.ExpandDeviceConfig(deviceConfig, siteWLANs, templates, apiLabel)
+	return configPkg.ExpandDeviceConfigForModel(deviceConfig, deviceModel(mac), siteWLANs, templates, apiLabel)
+}
+
+// deviceModel returns the hardware model the API inventory cache reports for
+// mac, or "" when the device is not cached.
+func deviceModel(mac string) string {
+	accessor := vendors.GetGlobalCacheAccessor()
+	if accessor == nil {
+		return ""
+	}
+	item, err := accessor.GetDeviceByMAC(mac)
+	if err != nil || item == nil {
+		return ""
+	}
+	return item.Model
 }
 
 // printWLANError prints a user-friendly error message for WLAN operations
@@ -1468,27 +1614,30 @@ func containsIgnoreCase(s, substr string) bool {
 
 // applyWLANsMeraki applies WLAN configurations for Meraki using the vendors.Client interface.
 // Uses availability tags for per-AP WLAN assignment instead of Mist's ap_ids/apply_to model.
-func applyWLANsMeraki(ctx context.Context, _ *configPkg.Config, _ SiteConfig, siteID, apiLabel string,
-	desiredWLANs []map[string]any, diffMode, force bool) (int, error) {
+func applyWLANsMeraki(ctx context.Context, _ *configPkg.Config, siteConfig SiteConfig, siteID, apiLabel string,
+	desiredWLANs []map[string]any, diffMode, force bool) (int, []notify.Announcement, error) {
+	warn := warnings.FromContext(ctx)
+	summary := summaryFromContext(ctx)
+	siteName := siteNameFromConfig(siteConfig)
 
 	// Get vendor client from global registry
 	registry := vendors.GetGlobalRegistry()
 	if registry == nil {
-		return 0, fmt.Errorf("vendor registry not initialized")
+		return 0, nil, fmt.Errorf("vendor registry not initialized")
 	}
 	vendorClient, err := registry.GetClient(apiLabel)
 	if err != nil {
-		return 0, fmt.Errorf("failed to get vendor client for %s: %w", apiLabel, err)
+		return 0, nil, fmt.Errorf("failed to get vendor client for %s: %w", apiLabel, err)
 	}
 	wlansSvc := vendorClient.WLANs()
 	if wlansSvc == nil {
-		return 0, fmt.Errorf("vendor %s does not support WLANs", apiLabel)
+		return 0, nil, fmt.Errorf("vendor %s does not support WLANs", apiLabel)
 	}
 
 	// Get existing WLANs for this network
 	existingWLANs, err := wlansSvc.ListBySite(ctx, siteID)
 	if err != nil {
-		logging.Warnf("Failed to get existing WLANs for site %s: %v", siteID, err)
+		warn.Add("WLANs", "", "failed to get existing WLANs for site %s: %v", siteID, err)
 		existingWLANs = nil
 	}
 
@@ -1508,11 +1657,12 @@ func applyWLANsMeraki(ctx context.Context, _ *configPkg.Config, _ SiteConfig, si
 	logging.Debugf("Found %d existing Meraki SSIDs in network", len(existingBySSID))
 
 	changeCount := 0
+	var announcements []notify.Announcement
 
 	for _, desired := range desiredWLANs {
 		ssid, ok := desired["ssid"].(string)
 		if !ok || ssid == "" {
-			logging.Warnf("WLAN template has no ssid field, skipping")
+			warn.Add("WLANs", "", "WLAN template has no ssid field, skipping")
 			continue
 		}
 
@@ -1547,20 +1697,39 @@ func applyWLANsMeraki(ctx context.Context, _ *configPkg.Config, _ SiteConfig, si
 				if diffMode {
 					switch {
 					case renamed:
-						fmt.Printf("Would rename WLAN slot %d '%s' → '%s' (template: %s)\n", pinnedSlot, existing.SSID, ssid, templateLabel)
+						summary.add(sectionWLANs, wlanEntry(siteName, ssid, templateLabel, resultPending,
+							fmt.Sprintf("rename from '%s' (slot %d)", existing.SSID, pinnedSlot),
+							fmt.Sprintf("Would rename WLAN slot %d '%s' → '%s' (template: %s)", pinnedSlot, existing.SSID, ssid, templateLabel)))
 					case force && !needsUpdate:
-						fmt.Printf("Would force update WLAN '%s' (template: %s) - no changes detected\n", ssid, templateLabel)
+						summary.add(sectionWLANs, wlanEntry(siteName, ssid, templateLabel, resultPending, "force update, no changes",
+							fmt.Sprintf("Would force update WLAN '%s' (template: %s) - no changes detected", ssid, templateLabel)))
 					default:
-						fmt.Printf("Would update WLAN '%s' (template: %s)\n", ssid, templateLabel)
+						summary.add(sectionWLANs, wlanEntry(siteName, ssid, templateLabel, resultPending, "update",
+							fmt.Sprintf("Would update WLAN '%s' (template: %s)", ssid, templateLabel)))
 					}
 				} else {
 					logging.Infof("Updating Meraki SSID '%s' (template: %s)", ssid, templateLabel)
-					if _, err := wlansSvc.Update(ctx, targetID, wlan); err != nil {
+					stepStart := time.Now()
+					_, err := wlansSvc.Update(ctx, targetID, wlan)
+					summary.timed(sectionWLANs, stepStart)
+					if err != nil {
 						logging.Errorf("Failed to update Meraki SSID '%s': %v", ssid, err)
 						fmt.Printf("%s Failed to update WLAN '%s': %v\n", symbols.ErrorPrefix(), ssid, err)
+						summary.add(sectionWLANs, wlanEntry(siteName, ssid, templateLabel, resultFailed, "update", ""))
 						continue
 					}
-					fmt.Printf("%s Updated WLAN '%s'\n", symbols.SuccessPrefix(), ssid)
+					summary.add(sectionWLANs, wlanEntry(siteName, ssid, templateLabel, resultDone, "update",
+						fmt.Sprintf("%s Updated WLAN '%s'", symbols.SuccessPrefix(), ssid)))
+					switch {
+					case renamed:
+						if a, ok := wlanAnnouncement(notify.KindRenamed, existing.SSID, desired); ok {
+							announcements = append(announcements, a)
+						}
+					case pskChanged(existing.PSK, wlan.PSK):
+						if a, ok := wlanAnnouncement(notify.KindPSK, "", desired); ok {
+							announcements = append(announcements, a)
+						}
+					}
 				}
 				changeCount++
 			} else {
@@ -1570,29 +1739,47 @@ func applyWLANsMeraki(ctx context.Context, _ *configPkg.Config, _ SiteConfig, si
 			// Pinned to a slot that is currently inactive/empty. Write straight to
 			// it instead of letting Create() pick an arbitrary free slot.
 			if diffMode {
-				fmt.Printf("Would configure WLAN '%s' in slot %d (template: %s)\n", ssid, pinnedSlot, templateLabel)
+				summary.add(sectionWLANs, wlanEntry(siteName, ssid, templateLabel, resultPending, fmt.Sprintf("configure slot %d", pinnedSlot),
+					fmt.Sprintf("Would configure WLAN '%s' in slot %d (template: %s)", ssid, pinnedSlot, templateLabel)))
 			} else {
 				logging.Infof("Configuring Meraki SSID '%s' in pinned slot %d (template: %s)", ssid, pinnedSlot, templateLabel)
-				if _, err := wlansSvc.Update(ctx, targetID, wlan); err != nil {
+				stepStart := time.Now()
+				_, err := wlansSvc.Update(ctx, targetID, wlan)
+				summary.timed(sectionWLANs, stepStart)
+				if err != nil {
 					logging.Errorf("Failed to configure Meraki SSID '%s' in slot %d: %v", ssid, pinnedSlot, err)
 					fmt.Printf("%s Failed to configure WLAN '%s': %v\n", symbols.ErrorPrefix(), ssid, err)
+					summary.add(sectionWLANs, wlanEntry(siteName, ssid, templateLabel, resultFailed, fmt.Sprintf("configure slot %d", pinnedSlot), ""))
 					continue
 				}
-				fmt.Printf("%s Configured WLAN '%s' in slot %d\n", symbols.SuccessPrefix(), ssid, pinnedSlot)
+				summary.add(sectionWLANs, wlanEntry(siteName, ssid, templateLabel, resultDone, fmt.Sprintf("configure slot %d", pinnedSlot),
+					fmt.Sprintf("%s Configured WLAN '%s' in slot %d", symbols.SuccessPrefix(), ssid, pinnedSlot)))
+				if a, ok := wlanAnnouncement(notify.KindNewSSID, "", desired); ok {
+					announcements = append(announcements, a)
+				}
 			}
 			changeCount++
 		case merakiWLANCreate:
 			// Brand-new SSID with no pin and no name match: allocate a free slot.
 			if diffMode {
-				fmt.Printf("Would create WLAN '%s' (template: %s)\n", ssid, templateLabel)
+				summary.add(sectionWLANs, wlanEntry(siteName, ssid, templateLabel, resultPending, "create",
+					fmt.Sprintf("Would create WLAN '%s' (template: %s)", ssid, templateLabel)))
 			} else {
 				logging.Infof("Creating Meraki SSID '%s' (template: %s)", ssid, templateLabel)
-				if _, err := wlansSvc.Create(ctx, wlan); err != nil {
+				stepStart := time.Now()
+				_, err := wlansSvc.Create(ctx, wlan)
+				summary.timed(sectionWLANs, stepStart)
+				if err != nil {
 					logging.Errorf("Failed to create Meraki SSID '%s': %v", ssid, err)
 					fmt.Printf("%s Failed to create WLAN '%s': %v\n", symbols.ErrorPrefix(), ssid, err)
+					summary.add(sectionWLANs, wlanEntry(siteName, ssid, templateLabel, resultFailed, "create", ""))
 					continue
 				}
-				fmt.Printf("%s Created WLAN '%s'\n", symbols.SuccessPrefix(), ssid)
+				summary.add(sectionWLANs, wlanEntry(siteName, ssid, templateLabel, resultDone, "create",
+					fmt.Sprintf("%s Created WLAN '%s'", symbols.SuccessPrefix(), ssid)))
+				if a, ok := wlanAnnouncement(notify.KindNewSSID, "", desired); ok {
+					announcements = append(announcements, a)
+				}
 			}
 			changeCount++
 		}
@@ -1602,7 +1789,7 @@ func applyWLANsMeraki(ctx context.Context, _ *configPkg.Config, _ SiteConfig, si
 		logging.Infof("Applied %d Meraki WLAN change(s)", changeCount)
 	}
 
-	return changeCount, nil
+	return changeCount, announcements, nil
 }
 
 // buildVendorWLANFromConfig converts an expanded WLAN template config map to a vendors.WLAN.
@@ -1693,6 +1880,9 @@ func merakiWLANNeedsUpdate(existing *vendors.WLAN, desired *vendors.WLAN) bool {
 	if desired.EncryptionMode != "" && existing.EncryptionMode != desired.EncryptionMode {
 		return true
 	}
+	if pskChanged(existing.PSK, desired.PSK) {
+		return true
+	}
 
 	// Compare availability tags
 	existingTags := extractStringSliceFromConfig(existing.Config, "availabilityTags")
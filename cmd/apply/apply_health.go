@@ -0,0 +1,147 @@
+package apply
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	configPkg "github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/formatter"
+	"github.com/ravinald/wifimgr/internal/integrations"
+	"github.com/ravinald/wifimgr/internal/logging"
+	"github.com/ravinald/wifimgr/internal/sitehealth"
+	"github.com/ravinald/wifimgr/internal/symbols"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+// healthEntry is one site/device-type run's health comparison in the summary.
+type healthEntry struct {
+	Site       string
+	Type       string
+	Comparison sitehealth.Comparison
+}
+
+// preApplyHealth snapshots the site before anything is pushed, or returns nil
+// when apply.health_check is off.
+func preApplyHealth(ctx context.Context, client vendors.Client, siteName, siteID string) *sitehealth.Snapshot {
+	if sitehealth.LoadConfig() == nil {
+		return nil
+	}
+	logging.Infof("Capturing pre-apply health for site %s", siteName)
+	s := sitehealth.Collect(ctx, client, siteID)
+	return &s
+}
+
+// postApplyHealth waits apply.health_settle and snapshots the site again. It
+// returns nil when there is no pre-apply snapshot or the run was interrupted
+// while settling.
+func postApplyHealth(ctx context.Context, client vendors.Client, siteName, siteID string, before *sitehealth.Snapshot) *sitehealth.Comparison {
+	cfg := sitehealth.LoadConfig()
+	if before == nil || cfg == nil {
+		return nil
+	}
+	if cfg.Settle > 0 {
+		fmt.Printf("Waiting %s for site %s to settle before the post-apply health check...\n", cfg.Settle, siteName)
+		select {
+		case <-ctx.Done():
+			logging.Warnf("Post-apply health check skipped: %v", ctx.Err())
+			return nil
+		case <-time.After(cfg.Settle):
+		}
+	}
+	return &sitehealth.Comparison{Before: *before, After: sitehealth.Collect(ctx, client, siteID)}
+}
+
+// reportApplyHealth adds a health comparison to the summary, or prints it
+// when no summary is collecting, and sends it to the integrations as an
+// apply_health notification.
+func reportApplyHealth(ctx context.Context, cfg *configPkg.Config, siteName, deviceType string, cmp *sitehealth.Comparison) {
+	if cmp == nil {
+		return
+	}
+	regressions := cmp.Regressions()
+	if s := summaryFromContext(ctx); s != nil {
+		s.addHealth(healthEntry{Site: siteName, Type: deviceType, Comparison: *cmp})
+	} else {
+		fmt.Printf("Site health for %s: devices connected %s, clients %s, alarms %s\n",
+			siteName, cmp.Devices(), cmp.Clients(), cmp.Alarms())
+		if len(regressions) > 0 {
+			fmt.Printf("%s Site %s regressed after apply: %s\n", symbols.WarningPrefix(), siteName, strings.Join(regressions, "; "))
+		}
+	}
+
+	subject := fmt.Sprintf("%s: %s apply left the site healthy", siteName, deviceType)
+	if len(regressions) > 0 {
+		subject = fmt.Sprintf("%s: %s apply regressed site health (%s)", siteName, deviceType, strings.Join(regressions, "; "))
+	}
+	n := integrations.Notification{
+		Kind:    integrations.NotifyApplyHealth,
+		Site:    siteName,
+		Subject: subject,
+		Time:    cmp.After.Time,
+		Data:    *cmp,
+		Details: cmp.Details(),
+	}
+	if cfg != nil {
+		n.ConfigDir = cfg.Files.ConfigDir
+	}
+	// A fresh context: an interrupted apply should still be reported.
+	integrations.Notify(context.WithoutCancel(ctx), n)
+}
+
+// addHealth records a run's health comparison.
+func (s *applySummary) addHealth(e healthEntry) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.health = append(s.health, e)
+}
+
+// renderHealth returns the health table, or "" when no run compared health.
+// The caller holds s.mu.
+func (s *applySummary) renderHealth() string {
+	if len(s.health) == 0 {
+		return ""
+	}
+	rows := make([]formatter.GenericTableData, 0, len(s.health))
+	regressed := 0
+	for _, h := range s.health {
+		result := "ok"
+		if r := h.Comparison.Regressions(); len(r) > 0 {
+			result = "regressed: " + strings.Join(r, "; ")
+			regressed++
+		}
+		rows = append(rows, formatter.GenericTableData{
+			"site":      h.Site,
+			"type":      h.Type,
+			"connected": h.Comparison.Devices(),
+			"clients":   h.Comparison.Clients(),
+			"alarms":    h.Comparison.Alarms(),
+			"result":    result,
+		})
+	}
+	heading := fmt.Sprintf("Site health, before -> after (%d", len(s.health))
+	if regressed > 0 {
+		heading += fmt.Sprintf(", %d regressed", regressed)
+	}
+	heading += ")"
+	printer := formatter.NewGenericTablePrinter(formatter.TableConfig{
+		Title:         heading,
+		Format:        "table",
+		BoldHeaders:   true,
+		ShowSeparator: true,
+		CommandPath:   "apply.summary.health",
+		Columns: []formatter.TableColumn{
+			{Field: "site", Title: "Site"},
+			{Field: "type", Title: "Type"},
+			{Field: "connected", Title: "Connected"},
+			{Field: "clients", Title: "Clients"},
+			{Field: "alarms", Title: "Alarms"},
+			{Field: "result", Title: "Result"},
+		},
+	}, rows)
+	return printer.Print()
+}
@@ -0,0 +1,62 @@
+package apply
+
+import (
+	"context"
+
+	configPkg "github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/history"
+	"github.com/ravinald/wifimgr/internal/integrations"
+	"github.com/ravinald/wifimgr/internal/logging"
+)
+
+// recordApplyHistory appends one apply run to CHANGELOG.jsonl in the config
+// directory. Runs that changed nothing and did not fail are not recorded, so
+// the file reads as a list of actual changes. A write failure is logged, never
+// returned: the apply itself already happened.
+func recordApplyHistory(cfg *configPkg.Config, entry history.Entry, diverged []string, applyErr error) {
+	switch {
+	case applyErr == nil && entry.DevicesChanged() == 0 && entry.WLANsChanged == 0:
+		return
+	case applyErr == nil:
+		entry.Result = history.ResultSuccess
+	case len(diverged) > 0:
+		entry.Result = history.ResultDiverged
+		entry.Error = applyErr.Error()
+	default:
+		entry.Result = history.ResultFailed
+		entry.Error = applyErr.Error()
+	}
+
+	if cfg == nil || cfg.Files.ConfigDir == "" {
+		logging.Debugf("apply history not recorded: no config directory")
+		return
+	}
+	if err := history.Append(cfg.Files.ConfigDir, entry); err != nil {
+		logging.Warnf("Failed to record apply history: %v", err)
+	}
+}
+
+// runPostApplyHooks sends the run to the integrations' post-apply hooks,
+// under the same rule as the history: only runs that changed something or
+// failed.
+func runPostApplyHooks(ctx context.Context, cfg *configPkg.Config, entry history.Entry, applyErr error) {
+	if applyErr == nil && entry.DevicesChanged() == 0 && entry.WLANsChanged == 0 {
+		return
+	}
+	ev := integrations.ApplyEvent{
+		Site:         entry.Site,
+		API:          entry.API,
+		DeviceType:   entry.DeviceType,
+		Assigned:     entry.Assigned,
+		Updated:      entry.Updated,
+		Unassigned:   entry.Unassigned,
+		WLANsChanged: entry.WLANsChanged,
+		Err:          applyErr,
+		Health:       entry.Health,
+	}
+	if cfg != nil {
+		ev.ConfigDir = cfg.Files.ConfigDir
+	}
+	// A fresh context: an interrupted apply should still be reported.
+	integrations.PostApply(context.WithoutCancel(ctx), ev)
+}
@@ -0,0 +1,38 @@
+package apply
+
+import (
+	"errors"
+	"testing"
+
+	configPkg "github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/history"
+)
+
+func TestRecordApplyHistory(t *testing.T) {
+	cfg := &configPkg.Config{}
+	cfg.Files.ConfigDir = t.TempDir()
+
+	// No-op runs are not recorded.
+	recordApplyHistory(cfg, history.Entry{Site: "US-LAB-01", DeviceType: "ap"}, nil, nil)
+	recordApplyHistory(cfg, history.Entry{Site: "US-LAB-01", DeviceType: "ap", Updated: []string{"aa0000000001"}}, nil, nil)
+	recordApplyHistory(cfg, history.Entry{Site: "US-LAB-01", DeviceType: "ap", Updated: []string{"aa0000000002"}},
+		[]string{"aa0000000002"}, errors.New("1 ap(s) accepted but running config does not match intent"))
+	recordApplyHistory(cfg, history.Entry{Site: "US-LAB-01", DeviceType: "switch"}, nil, errors.New("error assigning switches"))
+
+	entries, err := history.Read(history.Path(cfg.Files.ConfigDir), "US-LAB-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{history.ResultSuccess, history.ResultDiverged, history.ResultFailed}
+	if len(entries) != len(want) {
+		t.Fatalf("got %d entries, want %d: %+v", len(entries), len(want), entries)
+	}
+	for i, w := range want {
+		if entries[i].Result != w {
+			t.Errorf("entry %d result = %q, want %q", i, entries[i].Result, w)
+		}
+	}
+	if entries[2].Error == "" {
+		t.Error("failed entry should carry the error")
+	}
+}
@@ -0,0 +1,167 @@
+package apply
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// Apply step names, the nodes of the dependency graph below.
+const (
+	stepNetworks       = "networks"
+	stepLabels         = "labels"
+	stepWLANs          = "wlans"
+	stepDeviceProfiles = "device-profiles"
+	stepDevices        = "devices"
+)
+
+// applyStep is one resource type an apply run pushes. A step runs only after
+// every step in After, so a resource exists before anything that references
+// it. A new resource type is added here with its dependencies rather than
+// wired into applySiteGeneric at a hand-picked position.
+type applyStep struct {
+	Name        string
+	After       []string
+	DeviceTypes []string // device-type runs that push the step; empty means every run
+	Why         string   // what the ordering protects, shown by --explain
+	PushedBy    string   // set when apply <site> <type> does not push the step itself
+}
+
+// applySteps is the apply dependency graph: networks -> labels -> WLANs ->
+// device profiles -> devices.
+var applySteps = []applyStep{
+	{
+		Name:     stepNetworks,
+		Why:      "WLANs bind to site networks by VLAN",
+		PushedBy: "not managed by apply yet",
+	},
+	{
+		Name:     stepLabels,
+		After:    []string{stepNetworks},
+		Why:      "WLANs and policies are scoped by labels",
+		PushedBy: "not managed by apply yet",
+	},
+	{
+		Name:        stepWLANs,
+		After:       []string{stepLabels},
+		DeviceTypes: []string{"ap"},
+		Why:         "AP WLAN assignments reference the site's WLANs",
+	},
+	{
+		Name:     stepDeviceProfiles,
+		After:    []string{stepWLANs},
+		Why:      "profiles carry settings devices inherit",
+		PushedBy: "apply <site> device-profile",
+	},
+	{
+		Name:  stepDevices,
+		After: []string{stepDeviceProfiles},
+		Why:   "devices reference profiles and WLANs",
+	},
+}
+
+// sortApplySteps orders steps so each follows everything in its After list,
+// keeping declaration order among steps that are free to run. It is an error
+// for a step to depend on an unknown step or for the graph to have a cycle.
+func sortApplySteps(steps []applyStep) ([]applyStep, error) {
+	byName := make(map[string]bool, len(steps))
+	for _, s := range steps {
+		byName[s.Name] = true
+	}
+	for _, s := range steps {
+		for _, dep := range s.After {
+			if !byName[dep] {
+				return nil, fmt.Errorf("apply step %q depends on unknown step %q", s.Name, dep)
+			}
+		}
+	}
+
+	done := make(map[string]bool, len(steps))
+	out := make([]applyStep, 0, len(steps))
+	for len(out) < len(steps) {
+		progressed := false
+		for _, s := range steps {
+			if done[s.Name] {
+				continue
+			}
+			ready := true
+			for _, dep := range s.After {
+				if !done[dep] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				done[s.Name] = true
+				out = append(out, s)
+				progressed = true
+			}
+		}
+		if !progressed {
+			var stuck []string
+			for _, s := range steps {
+				if !done[s.Name] {
+					stuck = append(stuck, s.Name)
+				}
+			}
+			return nil, fmt.Errorf("apply steps have a dependency cycle among: %s", strings.Join(stuck, ", "))
+		}
+	}
+	return out, nil
+}
+
+// applyOrder returns the steps an apply run for deviceType executes itself,
+// in dependency order.
+func applyOrder(deviceType string) ([]applyStep, error) {
+	sorted, err := sortApplySteps(applySteps)
+	if err != nil {
+		return nil, err
+	}
+	var out []applyStep
+	for _, s := range sorted {
+		if s.PushedBy == "" && (len(s.DeviceTypes) == 0 || slices.Contains(s.DeviceTypes, deviceType)) {
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+
+// ExplainOrder writes the apply dependency graph in execution order. With a
+// deviceType, each step is marked with whether a run for that type pushes it.
+func ExplainOrder(w io.Writer, deviceType string) error {
+	sorted, err := sortApplySteps(applySteps)
+	if err != nil {
+		return err
+	}
+	runs := make(map[string]bool)
+	if deviceType != "" {
+		steps, err := applyOrder(deviceType)
+		if err != nil {
+			return err
+		}
+		for _, s := range steps {
+			runs[s.Name] = true
+		}
+		_, _ = fmt.Fprintf(w, "Apply order for %s:\n", deviceType)
+	} else {
+		_, _ = fmt.Fprintln(w, "Apply order:")
+	}
+
+	for i, s := range sorted {
+		line := fmt.Sprintf("  %d. %-16s %s", i+1, s.Name, s.Why)
+		switch {
+		case s.PushedBy != "":
+			line += fmt.Sprintf(" [%s]", s.PushedBy)
+		case deviceType != "" && !runs[s.Name]:
+			line += fmt.Sprintf(" [skipped for %s]", deviceType)
+		case len(s.DeviceTypes) > 0 && deviceType == "":
+			line += fmt.Sprintf(" [%s only]", strings.Join(s.DeviceTypes, ", "))
+		}
+		_, _ = fmt.Fprintln(w, line)
+		if len(s.After) > 0 {
+			_, _ = fmt.Fprintf(w, "     after: %s\n", strings.Join(s.After, ", "))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package apply
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func stepNames(steps []applyStep) string {
+	names := make([]string, len(steps))
+	for i, s := range steps {
+		names[i] = s.Name
+	}
+	return strings.Join(names, ",")
+}
+
+func TestSortApplySteps(t *testing.T) {
+	sorted, err := sortApplySteps(applySteps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := stepNames(sorted); got != "networks,labels,wlans,device-profiles,devices" {
+		t.Errorf("order = %s", got)
+	}
+
+	// Declared out of order, a new type still lands after its dependencies.
+	sorted, err = sortApplySteps([]applyStep{
+		{Name: "devices", After: []string{"psks"}},
+		{Name: "psks", After: []string{"wlans"}},
+		{Name: "wlans"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := stepNames(sorted); got != "wlans,psks,devices" {
+		t.Errorf("order = %s", got)
+	}
+
+	if _, err := sortApplySteps([]applyStep{{Name: "a", After: []string{"b"}}, {Name: "b", After: []string{"a"}}}); err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Errorf("err = %v, want a cycle reported", err)
+	}
+	if _, err := sortApplySteps([]applyStep{{Name: "a", After: []string{"missing"}}}); err == nil || !strings.Contains(err.Error(), "unknown step") {
+		t.Errorf("err = %v, want an unknown dependency reported", err)
+	}
+}
+
+func TestApplyOrder(t *testing.T) {
+	for deviceType, want := range map[string]string{
+		"ap":      "wlans,devices",
+		"switch":  "devices",
+		"gateway": "devices",
+	} {
+		steps, err := applyOrder(deviceType)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := stepNames(steps); got != want {
+			t.Errorf("applyOrder(%s) = %s, want %s", deviceType, got, want)
+		}
+	}
+}
+
+func TestExplainOrder(t *testing.T) {
+	var buf bytes.Buffer
+	if err := ExplainOrder(&buf, "switch"); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"Apply order for switch:",
+		"3. wlans",
+		"[skipped for switch]",
+		"[apply <site> device-profile]",
+		"after: device-profiles",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	if err := ExplainOrder(&buf, ""); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "[ap only]") {
+		t.Errorf("full graph should mark AP-only steps:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,261 @@
+package apply
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ravinald/wifimgr/internal/formatter"
+	"github.com/ravinald/wifimgr/internal/symbols"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+// Summary sections, in the order they are shown.
+const (
+	sectionUnassign = "Unassign"
+	sectionAssign   = "Assign"
+	sectionUpdate   = "Update"
+	sectionWLANs    = "WLANs"
+)
+
+var summarySections = []string{sectionUnassign, sectionAssign, sectionUpdate, sectionWLANs}
+
+// Entry results.
+const (
+	resultDone    = "done"
+	resultPending = "would change" // diff mode
+	resultFailed  = "failed"
+)
+
+// summaryEntry is one device or WLAN an apply run touched.
+type summaryEntry struct {
+	Site    string
+	Type    string // "ap", "switch", "gateway", or "wlan"
+	Subject string // device MAC or SSID
+	Name    string // device name, or the WLAN's template label
+	Result  string
+	Detail  string
+
+	// Line is the message printed at once when no summary is collecting,
+	// as apply printed before summaries existed.
+	Line string
+}
+
+// applySummary collects what an apply run changed, by section, so the run
+// ends with one grouped summary — counts, tables of the affected devices and
+// WLANs, and the time each section took — instead of lines interleaved with
+// diffs and progress output. It travels on the context like the warnings
+// collector; without one, each entry prints its Line as it is added.
+type applySummary struct {
+	mu        sync.Mutex
+	start     time.Time
+	entries   map[string][]summaryEntry
+	durations map[string]time.Duration
+	upToDate  int
+	runs      int // site/device-type runs that reached the comparison
+	diffOnly  bool
+	health    []healthEntry
+}
+
+func newApplySummary() *applySummary {
+	return &applySummary{
+		start:     time.Now(),
+		entries:   make(map[string][]summaryEntry),
+		durations: make(map[string]time.Duration),
+	}
+}
+
+type summaryKey struct{}
+
+func withApplySummary(ctx context.Context, s *applySummary) context.Context {
+	return context.WithValue(ctx, summaryKey{}, s)
+}
+
+// summaryFromContext returns the summary on ctx, or nil.
+func summaryFromContext(ctx context.Context) *applySummary {
+	if ctx == nil {
+		return nil
+	}
+	s, _ := ctx.Value(summaryKey{}).(*applySummary)
+	return s
+}
+
+// add records an entry under section.
+func (s *applySummary) add(section string, e summaryEntry) {
+	if s == nil {
+		if e.Line != "" {
+			fmt.Println(e.Line)
+		}
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[section] = append(s.entries[section], e)
+}
+
+// addDevices records one entry per device MAC under section.
+func (s *applySummary) addDevices(section, site, deviceType, result string, macs []string) {
+	for _, mac := range macs {
+		e := summaryEntry{Site: site, Type: deviceType, Subject: mac, Result: result}
+		if s != nil {
+			e.Name = summaryDeviceName(mac)
+		}
+		if result == resultPending {
+			// Diff mode listed devices one per line; apply mode did not.
+			e.Line = "  - " + mac
+		}
+		s.add(section, e)
+	}
+}
+
+// timed adds the time since start to section.
+func (s *applySummary) timed(section string, start time.Time) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.durations[section] += time.Since(start)
+}
+
+// finishRun records one site/device-type run and the devices it compared
+// against intent and found up to date.
+func (s *applySummary) finishRun(upToDate int, diffOnly bool) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.runs++
+	s.upToDate += upToDate
+	s.diffOnly = s.diffOnly || diffOnly
+}
+
+// summaryDeviceName returns the cached name of a device, or "".
+func summaryDeviceName(mac string) string {
+	accessor := vendors.GetGlobalCacheAccessor()
+	if accessor == nil {
+		return ""
+	}
+	if item, err := accessor.GetDeviceByMAC(mac); err == nil && item != nil {
+		return item.Name
+	}
+	return ""
+}
+
+// Render writes the summary to w: a headline with the count per section,
+// then a table per section that has entries. Nothing is written when no
+// apply run finished (a failed run, or a subcommand such as backups).
+func (s *applySummary) Render(w io.Writer) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.runs == 0 {
+		return
+	}
+
+	total := 0
+	counts := make([]string, 0, len(summarySections))
+	for _, section := range summarySections {
+		n := len(s.entries[section])
+		total += n
+		counts = append(counts, fmt.Sprintf("%s %d", strings.ToLower(section), n))
+	}
+	elapsed := time.Since(s.start).Round(100 * time.Millisecond)
+
+	title := "Apply summary"
+	if s.diffOnly {
+		title = "Diff summary (nothing applied)"
+	}
+	_, _ = fmt.Fprintf(w, "\n%s: %s; %d up to date (%s)\n", title, strings.Join(counts, ", "), s.upToDate, elapsed)
+	if total == 0 {
+		_, _ = fmt.Fprintf(w, "%s No changes needed - all devices and WLANs are already configured correctly.\n", symbols.SuccessPrefix())
+		return
+	}
+
+	for _, section := range summarySections {
+		entries := s.entries[section]
+		if len(entries) == 0 {
+			continue
+		}
+		rows := make([]formatter.GenericTableData, 0, len(entries))
+		failed := 0
+		for _, e := range entries {
+			if e.Result == resultFailed {
+				failed++
+			}
+			rows = append(rows, formatter.GenericTableData{
+				"site":    e.Site,
+				"type":    e.Type,
+				"subject": e.Subject,
+				"name":    e.Name,
+				"result":  e.Result,
+				"detail":  e.Detail,
+			})
+		}
+		heading := fmt.Sprintf("%s (%d", section, len(entries))
+		if failed > 0 {
+			heading += fmt.Sprintf(", %d failed", failed)
+		}
+		heading += ")"
+		if d := s.durations[section]; d > 0 {
+			heading += " in " + d.Round(100*time.Millisecond).String()
+		}
+		subjectTitle, nameTitle := "MAC", "Name"
+		if section == sectionWLANs {
+			subjectTitle, nameTitle = "SSID", "Template"
+		}
+		printer := formatter.NewGenericTablePrinter(formatter.TableConfig{
+			Title:         heading,
+			Format:        "table",
+			BoldHeaders:   true,
+			ShowSeparator: true,
+			CommandPath:   "apply.summary",
+			Columns: []formatter.TableColumn{
+				{Field: "site", Title: "Site"},
+				{Field: "type", Title: "Type"},
+				{Field: "subject", Title: subjectTitle},
+				{Field: "name", Title: nameTitle},
+				{Field: "result", Title: "Result"},
+				{Field: "detail", Title: "Detail"},
+			},
+		}, rows)
+		_, _ = fmt.Fprintf(w, "\n%s", printer.Print())
+	}
+	if table := s.renderHealth(); table != "" {
+		_, _ = fmt.Fprintf(w, "\n%s", table)
+	}
+}
+
+// withoutMACs returns the MACs in all that are not in done.
+func withoutMACs(all, done []string) []string {
+	seen := make(map[string]bool, len(done))
+	for _, mac := range done {
+		seen[mac] = true
+	}
+	var rest []string
+	for _, mac := range all {
+		if !seen[mac] {
+			rest = append(rest, mac)
+		}
+	}
+	return rest
+}
+
+// wlanEntry builds the summary entry for one WLAN change.
+func wlanEntry(site, ssid, templateLabel, result, detail, line string) summaryEntry {
+	return summaryEntry{
+		Site:    site,
+		Type:    "wlan",
+		Subject: ssid,
+		Name:    templateLabel,
+		Result:  result,
+		Detail:  detail,
+		Line:    line,
+	}
+}
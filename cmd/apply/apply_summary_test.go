@@ -0,0 +1,104 @@
+package apply
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ravinald/wifimgr/internal/sitehealth"
+)
+
+func TestApplySummaryRender(t *testing.T) {
+	var buf bytes.Buffer
+
+	// Nothing is shown until a run finishes.
+	s := newApplySummary()
+	s.Render(&buf)
+	if buf.Len() != 0 {
+		t.Fatalf("summary without runs rendered %q", buf.String())
+	}
+
+	s.finishRun(3, false)
+	s.Render(&buf)
+	if !strings.Contains(buf.String(), "No changes needed") {
+		t.Errorf("empty run should say no changes needed, got %q", buf.String())
+	}
+
+	buf.Reset()
+	s = newApplySummary()
+	s.addDevices(sectionUpdate, "US-LAB-01", "ap", resultDone, []string{"aa0000000001", "aa0000000002"})
+	s.addDevices(sectionUpdate, "US-LAB-01", "ap", resultFailed, withoutMACs([]string{"aa0000000001", "aa0000000002", "aa0000000003"}, []string{"aa0000000001", "aa0000000002"}))
+	s.add(sectionWLANs, wlanEntry("US-LAB-01", "Corp", "corp-wlan", resultDone, "create", ""))
+	s.timed(sectionUpdate, time.Now().Add(-2*time.Second))
+	s.finishRun(5, false)
+	s.Render(&buf)
+
+	out := buf.String()
+	for _, want := range []string{
+		"Apply summary: unassign 0, assign 0, update 3, wlans 1; 5 up to date",
+		"Update (3, 1 failed) in 2s",
+		"WLANs (1)",
+		"aa0000000003",
+		"corp-wlan",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("summary missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Assign (") {
+		t.Errorf("empty section rendered:\n%s", out)
+	}
+}
+
+func TestApplySummaryDiffTitle(t *testing.T) {
+	var buf bytes.Buffer
+	s := newApplySummary()
+	s.addDevices(sectionAssign, "US-LAB-01", "switch", resultPending, []string{"bb0000000001"})
+	s.finishRun(0, true)
+	s.Render(&buf)
+	if !strings.Contains(buf.String(), "Diff summary (nothing applied): unassign 0, assign 1") {
+		t.Errorf("unexpected diff headline:\n%s", buf.String())
+	}
+}
+
+func TestSummaryFromContext(t *testing.T) {
+	if summaryFromContext(context.Background()) != nil {
+		t.Error("expected no summary on a bare context")
+	}
+	s := newApplySummary()
+	if summaryFromContext(withApplySummary(context.Background(), s)) != s {
+		t.Error("summary not carried on context")
+	}
+	// A nil summary is safe to use.
+	var none *applySummary
+	none.addDevices(sectionUpdate, "US-LAB-01", "ap", resultDone, []string{"aa0000000001"})
+	none.timed(sectionUpdate, time.Now())
+	none.finishRun(1, false)
+	none.Render(&bytes.Buffer{})
+}
+
+func TestApplySummaryHealth(t *testing.T) {
+	var buf bytes.Buffer
+	s := newApplySummary()
+	s.addDevices(sectionUpdate, "US-LAB-01", "ap", resultDone, []string{"aa0000000001"})
+	s.addHealth(healthEntry{Site: "US-LAB-01", Type: "ap", Comparison: sitehealth.Comparison{
+		Before: sitehealth.Snapshot{Devices: 4, Connected: 4, Clients: 40, Alarms: 1},
+		After:  sitehealth.Snapshot{Devices: 4, Connected: 3, Clients: 38, Alarms: 1},
+	}})
+	s.finishRun(0, false)
+	s.Render(&buf)
+
+	out := buf.String()
+	for _, want := range []string{
+		"Site health, before -> after (1, 1 regressed)",
+		"4/4 -> 3/4",
+		"40 -> 38",
+		"regressed: 1 fewer device(s) connected",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("summary missing %q:\n%s", want, out)
+		}
+	}
+}
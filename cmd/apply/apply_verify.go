@@ -7,6 +7,7 @@ import (
 
 	"github.com/spf13/viper"
 
+	"github.com/ravinald/wifimgr/internal/canonical"
 	"github.com/ravinald/wifimgr/internal/config"
 	"github.com/ravinald/wifimgr/internal/logging"
 	"github.com/ravinald/wifimgr/internal/symbols"
@@ -51,6 +52,7 @@ func recordApplyOutcome(ctx context.Context, client vendors.Client, updater Devi
 	}
 
 	managedKeys := getManagedKeysForDevice(apiLabel, deviceType)
+	diffRules := getDiffRulesForDevice(apiLabel, deviceType)
 	vendorName := config.GetVendorFromAPILabel(apiLabel)
 	remaining := append([]string{}, succeeded...)
 	for attempt := 0; attempt < verifyAttempts && len(remaining) > 0; attempt++ {
@@ -63,7 +65,7 @@ func recordApplyOutcome(ctx context.Context, client vendors.Client, updater Devi
 		}
 		still := make([]string, 0, len(remaining))
 		for _, mac := range remaining {
-			if deviceStillDiverges(updater, batchLoader, siteConfig, mac, vendorName, deviceType, managedKeys) {
+			if deviceStillDiverges(ctx, client, updater, batchLoader, siteConfig, siteID, mac, vendorName, deviceType, managedKeys, diffRules) {
 				still = append(still, mac)
 			}
 		}
@@ -96,18 +98,28 @@ func recordApplyOutcome(ctx context.Context, client vendors.Client, updater Devi
 // deviceStillDiverges reports whether the re-fetched running config for mac still
 // differs from the applicable intent across the managed keys — i.e. the push did not
 // realize what it could apply. Fields the API/device cannot honor are filtered out, so
-// a knowingly-skipped field (e.g. 6 GHz on Meraki) does not read as divergence.
-func deviceStillDiverges(updater DeviceUpdater, batchLoader *DeviceBatchLoader, siteConfig SiteConfig, mac, vendorName, deviceType string, managedKeys []string) bool {
+// a knowingly-skipped field (e.g. 6 GHz on Meraki) does not read as divergence, and
+// the diff ignore rules canonicalize both sides as they do for the plan.
+func deviceStillDiverges(ctx context.Context, client vendors.Client, updater DeviceUpdater, batchLoader *DeviceBatchLoader, siteConfig SiteConfig, siteID, mac, vendorName, deviceType string, managedKeys []string, diffRules []canonical.Rule) bool {
 	desired, _, ok := applicableDesiredConfig(updater, siteConfig, mac, vendorName, deviceType)
 	if !ok {
 		return false
 	}
+	// An AP placed by map name was pushed with the map's ID; compare the same.
+	if ap, isAP := updater.(*APUpdater); isAP {
+		if resolved, err := ap.resolvePlacement(ctx, client, siteID, desired); err == nil {
+			desired = resolved
+		}
+	}
 	device, err := batchLoader.GetDeviceByMAC(mac)
 	if err != nil {
 		// Can't read the running config back — treat as unconfirmed (divergent).
 		return true
 	}
-	return compareDeviceConfigsWithManagedKeys(device.ToConfigMap(), desired, managedKeys)
+	current := device.ToConfigMap()
+	settlePlacement(current, desired)
+	current, desired = canonical.Apply(current, desired, diffRules)
+	return compareDeviceConfigsWithManagedKeys(current, desired, managedKeys)
 }
 
 func subtractMACs(all, remove []string) []string {
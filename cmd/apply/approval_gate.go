@@ -0,0 +1,101 @@
+package apply
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ravinald/wifimgr/internal/approval"
+	"github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/history"
+	"github.com/ravinald/wifimgr/internal/logging"
+	"github.com/ravinald/wifimgr/internal/pipeline"
+	"github.com/ravinald/wifimgr/internal/symbols"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+// requestApproval turns a push to a protected site into a pending change
+// bundle. It reports true when the apply was held for approval, in which case
+// nothing was pushed. Applies running on behalf of 'wifimgr approve' pass
+// through.
+func requestApproval(ctx context.Context, client vendors.Client, cfg *config.Config, args []string, apiLabel string, force bool) (bool, error) {
+	ac := approval.LoadConfig()
+	if !ac.Protects(args[0]) || approval.ApprovedID(ctx) != "" {
+		return false, nil
+	}
+
+	diff, err := renderApplyDiff(ctx, client, cfg, args, apiLabel, force)
+	fmt.Print(diff)
+	if err != nil {
+		return true, fmt.Errorf("site %s requires approval, and its diff could not be rendered: %w", args[0], err)
+	}
+
+	b, err := approval.New(args[0], apiLabel, args, force, diff, history.Operator(), history.Account())
+	if err != nil {
+		return true, err
+	}
+	if err := approval.Save(ac.Dir, b); err != nil {
+		return true, err
+	}
+	logging.Infof("Apply to %s held for approval as change %s (%s)", b.Site, b.ID, b.Hash)
+
+	fmt.Println()
+	fmt.Printf("%s Site %s requires a second operator's approval; nothing was applied.\n", symbols.WarningPrefix(), b.Site)
+	fmt.Printf("    Change %s is pending (requested by %s).\n", b.ID, b.RequestedBy)
+	fmt.Printf("    Another operator approves it with: wifimgr approve %s\n", b.ID)
+	return true, nil
+}
+
+// renderApplyDiff runs the apply in diff mode and returns what it printed.
+func renderApplyDiff(ctx context.Context, client vendors.Client, cfg *config.Config, args []string, apiLabel string, force bool) (string, error) {
+	diffArgs := append(append([]string{}, args...), "diff")
+	return pipeline.CaptureOutput(func() error {
+		return handleCommand(ctx, client, cfg, diffArgs, apiLabel, force)
+	})
+}
+
+// ExecuteApproved runs a pending change bundle on behalf of approver, logged
+// in as account. The
+// diff is rendered again first; if it no longer matches the approved hash
+// (the intent or the live site changed since the request) the bundle is
+// marked stale and nothing is pushed. Every outcome is saved to the bundle.
+func ExecuteApproved(ctx context.Context, client vendors.Client, cfg *config.Config, dir string, b *approval.Bundle, approver, account string) error {
+	if err := b.CheckApprover(account); err != nil {
+		return err
+	}
+
+	diff, err := renderApplyDiff(ctx, client, cfg, b.Args, b.API, b.Force)
+	if err != nil {
+		return fmt.Errorf("re-render diff for change %s: %w", b.ID, err)
+	}
+	if !b.Matches(diff) {
+		b.Status = approval.StatusStale
+		b.FinishedAt = time.Now().UTC()
+		b.Error = "diff changed between request and approval"
+		if err := approval.Save(dir, b); err != nil {
+			return err
+		}
+		fmt.Print(approval.Normalize(diff))
+		return fmt.Errorf("change %s no longer matches what was requested (site %s changed since); it is now stale — request it again with apply", b.ID, b.Site)
+	}
+
+	b.ApprovedBy = approver
+	b.ApprovedByAccount = account
+	b.ApprovedAt = time.Now().UTC()
+	if err := approval.Save(dir, b); err != nil {
+		return err
+	}
+	logging.Infof("Change %s for %s approved by %s (requested by %s)", b.ID, b.Site, approver, b.RequestedBy)
+
+	applyErr := HandleCommand(approval.WithApproved(ctx, b.ID), client, cfg, b.Args, b.API, b.Force)
+	b.FinishedAt = time.Now().UTC()
+	b.Status = approval.StatusApplied
+	if applyErr != nil {
+		b.Status = approval.StatusFailed
+		b.Error = applyErr.Error()
+	}
+	if err := approval.Save(dir, b); err != nil {
+		logging.Warnf("Failed to record the outcome of change %s: %v", b.ID, err)
+	}
+	return applyErr
+}
@@ -0,0 +1,41 @@
+package apply
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+
+	"github.com/ravinald/wifimgr/internal/approval"
+)
+
+func TestRequestApproval_PassThrough(t *testing.T) {
+	dir := t.TempDir()
+	t.Cleanup(func() {
+		viper.Set("approvals.sites", nil)
+		viper.Set("approvals.dir", "")
+	})
+	viper.Set("approvals.sites", []string{"US-HQ-*"})
+	viper.Set("approvals.dir", dir)
+
+	// An unprotected site, and a protected one run on behalf of approve, go
+	// straight through without rendering a diff or storing a bundle.
+	ctx := context.Background()
+	for _, c := range []struct {
+		ctx  context.Context
+		site string
+	}{
+		{ctx, "US-LAB-01"},
+		{approval.WithApproved(ctx, "3fa4c2d1"), "US-HQ-01"},
+	} {
+		held, err := requestApproval(c.ctx, nil, nil, []string{c.site, "ap"}, "mist-prod", false)
+		if held || err != nil {
+			t.Errorf("%s: held=%v err=%v, want pass-through", c.site, held, err)
+		}
+	}
+	if entries, _ := os.ReadDir(filepath.Clean(dir)); len(entries) != 0 {
+		t.Errorf("bundles stored for pass-through applies: %v", entries)
+	}
+}
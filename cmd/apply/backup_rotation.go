@@ -12,8 +12,9 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/helpers"
 	"github.com/ravinald/wifimgr/internal/logging"
-	"github.com/ravinald/wifimgr/internal/xdg"
+	"github.com/ravinald/wifimgr/internal/remotebackup"
 )
 
 // createRotatedBackup function removed - legacy backup format no longer used
@@ -72,7 +73,7 @@ func rotateConfigFileBackups(backupDir string, baseFileName string, maxBackups i
 
 // listBackupsWithRotation lists backups with rotation index information
 func listBackupsWithRotation(_ *config.Config, siteName string) ([]ConfigurationBackup, error) {
-	backupDir := xdg.GetBackupsDir()
+	backupDir := config.BackupDir()
 
 	// Check if backup directory exists
 	if _, err := os.Stat(backupDir); os.IsNotExist(err) {
@@ -109,6 +110,13 @@ func listBackupsWithRotation(_ *config.Config, siteName string) ([]Configuration
 						continue
 					}
 
+					if siteBackup, ok := readSiteBackup(data); ok {
+						if siteBackup.SiteName == siteName {
+							backupGroups[baseFile] = append(backupGroups[baseFile], backupPath)
+						}
+						continue
+					}
+
 					var configData ConfigFileStructure
 					if err := json.Unmarshal(data, &configData); err != nil {
 						continue
@@ -204,7 +212,7 @@ func extractSerial(path string) int {
 
 // cleanupBackupsWithConfig removes backups based on configured retention policy
 func cleanupBackupsWithConfig(cfg *config.Config) error {
-	backupDir := xdg.GetBackupsDir()
+	backupDir := config.BackupDir()
 
 	// Check if backup directory exists
 	if _, err := os.Stat(backupDir); os.IsNotExist(err) {
@@ -296,10 +304,16 @@ func cleanupBackupsWithConfig(cfg *config.Config) error {
 	return nil
 }
 
-// createConfigBackupAfterApply creates a backup of the applied configuration file
-// Note: siteName is unused - backups are file-based, not site-specific
-func createConfigBackupAfterApply(cfg *config.Config, _ string, configFilePath string) error {
-	backupDir := xdg.GetBackupsDir()
+// createConfigBackupAfterApply creates a backup of the applied configuration file.
+// With backup.site_scoped enabled and a siteName given, only that site's block
+// is backed up (see createSiteBackup); otherwise the whole file is.
+func createConfigBackupAfterApply(cfg *config.Config, siteName string, configFilePath string) error {
+	if siteName != "" && siteScopedBackups() {
+		_, err := createSiteBackup(cfg, siteName, configFilePath)
+		return err
+	}
+
+	backupDir := config.BackupDir()
 	if err := os.MkdirAll(backupDir, 0750); err != nil {
 		return fmt.Errorf("failed to create backup directory: %w", err)
 	}
@@ -352,10 +366,14 @@ func createConfigBackupAfterApply(cfg *config.Config, _ string, configFilePath s
 		return fmt.Errorf("failed to marshal backup data: %w", err)
 	}
 
-	if err := os.WriteFile(backupPath, backupData, 0600); err != nil {
+	if err := helpers.WriteFileAtomic(backupPath, backupData, 0600); err != nil {
 		return fmt.Errorf("failed to save backup to %s: %w", backupPath, err)
 	}
 
 	logging.Infof("Configuration backup saved: %s", backupFileName)
+
+	// Mirror off-host when backup.remote is enabled. Best-effort: a bucket
+	// outage must not turn a successful apply into a failure.
+	remotebackup.MirrorFiles(remotebackup.KindConfig, backupPath)
 	return nil
 }
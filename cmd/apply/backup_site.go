@@ -0,0 +1,333 @@
+package apply
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/helpers"
+	"github.com/ravinald/wifimgr/internal/logging"
+	"github.com/ravinald/wifimgr/internal/merge3"
+	"github.com/ravinald/wifimgr/internal/remotebackup"
+	"github.com/ravinald/wifimgr/internal/symbols"
+)
+
+// siteBackupFormat marks a backup holding one site block instead of a whole
+// config file.
+const siteBackupFormat = "site"
+
+// SiteBackup is a site-scoped backup: the config.sites block of one site and
+// where it came from. With backup.site_scoped enabled, apply writes these
+// instead of copying the whole config file, and only when the block changed
+// since the last one, so a monolithic file of hundreds of sites does not fill
+// the backup directory with copies of sites nobody touched.
+type SiteBackup struct {
+	Format       string         `json:"format"`
+	File         string         `json:"file"` // base name of the config file the block belongs to
+	SiteKey      string         `json:"site_key"`
+	SiteName     string         `json:"site_name"`
+	LastModified string         `json:"last_modified"`
+	Site         map[string]any `json:"site"`
+}
+
+// siteScopedBackups reports whether backup.site_scoped is enabled.
+func siteScopedBackups() bool {
+	return viper.GetBool("backup.site_scoped")
+}
+
+// siteBackupBaseName is the rotation base for a site's backups, e.g.
+// "us-sites.json.site-us-lab-01"; serials are appended as for file backups.
+func siteBackupBaseName(configFileName, siteKey string) string {
+	return fmt.Sprintf("%s.site-%s", configFileName, siteKey)
+}
+
+// findSiteBlock returns the key and block of siteName in a parsed config
+// file. The site is matched by site_config.name, falling back to the key.
+func findSiteBlock(configData map[string]any, siteName string) (string, map[string]any, bool) {
+	configSection, _ := configData["config"].(map[string]any)
+	sites, _ := configSection["sites"].(map[string]any)
+	for key, raw := range sites {
+		block, ok := raw.(map[string]any)
+		if !ok {
+			continue
+		}
+		siteConfig, _ := block["site_config"].(map[string]any)
+		if name, _ := siteConfig["name"].(string); name == siteName {
+			return key, block, true
+		}
+	}
+	if block, ok := sites[siteName].(map[string]any); ok {
+		return siteName, block, true
+	}
+	return "", nil, false
+}
+
+// readSiteBackup parses a site-scoped backup. ok is false for any other
+// backup, including whole-file backups.
+func readSiteBackup(data []byte) (*SiteBackup, bool) {
+	var b SiteBackup
+	if err := json.Unmarshal(data, &b); err != nil || b.Format != siteBackupFormat || b.Site == nil {
+		return nil, false
+	}
+	return &b, true
+}
+
+// createSiteBackup backs up the block of siteName in configFilePath. It
+// writes nothing when the block matches the most recent backup of the site.
+// It returns the written backup path, or "" when nothing was written.
+func createSiteBackup(cfg *config.Config, siteName, configFilePath string) (string, error) {
+	data, err := os.ReadFile(configFilePath) // #nosec G304 -- path from operator-controlled config
+	if err != nil {
+		return "", fmt.Errorf("failed to read config file %s: %w", configFilePath, err)
+	}
+	var configData map[string]any
+	if err := json.Unmarshal(data, &configData); err != nil {
+		return "", fmt.Errorf("failed to parse config file: %w", err)
+	}
+	siteKey, block, ok := findSiteBlock(configData, siteName)
+	if !ok {
+		return "", fmt.Errorf("site %s not found in %s", siteName, configFilePath)
+	}
+
+	backupDir := config.BackupDir()
+	if err := os.MkdirAll(backupDir, 0750); err != nil {
+		return "", fmt.Errorf("failed to create backup directory: %w", err)
+	}
+	configFileName := filepath.Base(configFilePath)
+	baseName := siteBackupBaseName(configFileName, siteKey)
+
+	latestPath := filepath.Join(backupDir, baseName+".0")
+	if latest, err := os.ReadFile(latestPath); err == nil { // #nosec G304 -- path under the backups directory
+		if prev, ok := readSiteBackup(latest); ok && reflect.DeepEqual(prev.Site, block) {
+			logging.Debugf("Site %s unchanged since %s; no backup written", siteName, filepath.Base(latestPath))
+			return "", nil
+		}
+	}
+
+	maxBackups := 10 // Default
+	if cfg.Files.ConfigBackups > 0 {
+		maxBackups = cfg.Files.ConfigBackups
+	}
+	if err := rotateConfigFileBackups(backupDir, baseName, maxBackups); err != nil {
+		logging.Warnf("Failed to rotate backups: %v", err)
+	}
+
+	backupData, err := json.MarshalIndent(SiteBackup{
+		Format:       siteBackupFormat,
+		File:         configFileName,
+		SiteKey:      siteKey,
+		SiteName:     siteName,
+		LastModified: time.Now().UTC().Format(time.RFC3339),
+		Site:         block,
+	}, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal backup data: %w", err)
+	}
+	if err := helpers.WriteFileAtomic(latestPath, backupData, 0600); err != nil {
+		return "", fmt.Errorf("failed to save backup to %s: %w", latestPath, err)
+	}
+	logging.Infof("Site configuration backup saved: %s", filepath.Base(latestPath))
+
+	remotebackup.MirrorFiles(remotebackup.KindConfig, latestPath)
+	return latestPath, nil
+}
+
+// rollbackSiteBlock restores one site's block from its site-scoped backup at
+// backupIndex, leaving every other site in the file as it is. The current
+// block is backed up first, so the rollback itself can be undone. Restoring
+// an older backup over a block edited since the most recent one merges the
+// two, as rollbackConfigFile does for whole files.
+func rollbackSiteBlock(cfg *config.Config, siteName, configFilePath string, backupIndex int, force bool) error {
+	data, err := os.ReadFile(configFilePath) // #nosec G304 -- path from operator-controlled config
+	if err != nil {
+		return fmt.Errorf("failed to read current config: %w", err)
+	}
+	var configData map[string]any
+	if err := json.Unmarshal(data, &configData); err != nil {
+		return fmt.Errorf("failed to parse current config: %w", err)
+	}
+	siteKey, current, ok := findSiteBlock(configData, siteName)
+	if !ok {
+		return fmt.Errorf("site %s not found in %s", siteName, configFilePath)
+	}
+
+	backupName := fmt.Sprintf("%s.%d", siteBackupBaseName(filepath.Base(configFilePath), siteKey), backupIndex)
+	backupPath := filepath.Join(config.BackupDir(), backupName)
+	backupData, err := os.ReadFile(backupPath) // #nosec G304 -- path under the backups directory
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("backup file not found: %s", backupName)
+		}
+		return fmt.Errorf("failed to read backup file: %w", err)
+	}
+	backup, ok := readSiteBackup(backupData)
+	if !ok {
+		return fmt.Errorf("%s is not a site backup", backupName)
+	}
+
+	fmt.Printf("Rolling back site %s from backup index %d\n", siteName, backupIndex)
+	fmt.Printf("  Config file: %s\n", configFilePath)
+	fmt.Printf("  Backup file: %s\n", backupPath)
+
+	restore, merged := backup.Site, false
+	if backupIndex > 0 {
+		latestPath := filepath.Join(config.BackupDir(), siteBackupBaseName(filepath.Base(configFilePath), siteKey)+".0")
+		if latestData, err := os.ReadFile(latestPath); err == nil { // #nosec G304 -- path under the backups directory
+			if latest, ok := readSiteBackup(latestData); ok && !reflect.DeepEqual(current, latest.Site) {
+				block, conflicts := merge3.Merge(latest.Site, current, backup.Site)
+				plan := &rollbackMerge{Merged: block, Sites: []rollbackSiteOutcome{{
+					Key: siteKey, Name: siteName, Outcome: mergeOutcome(latest.Site, current, backup.Site, conflicts), Conflicts: conflicts,
+				}}}
+				printRollbackMerge(plan)
+				if err := resolveRollbackConflicts(plan, force); err != nil {
+					return err
+				}
+				restore, merged = plan.Merged, true
+			}
+		}
+	}
+
+	// The selected backup is already in memory, so rotation below cannot
+	// shift it out from under us.
+	saved, err := createSiteBackup(cfg, siteName, configFilePath)
+	if err != nil {
+		return fmt.Errorf("failed to back up current site block: %w", err)
+	}
+	if saved != "" {
+		fmt.Printf("  Created backup: %s (previous site block)\n", filepath.Base(saved))
+	}
+
+	if err := writeSiteBlock(configFilePath, configData, siteKey, restore); err != nil {
+		return err
+	}
+
+	fmt.Printf("  Restored: %s -> config.sites.%s in %s\n", backupName, siteKey, filepath.Base(configFilePath))
+	if merged {
+		fmt.Printf("  Merged with edits made since the most recent backup\n")
+	}
+	fmt.Printf("\nRollback complete. The configuration has NOT been applied to the API.\n")
+	fmt.Printf("To review changes: wifimgr apply site %s ap diff\n", siteName)
+	fmt.Printf("To apply changes:  wifimgr apply site %s ap\n", siteName)
+	return nil
+}
+
+// writeSiteBlock replaces config.sites[siteKey] in the parsed configData and
+// writes the file back.
+func writeSiteBlock(configFilePath string, configData map[string]any, siteKey string, block map[string]any) error {
+	sites := configData["config"].(map[string]any)["sites"].(map[string]any)
+	sites[siteKey] = block
+	out, err := json.MarshalIndent(configData, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+	if err := os.WriteFile(configFilePath, append(out, '\n'), 0600); err != nil { // #nosec G304 G703 -- path from operator-controlled config
+		return fmt.Errorf("failed to restore config: %w", err)
+	}
+	return nil
+}
+
+// validateSiteBackup reports on a site-scoped backup for validate-backup.
+func validateSiteBackup(b *SiteBackup) error {
+	var site SiteConfig
+	data, err := json.Marshal(b.Site)
+	if err == nil {
+		err = json.Unmarshal(data, &site)
+	}
+	if err != nil {
+		fmt.Printf("%s Validation failed: invalid site block: %v\n", symbols.FailurePrefix(), err)
+		return err
+	}
+	if b.SiteKey == "" || b.File == "" {
+		fmt.Printf("%s Validation failed: missing site_key or file\n", symbols.FailurePrefix())
+		return fmt.Errorf("site backup is missing site_key or file")
+	}
+
+	fmt.Printf("%s Backup validation passed\n\n", symbols.SuccessPrefix())
+	fmt.Printf("Backup Details:\n")
+	fmt.Printf("  Format: site\n")
+	fmt.Printf("  Site: %s (config.sites.%s in %s)\n", b.SiteName, b.SiteKey, b.File)
+	fmt.Printf("  Total Devices: %d\n", len(site.Devices.APs)+len(site.Devices.Switches)+len(site.Devices.WanEdge))
+	fmt.Printf("  Last Modified: %s\n", b.LastModified)
+	return nil
+}
+
+// handleRestoreSiteCommand restores one site's block from a backup into the
+// current config file: "restore-site <site> from <backup>". The backup may be
+// a whole-file backup, from which only the site's block is taken, or a
+// site-scoped one. Unlike rollback, sites edited since the backup keep their
+// edits.
+func handleRestoreSiteCommand(cfg *config.Config, siteName string, args []string) error {
+	if len(args) != 2 || args[0] != "from" {
+		return fmt.Errorf("usage: apply restore-site <site-name> from <backup-file>")
+	}
+	configFilePath, err := findConfigFileForSite(cfg, siteName)
+	if err != nil {
+		return err
+	}
+	return restoreSiteFromBackup(cfg, siteName, configFilePath, resolveBackupPath(args[1]))
+}
+
+// restoreSiteFromBackup merges siteName's block from backupPath into
+// configFilePath, backing up the current state first.
+func restoreSiteFromBackup(cfg *config.Config, siteName, configFilePath, backupPath string) error {
+	backupData, err := os.ReadFile(backupPath) // #nosec G304 -- backup named by the operator
+	if err != nil {
+		return fmt.Errorf("failed to read backup file: %w", err)
+	}
+	var block map[string]any
+	if siteBackup, ok := readSiteBackup(backupData); ok {
+		if siteBackup.SiteName != siteName {
+			return fmt.Errorf("%s is a backup of site %s, not %s", filepath.Base(backupPath), siteBackup.SiteName, siteName)
+		}
+		block = siteBackup.Site
+	} else {
+		var backupConfig map[string]any
+		if err := json.Unmarshal(backupData, &backupConfig); err != nil {
+			return fmt.Errorf("failed to parse backup file: %w", err)
+		}
+		if _, block, ok = findSiteBlock(backupConfig, siteName); !ok {
+			return fmt.Errorf("site %s not found in backup %s", siteName, filepath.Base(backupPath))
+		}
+	}
+
+	data, err := os.ReadFile(configFilePath) // #nosec G304 -- path from operator-controlled config
+	if err != nil {
+		return fmt.Errorf("failed to read current config: %w", err)
+	}
+	var configData map[string]any
+	if err := json.Unmarshal(data, &configData); err != nil {
+		return fmt.Errorf("failed to parse current config: %w", err)
+	}
+	siteKey, current, ok := findSiteBlock(configData, siteName)
+	if !ok {
+		return fmt.Errorf("site %s not found in %s", siteName, configFilePath)
+	}
+	if reflect.DeepEqual(current, block) {
+		fmt.Printf("Site %s already matches %s; nothing to restore.\n", siteName, filepath.Base(backupPath))
+		return nil
+	}
+
+	fmt.Printf("Restoring site %s from %s\n", siteName, backupPath)
+	fmt.Printf("  Config file: %s\n", configFilePath)
+
+	// Back up the current state in the configured format so the restore can
+	// itself be rolled back.
+	if err := createConfigBackupAfterApply(cfg, siteName, configFilePath); err != nil {
+		return fmt.Errorf("failed to back up current config: %w", err)
+	}
+	if err := writeSiteBlock(configFilePath, configData, siteKey, block); err != nil {
+		return err
+	}
+
+	fmt.Printf("  Restored: config.sites.%s in %s; other sites unchanged\n", siteKey, filepath.Base(configFilePath))
+	fmt.Printf("\nRestore complete. The configuration has NOT been applied to the API.\n")
+	fmt.Printf("To review changes: wifimgr apply site %s ap diff\n", siteName)
+	fmt.Printf("To apply changes:  wifimgr apply site %s ap\n", siteName)
+	return nil
+}
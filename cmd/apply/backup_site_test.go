@@ -0,0 +1,130 @@
+package apply
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+
+	"github.com/ravinald/wifimgr/internal/config"
+)
+
+const twoSiteConfig = `{
+  "version": 1,
+  "config": {"sites": {
+    "lab-01": {"site_config": {"name": "LAB-01"}, "devices": {"ap": {"aabbccddeeff": {"name": "ap-1"}}}},
+    "lab-02": {"site_config": {"name": "LAB-02"}, "devices": {"ap": {}}}
+  }}
+}`
+
+func TestSiteBackupAndRollback(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	viper.Set("backup.site_scoped", true)
+	t.Cleanup(func() { viper.Set("backup.site_scoped", nil) })
+
+	file := filepath.Join(t.TempDir(), "sites.json")
+	if err := os.WriteFile(file, []byte(twoSiteConfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{}
+
+	if err := createConfigBackupAfterApply(cfg, "LAB-01", file); err != nil {
+		t.Fatalf("backup: %v", err)
+	}
+	backups, err := listBackupsWithRotation(cfg, "LAB-01")
+	if err != nil || len(backups) != 1 {
+		t.Fatalf("backups = %+v, %v; want one", backups, err)
+	}
+	if got := filepath.Base(backups[0].BackupFilePath); got != "sites.json.site-lab-01.0" {
+		t.Errorf("backup name = %s", got)
+	}
+
+	// Unchanged block: no new backup.
+	if path, err := createSiteBackup(cfg, "LAB-01", file); err != nil || path != "" {
+		t.Fatalf("unchanged site wrote %q, %v", path, err)
+	}
+
+	// Edit both sites, then roll LAB-01 back; LAB-02's edit must survive.
+	var data map[string]any
+	raw, _ := os.ReadFile(file)
+	_ = json.Unmarshal(raw, &data)
+	sites := data["config"].(map[string]any)["sites"].(map[string]any)
+	sites["lab-01"].(map[string]any)["site_config"].(map[string]any)["notes"] = "edited"
+	sites["lab-02"].(map[string]any)["site_config"].(map[string]any)["notes"] = "kept"
+	raw, _ = json.Marshal(data)
+	if err := os.WriteFile(file, raw, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := rollbackSiteBlock(cfg, "LAB-01", file, 0, false); err != nil {
+		t.Fatalf("rollback: %v", err)
+	}
+	raw, _ = os.ReadFile(file)
+	data = nil
+	if err := json.Unmarshal(raw, &data); err != nil {
+		t.Fatal(err)
+	}
+	sites = data["config"].(map[string]any)["sites"].(map[string]any)
+	if _, ok := sites["lab-01"].(map[string]any)["site_config"].(map[string]any)["notes"]; ok {
+		t.Error("LAB-01 was not rolled back")
+	}
+	if sites["lab-02"].(map[string]any)["site_config"].(map[string]any)["notes"] != "kept" {
+		t.Error("rollback touched LAB-02")
+	}
+
+	// The edited block was saved as .0 and the restored one rotated to .1.
+	backups, _ = listBackupsWithRotation(cfg, "LAB-01")
+	if len(backups) != 2 {
+		t.Fatalf("got %d backups after rollback, want 2", len(backups))
+	}
+	if others, _ := listBackupsWithRotation(cfg, "LAB-02"); len(others) != 0 {
+		t.Errorf("LAB-02 has %d backups, want 0", len(others))
+	}
+}
+
+func TestRestoreSiteFromWholeFileBackup(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	dir := t.TempDir()
+	backup := filepath.Join(dir, "sites.json.0")
+	if err := os.WriteFile(backup, []byte(twoSiteConfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Both sites were edited after the backup was taken.
+	edited := `{"version": 1, "config": {"sites": {
+	  "lab-01": {"site_config": {"name": "LAB-01", "notes": "bad"}, "devices": {"ap": {}}},
+	  "lab-02": {"site_config": {"name": "LAB-02", "notes": "good"}, "devices": {"ap": {}}}
+	}}}`
+	file := filepath.Join(dir, "sites.json")
+	if err := os.WriteFile(file, []byte(edited), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := restoreSiteFromBackup(&config.Config{}, "LAB-01", file, backup); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+
+	var data ConfigFileStructure
+	raw, _ := os.ReadFile(file)
+	if err := json.Unmarshal(raw, &data); err != nil {
+		t.Fatal(err)
+	}
+	lab1, lab2 := data.Config.Sites["lab-01"], data.Config.Sites["lab-02"]
+	if _, ok := lab1.SiteConfig["notes"]; ok || len(lab1.Devices.APs) != 1 {
+		t.Errorf("LAB-01 not restored: %+v", lab1)
+	}
+	if lab2.SiteConfig["notes"] != "good" {
+		t.Errorf("LAB-02 lost its edit: %+v", lab2.SiteConfig)
+	}
+
+	// The pre-restore file was backed up.
+	if _, err := os.Stat(filepath.Join(os.Getenv("XDG_STATE_HOME"), "wifimgr", "backups", "sites.json.0")); err != nil {
+		t.Errorf("no backup of the pre-restore config: %v", err)
+	}
+
+	if err := restoreSiteFromBackup(&config.Config{}, "LAB-03", file, backup); err == nil {
+		t.Error("restoring a site missing from the backup succeeded")
+	}
+}
@@ -0,0 +1,72 @@
+package apply
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/freeze"
+	"github.com/ravinald/wifimgr/internal/logging"
+	"github.com/ravinald/wifimgr/internal/symbols"
+)
+
+// enforceChangeFreeze consults the change_freeze calendar before a mutating
+// apply. Inside an active window it prints a banner; in block mode it refuses
+// unless force was given. A calendar that cannot be read is reported and does
+// not block, so an outage of the calendar host never stops an incident fix.
+func enforceChangeFreeze(ctx context.Context, cfg *config.Config, siteName string, force bool) error {
+	fc, err := freeze.LoadConfig()
+	if err != nil {
+		return err
+	}
+	if fc == nil {
+		return nil
+	}
+
+	windows, err := freeze.Load(ctx, fc.Calendar)
+	if err != nil {
+		logging.Warnf("Change-freeze calendar unavailable: %v", err)
+		fmt.Printf("%s Change-freeze calendar could not be read (%v); proceeding without a freeze check\n",
+			symbols.WarningPrefix(), err)
+		return nil
+	}
+
+	active := freeze.Active(windows, freezeSiteFor(cfg, siteName), time.Now())
+	if len(active) == 0 {
+		return nil
+	}
+
+	fmt.Println()
+	fmt.Println(strings.Repeat("!", 72))
+	fmt.Printf("%s CHANGE FREEZE in effect for site %s\n", symbols.WarningPrefix(), siteName)
+	for _, w := range active {
+		fmt.Printf("    %s  (%s -> %s)\n", w.Name,
+			w.Start.Local().Format("2006-01-02 15:04 MST"), w.End.Local().Format("2006-01-02 15:04 MST"))
+	}
+	fmt.Println(strings.Repeat("!", 72))
+	fmt.Println()
+
+	if fc.Mode == freeze.ModeBlock && !force {
+		return fmt.Errorf("apply to %s blocked by change freeze %q (add 'force' to override)", siteName, active[0].Name)
+	}
+	if force {
+		logging.Warnf("Applying to %s during change freeze %q (forced)", siteName, active[0].Name)
+	}
+	return nil
+}
+
+// freezeSiteFor builds the match target from the site's intent config. A
+// site missing from intent still matches site-name globs.
+func freezeSiteFor(cfg *config.Config, siteName string) freeze.Site {
+	site := freeze.Site{Name: siteName}
+	siteConfig, err := getSiteConfiguration(cfg, siteConfigFiles(cfg), siteName)
+	if err != nil {
+		logging.Debugf("Change freeze: no intent config for %s, matching by name only: %v", siteName, err)
+		return site
+	}
+	site.CountryCode, _ = siteConfig.SiteConfig["country_code"].(string)
+	site.Timezone, _ = siteConfig.SiteConfig["timezone"].(string)
+	return site
+}
@@ -0,0 +1,119 @@
+package apply
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/viper"
+
+	configPkg "github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/logging"
+	"github.com/ravinald/wifimgr/internal/macaddr"
+	"github.com/ravinald/wifimgr/internal/symbols"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+// DeviceRef locates a device's intent: the site config that declares it, its
+// type there, and the API it resolves to (its own "api" field, else the site's).
+type DeviceRef struct {
+	SiteName   string
+	DeviceType string
+	MAC        string
+	API        string
+}
+
+// FindDeviceIntent scans the site configs for mac and returns where it is
+// declared. siteDefaultAPI resolves the site's API when the device pins none;
+// it may be nil or return "" to leave API unset. A MAC
+// declared at more than one site is an error, since the diff would be ambiguous.
+func FindDeviceIntent(cfg *configPkg.Config, mac string, siteDefaultAPI func(string) string) (*DeviceRef, error) {
+	normalized := macaddr.NormalizeOrEmpty(mac)
+	if normalized == "" {
+		return nil, fmt.Errorf("invalid MAC: %q", mac)
+	}
+	if cfg.Files.ConfigDir != "" {
+		if err := os.Setenv("CONFIG_DIR", cfg.Files.ConfigDir); err != nil {
+			return nil, fmt.Errorf("error setting CONFIG_DIR environment variable: %v", err)
+		}
+	}
+	siteConfigs, err := getSiteConfigsFromFiles(siteConfigFiles(cfg))
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []DeviceRef
+	for siteName, sc := range siteConfigs {
+		for _, dtype := range []string{"ap", "switch", "gateway"} {
+			groups, err := groupDevicesByAPI(sc, dtype, "")
+			if err != nil {
+				return nil, err
+			}
+			for api, macs := range groups {
+				for _, m := range macs {
+					if m == normalized {
+						matches = append(matches, DeviceRef{SiteName: siteName, DeviceType: dtype, MAC: normalized, API: api})
+					}
+				}
+			}
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("device %s is not declared in any site config", mac)
+	case 1:
+		ref := &matches[0]
+		if ref.API == "" && siteDefaultAPI != nil {
+			ref.API = siteDefaultAPI(ref.SiteName)
+		}
+		return ref, nil
+	}
+	sites := make([]string, 0, len(matches))
+	for _, m := range matches {
+		sites = append(sites, m.SiteName)
+	}
+	sort.Strings(sites)
+	return nil, fmt.Errorf("device %s is declared in more than one site config: %v", mac, sites)
+}
+
+// DiffDevice prints the managed-key diff between one device's intent (site
+// config with templates expanded) and its current config from the API cache —
+// the same comparison a site diff makes, for just this device. Nothing is
+// pushed. Returns whether the device differs from intent.
+func DiffDevice(ctx context.Context, client vendors.Client, cfg *configPkg.Config, ref *DeviceRef) (bool, error) {
+	updater, err := getDeviceUpdater(ref.DeviceType)
+	if err != nil {
+		return false, err
+	}
+
+	templates, err := loadTemplatesFromConfig(cfg)
+	if err != nil {
+		logging.Warnf("Failed to load templates: %v - continuing without template expansion", err)
+		templates = configPkg.NewTemplateStore()
+	}
+
+	siteConfig, err := getSiteConfiguration(cfg, siteConfigFiles(cfg), ref.SiteName)
+	if err != nil {
+		return false, err
+	}
+	setTemplateStore(templatesForSite(templates, ref.SiteName, siteConfig), ref.API)
+	siteID, err := getSiteIDByName(client, ref.SiteName)
+	if err != nil {
+		return false, fmt.Errorf("error getting site ID for %s: %v", ref.SiteName, err)
+	}
+
+	if !isManagedKeysConfigured(ref.API, ref.DeviceType) {
+		fmt.Printf("%s No managed keys configured for %s devices (api.%s.managed_keys.%s); every field is compared.\n",
+			symbols.WarningPrefix(), ref.DeviceType, ref.API, ref.DeviceType)
+	}
+
+	// FindDevicesToUpdate renders the diff when show_diff is set.
+	viper.Set("show_diff", true)
+	differs, err := updater.FindDevicesToUpdate(ctx, client, cfg, siteConfig, []string{ref.MAC}, siteID, ref.API)
+	if err != nil {
+		return false, err
+	}
+	return len(differs) > 0, nil
+}
@@ -0,0 +1,60 @@
+package apply
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	configPkg "github.com/ravinald/wifimgr/internal/config"
+)
+
+const deviceDiffSites = `{
+  "config": {"sites": {
+    "lab": {"site_config": {"name": "US-LAB-01"}, "devices": {
+      "ap": {"5c:5b:35:8e:4c:f9": {"name": "lab-ap-01"}, "aa0000000002": {"name": "lab-ap-02", "api": "meraki-lab"}},
+      "switch": {"aa0000000010": {"name": "lab-sw-01"}}
+    }},
+    "dup": {"site_config": {"name": "US-DUP-01"}, "devices": {"ap": {"bb0000000001": {}}}},
+    "dup2": {"site_config": {"name": "US-DUP-02"}, "devices": {"ap": {"bb:00:00:00:00:01": {}}}}
+  }}
+}`
+
+func TestFindDeviceIntent(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "sites.json"), []byte(deviceDiffSites), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &configPkg.Config{}
+	cfg.Files.ConfigDir = dir
+	cfg.Files.SiteConfigs = []string{"sites.json"}
+	t.Setenv("CONFIG_DIR", dir)
+	siteAPI := func(string) string { return "mist-prod" }
+
+	tests := []struct {
+		mac, site, dtype, api, err string
+	}{
+		{mac: "5C5B.358E.4CF9", site: "US-LAB-01", dtype: "ap", api: "mist-prod"},
+		{mac: "aa:00:00:00:00:02", site: "US-LAB-01", dtype: "ap", api: "meraki-lab"},
+		{mac: "aa0000000010", site: "US-LAB-01", dtype: "switch", api: "mist-prod"},
+		{mac: "cc0000000001", err: "not declared"},
+		{mac: "bb0000000001", err: "more than one site"},
+		{mac: "not-a-mac", err: "invalid MAC"},
+	}
+	for _, tt := range tests {
+		ref, err := FindDeviceIntent(cfg, tt.mac, siteAPI)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("%s: err = %v, want %q", tt.mac, err, tt.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.mac, err)
+			continue
+		}
+		if ref.SiteName != tt.site || ref.DeviceType != tt.dtype || ref.API != tt.api {
+			t.Errorf("%s: got %+v, want site=%s type=%s api=%s", tt.mac, *ref, tt.site, tt.dtype, tt.api)
+		}
+	}
+}
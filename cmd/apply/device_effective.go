@@ -0,0 +1,70 @@
+package apply
+
+import (
+	"fmt"
+	"sort"
+
+	configPkg "github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/logging"
+)
+
+// EffectiveDeviceConfig resolves one device's intent into the config apply
+// would push for it — model defaults, templates, site WLANs and device
+// overrides expanded, intent-only keys dropped. Nothing is read from the API;
+// the model comes from the cache.
+func EffectiveDeviceConfig(cfg *configPkg.Config, ref *DeviceRef) (map[string]any, error) {
+	updater, err := getDeviceUpdater(ref.DeviceType)
+	if err != nil {
+		return nil, err
+	}
+	templates, err := loadTemplatesFromConfig(cfg)
+	if err != nil {
+		logging.Warnf("Failed to load templates: %v - continuing without template expansion", err)
+		templates = configPkg.NewTemplateStore()
+	}
+	siteConfig, err := getSiteConfiguration(cfg, siteConfigFiles(cfg), ref.SiteName)
+	if err != nil {
+		return nil, err
+	}
+	deviceConfig, ok := updater.GetDeviceConfigFromSite(siteConfig, ref.MAC)
+	if !ok {
+		return nil, fmt.Errorf("device %s is not declared as %s at site %s", ref.MAC, ref.DeviceType, ref.SiteName)
+	}
+	templates = templatesForSite(templates, ref.SiteName, siteConfig)
+
+	siteWLANs := configPkg.GetSiteWLANLabels(siteConfig.SiteConfig)
+	expanded, err := configPkg.ExpandDeviceConfigForModel(deviceConfig, deviceModel(ref.MAC), siteWLANs, templates, ref.API)
+	if err != nil {
+		return nil, err
+	}
+	if ref.DeviceType == "switch" {
+		expanded = withoutIntentOnlySwitchKeys(expanded)
+	}
+	return expanded, nil
+}
+
+// SiteDeviceRefs lists the devices of deviceType declared at siteName, sorted
+// by MAC. siteDefaultAPI resolves the API for devices that pin none, as in
+// FindDeviceIntent.
+func SiteDeviceRefs(cfg *configPkg.Config, siteName, deviceType string, siteDefaultAPI func(string) string) ([]DeviceRef, error) {
+	siteConfig, err := getSiteConfiguration(cfg, siteConfigFiles(cfg), siteName)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := groupDevicesByAPI(siteConfig, deviceType, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var refs []DeviceRef
+	for api, macs := range groups {
+		if api == "" && siteDefaultAPI != nil {
+			api = siteDefaultAPI(siteName)
+		}
+		for _, mac := range macs {
+			refs = append(refs, DeviceRef{SiteName: siteName, DeviceType: deviceType, MAC: mac, API: api})
+		}
+	}
+	sort.Slice(refs, func(i, j int) bool { return refs[i].MAC < refs[j].MAC })
+	return refs, nil
+}
@@ -0,0 +1,120 @@
+package apply
+
+import (
+	"fmt"
+	"strings"
+
+	configPkg "github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/logging"
+	"github.com/ravinald/wifimgr/internal/macaddr"
+	"github.com/ravinald/wifimgr/internal/vendors/meraki"
+)
+
+// DeviceExplanation is a device's effective config, field by field, with the
+// layer each value came from.
+type DeviceExplanation struct {
+	Model  string                     `json:"model,omitempty"` // from the API inventory cache
+	Fields []configPkg.ExplainedField `json:"fields"`
+	// Skipped lists the fields the target API cannot apply; apply drops them
+	// during vendor translation, so they are not in Fields.
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+// ExplainDevice resolves one device's intent the way apply does — model
+// defaults, templates, site WLANs, device overrides, then the vendor's
+// applicable-field filter — and records where every final field was set.
+// Nothing is read from the API; the model comes from the cache.
+func ExplainDevice(cfg *configPkg.Config, ref *DeviceRef) (*DeviceExplanation, error) {
+	updater, err := getDeviceUpdater(ref.DeviceType)
+	if err != nil {
+		return nil, err
+	}
+	templates, err := loadTemplatesFromConfig(cfg)
+	if err != nil {
+		logging.Warnf("Failed to load templates: %v - continuing without template expansion", err)
+		templates = configPkg.NewTemplateStore()
+	}
+	siteConfig, err := getSiteConfiguration(cfg, siteConfigFiles(cfg), ref.SiteName)
+	if err != nil {
+		return nil, err
+	}
+	deviceConfig, ok := updater.GetDeviceConfigFromSite(siteConfig, ref.MAC)
+	if !ok {
+		return nil, fmt.Errorf("device %s is not declared as %s at site %s", ref.MAC, ref.DeviceType, ref.SiteName)
+	}
+	templates = templatesForSite(templates, ref.SiteName, siteConfig)
+
+	model := deviceModel(ref.MAC)
+	siteWLANs := configPkg.GetSiteWLANLabels(siteConfig.SiteConfig)
+	fields, err := configPkg.ExplainDeviceConfig(deviceConfig, model, siteWLANs, templates, ref.API, deviceLocation(siteConfig, ref))
+	if err != nil {
+		return nil, err
+	}
+
+	out := &DeviceExplanation{Model: model, Fields: fields}
+	if configPkg.GetVendorFromAPILabel(ref.API) == "meraki" && ref.DeviceType == "ap" {
+		expanded, err := configPkg.ExpandDeviceConfigForModel(deviceConfig, model, siteWLANs, templates, ref.API)
+		if err != nil {
+			return nil, err
+		}
+		_, out.Skipped = meraki.FilterApplicableRadio(expanded)
+		out.Fields = withoutSkipped(fields, out.Skipped)
+	}
+	return out, nil
+}
+
+// deviceLocation finds the site config file and key path of the device's
+// entry, for line annotations. The file is left empty when the site index
+// does not know the site.
+func deviceLocation(siteConfig SiteConfig, ref *DeviceRef) configPkg.DeviceLocation {
+	file, ok := configPkg.GetSiteConfigFullPath(ref.SiteName)
+	if !ok {
+		return configPkg.DeviceLocation{}
+	}
+	siteKey, ok := configPkg.GetSiteConfigKey(ref.SiteName)
+	if !ok {
+		siteKey = ref.SiteName
+	}
+
+	var devices map[string]map[string]any
+	switch ref.DeviceType {
+	case "ap":
+		devices = siteConfig.Devices.APs
+	case "switch":
+		devices = siteConfig.Devices.Switches
+	case "gateway":
+		devices = siteConfig.Devices.WanEdge
+	}
+	macKey := ref.MAC
+	for key := range devices {
+		if macaddr.NormalizeOrEmpty(key) == ref.MAC {
+			macKey = key
+			break
+		}
+	}
+	return configPkg.DeviceLocation{
+		File:    file,
+		KeyPath: []string{"config", "sites", siteKey, "devices", ref.DeviceType, macKey},
+	}
+}
+
+// withoutSkipped drops the fields under any skipped path.
+func withoutSkipped(fields []configPkg.ExplainedField, skipped []string) []configPkg.ExplainedField {
+	if len(skipped) == 0 {
+		return fields
+	}
+	kept := fields[:0:0]
+	for _, f := range fields {
+		drop := false
+		for _, s := range skipped {
+			if f.Path == s || strings.HasPrefix(f.Path, s+".") {
+				drop = true
+				break
+			}
+		}
+		if !drop {
+			kept = append(kept, f)
+		}
+	}
+	return kept
+}
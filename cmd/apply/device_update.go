@@ -9,6 +9,7 @@ import (
 	"github.com/ravinald/wifimgr/internal/keypath"
 	"github.com/ravinald/wifimgr/internal/logging"
 	"github.com/ravinald/wifimgr/internal/macaddr"
+	"github.com/ravinald/wifimgr/internal/validation"
 	"github.com/ravinald/wifimgr/internal/vendors"
 )
 
@@ -88,7 +89,9 @@ type DeviceInventoryStatus struct {
 // BaseDeviceUpdater provides common functionality for all device types
 type BaseDeviceUpdater struct {
 	deviceType       string
-	inventoryChecker *InventoryChecker // Shared inventory checker for reuse across operations
+	inventoryChecker *InventoryChecker                 // Shared inventory checker for reuse across operations
+	ipChanges        map[string]ipConfigChange         // ip_config changes found by FindDevicesToUpdate
+	radioIssues      map[string][]validation.LintIssue // radio_config regulatory problems found by FindDevicesToUpdate
 }
 
 // NewBaseDeviceUpdater creates a new base device updater
@@ -124,6 +127,20 @@ func siteNameFromConfig(sc SiteConfig) string {
 	return ""
 }
 
+// interruptedUpdate reports a device update loop stopped by cancellation:
+// how many devices were pushed and failed, and which were never attempted.
+// Devices already pushed stay pushed, so rerunning apply finishes the rest.
+func interruptedUpdate(ctx context.Context, deviceType string, total int, succeeded, failed, pending []string) error {
+	return &vendors.InterruptedError{
+		Op:      fmt.Sprintf("apply (%s configs)", deviceType),
+		Done:    len(succeeded) + len(failed),
+		Total:   total,
+		Pending: pending,
+		Outcome: fmt.Sprintf("%d updated, %d failed; rerun apply to update the rest", len(succeeded), len(failed)),
+		Err:     ctx.Err(),
+	}
+}
+
 // GetAssignedDevices gets MAC addresses of devices assigned to a site from cache
 func (b *BaseDeviceUpdater) GetAssignedDevices(_ context.Context, _ vendors.Client, siteID string) ([]string, error) {
 	accessor := vendors.GetGlobalCacheAccessor()
@@ -145,11 +162,14 @@ func (b *BaseDeviceUpdater) GetAssignedDevices(_ context.Context, _ vendors.Clie
 
 // UnassignDevices removes devices from a site
 func (b *BaseDeviceUpdater) UnassignDevices(ctx context.Context, client vendors.Client, _ *config.Config, macs []string) error {
+	// Even a failed call may have moved some devices.
+	defer invalidateInventorySnapshot()
 	return client.Inventory().UnassignFromSite(ctx, macs)
 }
 
 // AssignDevices assigns devices to a site
 func (b *BaseDeviceUpdater) AssignDevices(ctx context.Context, client vendors.Client, _ *config.Config, macs []string, siteID string) error {
+	defer invalidateInventorySnapshot()
 	return client.Inventory().AssignToSite(ctx, siteID, macs)
 }
 
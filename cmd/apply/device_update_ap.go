@@ -4,12 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/ravinald/jsondiff/pkg/jsondiff"
 	"github.com/spf13/viper"
 
 	"github.com/ravinald/wifimgr/api"
+	"github.com/ravinald/wifimgr/internal/canonical"
 	"github.com/ravinald/wifimgr/internal/config"
 	"github.com/ravinald/wifimgr/internal/logging"
 	"github.com/ravinald/wifimgr/internal/macaddr"
@@ -17,12 +19,14 @@ import (
 	"github.com/ravinald/wifimgr/internal/vendors"
 	"github.com/ravinald/wifimgr/internal/vendors/meraki"
 	"github.com/ravinald/wifimgr/internal/vendors/mist"
+	"github.com/ravinald/wifimgr/internal/warnings"
 )
 
 // APUpdater implements DeviceUpdater for Access Points
 type APUpdater struct {
 	*BaseDeviceUpdater
-	batchLoader *DeviceBatchLoader // Reusable batch loader for device lookups
+	batchLoader *DeviceBatchLoader            // Reusable batch loader for device lookups
+	siteMaps    map[string][]*vendors.SiteMap // Floor plans by site ID, for map_name
 }
 
 func init() {
@@ -46,7 +50,7 @@ func applicableDesiredConfig(updater DeviceUpdater, siteConfig SiteConfig, mac,
 	if !ok {
 		return nil, nil, false
 	}
-	if expanded, err := expandDeviceConfigWithTemplates(cfg, siteConfig); err == nil {
+	if expanded, err := expandDeviceConfigWithTemplates(cfg, siteConfig, mac); err == nil {
 		cfg = expanded
 	}
 	if vendorName == "meraki" && deviceType == "ap" {
@@ -56,19 +60,21 @@ func applicableDesiredConfig(updater DeviceUpdater, siteConfig SiteConfig, mac,
 	return cfg, nil, true
 }
 
-// reportSkippedFields prints, by default (not gated behind debug), the configured
-// fields that did not apply to a device's API/hardware and were skipped. It is
-// informational — apply continues and exits clean — so an operator sees a site-wide
-// setting land only where it can.
-func reportSkippedFields(deviceType string, skippedByMAC map[string][]string, nameFor func(string) string) {
-	if len(skippedByMAC) == 0 {
-		return
-	}
-	fmt.Printf("\n%s Some configured %s fields do not apply to the target API/device and were skipped:\n", symbols.WarningPrefix(), deviceType)
-	for mac, fields := range skippedByMAC {
-		fmt.Printf("  - %s: %s\n", nameFor(mac), strings.Join(fields, ", "))
+// reportSkippedFields warns, by default (not gated behind debug), about the
+// configured fields that did not apply to a device's API/hardware and were
+// skipped. It is informational — apply continues and exits clean — so an
+// operator sees a site-wide setting land only where it can.
+func reportSkippedFields(ctx context.Context, deviceType string, skippedByMAC map[string][]string, nameFor func(string) string) {
+	warn := warnings.FromContext(ctx)
+	macs := make([]string, 0, len(skippedByMAC))
+	for mac := range skippedByMAC {
+		macs = append(macs, mac)
+	}
+	sort.Strings(macs)
+	for _, mac := range macs {
+		warn.Add("Skipped "+deviceType+" fields", nameFor(mac),
+			"do not apply to the target API/device: %s", strings.Join(skippedByMAC[mac], ", "))
 	}
-	fmt.Println()
 }
 
 // GetConfiguredDevices extracts AP MAC addresses from site configuration
@@ -136,11 +142,15 @@ func (a *APUpdater) FindDevicesToUpdate(ctx context.Context, client vendors.Clie
 		return nil, fmt.Errorf("failed to create batch loader: %w", err)
 	}
 	a.batchLoader = batchLoader // Store for reuse - eliminates duplicate API call
+	a.ipChanges = nil
+	a.radioIssues = nil
 	logging.Debugf("Batch loader created with %d devices for comparison", batchLoader.GetDeviceCount())
 
 	// Get managed keys for AP devices from the API-specific config
 	managedKeys := getManagedKeysForDevice(apiLabel, "ap")
+	diffRules := getDiffRulesForDevice(apiLabel, "ap")
 	vendorName := config.GetVendorFromAPILabel(apiLabel)
+	countryCode := siteCountryCode(siteConfig)
 
 	apsToUpdate := make([]string, 0)
 	skippedByMAC := make(map[string][]string)
@@ -155,6 +165,12 @@ func (a *APUpdater) FindDevicesToUpdate(ctx context.Context, client vendors.Clie
 		if len(skipped) > 0 {
 			skippedByMAC[mac] = skipped
 		}
+		if resolved, err := a.resolvePlacement(ctx, client, siteID, desiredConfig); err != nil {
+			logging.Warnf("AP %s: %v", mac, err)
+		} else {
+			desiredConfig = resolved
+		}
+		a.noteRadioRegulatory(mac, desiredConfig, managedKeys, countryCode)
 
 		// Get current device state
 		device, err := batchLoader.GetDeviceByMAC(mac)
@@ -166,8 +182,11 @@ func (a *APUpdater) FindDevicesToUpdate(ctx context.Context, client vendors.Clie
 
 		// Get current config
 		currentConfig := device.ToConfigMap()
+		settlePlacement(currentConfig, desiredConfig)
+		a.noteIPConfigChange(mac, currentConfig, desiredConfig, managedKeys)
 
-		// Compare configurations using managed keys
+		// Canonicalize both sides (ignore rules), then compare using managed keys
+		currentConfig, desiredConfig = canonical.Apply(currentConfig, desiredConfig, diffRules)
 		needsUpdate := compareDeviceConfigsWithManagedKeys(currentConfig, desiredConfig, managedKeys)
 		logging.Debugf("AP %s - Current config: %+v", mac, currentConfig)
 		logging.Debugf("AP %s - Desired config: %+v", mac, desiredConfig)
@@ -197,7 +216,7 @@ func (a *APUpdater) FindDevicesToUpdate(ctx context.Context, client vendors.Clie
 		}
 	}
 
-	reportSkippedFields("ap", skippedByMAC, func(mac string) string {
+	reportSkippedFields(ctx, "ap", skippedByMAC, func(mac string) string {
 		if d, err := batchLoader.GetDeviceByMAC(mac); err == nil && d.Name != nil && *d.Name != "" {
 			return fmt.Sprintf("%s (%s)", *d.Name, mac)
 		}
@@ -271,7 +290,10 @@ func (a *APUpdater) UpdateDeviceConfigurations(ctx context.Context, client vendo
 
 	vendorNameForFilter := config.GetVendorFromAPILabel(apiLabel)
 
-	for _, mac := range macs {
+	for i, mac := range macs {
+		if ctx.Err() != nil {
+			return succeeded, interruptedUpdate(ctx, "ap", len(macs), succeeded, failedDevices, macs[i:])
+		}
 		// Intent expanded and filtered to the fields this API/device can apply, so the
 		// push carries only applicable fields (matching the diff and verify comparison).
 		apConfig, _, found := applicableDesiredConfig(a, siteConfig, mac, vendorNameForFilter, "ap")
@@ -279,6 +301,12 @@ func (a *APUpdater) UpdateDeviceConfigurations(ctx context.Context, client vendo
 			logging.Warnf("AP %s is in the list to update but not found in site configuration", mac)
 			continue
 		}
+		apConfig, err = a.resolvePlacement(ctx, client, siteID, apConfig)
+		if err != nil {
+			logging.Errorf("AP %s: %v", mac, err)
+			failedDevices = append(failedDevices, mac)
+			continue
+		}
 
 		device, err := batchLoader.GetDeviceByMAC(mac)
 		if err != nil {
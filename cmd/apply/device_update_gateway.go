@@ -8,6 +8,7 @@ import (
 	"github.com/ravinald/jsondiff/pkg/jsondiff"
 	"github.com/spf13/viper"
 
+	"github.com/ravinald/wifimgr/internal/canonical"
 	"github.com/ravinald/wifimgr/internal/config"
 	"github.com/ravinald/wifimgr/internal/logging"
 	"github.com/ravinald/wifimgr/internal/macaddr"
@@ -96,10 +97,12 @@ func (g *GatewayUpdater) FindDevicesToUpdate(ctx context.Context, client vendors
 		return nil, fmt.Errorf("failed to create batch loader: %w", err)
 	}
 	g.batchLoader = batchLoader // Store for reuse - eliminates duplicate API call
+	g.ipChanges = nil
 	logging.Debugf("Batch loader created with %d devices for comparison", batchLoader.GetDeviceCount())
 
 	// Get managed keys for gateway devices from the API-specific config
 	managedKeys := getManagedKeysForDevice(apiLabel, "gateway")
+	diffRules := getDiffRulesForDevice(apiLabel, "gateway")
 
 	gatewaysToUpdate := make([]string, 0)
 
@@ -112,7 +115,7 @@ func (g *GatewayUpdater) FindDevicesToUpdate(ctx context.Context, client vendors
 		}
 
 		// Expand template references (device_template)
-		expandedConfig, err := expandDeviceConfigWithTemplates(desiredConfig, siteConfig)
+		expandedConfig, err := expandDeviceConfigWithTemplates(desiredConfig, siteConfig, mac)
 		if err != nil {
 			logging.Warnf("Error expanding templates for Gateway %s: %v - using unexpanded config", mac, err)
 		} else {
@@ -129,8 +132,11 @@ func (g *GatewayUpdater) FindDevicesToUpdate(ctx context.Context, client vendors
 
 		// Get current config
 		currentConfig := device.ToConfigMap()
+		g.noteIPConfigChange(mac, currentConfig, desiredConfig, managedKeys)
+		g.noteGatewayIPv6(mac, desiredConfig, managedKeys)
 
-		// Compare configurations using managed keys
+		// Canonicalize both sides (ignore rules), then compare using managed keys
+		currentConfig, desiredConfig = canonical.Apply(currentConfig, desiredConfig, diffRules)
 		needsUpdate := compareDeviceConfigsWithManagedKeys(currentConfig, desiredConfig, managedKeys)
 		logging.Debugf("Gateway %s - Current config: %+v", mac, currentConfig)
 		logging.Debugf("Gateway %s - Desired config: %+v", mac, desiredConfig)
@@ -221,7 +227,10 @@ func (g *GatewayUpdater) UpdateDeviceConfigurations(ctx context.Context, client
 		profileNameToID = make(map[string]string)
 	}
 
-	for _, mac := range macs {
+	for i, mac := range macs {
+		if ctx.Err() != nil {
+			return succeeded, interruptedUpdate(ctx, "gateway", len(macs), succeeded, failedDevices, macs[i:])
+		}
 		gatewayConfig, found := g.GetDeviceConfigFromSite(siteConfig, mac)
 		if !found {
 			logging.Warnf("Gateway %s is in the list to update but not found in site configuration", mac)
@@ -229,7 +238,7 @@ func (g *GatewayUpdater) UpdateDeviceConfigurations(ctx context.Context, client
 		}
 
 		// Expand template references (device_template)
-		expandedConfig, err := expandDeviceConfigWithTemplates(gatewayConfig, siteConfig)
+		expandedConfig, err := expandDeviceConfigWithTemplates(gatewayConfig, siteConfig, mac)
 		if err != nil {
 			logging.Warnf("Error expanding templates for Gateway %s: %v - using unexpanded config", mac, err)
 		} else {
@@ -8,6 +8,7 @@ import (
 	"github.com/ravinald/jsondiff/pkg/jsondiff"
 	"github.com/spf13/viper"
 
+	"github.com/ravinald/wifimgr/internal/canonical"
 	"github.com/ravinald/wifimgr/internal/config"
 	"github.com/ravinald/wifimgr/internal/logging"
 	"github.com/ravinald/wifimgr/internal/macaddr"
@@ -96,10 +97,12 @@ func (s *SwitchUpdater) FindDevicesToUpdate(ctx context.Context, client vendors.
 		return nil, fmt.Errorf("failed to create batch loader: %w", err)
 	}
 	s.batchLoader = batchLoader // Store for reuse - eliminates duplicate API call
+	s.ipChanges = nil
 	logging.Debugf("Batch loader created with %d devices for comparison", batchLoader.GetDeviceCount())
 
 	// Get managed keys for switch devices from the API-specific config
 	managedKeys := getManagedKeysForDevice(apiLabel, "switch")
+	diffRules := getDiffRulesForDevice(apiLabel, "switch")
 
 	switchesToUpdate := make([]string, 0)
 
@@ -112,7 +115,7 @@ func (s *SwitchUpdater) FindDevicesToUpdate(ctx context.Context, client vendors.
 		}
 
 		// Expand template references (device_template)
-		expandedConfig, err := expandDeviceConfigWithTemplates(desiredConfig, siteConfig)
+		expandedConfig, err := expandDeviceConfigWithTemplates(desiredConfig, siteConfig, mac)
 		if err != nil {
 			logging.Warnf("Error expanding templates for Switch %s: %v - using unexpanded config", mac, err)
 		} else {
@@ -127,10 +130,18 @@ func (s *SwitchUpdater) FindDevicesToUpdate(ctx context.Context, client vendors.
 			continue
 		}
 
+		// Virtual chassis membership is intent-only: warn on drift, never push
+		if device.ID != nil {
+			checkVirtualChassis(ctx, legacyClient(client), siteID, *device.ID, mac, desiredConfig)
+		}
+		desiredConfig = withoutIntentOnlySwitchKeys(desiredConfig)
+
 		// Get current config
 		currentConfig := device.ToConfigMap()
+		s.noteIPConfigChange(mac, currentConfig, desiredConfig, managedKeys)
 
-		// Compare configurations using managed keys
+		// Canonicalize both sides (ignore rules), then compare using managed keys
+		currentConfig, desiredConfig = canonical.Apply(currentConfig, desiredConfig, diffRules)
 		needsUpdate := compareDeviceConfigsWithManagedKeys(currentConfig, desiredConfig, managedKeys)
 		logging.Debugf("Switch %s - Current config: %+v", mac, currentConfig)
 		logging.Debugf("Switch %s - Desired config: %+v", mac, desiredConfig)
@@ -221,7 +232,10 @@ func (s *SwitchUpdater) UpdateDeviceConfigurations(ctx context.Context, client v
 		profileNameToID = make(map[string]string)
 	}
 
-	for _, mac := range macs {
+	for i, mac := range macs {
+		if ctx.Err() != nil {
+			return succeeded, interruptedUpdate(ctx, "switch", len(macs), succeeded, failedDevices, macs[i:])
+		}
 		switchConfig, found := s.GetDeviceConfigFromSite(siteConfig, mac)
 		if !found {
 			logging.Warnf("Switch %s is in the list to update but not found in site configuration", mac)
@@ -229,7 +243,7 @@ func (s *SwitchUpdater) UpdateDeviceConfigurations(ctx context.Context, client v
 		}
 
 		// Expand template references (device_template)
-		expandedConfig, err := expandDeviceConfigWithTemplates(switchConfig, siteConfig)
+		expandedConfig, err := expandDeviceConfigWithTemplates(switchConfig, siteConfig, mac)
 		if err != nil {
 			logging.Warnf("Error expanding templates for Switch %s: %v - using unexpanded config", mac, err)
 		} else {
@@ -258,7 +272,7 @@ func (s *SwitchUpdater) UpdateDeviceConfigurations(ctx context.Context, client v
 		updatedDevice := *device
 
 		// Handle _name suffix translations using cached profile map (O(1) lookup)
-		translatedConfig := translateNameFieldsWithCache(switchConfig, profileNameToID)
+		translatedConfig := translateNameFieldsWithCache(withoutIntentOnlySwitchKeys(switchConfig), profileNameToID)
 
 		// Filter config to only include managed keys if configured
 		managedKeys := getManagedKeysForDevice(apiLabel, "switch")
@@ -344,6 +358,21 @@ func (s *SwitchUpdater) GetDeviceConfigFromSite(siteConfig SiteConfig, mac strin
 	return nil, false
 }
 
+// withoutIntentOnlySwitchKeys returns a copy of a switch config without the
+// keys that are checked against the API but never pushed.
+func withoutIntentOnlySwitchKeys(switchConfig map[string]any) map[string]any {
+	if _, ok := switchConfig[virtualChassisKey]; !ok {
+		return switchConfig
+	}
+	out := make(map[string]any, len(switchConfig))
+	for k, v := range switchConfig {
+		if k != virtualChassisKey {
+			out[k] = v
+		}
+	}
+	return out
+}
+
 // showSwitchConfigDiffWithManagedKeys displays a colored JSON diff with managed keys highlighted
 func showSwitchConfigDiffWithManagedKeys(mac string, currentConfig, desiredConfig map[string]any, managedKeys []string, siteName string) {
 	// Filter out status fields that shouldn't be compared
@@ -0,0 +1,396 @@
+package apply
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ravinald/wifimgr/api"
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/i18n"
+	"github.com/ravinald/wifimgr/internal/logging"
+	"github.com/ravinald/wifimgr/internal/macaddr"
+	"github.com/ravinald/wifimgr/internal/symbols"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+// FabricConfig is the "fabric" section of a site config: Mist campus fabric
+// (EVPN) topologies keyed by topology name. Each topology is the Mist
+// evpn_topology object (switches, evpn_options, pod_names, ...) plus an
+// optional "scope" of "site" (default) or "org" for fabrics spanning sites.
+type FabricConfig struct {
+	Topologies map[string]map[string]any `json:"topologies,omitempty"`
+}
+
+// Fabric plan actions.
+const (
+	fabricCreate    = "create"
+	fabricUpdate    = "update"
+	fabricUnchanged = "unchanged"
+)
+
+// fabricChange is the planned reconcile of one topology.
+type fabricChange struct {
+	Name    string
+	Scope   string
+	Action  string
+	ID      string         // existing topology ID, for updates
+	Body    map[string]any // request body: the declared keys only
+	Details []string       // human-readable differences
+}
+
+// desiredTopology turns one declared topology into a request body: scope is
+// stripped, the name is set, and switch MACs are normalized. Returns the scope.
+func desiredTopology(name string, decl map[string]any) (map[string]any, string, error) {
+	scope := api.EVPNScopeSite
+	body := make(map[string]any, len(decl)+1)
+	for k, v := range decl {
+		if k == "scope" {
+			s, _ := v.(string)
+			switch s {
+			case api.EVPNScopeSite, api.EVPNScopeOrg:
+				scope = s
+			default:
+				return nil, "", fmt.Errorf("topology %s: invalid scope %q (use %q or %q)", name, v, api.EVPNScopeSite, api.EVPNScopeOrg)
+			}
+			continue
+		}
+		body[k] = v
+	}
+	body["name"] = name
+
+	if raw, ok := body["switches"]; ok {
+		list, ok := raw.([]any)
+		if !ok {
+			return nil, "", fmt.Errorf("topology %s: switches must be a list of {mac, role}", name)
+		}
+		switches := make([]any, 0, len(list))
+		for i, item := range list {
+			sw, ok := item.(map[string]any)
+			if !ok {
+				return nil, "", fmt.Errorf("topology %s: switches[%d] must be an object", name, i)
+			}
+			mac, _ := sw["mac"].(string)
+			normalized := macaddr.NormalizeOrEmpty(mac)
+			if normalized == "" {
+				return nil, "", fmt.Errorf("topology %s: switches[%d] has invalid mac %q", name, i, mac)
+			}
+			if role, _ := sw["role"].(string); role == "" {
+				return nil, "", fmt.Errorf("topology %s: switch %s has no role", name, mac)
+			}
+			out := make(map[string]any, len(sw))
+			for k, v := range sw {
+				out[k] = v
+			}
+			out["mac"] = normalized
+			switches = append(switches, out)
+		}
+		body["switches"] = switches
+	}
+	return body, scope, nil
+}
+
+// planFabric compares declared topologies with the existing ones (by scope,
+// then name). Only keys the intent declares are compared, so fields Mist
+// fills in (IDs, timestamps, derived pod data) never show as drift.
+// Topologies that exist in the API but are not declared are left alone.
+func planFabric(declared map[string]map[string]any, existing map[string][]map[string]any) ([]fabricChange, error) {
+	names := make([]string, 0, len(declared))
+	for n := range declared {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	var plan []fabricChange
+	for _, name := range names {
+		body, scope, err := desiredTopology(name, declared[name])
+		if err != nil {
+			return nil, err
+		}
+		change := fabricChange{Name: name, Scope: scope, Body: body}
+
+		var current map[string]any
+		for _, t := range existing[scope] {
+			if n, _ := t["name"].(string); n == name {
+				current = t
+				break
+			}
+		}
+		if current == nil {
+			change.Action = fabricCreate
+			if sw, ok := body["switches"].([]any); ok {
+				for _, item := range sw {
+					m := item.(map[string]any)
+					change.Details = append(change.Details, fmt.Sprintf("+ switch %s (%v)", m["mac"], m["role"]))
+				}
+			}
+			plan = append(plan, change)
+			continue
+		}
+
+		change.ID, _ = current["id"].(string)
+		change.Details = diffTopology(body, current)
+		change.Action = fabricUpdate
+		if len(change.Details) == 0 {
+			change.Action = fabricUnchanged
+		}
+		plan = append(plan, change)
+	}
+	return plan, nil
+}
+
+// diffTopology lists the differences between a desired body and the existing
+// topology, for the keys the body declares. Switches are compared by MAC so a
+// role change reads as one line rather than a list rewrite.
+func diffTopology(desired, current map[string]any) []string {
+	keys := make([]string, 0, len(desired))
+	for k := range desired {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var details []string
+	for _, k := range keys {
+		if k == "switches" {
+			details = append(details, diffFabricSwitches(desired[k], current[k])...)
+			continue
+		}
+		if !jsonEqual(desired[k], current[k]) {
+			details = append(details, fmt.Sprintf("~ %s: %s -> %s", k, compactJSON(current[k]), compactJSON(desired[k])))
+		}
+	}
+	return details
+}
+
+func diffFabricSwitches(desired, current any) []string {
+	index := func(v any) map[string]map[string]any {
+		out := make(map[string]map[string]any)
+		list, _ := v.([]any)
+		for _, item := range list {
+			if sw, ok := item.(map[string]any); ok {
+				mac, _ := sw["mac"].(string)
+				if n := macaddr.NormalizeOrEmpty(mac); n != "" {
+					out[n] = sw
+				}
+			}
+		}
+		return out
+	}
+	want, have := index(desired), index(current)
+
+	macs := make([]string, 0, len(want)+len(have))
+	for m := range want {
+		macs = append(macs, m)
+	}
+	for m := range have {
+		if _, ok := want[m]; !ok {
+			macs = append(macs, m)
+		}
+	}
+	sort.Strings(macs)
+
+	var details []string
+	for _, mac := range macs {
+		w, inWant := want[mac]
+		h, inHave := have[mac]
+		switch {
+		case !inHave:
+			details = append(details, fmt.Sprintf("+ switch %s (%v)", mac, w["role"]))
+		case !inWant:
+			details = append(details, fmt.Sprintf("- switch %s (%v)", mac, h["role"]))
+		default:
+			fields := make([]string, 0, len(w))
+			for k := range w {
+				if k != "mac" {
+					fields = append(fields, k)
+				}
+			}
+			sort.Strings(fields)
+			for _, k := range fields {
+				if !jsonEqual(w[k], h[k]) {
+					details = append(details, fmt.Sprintf("~ switch %s %s: %s -> %s", mac, k, compactJSON(h[k]), compactJSON(w[k])))
+				}
+			}
+		}
+	}
+	return details
+}
+
+// jsonEqual compares two decoded JSON values, treating numeric types alike.
+func jsonEqual(a, b any) bool {
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	if errA != nil || errB != nil {
+		return reflect.DeepEqual(a, b)
+	}
+	var na, nb any
+	_ = json.Unmarshal(ja, &na)
+	_ = json.Unmarshal(jb, &nb)
+	return reflect.DeepEqual(na, nb)
+}
+
+func compactJSON(v any) string {
+	if v == nil {
+		return "(unset)"
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+// undeclaredFabricSwitches returns site-scoped topology members that the
+// site config does not declare under devices.switch.
+func undeclaredFabricSwitches(plan []fabricChange, siteSwitches map[string]map[string]any) []string {
+	declared := make(map[string]bool, len(siteSwitches))
+	for mac := range siteSwitches {
+		declared[macaddr.NormalizeOrEmpty(mac)] = true
+	}
+	var out []string
+	for _, c := range plan {
+		if c.Scope != api.EVPNScopeSite {
+			continue
+		}
+		list, _ := c.Body["switches"].([]any)
+		for _, item := range list {
+			m := item.(map[string]any)
+			if mac, _ := m["mac"].(string); !declared[mac] {
+				out = append(out, fmt.Sprintf("%s (topology %s)", mac, c.Name))
+			}
+		}
+	}
+	return out
+}
+
+// applyFabric reconciles a site's campus fabric (EVPN topologies) with the
+// Mist evpn_topologies endpoints. The plan is always printed in full. Changing
+// a fabric re-provisions the overlay on every member switch and can interrupt
+// traffic, so pushing needs force or the site name typed at the prompt;
+// --yes alone is not enough.
+func applyFabric(ctx context.Context, client vendors.Client, cfg *config.Config, siteName string, force, diffMode bool) error {
+	lc := legacyClient(client)
+	if lc == nil {
+		return fmt.Errorf("campus fabric is only supported for Mist")
+	}
+
+	if cfg.Files.ConfigDir != "" {
+		if err := os.Setenv("CONFIG_DIR", cfg.Files.ConfigDir); err != nil {
+			return fmt.Errorf("error setting CONFIG_DIR environment variable: %v", err)
+		}
+	}
+	siteConfigs, err := getSiteConfigsFromFiles(siteConfigFiles(cfg))
+	if err != nil {
+		return fmt.Errorf("error reading site configurations: %v", err)
+	}
+	siteConfig, found := siteConfigs[siteName]
+	if !found {
+		return fmt.Errorf("site %s not found in configuration files", siteName)
+	}
+	if siteConfig.Fabric == nil || len(siteConfig.Fabric.Topologies) == 0 {
+		fmt.Printf("Site %s declares no fabric topologies\n", siteName)
+		return nil
+	}
+
+	siteID, err := getSiteIDByName(client, siteName)
+	if err != nil {
+		return fmt.Errorf("error getting site ID for %s: %v", siteName, err)
+	}
+	owners := map[string]string{api.EVPNScopeSite: siteID, api.EVPNScopeOrg: client.OrgID()}
+
+	existing := make(map[string][]map[string]any)
+	for _, decl := range siteConfig.Fabric.Topologies {
+		scope := api.EVPNScopeSite
+		if s, ok := decl["scope"].(string); ok && s != "" {
+			scope = s
+		}
+		if _, done := existing[scope]; done {
+			continue
+		}
+		owner, ok := owners[scope]
+		if !ok {
+			continue // rejected with a clear error by planFabric
+		}
+		topos, err := lc.GetEVPNTopologies(ctx, scope, owner)
+		if err != nil {
+			return err
+		}
+		if topos == nil {
+			topos = []map[string]any{}
+		}
+		existing[scope] = topos
+	}
+
+	plan, err := planFabric(siteConfig.Fabric.Topologies, existing)
+	if err != nil {
+		return err
+	}
+
+	pending := 0
+	fmt.Printf("Campus fabric plan for site %s:\n", siteName)
+	for _, c := range plan {
+		marker := map[string]string{fabricCreate: "+", fabricUpdate: "~", fabricUnchanged: "="}[c.Action]
+		fmt.Printf("  %s %s (%s scope): %s\n", marker, c.Name, c.Scope, c.Action)
+		for _, d := range c.Details {
+			fmt.Printf("      %s\n", d)
+		}
+		if c.Action != fabricUnchanged {
+			pending++
+		}
+	}
+	for _, s := range undeclaredFabricSwitches(plan, siteConfig.Devices.Switches) {
+		fmt.Printf("%s Switch %s is not declared under devices.switch for this site\n", symbols.WarningPrefix(), s)
+	}
+
+	if pending == 0 {
+		fmt.Printf("%s Fabric matches intent\n", symbols.SuccessPrefix())
+		return nil
+	}
+	if diffMode {
+		return nil
+	}
+	if !force && !confirmFabricChange(siteName) {
+		return fmt.Errorf("fabric apply cancelled")
+	}
+
+	for _, c := range plan {
+		switch c.Action {
+		case fabricCreate:
+			logging.Infof("Creating EVPN topology %s (%s) for site %s", c.Name, c.Scope, siteName)
+			if _, err := lc.CreateEVPNTopology(ctx, c.Scope, owners[c.Scope], c.Body); err != nil {
+				return fmt.Errorf("topology %s: %w", c.Name, err)
+			}
+		case fabricUpdate:
+			logging.Infof("Updating EVPN topology %s (%s) for site %s", c.Name, c.Scope, siteName)
+			if _, err := lc.UpdateEVPNTopology(ctx, c.Scope, owners[c.Scope], c.ID, c.Body); err != nil {
+				return fmt.Errorf("topology %s: %w", c.Name, err)
+			}
+		default:
+			continue
+		}
+		fmt.Printf("%s Topology %s: %sd\n", symbols.SuccessPrefix(), c.Name, c.Action)
+	}
+	return nil
+}
+
+// confirmFabricChange asks for the site name to be typed back. It fails
+// closed under --no-input.
+func confirmFabricChange(siteName string) bool {
+	if cmdutils.NoInput() {
+		fmt.Println(i18n.T("fabric.no_input"))
+		return false
+	}
+	fmt.Printf("%s %s\n", symbols.WarningPrefix(), i18n.T("fabric.warning"))
+	fmt.Print(i18n.T("fabric.type_site", siteName))
+	input, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false
+	}
+	return strings.TrimSpace(input) == siteName
+}
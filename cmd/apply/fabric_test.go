@@ -0,0 +1,105 @@
+package apply
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func fabricDecl(t *testing.T, s string) map[string]map[string]any {
+	t.Helper()
+	var out map[string]map[string]any
+	if err := json.Unmarshal([]byte(s), &out); err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func TestPlanFabric_CreateUpdateUnchanged(t *testing.T) {
+	declared := fabricDecl(t, `{
+		"core": {"switches": [{"mac": "AA:BB:CC:00:00:01", "role": "core"}, {"mac": "aabbcc000002", "role": "distribution"}]},
+		"lab":  {"switches": [{"mac": "aabbcc000010", "role": "access"}]},
+		"campus": {"scope": "org", "evpn_options": {"overlay": {"as": 65000}}}
+	}`)
+	existing := map[string][]map[string]any{
+		"site": {
+			{"id": "t-core", "name": "core", "switches": []any{
+				map[string]any{"mac": "aabbcc000001", "role": "core"},
+				map[string]any{"mac": "aabbcc000002", "role": "access"},
+				map[string]any{"mac": "aabbcc000003", "role": "access"},
+			}},
+			{"id": "t-other", "name": "unmanaged"},
+		},
+		"org": {
+			{"id": "t-campus", "name": "campus", "evpn_options": map[string]any{"overlay": map[string]any{"as": float64(65000)}}, "created_time": 1},
+		},
+	}
+
+	plan, err := planFabric(declared, existing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan) != 3 {
+		t.Fatalf("want 3 changes, got %d", len(plan))
+	}
+	byName := map[string]fabricChange{}
+	for _, c := range plan {
+		byName[c.Name] = c
+	}
+
+	if c := byName["campus"]; c.Action != fabricUnchanged || c.Scope != "org" {
+		t.Errorf("campus: got %s/%s, want unchanged/org (%v)", c.Action, c.Scope, c.Details)
+	}
+	if _, ok := byName["campus"].Body["scope"]; ok {
+		t.Error("scope must not be sent in the request body")
+	}
+	if c := byName["lab"]; c.Action != fabricCreate {
+		t.Errorf("lab: got %s, want create", c.Action)
+	}
+
+	core := byName["core"]
+	if core.Action != fabricUpdate || core.ID != "t-core" {
+		t.Fatalf("core: got %s id=%s, want update t-core", core.Action, core.ID)
+	}
+	got := strings.Join(core.Details, "\n")
+	for _, want := range []string{
+		`~ switch aabbcc000002 role: "access" -> "distribution"`,
+		"- switch aabbcc000003 (access)",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("core details missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "aabbcc000001") {
+		t.Errorf("unchanged switch should not be listed:\n%s", got)
+	}
+}
+
+func TestPlanFabric_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"bad scope": `{"x": {"scope": "global"}}`,
+		"bad mac":   `{"x": {"switches": [{"mac": "nope", "role": "core"}]}}`,
+		"no role":   `{"x": {"switches": [{"mac": "aabbcc000001"}]}}`,
+	}
+	for name, decl := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := planFabric(fabricDecl(t, decl), nil); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestUndeclaredFabricSwitches(t *testing.T) {
+	plan, err := planFabric(fabricDecl(t, `{
+		"core": {"switches": [{"mac": "aabbcc000001", "role": "core"}, {"mac": "aabbcc000002", "role": "core"}]},
+		"wide": {"scope": "org", "switches": [{"mac": "aabbcc000009", "role": "core"}]}
+	}`), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := undeclaredFabricSwitches(plan, map[string]map[string]any{"AA:BB:CC:00:00:01": {}})
+	if len(got) != 1 || !strings.HasPrefix(got[0], "aabbcc000002") {
+		t.Errorf("got %v, want only aabbcc000002", got)
+	}
+}
@@ -39,18 +39,10 @@ func NewInventoryChecker(_ context.Context, client vendors.Client, cfg *config.C
 		client:         client,
 	}
 
-	// Get devices of this type from cache
-	var inventoryItems []*vendors.InventoryItem
-	switch deviceType {
-	case "ap":
-		inventoryItems = accessor.GetAllAPs()
-	case "switch":
-		inventoryItems = accessor.GetAllSwitches()
-	case "gateway":
-		inventoryItems = accessor.GetAllGateways()
-	default:
-		inventoryItems = accessor.GetAllDevices()
-	}
+	// Inventory and the armed allowlist come from the process-wide snapshot,
+	// shared with every other site and device type in this run.
+	snapshot := orgInventorySnapshot(accessor, config.InventoryPath(cfg))
+	inventoryItems := snapshot.devices(deviceType)
 
 	// Store inventory from cache
 	for _, item := range inventoryItems {
@@ -75,9 +67,7 @@ func NewInventoryChecker(_ context.Context, client vendors.Client, cfg *config.C
 	// caller must abort the write rather than proceed against an ambiguous
 	// allowlist. A missing/unreadable file is non-fatal here — localInventory
 	// stays empty, so writes fail closed (IsInInventory returns false).
-	inventoryPath := config.InventoryPath(cfg)
-	logging.Infof("Loading armed inventory for site %s from path: %s", siteName, inventoryPath)
-	invFile, err := config.LoadInventoryFile(inventoryPath)
+	invFile, err := snapshot.armed, snapshot.armedErr
 	if err != nil {
 		if errors.Is(err, config.ErrLegacyInventorySchema) {
 			return nil, err
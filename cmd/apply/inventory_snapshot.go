@@ -0,0 +1,77 @@
+package apply
+
+import (
+	"sync"
+
+	"github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/logging"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+// inventorySnapshot is the org inventory and the armed allowlist as one apply
+// process sees them. NewInventoryChecker is called once per (site, device
+// type, API) pass, and every pass used to walk the whole cached inventory and
+// re-read inventory.json; a multi-site or multi-type run now builds both once
+// and shares them. Assignment operations change which site a device belongs
+// to, so AssignDevices and UnassignDevices drop the snapshot and the next
+// checker rebuilds it.
+type inventorySnapshot struct {
+	accessor *vendors.CacheAccessor // the accessor the snapshot was built from
+	path     string                 // inventory.json path the allowlist came from
+	byType   map[string][]*vendors.InventoryItem
+	all      []*vendors.InventoryItem
+	armed    *config.InventoryFile
+	armedErr error
+}
+
+var (
+	sharedInventoryMu sync.Mutex
+	sharedInventory   *inventorySnapshot
+)
+
+// orgInventorySnapshot returns the shared snapshot, building it when there is
+// none or it was built from a different cache accessor or inventory file.
+func orgInventorySnapshot(accessor *vendors.CacheAccessor, inventoryPath string) *inventorySnapshot {
+	sharedInventoryMu.Lock()
+	defer sharedInventoryMu.Unlock()
+
+	if s := sharedInventory; s != nil && s.accessor == accessor && s.path == inventoryPath {
+		logging.Debugf("Reusing org inventory snapshot (%d devices)", len(s.all))
+		return s
+	}
+
+	s := &inventorySnapshot{
+		accessor: accessor,
+		path:     inventoryPath,
+		byType:   make(map[string][]*vendors.InventoryItem),
+		all:      accessor.GetAllDevices(),
+	}
+	for _, item := range s.all {
+		s.byType[item.Type] = append(s.byType[item.Type], item)
+	}
+	logging.Infof("Loading armed inventory from path: %s", inventoryPath)
+	s.armed, s.armedErr = config.LoadInventory(inventoryPath)
+	logging.Debugf("Built org inventory snapshot: %d devices", len(s.all))
+
+	sharedInventory = s
+	return s
+}
+
+// devices returns the snapshot's inventory for a device type; an unknown
+// type gets every device, as the accessor's GetAllDevices did.
+func (s *inventorySnapshot) devices(deviceType string) []*vendors.InventoryItem {
+	switch deviceType {
+	case "ap", "switch", "gateway":
+		return s.byType[deviceType]
+	default:
+		return s.all
+	}
+}
+
+// invalidateInventorySnapshot drops the shared snapshot after devices were
+// assigned to or unassigned from a site.
+func invalidateInventorySnapshot() {
+	sharedInventoryMu.Lock()
+	defer sharedInventoryMu.Unlock()
+	sharedInventory = nil
+}
@@ -0,0 +1,93 @@
+package apply
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+func writeArmedInventory(t *testing.T, path, apMAC string) {
+	t.Helper()
+	body := `{"version": 1, "config": {"inventory": {"site": {
+	  "US-LAB-01": {"ap": ["` + apMAC + `"], "switch": ["aa0000000003"]},
+	  "US-LAB-02": {"ap": ["aa0000000002"]}
+	}}}}`
+	if err := os.WriteFile(path, []byte(body), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestInventorySnapshotShared(t *testing.T) {
+	registry := vendors.NewAPIClientRegistry()
+	registry.RegisterFactory("mock", func(c *vendors.APIConfig) (vendors.Client, error) {
+		return vendors.NewMockClientWithAllServices(c.Vendor, "org-123"), nil
+	})
+	registry.InitializeClients(map[string]*vendors.APIConfig{
+		"test-api": {Label: "test-api", Vendor: "mock", Credentials: map[string]string{"org_id": "org-123"}},
+	})
+	cm := vendors.NewCacheManager(t.TempDir(), registry)
+	if err := cm.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	cache := vendors.NewAPICache("test-api", "mock", "org-123")
+	cache.Inventory.AP["aa0000000001"] = &vendors.InventoryItem{MAC: "aa0000000001", Type: "ap", SiteID: "site-001"}
+	cache.Inventory.AP["aa0000000002"] = &vendors.InventoryItem{MAC: "aa0000000002", Type: "ap", SiteID: "site-002"}
+	cache.Inventory.Switch["aa0000000003"] = &vendors.InventoryItem{MAC: "aa0000000003", Type: "switch", SiteID: "site-001"}
+	if err := cm.SaveAPICache(cache); err != nil {
+		t.Fatal(err)
+	}
+
+	prev := vendors.GetGlobalCacheAccessor()
+	vendors.SetGlobalCacheAccessor(vendors.NewCacheAccessor(cm))
+	invalidateInventorySnapshot()
+	t.Cleanup(func() {
+		vendors.SetGlobalCacheAccessor(prev)
+		invalidateInventorySnapshot()
+	})
+
+	cfg := &config.Config{}
+	cfg.Files.Inventory = filepath.Join(t.TempDir(), "inventory.json")
+	writeArmedInventory(t, cfg.Files.Inventory, "aa0000000001")
+
+	ctx := context.Background()
+	ap1, err := NewInventoryChecker(ctx, nil, cfg, "ap", "US-LAB-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := sharedInventory
+	sw1, err := NewInventoryChecker(ctx, nil, cfg, "switch", "US-LAB-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ap2, err := NewInventoryChecker(ctx, nil, cfg, "ap", "US-LAB-02")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sharedInventory != first {
+		t.Error("checkers for other types and sites should share one snapshot")
+	}
+	if len(ap1.apiInventory) != 2 || len(sw1.apiInventory) != 1 {
+		t.Errorf("api inventory: ap %d, switch %d", len(ap1.apiInventory), len(sw1.apiInventory))
+	}
+	if !ap1.IsInInventory("aa0000000001") || ap1.IsInInventory("aa0000000002") || !ap2.IsInInventory("aa0000000002") {
+		t.Error("armed allowlist must stay scoped to each checker's site")
+	}
+
+	// Arming another AP is not seen until an assignment drops the snapshot.
+	writeArmedInventory(t, cfg.Files.Inventory, "aa0000000002")
+	if c, _ := NewInventoryChecker(ctx, nil, cfg, "ap", "US-LAB-01"); c.IsInLocalInventory("aa0000000002") {
+		t.Error("snapshot should be reused until invalidated")
+	}
+	updater := &BaseDeviceUpdater{deviceType: "ap"}
+	_ = updater.AssignDevices(ctx, vendors.NewMockClientWithAllServices("mock", "org-123"), cfg, []string{"aa0000000002"}, "site-001")
+	if sharedInventory != nil {
+		t.Fatal("AssignDevices should invalidate the snapshot")
+	}
+	if c, _ := NewInventoryChecker(ctx, nil, cfg, "ap", "US-LAB-01"); !c.IsInLocalInventory("aa0000000002") {
+		t.Error("rebuilt snapshot should reread the armed inventory")
+	}
+}
@@ -0,0 +1,222 @@
+package apply
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/i18n"
+	"github.com/ravinald/wifimgr/internal/symbols"
+	"github.com/ravinald/wifimgr/internal/validation"
+)
+
+// ipConfigChange is a pending management-IP change on one device.
+type ipConfigChange struct {
+	MAC    string
+	From   string
+	To     string
+	Issues []validation.LintIssue // validation errors in the desired ip_config
+}
+
+// ipConfigChangeReporter is implemented by updaters that record ip_config
+// changes while finding devices to update.
+type ipConfigChangeReporter interface {
+	IPConfigChanges() map[string]ipConfigChange
+}
+
+// noteIPConfigChange records a device whose pushed ip_config (after template
+// expansion and managed-key filtering) differs from the API, and validates
+// the desired block. Devices that do not declare ip_config are not recorded.
+func (b *BaseDeviceUpdater) noteIPConfigChange(mac string, current, desired map[string]any, managedKeys []string) {
+	want, ok := filterConfigByManagedKeys(desired, managedKeys)["ip_config"].(map[string]any)
+	if !ok {
+		return
+	}
+	have, _ := current["ip_config"].(map[string]any)
+	if ipConfigSubsetEqual(want, have) {
+		return
+	}
+	if b.ipChanges == nil {
+		b.ipChanges = make(map[string]ipConfigChange)
+	}
+	b.ipChanges[mac] = ipConfigChange{
+		MAC:    mac,
+		From:   describeIPConfig(have),
+		To:     describeIPConfig(want),
+		Issues: validation.ValidateIPConfig(want),
+	}
+}
+
+// noteGatewayIPv6 validates the IPv6 settings a gateway's desired config
+// pushes, on its ports and networks. Gateway addressing lives outside a
+// top-level ip_config, so a gateway with errors is recorded here as an
+// invalid change without a from/to, and the guard drops it the same way.
+func (b *BaseDeviceUpdater) noteGatewayIPv6(mac string, desired map[string]any, managedKeys []string) {
+	issues := validation.ValidateGatewayIPv6(filterConfigByManagedKeys(desired, managedKeys))
+	if len(issues) == 0 {
+		return
+	}
+	if b.ipChanges == nil {
+		b.ipChanges = make(map[string]ipConfigChange)
+	}
+	c, ok := b.ipChanges[mac]
+	if !ok {
+		c = ipConfigChange{MAC: mac}
+	}
+	c.Issues = append(c.Issues, issues...)
+	b.ipChanges[mac] = c
+}
+
+// IPConfigChanges returns the ip_config changes recorded by the last
+// FindDevicesToUpdate, keyed by MAC.
+func (b *BaseDeviceUpdater) IPConfigChanges() map[string]ipConfigChange {
+	return b.ipChanges
+}
+
+// ipConfigSubsetEqual reports whether every key declared in want matches have.
+func ipConfigSubsetEqual(want, have map[string]any) bool {
+	for k, v := range want {
+		if !jsonEqual(v, have[k]) {
+			return false
+		}
+	}
+	return true
+}
+
+// describeIPConfig renders an ip_config block in one line, e.g.
+// "static 10.0.1.10/255.255.255.0 gw 10.0.1.1 vlan 100 v6 static
+// 2001:db8::10/64 gw6 fe80::1".
+func describeIPConfig(ipConfig map[string]any) string {
+	if len(ipConfig) == 0 {
+		return "(unset)"
+	}
+	ipType, _ := ipConfig["type"].(string)
+	if ipType == "" {
+		ipType = "dhcp"
+	}
+	parts := []string{ipType}
+	if ip, ok := ipConfig["ip"].(string); ok && ipType == "static" {
+		if mask, ok := ipConfig["netmask"].(string); ok {
+			ip += "/" + mask
+		}
+		parts = append(parts, ip)
+	}
+	if gw, ok := ipConfig["gateway"].(string); ok && ipType == "static" {
+		parts = append(parts, "gw "+gw)
+	}
+	if vlan, ok := ipConfig["vlan_id"]; ok {
+		parts = append(parts, fmt.Sprintf("vlan %v", vlan))
+	}
+	if type6, _ := ipConfig["type6"].(string); type6 != "" {
+		parts = append(parts, "v6 "+type6)
+		if ip6, ok := ipConfig["ip6"].(string); ok && type6 == "static" {
+			if prefix, ok := ipConfig["netmask6"].(string); ok {
+				ip6 += "/" + strings.TrimPrefix(prefix, "/")
+			}
+			parts = append(parts, ip6)
+		}
+		if gw6, ok := ipConfig["gateway6"].(string); ok && type6 == "static" {
+			parts = append(parts, "gw6 "+gw6)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// guardIPConfigChanges is the extra confirmation for management-IP changes:
+// an incorrect static IP can strand a device beyond the reach of the next
+// apply. Devices whose desired ip_config fails validation are always dropped.
+// The rest are listed and need "yes" typed back, or force; --yes does not
+// cover them. When the answer is no, those devices are dropped and the other
+// updates proceed. Returns the devices to update.
+func guardIPConfigChanges(updater DeviceUpdater, deviceType string, devicesToUpdate []string, force bool) []string {
+	reporter, ok := updater.(ipConfigChangeReporter)
+	if !ok {
+		return devicesToUpdate
+	}
+	recorded := reporter.IPConfigChanges()
+
+	var changes []ipConfigChange
+	drop := make(map[string]bool)
+	for _, mac := range devicesToUpdate {
+		c, ok := recorded[mac]
+		if !ok {
+			continue
+		}
+		if len(c.Issues) > 0 {
+			fmt.Printf("%s %s %s: not updated, invalid IP settings:\n", symbols.ErrorPrefix(), deviceType, mac)
+			for _, issue := range c.Issues {
+				fmt.Printf("    %s: %s\n", issue.Field, issue.Message)
+			}
+			drop[mac] = true
+			continue
+		}
+		changes = append(changes, c)
+	}
+
+	if len(changes) > 0 && !force {
+		sort.Slice(changes, func(i, j int) bool { return changes[i].MAC < changes[j].MAC })
+		fmt.Printf("%s %s\n", symbols.WarningPrefix(), i18n.T("ip_config.header", len(changes), deviceType))
+		for _, c := range changes {
+			fmt.Printf("  %s: %s -> %s\n", c.MAC, c.From, c.To)
+		}
+		if !confirmIPConfigChange() {
+			fmt.Println(i18n.T("ip_config.skipping", len(changes), deviceType))
+			for _, c := range changes {
+				drop[c.MAC] = true
+			}
+		}
+	}
+
+	if len(drop) == 0 {
+		return devicesToUpdate
+	}
+	kept := make([]string, 0, len(devicesToUpdate))
+	for _, mac := range devicesToUpdate {
+		if !drop[mac] {
+			kept = append(kept, mac)
+		}
+	}
+	return kept
+}
+
+// reportIPConfigChanges prints the pending management-IP changes and any
+// validation errors, for diff mode.
+func reportIPConfigChanges(updater DeviceUpdater, deviceType string, devicesToUpdate []string) {
+	reporter, ok := updater.(ipConfigChangeReporter)
+	if !ok {
+		return
+	}
+	recorded := reporter.IPConfigChanges()
+	for _, mac := range devicesToUpdate {
+		c, ok := recorded[mac]
+		if !ok {
+			continue
+		}
+		if c.From != c.To {
+			fmt.Printf("Would change management IP of %s %s: %s -> %s\n", deviceType, mac, c.From, c.To)
+		} else {
+			fmt.Printf("Invalid IP settings on %s %s:\n", deviceType, mac)
+		}
+		for _, issue := range c.Issues {
+			fmt.Printf("  %s %s: %s (will not be applied)\n", symbols.ErrorPrefix(), issue.Field, issue.Message)
+		}
+	}
+}
+
+// confirmIPConfigChange asks for "yes" to be typed. It fails closed under
+// --no-input.
+func confirmIPConfigChange() bool {
+	if cmdutils.NoInput() {
+		fmt.Println(i18n.T("ip_config.no_input"))
+		return false
+	}
+	fmt.Print(i18n.T("ip_config.type_yes"))
+	input, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false
+	}
+	return strings.TrimSpace(input) == "yes"
+}
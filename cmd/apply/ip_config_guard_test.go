@@ -0,0 +1,76 @@
+package apply
+
+import (
+	"testing"
+)
+
+func TestNoteIPConfigChange(t *testing.T) {
+	b := NewBaseDeviceUpdater("ap")
+	current := map[string]any{"ip_config": map[string]any{"type": "dhcp", "vlan_id": float64(1)}}
+
+	// Declared keys that already match are not a change
+	b.noteIPConfigChange("aa0000000001", current, map[string]any{"ip_config": map[string]any{"type": "dhcp"}}, nil)
+	// No ip_config declared
+	b.noteIPConfigChange("aa0000000002", current, map[string]any{"name": "ap"}, nil)
+	// ip_config not managed, so not pushed
+	b.noteIPConfigChange("aa0000000003", current, map[string]any{"ip_config": map[string]any{"type": "static"}}, []string{"name"})
+	// A real change, and an invalid one
+	b.noteIPConfigChange("aa0000000004", current, map[string]any{"ip_config": map[string]any{
+		"type": "static", "ip": "10.0.1.10", "netmask": "255.255.255.0", "gateway": "10.0.1.1",
+	}}, nil)
+	b.noteIPConfigChange("aa0000000005", current, map[string]any{"ip_config": map[string]any{"type": "static"}}, []string{"ip_config"})
+
+	changes := b.IPConfigChanges()
+	if len(changes) != 2 {
+		t.Fatalf("want 2 recorded changes, got %v", changes)
+	}
+	c := changes["aa0000000004"]
+	if c.From != "dhcp vlan 1" || c.To != "static 10.0.1.10/255.255.255.0 gw 10.0.1.1" || len(c.Issues) != 0 {
+		t.Errorf("unexpected change %+v", c)
+	}
+	if len(changes["aa0000000005"].Issues) == 0 {
+		t.Error("static ip_config without an address should fail validation")
+	}
+}
+
+func TestGuardIPConfigChanges_Force(t *testing.T) {
+	u := NewAPUpdater()
+	current := map[string]any{}
+	u.noteIPConfigChange("aa0000000001", current, map[string]any{"ip_config": map[string]any{
+		"type": "static", "ip": "10.0.1.10", "netmask": "255.255.255.0", "gateway": "10.0.1.1",
+	}}, nil)
+	u.noteIPConfigChange("aa0000000002", current, map[string]any{"ip_config": map[string]any{"type": "static"}}, nil)
+
+	got := guardIPConfigChanges(u, "ap", []string{"aa0000000001", "aa0000000002", "aa0000000003"}, true)
+	want := []string{"aa0000000001", "aa0000000003"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("got %v, want %v (invalid ip_config is dropped even with force)", got, want)
+	}
+}
+
+func TestDescribeIPConfigIPv6(t *testing.T) {
+	got := describeIPConfig(map[string]any{
+		"type": "dhcp", "type6": "static", "ip6": "2001:db8::10", "netmask6": "/64", "gateway6": "fe80::1",
+	})
+	if want := "dhcp v6 static 2001:db8::10/64 gw6 fe80::1"; got != want {
+		t.Errorf("describeIPConfig() = %q, want %q", got, want)
+	}
+	if got := describeIPConfig(map[string]any{"type6": "autoconf"}); got != "dhcp v6 autoconf" {
+		t.Errorf("describeIPConfig() = %q", got)
+	}
+}
+
+func TestNoteGatewayIPv6(t *testing.T) {
+	u := NewGatewayUpdater()
+	valid := map[string]any{"networks": []any{map[string]any{"name": "corp", "subnet6": "2001:db8:10::/64"}}}
+	invalid := map[string]any{"networks": []any{map[string]any{"name": "corp", "subnet6": "2001:db8:10::1/64"}}}
+	u.noteGatewayIPv6("aa0000000001", valid, nil)
+	u.noteGatewayIPv6("aa0000000002", invalid, nil)
+	// Not managed, so not pushed
+	u.noteGatewayIPv6("aa0000000003", invalid, []string{"name"})
+
+	got := guardIPConfigChanges(u, "gateway", []string{"aa0000000001", "aa0000000002", "aa0000000003"}, false)
+	if len(got) != 2 || got[0] != "aa0000000001" || got[1] != "aa0000000003" {
+		t.Errorf("got %v, want the gateway with the invalid subnet6 dropped", got)
+	}
+}
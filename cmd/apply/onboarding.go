@@ -0,0 +1,247 @@
+package apply
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	configPkg "github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/history"
+	"github.com/ravinald/wifimgr/internal/logging"
+	"github.com/ravinald/wifimgr/internal/macaddr"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+// Onboarding stages, in rollout order. A device sits at the last stage it
+// has reached; StageNone means it is not even claimed yet.
+const (
+	StageNone       = "not claimed"
+	StageClaimed    = "claimed"
+	StageAssigned   = "assigned"
+	StageConfigured = "configured"
+	StageConnected  = "connected"
+	StageNamed      = "named"
+)
+
+// OnboardingStages lists the board columns from left to right.
+var OnboardingStages = []string{StageNone, StageClaimed, StageAssigned, StageConfigured, StageConnected, StageNamed}
+
+// OnboardingDevice is one declared device's position on the onboarding board.
+type OnboardingDevice struct {
+	MAC     string `json:"mac"`
+	Name    string `json:"name,omitempty"` // intended name
+	Type    string `json:"type"`
+	API     string `json:"api"`
+	Stage   string `json:"stage"`             // last stage reached
+	Blocker string `json:"blocker,omitempty"` // why it has not reached the next stage
+}
+
+// Done reports whether the device has cleared every stage.
+func (d OnboardingDevice) Done() bool { return d.Stage == StageNamed }
+
+// SiteOnboarding places every device the site declares on the onboarding
+// board, read from the API caches and the apply history; nothing is fetched.
+// Devices route to their own "api" field, else siteDefaultAPI, as apply does.
+// The result is ordered by stage (least progressed first), then type and name.
+func SiteOnboarding(cfg *configPkg.Config, siteName, siteDefaultAPI string) ([]OnboardingDevice, error) {
+	siteConfig, err := getSiteConfiguration(cfg, siteConfigFiles(cfg), siteName)
+	if err != nil {
+		return nil, err
+	}
+	accessor := vendors.GetGlobalCacheAccessor()
+	if accessor == nil || accessor.GetManager() == nil {
+		return nil, fmt.Errorf("cache not initialized")
+	}
+	pushed := pushedDevices(cfg, siteName)
+
+	var board []OnboardingDevice
+	for _, deviceType := range []string{"ap", "switch", "gateway"} {
+		groups, err := groupDevicesByAPI(siteConfig, deviceType, siteDefaultAPI)
+		if err != nil {
+			return nil, err
+		}
+		names := intendedNames(siteConfig, deviceType)
+		for api, macs := range groups {
+			cache, err := accessor.GetManager().GetAPICache(api)
+			if err != nil {
+				return nil, fmt.Errorf("no cache for API %s (run 'wifimgr refresh'): %w", api, err)
+			}
+			siteID := siteIDInCache(cache, siteName)
+			for _, mac := range macs {
+				board = append(board, onboardingStage(cache, siteID, deviceType, mac, names[mac], pushed[mac]))
+			}
+		}
+	}
+	SortOnboarding(board)
+	return board, nil
+}
+
+// SortOnboarding orders devices least progressed first, then by type and name.
+func SortOnboarding(board []OnboardingDevice) {
+	rank := make(map[string]int, len(OnboardingStages))
+	for i, s := range OnboardingStages {
+		rank[s] = i
+	}
+	sort.SliceStable(board, func(i, j int) bool {
+		a, b := board[i], board[j]
+		if rank[a.Stage] != rank[b.Stage] {
+			return rank[a.Stage] < rank[b.Stage]
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.MAC < b.MAC
+	})
+}
+
+// onboardingStage walks one device through the stages against its API's
+// cache, stopping at the first it has not reached. pushed reports whether an
+// apply has sent it intent, per the apply history.
+func onboardingStage(cache *vendors.APICache, siteID, deviceType, mac, wantName string, pushed bool) OnboardingDevice {
+	d := OnboardingDevice{MAC: mac, Name: wantName, Type: deviceType, API: cache.APILabel, Stage: StageNone}
+
+	item := inventoryMap(cache, deviceType)[mac]
+	if item == nil {
+		d.Blocker = "not in the org inventory; claim it"
+		return d
+	}
+	d.Stage = StageClaimed
+
+	switch {
+	case siteID == "":
+		d.Blocker = "site not found in the API cache"
+		return d
+	case item.SiteID == "":
+		d.Blocker = "unassigned; run apply for the site"
+		return d
+	case item.SiteID != siteID:
+		other := item.SiteName
+		if other == "" {
+			other = item.SiteID
+		}
+		d.Blocker = fmt.Sprintf("assigned to another site (%s)", other)
+		return d
+	}
+	d.Stage = StageAssigned
+
+	meta := configMeta(cache, deviceType, mac)
+	switch {
+	case meta != nil && meta.ApplyState == vendors.ApplyStateDivergent:
+		d.Blocker = "running config diverged from intent at the last apply"
+		return d
+	case !pushed && (meta == nil || meta.ApplyState == ""):
+		d.Blocker = "no apply has pushed its config yet"
+		return d
+	}
+	d.Stage = StageConfigured
+
+	status := cache.DeviceStatus[mac]
+	switch {
+	case status == nil:
+		d.Blocker = "no status cached; refresh the site"
+		return d
+	case status.Status != "online" && status.Status != "alerting":
+		d.Blocker = "device is " + status.Status
+		return d
+	}
+	d.Stage = StageConnected
+
+	if wantName != "" && item.Name != wantName {
+		current := item.Name
+		if current == "" {
+			current = "unnamed"
+		}
+		d.Blocker = fmt.Sprintf("named %q, intent says %q", current, wantName)
+		return d
+	}
+	d.Stage = StageNamed
+	return d
+}
+
+func inventoryMap(cache *vendors.APICache, deviceType string) map[string]*vendors.InventoryItem {
+	switch deviceType {
+	case "switch":
+		return cache.Inventory.Switch
+	case "gateway":
+		return cache.Inventory.Gateway
+	default:
+		return cache.Inventory.AP
+	}
+}
+
+// configMeta returns the apply state block of a cached device config, or nil
+// when the config is not cached.
+func configMeta(cache *vendors.APICache, deviceType, mac string) *vendors.ObjectMeta {
+	switch deviceType {
+	case "ap":
+		if c := cache.Configs.AP[mac]; c != nil {
+			return &c.ObjectMeta
+		}
+	case "switch":
+		if c := cache.Configs.Switch[mac]; c != nil {
+			return &c.ObjectMeta
+		}
+	case "gateway":
+		if c := cache.Configs.Gateway[mac]; c != nil {
+			return &c.ObjectMeta
+		}
+	}
+	return nil
+}
+
+func siteIDInCache(cache *vendors.APICache, siteName string) string {
+	for _, s := range cache.Sites.Info {
+		if strings.EqualFold(s.Name, siteName) {
+			return s.ID
+		}
+	}
+	return ""
+}
+
+// intendedNames maps each declared device's normalized MAC to the name its
+// intent gives it.
+func intendedNames(siteConfig SiteConfig, deviceType string) map[string]string {
+	var devices map[string]map[string]any
+	switch deviceType {
+	case "ap":
+		devices = siteConfig.Devices.APs
+	case "switch":
+		devices = siteConfig.Devices.Switches
+	case "gateway":
+		devices = siteConfig.Devices.WanEdge
+	}
+	names := make(map[string]string, len(devices))
+	for mac, devCfg := range devices {
+		if name, ok := devCfg["name"].(string); ok {
+			names[macaddr.NormalizeOrEmpty(mac)] = name
+		}
+	}
+	return names
+}
+
+// pushedDevices returns the MACs that an apply assigned or updated at the
+// site, per the apply history. Failed runs are skipped: which devices they
+// reached is not recorded.
+func pushedDevices(cfg *configPkg.Config, siteName string) map[string]bool {
+	pushed := make(map[string]bool)
+	if cfg == nil || cfg.Files.ConfigDir == "" {
+		return pushed
+	}
+	entries, err := history.Read(history.Path(cfg.Files.ConfigDir), siteName)
+	if err != nil {
+		logging.Debugf("onboarding: apply history not read: %v", err)
+		return pushed
+	}
+	for _, e := range entries {
+		if e.Result == history.ResultFailed {
+			continue
+		}
+		for _, mac := range append(append([]string{}, e.Assigned...), e.Updated...) {
+			pushed[macaddr.NormalizeOrEmpty(mac)] = true
+		}
+	}
+	return pushed
+}
@@ -0,0 +1,92 @@
+package apply
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+func onboardingCache() *vendors.APICache {
+	cache := &vendors.APICache{APILabel: "mist-prod"}
+	cache.Sites.Info = []vendors.SiteInfo{{ID: "site-1", Name: "US-LAB-01"}, {ID: "site-2", Name: "US-HQ-01"}}
+	cache.Inventory.AP = map[string]*vendors.InventoryItem{
+		"aa0000000002": {MAC: "aa0000000002"},
+		"aa0000000003": {MAC: "aa0000000003", SiteID: "site-2", SiteName: "US-HQ-01"},
+		"aa0000000004": {MAC: "aa0000000004", SiteID: "site-1"},
+		"aa0000000005": {MAC: "aa0000000005", SiteID: "site-1"},
+		"aa0000000006": {MAC: "aa0000000006", SiteID: "site-1"},
+		"aa0000000007": {MAC: "aa0000000007", SiteID: "site-1", Name: "ap-old"},
+		"aa0000000008": {MAC: "aa0000000008", SiteID: "site-1", Name: "lab-ap-08"},
+	}
+	cache.Configs.AP = map[string]*vendors.APConfig{
+		"aa0000000005": {ObjectMeta: vendors.ObjectMeta{ApplyState: vendors.ApplyStateDivergent}},
+		"aa0000000006": {ObjectMeta: vendors.ObjectMeta{ApplyState: vendors.ApplyStateVerified}},
+	}
+	cache.DeviceStatus = map[string]*vendors.DeviceStatus{
+		"aa0000000006": {Status: "offline"},
+		"aa0000000007": {Status: "online"},
+		"aa0000000008": {Status: "alerting"},
+	}
+	return cache
+}
+
+func TestOnboardingStage(t *testing.T) {
+	cache := onboardingCache()
+	tests := []struct {
+		mac, name string
+		pushed    bool
+		stage     string
+		blocker   string // substring; empty means none
+	}{
+		{mac: "aa0000000001", stage: StageNone, blocker: "claim it"},
+		{mac: "aa0000000002", stage: StageClaimed, blocker: "unassigned"},
+		{mac: "aa0000000003", stage: StageClaimed, blocker: "another site (US-HQ-01)"},
+		{mac: "aa0000000004", stage: StageAssigned, blocker: "no apply"},
+		{mac: "aa0000000005", pushed: true, stage: StageAssigned, blocker: "diverged"},
+		{mac: "aa0000000006", stage: StageConfigured, blocker: "device is offline"},
+		{mac: "aa0000000007", name: "lab-ap-07", pushed: true, stage: StageConnected, blocker: `named "ap-old"`},
+		{mac: "aa0000000008", name: "lab-ap-08", pushed: true, stage: StageNamed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mac, func(t *testing.T) {
+			d := onboardingStage(cache, "site-1", "ap", tt.mac, tt.name, tt.pushed)
+			if d.Stage != tt.stage {
+				t.Errorf("stage = %q, want %q (blocker %q)", d.Stage, tt.stage, d.Blocker)
+			}
+			if tt.blocker == "" && d.Blocker != "" {
+				t.Errorf("blocker = %q, want none", d.Blocker)
+			}
+			if !strings.Contains(d.Blocker, tt.blocker) {
+				t.Errorf("blocker = %q, want substring %q", d.Blocker, tt.blocker)
+			}
+			if d.API != "mist-prod" {
+				t.Errorf("api = %q", d.API)
+			}
+		})
+	}
+}
+
+func TestOnboardingStage_SiteMissingFromCache(t *testing.T) {
+	d := onboardingStage(onboardingCache(), siteIDInCache(onboardingCache(), "US-NEW-01"), "ap", "aa0000000004", "", true)
+	if d.Stage != StageClaimed || !strings.Contains(d.Blocker, "site not found") {
+		t.Errorf("got %+v", d)
+	}
+}
+
+func TestSortOnboarding(t *testing.T) {
+	board := []OnboardingDevice{
+		{MAC: "3", Type: "ap", Stage: StageNamed},
+		{MAC: "2", Type: "switch", Stage: StageClaimed},
+		{MAC: "1", Type: "ap", Stage: StageClaimed},
+		{MAC: "4", Type: "ap", Stage: StageNone},
+	}
+	SortOnboarding(board)
+	var got []string
+	for _, d := range board {
+		got = append(got, d.MAC)
+	}
+	if strings.Join(got, ",") != "4,1,2,3" {
+		t.Errorf("order = %v, want 4,1,2,3", got)
+	}
+}
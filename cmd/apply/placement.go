@@ -0,0 +1,78 @@
+package apply
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+// resolvePlacement prepares an AP's floor-plan placement for comparison and
+// push. A map_name is replaced with the ID of the site's floor plan of that
+// name, since the vendors only accept map IDs; floor plans are listed once
+// per site and reused for every AP. The result is a copy; cfg is not
+// modified.
+func (a *APUpdater) resolvePlacement(ctx context.Context, client vendors.Client, siteID string, cfg map[string]any) (map[string]any, error) {
+	name, ok := cfg["map_name"].(string)
+	if !ok || name == "" {
+		return cfg, nil
+	}
+	maps, err := a.siteMapList(ctx, client, siteID)
+	if err != nil {
+		return nil, err
+	}
+	var mapID string
+	for _, m := range maps {
+		if strings.EqualFold(m.Name, name) {
+			mapID = m.ID
+			break
+		}
+	}
+	if mapID == "" {
+		return nil, fmt.Errorf("map_name %q: no floor plan of that name at this site", name)
+	}
+	out := make(map[string]any, len(cfg))
+	for k, v := range cfg {
+		out[k] = v
+	}
+	delete(out, "map_name")
+	out["map_id"] = mapID
+	return out, nil
+}
+
+// siteMapList returns the floor plans of a site, listing them on first use.
+func (a *APUpdater) siteMapList(ctx context.Context, client vendors.Client, siteID string) ([]*vendors.SiteMap, error) {
+	if maps, ok := a.siteMaps[siteID]; ok {
+		return maps, nil
+	}
+	svc := client.Maps()
+	if svc == nil {
+		return nil, fmt.Errorf("map_name needs floor plans, which are not available with this API (%s); use map_id", client.VendorName())
+	}
+	maps, err := svc.ListBySite(ctx, siteID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list floor plans: %w", err)
+	}
+	if a.siteMaps == nil {
+		a.siteMaps = make(map[string][]*vendors.SiteMap)
+	}
+	a.siteMaps[siteID] = maps
+	return maps, nil
+}
+
+// settlePlacement copies the API's x and y into desired where they are
+// within vendors.PlacementTolerance of intent, so a position rounded by the
+// vendor's floor-plan editor is not pushed again on every apply.
+func settlePlacement(current, desired map[string]any) {
+	for _, key := range []string{"x", "y"} {
+		want, ok := desired[key].(float64)
+		if !ok {
+			continue
+		}
+		if have, ok := current[key].(float64); ok && want != have && math.Abs(want-have) <= vendors.PlacementTolerance {
+			desired[key] = have
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package apply
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+// mapsClient is a vendor client with floor plans, counting how often they
+// are listed.
+type mapsClient struct {
+	*vendors.MockClient
+	lists int
+}
+
+func (c *mapsClient) Maps() vendors.MapsService { return c }
+
+func (c *mapsClient) ListBySite(_ context.Context, siteID string) ([]*vendors.SiteMap, error) {
+	c.lists++
+	return []*vendors.SiteMap{{ID: "map-2", Name: "Floor 2", SiteID: siteID}}, nil
+}
+
+func TestResolvePlacement(t *testing.T) {
+	client := &mapsClient{MockClient: vendors.NewMockClient("mist", "org-1")}
+	a := NewAPUpdater()
+
+	intent := map[string]any{"name": "AP-01", "map_name": "floor 2", "x": 10.0, "y": 20.0}
+	got, err := a.resolvePlacement(context.Background(), client, "site-1", intent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got["map_id"] != "map-2" || got["map_name"] != nil || got["x"] != 10.0 {
+		t.Errorf("resolved = %v", got)
+	}
+	if intent["map_name"] != "floor 2" {
+		t.Error("the intent map should not be modified")
+	}
+
+	if _, err := a.resolvePlacement(context.Background(), client, "site-1", map[string]any{"map_name": "Roof"}); err == nil {
+		t.Error("an unknown map name should be an error")
+	}
+	if client.lists != 1 {
+		t.Errorf("floor plans listed %d times, want once per site", client.lists)
+	}
+
+	// Without map_name there is nothing to resolve and no API call.
+	plain := map[string]any{"map_id": "map-1"}
+	if got, err := a.resolvePlacement(context.Background(), vendors.NewMockClient("mist", "org-1"), "site-2", plain); err != nil || got["map_id"] != "map-1" {
+		t.Errorf("map_id intent = %v, %v", got, err)
+	}
+}
+
+func TestSettlePlacement(t *testing.T) {
+	current := map[string]any{"x": 100.25, "y": 50.0}
+	desired := map[string]any{"x": 100.0, "y": 60.0}
+	settlePlacement(current, desired)
+	if desired["x"] != 100.25 {
+		t.Errorf("x within tolerance should take the API's value, got %v", desired["x"])
+	}
+	if desired["y"] != 60.0 {
+		t.Errorf("y moved beyond tolerance should keep intent, got %v", desired["y"])
+	}
+}
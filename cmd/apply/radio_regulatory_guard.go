@@ -0,0 +1,84 @@
+package apply
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/ravinald/wifimgr/internal/symbols"
+	"github.com/ravinald/wifimgr/internal/validation"
+)
+
+// radioRegulatoryReporter is implemented by updaters that check radio_config
+// against the site's regulatory domain while finding devices to update.
+type radioRegulatoryReporter interface {
+	RadioRegulatoryIssues() map[string][]validation.LintIssue
+}
+
+// noteRadioRegulatory checks the pushed radio_config (after template
+// expansion and managed-key filtering) against the regulatory domain of the
+// site's country_code, and records the devices that break it.
+func (b *BaseDeviceUpdater) noteRadioRegulatory(mac string, desired map[string]any, managedKeys []string, countryCode string) {
+	rc, ok := filterConfigByManagedKeys(desired, managedKeys)["radio_config"].(map[string]any)
+	if !ok {
+		return
+	}
+	issues := validation.ValidateRadioRegulatory(rc, countryCode)
+	if len(issues) == 0 {
+		return
+	}
+	if b.radioIssues == nil {
+		b.radioIssues = make(map[string][]validation.LintIssue)
+	}
+	b.radioIssues[mac] = issues
+}
+
+// RadioRegulatoryIssues returns the regulatory problems recorded by the last
+// FindDevicesToUpdate, keyed by MAC.
+func (b *BaseDeviceUpdater) RadioRegulatoryIssues() map[string][]validation.LintIssue {
+	return b.radioIssues
+}
+
+// siteCountryCode returns the site_config country_code, upper-cased.
+func siteCountryCode(siteConfig SiteConfig) string {
+	cc, _ := siteConfig.SiteConfig["country_code"].(string)
+	return strings.ToUpper(strings.TrimSpace(cc))
+}
+
+// checkRadioRegulatory fails the apply when any configured device's desired
+// radio_config breaks the site's regulatory rules: the vendor would reject
+// the push or, worse, accept a non-compliant radio. It runs before anything
+// is changed, and force does not override it.
+func checkRadioRegulatory(updater DeviceUpdater, deviceType, siteName string) error {
+	reporter, ok := updater.(radioRegulatoryReporter)
+	if !ok {
+		return nil
+	}
+	recorded := reporter.RadioRegulatoryIssues()
+	if len(recorded) == 0 {
+		return nil
+	}
+	for _, mac := range slices.Sorted(maps.Keys(recorded)) {
+		fmt.Printf("%s %s %s: radio_config breaks regulatory rules:\n", symbols.ErrorPrefix(), deviceType, mac)
+		for _, issue := range recorded[mac] {
+			fmt.Printf("    %s: %s\n", issue.Field, issue.Message)
+		}
+	}
+	return fmt.Errorf("%d %s(s) in site %s have radio settings its country does not permit; fix radio_config or the template and re-run", len(recorded), deviceType, siteName)
+}
+
+// reportRadioRegulatory prints the regulatory problems that would fail the
+// apply, for diff mode.
+func reportRadioRegulatory(updater DeviceUpdater, deviceType string) {
+	reporter, ok := updater.(radioRegulatoryReporter)
+	if !ok {
+		return
+	}
+	recorded := reporter.RadioRegulatoryIssues()
+	for _, mac := range slices.Sorted(maps.Keys(recorded)) {
+		for _, issue := range recorded[mac] {
+			fmt.Printf("  %s %s %s %s: %s (apply will fail)\n", symbols.ErrorPrefix(), deviceType, mac, issue.Field, issue.Message)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package apply
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSiteCountryCode(t *testing.T) {
+	if got := siteCountryCode(SiteConfig{SiteConfig: map[string]any{"country_code": " gb "}}); got != "GB" {
+		t.Errorf("got %q, want GB", got)
+	}
+	if got := siteCountryCode(SiteConfig{}); got != "" {
+		t.Errorf("got %q, want empty", got)
+	}
+}
+
+func TestCheckRadioRegulatory(t *testing.T) {
+	u := NewAPUpdater()
+	afc := map[string]any{"radio_config": map[string]any{
+		"band_6": map[string]any{"standard_power": true, "channel": float64(37)},
+	}}
+	unii3 := map[string]any{"radio_config": map[string]any{
+		"band_5": map[string]any{"channel": float64(149)},
+	}}
+
+	// AFC is fine in the US, not in Great Britain
+	u.noteRadioRegulatory("aa0000000001", afc, nil, "US")
+	// radio_config not managed, so not pushed
+	u.noteRadioRegulatory("aa0000000002", unii3, []string{"name"}, "DE")
+	// Country not in the table
+	u.noteRadioRegulatory("aa0000000003", unii3, nil, "")
+	if err := checkRadioRegulatory(u, "ap", "US-LAB-01"); err != nil {
+		t.Fatalf("compliant site: %v", err)
+	}
+
+	u.noteRadioRegulatory("aa0000000004", afc, nil, "GB")
+	u.noteRadioRegulatory("aa0000000005", unii3, nil, "DE")
+	if issues := u.RadioRegulatoryIssues(); len(issues) != 2 {
+		t.Fatalf("want 2 devices with issues, got %v", issues)
+	}
+	err := checkRadioRegulatory(u, "ap", "DE-BER-01")
+	if err == nil || !strings.Contains(err.Error(), "2 ap(s) in site DE-BER-01") {
+		t.Errorf("got %v, want a failure naming 2 APs", err)
+	}
+}
@@ -0,0 +1,124 @@
+package apply
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/viper"
+
+	configPkg "github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/logging"
+	"github.com/ravinald/wifimgr/internal/symbols"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+// reconcileSiteKeys are the site_config keys compared against the cached
+// site. Other site_config keys (wlan lists and the like) are intent-only.
+var reconcileSiteKeys = []string{"name", "timezone", "address", "country_code", "notes", "latitude", "longitude"}
+
+// siteSettingsPair returns the running and intended site settings limited to
+// reconcileSiteKeys that the intent declares.
+func siteSettingsPair(intent map[string]any, site *vendors.SiteInfo) (running, desired map[string]any) {
+	running = make(map[string]any)
+	desired = make(map[string]any)
+
+	var cached map[string]any
+	if site != nil {
+		if data, err := json.Marshal(site); err == nil {
+			_ = json.Unmarshal(data, &cached)
+		}
+	}
+	for _, k := range reconcileSiteKeys {
+		v, ok := intent[k]
+		if !ok {
+			continue
+		}
+		desired[k] = v
+		if c, ok := cached[k]; ok {
+			running[k] = c
+		}
+	}
+	return running, desired
+}
+
+// ReconcileSite renders a site's running config (API cache) against its
+// intent, one managed object at a time: site settings, WLANs, then each
+// declared AP, switch, and gateway. It reuses the diff renderers apply uses,
+// side by side unless unified is set. Only devices that resolve to apiLabel
+// are compared. Nothing is pushed. Returns the number of objects that differ.
+func ReconcileSite(ctx context.Context, client vendors.Client, cfg *configPkg.Config, siteName, apiLabel string, unified bool) (int, error) {
+	viper.Set("show_diff", true)
+	viper.Set("split_diff", !unified)
+
+	templates, err := loadTemplatesFromConfig(cfg)
+	if err != nil {
+		logging.Warnf("Failed to load templates: %v - continuing without template expansion", err)
+		templates = configPkg.NewTemplateStore()
+	}
+
+	siteConfig, err := getSiteConfiguration(cfg, siteConfigFiles(cfg), siteName)
+	if err != nil {
+		return 0, err
+	}
+	setTemplateStore(templatesForSite(templates, siteName, siteConfig), apiLabel)
+	siteID, err := getSiteIDByName(client, siteName)
+	if err != nil {
+		return 0, fmt.Errorf("error getting site ID for %s: %v", siteName, err)
+	}
+
+	differ := 0
+	fmt.Printf("== Site settings: %s (API %s) ==\n", siteName, apiLabel)
+	var cached *vendors.SiteInfo
+	if accessor := vendors.GetGlobalCacheAccessor(); accessor != nil {
+		cached, _ = accessor.GetSiteByID(siteID)
+	}
+	running, desired := siteSettingsPair(siteConfig.SiteConfig, cached)
+	if jsonEqual(running, desired) {
+		fmt.Printf("%s site settings match intent\n", symbols.SuccessPrefix())
+	} else {
+		showJSONDiff(running, desired, "API Cache", siteName)
+		differ++
+	}
+
+	fmt.Println("\n== WLANs ==")
+	wlanChanges, _, err := applyWLANs(ctx, client, cfg, siteConfig, siteID, apiLabel, true, false, nil)
+	switch {
+	case err != nil:
+		fmt.Printf("%s could not compare WLANs: %v\n", symbols.WarningPrefix(), err)
+	case wlanChanges == 0:
+		fmt.Printf("%s WLANs match intent\n", symbols.SuccessPrefix())
+	}
+	differ += wlanChanges
+
+	for _, deviceType := range []string{"ap", "switch", "gateway"} {
+		groups, err := groupDevicesByAPI(siteConfig, deviceType, apiLabel)
+		if err != nil {
+			return differ, err
+		}
+		macs := groups[apiLabel]
+		for api, other := range groups {
+			if api != apiLabel {
+				fmt.Printf("%s %d %s(s) use API %s and are not compared here\n", symbols.WarningPrefix(), len(other), deviceType, api)
+			}
+		}
+		if len(macs) == 0 {
+			continue
+		}
+
+		fmt.Printf("\n== %ss (%d declared) ==\n", deviceType, len(macs))
+		updater, err := getDeviceUpdater(deviceType)
+		if err != nil {
+			return differ, err
+		}
+		changed, err := updater.FindDevicesToUpdate(ctx, client, cfg, siteConfig, macs, siteID, apiLabel)
+		if err != nil {
+			return differ, fmt.Errorf("error comparing %ss: %w", deviceType, err)
+		}
+		if len(changed) == 0 {
+			fmt.Printf("%s all %ss match intent\n", symbols.SuccessPrefix(), deviceType)
+		}
+		differ += len(changed)
+	}
+	return differ, nil
+}
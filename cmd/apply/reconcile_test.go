@@ -0,0 +1,39 @@
+package apply
+
+import (
+	"testing"
+
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+func TestSiteSettingsPair(t *testing.T) {
+	intent := map[string]any{
+		"name":     "US-LAB-01",
+		"timezone": "America/Los_Angeles",
+		"notes":    "lab",
+		"wlan":     []any{"corp"},
+	}
+	site := &vendors.SiteInfo{Name: "US-LAB-01", Timezone: "America/New_York", Address: "1 Main St"}
+
+	running, desired := siteSettingsPair(intent, site)
+	if _, ok := desired["wlan"]; ok {
+		t.Error("intent-only keys must not be compared")
+	}
+	if _, ok := running["address"]; ok {
+		t.Error("keys the intent does not declare must not be compared")
+	}
+	if running["timezone"] != "America/New_York" || desired["timezone"] != "America/Los_Angeles" {
+		t.Errorf("timezone: running %v, desired %v", running["timezone"], desired["timezone"])
+	}
+	if _, ok := running["notes"]; ok {
+		t.Error("empty cached notes should be absent, showing as an addition")
+	}
+	if jsonEqual(running, desired) {
+		t.Error("pair should differ")
+	}
+
+	running, desired = siteSettingsPair(map[string]any{"name": "US-LAB-01"}, site)
+	if !jsonEqual(running, desired) {
+		t.Errorf("matching name should be equal: %v vs %v", running, desired)
+	}
+}
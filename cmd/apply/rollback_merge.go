@@ -0,0 +1,289 @@
+package apply
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/i18n"
+	"github.com/ravinald/wifimgr/internal/merge3"
+	"github.com/ravinald/wifimgr/internal/symbols"
+)
+
+// errRollbackCancelled is returned when the operator declines to settle the
+// conflicts of a rollback; no file has been changed.
+var errRollbackCancelled = errors.New("rollback cancelled")
+
+// Rolling back to an older backup while the config has been edited since the
+// most recent one would silently drop those edits. Instead the rollback is a
+// three-way merge: the most recent backup is the common ancestor, the
+// selected backup is what is being restored, and the current file holds the
+// newer edits. Changes between the two backups are undone; edits made since
+// the most recent backup are kept. Where both touch the same field, the
+// operator chooses, one site block at a time.
+
+// rollbackSiteOutcome describes what a rollback does to one site block.
+type rollbackSiteOutcome struct {
+	Key       string
+	Name      string
+	Outcome   string
+	Conflicts []merge3.Conflict
+}
+
+// rollbackMerge is a planned merging rollback of a whole config file.
+type rollbackMerge struct {
+	Merged map[string]any
+	Sites  []rollbackSiteOutcome
+	// FileConflicts are conflicts outside config.sites (version, top-level keys).
+	FileConflicts []merge3.Conflict
+}
+
+// readJSONDoc reads and parses a config file or backup.
+func readJSONDoc(path string) (map[string]any, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- path from operator-controlled config
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return doc, nil
+}
+
+// stripBackupMetadata removes the last_modified stamps a backup adds at the
+// root and under config, so they do not read as edits.
+func stripBackupMetadata(doc map[string]any) {
+	delete(doc, "last_modified")
+	if configSection, ok := doc["config"].(map[string]any); ok {
+		for _, v := range configSection {
+			if section, ok := v.(map[string]any); ok {
+				delete(section, "last_modified")
+			}
+		}
+	}
+}
+
+// sitesOf returns config.sites of a parsed config file.
+func sitesOf(doc map[string]any) map[string]any {
+	configSection, _ := doc["config"].(map[string]any)
+	sites, _ := configSection["sites"].(map[string]any)
+	return sites
+}
+
+// planRollbackFileMerge plans the merge of restoring backupPath over
+// currentPath with basePath as the ancestor. It returns nil when there is
+// nothing to merge — no ancestor backup, or no edits since it — and the
+// backup is restored as it is.
+func planRollbackFileMerge(currentPath, basePath, backupPath string) (*rollbackMerge, error) {
+	base, err := readJSONDoc(basePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read most recent backup: %w", err)
+	}
+	current, err := readJSONDoc(currentPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read current config: %w", err)
+	}
+	backup, err := readJSONDoc(backupPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup file: %w", err)
+	}
+	for _, doc := range []map[string]any{base, current, backup} {
+		stripBackupMetadata(doc)
+	}
+	if reflect.DeepEqual(current, base) {
+		return nil, nil
+	}
+	return planRollbackMerge(base, current, backup), nil
+}
+
+// planRollbackMerge merges the rollback of current to backup against base,
+// the most recent backup. Each site block is reported with what the rollback
+// does to it; conflicts are left unresolved (holding the current values).
+func planRollbackMerge(base, current, backup map[string]any) *rollbackMerge {
+	merged, conflicts := merge3.Merge(base, current, backup)
+	plan := &rollbackMerge{Merged: merged}
+
+	bySite := make(map[string][]merge3.Conflict)
+	for _, c := range conflicts {
+		if len(c.Path) >= 3 && c.Path[0] == "config" && c.Path[1] == "sites" {
+			bySite[c.Path[2]] = append(bySite[c.Path[2]], c)
+		} else {
+			plan.FileConflicts = append(plan.FileConflicts, c)
+		}
+	}
+
+	baseSites, currentSites, backupSites := sitesOf(base), sitesOf(current), sitesOf(backup)
+	keys := make(map[string]bool)
+	for _, sites := range []map[string]any{currentSites, backupSites} {
+		for k := range sites {
+			keys[k] = true
+		}
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	for _, key := range sorted {
+		b, c, r := baseSites[key], currentSites[key], backupSites[key]
+		if reflect.DeepEqual(c, r) {
+			continue
+		}
+		plan.Sites = append(plan.Sites, rollbackSiteOutcome{
+			Key:       key,
+			Name:      siteBlockName(key, c, r),
+			Outcome:   mergeOutcome(b, c, r, bySite[key]),
+			Conflicts: bySite[key],
+		})
+	}
+	return plan
+}
+
+// mergeOutcome describes what merging does to a site block that differs
+// between current and backup.
+func mergeOutcome(base, current, backup any, conflicts []merge3.Conflict) string {
+	switch {
+	case reflect.DeepEqual(current, base):
+		return "restored from backup"
+	case reflect.DeepEqual(backup, base):
+		return "kept (edited since the most recent backup)"
+	case len(conflicts) > 0:
+		return fmt.Sprintf("merged, %d conflict(s)", len(conflicts))
+	}
+	return "merged with edits since the most recent backup"
+}
+
+// siteBlockName is the site_config.name of the first block that has one, or
+// key.
+func siteBlockName(key string, blocks ...any) string {
+	for _, b := range blocks {
+		block, _ := b.(map[string]any)
+		siteConfig, _ := block["site_config"].(map[string]any)
+		if name, _ := siteConfig["name"].(string); name != "" {
+			return name
+		}
+	}
+	return key
+}
+
+// printRollbackMerge lists what the rollback does to each site block.
+func printRollbackMerge(plan *rollbackMerge) {
+	fmt.Printf("  Merging with edits made since the most recent backup:\n")
+	for _, s := range plan.Sites {
+		fmt.Printf("    %-24s %s\n", s.Name, s.Outcome)
+	}
+}
+
+// resolveRollbackConflicts settles the conflicts of plan in place. With
+// force the backup wins every conflict; otherwise the operator is asked once
+// per site block. It fails closed under --no-input.
+func resolveRollbackConflicts(plan *rollbackMerge, force bool) error {
+	type group struct {
+		heading   string
+		conflicts []merge3.Conflict
+	}
+	var groups []group
+	if len(plan.FileConflicts) > 0 {
+		groups = append(groups, group{i18n.T("rollback.conflicts_file", len(plan.FileConflicts)), plan.FileConflicts})
+	}
+	for _, s := range plan.Sites {
+		if len(s.Conflicts) > 0 {
+			groups = append(groups, group{i18n.T("rollback.conflicts_site", s.Name, len(s.Conflicts)), s.Conflicts})
+		}
+	}
+	if len(groups) == 0 {
+		return nil
+	}
+	if force {
+		for _, g := range groups {
+			for _, c := range g.conflicts {
+				merge3.Resolve(plan.Merged, c)
+			}
+		}
+		return nil
+	}
+	if cmdutils.NoInput() {
+		fmt.Println(i18n.T("rollback.no_input"))
+		return errRollbackCancelled
+	}
+
+	in := bufio.NewReader(os.Stdin)
+	for _, g := range groups {
+		fmt.Printf("\n%s %s\n", symbols.WarningPrefix(), g.heading)
+		for _, c := range g.conflicts {
+			printRollbackConflict(c)
+		}
+		fmt.Print(i18n.T("rollback.choose"))
+		switch readChoice(in) {
+		case "b":
+			for _, c := range g.conflicts {
+				merge3.Resolve(plan.Merged, c)
+			}
+		case "c":
+		case "e":
+			for _, c := range g.conflicts {
+				fmt.Print(i18n.T("rollback.choose_one", conflictKey(c)))
+				switch readChoice(in) {
+				case "b":
+					merge3.Resolve(plan.Merged, c)
+				case "c":
+				default:
+					return errRollbackCancelled
+				}
+			}
+		default:
+			return errRollbackCancelled
+		}
+	}
+	return nil
+}
+
+// conflictKey is the conflict's path within its site block.
+func conflictKey(c merge3.Conflict) string {
+	if len(c.Path) > 3 && c.Path[0] == "config" && c.Path[1] == "sites" {
+		return strings.Join(c.Path[3:], ".")
+	}
+	return c.Key()
+}
+
+func printRollbackConflict(c merge3.Conflict) {
+	fmt.Printf("  %s\n", conflictKey(c))
+	fmt.Printf("    ancestor: %s\n", formatConflictValue(c.Base))
+	fmt.Printf("    current:  %s\n", formatConflictValue(c.Ours))
+	fmt.Printf("    backup:   %s\n", formatConflictValue(c.Theirs))
+}
+
+// formatConflictValue renders a value as compact JSON, shortened to one line.
+func formatConflictValue(v any) string {
+	if v == merge3.Absent {
+		return "(absent)"
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	s := string(data)
+	if len(s) > 100 {
+		s = s[:97] + "..."
+	}
+	return s
+}
+
+func readChoice(in *bufio.Reader) string {
+	input, err := in.ReadString('\n')
+	if err != nil && input == "" {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(input))
+}
@@ -0,0 +1,110 @@
+package apply
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/config"
+)
+
+// writeRollbackFixture lays out sites.json with backups .0 (the most recent)
+// and .1 (the one being restored) under dir/backups.
+func writeRollbackFixture(t *testing.T, dir, current, latest, older string) string {
+	t.Helper()
+	backups := filepath.Join(dir, "backups")
+	if err := os.MkdirAll(backups, 0750); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(dir, "sites.json")
+	for path, body := range map[string]string{
+		file:                                   current,
+		filepath.Join(backups, "sites.json.0"): latest,
+		filepath.Join(backups, "sites.json.1"): older,
+	} {
+		if err := os.WriteFile(path, []byte(body), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return file
+}
+
+func readSiteNotes(t *testing.T, file string) map[string]any {
+	t.Helper()
+	var data ConfigFileStructure
+	raw, _ := os.ReadFile(file)
+	if err := json.Unmarshal(raw, &data); err != nil {
+		t.Fatal(err)
+	}
+	notes := make(map[string]any)
+	for key, site := range data.Config.Sites {
+		notes[key] = site.SiteConfig["notes"]
+	}
+	return notes
+}
+
+const (
+	// .1: before LAB-01's bad change.
+	rollbackOlder = `{"version": 1, "last_modified": "2026-01-01T00:00:00Z", "config": {"sites": {
+	  "lab-01": {"site_config": {"name": "LAB-01", "notes": "good"}, "devices": {"ap": {}}},
+	  "lab-02": {"site_config": {"name": "LAB-02", "notes": "v1"}, "devices": {"ap": {}}},
+	  "last_modified": "2026-01-01T00:00:00Z"}}}`
+	// .0: LAB-01's bad change was applied.
+	rollbackLatest = `{"version": 1, "last_modified": "2026-01-02T00:00:00Z", "config": {"sites": {
+	  "lab-01": {"site_config": {"name": "LAB-01", "notes": "bad"}, "devices": {"ap": {}}},
+	  "lab-02": {"site_config": {"name": "LAB-02", "notes": "v1"}, "devices": {"ap": {}}},
+	  "last_modified": "2026-01-02T00:00:00Z"}}}`
+)
+
+func TestRollbackMergesNewerEdits(t *testing.T) {
+	dir := t.TempDir()
+	// LAB-02 was edited after the most recent backup.
+	current := `{"version": 1, "config": {"sites": {
+	  "lab-01": {"site_config": {"name": "LAB-01", "notes": "bad"}, "devices": {"ap": {}}},
+	  "lab-02": {"site_config": {"name": "LAB-02", "notes": "v2"}, "devices": {"ap": {}}}}}}`
+	file := writeRollbackFixture(t, dir, current, rollbackLatest, rollbackOlder)
+	cfg := &config.Config{Files: config.Files{ConfigDir: dir}}
+
+	if err := rollbackConfigFile(cfg, "LAB-01", file, 1, false); err != nil {
+		t.Fatalf("rollback: %v", err)
+	}
+	notes := readSiteNotes(t, file)
+	if notes["lab-01"] != "good" || notes["lab-02"] != "v2" {
+		t.Errorf("notes = %v, want LAB-01 rolled back and LAB-02's edit kept", notes)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "backups", "sites.json.2")); err != nil {
+		t.Errorf("backups not rotated: %v", err)
+	}
+}
+
+func TestRollbackConflicts(t *testing.T) {
+	// LAB-01 itself was edited again after the most recent backup.
+	current := `{"version": 1, "config": {"sites": {
+	  "lab-01": {"site_config": {"name": "LAB-01", "notes": "worse"}, "devices": {"ap": {}}},
+	  "lab-02": {"site_config": {"name": "LAB-02", "notes": "v1"}, "devices": {"ap": {}}}}}}`
+
+	dir := t.TempDir()
+	file := writeRollbackFixture(t, dir, current, rollbackLatest, rollbackOlder)
+	cfg := &config.Config{Files: config.Files{ConfigDir: dir}}
+
+	cmdutils.SetNoInput(true)
+	t.Cleanup(func() { cmdutils.SetNoInput(false) })
+	if err := rollbackConfigFile(cfg, "LAB-01", file, 1, false); err != errRollbackCancelled {
+		t.Fatalf("rollback under --no-input = %v, want cancelled", err)
+	}
+	if raw, _ := os.ReadFile(file); string(raw) != current {
+		t.Error("a cancelled rollback changed the config")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "backups", "sites.json.2")); err == nil {
+		t.Error("a cancelled rollback rotated the backups")
+	}
+
+	if err := rollbackConfigFile(cfg, "LAB-01", file, 1, true); err != nil {
+		t.Fatalf("forced rollback: %v", err)
+	}
+	if notes := readSiteNotes(t, file); notes["lab-01"] != "good" {
+		t.Errorf("notes = %v, want the backup's value with force", notes)
+	}
+}
@@ -0,0 +1,71 @@
+package apply
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ravinald/wifimgr/internal/logging"
+	"github.com/ravinald/wifimgr/internal/sitelock"
+	"github.com/ravinald/wifimgr/internal/symbols"
+)
+
+// lockReleaseTimeout bounds the release at the end of apply; it runs on a
+// fresh context so a cancelled apply still gives the lock back.
+const lockReleaseTimeout = 10 * time.Second
+
+// acquireSiteLock takes the apply_lock lease on siteName before a mutating
+// apply. It returns a release func (a no-op when locking is disabled). A site
+// held by another operator is an error naming the holder; a stale lease is
+// taken over with a warning. The lease is renewed while apply runs and given
+// back as soon as ctx is cancelled.
+func acquireSiteLock(ctx context.Context, siteName, command string) (func(), error) {
+	locker, err := sitelock.FromConfig()
+	if err != nil {
+		return nil, err
+	}
+	if locker == nil {
+		return func() {}, nil
+	}
+
+	lease, err := locker.Acquire(ctx, siteName, "apply "+command)
+	if err != nil {
+		return nil, err
+	}
+	if prev := lease.TookOver; prev != nil {
+		fmt.Printf("%s Took over stale lock on %s held by %s (expired %s)\n", symbols.WarningPrefix(),
+			siteName, prev.String(), prev.ExpiresAt.Local().Format("2006-01-02 15:04 MST"))
+		logging.Warnf("Took over stale apply lock on %s from %s", siteName, prev.Owner)
+	}
+	logging.Debugf("Acquired apply lock on %s until %s", siteName, lease.ExpiresAt.Format(time.RFC3339))
+
+	stopRenew := lease.KeepAlive(ctx, func(err error) {
+		if errors.Is(err, sitelock.ErrLeaseLost) {
+			fmt.Fprintf(os.Stderr, "%s Lost the apply lock on %s; another operator may now apply to it\n", symbols.WarningPrefix(), siteName)
+		}
+		logging.Warnf("Failed to renew apply lock on %s: %v", siteName, err)
+	})
+
+	var once sync.Once
+	release := func() {
+		once.Do(func() {
+			stopRenew()
+			releaseCtx, cancel := context.WithTimeout(context.Background(), lockReleaseTimeout)
+			defer cancel()
+			if err := lease.Release(releaseCtx); err != nil {
+				logging.Warnf("Failed to release apply lock on %s (it expires at %s): %v",
+					siteName, lease.ExpiresAt.Local().Format("15:04 MST"), err)
+			}
+		})
+	}
+	// An interrupted apply gives the site back right away rather than
+	// holding it until the deferred release runs.
+	stopOnCancel := context.AfterFunc(ctx, release)
+	return func() {
+		stopOnCancel()
+		release()
+	}, nil
+}
@@ -0,0 +1,185 @@
+package apply
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ravinald/wifimgr/api"
+	"github.com/ravinald/wifimgr/internal/logging"
+	"github.com/ravinald/wifimgr/internal/macaddr"
+	"github.com/ravinald/wifimgr/internal/warnings"
+)
+
+// virtualChassisKey is the switch intent key declaring virtual chassis
+// membership. It is intent-only: apply checks it against the API and never
+// pushes it, since VC formation is done on the switches themselves.
+const virtualChassisKey = "virtual_chassis"
+
+// Virtual chassis roles as Mist reports them.
+var virtualChassisRoles = map[string]bool{"master": true, "backup": true, "linecard": true}
+
+// vcMember is one virtual chassis member, from intent or API state.
+// MemberID is -1 when not declared.
+type vcMember struct {
+	MAC      string
+	Role     string
+	MemberID int
+}
+
+// parseVirtualChassisIntent reads a switch's virtual_chassis declaration:
+//
+//	"virtual_chassis": {"members": [{"mac": "...", "vc_role": "master", "member_id": 0}]}
+//
+// Returns nil when the switch declares none. Exactly one master is required.
+func parseVirtualChassisIntent(switchConfig map[string]any) ([]vcMember, error) {
+	raw, ok := switchConfig[virtualChassisKey]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	vc, ok := raw.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("virtual_chassis must be an object with a members list")
+	}
+	list, ok := vc["members"].([]any)
+	if !ok || len(list) == 0 {
+		return nil, fmt.Errorf("virtual_chassis.members must be a non-empty list")
+	}
+
+	members := make([]vcMember, 0, len(list))
+	seen := make(map[string]bool, len(list))
+	masters := 0
+	for i, item := range list {
+		m, ok := item.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("virtual_chassis.members[%d] must be an object", i)
+		}
+		rawMAC, _ := m["mac"].(string)
+		mac := macaddr.NormalizeOrEmpty(rawMAC)
+		if mac == "" {
+			return nil, fmt.Errorf("virtual_chassis.members[%d] has invalid mac %q", i, rawMAC)
+		}
+		if seen[mac] {
+			return nil, fmt.Errorf("virtual_chassis member %s is listed more than once", mac)
+		}
+		seen[mac] = true
+		role, _ := m["vc_role"].(string)
+		role = strings.ToLower(role)
+		if !virtualChassisRoles[role] {
+			return nil, fmt.Errorf("virtual_chassis member %s has invalid vc_role %q (use master, backup, or linecard)", mac, m["vc_role"])
+		}
+		if role == "master" {
+			masters++
+		}
+		member := vcMember{MAC: mac, Role: role, MemberID: -1}
+		if id, ok := m["member_id"].(float64); ok {
+			member.MemberID = int(id)
+		}
+		members = append(members, member)
+	}
+	if masters != 1 {
+		return nil, fmt.Errorf("virtual_chassis needs exactly one master, found %d", masters)
+	}
+	return members, nil
+}
+
+// virtualChassisFromAPI reads the members of a Mist virtual chassis status
+// response. The member number is "member" in status and "member_id" in config.
+func virtualChassisFromAPI(status map[string]any) []vcMember {
+	list, _ := status["members"].([]any)
+	members := make([]vcMember, 0, len(list))
+	for _, item := range list {
+		m, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		rawMAC, _ := m["mac"].(string)
+		mac := macaddr.NormalizeOrEmpty(rawMAC)
+		if mac == "" {
+			continue
+		}
+		role, _ := m["vc_role"].(string)
+		member := vcMember{MAC: mac, Role: strings.ToLower(role), MemberID: -1}
+		if id, ok := m["member"].(float64); ok {
+			member.MemberID = int(id)
+		} else if id, ok := m["member_id"].(float64); ok {
+			member.MemberID = int(id)
+		}
+		members = append(members, member)
+	}
+	return members
+}
+
+// compareVirtualChassis lists how the actual VC composition differs from
+// intent: missing and unexpected members, role changes, and member numbers
+// (only when intent declares one). Output is ordered by MAC.
+func compareVirtualChassis(want, have []vcMember) []string {
+	index := func(list []vcMember) map[string]vcMember {
+		out := make(map[string]vcMember, len(list))
+		for _, m := range list {
+			out[m.MAC] = m
+		}
+		return out
+	}
+	w, h := index(want), index(have)
+
+	macs := make([]string, 0, len(w)+len(h))
+	for mac := range w {
+		macs = append(macs, mac)
+	}
+	for mac := range h {
+		if _, ok := w[mac]; !ok {
+			macs = append(macs, mac)
+		}
+	}
+	sort.Strings(macs)
+
+	var diffs []string
+	for _, mac := range macs {
+		wm, inWant := w[mac]
+		hm, inHave := h[mac]
+		switch {
+		case !inHave:
+			diffs = append(diffs, fmt.Sprintf("member %s (%s) is declared but not in the virtual chassis", mac, wm.Role))
+		case !inWant:
+			diffs = append(diffs, fmt.Sprintf("member %s (%s) is in the virtual chassis but not declared", mac, hm.Role))
+		default:
+			if wm.Role != hm.Role {
+				diffs = append(diffs, fmt.Sprintf("member %s role is %s, intent says %s", mac, hm.Role, wm.Role))
+			}
+			if wm.MemberID >= 0 && wm.MemberID != hm.MemberID {
+				diffs = append(diffs, fmt.Sprintf("member %s is member %d, intent says %d", mac, hm.MemberID, wm.MemberID))
+			}
+		}
+	}
+	return diffs
+}
+
+// checkVirtualChassis compares a switch's declared virtual chassis with the
+// API and raises a warning per difference. It never blocks the apply: VC
+// drift usually means a member was swapped or renumbered on site, and the
+// operator should know before port configs land on the wrong member.
+func checkVirtualChassis(ctx context.Context, lc api.Client, siteID, deviceID, mac string, switchConfig map[string]any) {
+	warn := warnings.FromContext(ctx)
+	want, err := parseVirtualChassisIntent(switchConfig)
+	if err != nil {
+		warn.Add("Virtual chassis", "switch "+mac, "%v", err)
+		return
+	}
+	if want == nil {
+		return
+	}
+	if lc == nil {
+		logging.Debugf("Switch %s: virtual chassis check needs the Mist API; skipped", mac)
+		return
+	}
+	status, err := lc.GetVirtualChassis(ctx, siteID, deviceID)
+	if err != nil {
+		warn.Add("Virtual chassis", "switch "+mac, "could not read virtual chassis state: %v", err)
+		return
+	}
+	for _, d := range compareVirtualChassis(want, virtualChassisFromAPI(status)) {
+		warn.Add("Virtual chassis", "switch "+mac, "%s", d)
+	}
+}
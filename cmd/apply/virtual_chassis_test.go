@@ -0,0 +1,87 @@
+package apply
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseVirtualChassisIntent(t *testing.T) {
+	members, err := parseVirtualChassisIntent(map[string]any{
+		"virtual_chassis": map[string]any{"members": []any{
+			map[string]any{"mac": "AA:BB:CC:00:00:01", "vc_role": "master", "member_id": float64(0)},
+			map[string]any{"mac": "aabbcc000002", "vc_role": "Backup"},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(members) != 2 || members[0].MAC != "aabbcc000001" || members[0].MemberID != 0 {
+		t.Errorf("unexpected first member: %+v", members)
+	}
+	if members[1].Role != "backup" || members[1].MemberID != -1 {
+		t.Errorf("role should be lowercased and member_id unset: %+v", members[1])
+	}
+
+	if got, err := parseVirtualChassisIntent(map[string]any{"name": "sw"}); got != nil || err != nil {
+		t.Errorf("no declaration: got %v, %v", got, err)
+	}
+
+	bad := map[string]string{
+		"two masters": `master,master`,
+		"no master":   `backup,linecard`,
+		"bad role":    `master,spine`,
+	}
+	for name, roles := range bad {
+		var list []any
+		for i, r := range strings.Split(roles, ",") {
+			list = append(list, map[string]any{"mac": []string{"aabbcc000001", "aabbcc000002"}[i], "vc_role": r})
+		}
+		if _, err := parseVirtualChassisIntent(map[string]any{"virtual_chassis": map[string]any{"members": list}}); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestCompareVirtualChassis(t *testing.T) {
+	want := []vcMember{
+		{MAC: "aabbcc000001", Role: "master", MemberID: 0},
+		{MAC: "aabbcc000002", Role: "backup", MemberID: 1},
+		{MAC: "aabbcc000003", Role: "linecard", MemberID: -1},
+	}
+	have := virtualChassisFromAPI(map[string]any{"members": []any{
+		map[string]any{"mac": "aabbcc000001", "vc_role": "master", "member": float64(0)},
+		map[string]any{"mac": "aabbcc000002", "vc_role": "linecard", "member": float64(2)},
+		map[string]any{"mac": "aabbcc000004", "vc_role": "linecard", "member": float64(3)},
+	}})
+
+	diffs := compareVirtualChassis(want, have)
+	wantDiffs := []string{
+		"member aabbcc000002 role is linecard, intent says backup",
+		"member aabbcc000002 is member 2, intent says 1",
+		"member aabbcc000003 (linecard) is declared but not in the virtual chassis",
+		"member aabbcc000004 (linecard) is in the virtual chassis but not declared",
+	}
+	if len(diffs) != len(wantDiffs) {
+		t.Fatalf("got %v, want %v", diffs, wantDiffs)
+	}
+	for i := range diffs {
+		if diffs[i] != wantDiffs[i] {
+			t.Errorf("diff %d: got %q, want %q", i, diffs[i], wantDiffs[i])
+		}
+	}
+
+	if d := compareVirtualChassis(want[:1], have[:1]); len(d) != 0 {
+		t.Errorf("matching chassis: got %v", d)
+	}
+}
+
+func TestWithoutIntentOnlySwitchKeys(t *testing.T) {
+	in := map[string]any{"name": "sw", "virtual_chassis": map[string]any{}}
+	out := withoutIntentOnlySwitchKeys(in)
+	if _, ok := out["virtual_chassis"]; ok {
+		t.Error("virtual_chassis must be stripped")
+	}
+	if _, ok := in["virtual_chassis"]; !ok {
+		t.Error("input map must not be modified")
+	}
+}
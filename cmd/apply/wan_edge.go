@@ -0,0 +1,315 @@
+package apply
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ravinald/wifimgr/api"
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/i18n"
+	"github.com/ravinald/wifimgr/internal/logging"
+	"github.com/ravinald/wifimgr/internal/symbols"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+// WANEdgeConfig is the "wan_edge" section of a site config: the Mist WAN
+// Assurance gateway template to build for the site and the app_policy and
+// traffic_steering template labels that go into it, plus an optional OSPF/BGP
+// underlay. App policies are listed in evaluation order.
+type WANEdgeConfig struct {
+	GatewayTemplate string          `json:"gateway_template"`
+	AppPolicies     []string        `json:"app_policies,omitempty"`
+	TrafficSteering []string        `json:"traffic_steering,omitempty"`
+	Routing         *WANEdgeRouting `json:"routing,omitempty"`
+}
+
+// wanEdgeManagedKeys are the gateway template keys apply always owns.
+// Routing keys are owned only when the site declares them (see
+// wanEdgeKeys); everything else on the template (networks, port_config) is
+// left alone.
+var wanEdgeManagedKeys = []string{"service_policies", "path_preferences"}
+
+// wanEdgeRoutingKeys are the gateway template keys a routing block produces.
+var wanEdgeRoutingKeys = []string{"ospf_config", "ospf_areas", "bgp_config", "routing_policies"}
+
+// wanEdgeKeys returns the keys apply owns for a desired template body.
+func wanEdgeKeys(body map[string]any) []string {
+	keys := append([]string{}, wanEdgeManagedKeys...)
+	for _, k := range wanEdgeRoutingKeys {
+		if _, ok := body[k]; ok {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// wanEdgePlan is the planned reconcile of a site's gateway template.
+type wanEdgePlan struct {
+	Name       string
+	Action     string // fabricCreate, fabricUpdate, or fabricUnchanged
+	ID         string // existing template ID, for updates and assignment
+	Body       map[string]any
+	Details    []string
+	AssignFrom string // current site gatewaytemplate_id when assignment changes
+	Assign     bool
+}
+
+// buildWANEdgeTemplate expands the referenced app_policy and traffic_steering
+// templates into a Mist gateway template body. Vendor blocks are merged for
+// "mist". An app policy's path_preference must name a traffic_steering label
+// the site includes, so a typo cannot leave a policy steering to nothing.
+func buildWANEdgeTemplate(wan *WANEdgeConfig, store *config.TemplateStore) (map[string]any, error) {
+	if wan.GatewayTemplate == "" {
+		return nil, fmt.Errorf("wan_edge.gateway_template is required")
+	}
+
+	paths := make(map[string]any, len(wan.TrafficSteering))
+	for _, label := range wan.TrafficSteering {
+		tmpl, ok := store.GetTrafficSteeringTemplate(label)
+		if !ok {
+			return nil, fmt.Errorf("traffic_steering template '%s' not found", label)
+		}
+		paths[label] = config.ExpandForVendor(tmpl, "mist")
+	}
+
+	policies := make([]any, 0, len(wan.AppPolicies))
+	seen := make(map[string]bool, len(wan.AppPolicies))
+	for _, label := range wan.AppPolicies {
+		if seen[label] {
+			return nil, fmt.Errorf("app_policy '%s' is listed more than once", label)
+		}
+		seen[label] = true
+		tmpl, ok := store.GetAppPolicyTemplate(label)
+		if !ok {
+			return nil, fmt.Errorf("app_policy template '%s' not found", label)
+		}
+		policy := config.ExpandForVendor(tmpl, "mist")
+		if _, named := policy["name"]; !named {
+			policy["name"] = label
+		}
+		if pref, ok := policy["path_preference"].(string); ok && pref != "" {
+			if _, ok := paths[pref]; !ok {
+				return nil, fmt.Errorf("app_policy '%s' uses path_preference '%s', which is not in wan_edge.traffic_steering", label, pref)
+			}
+		}
+		policies = append(policies, policy)
+	}
+
+	body := map[string]any{
+		"name":             wan.GatewayTemplate,
+		"service_policies": policies,
+		"path_preferences": paths,
+	}
+	if wan.Routing != nil {
+		if problems := validateWANEdgeRouting(wan.Routing); len(problems) > 0 {
+			return nil, fmt.Errorf("invalid wan_edge.routing:\n  %s", strings.Join(problems, "\n  "))
+		}
+		for k, v := range buildWANEdgeRouting(wan.Routing) {
+			body[k] = v
+		}
+	}
+	return body, nil
+}
+
+// planWANEdge compares the desired template body with the org's gateway
+// templates (matched by name) and the site's current assignment.
+func planWANEdge(body map[string]any, existing []map[string]any, siteTemplateID string) wanEdgePlan {
+	name, _ := body["name"].(string)
+	plan := wanEdgePlan{Name: name, Body: body}
+
+	var current map[string]any
+	for _, t := range existing {
+		if n, _ := t["name"].(string); n == name {
+			current = t
+			break
+		}
+	}
+	if current == nil {
+		plan.Action = fabricCreate
+		for _, p := range body["service_policies"].([]any) {
+			plan.Details = append(plan.Details, fmt.Sprintf("+ app policy %v", p.(map[string]any)["name"]))
+		}
+		for _, label := range sortedMapKeys(body["path_preferences"].(map[string]any)) {
+			plan.Details = append(plan.Details, fmt.Sprintf("+ traffic steering %s", label))
+		}
+		for _, k := range wanEdgeRoutingKeys {
+			if v, ok := body[k].(map[string]any); ok {
+				for _, entry := range sortedMapKeys(v) {
+					plan.Details = append(plan.Details, fmt.Sprintf("+ %s.%s", k, entry))
+				}
+			}
+		}
+		plan.Assign = true
+		plan.AssignFrom = siteTemplateID
+		return plan
+	}
+
+	plan.ID, _ = current["id"].(string)
+	plan.Action = fabricUnchanged
+	for _, k := range wanEdgeKeys(body) {
+		if jsonEqual(body[k], current[k]) {
+			continue
+		}
+		plan.Action = fabricUpdate
+		if lines := diffMapEntries(k, body[k], current[k]); lines != nil {
+			plan.Details = append(plan.Details, lines...)
+			continue
+		}
+		plan.Details = append(plan.Details, fmt.Sprintf("~ %s: %s -> %s", k, compactJSON(current[k]), compactJSON(body[k])))
+	}
+	if siteTemplateID != plan.ID {
+		plan.Assign = true
+		plan.AssignFrom = siteTemplateID
+	}
+	return plan
+}
+
+func sortedMapKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// applyWANEdge builds the site's gateway template from its app_policy and
+// traffic_steering templates, creates or updates it in the org, and assigns
+// it to the site. Only service_policies, path_preferences, and the routing
+// keys the site declares are written, so networks and ports configured on
+// the template elsewhere are kept.
+func applyWANEdge(ctx context.Context, client vendors.Client, cfg *config.Config, siteName string, force, diffMode bool) error {
+	lc := legacyClient(client)
+	if lc == nil {
+		return fmt.Errorf("WAN edge policy is only supported for Mist")
+	}
+
+	if cfg.Files.ConfigDir != "" {
+		if err := os.Setenv("CONFIG_DIR", cfg.Files.ConfigDir); err != nil {
+			return fmt.Errorf("error setting CONFIG_DIR environment variable: %v", err)
+		}
+	}
+	siteConfigs, err := getSiteConfigsFromFiles(siteConfigFiles(cfg))
+	if err != nil {
+		return fmt.Errorf("error reading site configurations: %v", err)
+	}
+	siteConfig, found := siteConfigs[siteName]
+	if !found {
+		return fmt.Errorf("site %s not found in configuration files", siteName)
+	}
+	if siteConfig.WANEdge == nil {
+		fmt.Printf("Site %s declares no wan_edge section\n", siteName)
+		return nil
+	}
+
+	store, err := loadTemplatesFromConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to load templates: %w", err)
+	}
+	body, err := buildWANEdgeTemplate(siteConfig.WANEdge, store)
+	if err != nil {
+		return fmt.Errorf("site %s: %w", siteName, err)
+	}
+
+	siteID, err := getSiteIDByName(client, siteName)
+	if err != nil {
+		return fmt.Errorf("error getting site ID for %s: %v", siteName, err)
+	}
+	site, err := lc.GetSite(ctx, siteID)
+	if err != nil {
+		return fmt.Errorf("error getting site %s: %w", siteName, err)
+	}
+	var siteTemplateID string
+	if site.GatewayTemplateID != nil {
+		siteTemplateID = *site.GatewayTemplateID
+	}
+	existing, err := lc.GetGatewayTemplatesRaw(ctx, client.OrgID())
+	if err != nil {
+		return err
+	}
+
+	plan := planWANEdge(body, existing, siteTemplateID)
+
+	marker := map[string]string{fabricCreate: "+", fabricUpdate: "~", fabricUnchanged: "="}[plan.Action]
+	fmt.Printf("WAN edge plan for site %s:\n", siteName)
+	fmt.Printf("  %s gateway template %s: %s\n", marker, plan.Name, plan.Action)
+	for _, d := range plan.Details {
+		fmt.Printf("      %s\n", d)
+	}
+	if plan.Assign {
+		from := plan.AssignFrom
+		if from == "" {
+			from = "(none)"
+		}
+		fmt.Printf("  ~ site assignment: %s -> %s\n", from, plan.Name)
+	}
+
+	if plan.Action == fabricUnchanged && !plan.Assign {
+		fmt.Printf("%s WAN edge policy matches intent\n", symbols.SuccessPrefix())
+		return nil
+	}
+	if diffMode {
+		return nil
+	}
+	if !force && !confirmWANEdgeChange(siteName) {
+		return fmt.Errorf("WAN edge apply cancelled")
+	}
+
+	templateID := plan.ID
+	switch plan.Action {
+	case fabricCreate:
+		logging.Infof("Creating gateway template %s for site %s", plan.Name, siteName)
+		created, err := lc.CreateGatewayTemplate(ctx, client.OrgID(), plan.Body)
+		if err != nil {
+			return fmt.Errorf("gateway template %s: %w", plan.Name, err)
+		}
+		templateID, _ = created["id"].(string)
+		fmt.Printf("%s Gateway template %s: created\n", symbols.SuccessPrefix(), plan.Name)
+	case fabricUpdate:
+		logging.Infof("Updating gateway template %s for site %s", plan.Name, siteName)
+		keys := wanEdgeKeys(plan.Body)
+		update := make(map[string]any, len(keys))
+		for _, k := range keys {
+			update[k] = plan.Body[k]
+		}
+		if _, err := lc.UpdateGatewayTemplate(ctx, client.OrgID(), plan.ID, update); err != nil {
+			return fmt.Errorf("gateway template %s: %w", plan.Name, err)
+		}
+		fmt.Printf("%s Gateway template %s: updated\n", symbols.SuccessPrefix(), plan.Name)
+	}
+
+	if plan.Assign {
+		if templateID == "" {
+			return fmt.Errorf("gateway template %s has no ID; cannot assign it to site %s", plan.Name, siteName)
+		}
+		logging.Infof("Assigning gateway template %s to site %s", plan.Name, siteName)
+		if _, err := lc.UpdateSite(ctx, siteID, &api.MistSite{GatewayTemplateID: &templateID}); err != nil {
+			return fmt.Errorf("assign gateway template %s to site %s: %w", plan.Name, siteName, err)
+		}
+		fmt.Printf("%s Site %s: gateway template %s assigned\n", symbols.SuccessPrefix(), siteName, plan.Name)
+	}
+	return nil
+}
+
+// confirmWANEdgeChange asks a y/N question. --yes approves; --no-input
+// refuses, so unattended runs need 'force'.
+func confirmWANEdgeChange(siteName string) bool {
+	if cmdutils.AssumeYes() {
+		return true
+	}
+	if cmdutils.NoInput() {
+		fmt.Println(i18n.T("wan_edge.no_input"))
+		return false
+	}
+	fmt.Printf("%s %s ", i18n.T("wan_edge.confirm", siteName), i18n.T("prompt.yes_no"))
+	input, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false
+	}
+	return i18n.IsYes(input)
+}
@@ -0,0 +1,338 @@
+package apply
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// WANEdgeRouting is the "routing" block of a site's wan_edge section: the
+// OSPF and BGP underlay for the branch gateway.
+//
+//	"routing": {
+//	  "ospf": {"areas": {"0": {"networks": ["corp"], "passive": ["guest"]}}, "redistribute": ["static"]},
+//	  "bgp": {"local_as": 65010, "via": "wan", "neighbors": {"192.0.2.1": {"neighbor_as": 65000}}, "redistribute": ["connected"]}
+//	}
+type WANEdgeRouting struct {
+	OSPF *OSPFIntent `json:"ospf,omitempty"`
+	BGP  *BGPIntent  `json:"bgp,omitempty"`
+}
+
+// OSPFIntent declares OSPF areas by area ID (integer or dotted form) and the
+// protocols redistributed into OSPF.
+type OSPFIntent struct {
+	Areas              map[string]OSPFArea `json:"areas"`
+	Redistribute       []string            `json:"redistribute,omitempty"`
+	ReferenceBandwidth string              `json:"reference_bandwidth,omitempty"`
+}
+
+// OSPFArea lists the gateway networks in an area. Passive networks are
+// advertised without forming adjacencies.
+type OSPFArea struct {
+	Type     string   `json:"type,omitempty"` // default, stub, or nssa
+	Networks []string `json:"networks"`
+	Passive  []string `json:"passive,omitempty"`
+}
+
+// BGPIntent declares the gateway's BGP peers, the networks it originates,
+// and the protocols redistributed into BGP.
+type BGPIntent struct {
+	LocalAS      int64                  `json:"local_as"`
+	Via          string                 `json:"via,omitempty"` // lan, wan, tunnel, or vpn; default wan
+	Neighbors    map[string]BGPNeighbor `json:"neighbors"`
+	Networks     []string               `json:"networks,omitempty"`
+	Redistribute []string               `json:"redistribute,omitempty"`
+}
+
+// BGPNeighbor is one BGP peer, keyed by its address.
+type BGPNeighbor struct {
+	NeighborAS int64 `json:"neighbor_as"`
+	HoldTime   int   `json:"hold_time,omitempty"`
+}
+
+// Redistribution policy names apply owns in the gateway template's
+// routing_policies.
+const (
+	ospfRedistributePolicy = "wifimgr-ospf-redistribute"
+	bgpRedistributePolicy  = "wifimgr-bgp-redistribute"
+)
+
+// routingProtocols maps redistribute keywords to Mist routing policy
+// protocol names.
+var routingProtocols = map[string]string{
+	"connected": "direct",
+	"static":    "static",
+	"ospf":      "ospf",
+	"bgp":       "bgp",
+}
+
+// normalizeOSPFAreaID accepts an area as an integer ("0") or dotted quad
+// ("0.0.0.0") and returns the dotted form.
+func normalizeOSPFAreaID(id string) (string, bool) {
+	if n, err := strconv.ParseUint(id, 10, 32); err == nil {
+		ip := make(net.IP, 4)
+		binary.BigEndian.PutUint32(ip, uint32(n))
+		return ip.String(), true
+	}
+	if ip := net.ParseIP(id).To4(); ip != nil && strings.Count(id, ".") == 3 {
+		return ip.String(), true
+	}
+	return "", false
+}
+
+func validASN(as int64) bool {
+	return as >= 1 && as <= 4294967295
+}
+
+// validateWANEdgeRouting checks the routing block and returns every problem
+// found, so a turn-up config can be fixed in one pass.
+func validateWANEdgeRouting(r *WANEdgeRouting) []string {
+	var problems []string
+	add := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+	checkRedistribute := func(proto string, list []string) {
+		for _, p := range list {
+			if _, ok := routingProtocols[p]; !ok {
+				add("%s.redistribute: unknown protocol %q (use connected, static, ospf, or bgp)", proto, p)
+			} else if p == proto {
+				add("%s.redistribute: %s cannot redistribute into itself", proto, p)
+			}
+		}
+	}
+
+	if o := r.OSPF; o != nil {
+		if len(o.Areas) == 0 {
+			add("ospf.areas must declare at least one area")
+		}
+		areaOf := make(map[string]string) // network -> area ID
+		dotted := make(map[string]string) // normalized -> declared ID
+		for _, id := range sortedAreaIDs(o.Areas) {
+			area := o.Areas[id]
+			norm, ok := normalizeOSPFAreaID(id)
+			if !ok {
+				add("ospf.areas: invalid area ID %q", id)
+				continue
+			}
+			if prev, dup := dotted[norm]; dup {
+				add("ospf.areas: %q and %q are the same area", prev, id)
+			}
+			dotted[norm] = id
+			switch area.Type {
+			case "", "default":
+			case "stub", "nssa":
+				if norm == "0.0.0.0" {
+					add("ospf.areas[%s]: the backbone area cannot be %s", id, area.Type)
+				}
+			default:
+				add("ospf.areas[%s]: invalid type %q (use default, stub, or nssa)", id, area.Type)
+			}
+			if len(area.Networks) == 0 {
+				add("ospf.areas[%s]: networks must list at least one network", id)
+			}
+			inArea := make(map[string]bool, len(area.Networks))
+			for _, n := range area.Networks {
+				if other, ok := areaOf[n]; ok {
+					add("ospf: network %s is in areas %s and %s", n, other, id)
+				}
+				areaOf[n] = id
+				inArea[n] = true
+			}
+			for _, n := range area.Passive {
+				if !inArea[n] {
+					add("ospf.areas[%s]: passive network %s is not in the area's networks", id, n)
+				}
+			}
+		}
+		checkRedistribute("ospf", o.Redistribute)
+	}
+
+	if b := r.BGP; b != nil {
+		if !validASN(b.LocalAS) {
+			add("bgp.local_as %d is out of range (1-4294967295)", b.LocalAS)
+		}
+		switch b.Via {
+		case "", "lan", "wan", "tunnel", "vpn":
+		default:
+			add("bgp.via: invalid value %q (use lan, wan, tunnel, or vpn)", b.Via)
+		}
+		if len(b.Neighbors) == 0 {
+			add("bgp.neighbors must declare at least one neighbor")
+		}
+		for _, addr := range sortedNeighborAddrs(b.Neighbors) {
+			n := b.Neighbors[addr]
+			if net.ParseIP(addr) == nil {
+				add("bgp.neighbors: %q is not an IP address", addr)
+			}
+			if !validASN(n.NeighborAS) {
+				add("bgp.neighbors[%s]: neighbor_as %d is out of range (1-4294967295)", addr, n.NeighborAS)
+			}
+			if n.HoldTime != 0 && (n.HoldTime < 3 || n.HoldTime > 65535) {
+				add("bgp.neighbors[%s]: hold_time must be 0 or 3-65535", addr)
+			}
+		}
+		checkRedistribute("bgp", b.Redistribute)
+	}
+	return problems
+}
+
+// buildWANEdgeRouting renders a validated routing block as Mist gateway
+// template keys: ospf_config and ospf_areas for OSPF, bgp_config for BGP
+// (one "underlay-ebgp" and/or "underlay-ibgp" group), and routing_policies
+// carrying the redistribution export policies.
+func buildWANEdgeRouting(r *WANEdgeRouting) map[string]any {
+	out := make(map[string]any)
+	policies := make(map[string]any)
+
+	if o := r.OSPF; o != nil {
+		if len(o.Redistribute) > 0 {
+			policies[ospfRedistributePolicy] = redistributePolicy(o.Redistribute)
+		}
+		areas := make(map[string]any, len(o.Areas))
+		for id, area := range o.Areas {
+			norm, _ := normalizeOSPFAreaID(id)
+			passive := make(map[string]bool, len(area.Passive))
+			for _, n := range area.Passive {
+				passive[n] = true
+			}
+			networks := make(map[string]any, len(area.Networks))
+			for _, n := range area.Networks {
+				network := map[string]any{"passive": passive[n]}
+				if len(o.Redistribute) > 0 {
+					network["export_policy"] = ospfRedistributePolicy
+				}
+				networks[n] = network
+			}
+			areaType := area.Type
+			if areaType == "" {
+				areaType = "default"
+			}
+			areas[norm] = map[string]any{"type": areaType, "networks": networks}
+		}
+		ospfConfig := map[string]any{"enabled": true}
+		if o.ReferenceBandwidth != "" {
+			ospfConfig["reference_bandwidth"] = o.ReferenceBandwidth
+		}
+		out["ospf_config"] = ospfConfig
+		out["ospf_areas"] = areas
+	}
+
+	if b := r.BGP; b != nil {
+		if len(b.Redistribute) > 0 {
+			policies[bgpRedistributePolicy] = redistributePolicy(b.Redistribute)
+		}
+		via := b.Via
+		if via == "" {
+			via = "wan"
+		}
+		groups := make(map[string]any)
+		for addr, n := range b.Neighbors {
+			name, peerType := "underlay-ebgp", "external"
+			if n.NeighborAS == b.LocalAS {
+				name, peerType = "underlay-ibgp", "internal"
+			}
+			group, ok := groups[name].(map[string]any)
+			if !ok {
+				group = map[string]any{
+					"type":      peerType,
+					"local_as":  b.LocalAS,
+					"via":       via,
+					"neighbors": map[string]any{},
+				}
+				if len(b.Networks) > 0 {
+					group["networks"] = stringsToAny(b.Networks)
+				}
+				if len(b.Redistribute) > 0 {
+					group["export_policy"] = bgpRedistributePolicy
+				}
+				groups[name] = group
+			}
+			neighbor := map[string]any{"neighbor_as": n.NeighborAS}
+			if n.HoldTime > 0 {
+				neighbor["hold_time"] = n.HoldTime
+			}
+			group["neighbors"].(map[string]any)[addr] = neighbor
+		}
+		out["bgp_config"] = groups
+	}
+
+	if len(policies) > 0 {
+		out["routing_policies"] = policies
+	}
+	return out
+}
+
+// redistributePolicy is a one-term routing policy accepting routes learned
+// from the given protocols.
+func redistributePolicy(protocols []string) map[string]any {
+	mapped := make([]any, 0, len(protocols))
+	for _, p := range protocols {
+		mapped = append(mapped, routingProtocols[p])
+	}
+	return map[string]any{
+		"terms": []any{map[string]any{
+			"name":     "redistribute",
+			"matching": map[string]any{"protocol": mapped},
+			"actions":  map[string]any{"accept": true},
+		}},
+	}
+}
+
+func stringsToAny(list []string) []any {
+	out := make([]any, len(list))
+	for i, s := range list {
+		out[i] = s
+	}
+	return out
+}
+
+func sortedAreaIDs(m map[string]OSPFArea) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedNeighborAddrs(m map[string]BGPNeighbor) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// diffMapEntries lists per-entry changes between two object-valued template
+// keys (e.g. one line per OSPF area or BGP group) instead of one line for the
+// whole key. It returns nil when either side is not an object.
+func diffMapEntries(key string, want, have any) []string {
+	w, ok := want.(map[string]any)
+	if !ok {
+		return nil
+	}
+	h, ok := have.(map[string]any)
+	if !ok && have != nil {
+		return nil
+	}
+	var lines []string
+	for _, k := range sortedMapKeys(w) {
+		old, exists := h[k]
+		switch {
+		case !exists:
+			lines = append(lines, fmt.Sprintf("+ %s.%s: %s", key, k, compactJSON(w[k])))
+		case !jsonEqual(w[k], old):
+			lines = append(lines, fmt.Sprintf("~ %s.%s: %s -> %s", key, k, compactJSON(old), compactJSON(w[k])))
+		}
+	}
+	for _, k := range sortedMapKeys(h) {
+		if _, ok := w[k]; !ok {
+			lines = append(lines, fmt.Sprintf("- %s.%s", key, k))
+		}
+	}
+	return lines
+}
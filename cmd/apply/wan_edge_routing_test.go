@@ -0,0 +1,133 @@
+package apply
+
+import (
+	"strings"
+	"testing"
+)
+
+func branchRouting() *WANEdgeRouting {
+	return &WANEdgeRouting{
+		OSPF: &OSPFIntent{
+			Areas: map[string]OSPFArea{
+				"0":  {Networks: []string{"corp", "guest"}, Passive: []string{"guest"}},
+				"10": {Type: "stub", Networks: []string{"iot"}},
+			},
+			Redistribute: []string{"static"},
+		},
+		BGP: &BGPIntent{
+			LocalAS: 65010,
+			Neighbors: map[string]BGPNeighbor{
+				"192.0.2.1": {NeighborAS: 65000, HoldTime: 90},
+				"10.0.0.2":  {NeighborAS: 65010},
+			},
+			Networks:     []string{"corp"},
+			Redistribute: []string{"connected", "ospf"},
+		},
+	}
+}
+
+func TestValidateWANEdgeRouting(t *testing.T) {
+	if problems := validateWANEdgeRouting(branchRouting()); len(problems) != 0 {
+		t.Fatalf("valid routing reported problems: %v", problems)
+	}
+
+	r := &WANEdgeRouting{
+		OSPF: &OSPFIntent{
+			Areas: map[string]OSPFArea{
+				"0":       {Type: "stub", Networks: []string{"corp"}, Passive: []string{"lab"}},
+				"0.0.0.0": {Networks: []string{"corp"}},
+				"bogus":   {Networks: []string{"x"}},
+			},
+			Redistribute: []string{"ospf", "rip"},
+		},
+		BGP: &BGPIntent{
+			LocalAS:   0,
+			Via:       "mpls",
+			Neighbors: map[string]BGPNeighbor{"not-an-ip": {NeighborAS: 65000, HoldTime: 1}},
+		},
+	}
+	got := strings.Join(validateWANEdgeRouting(r), "\n")
+	for _, want := range []string{
+		"backbone area cannot be stub",
+		`"0" and "0.0.0.0" are the same area`,
+		"network corp is in areas",
+		"passive network lab",
+		`invalid area ID "bogus"`,
+		"ospf cannot redistribute into itself",
+		`unknown protocol "rip"`,
+		"bgp.local_as 0 is out of range",
+		`invalid value "mpls"`,
+		`"not-an-ip" is not an IP address`,
+		"hold_time must be 0 or 3-65535",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing problem %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestBuildWANEdgeRouting(t *testing.T) {
+	out := buildWANEdgeRouting(branchRouting())
+
+	areas := out["ospf_areas"].(map[string]any)
+	backbone, ok := areas["0.0.0.0"].(map[string]any)
+	if !ok {
+		t.Fatalf("area 0 should be keyed in dotted form: %v", areas)
+	}
+	guest := backbone["networks"].(map[string]any)["guest"].(map[string]any)
+	if guest["passive"] != true || guest["export_policy"] != ospfRedistributePolicy {
+		t.Errorf("guest network = %v", guest)
+	}
+	if areas["0.0.0.10"].(map[string]any)["type"] != "stub" {
+		t.Errorf("area 10 type = %v", areas["0.0.0.10"])
+	}
+
+	groups := out["bgp_config"].(map[string]any)
+	ebgp := groups["underlay-ebgp"].(map[string]any)
+	if ebgp["type"] != "external" || ebgp["via"] != "wan" || ebgp["export_policy"] != bgpRedistributePolicy {
+		t.Errorf("ebgp group = %v", ebgp)
+	}
+	if _, ok := ebgp["neighbors"].(map[string]any)["192.0.2.1"]; !ok {
+		t.Errorf("ebgp neighbors = %v", ebgp["neighbors"])
+	}
+	if groups["underlay-ibgp"].(map[string]any)["type"] != "internal" {
+		t.Errorf("same-AS neighbor should be in an internal group: %v", groups)
+	}
+
+	policy := out["routing_policies"].(map[string]any)[bgpRedistributePolicy].(map[string]any)
+	term := policy["terms"].([]any)[0].(map[string]any)
+	protocols := term["matching"].(map[string]any)["protocol"].([]any)
+	if len(protocols) != 2 || protocols[0] != "direct" {
+		t.Errorf("connected should map to direct: %v", protocols)
+	}
+}
+
+func TestPlanWANEdge_RoutingDiff(t *testing.T) {
+	wan := &WANEdgeConfig{GatewayTemplate: "branch-wan", Routing: branchRouting()}
+	body, err := buildWANEdgeTemplate(wan, wanEdgeStore())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	existing := []map[string]any{{"id": "gt-1", "name": "branch-wan"}}
+	for _, k := range wanEdgeKeys(body) {
+		existing[0][k] = body[k]
+	}
+	existing[0]["ospf_areas"] = map[string]any{
+		"0.0.0.0":  body["ospf_areas"].(map[string]any)["0.0.0.0"],
+		"0.0.0.99": map[string]any{"type": "default"},
+	}
+	plan := planWANEdge(body, existing, "gt-1")
+	got := strings.Join(plan.Details, "\n")
+	if plan.Action != fabricUpdate || !strings.Contains(got, "+ ospf_areas.0.0.0.10") || !strings.Contains(got, "- ospf_areas.0.0.0.99") {
+		t.Errorf("got %s:\n%s", plan.Action, got)
+	}
+	if strings.Contains(got, "0.0.0.0:") {
+		t.Errorf("unchanged area should not be listed:\n%s", got)
+	}
+
+	wan.Routing.BGP.LocalAS = 0
+	if _, err := buildWANEdgeTemplate(wan, wanEdgeStore()); err == nil || !strings.Contains(err.Error(), "invalid wan_edge.routing") {
+		t.Errorf("got %v, want routing validation error", err)
+	}
+}
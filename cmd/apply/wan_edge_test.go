@@ -0,0 +1,121 @@
+package apply
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ravinald/wifimgr/internal/config"
+)
+
+func wanEdgeStore() *config.TemplateStore {
+	store := config.NewTemplateStore()
+	store.AppPolicy["guest-internet"] = map[string]any{
+		"tenants":         []any{"guest"},
+		"services":        []any{"any"},
+		"action":          "allow",
+		"path_preference": "internet-first",
+		"mist:":           map[string]any{"idp": map[string]any{"enabled": true}},
+	}
+	store.AppPolicy["corp-to-dc"] = map[string]any{"name": "corp-dc", "action": "allow", "path_preference": "mpls-first"}
+	store.TrafficSteering["internet-first"] = map[string]any{"strategy": "ordered", "paths": []any{map[string]any{"type": "wan", "name": "wan0"}}}
+	store.TrafficSteering["mpls-first"] = map[string]any{"strategy": "ordered"}
+	return store
+}
+
+func TestBuildWANEdgeTemplate(t *testing.T) {
+	wan := &WANEdgeConfig{
+		GatewayTemplate: "branch-wan",
+		AppPolicies:     []string{"guest-internet", "corp-to-dc"},
+		TrafficSteering: []string{"internet-first", "mpls-first"},
+	}
+	body, err := buildWANEdgeTemplate(wan, wanEdgeStore())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body["name"] != "branch-wan" {
+		t.Errorf("name = %v, want branch-wan", body["name"])
+	}
+	policies := body["service_policies"].([]any)
+	if len(policies) != 2 {
+		t.Fatalf("want 2 service policies, got %d", len(policies))
+	}
+	first := policies[0].(map[string]any)
+	if first["name"] != "guest-internet" {
+		t.Errorf("policy name defaults to label: got %v", first["name"])
+	}
+	if _, ok := first["idp"]; !ok {
+		t.Error("mist: block should be merged into the policy")
+	}
+	if _, ok := first["mist:"]; ok {
+		t.Error("vendor block key must not be sent")
+	}
+	if second := policies[1].(map[string]any); second["name"] != "corp-dc" {
+		t.Errorf("explicit policy name must be kept: got %v", second["name"])
+	}
+	if _, ok := body["path_preferences"].(map[string]any)["mpls-first"]; !ok {
+		t.Error("path_preferences should be keyed by traffic_steering label")
+	}
+}
+
+func TestBuildWANEdgeTemplate_Errors(t *testing.T) {
+	cases := map[string]struct {
+		wan  WANEdgeConfig
+		want string
+	}{
+		"no template name":  {WANEdgeConfig{AppPolicies: []string{"corp-to-dc"}}, "gateway_template is required"},
+		"unknown policy":    {WANEdgeConfig{GatewayTemplate: "t", AppPolicies: []string{"nope"}}, "app_policy template 'nope' not found"},
+		"unknown steering":  {WANEdgeConfig{GatewayTemplate: "t", TrafficSteering: []string{"nope"}}, "traffic_steering template 'nope' not found"},
+		"dangling path ref": {WANEdgeConfig{GatewayTemplate: "t", AppPolicies: []string{"corp-to-dc"}}, "not in wan_edge.traffic_steering"},
+		"duplicate policy": {WANEdgeConfig{GatewayTemplate: "t", AppPolicies: []string{"corp-to-dc", "corp-to-dc"},
+			TrafficSteering: []string{"mpls-first"}}, "listed more than once"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := buildWANEdgeTemplate(&tc.wan, wanEdgeStore())
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("got %v, want error containing %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestPlanWANEdge(t *testing.T) {
+	body, err := buildWANEdgeTemplate(&WANEdgeConfig{
+		GatewayTemplate: "branch-wan",
+		AppPolicies:     []string{"corp-to-dc"},
+		TrafficSteering: []string{"mpls-first"},
+	}, wanEdgeStore())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plan := planWANEdge(body, nil, "")
+	if plan.Action != fabricCreate || !plan.Assign {
+		t.Errorf("missing template: got %s assign=%v, want create and assign", plan.Action, plan.Assign)
+	}
+
+	existing := []map[string]any{{
+		"id":   "gt-1",
+		"name": "branch-wan",
+		"service_policies": []any{
+			map[string]any{"name": "corp-dc", "action": "allow", "path_preference": "mpls-first"},
+		},
+		"path_preferences": map[string]any{"mpls-first": map[string]any{"strategy": "ordered"}},
+		"networks":         map[string]any{"corp": map[string]any{}},
+	}}
+	plan = planWANEdge(body, existing, "gt-1")
+	if plan.Action != fabricUnchanged || plan.Assign {
+		t.Errorf("matching template: got %s assign=%v %v, want unchanged", plan.Action, plan.Assign, plan.Details)
+	}
+
+	plan = planWANEdge(body, existing, "gt-other")
+	if plan.Action != fabricUnchanged || !plan.Assign || plan.AssignFrom != "gt-other" {
+		t.Errorf("reassignment: got %s assign=%v from=%q", plan.Action, plan.Assign, plan.AssignFrom)
+	}
+
+	existing[0]["service_policies"] = []any{map[string]any{"name": "corp-dc", "action": "deny", "path_preference": "mpls-first"}}
+	plan = planWANEdge(body, existing, "gt-1")
+	if plan.Action != fabricUpdate || len(plan.Details) != 1 || !strings.Contains(plan.Details[0], "service_policies") {
+		t.Errorf("changed policy: got %s %v, want one service_policies update", plan.Action, plan.Details)
+	}
+}
@@ -0,0 +1,186 @@
+package apply
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/ravinald/wifimgr/api"
+	configPkg "github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/logging"
+	"github.com/ravinald/wifimgr/internal/macaddr"
+	"github.com/ravinald/wifimgr/internal/symbols"
+	"github.com/ravinald/wifimgr/internal/vendors"
+	"github.com/ravinald/wifimgr/internal/warnings"
+)
+
+// expandSiteWLAN expands a WLAN template for vendor with the site's overrides
+// applied. A WLAN listed in wlan_disabled is expanded with enabled false.
+func expandSiteWLAN(templates *configPkg.TemplateStore, siteConfig SiteConfig, label, vendor string) (map[string]any, bool) {
+	template, found := templates.GetWLANTemplate(label)
+	if !found {
+		return nil, false
+	}
+	// Expand for vendor (handles mist:/meraki: blocks)
+	expanded := configPkg.ExpandForVendor(template, vendor)
+	// Per-site exceptions go over the template; wlan_disabled still wins
+	if override, ok := siteConfig.WLANOverrides[label]; ok {
+		expanded = configPkg.ApplyWLANOverride(expanded, override, vendor)
+		logging.Debugf("Applied site override to WLAN '%s': %v", label, configPkg.WLANOverridePaths(override))
+	}
+	// A site can hold a shared WLAN off without editing the template
+	if slices.Contains(siteConfig.WLANDisabled, label) {
+		expanded["enabled"] = false
+	}
+	return expanded, true
+}
+
+// resolveWLANAPIDs maps the MACs of the APs a WLAN targets to AP IDs, taking
+// fresh (MAC to ID, as just read from the API) over the cache. MACs that do
+// not resolve are warned about, except those in pending, which are still
+// being assigned to the site.
+func resolveWLANAPIDs(ctx context.Context, templateLabel string, macs []string, fresh map[string]string, pending map[string]bool) []string {
+	warn := warnings.FromContext(ctx)
+	accessor := vendors.GetGlobalCacheAccessor()
+	if accessor == nil && fresh == nil {
+		warn.Add("WLANs", templateLabel, "cache accessor not available, cannot resolve MACs to AP IDs")
+		return nil
+	}
+
+	var apIDs []string
+	for _, mac := range macs {
+		if id := fresh[mac]; id != "" {
+			apIDs = append(apIDs, id)
+			logging.Debugf("WLAN '%s': resolved MAC %s to AP ID %s from the API", templateLabel, mac, id)
+			continue
+		}
+		var err error
+		if accessor != nil {
+			var ap *vendors.InventoryItem
+			ap, err = accessor.GetAPByMAC(mac)
+			if err == nil && ap != nil && ap.ID != "" {
+				apIDs = append(apIDs, ap.ID)
+				logging.Debugf("WLAN '%s': resolved MAC %s to AP ID %s", templateLabel, mac, ap.ID)
+				continue
+			}
+		}
+		if pending[mac] {
+			logging.Debugf("WLAN '%s': AP %s is being assigned; its ID is added after assignment", templateLabel, mac)
+			continue
+		}
+		warn.Add("WLANs", templateLabel, "could not resolve MAC %s to an AP ID: %v", mac, err)
+	}
+	return apIDs
+}
+
+// freshAPIDs reads the site's APs from the API and maps each MAC to its ID.
+// The cache predates the run's assignments, so it cannot name new APs.
+func freshAPIDs(ctx context.Context, client vendors.Client, siteID string) (map[string]string, error) {
+	devices, err := client.Devices().List(ctx, siteID, "ap")
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[string]string, len(devices))
+	for _, d := range devices {
+		if mac := macaddr.NormalizeOrEmpty(d.MAC); mac != "" && d.ID != "" {
+			ids[mac] = d.ID
+		}
+	}
+	return ids, nil
+}
+
+// reconcileWLANAPIDs runs after APs are assigned to a site. WLANs that target
+// APs by ap_ids were pushed before the assignment, when the new APs had no
+// ID to list, so each WLAN naming an assigned AP is re-resolved against the
+// site's APs as the API now reports them and patched when its ap_ids change.
+// Problems are warnings: the assignment itself has succeeded. Returns the
+// number of WLANs patched.
+func reconcileWLANAPIDs(ctx context.Context, client vendors.Client, siteConfig SiteConfig, siteID, apiLabel string, assigned []string) int {
+	warn := warnings.FromContext(ctx)
+	summary := summaryFromContext(ctx)
+	siteName := siteNameFromConfig(siteConfig)
+
+	// Meraki scopes SSIDs by availability tags, not AP IDs
+	lc := legacyClient(client)
+	if lc == nil || len(assigned) == 0 {
+		return 0
+	}
+
+	wlanToDevices := collectWLANDeviceMapping(siteConfig)
+	var labels []string
+	for label, macs := range wlanToDevices {
+		if slices.ContainsFunc(macs, func(mac string) bool { return slices.Contains(assigned, mac) }) {
+			labels = append(labels, label)
+		}
+	}
+	if len(labels) == 0 {
+		return 0
+	}
+	slices.Sort(labels)
+	logging.Infof("Refreshing ap_ids of %d WLAN(s) after assigning %d AP(s)", len(labels), len(assigned))
+
+	fresh, err := freshAPIDs(ctx, client, siteID)
+	if err != nil {
+		warn.Add("WLANs", "", "could not refresh AP IDs after assignment (%v); using the cache", err)
+	}
+
+	templates, templateAPILabel := getTemplateStore()
+	if templates == nil {
+		return 0
+	}
+	vendor := configPkg.GetVendorFromAPILabel(apiLabel)
+	if vendor == "" {
+		vendor = configPkg.GetVendorFromAPILabel(templateAPILabel)
+	}
+
+	existingWLANs, err := lc.GetSiteWLANs(ctx, siteID)
+	if err != nil {
+		warn.Add("WLANs", "", "failed to get WLANs for site %s after assignment: %v", siteID, err)
+		return 0
+	}
+	existingBySSID := make(map[string]*api.MistWLAN, len(existingWLANs))
+	for i := range existingWLANs {
+		if w := &existingWLANs[i]; w.SSID != nil {
+			existingBySSID[*w.SSID] = w
+		}
+	}
+
+	patched := 0
+	for _, label := range labels {
+		desired, ok := expandSiteWLAN(templates, siteConfig, label, vendor)
+		if !ok {
+			continue
+		}
+		ssid, _ := desired["ssid"].(string)
+		existing, ok := existingBySSID[ssid]
+		if !ok || existing.ID == nil {
+			warn.Add("WLANs", label, "WLAN '%s' not found after assignment; ap_ids not refreshed", ssid)
+			continue
+		}
+		apIDs := resolveWLANAPIDs(ctx, label, wlanToDevices[label], fresh, nil)
+		if len(apIDs) == 0 {
+			continue
+		}
+		desired["ap_ids"] = apIDs
+		desired["apply_to"] = "aps"
+		if !wlanNeedsUpdate(existing, desired) {
+			logging.Debugf("WLAN '%s' already lists every assigned AP", ssid)
+			continue
+		}
+
+		stepStart := time.Now()
+		err := updateWLAN(ctx, lc, siteID, *existing.ID, desired)
+		summary.timed(sectionWLANs, stepStart)
+		if err != nil {
+			logging.Errorf("Failed to refresh ap_ids of WLAN '%s': %v", ssid, err)
+			printWLANError("update", ssid, label, desired, err)
+			summary.add(sectionWLANs, wlanEntry(siteName, ssid, label, resultFailed, "ap_ids", ""))
+			continue
+		}
+		summary.add(sectionWLANs, wlanEntry(siteName, ssid, label, resultDone, "ap_ids",
+			fmt.Sprintf("%s Refreshed ap_ids of WLAN '%s' (%d AP(s))", symbols.SuccessPrefix(), ssid, len(apIDs))))
+		patched++
+	}
+	return patched
+}
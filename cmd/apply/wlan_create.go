@@ -0,0 +1,53 @@
+package apply
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+// CreateSiteWLAN creates one WLAN at a site from an expanded WLAN template and
+// returns the vendor's ID for it. Mist WLANs are built the way apply builds
+// them, so every template field reaches the API; other vendors go through the
+// agnostic WLANs().Create, broadcasting on all APs unless the template's
+// vendor block says otherwise.
+func CreateSiteWLAN(ctx context.Context, client vendors.Client, siteID string, cfg map[string]any) (string, error) {
+	if lc := legacyClient(client); lc != nil {
+		created, err := lc.CreateSiteWLAN(ctx, siteID, buildMistWLANFromConfig(cfg))
+		if err != nil {
+			return "", err
+		}
+		if created == nil || created.ID == nil {
+			return "", fmt.Errorf("the API returned no ID for the new WLAN")
+		}
+		return *created.ID, nil
+	}
+
+	svc := client.WLANs()
+	if svc == nil {
+		return "", fmt.Errorf("WLANs are not available with this API (%s)", client.VendorName())
+	}
+	wlan := buildVendorWLANFromConfig(cfg, siteID)
+	if _, set := wlan.Config["availableOnAllAps"]; !set {
+		wlan.Config["availableOnAllAps"] = len(extractStringSliceFromConfig(wlan.Config, "availabilityTags")) == 0
+	}
+	created, err := svc.Create(ctx, wlan)
+	if err != nil {
+		return "", err
+	}
+	return created.ID, nil
+}
+
+// DeleteSiteWLAN removes a site WLAN created by CreateSiteWLAN. On Meraki the
+// SSID slot is reset, since SSIDs cannot be deleted there.
+func DeleteSiteWLAN(ctx context.Context, client vendors.Client, siteID, id string) error {
+	if lc := legacyClient(client); lc != nil {
+		return lc.DeleteSiteWLAN(ctx, siteID, id)
+	}
+	svc := client.WLANs()
+	if svc == nil {
+		return fmt.Errorf("WLANs are not available with this API (%s)", client.VendorName())
+	}
+	return svc.Delete(ctx, id)
+}
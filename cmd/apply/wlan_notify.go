@@ -0,0 +1,82 @@
+package apply
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	configPkg "github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/integrations"
+	"github.com/ravinald/wifimgr/internal/logging"
+	"github.com/ravinald/wifimgr/internal/notify"
+	"github.com/ravinald/wifimgr/internal/qrcode"
+)
+
+// wlanAnnouncement builds the end-user announcement for a WLAN change apply
+// just pushed. Disabled WLANs and auth types users cannot join with a
+// passphrase (enterprise) get none.
+func wlanAnnouncement(kind, previousSSID string, desired map[string]any) (notify.Announcement, bool) {
+	if enabled, ok := desired["enabled"].(bool); ok && !enabled {
+		return notify.Announcement{}, false
+	}
+	wifi, err := qrcode.WiFiFromWLAN(desired)
+	if err != nil {
+		logging.Debugf("No announcement for WLAN %v: %v", desired["ssid"], err)
+		return notify.Announcement{}, false
+	}
+	if wifi.Passphrase, err = configPkg.DecryptIfNeeded(wifi.Passphrase, "wlan.auth.psk"); err != nil {
+		logging.Warnf("No announcement for WLAN '%s': %v", wifi.SSID, err)
+		return notify.Announcement{}, false
+	}
+	return notify.Announcement{Kind: kind, SSID: wifi.SSID, PreviousSSID: previousSSID, WiFi: wifi}, true
+}
+
+// desiredPSK returns the plaintext passphrase of an expanded WLAN config, or
+// "" when it has none or it cannot be decrypted.
+func desiredPSK(desired map[string]any) string {
+	auth, _ := desired["auth"].(map[string]any)
+	psk, _ := auth["psk"].(string)
+	plain, err := configPkg.DecryptIfNeeded(psk, "wlan.auth.psk")
+	if err != nil {
+		return ""
+	}
+	return plain
+}
+
+// pskChanged reports whether a WLAN's passphrase differs from the live one.
+// A live passphrase the API did not return, or returned masked, is unknown
+// and never counts as a change.
+func pskChanged(existing, desired string) bool {
+	if desired == "" || strings.Trim(existing, "*") == "" {
+		return false
+	}
+	return existing != desired
+}
+
+// notifyWLANChanges sends one notification per change to the integrations
+// (notify_users writes the end-user announcement). Delivery is best effort:
+// the WLANs are already live.
+func notifyWLANChanges(ctx context.Context, cfg *configPkg.Config, siteName string, announcements []notify.Announcement) {
+	configDir := ""
+	if cfg != nil {
+		configDir = cfg.Files.ConfigDir
+	}
+	now := time.Now()
+	for _, a := range announcements {
+		a.Site = siteName
+		a.Effective = now
+		details := map[string]any{"change": a.Kind, "ssid": a.SSID}
+		if a.PreviousSSID != "" {
+			details["previous_ssid"] = a.PreviousSSID
+		}
+		integrations.Notify(ctx, integrations.Notification{
+			Kind:      integrations.NotifyWLANChange,
+			Site:      siteName,
+			Subject:   a.Subject(),
+			Time:      now,
+			Data:      a,
+			Details:   details,
+			ConfigDir: configDir,
+		})
+	}
+}
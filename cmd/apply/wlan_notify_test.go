@@ -0,0 +1,45 @@
+package apply
+
+import (
+	"testing"
+
+	"github.com/ravinald/wifimgr/internal/notify"
+)
+
+func TestPSKChanged(t *testing.T) {
+	tests := []struct {
+		existing, desired string
+		want              bool
+	}{
+		{"old-pass", "new-pass", true},
+		{"same", "same", false},
+		{"", "new-pass", false},         // API did not return the live passphrase
+		{"********", "new-pass", false}, // masked
+		{"old-pass", "", false},         // not a PSK WLAN
+	}
+	for _, tt := range tests {
+		if got := pskChanged(tt.existing, tt.desired); got != tt.want {
+			t.Errorf("pskChanged(%q, %q) = %v, want %v", tt.existing, tt.desired, got, tt.want)
+		}
+	}
+}
+
+func TestWLANAnnouncement(t *testing.T) {
+	psk := map[string]any{"ssid": "Corp", "auth": map[string]any{"type": "psk", "psk": "pass1234"}}
+	a, ok := wlanAnnouncement(notify.KindRenamed, "Old", psk)
+	if !ok {
+		t.Fatal("expected an announcement for a PSK WLAN")
+	}
+	if a.SSID != "Corp" || a.PreviousSSID != "Old" || a.WiFi.Passphrase != "pass1234" || a.Kind != notify.KindRenamed {
+		t.Errorf("unexpected announcement: %+v", a)
+	}
+
+	disabled := map[string]any{"ssid": "Corp", "enabled": false}
+	if _, ok := wlanAnnouncement(notify.KindNewSSID, "", disabled); ok {
+		t.Error("expected no announcement for a disabled WLAN")
+	}
+	enterprise := map[string]any{"ssid": "Corp", "auth": map[string]any{"type": "eap"}}
+	if _, ok := wlanAnnouncement(notify.KindNewSSID, "", enterprise); ok {
+		t.Error("expected no announcement for an enterprise WLAN")
+	}
+}
@@ -29,7 +29,7 @@ import (
 
 // applyRollbackCmd represents the "apply rollback" command
 var applyRollbackCmd = &cobra.Command{
-	Use:   "rollback <site-name> [backup-index]",
+	Use:   "rollback <site-name> [backup-index] [force]",
 	Short: "Restore intent config from a backup (file-based, does NOT apply to API)",
 	Long: `Restore the intent configuration file from a backup.
 
@@ -37,6 +37,15 @@ This is a FILE-BASED operation that does NOT send anything to the API.
 The current config becomes the new .0 backup, and the selected backup
 becomes the current intent config.
 
+When an older backup (index 1 or more) is restored and the config has been
+edited since the most recent backup (.0), those edits are not overwritten:
+each site block is merged three ways, with .0 as the common ancestor. Changes
+between the selected backup and .0 are undone, and newer edits — to other
+devices or other sites — are kept. Where both changed the same field, you
+choose per site block: take the backup, keep the current value, or decide
+field by field. 'force' takes the backup for every conflict; with --no-input
+and no 'force' a conflicting rollback is refused.
+
 After rollback, you can:
   - Review the restored config
   - Edit if needed
@@ -45,19 +54,21 @@ After rollback, you can:
 
 Arguments:
   backup-index - The backup index to restore (default: 0 = most recent)
+  force        - Take the backup's value for every merge conflict
 
 Examples:
   wifimgr apply rollback US-SFO-LAB      - Restore from most recent backup (.0)
   wifimgr apply rollback US-SFO-LAB 1    - Restore from second most recent (.1)
-  wifimgr apply rollback US-SFO-LAB 2    - Restore from third most recent (.2)`,
+  wifimgr apply rollback US-SFO-LAB 2    - Restore from third most recent (.2)
+  wifimgr apply rollback US-SFO-LAB 2 force`,
 	Args: func(cmd *cobra.Command, args []string) error {
 		for _, arg := range args {
 			if strings.ToLower(arg) == "help" {
 				return nil
 			}
 		}
-		if len(args) < 1 || len(args) > 2 {
-			return fmt.Errorf("accepts between 1 and 2 arg(s), received %d", len(args))
+		if len(args) < 1 || len(args) > 3 {
+			return fmt.Errorf("accepts between 1 and 3 arg(s), received %d", len(args))
 		}
 		return nil
 	},
@@ -69,14 +80,58 @@ Examples:
 		}
 		// Create legacy args format
 		legacyArgs := []string{args[0], "rollback"}
-		if len(args) > 1 {
-			legacyArgs = append(legacyArgs, args[1])
+		for _, arg := range args[1:] {
+			if strings.ToLower(arg) == "force" {
+				legacyArgs = append(legacyArgs, "force")
+				continue
+			}
+			if _, err := strconv.Atoi(arg); err != nil {
+				return fmt.Errorf("unexpected argument %q (expected a backup index or force)", arg)
+			}
+			legacyArgs = append(legacyArgs, arg)
 		}
 
 		return apply.HandleCommand(globalContext, vendorClientForApply(""), globalConfig, legacyArgs, "", false)
 	},
 }
 
+// applyRestoreSiteCmd represents the "apply restore-site" command
+var applyRestoreSiteCmd = &cobra.Command{
+	Use:   "restore-site <site-name> from <backup-file>",
+	Short: "Restore one site's intent config from a backup (does NOT apply to API)",
+	Long: `Restore a single site's block from a backup into the current config file.
+
+Rolling back a whole config file also reverts every other site edited since
+the backup. restore-site takes only the named site's block from the backup
+(a whole-file or a site-scoped backup) and replaces that site in the current
+file; all other sites are left as they are. The current state is backed up
+first, so the restore can itself be rolled back.
+
+This is a FILE-BASED operation that does NOT send anything to the API.
+
+The backup file is looked up in the current directory, then in the backups
+directory (see 'apply list-backups').
+
+Examples:
+  wifimgr apply restore-site US-SFO-LAB from us-sites.json.3`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if cmdutils.ContainsHelp(args) {
+			return nil
+		}
+		if len(args) != 3 || args[1] != "from" {
+			return fmt.Errorf("usage: apply restore-site <site-name> from <backup-file>")
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if cmdutils.ContainsHelp(args) {
+			return cmd.Help()
+		}
+		legacyArgs := []string{args[0], "restore-site", args[1], args[2]}
+		return apply.HandleCommand(globalContext, vendorClientForApply(""), globalConfig, legacyArgs, "", false)
+	},
+}
+
 // applyListBackupsCmd represents the "apply list-backups" command
 var applyListBackupsCmd = &cobra.Command{
 	Use:   "list-backups <site-name>",
@@ -189,6 +244,7 @@ Example:
 func init() {
 	// Add backup subcommands to apply
 	applyCmd.AddCommand(applyRollbackCmd)
+	applyCmd.AddCommand(applyRestoreSiteCmd)
 	applyCmd.AddCommand(applyListBackupsCmd)
 	applyCmd.AddCommand(applyCleanupBackupsCmd)
 	applyCmd.AddCommand(applyValidateBackupCmd)
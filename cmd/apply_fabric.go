@@ -0,0 +1,99 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ravinald/wifimgr/cmd/apply"
+)
+
+// applyFabricCmd represents the "apply fabric" command
+var applyFabricCmd = &cobra.Command{
+	Use:   "fabric <site-name> [diff] [force]",
+	Short: "Apply campus fabric (EVPN topology) configuration",
+	Long: `Reconcile the campus fabric declared in a site's "fabric" section with the
+Mist EVPN topologies for that site (or the org, for topologies with
+"scope": "org").
+
+The full plan is always shown first: topologies to create or update, switches
+joining or leaving, and role or option changes. Topologies that exist in Mist
+but are not declared are reported as unchanged and never deleted.
+
+Fabric changes re-provision EVPN on every member switch and can interrupt
+traffic, so applying asks you to type the site name back. Use 'force' to skip
+the prompt in automation; --yes does not bypass it.
+
+Arguments:
+  site-name - The name of the site whose fabric to apply
+  diff      - Show the plan without applying it (optional)
+  force     - Apply without the typed confirmation (optional)
+
+Examples:
+  wifimgr apply fabric US-LAB-01 diff    - Preview fabric changes
+  wifimgr apply fabric US-LAB-01         - Apply after confirmation
+  wifimgr apply fabric US-LAB-01 force   - Apply without prompting`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		for _, arg := range args {
+			if strings.ToLower(arg) == "help" {
+				return nil
+			}
+		}
+		if len(args) < 1 || len(args) > 3 {
+			return fmt.Errorf("accepts between 1 and 3 arg(s), received %d", len(args))
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		for _, arg := range args {
+			if strings.ToLower(arg) == "help" {
+				return cmd.Help()
+			}
+		}
+
+		siteName := args[0]
+		diffMode := false
+		force := false
+		for _, arg := range args[1:] {
+			switch strings.ToLower(arg) {
+			case "diff":
+				diffMode = true
+			case "force":
+				force = true
+			default:
+				return fmt.Errorf("unknown argument %q (expected diff or force)", arg)
+			}
+		}
+
+		apiLabel, err := ValidateMultiVendorApply(globalContext, siteName, nil)
+		if err != nil {
+			return err
+		}
+
+		legacyArgs := []string{siteName, "fabric"}
+		if diffMode {
+			legacyArgs = append(legacyArgs, "diff")
+		}
+		return apply.HandleCommand(globalContext, vendorClientForApply(apiLabel), globalConfig, legacyArgs, apiLabel, force)
+	},
+}
+
+func init() {
+	applyCmd.AddCommand(applyFabricCmd)
+}
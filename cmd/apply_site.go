@@ -75,6 +75,9 @@ Examples:
 		if !validTypes[deviceType] {
 			return fmt.Errorf("invalid device type: %s. Valid types: ap, switch, gateway, all", deviceType)
 		}
+		if applyExplain {
+			return explainApplyOrder(deviceType)
+		}
 
 		// Validate and resolve API for this site
 		apiLabel, err := ValidateMultiVendorApply(globalContext, siteName, nil)
@@ -147,6 +150,9 @@ Options:
 		siteName := args[0]
 		opts := cmdutils.ParseApplyOptions(args[1:])
 		force := opts.Force
+		if applyExplain {
+			return explainApplyOrder("ap")
+		}
 
 		apiLabel, err := ValidateMultiVendorApply(globalContext, siteName, nil)
 		if err != nil {
@@ -210,6 +216,9 @@ Options:
 		siteName := args[0]
 		opts := cmdutils.ParseApplyOptions(args[1:])
 		force := opts.Force
+		if applyExplain {
+			return explainApplyOrder("switch")
+		}
 
 		apiLabel, err := ValidateMultiVendorApply(globalContext, siteName, nil)
 		if err != nil {
@@ -273,6 +282,9 @@ Options:
 		siteName := args[0]
 		opts := cmdutils.ParseApplyOptions(args[1:])
 		force := opts.Force
+		if applyExplain {
+			return explainApplyOrder("gateway")
+		}
 
 		apiLabel, err := ValidateMultiVendorApply(globalContext, siteName, nil)
 		if err != nil {
@@ -341,6 +353,9 @@ Options:
 		siteName := args[0]
 		opts := cmdutils.ParseApplyOptions(args[1:])
 		force := opts.Force
+		if applyExplain {
+			return explainApplyOrder("all")
+		}
 
 		apiLabel, err := ValidateMultiVendorApply(globalContext, siteName, nil)
 		if err != nil {
@@ -0,0 +1,96 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ravinald/wifimgr/cmd/apply"
+)
+
+// applyWANEdgeCmd represents the "apply wan-edge" command
+var applyWANEdgeCmd = &cobra.Command{
+	Use:   "wan-edge <site-name> [diff] [force]",
+	Short: "Apply gateway app policy and traffic steering templates",
+	Long: `Build the Mist WAN Assurance gateway template declared in a site's "wan_edge"
+section from its app_policy and traffic_steering templates, then assign it to
+the site.
+
+The gateway template is created when it does not exist. Only its
+service_policies and path_preferences are written, plus the OSPF/BGP keys
+when wan_edge declares a routing block; networks and ports configured on the
+template elsewhere are kept.
+
+Arguments:
+  site-name - The name of the site whose WAN edge policy to apply
+  diff      - Show the plan without applying it (optional)
+  force     - Apply without confirmation (optional)
+
+Examples:
+  wifimgr apply wan-edge US-LAB-01 diff    - Preview WAN edge changes
+  wifimgr apply wan-edge US-LAB-01         - Apply after confirmation
+  wifimgr apply wan-edge US-LAB-01 force   - Apply without prompting`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		for _, arg := range args {
+			if strings.ToLower(arg) == "help" {
+				return nil
+			}
+		}
+		if len(args) < 1 || len(args) > 3 {
+			return fmt.Errorf("accepts between 1 and 3 arg(s), received %d", len(args))
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		for _, arg := range args {
+			if strings.ToLower(arg) == "help" {
+				return cmd.Help()
+			}
+		}
+
+		siteName := args[0]
+		diffMode := false
+		force := false
+		for _, arg := range args[1:] {
+			switch strings.ToLower(arg) {
+			case "diff":
+				diffMode = true
+			case "force":
+				force = true
+			default:
+				return fmt.Errorf("unknown argument %q (expected diff or force)", arg)
+			}
+		}
+
+		apiLabel, err := ValidateMultiVendorApply(globalContext, siteName, nil)
+		if err != nil {
+			return err
+		}
+
+		legacyArgs := []string{siteName, "wan-edge"}
+		if diffMode {
+			legacyArgs = append(legacyArgs, "diff")
+		}
+		return apply.HandleCommand(globalContext, vendorClientForApply(apiLabel), globalConfig, legacyArgs, apiLabel, force)
+	},
+}
+
+func init() {
+	applyCmd.AddCommand(applyWANEdgeCmd)
+}
@@ -0,0 +1,266 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ravinald/wifimgr/cmd/apply"
+	"github.com/ravinald/wifimgr/internal/approval"
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/history"
+	"github.com/ravinald/wifimgr/internal/logging"
+	"github.com/ravinald/wifimgr/internal/symbols"
+)
+
+// approveCmd executes a pending change bundle on a second operator's say-so.
+var approveCmd = &cobra.Command{
+	Use:   "approve <change-id>",
+	Short: "Approve and execute a pending change to a protected site",
+	Long: `Approve and execute a change held for approval.
+
+Applies to sites matched by approvals.sites are not pushed: apply renders the
+diff and stores a pending change bundle instead. A different operator than the
+one who requested it approves the change here. The diff is rendered again
+first, and if the intent or the live site changed since the request the change
+is marked stale and nothing is pushed.
+
+Bundles are kept under approvals.dir for audit: bundle.json records who
+requested, approved, and applied the change, and diff.txt the approved plan.
+The requester and approver must be different OS accounts; WIFIMGR_OPERATOR,
+when set, is recorded as the display name but does not count as a different
+operator.`,
+	Example: `  wifimgr approve list
+  wifimgr approve show 3fa4c2d1
+  wifimgr approve 3fa4c2d1
+  wifimgr approve reject 3fa4c2d1`,
+	Args: func(_ *cobra.Command, args []string) error {
+		if cmdutils.ContainsHelp(args) {
+			return nil
+		}
+		if len(args) != 1 {
+			return fmt.Errorf("requires a change id (see 'wifimgr approve list')")
+		}
+		return nil
+	},
+	RunE: runApprove,
+}
+
+// approveListCmd represents the "approve list" command
+var approveListCmd = &cobra.Command{
+	Use:   "list [all]",
+	Short: "List pending changes (all: include decided ones)",
+	Annotations: map[string]string{
+		cmdutils.AnnotationNeedsConfig: "true",
+	},
+	Args: func(_ *cobra.Command, args []string) error {
+		if cmdutils.ContainsHelp(args) {
+			return nil
+		}
+		if len(args) > 1 || (len(args) == 1 && strings.ToLower(args[0]) != "all") {
+			return fmt.Errorf("usage: approve list [all]")
+		}
+		return nil
+	},
+	RunE: runApproveList,
+}
+
+// approveShowCmd represents the "approve show" command
+var approveShowCmd = &cobra.Command{
+	Use:   "show <change-id>",
+	Short: "Show a change and its diff",
+	Annotations: map[string]string{
+		cmdutils.AnnotationNeedsConfig: "true",
+	},
+	Args: func(_ *cobra.Command, args []string) error {
+		if cmdutils.ContainsHelp(args) {
+			return nil
+		}
+		if len(args) != 1 {
+			return fmt.Errorf("requires a change id")
+		}
+		return nil
+	},
+	RunE: runApproveShow,
+}
+
+// approveRejectCmd represents the "approve reject" command
+var approveRejectCmd = &cobra.Command{
+	Use:   "reject <change-id>",
+	Short: "Reject a pending change without applying it",
+	Annotations: map[string]string{
+		cmdutils.AnnotationNeedsConfig: "true",
+	},
+	Args: func(_ *cobra.Command, args []string) error {
+		if cmdutils.ContainsHelp(args) {
+			return nil
+		}
+		if len(args) != 1 {
+			return fmt.Errorf("requires a change id")
+		}
+		return nil
+	},
+	RunE: runApproveReject,
+}
+
+func init() {
+	approveCmd.AddCommand(approveListCmd)
+	approveCmd.AddCommand(approveShowCmd)
+	approveCmd.AddCommand(approveRejectCmd)
+	rootCmd.AddCommand(approveCmd)
+}
+
+// approvalsConfig returns the approvals config or an error explaining that
+// no site is protected.
+func approvalsConfig() (*approval.Config, error) {
+	ac := approval.LoadConfig()
+	if ac == nil {
+		return nil, fmt.Errorf("approvals are not enabled (set approvals.sites)")
+	}
+	return ac, nil
+}
+
+func runApprove(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+	ac, err := approvalsConfig()
+	if err != nil {
+		return err
+	}
+	b, err := approval.Load(ac.Dir, cmdutils.StripQuotes(args[0]))
+	if err != nil {
+		return err
+	}
+	approver, account := history.Operator(), history.Account()
+	if err := b.CheckApprover(account); err != nil {
+		return err
+	}
+
+	fmt.Printf("Approving change %s: apply %s via API '%s' (requested by %s)\n",
+		b.ID, strings.Join(b.Args, " "), b.API, b.RequestedBy)
+	if b.API != "" {
+		if err := RefreshSiteForApply(globalContext, b.Site, b.API); err != nil {
+			return err
+		}
+		if len(b.Args) > 1 {
+			switch b.Args[1] {
+			case "ap", "switch", "gateway":
+				if _, err := EnsureDeviceConfigsForSite(globalContext, b.API, b.Site, b.Args[1], nil); err != nil {
+					return fmt.Errorf("failed to fetch device configs: %w", err)
+				}
+			}
+		}
+	}
+
+	if err := apply.ExecuteApproved(globalContext, vendorClientForApply(b.API), globalConfig, ac.Dir, b, approver, account); err != nil {
+		return err
+	}
+	fmt.Printf("%s Change %s applied (requested by %s, approved by %s)\n", symbols.SuccessPrefix(), b.ID, b.RequestedBy, approver)
+	return nil
+}
+
+func runApproveList(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+	ac, err := approvalsConfig()
+	if err != nil {
+		return err
+	}
+	bundles, err := approval.List(ac.Dir)
+	if err != nil {
+		return err
+	}
+	all := len(args) == 1
+
+	shown := 0
+	for _, b := range bundles {
+		if !all && b.Status != approval.StatusPending {
+			continue
+		}
+		if shown == 0 {
+			fmt.Printf("%-8s  %-9s  %-20s  %-12s  %-16s  %s\n", "ID", "STATUS", "SITE", "REQUESTED BY", "REQUESTED", "COMMAND")
+		}
+		fmt.Printf("%-8s  %-9s  %-20s  %-12s  %-16s  %s\n", b.ID, b.Status, b.Site, b.RequestedBy,
+			b.RequestedAt.Local().Format("2006-01-02 15:04"), strings.Join(b.Args[1:], " "))
+		shown++
+	}
+	if shown == 0 {
+		fmt.Printf("%s No pending changes\n", symbols.SuccessPrefix())
+	}
+	return nil
+}
+
+func runApproveShow(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+	ac, err := approvalsConfig()
+	if err != nil {
+		return err
+	}
+	b, err := approval.Load(ac.Dir, cmdutils.StripQuotes(args[0]))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Change:    %s\n", b.ID)
+	fmt.Printf("Status:    %s\n", b.Status)
+	fmt.Printf("Site:      %s (API %s)\n", b.Site, b.API)
+	fmt.Printf("Command:   apply %s\n", strings.Join(b.Args, " "))
+	fmt.Printf("Requested: %s by %s\n", b.RequestedAt.Local().Format("2006-01-02 15:04:05 MST"), b.RequestedBy)
+	if b.ApprovedBy != "" {
+		fmt.Printf("Approved:  %s by %s\n", b.ApprovedAt.Local().Format("2006-01-02 15:04:05 MST"), b.ApprovedBy)
+	}
+	if b.RejectedBy != "" {
+		fmt.Printf("Rejected:  %s by %s\n", b.FinishedAt.Local().Format("2006-01-02 15:04:05 MST"), b.RejectedBy)
+	}
+	if b.Error != "" {
+		fmt.Printf("Error:     %s\n", b.Error)
+	}
+	fmt.Printf("Hash:      %s\n\n", b.Hash)
+	fmt.Print(b.Diff)
+	return nil
+}
+
+func runApproveReject(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+	ac, err := approvalsConfig()
+	if err != nil {
+		return err
+	}
+	b, err := approval.Load(ac.Dir, cmdutils.StripQuotes(args[0]))
+	if err != nil {
+		return err
+	}
+	if b.Status != approval.StatusPending {
+		return fmt.Errorf("change %s is %s, not pending", b.ID, b.Status)
+	}
+
+	b.Reject(history.Operator())
+	if err := approval.Save(ac.Dir, b); err != nil {
+		return err
+	}
+	logging.Infof("Change %s for %s rejected by %s", b.ID, b.Site, b.RejectedBy)
+	fmt.Printf("%s Change %s rejected\n", symbols.SuccessPrefix(), b.ID)
+	return nil
+}
@@ -13,7 +13,8 @@ import (
 var backupCmd = &cobra.Command{
 	Use:   "backup",
 	Short: "Manage configuration backups",
-	Long: `Manage configuration backups including listing and restoring.
+	Long: `Manage configuration backups including listing, restoring, and pushing
+them to a remote S3/GCS bucket (backup.remote).
 
 Examples:
   # List all backups
@@ -24,7 +25,10 @@ Examples:
   wifimgr backup list US-SFO-LAB
 
   # Restore a backup
-  wifimgr backup restore US-SFO-LAB 0`,
+  wifimgr backup restore US-SFO-LAB 0
+
+  # Push local backups and cache snapshots to the backup.remote bucket
+  wifimgr backup push`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Check for help keyword in positional arguments
 		if cmdutils.ContainsHelp(args) {
@@ -36,8 +40,11 @@ Examples:
 			return fmt.Errorf("configuration not loaded")
 		}
 
-		if err := requireMistClient("backup"); err != nil {
-			return err
+		// push only copies local files off-host; it works without a Mist API.
+		if len(args) == 0 || args[0] != "push" {
+			if err := requireMistClient("backup"); err != nil {
+				return err
+			}
 		}
 
 		// Handle the backup command
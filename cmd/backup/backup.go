@@ -14,7 +14,10 @@ import (
 	"github.com/ravinald/wifimgr/api"
 	"github.com/ravinald/wifimgr/internal/config"
 	"github.com/ravinald/wifimgr/internal/formatter"
+	"github.com/ravinald/wifimgr/internal/helpers"
 	"github.com/ravinald/wifimgr/internal/logging"
+	"github.com/ravinald/wifimgr/internal/remotebackup"
+	"github.com/ravinald/wifimgr/internal/vendors"
 )
 
 // BackupInfo represents information about a backup file
@@ -291,7 +294,7 @@ func restoreBackup(cfg *config.Config, siteName string, serial int) error {
 			return fmt.Errorf("failed to read current config file: %w", err)
 		}
 
-		if err := os.WriteFile(currentBackupPath, currentData, 0600); err != nil { // #nosec G304 G703 -- path derived from operator-controlled config dir and timestamp
+		if err := helpers.WriteFileAtomic(currentBackupPath, currentData, 0600); err != nil {
 			return fmt.Errorf("failed to backup current config: %w", err)
 		}
 
@@ -316,10 +319,70 @@ func restoreBackup(cfg *config.Config, siteName string, serial int) error {
 	return nil
 }
 
+// pushBackups uploads every local config backup and per-API cache snapshot to
+// the backup.remote target and applies its retention policy. Unlike the
+// best-effort hook that runs after apply/refresh, failures here are returned.
+func pushBackups(ctx context.Context) error {
+	mirror, err := remotebackup.FromConfig()
+	if err != nil {
+		return err
+	}
+	if mirror == nil {
+		return fmt.Errorf("remote backup is not enabled (set backup.remote.enabled in the wifimgr config)")
+	}
+
+	type upload struct{ kind, path string }
+	var uploads []upload
+
+	backupDir := config.BackupDir()
+	if files, err := os.ReadDir(backupDir); err == nil {
+		for _, file := range files {
+			if !file.IsDir() && strings.Contains(file.Name(), ".json.") {
+				uploads = append(uploads, upload{remotebackup.KindConfig, filepath.Join(backupDir, file.Name())})
+			}
+		}
+	}
+
+	// The cache manager owns the cache layout; without one no API is
+	// configured and there are no cache snapshots to push.
+	if accessor := vendors.GetGlobalCacheAccessor(); accessor != nil && accessor.GetManager() != nil {
+		paths, err := accessor.GetManager().CacheFilePaths()
+		if err != nil {
+			return err
+		}
+		for _, path := range paths {
+			uploads = append(uploads, upload{remotebackup.KindCache, path})
+		}
+	}
+
+	if len(uploads) == 0 {
+		fmt.Println("No local backups or cache snapshots to push")
+		return nil
+	}
+
+	for _, u := range uploads {
+		key, err := mirror.Upload(ctx, u.kind, u.path)
+		if err != nil {
+			return fmt.Errorf("failed to push %s: %w", filepath.Base(u.path), err)
+		}
+		fmt.Printf("  %s -> %s\n", filepath.Base(u.path), key)
+	}
+	fmt.Printf("Pushed %d file(s)\n", len(uploads))
+
+	removed, err := mirror.Prune(ctx)
+	if err != nil {
+		return fmt.Errorf("retention pass failed: %w", err)
+	}
+	if removed > 0 {
+		fmt.Printf("Pruned %d expired remote object(s)\n", removed)
+	}
+	return nil
+}
+
 // HandleCommand is the entry point for backup commands
 func HandleCommand(ctx context.Context, client api.Client, cfg *config.Config, args []string) error {
 	if len(args) < 1 {
-		return fmt.Errorf("backup command requires a subcommand: list or restore")
+		return fmt.Errorf("backup command requires a subcommand: list, restore, or push")
 	}
 
 	subcommand := args[0]
@@ -344,6 +407,9 @@ func HandleCommand(ctx context.Context, client api.Client, cfg *config.Config, a
 		}
 		return restoreBackup(cfg, siteName, serial)
 
+	case "push":
+		return pushBackups(ctx)
+
 	default:
 		return fmt.Errorf("unknown backup subcommand: %s", subcommand)
 	}
@@ -0,0 +1,300 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ravinald/wifimgr/internal/bench"
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+// maxBenchLookups caps how many cached MACs and site names are looked up per
+// API, so a huge org doesn't turn the lookup phase into the slow part.
+const maxBenchLookups = 1000
+
+// benchCmd represents the "bench" command
+var benchCmd = &cobra.Command{
+	Use:   "bench [cache|api] [target <api-label>] [iterations <n>]",
+	Short: "Measure cache and API performance",
+	Long: `Measure how long the cache and each API take, to guide tuning.
+
+For each API label:
+  cache load    Read and parse the API's cache file
+  cache save    Write the cache (to a temp dir; the real cache is untouched)
+  index build   Rebuild the cross-API lookup indexes
+  MAC lookup    Cached device lookup by MAC (up to 1000 devices)
+  site lookup   Cached site lookup by name (up to 1000 sites)
+  API RTT       Round trip of a site list call to the vendor
+
+The report ends with tuning suggestions when a number is out of line.
+
+Arguments:
+  cache        Optional. Only the cache measurements (no API calls)
+  api          Optional. Only the API round trips
+  target       Optional. Limit to one API label
+  iterations   Optional. Repetitions per measurement (default 5; API RTT uses 3)`,
+	Example: `  wifimgr bench
+  wifimgr bench cache
+  wifimgr bench api target mist-prod iterations 10`,
+	RunE: runBench,
+}
+
+func init() {
+	rootCmd.AddCommand(benchCmd)
+}
+
+// apiBenchResult holds one API label's measurements.
+type apiBenchResult struct {
+	label     string
+	cacheSize int64
+	devices   int
+	load      bench.Stats
+	save      bench.Stats
+	macLookup bench.Stats
+	siteLook  bench.Stats
+	rtt       bench.Stats
+	errs      []string
+}
+
+func runBench(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+	doCache, doAPI := true, true
+	iterations := 5
+	for i := 0; i < len(args); i++ {
+		switch strings.ToLower(args[i]) {
+		case "cache":
+			doCache, doAPI = true, false
+		case "api":
+			doCache, doAPI = false, true
+		case "target":
+			if i+1 >= len(args) {
+				return fmt.Errorf("'target' requires an API label")
+			}
+			i++
+			SetAPITarget(args[i])
+		case "iterations":
+			if i+1 >= len(args) {
+				return fmt.Errorf("'iterations' requires a number")
+			}
+			i++
+			n, err := strconv.Atoi(args[i])
+			if err != nil || n < 1 {
+				return fmt.Errorf("iterations must be a positive number, got %q", args[i])
+			}
+			iterations = n
+		default:
+			return fmt.Errorf("unknown argument %q", args[i])
+		}
+	}
+	if err := ValidateAPIFlag(); err != nil {
+		return err
+	}
+	labels := GetTargetAPIs()
+	if len(labels) == 0 {
+		return fmt.Errorf("no APIs are configured")
+	}
+
+	var results []*apiBenchResult
+	var index bench.Stats
+	if doCache {
+		cm := GetCacheManager()
+		if cm == nil {
+			return fmt.Errorf("cache manager not initialized")
+		}
+		tmp, err := os.MkdirTemp("", "wifimgr-bench-*")
+		if err != nil {
+			return err
+		}
+		defer func() { _ = os.RemoveAll(tmp) }()
+		scratch := vendors.NewCacheManager(tmp, GetAPIRegistry())
+
+		for _, label := range labels {
+			results = append(results, benchCache(cm, scratch, label, iterations))
+		}
+		if cacheAccessor != nil {
+			index, _ = bench.Measure(iterations, func() error {
+				cacheAccessor.RebuildIndexes()
+				return nil
+			})
+			for _, r := range results {
+				benchLookups(r, cm)
+			}
+		}
+	} else {
+		for _, label := range labels {
+			results = append(results, &apiBenchResult{label: label})
+		}
+	}
+	if doAPI {
+		for _, r := range results {
+			benchAPI(cmd.Context(), r, min(iterations, 3))
+		}
+	}
+
+	printBenchReport(results, index, doCache, doAPI)
+	return nil
+}
+
+func benchCache(cm, scratch *vendors.CacheManager, label string, n int) *apiBenchResult {
+	r := &apiBenchResult{label: label}
+	if info, err := os.Stat(cm.CacheFilePath(label)); err == nil {
+		r.cacheSize = info.Size()
+	}
+	var cache *vendors.APICache
+	stats, err := bench.Measure(n, func() error {
+		c, err := cm.GetAPICache(label)
+		cache = c
+		return err
+	})
+	r.load = stats
+	if err != nil {
+		r.errs = append(r.errs, fmt.Sprintf("cache load: %v", err))
+		return r
+	}
+	r.devices = len(cache.Inventory.AP) + len(cache.Inventory.Switch) + len(cache.Inventory.Gateway)
+	r.save, err = bench.Measure(n, func() error { return scratch.SaveAPICache(cache) })
+	if err != nil {
+		r.errs = append(r.errs, fmt.Sprintf("cache save: %v", err))
+	}
+	return r
+}
+
+// benchLookups times cached lookups of the label's own devices and sites
+// through the cross-API accessor, one sample per lookup.
+func benchLookups(r *apiBenchResult, cm *vendors.CacheManager) {
+	cache, err := cm.GetAPICache(r.label)
+	if err != nil {
+		return
+	}
+	var macSamples []time.Duration
+	for _, inv := range []map[string]*vendors.InventoryItem{cache.Inventory.AP, cache.Inventory.Switch, cache.Inventory.Gateway} {
+		for mac := range inv {
+			if len(macSamples) == maxBenchLookups {
+				break
+			}
+			start := time.Now()
+			_, _ = cacheAccessor.GetDeviceByMAC(mac)
+			macSamples = append(macSamples, time.Since(start))
+		}
+	}
+	var siteSamples []time.Duration
+	for i := range cache.Sites.Info {
+		if i == maxBenchLookups {
+			break
+		}
+		start := time.Now()
+		_, _ = cacheAccessor.GetSiteByName(cache.Sites.Info[i].Name)
+		siteSamples = append(siteSamples, time.Since(start))
+	}
+	r.macLookup = bench.Summarize(macSamples)
+	r.siteLook = bench.Summarize(siteSamples)
+}
+
+func benchAPI(ctx context.Context, r *apiBenchResult, n int) {
+	client, err := GetAPIRegistry().GetClient(r.label)
+	if err != nil {
+		r.errs = append(r.errs, fmt.Sprintf("API: %v", err))
+		return
+	}
+	r.rtt, err = bench.Measure(n, func() error {
+		_, err := client.Sites().List(ctx)
+		return err
+	})
+	if err != nil {
+		r.errs = append(r.errs, fmt.Sprintf("API: %v", err))
+	}
+}
+
+func printBenchReport(results []*apiBenchResult, index bench.Stats, doCache, doAPI bool) {
+	for _, r := range results {
+		fmt.Printf("== %s ==\n", r.label)
+		if doCache {
+			fmt.Printf("  cache file    %.1f MB, %d devices\n", float64(r.cacheSize)/(1<<20), r.devices)
+			fmt.Printf("  cache load    %s\n", r.load)
+			fmt.Printf("  cache save    %s\n", r.save)
+			fmt.Printf("  MAC lookup    %s\n", r.macLookup)
+			fmt.Printf("  site lookup   %s\n", r.siteLook)
+		}
+		if doAPI {
+			fmt.Printf("  API RTT       %s\n", r.rtt)
+		}
+		for _, e := range r.errs {
+			fmt.Printf("  error         %s\n", e)
+		}
+		fmt.Println()
+	}
+	if doCache {
+		fmt.Printf("Index build (all APIs): %s\n\n", index)
+	}
+
+	advice := benchAdvice(results, index)
+	if len(advice) == 0 {
+		fmt.Println("No tuning suggestions; all measurements are in the expected range.")
+		return
+	}
+	fmt.Println("Suggestions:")
+	for _, a := range advice {
+		fmt.Printf("  - %s\n", a)
+	}
+}
+
+// Thresholds above which bench suggests tuning.
+const (
+	slowCacheIO    = time.Second
+	slowIndexBuild = 2 * time.Second
+	slowLookup     = time.Millisecond
+	slowAPI        = 2 * time.Second
+)
+
+// benchAdvice turns the measurements into tuning suggestions. It only names
+// settings and commands that exist today.
+func benchAdvice(results []*apiBenchResult, index bench.Stats) []string {
+	var advice []string
+	for _, r := range results {
+		if r.load.P50 > slowCacheIO || r.save.P50 > slowCacheIO {
+			advice = append(advice, fmt.Sprintf(
+				"%s: cache I/O is slow (load p50 %s, save p50 %s for %.1f MB). Keep routine refreshes to managed devices ('refresh', not 'refresh all') to keep the file small, and check the cache directory is on local disk.",
+				r.label, bench.Round(r.load.P50), bench.Round(r.save.P50), float64(r.cacheSize)/(1<<20)))
+		}
+		if r.macLookup.P95 > slowLookup || r.siteLook.P95 > slowLookup {
+			advice = append(advice, fmt.Sprintf(
+				"%s: cached lookups are unusually slow (MAC p95 %s, site p95 %s); lookups are in-memory map reads, so this points at memory pressure on the host.",
+				r.label, bench.Round(r.macLookup.P95), bench.Round(r.siteLook.P95)))
+		}
+		if r.rtt.P50 > slowAPI {
+			advice = append(advice, fmt.Sprintf(
+				"%[1]s: API round trips are slow (p50 %[2]s). Set api.%[1]s.cache_ttl high enough to avoid needless refreshes, raise api.%[1]s.connection_timeout if connects time out, and raise api.refresh_concurrency so slow APIs refresh in parallel.",
+				r.label, bench.Round(r.rtt.P50)))
+		}
+	}
+	if index.P50 > slowIndexBuild {
+		advice = append(advice, fmt.Sprintf(
+			"Index build takes %s at startup of every cache-reading command; remove API labels you no longer use so their caches are not indexed.",
+			bench.Round(index.P50)))
+	}
+	return advice
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ravinald/wifimgr/internal/bench"
+)
+
+func TestBenchAdvice(t *testing.T) {
+	fast := &apiBenchResult{
+		label:     "mist-prod",
+		load:      bench.Stats{N: 5, P50: 50 * time.Millisecond},
+		save:      bench.Stats{N: 5, P50: 80 * time.Millisecond},
+		macLookup: bench.Stats{N: 100, P95: time.Microsecond},
+		rtt:       bench.Stats{N: 3, P50: 300 * time.Millisecond},
+	}
+	if advice := benchAdvice([]*apiBenchResult{fast}, bench.Stats{N: 5, P50: 100 * time.Millisecond}); len(advice) != 0 {
+		t.Errorf("fast run got advice: %v", advice)
+	}
+
+	slow := &apiBenchResult{
+		label:     "meraki-corp",
+		cacheSize: 80 << 20,
+		load:      bench.Stats{N: 5, P50: 3 * time.Second},
+		rtt:       bench.Stats{N: 3, P50: 4 * time.Second},
+	}
+	got := strings.Join(benchAdvice([]*apiBenchResult{fast, slow}, bench.Stats{N: 5, P50: 5 * time.Second}), "\n")
+	for _, want := range []string{"meraki-corp: cache I/O is slow", "meraki-corp: API round trips are slow", "api.meraki-corp.cache_ttl", "Index build takes 5s"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("advice missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "mist-prod") {
+		t.Errorf("fast API should get no advice:\n%s", got)
+	}
+}
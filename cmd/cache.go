@@ -0,0 +1,319 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/i18n"
+	"github.com/ravinald/wifimgr/internal/logging"
+	"github.com/ravinald/wifimgr/internal/runstats"
+	"github.com/ravinald/wifimgr/internal/symbols"
+	"github.com/ravinald/wifimgr/internal/vendors"
+	"github.com/ravinald/wifimgr/internal/xdg"
+)
+
+// cacheCmd groups maintenance of the per-API cache files.
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Maintain the per-API cache files",
+	Long: `Maintain the per-API cache files under the cache directory
+(files.cache_dir/apis). Refresh writes one cache per configured API label.
+Push and pull copy the cache and the config backups to and from a standby
+jump host or bucket.`,
+	Example: `  wifimgr cache prune
+  wifimgr cache migrate-legacy mist-prod
+  wifimgr cache push ops@jump2:wifimgr
+  wifimgr cache pull s3://netops-standby`,
+}
+
+// cachePruneCmd represents the "cache prune" command
+var cachePruneCmd = &cobra.Command{
+	Use:   "prune [force]",
+	Short: "Delete the caches of APIs no longer in the config",
+	Long: `Delete the cache files of API labels that are no longer defined under api.*
+in the config, and report the space reclaimed. The cache file, its metadata,
+its site shards, and any stale lock file are removed, and the cross-API index
+is rebuilt without them.
+
+An API that is defined but fails to load (bad credentials, unknown vendor)
+keeps its cache. Refresh offers the same prune when it finds such caches.
+
+Arguments:
+  force       Optional. Skip the confirmation prompt`,
+	Example: `  wifimgr cache prune
+  wifimgr cache prune force`,
+	Annotations: map[string]string{
+		cmdutils.AnnotationNeedsConfig: "true",
+	},
+	Args: func(_ *cobra.Command, args []string) error {
+		if cmdutils.ContainsHelp(args) {
+			return nil
+		}
+		if len(args) > 1 || (len(args) == 1 && strings.ToLower(args[0]) != "force") {
+			return fmt.Errorf("usage: cache prune [force]")
+		}
+		return nil
+	},
+	RunE: runCachePrune,
+}
+
+// cacheMigrateLegacyCmd represents the "cache migrate-legacy" command
+var cacheMigrateLegacyCmd = &cobra.Command{
+	Use:   "migrate-legacy [api-label]",
+	Short: "Convert the legacy cache.json into a per-API cache",
+	Long: `Convert the single-org cache.json written by older versions into the cache
+of a Mist API, then remove it. Startup does this on its own when it can tell
+which API the file belongs to: the Mist API whose org_id the file records, or
+the only Mist API configured. Run this when it cannot, naming the API.
+
+Sites, inventory, and device profiles are converted and keep the age of the
+old file, so the next refresh replaces them. If the API already has a cache,
+that cache is newer and is kept; the legacy file is only removed.
+
+Arguments:
+  api-label   Optional. The Mist API that owns the legacy cache`,
+	Example: `  wifimgr cache migrate-legacy
+  wifimgr cache migrate-legacy mist-prod`,
+	Args: func(_ *cobra.Command, args []string) error {
+		if cmdutils.ContainsHelp(args) {
+			return nil
+		}
+		if len(args) > 1 {
+			return fmt.Errorf("usage: cache migrate-legacy [api-label]")
+		}
+		return nil
+	},
+	RunE: runCacheMigrateLegacy,
+}
+
+func init() {
+	cacheCmd.AddCommand(cachePruneCmd)
+	cacheCmd.AddCommand(cacheMigrateLegacyCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
+
+func runCachePrune(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+	force := len(args) == 1
+
+	cacheMgr, err := pruneCacheManager()
+	if err != nil {
+		return err
+	}
+	orphans, err := cacheMgr.FindOrphanedCaches(config.ConfiguredAPILabels())
+	if err != nil {
+		return err
+	}
+	if len(orphans) == 0 {
+		fmt.Printf("%s No caches of departed APIs\n", symbols.SuccessPrefix())
+		return nil
+	}
+	return pruneOrphanedCaches(cacheMgr, orphans, force)
+}
+
+// pruneCacheManager returns the global cache manager, or one over the
+// configured cache directory when no API is configured — the case where
+// every cache left is an orphan.
+func pruneCacheManager() (*vendors.CacheManager, error) {
+	if cm := GetCacheManager(); cm != nil {
+		return cm, nil
+	}
+	cacheDir := viper.GetString("files.cache_dir")
+	if cacheDir == "" {
+		cacheDir = xdg.GetCacheDir()
+	}
+	cm := vendors.NewCacheManager(cacheDir, GetAPIRegistry())
+	if err := cm.Initialize(); err != nil {
+		return nil, fmt.Errorf("failed to initialize cache manager: %w", err)
+	}
+	return cm, nil
+}
+
+// pruneOrphanedCaches lists orphans, asks unless force, and deletes them.
+func pruneOrphanedCaches(cacheMgr *vendors.CacheManager, orphans []vendors.OrphanedCache, force bool) error {
+	var total int64
+	fmt.Println("Caches of APIs no longer in the config:")
+	for _, o := range orphans {
+		fmt.Printf("  %-24s %10s\n", o.APILabel, runstats.FormatBytes(o.Bytes))
+		total += o.Bytes
+	}
+
+	if !force {
+		fmt.Printf("%s %s ", i18n.T("cache.confirm_prune", len(orphans), runstats.FormatBytes(total)), i18n.T("prompt.yes_no"))
+		if !confirmPrompt() {
+			fmt.Println(i18n.T("cache.cancelled"))
+			return nil
+		}
+	}
+
+	reclaimed, err := cacheMgr.PruneCaches(orphans)
+	if err != nil {
+		return fmt.Errorf("failed to prune cache: %w", err)
+	}
+	fmt.Printf("%s Pruned %d API cache(s), reclaimed %s\n", symbols.SuccessPrefix(), len(orphans), runstats.FormatBytes(reclaimed))
+	return nil
+}
+
+// offerCachePrune runs after a refresh: when caches of departed APIs are
+// found it offers to prune them. With --no-input it only points at
+// cache prune, so scheduled refreshes never delete anything on their own.
+func offerCachePrune(cacheMgr *vendors.CacheManager) {
+	orphans, err := cacheMgr.FindOrphanedCaches(config.ConfiguredAPILabels())
+	if err != nil || len(orphans) == 0 {
+		return
+	}
+	if cmdutils.NoInput() && !cmdutils.AssumeYes() {
+		fmt.Printf("%s %d cache(s) of APIs no longer in the config; run 'wifimgr cache prune' to delete them\n",
+			symbols.WarningPrefix(), len(orphans))
+		return
+	}
+	fmt.Println()
+	if err := pruneOrphanedCaches(cacheMgr, orphans, false); err != nil {
+		fmt.Printf("%s %v\n", symbols.WarningPrefix(), err)
+	}
+}
+
+func runCacheMigrateLegacy(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+	cacheMgr := GetCacheManager()
+	if cacheMgr == nil {
+		return fmt.Errorf("no API configured to migrate the legacy cache into")
+	}
+	path := legacyCachePath(cacheMgr)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		fmt.Printf("%s No legacy cache at %s\n", symbols.SuccessPrefix(), path)
+		return nil
+	}
+
+	var apiLabel string
+	if len(args) == 1 {
+		apiLabel = args[0]
+		if vendor, err := GetAPIRegistry().GetVendor(apiLabel); err != nil {
+			return err
+		} else if vendor != "mist" {
+			return fmt.Errorf("API %s is %s; the legacy cache holds Mist data", apiLabel, vendor)
+		}
+	} else {
+		var err error
+		if apiLabel, err = legacyCacheTarget(path); err != nil {
+			return err
+		}
+	}
+
+	m, err := cacheMgr.MigrateLegacyCache(path, apiLabel)
+	if err != nil {
+		return fmt.Errorf("failed to migrate legacy cache: %w", err)
+	}
+	printLegacyMigration(os.Stdout, m)
+	return nil
+}
+
+// migrateLegacyCache runs once at startup: a legacy cache.json left by an
+// older version is converted into the matching Mist API's cache and removed,
+// so upgraded installs don't read as empty. When the owning API can't be
+// told, it only points at cache migrate-legacy.
+func migrateLegacyCache(cacheMgr *vendors.CacheManager) {
+	path := legacyCachePath(cacheMgr)
+	if _, err := os.Stat(path); err != nil {
+		return
+	}
+	apiLabel, err := legacyCacheTarget(path)
+	if err != nil {
+		logging.Warnf("Legacy cache %s not migrated: %v", path, err)
+		fmt.Fprintf(os.Stderr, "%s Legacy cache %s not migrated: %v; run 'wifimgr cache migrate-legacy <api-label>'\n",
+			symbols.WarningPrefix(), path, err)
+		return
+	}
+	m, err := cacheMgr.MigrateLegacyCache(path, apiLabel)
+	if err != nil {
+		logging.Warnf("Legacy cache migration failed: %v", err)
+		fmt.Fprintf(os.Stderr, "%s Legacy cache migration failed: %v\n", symbols.WarningPrefix(), err)
+		return
+	}
+	logging.Infof("Migrated legacy cache %s into %s", path, apiLabel)
+	printLegacyMigration(os.Stderr, m)
+}
+
+// legacyCachePath returns files.cache when set, else cache.json in the cache
+// directory — where older versions wrote it.
+func legacyCachePath(cacheMgr *vendors.CacheManager) string {
+	if path := viper.GetString("files.cache"); path != "" {
+		return path
+	}
+	return cacheMgr.LegacyCachePath()
+}
+
+// legacyCacheTarget picks the API whose cache the legacy file becomes: the
+// Mist API of the org the file records, or else the only Mist API configured.
+func legacyCacheTarget(path string) (string, error) {
+	orgID, err := vendors.ReadLegacyCacheOrgID(path)
+	if err != nil {
+		return "", err
+	}
+	registry := GetAPIRegistry()
+	if registry == nil {
+		return "", fmt.Errorf("no Mist API is configured")
+	}
+	var mist []string
+	for _, label := range registry.GetAllLabels() {
+		if vendor, _ := registry.GetVendor(label); vendor != "mist" {
+			continue
+		}
+		if orgID != "" {
+			if id, _ := registry.GetOrgID(label); id == orgID {
+				return label, nil
+			}
+		}
+		mist = append(mist, label)
+	}
+	if orgID != "" {
+		// A recorded org that matches no API is not guessed at.
+		return "", fmt.Errorf("no configured Mist API has org %s", orgID)
+	}
+	switch len(mist) {
+	case 0:
+		return "", fmt.Errorf("no Mist API is configured")
+	case 1:
+		return mist[0], nil
+	}
+	return "", fmt.Errorf("more than one Mist API is configured and the cache does not record its org")
+}
+
+// printLegacyMigration reports what a legacy cache migration did.
+func printLegacyMigration(w io.Writer, m *vendors.LegacyMigration) {
+	if m.Converted {
+		_, _ = fmt.Fprintf(w, "%s Migrated legacy cache into %s: %d site(s), %d device(s), %d device profile(s)\n",
+			symbols.SuccessPrefix(), m.APILabel, m.Sites, m.Devices, m.Profiles)
+		_, _ = fmt.Fprintf(w, "  The data keeps the age of the old file; run 'wifimgr refresh' to bring it current\n")
+	} else {
+		_, _ = fmt.Fprintf(w, "%s %s already has a newer cache; legacy contents were not merged\n",
+			symbols.SuccessPrefix(), m.APILabel)
+	}
+	_, _ = fmt.Fprintf(w, "  Removed %s\n", m.Path)
+}
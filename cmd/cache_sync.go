@@ -0,0 +1,228 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/ravinald/wifimgr/internal/cachesync"
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/i18n"
+	"github.com/ravinald/wifimgr/internal/symbols"
+	"github.com/ravinald/wifimgr/internal/xdg"
+)
+
+const cacheSyncRemoteHelp = `Remotes:
+  [user@]host:path         rsync over SSH; path must exist on the host
+  s3://bucket[/prefix]     Amazon S3 or an S3-compatible store
+  gs://bucket[/prefix]     Google Cloud Storage (HMAC interoperability keys)
+
+Object stores take region, endpoint, and credentials from backup.remote.*
+(backup.remote.enabled is not required). The prefix defaults to wifimgr-sync.
+The cache lands under <remote>/cache and the backups under <remote>/backups,
+or under <remote>/tenants/<name>/ when running as a tenant.`
+
+// cachePushCmd represents the "cache push" command
+var cachePushCmd = &cobra.Command{
+	Use:   "push <remote> [cache|backups]",
+	Short: "Copy the cache and backups to a standby host or bucket",
+	Long: `Copy the cache directory and the config backups to a remote, so a standby
+jump host can pull them and carry on when this host is unavailable. Run it
+after refreshes and applies, or from a schedule.
+
+Files are added and overwritten, never deleted on the remote; an rsync
+remote keeps a file that is newer there. Lock files and writes in progress
+are skipped.
+
+` + cacheSyncRemoteHelp + `
+
+Arguments:
+  remote      Required. Where to copy to
+  cache       Optional. Copy only the cache
+  backups     Optional. Copy only the backups`,
+	Example: `  wifimgr cache push ops@jump2:wifimgr
+  wifimgr cache push s3://netops-standby
+  wifimgr cache push gs://netops-standby/wifimgr backups`,
+	Annotations: map[string]string{
+		cmdutils.AnnotationNeedsConfig: "true",
+	},
+	Args: func(_ *cobra.Command, args []string) error {
+		if cmdutils.ContainsHelp(args) {
+			return nil
+		}
+		if _, _, err := parseCacheSyncArgs(args, false); err != nil {
+			return fmt.Errorf("%w\nusage: cache push <remote> [cache|backups]", err)
+		}
+		return nil
+	},
+	RunE: runCachePush,
+}
+
+// cachePullCmd represents the "cache pull" command
+var cachePullCmd = &cobra.Command{
+	Use:   "pull <remote> [cache|backups] [force]",
+	Short: "Copy the cache and backups from the primary host's remote",
+	Long: `Copy the cache directory and the config backups from a remote that the
+primary host pushes to, overwriting older local copies of the same files.
+Files that exist only locally, or that changed locally since the remote copy
+was pushed, are kept.
+
+` + cacheSyncRemoteHelp + `
+
+Arguments:
+  remote      Required. Where to copy from
+  cache       Optional. Copy only the cache
+  backups     Optional. Copy only the backups
+  force       Optional. Skip the confirmation prompt`,
+	Example: `  wifimgr cache pull ops@jump1:wifimgr
+  wifimgr cache pull s3://netops-standby force`,
+	Annotations: map[string]string{
+		cmdutils.AnnotationNeedsConfig: "true",
+	},
+	Args: func(_ *cobra.Command, args []string) error {
+		if cmdutils.ContainsHelp(args) {
+			return nil
+		}
+		if _, _, err := parseCacheSyncArgs(args, true); err != nil {
+			return fmt.Errorf("%w\nusage: cache pull <remote> [cache|backups] [force]", err)
+		}
+		return nil
+	},
+	RunE: runCachePull,
+}
+
+func init() {
+	cacheCmd.AddCommand(cachePushCmd)
+	cacheCmd.AddCommand(cachePullCmd)
+}
+
+// cacheSyncArgs are the arguments after <remote>.
+type cacheSyncArgs struct {
+	only  string // cachesync.DirCache, cachesync.DirBackups, or "" for both
+	force bool
+}
+
+// parseCacheSyncArgs splits <remote> from the keywords that follow it;
+// force is accepted only when allowForce is set.
+func parseCacheSyncArgs(args []string, allowForce bool) (string, cacheSyncArgs, error) {
+	var opts cacheSyncArgs
+	if len(args) == 0 {
+		return "", opts, fmt.Errorf("remote is required")
+	}
+	for _, arg := range args[1:] {
+		switch kw := strings.ToLower(arg); {
+		case kw == cachesync.DirCache || kw == cachesync.DirBackups:
+			if opts.only != "" {
+				return "", opts, fmt.Errorf("name one of cache or backups, or neither for both")
+			}
+			opts.only = kw
+		case kw == "force" && allowForce:
+			opts.force = true
+		default:
+			return "", opts, fmt.Errorf("unexpected argument %q", arg)
+		}
+	}
+	return cmdutils.StripQuotes(args[0]), opts, nil
+}
+
+// cacheSyncDirs returns the local directories to copy: the cache directory
+// and the backup directory, or the one opts names. Under a tenant they land
+// in tenants/<name>/ on the remote, apart from other tenants' copies.
+func cacheSyncDirs(opts cacheSyncArgs) []cachesync.Dir {
+	cacheDir := viper.GetString("files.cache_dir")
+	if cacheDir == "" {
+		cacheDir = xdg.GetCacheDir()
+	}
+	scope := config.TenantPrefix("")
+	var dirs []cachesync.Dir
+	if opts.only != cachesync.DirBackups {
+		dirs = append(dirs, cachesync.Dir{Name: path.Join(scope, cachesync.DirCache), Path: cacheDir})
+	}
+	if opts.only != cachesync.DirCache {
+		dirs = append(dirs, cachesync.Dir{Name: path.Join(scope, cachesync.DirBackups), Path: config.BackupDir()})
+	}
+	return dirs
+}
+
+// cacheSyncWhat names the directories for messages.
+func cacheSyncWhat(dirs []cachesync.Dir) string {
+	names := make([]string, len(dirs))
+	for i, d := range dirs {
+		names[i] = d.Name
+	}
+	return strings.Join(names, " and ")
+}
+
+func runCachePush(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+	spec, opts, err := parseCacheSyncArgs(args, false)
+	if err != nil {
+		return err
+	}
+	remote, err := cachesync.Parse(spec)
+	if err != nil {
+		return err
+	}
+	dirs := cacheSyncDirs(opts)
+
+	n, err := remote.Push(cmd.Context(), dirs)
+	if err != nil {
+		return fmt.Errorf("failed to push to %s after %d file(s): %w", remote, n, err)
+	}
+	fmt.Printf("%s Pushed %s to %s (%d file(s) copied)\n", symbols.SuccessPrefix(), cacheSyncWhat(dirs), remote, n)
+	return nil
+}
+
+func runCachePull(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+	spec, opts, err := parseCacheSyncArgs(args, true)
+	if err != nil {
+		return err
+	}
+	remote, err := cachesync.Parse(spec)
+	if err != nil {
+		return err
+	}
+	dirs := cacheSyncDirs(opts)
+
+	if !opts.force {
+		fmt.Printf("%s %s ", i18n.T("cache.confirm_pull", cacheSyncWhat(dirs), remote), i18n.T("prompt.yes_no"))
+		if !confirmPrompt() {
+			fmt.Println(i18n.T("cache.cancelled"))
+			return nil
+		}
+	}
+
+	n, err := remote.Pull(cmd.Context(), dirs)
+	if err != nil {
+		return fmt.Errorf("failed to pull from %s after %d file(s): %w", remote, n, err)
+	}
+	fmt.Printf("%s Pulled %s from %s (%d file(s) copied)\n", symbols.SuccessPrefix(), cacheSyncWhat(dirs), remote, n)
+	if opts.only != cachesync.DirBackups {
+		fmt.Println("  The pulled cache is used from the next command on.")
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/ravinald/wifimgr/internal/cachesync"
+)
+
+func TestParseCacheSyncArgs(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		allowForce bool
+		wantRemote string
+		want       cacheSyncArgs
+		wantErr    bool
+	}{
+		{"remote only", []string{"ops@jump2:wifimgr"}, false, "ops@jump2:wifimgr", cacheSyncArgs{}, false},
+		{"backups only", []string{"s3://b", "Backups"}, false, "s3://b", cacheSyncArgs{only: cachesync.DirBackups}, false},
+		{"pull force", []string{"s3://b", "cache", "force"}, true, "s3://b", cacheSyncArgs{only: cachesync.DirCache, force: true}, false},
+		{"push has no force", []string{"s3://b", "force"}, false, "", cacheSyncArgs{}, true},
+		{"both named", []string{"s3://b", "cache", "backups"}, false, "", cacheSyncArgs{}, true},
+		{"no remote", nil, false, "", cacheSyncArgs{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remote, got, err := parseCacheSyncArgs(tt.args, tt.allowForce)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseCacheSyncArgs(%v) = %v, want error", tt.args, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseCacheSyncArgs(%v): %v", tt.args, err)
+			}
+			if remote != tt.wantRemote || got != tt.want {
+				t.Errorf("parseCacheSyncArgs(%v) = %q, %+v; want %q, %+v", tt.args, remote, got, tt.wantRemote, tt.want)
+			}
+		})
+	}
+}
+
+func TestCacheSyncDirs(t *testing.T) {
+	if dirs := cacheSyncDirs(cacheSyncArgs{}); len(dirs) != 2 || cacheSyncWhat(dirs) != "cache and backups" {
+		t.Errorf("default dirs = %+v", dirs)
+	}
+	if dirs := cacheSyncDirs(cacheSyncArgs{only: cachesync.DirCache}); len(dirs) != 1 || dirs[0].Name != cachesync.DirCache {
+		t.Errorf("cache-only dirs = %+v", dirs)
+	}
+}
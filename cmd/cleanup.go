@@ -0,0 +1,37 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// cleanupCmd groups commands that find and remove objects nothing uses.
+var cleanupCmd = &cobra.Command{
+	Use:   "cleanup",
+	Short: "Find and remove unused objects in the vendor org",
+	Long: `Find objects in the vendor org that nothing is assigned to — the clutter
+left by years of experiments in the vendor dashboard — and optionally delete
+them.
+
+Cleanup only ever lists by default; deleting is a separate, confirmed step.`,
+	Example: `  # List unused device profiles, RF templates, and WLAN templates
+  wifimgr cleanup org --dry-run`,
+}
+
+func init() {
+	rootCmd.AddCommand(cleanupCmd)
+}
@@ -0,0 +1,541 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/ravinald/wifimgr/api"
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/formatter"
+	"github.com/ravinald/wifimgr/internal/i18n"
+	"github.com/ravinald/wifimgr/internal/logging"
+	"github.com/ravinald/wifimgr/internal/symbols"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+// Object kinds cleanup org looks at, in report order.
+const (
+	cleanupKindProfile = "profile"
+	cleanupKindRF      = "rf"
+	cleanupKindWLAN    = "wlan"
+)
+
+var cleanupKinds = []string{cleanupKindProfile, cleanupKindRF, cleanupKindWLAN}
+
+var cleanupOrgDryRun bool
+
+var cleanupOrgCmd = &cobra.Command{
+	Use:   "org [target <api-label>] [type profile|rf|wlan] [dry-run] [delete] [force]",
+	Short: "Find unused device profiles, RF templates, and WLAN templates",
+	Long: `List org-level objects that nothing is assigned to, read live from the API:
+
+  profile  device profiles no AP, switch, or gateway in the org inventory uses
+  rf       RF templates no site uses
+  wlan     WLAN templates applied to neither the org, a site, nor a site group
+
+A device profile that a site config names (deviceprofile_id or
+deviceprofile_name on a device) is kept even when unassigned, since the next
+apply assigns it; it is listed as kept with the file that names it. Only Mist
+APIs have these objects.
+
+Without 'delete' the command only lists. With 'delete' the objects are deleted
+after 'delete' is typed back; each is checked again just before it goes, and
+one that has picked up an assignment since the listing is skipped. Deleted
+objects cannot be restored.
+
+Arguments:
+  target <label>  Optional. API to clean up (required when more than one is configured)
+  type <kind>     Optional. Only look at profile, rf, or wlan (may be repeated)
+  dry-run         Optional. List only, even with 'delete' (also --dry-run)
+  delete          Optional. Delete the unused objects
+  force           Optional. Delete without the typed confirmation`,
+	Example: `  wifimgr cleanup org --dry-run
+  wifimgr cleanup org target mist-prod type rf
+  wifimgr cleanup org target mist-prod delete`,
+	RunE: runCleanupOrg,
+}
+
+func init() {
+	cleanupOrgCmd.Flags().BoolVar(&cleanupOrgDryRun, "dry-run", false, "List unused objects without deleting")
+	cleanupCmd.AddCommand(cleanupOrgCmd)
+}
+
+// cleanupOrgArgs holds the parsed positional arguments.
+type cleanupOrgArgs struct {
+	Target string
+	Kinds  []string
+	DryRun bool
+	Delete bool
+	Force  bool
+}
+
+func parseCleanupOrgArgs(args []string) (*cleanupOrgArgs, error) {
+	out := &cleanupOrgArgs{}
+	for i := 0; i < len(args); i++ {
+		switch strings.ToLower(args[i]) {
+		case "target":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("'target' requires an API label")
+			}
+			out.Target = cmdutils.StripQuotes(args[i+1])
+			i++
+		case "type":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("'type' requires a kind (profile, rf, wlan)")
+			}
+			kind := strings.ToLower(args[i+1])
+			if !slices.Contains(cleanupKinds, kind) {
+				return nil, fmt.Errorf("invalid type %q: must be 'profile', 'rf', or 'wlan'", args[i+1])
+			}
+			if !slices.Contains(out.Kinds, kind) {
+				out.Kinds = append(out.Kinds, kind)
+			}
+			i++
+		case "dry-run", "dryrun":
+			out.DryRun = true
+		case "delete":
+			out.Delete = true
+		case "force":
+			out.Force = true
+		default:
+			return nil, fmt.Errorf("unexpected argument: %s", args[i])
+		}
+	}
+	if len(out.Kinds) == 0 {
+		out.Kinds = cleanupKinds
+	}
+	return out, nil
+}
+
+func runCleanupOrg(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+
+	parsed, err := parseCleanupOrgArgs(args)
+	if err != nil {
+		return err
+	}
+	parsed.DryRun = parsed.DryRun || cleanupOrgDryRun
+
+	deps := currentDeps()
+	label, client, err := cleanupOrgClient(deps, parsed.Target)
+	if err != nil {
+		return err
+	}
+	lc := legacyAPIClient(client)
+	if lc == nil {
+		return &vendors.CapabilityNotSupportedError{
+			Capability:  "org cleanup",
+			APILabel:    label,
+			VendorName:  client.VendorName(),
+			SupportedBy: []string{"mist"},
+		}
+	}
+	orgID := client.OrgID()
+
+	sites := loadConfiguredSites(viper.GetString("files.config_dir"), viper.GetStringSlice("files.site_configs"))
+	refs := intentProfileRefs(sites)
+
+	objs, err := fetchOrgObjects(deps.Ctx, lc, orgID, parsed.Kinds)
+	if err != nil {
+		return err
+	}
+	report := findUnusedOrgObjects(objs, refs, parsed.Kinds)
+	if len(report.Unused) == 0 && len(report.Kept) == 0 {
+		fmt.Printf("%s No unused %s in %s\n", symbols.SuccessPrefix(), cleanupKindsLabel(parsed.Kinds), label)
+		return nil
+	}
+	printOrgCleanupReport(label, report)
+
+	if len(report.Unused) == 0 {
+		return nil
+	}
+	if !parsed.Delete {
+		fmt.Printf("Nothing deleted. Run 'wifimgr cleanup org target %s delete' to delete the %d unused object(s).\n", label, len(report.Unused))
+		return nil
+	}
+	if parsed.DryRun {
+		fmt.Println("Dry run: nothing deleted.")
+		return nil
+	}
+	if !parsed.Force && !confirmOrgCleanup(len(report.Unused)) {
+		fmt.Println(i18n.T("cleanup_org.cancelled"))
+		return nil
+	}
+
+	return deleteUnusedOrgObjects(deps.Ctx, lc, orgID, refs, parsed.Kinds, report.Unused)
+}
+
+// cleanupOrgClient picks the API to clean up: target, or the only one
+// configured.
+func cleanupOrgClient(deps *Deps, target string) (string, vendors.Client, error) {
+	if deps.Registry == nil || deps.Registry.Count() == 0 {
+		return "", nil, fmt.Errorf("no API configured")
+	}
+	if target == "" {
+		labels := deps.Registry.GetAllLabels()
+		if len(labels) > 1 {
+			return "", nil, fmt.Errorf("more than one API is configured; add 'target <api-label>' (one of: %s)", strings.Join(labels, ", "))
+		}
+		target = labels[0]
+	}
+	if !deps.Registry.HasAPI(target) {
+		return "", nil, FormatAPINotFoundError(target)
+	}
+	client, err := deps.Client(target)
+	if err != nil {
+		return "", nil, err
+	}
+	return target, client, nil
+}
+
+// legacyAPIClient returns the Mist api.Client behind client, or nil.
+func legacyAPIClient(client vendors.Client) api.Client {
+	if acc, ok := client.(vendors.LegacyClientAccessor); ok {
+		if lc, ok := acc.LegacyClient().(api.Client); ok {
+			return lc
+		}
+	}
+	return nil
+}
+
+// orgObjects is what cleanup org reads from the API: the candidates, and
+// the sites and inventory that assign them.
+type orgObjects struct {
+	Profiles      []api.DeviceProfile
+	RFTemplates   []api.MistRFTemplate
+	WLANTemplates []api.MistWLANTemplate
+	Sites         []*api.MistSite
+	Inventory     []*api.MistInventoryItem
+}
+
+// fetchOrgObjects reads the objects of kinds and whatever is needed to tell
+// whether they are used.
+func fetchOrgObjects(ctx context.Context, lc api.Client, orgID string, kinds []string) (*orgObjects, error) {
+	objs := &orgObjects{}
+	var err error
+	if slices.Contains(kinds, cleanupKindProfile) {
+		if objs.Profiles, err = lc.GetDeviceProfiles(ctx, orgID, ""); err != nil {
+			return nil, err
+		}
+		for _, deviceType := range []string{"ap", "switch", "gateway"} {
+			items, err := lc.GetInventory(ctx, orgID, deviceType)
+			if err != nil {
+				return nil, err
+			}
+			objs.Inventory = append(objs.Inventory, items...)
+		}
+	}
+	if slices.Contains(kinds, cleanupKindRF) {
+		if objs.RFTemplates, err = lc.GetRFTemplates(ctx, orgID); err != nil {
+			return nil, err
+		}
+		if objs.Sites, err = lc.GetSites(ctx, orgID); err != nil {
+			return nil, err
+		}
+	}
+	if slices.Contains(kinds, cleanupKindWLAN) {
+		if objs.WLANTemplates, err = lc.GetWLANTemplates(ctx, orgID); err != nil {
+			return nil, err
+		}
+	}
+	return objs, nil
+}
+
+// intentProfileRefs maps each device profile ID, and lowercased name, that a
+// site config names to the file naming it. Every site config counts, whatever
+// its API: keeping a profile too many is the safe mistake.
+func intentProfileRefs(sites []configuredSite) map[string]string {
+	refs := make(map[string]string)
+	add := func(ref, file string) {
+		if ref = strings.ToLower(strings.TrimSpace(ref)); ref != "" {
+			if _, seen := refs[ref]; !seen {
+				refs[ref] = file
+			}
+		}
+	}
+	for _, site := range sites {
+		for _, ap := range site.Devices.APs {
+			if ap.APDeviceConfig != nil {
+				add(ap.DeviceProfileID, site.File)
+				add(ap.DeviceProfileName, site.File)
+			}
+		}
+		for _, sw := range site.Devices.Switches {
+			add(sw.DeviceProfileID, site.File)
+		}
+	}
+	return refs
+}
+
+// orgCleanupEntry is one object in the report. Detail is when it last
+// changed for unused objects, and why it is kept otherwise.
+type orgCleanupEntry struct {
+	Kind   string
+	ID     string
+	Name   string
+	Detail string
+}
+
+// orgCleanupReport holds the unused objects, and those unassigned but named
+// in intent.
+type orgCleanupReport struct {
+	Unused []orgCleanupEntry
+	Kept   []orgCleanupEntry
+}
+
+// findUnusedOrgObjects returns the objects of kinds in objs that nothing is
+// assigned to. Device profiles named in refs are kept instead.
+func findUnusedOrgObjects(objs *orgObjects, refs map[string]string, kinds []string) *orgCleanupReport {
+	report := &orgCleanupReport{}
+
+	if slices.Contains(kinds, cleanupKindProfile) {
+		assigned := make(map[string]bool)
+		for _, item := range objs.Inventory {
+			if id := derefStr(item.DeviceProfileID); id != "" {
+				assigned[id] = true
+			}
+		}
+		for _, p := range objs.Profiles {
+			id, name := derefStr(p.ID), derefStr(p.Name)
+			if id == "" || assigned[id] {
+				continue
+			}
+			e := orgCleanupEntry{Kind: cleanupKindProfile, ID: id, Name: name, Detail: lastChanged(p.CreatedTime, p.ModifiedTime)}
+			if t := derefStr(p.Type); t != "" {
+				e.Detail = strings.TrimSpace(t + " " + e.Detail)
+			}
+			file, named := refs[strings.ToLower(id)]
+			if !named {
+				file, named = refs[strings.ToLower(name)]
+			}
+			if named {
+				e.Detail = "named in " + file
+				report.Kept = append(report.Kept, e)
+				continue
+			}
+			report.Unused = append(report.Unused, e)
+		}
+	}
+
+	if slices.Contains(kinds, cleanupKindRF) {
+		assigned := make(map[string]bool)
+		for _, site := range objs.Sites {
+			if id := derefStr(site.RFTemplateID); id != "" {
+				assigned[id] = true
+			}
+		}
+		for _, t := range objs.RFTemplates {
+			id := derefStr(t.ID)
+			if id == "" || assigned[id] {
+				continue
+			}
+			report.Unused = append(report.Unused, orgCleanupEntry{
+				Kind: cleanupKindRF, ID: id, Name: derefStr(t.Name), Detail: lastChanged(int64Time(t.CreatedTime), int64Time(t.ModifiedTime)),
+			})
+		}
+	}
+
+	if slices.Contains(kinds, cleanupKindWLAN) {
+		for _, t := range objs.WLANTemplates {
+			id := derefStr(t.ID)
+			if id == "" || templateApplied(t.Applies) {
+				continue
+			}
+			report.Unused = append(report.Unused, orgCleanupEntry{
+				Kind: cleanupKindWLAN, ID: id, Name: derefStr(t.Name), Detail: lastChanged(int64Time(t.CreatedTime), int64Time(t.ModifiedTime)),
+			})
+		}
+	}
+
+	sortOrgCleanupEntries(report.Unused)
+	sortOrgCleanupEntries(report.Kept)
+	return report
+}
+
+// templateApplied reports whether a WLAN template is assigned anywhere.
+func templateApplied(a *api.MistTemplateApplies) bool {
+	return a != nil && (derefStr(a.OrgID) != "" || len(a.SiteIDs) > 0 || len(a.SiteGroupIDs) > 0)
+}
+
+func sortOrgCleanupEntries(entries []orgCleanupEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Kind != b.Kind {
+			return slices.Index(cleanupKinds, a.Kind) < slices.Index(cleanupKinds, b.Kind)
+		}
+		if a.Name != b.Name {
+			return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+		}
+		return a.ID < b.ID
+	})
+}
+
+// deleteUnusedOrgObjects deletes entries, checking each against a fresh read
+// of the org first so nothing assigned since the listing is removed.
+func deleteUnusedOrgObjects(ctx context.Context, lc api.Client, orgID string, refs map[string]string, kinds []string, entries []orgCleanupEntry) error {
+	fresh, err := fetchOrgObjects(ctx, lc, orgID, kinds)
+	if err != nil {
+		return fmt.Errorf("re-checking usage before deleting: %w", err)
+	}
+	stillUnused := make(map[string]bool)
+	for _, e := range findUnusedOrgObjects(fresh, refs, kinds).Unused {
+		stillUnused[e.Kind+"/"+e.ID] = true
+	}
+
+	deleted, skipped, failed := 0, 0, 0
+	for _, e := range entries {
+		if !stillUnused[e.Kind+"/"+e.ID] {
+			fmt.Printf("%s %s '%s' is now in use or gone; skipped\n", symbols.WarningPrefix(), cleanupKindTitle(e.Kind), e.Name)
+			skipped++
+			continue
+		}
+		var err error
+		switch e.Kind {
+		case cleanupKindProfile:
+			err = lc.DeleteDeviceProfile(ctx, orgID, e.ID)
+		case cleanupKindRF:
+			err = lc.DeleteRFTemplate(ctx, orgID, e.ID)
+		case cleanupKindWLAN:
+			err = lc.DeleteWLANTemplate(ctx, orgID, e.ID)
+		}
+		if err != nil {
+			logging.Errorf("Failed to delete %s %s (%s): %v", e.Kind, e.Name, e.ID, err)
+			fmt.Printf("%s %s '%s': %v\n", symbols.FailurePrefix(), cleanupKindTitle(e.Kind), e.Name, err)
+			failed++
+			continue
+		}
+		logging.Infof("Deleted %s %s (%s) from org %s", e.Kind, e.Name, e.ID, orgID)
+		fmt.Printf("%s Deleted %s '%s'\n", symbols.SuccessPrefix(), cleanupKindTitle(e.Kind), e.Name)
+		deleted++
+	}
+
+	fmt.Printf("%d deleted, %d skipped, %d failed\n", deleted, skipped, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d object(s) could not be deleted", failed)
+	}
+	return nil
+}
+
+// confirmOrgCleanup asks for "delete" to be typed. --yes does not count,
+// and it fails closed under --no-input.
+func confirmOrgCleanup(count int) bool {
+	if cmdutils.NoInput() {
+		fmt.Println(i18n.T("cleanup_org.no_input"))
+		return false
+	}
+	fmt.Printf("%s %s\n", symbols.WarningPrefix(), i18n.T("cleanup_org.warning"))
+	fmt.Print(i18n.T("cleanup_org.type_delete", count))
+	input, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false
+	}
+	return strings.TrimSpace(input) == "delete"
+}
+
+func printOrgCleanupReport(label string, report *orgCleanupReport) {
+	rows := make([]formatter.GenericTableData, 0, len(report.Unused)+len(report.Kept))
+	for _, set := range []struct {
+		status  string
+		entries []orgCleanupEntry
+	}{{"unused", report.Unused}, {"kept", report.Kept}} {
+		for _, e := range set.entries {
+			rows = append(rows, formatter.GenericTableData{
+				"status": set.status,
+				"kind":   cleanupKindTitle(e.Kind),
+				"name":   e.Name,
+				"id":     e.ID,
+				"detail": e.Detail,
+			})
+		}
+	}
+	printer := formatter.NewGenericTablePrinter(formatter.TableConfig{
+		Title:         fmt.Sprintf("Unused org objects in %s (%d unused, %d kept)", label, len(report.Unused), len(report.Kept)),
+		Format:        "table",
+		BoldHeaders:   true,
+		ShowSeparator: true,
+		CommandPath:   "cleanup.org",
+		Columns: []formatter.TableColumn{
+			{Field: "status", Title: "Status"},
+			{Field: "kind", Title: "Kind"},
+			{Field: "name", Title: "Name"},
+			{Field: "id", Title: "ID"},
+			{Field: "detail", Title: "Detail"},
+		},
+	}, rows)
+	fmt.Print(printer.Print())
+}
+
+// cleanupKindTitle names a kind for output.
+func cleanupKindTitle(kind string) string {
+	switch kind {
+	case cleanupKindProfile:
+		return "device profile"
+	case cleanupKindRF:
+		return "RF template"
+	case cleanupKindWLAN:
+		return "WLAN template"
+	}
+	return kind
+}
+
+// cleanupKindsLabel names the kinds looked at, e.g. "RF templates".
+func cleanupKindsLabel(kinds []string) string {
+	names := make([]string, 0, len(kinds))
+	for _, k := range kinds {
+		names = append(names, cleanupKindTitle(k)+"s")
+	}
+	return strings.Join(names, ", ")
+}
+
+// lastChanged renders the newer of two epoch times as "modified <date>", or
+// "" when neither is set.
+func lastChanged(created, modified *float64) string {
+	switch {
+	case modified != nil && *modified > 0:
+		return "modified " + time.Unix(int64(*modified), 0).UTC().Format("2006-01-02")
+	case created != nil && *created > 0:
+		return "created " + time.Unix(int64(*created), 0).UTC().Format("2006-01-02")
+	}
+	return ""
+}
+
+func int64Time(t *int64) *float64 {
+	if t == nil {
+		return nil
+	}
+	f := float64(*t)
+	return &f
+}
+
+func derefStr(p *string) string {
+	if p == nil {
+		return ""
+	}
+	return *p
+}
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+
+	"github.com/ravinald/wifimgr/api"
+	"github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+func TestFindUnusedOrgObjects(t *testing.T) {
+	s := api.StringPtr
+	objs := &orgObjects{
+		Profiles: []api.DeviceProfile{
+			{ID: s("p-used"), Name: s("Lobby APs"), Type: s("ap")},
+			{ID: s("p-unused"), Name: s("test-do-not-use"), Type: s("ap")},
+			{ID: s("p-intent"), Name: s("Warehouse APs"), Type: s("ap")},
+		},
+		Inventory: []*api.MistInventoryItem{{MAC: s("aa0000000001"), DeviceProfileID: s("p-used")}},
+		RFTemplates: []api.MistRFTemplate{
+			{ID: s("rf-used"), Name: s("Office")},
+			{ID: s("rf-unused"), Name: s("Old RF")},
+		},
+		Sites: []*api.MistSite{{RFTemplateID: s("rf-used")}},
+		WLANTemplates: []api.MistWLANTemplate{
+			{ID: s("w-org"), Name: s("Corp"), Applies: &api.MistTemplateApplies{OrgID: s("org")}},
+			{ID: s("w-sitegroup"), Name: s("Retail"), Applies: &api.MistTemplateApplies{SiteGroupIDs: []string{"sg1"}}},
+			{ID: s("w-empty"), Name: s("Trial"), Applies: &api.MistTemplateApplies{}},
+			{ID: s("w-none"), Name: s("Scratch")},
+		},
+	}
+	sites := []configuredSite{{
+		File: "sites/wh.json",
+		Devices: config.Devices{APs: map[string]config.APConfig{
+			"aa0000000002": {APDeviceConfig: &vendors.APDeviceConfig{DeviceProfileName: "warehouse aps"}},
+		}},
+	}}
+
+	report := findUnusedOrgObjects(objs, intentProfileRefs(sites), cleanupKinds)
+	var unused []string
+	for _, e := range report.Unused {
+		unused = append(unused, e.Kind+"/"+e.ID)
+	}
+	want := []string{"profile/p-unused", "rf/rf-unused", "wlan/w-none", "wlan/w-empty"}
+	if len(unused) != len(want) {
+		t.Fatalf("Unused = %v, want %v", unused, want)
+	}
+	for i := range want {
+		if unused[i] != want[i] {
+			t.Errorf("Unused = %v, want %v", unused, want)
+			break
+		}
+	}
+	if len(report.Kept) != 1 || report.Kept[0].ID != "p-intent" || report.Kept[0].Detail != "named in sites/wh.json" {
+		t.Errorf("Kept = %+v", report.Kept)
+	}
+
+	report = findUnusedOrgObjects(objs, nil, []string{cleanupKindRF})
+	if len(report.Unused) != 1 || report.Unused[0].ID != "rf-unused" || len(report.Kept) != 0 {
+		t.Errorf("type rf: %+v", report)
+	}
+}
+
+func TestParseCleanupOrgArgs(t *testing.T) {
+	parsed, err := parseCleanupOrgArgs([]string{"target", "mist-prod", "type", "rf", "type", "RF", "delete"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Target != "mist-prod" || len(parsed.Kinds) != 1 || !parsed.Delete || parsed.Force {
+		t.Errorf("parsed = %+v", parsed)
+	}
+	if _, err := parseCleanupOrgArgs([]string{"type", "gateway"}); err == nil {
+		t.Error("want an error for an unknown type")
+	}
+}
+
+// TestCleanupOrgInitializesAPIs runs "cleanup org" through Execute, so the
+// command gets whatever its init tier sets up. With only a Meraki API
+// configured it must find that API and refuse it as unsupported; a tier that
+// skips API init reports no API configured instead.
+func TestCleanupOrgInitializesAPIs(t *testing.T) {
+	dir := t.TempDir()
+	for _, env := range []string{"XDG_CONFIG_HOME", "XDG_CACHE_HOME", "XDG_DATA_HOME", "XDG_STATE_HOME"} {
+		t.Setenv(env, filepath.Join(dir, strings.ToLower(env)))
+	}
+	cfgPath := filepath.Join(dir, "wifimgr-config.json")
+	cfg := `{
+  "version": 1,
+  "files": {"config_dir": "` + dir + `", "cache_dir": "` + filepath.Join(dir, "cache") + `"},
+  "api": {"meraki-lab": {"vendor": "meraki", "credentials": {"org_id": "L_1", "api_key": "test"}}}
+}`
+	if err := os.WriteFile(cfgPath, []byte(cfg), 0600); err != nil {
+		t.Fatal(err)
+	}
+	prevRegistry, prevManager, prevAccessor := apiRegistry, cacheManager, cacheAccessor
+	prevConfig, prevVendorClient := globalConfig, globalVendorClient
+	prevGlobalRegistry, prevGlobalAccessor := vendors.GetGlobalRegistry(), vendors.GetGlobalCacheAccessor()
+	t.Cleanup(func() {
+		configFile = ""
+		viper.Reset()
+		apiRegistry, cacheManager, cacheAccessor = prevRegistry, prevManager, prevAccessor
+		globalConfig, globalVendorClient = prevConfig, prevVendorClient
+		vendors.SetGlobalRegistry(prevGlobalRegistry)
+		vendors.SetGlobalCacheAccessor(prevGlobalAccessor)
+	})
+
+	rootCmd.SetArgs([]string{"--config", cfgPath, "cleanup", "org"})
+	err := Execute(context.Background())
+
+	var unsupported *vendors.CapabilityNotSupportedError
+	if !errors.As(err, &unsupported) || unsupported.APILabel != "meraki-lab" {
+		t.Fatalf("cleanup org error = %v, want capability not supported for meraki-lab", err)
+	}
+}
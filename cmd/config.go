@@ -0,0 +1,38 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+)
+
+// configCmd groups commands that maintain the config files themselves.
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Maintain the site config files",
+	Long: `Maintain the site config files listed in files.site_configs, such as
+migrating them to the current schema version.`,
+	Example: `  wifimgr config upgrade diff`,
+	Annotations: map[string]string{
+		cmdutils.AnnotationNeedsConfig: "true",
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+}
@@ -0,0 +1,44 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ravinald/wifimgr/cmd/apply"
+	"github.com/ravinald/wifimgr/internal/config"
+)
+
+// applyRewriteWithBackups backs up each of paths to the backup directory, then
+// runs write to rewrite the planned files. op names the change in errors
+// ("rename", "upgrade"). A failed backup stops before anything is written; a
+// failed write that could not be fully rolled back says so, since the error
+// then names files left changed.
+func applyRewriteWithBackups(op string, paths []string, write func() error) error {
+	for _, path := range paths {
+		if err := apply.CreateConfigBackup(globalConfig, path); err != nil {
+			return fmt.Errorf("backup of %s failed, nothing changed: %w", path, err)
+		}
+	}
+	if err := write(); err != nil {
+		if errors.Is(err, config.ErrRollbackIncomplete) {
+			return fmt.Errorf("%s failed partway and was not fully undone; restore the files named in this error from their backups: %w", op, err)
+		}
+		return fmt.Errorf("%s failed, no files changed: %w", op, err)
+	}
+	return nil
+}
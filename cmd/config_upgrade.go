@@ -0,0 +1,116 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/i18n"
+	"github.com/ravinald/wifimgr/internal/logging"
+	"github.com/ravinald/wifimgr/internal/symbols"
+)
+
+// configUpgradeCmd represents the "config upgrade" command
+var configUpgradeCmd = &cobra.Command{
+	Use:   "upgrade [diff] [force]",
+	Short: "Migrate site config files to the current schema version",
+	Long: `Migrate every file in files.site_configs to the schema version this wifimgr
+uses, applying field renames and structure changes so they need no hand edits.
+
+Current migrations (version 1):
+  - a missing "version" is stamped
+  - the deprecated AP "config" block moves to radio_config and led
+    (tx_power becomes power; zero values, which meant unset, are dropped)
+  - the deprecated top-level AP vlan_id moves to ip_config.vlan_id
+
+A value already set in the new location is kept and the legacy one dropped.
+Files written by a newer wifimgr are refused. Every file is prepared in memory
+first; each changed file is backed up to the backup directory, then written
+atomically. If any write fails, the files already written are restored.
+
+Arguments:
+  diff         Optional. Show what would change without writing
+  force        Optional. Skip the confirmation prompt`,
+	Example: `  wifimgr config upgrade diff
+  wifimgr config upgrade
+  wifimgr config upgrade force`,
+	Annotations: map[string]string{
+		cmdutils.AnnotationNeedsConfig: "true",
+	},
+	RunE: runConfigUpgrade,
+}
+
+func init() {
+	configCmd.AddCommand(configUpgradeCmd)
+}
+
+func runConfigUpgrade(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+
+	var diffOnly, force bool
+	for _, arg := range args {
+		switch strings.ToLower(arg) {
+		case "diff":
+			diffOnly = true
+		case "force":
+			force = true
+		default:
+			return fmt.Errorf("unexpected argument: %s", arg)
+		}
+	}
+
+	plan, err := config.PlanConfigUpgrade(globalConfig.Files.SiteConfigs, globalConfig.Files.ConfigDir)
+	if err != nil {
+		return err
+	}
+	if len(plan.Files) == 0 {
+		fmt.Printf("%s All %d site config file(s) are at version %d\n",
+			symbols.SuccessPrefix(), len(globalConfig.Files.SiteConfigs), config.SiteConfigVersion)
+		return nil
+	}
+
+	for _, f := range plan.Files {
+		fmt.Printf("%s (version %d -> %d)\n", f.File, f.From, f.To)
+		for _, c := range f.Changes {
+			fmt.Printf("  %s\n", c)
+		}
+	}
+	if diffOnly {
+		return nil
+	}
+	if !force {
+		fmt.Printf("%s %s ", i18n.T("config_upgrade.confirm", len(plan.Files), config.SiteConfigVersion), i18n.T("prompt.yes_no"))
+		if !confirmPrompt() {
+			fmt.Println(i18n.T("config_upgrade.cancelled"))
+			return nil
+		}
+	}
+
+	if err := applyRewriteWithBackups("upgrade", plan.Paths(), plan.Apply); err != nil {
+		return err
+	}
+	logging.Infof("Upgraded %d site config file(s) to version %d", len(plan.Files), config.SiteConfigVersion)
+	fmt.Printf("%s Upgraded %d site config file(s) to version %d\n",
+		symbols.SuccessPrefix(), len(plan.Files), config.SiteConfigVersion)
+	return nil
+}
@@ -0,0 +1,76 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ravinald/wifimgr/api"
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+// Deps carries what a command handler needs from the process: the request
+// context, the loaded config, the vendor clients and the cache. Handlers that
+// take a *Deps instead of reading the package globals can be exercised in unit
+// tests with fake clients and a temporary cache.
+//
+// RunE functions build one with currentDeps() and pass it down; new handler
+// logic should accept it rather than reach for globalConfig, GetAPIRegistry()
+// or GetCacheManager() directly.
+type Deps struct {
+	Ctx      context.Context
+	Config   *config.Config
+	Registry *vendors.APIClientRegistry
+	Cache    *vendors.CacheAccessor
+	// Legacy is the single-Mist api.Client; nil when no Mist API is configured.
+	Legacy api.Client
+}
+
+// currentDeps returns the dependencies initialized for this process.
+func currentDeps() *Deps {
+	return &Deps{
+		Ctx:      globalContext,
+		Config:   globalConfig,
+		Registry: apiRegistry,
+		Cache:    cacheAccessor,
+		Legacy:   globalClient,
+	}
+}
+
+// Client returns the vendor client registered under apiLabel.
+func (d *Deps) Client(apiLabel string) (vendors.Client, error) {
+	if d.Registry == nil {
+		return nil, fmt.Errorf("API registry not initialized")
+	}
+	return d.Registry.GetClient(apiLabel)
+}
+
+// ResolveSite maps a site name or ID to its owning API using d.Cache; see
+// cmdutils.ResolveSite.
+func (d *Deps) ResolveSite(identifier, apiLabel string) (*cmdutils.SiteRef, error) {
+	return cmdutils.ResolveSiteWith(d.Cache, identifier, apiLabel)
+}
+
+// CacheManager returns the cache manager behind d.Cache, or nil.
+func (d *Deps) CacheManager() *vendors.CacheManager {
+	if d.Cache == nil {
+		return nil
+	}
+	return d.Cache.GetManager()
+}
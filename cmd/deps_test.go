@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+// newTestDeps builds Deps around fake clients and a temporary cache seeded
+// with each API's sites, so handlers run without the process globals.
+func newTestDeps(t *testing.T, cfg *config.Config, clients map[string]vendors.Client, sites map[string][]vendors.SiteInfo) *Deps {
+	t.Helper()
+	registry := vendors.NewAPIClientRegistry()
+	for label, client := range clients {
+		registry.RegisterClient(label, client, nil)
+	}
+	cm := vendors.NewCacheManager(t.TempDir(), registry)
+	if err := cm.Initialize(); err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+	for label, list := range sites {
+		cache := vendors.NewAPICache(label, clients[label].VendorName(), clients[label].OrgID())
+		cache.Sites.Info = list
+		if err := cm.SaveAPICache(cache); err != nil {
+			t.Fatalf("SaveAPICache(%s): %v", label, err)
+		}
+	}
+	if err := cm.RebuildIndex(); err != nil {
+		t.Fatalf("RebuildIndex: %v", err)
+	}
+	return &Deps{
+		Ctx:      context.Background(),
+		Config:   cfg,
+		Registry: registry,
+		Cache:    vendors.NewCacheAccessor(cm),
+	}
+}
+
+func TestCollectFirmwareComplianceWithFakes(t *testing.T) {
+	dir := t.TempDir()
+	site := `{"version": 1, "config": {"sites": {"lab": {
+	  "site_config": {"name": "US-LAB-01"},
+	  "firmware": {"AP43": "0.14.1"}
+	}}}}`
+	if err := os.WriteFile(filepath.Join(dir, "sites.json"), []byte(site), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{}
+	cfg.Files.ConfigDir = dir
+	cfg.Files.SiteConfigs = []string{"sites.json"}
+
+	client := vendors.NewMockClient("mist", "org-1")
+	client.SetDevicesService(&vendors.MockDevicesService{Devices: []*vendors.DeviceInfo{
+		{ID: "d1", MAC: "aa0000000001", Name: "ap-ok", Model: "AP43", Type: "ap", SiteID: "site-1", Version: "0.14.1"},
+		{ID: "d2", MAC: "aa0000000002", Name: "ap-old", Model: "AP43", Type: "ap", SiteID: "site-1", Version: "0.12.0"},
+		{ID: "d3", MAC: "aa0000000003", Name: "ap-elsewhere", Model: "AP43", Type: "ap", SiteID: "site-2", Version: "0.12.0"},
+	}})
+	deps := newTestDeps(t, cfg,
+		map[string]vendors.Client{"mist-lab": client},
+		map[string][]vendors.SiteInfo{"mist-lab": {{ID: "site-1", Name: "US-LAB-01"}}})
+
+	ref, _, rows, err := collectFirmwareCompliance(deps, "us-lab-01", "")
+	if err != nil {
+		t.Fatalf("collectFirmwareCompliance: %v", err)
+	}
+	if ref.APILabel != "mist-lab" || ref.SiteID != "site-1" {
+		t.Errorf("ref = %+v", ref)
+	}
+	if len(rows) != 2 || rows[0].Name != "ap-old" || rows[0].Status != firmwareOutdated {
+		t.Errorf("rows = %+v", rows)
+	}
+
+	// A vendor without a devices service is reported, not dereferenced.
+	client.SetDevicesService(nil)
+	_, _, _, err = collectFirmwareCompliance(deps, "US-LAB-01", "")
+	if err == nil || !strings.Contains(err.Error(), "not available with this API (mist-lab:mist)") {
+		t.Errorf("err = %v", err)
+	}
+}
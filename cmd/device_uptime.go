@@ -0,0 +1,106 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"context"
+	"time"
+
+	"github.com/ravinald/wifimgr/internal/logging"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+// rebootLookback is how far back the uptime columns look for a device's last
+// reboot reason.
+const rebootLookback = 7 * 24 * time.Hour
+
+// deviceUptime is one device's live uptime and most recent recorded reboot.
+type deviceUptime struct {
+	Uptime     time.Duration
+	LastReboot *vendors.DeviceReboot
+}
+
+// uptimeLookup fetches live device stats and reboot events a site at a time,
+// on first use, for the optional uptime columns of the show commands. Sites
+// whose API has no stats or events service yield nothing.
+type uptimeLookup struct {
+	ctx   context.Context
+	sites map[string]map[string]deviceUptime // "api/site-id" -> MAC -> uptime
+}
+
+func newUptimeLookup(ctx context.Context) *uptimeLookup {
+	return &uptimeLookup{ctx: ctx, sites: make(map[string]map[string]deviceUptime)}
+}
+
+// get returns the uptime of the device with normalized MAC mac at siteID.
+func (u *uptimeLookup) get(apiLabel, siteID, mac string) (deviceUptime, bool) {
+	key := apiLabel + "/" + siteID
+	devices, ok := u.sites[key]
+	if !ok {
+		devices = u.fetch(apiLabel, siteID)
+		u.sites[key] = devices
+	}
+	d, ok := devices[mac]
+	return d, ok
+}
+
+func (u *uptimeLookup) fetch(apiLabel, siteID string) map[string]deviceUptime {
+	devices := make(map[string]deviceUptime)
+	registry := GetAPIRegistry()
+	if registry == nil || siteID == "" {
+		return devices
+	}
+	client, err := registry.GetClient(apiLabel)
+	if err != nil {
+		return devices
+	}
+
+	if svc := client.DeviceStats(); svc != nil {
+		stats, err := svc.ListBySite(u.ctx, siteID)
+		if err != nil {
+			logging.Warnf("Failed to fetch device stats for site %s (%s): %v", siteID, apiLabel, err)
+		}
+		for _, st := range stats {
+			if st.Uptime > 0 {
+				devices[st.MAC] = deviceUptime{Uptime: time.Duration(st.Uptime) * time.Second}
+			}
+		}
+	}
+	if svc := client.DeviceEvents(); svc != nil {
+		end := time.Now()
+		reboots, err := svc.Reboots(u.ctx, siteID, end.Add(-rebootLookback), end)
+		if err != nil {
+			logging.Warnf("Failed to fetch reboot events for site %s (%s): %v", siteID, apiLabel, err)
+		}
+		for mac, ev := range latestReboots(reboots) {
+			d := devices[mac]
+			d.LastReboot = ev
+			devices[mac] = d
+		}
+	}
+	return devices
+}
+
+// latestReboots returns each device's most recent reboot.
+func latestReboots(reboots []*vendors.DeviceReboot) map[string]*vendors.DeviceReboot {
+	latest := make(map[string]*vendors.DeviceReboot)
+	for _, ev := range reboots {
+		if cur, ok := latest[ev.MAC]; !ok || ev.Timestamp.After(cur.Timestamp) {
+			latest[ev.MAC] = ev
+		}
+	}
+	return latest
+}
@@ -0,0 +1,33 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// devtoolsCmd groups commands for contributors and bug reporters.
+var devtoolsCmd = &cobra.Command{
+	Use:   "devtools",
+	Short: "Tools for reporting and reproducing bugs",
+	Long: `Tools for contributors and for users reporting a bug: they gather what a
+maintainer needs to reproduce a problem without access to your org.`,
+	Example: `  wifimgr devtools dump-fixtures site US-LAB-01 sanitize`,
+}
+
+func init() {
+	rootCmd.AddCommand(devtoolsCmd)
+}
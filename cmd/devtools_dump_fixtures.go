@@ -0,0 +1,191 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/fixtures"
+	"github.com/ravinald/wifimgr/internal/runstats"
+	"github.com/ravinald/wifimgr/internal/symbols"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+// devtoolsDumpFixturesCmd represents the "devtools dump-fixtures" command
+var devtoolsDumpFixturesCmd = &cobra.Command{
+	Use:   "dump-fixtures site <site-name> [target <api-label>] [sanitize] [dir <path>]",
+	Short: "Export one site's raw API payloads as test fixtures",
+	Long: `Fetch one site's data from its API — the site, inventory, devices, device
+configs, WLANs, and device statuses — and write each raw response to a file,
+with a manifest.json recording the request it answered. Attach the directory
+to an issue about a vendor payload wifimgr parses wrong, and a maintainer can
+replay it in a test.
+
+Org-wide lists are trimmed to the site's entries. Secrets (API keys, PSKs,
+RADIUS secrets) are always redacted. With sanitize, identifying values are
+also replaced with stable stand-ins: UUIDs, MACs, and IPs (mapped into the
+documentation ranges), and names, serials, hostnames, emails, addresses, and
+SSIDs; coordinates are zeroed. The same value gets the same stand-in in every
+file, so references between payloads still match. Review the files before
+sharing them.
+
+A call that fails is recorded in the manifest and skipped.
+
+Arguments:
+  site <name>      Required. Site to export
+  target <label>   Optional. API owning the site (when the name is ambiguous)
+  sanitize         Optional. Pseudonymize identifying values
+  dir <path>       Optional. Output directory (default: fixtures/<vendor>/<site>)`,
+	Example: `  wifimgr devtools dump-fixtures site US-LAB-01 sanitize
+  wifimgr devtools dump-fixtures site US-LAB-01 target meraki-prod sanitize dir /tmp/lab-fixtures`,
+	RunE: runDevtoolsDumpFixtures,
+}
+
+func init() {
+	devtoolsCmd.AddCommand(devtoolsDumpFixturesCmd)
+}
+
+func runDevtoolsDumpFixtures(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+	var siteName, target, dir string
+	sanitize := false
+	for i := 0; i < len(args); i++ {
+		switch strings.ToLower(args[i]) {
+		case "site", "target", "dir":
+			if i+1 >= len(args) {
+				return fmt.Errorf("'%s' requires a value", args[i])
+			}
+			value := cmdutils.StripQuotes(args[i+1])
+			switch strings.ToLower(args[i]) {
+			case "site":
+				siteName = value
+			case "target":
+				target = value
+			case "dir":
+				dir = value
+			}
+			i++
+		case "sanitize":
+			sanitize = true
+		default:
+			return fmt.Errorf("unknown argument %q", args[i])
+		}
+	}
+	if siteName == "" {
+		return fmt.Errorf("requires 'site <name>'")
+	}
+
+	ref, err := cmdutils.ResolveSite(siteName, target)
+	if err != nil {
+		return err
+	}
+	registry := GetAPIRegistry()
+	if registry == nil {
+		return fmt.Errorf("API registry not initialized")
+	}
+
+	// The shared client was built before capture was on, so its transport
+	// does not record; build a fresh one for the export.
+	var rec fixtures.Recorder
+	runstats.SetCapture(rec.Record)
+	defer runstats.SetCapture(nil)
+	client, err := registry.NewClient(ref.APILabel)
+	if err != nil {
+		return err
+	}
+
+	errs := fetchSiteFixtures(client, ref.SiteID)
+
+	if dir == "" {
+		dir = filepath.Join("fixtures", client.VendorName(), ref.Name)
+	}
+	var s *fixtures.Sanitizer
+	if sanitize {
+		s = fixtures.NewSanitizer()
+	}
+	m, err := fixtures.Write(dir, rec.Fixtures(), ref.SiteID, s, fixtures.Manifest{
+		Vendor: client.VendorName(),
+		Site:   ref.Name,
+		Errors: errs,
+	})
+	if err != nil {
+		return fmt.Errorf("write fixtures: %w", err)
+	}
+
+	for _, e := range errs {
+		fmt.Printf("%s %s\n", symbols.WarningPrefix(), e)
+	}
+	fmt.Printf("%s Wrote %d fixture(s) and manifest.json to %s\n", symbols.SuccessPrefix(), len(m.Fixtures), dir)
+	if !sanitize {
+		fmt.Printf("%s Not sanitized: names, MACs, and IPs are as the API returned them. Add 'sanitize' before sharing.\n", symbols.WarningPrefix())
+	}
+	return nil
+}
+
+// fetchSiteFixtures makes the read calls whose responses make up a site's
+// fixtures. Responses are captured by the client's transport, so the results
+// are discarded; failures are returned as messages for the manifest.
+func fetchSiteFixtures(client vendors.Client, siteID string) []string {
+	ctx := globalContext
+	var errs []string
+	note := func(what string, err error) {
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", what, err))
+		}
+	}
+
+	_, err := client.Sites().Get(ctx, siteID)
+	note("site", err)
+	for _, deviceType := range []string{"ap", "switch", "gateway"} {
+		_, err := client.Inventory().List(ctx, deviceType)
+		note("inventory "+deviceType, err)
+	}
+	devices, err := client.Devices().List(ctx, siteID, "")
+	note("devices", err)
+
+	if configs := client.Configs(); configs != nil {
+		for _, d := range devices {
+			var err error
+			switch d.Type {
+			case "ap":
+				_, err = configs.GetAPConfig(ctx, siteID, d.ID)
+			case "switch":
+				_, err = configs.GetSwitchConfig(ctx, siteID, d.ID)
+			case "gateway":
+				_, err = configs.GetGatewayConfig(ctx, siteID, d.ID)
+			default:
+				continue
+			}
+			note(d.Type+" config "+d.ID, err)
+		}
+	}
+	if wlans := client.WLANs(); wlans != nil {
+		_, err := wlans.ListBySite(ctx, siteID)
+		note("wlans", err)
+	}
+	if statuses := client.Statuses(); statuses != nil {
+		_, err := statuses.GetAll(ctx)
+		note("statuses", err)
+	}
+	return errs
+}
@@ -0,0 +1,129 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ravinald/wifimgr/cmd/apply"
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/symbols"
+)
+
+// diffCmd groups read-only comparisons of intent against the API.
+var diffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Compare intent against the current API config",
+	Long: `Compare what the site configs declare against what the API cache holds,
+without pushing anything.`,
+	Example: `  wifimgr diff device 5c:5b:35:8e:4c:f9`,
+	Annotations: map[string]string{
+		cmdutils.AnnotationNeedsConfig: "true",
+	},
+}
+
+// diffDeviceCmd represents the "diff device" command
+var diffDeviceCmd = &cobra.Command{
+	Use:   "device <mac> [target <api-label>]",
+	Short: "Show the managed-key diff for a single device",
+	Long: `Show the managed-key diff for one device: its intent from the site config
+that declares it (templates and overrides expanded) against its current
+config in the API cache. This is the comparison "apply site <name> ap diff"
+makes, for just this device. Nothing is pushed.
+
+The API is the device's own "api" field if set, else the site's API. Use
+target to override it.
+
+Arguments:
+  mac          Required. MAC address of the device (any common format)
+  target       Optional. API label to compare against`,
+	Example: `  wifimgr diff device 5c:5b:35:8e:4c:f9
+  wifimgr diff device 5c5b358e4cf9 target mist-prod`,
+	Args: func(_ *cobra.Command, args []string) error {
+		if cmdutils.ContainsHelp(args) {
+			return nil
+		}
+		if len(args) < 1 {
+			return fmt.Errorf("requires a device MAC address")
+		}
+		return nil
+	},
+	RunE: runDiffDevice,
+}
+
+func init() {
+	diffCmd.AddCommand(diffDeviceCmd)
+	rootCmd.AddCommand(diffCmd)
+}
+
+func runDiffDevice(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+
+	mac := cmdutils.StripQuotes(args[0])
+	var target string
+	for i := 1; i < len(args); i++ {
+		switch strings.ToLower(args[i]) {
+		case "target":
+			if i+1 >= len(args) {
+				return fmt.Errorf("'target' requires an API label")
+			}
+			target = args[i+1]
+			i++
+		default:
+			return fmt.Errorf("unexpected argument: %s", args[i])
+		}
+	}
+	if target != "" {
+		SetAPITarget(target)
+		if err := ValidateAPIFlag(); err != nil {
+			return err
+		}
+	}
+
+	ref, err := apply.FindDeviceIntent(globalConfig, mac, func(siteName string) string {
+		label, err := ResolveAPIForSite(siteName, nil)
+		if err != nil {
+			return ""
+		}
+		return label
+	})
+	if err != nil {
+		return err
+	}
+	if target != "" {
+		ref.API = target
+	}
+	if ref.API == "" {
+		if ref.API, err = ValidateMultiVendorApply(globalContext, ref.SiteName, nil); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("Device %s: %s at site %s (API %s)\n", ref.MAC, ref.DeviceType, ref.SiteName, ref.API)
+	differs, err := apply.DiffDevice(globalContext, vendorClientForApply(ref.API), globalConfig, ref)
+	if err != nil {
+		return err
+	}
+	if !differs {
+		fmt.Printf("%s %s matches intent\n", symbols.SuccessPrefix(), ref.MAC)
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ravinald/wifimgr/cmd/apply"
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/intent"
+	"github.com/ravinald/wifimgr/internal/logging"
+	"github.com/ravinald/wifimgr/internal/symbols"
+)
+
+// editCmd groups the in-place editors for intent files.
+var editCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Edit intent files in $EDITOR with validation",
+	Long: `Open an intent file in $EDITOR, validate it when the editor exits, and show
+the diff before anything is written.`,
+	Example: `  wifimgr edit site US-LAB-01`,
+}
+
+// editSiteCmd represents the "edit site" command
+var editSiteCmd = &cobra.Command{
+	Use:   "site <site-name>",
+	Short: "Edit a site's config file at the site block",
+	Long: `Open the config file that declares a site in $VISUAL or $EDITOR (default vi),
+positioned at the site's block. The edit happens on a scratch copy. When the
+editor exits the copy is checked: it must be valid JSON, still declare the
+site, and pass the site-config schema. An invalid edit is never written — you
+can re-open the editor to fix it, or stop and keep the scratch copy.
+
+A valid edit is shown as a diff against the current file and written after
+you confirm. The file is backed up first, so 'apply rollback' can recover it.
+Nothing is pushed: review and apply with 'apply site <name> <type> diff'.
+
+Arguments:
+  site-name   Required. Site whose config file to edit`,
+	Example: `  wifimgr edit site US-LAB-01
+  EDITOR="code --wait" wifimgr edit site US-LAB-01`,
+	Args: func(_ *cobra.Command, args []string) error {
+		if cmdutils.ContainsHelp(args) {
+			return nil
+		}
+		if len(args) != 1 {
+			return fmt.Errorf("requires a site name")
+		}
+		return nil
+	},
+	RunE: runEditSite,
+}
+
+func init() {
+	editCmd.AddCommand(editSiteCmd)
+	rootCmd.AddCommand(editCmd)
+}
+
+// lineArgEditors take a "+N" argument to open the file at line N.
+var lineArgEditors = map[string]bool{
+	"vi": true, "vim": true, "nvim": true, "view": true, "nano": true,
+	"emacs": true, "emacsclient": true, "micro": true, "kak": true, "joe": true,
+}
+
+// editorCommand builds the command that opens path at line, from $VISUAL or
+// $EDITOR (which may carry arguments, e.g. "code --wait"), defaulting to vi.
+func editorCommand(path string, line int) (string, []string) {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	fields := strings.Fields(editor)
+	if len(fields) == 0 {
+		fields = []string{"vi"}
+	}
+	args := fields[1:]
+	if line > 0 && lineArgEditors[filepath.Base(fields[0])] {
+		args = append(args, fmt.Sprintf("+%d", line))
+	}
+	return fields[0], append(args, path)
+}
+
+func runEditSite(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+	if cmdutils.NoInput() || !isInteractive() {
+		return fmt.Errorf("edit needs an interactive terminal")
+	}
+
+	siteName := cmdutils.StripQuotes(args[0])
+	path, ok := config.GetSiteConfigFullPath(siteName)
+	if !ok {
+		return fmt.Errorf("edit: site %q not found in any configured site file", siteName)
+	}
+	siteKey, ok := config.GetSiteConfigKey(siteName)
+	if !ok {
+		return fmt.Errorf("edit: site %q has no config key", siteName)
+	}
+
+	original, err := os.ReadFile(path) // #nosec G304 -- path from operator-controlled config
+	if err != nil {
+		return fmt.Errorf("edit: read %s: %w", path, err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("edit: stat %s: %w", path, err)
+	}
+
+	// Edit a scratch copy so the real file is only replaced by a validated
+	// version. It keeps the .json name so the editor picks JSON highlighting.
+	scratch, err := os.CreateTemp("", "wifimgr-edit-*-"+filepath.Base(path))
+	if err != nil {
+		return fmt.Errorf("edit: create scratch copy: %w", err)
+	}
+	scratchPath := scratch.Name()
+	keepScratch := false
+	defer func() {
+		if !keepScratch {
+			_ = os.Remove(scratchPath)
+		}
+	}()
+	if _, err := scratch.Write(original); err != nil {
+		_ = scratch.Close()
+		return fmt.Errorf("edit: write scratch copy: %w", err)
+	}
+	if err := scratch.Close(); err != nil {
+		return fmt.Errorf("edit: write scratch copy: %w", err)
+	}
+
+	line := config.EstimateLineNumber(original, []string{"config", "sites", siteKey})
+	var edited []byte
+	for {
+		name, editorArgs := editorCommand(scratchPath, line)
+		editor := exec.Command(name, editorArgs...) // #nosec G204 -- the operator's own $EDITOR
+		editor.Stdin, editor.Stdout, editor.Stderr = os.Stdin, os.Stdout, os.Stderr
+		if err := editor.Run(); err != nil {
+			keepScratch = true
+			return fmt.Errorf("edit: editor %s failed: %w (your edit is kept in %s)", name, err, scratchPath)
+		}
+
+		edited, err = os.ReadFile(scratchPath) // #nosec G304 -- scratch file created above
+		if err != nil {
+			return fmt.Errorf("edit: read scratch copy: %w", err)
+		}
+		if string(edited) == string(original) {
+			fmt.Println("No changes.")
+			return nil
+		}
+
+		verr := intent.ValidateSiteConfigFile(edited, siteKey, schemasDir())
+		if verr == nil {
+			break
+		}
+		fmt.Printf("%s %v\n", symbols.FailurePrefix(), verr)
+		fmt.Print("Re-open the editor to fix it? [y/N]: ")
+		if !confirmPrompt() {
+			keepScratch = true
+			return fmt.Errorf("edit: refusing to write invalid config to %s (your edit is kept in %s)", path, scratchPath)
+		}
+	}
+
+	fmt.Printf("\n--- %s\n+++ %s (edited)\n", path, path)
+	for _, l := range intent.DiffLines(string(original), string(edited), 3) {
+		switch {
+		case strings.HasPrefix(l, "@@"):
+			fmt.Println(symbols.BlueText(l))
+		case strings.HasPrefix(l, "-"):
+			fmt.Println(symbols.RedText(l))
+		case strings.HasPrefix(l, "+"):
+			fmt.Println(symbols.GreenText(l))
+		default:
+			fmt.Println(l)
+		}
+	}
+	fmt.Printf("\nWrite changes to %s? [y/N]: ", path)
+	if !confirmPrompt() {
+		fmt.Println("Discarded; the file was not changed.")
+		return nil
+	}
+
+	// Back up before replacing so apply rollback can recover the prior intent.
+	if globalConfig != nil {
+		if err := apply.CreateConfigBackup(globalConfig, path); err != nil {
+			logging.Warnf("edit: backup failed, continuing without one: %v", err)
+		}
+	}
+	if err := os.WriteFile(path, edited, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("edit: write %s: %w", path, err)
+	}
+	logging.Infof("edit: wrote %s (site %s)", path, siteName)
+	fmt.Printf("%s Wrote %s\nReview and push:\n  wifimgr apply site %s ap diff\n", symbols.SuccessPrefix(), path, siteName)
+	return nil
+}
@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestEditorCommand(t *testing.T) {
+	cases := []struct {
+		visual, editor string
+		wantName       string
+		wantArgs       []string
+	}{
+		{"", "", "vi", []string{"+12", "/tmp/site.json"}},
+		{"", "nano", "nano", []string{"+12", "/tmp/site.json"}},
+		{"nvim", "nano", "nvim", []string{"+12", "/tmp/site.json"}},
+		{"", "code --wait", "code", []string{"--wait", "/tmp/site.json"}},
+		{"", "/usr/local/bin/vim -u NONE", "/usr/local/bin/vim", []string{"-u", "NONE", "+12", "/tmp/site.json"}},
+	}
+	for _, c := range cases {
+		t.Setenv("VISUAL", c.visual)
+		t.Setenv("EDITOR", c.editor)
+		name, args := editorCommand("/tmp/site.json", 12)
+		if name != c.wantName || !slices.Equal(args, c.wantArgs) {
+			t.Errorf("VISUAL=%q EDITOR=%q: got %s %v, want %s %v", c.visual, c.editor, name, args, c.wantName, c.wantArgs)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ravinald/wifimgr/cmd/apply"
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/formatter"
+	"github.com/ravinald/wifimgr/internal/symbols"
+)
+
+// explainCmd groups commands that show how intent is resolved.
+var explainCmd = &cobra.Command{
+	Use:   "explain",
+	Short: "Show where a device's effective config values come from",
+	Long: `Show how intent is resolved into the config apply would push, and which
+template or site config set each value.`,
+	Example: `  wifimgr explain device 5c:5b:35:8e:4c:f9`,
+	Annotations: map[string]string{
+		cmdutils.AnnotationNeedsConfig: "true",
+	},
+}
+
+// explainDeviceCmd represents the "explain device" command
+var explainDeviceCmd = &cobra.Command{
+	Use:   "device <mac> [target <api-label>] [format json]",
+	Short: "Show each effective config field of a device and its source",
+	Long: `Resolve one device's intent the way apply does and print every final field
+with the layer that set it:
+
+  model               the model template for the device's hardware model
+  device_template     the device template named by device_template
+  security_baseline   the switch security baseline, translated to ports
+  radio_profile       the radio template named by radio_profile
+  site wlan           a WLAN template picked by the site's WLAN list
+  device wlan         a WLAN template picked by the device's own wlan list
+  device              the device's entry in its site config
+  derived             computed during expansion (e.g. radio bands enabled)
+
+Later layers override earlier ones. A value taken from a template's vendor
+block (e.g. "mist:") is marked as such, and each source names its file and
+the approximate line. Fields the target API cannot apply are listed as
+skipped. Nothing is read from or pushed to the API.
+
+Arguments:
+  mac          Required. MAC address of the device (any common format)
+  target       Optional. API label to resolve for (selects vendor blocks)
+  format       Optional. "json" (default: table)`,
+	Example: `  wifimgr explain device 5c:5b:35:8e:4c:f9
+  wifimgr explain device 5c5b358e4cf9 target meraki-corp
+  wifimgr explain device 5c5b358e4cf9 format json`,
+	Args: func(_ *cobra.Command, args []string) error {
+		if cmdutils.ContainsHelp(args) {
+			return nil
+		}
+		if len(args) < 1 {
+			return fmt.Errorf("requires a device MAC address")
+		}
+		return nil
+	},
+	RunE: runExplainDevice,
+}
+
+func init() {
+	explainCmd.AddCommand(explainDeviceCmd)
+	rootCmd.AddCommand(explainCmd)
+}
+
+func runExplainDevice(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+
+	mac := cmdutils.StripQuotes(args[0])
+	var target, format string
+	for i := 1; i < len(args); i++ {
+		switch strings.ToLower(args[i]) {
+		case "target":
+			if i+1 >= len(args) {
+				return fmt.Errorf("'target' requires an API label")
+			}
+			target = args[i+1]
+			i++
+		case "format":
+			if i+1 >= len(args) {
+				return fmt.Errorf("'format' requires a format type (json)")
+			}
+			if f := strings.ToLower(args[i+1]); f != "json" {
+				return fmt.Errorf("invalid format %q: must be 'json'", args[i+1])
+			}
+			format = "json"
+			i++
+		default:
+			return fmt.Errorf("unexpected argument: %s", args[i])
+		}
+	}
+	if target != "" {
+		SetAPITarget(target)
+		if err := ValidateAPIFlag(); err != nil {
+			return err
+		}
+	}
+
+	ref, err := apply.FindDeviceIntent(globalConfig, mac, func(siteName string) string {
+		label, err := ResolveAPIForSite(siteName, nil)
+		if err != nil {
+			return ""
+		}
+		return label
+	})
+	if err != nil {
+		return err
+	}
+	if target != "" {
+		ref.API = target
+	}
+
+	explanation, err := apply.ExplainDevice(globalConfig, ref)
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		out, err := json.MarshalIndent(struct {
+			*apply.DeviceRef
+			*apply.DeviceExplanation
+		}{ref, explanation}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal explanation: %w", err)
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+
+	api := ref.API
+	if api == "" {
+		api = "none; vendor blocks not applied"
+	}
+	title := fmt.Sprintf("Device %s: %s at site %s (API %s)", ref.MAC, ref.DeviceType, ref.SiteName, api)
+	if explanation.Model != "" {
+		title = fmt.Sprintf("Device %s: %s %s at site %s (API %s)", ref.MAC, explanation.Model, ref.DeviceType, ref.SiteName, api)
+	}
+	if len(explanation.Fields) == 0 {
+		fmt.Printf("%s:\n%s No config fields resolve for this device\n", title, symbols.WarningPrefix())
+		return nil
+	}
+
+	rows := make([]formatter.GenericTableData, 0, len(explanation.Fields))
+	for _, f := range explanation.Fields {
+		rows = append(rows, formatter.GenericTableData{
+			"field":    f.Path,
+			"value":    formatExplainValue(f.Value),
+			"source":   describeFieldSource(f.Source),
+			"location": describeFieldLocation(f.Source),
+		})
+	}
+	printer := formatter.NewGenericTablePrinter(formatter.TableConfig{
+		Title:         title,
+		BoldHeaders:   true,
+		ShowSeparator: true,
+		CommandPath:   "explain.device",
+		Columns: []formatter.TableColumn{
+			{Field: "field", Title: "Field"},
+			{Field: "value", Title: "Value", MaxWidth: 48},
+			{Field: "source", Title: "Source"},
+			{Field: "location", Title: "Location"},
+		},
+	}, rows)
+	fmt.Print(printer.Print())
+
+	if len(explanation.Skipped) > 0 {
+		fmt.Printf("\n%s Skipped in vendor translation (%s cannot apply them): %s\n",
+			symbols.WarningPrefix(), ref.API, strings.Join(explanation.Skipped, ", "))
+	}
+	return nil
+}
+
+// formatExplainValue renders a field value compactly, as JSON for anything
+// that is not a plain string.
+func formatExplainValue(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(out)
+}
+
+// describeFieldSource names the layer, e.g. `radio_profile "lab" (mist: block)`.
+func describeFieldSource(src config.FieldSource) string {
+	s := src.Layer
+	if src.Name != "" {
+		s += fmt.Sprintf(" %q", src.Name)
+	}
+	if src.Vendor != "" {
+		s += fmt.Sprintf(" (%s block)", src.Vendor)
+	}
+	return s
+}
+
+// describeFieldLocation renders file:line relative to the config dir.
+func describeFieldLocation(src config.FieldSource) string {
+	if src.File == "" {
+		return ""
+	}
+	file := src.File
+	if globalConfig != nil && globalConfig.Files.ConfigDir != "" {
+		if rel, err := filepath.Rel(globalConfig.Files.ConfigDir, file); err == nil && !strings.HasPrefix(rel, "..") {
+			file = rel
+		}
+	}
+	if src.Line > 0 {
+		return fmt.Sprintf("%s:%d", file, src.Line)
+	}
+	return file
+}
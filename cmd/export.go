@@ -24,9 +24,13 @@ var exportCmd = &cobra.Command{
 	Short: "Export data to external systems",
 	Long: `Export wifimgr device data to external systems.
 
-The export command provides integration with external DCIM/IPAM systems:
+The export command provides integration with external DCIM/IPAM systems
+and billing:
 
   netbox - Export device inventory to NetBox
+  usage  - Export monthly client counts and traffic as CSV
+  junos  - Render switch configs as Junos set commands
+  siem   - Send wireless intrusion events to a SIEM over syslog
 
 Use 'wifimgr export <subcommand> --help' for detailed information about each export target.`,
 	Example: `  # Export all devices to NetBox
@@ -36,7 +40,16 @@ Use 'wifimgr export <subcommand> --help' for detailed information about each exp
   wifimgr export netbox site US-LAB-01
 
   # Dry run (validate without writing)
-  wifimgr export netbox all dry-run`,
+  wifimgr export netbox all dry-run
+
+  # Per-site usage for June 2024
+  wifimgr export usage --month 2024-06 --per-site
+
+  # Switch configs of a site as Junos set commands
+  wifimgr export junos site US-LAB-01 dir ./junos
+
+  # Preview the last day's intrusion events as SIEM records
+  wifimgr export siem since 24h dry-run`,
 }
 
 func init() {
@@ -0,0 +1,217 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ravinald/wifimgr/cmd/apply"
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/symbols"
+	"github.com/ravinald/wifimgr/internal/vendors/mist"
+)
+
+var exportJunosCmd = &cobra.Command{
+	Use:   "junos <switch-mac> | site <site-name> [target <api-label>] [dir <path>]",
+	Short: "Render switch configs as Junos set commands",
+	Long: `Render the effective config of Mist switches — intent with templates and
+overrides expanded, as apply would push it — as Junos set-style commands, for
+review in a familiar syntax and for archiving device-level configs.
+
+This is best effort. Mist assembles the config it sends to a switch from
+site settings and its own defaults as well as the device object, so the
+output approximates the running config rather than reproducing it. Networks,
+port usages and port config, IRB addresses, DNS/NTP, static routes, RSTP
+bridge priority, DHCP snooping and additional_config_cmds are rendered; every
+other key is listed in a comment at the top. Nothing is read from the API.
+
+Arguments:
+  switch-mac     MAC address of one switch (any common format)
+  site <name>    Every switch declared at the site
+  target         Optional. API label to resolve for (selects vendor blocks)
+  dir            Optional. Write one <switch-name>.set file per switch to the
+                 directory instead of printing`,
+	Example: `  wifimgr export junos 5c:5b:35:8e:4c:f9
+  wifimgr export junos site US-LAB-01 dir ./junos
+  wifimgr export junos site US-LAB-01 target mist-prod`,
+	Args: func(_ *cobra.Command, args []string) error {
+		if cmdutils.ContainsHelp(args) {
+			return nil
+		}
+		if len(args) < 1 {
+			return fmt.Errorf("requires a switch MAC address or 'site <site-name>'")
+		}
+		return nil
+	},
+	RunE: runExportJunos,
+}
+
+func init() {
+	exportCmd.AddCommand(exportJunosCmd)
+}
+
+func runExportJunos(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+
+	var mac, site, target, dir string
+	for i := 0; i < len(args); i++ {
+		switch strings.ToLower(args[i]) {
+		case "site", "target", "dir":
+			if i+1 >= len(args) {
+				return fmt.Errorf("'%s' requires a value", strings.ToLower(args[i]))
+			}
+			value := cmdutils.StripQuotes(args[i+1])
+			switch strings.ToLower(args[i]) {
+			case "site":
+				site = value
+			case "target":
+				target = value
+			case "dir":
+				dir = value
+			}
+			i++
+		default:
+			if mac != "" {
+				return fmt.Errorf("unexpected argument: %s", args[i])
+			}
+			mac = cmdutils.StripQuotes(args[i])
+		}
+	}
+	if (mac == "") == (site == "") {
+		return fmt.Errorf("specify either a switch MAC address or 'site <site-name>'")
+	}
+	if target != "" {
+		SetAPITarget(target)
+		if err := ValidateAPIFlag(); err != nil {
+			return err
+		}
+	}
+
+	siteAPI := func(siteName string) string {
+		label, err := ResolveAPIForSite(siteName, nil)
+		if err != nil {
+			return ""
+		}
+		return label
+	}
+
+	var refs []apply.DeviceRef
+	if mac != "" {
+		ref, err := apply.FindDeviceIntent(globalConfig, mac, siteAPI)
+		if err != nil {
+			return err
+		}
+		if ref.DeviceType != "switch" {
+			return fmt.Errorf("device %s is declared as %s at site %s, not a switch", ref.MAC, ref.DeviceType, ref.SiteName)
+		}
+		refs = []apply.DeviceRef{*ref}
+	} else {
+		var err error
+		if refs, err = apply.SiteDeviceRefs(globalConfig, site, "switch", siteAPI); err != nil {
+			return err
+		}
+		if len(refs) == 0 {
+			return fmt.Errorf("site %s declares no switches", site)
+		}
+	}
+
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0750); err != nil {
+			return fmt.Errorf("failed to create %s: %w", dir, err)
+		}
+	}
+
+	for i := range refs {
+		ref := &refs[i]
+		if target != "" {
+			ref.API = target
+		}
+		if vendor := config.GetVendorFromAPILabel(ref.API); vendor != "" && vendor != "mist" {
+			msg := fmt.Sprintf("switch %s resolves to %s (%s); only Mist switch configs can be rendered", ref.MAC, ref.API, vendor)
+			if len(refs) == 1 {
+				return fmt.Errorf("%s", msg)
+			}
+			fmt.Fprintf(os.Stderr, "%s %s\n", symbols.WarningPrefix(), msg)
+			continue
+		}
+
+		effective, err := apply.EffectiveDeviceConfig(globalConfig, ref)
+		if err != nil {
+			return err
+		}
+		text := junosSetFile(ref, effective)
+
+		if dir == "" {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Print(text)
+			continue
+		}
+		path := filepath.Join(dir, junosFileName(ref, effective))
+		if err := os.WriteFile(path, []byte(text), 0600); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		fmt.Printf("%s Wrote %s\n", symbols.SuccessPrefix(), path)
+	}
+	return nil
+}
+
+// junosSetFile renders one switch as a set-command file, with a comment
+// header naming the switch and the keys that were not rendered.
+func junosSetFile(ref *apply.DeviceRef, effective map[string]any) string {
+	lines, skipped := mist.RenderJunosSet(effective)
+
+	var b strings.Builder
+	name, _ := effective["name"].(string)
+	if name == "" {
+		name = ref.MAC
+	}
+	fmt.Fprintf(&b, "# %s (%s) at site %s\n", name, ref.MAC, ref.SiteName)
+	b.WriteString("# Rendered from intent by wifimgr; best effort, review before loading.\n")
+	if len(skipped) > 0 {
+		fmt.Fprintf(&b, "# Not rendered: %s\n", strings.Join(skipped, ", "))
+	}
+	for _, l := range lines {
+		b.WriteString(l)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// junosFileName names a switch's set file after its configured name, or its
+// MAC when it has none.
+func junosFileName(ref *apply.DeviceRef, effective map[string]any) string {
+	name, _ := effective["name"].(string)
+	name = strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ' ' || r == os.PathSeparator {
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(name))
+	if name == "" || name == "." || name == ".." {
+		name = strings.ReplaceAll(ref.MAC, ":", "")
+	}
+	return name + ".set"
+}
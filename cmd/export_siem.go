@@ -0,0 +1,239 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/siem"
+	"github.com/ravinald/wifimgr/internal/symbols"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+// defaultSIEMWindow is how far back a site with no recorded export looks.
+const defaultSIEMWindow = time.Hour
+
+var exportSIEMCmd = &cobra.Command{
+	Use:   "siem [since <duration>] [site <site-name>] [target <api-label>] [dry-run]",
+	Short: "Send wireless intrusion events to a SIEM over syslog",
+	Long: `Send wireless intrusion events — rogue APs seen on the wired network,
+honeypot APs broadcasting a managed SSID, BSSID spoofing, and frame floods —
+to the SIEM configured under siem in the main config, as CEF or LEEF records
+over syslog (UDP, TCP, or TLS).
+
+Each site resumes from the newest event it last sent, recorded in siem.json in
+the state directory, so running this from cron ships every event once. A site
+with no record starts an hour back.
+
+Arguments:
+  since <duration>  Optional. Send the events of this window (e.g. 24h) again,
+                    regardless of what was already sent
+  site <name>       Optional. Limit to one site
+  target <label>    Optional. Limit to one API
+  dry-run           Optional. Print the records instead of sending them; the
+                    record of what was sent is left alone
+
+Vendor support:
+  Mist    Rogue events (rogue, honeypot, spoof) and security alarms
+Sites on other vendors are skipped with a warning on stderr.`,
+	Example: `  wifimgr export siem
+  wifimgr export siem since 24h site US-LAB-01 dry-run
+  wifimgr export siem target mist-prod`,
+	RunE: runExportSIEM,
+}
+
+func init() {
+	exportCmd.AddCommand(exportSIEMCmd)
+}
+
+// siemArgs is the parsed form of the export siem positional arguments.
+type siemArgs struct {
+	Site   string
+	Target string
+	Since  time.Duration
+	DryRun bool
+}
+
+func parseSIEMArgs(args []string) (siemArgs, error) {
+	var parsed siemArgs
+	for i := 0; i < len(args); i++ {
+		switch strings.ToLower(args[i]) {
+		case "dry-run", "dryrun":
+			parsed.DryRun = true
+		case "site", "target", "since":
+			if i+1 >= len(args) {
+				return parsed, fmt.Errorf("%s requires a value", args[i])
+			}
+			value := cmdutils.StripQuotes(args[i+1])
+			switch strings.ToLower(args[i]) {
+			case "site":
+				parsed.Site = value
+			case "target":
+				parsed.Target = value
+			default:
+				d, err := time.ParseDuration(value)
+				if err != nil || d <= 0 {
+					return parsed, fmt.Errorf("since must be a positive duration such as 24h, got %q", value)
+				}
+				parsed.Since = d
+			}
+			i++
+		default:
+			return parsed, fmt.Errorf("unexpected argument %q", args[i])
+		}
+	}
+	return parsed, nil
+}
+
+// siemWindowStart is where a site's export begins: since back from now when
+// given, else just after the last event sent, else defaultSIEMWindow back.
+func siemWindowStart(since time.Duration, sent time.Time, now time.Time) time.Time {
+	switch {
+	case since > 0:
+		return now.Add(-since)
+	case !sent.IsZero():
+		return sent
+	default:
+		return now.Add(-defaultSIEMWindow)
+	}
+}
+
+// unsentEvents drops the events at or before the last one sent. The vendor
+// APIs filter by whole seconds, so the window's first second comes back again.
+func unsentEvents(events []*vendors.SecurityEvent, sent time.Time) []*vendors.SecurityEvent {
+	if sent.IsZero() {
+		return events
+	}
+	var out []*vendors.SecurityEvent
+	for _, ev := range events {
+		if ev.Timestamp.After(sent) {
+			out = append(out, ev)
+		}
+	}
+	return out
+}
+
+func runExportSIEM(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+	parsed, err := parseSIEMArgs(args)
+	if err != nil {
+		return err
+	}
+	cfg, err := siem.LoadConfig()
+	if err != nil {
+		return err
+	}
+	format := siem.FormatCEF
+	if cfg != nil {
+		format = cfg.Format
+	} else if !parsed.DryRun {
+		return fmt.Errorf("siem.address is not set; configure the siem section or use dry-run")
+	}
+
+	SetAPITarget(parsed.Target)
+	if err := ValidateAPIFlag(); err != nil {
+		return err
+	}
+	deps := currentDeps()
+	sites, err := usageSites(deps, usageArgs{Site: parsed.Site, Target: parsed.Target})
+	if err != nil {
+		return err
+	}
+	if len(sites) == 0 {
+		return fmt.Errorf("no cached sites to export; run 'wifimgr refresh' first")
+	}
+	state, err := siem.LoadState()
+	if err != nil {
+		return err
+	}
+
+	var sender *siem.Sender
+	if !parsed.DryRun {
+		sender, err = siem.Dial(deps.Ctx, cfg)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = sender.Close() }()
+	}
+
+	// Records go to stdout in dry-run; progress and problems go to stderr.
+	now := time.Now()
+	unsupported := make(map[string]bool)
+	sent, failed := 0, 0
+	for _, ref := range sites {
+		client, err := deps.Client(ref.APILabel)
+		if err != nil {
+			return err
+		}
+		svc := client.SecurityEvents()
+		if svc == nil {
+			if !unsupported[ref.APILabel] {
+				unsupported[ref.APILabel] = true
+				fmt.Fprintf(os.Stderr, "%s security events are not available with this API (%s:%s); its sites are skipped\n",
+					symbols.WarningPrefix(), ref.APILabel, client.VendorName())
+			}
+			continue
+		}
+		key := siem.StateKey(ref.APILabel, ref.SiteID)
+		last := state[key]
+		events, err := svc.Search(deps.Ctx, ref.SiteID, siemWindowStart(parsed.Since, last, now), now)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s %s (%s): %v\n", symbols.ErrorPrefix(), ref.Name, ref.APILabel, err)
+			failed++
+			continue
+		}
+		if parsed.Since == 0 {
+			events = unsentEvents(events, last)
+		}
+		for _, ev := range events {
+			rec := siem.Record{Event: ev, Site: ref.Name, API: ref.APILabel, Vendor: client.VendorName()}
+			if parsed.DryRun {
+				fmt.Println(siem.Format(format, rec, Version))
+				continue
+			}
+			if err := sender.Send(rec, Version); err != nil {
+				// Keep what reached the receiver so the next run resumes there.
+				if saveErr := siem.SaveState(state); saveErr != nil {
+					fmt.Fprintf(os.Stderr, "%s %v\n", symbols.WarningPrefix(), saveErr)
+				}
+				return err
+			}
+			sent++
+			if ev.Timestamp.After(state[key]) {
+				state[key] = ev.Timestamp
+			}
+		}
+	}
+
+	if !parsed.DryRun {
+		if err := siem.SaveState(state); err != nil {
+			return fmt.Errorf("events were sent but the export state could not be saved: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "%s Sent %d event(s) to %s\n", symbols.SuccessPrefix(), sent, cfg.Describe())
+	}
+	if failed > 0 {
+		return fmt.Errorf("events for %d site(s) could not be fetched; the export is incomplete", failed)
+	}
+	return nil
+}
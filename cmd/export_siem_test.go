@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+func TestParseSIEMArgs(t *testing.T) {
+	parsed, err := parseSIEMArgs([]string{"since", "24h", "site", "US-LAB-01", "dry-run"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Since != 24*time.Hour || parsed.Site != "US-LAB-01" || !parsed.DryRun {
+		t.Errorf("got %+v", parsed)
+	}
+	for _, args := range [][]string{{"since", "yesterday"}, {"since", "-1h"}, {"target"}, {"now"}} {
+		if _, err := parseSIEMArgs(args); err == nil {
+			t.Errorf("%v: expected an error", args)
+		}
+	}
+}
+
+func TestSIEMWindow(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	last := now.Add(-10 * time.Minute)
+
+	if got := siemWindowStart(0, time.Time{}, now); !got.Equal(now.Add(-defaultSIEMWindow)) {
+		t.Errorf("first run starts at %v", got)
+	}
+	if got := siemWindowStart(0, last, now); !got.Equal(last) {
+		t.Errorf("resume starts at %v", got)
+	}
+	if got := siemWindowStart(24*time.Hour, last, now); !got.Equal(now.Add(-24 * time.Hour)) {
+		t.Errorf("since starts at %v", got)
+	}
+
+	events := []*vendors.SecurityEvent{{Timestamp: last}, {Timestamp: last.Add(time.Millisecond)}}
+	if got := unsentEvents(events, last); len(got) != 1 || got[0] != events[1] {
+		t.Errorf("unsent = %v", got)
+	}
+	if got := unsentEvents(events, time.Time{}); len(got) != 2 {
+		t.Errorf("with nothing sent, all events are new: %v", got)
+	}
+}
@@ -0,0 +1,328 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/formatter"
+	"github.com/ravinald/wifimgr/internal/symbols"
+)
+
+var exportUsageCmd = &cobra.Command{
+	Use:   "usage --month YYYY-MM [--per-site] [site <site-name>] [target <api-label>] [format csv|json]",
+	Short: "Export monthly client counts and traffic for billing",
+	Long: `Export client counts and traffic for one calendar month (UTC), queried
+live from each vendor's historical stats, as CSV for per-tenant billing or
+chargeback.
+
+By default there is one row per API, the tenant boundary; --per-site breaks
+it down by site. Clients are distinct per site, so an API row sums its sites
+and counts a client seen at two sites twice.
+
+Arguments:
+  --month YYYY-MM   Required. Month to export; the current month runs to now
+  --per-site        Optional. One row per site instead of per API
+  site <name>       Optional. Limit to one site
+  target <label>    Optional. Limit to one API
+  format            Optional. "csv" (default) or "json"
+
+Vendor support:
+  Mist    Distinct clients from client sessions; traffic from the site bytes
+          insight, split into down (to clients) and up (from clients)
+  Meraki  Clients overview: client count, and total traffic as the average
+          per client times the count (no up/down split). Meraki keeps 31 days
+Sites on other vendors are skipped with a warning on stderr.`,
+	Example: `  wifimgr export usage --month 2024-06 --per-site
+  wifimgr export usage --month 2024-06 target mist-prod > usage-2024-06.csv
+  wifimgr export usage --month 2024-06 site US-LAB-01 format json`,
+	RunE: runExportUsage,
+}
+
+func init() {
+	exportCmd.AddCommand(exportUsageCmd)
+	exportUsageCmd.Flags().String("month", "", "Month to export, YYYY-MM (UTC)")
+	exportUsageCmd.Flags().Bool("per-site", false, "One row per site instead of per API")
+}
+
+// usageArgs is the parsed form of the export usage positional arguments.
+type usageArgs struct {
+	Site   string
+	Target string
+	Format string
+}
+
+func parseUsageArgs(args []string) (usageArgs, error) {
+	parsed := usageArgs{Format: "csv"}
+	for i := 0; i < len(args); i++ {
+		switch strings.ToLower(args[i]) {
+		case "site", "target", "format":
+			if i+1 >= len(args) {
+				return parsed, fmt.Errorf("%s requires a value", args[i])
+			}
+			value := cmdutils.StripQuotes(args[i+1])
+			switch strings.ToLower(args[i]) {
+			case "site":
+				parsed.Site = value
+			case "target":
+				parsed.Target = value
+			default:
+				parsed.Format = strings.ToLower(value)
+				if parsed.Format != "csv" && parsed.Format != "json" {
+					return parsed, fmt.Errorf("format must be csv or json, got %q", value)
+				}
+			}
+			i++
+		default:
+			return parsed, fmt.Errorf("unexpected argument %q", args[i])
+		}
+	}
+	return parsed, nil
+}
+
+// usageMonth returns the UTC bounds of month (YYYY-MM). The current month
+// ends at now; a future month is an error.
+func usageMonth(month string, now time.Time) (time.Time, time.Time, error) {
+	start, err := time.Parse("2006-01", strings.TrimSpace(month))
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("--month must be YYYY-MM, got %q", month)
+	}
+	now = now.UTC()
+	if start.After(now) {
+		return time.Time{}, time.Time{}, fmt.Errorf("month %s has not started", month)
+	}
+	end := start.AddDate(0, 1, 0)
+	if end.After(now) {
+		end = now
+	}
+	return start, end, nil
+}
+
+// usageRow is one line of the export: a site, or an API rolled up.
+type usageRow struct {
+	Month      string `json:"month"`
+	API        string `json:"api"`
+	Vendor     string `json:"vendor"`
+	Site       string `json:"site,omitempty"`
+	SiteID     string `json:"site_id,omitempty"`
+	Sites      int    `json:"sites,omitempty"`
+	Clients    int    `json:"clients"`
+	DownBytes  int64  `json:"down_bytes"`
+	UpBytes    int64  `json:"up_bytes"`
+	TotalBytes int64  `json:"total_bytes"`
+}
+
+// rollUpUsage sums per-site rows into one row per API, sorted by API.
+func rollUpUsage(rows []usageRow) []usageRow {
+	byAPI := make(map[string]*usageRow)
+	var order []string
+	for _, r := range rows {
+		agg, ok := byAPI[r.API]
+		if !ok {
+			agg = &usageRow{Month: r.Month, API: r.API, Vendor: r.Vendor}
+			byAPI[r.API] = agg
+			order = append(order, r.API)
+		}
+		agg.Sites++
+		agg.Clients += r.Clients
+		agg.DownBytes += r.DownBytes
+		agg.UpBytes += r.UpBytes
+		agg.TotalBytes += r.TotalBytes
+	}
+	sort.Strings(order)
+	out := make([]usageRow, 0, len(order))
+	for _, api := range order {
+		out = append(out, *byAPI[api])
+	}
+	return out
+}
+
+// usageSites returns the sites to export: the named one, or every cached
+// site of the target APIs, sorted by API then name.
+func usageSites(deps *Deps, parsed usageArgs) ([]cmdutils.SiteRef, error) {
+	if parsed.Site != "" {
+		ref, err := deps.ResolveSite(parsed.Site, parsed.Target)
+		if err != nil {
+			return nil, err
+		}
+		return []cmdutils.SiteRef{*ref}, nil
+	}
+	cacheMgr := deps.CacheManager()
+	if cacheMgr == nil {
+		return nil, fmt.Errorf("cache manager not initialized")
+	}
+	var refs []cmdutils.SiteRef
+	for _, label := range GetTargetAPIs() {
+		cache, err := cacheMgr.GetAPICache(label)
+		if err != nil {
+			continue
+		}
+		for _, s := range cache.Sites.Info {
+			refs = append(refs, cmdutils.SiteRef{APILabel: label, SiteID: s.ID, Name: s.Name})
+		}
+	}
+	sort.Slice(refs, func(i, j int) bool {
+		if refs[i].APILabel != refs[j].APILabel {
+			return refs[i].APILabel < refs[j].APILabel
+		}
+		return refs[i].Name < refs[j].Name
+	})
+	return refs, nil
+}
+
+func runExportUsage(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+	parsed, err := parseUsageArgs(args)
+	if err != nil {
+		return err
+	}
+	month, _ := cmd.Flags().GetString("month")
+	if month == "" {
+		return fmt.Errorf("--month is required, e.g. --month 2024-06")
+	}
+	perSite, _ := cmd.Flags().GetBool("per-site")
+	start, end, err := usageMonth(month, time.Now())
+	if err != nil {
+		return err
+	}
+
+	SetAPITarget(parsed.Target)
+	if err := ValidateAPIFlag(); err != nil {
+		return err
+	}
+	deps := currentDeps()
+	sites, err := usageSites(deps, parsed)
+	if err != nil {
+		return err
+	}
+	if len(sites) == 0 {
+		return fmt.Errorf("no cached sites to export; run 'wifimgr refresh' first")
+	}
+
+	// Progress and problems go to stderr so the CSV on stdout stays clean.
+	var rows []usageRow
+	unsupported := make(map[string]bool)
+	failed := 0
+	for _, ref := range sites {
+		client, err := deps.Client(ref.APILabel)
+		if err != nil {
+			return err
+		}
+		svc := client.Usage()
+		if svc == nil {
+			if !unsupported[ref.APILabel] {
+				unsupported[ref.APILabel] = true
+				fmt.Fprintf(os.Stderr, "%s usage is not available with this API (%s:%s); its sites are skipped\n",
+					symbols.WarningPrefix(), ref.APILabel, client.VendorName())
+			}
+			continue
+		}
+		usage, err := svc.SiteUsage(deps.Ctx, ref.SiteID, start, end)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s %s (%s): %v\n", symbols.ErrorPrefix(), ref.Name, ref.APILabel, err)
+			failed++
+			continue
+		}
+		rows = append(rows, usageRow{
+			Month:      month,
+			API:        ref.APILabel,
+			Vendor:     client.VendorName(),
+			Site:       ref.Name,
+			SiteID:     ref.SiteID,
+			Clients:    usage.Clients,
+			DownBytes:  usage.DownBytes,
+			UpBytes:    usage.UpBytes,
+			TotalBytes: usage.TotalBytes,
+		})
+	}
+	if !perSite {
+		rows = rollUpUsage(rows)
+	}
+
+	if err := printUsage(rows, parsed.Format, perSite); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("usage for %d site(s) could not be fetched; the export is incomplete", failed)
+	}
+	return nil
+}
+
+func printUsage(rows []usageRow, format string, perSite bool) error {
+	if format == "json" {
+		if rows == nil {
+			rows = []usageRow{}
+		}
+		out, err := json.MarshalIndent(rows, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal usage: %w", err)
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+
+	columns := []formatter.TableColumn{
+		{Field: "month", Title: "month"},
+		{Field: "api", Title: "api"},
+		{Field: "vendor", Title: "vendor"},
+	}
+	if perSite {
+		columns = append(columns,
+			formatter.TableColumn{Field: "site", Title: "site"},
+			formatter.TableColumn{Field: "site_id", Title: "site_id"})
+	} else {
+		columns = append(columns, formatter.TableColumn{Field: "sites", Title: "sites"})
+	}
+	columns = append(columns,
+		formatter.TableColumn{Field: "clients", Title: "clients"},
+		formatter.TableColumn{Field: "down_bytes", Title: "down_bytes"},
+		formatter.TableColumn{Field: "up_bytes", Title: "up_bytes"},
+		formatter.TableColumn{Field: "total_bytes", Title: "total_bytes"})
+
+	data := make([]formatter.GenericTableData, 0, len(rows))
+	for _, r := range rows {
+		data = append(data, formatter.GenericTableData{
+			"month":       r.Month,
+			"api":         r.API,
+			"vendor":      r.Vendor,
+			"site":        r.Site,
+			"site_id":     r.SiteID,
+			"sites":       strconv.Itoa(r.Sites),
+			"clients":     strconv.Itoa(r.Clients),
+			"down_bytes":  strconv.FormatInt(r.DownBytes, 10),
+			"up_bytes":    strconv.FormatInt(r.UpBytes, 10),
+			"total_bytes": strconv.FormatInt(r.TotalBytes, 10),
+		})
+	}
+	printer := formatter.NewGenericTablePrinter(formatter.TableConfig{
+		Format:      "csv",
+		CommandPath: "export.usage",
+		Columns:     columns,
+	}, data)
+	fmt.Print(printer.Print())
+	return nil
+}
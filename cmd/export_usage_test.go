@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUsageMonth(t *testing.T) {
+	now := time.Date(2024, 7, 10, 12, 0, 0, 0, time.UTC)
+
+	start, end, err := usageMonth("2024-06", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !start.Equal(time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)) || !end.Equal(time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("June: got %v - %v", start, end)
+	}
+
+	// The current month runs to now
+	if _, end, err := usageMonth("2024-07", now); err != nil || !end.Equal(now) {
+		t.Errorf("July: got end %v, %v", end, err)
+	}
+	if _, _, err := usageMonth("2024-08", now); err == nil {
+		t.Error("a future month should be an error")
+	}
+	if _, _, err := usageMonth("June 2024", now); err == nil {
+		t.Error("a malformed month should be an error")
+	}
+}
+
+func TestParseUsageArgs(t *testing.T) {
+	parsed, err := parseUsageArgs([]string{"site", "US-LAB-01", "target", "mist-prod"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Site != "US-LAB-01" || parsed.Target != "mist-prod" || parsed.Format != "csv" {
+		t.Errorf("got %+v", parsed)
+	}
+	if parsed, err := parseUsageArgs([]string{"format", "json"}); err != nil || parsed.Format != "json" {
+		t.Errorf("format json: got %+v, %v", parsed, err)
+	}
+	for _, bad := range [][]string{{"format", "table"}, {"site"}, {"bogus"}} {
+		if _, err := parseUsageArgs(bad); err == nil {
+			t.Errorf("%v: want an error", bad)
+		}
+	}
+}
+
+func TestRollUpUsage(t *testing.T) {
+	rows := []usageRow{
+		{Month: "2024-06", API: "mist-prod", Vendor: "mist", Site: "A", Clients: 10, DownBytes: 100, UpBytes: 50, TotalBytes: 150},
+		{Month: "2024-06", API: "meraki-prod", Vendor: "meraki", Site: "C", Clients: 7, TotalBytes: 700},
+		{Month: "2024-06", API: "mist-prod", Vendor: "mist", Site: "B", Clients: 5, DownBytes: 10, UpBytes: 5, TotalBytes: 15},
+	}
+	got := rollUpUsage(rows)
+	if len(got) != 2 {
+		t.Fatalf("want 2 API rows, got %+v", got)
+	}
+	if got[0].API != "meraki-prod" || got[0].Sites != 1 || got[0].TotalBytes != 700 {
+		t.Errorf("meraki-prod: got %+v", got[0])
+	}
+	mist := got[1]
+	if mist.Sites != 2 || mist.Clients != 15 || mist.DownBytes != 110 || mist.UpBytes != 55 || mist.TotalBytes != 165 || mist.Site != "" {
+		t.Errorf("mist-prod: got %+v", mist)
+	}
+}
@@ -0,0 +1,209 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/i18n"
+	"github.com/ravinald/wifimgr/internal/logging"
+	"github.com/ravinald/wifimgr/internal/symbols"
+)
+
+// firmwareCmd groups firmware operations driven by the pins in site intent.
+var firmwareCmd = &cobra.Command{
+	Use:   "firmware",
+	Short: "Bring devices to the firmware versions pinned in intent",
+	Long: `Bring devices to the firmware versions pinned in site intent.
+
+Pins are declared per device model in a site's "firmware" section or a device
+template's "firmware" section. 'wifimgr report firmware' shows compliance.`,
+	Example: `  wifimgr report firmware US-LAB-01
+  wifimgr firmware apply US-LAB-01`,
+}
+
+// firmwareApplyCmd represents the "firmware apply" command
+var firmwareApplyCmd = &cobra.Command{
+	Use:   "apply <site-name> [target <api-label>] [diff] [force]",
+	Short: "Upgrade outdated devices at a site to their pinned versions",
+	Long: `Upgrade every outdated device at a site to the firmware version pinned for
+its model (see 'wifimgr report firmware'). Devices are grouped by target
+version and one upgrade request is sent per version. The vendor runs the
+upgrade and reboots each device; run the report again to follow progress.
+
+Devices that are compliant, unpinned, or report no running version are left
+alone.
+
+Arguments:
+  site-name        Required. Site to upgrade
+  target <label>   Optional. API owning the site (when the name is ambiguous)
+  diff             Optional. Show the planned upgrades without starting them
+  force            Optional. Skip the confirmation prompt
+
+Vendor support: Mist. Other vendors report "not available with this API".`,
+	Example: `  wifimgr firmware apply US-LAB-01 diff
+  wifimgr firmware apply US-LAB-01 force`,
+	Args: func(_ *cobra.Command, args []string) error {
+		if cmdutils.ContainsHelp(args) {
+			return nil
+		}
+		if len(args) < 1 {
+			return fmt.Errorf("requires a site name")
+		}
+		return nil
+	},
+	RunE: runFirmwareApply,
+}
+
+func init() {
+	firmwareCmd.AddCommand(firmwareApplyCmd)
+	rootCmd.AddCommand(firmwareCmd)
+}
+
+// firmwareApplyArgs is the parsed form of the firmware apply arguments.
+type firmwareApplyArgs struct {
+	SiteName string
+	Target   string
+	Diff     bool
+	Force    bool
+}
+
+func parseFirmwareApplyArgs(args []string) (*firmwareApplyArgs, error) {
+	parsed := &firmwareApplyArgs{SiteName: cmdutils.StripQuotes(args[0])}
+	for i := 1; i < len(args); i++ {
+		switch strings.ToLower(args[i]) {
+		case "target":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("'target' requires an API label")
+			}
+			parsed.Target = cmdutils.StripQuotes(args[i+1])
+			i++
+		case "diff":
+			parsed.Diff = true
+		case "force":
+			parsed.Force = true
+		default:
+			return nil, fmt.Errorf("unexpected argument: %s", args[i])
+		}
+	}
+	return parsed, nil
+}
+
+// firmwareBatch is one upgrade request: the devices moving to one version.
+type firmwareBatch struct {
+	Version string
+	Devices []firmwareRow
+}
+
+// planFirmwareUpgrades groups outdated devices by target version, ordered by
+// version. Devices without a vendor ID cannot be addressed and are returned
+// separately.
+func planFirmwareUpgrades(rows []firmwareRow) (batches []firmwareBatch, skipped []firmwareRow) {
+	byVersion := make(map[string][]firmwareRow)
+	for _, r := range rows {
+		if r.Status != firmwareOutdated {
+			continue
+		}
+		if r.ID == "" {
+			skipped = append(skipped, r)
+			continue
+		}
+		byVersion[r.Target] = append(byVersion[r.Target], r)
+	}
+	versions := make([]string, 0, len(byVersion))
+	for v := range byVersion {
+		versions = append(versions, v)
+	}
+	sort.Strings(versions)
+	for _, v := range versions {
+		batches = append(batches, firmwareBatch{Version: v, Devices: byVersion[v]})
+	}
+	return batches, skipped
+}
+
+func runFirmwareApply(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+	parsed, err := parseFirmwareApplyArgs(args)
+	if err != nil {
+		return err
+	}
+
+	deps := currentDeps()
+	ref, client, rows, err := collectFirmwareCompliance(deps, parsed.SiteName, parsed.Target)
+	if err != nil {
+		return err
+	}
+	svc := client.Firmware()
+	if svc == nil {
+		return fmt.Errorf("this feature is not available with this API (%s:%s)", ref.APILabel, client.VendorName())
+	}
+
+	batches, skipped := planFirmwareUpgrades(rows)
+	for _, r := range skipped {
+		fmt.Printf("%s %s: no device ID from the API, skipped\n", symbols.WarningPrefix(), r.Name)
+	}
+	if len(batches) == 0 {
+		fmt.Printf("%s All pinned devices at %s are compliant\n", symbols.SuccessPrefix(), ref.Name)
+		return nil
+	}
+
+	total := 0
+	fmt.Printf("Firmware upgrades at %s:\n", ref.Name)
+	for _, b := range batches {
+		fmt.Printf("  -> %s\n", b.Version)
+		for _, d := range b.Devices {
+			fmt.Printf("     %s (%s) %s\n", d.Name, d.Model, d.Version)
+		}
+		total += len(b.Devices)
+	}
+	if parsed.Diff {
+		return nil
+	}
+
+	if !parsed.Force {
+		fmt.Printf("%s %s ", i18n.T("firmware.confirm_apply", total, ref.Name), i18n.T("prompt.yes_no"))
+		if !confirmPrompt() {
+			fmt.Println(i18n.T("firmware.cancelled"))
+			return nil
+		}
+	}
+
+	failed := 0
+	for _, b := range batches {
+		ids := make([]string, 0, len(b.Devices))
+		for _, d := range b.Devices {
+			ids = append(ids, d.ID)
+		}
+		if err := svc.Upgrade(deps.Ctx, ref.SiteID, ids, b.Version); err != nil {
+			failed += len(ids)
+			fmt.Printf("%s %s: upgrade of %d device(s) failed: %v\n", symbols.FailurePrefix(), b.Version, len(ids), err)
+			continue
+		}
+		logging.Infof("Requested firmware %s for %d device(s) at %s", b.Version, len(ids), ref.Name)
+		fmt.Printf("%s %s: upgrade started on %d device(s)\n", symbols.SuccessPrefix(), b.Version, len(ids))
+	}
+	if failed > 0 {
+		return fmt.Errorf("upgrade request failed for %d of %d device(s)", failed, total)
+	}
+	return nil
+}
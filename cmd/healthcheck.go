@@ -0,0 +1,55 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ravinald/wifimgr/internal/healthcheck"
+)
+
+// healthcheckPinger reports this run to --healthcheck-url; nil when unset.
+var healthcheckPinger *healthcheck.Pinger
+
+// startHealthcheck sends the start ping when --healthcheck-url is set. It runs
+// before initialization so a run that fails to load its config still reports
+// the failure.
+func startHealthcheck(ctx context.Context) error {
+	p, err := healthcheck.New(healthcheckURL)
+	if err != nil {
+		return err
+	}
+	healthcheckPinger = p
+	healthcheckPinger.Start(ctx)
+	return nil
+}
+
+// finishHealthcheck sends the success or failure ping for a started run.
+func finishHealthcheck(ctx context.Context, cmd *cobra.Command, start time.Time, runErr error) {
+	if cmd == nil || healthcheckPinger == nil {
+		return
+	}
+	command := strings.TrimSpace(strings.TrimPrefix(cmd.CommandPath(), rootCmd.Name()))
+	if command == "" {
+		command = rootCmd.Name()
+	}
+	// Ping on a fresh context: an interrupted run should still report failing.
+	healthcheckPinger.Finish(context.WithoutCancel(ctx), command, time.Since(start), runErr)
+}
@@ -0,0 +1,223 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/formatter"
+	"github.com/ravinald/wifimgr/internal/history"
+)
+
+// historyCmd groups views of the apply changelog.
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show what apply has changed, from the config-dir changelog",
+	Long: `Every apply that changes something appends one JSON line to CHANGELOG.jsonl
+in the config directory: timestamp, site, API, device type, the devices
+assigned, updated, and unassigned, the number of WLANs changed, the operator,
+the duration, and the result. The operator is WIFIMGR_OPERATOR when set,
+otherwise the OS user.`,
+	Example: `  wifimgr history site US-LAB-01`,
+	Annotations: map[string]string{
+		cmdutils.AnnotationNeedsConfig: "true",
+	},
+}
+
+// historySiteCmd represents the "history site" command
+var historySiteCmd = &cobra.Command{
+	Use:   "site <site-name> [limit <n>] [format json|csv]",
+	Short: "Show the apply history of a site, newest first",
+	Long: `Show the apply history of a site from CHANGELOG.jsonl, newest first.
+
+Arguments:
+  site-name    Required. Site to show
+  limit <n>    Optional. Show only the n most recent entries
+  format       Optional. "json" or "csv" (default: table)`,
+	Example: `  wifimgr history site US-LAB-01
+  wifimgr history site US-LAB-01 limit 10
+  wifimgr history site US-LAB-01 format json`,
+	Annotations: map[string]string{
+		cmdutils.AnnotationNeedsConfig: "true",
+	},
+	Args: func(_ *cobra.Command, args []string) error {
+		if cmdutils.ContainsHelp(args) {
+			return nil
+		}
+		if len(args) < 1 {
+			return fmt.Errorf("requires a site name")
+		}
+		return nil
+	},
+	RunE: runHistorySite,
+}
+
+func init() {
+	historyCmd.AddCommand(historySiteCmd)
+	rootCmd.AddCommand(historyCmd)
+}
+
+func runHistorySite(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+
+	siteName := cmdutils.StripQuotes(args[0])
+	format := "table"
+	limit := 0
+	for i := 1; i < len(args); i++ {
+		switch strings.ToLower(args[i]) {
+		case "limit":
+			if i+1 >= len(args) {
+				return fmt.Errorf("'limit' requires a number")
+			}
+			n, err := strconv.Atoi(args[i+1])
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid limit %q: must be a positive number", args[i+1])
+			}
+			limit = n
+			i++
+		case "format":
+			if i+1 >= len(args) {
+				return fmt.Errorf("'format' requires a format type (json, csv)")
+			}
+			switch f := strings.ToLower(args[i+1]); f {
+			case "json", "csv":
+				format = f
+			default:
+				return fmt.Errorf("invalid format %q: must be 'json' or 'csv'", args[i+1])
+			}
+			i++
+		default:
+			return fmt.Errorf("unexpected argument: %s", args[i])
+		}
+	}
+
+	configDir, err := changelogDir()
+	if err != nil {
+		return err
+	}
+	path := history.Path(configDir)
+
+	entries, err := history.Read(path, siteName)
+	if err != nil {
+		return err
+	}
+	entries = newestFirst(entries, limit)
+
+	if format == "json" {
+		out, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal history: %w", err)
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+
+	title := fmt.Sprintf("Apply History — %s (%d)", siteName, len(entries))
+	if len(entries) == 0 {
+		fmt.Printf("%s:\nNo applies recorded for %s in %s\n", title, siteName, path)
+		return nil
+	}
+
+	rows := make([]formatter.GenericTableData, 0, len(entries))
+	for _, e := range entries {
+		rows = append(rows, formatter.GenericTableData{
+			"time":     e.Timestamp.Local().Format("2006-01-02 15:04:05"),
+			"type":     e.DeviceType,
+			"api":      e.API,
+			"devices":  describeHistoryDevices(e),
+			"wlans":    e.WLANsChanged,
+			"operator": e.Operator,
+			"duration": (time.Duration(e.DurationMS) * time.Millisecond).Round(100 * time.Millisecond).String(),
+			"result":   e.Result,
+		})
+	}
+	printer := formatter.NewGenericTablePrinter(formatter.TableConfig{
+		Title:         title,
+		Format:        format,
+		BoldHeaders:   true,
+		ShowSeparator: true,
+		CommandPath:   "history.site",
+		Columns: []formatter.TableColumn{
+			{Field: "time", Title: "Time"},
+			{Field: "type", Title: "Type"},
+			{Field: "api", Title: "API"},
+			{Field: "devices", Title: "Devices"},
+			{Field: "wlans", Title: "WLANs"},
+			{Field: "operator", Title: "Operator"},
+			{Field: "duration", Title: "Duration"},
+			{Field: "result", Title: "Result"},
+		},
+	}, rows)
+	fmt.Print(printer.Print())
+	return nil
+}
+
+// changelogDir returns the config directory, where CHANGELOG.jsonl lives.
+func changelogDir() (string, error) {
+	configDir := viper.GetString("files.config_dir")
+	if configDir == "" && globalConfig != nil {
+		configDir = globalConfig.Files.ConfigDir
+	}
+	if configDir == "" {
+		return "", fmt.Errorf("config directory not set")
+	}
+	return configDir, nil
+}
+
+// newestFirst reverses the oldest-first entries and keeps at most limit
+// (0 keeps all).
+func newestFirst(entries []history.Entry, limit int) []history.Entry {
+	out := make([]history.Entry, 0, len(entries))
+	for i := len(entries) - 1; i >= 0; i-- {
+		out = append(out, entries[i])
+		if limit > 0 && len(out) == limit {
+			break
+		}
+	}
+	return out
+}
+
+// describeHistoryDevices summarizes an entry's device changes, e.g.
+// "3 (2 updated, 1 assigned)", or names the call of a raw API write.
+func describeHistoryDevices(e history.Entry) string {
+	if e.Method != "" {
+		return e.Method + " " + e.Path
+	}
+	total := e.DevicesChanged()
+	if total == 0 {
+		return "0"
+	}
+	var parts []string
+	for _, p := range []struct {
+		n    int
+		verb string
+	}{{len(e.Updated), "updated"}, {len(e.Assigned), "assigned"}, {len(e.Unassigned), "unassigned"}} {
+		if p.n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", p.n, p.verb))
+		}
+	}
+	return fmt.Sprintf("%d (%s)", total, strings.Join(parts, ", "))
+}
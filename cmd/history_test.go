@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/ravinald/wifimgr/internal/history"
+)
+
+func TestNewestFirst(t *testing.T) {
+	entries := []history.Entry{{Site: "a"}, {Site: "b"}, {Site: "c"}}
+	got := newestFirst(entries, 2)
+	if len(got) != 2 || got[0].Site != "c" || got[1].Site != "b" {
+		t.Errorf("newestFirst(limit 2) = %+v", got)
+	}
+	if got := newestFirst(nil, 0); got == nil || len(got) != 0 {
+		t.Errorf("newestFirst(nil) = %#v, want empty non-nil", got)
+	}
+}
+
+func TestDescribeHistoryDevices(t *testing.T) {
+	e := history.Entry{Updated: []string{"a", "b"}, Unassigned: []string{"c"}}
+	if got := describeHistoryDevices(e); got != "3 (2 updated, 1 unassigned)" {
+		t.Errorf("describeHistoryDevices = %q", got)
+	}
+	if got := describeHistoryDevices(history.Entry{WLANsChanged: 1}); got != "0" {
+		t.Errorf("describeHistoryDevices(no devices) = %q", got)
+	}
+	raw := history.Entry{DeviceType: history.DeviceTypeRawAPI, Method: "PUT", Path: "/sites/s1/setting"}
+	if got := describeHistoryDevices(raw); got != "PUT /sites/s1/setting" {
+		t.Errorf("describeHistoryDevices(raw write) = %q", got)
+	}
+}
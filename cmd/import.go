@@ -24,17 +24,21 @@ var importCmd = &cobra.Command{
 	Short: "Import configuration data from external sources",
 	Long: `Import configuration data from external sources into wifimgr.
 
-The import command provides two methods for importing configuration:
+The import command provides these methods for importing configuration:
 
-  api  - Import from API cache to create local config files
-  pdf  - Import AP radio configurations from PDF floor plans
+  api               - Import from API cache to create local config files
+  pdf               - Import AP radio configurations from PDF floor plans
+  device-overrides  - Merge per-device names, profiles, and notes from a CSV
 
 Use 'wifimgr import <subcommand> --help' for detailed information about each import method.`,
 	Example: `  # Import site from API cache
   wifimgr import api site US-LAB-01 save
 
   # Import AP radio configs from PDF
-  wifimgr import pdf file floor-plan.pdf site US-LAB-01`,
+  wifimgr import pdf file floor-plan.pdf site US-LAB-01
+
+  # Merge per-device overrides from a spreadsheet
+  wifimgr import device-overrides --file overrides.csv`,
 }
 
 func init() {
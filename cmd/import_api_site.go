@@ -1028,7 +1028,7 @@ func buildDevicesExport(cacheAccessor *vendors.CacheAccessor, siteID string, sou
 // lists so apply resolves them back to the same ap_ids — a functional no-op.
 // Meraki carries its availability inside the WLAN's vendor block instead.
 func buildWLANsExport(cacheAccessor *vendors.CacheAccessor, siteID, siteSlug string, reveal secretReveal) ([]string, map[string]map[string]any, map[string][]string) {
-	wlans := cacheAccessor.GetWLANsBySite(siteID)
+	wlans := withoutEphemeralWLANs(cacheAccessor.GetWLANsBySite(siteID))
 	labels, profiles, vendorBlocks := synthesizeWLANLabels(wlans, siteSlug, reveal)
 	if len(profiles) == 0 {
 		return labels, nil, nil
@@ -0,0 +1,299 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/formatter"
+	"github.com/ravinald/wifimgr/internal/i18n"
+	"github.com/ravinald/wifimgr/internal/macaddr"
+	"github.com/ravinald/wifimgr/internal/symbols"
+)
+
+var deviceOverridesFile string
+
+var importDeviceOverridesCmd = &cobra.Command{
+	Use:   "device-overrides file <csv-path> [diff] [force]",
+	Short: "Merge per-device names, profiles, and notes from a CSV into site configs",
+	Long: `Merge per-device overrides from a CSV sheet into the site config files.
+
+The first row is a header. mac is required; the other columns are optional
+and may appear in any order:
+
+  mac             Device MAC, any spelling
+  name            Device name
+  deviceprofile   Device profile name (APs; sets deviceprofile_name)
+  radio template  Radio template name (APs; sets radio_profile)
+  notes           Free-text notes
+
+An empty cell leaves that field as it is. Each MAC must be armed in
+inventory.json; its inventory site and device type decide which site config
+block the row lands in, and a MAC the site config does not list yet gets a
+new entry. Every row is validated before anything is written, and the
+changes are previewed and confirmed first.
+
+Arguments:
+  file <path>   Required. CSV file to import (or --file)
+  diff          Optional. Show the changes without writing
+  force         Optional. Skip the confirmation prompt`,
+	Example: `  wifimgr import device-overrides --file overrides.csv
+  wifimgr import device-overrides file overrides.csv diff
+  wifimgr import device-overrides file overrides.csv force`,
+	Annotations: map[string]string{
+		cmdutils.AnnotationNeedsConfig: "true",
+	},
+	RunE: runImportDeviceOverrides,
+}
+
+func init() {
+	importDeviceOverridesCmd.Flags().StringVar(&deviceOverridesFile, "file", "", "CSV file of per-device overrides")
+	importCmd.AddCommand(importDeviceOverridesCmd)
+}
+
+// deviceOverridesArgs holds the parsed positional arguments.
+type deviceOverridesArgs struct {
+	File  string
+	Diff  bool
+	Force bool
+}
+
+func parseDeviceOverridesArgs(args []string) (*deviceOverridesArgs, error) {
+	out := &deviceOverridesArgs{File: deviceOverridesFile}
+	for i := 0; i < len(args); i++ {
+		switch strings.ToLower(args[i]) {
+		case "file":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("'file' requires a path")
+			}
+			out.File = cmdutils.StripQuotes(args[i+1])
+			i++
+		case "diff":
+			out.Diff = true
+		case "force":
+			out.Force = true
+		default:
+			return nil, fmt.Errorf("unexpected argument: %s", args[i])
+		}
+	}
+	if out.File == "" {
+		return nil, fmt.Errorf("a CSV file is required: import device-overrides file <csv-path>")
+	}
+	return out, nil
+}
+
+// overrideTarget is the site config block a group of override rows lands in.
+type overrideTarget struct {
+	Site string // site name as written in inventory.json
+	File string // site config file, relative to config_dir
+	Key  string // key under config.sites in File
+	Type string // ap, switch, or gateway
+}
+
+// planDeviceOverrides places each row in the site config block of its
+// inventory site and type. Every problem is returned, one per line, so a
+// sheet can be fixed in one pass; no rows are placed when any row is bad.
+func planDeviceOverrides(rows []config.DeviceOverride, inv *config.InventoryFile, sites []configuredSite) (map[overrideTarget][]config.DeviceOverride, []string) {
+	type armed struct{ site, dtype string }
+	inventory := make(map[string][]armed)
+	for _, site := range inv.SiteNames() {
+		for _, dtype := range []string{"ap", "switch", "gateway"} {
+			for _, mac := range inv.MACsForSite(site, dtype) {
+				if n := macaddr.NormalizeOrEmpty(mac); n != "" {
+					inventory[n] = append(inventory[n], armed{site, dtype})
+				}
+			}
+		}
+	}
+
+	var problems []string
+	seen := make(map[string]int)
+	plan := make(map[overrideTarget][]config.DeviceOverride)
+	for _, row := range rows {
+		mac := macaddr.NormalizeOrEmpty(row.MAC)
+		if mac == "" {
+			problems = append(problems, fmt.Sprintf("line %d: invalid MAC address %q", row.Line, row.MAC))
+			continue
+		}
+		if first, dup := seen[mac]; dup {
+			problems = append(problems, fmt.Sprintf("line %d: %s already given on line %d", row.Line, row.MAC, first))
+			continue
+		}
+		seen[mac] = row.Line
+
+		if row.Name == "" && row.DeviceProfile == "" && row.RadioProfile == "" && row.Notes == "" {
+			problems = append(problems, fmt.Sprintf("line %d: %s has no fields to set", row.Line, row.MAC))
+			continue
+		}
+		matches := inventory[mac]
+		switch {
+		case len(matches) == 0:
+			problems = append(problems, fmt.Sprintf("line %d: %s is not in inventory.json", row.Line, row.MAC))
+			continue
+		case len(matches) > 1:
+			problems = append(problems, fmt.Sprintf("line %d: %s is armed at more than one site in inventory.json", row.Line, row.MAC))
+			continue
+		}
+		m := matches[0]
+		if m.dtype != "ap" && (row.DeviceProfile != "" || row.RadioProfile != "") {
+			problems = append(problems, fmt.Sprintf("line %d: %s is a %s; deviceprofile and radio template apply to APs only", row.Line, row.MAC, m.dtype))
+			continue
+		}
+
+		target := overrideTarget{Site: m.site, Type: m.dtype}
+		for _, s := range sites {
+			if strings.EqualFold(s.Key, m.site) {
+				target.File, target.Key = s.File, s.Key
+				break
+			}
+		}
+		if target.File == "" {
+			problems = append(problems, fmt.Sprintf("line %d: %s is armed at %s, which has no site config", row.Line, row.MAC, m.site))
+			continue
+		}
+		plan[target] = append(plan[target], row)
+	}
+	if len(problems) > 0 {
+		return nil, problems
+	}
+	return plan, nil
+}
+
+// sortedOverrideTargets returns the plan's targets by site, then type.
+func sortedOverrideTargets(plan map[overrideTarget][]config.DeviceOverride) []overrideTarget {
+	targets := make([]overrideTarget, 0, len(plan))
+	for t := range plan {
+		targets = append(targets, t)
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		if targets[i].Site != targets[j].Site {
+			return targets[i].Site < targets[j].Site
+		}
+		return targets[i].Type < targets[j].Type
+	})
+	return targets
+}
+
+func runImportDeviceOverrides(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+	parsed, err := parseDeviceOverridesArgs(args)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(parsed.File) // #nosec G304 -- operator-supplied import path
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", parsed.File, err)
+	}
+	rows, err := config.ParseDeviceOverridesCSV(f)
+	_ = f.Close()
+	if err != nil {
+		return fmt.Errorf("%s: %w", parsed.File, err)
+	}
+	if len(rows) == 0 {
+		fmt.Printf("%s %s has no rows\n", symbols.WarningPrefix(), parsed.File)
+		return nil
+	}
+
+	inv, err := config.LoadInventory(config.InventoryPath(globalConfig))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("inventory.json not found: device overrides are only accepted for armed devices")
+		}
+		return err
+	}
+	configDir := viper.GetString("files.config_dir")
+	sites := loadConfiguredSites(configDir, viper.GetStringSlice("files.site_configs"))
+
+	plan, problems := planDeviceOverrides(rows, inv, sites)
+	if len(problems) > 0 {
+		for _, p := range problems {
+			fmt.Printf("%s %s\n", symbols.FailurePrefix(), p)
+		}
+		return fmt.Errorf("%d invalid row(s) in %s; no files changed", len(problems), parsed.File)
+	}
+
+	targets := sortedOverrideTargets(plan)
+	var tableRows []formatter.GenericTableData
+	for _, t := range targets {
+		changes, err := config.MergeSiteDeviceOverrides(filepath.Join(configDir, t.File), t.Key, t.Type, plan[t], false)
+		if err != nil {
+			return err
+		}
+		for _, c := range changes {
+			current := c.Old
+			if c.Added {
+				current = "(new entry)"
+			}
+			tableRows = append(tableRows, formatter.GenericTableData{
+				"site": t.Site, "type": t.Type, "mac": c.MAC, "field": c.Field, "current": current, "new": c.New,
+			})
+		}
+	}
+	if len(tableRows) == 0 {
+		fmt.Printf("%s Site configs already match %s\n", symbols.SuccessPrefix(), parsed.File)
+		return nil
+	}
+	printer := formatter.NewGenericTablePrinter(formatter.TableConfig{
+		Title:         fmt.Sprintf("Device Overrides (%d change(s))", len(tableRows)),
+		Format:        "table",
+		BoldHeaders:   true,
+		ShowSeparator: true,
+		CommandPath:   "import.device-overrides",
+		Columns: []formatter.TableColumn{
+			{Field: "site", Title: "Site"},
+			{Field: "type", Title: "Type"},
+			{Field: "mac", Title: "MAC"},
+			{Field: "field", Title: "Field"},
+			{Field: "current", Title: "Current"},
+			{Field: "new", Title: "New"},
+		},
+	}, tableRows)
+	fmt.Print(printer.Print())
+
+	if parsed.Diff {
+		return nil
+	}
+	if !parsed.Force {
+		fmt.Printf("%s %s ", i18n.T("device_overrides.confirm", len(tableRows)), i18n.T("prompt.yes_no"))
+		if !confirmPrompt() {
+			return fmt.Errorf("aborted; no files changed")
+		}
+	}
+
+	files := make(map[string]bool)
+	for _, t := range targets {
+		if _, err := config.MergeSiteDeviceOverrides(filepath.Join(configDir, t.File), t.Key, t.Type, plan[t], true); err != nil {
+			return err
+		}
+		files[t.File] = true
+	}
+	fmt.Printf("%s Applied %d change(s) to %d site config file(s); run 'wifimgr apply' to push them\n",
+		symbols.SuccessPrefix(), len(tableRows), len(files))
+	return nil
+}
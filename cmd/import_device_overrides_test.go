@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ravinald/wifimgr/internal/config"
+)
+
+func TestPlanDeviceOverrides(t *testing.T) {
+	inv := &config.InventoryFile{}
+	inv.Config.Inventory.Site = map[string]config.SiteInventory{
+		"US-LAB-01": {AP: []string{"aa0000000001"}, Switch: []string{"bb0000000001"}},
+		"US-NOCFG":  {AP: []string{"cc0000000001"}},
+	}
+	sites := []configuredSite{{Key: "us-lab-01", File: "sites/lab.json"}}
+
+	plan, problems := planDeviceOverrides([]config.DeviceOverride{
+		{Line: 2, MAC: "AA:00:00:00:00:01", Name: "ap-lobby", RadioProfile: "lobby"},
+		{Line: 3, MAC: "bb-00-00-00-00-01", Notes: "idf-2"},
+	}, inv, sites)
+	if len(problems) != 0 {
+		t.Fatalf("problems = %v", problems)
+	}
+	ap := overrideTarget{Site: "US-LAB-01", File: "sites/lab.json", Key: "us-lab-01", Type: "ap"}
+	sw := overrideTarget{Site: "US-LAB-01", File: "sites/lab.json", Key: "us-lab-01", Type: "switch"}
+	if len(plan[ap]) != 1 || len(plan[sw]) != 1 {
+		t.Errorf("plan = %+v", plan)
+	}
+
+	plan, problems = planDeviceOverrides([]config.DeviceOverride{
+		{Line: 2, MAC: "not-a-mac", Name: "x"},
+		{Line: 3, MAC: "dd0000000001", Name: "x"},
+		{Line: 4, MAC: "bb0000000001", DeviceProfile: "core"},
+		{Line: 5, MAC: "cc0000000001", Name: "x"},
+		{Line: 6, MAC: "aa0000000001"},
+		{Line: 7, MAC: "aa:00:00:00:00:01", Name: "x"},
+	}, inv, sites)
+	if plan != nil {
+		t.Errorf("a bad sheet should place no rows, got %+v", plan)
+	}
+	want := []string{"invalid MAC", "not in inventory", "APs only", "no site config", "no fields", "already given on line 6"}
+	if len(problems) != len(want) {
+		t.Fatalf("problems = %v", problems)
+	}
+	for i, w := range want {
+		if !strings.Contains(problems[i], w) {
+			t.Errorf("problem %d = %q, want %q", i, problems[i], w)
+		}
+	}
+}
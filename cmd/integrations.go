@@ -0,0 +1,51 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	// Integrations register themselves from init; importing a package here
+	// is all it takes to add one to every hook and to integrations status.
+	_ "github.com/ravinald/wifimgr/internal/consistency"
+	_ "github.com/ravinald/wifimgr/internal/integrations/netbox"
+	_ "github.com/ravinald/wifimgr/internal/notify"
+	_ "github.com/ravinald/wifimgr/internal/remotebackup"
+	_ "github.com/ravinald/wifimgr/internal/siem"
+	_ "github.com/ravinald/wifimgr/internal/sitelock"
+	_ "github.com/ravinald/wifimgr/internal/telemetry"
+	_ "github.com/ravinald/wifimgr/internal/webhook"
+)
+
+// integrationsCmd groups commands for the external systems wifimgr talks to
+// besides the vendor APIs.
+var integrationsCmd = &cobra.Command{
+	Use:   "integrations",
+	Short: "Check the external systems wifimgr is configured to use",
+	Long: `Commands for the external systems wifimgr uses besides the vendor APIs:
+NetBox, the consistency DNS server, the remote backup bucket, the apply lock
+backend, the telemetry endpoint, user announcements, the SIEM syslog
+receiver, and the notification webhook.
+
+Each integration registers itself with the hooks it implements; apply,
+refresh, and WLAN changes fire those hooks on every configured integration.`,
+	Example: `  wifimgr integrations list
+  wifimgr integrations status`,
+}
+
+func init() {
+	rootCmd.AddCommand(integrationsCmd)
+}
@@ -0,0 +1,106 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/formatter"
+	"github.com/ravinald/wifimgr/internal/integrations"
+)
+
+var integrationsListCmd = &cobra.Command{
+	Use:   "list [format json|csv]",
+	Short: "List registered integrations and the hooks they implement",
+	Long: `List every registered integration with the config section it reads, the
+hooks it implements, and whether it is configured. Hooks of an integration
+that is not configured are never called.
+
+Hooks:
+  check          Run by integrations status
+  post-apply     Run after an apply that changed something or failed
+  post-refresh   Run after refresh rewrites the cache
+  notify         Run for user-facing events such as a WLAN change
+
+Arguments:
+  format        Optional. "json" or "csv" (default: table)`,
+	Example: `  wifimgr integrations list
+  wifimgr integrations list format json`,
+	Annotations: map[string]string{
+		cmdutils.AnnotationNeedsConfig: "true",
+	},
+	RunE: runIntegrationsList,
+}
+
+func init() {
+	integrationsCmd.AddCommand(integrationsListCmd)
+}
+
+func runIntegrationsList(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+	format := "table"
+	for i := 0; i < len(args); i++ {
+		switch strings.ToLower(args[i]) {
+		case "format":
+			if i+1 >= len(args) {
+				return fmt.Errorf("'format' requires a format type (json, csv)")
+			}
+			i++
+			format = strings.ToLower(args[i])
+			if format != "json" && format != "csv" {
+				return fmt.Errorf("invalid format %q: must be 'json' or 'csv'", args[i])
+			}
+		default:
+			return fmt.Errorf("unexpected argument: %s", args[i])
+		}
+	}
+
+	all := integrations.All()
+	rows := make([]formatter.GenericTableData, 0, len(all))
+	for _, i := range all {
+		configured := "no"
+		if i.IsConfigured() {
+			configured = "yes"
+		}
+		rows = append(rows, formatter.GenericTableData{
+			"name":       i.Name,
+			"config_key": i.ConfigKey,
+			"hooks":      strings.Join(i.Hooks(), ", "),
+			"configured": configured,
+		})
+	}
+	printer := formatter.NewGenericTablePrinter(formatter.TableConfig{
+		Title:         "Integrations",
+		Format:        format,
+		BoldHeaders:   true,
+		ShowSeparator: true,
+		CommandPath:   "integrations list",
+		Columns: []formatter.TableColumn{
+			{Field: "name", Title: "Name"},
+			{Field: "config_key", Title: "Config Key"},
+			{Field: "hooks", Title: "Hooks"},
+			{Field: "configured", Title: "Configured"},
+		},
+	}, rows)
+	fmt.Print(printer.Print())
+	return nil
+}
@@ -0,0 +1,269 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/formatter"
+	"github.com/ravinald/wifimgr/internal/integrations"
+	"github.com/ravinald/wifimgr/internal/symbols"
+)
+
+var integrationsStatusCmd = &cobra.Command{
+	Use:   "status [format json|csv]",
+	Short: "Test connectivity and credentials of every configured integration",
+	Long: `Test every configured integration in one run and report, for each, whether
+it could be reached with the configured credentials. A failure comes with a
+hint naming the config key most likely at fault.
+
+Every registered integration is checked (see "integrations list"). Its
+config section is first validated against the integration's schema, then
+its check runs. The built-in checks:
+  apply-lock      Reads a probe key from the lock backend (apply_lock.*)
+  dns             Queries the consistency DNS server for the zone's NS
+                  records (consistency.dns.*)
+  netbox          Reads the NetBox status endpoint (netbox.*)
+  notify-users    Renders a sample announcement with the template (notify_users.*)
+  remote-backup   Lists the backup bucket under its prefix (backup.remote.*)
+  telemetry       Sends a HEAD request to the upload endpoint (telemetry.endpoint)
+
+Integrations without configuration are listed as not configured. Nothing is
+written to any of them. The command exits non-zero when a check fails, so it
+can gate a CI job or a monitoring probe.
+
+Arguments:
+  format        Optional. "json" or "csv" (default: table)`,
+	Example: `  wifimgr integrations status
+  wifimgr integrations status format json`,
+	Annotations: map[string]string{
+		cmdutils.AnnotationNeedsConfig: "true",
+	},
+	RunE: runIntegrationsStatus,
+}
+
+func init() {
+	integrationsCmd.AddCommand(integrationsStatusCmd)
+}
+
+// integrationCheckTimeout bounds each check so one unreachable system cannot
+// hold up the others.
+const integrationCheckTimeout = 10 * time.Second
+
+// Integration check outcomes.
+const (
+	integrationOK            = "ok"
+	integrationFailed        = "failed"
+	integrationNotConfigured = "not configured"
+)
+
+// integrationResult is one row of integrations status.
+type integrationResult struct {
+	Integration string `json:"integration"`
+	Status      string `json:"status"`
+	Detail      string `json:"detail,omitempty"`
+	Hint        string `json:"hint,omitempty"`
+}
+
+func runIntegrationsStatus(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+	format := "table"
+	for i := 0; i < len(args); i++ {
+		switch strings.ToLower(args[i]) {
+		case "format":
+			if i+1 >= len(args) {
+				return fmt.Errorf("'format' requires a format type (json, csv)")
+			}
+			i++
+			format = strings.ToLower(args[i])
+			if format != "json" && format != "csv" {
+				return fmt.Errorf("invalid format %q: must be 'json' or 'csv'", args[i])
+			}
+		default:
+			return fmt.Errorf("unexpected argument: %s", args[i])
+		}
+	}
+
+	results := runIntegrationChecks(cmd.Context(), integrations.All())
+
+	if format == "json" {
+		out, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal integration status: %w", err)
+		}
+		fmt.Println(string(out))
+	} else if format == "csv" {
+		rows := make([]formatter.GenericTableData, 0, len(results))
+		for _, r := range results {
+			rows = append(rows, formatter.GenericTableData{
+				"integration": r.Integration,
+				"status":      r.Status,
+				"detail":      r.Detail,
+				"hint":        r.Hint,
+			})
+		}
+		printer := formatter.NewGenericTablePrinter(formatter.TableConfig{
+			Format:      format,
+			CommandPath: "integrations status",
+			Columns: []formatter.TableColumn{
+				{Field: "integration", Title: "Integration"},
+				{Field: "status", Title: "Status"},
+				{Field: "detail", Title: "Detail"},
+				{Field: "hint", Title: "Hint"},
+			},
+		}, rows)
+		fmt.Print(printer.Print())
+	} else {
+		printIntegrationResults(results)
+	}
+
+	failed := 0
+	for _, r := range results {
+		if r.Status == integrationFailed {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d integration check(s) failed", failed)
+	}
+	return nil
+}
+
+// printIntegrationResults prints one line per integration, with the failure
+// and its hint underneath. Details and hints are sentences, too long to share
+// a table row.
+func printIntegrationResults(results []integrationResult) {
+	for _, r := range results {
+		switch r.Status {
+		case integrationOK:
+			fmt.Printf("%s %s: %s\n", symbols.SuccessPrefix(), r.Integration, r.Detail)
+		case integrationFailed:
+			fmt.Printf("%s %s: %s\n", symbols.FailurePrefix(), r.Integration, r.Detail)
+			fmt.Printf("    Hint: %s\n", r.Hint)
+		default:
+			fmt.Printf("  %s: %s\n", r.Integration, r.Status)
+		}
+	}
+}
+
+// runIntegrationChecks validates and checks each integration in order, each
+// check under its own timeout.
+func runIntegrationChecks(ctx context.Context, all []integrations.Integration) []integrationResult {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	results := make([]integrationResult, 0, len(all))
+	for _, i := range all {
+		r := integrationResult{Integration: i.Name, Status: integrationOK}
+		if !i.IsConfigured() {
+			r.Status = integrationNotConfigured
+			results = append(results, r)
+			continue
+		}
+		if err := integrations.ValidateConfig(i); err != nil {
+			r.Status = integrationFailed
+			r.Detail = firstLine(err.Error(), 160)
+			r.Hint = "fix the " + i.ConfigKey + " section of the config"
+			results = append(results, r)
+			continue
+		}
+		if i.Check == nil {
+			r.Detail = "configured (no connectivity check)"
+			results = append(results, r)
+			continue
+		}
+
+		checkCtx, cancel := context.WithTimeout(ctx, integrationCheckTimeout)
+		detail, err := i.Check(checkCtx)
+		cancel()
+		if err != nil {
+			r.Status = integrationFailed
+			r.Detail = firstLine(err.Error(), 160)
+			r.Hint = integrationHint(i, err)
+		} else {
+			r.Detail = detail
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+var (
+	authFailure     = regexp.MustCompile(`\b(401|403)\b|(?i)unauthorized|forbidden|access ?denied|invalid ?token|WRONGPASS|NOAUTH|UnrecognizedClient|InvalidAccessKeyId|SignatureDoesNotMatch`)
+	missingResource = regexp.MustCompile(`\b404\b|NoSuchBucket|ResourceNotFound`)
+)
+
+// integrationHint turns a failed check into the next step to take: which
+// config key to check for the kind of failure seen.
+func integrationHint(i integrations.Integration, err error) string {
+	if i.Hint != nil {
+		if hint := i.Hint(err); hint != "" {
+			return hint
+		}
+	}
+	var dnsErr *net.DNSError
+	var unknownAuthority x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var certErr x509.CertificateInvalidError
+	msg := err.Error()
+
+	switch {
+	case errors.As(err, &dnsErr) && dnsErr.IsNotFound:
+		return fmt.Sprintf("host %s does not resolve; check %s", dnsErr.Name, i.EndpointKeys)
+	case errors.As(err, &unknownAuthority), errors.As(err, &hostnameErr), errors.As(err, &certErr):
+		return "TLS certificate not trusted; add its CA to the system trust store or check the host name in " + i.EndpointKeys
+	case errors.Is(err, context.DeadlineExceeded), isTimeout(err):
+		return fmt.Sprintf("no answer within %s; check %s and any firewall or proxy in between", integrationCheckTimeout, i.EndpointKeys)
+	case strings.Contains(msg, "connection refused"):
+		return "connection refused; check the host and port in " + i.EndpointKeys
+	case i.CredentialKeys != "" && authFailure.MatchString(msg):
+		return "credentials rejected; check " + i.CredentialKeys
+	case missingResource.MatchString(msg):
+		return "reached, but the target does not exist; check " + i.EndpointKeys
+	case errors.As(err, &dnsErr):
+		return "DNS query failed; check " + i.EndpointKeys
+	}
+	return "check " + i.EndpointKeys
+}
+
+func isTimeout(err error) bool {
+	var ne net.Error
+	return errors.As(err, &ne) && ne.Timeout()
+}
+
+// firstLine returns the first line of s, cut to max runes.
+func firstLine(s string, max int) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = s[:i]
+	}
+	if r := []rune(s); len(r) > max {
+		s = string(r[:max-1]) + "…"
+	}
+	return s
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/ravinald/wifimgr/internal/integrations"
+)
+
+func TestRunIntegrationChecks(t *testing.T) {
+	checks := []integrations.Integration{
+		{Name: "a", ConfigKey: "a", Check: func(context.Context) (string, error) { return "reached", nil }},
+		{Name: "b", ConfigKey: "b", Configured: func() bool { return false }},
+		{Name: "c", ConfigKey: "c", EndpointKeys: "c.url", CredentialKeys: "c.token", Check: func(context.Context) (string, error) {
+			return "", fmt.Errorf("401 Unauthorized\nbody follows")
+		}},
+		{Name: "d", ConfigKey: "d"},
+	}
+	got := runIntegrationChecks(context.Background(), checks)
+	want := []integrationResult{
+		{Integration: "a", Status: integrationOK, Detail: "reached"},
+		{Integration: "b", Status: integrationNotConfigured},
+		{Integration: "c", Status: integrationFailed, Detail: "401 Unauthorized", Hint: "credentials rejected; check c.token"},
+		{Integration: "d", Status: integrationOK, Detail: "configured (no connectivity check)"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d results, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("result %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestIntegrationHint(t *testing.T) {
+	c := integrations.Integration{Name: "netbox", EndpointKeys: "netbox.url", CredentialKeys: "netbox.credentials.api_key"}
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"unresolvable host", &net.DNSError{Name: "nb.example", IsNotFound: true}, "host nb.example does not resolve"},
+		{"timeout", fmt.Errorf("get: %w", context.DeadlineExceeded), "no answer within"},
+		{"refused", errors.New("dial tcp 10.0.0.1:443: connect: connection refused"), "connection refused; check the host and port in netbox.url"},
+		{"forbidden", errors.New("HTTP 403: AccessDenied"), "credentials rejected; check netbox.credentials.api_key"},
+		{"missing", errors.New("HTTP 404: NoSuchBucket"), "the target does not exist; check netbox.url"},
+		{"other", errors.New("boom"), "check netbox.url"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := integrationHint(c, tt.err); !strings.Contains(got, tt.want) {
+				t.Errorf("hint = %q, want it to contain %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIntegrationHintPrefersOwnHint(t *testing.T) {
+	c := integrations.Integration{
+		Name:         "dns",
+		EndpointKeys: "consistency.dns.server",
+		Hint: func(err error) string {
+			if strings.Contains(err.Error(), "zone") {
+				return "check consistency.dns.domain"
+			}
+			return ""
+		},
+	}
+	if got := integrationHint(c, errors.New("no such zone")); got != "check consistency.dns.domain" {
+		t.Errorf("hint = %q, want the integration's own hint", got)
+	}
+	if got := integrationHint(c, errors.New("boom")); got != "check consistency.dns.server" {
+		t.Errorf("hint = %q, want the generic hint", got)
+	}
+}
@@ -0,0 +1,37 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// inventoryCmd groups maintenance of the local inventory.json allowlist.
+var inventoryCmd = &cobra.Command{
+	Use:   "inventory",
+	Short: "Maintain the local inventory.json allowlist",
+	Long: `Maintain inventory.json — the per-site allowlist of devices wifimgr may
+write configuration to — against the org inventory in the local cache.
+
+To arm or disarm individual devices use 'wifimgr set site <site> ... managed|unmanaged'.`,
+	Example: `  # Reconcile inventory.json with the org after a procurement batch
+  wifimgr refresh all
+  wifimgr inventory sync-file`,
+}
+
+func init() {
+	rootCmd.AddCommand(inventoryCmd)
+}
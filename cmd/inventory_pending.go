@@ -0,0 +1,360 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/formatter"
+	"github.com/ravinald/wifimgr/internal/i18n"
+	"github.com/ravinald/wifimgr/internal/macaddr"
+	"github.com/ravinald/wifimgr/internal/symbols"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+// inventoryPendingCmd represents the "inventory pending" command
+var inventoryPendingCmd = &cobra.Command{
+	Use:   "pending [site <site-name>] [target <api-label>] [approve] [force] [format json|csv]",
+	Short: "List claimed devices wifimgr does not manage yet, and approve them",
+	Long: `List devices the org has claimed that are neither armed in inventory.json
+nor declared in any site config — new hardware waiting to be adopted. The org
+inventory is read from the local cache, so run 'wifimgr refresh' first.
+
+With 'approve', each pending device is offered in turn. Approving one arms it
+in inventory.json under the site the org assigns it to and adds a stub entry
+(MAC and current name) to that site's config, ready to fill in and apply.
+Devices the org has not assigned to a site are listed but cannot be approved
+until they are assigned. --yes approves every device; --no-input approves
+none.
+
+Arguments:
+  site <name>      Optional. Only devices the org assigns to this site
+  target <label>   Optional. Only use this API's cache
+  approve          Optional. Approve pending devices interactively
+  force            Optional. With approve, approve every device without prompting
+  format           Optional. "json" or "csv" (default: table)`,
+	Example: `  wifimgr inventory pending
+  wifimgr inventory pending site US-LAB-01
+  wifimgr inventory pending approve
+  wifimgr inventory pending site US-LAB-01 approve force`,
+	RunE: runInventoryPending,
+}
+
+func init() {
+	inventoryCmd.AddCommand(inventoryPendingCmd)
+}
+
+// inventoryPendingArgs holds the parsed positional arguments.
+type inventoryPendingArgs struct {
+	SiteName string
+	Target   string
+	Approve  bool
+	Force    bool
+	Format   string
+}
+
+func parseInventoryPendingArgs(args []string) (*inventoryPendingArgs, error) {
+	out := &inventoryPendingArgs{Format: "table"}
+	for i := 0; i < len(args); i++ {
+		switch strings.ToLower(args[i]) {
+		case "site":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("'site' requires a site name")
+			}
+			out.SiteName = cmdutils.StripQuotes(args[i+1])
+			i++
+		case "target":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("'target' requires an API label")
+			}
+			out.Target = cmdutils.StripQuotes(args[i+1])
+			i++
+		case "approve":
+			out.Approve = true
+		case "force":
+			out.Force = true
+		case "format":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("'format' requires a format type (json, csv)")
+			}
+			switch f := strings.ToLower(args[i+1]); f {
+			case "json", "csv":
+				out.Format = f
+			default:
+				return nil, fmt.Errorf("invalid format %q: must be 'json' or 'csv'", args[i+1])
+			}
+			i++
+		default:
+			return nil, fmt.Errorf("unexpected argument: %s", args[i])
+		}
+	}
+	if out.Force && !out.Approve {
+		return nil, fmt.Errorf("'force' only applies with 'approve'")
+	}
+	if out.Approve && out.Format != "table" {
+		return nil, fmt.Errorf("'approve' cannot be combined with 'format'")
+	}
+	return out, nil
+}
+
+// pendingDevice is a claimed device that wifimgr does not manage yet.
+type pendingDevice struct {
+	Site  string `json:"site,omitempty"` // site the org assigns it to; empty when unassigned
+	Type  string `json:"type"`
+	MAC   string `json:"mac"`
+	Name  string `json:"name,omitempty"`
+	Model string `json:"model,omitempty"`
+	API   string `json:"api"`
+	File  string `json:"site_config,omitempty"` // site config the stub goes into
+	key   string // key under config.sites in File
+}
+
+func runInventoryPending(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+
+	parsed, err := parseInventoryPendingArgs(args)
+	if err != nil {
+		return err
+	}
+	SetAPITarget(parsed.Target)
+	if err := ValidateAPIFlag(); err != nil {
+		return err
+	}
+
+	path := config.InventoryPath(globalConfig)
+	inv, err := config.LoadInventory(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		inv = &config.InventoryFile{}
+	}
+	configDir := viper.GetString("files.config_dir")
+	sites := loadConfiguredSites(configDir, viper.GetStringSlice("files.site_configs"))
+
+	cacheMgr := GetCacheManager()
+	if cacheMgr == nil {
+		return fmt.Errorf("cache not initialized; run a refresh first")
+	}
+	caches := make(map[string]*vendors.APICache)
+	for _, apiLabel := range GetTargetAPIs() {
+		if cache, err := cacheMgr.GetAPICache(apiLabel); err == nil {
+			caches[apiLabel] = cache
+		}
+	}
+	if len(caches) == 0 {
+		return fmt.Errorf("no cached org inventory; run 'wifimgr refresh' first")
+	}
+
+	pending := findPendingDevices(sites, inv, caches, parsed.SiteName)
+
+	if parsed.Format == "json" {
+		out, err := json.MarshalIndent(pending, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal pending devices: %w", err)
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+	if len(pending) == 0 {
+		if parsed.Format == "table" {
+			fmt.Printf("%s No pending devices: every claimed device is armed or in a site config\n", symbols.SuccessPrefix())
+		}
+		return nil
+	}
+	printPendingDevices(pending, parsed.Format)
+	if !parsed.Approve {
+		return nil
+	}
+	if path == "" {
+		return fmt.Errorf("no inventory file configured (files.inventory)")
+	}
+
+	var approved []pendingDevice
+	for _, d := range pending {
+		if d.Site == "" {
+			fmt.Printf("%s %s %s is not assigned to a site; assign it in the org first\n", symbols.WarningPrefix(), d.Type, pendingLabel(d))
+			continue
+		}
+		if parsed.Force || confirmPendingApproval(d) {
+			approved = append(approved, d)
+		}
+	}
+	if len(approved) == 0 {
+		fmt.Println("No devices approved; no files changed")
+		return nil
+	}
+	return approvePendingDevices(path, configDir, inv, approved)
+}
+
+// findPendingDevices returns every device in the cached org inventories that
+// is neither armed in inv (at any site) nor declared in a site config. With a
+// siteFilter only devices the org assigns to that site are returned.
+func findPendingDevices(sites []configuredSite, inv *config.InventoryFile, caches map[string]*vendors.APICache, siteFilter string) []pendingDevice {
+	known := make(map[string]bool)
+	siteFiles := make(map[string]configuredSite)
+	for _, site := range sites {
+		siteFiles[strings.ToLower(site.Key)] = site
+		for _, keys := range configuredDeviceMACs(site.Devices) {
+			for _, key := range keys {
+				known[macaddr.NormalizeOrEmpty(key)] = true
+			}
+		}
+	}
+	for _, siteName := range inv.SiteNames() {
+		for _, dtype := range []string{"ap", "switch", "gateway"} {
+			for _, raw := range inv.MACsForSite(siteName, dtype) {
+				known[macaddr.NormalizeOrEmpty(raw)] = true
+			}
+		}
+	}
+
+	var pending []pendingDevice
+	for label, cache := range caches {
+		for dtype, items := range map[string]map[string]*vendors.InventoryItem{
+			"ap": cache.Inventory.AP, "switch": cache.Inventory.Switch, "gateway": cache.Inventory.Gateway,
+		} {
+			for mac, item := range items {
+				n := macaddr.NormalizeOrEmpty(mac)
+				if n == "" || known[n] {
+					continue
+				}
+				site := cache.SiteIndex.ByID[item.SiteID]
+				if siteFilter != "" && !strings.EqualFold(site, siteFilter) {
+					continue
+				}
+				d := pendingDevice{Site: site, Type: dtype, MAC: n, Name: item.Name, Model: item.Model, API: label}
+				if sc, ok := siteFiles[strings.ToLower(site)]; ok && site != "" {
+					d.File, d.key = sc.File, sc.Key
+				}
+				pending = append(pending, d)
+			}
+		}
+	}
+
+	sort.Slice(pending, func(i, j int) bool {
+		a, b := pending[i], pending[j]
+		if a.Site != b.Site {
+			return a.Site < b.Site
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.MAC < b.MAC
+	})
+	return pending
+}
+
+// confirmPendingApproval asks whether to adopt one device; --yes and
+// --no-input apply.
+func confirmPendingApproval(d pendingDevice) bool {
+	fmt.Printf("Approve %s %s (%s) at %s? %s ", d.Type, pendingLabel(d), d.Model, d.Site, i18n.T("prompt.yes_no"))
+	return confirmPrompt()
+}
+
+// approvePendingDevices arms the approved devices in inventory.json and adds
+// stubs to their site configs. An armed site keeps its existing key spelling.
+func approvePendingDevices(path, configDir string, inv *config.InventoryFile, approved []pendingDevice) error {
+	armedKeys := make(map[string]string)
+	for _, site := range inv.SiteNames() {
+		armedKeys[strings.ToLower(site)] = site
+	}
+
+	changes := make([]inventoryChange, 0, len(approved))
+	entries := make([]danglingEntry, 0, len(approved))
+	for _, d := range approved {
+		site := d.Site
+		if key, ok := armedKeys[strings.ToLower(site)]; ok {
+			site = key
+		}
+		changes = append(changes, inventoryChange{Site: site, Type: d.Type, MAC: d.MAC, Name: d.Name})
+		entries = append(entries, danglingEntry{Site: d.Site, File: d.File, Key: d.key, Type: d.Type, MAC: d.MAC, Name: d.Name})
+	}
+
+	for site, g := range groupInventoryChanges(changes) {
+		if err := config.ArmSiteDevices(path, site, g["ap"], g["switch"], g["gateway"], ""); err != nil {
+			return err
+		}
+	}
+	added, skipped, err := appendMissingEntries(configDir, entries)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Approved %d device(s): armed in %s, %d added to site configs\n",
+		symbols.SuccessPrefix(), len(approved), path, added)
+	for _, site := range skipped {
+		fmt.Printf("%s Site %s has no site config; add one (wifimgr init site) and rerun 'lint dangling fix append-missing'\n",
+			symbols.WarningPrefix(), site)
+	}
+	return nil
+}
+
+func pendingLabel(d pendingDevice) string {
+	if d.Name != "" {
+		return d.Name + " " + d.MAC
+	}
+	return d.MAC
+}
+
+func printPendingDevices(pending []pendingDevice, format string) {
+	rows := make([]formatter.GenericTableData, 0, len(pending))
+	for _, d := range pending {
+		site := d.Site
+		if site == "" && format == "table" {
+			site = "(unassigned)"
+		}
+		file := d.File
+		if file == "" && d.Site != "" && format == "table" {
+			file = "(no site config)"
+		}
+		rows = append(rows, formatter.GenericTableData{
+			"site": site, "type": d.Type, "name": d.Name, "mac": d.MAC, "model": d.Model, "api": d.API, "file": file,
+		})
+	}
+	printer := formatter.NewGenericTablePrinter(formatter.TableConfig{
+		Title:         fmt.Sprintf("Pending Devices (%d)", len(pending)),
+		Format:        format,
+		BoldHeaders:   true,
+		ShowSeparator: true,
+		CommandPath:   "inventory.pending",
+		Columns: []formatter.TableColumn{
+			{Field: "site", Title: "Site"},
+			{Field: "type", Title: "Type"},
+			{Field: "name", Title: "Name"},
+			{Field: "mac", Title: "MAC"},
+			{Field: "model", Title: "Model"},
+			{Field: "api", Title: "API"},
+			{Field: "file", Title: "Site Config"},
+		},
+	}, rows)
+	fmt.Print(printer.Print())
+}
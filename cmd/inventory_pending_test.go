@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+func TestFindPendingDevices(t *testing.T) {
+	cache := vendors.NewAPICache("mist-prod", "mist", "org")
+	for id, name := range map[string]string{"s1": "US-LAB-01", "s2": "US-HQ-01"} {
+		cache.SiteIndex.ByID[id] = name
+		cache.SiteIndex.ByName[name] = id
+	}
+	cache.Inventory.AP["aa0000000001"] = &vendors.InventoryItem{MAC: "aa0000000001", Name: "ap-armed", SiteID: "s1"}
+	cache.Inventory.AP["aa0000000002"] = &vendors.InventoryItem{MAC: "aa0000000002", Name: "ap-configured", SiteID: "s1"}
+	cache.Inventory.AP["aa0000000003"] = &vendors.InventoryItem{MAC: "aa0000000003", Name: "ap-new", SiteID: "s1", Model: "AP45"}
+	cache.Inventory.Switch["bb0000000001"] = &vendors.InventoryItem{MAC: "bb0000000001", Name: "sw-hq", SiteID: "s2"}
+	cache.Inventory.AP["aa0000000004"] = &vendors.InventoryItem{MAC: "aa0000000004"}
+	caches := map[string]*vendors.APICache{"mist-prod": cache}
+
+	inv := &config.InventoryFile{}
+	inv.Config.Inventory.Site = map[string]config.SiteInventory{
+		"US-HQ-01": {AP: []string{"aa:00:00:00:00:01"}}, // armed at another site still counts as known
+	}
+	sites := []configuredSite{{
+		Key:  "US-LAB-01",
+		File: "sites/us-lab-01.json",
+		Devices: config.Devices{
+			APs: map[string]config.APConfig{"AA-00-00-00-00-02": {}},
+		},
+	}}
+
+	pending := findPendingDevices(sites, inv, caches, "")
+	if len(pending) != 3 {
+		t.Fatalf("got %d pending devices, want 3: %+v", len(pending), pending)
+	}
+	// Unassigned first (empty site sorts first), then by site and type.
+	if pending[0].MAC != "aa0000000004" || pending[0].Site != "" {
+		t.Errorf("pending[0] = %+v, want the unassigned AP", pending[0])
+	}
+	if d := pending[2]; d.Name != "ap-new" || d.File != "sites/us-lab-01.json" || d.key != "US-LAB-01" || d.Model != "AP45" || d.API != "mist-prod" {
+		t.Errorf("pending[2] = %+v", d)
+	}
+	if d := pending[1]; d.Name != "sw-hq" || d.File != "" {
+		t.Errorf("pending[1] = %+v, want sw-hq with no site config", d)
+	}
+
+	if got := findPendingDevices(sites, inv, caches, "us-hq-01"); len(got) != 1 || got[0].Name != "sw-hq" {
+		t.Errorf("site-filtered = %+v", got)
+	}
+}
+
+func TestParseInventoryPendingArgs(t *testing.T) {
+	a, err := parseInventoryPendingArgs([]string{"site", "US-LAB-01", "approve", "force"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.SiteName != "US-LAB-01" || !a.Approve || !a.Force || a.Format != "table" {
+		t.Errorf("parsed = %+v", a)
+	}
+	for _, bad := range [][]string{{"force"}, {"approve", "format", "json"}, {"bogus"}} {
+		if _, err := parseInventoryPendingArgs(bad); err == nil {
+			t.Errorf("expected error for %v", bad)
+		}
+	}
+}
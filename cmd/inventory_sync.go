@@ -0,0 +1,357 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/formatter"
+	"github.com/ravinald/wifimgr/internal/i18n"
+	"github.com/ravinald/wifimgr/internal/macaddr"
+	"github.com/ravinald/wifimgr/internal/symbols"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+// inventorySyncCmd represents the "inventory sync-file" command
+var inventorySyncCmd = &cobra.Command{
+	Use:   "sync-file [site <site-name>] [target <api-label>] [diff] [force]",
+	Short: "Update inventory.json from the current org inventory",
+	Long: `Reconcile inventory.json with the org inventory in the local cache so the
+managed-device list stays accurate without hand-editing after every
+procurement batch or RMA.
+
+For each site already in inventory.json (or the one named with 'site'):
+
+  add     devices the org has assigned to the site that are not armed there
+  remove  armed MACs no longer in the org inventory, or now assigned to a
+          different site (they are added under the new site if it is in scope)
+
+The plan is printed first; additions and removals are confirmed separately.
+Armed sites that no cached API knows are left untouched. Run 'wifimgr refresh'
+first so the cache reflects the org.
+
+Arguments:
+  site <name>      Optional. Sync one site (may be a site not yet in the file)
+  target <label>   Optional. Only use this API's cache
+  diff             Optional. Show the plan without writing
+  force            Optional. Apply additions and removals without prompting`,
+	Example: `  wifimgr inventory sync-file diff
+  wifimgr inventory sync-file site US-LAB-01
+  wifimgr inventory sync-file force`,
+	RunE: runInventorySync,
+}
+
+func init() {
+	inventoryCmd.AddCommand(inventorySyncCmd)
+}
+
+// inventorySyncArgs holds the parsed positional arguments.
+type inventorySyncArgs struct {
+	SiteName string
+	Target   string
+	DiffOnly bool
+	Force    bool
+}
+
+func parseInventorySyncArgs(args []string) (*inventorySyncArgs, error) {
+	out := &inventorySyncArgs{}
+	for i := 0; i < len(args); i++ {
+		switch strings.ToLower(args[i]) {
+		case "site":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("'site' requires a site name")
+			}
+			out.SiteName = cmdutils.StripQuotes(args[i+1])
+			i++
+		case "target":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("'target' requires an API label")
+			}
+			out.Target = cmdutils.StripQuotes(args[i+1])
+			i++
+		case "diff":
+			out.DiffOnly = true
+		case "force":
+			out.Force = true
+		default:
+			return nil, fmt.Errorf("unexpected argument: %s", args[i])
+		}
+	}
+	return out, nil
+}
+
+func runInventorySync(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+
+	parsed, err := parseInventorySyncArgs(args)
+	if err != nil {
+		return err
+	}
+	SetAPITarget(parsed.Target)
+	if err := ValidateAPIFlag(); err != nil {
+		return err
+	}
+
+	path := config.InventoryPath(globalConfig)
+	if path == "" {
+		return fmt.Errorf("no inventory file configured (files.inventory)")
+	}
+	inv, err := config.LoadInventory(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		inv = &config.InventoryFile{}
+	}
+
+	cacheMgr := GetCacheManager()
+	if cacheMgr == nil {
+		return fmt.Errorf("cache not initialized; run a refresh first")
+	}
+	caches := make(map[string]*vendors.APICache)
+	for _, apiLabel := range GetTargetAPIs() {
+		if cache, err := cacheMgr.GetAPICache(apiLabel); err == nil {
+			caches[apiLabel] = cache
+		}
+	}
+	if len(caches) == 0 {
+		return fmt.Errorf("no cached org inventory; run 'wifimgr refresh' first")
+	}
+
+	plan, err := planInventorySync(inv, caches, parsed.SiteName)
+	if err != nil {
+		return err
+	}
+	for _, site := range plan.Skipped {
+		cmdutils.Noticef("site %q is in %s but not in any cached API; left untouched", site, path)
+	}
+	if len(plan.Add) == 0 && len(plan.Remove) == 0 {
+		fmt.Printf("%s %s already matches the org inventory\n", symbols.SuccessPrefix(), path)
+		return nil
+	}
+	printInventorySyncPlan(plan)
+
+	if parsed.DiffOnly {
+		return nil
+	}
+
+	added, removed := 0, 0
+	if len(plan.Add) > 0 && (parsed.Force || confirmInventorySync(fmt.Sprintf("Add %d device(s) to %s?", len(plan.Add), path))) {
+		for site, g := range groupInventoryChanges(plan.Add) {
+			if err := config.ArmSiteDevices(path, site, g["ap"], g["switch"], g["gateway"], ""); err != nil {
+				return err
+			}
+		}
+		added = len(plan.Add)
+	}
+	if len(plan.Remove) > 0 && (parsed.Force || confirmInventorySync(fmt.Sprintf("Remove %d device(s) from %s?", len(plan.Remove), path))) {
+		for site, g := range groupInventoryChanges(plan.Remove) {
+			if _, err := config.DisarmSiteDevices(path, site, g["ap"], g["switch"], g["gateway"]); err != nil {
+				return err
+			}
+		}
+		removed = len(plan.Remove)
+	}
+
+	fmt.Printf("%s %s: %d added, %d removed\n", symbols.SuccessPrefix(), path, added, removed)
+	return nil
+}
+
+// confirmInventorySync asks a y/N question; --yes and --no-input apply.
+func confirmInventorySync(question string) bool {
+	fmt.Printf("%s %s ", question, i18n.T("prompt.yes_no"))
+	return confirmPrompt()
+}
+
+// inventoryChange is one planned allowlist edit.
+type inventoryChange struct {
+	Site   string
+	Type   string
+	MAC    string
+	Name   string
+	Reason string
+}
+
+// inventorySyncPlan is the difference between inventory.json and the cached
+// org inventory. Skipped lists armed sites no cached API knows about.
+type inventorySyncPlan struct {
+	Add     []inventoryChange
+	Remove  []inventoryChange
+	Skipped []string
+}
+
+// orgDevice is where the org inventory places a device.
+type orgDevice struct {
+	site  string
+	dtype string
+	name  string
+}
+
+// planInventorySync compares inv against the org inventory in caches. With a
+// siteFilter only that site is planned; otherwise every armed site a cache
+// knows about is.
+func planInventorySync(inv *config.InventoryFile, caches map[string]*vendors.APICache, siteFilter string) (*inventorySyncPlan, error) {
+	org := make(map[string]orgDevice)
+	knownSites := make(map[string]string) // lowercase -> cached spelling
+	for _, cache := range caches {
+		for name := range cache.SiteIndex.ByName {
+			knownSites[strings.ToLower(name)] = name
+		}
+		for dtype, items := range map[string]map[string]*vendors.InventoryItem{
+			"ap": cache.Inventory.AP, "switch": cache.Inventory.Switch, "gateway": cache.Inventory.Gateway,
+		} {
+			for mac, item := range items {
+				n := macaddr.NormalizeOrEmpty(mac)
+				if n == "" {
+					continue
+				}
+				org[n] = orgDevice{site: cache.SiteIndex.ByID[item.SiteID], dtype: dtype, name: item.Name}
+			}
+		}
+	}
+
+	plan := &inventorySyncPlan{}
+	var scope []string
+	if siteFilter != "" {
+		name, ok := knownSites[strings.ToLower(siteFilter)]
+		if !ok {
+			return nil, fmt.Errorf("site %q not found in the cache; run 'wifimgr refresh' first", siteFilter)
+		}
+		scope = []string{name}
+	} else {
+		for _, site := range inv.SiteNames() {
+			if _, ok := knownSites[strings.ToLower(site)]; ok {
+				scope = append(scope, site)
+			} else {
+				plan.Skipped = append(plan.Skipped, site)
+			}
+		}
+		sort.Strings(plan.Skipped)
+	}
+	inScope := make(map[string]string, len(scope))
+	for _, site := range scope {
+		inScope[strings.ToLower(site)] = site
+	}
+
+	armed := make(map[string]bool)
+	for _, site := range scope {
+		for _, dtype := range []string{"ap", "switch", "gateway"} {
+			for _, raw := range inv.MACsForSite(site, dtype) {
+				mac := macaddr.NormalizeOrEmpty(raw)
+				if mac == "" {
+					continue
+				}
+				dev, ok := org[mac]
+				switch {
+				case !ok:
+					plan.Remove = append(plan.Remove, inventoryChange{Site: site, Type: dtype, MAC: mac, Reason: "not in org inventory"})
+				case dev.site == "":
+					plan.Remove = append(plan.Remove, inventoryChange{Site: site, Type: dtype, MAC: mac, Name: dev.name, Reason: "unassigned in org"})
+				case !strings.EqualFold(dev.site, site):
+					plan.Remove = append(plan.Remove, inventoryChange{Site: site, Type: dtype, MAC: mac, Name: dev.name, Reason: "moved to " + dev.site})
+				default:
+					armed[mac] = true
+				}
+			}
+		}
+	}
+
+	for mac, dev := range org {
+		site, ok := inScope[strings.ToLower(dev.site)]
+		if !ok || dev.site == "" || armed[mac] {
+			continue
+		}
+		plan.Add = append(plan.Add, inventoryChange{Site: site, Type: dev.dtype, MAC: mac, Name: dev.name, Reason: "assigned in org"})
+	}
+
+	sortInventoryChanges(plan.Add)
+	sortInventoryChanges(plan.Remove)
+	return plan, nil
+}
+
+func sortInventoryChanges(changes []inventoryChange) {
+	sort.Slice(changes, func(i, j int) bool {
+		a, b := changes[i], changes[j]
+		if a.Site != b.Site {
+			return a.Site < b.Site
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.MAC < b.MAC
+	})
+}
+
+// groupInventoryChanges buckets MACs by site then device type, the shape the
+// config arm/disarm helpers take.
+func groupInventoryChanges(changes []inventoryChange) map[string]map[string][]string {
+	out := make(map[string]map[string][]string)
+	for _, c := range changes {
+		if out[c.Site] == nil {
+			out[c.Site] = make(map[string][]string)
+		}
+		out[c.Site][c.Type] = append(out[c.Site][c.Type], c.MAC)
+	}
+	return out
+}
+
+func printInventorySyncPlan(plan *inventorySyncPlan) {
+	rows := make([]formatter.GenericTableData, 0, len(plan.Add)+len(plan.Remove))
+	for _, set := range []struct {
+		action  string
+		changes []inventoryChange
+	}{{"add", plan.Add}, {"remove", plan.Remove}} {
+		for _, c := range set.changes {
+			rows = append(rows, formatter.GenericTableData{
+				"action": set.action,
+				"site":   c.Site,
+				"type":   c.Type,
+				"name":   c.Name,
+				"mac":    c.MAC,
+				"reason": c.Reason,
+			})
+		}
+	}
+	printer := formatter.NewGenericTablePrinter(formatter.TableConfig{
+		Title:         fmt.Sprintf("Inventory Sync Plan (%d to add, %d to remove)", len(plan.Add), len(plan.Remove)),
+		Format:        "table",
+		BoldHeaders:   true,
+		ShowSeparator: true,
+		CommandPath:   "inventory.sync-file",
+		Columns: []formatter.TableColumn{
+			{Field: "action", Title: "Action"},
+			{Field: "site", Title: "Site"},
+			{Field: "type", Title: "Type"},
+			{Field: "name", Title: "Name"},
+			{Field: "mac", Title: "MAC"},
+			{Field: "reason", Title: "Reason"},
+		},
+	}, rows)
+	fmt.Print(printer.Print())
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+func TestPlanInventorySync(t *testing.T) {
+	cache := vendors.NewAPICache("mist-prod", "mist", "org")
+	for id, name := range map[string]string{"s1": "US-LAB-01", "s2": "US-HQ-01", "s3": "US-NEW-01"} {
+		cache.SiteIndex.ByID[id] = name
+		cache.SiteIndex.ByName[name] = id
+	}
+	cache.Inventory.AP["aa0000000001"] = &vendors.InventoryItem{MAC: "aa0000000001", Name: "ap-armed", SiteID: "s1"}
+	cache.Inventory.AP["aa0000000002"] = &vendors.InventoryItem{MAC: "aa0000000002", Name: "ap-new", SiteID: "s1"}
+	cache.Inventory.AP["aa0000000003"] = &vendors.InventoryItem{MAC: "aa0000000003", Name: "ap-moved", SiteID: "s2"}
+	cache.Inventory.Switch["bb0000000001"] = &vendors.InventoryItem{MAC: "bb0000000001", Name: "sw-new", SiteID: "s1"}
+	cache.Inventory.AP["aa0000000009"] = &vendors.InventoryItem{MAC: "aa0000000009", Name: "ap-other", SiteID: "s3"}
+	caches := map[string]*vendors.APICache{"mist-prod": cache}
+
+	inv := &config.InventoryFile{}
+	inv.Config.Inventory.Site = map[string]config.SiteInventory{
+		"us-lab-01":  {AP: []string{"aa:00:00:00:00:01", "aa0000000003", "aa00000000ff"}},
+		"EU-GONE-01": {AP: []string{"cc0000000001"}},
+	}
+
+	plan, err := planInventorySync(inv, caches, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Skipped) != 1 || plan.Skipped[0] != "EU-GONE-01" {
+		t.Errorf("Skipped = %v", plan.Skipped)
+	}
+	// ap-new and sw-new join the armed site under its existing key; US-NEW-01
+	// is not armed, so its device is out of scope.
+	if len(plan.Add) != 2 || plan.Add[0].Name != "ap-new" || plan.Add[1].Name != "sw-new" || plan.Add[0].Site != "us-lab-01" {
+		t.Errorf("Add = %+v", plan.Add)
+	}
+	reasons := map[string]string{}
+	for _, c := range plan.Remove {
+		reasons[c.MAC] = c.Reason
+	}
+	if len(plan.Remove) != 2 || reasons["aa0000000003"] != "moved to US-HQ-01" || reasons["aa00000000ff"] != "not in org inventory" {
+		t.Errorf("Remove = %+v", plan.Remove)
+	}
+
+	// Naming a site brings an unarmed one into scope.
+	plan, err = planInventorySync(inv, caches, "us-new-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Add) != 1 || plan.Add[0].Site != "US-NEW-01" || len(plan.Remove) != 0 {
+		t.Errorf("site plan = %+v", plan)
+	}
+
+	if _, err := planInventorySync(inv, caches, "NOPE"); err == nil {
+		t.Error("expected error for unknown site")
+	}
+}
+
+func TestParseInventorySyncArgs(t *testing.T) {
+	a, err := parseInventorySyncArgs([]string{"site", "US-LAB-01", "diff", "force"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.SiteName != "US-LAB-01" || !a.DiffOnly || !a.Force {
+		t.Errorf("parsed = %+v", a)
+	}
+	if _, err := parseInventorySyncArgs([]string{"bogus"}); err == nil {
+		t.Error("expected error for unknown argument")
+	}
+}
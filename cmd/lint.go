@@ -16,10 +16,14 @@ The lint command performs static analysis on configuration files to detect:
 - Missing required fields
 - Deprecated field usage
 - Vendor-specific incompatibilities
+- Devices that site configs and the org inventory disagree on (dangling)
 
 Use 'wifimgr lint <subcommand> help' for detailed information.`,
 	Example: `  # Lint a site configuration
-  wifimgr lint config US-LAB-01`,
+  wifimgr lint config US-LAB-01
+
+  # Find site-config devices the org no longer has
+  wifimgr lint dangling`,
 }
 
 func init() {
@@ -28,6 +28,7 @@ This command performs comprehensive validation including:
 - Schema validation (field types match expected schema)
 - Vendor block validation (correct vendor-specific fields)
 - Reference validation (profiles and templates exist)
+- Duplicate device names (within the site, and against the cache)
 - Deprecated field detection
 - Range validation (numeric values within acceptable ranges)
 - Radio configuration validation
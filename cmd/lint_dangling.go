@@ -0,0 +1,452 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/formatter"
+	"github.com/ravinald/wifimgr/internal/i18n"
+	"github.com/ravinald/wifimgr/internal/logging"
+	"github.com/ravinald/wifimgr/internal/macaddr"
+	"github.com/ravinald/wifimgr/internal/symbols"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+// Fix modes for lint dangling.
+const (
+	danglingFixCommentOut    = "comment-out"
+	danglingFixAppendMissing = "append-missing"
+	danglingFixAll           = "all"
+)
+
+var lintDanglingCmd = &cobra.Command{
+	Use:   "dangling [site <site-name>] [target <api-label>] [fix comment-out|append-missing|all] [force]",
+	Short: "Find site-config devices missing from the org, and managed devices missing from site configs",
+	Long: `Cross-check site configs, inventory.json, and the cached org inventory:
+
+  not in org          a device in a site config that the org inventory does
+                      not have (RMA'd, decommissioned, or a typo'd MAC)
+  not in site config  a managed MAC in inventory.json that the org has but no
+                      site config declares, so apply has nothing to push
+
+Sites that no cached API knows about are not checked. Run 'wifimgr refresh'
+first so the cache reflects the org.
+
+Fixes (each confirmed unless 'force' is given):
+  fix comment-out     move dangling entries to devices._disabled.<type> in
+                      their site config (JSON has no comments; the loader
+                      ignores the _disabled key, and moving an entry back
+                      restores it)
+  fix append-missing  add a stub entry (MAC and org name) for each missing
+                      managed device to the site config of its inventory site
+  fix all             both
+
+Arguments:
+  site <name>      Optional. Check one site
+  target <label>   Optional. Only use this API's cache
+  fix <mode>       Optional. Rewrite site configs as above
+  force            Optional. Skip the confirmation prompt`,
+	Example: `  wifimgr lint dangling
+  wifimgr lint dangling site US-LAB-01 fix comment-out
+  wifimgr lint dangling fix all force`,
+	RunE: runLintDangling,
+}
+
+func init() {
+	lintCmd.AddCommand(lintDanglingCmd)
+}
+
+// lintDanglingArgs holds the parsed positional arguments.
+type lintDanglingArgs struct {
+	SiteName string
+	Target   string
+	Fix      string
+	Force    bool
+}
+
+func parseLintDanglingArgs(args []string) (*lintDanglingArgs, error) {
+	out := &lintDanglingArgs{}
+	for i := 0; i < len(args); i++ {
+		switch strings.ToLower(args[i]) {
+		case "site":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("'site' requires a site name")
+			}
+			out.SiteName = cmdutils.StripQuotes(args[i+1])
+			i++
+		case "target":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("'target' requires an API label")
+			}
+			out.Target = cmdutils.StripQuotes(args[i+1])
+			i++
+		case "fix":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("'fix' requires a mode (comment-out, append-missing, all)")
+			}
+			mode := strings.ToLower(args[i+1])
+			switch mode {
+			case danglingFixCommentOut, danglingFixAppendMissing, danglingFixAll:
+				out.Fix = mode
+			default:
+				return nil, fmt.Errorf("invalid fix mode %q: must be 'comment-out', 'append-missing', or 'all'", args[i+1])
+			}
+			i++
+		case "force":
+			out.Force = true
+		default:
+			return nil, fmt.Errorf("unexpected argument: %s", args[i])
+		}
+	}
+	return out, nil
+}
+
+func runLintDangling(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+
+	parsed, err := parseLintDanglingArgs(args)
+	if err != nil {
+		return err
+	}
+	SetAPITarget(parsed.Target)
+	if err := ValidateAPIFlag(); err != nil {
+		return err
+	}
+
+	configDir := viper.GetString("files.config_dir")
+	sites := loadConfiguredSites(configDir, viper.GetStringSlice("files.site_configs"))
+
+	inv, err := config.LoadInventory(config.InventoryPath(globalConfig))
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		inv = &config.InventoryFile{}
+	}
+
+	cacheMgr := GetCacheManager()
+	if cacheMgr == nil {
+		return fmt.Errorf("cache not initialized; run a refresh first")
+	}
+	caches := make(map[string]*vendors.APICache)
+	for _, apiLabel := range GetTargetAPIs() {
+		if cache, err := cacheMgr.GetAPICache(apiLabel); err == nil {
+			caches[apiLabel] = cache
+		}
+	}
+	if len(caches) == 0 {
+		return fmt.Errorf("no cached org inventory; run 'wifimgr refresh' first")
+	}
+
+	report := findDanglingConfig(sites, inv, caches, parsed.SiteName)
+	for _, site := range report.Unchecked {
+		logging.Debugf("lint dangling: site %s not in any cached API; not checked", site)
+	}
+	if len(report.Dangling) == 0 && len(report.Missing) == 0 {
+		fmt.Printf("%s No dangling devices: site configs and inventory.json match the org\n", symbols.SuccessPrefix())
+		return nil
+	}
+	printDanglingReport(report)
+
+	fixDangling := parsed.Fix == danglingFixCommentOut || parsed.Fix == danglingFixAll
+	fixMissing := parsed.Fix == danglingFixAppendMissing || parsed.Fix == danglingFixAll
+	if !fixDangling && !fixMissing {
+		return fmt.Errorf("%d dangling and %d missing device(s); rerun with 'fix comment-out|append-missing|all'",
+			len(report.Dangling), len(report.Missing))
+	}
+	if !parsed.Force {
+		fmt.Printf("%s %s ", i18n.T("lint_dangling.confirm"), i18n.T("prompt.yes_no"))
+		if !confirmPrompt() {
+			return fmt.Errorf("aborted; no files changed")
+		}
+	}
+
+	unresolved := 0
+	if fixDangling {
+		moved, err := disableDanglingEntries(configDir, report.Dangling)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s Moved %d dangling device(s) to devices.%s\n", symbols.SuccessPrefix(), moved, config.DisabledDevicesKey)
+	} else {
+		unresolved += len(report.Dangling)
+	}
+	if fixMissing {
+		added, skipped, err := appendMissingEntries(configDir, report.Missing)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s Added %d missing device(s) to site configs\n", symbols.SuccessPrefix(), added)
+		for _, site := range skipped {
+			fmt.Printf("%s Site %s has no site config; add one (wifimgr init site) and rerun\n", symbols.WarningPrefix(), site)
+		}
+		unresolved += len(skipped)
+	} else {
+		unresolved += len(report.Missing)
+	}
+	if unresolved > 0 {
+		return fmt.Errorf("%d issue(s) left unresolved", unresolved)
+	}
+	return nil
+}
+
+// configuredSite is one site object from a site config file.
+type configuredSite struct {
+	Key     string // key under config.sites
+	File    string // path relative to config_dir
+	API     string
+	Devices config.Devices
+}
+
+// loadConfiguredSites reads every site object from the configured site files.
+// Unreadable files are logged and skipped; lint config reports them properly.
+func loadConfiguredSites(configDir string, files []string) []configuredSite {
+	var out []configuredSite
+	for _, file := range files {
+		cfg, err := config.LoadSiteConfig(configDir, file)
+		if err != nil {
+			logging.Warnf("Skipping site config %s: %v", file, err)
+			continue
+		}
+		for key, obj := range cfg.Config.Sites {
+			out = append(out, configuredSite{Key: key, File: file, API: obj.API, Devices: obj.Devices})
+		}
+	}
+	return out
+}
+
+// danglingEntry is one device found on only one side of the comparison.
+type danglingEntry struct {
+	Site string // site name as written in the site config or inventory.json
+	File string // site config file (empty when the site has none)
+	Key  string // key under config.sites in File
+	Type string
+	MAC  string
+	Name string
+}
+
+// danglingReport collects both directions. Unchecked lists configured sites
+// that no loaded cache knows about.
+type danglingReport struct {
+	Dangling  []danglingEntry // in a site config, not in the org
+	Missing   []danglingEntry // managed and in the org, not in any site config
+	Unchecked []string
+}
+
+// findDanglingConfig compares site configs and managed MACs against the org
+// inventory in caches. A configured site is only checked when a loaded cache
+// knows it (by its pinned API, or by name), so a partial 'target' view never
+// reports another org's devices as dangling.
+func findDanglingConfig(sites []configuredSite, inv *config.InventoryFile, caches map[string]*vendors.APICache, siteFilter string) *danglingReport {
+	org := make(map[string]orgDevice)
+	knownSites := make(map[string]bool)
+	for _, cache := range caches {
+		for name := range cache.SiteIndex.ByName {
+			knownSites[strings.ToLower(name)] = true
+		}
+		for dtype, items := range map[string]map[string]*vendors.InventoryItem{
+			"ap": cache.Inventory.AP, "switch": cache.Inventory.Switch, "gateway": cache.Inventory.Gateway,
+		} {
+			for mac, item := range items {
+				if n := macaddr.NormalizeOrEmpty(mac); n != "" {
+					org[n] = orgDevice{site: cache.SiteIndex.ByID[item.SiteID], dtype: dtype, name: item.Name}
+				}
+			}
+		}
+	}
+
+	report := &danglingReport{}
+	inConfig := make(map[string]bool)
+	siteFiles := make(map[string]configuredSite)
+	for _, site := range sites {
+		siteFiles[strings.ToLower(site.Key)] = site
+		for _, keys := range configuredDeviceMACs(site.Devices) {
+			for _, key := range keys {
+				inConfig[macaddr.NormalizeOrEmpty(key)] = true
+			}
+		}
+	}
+
+	for _, site := range sites {
+		if siteFilter != "" && !strings.EqualFold(site.Key, siteFilter) {
+			continue
+		}
+		_, apiLoaded := caches[site.API]
+		if !apiLoaded && !knownSites[strings.ToLower(site.Key)] {
+			report.Unchecked = append(report.Unchecked, site.Key)
+			continue
+		}
+		for dtype, keys := range configuredDeviceMACs(site.Devices) {
+			for _, key := range keys {
+				if _, ok := org[macaddr.NormalizeOrEmpty(key)]; ok {
+					continue
+				}
+				name := ""
+				switch dtype {
+				case "ap":
+					// APConfig embeds a pointer that stays nil for an empty entry.
+					if ap := site.Devices.APs[key]; ap.APDeviceConfig != nil {
+						name = ap.Name
+					}
+				case "switch":
+					name = site.Devices.Switches[key].Name
+				case "gateway":
+					name = site.Devices.WanEdge[key].Name
+				}
+				report.Dangling = append(report.Dangling, danglingEntry{
+					Site: site.Key, File: site.File, Key: site.Key, Type: dtype, MAC: key, Name: name,
+				})
+			}
+		}
+	}
+
+	for _, siteName := range inv.SiteNames() {
+		if siteFilter != "" && !strings.EqualFold(siteName, siteFilter) {
+			continue
+		}
+		for _, dtype := range []string{"ap", "switch", "gateway"} {
+			for _, raw := range inv.MACsForSite(siteName, dtype) {
+				mac := macaddr.NormalizeOrEmpty(raw)
+				dev, ok := org[mac]
+				if mac == "" || !ok || inConfig[mac] {
+					continue
+				}
+				entry := danglingEntry{Site: siteName, Type: dtype, MAC: mac, Name: dev.name}
+				if site, ok := siteFiles[strings.ToLower(siteName)]; ok {
+					entry.File, entry.Key = site.File, site.Key
+				}
+				report.Missing = append(report.Missing, entry)
+			}
+		}
+	}
+
+	sortDanglingEntries(report.Dangling)
+	sortDanglingEntries(report.Missing)
+	sort.Strings(report.Unchecked)
+	return report
+}
+
+// configuredDeviceMACs returns a site's device keys per type, as written.
+func configuredDeviceMACs(d config.Devices) map[string][]string {
+	out := make(map[string][]string, 3)
+	for key := range d.APs {
+		out["ap"] = append(out["ap"], key)
+	}
+	for key := range d.Switches {
+		out["switch"] = append(out["switch"], key)
+	}
+	for key := range d.WanEdge {
+		out["gateway"] = append(out["gateway"], key)
+	}
+	return out
+}
+
+func sortDanglingEntries(entries []danglingEntry) {
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Site != b.Site {
+			return a.Site < b.Site
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		return a.MAC < b.MAC
+	})
+}
+
+// disableDanglingEntries moves each dangling entry aside in its site config.
+func disableDanglingEntries(configDir string, entries []danglingEntry) (int, error) {
+	type target struct{ file, key, dtype string }
+	groups := make(map[target][]string)
+	for _, e := range entries {
+		t := target{e.File, e.Key, e.Type}
+		groups[t] = append(groups[t], e.MAC)
+	}
+	moved := 0
+	for t, macs := range groups {
+		n, err := config.DisableSiteDevices(filepath.Join(configDir, t.file), t.key, t.dtype, macs)
+		if err != nil {
+			return moved, err
+		}
+		moved += n
+	}
+	return moved, nil
+}
+
+// appendMissingEntries adds stubs for missing managed devices to their site
+// configs. Sites without a site config are returned for the caller to report.
+func appendMissingEntries(configDir string, entries []danglingEntry) (int, []string, error) {
+	type target struct{ file, key, dtype string }
+	groups := make(map[target]map[string]string)
+	skippedSet := make(map[string]bool)
+	for _, e := range entries {
+		if e.File == "" {
+			skippedSet[e.Site] = true
+			continue
+		}
+		t := target{e.File, e.Key, e.Type}
+		if groups[t] == nil {
+			groups[t] = make(map[string]string)
+		}
+		groups[t][e.MAC] = e.Name
+	}
+	added := 0
+	for t, names := range groups {
+		n, err := config.AddSiteDevices(filepath.Join(configDir, t.file), t.key, t.dtype, names)
+		if err != nil {
+			return added, nil, err
+		}
+		added += n
+	}
+	skipped := make([]string, 0, len(skippedSet))
+	for site := range skippedSet {
+		skipped = append(skipped, site)
+	}
+	sort.Strings(skipped)
+	return added, skipped, nil
+}
+
+func printDanglingReport(report *danglingReport) {
+	rows := make([]formatter.GenericTableData, 0, len(report.Dangling)+len(report.Missing))
+	for _, e := range report.Dangling {
+		rows = append(rows, formatter.GenericTableData{
+			"issue": "not in org", "site": e.Site, "type": e.Type, "name": e.Name, "mac": e.MAC, "file": e.File,
+		})
+	}
+	for _, e := range report.Missing {
+		file := e.File
+		if file == "" {
+			file = "(no site config)"
+		}
+		rows = append(rows, formatter.GenericTableData{
+			"issue": "not in site config", "site": e.Site, "type": e.Type, "name": e.Name, "mac": e.MAC, "file": file,
+		})
+	}
+	printer := formatter.NewGenericTablePrinter(formatter.TableConfig{
+		Title:         fmt.Sprintf("Dangling Devices (%d not in org, %d not in site config)", len(report.Dangling), len(report.Missing)),
+		Format:        "table",
+		BoldHeaders:   true,
+		ShowSeparator: true,
+		CommandPath:   "lint.dangling",
+		Columns: []formatter.TableColumn{
+			{Field: "issue", Title: "Issue"},
+			{Field: "site", Title: "Site"},
+			{Field: "type", Title: "Type"},
+			{Field: "name", Title: "Name"},
+			{Field: "mac", Title: "MAC"},
+			{Field: "file", Title: "File"},
+		},
+	}, rows)
+	fmt.Print(printer.Print())
+}
@@ -0,0 +1,179 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/i18n"
+	"github.com/ravinald/wifimgr/internal/logging"
+	"github.com/ravinald/wifimgr/internal/sitelock"
+	"github.com/ravinald/wifimgr/internal/symbols"
+)
+
+// lockCmd groups inspection of the per-site apply locks.
+var lockCmd = &cobra.Command{
+	Use:   "lock",
+	Short: "Inspect or break per-site apply locks",
+	Long: `Inspect or break the per-site locks apply holds while it pushes changes.
+
+Locks are enabled with apply_lock.enabled and stored in the configured backend
+(a shared directory, Redis, or DynamoDB), so operators on different machines
+see each other's applies.`,
+	Example: `  wifimgr lock show US-LAB-01
+  wifimgr lock break US-LAB-01`,
+}
+
+// lockShowCmd represents the "lock show" command
+var lockShowCmd = &cobra.Command{
+	Use:   "show <site-name>",
+	Short: "Show who holds the apply lock on a site",
+	Annotations: map[string]string{
+		cmdutils.AnnotationNeedsConfig: "true",
+	},
+	Args: func(_ *cobra.Command, args []string) error {
+		if cmdutils.ContainsHelp(args) {
+			return nil
+		}
+		if len(args) != 1 {
+			return fmt.Errorf("requires a site name")
+		}
+		return nil
+	},
+	RunE: runLockShow,
+}
+
+// lockBreakCmd represents the "lock break" command
+var lockBreakCmd = &cobra.Command{
+	Use:   "break <site-name> [force]",
+	Short: "Remove a site's apply lock regardless of owner",
+	Long: `Remove the apply lock on a site regardless of who holds it. Use this when
+an apply was killed and its lock has not yet expired. Breaking the lock of an
+apply that is still running lets a second apply interleave with it.
+
+Arguments:
+  site-name   Required. Site whose lock to remove
+  force       Optional. Skip the confirmation prompt`,
+	Annotations: map[string]string{
+		cmdutils.AnnotationNeedsConfig: "true",
+	},
+	Args: func(_ *cobra.Command, args []string) error {
+		if cmdutils.ContainsHelp(args) {
+			return nil
+		}
+		if len(args) < 1 || len(args) > 2 || (len(args) == 2 && strings.ToLower(args[1]) != "force") {
+			return fmt.Errorf("usage: lock break <site-name> [force]")
+		}
+		return nil
+	},
+	RunE: runLockBreak,
+}
+
+func init() {
+	lockCmd.AddCommand(lockShowCmd)
+	lockCmd.AddCommand(lockBreakCmd)
+	rootCmd.AddCommand(lockCmd)
+}
+
+// configuredLocker returns the apply_lock Locker or an error explaining that
+// locking is off.
+func configuredLocker() (*sitelock.Locker, error) {
+	locker, err := sitelock.FromConfig()
+	if err != nil {
+		return nil, err
+	}
+	if locker == nil {
+		return nil, fmt.Errorf("apply locks are not enabled (set apply_lock.enabled)")
+	}
+	return locker, nil
+}
+
+func runLockShow(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+	locker, err := configuredLocker()
+	if err != nil {
+		return err
+	}
+	site := cmdutils.StripQuotes(args[0])
+	rec, err := locker.Status(globalContext, site)
+	if err != nil {
+		return err
+	}
+	if rec == nil {
+		fmt.Printf("%s %s is not locked\n", symbols.SuccessPrefix(), site)
+		return nil
+	}
+
+	state := "held"
+	if rec.Stale(locker.Now()) {
+		state = "stale (the next apply takes it over)"
+	}
+	fmt.Printf("Site:     %s\n", rec.Site)
+	fmt.Printf("Owner:    %s (pid %d)\n", rec.Owner, rec.PID)
+	if rec.Command != "" {
+		fmt.Printf("Command:  %s\n", rec.Command)
+	}
+	fmt.Printf("Acquired: %s\n", rec.AcquiredAt.Local().Format("2006-01-02 15:04:05 MST"))
+	fmt.Printf("Expires:  %s\n", rec.ExpiresAt.Local().Format("2006-01-02 15:04:05 MST"))
+	fmt.Printf("State:    %s\n", state)
+	return nil
+}
+
+func runLockBreak(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+	locker, err := configuredLocker()
+	if err != nil {
+		return err
+	}
+	site := cmdutils.StripQuotes(args[0])
+	force := len(args) == 2
+
+	if !force {
+		rec, err := locker.Status(globalContext, site)
+		if err != nil {
+			return err
+		}
+		if rec == nil {
+			fmt.Printf("%s %s is not locked\n", symbols.SuccessPrefix(), site)
+			return nil
+		}
+		fmt.Printf("%s %s ", i18n.T("lock.confirm_break", site, rec.String()), i18n.T("prompt.yes_no"))
+		if !confirmPrompt() {
+			fmt.Println(i18n.T("lock.cancelled"))
+			return nil
+		}
+	}
+
+	removed, err := locker.Break(globalContext, site)
+	if err != nil {
+		return fmt.Errorf("failed to break lock on %s: %w", site, err)
+	}
+	if removed != nil {
+		logging.Warnf("Broke apply lock on %s held by %s", site, removed.Owner)
+		fmt.Printf("%s Removed lock on %s held by %s\n", symbols.SuccessPrefix(), site, removed.String())
+		return nil
+	}
+	fmt.Printf("%s %s is not locked\n", symbols.SuccessPrefix(), site)
+	return nil
+}
@@ -157,9 +157,13 @@ func InitializeMultiVendor() error {
 		if err := cacheManager.Initialize(); err != nil {
 			return fmt.Errorf("failed to initialize cache manager: %w", err)
 		}
+		migrateLegacyCache(cacheManager)
 
-		// Create and set global cache accessor for cross-package access
-		cacheAccessor = vendors.NewCacheAccessor(cacheManager)
+		// Create and set global cache accessor for cross-package access. It
+		// loads on first use, so commands that never read the cache (and
+		// single-site applies, which narrow it with LoadSite) skip reading
+		// every API's cache at startup.
+		cacheAccessor = vendors.NewLazyCacheAccessor(cacheManager)
 		vendors.SetGlobalCacheAccessor(cacheAccessor)
 
 		logging.Infof("Initialized %d API connections", apiRegistry.Count())
@@ -186,11 +190,28 @@ func createMistClient(config *vendors.APIConfig) (vendors.Client, error) {
 		config.URL,
 		orgID,
 		api.WithConnectTimeout(config.ConnectTimeout),
+		api.WithResultsLimit(config.ResultsLimit),
+		api.WithPageSizes(config.PageSizes),
+		api.WithPagination(config.Pagination),
+		api.WithDeviceCache(newDeviceCache(config.Label)),
 	)
 
 	return mist.NewAdapter(legacyClient, orgID), nil
 }
 
+// newDeviceCache builds the in-memory device cache for a Mist client, tied to
+// the API's file cache so a refresh (here or in another process) invalidates
+// it. The cache manager may be created after the client, so it is looked up
+// on each check.
+func newDeviceCache(apiLabel string) *api.DeviceCache {
+	return api.NewDeviceCache(api.WithSourceGeneration(func() uint64 {
+		if cm := GetCacheManager(); cm != nil {
+			return cm.Generation(apiLabel)
+		}
+		return 0
+	}))
+}
+
 // createMerakiClient creates a Meraki vendor client from config.
 func createMerakiClient(config *vendors.APIConfig) (vendors.Client, error) {
 	apiKey := config.Credentials["api_key"]
@@ -85,7 +85,7 @@ func ResolveAPIForSite(siteName string, siteConfig *config.SiteConfig) (string,
 	// If not in config, try to find in cache
 	if expectedAPI == "" && cacheMgr != nil {
 		for _, label := range allLabels {
-			cache, err := cacheMgr.GetAPICache(label)
+			cache, err := cacheMgr.GetCoreCache(label)
 			if err != nil {
 				continue
 			}
@@ -138,7 +138,7 @@ func ValidateMultiVendorApply(_ context.Context, siteName string, siteConfig *co
 	// Verify site exists in the API's cache
 	cacheMgr := GetCacheManager()
 	if cacheMgr != nil {
-		cache, err := cacheMgr.GetAPICache(apiLabel)
+		cache, err := cacheMgr.GetCoreCache(apiLabel)
 		if err == nil {
 			if _, ok := cache.SiteIndex.ByName[siteName]; !ok {
 				logging.Warnf("Site '%s' not found in %s cache - may need to refresh cache", siteName, apiLabel)
@@ -15,7 +15,9 @@ import (
 	"golang.org/x/term"
 
 	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/common"
 	"github.com/ravinald/wifimgr/internal/formatter"
+	"github.com/ravinald/wifimgr/internal/i18n"
 	"github.com/ravinald/wifimgr/internal/vendors"
 )
 
@@ -142,9 +144,9 @@ func searchWirelessMultiVendor(ctx context.Context, searchText, siteID, format s
 
 			vendorName, _ := registry.GetVendor(apiLabel)
 			data := formatter.GenericTableData{
-				"mac":           client.MAC,
+				"mac":           common.AnonymizeMAC(client.MAC),
 				"ip":            client.IP,
-				"hostname":      client.Hostname,
+				"hostname":      common.AnonymizeHostname(client.Hostname),
 				"ssid":          client.SSID,
 				"ap_name":       client.APName,
 				"ap_mac":        client.APMAC,
@@ -403,9 +405,9 @@ func searchWiredMultiVendor(ctx context.Context, searchText, siteID, format stri
 			enrichWiredClientFromCache(wiredClient, apiCache)
 			vendorName, _ := registry.GetVendor(apiLabel)
 			data := formatter.GenericTableData{
-				"mac":         wiredClient.MAC,
+				"mac":         common.AnonymizeMAC(wiredClient.MAC),
 				"ip":          wiredClient.IP,
-				"hostname":    wiredClient.Hostname,
+				"hostname":    common.AnonymizeHostname(wiredClient.Hostname),
 				"switch_name": wiredClient.SwitchName,
 				"switch_mac":  wiredClient.SwitchMAC,
 				"port":        wiredClient.PortID,
@@ -694,6 +696,5 @@ func confirmPrompt() bool {
 	if err != nil {
 		return false
 	}
-	input = strings.TrimSpace(strings.ToLower(input))
-	return input == "y" || input == "yes"
+	return i18n.IsYes(input)
 }
@@ -11,7 +11,9 @@ import (
 
 	"github.com/ravinald/wifimgr/internal/cmdutils"
 	"github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/filterexpr"
 	"github.com/ravinald/wifimgr/internal/formatter"
+	"github.com/ravinald/wifimgr/internal/lifecycle"
 	"github.com/ravinald/wifimgr/internal/logging"
 	"github.com/ravinald/wifimgr/internal/vendors"
 )
@@ -26,7 +28,7 @@ const (
 // showDevicesMultiVendor shows devices of a specific type from one or more APIs.
 // deviceType should be "ap", "switch", or "gateway". Devices armed in
 // inventory.json carry an 'M' flag; those whose intent has drifted carry '*'.
-func showDevicesMultiVendor(_ context.Context, deviceType string, parsed *cmdutils.ParsedShowArgs) error {
+func showDevicesMultiVendor(ctx context.Context, deviceType string, parsed *cmdutils.ParsedShowArgs) error {
 	// Validate target API if provided
 	if err := ValidateAPIFlag(); err != nil {
 		return err
@@ -50,8 +52,20 @@ func showDevicesMultiVendor(_ context.Context, deviceType string, parsed *cmduti
 		return err
 	}
 	deviceIntents := loadDeviceIntentsFromSiteConfigs()
+	lifecycleDB, err := lifecycle.FromConfig()
+	if err != nil {
+		logging.Warnf("Lifecycle dates unavailable: %v", err)
+	}
+	where, err := compileShowFilter(parsed.Where)
+	if err != nil {
+		return err
+	}
 	hasDrift := false
 	usedManaged := false
+	var uptimes *uptimeLookup
+	if parsed.Uptime {
+		uptimes = newUptimeLookup(ctx)
+	}
 
 	// Collect devices from all target APIs
 	var allDevices []formatter.GenericTableData
@@ -141,6 +155,11 @@ func showDevicesMultiVendor(_ context.Context, deviceType string, parsed *cmduti
 				"site_id": item.SiteID,
 				"api":     apiLabel,
 			}
+			if rec, ok := lifecycleDB.Lookup(item.Model, item.Serial); ok {
+				data["end_of_sale"] = rec.EndOfSale.String()
+				data["end_of_support"] = rec.EndOfSupport.String()
+				data["warranty_end"] = rec.WarrantyEnd.String()
+			}
 
 			// Look up status from DeviceStatus section
 			if status, ok := cache.DeviceStatus[normalizedMAC]; ok {
@@ -160,6 +179,26 @@ func showDevicesMultiVendor(_ context.Context, deviceType string, parsed *cmduti
 				data["site_name"] = item.SiteID
 			}
 
+			// Live uptime columns, fetched per site only when asked for.
+			if uptimes != nil {
+				data["uptime"] = ""
+				data["last_reboot"] = ""
+				if up, ok := uptimes.get(apiLabel, item.SiteID, normalizedMAC); ok {
+					if up.Uptime > 0 {
+						data["uptime"] = formatDuration(up.Uptime)
+						data["uptime_seconds"] = int64(up.Uptime.Seconds())
+					}
+					if ev := up.LastReboot; ev != nil {
+						data["last_reboot"] = ev.Timestamp.Local().Format("2006-01-02 15:04")
+						data["last_reboot_reason"] = ev.Reason
+					}
+				}
+			}
+
+			if !matchesShowFilter(where, data) {
+				continue
+			}
+
 			allDevices = append(allDevices, data)
 			apiCounts[apiLabel]++
 		}
@@ -247,7 +286,26 @@ func showDevicesMultiVendor(_ context.Context, deviceType string, parsed *cmduti
 		formatter.TableColumn{Field: "mac", Title: "MAC", MaxWidth: 0},
 		formatter.TableColumn{Field: "serial", Title: "Serial", MaxWidth: 0},
 		formatter.TableColumn{Field: "model", Title: "Model", MaxWidth: 0},
+	)
+	// Lifecycle columns appear only when files.lifecycle is configured.
+	if lifecycleDB != nil {
+		defaultColumns = append(defaultColumns,
+			formatter.TableColumn{Field: "end_of_sale", Title: "End of Sale", MaxWidth: 0},
+			formatter.TableColumn{Field: "end_of_support", Title: "End of Support", MaxWidth: 0},
+		)
+	}
+	defaultColumns = append(defaultColumns,
 		formatter.TableColumn{Field: "status", Title: "Status", MaxWidth: 0, IsStatusField: true},
+	)
+	// Uptime columns appear only with the uptime keyword.
+	if uptimes != nil {
+		defaultColumns = append(defaultColumns,
+			formatter.TableColumn{Field: "uptime", Title: "Uptime", MaxWidth: 0},
+			formatter.TableColumn{Field: "last_reboot", Title: "Last Reboot", MaxWidth: 0},
+			formatter.TableColumn{Field: "last_reboot_reason", Title: "Reboot Reason", MaxWidth: 40},
+		)
+	}
+	defaultColumns = append(defaultColumns,
 		formatter.TableColumn{Field: "site_name", Title: "Site", MaxWidth: 0},
 	)
 
@@ -300,6 +358,31 @@ func showDevicesMultiVendor(_ context.Context, deviceType string, parsed *cmduti
 	return nil
 }
 
+// compileShowFilter parses the "filter" keyword value, resolving @name against
+// the saved filters in config. An empty value yields a nil (match-all) filter.
+func compileShowFilter(arg string) (*filterexpr.Expr, error) {
+	if arg == "" {
+		return nil, nil
+	}
+	return filterexpr.Compile(arg)
+}
+
+// matchesShowFilter evaluates where against a display row, ignoring the
+// BOLD_TEXT: marker so filters see the plain name.
+func matchesShowFilter(where *filterexpr.Expr, data formatter.GenericTableData) bool {
+	if where == nil {
+		return true
+	}
+	row := make(map[string]any, len(data))
+	for k, v := range data {
+		if str, ok := v.(string); ok {
+			v = strings.TrimPrefix(str, "BOLD_TEXT:")
+		}
+		row[k] = v
+	}
+	return where.Match(row)
+}
+
 // showSitesMultiVendor shows sites from one or more APIs.
 func showSitesMultiVendor(_ context.Context, parsed *cmdutils.ParsedShowArgs) error {
 	// Validate target API if provided
@@ -340,6 +423,11 @@ func showSitesMultiVendor(_ context.Context, parsed *cmdutils.ParsedShowArgs) er
 		return err
 	}
 
+	where, err := compileShowFilter(parsed.Where)
+	if err != nil {
+		return err
+	}
+
 	// Collect sites from all target APIs (list view)
 	var allSites []formatter.GenericTableData
 	apiCounts := make(map[string]int)
@@ -434,6 +522,10 @@ func showSitesMultiVendor(_ context.Context, parsed *cmdutils.ParsedShowArgs) er
 				"api":            apiLabel,
 			}
 
+			if !matchesShowFilter(where, data) {
+				continue
+			}
+
 			allSites = append(allSites, data)
 			apiCounts[apiLabel]++
 		}
@@ -654,26 +746,33 @@ func findExactSiteMatches(cacheMgr *vendors.CacheManager, targetAPIs []string, s
 // showSiteDetailMultiVendor shows detailed information for a site across vendors
 func showSiteDetailMultiVendor(matches []siteMatch, parsed *cmdutils.ParsedShowArgs) error {
 	siteName := matches[0].site.Name
+	contact := siteContact(siteName)
+	if contact == nil {
+		contact = &config.SiteContact{}
+	}
 
 	// Handle JSON/CSV format
 	if parsed.Format == "json" || parsed.Format == "csv" {
 		var tableData []formatter.GenericTableData
 		for _, m := range matches {
 			data := formatter.GenericTableData{
-				"name":         m.site.Name,
-				"id":           m.site.ID,
-				"timezone":     m.site.Timezone,
-				"country_code": m.site.CountryCode,
-				"address":      m.site.Address,
-				"latitude":     m.site.Latitude,
-				"longitude":    m.site.Longitude,
-				"notes":        m.site.Notes,
-				"ap_count":     m.apCount,
-				"switch_count": m.switchCount,
-				"gw_count":     m.gwCount,
-				"total":        m.apCount + m.switchCount + m.gwCount,
-				"vendor":       m.vendor,
-				"api":          m.apiLabel,
+				"name":               m.site.Name,
+				"id":                 m.site.ID,
+				"timezone":           m.site.Timezone,
+				"country_code":       m.site.CountryCode,
+				"address":            m.site.Address,
+				"latitude":           m.site.Latitude,
+				"longitude":          m.site.Longitude,
+				"notes":              m.site.Notes,
+				"contact_owner":      contact.Owner,
+				"contact_phone":      contact.Phone,
+				"contact_escalation": contact.Escalation,
+				"ap_count":           m.apCount,
+				"switch_count":       m.switchCount,
+				"gw_count":           m.gwCount,
+				"total":              m.apCount + m.switchCount + m.gwCount,
+				"vendor":             m.vendor,
+				"api":                m.apiLabel,
 			}
 			tableData = append(tableData, data)
 		}
@@ -687,6 +786,9 @@ func showSiteDetailMultiVendor(matches []siteMatch, parsed *cmdutils.ParsedShowA
 			{Field: "latitude", Title: "Latitude"},
 			{Field: "longitude", Title: "Longitude"},
 			{Field: "notes", Title: "Notes"},
+			{Field: "contact_owner", Title: "Owner"},
+			{Field: "contact_phone", Title: "Phone"},
+			{Field: "contact_escalation", Title: "Escalation"},
 			{Field: "ap_count", Title: "APs"},
 			{Field: "switch_count", Title: "Switches"},
 			{Field: "gw_count", Title: "Gateways"},
@@ -737,6 +839,15 @@ func showSiteDetailMultiVendor(matches []siteMatch, parsed *cmdutils.ParsedShowA
 		if m.site.Notes != "" {
 			fmt.Printf("  Notes:        %s\n", m.site.Notes)
 		}
+		if contact.Owner != "" {
+			fmt.Printf("  Owner:        %s\n", contact.Owner)
+		}
+		if contact.Phone != "" {
+			fmt.Printf("  Phone:        %s\n", contact.Phone)
+		}
+		if contact.Escalation != "" {
+			fmt.Printf("  Escalation:   %s\n", contact.Escalation)
+		}
 
 		// Device counts
 		total := m.apCount + m.switchCount + m.gwCount
@@ -790,7 +901,7 @@ func getDeviceTypeName(deviceType string) string {
 // (the operator must migrate); a missing/unreadable file yields an empty set —
 // managed-first means nothing is armed until the operator says so.
 func loadManagedMACSet(deviceTypes []string) (map[string]bool, error) {
-	inv, err := config.LoadInventoryFile(config.InventoryPath(nil))
+	inv, err := config.LoadInventory(config.InventoryPath(nil))
 	if err != nil {
 		if errors.Is(err, config.ErrLegacyInventorySchema) {
 			return nil, err
@@ -811,7 +922,7 @@ func loadManagedMACSet(deviceTypes []string) (map[string]bool, error) {
 // inventory file — the sites that contain at least one armed device. Same
 // fatal/empty semantics as loadManagedMACSet.
 func loadManagedSiteSet() (map[string]bool, error) {
-	inv, err := config.LoadInventoryFile(config.InventoryPath(nil))
+	inv, err := config.LoadInventory(config.InventoryPath(nil))
 	if err != nil {
 		if errors.Is(err, config.ErrLegacyInventorySchema) {
 			return nil, err
@@ -929,7 +1040,8 @@ func formatDuration(d time.Duration) string {
 
 // deviceIntent holds the intended configuration for a device from local site configs.
 type deviceIntent struct {
-	Name string
+	Name      string
+	Placement vendors.APPlacement // floor-plan placement; APs only
 }
 
 // loadDeviceIntentsFromSiteConfigs loads device intents from local site configuration files.
@@ -956,7 +1068,7 @@ func loadDeviceIntentsFromSiteConfigs() map[string]deviceIntent {
 			// Process APs
 			for mac, ap := range siteObj.Devices.APs {
 				normalizedMAC := vendors.NormalizeMAC(mac)
-				intents[normalizedMAC] = deviceIntent{Name: ap.Name}
+				intents[normalizedMAC] = deviceIntent{Name: ap.Name, Placement: ap.Placement()}
 			}
 			// Process Switches
 			for mac, sw := range siteObj.Devices.Switches {
@@ -979,7 +1091,8 @@ func loadDeviceIntentsFromSiteConfigs() map[string]deviceIntent {
 }
 
 // hasConfigDrift checks if a device has configuration drift between cache and intent.
-// Currently checks the name field; can be extended to check other fields.
+// It checks the name and, for APs, the floor-plan placement; a map given by
+// name in intent is not resolved here, so only its x and y are compared.
 func hasConfigDrift(cache *vendors.APICache, normalizedMAC, deviceType string, intent deviceIntent) bool {
 	// Get the device config from cache
 	var cacheName string
@@ -987,6 +1100,9 @@ func hasConfigDrift(cache *vendors.APICache, normalizedMAC, deviceType string, i
 	case "ap":
 		if cfg, ok := cache.Configs.AP[normalizedMAC]; ok && cfg != nil {
 			cacheName = cfg.Name
+			if len(vendors.PlacementDrift(intent.Placement, vendors.PlacementFromConfig(cfg.Config))) > 0 {
+				return true
+			}
 		}
 	case "switch":
 		if cfg, ok := cache.Configs.Switch[normalizedMAC]; ok && cfg != nil {
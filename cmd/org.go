@@ -0,0 +1,35 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// orgCmd groups operations that act on a whole organization (API label)
+// rather than a single site.
+var orgCmd = &cobra.Command{
+	Use:   "org",
+	Short: "Organization-wide operations",
+	Long: `Operations that act on a whole organization (one configured API label)
+rather than a single site.`,
+	Example: `  # Build a lab copy of the prod org's sites and WLAN templates
+  wifimgr org clone from mist-prod to mist-lab anonymize save`,
+}
+
+func init() {
+	rootCmd.AddCommand(orgCmd)
+}
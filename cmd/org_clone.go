@@ -0,0 +1,391 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/logging"
+	"github.com/ravinald/wifimgr/internal/symbols"
+	"github.com/ravinald/wifimgr/internal/vendors"
+	"github.com/ravinald/wifimgr/internal/xdg"
+)
+
+// orgCloneCmd represents the "org clone" command
+var orgCloneCmd = &cobra.Command{
+	Use:   "clone from <api-label> to <api-label> [anonymize] [decrypt] [save] [file <filename>]",
+	Short: "Copy sites and WLAN templates from one org into another (no devices or device profiles)",
+	Long: `Build a staging copy of an organization from the API cache.
+
+Reads the source API's cached sites, site WLANs and org WLAN templates, and
+emits a single import envelope whose sites are re-pointed at the destination
+API label. Devices are never copied — a lab org gets the structure, not the
+hardware. Cloned site WLANs are assigned site-wide, since the per-AP placement
+they had in the source org has no devices to attach to.
+
+Device profiles are not cloned. The cache holds only their names, not their
+settings, so the command lists the source org's device profiles in a warning;
+recreate them on the destination before assigning devices to them.
+
+Nothing is written to either API. Review the envelope, add it to files.imports,
+then 'apply site <name>' against the destination creates the sites and WLANs.
+
+Secrets:
+  default     PSKs/RADIUS secrets keep their stored enc: value (clear-text
+              values in the cache are masked), same as 'import api site'
+  anonymize   Every PSK and RADIUS secret is replaced with a freshly generated
+              random value, and site address, notes and coordinates are blanked
+  decrypt     Emit decrypted plaintext secrets (ignored with anonymize)
+
+Arguments:
+  from <label>   Required. Source API label (the org to copy)
+  to <label>     Required. Destination API label the copy is addressed to
+  anonymize      Optional. Regenerate secrets and strip site location details
+  save           Optional. Write the envelope (default: print to STDOUT)
+  file <name>    Optional. Output filename (relative to config_dir or absolute)
+
+Output Location:
+  With 'save' (no file): <config_dir>/import/clone_<from>_to_<to>.json`,
+	Example: `  wifimgr org clone from mist-prod to mist-lab
+  wifimgr org clone from mist-prod to mist-lab anonymize save
+  wifimgr org clone from mist-prod to mist-lab anonymize save file lab/clone.json`,
+	Args: func(_ *cobra.Command, args []string) error {
+		if cmdutils.ContainsHelp(args) {
+			return nil
+		}
+		if len(args) < 4 {
+			return fmt.Errorf("requires 'from <api-label> to <api-label>'")
+		}
+		return nil
+	},
+	RunE: runOrgClone,
+}
+
+func init() {
+	orgCmd.AddCommand(orgCloneCmd)
+}
+
+// orgCloneArgs holds parsed arguments for org clone.
+type orgCloneArgs struct {
+	from      string
+	to        string
+	anonymize bool
+	cmdutils.ImportOutputArgs
+}
+
+// parseOrgCloneArgs parses positional arguments for org clone.
+func parseOrgCloneArgs(args []string) (*orgCloneArgs, error) {
+	result := &orgCloneArgs{}
+	for i := 0; i < len(args); i++ {
+		if matched, last, err := result.Consume(args, i); err != nil {
+			return nil, err
+		} else if matched {
+			i = last
+			continue
+		}
+		switch strings.ToLower(args[i]) {
+		case "from":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("'from' requires an API label")
+			}
+			result.from = cmdutils.StripQuotes(args[i+1])
+			i++
+		case "to":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("'to' requires an API label")
+			}
+			result.to = cmdutils.StripQuotes(args[i+1])
+			i++
+		case "anonymize":
+			result.anonymize = true
+		default:
+			return nil, fmt.Errorf("unknown argument: %s", args[i])
+		}
+	}
+
+	if result.from == "" || result.to == "" {
+		return nil, fmt.Errorf("both 'from <api-label>' and 'to <api-label>' are required")
+	}
+	if result.from == result.to {
+		return nil, fmt.Errorf("source and destination are the same API (%s)", result.from)
+	}
+	if err := result.Validate(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func runOrgClone(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+
+	parsed, err := parseOrgCloneArgs(args)
+	if err != nil {
+		return err
+	}
+
+	registry := GetAPIRegistry()
+	if registry == nil {
+		return fmt.Errorf("API registry not initialized")
+	}
+	for _, label := range []string{parsed.from, parsed.to} {
+		if !registry.HasAPI(label) {
+			return FormatAPINotFoundError(label)
+		}
+	}
+
+	cacheAccessor, err := cmdutils.GetCacheAccessor()
+	if err != nil {
+		return fmt.Errorf("failed to get cache accessor: %w", err)
+	}
+
+	reveal := secretReveal{}
+	if !parsed.anonymize {
+		if reveal, err = resolveSecretReveal(parsed.Decrypt); err != nil {
+			return err
+		}
+	}
+
+	env, profiles, err := buildOrgCloneEnvelope(cacheAccessor, parsed.from, parsed.to, reveal)
+	if err != nil {
+		return err
+	}
+	if len(profiles) > 0 {
+		fmt.Fprintf(os.Stderr, "%s %d device profile(s) not cloned; recreate them on %s before assigning devices: %s\n",
+			symbols.WarningPrefix(), len(profiles), parsed.to, strings.Join(profiles, ", "))
+	}
+	if parsed.anonymize {
+		anonymizeEnvelope(env)
+	}
+
+	if !parsed.SaveMode {
+		jsonData, err := json.MarshalIndent(env, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal data: %w", err)
+		}
+		fmt.Println(string(jsonData))
+		return nil
+	}
+
+	configDir := viper.GetString("files.config_dir")
+	outputPath := resolveCloneOutputPath(parsed.OutputFile, configDir, parsed.from, parsed.to)
+	if _, exists := loadExistingImport(outputPath); exists {
+		if !confirmOverwrite(outputPath) {
+			fmt.Println("Clone cancelled")
+			return nil
+		}
+	}
+	if err := writeImportFile(outputPath, env); err != nil {
+		return fmt.Errorf("failed to write clone file: %w", err)
+	}
+
+	siteCount := 0
+	if env.Config != nil {
+		siteCount = len(env.Config.Sites)
+	}
+	cmdutils.Noticef("Cloned %d site(s) from %s, addressed to %s", siteCount, parsed.from, parsed.to)
+	if len(profiles) > 0 {
+		cmdutils.Noticef("Device profiles were not cloned (%d listed above)", len(profiles))
+	}
+	printActivationHint(outputPath, configDir)
+	return nil
+}
+
+// buildOrgCloneEnvelope assembles the clone: every cached site of the source
+// API (site_config + site WLANs, no devices) re-pointed at the destination,
+// plus the source org's WLAN templates. It also returns the source org's device
+// profiles, which are left out and must be recreated by hand.
+func buildOrgCloneEnvelope(cacheAccessor *vendors.CacheAccessor, from, to string, reveal secretReveal) (*importEnvelope, []string, error) {
+	env := &importEnvelope{
+		Version: 1,
+		Source: &importSourceExport{
+			API:        from,
+			Kind:       "org-clone",
+			ImportedAt: time.Now().UTC(),
+		},
+		Config:    &siteConfigEnvelope{Sites: make(map[string]*siteObjExport)},
+		Templates: &templatesEnvelope{},
+	}
+
+	var sites []*vendors.SiteInfo
+	for _, site := range cacheAccessor.GetAllSites() {
+		if site.SourceAPI == from {
+			sites = append(sites, site)
+		}
+	}
+	if len(sites) == 0 {
+		return nil, nil, fmt.Errorf("no cached sites for API %q (run 'wifimgr refresh all' first)", from)
+	}
+	sort.Slice(sites, func(i, j int) bool { return sites[i].Name < sites[j].Name })
+
+	for _, site := range sites {
+		siteEnv, err := buildSiteExportData(cacheAccessor, site, ScopeWLANs, reveal)
+		if err != nil {
+			return nil, nil, fmt.Errorf("site %s: %w", site.Name, err)
+		}
+
+		body := cloneSiteBody(site, to)
+		if siteEnv != nil && siteEnv.Templates != nil {
+			for label, tmpl := range siteEnv.Templates.WLAN {
+				if env.Templates.WLAN == nil {
+					env.Templates.WLAN = make(map[string]map[string]any)
+				}
+				env.Templates.WLAN[label] = tmpl
+				body.WLAN = append(body.WLAN, label)
+			}
+			sort.Strings(body.WLAN)
+			body.Profiles.WLAN = append([]string(nil), body.WLAN...)
+		}
+		env.Config.Sites[site.Name] = body
+	}
+
+	for label, tmpl := range synthesizeOrgWLANTemplates(collectOrgWLANs(cacheAccessor, from), reveal) {
+		if env.Templates.WLAN == nil {
+			env.Templates.WLAN = make(map[string]map[string]any)
+		}
+		if _, taken := env.Templates.WLAN[label]; taken {
+			logging.Warnf("[org clone] org WLAN template %q collides with a site WLAN label; keeping the site copy", label)
+			continue
+		}
+		env.Templates.WLAN[label] = tmpl
+	}
+
+	// The cache holds device profiles by name only, so there is no body to
+	// clone; report them instead of emitting templates that can't be applied.
+	var profiles []string
+	for _, p := range cacheAccessor.GetAllDeviceProfiles() {
+		if p.SourceAPI == from && p.Name != "" {
+			profiles = append(profiles, fmt.Sprintf("%s (%s)", p.Name, p.Type))
+		}
+	}
+	sort.Strings(profiles)
+
+	if isTemplatesEmpty(env.Templates) {
+		env.Templates = nil
+	}
+	return env, profiles, nil
+}
+
+// cloneSiteBody renders a site's structural config (no devices) addressed to
+// the destination API.
+func cloneSiteBody(site *vendors.SiteInfo, to string) *siteObjExport {
+	body := &siteObjExport{
+		API: to,
+		SiteConfig: map[string]any{
+			"name":         site.Name,
+			"address":      site.Address,
+			"country_code": site.CountryCode,
+			"timezone":     site.Timezone,
+			"notes":        site.Notes,
+		},
+	}
+	if site.Latitude != 0 || site.Longitude != 0 {
+		body.SiteConfig["latlng"] = map[string]float64{
+			"lat": site.Latitude,
+			"lng": site.Longitude,
+		}
+	}
+	return body
+}
+
+// cloneSecretKeys are the leaf keys whose string values anonymize regenerates.
+var cloneSecretKeys = map[string]bool{
+	"psk":           true,
+	"secret":        true,
+	"passphrase":    true,
+	"password":      true,
+	"shared_secret": true,
+}
+
+// anonymizeEnvelope replaces every secret in the clone with a random value and
+// strips location details from site bodies, so a lab org built from it shares
+// nothing sensitive with production.
+func anonymizeEnvelope(env *importEnvelope) {
+	if env.Templates != nil {
+		for _, tmpl := range env.Templates.WLAN {
+			anonymizeValue(tmpl)
+		}
+	}
+	if env.Config == nil {
+		return
+	}
+	for _, body := range env.Config.Sites {
+		body.SiteConfig["address"] = ""
+		body.SiteConfig["notes"] = ""
+		delete(body.SiteConfig, "latlng")
+	}
+}
+
+// anonymizeValue walks a decoded JSON value in place, replacing non-empty
+// string secrets.
+func anonymizeValue(v any) {
+	switch val := v.(type) {
+	case map[string]any:
+		for k, child := range val {
+			if s, ok := child.(string); ok && s != "" && cloneSecretKeys[strings.ToLower(k)] {
+				val[k] = randomSecret()
+				continue
+			}
+			anonymizeValue(child)
+		}
+	case []any:
+		for _, child := range val {
+			anonymizeValue(child)
+		}
+	case []map[string]any:
+		for _, child := range val {
+			anonymizeValue(child)
+		}
+	}
+}
+
+// randomSecret returns a 20-character value that satisfies WPA2 PSK length
+// rules and is unique per call.
+func randomSecret() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return maskedSecret
+	}
+	return "lab-" + hex.EncodeToString(buf)
+}
+
+// resolveCloneOutputPath mirrors resolveImportOutputPath for clone files.
+func resolveCloneOutputPath(outputFile, configDir, from, to string) string {
+	if outputFile != "" {
+		if filepath.IsAbs(outputFile) {
+			return outputFile
+		}
+		return filepath.Join(configDir, outputFile)
+	}
+	baseDir := configDir
+	if baseDir == "" {
+		baseDir = xdg.GetConfigDir()
+	}
+	return filepath.Join(baseDir, "import", fmt.Sprintf("clone_%s_to_%s.json", from, to))
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+func TestParseOrgCloneArgs(t *testing.T) {
+	got, err := parseOrgCloneArgs([]string{"from", "mist-prod", "to", "mist-lab", "anonymize", "save", "file", "lab.json"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.from != "mist-prod" || got.to != "mist-lab" {
+		t.Errorf("from/to = %q/%q", got.from, got.to)
+	}
+	if !got.anonymize || !got.SaveMode || got.OutputFile != "lab.json" {
+		t.Errorf("flags not parsed: %+v", got)
+	}
+
+	errCases := [][]string{
+		{"from", "mist-prod"},
+		{"from", "mist-prod", "to", "mist-prod"},
+		{"from", "mist-prod", "to", "mist-lab", "bogus"},
+		{"from", "mist-prod", "to"},
+	}
+	for _, args := range errCases {
+		if _, err := parseOrgCloneArgs(args); err == nil {
+			t.Errorf("parseOrgCloneArgs(%v) expected error", args)
+		}
+	}
+}
+
+func TestAnonymizeEnvelope(t *testing.T) {
+	env := &importEnvelope{
+		Config: &siteConfigEnvelope{Sites: map[string]*siteObjExport{
+			"HQ": cloneSiteBodyForTest(),
+		}},
+		Templates: &templatesEnvelope{WLAN: map[string]map[string]any{
+			"corp": {
+				"ssid": "Corp",
+				"auth": map[string]any{"type": "psk", "psk": "enc:abcdef"},
+				"auth_servers": []any{
+					map[string]any{"host": "10.0.0.1", "secret": "radius-secret"},
+				},
+			},
+			"guest": {"ssid": "Guest", "auth": map[string]any{"type": "psk", "psk": "enc:abcdef"}},
+		}},
+	}
+
+	anonymizeEnvelope(env)
+
+	corpPSK := env.Templates.WLAN["corp"]["auth"].(map[string]any)["psk"].(string)
+	guestPSK := env.Templates.WLAN["guest"]["auth"].(map[string]any)["psk"].(string)
+	if !strings.HasPrefix(corpPSK, "lab-") || len(corpPSK) < 8 {
+		t.Errorf("psk not regenerated: %q", corpPSK)
+	}
+	if corpPSK == guestPSK {
+		t.Error("identical source secrets should get distinct placeholders")
+	}
+	secret := env.Templates.WLAN["corp"]["auth_servers"].([]any)[0].(map[string]any)["secret"]
+	if secret == "radius-secret" {
+		t.Error("RADIUS secret was not anonymized")
+	}
+	if env.Templates.WLAN["corp"]["ssid"] != "Corp" {
+		t.Error("non-secret fields must be left alone")
+	}
+
+	body := env.Config.Sites["HQ"]
+	if body.SiteConfig["address"] != "" || body.SiteConfig["notes"] != "" {
+		t.Errorf("site location details not stripped: %v", body.SiteConfig)
+	}
+	if _, ok := body.SiteConfig["latlng"]; ok {
+		t.Error("latlng should be removed")
+	}
+	if body.SiteConfig["name"] != "HQ" || body.API != "mist-lab" {
+		t.Errorf("site identity changed: %+v", body)
+	}
+}
+
+func cloneSiteBodyForTest() *siteObjExport {
+	return &siteObjExport{
+		API: "mist-lab",
+		SiteConfig: map[string]any{
+			"name":    "HQ",
+			"address": "1 Main St",
+			"notes":   "badge at door",
+			"latlng":  map[string]float64{"lat": 1, "lng": 2},
+		},
+	}
+}
+
+func TestBuildOrgCloneEnvelopeLeavesOutProfiles(t *testing.T) {
+	registry := vendors.NewAPIClientRegistry()
+	registry.RegisterClient("mist-prod", vendors.NewMockClient("mist", "org-1"), nil)
+	cm := vendors.NewCacheManager(t.TempDir(), registry)
+	if err := cm.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	cache := vendors.NewAPICache("mist-prod", "mist", "org-1")
+	cache.Sites.Info = []vendors.SiteInfo{{ID: "site-1", Name: "US-LAB-01"}}
+	cache.Profiles.Devices = []vendors.DeviceProfile{{ID: "p1", Name: "Lobby APs", Type: "ap"}}
+	if err := cm.SaveAPICache(cache); err != nil {
+		t.Fatal(err)
+	}
+	if err := cm.RebuildIndex(); err != nil {
+		t.Fatal(err)
+	}
+
+	env, profiles, err := buildOrgCloneEnvelope(vendors.NewCacheAccessor(cm), "mist-prod", "mist-lab", secretReveal{})
+	if err != nil {
+		t.Fatalf("buildOrgCloneEnvelope: %v", err)
+	}
+	if len(profiles) != 1 || profiles[0] != "Lobby APs (ap)" {
+		t.Errorf("profiles = %v, want [Lobby APs (ap)]", profiles)
+	}
+	if env.Templates != nil && len(env.Templates.Device) != 0 {
+		t.Errorf("device templates = %v, want none", env.Templates.Device)
+	}
+	if site := env.Config.Sites["US-LAB-01"]; site == nil || site.API != "mist-lab" {
+		t.Errorf("sites = %+v, want US-LAB-01 addressed to mist-lab", env.Config.Sites)
+	}
+}
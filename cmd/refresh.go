@@ -7,6 +7,7 @@ you may not use this file except in compliance with the License.
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strings"
@@ -16,6 +17,7 @@ import (
 
 	"github.com/ravinald/wifimgr/internal/cmdutils"
 	"github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/integrations"
 	"github.com/ravinald/wifimgr/internal/logging"
 	"github.com/ravinald/wifimgr/internal/refreshui"
 	"github.com/ravinald/wifimgr/internal/vendors"
@@ -95,7 +97,7 @@ func runRefresh(cmd *cobra.Command, args []string) error {
 // fatal — the operator must migrate. A missing/unreadable file yields an empty
 // set with a warning: managed-first means nothing is armed until you say so.
 func managedMACs(sites []string) (map[string]bool, error) {
-	inv, err := config.LoadInventoryFile(config.InventoryPath(nil))
+	inv, err := config.LoadInventory(config.InventoryPath(nil))
 	if err != nil {
 		if errors.Is(err, config.ErrLegacyInventorySchema) {
 			return nil, err
@@ -147,6 +149,7 @@ func runRefreshAllSites(scope string) error {
 			return fmt.Errorf("failed to refresh %s: %s", apiFlag, formatRefreshError(err))
 		}
 		fmt.Printf("Successfully refreshed %s\n", apiFlag)
+		runPostRefreshHooks(ctx, cacheMgr, []string{apiFlag})
 	} else {
 		fmt.Printf("Refreshing cache for %d APIs...\n", len(targetAPIs))
 
@@ -179,6 +182,20 @@ func runRefreshAllSites(scope string) error {
 			}
 		}
 		fmt.Println("Rebuilt cross-API index")
+
+		var refreshed []string
+		for _, apiLabel := range targetAPIs {
+			if _, failed := errs[apiLabel]; !failed {
+				refreshed = append(refreshed, apiLabel)
+			}
+		}
+		runPostRefreshHooks(ctx, cacheMgr, refreshed)
+	}
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("refresh interrupted: %w", err)
+	}
+	if apiFlag == "" {
+		offerCachePrune(cacheMgr)
 	}
 
 	if scope == cmdutils.RefreshScopeDetail || scope == cmdutils.RefreshScopeAll {
@@ -217,6 +234,7 @@ func runRefreshSite(siteName, target, scope string) error {
 		return fmt.Errorf("failed to refresh %s: %s", site.SourceAPI, formatRefreshError(err))
 	}
 	fmt.Printf("Successfully refreshed %s for site %s\n", site.SourceAPI, site.Name)
+	runPostRefreshHooks(globalContext, cacheMgr, []string{site.SourceAPI})
 
 	if scope == cmdutils.RefreshScopeDetail || scope == cmdutils.RefreshScopeAll {
 		return refreshClientDetailForSite(globalContext, site)
@@ -301,6 +319,18 @@ func runMultiVendorClientDetailRefresh() error {
 	return nil
 }
 
+// runPostRefreshHooks tells the integrations which per-API cache files were
+// just written (remote-backup mirrors them). Failures are logged, not returned.
+func runPostRefreshHooks(ctx context.Context, cacheMgr *vendors.CacheManager, apiLabels []string) {
+	var paths []string
+	for _, apiLabel := range apiLabels {
+		if cacheMgr.CacheExists(apiLabel) {
+			paths = append(paths, cacheMgr.CacheFilePath(apiLabel))
+		}
+	}
+	integrations.PostRefresh(context.WithoutCancel(ctx), integrations.RefreshEvent{APIs: apiLabels, Files: paths})
+}
+
 // formatRefreshError renders a refresh-batch error in the most useful form
 // available. Typed errors from internal/vendors that implement UserMessage get
 // the user-friendly rendering (remediation hint); everything else falls back to
@@ -329,5 +359,9 @@ func formatRefreshError(err error) string {
 	if errors.As(err, &tErr) {
 		return tErr.UserMessage()
 	}
+	var intErr *vendors.InterruptedError
+	if errors.As(err, &intErr) {
+		return intErr.UserMessage()
+	}
 	return err.Error()
 }
@@ -0,0 +1,39 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// reportCmd groups analysis reports computed from the local API cache.
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Analysis reports built from cached API data",
+	Long: `Analysis reports built from cached API data.
+
+Inventory reports read the local cache; run 'wifimgr refresh' first for
+current numbers. Reports on live state (power) query the API directly.`,
+	Example: `  # Devices reaching end-of-sale/support or warranty expiry in the next year
+  wifimgr report lifecycle
+
+  # PoE draw per switch and APs running in reduced-power mode
+  wifimgr report power US-LAB-01`,
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+}
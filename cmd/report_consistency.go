@@ -0,0 +1,226 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/consistency"
+	"github.com/ravinald/wifimgr/internal/formatter"
+	"github.com/ravinald/wifimgr/internal/integrations/netbox"
+	"github.com/ravinald/wifimgr/internal/logging"
+	"github.com/ravinald/wifimgr/internal/symbols"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+// reportConsistencyCmd represents the "report consistency" command
+var reportConsistencyCmd = &cobra.Command{
+	Use:   "consistency [site <site-name>] [target <api-label>] [all] [format json|csv]",
+	Short: "Cross-check device names between the vendor API, NetBox, and DNS",
+	Long: `Cross-check each device's name in the vendor API against NetBox and DNS and
+list the devices where they disagree, so the three can be converged.
+
+Sources are checked when configured:
+  NetBox   netbox.url and an API key (see 'wifimgr export netbox'). Devices are
+           matched by serial at their NetBox site, else by interface MAC.
+  DNS      consistency.dns.domain and/or consistency.dns.server. The PTR of the
+           device's management IP must match its name, and with a domain set
+           <name>.<domain> must resolve to that IP. Names are compared as DNS
+           labels: lowercased, other characters turned into '-'.
+
+Reported issues:
+  unnamed in API, not in NetBox, NetBox name differs, no PTR for <ip>,
+  PTR name differs, no A/AAAA for <fqdn>, <fqdn> resolves to <other ips>
+
+Device names and IPs come from the cache; run 'wifimgr refresh' first.
+
+Arguments:
+  site <name>      Optional. Only devices at this site
+  target <label>   Optional. Limit to one API
+  all              Optional. Check every cached device, not just managed ones
+  format           Optional. "json" or "csv" (default: table)`,
+	Example: `  wifimgr report consistency
+  wifimgr report consistency site US-LAB-01
+  wifimgr report consistency all format csv`,
+	RunE: runReportConsistency,
+}
+
+func init() {
+	reportCmd.AddCommand(reportConsistencyCmd)
+}
+
+func runReportConsistency(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+
+	parsed, err := cmdutils.ParseReportArgs(args)
+	if err != nil {
+		return err
+	}
+	SetAPITarget(parsed.Target)
+	if err := ValidateAPIFlag(); err != nil {
+		return err
+	}
+
+	checker := &consistency.Checker{}
+	var skipped []string
+	if nbCfg, err := netbox.LoadConfig(); err != nil {
+		skipped = append(skipped, fmt.Sprintf("NetBox not checked: %v", err))
+	} else {
+		client, err := netbox.NewClient(nbCfg)
+		if err != nil {
+			return fmt.Errorf("failed to create NetBox client: %w", err)
+		}
+		names := netbox.NewDeviceNames(client)
+		checker.NetBox = func(ctx context.Context, d consistency.Device) (string, error) {
+			return names.Lookup(ctx, d.Site, d.Serial, d.MAC)
+		}
+	}
+	if dnsCfg := consistency.LoadDNSConfig(); dnsCfg == nil {
+		skipped = append(skipped, "DNS not checked: set consistency.dns.domain or consistency.dns.server")
+	} else {
+		checker.DNS = dnsCfg.Resolver()
+		checker.Domain = dnsCfg.Domain
+	}
+	if checker.NetBox == nil && checker.DNS == nil {
+		return fmt.Errorf("nothing to compare the API names with: %s", strings.Join(skipped, "; "))
+	}
+
+	cacheMgr := GetCacheManager()
+	if cacheMgr == nil {
+		return fmt.Errorf("cache manager not initialized")
+	}
+	var managed map[string]bool
+	if !parsed.All {
+		if managed, err = loadManagedMACSet([]string{"ap", "switch", "gateway"}); err != nil {
+			return err
+		}
+	}
+	caches := make(map[string]*vendors.APICache)
+	for _, apiLabel := range GetTargetAPIs() {
+		if cache, err := cacheMgr.GetAPICache(apiLabel); err == nil {
+			caches[apiLabel] = cache
+		}
+	}
+
+	devices := consistencyDevices(caches, managed, parsed.SiteName)
+	logging.Debugf("report consistency: checking %d device(s)", len(devices))
+	mismatches := consistency.Mismatches(checker.Check(globalContext, devices))
+
+	if parsed.Format == "json" {
+		out, err := json.MarshalIndent(mismatches, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal report: %w", err)
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+
+	if parsed.Format == "table" {
+		for _, s := range skipped {
+			fmt.Printf("%s %s\n", symbols.WarningPrefix(), s)
+		}
+	}
+	if len(mismatches) == 0 {
+		if parsed.Format == "table" {
+			fmt.Printf("%s Names agree for all %d device(s) checked\n", symbols.SuccessPrefix(), len(devices))
+		}
+		return nil
+	}
+
+	rows := make([]formatter.GenericTableData, 0, len(mismatches))
+	for _, r := range mismatches {
+		rows = append(rows, formatter.GenericTableData{
+			"site_name": r.Site,
+			"type":      r.Type,
+			"mac":       r.MAC,
+			"name":      r.Name,
+			"netbox":    r.NetBox,
+			"dns":       r.DNS,
+			"issues":    strings.Join(r.Issues, "; "),
+			"api":       r.API,
+		})
+	}
+	printer := formatter.NewGenericTablePrinter(formatter.TableConfig{
+		Title:         fmt.Sprintf("Name Consistency (%d of %d devices disagree)", len(mismatches), len(devices)),
+		Format:        parsed.Format,
+		BoldHeaders:   true,
+		ShowSeparator: true,
+		CommandPath:   "report.consistency",
+		Columns: []formatter.TableColumn{
+			{Field: "site_name", Title: "Site"},
+			{Field: "type", Title: "Type"},
+			{Field: "mac", Title: "MAC"},
+			{Field: "name", Title: "API Name"},
+			{Field: "netbox", Title: "NetBox"},
+			{Field: "dns", Title: "DNS"},
+			{Field: "issues", Title: "Issues"},
+			{Field: "api", Title: "API"},
+		},
+	}, rows)
+	fmt.Print(printer.Print())
+	return nil
+}
+
+// consistencyDevices lists the cached devices to check with their site name
+// and status-cache IP, in a stable order. A nil managed set includes every
+// cached device.
+func consistencyDevices(caches map[string]*vendors.APICache, managed map[string]bool, siteFilter string) []consistency.Device {
+	var devices []consistency.Device
+	for apiLabel, cache := range caches {
+		for _, inv := range []map[string]*vendors.InventoryItem{cache.Inventory.AP, cache.Inventory.Switch, cache.Inventory.Gateway} {
+			for mac, item := range inv {
+				if managed != nil && !managed[vendors.NormalizeMAC(mac)] {
+					continue
+				}
+				site := item.SiteName
+				if name, ok := cache.SiteIndex.ByID[item.SiteID]; ok {
+					site = name
+				}
+				if siteFilter != "" && !strings.EqualFold(site, siteFilter) {
+					continue
+				}
+				d := consistency.Device{
+					MAC:    item.MAC,
+					Serial: item.Serial,
+					Type:   item.Type,
+					Site:   site,
+					API:    apiLabel,
+					Name:   item.Name,
+				}
+				if status, ok := cache.DeviceStatus[mac]; ok && status != nil {
+					d.IP = status.IP
+				}
+				devices = append(devices, d)
+			}
+		}
+	}
+	sort.Slice(devices, func(i, j int) bool {
+		if devices[i].API != devices[j].API {
+			return devices[i].API < devices[j].API
+		}
+		return devices[i].MAC < devices[j].MAC
+	})
+	return devices
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+func TestConsistencyDevices(t *testing.T) {
+	cache := &vendors.APICache{}
+	cache.SiteIndex.ByID = map[string]string{"s1": "US-LAB-01", "s2": "US-HQ-01"}
+	cache.Inventory.AP = map[string]*vendors.InventoryItem{
+		"aa0000000001": {MAC: "aa0000000001", Name: "ap-lobby", Type: "ap", SiteID: "s1", Serial: "S1"},
+		"aa0000000002": {MAC: "aa0000000002", Name: "ap-hall", Type: "ap", SiteID: "s2"},
+		"aa0000000003": {MAC: "aa0000000003", Name: "ap-spare", Type: "ap", SiteID: "s1"},
+	}
+	cache.DeviceStatus = map[string]*vendors.DeviceStatus{"aa0000000001": {IP: "10.0.0.1"}}
+	caches := map[string]*vendors.APICache{"mist-prod": cache}
+
+	managed := map[string]bool{"aa0000000001": true, "aa0000000002": true}
+	devices := consistencyDevices(caches, managed, "us-lab-01")
+	if len(devices) != 1 {
+		t.Fatalf("devices = %+v, want the managed lab AP only", devices)
+	}
+	if d := devices[0]; d.Site != "US-LAB-01" || d.IP != "10.0.0.1" || d.Serial != "S1" || d.API != "mist-prod" {
+		t.Errorf("device = %+v", d)
+	}
+
+	if all := consistencyDevices(caches, nil, ""); len(all) != 3 || all[0].MAC != "aa0000000001" {
+		t.Errorf("all = %+v", all)
+	}
+}
@@ -0,0 +1,492 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/formatter"
+	"github.com/ravinald/wifimgr/internal/symbols"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+// Heat grid dimensions in character cells. Map aspect ratio is not
+// preserved; the grid is for spotting hot areas, not for measuring.
+const (
+	densityGridCols = 32
+	densityGridRows = 12
+)
+
+// densityRamp shades a cell from idle to busiest, scaled to the busiest cell.
+const densityRamp = " .:-=+*#%@"
+
+// reportDensityCmd represents the "report density" command
+var reportDensityCmd = &cobra.Command{
+	Use:   "density <site-name> [map <name>] [target <api-label>] [format json|csv|html]",
+	Short: "Wireless client load per floor plan and area for a site",
+	Long: `Report wireless client density for one site from live AP stats and the
+APs' floor-plan placements.
+
+Without 'map', each floor plan is summarized: APs placed on it, clients
+associated, and the busiest AP. APs with no placement are counted under
+"(unplaced)".
+
+With 'map <name>', the floor plan is divided into a grid and each cell is
+shaded by the clients of the APs placed in it, scaled to the busiest cell,
+followed by the per-AP client counts. 'format html' renders the same grid
+as a self-contained coloured table for sharing in capacity planning
+discussions.
+
+Counts are a point-in-time sample of associated clients, not a time average.
+
+Arguments:
+  site-name        Required. Site to report on
+  map <name>       Optional. Floor plan to render as a heat grid
+  target <label>   Optional. API owning the site (when the name is ambiguous)
+  format           Optional. "json" or "csv"; "html" with 'map' (default: table)
+
+Vendor support: Mist. Other vendors report "not available with this API".`,
+	Example: `  wifimgr report density US-LAB-01
+  wifimgr report density US-LAB-01 map "Floor 2"
+  wifimgr report density US-LAB-01 map "Floor 2" format html > floor2.html`,
+	Args: func(_ *cobra.Command, args []string) error {
+		if cmdutils.ContainsHelp(args) {
+			return nil
+		}
+		if len(args) < 1 {
+			return fmt.Errorf("requires a site name")
+		}
+		return nil
+	},
+	RunE: runReportDensity,
+}
+
+func init() {
+	reportCmd.AddCommand(reportDensityCmd)
+}
+
+// densityArgs is the parsed form of the report density arguments.
+type densityArgs struct {
+	*cmdutils.ReportArgs
+	MapName string
+}
+
+// parseDensityArgs pulls the density-only keywords ('map <name>' and
+// 'format html') out before handing the rest to ParseReportArgs.
+func parseDensityArgs(args []string) (*densityArgs, error) {
+	var mapName string
+	htmlOut := false
+	rest := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		switch strings.ToLower(args[i]) {
+		case "map":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("'map' requires a map name")
+			}
+			if mapName != "" {
+				return nil, fmt.Errorf("map specified multiple times")
+			}
+			mapName = cmdutils.StripQuotes(args[i+1])
+			i++
+		case "format":
+			if i+1 < len(args) && strings.EqualFold(args[i+1], "html") {
+				htmlOut = true
+				i++
+				continue
+			}
+			rest = append(rest, args[i])
+		default:
+			rest = append(rest, args[i])
+		}
+	}
+
+	parsed, err := cmdutils.ParseReportArgs(rest)
+	if err != nil {
+		return nil, err
+	}
+	if htmlOut {
+		if mapName == "" {
+			return nil, fmt.Errorf("'format html' requires 'map <name>'")
+		}
+		if parsed.Format != "table" {
+			return nil, fmt.Errorf("format specified multiple times")
+		}
+		parsed.Format = "html"
+	}
+	return &densityArgs{ReportArgs: parsed, MapName: mapName}, nil
+}
+
+func runReportDensity(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+
+	parsed, err := parseDensityArgs(args)
+	if err != nil {
+		return err
+	}
+	if parsed.SiteName == "" {
+		return fmt.Errorf("requires a site name")
+	}
+
+	ref, err := cmdutils.ResolveSite(parsed.SiteName, parsed.Target)
+	if err != nil {
+		return err
+	}
+	registry := GetAPIRegistry()
+	if registry == nil {
+		return fmt.Errorf("API registry not initialized")
+	}
+	client, err := registry.GetClient(ref.APILabel)
+	if err != nil {
+		return err
+	}
+	statsSvc, mapsSvc := client.DeviceStats(), client.Maps()
+	if statsSvc == nil || mapsSvc == nil {
+		return fmt.Errorf("this feature is not available with this API (%s:%s)", ref.APILabel, client.VendorName())
+	}
+
+	maps, err := mapsSvc.ListBySite(globalContext, ref.SiteID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch maps for %s: %w", ref.Name, err)
+	}
+	stats, err := statsSvc.ListBySite(globalContext, ref.SiteID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch device stats for %s: %w", ref.Name, err)
+	}
+
+	if parsed.MapName == "" {
+		return printDensitySummary(ref.Name, parsed.Format, buildDensitySummary(maps, stats))
+	}
+
+	siteMap := findSiteMap(maps, parsed.MapName)
+	if siteMap == nil {
+		return fmt.Errorf("map %q not found at site %s", parsed.MapName, ref.Name)
+	}
+	return printDensityMap(ref.Name, parsed.Format, buildDensityMap(siteMap, stats))
+}
+
+// densityMapSummary is one row of the per-map density summary.
+type densityMapSummary struct {
+	Map            string `json:"map"`
+	MapID          string `json:"map_id,omitempty"`
+	APs            int    `json:"aps"`
+	Clients        int    `json:"clients"`
+	BusiestAP      string `json:"busiest_ap,omitempty"`
+	BusiestClients int    `json:"busiest_ap_clients,omitempty"`
+}
+
+// densityAP is one placed AP on a density heat map.
+type densityAP struct {
+	Name    string  `json:"name"`
+	MAC     string  `json:"mac"`
+	X       float64 `json:"x"`
+	Y       float64 `json:"y"`
+	Clients int     `json:"clients"`
+}
+
+// densityHeatMap is the heat grid for one floor plan. Grid holds client
+// totals per cell, row-major from the top-left of the map.
+type densityHeatMap struct {
+	Map     string      `json:"map"`
+	MapID   string      `json:"map_id"`
+	Width   float64     `json:"width"`
+	Height  float64     `json:"height"`
+	Clients int         `json:"clients"`
+	Peak    int         `json:"peak_cell_clients"`
+	Grid    [][]int     `json:"grid"`
+	APs     []densityAP `json:"aps"`
+}
+
+// buildDensitySummary totals APs and clients per map. Maps with no APs are
+// still listed so an empty floor is visible; APs on an unknown or missing
+// map fall under "(unplaced)". Rows sort busiest first.
+func buildDensitySummary(maps []*vendors.SiteMap, stats []*vendors.DeviceStats) []densityMapSummary {
+	const unplaced = "(unplaced)"
+	byID := make(map[string]*densityMapSummary, len(maps))
+	rows := make([]*densityMapSummary, 0, len(maps)+1)
+	for _, m := range maps {
+		row := &densityMapSummary{Map: m.Name, MapID: m.ID}
+		byID[m.ID] = row
+		rows = append(rows, row)
+	}
+	for _, st := range stats {
+		if st.Type != "ap" {
+			continue
+		}
+		row, ok := byID[st.MapID]
+		if !ok {
+			row, ok = byID[""]
+			if !ok {
+				row = &densityMapSummary{Map: unplaced}
+				byID[""] = row
+				rows = append(rows, row)
+			}
+		}
+		row.APs++
+		row.Clients += st.Clients
+		if row.BusiestAP == "" || st.Clients > row.BusiestClients {
+			row.BusiestAP, row.BusiestClients = st.Name, st.Clients
+		}
+	}
+
+	out := make([]densityMapSummary, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, *row)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Clients != out[j].Clients {
+			return out[i].Clients > out[j].Clients
+		}
+		return out[i].Map < out[j].Map
+	})
+	return out
+}
+
+// findSiteMap matches a map by name (case-insensitive) or ID.
+func findSiteMap(maps []*vendors.SiteMap, name string) *vendors.SiteMap {
+	for _, m := range maps {
+		if strings.EqualFold(m.Name, name) || m.ID == name {
+			return m
+		}
+	}
+	return nil
+}
+
+// buildDensityMap places the map's APs into a densityGridCols x
+// densityGridRows grid and sums their clients per cell. When the map has
+// no recorded dimensions the extent of the placed APs is used instead.
+func buildDensityMap(siteMap *vendors.SiteMap, stats []*vendors.DeviceStats) *densityHeatMap {
+	heat := &densityHeatMap{
+		Map:    siteMap.Name,
+		MapID:  siteMap.ID,
+		Width:  float64(siteMap.Width),
+		Height: float64(siteMap.Height),
+		APs:    []densityAP{},
+	}
+	for _, st := range stats {
+		if st.Type != "ap" || st.MapID != siteMap.ID {
+			continue
+		}
+		heat.APs = append(heat.APs, densityAP{Name: st.Name, MAC: st.MAC, X: st.X, Y: st.Y, Clients: st.Clients})
+		heat.Clients += st.Clients
+		if siteMap.Width <= 0 && st.X > heat.Width {
+			heat.Width = st.X
+		}
+		if siteMap.Height <= 0 && st.Y > heat.Height {
+			heat.Height = st.Y
+		}
+	}
+	sort.SliceStable(heat.APs, func(i, j int) bool {
+		if heat.APs[i].Clients != heat.APs[j].Clients {
+			return heat.APs[i].Clients > heat.APs[j].Clients
+		}
+		return heat.APs[i].Name < heat.APs[j].Name
+	})
+
+	heat.Grid = make([][]int, densityGridRows)
+	for r := range heat.Grid {
+		heat.Grid[r] = make([]int, densityGridCols)
+	}
+	for _, ap := range heat.APs {
+		r := densityCell(ap.Y, heat.Height, densityGridRows)
+		c := densityCell(ap.X, heat.Width, densityGridCols)
+		heat.Grid[r][c] += ap.Clients
+		if heat.Grid[r][c] > heat.Peak {
+			heat.Peak = heat.Grid[r][c]
+		}
+	}
+	return heat
+}
+
+// densityCell maps a pixel coordinate onto one of n cells, clamping
+// placements at or beyond the map edge into the last cell.
+func densityCell(pos, extent float64, n int) int {
+	if extent <= 0 || pos <= 0 {
+		return 0
+	}
+	cell := int(pos / extent * float64(n))
+	if cell >= n {
+		cell = n - 1
+	}
+	return cell
+}
+
+// densityShade returns the ramp character for a cell relative to the peak.
+// Any non-zero cell gets at least the first visible shade.
+func densityShade(clients, peak int) byte {
+	if clients <= 0 || peak <= 0 {
+		return densityRamp[0]
+	}
+	idx := 1 + clients*(len(densityRamp)-2)/peak
+	if idx >= len(densityRamp) {
+		idx = len(densityRamp) - 1
+	}
+	return densityRamp[idx]
+}
+
+// renderDensityGrid draws the heat grid inside an ASCII frame.
+func renderDensityGrid(heat *densityHeatMap) string {
+	var b strings.Builder
+	border := "+" + strings.Repeat("-", densityGridCols) + "+\n"
+	b.WriteString(border)
+	for _, row := range heat.Grid {
+		b.WriteByte('|')
+		for _, clients := range row {
+			b.WriteByte(densityShade(clients, heat.Peak))
+		}
+		b.WriteString("|\n")
+	}
+	b.WriteString(border)
+	fmt.Fprintf(&b, "Scale: %q fewest .. %q %d clients (busiest cell); blank has no placed APs\n", densityRamp[1:2], densityRamp[len(densityRamp)-1:], heat.Peak)
+	return b.String()
+}
+
+// renderDensityHTML renders the heat grid and AP table as a standalone page.
+// Cell colour runs from white to red relative to the busiest cell.
+func renderDensityHTML(site string, heat *densityHeatMap) string {
+	var b strings.Builder
+	title := html.EscapeString(fmt.Sprintf("Client density — %s / %s", site, heat.Map))
+	fmt.Fprintf(&b, "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>%s</title>\n", title)
+	b.WriteString("<style>table.grid{border-collapse:collapse}table.grid td{width:18px;height:18px;border:1px solid #eee;font:10px sans-serif;text-align:center}</style>\n")
+	fmt.Fprintf(&b, "</head>\n<body>\n<h1>%s</h1>\n<p>%d clients on %d APs; busiest cell %d.</p>\n", title, heat.Clients, len(heat.APs), heat.Peak)
+	b.WriteString("<table class=\"grid\">\n")
+	for _, row := range heat.Grid {
+		b.WriteString("<tr>")
+		for _, clients := range row {
+			level := 0
+			if heat.Peak > 0 {
+				level = clients * 255 / heat.Peak
+			}
+			label := ""
+			if clients > 0 {
+				label = fmt.Sprint(clients)
+			}
+			fmt.Fprintf(&b, "<td style=\"background:rgb(255,%d,%d)\">%s</td>", 255-level, 255-level, label)
+		}
+		b.WriteString("</tr>\n")
+	}
+	b.WriteString("</table>\n<table>\n<tr><th>AP</th><th>MAC</th><th>X</th><th>Y</th><th>Clients</th></tr>\n")
+	for _, ap := range heat.APs {
+		fmt.Fprintf(&b, "<tr><td>%s</td><td>%s</td><td>%.0f</td><td>%.0f</td><td>%d</td></tr>\n",
+			html.EscapeString(ap.Name), html.EscapeString(ap.MAC), ap.X, ap.Y, ap.Clients)
+	}
+	b.WriteString("</table>\n</body>\n</html>\n")
+	return b.String()
+}
+
+func printDensitySummary(site, format string, rows []densityMapSummary) error {
+	if format == "json" {
+		out, err := json.MarshalIndent(rows, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal report: %w", err)
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+	if len(rows) == 0 {
+		fmt.Printf("%s No maps or APs found at %s\n", symbols.WarningPrefix(), site)
+		return nil
+	}
+
+	data := make([]formatter.GenericTableData, 0, len(rows))
+	for _, row := range rows {
+		busiest := ""
+		if row.BusiestAP != "" {
+			busiest = fmt.Sprintf("%s (%d)", row.BusiestAP, row.BusiestClients)
+		}
+		data = append(data, formatter.GenericTableData{
+			"map":     row.Map,
+			"aps":     row.APs,
+			"clients": row.Clients,
+			"busiest": busiest,
+		})
+	}
+	printer := formatter.NewGenericTablePrinter(formatter.TableConfig{
+		Title:         fmt.Sprintf("Client Density — %s (%d maps)", site, len(rows)),
+		Format:        format,
+		BoldHeaders:   true,
+		ShowSeparator: true,
+		CommandPath:   "report.density",
+		Columns: []formatter.TableColumn{
+			{Field: "map", Title: "Map"},
+			{Field: "aps", Title: "APs"},
+			{Field: "clients", Title: "Clients"},
+			{Field: "busiest", Title: "Busiest AP"},
+		},
+	}, data)
+	fmt.Print(printer.Print())
+	return nil
+}
+
+func printDensityMap(site, format string, heat *densityHeatMap) error {
+	switch format {
+	case "json":
+		out, err := json.MarshalIndent(heat, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal report: %w", err)
+		}
+		fmt.Println(string(out))
+		return nil
+	case "html":
+		fmt.Print(renderDensityHTML(site, heat))
+		return nil
+	case "table":
+		fmt.Printf("Client Density — %s / %s: %d clients on %d APs\n", site, heat.Map, heat.Clients, len(heat.APs))
+		fmt.Print(renderDensityGrid(heat))
+		fmt.Println()
+	}
+
+	if len(heat.APs) == 0 {
+		if format == "table" {
+			fmt.Printf("%s No APs placed on map %s\n", symbols.WarningPrefix(), heat.Map)
+		}
+		return nil
+	}
+	data := make([]formatter.GenericTableData, 0, len(heat.APs))
+	for _, ap := range heat.APs {
+		data = append(data, formatter.GenericTableData{
+			"name":    ap.Name,
+			"mac":     ap.MAC,
+			"x":       fmt.Sprintf("%.0f", ap.X),
+			"y":       fmt.Sprintf("%.0f", ap.Y),
+			"clients": ap.Clients,
+		})
+	}
+	printer := formatter.NewGenericTablePrinter(formatter.TableConfig{
+		Title:         fmt.Sprintf("APs on %s (%d)", heat.Map, len(data)),
+		Format:        format,
+		BoldHeaders:   true,
+		ShowSeparator: true,
+		CommandPath:   "report.density",
+		Columns: []formatter.TableColumn{
+			{Field: "name", Title: "Name"},
+			{Field: "mac", Title: "MAC"},
+			{Field: "x", Title: "X"},
+			{Field: "y", Title: "Y"},
+			{Field: "clients", Title: "Clients"},
+		},
+	}, data)
+	fmt.Print(printer.Print())
+	return nil
+}
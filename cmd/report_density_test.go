@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+func TestBuildDensitySummary(t *testing.T) {
+	maps := []*vendors.SiteMap{
+		{ID: "m1", Name: "Floor 1"},
+		{ID: "m2", Name: "Floor 2"},
+		{ID: "m3", Name: "Basement"},
+	}
+	stats := []*vendors.DeviceStats{
+		{Type: "ap", Name: "ap-1a", MapID: "m1", Clients: 5},
+		{Type: "ap", Name: "ap-2a", MapID: "m2", Clients: 30},
+		{Type: "ap", Name: "ap-2b", MapID: "m2", Clients: 12},
+		{Type: "ap", Name: "ap-loose", Clients: 3},
+		{Type: "switch", Name: "sw-1", MapID: "m1"},
+	}
+
+	rows := buildDensitySummary(maps, stats)
+
+	if len(rows) != 4 {
+		t.Fatalf("rows = %d, want 4: %+v", len(rows), rows)
+	}
+	if rows[0].Map != "Floor 2" || rows[0].APs != 2 || rows[0].Clients != 42 || rows[0].BusiestAP != "ap-2a" {
+		t.Errorf("busiest map first: %+v", rows[0])
+	}
+	if rows[2].Map != "(unplaced)" || rows[2].Clients != 3 {
+		t.Errorf("unplaced APs should be grouped: %+v", rows[2])
+	}
+	if rows[3].Map != "Basement" || rows[3].APs != 0 {
+		t.Errorf("empty map should still be listed: %+v", rows[3])
+	}
+}
+
+func TestBuildDensityMap(t *testing.T) {
+	siteMap := &vendors.SiteMap{ID: "m1", Name: "Floor 1", Width: 320, Height: 120}
+	stats := []*vendors.DeviceStats{
+		{Type: "ap", Name: "ap-corner", MapID: "m1", X: 0, Y: 0, Clients: 4},
+		{Type: "ap", Name: "ap-hot", MapID: "m1", X: 315, Y: 115, Clients: 20},
+		{Type: "ap", Name: "ap-edge", MapID: "m1", X: 400, Y: 500, Clients: 6},
+		{Type: "ap", Name: "ap-other", MapID: "m2", X: 10, Y: 10, Clients: 50},
+	}
+
+	heat := buildDensityMap(siteMap, stats)
+
+	if heat.Clients != 30 || len(heat.APs) != 3 {
+		t.Fatalf("clients = %d, aps = %d; want 30 on 3 APs", heat.Clients, len(heat.APs))
+	}
+	if heat.APs[0].Name != "ap-hot" {
+		t.Errorf("APs should sort busiest first: %+v", heat.APs)
+	}
+	if heat.Grid[0][0] != 4 {
+		t.Errorf("top-left cell = %d, want 4", heat.Grid[0][0])
+	}
+	// ap-hot and the off-map ap-edge both clamp into the bottom-right cell.
+	if got := heat.Grid[densityGridRows-1][densityGridCols-1]; got != 26 || heat.Peak != 26 {
+		t.Errorf("bottom-right cell = %d, peak = %d; want 26", got, heat.Peak)
+	}
+
+	grid := renderDensityGrid(heat)
+	lines := strings.Split(grid, "\n")
+	if lines[1][1] != ':' || lines[densityGridRows][densityGridCols] != '@' {
+		t.Errorf("unexpected shading:\n%s", grid)
+	}
+}
+
+func TestBuildDensityMapWithoutDimensions(t *testing.T) {
+	siteMap := &vendors.SiteMap{ID: "m1", Name: "Unscaled"}
+	stats := []*vendors.DeviceStats{
+		{Type: "ap", Name: "ap-a", MapID: "m1", X: 50, Y: 10, Clients: 1},
+		{Type: "ap", Name: "ap-b", MapID: "m1", X: 100, Y: 40, Clients: 2},
+	}
+
+	heat := buildDensityMap(siteMap, stats)
+
+	if heat.Width != 100 || heat.Height != 40 {
+		t.Errorf("extent = %.0fx%.0f, want 100x40", heat.Width, heat.Height)
+	}
+	if heat.Grid[densityGridRows-1][densityGridCols-1] != 2 {
+		t.Errorf("furthest AP should land in the last cell")
+	}
+}
+
+func TestParseDensityArgs(t *testing.T) {
+	parsed, err := parseDensityArgs([]string{"US-LAB-01", "map", "Floor 2", "format", "html"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if parsed.SiteName != "US-LAB-01" || parsed.MapName != "Floor 2" || parsed.Format != "html" {
+		t.Errorf("parsed = %+v (map %q)", parsed.ReportArgs, parsed.MapName)
+	}
+
+	if _, err := parseDensityArgs([]string{"US-LAB-01", "format", "html"}); err == nil {
+		t.Error("expected error for html without map")
+	}
+	if _, err := parseDensityArgs([]string{"US-LAB-01", "map"}); err == nil {
+		t.Error("expected error for map without a name")
+	}
+}
@@ -0,0 +1,200 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/formatter"
+	"github.com/ravinald/wifimgr/internal/symbols"
+	"github.com/ravinald/wifimgr/internal/validation"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+// reportDuplicateNamesCmd represents the "report duplicate-names" command
+var reportDuplicateNamesCmd = &cobra.Command{
+	Use:   "duplicate-names [site <site-name>] [target <api-label>] [format json|csv]",
+	Short: "Find devices that share a name within a site or across the org",
+	Long: `Find cached devices that share a name. Lookups by name (device arguments,
+GetDeviceByName) resolve to only one of them, so the others cannot be
+addressed by name until renamed.
+
+Each duplicate is reported with its scope — "site" when every device holding
+the name is at one site, "org" when the name spans sites or APIs — and a
+suggested rename: the first device by site and MAC keeps the name, the rest
+get the lowest free numeric suffix (ap-lobby-2, ap-lobby-3, ...). Nothing is
+renamed; set the new names in the site config and apply.
+
+Every cached device is checked, managed or not, since unmanaged devices
+collide with name lookups too. 'lint config <site>' flags duplicates in a
+site's intent before they reach the API.
+
+Arguments:
+  site <name>      Optional. Only names held by at least one device at this site
+  target <label>   Optional. Limit to one API
+  format           Optional. "json" or "csv" (default: table)`,
+	Example: `  wifimgr report duplicate-names
+  wifimgr report duplicate-names site US-LAB-01
+  wifimgr report duplicate-names format json`,
+	RunE: runReportDuplicateNames,
+}
+
+func init() {
+	reportCmd.AddCommand(reportDuplicateNamesCmd)
+}
+
+func runReportDuplicateNames(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+
+	parsed, err := cmdutils.ParseReportArgs(args)
+	if err != nil {
+		return err
+	}
+	SetAPITarget(parsed.Target)
+	if err := ValidateAPIFlag(); err != nil {
+		return err
+	}
+
+	cacheMgr := GetCacheManager()
+	if cacheMgr == nil {
+		return fmt.Errorf("cache manager not initialized")
+	}
+	caches := make(map[string]*vendors.APICache)
+	for _, apiLabel := range GetTargetAPIs() {
+		if cache, err := cacheMgr.GetAPICache(apiLabel); err == nil {
+			caches[apiLabel] = cache
+		}
+	}
+
+	dups := filterDuplicatesBySite(validation.FindDuplicateNames(namedDevicesFromCaches(caches)), parsed.SiteName)
+
+	if parsed.Format == "json" {
+		out, err := json.MarshalIndent(dups, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal report: %w", err)
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+
+	if len(dups) == 0 {
+		if parsed.Format == "table" {
+			fmt.Printf("%s No duplicate device names found\n", symbols.SuccessPrefix())
+		}
+		return nil
+	}
+
+	var rows []formatter.GenericTableData
+	devices := 0
+	for _, dup := range dups {
+		for _, d := range dup.Devices {
+			suggested := d.Suggested
+			if suggested == "" && parsed.Format == "table" {
+				suggested = "(keep)"
+			}
+			rows = append(rows, formatter.GenericTableData{
+				"name":      dup.Name,
+				"scope":     dup.Scope,
+				"site_name": d.Site,
+				"type":      d.Type,
+				"mac":       d.MAC,
+				"api":       d.API,
+				"suggested": suggested,
+			})
+			devices++
+		}
+	}
+	printer := formatter.NewGenericTablePrinter(formatter.TableConfig{
+		Title:         fmt.Sprintf("Duplicate Device Names (%d names, %d devices)", len(dups), devices),
+		Format:        parsed.Format,
+		BoldHeaders:   true,
+		ShowSeparator: true,
+		CommandPath:   "report.duplicate-names",
+		Columns: []formatter.TableColumn{
+			{Field: "name", Title: "Name"},
+			{Field: "scope", Title: "Scope"},
+			{Field: "site_name", Title: "Site"},
+			{Field: "type", Title: "Type"},
+			{Field: "mac", Title: "MAC"},
+			{Field: "api", Title: "API"},
+			{Field: "suggested", Title: "Suggested"},
+		},
+	}, rows)
+	fmt.Print(printer.Print())
+
+	if parsed.Format == "table" {
+		fmt.Printf("%s %d name(s) are shared; lookups by name reach only one device each\n", symbols.WarningPrefix(), len(dups))
+	}
+	return nil
+}
+
+// namedDevicesFromCaches lists every cached device with its site name, in a
+// stable order so suggestions do not change between runs.
+func namedDevicesFromCaches(caches map[string]*vendors.APICache) []validation.NamedDevice {
+	labels := make([]string, 0, len(caches))
+	for label := range caches {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+
+	var devices []validation.NamedDevice
+	for _, label := range labels {
+		cache := caches[label]
+		for _, inv := range []map[string]*vendors.InventoryItem{cache.Inventory.AP, cache.Inventory.Switch, cache.Inventory.Gateway} {
+			for _, item := range inv {
+				site := item.SiteName
+				if name, ok := cache.SiteIndex.ByID[item.SiteID]; ok {
+					site = name
+				}
+				devices = append(devices, validation.NamedDevice{
+					MAC:  item.MAC,
+					Name: item.Name,
+					Type: item.Type,
+					Site: site,
+					API:  label,
+				})
+			}
+		}
+	}
+	return devices
+}
+
+// filterDuplicatesBySite keeps the names held by at least one device at
+// siteName. Each kept group still lists every holder, including those at
+// other sites. An empty siteName keeps everything.
+func filterDuplicatesBySite(dups []validation.DuplicateName, siteName string) []validation.DuplicateName {
+	if siteName == "" {
+		return dups
+	}
+	kept := dups[:0]
+	for _, dup := range dups {
+		for _, d := range dup.Devices {
+			if strings.EqualFold(d.Site, siteName) {
+				kept = append(kept, dup)
+				break
+			}
+		}
+	}
+	return kept
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/ravinald/wifimgr/internal/validation"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+func TestReportDuplicateNames(t *testing.T) {
+	cache := &vendors.APICache{}
+	cache.SiteIndex.ByID = map[string]string{"s1": "US-LAB-01", "s2": "US-HQ-01"}
+	cache.Inventory.AP = map[string]*vendors.InventoryItem{
+		"aa0000000001": {MAC: "aa0000000001", Name: "ap-lobby", Type: "ap", SiteID: "s1"},
+		"aa0000000002": {MAC: "aa0000000002", Name: "ap-lobby", Type: "ap", SiteID: "s2"},
+		"aa0000000003": {MAC: "aa0000000003", Name: "ap-hall", Type: "ap", SiteID: "s2"},
+		"aa0000000004": {MAC: "aa0000000004", Name: "ap-hall", Type: "ap", SiteID: "s2"},
+	}
+	devices := namedDevicesFromCaches(map[string]*vendors.APICache{"mist-prod": cache})
+	dups := validation.FindDuplicateNames(devices)
+	if len(dups) != 2 {
+		t.Fatalf("got %d duplicate names, want 2", len(dups))
+	}
+
+	lab := filterDuplicatesBySite(dups, "us-lab-01")
+	if len(lab) != 1 || lab[0].Name != "ap-lobby" {
+		t.Fatalf("site filter kept %+v, want only ap-lobby", lab)
+	}
+	if len(lab[0].Devices) != 2 || lab[0].Devices[0].API != "mist-prod" {
+		t.Errorf("filtered group should keep every holder with its API: %+v", lab[0].Devices)
+	}
+}
@@ -0,0 +1,265 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ravinald/wifimgr/cmd/apply"
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/formatter"
+	"github.com/ravinald/wifimgr/internal/macaddr"
+	"github.com/ravinald/wifimgr/internal/symbols"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+// Firmware compliance states, in report sort order.
+const (
+	firmwareOutdated  = "outdated"  // running a version other than the pin
+	firmwareUnknown   = "unknown"   // pinned, but no running version reported
+	firmwareCompliant = "compliant" // running the pinned version
+	firmwareUnpinned  = "unpinned"  // no pin matches the model
+)
+
+var firmwareStatusOrder = map[string]int{
+	firmwareOutdated: 0, firmwareUnknown: 1, firmwareCompliant: 2, firmwareUnpinned: 3,
+}
+
+// reportFirmwareCmd represents the "report firmware" command
+var reportFirmwareCmd = &cobra.Command{
+	Use:   "firmware <site-name> [all] [target <api-label>] [format json|csv]",
+	Short: "Compare running firmware against the versions pinned in intent",
+	Long: `Report firmware compliance for one site: each device's running version
+against the version pinned for its model by the site's "firmware" section or
+its device template's "firmware" section (the template wins).
+
+Status is one of:
+  outdated    Running a different version than the pin
+  unknown     Pinned, but the API reported no running version
+  compliant   Running the pinned version
+  unpinned    No pin matches the device model (shown with 'all')
+
+Use 'wifimgr firmware apply' to upgrade outdated devices.
+
+Arguments:
+  site-name        Required. Site to report on
+  all              Optional. Include devices with no pin
+  target <label>   Optional. API owning the site (when the name is ambiguous)
+  format           Optional. "json" or "csv" (default: table)`,
+	Example: `  wifimgr report firmware US-LAB-01
+  wifimgr report firmware US-LAB-01 all format csv`,
+	Args: func(_ *cobra.Command, args []string) error {
+		if cmdutils.ContainsHelp(args) {
+			return nil
+		}
+		if len(args) < 1 {
+			return fmt.Errorf("requires a site name")
+		}
+		return nil
+	},
+	RunE: runReportFirmware,
+}
+
+func init() {
+	reportCmd.AddCommand(reportFirmwareCmd)
+}
+
+// firmwareRow is one device in the firmware compliance report.
+type firmwareRow struct {
+	Name     string `json:"name"`
+	MAC      string `json:"mac"`
+	ID       string `json:"id,omitempty"`
+	Type     string `json:"type"`
+	Model    string `json:"model"`
+	Version  string `json:"version"`
+	Target   string `json:"target,omitempty"`
+	Source   string `json:"source,omitempty"` // "site" or "template:<label>"
+	Status   string `json:"status"`
+	Template string `json:"-"`
+}
+
+func runReportFirmware(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+	parsed, err := cmdutils.ParseReportArgs(args)
+	if err != nil {
+		return err
+	}
+	if parsed.SiteName == "" {
+		return fmt.Errorf("requires a site name")
+	}
+
+	ref, _, rows, err := collectFirmwareCompliance(currentDeps(), parsed.SiteName, parsed.Target)
+	if err != nil {
+		return err
+	}
+	if !parsed.All {
+		pinned := rows[:0]
+		for _, r := range rows {
+			if r.Status != firmwareUnpinned {
+				pinned = append(pinned, r)
+			}
+		}
+		rows = pinned
+	}
+	return printFirmwareReport(ref.Name, parsed.Format, rows)
+}
+
+// collectFirmwareCompliance loads the site's firmware pins and its devices
+// and builds the compliance rows.
+func collectFirmwareCompliance(deps *Deps, siteName, target string) (*cmdutils.SiteRef, vendors.Client, []firmwareRow, error) {
+	ref, err := deps.ResolveSite(siteName, target)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	store, err := apply.LoadTemplateStore(deps.Config)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to load templates: %w", err)
+	}
+	pins, err := config.LoadFirmwarePins(templateRefFilesFor(deps.Config), deps.Config.Files.ConfigDir, ref.Name, store)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if pins.Empty() {
+		return nil, nil, nil, fmt.Errorf("no firmware pins declared for %s or its device templates", ref.Name)
+	}
+
+	client, err := deps.Client(ref.APILabel)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	devicesSvc := client.Devices()
+	if devicesSvc == nil {
+		return nil, nil, nil, fmt.Errorf("this feature is not available with this API (%s:%s)", ref.APILabel, client.VendorName())
+	}
+	devices, err := devicesSvc.List(deps.Ctx, ref.SiteID, "")
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to fetch devices for %s: %w", ref.Name, err)
+	}
+	// Inventory versions can lag an upgrade; live stats win where available.
+	var stats []*vendors.DeviceStats
+	if statsSvc := client.DeviceStats(); statsSvc != nil {
+		stats, err = statsSvc.ListBySite(deps.Ctx, ref.SiteID)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to fetch device stats for %s: %w", ref.Name, err)
+		}
+	}
+	return ref, client, buildFirmwareRows(devices, stats, pins), nil
+}
+
+// buildFirmwareRows classifies each device against its pin. Rows sort by
+// status (outdated first), then name.
+func buildFirmwareRows(devices []*vendors.DeviceInfo, stats []*vendors.DeviceStats, pins *config.FirmwarePins) []firmwareRow {
+	running := make(map[string]string, len(stats))
+	for _, st := range stats {
+		if st.Version != "" {
+			running[macaddr.NormalizeOrEmpty(st.MAC)] = st.Version
+		}
+	}
+
+	rows := make([]firmwareRow, 0, len(devices))
+	for _, d := range devices {
+		row := firmwareRow{Name: d.Name, MAC: d.MAC, ID: d.ID, Type: d.Type, Model: d.Model, Version: d.Version}
+		if v, ok := running[macaddr.NormalizeOrEmpty(d.MAC)]; ok {
+			row.Version = v
+		}
+		pin, ok := pins.Lookup(d.MAC, d.Model)
+		switch {
+		case !ok:
+			row.Status = firmwareUnpinned
+		default:
+			row.Target = pin.Version
+			row.Source = pin.Source
+			if pin.Source == config.FirmwareSourceTemplate {
+				row.Source += ":" + pin.Template
+				row.Template = pin.Template
+			}
+			switch {
+			case row.Version == "":
+				row.Status = firmwareUnknown
+			case strings.TrimSpace(row.Version) == pin.Version:
+				row.Status = firmwareCompliant
+			default:
+				row.Status = firmwareOutdated
+			}
+		}
+		rows = append(rows, row)
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		if a, b := firmwareStatusOrder[rows[i].Status], firmwareStatusOrder[rows[j].Status]; a != b {
+			return a < b
+		}
+		return rows[i].Name < rows[j].Name
+	})
+	return rows
+}
+
+func printFirmwareReport(site, format string, rows []firmwareRow) error {
+	if format == "json" {
+		out, err := json.MarshalIndent(rows, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal report: %w", err)
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+	if len(rows) == 0 {
+		fmt.Printf("%s No pinned devices found at %s\n", symbols.WarningPrefix(), site)
+		return nil
+	}
+
+	outdated := 0
+	data := make([]formatter.GenericTableData, 0, len(rows))
+	for _, row := range rows {
+		if row.Status == firmwareOutdated {
+			outdated++
+		}
+		data = append(data, formatter.GenericTableData{
+			"name":    row.Name,
+			"model":   row.Model,
+			"type":    row.Type,
+			"version": row.Version,
+			"target":  row.Target,
+			"source":  row.Source,
+			"status":  row.Status,
+		})
+	}
+	printer := formatter.NewGenericTablePrinter(formatter.TableConfig{
+		Title:         fmt.Sprintf("Firmware Compliance — %s (%d outdated of %d)", site, outdated, len(rows)),
+		Format:        format,
+		BoldHeaders:   true,
+		ShowSeparator: true,
+		CommandPath:   "report.firmware",
+		Columns: []formatter.TableColumn{
+			{Field: "name", Title: "Device"},
+			{Field: "model", Title: "Model"},
+			{Field: "type", Title: "Type"},
+			{Field: "version", Title: "Running"},
+			{Field: "target", Title: "Pinned"},
+			{Field: "source", Title: "Pinned By"},
+			{Field: "status", Title: "Status"},
+		},
+	}, data)
+	fmt.Print(printer.Print())
+	return nil
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+func loadTestFirmwarePins(t *testing.T) *config.FirmwarePins {
+	t.Helper()
+	dir := t.TempDir()
+	site := `{"version": 1, "config": {"sites": {"lab": {
+	  "site_config": {"name": "US-LAB-01"},
+	  "firmware": {"AP43": "0.14.1", "AP4*": "0.12.7"},
+	  "devices": {"ap": {"aa0000000003": {"device_template": "canary"}}}
+	}}}}`
+	if err := os.WriteFile(filepath.Join(dir, "sites.json"), []byte(site), 0600); err != nil {
+		t.Fatal(err)
+	}
+	store := config.NewTemplateStore()
+	store.Device["canary"] = map[string]any{"firmware": map[string]any{"AP43": "0.15.0"}}
+	pins, err := config.LoadFirmwarePins([]string{"sites.json"}, dir, "US-LAB-01", store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pins
+}
+
+func TestBuildFirmwareRows(t *testing.T) {
+	pins := loadTestFirmwarePins(t)
+	devices := []*vendors.DeviceInfo{
+		{ID: "d1", MAC: "aa:00:00:00:00:01", Name: "ap-ok", Model: "AP43", Type: "ap", Version: "0.14.1"},
+		{ID: "d2", MAC: "aa:00:00:00:00:02", Name: "ap-old", Model: "AP45", Type: "ap", Version: "0.10.0"},
+		{ID: "d3", MAC: "aa:00:00:00:00:03", Name: "ap-canary", Model: "AP43", Type: "ap", Version: "0.14.1"},
+		{ID: "d4", MAC: "aa:00:00:00:00:04", Name: "ap-new", Model: "AP43", Type: "ap"},
+		{ID: "d5", MAC: "aa:00:00:00:00:05", Name: "sw-1", Model: "EX2300", Type: "switch", Version: "21.4"},
+	}
+	// Live stats report ap-old already upgraded, which inventory has not caught up with.
+	stats := []*vendors.DeviceStats{{MAC: "aa0000000002", Version: "0.12.7"}}
+
+	rows := buildFirmwareRows(devices, stats, pins)
+
+	got := make(map[string]firmwareRow, len(rows))
+	for _, r := range rows {
+		got[r.Name] = r
+	}
+	checks := []struct{ name, status, target, source string }{
+		{"ap-ok", firmwareCompliant, "0.14.1", "site"},
+		{"ap-old", firmwareCompliant, "0.12.7", "site"},
+		{"ap-canary", firmwareOutdated, "0.15.0", "template:canary"},
+		{"ap-new", firmwareUnknown, "0.14.1", "site"},
+		{"sw-1", firmwareUnpinned, "", ""},
+	}
+	for _, c := range checks {
+		r := got[c.name]
+		if r.Status != c.status || r.Target != c.target || r.Source != c.source {
+			t.Errorf("%s = %+v, want status %s target %s source %s", c.name, r, c.status, c.target, c.source)
+		}
+	}
+	if rows[0].Name != "ap-canary" || rows[len(rows)-1].Name != "sw-1" {
+		t.Errorf("rows should sort outdated first, unpinned last: %+v", rows)
+	}
+}
+
+func TestPlanFirmwareUpgrades(t *testing.T) {
+	rows := []firmwareRow{
+		{Name: "a", ID: "1", Target: "0.15.0", Status: firmwareOutdated},
+		{Name: "b", ID: "2", Target: "0.12.7", Status: firmwareOutdated},
+		{Name: "c", ID: "3", Target: "0.15.0", Status: firmwareOutdated},
+		{Name: "d", Target: "0.15.0", Status: firmwareOutdated},
+		{Name: "e", ID: "5", Target: "0.15.0", Status: firmwareCompliant},
+		{Name: "f", ID: "6", Target: "0.15.0", Status: firmwareUnknown},
+	}
+
+	batches, skipped := planFirmwareUpgrades(rows)
+
+	if len(batches) != 2 || batches[0].Version != "0.12.7" || batches[1].Version != "0.15.0" {
+		t.Fatalf("batches = %+v", batches)
+	}
+	if len(batches[1].Devices) != 2 {
+		t.Errorf("0.15.0 batch = %+v, want a and c", batches[1].Devices)
+	}
+	if len(skipped) != 1 || skipped[0].Name != "d" {
+		t.Errorf("skipped = %+v, want d (no ID)", skipped)
+	}
+}
+
+func TestParseFirmwareApplyArgs(t *testing.T) {
+	got, err := parseFirmwareApplyArgs([]string{"US-LAB-01", "target", "mist-prod", "diff", "force"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.SiteName != "US-LAB-01" || got.Target != "mist-prod" || !got.Diff || !got.Force {
+		t.Errorf("parsed = %+v", got)
+	}
+	if _, err := parseFirmwareApplyArgs([]string{"US-LAB-01", "now"}); err == nil {
+		t.Error("expected error for unknown argument")
+	}
+}
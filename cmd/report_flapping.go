@@ -0,0 +1,220 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/formatter"
+	"github.com/ravinald/wifimgr/internal/symbols"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+// flappingMinReboots is how many reboots in the window mark a device as
+// flapping.
+const flappingMinReboots = 2
+
+// reportFlappingCmd represents the "report flapping" command
+var reportFlappingCmd = &cobra.Command{
+	Use:   "flapping <site-name> [within <days>] [target <api-label>] [format json|csv]",
+	Short: "Devices at a site that rebooted repeatedly",
+	Long: `List the devices at one site that rebooted more than once in a window, from
+the reboot events the API recorded, with the most recent reboot and its
+reason.
+
+A device that keeps restarting usually points at hardware — a failing power
+supply, a marginal PoE budget, a bad cable — rather than configuration, and
+shows up here before anyone opens a ticket.
+
+Arguments:
+  site-name        Required. Site to report on
+  within <days>    Optional. Window to count reboots in (default: 7)
+  target <label>   Optional. API owning the site (when the name is ambiguous)
+  format           Optional. "json" or "csv" (default: table)
+
+Vendor support: Mist. Other vendors report "not available with this API".`,
+	Example: `  wifimgr report flapping US-LAB-01
+  wifimgr report flapping US-LAB-01 within 1
+  wifimgr report flapping US-LAB-01 format json`,
+	Args: func(_ *cobra.Command, args []string) error {
+		if cmdutils.ContainsHelp(args) {
+			return nil
+		}
+		if len(args) < 1 {
+			return fmt.Errorf("requires a site name")
+		}
+		return nil
+	},
+	RunE: runReportFlapping,
+}
+
+func init() {
+	reportCmd.AddCommand(reportFlappingCmd)
+}
+
+func runReportFlapping(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+
+	parsed, err := cmdutils.ParseReportArgs(args)
+	if err != nil {
+		return err
+	}
+	if parsed.SiteName == "" {
+		return fmt.Errorf("requires a site name")
+	}
+	days := parsed.Within
+	if days == 0 {
+		days = 7
+	}
+
+	ref, err := cmdutils.ResolveSite(parsed.SiteName, parsed.Target)
+	if err != nil {
+		return err
+	}
+	registry := GetAPIRegistry()
+	if registry == nil {
+		return fmt.Errorf("API registry not initialized")
+	}
+	client, err := registry.GetClient(ref.APILabel)
+	if err != nil {
+		return err
+	}
+	svc := client.DeviceEvents()
+	if svc == nil {
+		return fmt.Errorf("this feature is not available with this API (%s:%s)", ref.APILabel, client.VendorName())
+	}
+
+	end := time.Now()
+	reboots, err := svc.Reboots(globalContext, ref.SiteID, end.AddDate(0, 0, -days), end)
+	if err != nil {
+		return fmt.Errorf("failed to fetch reboot events for %s: %w", ref.Name, err)
+	}
+	rows := buildFlappingReport(reboots, deviceNameFromCache)
+
+	if parsed.Format == "json" {
+		out, err := json.MarshalIndent(rows, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal report: %w", err)
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+
+	if len(rows) == 0 {
+		if parsed.Format == "table" {
+			fmt.Printf("%s No device at %s rebooted more than once in the last %d days\n", symbols.SuccessPrefix(), ref.Name, days)
+		}
+		return nil
+	}
+
+	tableRows := make([]formatter.GenericTableData, 0, len(rows))
+	for _, r := range rows {
+		tableRows = append(tableRows, formatter.GenericTableData{
+			"name":        r.Name,
+			"mac":         r.MAC,
+			"model":       r.Model,
+			"reboots":     r.Reboots,
+			"last_reboot": r.LastReboot.Local().Format("2006-01-02 15:04"),
+			"reason":      r.LastReason,
+		})
+	}
+	printer := formatter.NewGenericTablePrinter(formatter.TableConfig{
+		Title:         fmt.Sprintf("Flapping Devices — %s, last %d days (%d)", ref.Name, days, len(rows)),
+		Format:        parsed.Format,
+		BoldHeaders:   true,
+		ShowSeparator: true,
+		CommandPath:   "report.flapping",
+		Columns: []formatter.TableColumn{
+			{Field: "name", Title: "Name"},
+			{Field: "mac", Title: "MAC"},
+			{Field: "model", Title: "Model"},
+			{Field: "reboots", Title: "Reboots"},
+			{Field: "last_reboot", Title: "Last Reboot"},
+			{Field: "reason", Title: "Last Reason", MaxWidth: 50},
+		},
+	}, tableRows)
+	fmt.Print(printer.Print())
+	return nil
+}
+
+// flappingDevice is one row of report flapping.
+type flappingDevice struct {
+	Name       string    `json:"name"`
+	MAC        string    `json:"mac"`
+	Model      string    `json:"model,omitempty"`
+	Reboots    int       `json:"reboots"`
+	LastReboot time.Time `json:"last_reboot"`
+	LastReason string    `json:"last_reason,omitempty"`
+}
+
+// buildFlappingReport groups reboots by device and keeps the devices with at
+// least flappingMinReboots, most reboots first. lookup supplies the name and
+// model of a MAC.
+func buildFlappingReport(reboots []*vendors.DeviceReboot, lookup func(mac string) (name, model string)) []flappingDevice {
+	byMAC := make(map[string]*flappingDevice)
+	for _, ev := range reboots {
+		d, ok := byMAC[ev.MAC]
+		if !ok {
+			d = &flappingDevice{MAC: ev.MAC}
+			byMAC[ev.MAC] = d
+		}
+		d.Reboots++
+		if ev.Timestamp.After(d.LastReboot) || d.Reboots == 1 {
+			d.LastReboot = ev.Timestamp
+			d.LastReason = ev.Reason
+		}
+	}
+
+	var out []flappingDevice
+	for _, d := range byMAC {
+		if d.Reboots < flappingMinReboots {
+			continue
+		}
+		d.Name, d.Model = lookup(d.MAC)
+		if d.Name == "" {
+			d.Name = d.MAC
+		}
+		out = append(out, *d)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Reboots != out[j].Reboots {
+			return out[i].Reboots > out[j].Reboots
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+// deviceNameFromCache returns the cached name and model of a device.
+func deviceNameFromCache(mac string) (string, string) {
+	accessor := vendors.GetGlobalCacheAccessor()
+	if accessor == nil {
+		return "", ""
+	}
+	item, err := accessor.GetDeviceByMAC(mac)
+	if err != nil || item == nil {
+		return "", ""
+	}
+	return item.Name, item.Model
+}
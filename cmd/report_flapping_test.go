@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+func TestBuildFlappingReport(t *testing.T) {
+	t0 := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	reboots := []*vendors.DeviceReboot{
+		{MAC: "aa0000000001", Timestamp: t0, Reason: "power cycle"},
+		{MAC: "aa0000000001", Timestamp: t0.Add(2 * time.Hour), Reason: "watchdog"},
+		{MAC: "aa0000000001", Timestamp: t0.Add(time.Hour), Reason: "power cycle"},
+		{MAC: "aa0000000002", Timestamp: t0, Reason: "upgrade"},
+		{MAC: "aa0000000003", Timestamp: t0},
+		{MAC: "aa0000000003", Timestamp: t0.Add(time.Minute)},
+	}
+	names := map[string]string{"aa0000000001": "ap-lobby"}
+	lookup := func(mac string) (string, string) { return names[mac], "AP45" }
+
+	rows := buildFlappingReport(reboots, lookup)
+
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want 2 (single reboots are not flapping): %+v", len(rows), rows)
+	}
+	if r := rows[0]; r.Name != "ap-lobby" || r.Reboots != 3 || r.LastReason != "watchdog" || !r.LastReboot.Equal(t0.Add(2*time.Hour)) {
+		t.Errorf("first row = %+v, want ap-lobby with 3 reboots, last for watchdog", r)
+	}
+	if r := rows[1]; r.Name != "aa0000000003" || r.Reboots != 2 {
+		t.Errorf("second row = %+v, want the unnamed device keyed by MAC", r)
+	}
+}
@@ -0,0 +1,218 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/formatter"
+	"github.com/ravinald/wifimgr/internal/lifecycle"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+// defaultLifecycleWindowDays is the look-ahead when 'within' is not given.
+const defaultLifecycleWindowDays = 365
+
+// reportLifecycleCmd represents the "report lifecycle" command
+var reportLifecycleCmd = &cobra.Command{
+	Use:   "lifecycle [site <site-name>] [within <days>] [target <api-label>] [all] [format json|csv]",
+	Short: "List devices approaching end-of-sale, end-of-support, or warranty expiry",
+	Long: `List devices whose next lifecycle milestone (end-of-sale, end-of-support,
+warranty expiry) falls within the look-ahead window, soonest first. A device
+whose milestones have all passed is listed with the most recent one and a
+negative day count.
+
+Dates come from the file named by files.lifecycle, keyed by model and
+optionally overridden per serial:
+
+  {
+    "models":  { "AP41": { "end_of_sale": "2024-06-30", "end_of_support": "2029-06-30" } },
+    "serials": { "A07123456789": { "warranty_end": "2026-03-31" } }
+  }
+
+Arguments:
+  site <name>      Optional. Limit to one site
+  within <days>    Optional. Look-ahead window (default: 365)
+  target <label>   Optional. Limit to one API
+  all              Optional. Include devices not armed in inventory
+  format           Optional. "json" or "csv" (default: table)`,
+	Example: `  wifimgr report lifecycle
+  wifimgr report lifecycle within 90 site US-LAB-01
+  wifimgr report lifecycle all format csv`,
+	RunE: runReportLifecycle,
+}
+
+func init() {
+	reportCmd.AddCommand(reportLifecycleCmd)
+}
+
+func runReportLifecycle(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+
+	parsed, err := cmdutils.ParseReportArgs(args)
+	if err != nil {
+		return err
+	}
+	SetAPITarget(parsed.Target)
+	if err := ValidateAPIFlag(); err != nil {
+		return err
+	}
+
+	db, err := lifecycle.FromConfig()
+	if err != nil {
+		return err
+	}
+	if db == nil {
+		return fmt.Errorf("no lifecycle data configured (set files.lifecycle to a JSON file of model/serial dates)")
+	}
+
+	cacheMgr := GetCacheManager()
+	if cacheMgr == nil {
+		return fmt.Errorf("cache manager not initialized")
+	}
+
+	var managed map[string]bool
+	if !parsed.All {
+		if managed, err = loadManagedMACSet([]string{"ap", "switch", "gateway"}); err != nil {
+			return err
+		}
+	}
+
+	caches := make(map[string]*vendors.APICache)
+	for _, apiLabel := range GetTargetAPIs() {
+		if cache, err := cacheMgr.GetAPICache(apiLabel); err == nil {
+			caches[apiLabel] = cache
+		}
+	}
+
+	within := parsed.Within
+	if within == 0 {
+		within = defaultLifecycleWindowDays
+	}
+	rows := collectLifecycleRows(caches, db, managed, parsed.SiteName, time.Now(), within)
+
+	title := fmt.Sprintf("Lifecycle Milestones Within %d Days (%d)", within, len(rows))
+	if len(rows) == 0 {
+		fmt.Printf("%s:\nNo devices reach a lifecycle milestone in the next %d days\n", title, within)
+		return nil
+	}
+
+	printer := formatter.NewGenericTablePrinter(formatter.TableConfig{
+		Title:         title,
+		Format:        parsed.Format,
+		BoldHeaders:   true,
+		ShowSeparator: true,
+		CommandPath:   "report.lifecycle",
+		Columns: []formatter.TableColumn{
+			{Field: "name", Title: "Name"},
+			{Field: "model", Title: "Model"},
+			{Field: "serial", Title: "Serial"},
+			{Field: "site_name", Title: "Site"},
+			{Field: "milestone", Title: "Milestone"},
+			{Field: "date", Title: "Date"},
+			{Field: "days", Title: "Days"},
+			{Field: "api", Title: "API"},
+		},
+	}, rows)
+	fmt.Print(printer.Print())
+	return nil
+}
+
+// milestoneTitles renders lifecycle.Milestone kinds for display.
+var milestoneTitles = map[string]string{
+	"end_of_sale":    "End of Sale",
+	"end_of_support": "End of Support",
+	"warranty_end":   "Warranty End",
+}
+
+// collectLifecycleRows returns one row per device whose next milestone falls on
+// or before now+withinDays, sorted soonest first. A nil managed set includes
+// every cached device.
+func collectLifecycleRows(caches map[string]*vendors.APICache, db *lifecycle.DB, managed map[string]bool, siteFilter string, now time.Time, withinDays int) []formatter.GenericTableData {
+	horizon := now.AddDate(0, 0, withinDays)
+	type row struct {
+		data formatter.GenericTableData
+		when time.Time
+	}
+	var rows []row
+
+	for apiLabel, cache := range caches {
+		var siteID string
+		if siteFilter != "" {
+			id, ok := cache.SiteIndex.ByName[siteFilter]
+			if !ok {
+				continue
+			}
+			siteID = id
+		}
+		for _, inv := range []map[string]*vendors.InventoryItem{cache.Inventory.AP, cache.Inventory.Switch, cache.Inventory.Gateway} {
+			for mac, item := range inv {
+				if managed != nil && !managed[vendors.NormalizeMAC(mac)] {
+					continue
+				}
+				if siteID != "" && item.SiteID != siteID {
+					continue
+				}
+				rec, ok := db.Lookup(item.Model, item.Serial)
+				if !ok {
+					continue
+				}
+				m, ok := rec.Next(now, horizon)
+				if !ok {
+					continue
+				}
+				siteName := item.SiteID
+				if name, ok := cache.SiteIndex.ByID[item.SiteID]; ok {
+					siteName = name
+				}
+				rows = append(rows, row{
+					when: m.Date,
+					data: formatter.GenericTableData{
+						"name":      item.Name,
+						"mac":       item.MAC,
+						"model":     item.Model,
+						"serial":    item.Serial,
+						"type":      item.Type,
+						"site_name": siteName,
+						"milestone": milestoneTitles[m.Kind],
+						"date":      m.Date.Format("2006-01-02"),
+						"days":      int(m.Date.Sub(now).Hours() / 24),
+						"api":       apiLabel,
+					},
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		if !rows[i].when.Equal(rows[j].when) {
+			return rows[i].when.Before(rows[j].when)
+		}
+		return fmt.Sprint(rows[i].data["name"]) < fmt.Sprint(rows[j].data["name"])
+	})
+	out := make([]formatter.GenericTableData, len(rows))
+	for i, r := range rows {
+		out[i] = r.data
+	}
+	return out
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ravinald/wifimgr/internal/lifecycle"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+func TestCollectLifecycleRows(t *testing.T) {
+	var db lifecycle.DB
+	if err := json.Unmarshal([]byte(`{
+		"models": {
+			"AP41": {"end_of_support": "2026-06-01"},
+			"AP45": {"end_of_support": "2031-01-01"},
+			"EX2300": {"end_of_sale": "2025-01-01"}
+		}
+	}`), &db); err != nil {
+		t.Fatal(err)
+	}
+
+	cache := vendors.NewAPICache("mist-prod", "mist", "org")
+	cache.SiteIndex.ByID["s1"] = "US-LAB-01"
+	cache.SiteIndex.ByName["US-LAB-01"] = "s1"
+	cache.Inventory.AP["aa0000000001"] = &vendors.InventoryItem{MAC: "aa0000000001", Name: "ap-old", Model: "AP41", SiteID: "s1"}
+	cache.Inventory.AP["aa0000000002"] = &vendors.InventoryItem{MAC: "aa0000000002", Name: "ap-new", Model: "AP45", SiteID: "s1"}
+	cache.Inventory.AP["aa0000000003"] = &vendors.InventoryItem{MAC: "aa0000000003", Name: "ap-other", Model: "AP41", SiteID: "s2"}
+	cache.Inventory.Switch["bb0000000001"] = &vendors.InventoryItem{MAC: "bb0000000001", Name: "sw-1", Model: "EX2300", SiteID: "s1"}
+	caches := map[string]*vendors.APICache{"mist-prod": cache}
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	rows := collectLifecycleRows(caches, &db, nil, "", now, 365)
+	if len(rows) != 3 {
+		t.Fatalf("got %d rows, want 3: %v", len(rows), rows)
+	}
+	// Passed end-of-sale sorts first with a negative day count.
+	if rows[0]["name"] != "sw-1" || rows[0]["milestone"] != "End of Sale" || rows[0]["days"].(int) >= 0 {
+		t.Errorf("first row = %v", rows[0])
+	}
+
+	rows = collectLifecycleRows(caches, &db, nil, "US-LAB-01", now, 365)
+	if len(rows) != 2 {
+		t.Errorf("site filter: got %d rows, want 2", len(rows))
+	}
+
+	managed := map[string]bool{"aa0000000001": true}
+	rows = collectLifecycleRows(caches, &db, managed, "", now, 365)
+	if len(rows) != 1 || rows[0]["name"] != "ap-old" {
+		t.Errorf("managed filter: got %v", rows)
+	}
+}
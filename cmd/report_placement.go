@@ -0,0 +1,319 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/formatter"
+	"github.com/ravinald/wifimgr/internal/logging"
+	"github.com/ravinald/wifimgr/internal/symbols"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+// Placement report statuses.
+const (
+	placementOK       = "ok"
+	placementMoved    = "moved"
+	placementUnplaced = "unplaced"
+	placementMissing  = "not in cache"
+)
+
+// reportPlacementCmd represents the "report placement" command
+var reportPlacementCmd = &cobra.Command{
+	Use:   "placement [site <site-name>] [target <api-label>] [all] [format json|csv]",
+	Short: "APs whose floor-plan placement differs from intent",
+	Long: `Compare the floor-plan placement in AP intent (map_id or map_name, x, y)
+with the placement in the API cache, and list the APs that differ.
+
+An AP is "moved" when it sits on another map or more than half a pixel away
+from its intended position, and "unplaced" when the API has no placement for
+it at all, as with a replacement AP after an RMA swap. Applying the site puts
+both back. APs without a placement in intent are not checked.
+
+A map given by name is looked up in the site's floor plans; when the API
+cannot list them, only x and y are compared.
+
+Arguments:
+  site <name>      Optional. Limit to one site
+  target <label>   Optional. Limit to one API
+  all              Optional. Also list APs placed as intended
+  format           Optional. "json" or "csv" (default: table)`,
+	Example: `  wifimgr report placement
+  wifimgr report placement site US-LAB-01 all
+  wifimgr report placement format csv`,
+	RunE: runReportPlacement,
+}
+
+func init() {
+	reportCmd.AddCommand(reportPlacementCmd)
+}
+
+// placementRow is one AP of the placement report.
+type placementRow struct {
+	Site        string                  `json:"site"`
+	Name        string                  `json:"name"`
+	MAC         string                  `json:"mac"`
+	API         string                  `json:"api,omitempty"`
+	Status      string                  `json:"status"`
+	Differences []vendors.PlacementDiff `json:"differences,omitempty"`
+}
+
+// placedAP is an AP whose intent places it on a floor plan.
+type placedAP struct {
+	Site      string
+	Name      string
+	MAC       string
+	Placement vendors.APPlacement
+}
+
+func runReportPlacement(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+	parsed, err := cmdutils.ParseReportArgs(args)
+	if err != nil {
+		return err
+	}
+	SetAPITarget(parsed.Target)
+	if err := ValidateAPIFlag(); err != nil {
+		return err
+	}
+	cacheMgr := GetCacheManager()
+	if cacheMgr == nil {
+		return fmt.Errorf("cache manager not initialized")
+	}
+	caches := make(map[string]*vendors.APICache)
+	for _, apiLabel := range GetTargetAPIs() {
+		if cache, err := cacheMgr.GetAPICache(apiLabel); err == nil {
+			caches[apiLabel] = cache
+		}
+	}
+
+	aps := placedAPsFromIntent(parsed.SiteName)
+	maps := newSiteMapLookup()
+	var rows []placementRow
+	for _, ap := range aps {
+		row := comparePlacement(ap, caches, maps)
+		if row.Status == placementOK && !parsed.All {
+			continue
+		}
+		rows = append(rows, row)
+	}
+	return printPlacementReport(rows, len(aps), parsed.Format)
+}
+
+// placedAPsFromIntent lists the APs of files.site_configs that have a
+// placement in intent, sorted by site and MAC.
+func placedAPsFromIntent(siteName string) []placedAP {
+	configDir := viper.GetString("files.config_dir")
+	var aps []placedAP
+	for _, file := range viper.GetStringSlice("files.site_configs") {
+		cfg, err := config.LoadSiteConfig(configDir, file)
+		if err != nil {
+			logging.Warnf("Skipping site config %s: %v", file, err)
+			continue
+		}
+		for key, obj := range cfg.Config.Sites {
+			site := obj.SiteConfig.Name
+			if site == "" {
+				site = key
+			}
+			if siteName != "" && !strings.EqualFold(site, siteName) {
+				continue
+			}
+			for mac, ap := range obj.Devices.APs {
+				placement := ap.Placement()
+				if placement.IsZero() {
+					continue
+				}
+				aps = append(aps, placedAP{Site: site, Name: ap.Name, MAC: vendors.NormalizeMAC(mac), Placement: placement})
+			}
+		}
+	}
+	sort.Slice(aps, func(i, j int) bool {
+		if aps[i].Site != aps[j].Site {
+			return aps[i].Site < aps[j].Site
+		}
+		return aps[i].MAC < aps[j].MAC
+	})
+	return aps
+}
+
+// comparePlacement finds the AP in the caches and compares its placement
+// with intent.
+func comparePlacement(ap placedAP, caches map[string]*vendors.APICache, maps *siteMapLookup) placementRow {
+	row := placementRow{Site: ap.Site, Name: ap.Name, MAC: ap.MAC, Status: placementMissing}
+	for _, label := range sortedCacheLabels(caches) {
+		cache := caches[label]
+		item, ok := cache.Inventory.AP[ap.MAC]
+		if !ok {
+			continue
+		}
+		row.API = label
+		if row.Name == "" {
+			row.Name = item.Name
+		}
+		var current vendors.APPlacement
+		if cfg, ok := cache.Configs.AP[ap.MAC]; ok && cfg != nil {
+			current = vendors.PlacementFromConfig(cfg.Config)
+		}
+		intent := ap.Placement
+		if intent.MapID == "" && intent.MapName != "" {
+			intent.MapID = maps.resolve(label, item.SiteID, intent.MapName)
+		}
+		row.Differences = vendors.PlacementDrift(intent, current)
+		switch {
+		case current.IsZero():
+			row.Status = placementUnplaced
+		case len(row.Differences) > 0:
+			row.Status = placementMoved
+		default:
+			row.Status = placementOK
+		}
+		return row
+	}
+	return row
+}
+
+// sortedCacheLabels returns the API labels of caches in order.
+func sortedCacheLabels(caches map[string]*vendors.APICache) []string {
+	labels := make([]string, 0, len(caches))
+	for label := range caches {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	return labels
+}
+
+// siteMapLookup resolves map names to IDs through each API's floor plans,
+// listing a site's plans once. A site whose plans cannot be listed resolves
+// nothing, so only its coordinates are compared.
+type siteMapLookup struct {
+	bySite map[string][]*vendors.SiteMap
+	list   func(apiLabel, siteID string) ([]*vendors.SiteMap, error)
+}
+
+func newSiteMapLookup() *siteMapLookup {
+	return &siteMapLookup{
+		bySite: make(map[string][]*vendors.SiteMap),
+		list: func(apiLabel, siteID string) ([]*vendors.SiteMap, error) {
+			registry := GetAPIRegistry()
+			if registry == nil {
+				return nil, fmt.Errorf("API registry not initialized")
+			}
+			client, err := registry.GetClient(apiLabel)
+			if err != nil {
+				return nil, err
+			}
+			svc := client.Maps()
+			if svc == nil {
+				return nil, fmt.Errorf("floor plans are not available with this API (%s:%s)", apiLabel, client.VendorName())
+			}
+			return svc.ListBySite(globalContext, siteID)
+		},
+	}
+}
+
+func (l *siteMapLookup) resolve(apiLabel, siteID, name string) string {
+	key := apiLabel + "/" + siteID
+	maps, ok := l.bySite[key]
+	if !ok {
+		var err error
+		maps, err = l.list(apiLabel, siteID)
+		if err != nil {
+			logging.Warnf("Cannot resolve map names at site %s: %v", siteID, err)
+		}
+		l.bySite[key] = maps
+	}
+	if m := findSiteMap(maps, name); m != nil {
+		return m.ID
+	}
+	return ""
+}
+
+func printPlacementReport(rows []placementRow, checked int, format string) error {
+	if format == "json" {
+		if rows == nil {
+			rows = []placementRow{}
+		}
+		out, err := json.MarshalIndent(rows, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal report: %w", err)
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+
+	drifted := 0
+	data := make([]formatter.GenericTableData, 0, len(rows))
+	for _, r := range rows {
+		if r.Status != placementOK {
+			drifted++
+		}
+		changes := make([]string, 0, len(r.Differences))
+		for _, d := range r.Differences {
+			current := d.Current
+			if current == "" {
+				current = "-"
+			}
+			changes = append(changes, fmt.Sprintf("%s %s -> %s", d.Field, current, d.Intent))
+		}
+		data = append(data, formatter.GenericTableData{
+			"site_name": r.Site,
+			"name":      r.Name,
+			"mac":       r.MAC,
+			"api":       r.API,
+			"status":    r.Status,
+			"changes":   strings.Join(changes, "; "),
+		})
+	}
+	if len(data) > 0 {
+		printer := formatter.NewGenericTablePrinter(formatter.TableConfig{
+			Title:         fmt.Sprintf("AP Placement (%d checked, %d differ from intent)", checked, drifted),
+			Format:        format,
+			BoldHeaders:   true,
+			ShowSeparator: true,
+			CommandPath:   "report.placement",
+			Columns: []formatter.TableColumn{
+				{Field: "site_name", Title: "Site"},
+				{Field: "name", Title: "Name"},
+				{Field: "mac", Title: "MAC"},
+				{Field: "api", Title: "API"},
+				{Field: "status", Title: "Status"},
+				{Field: "changes", Title: "API -> Intent"},
+			},
+		}, data)
+		fmt.Print(printer.Print())
+	}
+
+	if format == "table" {
+		if drifted == 0 {
+			fmt.Printf("%s %d placed AP(s) match intent\n", symbols.SuccessPrefix(), checked)
+		} else {
+			fmt.Printf("%s %d AP(s) differ from their intended placement; apply the site to restore it\n", symbols.WarningPrefix(), drifted)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+func TestComparePlacement(t *testing.T) {
+	x, y := 120.0, 80.0
+	cache := &vendors.APICache{}
+	cache.Inventory.AP = map[string]*vendors.InventoryItem{
+		"aabbccddee01": {MAC: "aabbccddee01", Name: "AP-01", SiteID: "site-1"},
+		"aabbccddee02": {MAC: "aabbccddee02", Name: "AP-02", SiteID: "site-1"},
+		"aabbccddee03": {MAC: "aabbccddee03", Name: "AP-03", SiteID: "site-1"},
+	}
+	cache.Configs.AP = map[string]*vendors.APConfig{
+		"aabbccddee01": {Config: map[string]any{"map_id": "map-1", "x": 120.2, "y": 80.0}},
+		"aabbccddee02": {Config: map[string]any{"map_id": "map-1", "x": 300.0, "y": 80.0}},
+		"aabbccddee03": {Config: map[string]any{"name": "AP-03"}}, // replacement, not yet placed
+	}
+	caches := map[string]*vendors.APICache{"mist-lab": cache}
+	lists := 0
+	maps := &siteMapLookup{
+		bySite: make(map[string][]*vendors.SiteMap),
+		list: func(_, siteID string) ([]*vendors.SiteMap, error) {
+			lists++
+			return []*vendors.SiteMap{{ID: "map-1", Name: "Floor 1", SiteID: siteID}}, nil
+		},
+	}
+
+	cases := []struct {
+		mac     string
+		mapRef  vendors.APPlacement
+		status  string
+		changes int
+	}{
+		{"aabbccddee01", vendors.APPlacement{MapID: "map-1"}, placementOK, 0},
+		{"aabbccddee02", vendors.APPlacement{MapName: "floor 1"}, placementMoved, 1},
+		{"aabbccddee03", vendors.APPlacement{MapID: "map-1"}, placementUnplaced, 3},
+		{"aabbccddee09", vendors.APPlacement{MapID: "map-1"}, placementMissing, 0},
+	}
+	for _, tc := range cases {
+		p := tc.mapRef
+		p.X, p.Y = &x, &y
+		row := comparePlacement(placedAP{Site: "US-LAB-01", MAC: tc.mac, Placement: p}, caches, maps)
+		if row.Status != tc.status || len(row.Differences) != tc.changes {
+			t.Errorf("%s: status %q with %v, want %q with %d change(s)", tc.mac, row.Status, row.Differences, tc.status, tc.changes)
+		}
+	}
+	if lists != 1 {
+		t.Errorf("floor plans listed %d times, want once", lists)
+	}
+
+	// A site whose floor plans cannot be listed compares coordinates only.
+	failing := &siteMapLookup{
+		bySite: make(map[string][]*vendors.SiteMap),
+		list:   func(_, _ string) ([]*vendors.SiteMap, error) { return nil, fmt.Errorf("no maps") },
+	}
+	p := vendors.APPlacement{MapName: "Floor 9", X: &x, Y: &y}
+	if row := comparePlacement(placedAP{MAC: "aabbccddee01", Placement: p}, caches, failing); row.Status != placementOK {
+		t.Errorf("unresolved map name: %+v", row)
+	}
+}
@@ -0,0 +1,417 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ravinald/wifimgr/cmd/apply"
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/formatter"
+	"github.com/ravinald/wifimgr/internal/symbols"
+)
+
+// reportPortabilityCmd represents the "report portability" command
+var reportPortabilityCmd = &cobra.Command{
+	Use:   "portability [all] [format json|csv]",
+	Short: "Flag template keys that won't translate across the configured vendors",
+	Long: `Analyze the loaded templates against the vendors of the configured APIs and
+flag settings that will not carry over when the same template is applied to
+another vendor:
+
+  - vendor-only keys written as common fields (e.g. Mist's "mesh" or Meraki's
+    "rf_profile_id"), which other vendors ignore or reject
+  - vendor-native spellings that have a vendor-neutral equivalent
+    (e.g. "targetPower" instead of "power")
+  - API labels that do not start with their vendor name; vendor blocks are
+    selected by label prefix, so such an API never receives its block
+
+With "all", informational findings are listed too: vendor blocks for vendors
+no configured API uses, and vendor-block keys set for one vendor only.
+The report reads config only; no API calls are made.
+
+Arguments:
+  all          Optional. Include informational findings
+  format       Optional. "json" or "csv" (default: table)`,
+	Example: `  wifimgr report portability
+  wifimgr report portability all format csv`,
+	RunE: runReportPortability,
+}
+
+func init() {
+	reportCmd.AddCommand(reportPortabilityCmd)
+}
+
+// Portability finding severities.
+const (
+	portabilityWarn = "warn"
+	portabilityInfo = "info"
+)
+
+// portabilityFinding is one template setting that will not translate.
+type portabilityFinding struct {
+	Severity   string `json:"severity"`
+	Template   string `json:"template"` // kind/label, or api/<label> for label findings
+	Key        string `json:"key"`
+	Issue      string `json:"issue"`
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+// vendorOnlyKeys maps common-field paths (path.Match patterns over the
+// dot-joined key path; radio templates are rooted at radio_config) to the only
+// vendor that understands them, per docs/field-mappings.md.
+var vendorOnlyKeys = map[string]string{
+	"map_id": "mist", "x": "mist", "y": "mist", "orientation": "mist", "height": "mist",
+	"disable_eth1": "mist", "disable_eth2": "mist", "disable_eth3": "mist", "disable_module": "mist",
+	"poe_passthrough": "mist", "usb_config": "mist", "vars": "mist", "aeroscout": "mist",
+	"ble_config": "mist", "mesh": "mist", "led.brightness": "mist",
+	"deviceprofile_id": "mist", "deviceprofile_name": "mist",
+	"radio_config.allow_rrm_disable": "mist", "radio_config.scanning_enabled": "mist",
+	"radio_config.indoor_use": "mist", "radio_config.antenna_mode": "mist", "radio_config.band_24_usage": "mist",
+	"radio_config.band_*.power_min": "mist", "radio_config.band_*.power_max": "mist",
+	"radio_config.band_*.channels": "mist", "radio_config.band_*.antenna_mode": "mist",
+	"radio_config.band_*.ant_gain": "mist", "radio_config.band_*.preamble": "mist",
+	"band_steer": "mist", "arp_filter": "mist", "limit_bcast": "mist", "isolation": "mist",
+	"l2_isolation": "mist", "disable_11ax": "mist", "dtim": "mist", "max_num_clients": "mist",
+	"vlan_enabled": "mist", "apply_to": "mist", "ap_ids": "mist",
+
+	"rf_profile_id": "meraki", "radio_config.rf_profile_id": "meraki", "floor_plan_id": "meraki",
+	"lat": "meraki", "lng": "meraki", "band_selection": "meraki", "per_ssid_settings": "meraki",
+	"radio_config.band_*.min_bitrate": "meraki", "radio_config.band_*.rxsop": "meraki",
+	"number": "meraki", "availabilityTags": "meraki", "availableOnAllAps": "meraki",
+	"splashPage": "meraki",
+}
+
+// vendorNativeSpellings maps vendor API field names (matched on the last path
+// segment) to the vendor-neutral field to use instead.
+var vendorNativeSpellings = map[string]string{
+	"targetPower":                     "power",
+	"channelWidth":                    "bandwidth",
+	"ledLightsOn":                     "led.enabled",
+	"assignmentMode":                  "ip_config.type",
+	"twoFourGhzSettings":              "radio_config.band_24",
+	"fiveGhzSettings":                 "radio_config.band_5",
+	"sixGhzSettings":                  "radio_config.band_6",
+	"rfProfileId":                     "rf_profile_id in a meraki: block",
+	"vlanId":                          "vlan_id",
+	"perClientBandwidthLimitUp":       "client_limit_up",
+	"perClientBandwidthLimitDown":     "client_limit_down",
+	"per_client_bandwidth_limit_up":   "client_limit_up",
+	"per_client_bandwidth_limit_down": "client_limit_down",
+	"hide_ssid":                       "hidden",
+	"bandSelection":                   "band",
+	"wpaEncryptionMode":               "auth.pairwise",
+	"authMode":                        "auth.type",
+}
+
+// vendorOnlyKey returns the vendor that alone understands keyPath, if any.
+func vendorOnlyKey(keyPath string) (string, bool) {
+	if v, ok := vendorOnlyKeys[keyPath]; ok {
+		return v, true
+	}
+	for pattern, v := range vendorOnlyKeys {
+		if strings.Contains(pattern, "*") {
+			if ok, _ := path.Match(pattern, keyPath); ok {
+				return v, true
+			}
+		}
+	}
+	return "", false
+}
+
+// leafPaths returns the dot-joined paths of every leaf in m under prefix.
+// Vendor-only parents such as "mesh" are returned whole rather than expanded.
+func leafPaths(prefix string, m map[string]any) []string {
+	var out []string
+	for k, v := range m {
+		p := k
+		if prefix != "" {
+			p = prefix + "." + k
+		}
+		if _, whole := vendorOnlyKeys[p]; !whole {
+			if sub, ok := v.(map[string]any); ok && len(sub) > 0 {
+				out = append(out, leafPaths(p, sub)...)
+				continue
+			}
+		}
+		out = append(out, p)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// analyzeTemplatePortability checks every template against the configured
+// vendors (deduplicated, lower-case vendor names).
+func analyzeTemplatePortability(store *config.TemplateStore, vendors []string) []portabilityFinding {
+	store.Resolve()
+	configured := make(map[string]bool, len(vendors))
+	for _, v := range vendors {
+		configured[v] = true
+	}
+	otherThan := func(vendor string) bool {
+		for v := range configured {
+			if v != vendor {
+				return true
+			}
+		}
+		return false
+	}
+
+	var findings []portabilityFinding
+	check := func(kind string, templates map[string]map[string]any) {
+		labels := make([]string, 0, len(templates))
+		for l := range templates {
+			labels = append(labels, l)
+		}
+		sort.Strings(labels)
+		for _, label := range labels {
+			tmpl := templates[label]
+			name := kind + "/" + label
+			root := ""
+			if _, wrapped := tmpl["radio_config"]; kind == config.TemplateKindRadio && !wrapped {
+				root = "radio_config" // radio templates expand into radio_config
+			}
+
+			common := make(map[string]any)
+			blocks := make(map[string]map[string]any)
+			for k, v := range tmpl {
+				if vendor, ok := strings.CutSuffix(k, ":"); ok {
+					if b, ok := v.(map[string]any); ok {
+						blocks[strings.ToLower(vendor)] = b
+					}
+					continue
+				}
+				common[k] = v
+			}
+
+			commonPaths := make(map[string]bool)
+			for _, p := range leafPaths(root, common) {
+				commonPaths[p] = true
+				last := p[strings.LastIndex(p, ".")+1:]
+				if neutral, ok := vendorNativeSpellings[last]; ok {
+					findings = append(findings, portabilityFinding{
+						Severity: portabilityWarn, Template: name, Key: p,
+						Issue:      "vendor API spelling in common fields",
+						Suggestion: "use " + neutral,
+					})
+					continue
+				}
+				if vendor, ok := vendorOnlyKey(p); ok && otherThan(vendor) {
+					findings = append(findings, portabilityFinding{
+						Severity: portabilityWarn, Template: name, Key: p,
+						Issue:      vendor + "-only key in common fields",
+						Suggestion: fmt.Sprintf("move into a %s: block", vendor),
+					})
+				}
+			}
+
+			blockVendors := make([]string, 0, len(blocks))
+			for v := range blocks {
+				blockVendors = append(blockVendors, v)
+			}
+			sort.Strings(blockVendors)
+			for _, vendor := range blockVendors {
+				if !configured[vendor] {
+					findings = append(findings, portabilityFinding{
+						Severity: portabilityInfo, Template: name, Key: vendor + ":",
+						Issue: "no configured API uses this vendor; block is never applied",
+					})
+					continue
+				}
+				for _, p := range leafPaths(root, blocks[vendor]) {
+					if commonPaths[p] || !otherThan(vendor) {
+						continue
+					}
+					var missing []string
+					for other := range configured {
+						if other == vendor {
+							continue
+						}
+						if ob, ok := blocks[other]; !ok || !containsPath(leafPaths(root, ob), p) {
+							missing = append(missing, other)
+						}
+					}
+					if len(missing) == 0 {
+						continue
+					}
+					sort.Strings(missing)
+					f := portabilityFinding{
+						Severity: portabilityInfo, Template: name, Key: vendor + ":" + p,
+						Issue: fmt.Sprintf("set for %s only; %s get the vendor default", vendor, strings.Join(missing, ", ")),
+					}
+					last := p[strings.LastIndex(p, ".")+1:]
+					if neutral, ok := vendorNativeSpellings[last]; ok {
+						f.Suggestion = "use common field " + neutral
+					}
+					findings = append(findings, f)
+				}
+			}
+		}
+	}
+
+	check(config.TemplateKindWLAN, store.WLAN)
+	check(config.TemplateKindRadio, store.Radio)
+	check(config.TemplateKindDevice, store.Device)
+	return findings
+}
+
+func containsPath(paths []string, p string) bool {
+	for _, x := range paths {
+		if x == p {
+			return true
+		}
+	}
+	return false
+}
+
+// labelVendorMismatches flags API labels whose prefix does not name their
+// vendor: template vendor blocks are chosen by label prefix
+// (config.GetVendorFromAPILabel), so such an API never gets its block.
+func labelVendorMismatches(apiVendors map[string]string) []portabilityFinding {
+	labels := make([]string, 0, len(apiVendors))
+	for l := range apiVendors {
+		labels = append(labels, l)
+	}
+	sort.Strings(labels)
+	var findings []portabilityFinding
+	for _, label := range labels {
+		vendor := apiVendors[label]
+		if config.GetVendorFromAPILabel(label) == vendor {
+			continue
+		}
+		findings = append(findings, portabilityFinding{
+			Severity:   portabilityWarn,
+			Template:   "api/" + label,
+			Key:        vendor + ":",
+			Issue:      fmt.Sprintf("label does not start with %q; %s: template blocks are not applied to it", vendor, vendor),
+			Suggestion: fmt.Sprintf("rename the API to %s-<name>", vendor),
+		})
+	}
+	return findings
+}
+
+func runReportPortability(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+
+	parsed, err := cmdutils.ParseReportArgs(args)
+	if err != nil {
+		return err
+	}
+	if parsed.SiteName != "" || parsed.Target != "" || parsed.Within != 0 {
+		return fmt.Errorf("report portability takes no site, target, or within: it checks every template")
+	}
+
+	registry := GetAPIRegistry()
+	if registry == nil {
+		return fmt.Errorf("API registry not initialized")
+	}
+	apiVendors := make(map[string]string)
+	seen := make(map[string]bool)
+	var vendorList []string
+	for _, label := range registry.GetAllLabels() {
+		vendor, err := registry.GetVendor(label)
+		if err != nil {
+			continue
+		}
+		vendor = strings.ToLower(vendor)
+		apiVendors[label] = vendor
+		if !seen[vendor] {
+			seen[vendor] = true
+			vendorList = append(vendorList, vendor)
+		}
+	}
+	sort.Strings(vendorList)
+
+	store, err := apply.LoadTemplateStore(globalConfig)
+	if err != nil {
+		return fmt.Errorf("failed to load templates: %w", err)
+	}
+
+	findings := append(labelVendorMismatches(apiVendors), analyzeTemplatePortability(store, vendorList)...)
+	warnings := 0
+	for _, f := range findings {
+		if f.Severity == portabilityWarn {
+			warnings++
+		}
+	}
+	if !parsed.All {
+		kept := findings[:0]
+		for _, f := range findings {
+			if f.Severity == portabilityWarn {
+				kept = append(kept, f)
+			}
+		}
+		findings = kept
+	}
+
+	if parsed.Format == "json" {
+		if findings == nil {
+			findings = []portabilityFinding{}
+		}
+		out, err := json.MarshalIndent(findings, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal report: %w", err)
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+
+	if len(findings) > 0 {
+		rows := make([]formatter.GenericTableData, 0, len(findings))
+		for _, f := range findings {
+			rows = append(rows, formatter.GenericTableData{
+				"severity":   f.Severity,
+				"template":   f.Template,
+				"key":        f.Key,
+				"issue":      f.Issue,
+				"suggestion": f.Suggestion,
+			})
+		}
+		printer := formatter.NewGenericTablePrinter(formatter.TableConfig{
+			Title:         fmt.Sprintf("Template Portability — %s (%d)", strings.Join(vendorList, ", "), len(rows)),
+			Format:        parsed.Format,
+			BoldHeaders:   true,
+			ShowSeparator: true,
+			CommandPath:   "report.portability",
+			Columns: []formatter.TableColumn{
+				{Field: "severity", Title: "Severity"},
+				{Field: "template", Title: "Template"},
+				{Field: "key", Title: "Key"},
+				{Field: "issue", Title: "Issue"},
+				{Field: "suggestion", Title: "Suggestion"},
+			},
+		}, rows)
+		fmt.Print(printer.Print())
+	}
+
+	if parsed.Format == "table" {
+		if warnings > 0 {
+			fmt.Printf("%s %d setting(s) will not translate across %s\n", symbols.WarningPrefix(), warnings, strings.Join(vendorList, ", "))
+		} else {
+			fmt.Printf("%s Templates translate across %s\n", symbols.SuccessPrefix(), strings.Join(vendorList, ", "))
+		}
+	}
+	return nil
+}
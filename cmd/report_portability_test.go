@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/ravinald/wifimgr/internal/config"
+)
+
+func findingKeys(fs []portabilityFinding, severity string) map[string]string {
+	out := make(map[string]string)
+	for _, f := range fs {
+		if f.Severity == severity {
+			out[f.Template+" "+f.Key] = f.Suggestion
+		}
+	}
+	return out
+}
+
+func TestAnalyzeTemplatePortability(t *testing.T) {
+	store := config.NewTemplateStore()
+	store.WLAN["corp"] = map[string]any{
+		"ssid":        "Corp",
+		"vlanId":      10,
+		"arp_filter":  true,
+		"meraki:":     map[string]any{"number": 2},
+		"aruba:":      map[string]any{"opmode": "wpa3"},
+		"auth":        map[string]any{"type": "psk"},
+		"hidden":      false,
+		"client_list": []any{"a"},
+	}
+	store.Radio["hd"] = map[string]any{
+		"band_5":  map[string]any{"power": 12, "preamble": "short"},
+		"meraki:": map[string]any{"band_5": map[string]any{"targetPower": 12}},
+	}
+
+	store.Device["ap"] = map[string]any{"mesh": map[string]any{"enabled": true, "role": "root"}}
+
+	got := analyzeTemplatePortability(store, []string{"meraki", "mist"})
+	warn := findingKeys(got, portabilityWarn)
+	for key, suggestion := range map[string]string{
+		"wlan/corp vlanId":                      "use vlan_id",
+		"wlan/corp arp_filter":                  "move into a mist: block",
+		"radio/hd radio_config.band_5.preamble": "move into a mist: block",
+		"device/ap mesh":                        "move into a mist: block",
+	} {
+		if warn[key] != suggestion {
+			t.Errorf("%s: suggestion %q, want %q (warnings: %v)", key, warn[key], suggestion, warn)
+		}
+	}
+	if len(warn) != 4 {
+		t.Errorf("got %d warnings, want 4: %v", len(warn), warn)
+	}
+
+	info := findingKeys(got, portabilityInfo)
+	for _, key := range []string{"wlan/corp aruba:", "wlan/corp meraki:number", "radio/hd meraki:radio_config.band_5.targetPower"} {
+		if _, ok := info[key]; !ok {
+			t.Errorf("missing info finding %s: %v", key, info)
+		}
+	}
+	if info["radio/hd meraki:radio_config.band_5.targetPower"] != "use common field power" {
+		t.Errorf("targetPower suggestion = %q", info["radio/hd meraki:radio_config.band_5.targetPower"])
+	}
+
+	// With only Mist configured, Mist-only common keys are fine.
+	single := findingKeys(analyzeTemplatePortability(store, []string{"mist"}), portabilityWarn)
+	if _, ok := single["wlan/corp arp_filter"]; ok {
+		t.Errorf("mist-only key flagged with only mist configured: %v", single)
+	}
+}
+
+func TestLabelVendorMismatches(t *testing.T) {
+	got := labelVendorMismatches(map[string]string{
+		"mist-prod":    "mist",
+		"meraki":       "meraki",
+		"corp-wifi":    "meraki",
+		"mist-staging": "mist",
+	})
+	if len(got) != 1 || got[0].Template != "api/corp-wifi" {
+		t.Errorf("got %+v, want one finding for corp-wifi", got)
+	}
+}
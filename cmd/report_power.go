@@ -0,0 +1,255 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/formatter"
+	"github.com/ravinald/wifimgr/internal/symbols"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+// reportPowerCmd represents the "report power" command
+var reportPowerCmd = &cobra.Command{
+	Use:   "power <site-name> [target <api-label>] [format json|csv]",
+	Short: "PoE draw per switch and AP power modes for a site",
+	Long: `Report power for one site from live device stats: PoE draw against budget
+for each switch, and the power source and operating mode of each AP.
+
+APs running in a reduced-power mode — constrained by an insufficient PoE
+budget, typically with radios or ports disabled — are flagged. This shows up
+as coverage or throughput complaints long before anyone looks at the switch.
+
+Arguments:
+  site-name        Required. Site to report on
+  target <label>   Optional. API owning the site (when the name is ambiguous)
+  format           Optional. "json" or "csv" (default: table)
+
+Vendor support: Mist. Other vendors report "not available with this API".`,
+	Example: `  wifimgr report power US-LAB-01
+  wifimgr report power US-LAB-01 format json`,
+	Args: func(_ *cobra.Command, args []string) error {
+		if cmdutils.ContainsHelp(args) {
+			return nil
+		}
+		if len(args) < 1 {
+			return fmt.Errorf("requires a site name")
+		}
+		return nil
+	},
+	RunE: runReportPower,
+}
+
+func init() {
+	reportCmd.AddCommand(reportPowerCmd)
+}
+
+func runReportPower(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+
+	parsed, err := cmdutils.ParseReportArgs(args)
+	if err != nil {
+		return err
+	}
+	if parsed.SiteName == "" {
+		return fmt.Errorf("requires a site name")
+	}
+
+	ref, err := cmdutils.ResolveSite(parsed.SiteName, parsed.Target)
+	if err != nil {
+		return err
+	}
+	registry := GetAPIRegistry()
+	if registry == nil {
+		return fmt.Errorf("API registry not initialized")
+	}
+	client, err := registry.GetClient(ref.APILabel)
+	if err != nil {
+		return err
+	}
+	svc := client.DeviceStats()
+	if svc == nil {
+		return fmt.Errorf("this feature is not available with this API (%s:%s)", ref.APILabel, client.VendorName())
+	}
+
+	stats, err := svc.ListBySite(globalContext, ref.SiteID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch device stats for %s: %w", ref.Name, err)
+	}
+	report := buildPowerReport(stats)
+
+	if parsed.Format == "json" {
+		out, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal report: %w", err)
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+
+	switchRows := make([]formatter.GenericTableData, 0, len(report.Switches))
+	for _, sw := range report.Switches {
+		switchRows = append(switchRows, formatter.GenericTableData{
+			"name":        sw.Name,
+			"model":       sw.Model,
+			"draw":        fmt.Sprintf("%.1f", sw.DrawWatts),
+			"budget":      fmt.Sprintf("%.1f", sw.MaxWatts),
+			"utilization": fmt.Sprintf("%.0f%%", sw.Utilization),
+		})
+	}
+	apRows := make([]formatter.GenericTableData, 0, len(report.APs))
+	for _, ap := range report.APs {
+		flag := ""
+		if ap.Reduced {
+			flag = "REDUCED"
+		}
+		apRows = append(apRows, formatter.GenericTableData{
+			"name":   ap.Name,
+			"model":  ap.Model,
+			"source": ap.PowerSource,
+			"mode":   ap.PowerOpMode,
+			"budget": ap.PowerBudgetMW,
+			"flag":   flag,
+		})
+	}
+
+	printPowerTable(fmt.Sprintf("Switch PoE — %s (%d)", ref.Name, len(switchRows)), parsed.Format, switchRows, []formatter.TableColumn{
+		{Field: "name", Title: "Name"},
+		{Field: "model", Title: "Model"},
+		{Field: "draw", Title: "PoE Draw (W)"},
+		{Field: "budget", Title: "PoE Budget (W)"},
+		{Field: "utilization", Title: "Used"},
+	})
+	printPowerTable(fmt.Sprintf("AP Power — %s (%d)", ref.Name, len(apRows)), parsed.Format, apRows, []formatter.TableColumn{
+		{Field: "name", Title: "Name"},
+		{Field: "model", Title: "Model"},
+		{Field: "source", Title: "Power Source"},
+		{Field: "mode", Title: "Operating Mode"},
+		{Field: "budget", Title: "Budget (mW)"},
+		{Field: "flag", Title: "Flag"},
+	})
+
+	if parsed.Format == "table" {
+		if report.ReducedAPs > 0 {
+			fmt.Printf("%s %d of %d APs running in reduced-power mode (insufficient PoE budget)\n",
+				symbols.WarningPrefix(), report.ReducedAPs, len(report.APs))
+		} else if len(report.APs) > 0 {
+			fmt.Printf("%s All %d APs at full power\n", symbols.SuccessPrefix(), len(report.APs))
+		}
+	}
+	return nil
+}
+
+func printPowerTable(title, format string, rows []formatter.GenericTableData, columns []formatter.TableColumn) {
+	if len(rows) == 0 {
+		return
+	}
+	printer := formatter.NewGenericTablePrinter(formatter.TableConfig{
+		Title:         title,
+		Format:        format,
+		BoldHeaders:   true,
+		ShowSeparator: true,
+		CommandPath:   "report.power",
+		Columns:       columns,
+	}, rows)
+	fmt.Print(printer.Print())
+	fmt.Println()
+}
+
+// powerReport is the site power summary rendered by report power.
+type powerReport struct {
+	Switches   []switchPower `json:"switches"`
+	APs        []apPower     `json:"aps"`
+	ReducedAPs int           `json:"reduced_aps"`
+}
+
+type switchPower struct {
+	Name        string  `json:"name"`
+	MAC         string  `json:"mac"`
+	Model       string  `json:"model"`
+	DrawWatts   float64 `json:"poe_draw_watts"`
+	MaxWatts    float64 `json:"poe_max_watts"`
+	Utilization float64 `json:"utilization_pct"`
+}
+
+type apPower struct {
+	Name          string `json:"name"`
+	MAC           string `json:"mac"`
+	Model         string `json:"model"`
+	PowerSource   string `json:"power_source"`
+	PowerOpMode   string `json:"power_opmode,omitempty"`
+	PowerBudgetMW int    `json:"power_budget_mw"`
+	Reduced       bool   `json:"reduced"`
+}
+
+// buildPowerReport splits stats into switch and AP rows. Switches sort by
+// utilization (busiest first); APs sort reduced-first, then by name.
+func buildPowerReport(stats []*vendors.DeviceStats) *powerReport {
+	report := &powerReport{Switches: []switchPower{}, APs: []apPower{}}
+	for _, st := range stats {
+		switch st.Type {
+		case "switch":
+			sw := switchPower{Name: st.Name, MAC: st.MAC, Model: st.Model, DrawWatts: st.PoEDrawWatts, MaxWatts: st.PoEMaxWatts}
+			if st.PoEMaxWatts > 0 {
+				sw.Utilization = st.PoEDrawWatts / st.PoEMaxWatts * 100
+			}
+			report.Switches = append(report.Switches, sw)
+		case "ap":
+			ap := apPower{
+				Name:          st.Name,
+				MAC:           st.MAC,
+				Model:         st.Model,
+				PowerSource:   st.PowerSource,
+				PowerOpMode:   st.PowerOpMode,
+				PowerBudgetMW: st.PowerBudgetMW,
+				Reduced:       isReducedPower(st),
+			}
+			if ap.Reduced {
+				report.ReducedAPs++
+			}
+			report.APs = append(report.APs, ap)
+		}
+	}
+	sort.SliceStable(report.Switches, func(i, j int) bool {
+		if report.Switches[i].Utilization != report.Switches[j].Utilization {
+			return report.Switches[i].Utilization > report.Switches[j].Utilization
+		}
+		return report.Switches[i].Name < report.Switches[j].Name
+	})
+	sort.SliceStable(report.APs, func(i, j int) bool {
+		if report.APs[i].Reduced != report.APs[j].Reduced {
+			return report.APs[i].Reduced
+		}
+		return report.APs[i].Name < report.APs[j].Name
+	})
+	return report
+}
+
+// isReducedPower reports whether an AP is running below full capability for
+// lack of power: the vendor flags it as constrained, or the remaining budget
+// is negative. The operating-mode text is shown but not interpreted, since its
+// wording varies by model and firmware.
+func isReducedPower(st *vendors.DeviceStats) bool {
+	return st.PowerConstrained || st.PowerBudgetMW < 0
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+func TestBuildPowerReport(t *testing.T) {
+	stats := []*vendors.DeviceStats{
+		{Type: "switch", Name: "sw-idle", PoEDrawWatts: 10, PoEMaxWatts: 370},
+		{Type: "switch", Name: "sw-busy", PoEDrawWatts: 300, PoEMaxWatts: 370},
+		{Type: "ap", Name: "ap-a", PowerSource: "PoE 802.3at", PowerBudgetMW: 2000},
+		{Type: "ap", Name: "ap-b", PowerSource: "PoE 802.3af", PowerConstrained: true, PowerOpMode: "6GHz radio disabled"},
+		{Type: "ap", Name: "ap-c", PowerSource: "PoE 802.3af", PowerBudgetMW: -1500},
+		{Type: "gateway", Name: "gw-1"},
+	}
+
+	report := buildPowerReport(stats)
+
+	if len(report.Switches) != 2 || report.Switches[0].Name != "sw-busy" {
+		t.Errorf("switches should sort busiest first: %+v", report.Switches)
+	}
+	if u := report.Switches[0].Utilization; u < 81 || u > 82 {
+		t.Errorf("utilization = %.1f, want ~81.1", u)
+	}
+	if report.ReducedAPs != 2 {
+		t.Errorf("ReducedAPs = %d, want 2", report.ReducedAPs)
+	}
+	if len(report.APs) != 3 || !report.APs[0].Reduced || !report.APs[1].Reduced || report.APs[2].Name != "ap-a" {
+		t.Errorf("APs should sort reduced first: %+v", report.APs)
+	}
+}
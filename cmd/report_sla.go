@@ -0,0 +1,295 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/formatter"
+	"github.com/ravinald/wifimgr/internal/integrations"
+	"github.com/ravinald/wifimgr/internal/logging"
+	"github.com/ravinald/wifimgr/internal/sla"
+	"github.com/ravinald/wifimgr/internal/symbols"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+// reportSLACmd represents the "report sla" command
+var reportSLACmd = &cobra.Command{
+	Use:   "sla [site <site-name>] [target <api-label>] [notify] [format json|csv]",
+	Short: "Check sites against client load and AP availability thresholds",
+	Long: `Check sites against the thresholds under "sla" in the config and list the
+breaches:
+
+  max_clients_per_ap   An AP carrying more wireless clients than this
+  min_aps_online_pct   Fewer of the site's APs online than this percentage
+
+Defaults apply to every site; "overrides" entries replace them at the sites
+matching their name globs (first match wins). A threshold left at 0 is not
+checked, and a site with no thresholds is skipped.
+
+AP status is fetched live from the API, and client counts from live device
+stats where the vendor provides them (Mist, Meraki). The set of APs at a
+site comes from the cache; run 'wifimgr refresh' after adding APs.
+
+With notify, each breach is also sent as an "sla_breach" notification to the
+configured integrations (e.g. webhook). Run it from cron every few minutes
+to hear about overloaded APs and outages before users report them.
+
+Arguments:
+  site <name>      Optional. Only this site (default: every cached site)
+  target <label>   Optional. Limit to one API
+  notify           Optional. Send a notification per breach
+  format           Optional. "json" or "csv" (default: table)`,
+	Example: `  wifimgr report sla
+  wifimgr report sla site US-LAB-01
+  wifimgr report sla notify format json`,
+	RunE: runReportSLA,
+}
+
+func init() {
+	reportCmd.AddCommand(reportSLACmd)
+}
+
+func runReportSLA(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+
+	notifyBreaches := false
+	rest := make([]string, 0, len(args))
+	for _, arg := range args {
+		if strings.EqualFold(arg, "notify") {
+			notifyBreaches = true
+			continue
+		}
+		rest = append(rest, arg)
+	}
+	parsed, err := cmdutils.ParseReportArgs(rest)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := sla.LoadConfig()
+	if err != nil {
+		return err
+	}
+	if cfg == nil {
+		return fmt.Errorf("no SLA thresholds configured: add an \"sla\" section to the config")
+	}
+
+	var sites []cmdutils.SiteRef
+	if parsed.SiteName != "" {
+		ref, err := cmdutils.ResolveSite(parsed.SiteName, parsed.Target)
+		if err != nil {
+			return err
+		}
+		sites = []cmdutils.SiteRef{*ref}
+	} else {
+		SetAPITarget(parsed.Target)
+		if err := ValidateAPIFlag(); err != nil {
+			return err
+		}
+		sites = slaSites(GetTargetAPIs())
+	}
+
+	var breaches []sla.Breach
+	checked := 0
+	statuses := make(map[string]map[string]*vendors.DeviceStatus)
+	for _, ref := range sites {
+		t := cfg.For(ref.Name)
+		if t.IsZero() {
+			continue
+		}
+		aps, err := slaSamples(ref, statuses)
+		if err != nil {
+			logging.Warnf("report sla: skipping %s: %v", ref.Name, err)
+			continue
+		}
+		checked++
+		breaches = append(breaches, sla.Evaluate(ref.Name, t, aps)...)
+	}
+
+	delivered := 0
+	if notifyBreaches {
+		configDir := viper.GetString("files.config_dir")
+		now := time.Now()
+		for _, b := range breaches {
+			delivered += integrations.Notify(globalContext, integrations.Notification{
+				Kind:    integrations.NotifySLABreach,
+				Site:    b.Site,
+				Subject: fmt.Sprintf("%s: %s", b.Site, b.Message),
+				Time:    now,
+				Data:    b,
+				Details: map[string]any{
+					"metric":    b.Metric,
+					"device":    b.Device,
+					"mac":       b.MAC,
+					"value":     b.Value,
+					"threshold": b.Threshold,
+				},
+				ConfigDir: configDir,
+			})
+		}
+	}
+
+	if parsed.Format == "json" {
+		if breaches == nil {
+			breaches = []sla.Breach{}
+		}
+		out, err := json.MarshalIndent(breaches, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal report: %w", err)
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+
+	if len(breaches) == 0 {
+		if parsed.Format == "table" {
+			fmt.Printf("%s All %d site(s) checked are within their SLA thresholds\n", symbols.SuccessPrefix(), checked)
+		}
+		return nil
+	}
+
+	rows := make([]formatter.GenericTableData, 0, len(breaches))
+	for _, b := range breaches {
+		rows = append(rows, formatter.GenericTableData{
+			"site_name": b.Site,
+			"metric":    b.Metric,
+			"device":    b.Device,
+			"value":     fmt.Sprintf("%.0f", b.Value),
+			"threshold": fmt.Sprintf("%.0f", b.Threshold),
+			"message":   b.Message,
+		})
+	}
+	printer := formatter.NewGenericTablePrinter(formatter.TableConfig{
+		Title:         fmt.Sprintf("SLA Breaches (%d across %d site(s) checked)", len(breaches), checked),
+		Format:        parsed.Format,
+		BoldHeaders:   true,
+		ShowSeparator: true,
+		CommandPath:   "report.sla",
+		Columns: []formatter.TableColumn{
+			{Field: "site_name", Title: "Site"},
+			{Field: "metric", Title: "Metric"},
+			{Field: "device", Title: "AP"},
+			{Field: "value", Title: "Value"},
+			{Field: "threshold", Title: "Threshold"},
+			{Field: "message", Title: "Detail"},
+		},
+	}, rows)
+	fmt.Print(printer.Print())
+	if notifyBreaches && parsed.Format == "table" {
+		if delivered == 0 {
+			fmt.Printf("%s No integration took the breach notifications (configure webhook)\n", symbols.WarningPrefix())
+		} else {
+			fmt.Printf("%s Sent %d breach notification(s)\n", symbols.SuccessPrefix(), delivered)
+		}
+	}
+	return nil
+}
+
+// slaSites lists the cached sites of the given APIs, sorted by name.
+func slaSites(apiLabels []string) []cmdutils.SiteRef {
+	cacheMgr := GetCacheManager()
+	if cacheMgr == nil {
+		return nil
+	}
+	var sites []cmdutils.SiteRef
+	for _, label := range apiLabels {
+		cache, err := cacheMgr.GetAPICache(label)
+		if err != nil {
+			continue
+		}
+		for _, s := range cache.Sites.Info {
+			sites = append(sites, cmdutils.SiteRef{APILabel: label, SiteID: s.ID, Name: s.Name})
+		}
+	}
+	sort.Slice(sites, func(i, j int) bool {
+		if sites[i].Name != sites[j].Name {
+			return sites[i].Name < sites[j].Name
+		}
+		return sites[i].APILabel < sites[j].APILabel
+	})
+	return sites
+}
+
+// slaSamples returns the state of every cached AP at a site: online from the
+// API's live statuses (fetched once per API into statuses, falling back to
+// the cached status), clients from live device stats when the vendor has
+// them.
+func slaSamples(ref cmdutils.SiteRef, statuses map[string]map[string]*vendors.DeviceStatus) ([]sla.APSample, error) {
+	cacheMgr := GetCacheManager()
+	registry := GetAPIRegistry()
+	if cacheMgr == nil || registry == nil {
+		return nil, fmt.Errorf("cache or API registry not initialized")
+	}
+	cache, err := cacheMgr.GetAPICache(ref.APILabel)
+	if err != nil {
+		return nil, err
+	}
+	client, err := registry.GetClient(ref.APILabel)
+	if err != nil {
+		return nil, err
+	}
+
+	live, fetched := statuses[ref.APILabel]
+	if !fetched {
+		if svc := client.Statuses(); svc != nil {
+			if live, err = svc.GetAll(globalContext); err != nil {
+				logging.Warnf("report sla: live status unavailable for %s, using cached status: %v", ref.APILabel, err)
+			}
+		}
+		statuses[ref.APILabel] = live
+	}
+
+	clients := make(map[string]int)
+	if svc := client.DeviceStats(); svc != nil {
+		stats, err := svc.ListBySite(globalContext, ref.SiteID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch device stats: %w", err)
+		}
+		for _, st := range stats {
+			clients[st.MAC] = st.Clients
+		}
+	}
+
+	var aps []sla.APSample
+	for mac, item := range cache.Inventory.AP {
+		if item == nil || item.SiteID != ref.SiteID {
+			continue
+		}
+		status := live[mac]
+		if status == nil {
+			status = cache.DeviceStatus[mac]
+		}
+		aps = append(aps, sla.APSample{
+			Name:    item.Name,
+			MAC:     mac,
+			Online:  status != nil && (status.Status == "online" || status.Status == "alerting"),
+			Clients: clients[mac],
+		})
+	}
+	sort.Slice(aps, func(i, j int) bool { return aps[i].MAC < aps[j].MAC })
+	return aps, nil
+}
@@ -0,0 +1,312 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ravinald/wifimgr/cmd/apply"
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/formatter"
+	"github.com/ravinald/wifimgr/internal/logging"
+	"github.com/ravinald/wifimgr/internal/symbols"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+// reportTemplateDriftCmd represents the "report template-drift" command
+var reportTemplateDriftCmd = &cobra.Command{
+	Use:   "template-drift [site <site-name>] [target <api-label>] [all] [format json|csv]",
+	Short: "Compare Mist org-level WLANs with the local WLAN templates",
+	Long: `Compare the org-level WLANs in the cache (Mist org templates, which apply
+across sites) with the local WLAN templates of the same SSID, and list the
+fields changed outside wifimgr, e.g. in the Mist GUI. A change to an org
+template silently alters every site it is assigned to, so each drifted
+template is listed with the sites whose configs reference it.
+
+Compared fields: enabled, hidden, band, vlan_id, auth.type, client_limit_up,
+client_limit_down. Only fields the local template sets are compared; secrets
+are not compared because the cache holds them masked. The local template is
+expanded for Mist (mist: blocks applied) before comparison.
+
+Org WLANs are read from the cache; run 'wifimgr refresh' first. Meraki has
+no org-level WLANs, so Meraki APIs report nothing.
+
+Arguments:
+  site <name>      Optional. Only templates referenced by this site
+  target <label>   Optional. Limit to one API
+  all              Optional. Also list org WLANs with no local template
+  format           Optional. "json" or "csv" (default: table)`,
+	Example: `  wifimgr report template-drift
+  wifimgr report template-drift site US-LAB-01
+  wifimgr report template-drift all format json`,
+	RunE: runReportTemplateDrift,
+}
+
+func init() {
+	reportCmd.AddCommand(reportTemplateDriftCmd)
+}
+
+// orgWLANDriftFields are the WLAN fields compared between an org WLAN and its
+// local template, as dotted template paths.
+var orgWLANDriftFields = []string{
+	"enabled", "hidden", "band", "vlan_id", "auth.type", "client_limit_up", "client_limit_down",
+}
+
+// templateFieldDrift is one field where an org WLAN differs from its template.
+type templateFieldDrift struct {
+	Field string `json:"field"`
+	Local string `json:"local"`
+	Org   string `json:"org"`
+}
+
+// orgTemplateDrift is one org WLAN that differs from its local template, or
+// that has no local template at all (Template empty).
+type orgTemplateDrift struct {
+	API      string               `json:"api"`
+	SSID     string               `json:"ssid"`
+	WLANID   string               `json:"wlan_id"`
+	Template string               `json:"template,omitempty"`
+	Fields   []templateFieldDrift `json:"fields,omitempty"`
+	Sites    []string             `json:"sites,omitempty"` // sites referencing Template
+}
+
+// orgWLANValues returns the compared fields of a cached org WLAN, normalized
+// the way import api templates writes them.
+func orgWLANValues(w *vendors.WLAN) map[string]string {
+	p := convertVendorWLANToProfile(w, secretReveal{})
+	return map[string]string{
+		"enabled":           fmt.Sprint(p.Enabled),
+		"hidden":            fmt.Sprint(p.Hidden),
+		"band":              p.Band,
+		"vlan_id":           fmt.Sprint(p.VLANID),
+		"auth.type":         p.Auth.Type,
+		"client_limit_up":   fmt.Sprint(p.ClientLimitUp),
+		"client_limit_down": fmt.Sprint(p.ClientLimitDown),
+	}
+}
+
+// templateWLANValue returns a compared field of an expanded local template,
+// normalized like orgWLANValues. ok is false when the template does not set it.
+func templateWLANValue(tmpl map[string]any, field string) (string, bool) {
+	container := tmpl
+	key := field
+	if section, rest, nested := strings.Cut(field, "."); nested {
+		container, _ = tmpl[section].(map[string]any)
+		key = rest
+	}
+	v, ok := container[key]
+	if !ok || v == nil {
+		return "", false
+	}
+	s := fmt.Sprint(v)
+	switch field {
+	case "band":
+		s = normalizeBand(s)
+	case "auth.type":
+		s = normalizeAuthType(s)
+	}
+	return s, true
+}
+
+// orgWLANDrift compares each org WLAN with the local WLAN template of the same
+// SSID (the first label in sort order when several share it). sites returns
+// the sites referencing a template label. Org WLANs without a local template
+// are included with an empty Template only when untracked is set. Results are
+// ordered by SSID.
+func orgWLANDrift(apiLabel string, orgWLANs []*vendors.WLAN, templates map[string]map[string]any, sites func(label string) []string, untracked bool) []orgTemplateDrift {
+	labels := make([]string, 0, len(templates))
+	for label := range templates {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	bySSID := make(map[string]string, len(labels))
+	for _, label := range labels {
+		ssid, _ := templates[label]["ssid"].(string)
+		if _, taken := bySSID[ssid]; ssid != "" && !taken {
+			bySSID[ssid] = label
+		}
+	}
+
+	var out []orgTemplateDrift
+	for _, w := range orgWLANs {
+		d := orgTemplateDrift{API: apiLabel, SSID: w.SSID, WLANID: w.ID}
+		label, ok := bySSID[w.SSID]
+		if !ok {
+			if untracked {
+				out = append(out, d)
+			}
+			continue
+		}
+		d.Template = label
+		org := orgWLANValues(w)
+		for _, field := range orgWLANDriftFields {
+			local, set := templateWLANValue(templates[label], field)
+			if set && local != org[field] {
+				d.Fields = append(d.Fields, templateFieldDrift{Field: field, Local: local, Org: org[field]})
+			}
+		}
+		if len(d.Fields) == 0 {
+			continue
+		}
+		d.Sites = sites(label)
+		out = append(out, d)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].SSID < out[j].SSID })
+	return out
+}
+
+func runReportTemplateDrift(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+
+	parsed, err := cmdutils.ParseReportArgs(args)
+	if err != nil {
+		return err
+	}
+	SetAPITarget(parsed.Target)
+	if err := ValidateAPIFlag(); err != nil {
+		return err
+	}
+
+	store, err := apply.LoadTemplateStore(globalConfig)
+	if err != nil {
+		return fmt.Errorf("failed to load templates: %w", err)
+	}
+	templates := make(map[string]map[string]any)
+	for _, label := range store.ListTemplates()[config.TemplateKindWLAN] {
+		if tmpl, ok := store.GetWLANTemplate(label); ok {
+			templates[label] = config.ExpandForVendor(tmpl, "mist")
+		}
+	}
+
+	refFiles := templateRefFiles()
+	sitesFor := func(label string) []string {
+		refs, err := config.FindTemplateRefs(refFiles, globalConfig.Files.ConfigDir, config.TemplateKindWLAN, label)
+		if err != nil {
+			logging.Debugf("report template-drift: references to %s: %v", label, err)
+			return nil
+		}
+		seen := make(map[string]bool)
+		var sites []string
+		for _, r := range refs {
+			if !seen[r.Site] {
+				seen[r.Site] = true
+				sites = append(sites, r.Site)
+			}
+		}
+		sort.Strings(sites)
+		return sites
+	}
+
+	cacheAccessor, err := cmdutils.GetCacheAccessor()
+	if err != nil {
+		return fmt.Errorf("failed to get cache accessor: %w", err)
+	}
+	var drifts []orgTemplateDrift
+	orgCount := 0
+	for _, apiLabel := range GetTargetAPIs() {
+		wlans := collectOrgWLANs(cacheAccessor, apiLabel)
+		orgCount += len(wlans)
+		drifts = append(drifts, orgWLANDrift(apiLabel, wlans, templates, sitesFor, parsed.All)...)
+	}
+	if parsed.SiteName != "" {
+		kept := drifts[:0]
+		for _, d := range drifts {
+			for _, s := range d.Sites {
+				if strings.EqualFold(s, parsed.SiteName) {
+					kept = append(kept, d)
+					break
+				}
+			}
+		}
+		drifts = kept
+	}
+
+	if parsed.Format == "json" {
+		if drifts == nil {
+			drifts = []orgTemplateDrift{}
+		}
+		out, err := json.MarshalIndent(drifts, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal report: %w", err)
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+
+	if len(drifts) == 0 {
+		if parsed.Format == "table" {
+			fmt.Printf("%s %d org WLAN(s) match their local templates\n", symbols.SuccessPrefix(), orgCount)
+		}
+		return nil
+	}
+
+	var rows []formatter.GenericTableData
+	drifted := 0
+	for _, d := range drifts {
+		if d.Template == "" {
+			rows = append(rows, formatter.GenericTableData{
+				"ssid": d.SSID, "template": "-", "field": "(no local template)", "api": d.API,
+			})
+			continue
+		}
+		drifted++
+		sites := strings.Join(d.Sites, ", ")
+		if sites == "" {
+			sites = "-"
+		}
+		for _, f := range d.Fields {
+			rows = append(rows, formatter.GenericTableData{
+				"ssid":     d.SSID,
+				"template": d.Template,
+				"field":    f.Field,
+				"local":    f.Local,
+				"org":      f.Org,
+				"sites":    sites,
+				"api":      d.API,
+			})
+		}
+	}
+	printer := formatter.NewGenericTablePrinter(formatter.TableConfig{
+		Title:         fmt.Sprintf("Org Template Drift (%d of %d org WLANs)", len(drifts), orgCount),
+		Format:        parsed.Format,
+		BoldHeaders:   true,
+		ShowSeparator: true,
+		CommandPath:   "report.template-drift",
+		Columns: []formatter.TableColumn{
+			{Field: "ssid", Title: "SSID"},
+			{Field: "template", Title: "Template"},
+			{Field: "field", Title: "Field"},
+			{Field: "local", Title: "Local"},
+			{Field: "org", Title: "Org"},
+			{Field: "sites", Title: "Inheriting Sites"},
+			{Field: "api", Title: "API"},
+		},
+	}, rows)
+	fmt.Print(printer.Print())
+
+	if parsed.Format == "table" && drifted > 0 {
+		fmt.Printf("%s %d org template(s) changed outside wifimgr; the listed sites inherit the change\n", symbols.WarningPrefix(), drifted)
+	}
+	return nil
+}
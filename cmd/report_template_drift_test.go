@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+func TestOrgWLANDrift(t *testing.T) {
+	templates := map[string]map[string]any{
+		"corp": {
+			"ssid": "Corp", "enabled": true, "band": "dual", "vlan_id": float64(10),
+			"auth": map[string]any{"type": "eap"},
+		},
+		"guest":      {"ssid": "Guest", "enabled": true, "vlan_id": float64(20)},
+		"guest-copy": {"ssid": "Guest", "vlan_id": float64(99)},
+	}
+	wlans := []*vendors.WLAN{
+		{ID: "w1", SSID: "Guest", Enabled: true, VLANID: 20, AuthType: "psk"},
+		{ID: "w2", SSID: "Corp", Enabled: true, Band: "dual", VLANID: 30, AuthType: "psk"},
+		{ID: "w3", SSID: "IoT", Enabled: true},
+	}
+	sites := func(label string) []string {
+		if label == "corp" {
+			return []string{"US-HQ-01", "US-LAB-01"}
+		}
+		return nil
+	}
+
+	drifts := orgWLANDrift("mist-prod", wlans, templates, sites, false)
+	if len(drifts) != 1 {
+		t.Fatalf("drifts = %+v, want Corp only (Guest matches its first template, IoT is untracked)", drifts)
+	}
+	d := drifts[0]
+	if d.Template != "corp" || d.WLANID != "w2" || len(d.Sites) != 2 {
+		t.Errorf("drift = %+v", d)
+	}
+	want := []templateFieldDrift{
+		{Field: "vlan_id", Local: "10", Org: "30"},
+		{Field: "auth.type", Local: "eap", Org: "psk"},
+	}
+	if len(d.Fields) != len(want) {
+		t.Fatalf("fields = %+v, want %+v", d.Fields, want)
+	}
+	for i := range want {
+		if d.Fields[i] != want[i] {
+			t.Errorf("field %d = %+v, want %+v", i, d.Fields[i], want[i])
+		}
+	}
+
+	all := orgWLANDrift("mist-prod", wlans, templates, sites, true)
+	if len(all) != 2 || all[1].SSID != "IoT" || all[1].Template != "" {
+		t.Errorf("all = %+v, want Corp then untracked IoT", all)
+	}
+}
@@ -0,0 +1,247 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/formatter"
+	"github.com/ravinald/wifimgr/internal/symbols"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+// Uplink audit thresholds. A gigabit-capable AP negotiating below 1000 Mbps
+// almost always means a bad termination or a pair broken in the run; error
+// ratios above 0.1% (with enough errors to rule out a one-off) likewise point
+// at cabling rather than RF.
+const (
+	uplinkMinSpeedMbps  = 1000
+	uplinkMaxErrorRatio = 0.001
+	uplinkMinErrors     = 100
+)
+
+// reportUplinkCmd represents the "report uplink" command
+var reportUplinkCmd = &cobra.Command{
+	Use:   "uplink <site-name> [target <api-label>] [all] [format json|csv]",
+	Short: "Audit AP eth0 link speed, duplex, and error counters for a site",
+	Long: `Audit each AP's wired uplink (eth0) from live device stats and flag:
+
+  - links negotiated below 1000 Mbps (typically 100 Mbps on a damaged pair)
+  - half duplex
+  - rx/tx error ratio above 0.1% of packets (and at least 100 errors)
+  - link down on an AP that is otherwise reporting
+
+Mis-terminated cabling commonly masquerades as a WiFi problem; this check
+separates the two before anyone touches RF settings.
+
+Arguments:
+  site-name        Required. Site to audit
+  target <label>   Optional. API owning the site (when the name is ambiguous)
+  all              Optional. List every AP, not just flagged ones
+  format           Optional. "json" or "csv" (default: table)
+
+Vendor support: Mist. Other vendors report "not available with this API".`,
+	Example: `  wifimgr report uplink US-LAB-01
+  wifimgr report uplink US-LAB-01 all format csv`,
+	Args: func(_ *cobra.Command, args []string) error {
+		if cmdutils.ContainsHelp(args) {
+			return nil
+		}
+		if len(args) < 1 {
+			return fmt.Errorf("requires a site name")
+		}
+		return nil
+	},
+	RunE: runReportUplink,
+}
+
+func init() {
+	reportCmd.AddCommand(reportUplinkCmd)
+}
+
+func runReportUplink(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+
+	parsed, err := cmdutils.ParseReportArgs(args)
+	if err != nil {
+		return err
+	}
+	if parsed.SiteName == "" {
+		return fmt.Errorf("requires a site name")
+	}
+
+	ref, err := cmdutils.ResolveSite(parsed.SiteName, parsed.Target)
+	if err != nil {
+		return err
+	}
+	registry := GetAPIRegistry()
+	if registry == nil {
+		return fmt.Errorf("API registry not initialized")
+	}
+	client, err := registry.GetClient(ref.APILabel)
+	if err != nil {
+		return err
+	}
+	svc := client.DeviceStats()
+	if svc == nil {
+		return fmt.Errorf("this feature is not available with this API (%s:%s)", ref.APILabel, client.VendorName())
+	}
+
+	stats, err := svc.ListBySite(globalContext, ref.SiteID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch device stats for %s: %w", ref.Name, err)
+	}
+
+	results := auditUplinks(stats)
+	flagged := 0
+	for _, r := range results {
+		if len(r.Findings) > 0 {
+			flagged++
+		}
+	}
+	if !parsed.All {
+		kept := results[:0]
+		for _, r := range results {
+			if len(r.Findings) > 0 {
+				kept = append(kept, r)
+			}
+		}
+		results = kept
+	}
+
+	if parsed.Format == "json" {
+		out, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal report: %w", err)
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+
+	if len(results) > 0 {
+		rows := make([]formatter.GenericTableData, 0, len(results))
+		for _, r := range results {
+			duplex := "full"
+			if !r.FullDuplex {
+				duplex = "half"
+			}
+			rows = append(rows, formatter.GenericTableData{
+				"name":     r.Name,
+				"mac":      r.MAC,
+				"speed":    r.SpeedMbps,
+				"duplex":   duplex,
+				"errors":   r.Errors,
+				"findings": strings.Join(r.Findings, "; "),
+			})
+		}
+		printer := formatter.NewGenericTablePrinter(formatter.TableConfig{
+			Title:         fmt.Sprintf("AP Uplink Audit — %s (%d)", ref.Name, len(rows)),
+			Format:        parsed.Format,
+			BoldHeaders:   true,
+			ShowSeparator: true,
+			CommandPath:   "report.uplink",
+			Columns: []formatter.TableColumn{
+				{Field: "name", Title: "Name"},
+				{Field: "mac", Title: "MAC"},
+				{Field: "speed", Title: "Speed (Mbps)"},
+				{Field: "duplex", Title: "Duplex"},
+				{Field: "errors", Title: "Errors"},
+				{Field: "findings", Title: "Findings"},
+			},
+		}, rows)
+		fmt.Print(printer.Print())
+	}
+
+	if parsed.Format == "table" {
+		if flagged > 0 {
+			fmt.Printf("%s %d AP uplink(s) need attention — check cabling and switch ports before RF\n",
+				symbols.WarningPrefix(), flagged)
+		} else {
+			fmt.Printf("%s No AP uplink issues found at %s\n", symbols.SuccessPrefix(), ref.Name)
+		}
+	}
+	return nil
+}
+
+// uplinkResult is one AP's eth0 audit outcome.
+type uplinkResult struct {
+	Name       string   `json:"name"`
+	MAC        string   `json:"mac"`
+	SpeedMbps  int      `json:"speed_mbps"`
+	FullDuplex bool     `json:"full_duplex"`
+	Errors     int64    `json:"errors"`
+	Findings   []string `json:"findings"`
+}
+
+// auditUplinks audits every AP in stats, flagged APs first, then by name.
+func auditUplinks(stats []*vendors.DeviceStats) []uplinkResult {
+	results := []uplinkResult{}
+	for _, st := range stats {
+		if st.Type != "ap" {
+			continue
+		}
+		results = append(results, uplinkResult{
+			Name:       st.Name,
+			MAC:        st.MAC,
+			SpeedMbps:  st.Eth0SpeedMbps,
+			FullDuplex: st.Eth0FullDuplex,
+			Errors:     st.Eth0RxErrors + st.Eth0TxErrors,
+			Findings:   auditAPUplink(st),
+		})
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		fi, fj := len(results[i].Findings) > 0, len(results[j].Findings) > 0
+		if fi != fj {
+			return fi
+		}
+		return results[i].Name < results[j].Name
+	})
+	return results
+}
+
+// auditAPUplink returns the eth0 problems found for one AP, or nil. APs whose
+// stats carry no eth0 data at all (offline, or a vendor without port stats)
+// are not flagged.
+func auditAPUplink(st *vendors.DeviceStats) []string {
+	if st.Eth0SpeedMbps == 0 && !st.Eth0Up && st.Eth0RxPackets == 0 && st.Eth0TxPackets == 0 {
+		return nil
+	}
+	if !st.Eth0Up {
+		return []string{"eth0 down"}
+	}
+
+	var findings []string
+	if st.Eth0SpeedMbps > 0 && st.Eth0SpeedMbps < uplinkMinSpeedMbps {
+		findings = append(findings, fmt.Sprintf("negotiated %d Mbps", st.Eth0SpeedMbps))
+	}
+	if !st.Eth0FullDuplex {
+		findings = append(findings, "half duplex")
+	}
+	errors := st.Eth0RxErrors + st.Eth0TxErrors
+	packets := st.Eth0RxPackets + st.Eth0TxPackets
+	if errors >= uplinkMinErrors && packets > 0 && float64(errors)/float64(packets) > uplinkMaxErrorRatio {
+		findings = append(findings, fmt.Sprintf("%.2f%% errors", float64(errors)/float64(packets)*100))
+	}
+	return findings
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+func TestAuditAPUplink(t *testing.T) {
+	tests := []struct {
+		name string
+		st   vendors.DeviceStats
+		want []string // substrings, in order
+	}{
+		{
+			name: "healthy gigabit",
+			st:   vendors.DeviceStats{Eth0Up: true, Eth0SpeedMbps: 1000, Eth0FullDuplex: true, Eth0RxPackets: 1e6, Eth0RxErrors: 5},
+		},
+		{
+			name: "multigig is fine",
+			st:   vendors.DeviceStats{Eth0Up: true, Eth0SpeedMbps: 2500, Eth0FullDuplex: true},
+		},
+		{
+			name: "100 Mbps",
+			st:   vendors.DeviceStats{Eth0Up: true, Eth0SpeedMbps: 100, Eth0FullDuplex: true},
+			want: []string{"100 Mbps"},
+		},
+		{
+			name: "half duplex with errors",
+			st:   vendors.DeviceStats{Eth0Up: true, Eth0SpeedMbps: 1000, Eth0RxPackets: 100000, Eth0RxErrors: 400, Eth0TxErrors: 100},
+			want: []string{"half duplex", "0.50% errors"},
+		},
+		{
+			name: "few errors below floor",
+			st:   vendors.DeviceStats{Eth0Up: true, Eth0SpeedMbps: 1000, Eth0FullDuplex: true, Eth0RxPackets: 1000, Eth0RxErrors: 50},
+		},
+		{
+			name: "link down",
+			st:   vendors.DeviceStats{Eth0SpeedMbps: 1000, Eth0RxPackets: 10},
+			want: []string{"eth0 down"},
+		},
+		{
+			name: "no eth0 data",
+			st:   vendors.DeviceStats{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := tt.st
+			got := auditAPUplink(&st)
+			if len(got) != len(tt.want) {
+				t.Fatalf("findings = %v, want %v", got, tt.want)
+			}
+			for i, sub := range tt.want {
+				if !strings.Contains(got[i], sub) {
+					t.Errorf("finding %d = %q, want substring %q", i, got[i], sub)
+				}
+			}
+		})
+	}
+}
+
+func TestAuditUplinksOrdering(t *testing.T) {
+	results := auditUplinks([]*vendors.DeviceStats{
+		{Type: "ap", Name: "ap-a", Eth0Up: true, Eth0SpeedMbps: 1000, Eth0FullDuplex: true},
+		{Type: "ap", Name: "ap-b", Eth0Up: true, Eth0SpeedMbps: 100, Eth0FullDuplex: true},
+		{Type: "switch", Name: "sw-1"},
+	})
+	if len(results) != 2 || results[0].Name != "ap-b" {
+		t.Errorf("flagged APs should sort first and switches be skipped: %+v", results)
+	}
+}
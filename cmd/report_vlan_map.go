@@ -0,0 +1,505 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/formatter"
+	"github.com/ravinald/wifimgr/internal/symbols"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+// reportVLANMapCmd represents the "report vlan-map" command
+var reportVLANMapCmd = &cobra.Command{
+	Use:   "vlan-map <site-name> [target <api-label>] [all] [format json|csv]",
+	Short: "Cross-check SSID VLANs against AP switch port trunks and gateway networks",
+	Long: `Map each SSID's VLAN at a site to the switch ports feeding the APs and to
+the gateway networks, and flag SSIDs whose VLAN is not carried to every AP
+port. Clients on such an SSID associate but never get an address.
+
+AP ports are switch ports whose port usage (port profile) is named "ap" or
+contains "ap" as a word, e.g. "ap", "ap-trunk", "mist_ap". A port carries the
+VLANs of its usage: the native port_network plus networks, or every VLAN with
+all_networks. Network names resolve to VLAN IDs through the networks defined
+in the site's cached switch and gateway configs.
+
+Everything is read from the cache; run 'refresh' first. Port usages and
+networks inherited from a switch template are not part of the device config,
+so ports using them are listed as unresolved rather than flagged.
+
+Arguments:
+  site-name        Required. Site to audit
+  target <label>   Optional. API owning the site (when the name is ambiguous)
+  all              Optional. Include disabled SSIDs
+  format           Optional. "json" or "csv" (default: table)`,
+	Example: `  wifimgr report vlan-map US-LAB-01
+  wifimgr report vlan-map US-LAB-01 format json`,
+	Args: func(_ *cobra.Command, args []string) error {
+		if cmdutils.ContainsHelp(args) {
+			return nil
+		}
+		if len(args) < 1 {
+			return fmt.Errorf("requires a site name")
+		}
+		return nil
+	},
+	RunE: runReportVLANMap,
+}
+
+func init() {
+	reportCmd.AddCommand(reportVLANMapCmd)
+}
+
+func runReportVLANMap(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+
+	parsed, err := cmdutils.ParseReportArgs(args)
+	if err != nil {
+		return err
+	}
+	if parsed.SiteName == "" {
+		return fmt.Errorf("requires a site name")
+	}
+
+	deps := currentDeps()
+	ref, err := deps.ResolveSite(parsed.SiteName, parsed.Target)
+	if err != nil {
+		return err
+	}
+	if deps.Cache == nil {
+		return fmt.Errorf("cache not initialized")
+	}
+
+	var switches []*vendors.SwitchConfig
+	for _, item := range deps.Cache.GetDevicesBySite(ref.SiteID, "switch") {
+		if cfg, err := deps.Cache.GetSwitchConfigByMAC(item.MAC); err == nil {
+			switches = append(switches, cfg)
+		}
+	}
+	var gateways []*vendors.GatewayConfig
+	for _, item := range deps.Cache.GetDevicesBySite(ref.SiteID, "gateway") {
+		if cfg, err := deps.Cache.GetGatewayConfigByMAC(item.MAC); err == nil {
+			gateways = append(gateways, cfg)
+		}
+	}
+	var wlans []*vendors.WLAN
+	for _, w := range deps.Cache.GetWLANsBySite(ref.SiteID) {
+		if w.Enabled || parsed.All {
+			wlans = append(wlans, w)
+		}
+	}
+
+	report := buildVLANMap(ref.Name, wlans, switches, gateways)
+
+	if parsed.Format == "json" {
+		out, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal report: %w", err)
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+
+	if len(report.SSIDs) > 0 {
+		rows := make([]formatter.GenericTableData, 0, len(report.SSIDs))
+		for _, s := range report.SSIDs {
+			vlan := "untagged"
+			if s.VLAN > 0 {
+				vlan = strconv.Itoa(s.VLAN)
+			}
+			ports := "-"
+			if len(report.APPorts) > 0 && s.VLAN > 0 {
+				ports = fmt.Sprintf("%d/%d", s.TrunkedPorts, len(report.APPorts))
+			}
+			rows = append(rows, formatter.GenericTableData{
+				"ssid":     s.SSID,
+				"vlan":     vlan,
+				"ports":    ports,
+				"gateway":  strings.Join(s.GatewayNetworks, ", "),
+				"findings": strings.Join(s.Findings, "; "),
+			})
+		}
+		printer := formatter.NewGenericTablePrinter(formatter.TableConfig{
+			Title:         fmt.Sprintf("SSID VLAN Map — %s (%d)", ref.Name, len(rows)),
+			Format:        parsed.Format,
+			BoldHeaders:   true,
+			ShowSeparator: true,
+			CommandPath:   "report.vlan-map",
+			Columns: []formatter.TableColumn{
+				{Field: "ssid", Title: "SSID"},
+				{Field: "vlan", Title: "VLAN"},
+				{Field: "ports", Title: "AP Ports"},
+				{Field: "gateway", Title: "Gateway Network"},
+				{Field: "findings", Title: "Findings"},
+			},
+		}, rows)
+		fmt.Print(printer.Print())
+	}
+
+	if parsed.Format != "table" {
+		return nil
+	}
+	for _, p := range report.APPorts {
+		if len(p.Unresolved) > 0 {
+			fmt.Printf("%s %s %s: network(s) %s have no VLAN ID in the cached configs\n",
+				symbols.WarningPrefix(), p.Switch, p.Port, strings.Join(p.Unresolved, ", "))
+		}
+	}
+	switch {
+	case len(report.SSIDs) == 0:
+		fmt.Printf("%s No SSIDs cached for %s\n", symbols.WarningPrefix(), ref.Name)
+	case len(report.APPorts) == 0:
+		fmt.Printf("%s No AP ports found in the cached switch configs at %s; nothing to check SSID VLANs against\n",
+			symbols.WarningPrefix(), ref.Name)
+	case report.Flagged > 0:
+		fmt.Printf("%s %d SSID(s) have a VLAN missing from AP switch ports or gateway networks\n",
+			symbols.WarningPrefix(), report.Flagged)
+	default:
+		fmt.Printf("%s Every SSID VLAN at %s is carried to the AP ports\n", symbols.SuccessPrefix(), ref.Name)
+	}
+	return nil
+}
+
+// vlanMapReport is the vlan-map outcome for one site.
+type vlanMapReport struct {
+	Site            string           `json:"site"`
+	SSIDs           []vlanMapSSID    `json:"ssids"`
+	APPorts         []vlanMapPort    `json:"ap_ports"`
+	GatewayNetworks []vlanMapNetwork `json:"gateway_networks"`
+	Flagged         int              `json:"flagged"`
+}
+
+// vlanMapSSID is one SSID and where its VLAN is carried.
+type vlanMapSSID struct {
+	SSID            string   `json:"ssid"`
+	VLAN            int      `json:"vlan"` // 0: untagged on the AP's native VLAN
+	Enabled         bool     `json:"enabled"`
+	TrunkedPorts    int      `json:"trunked_ports"`
+	MissingPorts    []string `json:"missing_ports,omitempty"` // switch:port
+	GatewayNetworks []string `json:"gateway_networks,omitempty"`
+	Findings        []string `json:"findings,omitempty"`
+}
+
+// vlanMapPort is one AP-facing switch port and the VLANs it carries.
+type vlanMapPort struct {
+	Switch     string   `json:"switch"`
+	Port       string   `json:"port"`
+	Usage      string   `json:"usage,omitempty"`
+	AllVLANs   bool     `json:"all_vlans,omitempty"`
+	VLANs      []int    `json:"vlans,omitempty"`
+	Unresolved []string `json:"unresolved,omitempty"` // network names with no known VLAN ID
+}
+
+// vlanMapNetwork is one network defined on a gateway.
+type vlanMapNetwork struct {
+	Gateway string `json:"gateway"`
+	Name    string `json:"name"`
+	VLAN    int    `json:"vlan"`
+}
+
+// carries reports whether the port passes vlan.
+func (p vlanMapPort) carries(vlan int) bool {
+	if p.AllVLANs {
+		return true
+	}
+	for _, v := range p.VLANs {
+		if v == vlan {
+			return true
+		}
+	}
+	return false
+}
+
+// buildVLANMap cross-references the SSIDs' VLANs with the AP ports found in
+// the switch configs and the networks found in the gateway configs.
+func buildVLANMap(site string, wlans []*vendors.WLAN, switches []*vendors.SwitchConfig, gateways []*vendors.GatewayConfig) *vlanMapReport {
+	report := &vlanMapReport{Site: site, SSIDs: []vlanMapSSID{}, APPorts: []vlanMapPort{}, GatewayNetworks: []vlanMapNetwork{}}
+
+	// Network names resolve site-wide: a switch often trunks a network that
+	// only the gateway (or another switch) defines.
+	names := make(map[string]int)
+	for _, sw := range switches {
+		for name, vlan := range configNetworks(sw.Config) {
+			names[name] = vlan
+		}
+	}
+	for _, gw := range gateways {
+		nets := configNetworks(gw.Config)
+		for _, name := range sortedNetworkNames(nets) {
+			names[name] = nets[name]
+			report.GatewayNetworks = append(report.GatewayNetworks, vlanMapNetwork{Gateway: deviceConfigName(gw.Name, gw.MAC), Name: name, VLAN: nets[name]})
+		}
+	}
+
+	for _, sw := range switches {
+		report.APPorts = append(report.APPorts, switchAPPorts(deviceConfigName(sw.Name, sw.MAC), sw.Config, names)...)
+	}
+	sort.Slice(report.APPorts, func(i, j int) bool {
+		a, b := report.APPorts[i], report.APPorts[j]
+		if a.Switch != b.Switch {
+			return a.Switch < b.Switch
+		}
+		return a.Port < b.Port
+	})
+
+	for _, w := range wlans {
+		s := vlanMapSSID{SSID: w.SSID, VLAN: w.VLANID, Enabled: w.Enabled}
+		if s.VLAN > 0 {
+			for _, p := range report.APPorts {
+				switch {
+				case p.carries(s.VLAN):
+					s.TrunkedPorts++
+				case len(p.Unresolved) == 0:
+					s.MissingPorts = append(s.MissingPorts, p.Switch+":"+p.Port)
+				}
+			}
+			if len(s.MissingPorts) > 0 {
+				s.Findings = append(s.Findings, fmt.Sprintf("VLAN %d not trunked on %s", s.VLAN, strings.Join(s.MissingPorts, ", ")))
+			}
+			for _, n := range report.GatewayNetworks {
+				if n.VLAN == s.VLAN {
+					s.GatewayNetworks = append(s.GatewayNetworks, n.Name)
+				}
+			}
+			if len(report.GatewayNetworks) > 0 && len(s.GatewayNetworks) == 0 {
+				s.Findings = append(s.Findings, fmt.Sprintf("no gateway network for VLAN %d", s.VLAN))
+			}
+		}
+		if len(s.Findings) > 0 {
+			report.Flagged++
+		}
+		report.SSIDs = append(report.SSIDs, s)
+	}
+	sort.SliceStable(report.SSIDs, func(i, j int) bool {
+		fi, fj := len(report.SSIDs[i].Findings) > 0, len(report.SSIDs[j].Findings) > 0
+		if fi != fj {
+			return fi
+		}
+		return report.SSIDs[i].SSID < report.SSIDs[j].SSID
+	})
+	return report
+}
+
+// switchAPPorts returns the AP-facing ports in one switch config. A port's
+// own keys override its usage's, as the vendor applies them.
+func switchAPPorts(switchName string, cfg map[string]interface{}, names map[string]int) []vlanMapPort {
+	ports, _ := cfg["port_config"].(map[string]interface{})
+	usages, _ := cfg["port_usages"].(map[string]interface{})
+
+	var out []vlanMapPort
+	for portName, raw := range ports {
+		port, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		usage, _ := port["usage"].(string)
+		if !isAPPortUsage(usage) {
+			continue
+		}
+		profile := make(map[string]interface{})
+		if u, ok := usages[usage].(map[string]interface{}); ok {
+			for k, v := range u {
+				profile[k] = v
+			}
+		}
+		for k, v := range port {
+			profile[k] = v
+		}
+		p := vlanMapPort{Switch: switchName, Port: portName, Usage: usage}
+		resolvePortVLANs(&p, profile, names)
+		out = append(out, p)
+	}
+	return out
+}
+
+// resolvePortVLANs fills in the VLANs a port profile carries. It reads the
+// Mist shape (port_network, networks, all_networks), a plain vlans list, and
+// the Meraki shape (vlan, allowedVlans).
+func resolvePortVLANs(p *vlanMapPort, profile map[string]interface{}, names map[string]int) {
+	seen := make(map[int]bool)
+	add := func(v int) {
+		if v > 0 && !seen[v] {
+			seen[v] = true
+			p.VLANs = append(p.VLANs, v)
+		}
+	}
+	addName := func(name string) {
+		if name == "" {
+			return
+		}
+		if v, ok := names[name]; ok {
+			add(v)
+			return
+		}
+		p.Unresolved = append(p.Unresolved, name)
+	}
+
+	if net, ok := profile["port_network"].(string); ok {
+		addName(net)
+	}
+	mode, _ := profile["mode"].(string)
+	if mode != "access" {
+		if all, _ := profile["all_networks"].(bool); all {
+			p.AllVLANs = true
+		}
+		if list, ok := profile["networks"].([]interface{}); ok {
+			for _, item := range list {
+				if name, ok := item.(string); ok {
+					addName(name)
+				}
+			}
+		}
+		if allowed, ok := profile["allowedVlans"].(string); ok {
+			all, vlans := parseVLANList(allowed)
+			p.AllVLANs = p.AllVLANs || all
+			for _, v := range vlans {
+				add(v)
+			}
+		}
+	}
+	if list, ok := profile["vlans"].([]interface{}); ok {
+		for _, item := range list {
+			add(vlanNumber(item))
+		}
+	}
+	add(vlanNumber(profile["vlan"]))
+
+	if p.Usage != "" && len(p.VLANs) == 0 && !p.AllVLANs && len(p.Unresolved) == 0 {
+		// A usage with no VLAN settings on the device is defined in a
+		// switch template the cache does not hold.
+		p.Unresolved = append(p.Unresolved, "usage "+p.Usage)
+	}
+	sort.Ints(p.VLANs)
+	sort.Strings(p.Unresolved)
+}
+
+// isAPPortUsage reports whether a port usage name marks an AP port: "ap", or
+// "ap" as one word of a name split on - and _.
+func isAPPortUsage(usage string) bool {
+	for _, word := range strings.FieldsFunc(strings.ToLower(usage), func(r rune) bool { return r == '-' || r == '_' }) {
+		if word == "ap" || word == "aps" {
+			return true
+		}
+	}
+	return false
+}
+
+// configNetworks returns the network name -> VLAN ID map in a device config:
+// a Mist "networks" object keyed by name, a list of {name, vlan_id} objects,
+// or Meraki appliance "vlans" ({id, name}).
+func configNetworks(cfg map[string]interface{}) map[string]int {
+	out := make(map[string]int)
+	switch nets := cfg["networks"].(type) {
+	case map[string]interface{}:
+		for name, raw := range nets {
+			if n, ok := raw.(map[string]interface{}); ok {
+				if v := vlanNumber(n["vlan_id"]); v > 0 {
+					out[name] = v
+				}
+			}
+		}
+	case []interface{}:
+		for _, raw := range nets {
+			if n, ok := raw.(map[string]interface{}); ok {
+				name, _ := n["name"].(string)
+				if v := vlanNumber(n["vlan_id"]); name != "" && v > 0 {
+					out[name] = v
+				}
+			}
+		}
+	}
+	if vlans, ok := cfg["vlans"].([]interface{}); ok {
+		for _, raw := range vlans {
+			if n, ok := raw.(map[string]interface{}); ok {
+				name, _ := n["name"].(string)
+				if v := vlanNumber(n["id"]); name != "" && v > 0 {
+					out[name] = v
+				}
+			}
+		}
+	}
+	return out
+}
+
+// vlanNumber reads a VLAN ID given as a JSON number or a numeric string.
+func vlanNumber(v interface{}) int {
+	switch n := v.(type) {
+	case float64:
+		return int(n)
+	case int:
+		return n
+	case string:
+		id, _ := strconv.Atoi(strings.TrimSpace(n))
+		return id
+	}
+	return 0
+}
+
+// parseVLANList parses a Meraki allowed-VLANs string such as "1,10-20" or
+// "all".
+func parseVLANList(s string) (all bool, vlans []int) {
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if strings.EqualFold(part, "all") {
+			return true, nil
+		}
+		lo, hi, isRange := strings.Cut(part, "-")
+		a, err := strconv.Atoi(strings.TrimSpace(lo))
+		if err != nil {
+			continue
+		}
+		b := a
+		if isRange {
+			if b, err = strconv.Atoi(strings.TrimSpace(hi)); err != nil || b < a {
+				continue
+			}
+			if a <= 1 && b >= 4094 {
+				return true, nil
+			}
+		}
+		for v := a; v <= b; v++ {
+			vlans = append(vlans, v)
+		}
+	}
+	return false, vlans
+}
+
+func sortedNetworkNames(nets map[string]int) []string {
+	out := make([]string, 0, len(nets))
+	for name := range nets {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// deviceConfigName labels a cached device config by name, else MAC.
+func deviceConfigName(name, mac string) string {
+	if name != "" {
+		return name
+	}
+	return mac
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+func TestBuildVLANMap(t *testing.T) {
+	switches := []*vendors.SwitchConfig{{
+		Name: "sw-1",
+		MAC:  "aa0000000010",
+		Config: map[string]interface{}{
+			"networks": map[string]interface{}{
+				"mgmt":  map[string]interface{}{"vlan_id": float64(10)},
+				"corp":  map[string]interface{}{"vlan_id": "20"},
+				"guest": map[string]interface{}{"vlan_id": float64(30)},
+			},
+			"port_usages": map[string]interface{}{
+				"ap":       map[string]interface{}{"mode": "trunk", "port_network": "mgmt", "networks": []interface{}{"corp", "guest"}},
+				"ap-lobby": map[string]interface{}{"mode": "trunk", "port_network": "mgmt", "networks": []interface{}{"corp"}},
+			},
+			"port_config": map[string]interface{}{
+				"ge-0/0/1": map[string]interface{}{"usage": "ap"},
+				"ge-0/0/2": map[string]interface{}{"usage": "ap-lobby"},
+				"ge-0/0/3": map[string]interface{}{"usage": "mist_ap_tmpl"}, // defined in a template only
+				"ge-0/0/4": map[string]interface{}{"usage": "uplink"},
+			},
+		},
+	}}
+	gateways := []*vendors.GatewayConfig{{
+		Name:   "gw-1",
+		Config: map[string]interface{}{"networks": []interface{}{map[string]interface{}{"name": "corp", "vlan_id": float64(20)}}},
+	}}
+	wlans := []*vendors.WLAN{
+		{SSID: "Corp", VLANID: 20, Enabled: true},
+		{SSID: "Guest", VLANID: 30, Enabled: true},
+		{SSID: "IoT", Enabled: true},
+	}
+
+	r := buildVLANMap("US-LAB-01", wlans, switches, gateways)
+	if len(r.APPorts) != 3 {
+		t.Fatalf("ap ports = %+v, want the uplink port skipped", r.APPorts)
+	}
+	if p := r.APPorts[0]; p.Port != "ge-0/0/1" || fmt.Sprint(p.VLANs) != "[10 20 30]" {
+		t.Errorf("ge-0/0/1 = %+v", p)
+	}
+	if p := r.APPorts[2]; strings.Join(p.Unresolved, ",") != "usage mist_ap_tmpl" {
+		t.Errorf("template-only usage should be unresolved: %+v", p)
+	}
+
+	if r.Flagged != 1 || r.SSIDs[0].SSID != "Guest" {
+		t.Fatalf("ssids = %+v, want Guest flagged first", r.SSIDs)
+	}
+	guest := r.SSIDs[0]
+	if guest.TrunkedPorts != 1 || strings.Join(guest.MissingPorts, ",") != "sw-1:ge-0/0/2" {
+		t.Errorf("guest = %+v", guest)
+	}
+	if !strings.Contains(strings.Join(guest.Findings, ";"), "no gateway network for VLAN 30") {
+		t.Errorf("guest findings = %v", guest.Findings)
+	}
+	if corp := r.SSIDs[1]; corp.TrunkedPorts != 2 || len(corp.Findings) != 0 || strings.Join(corp.GatewayNetworks, ",") != "corp" {
+		t.Errorf("corp = %+v", corp)
+	}
+	if iot := r.SSIDs[2]; iot.VLAN != 0 || len(iot.Findings) != 0 {
+		t.Errorf("untagged SSID should not be checked: %+v", iot)
+	}
+}
+
+func TestVLANMapHelpers(t *testing.T) {
+	for usage, want := range map[string]bool{"ap": true, "AP-Trunk": true, "mist_aps": true, "trap": false, "uplink": false, "": false} {
+		if got := isAPPortUsage(usage); got != want {
+			t.Errorf("isAPPortUsage(%q) = %v", usage, got)
+		}
+	}
+	if all, vlans := parseVLANList("1, 10-12,bad,20"); all || fmt.Sprint(vlans) != "[1 10 11 12 20]" {
+		t.Errorf("parseVLANList = %v %v", all, vlans)
+	}
+	for _, s := range []string{"all", "1-4094"} {
+		if all, _ := parseVLANList(s); !all {
+			t.Errorf("parseVLANList(%q) should be all", s)
+		}
+	}
+
+	p := vlanMapPort{Usage: "ap"}
+	resolvePortVLANs(&p, map[string]interface{}{"type": "trunk", "vlan": float64(10), "allowedVlans": "10,30-31"}, nil)
+	if fmt.Sprint(p.VLANs) != "[10 30 31]" || p.AllVLANs {
+		t.Errorf("meraki-shaped port = %+v", p)
+	}
+	p = vlanMapPort{Usage: "ap"}
+	resolvePortVLANs(&p, map[string]interface{}{"mode": "access", "port_network": "mgmt", "all_networks": true}, map[string]int{"mgmt": 10})
+	if p.AllVLANs || fmt.Sprint(p.VLANs) != "[10]" {
+		t.Errorf("access port should carry only its port network: %+v", p)
+	}
+	if nets := configNetworks(map[string]interface{}{"vlans": []interface{}{map[string]interface{}{"id": "40", "name": "voice"}}}); nets["voice"] != 40 {
+		t.Errorf("meraki vlans = %v", nets)
+	}
+}
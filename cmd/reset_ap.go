@@ -17,6 +17,7 @@ import (
 	"golang.org/x/term"
 
 	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/i18n"
 	"github.com/ravinald/wifimgr/internal/vendors"
 )
 
@@ -140,7 +141,7 @@ func confirmReboot(apName, siteLabel, apiLabel, vendor string) (bool, error) {
 		return false, fmt.Errorf("stdin is not a terminal — pass 'force' to skip confirmation")
 	}
 
-	fmt.Printf("Reboot AP %q at site %q via %s (%s)? [y/N] ", apName, siteLabel, apiLabel, vendor)
+	fmt.Printf("%s %s ", i18n.T("reset_ap.confirm", apName, siteLabel, apiLabel, vendor), i18n.T("prompt.yes_no"))
 
 	reader := bufio.NewReader(os.Stdin)
 	line, err := reader.ReadString('\n')
@@ -148,8 +149,7 @@ func confirmReboot(apName, siteLabel, apiLabel, vendor string) (bool, error) {
 		return false, fmt.Errorf("failed to read confirmation: %w", err)
 	}
 
-	answer := strings.ToLower(strings.TrimSpace(line))
-	return answer == "y" || answer == "yes", nil
+	return i18n.IsYes(line), nil
 }
 
 // renderResetError converts an error from the vendor layer into a
@@ -29,7 +29,9 @@ import (
 
 	"github.com/ravinald/wifimgr/api"
 	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/common"
 	"github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/i18n"
 	"github.com/ravinald/wifimgr/internal/logging"
 	"github.com/ravinald/wifimgr/internal/symbols"
 	"github.com/ravinald/wifimgr/internal/xdg"
@@ -71,11 +73,16 @@ var (
 	useEnvFile      bool
 	configFile      string
 	caseInsensitive bool
-	suppressOutput  bool // --suppress: suppress SDK debug output
-	noColor         bool // --no-color: disable styled output
-	quiet           bool // -q/--quiet: suppress non-essential output
-	assumeYes       bool // -y/--yes: auto-approve confirmations
-	noInput         bool // --no-input: never prompt (fail closed)
+	suppressOutput  bool   // --suppress: suppress SDK debug output
+	noColor         bool   // --no-color: disable styled output
+	plainOutput     bool   // --plain: screen-reader friendly output
+	anonymize       bool   // --anonymize: hash client MACs and hostnames in output
+	quiet           bool   // -q/--quiet: suppress non-essential output
+	assumeYes       bool   // -y/--yes: auto-approve confirmations
+	noInput         bool   // --no-input: never prompt (fail closed)
+	healthcheckURL  string // --healthcheck-url: ping start/success/failure for monitored runs
+	showStats       bool   // --stats: print API call, cache, and timing accounting at exit
+	tenant          string // --tenant: scope the run to one tenant's APIs, configs, and cache
 
 	// Temporary compatibility for command handlers during Viper migration
 	globalConfig *config.Config
@@ -102,14 +109,24 @@ For detailed usage information, run 'wifimgr help [command]'`,
 		if ctx := cmd.Context(); ctx != nil {
 			globalContext = ctx
 		}
+		if err := startHealthcheck(globalContext); err != nil {
+			return err
+		}
+		defer startStats()()
 
 		// Apply operational flags before any output or prompt. Color policy and
 		// quiet/confirmation behavior are process-level, so they take effect for
 		// every command regardless of init tier.
 		symbols.ConfigureColor(noColor)
+		symbols.SetPlain(plainOutput)
+		i18n.Configure("")
 		cmdutils.SetQuiet(quiet)
 		cmdutils.SetAssumeYes(assumeYes)
 		cmdutils.SetNoInput(noInput)
+		if tenant == "" {
+			tenant = os.Getenv("WIFIMGR_TENANT")
+		}
+		config.SetTenant(tenant)
 
 		// Determine initialization tier based on command annotations
 		tier := cmdutils.GetCommandTier(cmd.Annotations)
@@ -130,24 +147,41 @@ For detailed usage information, run 'wifimgr help [command]'`,
 		}
 
 		// Execute appropriate initialization based on tier
+		var err error
 		switch tier {
 		case cmdutils.TierNoInit:
 			return nil
 		case cmdutils.TierConfigOnly:
-			return initializeConfig(cmd)
+			err = initializeConfig(cmd)
 		default:
-			return initializeApplication(cmd)
+			err = initializeApplication(cmd)
+		}
+		if err != nil {
+			return err
 		}
+		applyCommandTimeout(cmd)
+		return nil
 	},
 }
 
 // Execute runs the root Cobra command with the provided context. The context
 // is surfaced to every RunE via cmd.Context() and captured into globalContext
 // in PersistentPreRunE so existing call sites get cancellation for free.
-// Returns the command error (or nil); main owns the exit code.
+// Returns the command error (or nil); main owns the exit code. When the
+// operator has opted in, the run is recorded for usage telemetry. With
+// --healthcheck-url the run's outcome is pinged to the monitor, and with
+// --stats the run's API and cache accounting is printed. A command that runs
+// out its timeout says which setting to raise.
 func Execute(ctx context.Context) error {
 	defer logging.Cleanup()
-	return rootCmd.ExecuteContext(ctx)
+	start := time.Now()
+	cmd, err := rootCmd.ExecuteContextC(ctx)
+	err = explainCommandTimeout(err)
+	cancelCommandTimeout()
+	recordTelemetry(ctx, cmd, start, err)
+	finishHealthcheck(ctx, cmd, start, err)
+	finishStats()
+	return err
 }
 
 // initializeConfig initializes Viper configuration and logging only.
@@ -158,6 +192,12 @@ func initializeConfig(cmd *cobra.Command) error {
 		return fmt.Errorf("failed to initialize Viper: %w", err)
 	}
 
+	// Re-resolve the locale now that display.locale is readable
+	i18n.Configure(viper.GetString("display.locale"))
+	common.ConfigureRedaction(viper.GetStringSlice("redaction.fields"), viper.GetStringSlice("redaction.allow"))
+	common.ConfigureAnonymization(anonymize || viper.GetBool("redaction.anonymize_clients"))
+	config.ConfigureWriteRetry()
+
 	// Handle cascading debug levels
 	opts := buildCLIOptions()
 
@@ -185,11 +225,38 @@ func initializeConfig(cmd *cobra.Command) error {
 		return err
 	}
 
+	// Config-only commands get the Files section; initializeAPI replaces
+	// this with the full config.
+	globalConfig = &config.Config{Files: filesConfigFromViper()}
+
 	logging.Info("Starting wifimgr (config-only mode)")
 
 	return nil
 }
 
+// filesConfigFromViper builds the Files section of the config, deriving the
+// cache path from cache_dir when files.cache is not explicitly set.
+func filesConfigFromViper() config.Files {
+	cachePath := viper.GetString("files.cache")
+	if cachePath == "" {
+		cacheDir := viper.GetString("files.cache_dir")
+		if cacheDir == "" {
+			cacheDir = xdg.GetCacheDir()
+		}
+		cachePath = cacheDir + "/cache.json"
+	}
+	return config.Files{
+		ConfigDir:   viper.GetString("files.config_dir"),
+		SiteConfigs: viper.GetStringSlice("files.site_configs"),
+		Templates:   viper.GetStringSlice("files.templates"),
+		Imports:     viper.GetStringSlice("files.imports"),
+		Cache:       cachePath,
+		Inventory:   viper.GetString("files.inventory"),
+		LogFile:     viper.GetString("files.log_file"),
+		Schemas:     viper.GetString("files.schemas"),
+	}
+}
+
 // initializeApplication initializes the application with configuration and API client.
 // This is the full initialization path for Tier 2 commands that need API access.
 func initializeApplication(cmd *cobra.Command) error {
@@ -280,7 +347,8 @@ func initializeAPI() error {
 		resultsLimit = 100
 	}
 
-	var apiToken, apiURL, apiOrgID, mistLabel string
+	var apiToken, apiURL, apiOrgID, mistLabel, pagination string
+	var pageSizes map[string]int
 
 	// Locate Mist credentials (if any) from the multi-vendor registry.
 	// globalClient is the legacy single-Mist api.Client used by commands
@@ -295,6 +363,7 @@ func initializeAPI() error {
 				apiURL = apiConfig.URL
 				apiOrgID = apiConfig.Credentials["org_id"]
 				mistLabel = label
+				pageSizes, pagination = apiConfig.PageSizes, apiConfig.Pagination
 				logging.Debugf("Using credentials from %s API for globalClient (URL: %s, OrgID: %s)",
 					label, apiURL, apiOrgID)
 				break
@@ -306,15 +375,8 @@ func initializeAPI() error {
 	debugEnabled := opts.DebugLevelInt > config.DebugNone ||
 		(viper.GetBool("logging.enable") && viper.GetString("logging.level") == "debug")
 
-	// Derive cache path from cache_dir if files.cache is not explicitly set
-	cachePath := viper.GetString("files.cache")
-	if cachePath == "" {
-		cacheDir := viper.GetString("files.cache_dir")
-		if cacheDir == "" {
-			cacheDir = xdg.GetCacheDir()
-		}
-		cachePath = cacheDir + "/cache.json"
-	}
+	files := filesConfigFromViper()
+	cachePath := files.Cache
 
 	// globalConfig is built unconditionally so commands that only read the
 	// Files section (config paths, cache, inventory) still work in
@@ -330,16 +392,7 @@ func initializeAPI() error {
 			ResultsLimit: viper.GetInt("api.results_limit"),
 			ManagedKeys:  getManagedKeysFromViper(),
 		},
-		Files: config.Files{
-			ConfigDir:   viper.GetString("files.config_dir"),
-			SiteConfigs: viper.GetStringSlice("files.site_configs"),
-			Templates:   viper.GetStringSlice("files.templates"),
-			Imports:     viper.GetStringSlice("files.imports"),
-			Cache:       cachePath,
-			Inventory:   viper.GetString("files.inventory"),
-			LogFile:     viper.GetString("files.log_file"),
-			Schemas:     viper.GetString("files.schemas"),
-		},
+		Files: files,
 	}
 
 	if apiToken == "" {
@@ -364,6 +417,9 @@ func initializeAPI() error {
 		api.WithInventory(viper.GetString("files.inventory")),
 		api.WithDryRun(opts.DryRun),
 		api.WithResultsLimit(resultsLimit),
+		api.WithPageSizes(pageSizes),
+		api.WithPagination(pagination),
+		api.WithDeviceCache(newDeviceCache(mistLabel)),
 	)
 
 	// Set global client
@@ -510,9 +566,19 @@ func init() {
 	rootCmd.PersistentFlags().BoolVar(&suppressOutput, "suppress", false,
 		"Suppress Meraki SDK debug output (workaround for github.com/meraki/dashboard-api-go issues #72 and #75)")
 	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "Disable colored output")
+	rootCmd.PersistentFlags().BoolVar(&plainOutput, "plain", false,
+		"Screen-reader friendly output: no color or box drawing, tables as key: value records")
+	rootCmd.PersistentFlags().BoolVar(&anonymize, "anonymize", false,
+		"Replace client MACs and hostnames with per-run pseudonyms in search and export output")
 	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Suppress non-essential output")
 	rootCmd.PersistentFlags().BoolVarP(&assumeYes, "yes", "y", false, "Assume yes to confirmation prompts")
 	rootCmd.PersistentFlags().BoolVar(&noInput, "no-input", false, "Never prompt; fail instead of asking")
+	rootCmd.PersistentFlags().StringVar(&healthcheckURL, "healthcheck-url", "",
+		"Ping this healthchecks.io-style URL when the run starts (/start), succeeds, or fails (/fail)")
+	rootCmd.PersistentFlags().BoolVar(&showStats, "stats", false,
+		"Print API calls per endpoint, bytes transferred, cache hits/misses, and phase timings at exit")
+	rootCmd.PersistentFlags().StringVar(&tenant, "tenant", "",
+		"Scope the run to one tenant's APIs, config directory, and cache (env: WIFIMGR_TENANT)")
 
 	// Bind the case-insensitive flag to viper
 	if err := viper.BindPFlag("case-insensitive", rootCmd.PersistentFlags().Lookup("case-insensitive")); err != nil {
@@ -0,0 +1,255 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/formatter"
+	"github.com/ravinald/wifimgr/internal/logging"
+	"github.com/ravinald/wifimgr/internal/schedule"
+	"github.com/ravinald/wifimgr/internal/symbols"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+// scheduleCmd represents the "schedule" command
+var scheduleCmd = &cobra.Command{
+	Use:   "schedule [show|run] [format json|csv] [window <duration>] [dry-run]",
+	Short: "Show or run operations scheduled in site-local time",
+	Long: `Show or run the operations defined under "schedule" in the main config.
+
+Each job gives a wall-clock time ("at": "02:00"). A job whose command mentions
+{site} runs once per matching site at that time in the site's own timezone,
+read from the API cache, so "2am" means 2am local at every site. Sites with no
+cached timezone, and jobs without {site}, use the job's "timezone" (default:
+this host's zone).
+
+'schedule run' fires every occurrence that has come due since the last run and
+is no older than the window, each as its own wifimgr process. Run it from cron
+at least as often as the window, e.g. every 15 minutes. Fired occurrences are
+recorded under the state directory so none fires twice.
+
+Arguments:
+  show               List each job and site with its next run (default)
+  run                Fire the occurrences that are due
+  format             Optional. "json" or "csv" (show only)
+  window <duration>  Optional. How late a run may still fire (default: 1h)
+  dry-run            Optional. List what run would fire without firing it`,
+	Example: `  wifimgr schedule
+  wifimgr schedule show format json
+  wifimgr schedule run
+  wifimgr schedule run dry-run`,
+	RunE: runSchedule,
+}
+
+func init() {
+	rootCmd.AddCommand(scheduleCmd)
+}
+
+func runSchedule(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+
+	action := "show"
+	format := "table"
+	window := schedule.DefaultWindow
+	dryRun := false
+	for i := 0; i < len(args); i++ {
+		switch arg := strings.ToLower(args[i]); arg {
+		case "show", "run":
+			action = arg
+		case "format":
+			if i+1 >= len(args) {
+				return fmt.Errorf("'format' requires a format type (json, csv)")
+			}
+			switch f := strings.ToLower(args[i+1]); f {
+			case "json", "csv":
+				format = f
+			default:
+				return fmt.Errorf("invalid format %q: must be 'json' or 'csv'", args[i+1])
+			}
+			i++
+		case "window":
+			if i+1 >= len(args) {
+				return fmt.Errorf("'window' requires a duration (e.g. 30m, 2h)")
+			}
+			d, err := time.ParseDuration(args[i+1])
+			if err != nil || d <= 0 {
+				return fmt.Errorf("invalid window %q: must be a positive duration such as 30m or 2h", args[i+1])
+			}
+			window = d
+			i++
+		case "dry-run", "dryrun":
+			dryRun = true
+		default:
+			return fmt.Errorf("unexpected argument: %s", args[i])
+		}
+	}
+
+	jobs, err := schedule.LoadJobs()
+	if err != nil {
+		return err
+	}
+	if len(jobs) == 0 {
+		fmt.Println("No scheduled operations; add them under \"schedule\" in the main config")
+		return nil
+	}
+
+	now := time.Now()
+	occs := schedule.Expand(jobs, scheduleSites(), now)
+	if action == "run" {
+		return runDueOperations(occs, now, window, dryRun)
+	}
+	return showSchedule(occs, format)
+}
+
+// scheduleSites lists cached sites with their timezones, one entry per name.
+func scheduleSites() []schedule.Site {
+	accessor := vendors.GetGlobalCacheAccessor()
+	if accessor == nil {
+		logging.Warnf("Cache not initialized; per-site jobs have no sites to run at")
+		return nil
+	}
+	byName := make(map[string]schedule.Site)
+	for _, s := range accessor.GetAllSites() {
+		if existing, ok := byName[s.Name]; ok && existing.Timezone != "" {
+			continue
+		}
+		byName[s.Name] = schedule.Site{Name: s.Name, Timezone: s.Timezone}
+	}
+	sites := make([]schedule.Site, 0, len(byName))
+	for _, s := range byName {
+		sites = append(sites, s)
+	}
+	sort.Slice(sites, func(i, j int) bool { return sites[i].Name < sites[j].Name })
+	return sites
+}
+
+func showSchedule(occs []schedule.Occurrence, format string) error {
+	if format == "json" {
+		out, err := json.MarshalIndent(occs, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal schedule: %w", err)
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+
+	rows := make([]formatter.GenericTableData, 0, len(occs))
+	for _, o := range occs {
+		next := ""
+		if !o.Next.IsZero() {
+			next = o.Next.Format("Mon 2006-01-02 15:04")
+		}
+		rows = append(rows, formatter.GenericTableData{
+			"job":     o.Job,
+			"site":    o.Site,
+			"zone":    o.Zone,
+			"at":      o.At,
+			"next":    next,
+			"here":    o.Next.Local().Format("Mon 15:04 MST"),
+			"command": strings.Join(o.Args, " "),
+		})
+	}
+	printer := formatter.NewGenericTablePrinter(formatter.TableConfig{
+		Title:         fmt.Sprintf("Scheduled Operations (%d)", len(occs)),
+		Format:        format,
+		BoldHeaders:   true,
+		ShowSeparator: true,
+		CommandPath:   "schedule",
+		Columns: []formatter.TableColumn{
+			{Field: "job", Title: "Job"},
+			{Field: "site", Title: "Site"},
+			{Field: "zone", Title: "Timezone"},
+			{Field: "at", Title: "At"},
+			{Field: "next", Title: "Next (site-local)"},
+			{Field: "here", Title: "Next (here)"},
+			{Field: "command", Title: "Command"},
+		},
+	}, rows)
+	fmt.Print(printer.Print())
+	return nil
+}
+
+// runDueOperations fires each due occurrence as a child wifimgr process and
+// records it, so a failed operation is reported but not retried on the next
+// cron tick.
+func runDueOperations(occs []schedule.Occurrence, now time.Time, window time.Duration, dryRun bool) error {
+	state, err := schedule.LoadState()
+	if err != nil {
+		return err
+	}
+	due := schedule.Due(occs, state, now, window)
+	if len(due) == 0 {
+		cmdutils.Noticef("No scheduled operations are due")
+		return nil
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("cannot locate the wifimgr binary: %w", err)
+	}
+	failed := 0
+	for _, o := range due {
+		label := o.Key()
+		when := o.Previous.Format("15:04 MST")
+		if dryRun {
+			fmt.Printf("Would run %s (due %s): wifimgr %s\n", label, when, strings.Join(o.Args, " "))
+			continue
+		}
+
+		fmt.Printf("Running %s (due %s): wifimgr %s\n", label, when, strings.Join(o.Args, " "))
+		child := exec.CommandContext(globalContext, exe, scheduledArgs(o.Args)...) // #nosec G204 -- args from operator-controlled config
+		child.Stdout, child.Stderr = os.Stdout, os.Stderr
+		if err := child.Run(); err != nil {
+			failed++
+			fmt.Printf("%s %s failed: %v\n", symbols.FailurePrefix(), label, err)
+		} else {
+			fmt.Printf("%s %s done\n", symbols.SuccessPrefix(), label)
+		}
+
+		state[label] = o.Previous
+		if err := schedule.SaveState(state); err != nil {
+			return fmt.Errorf("failed to record scheduled run: %w", err)
+		}
+		if globalContext.Err() != nil {
+			return globalContext.Err()
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d scheduled operation(s) failed", failed, len(due))
+	}
+	return nil
+}
+
+// scheduledArgs runs a job unattended with this run's config file.
+func scheduledArgs(args []string) []string {
+	out := []string{"--no-input"}
+	if configFile != "" {
+		out = append(out, "--config", configFile)
+	}
+	return append(out, args...)
+}
@@ -0,0 +1,405 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/ravinald/wifimgr/cmd/apply"
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/formatter"
+	"github.com/ravinald/wifimgr/internal/logging"
+	"github.com/ravinald/wifimgr/internal/readiness"
+	"github.com/ravinald/wifimgr/internal/validation"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+// scoreCmd represents the "score" command
+var scoreCmd = &cobra.Command{
+	Use:   "score <site[,site...]|@group|all> [detail] [format json|csv]",
+	Short: "Readiness score (0-100) for one or many sites",
+	Long: `Score how ready a site is, 0-100, from four categories:
+
+  validation (35%)  lint config errors (-20 each) and warnings (-5 each)
+  drift      (25%)  share of devices in intent that are in the API inventory
+                    with a cached config matching intent
+  security   (25%)  WLAN findings: open WLANs (-30), PSKs stored in clear
+                    text (-30), WPA2-only PSK WLANs (-10)
+  naming     (15%)  share of devices with a unique name, matching
+                    score.naming_pattern (a regular expression) when set
+
+The site score is the weighted average. Nothing is changed and no API is
+called: drift is judged against the cache, so run 'wifimgr refresh' first.
+
+One site prints the category breakdown; several print one row per site,
+lowest score first.
+
+Arguments:
+  sites         Required. site[,site...], @group from site_groups, or all
+  detail        Optional. List each finding behind the deductions
+  format        Optional. "json" or "csv" (default: table)`,
+	Example: `  wifimgr score US-LAB-01
+  wifimgr score US-LAB-01 detail
+  wifimgr score all
+  wifimgr score @retail format csv`,
+	Args: func(_ *cobra.Command, args []string) error {
+		if cmdutils.ContainsHelp(args) {
+			return nil
+		}
+		if len(args) < 1 {
+			return fmt.Errorf("requires a site, a site list, @group, or all")
+		}
+		return nil
+	},
+	RunE: runScore,
+}
+
+func init() {
+	rootCmd.AddCommand(scoreCmd)
+}
+
+func runScore(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+	detail := false
+	format := "table"
+	for i := 1; i < len(args); i++ {
+		switch strings.ToLower(args[i]) {
+		case "detail":
+			detail = true
+		case "format":
+			if i+1 >= len(args) {
+				return fmt.Errorf("'format' requires a format type (json, csv)")
+			}
+			i++
+			format = strings.ToLower(args[i])
+			if format != "json" && format != "csv" {
+				return fmt.Errorf("invalid format %q: must be 'json' or 'csv'", args[i])
+			}
+		default:
+			return fmt.Errorf("unexpected argument: %s", args[i])
+		}
+	}
+
+	var sites []string
+	var err error
+	if strings.EqualFold(args[0], "all") {
+		sites = configuredSiteNames()
+	} else if sites, err = cmdutils.ResolveSiteList(args[0]); err != nil {
+		return err
+	}
+	if len(sites) == 0 {
+		return fmt.Errorf("no sites to score")
+	}
+
+	var pattern *regexp.Regexp
+	if p := viper.GetString("score.naming_pattern"); p != "" {
+		if pattern, err = regexp.Compile(p); err != nil {
+			return fmt.Errorf("invalid score.naming_pattern: %w", err)
+		}
+	}
+	store, err := apply.LoadTemplateStore(globalConfig)
+	if err != nil {
+		return fmt.Errorf("failed to load templates: %w", err)
+	}
+	caches := scoreCaches()
+	if len(caches) == 0 {
+		return fmt.Errorf("no API cache loaded; run 'wifimgr refresh' first")
+	}
+
+	var results []readiness.Result
+	for _, site := range sites {
+		siteConfig, err := loadSiteConfiguration(site)
+		if err != nil {
+			return fmt.Errorf("site %s: %w", site, err)
+		}
+		in, err := scoreInput(site, siteConfig, store, caches)
+		if err != nil {
+			return fmt.Errorf("site %s: %w", site, err)
+		}
+		in.NamingPattern = pattern
+		results = append(results, readiness.Score(in))
+	}
+
+	if len(results) == 1 {
+		return printSiteScore(results[0], detail, format)
+	}
+	return printScoreSummary(results, detail, format)
+}
+
+// configuredSiteNames lists every site in files.site_configs by its
+// site_config name, falling back to its key.
+func configuredSiteNames() []string {
+	configDir := viper.GetString("files.config_dir")
+	var names []string
+	for _, file := range viper.GetStringSlice("files.site_configs") {
+		cfg, err := config.LoadSiteConfig(configDir, file)
+		if err != nil {
+			logging.Warnf("Skipping site config %s: %v", file, err)
+			continue
+		}
+		for key, obj := range cfg.Config.Sites {
+			name := obj.SiteConfig.Name
+			if name == "" {
+				name = key
+			}
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// scoreCaches returns the loaded API caches by label.
+func scoreCaches() map[string]*vendors.APICache {
+	caches := make(map[string]*vendors.APICache)
+	cacheMgr := GetCacheManager()
+	if cacheMgr == nil {
+		return caches
+	}
+	for _, apiLabel := range GetTargetAPIs() {
+		if cache, err := cacheMgr.GetAPICache(apiLabel); err == nil {
+			caches[apiLabel] = cache
+		}
+	}
+	return caches
+}
+
+// scoreInput gathers what a site is scored on: its lint results, its
+// devices checked against the caches, and the WLAN templates it broadcasts.
+func scoreInput(site string, siteConfig *config.SiteConfigObj, store *config.TemplateStore, caches map[string]*vendors.APICache) (readiness.Input, error) {
+	in := readiness.Input{Site: site}
+
+	linter := validation.NewConfigLinter(vendors.GetGlobalCacheAccessor())
+	linter.SetTemplateStore(store)
+	lint, err := linter.LintSite(site, siteConfig)
+	if err != nil {
+		return in, fmt.Errorf("linting failed: %w", err)
+	}
+	for _, issue := range lint.Errors {
+		in.LintErrors = append(in.LintErrors, lintIssueText(issue))
+	}
+	for _, issue := range lint.Warnings {
+		in.LintWarnings = append(in.LintWarnings, lintIssueText(issue))
+	}
+	sort.Strings(in.LintErrors)
+	sort.Strings(in.LintWarnings)
+
+	addDevice := func(mac, deviceType, name string) {
+		normalized := vendors.NormalizeMAC(mac)
+		d := readiness.Device{MAC: normalized, Type: deviceType, IntentName: name}
+		for _, cache := range caches {
+			if cacheInventory(cache, deviceType)[normalized] == nil {
+				continue
+			}
+			d.InCache = true
+			d.Drifted = hasConfigDrift(cache, normalized, deviceType, deviceIntent{Name: name})
+			break
+		}
+		in.Devices = append(in.Devices, d)
+	}
+	for mac, ap := range siteConfig.Devices.APs {
+		name := ""
+		if ap.APDeviceConfig != nil {
+			name = ap.APDeviceConfig.Name
+		}
+		addDevice(mac, "ap", name)
+	}
+	for mac, sw := range siteConfig.Devices.Switches {
+		addDevice(mac, "switch", sw.Name)
+	}
+	for mac, gw := range siteConfig.Devices.WanEdge {
+		addDevice(mac, "gateway", gw.Name)
+	}
+	sort.Slice(in.Devices, func(i, j int) bool {
+		if in.Devices[i].Type != in.Devices[j].Type {
+			return in.Devices[i].Type < in.Devices[j].Type
+		}
+		return in.Devices[i].MAC < in.Devices[j].MAC
+	})
+
+	in.WLANs = siteWLANTemplates(siteConfig, store)
+	return in, nil
+}
+
+func lintIssueText(issue validation.LintIssue) string {
+	who := issue.DeviceName
+	if who == "" {
+		who = issue.DeviceMAC
+	}
+	if who == "" {
+		return issue.Message
+	}
+	return who + ": " + issue.Message
+}
+
+// cacheInventory returns the cache's inventory map for a device type.
+func cacheInventory(cache *vendors.APICache, deviceType string) map[string]*vendors.InventoryItem {
+	switch deviceType {
+	case "ap":
+		return cache.Inventory.AP
+	case "switch":
+		return cache.Inventory.Switch
+	case "gateway":
+		return cache.Inventory.Gateway
+	}
+	return nil
+}
+
+// siteWLANTemplates returns the templates of the WLANs a site broadcasts —
+// its site-wide and per-AP labels, less those held disabled — with the site's
+// wlan_overrides laid over them. Labels without a template are left to lint.
+func siteWLANTemplates(siteConfig *config.SiteConfigObj, store *config.TemplateStore) map[string]map[string]any {
+	disabled := make(map[string]bool)
+	for _, label := range siteConfig.WLANDisabled {
+		disabled[label] = true
+	}
+	labels := append([]string{}, siteConfig.WLAN...)
+	for _, ap := range siteConfig.Devices.APs {
+		labels = append(labels, ap.WLANs...)
+	}
+
+	out := make(map[string]map[string]any)
+	for _, label := range labels {
+		if disabled[label] || out[label] != nil || store == nil {
+			continue
+		}
+		tmpl, ok := store.GetWLANTemplate(label)
+		if !ok {
+			continue
+		}
+		merged := make(map[string]any, len(tmpl))
+		for k, v := range tmpl {
+			merged[k] = v
+		}
+		for k, v := range siteConfig.WLANOverrides[label] {
+			merged[k] = v
+		}
+		out[label] = merged
+	}
+	return out
+}
+
+func printSiteScore(r readiness.Result, detail bool, format string) error {
+	if format == "json" {
+		out, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal score: %w", err)
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+
+	rows := make([]formatter.GenericTableData, 0, len(r.Categories))
+	for _, c := range r.Categories {
+		rows = append(rows, formatter.GenericTableData{
+			"category": c.Name,
+			"weight":   fmt.Sprintf("%d%%", c.Weight),
+			"score":    c.Score,
+			"findings": len(c.Findings),
+		})
+	}
+	printer := formatter.NewGenericTablePrinter(formatter.TableConfig{
+		Title:         fmt.Sprintf("Readiness — %s: %d/100", r.Site, r.Score),
+		Format:        format,
+		BoldHeaders:   true,
+		ShowSeparator: true,
+		CommandPath:   "score",
+		Columns: []formatter.TableColumn{
+			{Field: "category", Title: "Category"},
+			{Field: "weight", Title: "Weight"},
+			{Field: "score", Title: "Score"},
+			{Field: "findings", Title: "Findings"},
+		},
+	}, rows)
+	fmt.Print(printer.Print())
+
+	if detail && format == "table" {
+		printScoreFindings(r)
+	}
+	return nil
+}
+
+func printScoreFindings(r readiness.Result) {
+	for _, c := range r.Categories {
+		if len(c.Findings) == 0 {
+			continue
+		}
+		fmt.Printf("\n%s (%s):\n", c.Name, r.Site)
+		for _, f := range c.Findings {
+			fmt.Printf("  - %s\n", f)
+		}
+	}
+}
+
+func printScoreSummary(results []readiness.Result, detail bool, format string) error {
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Score < results[j].Score })
+
+	if format == "json" {
+		out, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal scores: %w", err)
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+
+	total := 0
+	rows := make([]formatter.GenericTableData, 0, len(results))
+	for _, r := range results {
+		total += r.Score
+		rows = append(rows, formatter.GenericTableData{
+			"site":       r.Site,
+			"score":      r.Score,
+			"validation": r.Category(readiness.CategoryValidation).Score,
+			"drift":      r.Category(readiness.CategoryDrift).Score,
+			"security":   r.Category(readiness.CategorySecurity).Score,
+			"naming":     r.Category(readiness.CategoryNaming).Score,
+		})
+	}
+	printer := formatter.NewGenericTablePrinter(formatter.TableConfig{
+		Title:         fmt.Sprintf("Readiness — %d sites, average %d/100", len(results), (total+len(results)/2)/len(results)),
+		Format:        format,
+		BoldHeaders:   true,
+		ShowSeparator: true,
+		CommandPath:   "score",
+		Columns: []formatter.TableColumn{
+			{Field: "site", Title: "Site"},
+			{Field: "score", Title: "Score"},
+			{Field: "validation", Title: "Validation"},
+			{Field: "drift", Title: "Drift"},
+			{Field: "security", Title: "Security"},
+			{Field: "naming", Title: "Naming"},
+		},
+	}, rows)
+	fmt.Print(printer.Print())
+
+	if detail && format == "table" {
+		for _, r := range results {
+			printScoreFindings(r)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/i18n"
+	"github.com/ravinald/wifimgr/internal/selfupdate"
+	"github.com/ravinald/wifimgr/internal/symbols"
+)
+
+var (
+	updateChannel       string
+	updateSkipSignature bool
+)
+
+// selfupdateCmd represents the "selfupdate" command
+var selfupdateCmd = &cobra.Command{
+	Use:   "selfupdate [check]",
+	Short: "Update wifimgr to the latest release",
+	Long: `Check the release feed for a newer wifimgr and replace the running binary.
+
+The archive for this platform is downloaded and checked against the release's
+checksums.txt, whose cosign signature must name wifimgr's release workflow.
+Signature checking runs the cosign CLI; without it, install cosign or pass
+--skip-signature to rely on the checksum alone. The new binary is written next
+to the current one and renamed into place, so an interrupted update leaves the
+old binary intact.
+
+Arguments:
+  check    Optional. Report the latest release without installing it
+
+Flags:
+  --channel          stable (default) or beta; beta includes pre-releases
+  --skip-signature   Verify checksums only (not recommended)
+
+Set WIFIMGR_UPDATE_FEED to use a mirror of the GitHub releases API.`,
+	Example: `  wifimgr selfupdate check
+  wifimgr selfupdate
+  wifimgr selfupdate --channel beta --yes`,
+	Annotations: map[string]string{
+		cmdutils.AnnotationNoInit: "true",
+	},
+	Args: cobra.MaximumNArgs(1),
+	RunE: runSelfupdate,
+}
+
+func init() {
+	rootCmd.AddCommand(selfupdateCmd)
+	selfupdateCmd.Flags().StringVar(&updateChannel, "channel", selfupdate.ChannelStable, "Release channel: stable or beta")
+	selfupdateCmd.Flags().BoolVar(&updateSkipSignature, "skip-signature", false, "Skip cosign signature verification (checksums only)")
+}
+
+func runSelfupdate(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+	checkOnly := false
+	if len(args) == 1 {
+		if !strings.EqualFold(args[0], "check") {
+			return fmt.Errorf("unknown argument %q (expected 'check')", args[0])
+		}
+		checkOnly = true
+	}
+
+	feed := os.Getenv("WIFIMGR_UPDATE_FEED")
+	if feed == "" {
+		feed = selfupdate.DefaultFeed
+	}
+	ctx := cmd.Context()
+	releases, err := selfupdate.FetchReleases(ctx, feed)
+	if err != nil {
+		return err
+	}
+	latest, err := selfupdate.Latest(releases, strings.ToLower(updateChannel))
+	if err != nil {
+		return err
+	}
+
+	if selfupdate.CompareVersions(latest.Version(), Version) <= 0 {
+		fmt.Printf("%s wifimgr %s is up to date (latest %s: %s)\n", symbols.SuccessPrefix(), Version, updateChannel, latest.TagName)
+		return nil
+	}
+	fmt.Printf("wifimgr %s is available (running %s)\n", latest.TagName, Version)
+	if checkOnly {
+		return nil
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("cannot locate the running binary: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	if !confirmSelfupdate(latest.TagName) {
+		return nil
+	}
+
+	dl, err := selfupdate.Fetch(ctx, latest, updateSkipSignature)
+	if errors.Is(err, selfupdate.ErrCosignMissing) {
+		return fmt.Errorf("%w: install cosign to verify the release signature, or rerun with --skip-signature", err)
+	}
+	if err != nil {
+		return err
+	}
+	if !dl.Signed {
+		fmt.Printf("%s signature not verified; %s matched checksums.txt only\n", symbols.WarningPrefix(), dl.Archive)
+	}
+	if err := selfupdate.Replace(exe, dl.Binary); err != nil {
+		return fmt.Errorf("update %s: %w", exe, err)
+	}
+	fmt.Printf("%s Updated %s to %s\n", symbols.SuccessPrefix(), exe, latest.TagName)
+	return nil
+}
+
+// confirmSelfupdate asks a y/N question. --yes approves; --no-input refuses.
+func confirmSelfupdate(tag string) bool {
+	if cmdutils.AssumeYes() {
+		return true
+	}
+	if cmdutils.NoInput() {
+		fmt.Println(i18n.T("selfupdate.no_input"))
+		return false
+	}
+	fmt.Printf("%s %s ", i18n.T("selfupdate.confirm", Version, tag), i18n.T("prompt.yes_no"))
+	input, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false
+	}
+	return i18n.IsYes(input)
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -90,16 +91,22 @@ Examples:
 }
 
 var setDeviceCmd = &cobra.Command{
-	Use:   "device <mac> managed|unmanaged",
-	Short: "Arm or disarm a device by MAC",
-	Long: `Add or remove a device from the per-site armed allowlist (inventory.json)
+	Use:   "device <mac|mac-prefix*|mac..mac> managed|unmanaged",
+	Short: "Arm or disarm devices by MAC, MAC prefix, or MAC range",
+	Long: `Add or remove devices from the per-site armed allowlist (inventory.json)
 by MAC. The device's site and type are resolved from cache, so refresh first if
 it is missing. A device not yet assigned to a site cannot be armed by MAC — use
 'set site <site> ...' once it has a site.
 
+A prefix ending in * or an inclusive range a..b selects every cached device
+it matches, in any site, which suits a batch from one purchase order. Devices
+without a site are skipped and counted.
+
 Examples:
   wifimgr set device 5c5b35000001 managed
-  wifimgr set device 5c:5b:35:00:00:01 unmanaged`,
+  wifimgr set device 5c:5b:35:00:00:01 unmanaged
+  wifimgr set device 5c:5b:35:* managed
+  wifimgr set device 5c:5b:35:00:00:10..5c:5b:35:00:00:40 managed`,
 	Args: func(_ *cobra.Command, args []string) error {
 		if cmdutils.ContainsHelp(args) {
 			return nil
@@ -291,6 +298,9 @@ func siteConfiguredAPI(siteName string) *config.SiteConfig {
 // runDeviceArming resolves a MAC to its site and type from cache and toggles it.
 func runDeviceArming(rawMAC, keyword string) error {
 	arm, _ := armActionFor(keyword)
+	if macaddr.IsPattern(rawMAC) {
+		return runDevicePatternArming(rawMAC, arm)
+	}
 
 	mac := macaddr.NormalizeOrEmpty(rawMAC)
 	if mac == "" {
@@ -314,6 +324,51 @@ func runDeviceArming(rawMAC, keyword string) error {
 	return applyArming(item.SiteName, []armTarget{target}, arm)
 }
 
+// runDevicePatternArming toggles every cached device a MAC prefix or range
+// selects, one site at a time.
+func runDevicePatternArming(rawPattern string, arm bool) error {
+	pattern, err := macaddr.ParsePattern(rawPattern)
+	if err != nil {
+		return err
+	}
+	accessor := vendors.GetGlobalCacheAccessor()
+	if accessor == nil {
+		return fmt.Errorf("cache not initialized; run a refresh first")
+	}
+
+	bySite := make(map[string][]armTarget)
+	unassigned := 0
+	for _, item := range accessor.GetAllDevices() {
+		if !pattern.Match(item.MAC) {
+			continue
+		}
+		if item.SiteID == "" || item.SiteName == "" {
+			unassigned++
+			continue
+		}
+		bySite[item.SiteName] = append(bySite[item.SiteName], armTarget{mac: item.MAC, name: item.Name, dtype: item.Type})
+	}
+	if unassigned > 0 {
+		fmt.Printf("Skipping %d device(s) matching %s with no site assigned.\n", unassigned, rawPattern)
+	}
+	if len(bySite) == 0 {
+		fmt.Printf("No site-assigned devices match %s — run a refresh if they are new.\n", rawPattern)
+		return nil
+	}
+
+	sites := make([]string, 0, len(bySite))
+	for site := range bySite {
+		sites = append(sites, site)
+	}
+	sort.Strings(sites)
+	for _, site := range sites {
+		if err := applyArming(site, bySite[site], arm); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // applyArming writes the targets into (or out of) the site's allowlist and
 // prints an immediate, apply-free summary.
 func applyArming(siteName string, targets []armTarget, arm bool) error {
@@ -354,7 +409,12 @@ func applyArming(siteName string, targets []armTarget, arm bool) error {
 		}
 	}
 
-	printArmingSummary(siteName, path, targets, arm, changed, already)
+	// With inventory split across files, name the one holding the site.
+	written, err := config.InventoryFileForSite(path, siteName)
+	if err != nil {
+		written = path
+	}
+	printArmingSummary(siteName, written, targets, arm, changed, already)
 	return nil
 }
 
@@ -363,7 +423,7 @@ func applyArming(siteName string, targets []armTarget, arm bool) error {
 // state. A missing or unreadable file yields an empty set.
 func armedMembership(path, siteName string) map[string]bool {
 	set := make(map[string]bool)
-	f, err := config.LoadInventoryFile(path)
+	f, err := config.LoadInventory(path)
 	if err != nil {
 		return set
 	}
@@ -187,7 +187,7 @@ func outputConfigsJSON(configs []interface{}, _ *cmdutils.ParsedShowArgs) {
 
 func outputConfigsCSV(configs []interface{}) {
 	// Create table data
-	headers := []string{"Type", "Name", "MAC", "Site ID", "Model", "Serial", "IP"}
+	headers := []string{"Type", "Name", "MAC", "Site ID", "Model", "Serial", "IP", "IPv6"}
 	var rows [][]string
 
 	for _, config := range configs {
@@ -202,7 +202,8 @@ func outputConfigsCSV(configs []interface{}) {
 				c.SiteID,
 				getConfigString(c.Config, "model"),
 				getConfigString(c.Config, "serial"),
-				getIPFromConfigMap(c.Config),
+				getIPFromConfigMap(c.Config, "ip"),
+				getIPFromConfigMap(c.Config, "ip6"),
 			}
 		case *vendors.SwitchConfig:
 			row = []string{
@@ -212,7 +213,8 @@ func outputConfigsCSV(configs []interface{}) {
 				c.SiteID,
 				getConfigString(c.Config, "model"),
 				getConfigString(c.Config, "serial"),
-				getIPFromConfigMap(c.Config),
+				getIPFromConfigMap(c.Config, "ip"),
+				getIPFromConfigMap(c.Config, "ip6"),
 			}
 		case *vendors.GatewayConfig:
 			row = []string{
@@ -222,7 +224,8 @@ func outputConfigsCSV(configs []interface{}) {
 				c.SiteID,
 				getConfigString(c.Config, "model"),
 				getConfigString(c.Config, "serial"),
-				getIPFromPortConfigMap(c.Config),
+				getIPFromPortConfigMap(c.Config, "ip"),
+				getIPFromPortConfigMap(c.Config, "ip6"),
 			}
 		}
 
@@ -272,7 +275,8 @@ func outputConfigsTable(configs []interface{}, args *cmdutils.ParsedShowArgs, ac
 			row["mac"] = c.MAC
 			row["model"] = getConfigString(c.Config, "model")
 			row["serial"] = getConfigString(c.Config, "serial")
-			row["ip"] = getIPFromConfigMap(c.Config)
+			row["ip"] = getIPFromConfigMap(c.Config, "ip")
+			row["ip6"] = getIPFromConfigMap(c.Config, "ip6")
 			row["vlan"] = getVLANFromConfigMap(c.Config)
 
 			// Resolve site name
@@ -292,7 +296,8 @@ func outputConfigsTable(configs []interface{}, args *cmdutils.ParsedShowArgs, ac
 			row["mac"] = c.MAC
 			row["model"] = getConfigString(c.Config, "model")
 			row["serial"] = getConfigString(c.Config, "serial")
-			row["ip"] = getIPFromConfigMap(c.Config)
+			row["ip"] = getIPFromConfigMap(c.Config, "ip")
+			row["ip6"] = getIPFromConfigMap(c.Config, "ip6")
 			row["vlan"] = getVLANFromConfigMap(c.Config)
 
 			// Resolve site name
@@ -312,7 +317,8 @@ func outputConfigsTable(configs []interface{}, args *cmdutils.ParsedShowArgs, ac
 			row["mac"] = c.MAC
 			row["model"] = getConfigString(c.Config, "model")
 			row["serial"] = getConfigString(c.Config, "serial")
-			row["ip"] = getIPFromPortConfigMap(c.Config)
+			row["ip"] = getIPFromPortConfigMap(c.Config, "ip")
+			row["ip6"] = getIPFromPortConfigMap(c.Config, "ip6")
 			row["vlan"] = ""
 
 			// Resolve site name
@@ -341,6 +347,7 @@ func outputConfigsTable(configs []interface{}, args *cmdutils.ParsedShowArgs, ac
 		{Field: "model", Title: "Model"},
 		{Field: "serial", Title: "Serial"},
 		{Field: "ip", Title: "IP"},
+		{Field: "ip6", Title: "IPv6"},
 		{Field: "vlan", Title: "VLAN"},
 	}
 
@@ -359,8 +366,9 @@ func outputConfigsTable(configs []interface{}, args *cmdutils.ParsedShowArgs, ac
 
 // Helper functions to extract data from vendors config maps
 
-// getIPFromConfigMap extracts IP from a device config's ip_config field
-func getIPFromConfigMap(config map[string]interface{}) string {
+// getIPFromConfigMap extracts an address from a device config's ip_config
+// field: key "ip" for IPv4, "ip6" for IPv6
+func getIPFromConfigMap(config map[string]interface{}, key string) string {
 	if config == nil {
 		return ""
 	}
@@ -370,15 +378,16 @@ func getIPFromConfigMap(config map[string]interface{}) string {
 		return ""
 	}
 
-	if ip, ok := ipConfig["ip"].(string); ok {
+	if ip, ok := ipConfig[key].(string); ok {
 		return ip
 	}
 
 	return ""
 }
 
-// getIPFromPortConfigMap extracts IP from a gateway's port_config field
-func getIPFromPortConfigMap(config map[string]interface{}) string {
+// getIPFromPortConfigMap extracts an address (key "ip" or "ip6") from a
+// gateway's port_config field
+func getIPFromPortConfigMap(config map[string]interface{}, key string) string {
 	if config == nil {
 		return ""
 	}
@@ -392,7 +401,7 @@ func getIPFromPortConfigMap(config map[string]interface{}) string {
 	for _, portCfg := range portConfig {
 		if configMap, ok := portCfg.(map[string]interface{}); ok {
 			if ipConfig, ok := configMap["ip_config"].(map[string]interface{}); ok {
-				if ip, ok := ipConfig["ip"].(string); ok && ip != "" {
+				if ip, ok := ipConfig[key].(string); ok && ip != "" {
 					return ip
 				}
 			}
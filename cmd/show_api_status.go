@@ -23,6 +23,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/config"
 )
 
 // showAPIStatusCmd represents the show api status command
@@ -89,6 +90,14 @@ func runShowAPIStatus(cmd *cobra.Command, args []string) error {
 		fmt.Printf("    Vendor:       %s\n", status.Vendor)
 		fmt.Printf("    Org ID:       %s\n", status.OrgID)
 		fmt.Printf("    Capabilities: %s\n", strings.Join(status.Capabilities, ", "))
+		if apiConfig, err := registry.GetConfig(status.Label); err == nil {
+			if preset, ok := config.LookupAPIPreset(apiConfig.Preset); ok {
+				fmt.Printf("    Preset:       %s (%s)\n", preset.Name, preset.Notes)
+			}
+			if apiConfig.ReadOnly {
+				fmt.Printf("    Access:       read-only (apply shows diffs only)\n")
+			}
+		}
 		if status.Healthy {
 			fmt.Printf("    Status:       healthy\n")
 		} else {
@@ -87,7 +87,8 @@ func runShowFields(cmd *cobra.Command, args []string) error {
 
 	// Print config hint
 	fmt.Printf("\nConfigure in wifimgr-config.json:\n")
-	fmt.Printf("  display.commands[\"%s\"].fields\n\n", cmdPath)
+	fmt.Printf("  display.commands[\"%s\"].fields\n", cmdPath)
+	fmt.Printf("  computed column: {\"field\": \"up_days\", \"title\": \"Up (d)\", \"expr\": \"round(uptime / 86400, 1)\"}\n\n")
 
 	return nil
 }
@@ -0,0 +1,210 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/formatter"
+	"github.com/ravinald/wifimgr/internal/models"
+)
+
+// showModelCmd represents the "show model" command
+var showModelCmd = &cobra.Command{
+	Use:   "model [<name>] [format json|csv]",
+	Short: "Show hardware capabilities of a device model",
+	Long: `Show what a device model can do, from the built-in models database:
+radios and spatial streams per band, maximum clients, the PoE class it draws,
+a switch's PoE budget, and its ports. Without a name, every known model is
+listed. 'lint config' uses the same database to flag radio settings for a
+band the model has no radio for.
+
+Names match without case, and a regional suffix is ignored (AP43-US is the
+AP43). files.models can name a file in the same format to add models or
+correct entries.
+
+Arguments:
+  name         Optional. Model name, e.g. AP45 or MS120-48LP
+  format       Optional. "json" or "csv" (default: table)`,
+	Example: `  wifimgr show model AP45
+  wifimgr show model
+  wifimgr show model MR57 format json`,
+	Annotations: map[string]string{
+		cmdutils.AnnotationNeedsConfig: "true",
+	},
+	RunE: runShowModel,
+}
+
+func init() {
+	showCmd.AddCommand(showModelCmd)
+}
+
+func runShowModel(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+	var name string
+	format := "table"
+	for i := 0; i < len(args); i++ {
+		switch strings.ToLower(args[i]) {
+		case "format":
+			if i+1 >= len(args) {
+				return fmt.Errorf("format requires a value")
+			}
+			format = strings.ToLower(args[i+1])
+			if format != "table" && format != "json" && format != "csv" {
+				return fmt.Errorf("format must be table, json, or csv, got %q", args[i+1])
+			}
+			i++
+		default:
+			if name != "" {
+				return fmt.Errorf("unexpected argument: %s", args[i])
+			}
+			name = cmdutils.StripQuotes(args[i])
+		}
+	}
+
+	db, err := models.Default()
+	if err != nil {
+		return err
+	}
+	list := db.All()
+	if name != "" {
+		m, ok := db.Lookup(name)
+		if !ok {
+			return fmt.Errorf("model %q is not in the models database; add it with files.models", name)
+		}
+		list = []*models.Model{m}
+	}
+
+	if format == "json" {
+		var out any = list
+		if name != "" {
+			out = list[0]
+		}
+		data, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	rows := make([]formatter.GenericTableData, 0, len(list))
+	for _, m := range list {
+		rows = append(rows, modelRow(m))
+	}
+	columns := []formatter.TableColumn{
+		{Field: "name", Title: "Model"},
+		{Field: "vendor", Title: "Vendor"},
+		{Field: "type", Title: "Type"},
+		{Field: "wifi", Title: "Wi-Fi"},
+		{Field: "radios", Title: "Radios"},
+		{Field: "max_clients", Title: "Max Clients"},
+		{Field: "poe", Title: "PoE"},
+		{Field: "ports", Title: "Ports"},
+	}
+	title := fmt.Sprintf("Models (%d)", len(list))
+	if name != "" {
+		title = list[0].Name
+	}
+	printer := formatter.NewGenericTablePrinter(formatter.TableConfig{
+		Title:         title,
+		Format:        format,
+		BoldHeaders:   true,
+		ShowSeparator: true,
+		CommandPath:   "show.model",
+		Columns:       columns,
+	}, rows)
+	fmt.Print(printer.Print())
+	return nil
+}
+
+// modelRow flattens a model into table cells. Empty cells stay unset so
+// the table leaves them blank.
+func modelRow(m *models.Model) formatter.GenericTableData {
+	row := formatter.GenericTableData{
+		"name":   m.Name,
+		"vendor": m.Vendor,
+		"type":   m.Type,
+	}
+	if m.WiFi != "" {
+		wifi := m.WiFi
+		if m.Outdoor {
+			wifi += " outdoor"
+		}
+		row["wifi"] = wifi
+	}
+	if len(m.Radios) > 0 {
+		row["radios"] = describeRadios(m.Radios)
+	}
+	if m.MaxClients > 0 {
+		row["max_clients"] = m.MaxClients
+	}
+	switch {
+	case m.PoEBudgetWatts > 0:
+		row["poe"] = fmt.Sprintf("%dW budget", m.PoEBudgetWatts)
+	case m.PoE != "":
+		row["poe"] = m.PoE
+	}
+	if len(m.Ports) > 0 {
+		row["ports"] = describePorts(m.Ports)
+	}
+	return row
+}
+
+// modelBandGHz are the band keys as frequencies.
+var modelBandGHz = map[string]string{models.Band24: "2.4", models.Band5: "5", models.Band6: "6"}
+
+// describeRadios renders radios as "2.4 GHz 4x4, 5 GHz 4x4, 6 GHz 2x2",
+// with a flexible radio's bands joined by a slash.
+func describeRadios(radios []models.Radio) string {
+	parts := make([]string, 0, len(radios))
+	for _, r := range radios {
+		bands := make([]string, 0, len(r.Bands))
+		for _, b := range r.Bands {
+			bands = append(bands, modelBandGHz[b])
+		}
+		part := strings.Join(bands, "/") + " GHz"
+		if r.Streams > 0 {
+			part += fmt.Sprintf(" %dx%d", r.Streams, r.Streams)
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// describePorts renders port groups as "48x 1G access (802.3at), 4x 10G
+// SFP+ uplink".
+func describePorts(ports []models.PortGroup) string {
+	parts := make([]string, 0, len(ports))
+	for _, p := range ports {
+		part := fmt.Sprintf("%dx %s", p.Count, p.Speed)
+		if p.Role != "" {
+			part += " " + p.Role
+		}
+		if p.PoE != "" {
+			part += " (" + p.PoE + ")"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ", ")
+}
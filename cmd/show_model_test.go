@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/ravinald/wifimgr/internal/models"
+)
+
+func TestModelRow(t *testing.T) {
+	ap := &models.Model{
+		Name: "AP45", Vendor: "mist", Type: "ap", WiFi: "6E", MaxClients: 1024, PoE: "802.3bt",
+		Radios: []models.Radio{{Bands: []string{"band_24"}, Streams: 4}, {Bands: []string{"band_5", "band_6"}, Streams: 2}},
+		Ports:  []models.PortGroup{{Count: 1, Speed: "5G", Role: "uplink"}},
+	}
+	row := modelRow(ap)
+	if row["radios"] != "2.4 GHz 4x4, 5/6 GHz 2x2" {
+		t.Errorf("radios = %q", row["radios"])
+	}
+	if row["poe"] != "802.3bt" || row["ports"] != "1x 5G uplink" || row["wifi"] != "6E" {
+		t.Errorf("row = %v", row)
+	}
+
+	sw := &models.Model{
+		Name: "MS120-48LP", Vendor: "meraki", Type: "switch", PoEBudgetWatts: 370,
+		Ports: []models.PortGroup{{Count: 48, Speed: "1G", Role: "access", PoE: "802.3at"}, {Count: 4, Speed: "1G SFP", Role: "uplink"}},
+	}
+	row = modelRow(sw)
+	if row["poe"] != "370W budget" || row["ports"] != "48x 1G access (802.3at), 4x 1G SFP uplink" {
+		t.Errorf("row = %v", row)
+	}
+	if _, ok := row["radios"]; ok {
+		t.Errorf("switch has a radios cell: %v", row)
+	}
+}
@@ -0,0 +1,225 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ravinald/wifimgr/cmd/apply"
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/formatter"
+	"github.com/ravinald/wifimgr/internal/symbols"
+)
+
+// showOnboardingCmd represents the "show onboarding" command
+var showOnboardingCmd = &cobra.Command{
+	Use:   "onboarding <site> [target <api-label>] [format json|csv]",
+	Short: "Show where each declared device is in its rollout",
+	Long: `Show every device a site declares as a board of rollout stages, so a
+rollout can see which devices are stuck and where:
+
+  not claimed   Not in the org inventory
+  claimed       In the inventory, not assigned to this site
+  assigned      Assigned here; no apply has pushed its config yet
+  configured    Config pushed; device not online
+  connected     Online, but its name differs from intent
+  named         Done
+
+A device is "configured" once an apply has assigned or updated it (per the
+apply history) and its last verify did not find it diverged. Everything else
+is read from the API cache; nothing is fetched, so refresh the site first for
+an up-to-date board. The table view lists each unfinished device with what is
+holding it back.
+
+Arguments:
+  site         Required. Site name
+  target       Optional. API label for devices without their own "api"
+  format       Optional. "json" or "csv" one row per device (default: board)`,
+	Example: `  wifimgr show onboarding US-LAB-01
+  wifimgr show onboarding US-LAB-01 format json`,
+	Args: func(_ *cobra.Command, args []string) error {
+		if cmdutils.ContainsHelp(args) {
+			return nil
+		}
+		if len(args) < 1 {
+			return fmt.Errorf("requires a site name")
+		}
+		return nil
+	},
+	RunE: runShowOnboarding,
+}
+
+func init() {
+	showCmd.AddCommand(showOnboardingCmd)
+}
+
+func runShowOnboarding(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+
+	siteName := cmdutils.StripQuotes(args[0])
+	format := "table"
+	for i := 1; i < len(args); i++ {
+		switch strings.ToLower(args[i]) {
+		case "target":
+			if i+1 >= len(args) {
+				return fmt.Errorf("'target' requires an API label")
+			}
+			SetAPITarget(args[i+1])
+			if err := ValidateAPIFlag(); err != nil {
+				return err
+			}
+			i++
+		case "format":
+			if i+1 >= len(args) {
+				return fmt.Errorf("'format' requires a format type (json, csv)")
+			}
+			switch f := strings.ToLower(args[i+1]); f {
+			case "json", "csv":
+				format = f
+			default:
+				return fmt.Errorf("invalid format %q: must be 'json' or 'csv'", args[i+1])
+			}
+			i++
+		default:
+			return fmt.Errorf("unexpected argument: %s", args[i])
+		}
+	}
+
+	apiLabel, err := ResolveAPIForSite(siteName, nil)
+	if err != nil {
+		return err
+	}
+	board, err := apply.SiteOnboarding(globalConfig, siteName, apiLabel)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case "json":
+		out, err := json.MarshalIndent(board, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal onboarding board: %w", err)
+		}
+		fmt.Println(string(out))
+		return nil
+	case "csv":
+		rows := make([]formatter.GenericTableData, 0, len(board))
+		for _, d := range board {
+			rows = append(rows, formatter.GenericTableData{
+				"name": d.Name, "mac": d.MAC, "type": d.Type, "api": d.API, "stage": d.Stage, "blocker": d.Blocker,
+			})
+		}
+		printer := formatter.NewGenericTablePrinter(formatter.TableConfig{
+			Format:      "csv",
+			CommandPath: "show.onboarding",
+			Columns: []formatter.TableColumn{
+				{Field: "name", Title: "Name"},
+				{Field: "mac", Title: "MAC"},
+				{Field: "type", Title: "Type"},
+				{Field: "api", Title: "API"},
+				{Field: "stage", Title: "Stage"},
+				{Field: "blocker", Title: "Blocker"},
+			},
+		}, rows)
+		fmt.Print(printer.Print())
+		return nil
+	}
+
+	if len(board) == 0 {
+		fmt.Printf("Site %s declares no devices\n", siteName)
+		return nil
+	}
+	printOnboardingBoard(siteName, board)
+	return nil
+}
+
+// printOnboardingBoard renders the kanban view: one column per stage holding
+// the devices at that stage, then the blocker for each unfinished device.
+func printOnboardingBoard(siteName string, board []apply.OnboardingDevice) {
+	columns, rows := onboardingBoardRows(board)
+	printer := formatter.NewGenericTablePrinter(formatter.TableConfig{
+		Title:         fmt.Sprintf("Onboarding — %s (%d devices)", siteName, len(board)),
+		Format:        "table",
+		BoldHeaders:   true,
+		ShowSeparator: true,
+		CommandPath:   "show.onboarding",
+		Columns:       columns,
+	}, rows)
+	fmt.Print(printer.Print())
+
+	stuck := 0
+	for _, d := range board {
+		if d.Done() {
+			continue
+		}
+		if stuck == 0 {
+			fmt.Println("\nBlocked:")
+		}
+		stuck++
+		fmt.Printf("  %-28s %-8s %s\n", onboardingLabel(d), d.Type, d.Blocker)
+	}
+	fmt.Println()
+	if stuck == 0 {
+		fmt.Printf("%s All %d device(s) at %s are onboarded\n", symbols.SuccessPrefix(), len(board), siteName)
+	} else {
+		fmt.Printf("%s %d of %d device(s) at %s are not fully onboarded\n", symbols.WarningPrefix(), stuck, len(board), siteName)
+	}
+}
+
+// onboardingBoardRows lays the board out as table columns, one per stage,
+// titled with the stage's device count. Row i holds the i-th device of each
+// column, so columns read top to bottom like a kanban board.
+func onboardingBoardRows(board []apply.OnboardingDevice) ([]formatter.TableColumn, []formatter.GenericTableData) {
+	byStage := make(map[string][]string)
+	for _, d := range board {
+		byStage[d.Stage] = append(byStage[d.Stage], onboardingLabel(d))
+	}
+	columns := make([]formatter.TableColumn, 0, len(apply.OnboardingStages))
+	height := 0
+	for _, stage := range apply.OnboardingStages {
+		columns = append(columns, formatter.TableColumn{
+			Field: stage,
+			Title: fmt.Sprintf("%s (%d)", stage, len(byStage[stage])),
+		})
+		height = max(height, len(byStage[stage]))
+	}
+	rows := make([]formatter.GenericTableData, height)
+	for i := range rows {
+		rows[i] = formatter.GenericTableData{}
+		for _, stage := range apply.OnboardingStages {
+			if i < len(byStage[stage]) {
+				rows[i][stage] = byStage[stage][i]
+			} else {
+				rows[i][stage] = ""
+			}
+		}
+	}
+	return columns, rows
+}
+
+// onboardingLabel names a device by its intended name, else its MAC.
+func onboardingLabel(d apply.OnboardingDevice) string {
+	if d.Name != "" {
+		return d.Name
+	}
+	return d.MAC
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/ravinald/wifimgr/cmd/apply"
+)
+
+func TestOnboardingBoardRows(t *testing.T) {
+	board := []apply.OnboardingDevice{
+		{MAC: "aa0000000001", Name: "ap-1", Stage: apply.StageNone},
+		{MAC: "aa0000000002", Stage: apply.StageConfigured},
+		{MAC: "aa0000000003", Name: "ap-3", Stage: apply.StageNamed},
+		{MAC: "aa0000000004", Name: "ap-4", Stage: apply.StageNamed},
+	}
+	columns, rows := onboardingBoardRows(board)
+	if len(columns) != len(apply.OnboardingStages) {
+		t.Fatalf("got %d columns, want one per stage", len(columns))
+	}
+	if columns[0].Title != "not claimed (1)" || columns[len(columns)-1].Title != "named (2)" {
+		t.Errorf("column titles = %q ... %q", columns[0].Title, columns[len(columns)-1].Title)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want 2 (tallest column)", len(rows))
+	}
+	if rows[0][apply.StageConfigured] != "aa0000000002" {
+		t.Errorf("unnamed device should show its MAC, got %v", rows[0][apply.StageConfigured])
+	}
+	if rows[1][apply.StageNamed] != "ap-4" || rows[1][apply.StageNone] != "" {
+		t.Errorf("second row = %v", rows[1])
+	}
+}
@@ -0,0 +1,107 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ravinald/wifimgr/cmd/apply"
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/i18n"
+	"github.com/ravinald/wifimgr/internal/symbols"
+)
+
+// showReconciliationCmd represents the "show reconciliation" command
+var showReconciliationCmd = &cobra.Command{
+	Use:     "reconciliation <site> [unified] [target <api-label>]",
+	Aliases: []string{"recon"},
+	Short:   "Show running config against intent for every object at a site",
+	Long: `Show a site's running config (API cache) next to its intent, one managed
+object at a time: site settings, WLANs, then every declared AP, switch, and
+gateway. Each object that differs is rendered with the same side-by-side diff
+"apply ... diff split" prints; objects that match get one line.
+
+Device comparisons use managed keys, as apply does. Nothing is pushed; refresh
+the cache first for an up-to-date view.
+
+Arguments:
+  site         Required. Site name
+  unified      Optional. Unified diff instead of side-by-side
+  target       Optional. API label to compare against`,
+	Example: `  wifimgr show reconciliation US-LAB-01
+  wifimgr show recon US-LAB-01 unified
+  wifimgr show reconciliation US-LAB-01 target mist-prod`,
+	Args: func(_ *cobra.Command, args []string) error {
+		if cmdutils.ContainsHelp(args) {
+			return nil
+		}
+		if len(args) < 1 {
+			return fmt.Errorf("requires a site name")
+		}
+		return nil
+	},
+	RunE: runShowReconciliation,
+}
+
+func init() {
+	showCmd.AddCommand(showReconciliationCmd)
+}
+
+func runShowReconciliation(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+
+	siteName := cmdutils.StripQuotes(args[0])
+	unified := false
+	for i := 1; i < len(args); i++ {
+		switch strings.ToLower(args[i]) {
+		case "unified":
+			unified = true
+		case "target":
+			if i+1 >= len(args) {
+				return fmt.Errorf("'target' requires an API label")
+			}
+			SetAPITarget(args[i+1])
+			if err := ValidateAPIFlag(); err != nil {
+				return err
+			}
+			i++
+		default:
+			return fmt.Errorf("unexpected argument: %s", args[i])
+		}
+	}
+
+	apiLabel, err := ResolveAPIForSite(siteName, nil)
+	if err != nil {
+		return err
+	}
+
+	differ, err := apply.ReconcileSite(globalContext, vendorClientForApply(apiLabel), globalConfig, siteName, apiLabel, unified)
+	if err != nil {
+		return err
+	}
+	fmt.Println()
+	if differ == 0 {
+		fmt.Printf("%s %s\n", symbols.SuccessPrefix(), i18n.T("reconcile.match", siteName))
+	} else {
+		fmt.Printf("%s %s\n", symbols.WarningPrefix(), i18n.T("reconcile.differ", differ, siteName))
+	}
+	return nil
+}
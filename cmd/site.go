@@ -0,0 +1,52 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/logging"
+)
+
+// siteCmd groups commands that manage whole sites in the intent files.
+var siteCmd = &cobra.Command{
+	Use:   "site",
+	Short: "Archive and restore sites in the intent files",
+	Long: `Manage whole sites in the site config files: archive a site being
+decommissioned so its block is kept outside intent, and restore it later.`,
+	Example: `  wifimgr site archive US-LAB-01
+  wifimgr site restore US-LAB-01`,
+	Annotations: map[string]string{
+		cmdutils.AnnotationNeedsConfig: "true",
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(siteCmd)
+}
+
+// siteContact returns the contact declared in a site's intent, or nil when
+// the site is not in intent or declares none.
+func siteContact(siteName string) *config.SiteContact {
+	obj, err := loadSiteConfiguration(siteName)
+	if err != nil {
+		logging.Debugf("No intent contact for site %s: %v", siteName, err)
+		return nil
+	}
+	return obj.SiteConfig.Contact
+}
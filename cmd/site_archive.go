@@ -0,0 +1,249 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/formatter"
+	"github.com/ravinald/wifimgr/internal/i18n"
+	"github.com/ravinald/wifimgr/internal/logging"
+	"github.com/ravinald/wifimgr/internal/symbols"
+)
+
+// siteArchiveCmd represents the "site archive" command
+var siteArchiveCmd = &cobra.Command{
+	Use:   "archive <site-name> [diff] [force]",
+	Short: "Move a site's block out of intent into the archive",
+	Long: `Remove a site from the site config files, keeping its whole block in
+archives/<site>.json under the config directory with the time it was archived
+and the file it came from, so it can be restored with 'site restore'.
+
+Archiving changes intent only: the site and its devices are left as they are
+in the API, and the site's armed devices stay in inventory.json. The site
+config file is backed up first.
+
+Arguments:
+  site-name    Required. site_config.name or the site key (case-insensitive)
+  diff         Optional. Show what would move without writing
+  force        Optional. Skip the confirmation prompt`,
+	Example: `  wifimgr site archive US-LAB-01 diff
+  wifimgr site archive US-LAB-01`,
+	Annotations: map[string]string{
+		cmdutils.AnnotationNeedsConfig: "true",
+	},
+	RunE: runSiteArchive,
+}
+
+// siteRestoreCmd represents the "site restore" command
+var siteRestoreCmd = &cobra.Command{
+	Use:   "restore [site-name] [file <site-config-file>] [diff] [force]",
+	Short: "Put an archived site back into intent",
+	Long: `Put the most recently archived block of a site back into the site config
+file it was archived from, or into the file given with 'file'. The archive
+keeps the entry, marked with the time it was restored.
+
+Without a site name, lists the archived sites.
+
+A site already defined in intent is not overwritten; archive or remove it
+first.
+
+Arguments:
+  site-name    Optional. Archived site to restore
+  file <name>  Optional. Target site config file from files.site_configs
+  diff         Optional. Show what would be restored without writing
+  force        Optional. Skip the confirmation prompt`,
+	Example: `  wifimgr site restore
+  wifimgr site restore US-LAB-01
+  wifimgr site restore US-LAB-01 file sites/us-west.json`,
+	Annotations: map[string]string{
+		cmdutils.AnnotationNeedsConfig: "true",
+	},
+	RunE: runSiteRestore,
+}
+
+func init() {
+	siteCmd.AddCommand(siteArchiveCmd)
+	siteCmd.AddCommand(siteRestoreCmd)
+}
+
+// siteArchiveArgs is the parsed form of the site archive/restore arguments.
+type siteArchiveArgs struct {
+	Site  string
+	File  string
+	Diff  bool
+	Force bool
+}
+
+func parseSiteArchiveArgs(args []string, allowFile bool) (*siteArchiveArgs, error) {
+	parsed := &siteArchiveArgs{}
+	for i := 0; i < len(args); i++ {
+		switch strings.ToLower(args[i]) {
+		case "diff":
+			parsed.Diff = true
+		case "force":
+			parsed.Force = true
+		case "file":
+			if !allowFile {
+				return nil, fmt.Errorf("unexpected argument: %s", args[i])
+			}
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("'file' requires a site config file")
+			}
+			parsed.File = cmdutils.StripQuotes(args[i+1])
+			i++
+		default:
+			if parsed.Site != "" {
+				return nil, fmt.Errorf("unexpected argument: %s", args[i])
+			}
+			parsed.Site = cmdutils.StripQuotes(args[i])
+		}
+	}
+	return parsed, nil
+}
+
+func runSiteArchive(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+	parsed, err := parseSiteArchiveArgs(args, false)
+	if err != nil {
+		return err
+	}
+	if parsed.Site == "" {
+		return fmt.Errorf("requires a site name")
+	}
+
+	change, err := config.PlanSiteArchive(globalConfig.Files.SiteConfigs, globalConfig.Files.ConfigDir, parsed.Site, time.Now())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("site %s (key %s, %d device(s)): %s -> %s\n",
+		change.Site, change.SiteKey, change.Devices, change.File, change.Archive)
+	if parsed.Diff {
+		return nil
+	}
+	if !parsed.Force {
+		fmt.Printf("%s %s ", i18n.T("site_archive.confirm", change.Site, change.Devices, change.File), i18n.T("prompt.yes_no"))
+		if !confirmPrompt() {
+			fmt.Println(i18n.T("site_archive.cancelled"))
+			return nil
+		}
+	}
+	return writeSiteArchiveChange(change, "Archived", "archive")
+}
+
+func runSiteRestore(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+	parsed, err := parseSiteArchiveArgs(args, true)
+	if err != nil {
+		return err
+	}
+	if parsed.Site == "" {
+		return listSiteArchives()
+	}
+
+	change, err := config.PlanSiteRestore(globalConfig.Files.SiteConfigs, globalConfig.Files.ConfigDir, parsed.Site, parsed.File, time.Now())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("site %s (key %s, %d device(s)): %s -> %s\n",
+		change.Site, change.SiteKey, change.Devices, change.Archive, change.File)
+	if parsed.Diff {
+		return nil
+	}
+	if !parsed.Force {
+		fmt.Printf("%s %s ", i18n.T("site_archive.restore_confirm", change.Site, change.Devices, change.File), i18n.T("prompt.yes_no"))
+		if !confirmPrompt() {
+			fmt.Println(i18n.T("site_archive.cancelled"))
+			return nil
+		}
+	}
+	return writeSiteArchiveChange(change, "Restored", "restore")
+}
+
+// writeSiteArchiveChange backs up the site config files the change rewrites
+// (the archive file is not a site config and keeps its own history), then
+// writes the change.
+func writeSiteArchiveChange(change *config.SiteArchiveChange, verb, op string) error {
+	var paths []string
+	for _, path := range change.Files() {
+		if path != change.Archive {
+			paths = append(paths, path)
+		}
+	}
+	if err := applyRewriteWithBackups(op, paths, change.Apply); err != nil {
+		return err
+	}
+	logging.Infof("%s site %s (%s)", verb, change.Site, change.Archive)
+	fmt.Printf("%s %s site %s\n", symbols.SuccessPrefix(), verb, change.Site)
+	return nil
+}
+
+// listSiteArchives prints one row per archived site.
+func listSiteArchives() error {
+	archives, err := config.ListSiteArchives(globalConfig.Files.ConfigDir)
+	if err != nil {
+		return err
+	}
+	if len(archives) == 0 {
+		fmt.Printf("No archived sites in %s\n", filepath.Join(globalConfig.Files.ConfigDir, config.SiteArchiveDir))
+		return nil
+	}
+
+	var rows []formatter.GenericTableData
+	for _, a := range archives {
+		if len(a.Entries) == 0 {
+			continue
+		}
+		last := a.Entries[len(a.Entries)-1]
+		state := "archived"
+		if last.RestoredAt != nil {
+			state = "restored " + last.RestoredAt.Local().Format("2006-01-02 15:04")
+		}
+		rows = append(rows, formatter.GenericTableData{
+			"site":     a.Site,
+			"archived": last.ArchivedAt.Local().Format("2006-01-02 15:04"),
+			"file":     last.File,
+			"entries":  len(a.Entries),
+			"state":    state,
+		})
+	}
+	printer := formatter.NewGenericTablePrinter(formatter.TableConfig{
+		Title:         "Archived Sites",
+		Format:        "table",
+		BoldHeaders:   true,
+		ShowSeparator: true,
+		Columns: []formatter.TableColumn{
+			{Field: "site", Title: "Site"},
+			{Field: "archived", Title: "Archived"},
+			{Field: "file", Title: "From"},
+			{Field: "entries", Title: "Entries"},
+			{Field: "state", Title: "State"},
+		},
+	}, rows)
+	fmt.Print(printer.Print())
+	return nil
+}
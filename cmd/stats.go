@@ -0,0 +1,54 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"os"
+
+	"github.com/ravinald/wifimgr/internal/runstats"
+)
+
+// endCommandPhase ends the "command" phase started once initialization is
+// done; nil when --stats is off.
+var endCommandPhase func()
+
+// startStats turns on run accounting for --stats and times initialization.
+// It runs before initialization so the API clients built there are
+// instrumented. The returned function ends the "init" phase and starts the
+// "command" phase.
+func startStats() func() {
+	if !showStats {
+		return func() {}
+	}
+	runstats.Enable()
+	endInit := runstats.Phase("init")
+	return func() {
+		endInit()
+		endCommandPhase = runstats.Phase("command")
+	}
+}
+
+// finishStats prints the run statistics to stderr, keeping stdout clean for
+// json and csv output.
+func finishStats() {
+	if !runstats.Enabled() {
+		return
+	}
+	if endCommandPhase != nil {
+		endCommandPhase()
+	}
+	runstats.Print(os.Stderr, runstats.Snap())
+}
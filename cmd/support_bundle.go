@@ -0,0 +1,170 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/i18n"
+	"github.com/ravinald/wifimgr/internal/supportbundle"
+	"github.com/ravinald/wifimgr/internal/symbols"
+	"github.com/ravinald/wifimgr/internal/xdg"
+)
+
+// supportBundleCmd represents the "support-bundle" command
+var supportBundleCmd = &cobra.Command{
+	Use:   "support-bundle [file <path>]",
+	Short: "Collect a sanitized diagnostics tarball for bug reports",
+	Long: `Collect version info, the main and site config files, cache metadata, and
+recent log lines into a tar.gz to attach to a bug report.
+
+Secrets are removed before anything is written: config files are parsed and
+every credential, PSK, RADIUS secret, and enc: value is replaced with
+[REDACTED]; a config file that does not parse is left out. Cache files
+contribute only refresh times, item counts, and last errors. Log lines are
+scrubbed of token/password/secret values.
+
+Before writing, the included files are listed for review: enter item numbers
+to leave them out, 'v <n>' to view one, or press Enter to write the bundle.
+--yes and --no-input skip the review.
+
+The config is loaded leniently, so the command works when the config is the
+problem; the load error is recorded in version.json.
+
+Arguments:
+  file    Optional. Output path (default: wifimgr-support-<timestamp>.tar.gz)`,
+	Example: `  wifimgr support-bundle
+  wifimgr support-bundle file /tmp/wifimgr-bug.tar.gz`,
+	Annotations: map[string]string{
+		cmdutils.AnnotationNoInit: "true",
+	},
+	RunE: runSupportBundle,
+}
+
+func init() {
+	rootCmd.AddCommand(supportBundleCmd)
+}
+
+func runSupportBundle(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+	output := fmt.Sprintf("wifimgr-support-%s.tar.gz", time.Now().Format("20060102-150405"))
+	for i := 0; i < len(args); i++ {
+		switch strings.ToLower(args[i]) {
+		case "file":
+			if i+1 >= len(args) {
+				return fmt.Errorf("'file' requires a path")
+			}
+			i++
+			output = cmdutils.StripQuotes(args[i])
+		default:
+			return fmt.Errorf("unknown argument %q", args[i])
+		}
+	}
+
+	opts := supportbundle.Options{Build: supportbundle.BuildInfo{Version: Version, GitCommit: GitCommit, BuildTime: BuildTime}}
+	if err := config.InitializeViper(cmd); err != nil {
+		opts.ConfigError = err
+	} else {
+		configPath := configFile
+		if configPath == "" {
+			configPath = xdg.GetConfigFile()
+		}
+		opts.ConfigError = config.LoadViperConfig(configPath)
+	}
+	if opts.ConfigError != nil {
+		fmt.Printf("%s config did not load (%v); collecting with defaults\n", symbols.WarningPrefix(), opts.ConfigError)
+	}
+
+	entries, skipped := supportbundle.Collect(opts)
+	if !cmdutils.AssumeYes() && !cmdutils.NoInput() {
+		var ok bool
+		entries, skipped, ok = reviewSupportBundle(os.Stdin, entries, skipped)
+		if !ok {
+			fmt.Println(i18n.T("support_bundle.cancelled"))
+			return nil
+		}
+	}
+
+	if err := supportbundle.Write(output, entries, skipped); err != nil {
+		return fmt.Errorf("write support bundle: %w", err)
+	}
+	fmt.Printf("%s Wrote %s (%d file(s) plus MANIFEST.txt). Review it before attaching it to an issue.\n", symbols.SuccessPrefix(), output, len(entries))
+	return nil
+}
+
+// reviewSupportBundle lists the bundle contents and lets the operator view or
+// drop entries. It returns the entries to write, the skipped list extended
+// with anything dropped, and false if the operator quit.
+func reviewSupportBundle(in io.Reader, entries []supportbundle.Entry, skipped []supportbundle.Skipped) ([]supportbundle.Entry, []supportbundle.Skipped, bool) {
+	reader := bufio.NewReader(in)
+	excluded := make(map[int]bool)
+	for {
+		fmt.Println(i18n.T("support_bundle.review_header"))
+		for i, e := range entries {
+			mark := " "
+			if excluded[i] {
+				mark = "-"
+			}
+			fmt.Printf(" %s %2d  %-40s %8d  %s\n", mark, i+1, e.Name, len(e.Data), e.Description)
+		}
+		for _, s := range skipped {
+			fmt.Printf("    --  %s: %s\n", s.Source, s.Reason)
+		}
+		fmt.Printf("%s ", i18n.T("support_bundle.review_prompt"))
+
+		line, err := reader.ReadString('\n')
+		line = strings.TrimSpace(line)
+		if err != nil && line == "" {
+			return nil, nil, false
+		}
+		switch {
+		case line == "":
+			var kept []supportbundle.Entry
+			for i, e := range entries {
+				if excluded[i] {
+					skipped = append(skipped, supportbundle.Skipped{Source: e.Name, Reason: "excluded during review"})
+					continue
+				}
+				kept = append(kept, e)
+			}
+			return kept, skipped, true
+		case strings.EqualFold(line, "q"):
+			return nil, nil, false
+		case strings.HasPrefix(strings.ToLower(line), "v "):
+			if n, err := strconv.Atoi(strings.TrimSpace(line[2:])); err == nil && n >= 1 && n <= len(entries) {
+				fmt.Printf("\n--- %s ---\n%s\n", entries[n-1].Name, entries[n-1].Data)
+			}
+		default:
+			for _, field := range strings.FieldsFunc(line, func(r rune) bool { return r == ',' || r == ' ' }) {
+				if n, err := strconv.Atoi(field); err == nil && n >= 1 && n <= len(entries) {
+					excluded[n-1] = !excluded[n-1]
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,400 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/filterexpr"
+	"github.com/ravinald/wifimgr/internal/i18n"
+	"github.com/ravinald/wifimgr/internal/logging"
+	"github.com/ravinald/wifimgr/internal/symbols"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+const tagLong = `%s a device tag on every device matching a filter: the device entries
+in the site intent files and the devices in the API are both updated, so the
+change survives the next apply. Tags drive per-AP WLAN availability and
+policies, so a tag change can move clients.
+
+--match takes the same expression as the show filter keyword, or @name for a
+filter saved under filters in the main config. Each cached device is matched
+as a row with these fields:
+
+  mac, name, type, model, serial, site, api, tags (space-separated)
+
+mac matches in any format and takes a prefix (mac=5c:5b:35:*) or an inclusive
+range (mac=5c5b35000010..5c5b35000040).
+
+Only devices assigned to a site are considered. A matched device with no entry
+in any site config is updated in the API only, and reported as such.
+
+Arguments:
+  tag              Required. The tag
+  target <label>   Optional. Only match devices from this API
+  diff             Optional. Show the plan without changing anything
+  force            Optional. Skip the confirmation prompt
+
+Current tags come from the cache; run 'refresh' first if they were changed
+outside wifimgr.`
+
+// tagCmd groups bulk operations on device tags.
+var tagCmd = &cobra.Command{
+	Use:   "tag",
+	Short: "Add or remove a device tag across many devices",
+	Long: `Add or remove a device tag on every device matching a filter.
+
+Changes are written to the site intent files and pushed to the API in the same
+run, so a later apply does not undo them.`,
+	Example: `  wifimgr tag add guest-ok --match "type=ap && site=US-SFO-*"`,
+}
+
+// tagAddCmd represents the "tag add" command
+var tagAddCmd = &cobra.Command{
+	Use:   "add <tag> --match <filter> [target <api-label>] [diff] [force]",
+	Short: "Add a tag to matching devices (intent and API)",
+	Long:  fmt.Sprintf(tagLong, "Add"),
+	Example: `  wifimgr tag add guest-ok --match "type=ap && site=US-SFO-*"
+  wifimgr tag add lobby --match "name~lobby" diff
+  wifimgr tag add retail --match @retail-aps force`,
+	Args: tagArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTag(cmd, args, true)
+	},
+}
+
+// tagRemoveCmd represents the "tag remove" command
+var tagRemoveCmd = &cobra.Command{
+	Use:   "remove <tag> --match <filter> [target <api-label>] [diff] [force]",
+	Short: "Remove a tag from matching devices (intent and API)",
+	Long:  fmt.Sprintf(tagLong, "Remove"),
+	Example: `  wifimgr tag remove guest-ok --match "tags~guest-ok && site=US-SFO-01"
+  wifimgr tag remove lobby --match "type=ap" target meraki-prod force`,
+	Args: tagArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTag(cmd, args, false)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(tagCmd)
+	tagCmd.AddCommand(tagAddCmd)
+	tagCmd.AddCommand(tagRemoveCmd)
+	for _, c := range []*cobra.Command{tagAddCmd, tagRemoveCmd} {
+		c.Flags().String("match", "", "Filter selecting the devices (expression or @saved-filter)")
+	}
+}
+
+func tagArgs(_ *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return nil
+	}
+	if len(args) < 1 {
+		return fmt.Errorf("requires a tag")
+	}
+	return nil
+}
+
+// tagArgsParsed is the parsed form of the tag add/remove arguments.
+type tagArgsParsed struct {
+	Tag    string
+	Target string
+	Diff   bool
+	Force  bool
+}
+
+func parseTagArgs(args []string) (*tagArgsParsed, error) {
+	parsed := &tagArgsParsed{Tag: strings.TrimSpace(cmdutils.StripQuotes(args[0]))}
+	if parsed.Tag == "" || strings.ContainsAny(parsed.Tag, " \t") {
+		return nil, fmt.Errorf("invalid tag %q: tags are a single word", args[0])
+	}
+	for i := 1; i < len(args); i++ {
+		switch strings.ToLower(args[i]) {
+		case "target":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("'target' requires an API label")
+			}
+			parsed.Target = cmdutils.StripQuotes(args[i+1])
+			i++
+		case "diff":
+			parsed.Diff = true
+		case "force":
+			parsed.Force = true
+		default:
+			return nil, fmt.Errorf("unexpected argument: %s", args[i])
+		}
+	}
+	return parsed, nil
+}
+
+// tagDevice is one cached device matched by a tag command.
+type tagDevice struct {
+	API  string
+	Item *vendors.InventoryItem
+	Tags []string // the device's tags in the API, from the cache
+}
+
+// row is the device as the --match filter sees it.
+func (d tagDevice) row() map[string]any {
+	return map[string]any{
+		"mac":    d.Item.MAC,
+		"name":   d.Item.Name,
+		"type":   d.Item.Type,
+		"model":  d.Item.Model,
+		"serial": d.Item.Serial,
+		"site":   d.Item.SiteName,
+		"api":    d.API,
+		"tags":   strings.Join(d.Tags, " "),
+	}
+}
+
+// matchTagDevices returns the site-assigned devices in the given API caches
+// that where matches, sorted by site, type, then name.
+func matchTagDevices(caches []*vendors.APICache, where *filterexpr.Expr) []tagDevice {
+	var out []tagDevice
+	for _, cache := range caches {
+		for _, section := range []map[string]*vendors.InventoryItem{cache.Inventory.AP, cache.Inventory.Switch, cache.Inventory.Gateway} {
+			for _, item := range section {
+				if item.SiteID == "" {
+					continue
+				}
+				d := tagDevice{API: cache.APILabel, Item: item, Tags: cachedDeviceTags(cache, item)}
+				if where.Match(d.row()) {
+					out = append(out, d)
+				}
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i].Item, out[j].Item
+		if a.SiteName != b.SiteName {
+			return a.SiteName < b.SiteName
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.MAC < b.MAC
+	})
+	return out
+}
+
+// cachedDeviceTags reads a device's tags from its cached config.
+func cachedDeviceTags(cache *vendors.APICache, item *vendors.InventoryItem) []string {
+	mac := vendors.NormalizeMAC(item.MAC)
+	var cfg map[string]interface{}
+	switch item.Type {
+	case "ap":
+		if c := cache.Configs.AP[mac]; c != nil {
+			cfg = c.Config
+		}
+	case "switch":
+		if c := cache.Configs.Switch[mac]; c != nil {
+			cfg = c.Config
+		}
+	case "gateway":
+		if c := cache.Configs.Gateway[mac]; c != nil {
+			cfg = c.Config
+		}
+	}
+	return config.TagList(cfg["tags"])
+}
+
+// withTag returns tags with tag added or removed, and whether that changes it.
+func withTag(tags []string, tag string, add bool) ([]string, bool) {
+	has := slices.Contains(tags, tag)
+	switch {
+	case add && !has:
+		return append(slices.Clone(tags), tag), true
+	case !add && has:
+		return slices.DeleteFunc(slices.Clone(tags), func(s string) bool { return s == tag }), true
+	}
+	return tags, false
+}
+
+func runTag(cmd *cobra.Command, args []string, add bool) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+	parsed, err := parseTagArgs(args)
+	if err != nil {
+		return err
+	}
+	match, _ := cmd.Flags().GetString("match")
+	if strings.TrimSpace(match) == "" {
+		return fmt.Errorf("--match is required; use --match \"type=ap\" to tag every AP")
+	}
+	where, err := filterexpr.Compile(match)
+	if err != nil {
+		return err
+	}
+
+	SetAPITarget(parsed.Target)
+	if err := ValidateAPIFlag(); err != nil {
+		return err
+	}
+	cacheMgr := GetCacheManager()
+	if cacheMgr == nil {
+		return fmt.Errorf("cache manager not initialized")
+	}
+	var caches []*vendors.APICache
+	for _, label := range GetTargetAPIs() {
+		if cache, err := cacheMgr.GetAPICache(label); err == nil {
+			caches = append(caches, cache)
+		}
+	}
+	devices := matchTagDevices(caches, where)
+	if len(devices) == 0 {
+		return fmt.Errorf("no site-assigned devices match %q", where)
+	}
+
+	macs := make(map[string]bool, len(devices))
+	for _, d := range devices {
+		macs[vendors.NormalizeMAC(d.Item.MAC)] = true
+	}
+	plan, err := config.PlanDeviceTags(templateRefFiles(), globalConfig.Files.ConfigDir, parsed.Tag, add, macs)
+	if err != nil {
+		return err
+	}
+	intent := make(map[string]config.DeviceTagChange, len(plan.Devices))
+	for _, c := range plan.Devices {
+		intent[c.MAC] = c
+	}
+
+	verb, prep := "Add", "to"
+	if !add {
+		verb, prep = "Remove", "from"
+	}
+	fmt.Printf("%s tag '%s' %s %d device(s) matching %s:\n", verb, parsed.Tag, prep, len(devices), where)
+	var apiTargets []tagDevice
+	for _, d := range devices {
+		var steps []string
+		if c, ok := intent[vendors.NormalizeMAC(d.Item.MAC)]; !ok {
+			steps = append(steps, "not in a site config")
+		} else if c.Changed {
+			steps = append(steps, "update "+c.File)
+		} else {
+			steps = append(steps, "intent already set")
+		}
+		if _, changes := withTag(d.Tags, parsed.Tag, add); changes {
+			steps = append(steps, "update the API")
+			apiTargets = append(apiTargets, d)
+		} else {
+			steps = append(steps, "API already set")
+		}
+		fmt.Printf("  %s %s %s (%s): %s\n", d.Item.SiteName, d.Item.Type, tagDeviceName(d.Item), d.API, strings.Join(steps, ", "))
+	}
+	files := plan.Files()
+	if (len(files) == 0 && len(apiTargets) == 0) || parsed.Diff {
+		return nil
+	}
+
+	if !parsed.Force {
+		key := "tag.confirm_add"
+		if !add {
+			key = "tag.confirm_remove"
+		}
+		fmt.Printf("%s %s ", i18n.T(key, parsed.Tag, len(devices)), i18n.T("prompt.yes_no"))
+		if !confirmPrompt() {
+			fmt.Println(i18n.T("tag.cancelled"))
+			return nil
+		}
+	}
+
+	if err := applyRewriteWithBackups("updating site configs", files, plan.Apply); err != nil {
+		return err
+	}
+	logging.Infof("%s tag %s in intent for %d device(s) in %d file(s)", verb, parsed.Tag, len(plan.Devices), len(files))
+
+	failed := 0
+	for _, d := range apiTargets {
+		if err := setDeviceTagsAPI(d, parsed.Tag, add); err != nil {
+			failed++
+			fmt.Printf("%s %s: %v\n", symbols.FailurePrefix(), tagDeviceName(d.Item), err)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("intent updated but the API change failed for %d of %d device(s); rerun to retry them", failed, len(apiTargets))
+	}
+	return nil
+}
+
+// setDeviceTagsAPI sends the device's full tag list with tag added or
+// removed; the vendors replace the list rather than merging into it, so the
+// list is built from the device's config as re-fetched just now, not from
+// the cache, which may predate tags set since the last refresh.
+func setDeviceTagsAPI(d tagDevice, tag string, add bool) error {
+	client, err := currentDeps().Client(d.API)
+	if err != nil {
+		return err
+	}
+	svc := client.Devices()
+	if svc == nil {
+		return fmt.Errorf("this feature is not available with this API (%s:%s)", d.API, client.VendorName())
+	}
+	live, err := liveDeviceTags(d)
+	if err != nil {
+		return err
+	}
+	tags, changes := withTag(live, tag, add)
+	if !changes {
+		fmt.Printf("%s %s: tags already %s\n", symbols.SuccessPrefix(), tagDeviceName(d.Item), strings.Join(tags, " "))
+		return nil
+	}
+	if len(tags) == 0 && client.VendorName() == "meraki" {
+		return errors.New("the Meraki API cannot clear a device's last tag; remove it in the dashboard")
+	}
+	list := make([]any, len(tags))
+	for i, t := range tags {
+		list[i] = t
+	}
+	if err := svc.UpdateConfig(globalContext, d.Item.SiteID, d.Item.ID, map[string]interface{}{"tags": list}); err != nil {
+		return err
+	}
+	fmt.Printf("%s %s: tags %s\n", symbols.SuccessPrefix(), tagDeviceName(d.Item), strings.Join(tags, " "))
+	return nil
+}
+
+// liveDeviceTags re-fetches d's config into the cache and returns its tags.
+func liveDeviceTags(d tagDevice) ([]string, error) {
+	cacheMgr := GetCacheManager()
+	if err := cacheMgr.RefreshDeviceConfigs(globalContext, d.API, map[string][]string{d.Item.Type: {d.Item.MAC}}); err != nil {
+		return nil, fmt.Errorf("failed to read current tags: %w", err)
+	}
+	cache, err := cacheMgr.GetAPICache(d.API)
+	if err != nil {
+		return nil, err
+	}
+	return cachedDeviceTags(cache, d.Item), nil
+}
+
+// tagDeviceName labels a device by name, falling back to its MAC.
+func tagDeviceName(item *vendors.InventoryItem) string {
+	if item.Name != "" {
+		return item.Name
+	}
+	return item.MAC
+}
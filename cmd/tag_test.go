@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ravinald/wifimgr/internal/filterexpr"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+func TestParseTagArgs(t *testing.T) {
+	got, err := parseTagArgs([]string{"guest-ok", "target", "mist-prod", "diff", "force"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Tag != "guest-ok" || got.Target != "mist-prod" || !got.Diff || !got.Force {
+		t.Errorf("parsed = %+v", got)
+	}
+	for _, args := range [][]string{{"two words"}, {`""`}, {"lobby", "target"}, {"lobby", "extra"}} {
+		if _, err := parseTagArgs(args); err == nil {
+			t.Errorf("parseTagArgs(%q) should fail", args)
+		}
+	}
+}
+
+func TestMatchTagDevices(t *testing.T) {
+	cache := vendors.NewAPICache("meraki-prod", "meraki", "org-1")
+	cache.Inventory.AP["aa0000000001"] = &vendors.InventoryItem{MAC: "aa0000000001", Name: "ap-lobby", Type: "ap", SiteID: "N_1", SiteName: "US-SFO-01"}
+	cache.Inventory.AP["aa0000000002"] = &vendors.InventoryItem{MAC: "aa0000000002", Name: "ap-2", Type: "ap", SiteID: "N_2", SiteName: "US-NYC-02"}
+	cache.Inventory.AP["aa0000000003"] = &vendors.InventoryItem{MAC: "aa0000000003", Name: "ap-spare", Type: "ap"}
+	cache.Inventory.Switch["aa0000000004"] = &vendors.InventoryItem{MAC: "aa0000000004", Name: "sw-1", Type: "switch", SiteID: "N_1", SiteName: "US-SFO-01"}
+	cache.Configs.AP = map[string]*vendors.APConfig{
+		"aa0000000001": {MAC: "aa0000000001", Config: map[string]interface{}{"tags": "lobby guest-ok"}},
+	}
+
+	where, _ := filterexpr.Parse("type=ap")
+	devices := matchTagDevices([]*vendors.APICache{cache}, where)
+	var names []string
+	for _, d := range devices {
+		names = append(names, d.Item.Name)
+	}
+	if strings.Join(names, ",") != "ap-2,ap-lobby" {
+		t.Errorf("matched %v, want site-assigned APs sorted by site", names)
+	}
+
+	where, _ = filterexpr.Parse("tags~guest-ok")
+	devices = matchTagDevices([]*vendors.APICache{cache}, where)
+	if len(devices) != 1 || strings.Join(devices[0].Tags, ",") != "lobby,guest-ok" || devices[0].API != "meraki-prod" {
+		t.Fatalf("tag match = %+v", devices)
+	}
+
+	if tags, changed := withTag(devices[0].Tags, "guest-ok", false); !changed || strings.Join(tags, ",") != "lobby" {
+		t.Errorf("remove = %v, %v", tags, changed)
+	}
+	if _, changed := withTag(devices[0].Tags, "lobby", true); changed {
+		t.Error("adding a present tag should not change the list")
+	}
+	if strings.Join(devices[0].Tags, ",") != "lobby,guest-ok" {
+		t.Error("withTag must not modify the cached tags")
+	}
+}
@@ -0,0 +1,130 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/logging"
+	"github.com/ravinald/wifimgr/internal/symbols"
+	"github.com/ravinald/wifimgr/internal/telemetry"
+)
+
+// telemetryCmd represents the "telemetry" command
+var telemetryCmd = &cobra.Command{
+	Use:   "telemetry [status|on|off]",
+	Short: "Show or change anonymous usage telemetry",
+	Long: `Show or change opt-in usage telemetry. Telemetry is off by default.
+
+When on, each run records the command name (e.g. "show api status", never its
+arguments), how long it took, an error category (auth, timeout, not_found, ...),
+and the wifimgr version, OS, and architecture. Site, device, and API names,
+config values, and error text are never recorded.
+
+Events are spooled locally and uploaded in batches to telemetry.endpoint when
+one is configured. Turning telemetry off deletes the spool.
+WIFIMGR_TELEMETRY=off or DO_NOT_TRACK=1 disables it for a single environment.
+
+Arguments:
+  status   Show the current setting and spooled event count (default)
+  on       Opt in
+  off      Opt out and delete spooled events`,
+	Example: `  wifimgr telemetry
+  wifimgr telemetry on
+  wifimgr telemetry off`,
+	Annotations: map[string]string{
+		cmdutils.AnnotationNoInit: "true",
+	},
+	Args:      cobra.MaximumNArgs(1),
+	ValidArgs: []string{"status", "on", "off"},
+	RunE:      runTelemetry,
+}
+
+func init() {
+	rootCmd.AddCommand(telemetryCmd)
+}
+
+func runTelemetry(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+	action := "status"
+	if len(args) == 1 {
+		action = strings.ToLower(args[0])
+	}
+
+	switch action {
+	case "status":
+		state := "off"
+		if telemetry.Enabled() {
+			state = "on"
+		}
+		fmt.Printf("Telemetry:  %s\n", state)
+		if telemetry.EnvDisabled() {
+			fmt.Println("            (disabled by WIFIMGR_TELEMETRY or DO_NOT_TRACK)")
+		}
+		endpoint := telemetry.Endpoint()
+		if endpoint == "" {
+			endpoint = "not configured (events stay local)"
+		}
+		fmt.Printf("Endpoint:   %s\n", endpoint)
+		fmt.Printf("Spooled:    %d event(s)\n", telemetry.Pending())
+		fmt.Printf("Directory:  %s\n", telemetry.Dir())
+	case "on":
+		if err := telemetry.SetEnabled(true); err != nil {
+			return err
+		}
+		fmt.Printf("%s Telemetry on. Thank you; see 'wifimgr telemetry help' for what is recorded.\n", symbols.SuccessPrefix())
+		if telemetry.EnvDisabled() {
+			fmt.Printf("%s WIFIMGR_TELEMETRY or DO_NOT_TRACK is set, so nothing is recorded in this environment\n", symbols.WarningPrefix())
+		}
+	case "off":
+		if err := telemetry.SetEnabled(false); err != nil {
+			return err
+		}
+		fmt.Printf("%s Telemetry off; spooled events deleted\n", symbols.SuccessPrefix())
+	default:
+		return fmt.Errorf("unknown argument %q (expected status, on, or off)", args[0])
+	}
+	return nil
+}
+
+// recordTelemetry spools one event for the command that just ran and uploads
+// a full batch. It never fails the run; problems go to the debug log.
+func recordTelemetry(ctx context.Context, cmd *cobra.Command, start time.Time, runErr error) {
+	if cmd == nil || !telemetry.Enabled() {
+		return
+	}
+	command := strings.TrimSpace(strings.TrimPrefix(cmd.CommandPath(), rootCmd.Name()))
+	if command == "" {
+		command = rootCmd.Name()
+	}
+	if err := telemetry.Record(telemetry.NewEvent(command, Version, start, runErr)); err != nil {
+		logging.Debugf("telemetry: %v", err)
+		return
+	}
+	// Upload on a fresh context: a cancelled run should still hand off its
+	// batch within the upload timeout.
+	if err := telemetry.Flush(context.WithoutCancel(ctx), false); err != nil {
+		logging.Debugf("telemetry: %v", err)
+	}
+}
@@ -0,0 +1,278 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/ravinald/wifimgr/cmd/apply"
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/logging"
+	"github.com/ravinald/wifimgr/internal/vendors"
+	"github.com/ravinald/wifimgr/internal/xdg"
+)
+
+// templateImportWLANsCmd represents the "template import-wlans" command
+var templateImportWLANsCmd = &cobra.Command{
+	Use:   "import-wlans site <site-name> [target <api-label>] [save] [file <filename>] [decrypt]",
+	Short: "Convert a site's WLANs from the API into WLAN templates",
+	Long: `Convert the WLANs a site has in the API cache into vendor-neutral WLAN
+template definitions, to bring a brownfield org under templates.
+
+Each WLAN becomes a template labeled with its SSID slug. Portable settings
+(SSID, band, VLAN, auth, client limits, portal) are written as common fields;
+vendor-specific settings go in a "mist:" or "meraki:" block so apply restores
+them on that vendor only. WLANs whose label is already defined in the loaded
+templates are skipped, so importing several sites that share an SSID yields
+one template.
+
+Unlike "import api site", which writes a site-scoped import file, this writes
+a plain template file for files.templates. Reference the labels from site
+configs (profiles.wlan / wlan) to use them.
+
+Arguments:
+  site         Required. Site whose WLANs to convert
+  target       Optional. API label, when the site name exists in several APIs
+  save         Optional. Write the template file (default: print to stdout)
+  file         Optional. Output path (default: templates/wlan_<site>.json)
+  decrypt      Optional. Write secrets in plaintext instead of encrypted`,
+	Example: `  wifimgr template import-wlans site US-LAB-01
+  wifimgr template import-wlans site US-LAB-01 save
+  wifimgr template import-wlans site US-LAB-01 target meraki-corp save file templates/wlan.json`,
+	Args: func(_ *cobra.Command, args []string) error {
+		if cmdutils.ContainsHelp(args) {
+			return nil
+		}
+		if len(args) < 2 || strings.ToLower(args[0]) != "site" {
+			return fmt.Errorf("requires 'site <site-name>'")
+		}
+		return nil
+	},
+	RunE: runTemplateImportWLANs,
+}
+
+func init() {
+	templateCmd.AddCommand(templateImportWLANsCmd)
+}
+
+// mistVendorWLANKeys are Mist WLAN settings with no vendor-neutral field. When
+// set on a Mist WLAN they are kept in the template's "mist:" block; apply
+// passes unknown WLAN keys through to the Mist API unchanged.
+var mistVendorWLANKeys = []string{
+	"roam_mode", "band_steer", "arp_filter", "limit_bcast", "isolation",
+	"l2_isolation", "disable_11ax", "dtim", "max_num_clients", "vlan_enabled",
+}
+
+// mistVendorBlockForWLAN returns the "mist:" block for a Mist WLAN, holding the
+// mistVendorWLANKeys that are set to a non-default value. Returns nil for
+// other vendors or when nothing needs pinning.
+func mistVendorBlockForWLAN(w *vendors.WLAN) map[string]any {
+	if w.SourceVendor != "mist" || w.Config == nil {
+		return nil
+	}
+	block := map[string]any{}
+	for _, key := range mistVendorWLANKeys {
+		v, ok := w.Config[key]
+		if !ok {
+			continue
+		}
+		switch t := v.(type) {
+		case nil:
+			continue
+		case bool:
+			if !t {
+				continue
+			}
+		case string:
+			if t == "" || strings.EqualFold(t, "none") {
+				continue
+			}
+		case float64:
+			if t == 0 {
+				continue
+			}
+		case int:
+			if t == 0 {
+				continue
+			}
+		}
+		block[key] = v
+	}
+	if len(block) == 0 {
+		return nil
+	}
+	return map[string]any{"mist:": block}
+}
+
+// buildWLANTemplatesFromSite converts site WLANs into WLAN template
+// definitions keyed by SSID slug. Labels already in existing are returned in
+// skipped instead; repeated SSIDs within the site get a -2, -3 suffix.
+func buildWLANTemplatesFromSite(ws []*vendors.WLAN, existing *config.TemplateStore, reveal secretReveal) (map[string]map[string]any, []string) {
+	out := make(map[string]map[string]any, len(ws))
+	var skipped []string
+	used := make(map[string]int)
+	for _, w := range ws {
+		if w == nil {
+			continue
+		}
+		base := slug(w.SSID)
+		if base == "" {
+			logging.Warnf("[template import-wlans] skipping WLAN with unnamed/untranslatable SSID: %q", w.SSID)
+			continue
+		}
+		label := base
+		if n := used[base]; n > 0 {
+			label = fmt.Sprintf("%s-%d", base, n+1)
+		}
+		used[base]++
+		if existing != nil {
+			if _, ok := existing.GetWLANTemplate(label); ok {
+				skipped = append(skipped, label)
+				continue
+			}
+		}
+
+		m, err := profileToMap(convertVendorWLANToProfile(w, reveal))
+		if err != nil {
+			logging.Warnf("[template import-wlans] failed to serialize WLAN %q: %v", w.SSID, err)
+			continue
+		}
+		for k, v := range vendorBlockForWLAN(w) {
+			m[k] = v
+		}
+		for k, v := range mistVendorBlockForWLAN(w) {
+			m[k] = v
+		}
+		out[label] = m
+	}
+	sort.Strings(skipped)
+	return out, skipped
+}
+
+// resolveWLANTemplatePath picks the output path: an explicit file (relative to
+// configDir), else <configDir>/templates/wlan_<site-slug>.json.
+func resolveWLANTemplatePath(outputFile, configDir, siteName string) string {
+	if outputFile != "" {
+		if filepath.IsAbs(outputFile) {
+			return outputFile
+		}
+		return filepath.Join(configDir, outputFile)
+	}
+	baseDir := configDir
+	if baseDir == "" {
+		baseDir = xdg.GetConfigDir()
+	}
+	return filepath.Join(baseDir, "templates", fmt.Sprintf("wlan_%s.json", slug(siteName)))
+}
+
+func runTemplateImportWLANs(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+
+	siteName := cmdutils.StripQuotes(args[1])
+	var apiLabel, outputFile string
+	var save, decrypt bool
+	for i := 2; i < len(args); i++ {
+		switch strings.ToLower(args[i]) {
+		case "target":
+			if i+1 >= len(args) {
+				return fmt.Errorf("'target' requires an API label")
+			}
+			apiLabel = args[i+1]
+			i++
+		case "file":
+			if i+1 >= len(args) {
+				return fmt.Errorf("'file' requires a filename")
+			}
+			outputFile = args[i+1]
+			i++
+		case "save":
+			save = true
+		case "decrypt":
+			decrypt = true
+		default:
+			return fmt.Errorf("unexpected argument: %s", args[i])
+		}
+	}
+
+	cacheAccessor, err := cmdutils.GetCacheAccessor()
+	if err != nil {
+		return fmt.Errorf("failed to get cache accessor: %w", err)
+	}
+	ref, err := cmdutils.ResolveSite(siteName, apiLabel)
+	if err != nil {
+		return err
+	}
+	reveal, err := resolveSecretReveal(decrypt)
+	if err != nil {
+		return err
+	}
+	existing, err := apply.LoadTemplateStore(globalConfig)
+	if err != nil {
+		logging.Warnf("Failed to load existing templates, not checking for duplicates: %v", err)
+	}
+
+	wlans, skipped := buildWLANTemplatesFromSite(withoutEphemeralWLANs(cacheAccessor.GetWLANsBySite(ref.SiteID)), existing, reveal)
+	for _, label := range skipped {
+		fmt.Fprintf(os.Stderr, "Skipping '%s': a WLAN template with that label is already defined\n", label)
+	}
+	if len(wlans) == 0 {
+		fmt.Printf("No new WLAN templates to import from site %s.\n", siteName)
+		return nil
+	}
+
+	file := config.TemplateFile{Version: 1, Templates: config.TemplateDefinitions{WLAN: wlans}}
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal templates: %w", err)
+	}
+	if !save {
+		fmt.Println(string(data))
+		return nil
+	}
+
+	configDir := viper.GetString("files.config_dir")
+	outputPath := resolveWLANTemplatePath(outputFile, configDir, siteName)
+	if _, err := os.Stat(outputPath); err == nil {
+		if !confirmOverwrite(outputPath) {
+			fmt.Println("Import cancelled")
+			return nil
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0750); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := os.WriteFile(outputPath, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write template file: %w", err)
+	}
+
+	fmt.Printf("Wrote %d WLAN template(s): %s\n", len(wlans), outputPath)
+	fmt.Printf("\nTo activate, add to your wifimgr-config.json:\n")
+	fmt.Printf("  \"files\": {\n")
+	fmt.Printf("    \"templates\": [ ..., %q ]\n", relativeFromConfigDir(outputPath, configDir))
+	fmt.Printf("  }\n")
+	return nil
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+func TestBuildWLANTemplatesFromSite(t *testing.T) {
+	existing := config.NewTemplateStore()
+	existing.WLAN["guest"] = map[string]any{"ssid": "Guest"}
+
+	wlans := []*vendors.WLAN{
+		{SSID: "Corp", Enabled: true, AuthType: "eap", SourceVendor: "mist", VLANID: 10,
+			Config: map[string]any{"roam_mode": "11r", "arp_filter": true, "isolation": false, "band_steer": false}},
+		{SSID: "Guest", Enabled: true, AuthType: "open", SourceVendor: "mist"},
+		{SSID: "IoT", Enabled: true, AuthType: "psk", SourceVendor: "meraki",
+			Config: map[string]any{"number": float64(3)}},
+		{SSID: "", SourceVendor: "mist"},
+	}
+
+	got, skipped := buildWLANTemplatesFromSite(wlans, existing, secretReveal{})
+	if len(skipped) != 1 || skipped[0] != "guest" {
+		t.Errorf("skipped = %v, want [guest]", skipped)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d templates, want 2: %v", len(got), got)
+	}
+
+	corp := got["corp"]
+	if corp["ssid"] != "Corp" || corp["vlan_id"] != float64(10) {
+		t.Errorf("corp common fields = %v", corp)
+	}
+	mist, ok := corp["mist:"].(map[string]any)
+	if !ok {
+		t.Fatalf("corp has no mist: block: %v", corp)
+	}
+	if mist["roam_mode"] != "11r" || mist["arp_filter"] != true {
+		t.Errorf("mist: block = %v", mist)
+	}
+	if _, ok := mist["isolation"]; ok {
+		t.Error("default-valued isolation should not be pinned")
+	}
+
+	if _, ok := got["iot"]["meraki:"]; !ok {
+		t.Errorf("iot has no meraki: block: %v", got["iot"])
+	}
+	if _, ok := got["iot"]["mist:"]; ok {
+		t.Error("meraki WLAN should not get a mist: block")
+	}
+}
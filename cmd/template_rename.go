@@ -0,0 +1,154 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/i18n"
+	"github.com/ravinald/wifimgr/internal/logging"
+	"github.com/ravinald/wifimgr/internal/symbols"
+)
+
+// templateRenameCmd represents the "template rename" command
+var templateRenameCmd = &cobra.Command{
+	Use:   "rename <old-label> <new-label> [type wlan|radio|device] [diff] [force]",
+	Short: "Rename a template and rewrite every reference to it",
+	Long: `Rename a template and rewrite every site and device reference to it in one
+step. Renaming a template by hand leaves references to the old label behind,
+and apply then skips them with "template not found".
+
+The template definition in files.templates or files.imports and every
+reference in the site configs (see "template where-used") are rewritten. Every
+file is prepared in memory first; each changed file is backed up to the backup
+directory, then written atomically. Only the labels change: the rest of each
+file keeps its key order and formatting. If any write fails, the files already
+written are restored; if a restore fails as well, the error says so and names
+the files to recover from their backups.
+
+Arguments:
+  old-label    Required. Current template label
+  new-label    Required. New template label; must not already exist
+  type         Optional. wlan, radio, device, app_policy, traffic_steering, or
+               security; required when the old label is defined under more than one kind
+  diff         Optional. Show what would change without writing
+  force        Optional. Skip the confirmation prompt`,
+	Example: `  wifimgr template rename corp corp-wifi diff
+  wifimgr template rename corp corp-wifi
+  wifimgr template rename guest visitor type radio force`,
+	Annotations: map[string]string{
+		cmdutils.AnnotationNeedsConfig: "true",
+	},
+	Args: func(_ *cobra.Command, args []string) error {
+		if cmdutils.ContainsHelp(args) {
+			return nil
+		}
+		if len(args) < 2 {
+			return fmt.Errorf("requires the old and new template labels")
+		}
+		return nil
+	},
+	RunE: runTemplateRename,
+}
+
+func init() {
+	templateCmd.AddCommand(templateRenameCmd)
+}
+
+func runTemplateRename(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+
+	oldLabel := cmdutils.StripQuotes(args[0])
+	newLabel := cmdutils.StripQuotes(args[1])
+	var kind string
+	var diffOnly, force bool
+	for i := 2; i < len(args); i++ {
+		switch strings.ToLower(args[i]) {
+		case "type":
+			if i+1 >= len(args) {
+				return fmt.Errorf("'type' requires a template type (wlan, radio, device, app_policy, traffic_steering, security)")
+			}
+			k, err := parseTemplateKind(args[i+1])
+			if err != nil {
+				return err
+			}
+			kind = k
+			i++
+		case "diff":
+			diffOnly = true
+		case "force":
+			force = true
+		default:
+			return fmt.Errorf("unexpected argument: %s", args[i])
+		}
+	}
+
+	plan, err := config.PlanTemplateRename(globalConfig.Files.Templates, templateRefFiles(),
+		globalConfig.Files.ConfigDir, kind, oldLabel, newLabel)
+	if err != nil {
+		return err
+	}
+
+	printTemplateRenamePlan(plan)
+	if diffOnly {
+		return nil
+	}
+	if !force {
+		fmt.Printf("%s %s ", i18n.T("template_rename.confirm", plan.Kind, plan.Old, plan.New, len(plan.Files())), i18n.T("prompt.yes_no"))
+		if !confirmPrompt() {
+			fmt.Println(i18n.T("template_rename.cancelled"))
+			return nil
+		}
+	}
+
+	if err := applyRewriteWithBackups("rename", plan.Files(), plan.Apply); err != nil {
+		return err
+	}
+	logging.Infof("Renamed %s template %s -> %s (%d references)", plan.Kind, plan.Old, plan.New, len(plan.Refs))
+	fmt.Printf("%s Renamed %s template '%s' to '%s' and %d reference(s)\n",
+		symbols.SuccessPrefix(), plan.Kind, plan.Old, plan.New, len(plan.Refs))
+	return nil
+}
+
+// printTemplateRenamePlan lists the definition files and references a rename
+// rewrites.
+func printTemplateRenamePlan(plan *config.TemplateRename) {
+	fmt.Printf("Rename %s template '%s' -> '%s'\n", plan.Kind, plan.Old, plan.New)
+	fmt.Printf("  Defined in: %s\n", strings.Join(plan.Definitions, ", "))
+	if len(plan.Refs) == 0 {
+		fmt.Println("  No site config references it")
+		return
+	}
+	fmt.Printf("  References (%d):\n", len(plan.Refs))
+	for _, r := range plan.Refs {
+		where := r.Site
+		if r.MAC != "" {
+			name := r.MAC
+			if r.DeviceName != "" {
+				name = fmt.Sprintf("%s (%s)", r.DeviceName, r.MAC)
+			}
+			where = fmt.Sprintf("%s %s %s", r.Site, r.DeviceType, name)
+		}
+		fmt.Printf("    %s: %s [%s]\n", where, r.Field, r.File)
+	}
+}
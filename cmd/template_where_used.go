@@ -0,0 +1,229 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ravinald/wifimgr/cmd/apply"
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/formatter"
+	"github.com/ravinald/wifimgr/internal/symbols"
+)
+
+// templateWhereUsedCmd represents the "template where-used" command
+var templateWhereUsedCmd = &cobra.Command{
+	Use:   "where-used <label> [type <kind>] [format json|csv]",
+	Short: "List the sites and devices that reference a template",
+	Long: `List every site and device whose config references a template, to judge the
+blast radius before editing a shared template.
+
+Site-level references are profiles.wlan, profiles.radio, profiles.device, the
+site-wide wlan and wlan_disabled lists, the wlan_overrides keys, and
+wan_edge.app_policies / wan_edge.traffic_steering;
+device-level references are device_template,
+radio_profile, security_baseline, and the device wlan list. Entries set aside under
+devices._disabled are not counted.
+
+Arguments:
+  label        Required. Template label
+  type         Optional. Only count references of one kind: wlan, radio, device,
+               app_policy, traffic_steering, or security
+  format       Optional. "json" or "csv" (default: table)`,
+	Example: `  wifimgr template where-used corp-wifi
+  wifimgr template where-used high-density type radio
+  wifimgr template where-used corp-wifi format csv`,
+	Annotations: map[string]string{
+		cmdutils.AnnotationNeedsConfig: "true",
+	},
+	Args: func(_ *cobra.Command, args []string) error {
+		if cmdutils.ContainsHelp(args) {
+			return nil
+		}
+		if len(args) < 1 {
+			return fmt.Errorf("requires a template label")
+		}
+		return nil
+	},
+	RunE: runTemplateWhereUsed,
+}
+
+func init() {
+	templateCmd.AddCommand(templateWhereUsedCmd)
+}
+
+// parseTemplateKind accepts a template kind keyword; "rf" is an alias for
+// radio, matching import api templates.
+func parseTemplateKind(s string) (string, error) {
+	switch k := strings.ToLower(s); k {
+	case config.TemplateKindWLAN, config.TemplateKindRadio, config.TemplateKindDevice,
+		config.TemplateKindAppPolicy, config.TemplateKindTrafficSteering, config.TemplateKindSecurity:
+		return k, nil
+	case "rf":
+		return config.TemplateKindRadio, nil
+	}
+	return "", fmt.Errorf("invalid template type %q: must be wlan, radio, device, app_policy, traffic_steering, or security", s)
+}
+
+// definedTemplateKinds returns the kinds under which label is defined.
+func definedTemplateKinds(store *config.TemplateStore, label string) []string {
+	var kinds []string
+	if _, ok := store.GetWLANTemplate(label); ok {
+		kinds = append(kinds, config.TemplateKindWLAN)
+	}
+	if _, ok := store.GetRadioTemplate(label); ok {
+		kinds = append(kinds, config.TemplateKindRadio)
+	}
+	if _, ok := store.GetDeviceTemplate(label); ok {
+		kinds = append(kinds, config.TemplateKindDevice)
+	}
+	if _, ok := store.GetAppPolicyTemplate(label); ok {
+		kinds = append(kinds, config.TemplateKindAppPolicy)
+	}
+	if _, ok := store.GetTrafficSteeringTemplate(label); ok {
+		kinds = append(kinds, config.TemplateKindTrafficSteering)
+	}
+	if _, ok := store.GetSecurityTemplate(label); ok {
+		kinds = append(kinds, config.TemplateKindSecurity)
+	}
+	return kinds
+}
+
+// templateRefFiles lists the site config files that may reference templates:
+// site_configs plus import envelopes, the same set apply reads.
+func templateRefFiles() []string {
+	return templateRefFilesFor(globalConfig)
+}
+
+// templateRefFilesFor is templateRefFiles for an explicit config.
+func templateRefFilesFor(cfg *config.Config) []string {
+	files := append([]string{}, cfg.Files.SiteConfigs...)
+	return append(files, cfg.Files.Imports...)
+}
+
+func runTemplateWhereUsed(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+
+	label := cmdutils.StripQuotes(args[0])
+	var kind string
+	format := "table"
+	for i := 1; i < len(args); i++ {
+		switch strings.ToLower(args[i]) {
+		case "type":
+			if i+1 >= len(args) {
+				return fmt.Errorf("'type' requires a template type (wlan, radio, device, app_policy, traffic_steering, security)")
+			}
+			k, err := parseTemplateKind(args[i+1])
+			if err != nil {
+				return err
+			}
+			kind = k
+			i++
+		case "format":
+			if i+1 >= len(args) {
+				return fmt.Errorf("'format' requires a format type (json, csv)")
+			}
+			switch f := strings.ToLower(args[i+1]); f {
+			case "json", "csv":
+				format = f
+			default:
+				return fmt.Errorf("invalid format %q: must be 'json' or 'csv'", args[i+1])
+			}
+			i++
+		default:
+			return fmt.Errorf("unexpected argument: %s", args[i])
+		}
+	}
+
+	store, err := apply.LoadTemplateStore(globalConfig)
+	if err != nil {
+		return fmt.Errorf("failed to load templates: %w", err)
+	}
+	refs, err := config.FindTemplateRefs(templateRefFiles(), globalConfig.Files.ConfigDir, kind, label)
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		out, err := json.MarshalIndent(refs, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal references: %w", err)
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+
+	if format == "table" {
+		if kinds := definedTemplateKinds(store, label); len(kinds) == 0 {
+			fmt.Printf("%s Template '%s' is not defined in files.templates or files.imports\n", symbols.WarningPrefix(), label)
+		} else {
+			fmt.Printf("Template '%s' is defined as: %s\n", label, strings.Join(kinds, ", "))
+		}
+	}
+
+	title := fmt.Sprintf("Template '%s' References (%d)", label, len(refs))
+	if len(refs) == 0 {
+		fmt.Printf("%s:\nNo site config references '%s'\n", title, label)
+		return nil
+	}
+
+	sites := make(map[string]bool)
+	devices := make(map[string]bool)
+	rows := make([]formatter.GenericTableData, 0, len(refs))
+	for _, r := range refs {
+		sites[r.Site] = true
+		if r.MAC != "" {
+			devices[r.Site+"/"+r.MAC] = true
+		}
+		rows = append(rows, formatter.GenericTableData{
+			"kind":   r.Kind,
+			"site":   r.Site,
+			"type":   r.DeviceType,
+			"device": r.DeviceName,
+			"mac":    r.MAC,
+			"field":  r.Field,
+			"file":   r.File,
+		})
+	}
+	printer := formatter.NewGenericTablePrinter(formatter.TableConfig{
+		Title:         title,
+		Format:        format,
+		BoldHeaders:   true,
+		ShowSeparator: true,
+		CommandPath:   "template.where-used",
+		Columns: []formatter.TableColumn{
+			{Field: "kind", Title: "Kind"},
+			{Field: "site", Title: "Site"},
+			{Field: "type", Title: "Type"},
+			{Field: "device", Title: "Device"},
+			{Field: "mac", Title: "MAC"},
+			{Field: "field", Title: "Field"},
+			{Field: "file", Title: "File"},
+		},
+	}, rows)
+	fmt.Print(printer.Print())
+	if format == "table" {
+		fmt.Printf("\n%d site(s), %d device(s)\n", len(sites), len(devices))
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ravinald/wifimgr/internal/config"
+)
+
+func TestParseTemplateKind(t *testing.T) {
+	for in, want := range map[string]string{"wlan": "wlan", "RADIO": "radio", "rf": "radio", "device": "device"} {
+		got, err := parseTemplateKind(in)
+		if err != nil || got != want {
+			t.Errorf("parseTemplateKind(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := parseTemplateKind("gateway"); err == nil {
+		t.Error("parseTemplateKind(gateway) should fail")
+	}
+}
+
+func TestDefinedTemplateKinds(t *testing.T) {
+	store := config.NewTemplateStore()
+	store.WLAN["shared"] = map[string]any{"ssid": "Shared"}
+	store.Radio["shared"] = map[string]any{}
+	store.Device["ap-std"] = map[string]any{}
+
+	if got := definedTemplateKinds(store, "shared"); !reflect.DeepEqual(got, []string{"wlan", "radio"}) {
+		t.Errorf("shared: got %v", got)
+	}
+	if got := definedTemplateKinds(store, "missing"); got != nil {
+		t.Errorf("missing: got %v", got)
+	}
+}
@@ -0,0 +1,70 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/logging"
+)
+
+// The deadline applied to the running command, kept so Execute can release
+// it and explain a timeout.
+var (
+	commandTimeout       time.Duration
+	commandTimeoutPath   string
+	cancelCommandTimeout context.CancelFunc = func() {}
+)
+
+// applyCommandTimeout bounds the command's context, and with it every vendor
+// call made under globalContext or cmd.Context(), by the timeout configured
+// for its path. See config.CommandTimeout.
+func applyCommandTimeout(cmd *cobra.Command) {
+	path := commandTimeoutKey(cmd)
+	d := config.CommandTimeout(path)
+	if d == 0 {
+		logging.Debugf("No timeout for %q", path)
+		return
+	}
+	ctx, cancel := context.WithTimeout(globalContext, d)
+	globalContext = ctx
+	cmd.SetContext(ctx)
+	commandTimeout, commandTimeoutPath, cancelCommandTimeout = d, path, cancel
+	logging.Debugf("Timeout for %q: %s", path, d)
+}
+
+// commandTimeoutKey is the command's path without the program name, as
+// used under timeouts.commands.
+func commandTimeoutKey(cmd *cobra.Command) string {
+	return strings.TrimSpace(strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()))
+}
+
+// explainCommandTimeout adds the timeout and the setting that controls it to
+// an error caused by the command running out of time.
+func explainCommandTimeout(err error) error {
+	if err == nil || commandTimeout == 0 || !errors.Is(globalContext.Err(), context.DeadlineExceeded) {
+		return err
+	}
+	return fmt.Errorf("%w: %q ran longer than its %s timeout; set a longer one for it under timeouts.commands in the config",
+		err, commandTimeoutPath, commandTimeout)
+}
@@ -0,0 +1,36 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// troubleshootCmd groups diagnostics that query live vendor event data.
+var troubleshootCmd = &cobra.Command{
+	Use:   "troubleshoot",
+	Short: "Diagnose connectivity problems from live vendor events",
+	Long: `Diagnose connectivity problems from live vendor events.
+
+Troubleshooting commands query the API directly rather than the local cache,
+so the events are current but each run costs API calls.`,
+	Example: `  # Why can't this laptop get on the network?
+  wifimgr troubleshoot client aa:bb:cc:dd:ee:ff`,
+}
+
+func init() {
+	rootCmd.AddCommand(troubleshootCmd)
+}
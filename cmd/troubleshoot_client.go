@@ -0,0 +1,522 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/formatter"
+	"github.com/ravinald/wifimgr/internal/macaddr"
+	"github.com/ravinald/wifimgr/internal/symbols"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+// defaultTroubleshootWindow is how far back troubleshoot client looks when
+// 'last' is not given.
+const defaultTroubleshootWindow = 24 * time.Hour
+
+// troubleshootTimelineRows caps the failure timeline in table output; JSON
+// carries every failure.
+const troubleshootTimelineRows = 20
+
+// troubleshootClientCmd represents the "troubleshoot client" command
+var troubleshootClientCmd = &cobra.Command{
+	Use:   "client <mac> [last <duration>] [target <api-label>] [format json]",
+	Short: "Explain a client's authentication, DHCP, ARP and DNS failures",
+	Long: `Pull a wireless client's recent connection events and explain them.
+
+Failures are grouped by stage — authentication, DHCP, ARP (gateway
+reachability) and DNS — with the APs and SSIDs involved and the likely
+causes. Failures confined to one AP point at that AP's switchport or VLAN
+trunking; failures across many APs point at a shared service such as RADIUS
+or the DHCP server.
+
+Arguments:
+  mac              Required. Client MAC address (any common format)
+  last <duration>  Optional. How far back to look, e.g. 2h, 30m, 3d (default: 24h)
+  target <label>   Optional. Only query this API
+  format json      Optional. Machine-readable diagnosis
+
+Vendor support: Mist. APIs without client events are skipped.`,
+	Example: `  wifimgr troubleshoot client aa:bb:cc:dd:ee:ff
+  wifimgr troubleshoot client aabb.ccdd.eeff last 2h
+  wifimgr troubleshoot client aa:bb:cc:dd:ee:ff last 3d format json`,
+	Args: func(_ *cobra.Command, args []string) error {
+		if cmdutils.ContainsHelp(args) {
+			return nil
+		}
+		if len(args) < 1 {
+			return fmt.Errorf("requires a client MAC address")
+		}
+		return nil
+	},
+	RunE: runTroubleshootClient,
+}
+
+func init() {
+	troubleshootCmd.AddCommand(troubleshootClientCmd)
+}
+
+// troubleshootClientArgs is the parsed form of the troubleshoot client
+// arguments.
+type troubleshootClientArgs struct {
+	MAC    string
+	Window time.Duration
+	Target string
+	Format string
+}
+
+func parseTroubleshootClientArgs(args []string) (*troubleshootClientArgs, error) {
+	parsed := &troubleshootClientArgs{Window: defaultTroubleshootWindow, Format: "table"}
+	for i := 0; i < len(args); i++ {
+		switch strings.ToLower(args[i]) {
+		case "last":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("'last' requires a duration (e.g. 2h, 3d)")
+			}
+			d, err := parseTroubleshootWindow(args[i+1])
+			if err != nil {
+				return nil, err
+			}
+			parsed.Window = d
+			i++
+		case "target":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("'target' requires an API label")
+			}
+			parsed.Target = cmdutils.StripQuotes(args[i+1])
+			i++
+		case "format":
+			if i+1 >= len(args) || !strings.EqualFold(args[i+1], "json") {
+				return nil, fmt.Errorf("'format' must be followed by 'json'")
+			}
+			parsed.Format = "json"
+			i++
+		default:
+			if parsed.MAC != "" {
+				return nil, fmt.Errorf("unexpected argument %q", args[i])
+			}
+			mac, err := macaddr.Normalize(args[i])
+			if err != nil {
+				return nil, fmt.Errorf("invalid client MAC %q: %w", args[i], err)
+			}
+			parsed.MAC = mac
+		}
+	}
+	if parsed.MAC == "" {
+		return nil, fmt.Errorf("requires a client MAC address")
+	}
+	return parsed, nil
+}
+
+// parseTroubleshootWindow accepts Go durations plus a whole-day "Nd" form.
+func parseTroubleshootWindow(s string) (time.Duration, error) {
+	var d time.Duration
+	var err error
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		var n int
+		n, err = strconv.Atoi(days)
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		d, err = time.ParseDuration(s)
+	}
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid 'last' value %q: use a positive duration such as 30m, 2h or 3d", s)
+	}
+	return d, nil
+}
+
+func runTroubleshootClient(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+
+	parsed, err := parseTroubleshootClientArgs(args)
+	if err != nil {
+		return err
+	}
+	SetAPITarget(parsed.Target)
+	if err := ValidateAPIFlag(); err != nil {
+		return err
+	}
+	registry := GetAPIRegistry()
+	if registry == nil {
+		return fmt.Errorf("API registry not initialized")
+	}
+
+	end := time.Now()
+	start := end.Add(-parsed.Window)
+	var events []*vendors.ClientEvent
+	queried := 0
+	for _, apiLabel := range GetTargetAPIs() {
+		client, err := registry.GetClient(apiLabel)
+		if err != nil {
+			continue
+		}
+		svc := client.ClientEvents()
+		if svc == nil {
+			if parsed.Target != "" {
+				return fmt.Errorf("this feature is not available with this API (%s:%s)", apiLabel, client.VendorName())
+			}
+			continue
+		}
+		queried++
+		found, err := svc.Search(globalContext, parsed.MAC, start, end)
+		if err != nil {
+			return fmt.Errorf("failed to fetch client events from %s: %w", apiLabel, err)
+		}
+		events = append(events, found...)
+	}
+	if queried == 0 {
+		return fmt.Errorf("no configured API provides client events")
+	}
+
+	diag := diagnoseClientEvents(parsed.MAC, start, end, events, troubleshootAPName)
+
+	if parsed.Format == "json" {
+		out, err := json.MarshalIndent(diag, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal diagnosis: %w", err)
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+	printClientDiagnosis(diag)
+	return nil
+}
+
+// troubleshootAPName resolves an AP MAC to its cached name, falling back to
+// the MAC when the AP is not in the cache.
+func troubleshootAPName(mac string) string {
+	if mac == "" {
+		return ""
+	}
+	if accessor := vendors.GetGlobalCacheAccessor(); accessor != nil {
+		if item, err := accessor.GetDeviceByMAC(mac); err == nil && item.Name != "" {
+			return item.Name
+		}
+	}
+	return mac
+}
+
+// Failure stages, in the order a client meets them when joining.
+const (
+	stageAuth = "authentication"
+	stageDHCP = "dhcp"
+	stageARP  = "arp"
+	stageDNS  = "dns"
+)
+
+var stageOrder = map[string]int{stageAuth: 0, stageDHCP: 1, stageARP: 2, stageDNS: 3}
+
+// clientDiagnosis is the troubleshoot client result.
+type clientDiagnosis struct {
+	MAC       string          `json:"mac"`
+	Start     time.Time       `json:"start"`
+	End       time.Time       `json:"end"`
+	Events    int             `json:"events"`
+	Failures  int             `json:"failures"`
+	LastSeen  *clientSighting `json:"last_seen,omitempty"`
+	Findings  []clientFinding `json:"findings"`
+	Timeline  []clientFailure `json:"timeline"`
+	Narrative []string        `json:"narrative"`
+}
+
+// clientSighting is where the client's most recent event happened.
+type clientSighting struct {
+	Time time.Time `json:"time"`
+	AP   string    `json:"ap,omitempty"`
+	SSID string    `json:"ssid,omitempty"`
+	Band string    `json:"band,omitempty"`
+}
+
+// clientFinding summarizes the failures at one stage.
+type clientFinding struct {
+	Stage  string    `json:"stage"`
+	Count  int       `json:"count"`
+	First  time.Time `json:"first"`
+	Last   time.Time `json:"last"`
+	APs    []string  `json:"aps"`
+	SSIDs  []string  `json:"ssids"`
+	Causes []string  `json:"likely_causes"`
+}
+
+// clientFailure is one failure event on the timeline.
+type clientFailure struct {
+	Time  time.Time `json:"time"`
+	Stage string    `json:"stage"`
+	Type  string    `json:"type"`
+	AP    string    `json:"ap,omitempty"`
+	SSID  string    `json:"ssid,omitempty"`
+	Text  string    `json:"text,omitempty"`
+}
+
+// classifyClientEvent returns the join stage an event belongs to and
+// whether it records a failure. Event codes vary by vendor and firmware, so
+// the match is on keywords rather than exact codes. Deauthentication is a
+// disconnect, not an authentication failure.
+func classifyClientEvent(ev *vendors.ClientEvent) (stage string, failure bool) {
+	code := strings.ToUpper(ev.Type)
+	switch {
+	case strings.Contains(code, "DHCP"):
+		stage = stageDHCP
+	case strings.Contains(code, "ARP"):
+		stage = stageARP
+	case strings.Contains(code, "DNS"):
+		stage = stageDNS
+	case strings.Contains(code, "DEAUTH"):
+		return "", false
+	case strings.Contains(code, "AUTH"), strings.Contains(code, "EAP"), strings.Contains(code, "PSK"),
+		strings.Contains(code, "RADIUS"), strings.Contains(code, "DOT1X"), strings.Contains(code, "8021X"):
+		stage = stageAuth
+	default:
+		return "", false
+	}
+	for _, marker := range []string{"FAIL", "NAK", "STUCK", "TIMEOUT", "TIMED_OUT", "DENIED", "REJECT"} {
+		if strings.Contains(code, marker) {
+			return stage, true
+		}
+	}
+	return stage, false
+}
+
+// diagnoseClientEvents groups failure events by stage and writes the
+// narrative. apName resolves AP MACs for display.
+func diagnoseClientEvents(mac string, start, end time.Time, events []*vendors.ClientEvent, apName func(string) string) *clientDiagnosis {
+	diag := &clientDiagnosis{
+		MAC:      mac,
+		Start:    start,
+		End:      end,
+		Events:   len(events),
+		Findings: []clientFinding{},
+		Timeline: []clientFailure{},
+	}
+
+	sorted := make([]*vendors.ClientEvent, len(events))
+	copy(sorted, events)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Timestamp.Before(sorted[j].Timestamp) })
+
+	allAPs := map[string]bool{}
+	byStage := map[string]*clientFinding{}
+	stageAPs := map[string]map[string]bool{}
+	stageSSIDs := map[string]map[string]bool{}
+	stageTexts := map[string][]string{}
+	for _, ev := range sorted {
+		ap := apName(ev.APMAC)
+		if ap != "" {
+			allAPs[ap] = true
+		}
+		diag.LastSeen = &clientSighting{Time: ev.Timestamp, AP: ap, SSID: ev.SSID, Band: ev.Band}
+
+		stage, failure := classifyClientEvent(ev)
+		if !failure {
+			continue
+		}
+		diag.Failures++
+		diag.Timeline = append(diag.Timeline, clientFailure{Time: ev.Timestamp, Stage: stage, Type: ev.Type, AP: ap, SSID: ev.SSID, Text: ev.Text})
+
+		f, ok := byStage[stage]
+		if !ok {
+			f = &clientFinding{Stage: stage, First: ev.Timestamp}
+			byStage[stage] = f
+			stageAPs[stage] = map[string]bool{}
+			stageSSIDs[stage] = map[string]bool{}
+		}
+		f.Count++
+		f.Last = ev.Timestamp
+		if ap != "" {
+			stageAPs[stage][ap] = true
+		}
+		if ev.SSID != "" {
+			stageSSIDs[stage][ev.SSID] = true
+		}
+		stageTexts[stage] = append(stageTexts[stage], strings.ToUpper(ev.Type+" "+ev.Text))
+	}
+
+	for stage, f := range byStage {
+		f.APs = sortedKeys(stageAPs[stage])
+		f.SSIDs = sortedKeys(stageSSIDs[stage])
+		f.Causes = likelyCauses(f, stageTexts[stage], len(allAPs))
+		diag.Findings = append(diag.Findings, *f)
+	}
+	sort.SliceStable(diag.Findings, func(i, j int) bool {
+		return stageOrder[diag.Findings[i].Stage] < stageOrder[diag.Findings[j].Stage]
+	})
+	// Newest failures first on the timeline.
+	for i, j := 0, len(diag.Timeline)-1; i < j; i, j = i+1, j-1 {
+		diag.Timeline[i], diag.Timeline[j] = diag.Timeline[j], diag.Timeline[i]
+	}
+
+	diag.Narrative = clientNarrative(diag)
+	return diag
+}
+
+// likelyCauses lists the usual causes for a stage's failures, most likely
+// first, then says whether the failures are confined to one AP.
+func likelyCauses(f *clientFinding, texts []string, apsSeen int) []string {
+	mentions := func(word string) bool {
+		for _, t := range texts {
+			if strings.Contains(t, word) {
+				return true
+			}
+		}
+		return false
+	}
+
+	var causes []string
+	switch f.Stage {
+	case stageAuth:
+		if mentions("PSK") {
+			causes = append(causes, "Wrong passphrase saved on the client (PSK mismatch); forget the network and re-enter it")
+		}
+		causes = append(causes,
+			"RADIUS server unreachable or rejecting the credentials; check the server log for this client",
+			"Expired or untrusted certificate in the 802.1X exchange (server certificate or client certificate)")
+	case stageDHCP:
+		if mentions("NAK") {
+			causes = append(causes, "DHCP NAK: the client asked for an address from another subnet (VLAN changed or stale lease)")
+		}
+		causes = append(causes,
+			"Client VLAN not trunked to the AP's switchport, so DHCP requests never reach the server",
+			"DHCP scope exhausted, or no relay (ip helper) on the client VLAN")
+	case stageARP:
+		causes = append(causes,
+			"Default gateway not answering ARP on the client VLAN (gateway interface down or VLAN mismatch)",
+			"Address conflict: another host answering for the client's leased IP")
+	case stageDNS:
+		causes = append(causes,
+			"DNS servers handed out by DHCP unreachable from the client VLAN, or blocked by policy")
+	}
+
+	switch {
+	case len(f.APs) == 1 && apsSeen > 1:
+		causes = append(causes, fmt.Sprintf("Only AP %s is affected while other APs served this client: check that AP's switchport and VLAN trunking", f.APs[0]))
+	case len(f.APs) > 1:
+		causes = append(causes, fmt.Sprintf("Failures span %d APs: a shared service is more likely than a single AP", len(f.APs)))
+	}
+	return causes
+}
+
+// clientNarrative renders the diagnosis as plain sentences.
+func clientNarrative(diag *clientDiagnosis) []string {
+	window := diag.End.Sub(diag.Start).Round(time.Minute)
+	if diag.Events == 0 {
+		return []string{fmt.Sprintf("No events recorded for %s in the last %s. The client may not have tried to join, or it uses a randomized MAC on this network.", diag.MAC, window)}
+	}
+
+	lines := []string{fmt.Sprintf("%d events recorded for %s in the last %s, %d of them failures.", diag.Events, diag.MAC, window, diag.Failures)}
+	if diag.LastSeen != nil {
+		where := joinNonEmpty(", ", prefixed("AP ", diag.LastSeen.AP), prefixed("SSID ", diag.LastSeen.SSID), prefixed("band ", diag.LastSeen.Band))
+		lines = append(lines, fmt.Sprintf("Most recent event at %s (%s).", diag.LastSeen.Time.Local().Format("2006-01-02 15:04:05"), where))
+	}
+	if len(diag.Findings) == 0 {
+		lines = append(lines, "No authentication, DHCP, ARP or DNS failures: the client is joining cleanly. Look at RF (signal, roaming) or the application next.")
+		return lines
+	}
+
+	first := diag.Findings[0]
+	lines = append(lines, fmt.Sprintf("The earliest failing stage is %s; later stages only run once it succeeds, so fix it first.", first.Stage))
+	for _, f := range diag.Findings {
+		where := joinNonEmpty("; ", prefixed("APs: ", strings.Join(f.APs, ", ")), prefixed("SSIDs: ", strings.Join(f.SSIDs, ", ")))
+		lines = append(lines, fmt.Sprintf("%s failed %d time(s) between %s and %s (%s). Likely: %s.",
+			strings.ToUpper(f.Stage[:1])+f.Stage[1:], f.Count,
+			f.First.Local().Format("15:04:05"), f.Last.Local().Format("15:04:05"), where, f.Causes[0]))
+	}
+	return lines
+}
+
+func prefixed(prefix, value string) string {
+	if value == "" {
+		return ""
+	}
+	return prefix + value
+}
+
+func joinNonEmpty(sep string, parts ...string) string {
+	kept := parts[:0]
+	for _, p := range parts {
+		if p != "" {
+			kept = append(kept, p)
+		}
+	}
+	return strings.Join(kept, sep)
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func printClientDiagnosis(diag *clientDiagnosis) {
+	for _, line := range diag.Narrative {
+		fmt.Println(line)
+	}
+	if len(diag.Findings) == 0 {
+		if diag.Events > 0 {
+			fmt.Printf("%s No failures found\n", symbols.SuccessPrefix())
+		}
+		return
+	}
+
+	for _, f := range diag.Findings {
+		fmt.Printf("\n%s %s: %d failure(s)\n", symbols.WarningPrefix(), strings.ToUpper(f.Stage[:1])+f.Stage[1:], f.Count)
+		for _, cause := range f.Causes {
+			fmt.Printf("  - %s\n", cause)
+		}
+	}
+	fmt.Println()
+
+	rows := make([]formatter.GenericTableData, 0, troubleshootTimelineRows)
+	for i, ev := range diag.Timeline {
+		if i == troubleshootTimelineRows {
+			break
+		}
+		rows = append(rows, formatter.GenericTableData{
+			"time":  ev.Time.Local().Format("2006-01-02 15:04:05"),
+			"stage": ev.Stage,
+			"type":  ev.Type,
+			"ap":    ev.AP,
+			"ssid":  ev.SSID,
+		})
+	}
+	printer := formatter.NewGenericTablePrinter(formatter.TableConfig{
+		Title:         fmt.Sprintf("Recent Failures — %s (%d of %d)", diag.MAC, len(rows), len(diag.Timeline)),
+		Format:        "table",
+		BoldHeaders:   true,
+		ShowSeparator: true,
+		CommandPath:   "troubleshoot.client",
+		Columns: []formatter.TableColumn{
+			{Field: "time", Title: "Time"},
+			{Field: "stage", Title: "Stage"},
+			{Field: "type", Title: "Event"},
+			{Field: "ap", Title: "AP"},
+			{Field: "ssid", Title: "SSID"},
+		},
+	}, rows)
+	fmt.Print(printer.Print())
+}
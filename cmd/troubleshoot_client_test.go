@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+func TestClassifyClientEvent(t *testing.T) {
+	tests := []struct {
+		code    string
+		stage   string
+		failure bool
+	}{
+		{"CLIENT_AUTH_FAILURE", stageAuth, true},
+		{"MARVIS_EVENT_CLIENT_AUTH_FAILURE", stageAuth, true},
+		{"CLIENT_AUTHENTICATED", stageAuth, false},
+		{"CLIENT_DEAUTHENTICATED", "", false},
+		{"CLIENT_DHCP_STUCK", stageDHCP, true},
+		{"CLIENT_DHCP_NAK", stageDHCP, true},
+		{"CLIENT_GW_ARP_FAILURE", stageARP, true},
+		{"CLIENT_DNS_FAILURE", stageDNS, true},
+		{"CLIENT_ASSOCIATION", "", false},
+	}
+	for _, tt := range tests {
+		stage, failure := classifyClientEvent(&vendors.ClientEvent{Type: tt.code})
+		if stage != tt.stage || failure != tt.failure {
+			t.Errorf("%s: got (%q, %v), want (%q, %v)", tt.code, stage, failure, tt.stage, tt.failure)
+		}
+	}
+}
+
+func TestDiagnoseClientEvents(t *testing.T) {
+	base := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+	at := func(min int) time.Time { return base.Add(time.Duration(min) * time.Minute) }
+	events := []*vendors.ClientEvent{
+		{Timestamp: at(5), Type: "CLIENT_DHCP_STUCK", APMAC: "ap2", SSID: "Corp"},
+		{Timestamp: at(0), Type: "CLIENT_ASSOCIATION", APMAC: "ap1", SSID: "Corp"},
+		{Timestamp: at(1), Type: "CLIENT_AUTHENTICATED", APMAC: "ap1", SSID: "Corp"},
+		{Timestamp: at(2), Type: "CLIENT_IP_ASSIGNED", APMAC: "ap1", SSID: "Corp"},
+		{Timestamp: at(4), Type: "CLIENT_ASSOCIATION", APMAC: "ap2", SSID: "Corp"},
+		{Timestamp: at(6), Type: "CLIENT_DHCP_STUCK", APMAC: "ap2", SSID: "Corp", Band: "5"},
+	}
+	names := map[string]string{"ap1": "ap-lobby", "ap2": "ap-conf"}
+
+	diag := diagnoseClientEvents("aabbccddeeff", base.Add(-time.Hour), at(10), events, func(mac string) string { return names[mac] })
+
+	if diag.Events != 6 || diag.Failures != 2 {
+		t.Fatalf("events = %d, failures = %d; want 6, 2", diag.Events, diag.Failures)
+	}
+	if len(diag.Findings) != 1 || diag.Findings[0].Stage != stageDHCP {
+		t.Fatalf("findings = %+v, want one dhcp finding", diag.Findings)
+	}
+	f := diag.Findings[0]
+	if len(f.APs) != 1 || f.APs[0] != "ap-conf" || !f.First.Equal(at(5)) || !f.Last.Equal(at(6)) {
+		t.Errorf("finding = %+v", f)
+	}
+	if last := f.Causes[len(f.Causes)-1]; !strings.Contains(last, "Only AP ap-conf") {
+		t.Errorf("single-AP failures should be called out, got %q", last)
+	}
+	if diag.LastSeen == nil || diag.LastSeen.AP != "ap-conf" || diag.LastSeen.Band != "5" {
+		t.Errorf("last seen = %+v", diag.LastSeen)
+	}
+	if !diag.Timeline[0].Time.Equal(at(6)) {
+		t.Errorf("timeline should list newest failure first: %+v", diag.Timeline)
+	}
+	if !strings.Contains(strings.Join(diag.Narrative, "\n"), "earliest failing stage is dhcp") {
+		t.Errorf("narrative = %q", diag.Narrative)
+	}
+}
+
+func TestDiagnoseClientEventsStageOrder(t *testing.T) {
+	now := time.Now()
+	events := []*vendors.ClientEvent{
+		{Timestamp: now, Type: "CLIENT_DNS_FAILURE", APMAC: "ap1"},
+		{Timestamp: now, Type: "CLIENT_AUTH_FAILURE_PSK", APMAC: "ap1"},
+		{Timestamp: now, Type: "CLIENT_AUTH_FAILURE_PSK", APMAC: "ap2"},
+	}
+
+	diag := diagnoseClientEvents("aabbccddeeff", now.Add(-time.Hour), now, events, func(mac string) string { return mac })
+
+	if len(diag.Findings) != 2 || diag.Findings[0].Stage != stageAuth || diag.Findings[1].Stage != stageDNS {
+		t.Fatalf("findings should follow join order: %+v", diag.Findings)
+	}
+	auth := diag.Findings[0]
+	if !strings.Contains(auth.Causes[0], "passphrase") {
+		t.Errorf("PSK failures should lead with the passphrase cause: %q", auth.Causes)
+	}
+	if last := auth.Causes[len(auth.Causes)-1]; !strings.Contains(last, "span 2 APs") {
+		t.Errorf("multi-AP failures should be called out, got %q", last)
+	}
+}
+
+func TestParseTroubleshootClientArgs(t *testing.T) {
+	parsed, err := parseTroubleshootClientArgs([]string{"AA:BB:CC:DD:EE:FF", "last", "3d", "format", "json"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if parsed.MAC != "aabbccddeeff" || parsed.Window != 72*time.Hour || parsed.Format != "json" {
+		t.Errorf("parsed = %+v", parsed)
+	}
+
+	for _, args := range [][]string{
+		{"not-a-mac"},
+		{"aabbccddeeff", "last", "-2h"},
+		{"aabbccddeeff", "format", "csv"},
+		{"last", "2h"},
+	} {
+		if _, err := parseTroubleshootClientArgs(args); err == nil {
+			t.Errorf("expected error for %v", args)
+		}
+	}
+}
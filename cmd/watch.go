@@ -0,0 +1,324 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/macaddr"
+	"github.com/ravinald/wifimgr/internal/symbols"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+// Poll interval bounds for watch site, in seconds. The floor keeps a
+// forgotten watch from hammering an org-wide status endpoint.
+const (
+	watchDefaultInterval = 30
+	watchMinInterval     = 5
+)
+
+// watchCmd groups commands that follow live state until interrupted.
+var watchCmd = &cobra.Command{
+	Use:     "watch",
+	Short:   "Follow live device state until interrupted",
+	Long:    `Follow live device state and print changes as they happen. Press Ctrl-C to stop.`,
+	Example: `  wifimgr watch site US-LAB-01`,
+	Annotations: map[string]string{
+		cmdutils.AnnotationNeedsConfig: "true",
+	},
+}
+
+// watchSiteCmd represents the "watch site" command
+var watchSiteCmd = &cobra.Command{
+	Use:   "site <site-name> [target <api-label>] [every <seconds>]",
+	Short: "Print a live up/down ticker for a site's devices",
+	Long: `Poll device status for one site and print a timestamped line each time a
+device goes up or down, for watching a site come back during maintenance.
+
+The first line summarizes the site and names the devices that are not online.
+After that only changes are printed, each followed by the new totals. When
+the site's intent declares a contact, it is printed whenever a device drops. A failed
+poll prints a warning and the watch carries on; press Ctrl-C to stop.
+
+Devices are the site's devices in the local cache; run 'refresh' first if
+devices were added or moved since the last refresh. Status is polled: none
+of the supported vendors' integrations receive status webhooks.
+
+Arguments:
+  site-name          Required. Site to watch
+  target <label>     Optional. API owning the site (when the name is ambiguous)
+  every <seconds>    Optional. Poll interval (default: 30, minimum: 5)`,
+	Example: `  wifimgr watch site US-LAB-01
+  wifimgr watch site US-LAB-01 every 10`,
+	Args: func(_ *cobra.Command, args []string) error {
+		if cmdutils.ContainsHelp(args) {
+			return nil
+		}
+		if len(args) < 1 {
+			return fmt.Errorf("requires a site name")
+		}
+		return nil
+	},
+	RunE: runWatchSite,
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+	watchCmd.AddCommand(watchSiteCmd)
+}
+
+// watchArgs is the parsed form of the watch site arguments.
+type watchArgs struct {
+	SiteName string
+	Target   string
+	Interval time.Duration
+}
+
+// parseWatchArgs parses `<site-name> [target <api-label>] [every <seconds>]`.
+func parseWatchArgs(args []string) (*watchArgs, error) {
+	result := &watchArgs{Interval: watchDefaultInterval * time.Second}
+	for i := 0; i < len(args); i++ {
+		switch strings.ToLower(args[i]) {
+		case "target":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("'target' requires an API label")
+			}
+			result.Target = cmdutils.StripQuotes(args[i+1])
+			i++
+		case "every":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("'every' requires a number of seconds")
+			}
+			secs, err := strconv.Atoi(args[i+1])
+			if err != nil || secs < watchMinInterval {
+				return nil, fmt.Errorf("invalid 'every' value %q: must be at least %d seconds", args[i+1], watchMinInterval)
+			}
+			result.Interval = time.Duration(secs) * time.Second
+			i++
+		default:
+			if result.SiteName != "" {
+				return nil, fmt.Errorf("unexpected argument %q", args[i])
+			}
+			result.SiteName = cmdutils.StripQuotes(args[i])
+		}
+	}
+	if result.SiteName == "" {
+		return nil, fmt.Errorf("requires a site name")
+	}
+	return result, nil
+}
+
+func runWatchSite(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+	parsed, err := parseWatchArgs(args)
+	if err != nil {
+		return err
+	}
+
+	deps := currentDeps()
+	ref, err := deps.ResolveSite(parsed.SiteName, parsed.Target)
+	if err != nil {
+		return err
+	}
+	client, err := deps.Client(ref.APILabel)
+	if err != nil {
+		return err
+	}
+	svc := client.Statuses()
+	if svc == nil {
+		return fmt.Errorf("this feature is not available with this API (%s:%s)", ref.APILabel, client.VendorName())
+	}
+
+	watcher := newSiteWatcher(svc, deps.Cache.GetDevicesBySite(ref.SiteID, ""))
+	watcher.contact = siteContact(ref.Name).String()
+	if len(watcher.devices) == 0 {
+		return fmt.Errorf("no devices cached for site %s; run 'refresh' first", ref.Name)
+	}
+	return watcher.run(deps.Ctx, os.Stdout, ref.Name, ref.APILabel, parsed.Interval)
+}
+
+// watchDevice is one device followed by a site watch.
+type watchDevice struct {
+	MAC  string // normalized
+	Name string
+	Type string
+}
+
+// statusChange is one device's status moving between two polls.
+type statusChange struct {
+	Device   watchDevice
+	From, To string
+}
+
+// siteWatcher polls device status for a fixed set of devices and reports
+// what changed since the previous poll.
+type siteWatcher struct {
+	svc     vendors.StatusesService
+	devices []watchDevice
+	last    map[string]string // MAC -> status at the previous poll
+	contact string            // the site's intent contact, repeated when a device drops
+}
+
+// newSiteWatcher follows items, sorted by type then name.
+func newSiteWatcher(svc vendors.StatusesService, items []*vendors.InventoryItem) *siteWatcher {
+	w := &siteWatcher{svc: svc}
+	for _, item := range items {
+		mac := macaddr.NormalizeOrEmpty(item.MAC)
+		if mac == "" {
+			continue
+		}
+		name := item.Name
+		if name == "" {
+			name, _ = macaddr.Format(mac, macaddr.FormatColon)
+		}
+		w.devices = append(w.devices, watchDevice{MAC: mac, Name: name, Type: item.Type})
+	}
+	sort.Slice(w.devices, func(i, j int) bool {
+		if w.devices[i].Type != w.devices[j].Type {
+			return w.devices[i].Type < w.devices[j].Type
+		}
+		return w.devices[i].Name < w.devices[j].Name
+	})
+	return w
+}
+
+// poll fetches current status and returns the devices whose status changed
+// since the previous poll. The first poll only records the baseline.
+func (w *siteWatcher) poll(ctx context.Context) ([]statusChange, error) {
+	all, err := w.svc.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	current := make(map[string]string, len(w.devices))
+	var changes []statusChange
+	for _, d := range w.devices {
+		status := "unknown"
+		if s, ok := all[d.MAC]; ok && s != nil && s.Status != "" {
+			status = s.Status
+		}
+		current[d.MAC] = status
+		if w.last != nil && w.last[d.MAC] != status {
+			changes = append(changes, statusChange{Device: d, From: w.last[d.MAC], To: status})
+		}
+	}
+	w.last = current
+	return changes, nil
+}
+
+// summary counts the last poll by status ("12 online, 2 offline") and lists
+// the devices that are not online.
+func (w *siteWatcher) summary() (string, []string) {
+	counts := make(map[string]int)
+	var down []string
+	for _, d := range w.devices {
+		status := w.last[d.MAC]
+		counts[status]++
+		if status != "online" {
+			down = append(down, d.Name)
+		}
+	}
+	statuses := make([]string, 0, len(counts))
+	for s := range counts {
+		statuses = append(statuses, s)
+	}
+	// online first, the rest alphabetically
+	sort.Slice(statuses, func(i, j int) bool {
+		if (statuses[i] == "online") != (statuses[j] == "online") {
+			return statuses[i] == "online"
+		}
+		return statuses[i] < statuses[j]
+	})
+	parts := make([]string, len(statuses))
+	for i, s := range statuses {
+		parts[i] = fmt.Sprintf("%d %s", counts[s], s)
+	}
+	return strings.Join(parts, ", "), down
+}
+
+// formatStatusChange renders one change: ↑ when a device comes online, ↓ when
+// it drops, ~ for any other move (e.g. offline to alerting).
+func formatStatusChange(c statusChange) string {
+	marker := "~"
+	switch {
+	case c.To == "online":
+		marker = symbols.GreenText("↑")
+	case c.From == "online":
+		marker = symbols.RedText("↓")
+	}
+	return fmt.Sprintf("%s %s (%s) %s → %s", marker, c.Device.Name, c.Device.Type, c.From, c.To)
+}
+
+// run prints the baseline and then every change until ctx is cancelled.
+func (w *siteWatcher) run(ctx context.Context, out io.Writer, site, apiLabel string, interval time.Duration) error {
+	if _, err := w.poll(ctx); err != nil {
+		return fmt.Errorf("failed to fetch device status for %s: %w", site, err)
+	}
+	_, _ = fmt.Fprintf(out, "Watching %s via %s: %d device(s), polling every %s. Press Ctrl-C to stop.\n",
+		site, apiLabel, len(w.devices), interval)
+	counts, down := w.summary()
+	line := fmt.Sprintf("%s  %s", time.Now().Format(time.TimeOnly), counts)
+	if len(down) > 0 {
+		line += "  (not online: " + strings.Join(down, ", ") + ")"
+	}
+	_, _ = fmt.Fprintln(out, line)
+	if len(down) > 0 && w.contact != "" {
+		_, _ = fmt.Fprintf(out, "%s  contact: %s\n", time.Now().Format(time.TimeOnly), w.contact)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		changes, err := w.poll(ctx)
+		stamp := time.Now().Format(time.TimeOnly)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			_, _ = fmt.Fprintf(out, "%s  %s status poll failed: %v\n", stamp, symbols.WarningPrefix(), err)
+			continue
+		}
+		if len(changes) == 0 {
+			continue
+		}
+		dropped := false
+		for _, c := range changes {
+			_, _ = fmt.Fprintf(out, "%s  %s\n", stamp, formatStatusChange(c))
+			dropped = dropped || c.From == "online"
+		}
+		counts, _ := w.summary()
+		_, _ = fmt.Fprintf(out, "%s  %s\n", stamp, counts)
+		if dropped && w.contact != "" {
+			_, _ = fmt.Fprintf(out, "%s  contact: %s\n", stamp, w.contact)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+func TestParseWatchArgs(t *testing.T) {
+	got, err := parseWatchArgs([]string{"US-LAB-01", "every", "10", "target", "mist-prod"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.SiteName != "US-LAB-01" || got.Target != "mist-prod" || got.Interval != 10*time.Second {
+		t.Errorf("parsed = %+v", got)
+	}
+	if got, _ := parseWatchArgs([]string{"US-LAB-01"}); got.Interval != watchDefaultInterval*time.Second {
+		t.Errorf("default interval = %s", got.Interval)
+	}
+	for _, args := range [][]string{{}, {"US-LAB-01", "every", "1"}, {"US-LAB-01", "every"}, {"US-LAB-01", "US-LAB-02"}} {
+		if _, err := parseWatchArgs(args); err == nil {
+			t.Errorf("parseWatchArgs(%v) should fail", args)
+		}
+	}
+}
+
+func TestSiteWatcherPoll(t *testing.T) {
+	svc := &vendors.MockStatusesService{Statuses: map[string]*vendors.DeviceStatus{
+		"aa0000000001": {Status: "offline"},
+		"aa0000000002": {Status: "online"},
+	}}
+	w := newSiteWatcher(svc, []*vendors.InventoryItem{
+		{MAC: "aa:00:00:00:00:02", Name: "sw-1", Type: "switch"},
+		{MAC: "aa:00:00:00:00:01", Name: "ap-lobby", Type: "ap"},
+		{MAC: "aa:00:00:00:00:03", Type: "ap"},
+	})
+	if w.devices[0].Name != "aa:00:00:00:00:03" || w.devices[2].Name != "sw-1" {
+		t.Errorf("devices = %+v, want sorted by type then name with MAC for unnamed", w.devices)
+	}
+
+	ctx := context.Background()
+	if changes, err := w.poll(ctx); err != nil || len(changes) != 0 {
+		t.Fatalf("baseline poll = %v, %v; want no changes", changes, err)
+	}
+	counts, down := w.summary()
+	if counts != "1 online, 1 offline, 1 unknown" || strings.Join(down, ",") != "aa:00:00:00:00:03,ap-lobby" {
+		t.Errorf("summary = %q, down %v", counts, down)
+	}
+
+	svc.Statuses["aa0000000001"] = &vendors.DeviceStatus{Status: "online"}
+	svc.Statuses["aa0000000002"] = &vendors.DeviceStatus{Status: "offline"}
+	changes, err := w.poll(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 2 || changes[0].Device.Name != "ap-lobby" || changes[0].From != "offline" || changes[0].To != "online" {
+		t.Fatalf("changes = %+v", changes)
+	}
+	if got := formatStatusChange(changes[0]); !strings.Contains(got, "↑ ap-lobby (ap) offline → online") {
+		t.Errorf("up line = %q", got)
+	}
+	if got := formatStatusChange(changes[1]); !strings.Contains(got, "↓ sw-1 (switch) online → offline") {
+		t.Errorf("down line = %q", got)
+	}
+
+	if changes, _ := w.poll(ctx); len(changes) != 0 {
+		t.Errorf("unchanged poll = %+v", changes)
+	}
+}
+
+func TestSiteWatcherRunPrintsContact(t *testing.T) {
+	svc := &vendors.MockStatusesService{Statuses: map[string]*vendors.DeviceStatus{"aa0000000001": {Status: "offline"}}}
+	w := newSiteWatcher(svc, []*vendors.InventoryItem{{MAC: "aa0000000001", Name: "ap-lobby", Type: "ap"}})
+	w.contact = "Jane Doe, +1 555 0100 (escalate: netops-oncall)"
+
+	// A cancelled context prints the baseline and returns.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var out bytes.Buffer
+	if err := w.run(ctx, &out, "US-LAB-01", "mist-prod", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Watching US-LAB-01 via mist-prod", "not online: ap-lobby", "contact: Jane Doe, +1 555 0100 (escalate: netops-oncall)"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+}
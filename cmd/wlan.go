@@ -0,0 +1,39 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// wlanCmd groups operations on WLANs across sites.
+var wlanCmd = &cobra.Command{
+	Use:   "wlan",
+	Short: "Operate on WLANs across many sites",
+	Long: `Operate on WLANs across many sites at once.
+
+Changes are written to the site intent files and pushed to the API in the same
+run, so a later apply does not undo them.`,
+	Example: `  # Emergency shutdown of the guest SSID at every retail site
+  wifimgr wlan disable guest-wifi sites @retail force
+
+  # Broadcast a template as a test SSID at one site for two hours
+  wifimgr wlan test-deploy corp-wpa3 --site US-LAB-01 --ttl 2h`,
+}
+
+func init() {
+	rootCmd.AddCommand(wlanCmd)
+}
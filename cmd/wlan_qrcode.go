@@ -0,0 +1,196 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ravinald/wifimgr/cmd/apply"
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/encryption"
+	"github.com/ravinald/wifimgr/internal/i18n"
+	"github.com/ravinald/wifimgr/internal/qrcode"
+	"github.com/ravinald/wifimgr/internal/symbols"
+)
+
+// qrPNGScale is the PNG size of one QR module, in pixels.
+const qrPNGScale = 10
+
+// wlanQRCodeCmd represents the "wlan qrcode" command
+var wlanQRCodeCmd = &cobra.Command{
+	Use:   "qrcode <label|ssid> [png <file>] [invert] [force]",
+	Short: "Render a WiFi-join QR code for a WLAN template",
+	Long: `Render a QR code that joins a WLAN when scanned with a phone camera, built
+from the WLAN template's SSID, auth type, and passphrase — for a guest network
+sign or a conference room card.
+
+The WLAN is named by its template label, or by SSID when exactly one WLAN
+template broadcasts it. A passphrase stored encrypted (enc:) is decrypted,
+prompting for the encryption password. Because the code carries the
+passphrase, you are asked to confirm first; force or --yes skips the prompt.
+
+Open and OWE networks produce a code without a passphrase. Enterprise (802.1X)
+networks cannot be joined from a QR code and are refused.
+
+Arguments:
+  label|ssid    Required. WLAN template label or SSID
+  png <file>    Optional. Write a PNG instead of printing to the terminal
+  invert        Optional. Swap colours, for terminals with a light background
+  force         Optional. Skip the confirmation prompt`,
+	Example: `  wifimgr wlan qrcode guest-wifi
+  wifimgr wlan qrcode "Guest WiFi" png guest.png
+  wifimgr wlan qrcode conf-rooms invert`,
+	Annotations: map[string]string{
+		cmdutils.AnnotationNeedsConfig: "true",
+	},
+	Args: func(_ *cobra.Command, args []string) error {
+		if cmdutils.ContainsHelp(args) {
+			return nil
+		}
+		if len(args) < 1 {
+			return fmt.Errorf("requires a WLAN label or SSID")
+		}
+		return nil
+	},
+	RunE: runWLANQRCode,
+}
+
+func init() {
+	wlanCmd.AddCommand(wlanQRCodeCmd)
+}
+
+func runWLANQRCode(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+	var pngPath string
+	invert, force := false, false
+	for i := 1; i < len(args); i++ {
+		switch strings.ToLower(args[i]) {
+		case "png":
+			if i+1 >= len(args) {
+				return fmt.Errorf("'png' requires a file path")
+			}
+			i++
+			pngPath = cmdutils.StripQuotes(args[i])
+		case "invert":
+			invert = true
+		case "force":
+			force = true
+		default:
+			return fmt.Errorf("unexpected argument: %s", args[i])
+		}
+	}
+
+	store, err := apply.LoadTemplateStore(globalConfig)
+	if err != nil {
+		return fmt.Errorf("failed to load templates: %w", err)
+	}
+	label, err := resolveWLANLabel(store, cmdutils.StripQuotes(args[0]))
+	if err != nil {
+		return err
+	}
+	tmpl, _ := store.GetWLANTemplate(label)
+	join, err := wlanJoinFromTemplate(tmpl)
+	if err != nil {
+		return fmt.Errorf("WLAN '%s': %w", label, err)
+	}
+
+	if join.Passphrase != "" {
+		if !force {
+			fmt.Printf("%s %s ", i18n.T("wlan_qrcode.confirm", label), i18n.T("prompt.yes_no"))
+			if !confirmPrompt() {
+				fmt.Println(i18n.T("wlan_qrcode.cancelled"))
+				return nil
+			}
+		}
+		if encryption.IsEncrypted(join.Passphrase) {
+			pw, err := encryption.GetPasswordOrPrompt("Enter encryption password to decrypt the passphrase: ")
+			if err != nil {
+				return fmt.Errorf("decrypting the passphrase requires the encryption password: %w", err)
+			}
+			if join.Passphrase, err = encryption.Decrypt(join.Passphrase, pw); err != nil {
+				return fmt.Errorf("failed to decrypt the passphrase of WLAN '%s': %w", label, err)
+			}
+		}
+	}
+
+	code, err := qrcode.Encode(join.String())
+	if err != nil {
+		return fmt.Errorf("WLAN '%s': %w", label, err)
+	}
+
+	if pngPath != "" {
+		f, err := os.OpenFile(pngPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+		if err != nil {
+			return err
+		}
+		if err := code.WritePNG(f, qrPNGScale); err != nil {
+			_ = f.Close()
+			return fmt.Errorf("failed to write %s: %w", pngPath, err)
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+		fmt.Printf("%s Wrote QR code for SSID %q to %s\n", symbols.SuccessPrefix(), join.SSID, pngPath)
+		return nil
+	}
+
+	fmt.Printf("SSID: %s (%s)\n", join.SSID, join.Security)
+	fmt.Print(code.Terminal(invert))
+	return nil
+}
+
+// wlanJoinFromTemplate reads the SSID, auth, and hidden flag of a WLAN
+// template, falling back to its vendor blocks. The passphrase is returned as
+// stored, possibly still enc:.
+func wlanJoinFromTemplate(tmpl map[string]any) (qrcode.WiFi, error) {
+	flat := make(map[string]any, 3)
+	for _, key := range []string{"ssid", "hidden", "auth"} {
+		if v := wlanTemplateValue(tmpl, key); v != nil {
+			flat[key] = v
+		}
+	}
+	return qrcode.WiFiFromWLAN(flat)
+}
+
+// wlanTemplateValue returns key from the template's common fields, else from
+// the first vendor block (in name order) that sets it.
+func wlanTemplateValue(tmpl map[string]any, key string) any {
+	if v, ok := tmpl[key]; ok {
+		return v
+	}
+	var vendors []string
+	for k := range tmpl {
+		if strings.HasSuffix(k, ":") {
+			vendors = append(vendors, k)
+		}
+	}
+	sort.Strings(vendors)
+	for _, k := range vendors {
+		if block, ok := tmpl[k].(map[string]any); ok {
+			if v, ok := block[key]; ok {
+				return v
+			}
+		}
+	}
+	return nil
+}
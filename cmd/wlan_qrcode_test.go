@@ -0,0 +1,49 @@
+package cmd
+
+import "testing"
+
+func TestWLANJoinFromTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		tmpl    map[string]any
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "psk with escapes",
+			tmpl: map[string]any{"ssid": "Conf;Room", "auth": map[string]any{"type": "psk", "psk": `p:a"ss`}},
+			want: `WIFI:T:WPA;S:Conf\;Room;P:p\:a\"ss;;`,
+		},
+		{
+			name: "open and hidden",
+			tmpl: map[string]any{"ssid": "guest", "hidden": true},
+			want: "WIFI:T:nopass;S:guest;H:true;;",
+		},
+		{
+			name: "ssid from vendor block",
+			tmpl: map[string]any{"mist:": map[string]any{"ssid": "lab", "auth": map[string]any{"type": "sae", "psk": "x"}}},
+			want: "WIFI:T:WPA;S:lab;P:x;;",
+		},
+		{
+			name:    "enterprise refused",
+			tmpl:    map[string]any{"ssid": "corp", "auth": map[string]any{"type": "wpa2-enterprise"}},
+			wantErr: true,
+		},
+		{
+			name:    "psk missing",
+			tmpl:    map[string]any{"ssid": "corp", "auth": map[string]any{"type": "psk"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			j, err := wlanJoinFromTemplate(tt.tmpl)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && j.String() != tt.want {
+				t.Errorf("got %s, want %s", j.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,391 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ravinald/wifimgr/cmd/apply"
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/ephemeral"
+	"github.com/ravinald/wifimgr/internal/formatter"
+	"github.com/ravinald/wifimgr/internal/i18n"
+	"github.com/ravinald/wifimgr/internal/symbols"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+// testSSIDSuffix is added to the template's SSID when --ssid is not given,
+// so the test SSID never collides with the production one at the same site.
+const testSSIDSuffix = "-test"
+
+// wlanTestDeployCmd represents the "wlan test-deploy" command
+var wlanTestDeployCmd = &cobra.Command{
+	Use:   "test-deploy <label|ssid> --site <site-name> [--ttl 2h] [--ssid <name>]",
+	Short: "Broadcast a temporary test SSID from a WLAN template",
+	Long: `Create a temporary WLAN at one site from a WLAN template, to validate the
+template on real clients before it goes into intent.
+
+The test SSID is the template's SSID with "-test" appended unless --ssid is
+given, and is created in the API only: site intent is not touched, so the
+next apply neither removes nor keeps it. It is recorded as ephemeral in the
+state directory, which keeps it out of 'import api' and 'template
+import-wlans', and is deleted by 'wlan test-cleanup' once its TTL has run
+out. Run test-cleanup from cron or a schedule job for the removal to happen
+on time.
+
+Arguments:
+  label|ssid       Required. WLAN template label or SSID
+  --site <name>    Required. Site to broadcast at
+  --ttl <dur>      Optional. Lifetime (default: 2h, maximum: 168h)
+  --ssid <name>    Optional. SSID to broadcast instead of <template-ssid>-test`,
+	Example: `  wifimgr wlan test-deploy corp-wpa3 --site US-LAB-01 --ttl 2h
+  wifimgr wlan test-deploy guest-wifi --site US-LAB-01 --ssid guest-pilot --ttl 30m`,
+	Args: func(_ *cobra.Command, args []string) error {
+		if cmdutils.ContainsHelp(args) {
+			return nil
+		}
+		if len(args) != 1 {
+			return fmt.Errorf("requires a WLAN template label or SSID")
+		}
+		return nil
+	},
+	RunE: runWLANTestDeploy,
+}
+
+// wlanTestCleanupCmd represents the "wlan test-cleanup" command
+var wlanTestCleanupCmd = &cobra.Command{
+	Use:   "test-cleanup [<ssid>|all] [dry-run]",
+	Short: "Remove test SSIDs whose TTL has run out",
+	Long: `Delete the test SSIDs created by 'wlan test-deploy' whose TTL has run out,
+and drop them from the ephemeral record. A test SSID already removed in the
+vendor's dashboard is dropped from the record too.
+
+Arguments:
+  ssid             Optional. Remove this test SSID now, whatever its TTL
+  all              Optional. Remove every test SSID now
+  dry-run          Optional. Show what would be removed`,
+	Example: `  wifimgr wlan test-cleanup
+  wifimgr wlan test-cleanup corp-test
+  */10 * * * * wifimgr --no-input wlan test-cleanup`,
+	RunE: runWLANTestCleanup,
+}
+
+// wlanTestListCmd represents the "wlan test-list" command
+var wlanTestListCmd = &cobra.Command{
+	Use:     "test-list",
+	Short:   "List the test SSIDs and when they expire",
+	Long:    `List the test SSIDs created by 'wlan test-deploy' that are still recorded, soonest to expire first.`,
+	Example: `  wifimgr wlan test-list`,
+	Annotations: map[string]string{
+		cmdutils.AnnotationNeedsConfig: "true",
+	},
+	RunE: runWLANTestList,
+}
+
+func init() {
+	wlanCmd.AddCommand(wlanTestDeployCmd)
+	wlanCmd.AddCommand(wlanTestCleanupCmd)
+	wlanCmd.AddCommand(wlanTestListCmd)
+	wlanTestDeployCmd.Flags().String("site", "", "Site to broadcast the test SSID at")
+	wlanTestDeployCmd.Flags().Duration("ttl", 2*time.Hour, "Lifetime of the test SSID")
+	wlanTestDeployCmd.Flags().String("ssid", "", "SSID to broadcast (default: the template's SSID with -test)")
+}
+
+// testWLANConfig expands the template for the vendor and turns it into the
+// test SSID: renamed, and enabled whatever the template says.
+func testWLANConfig(tmpl map[string]any, vendor, ssid string) (map[string]any, error) {
+	cfg := config.ExpandForVendor(tmpl, vendor)
+	if ssid == "" {
+		base, _ := cfg["ssid"].(string)
+		if base == "" {
+			return nil, fmt.Errorf("the template has no ssid; give one with --ssid")
+		}
+		ssid = base + testSSIDSuffix
+	}
+	cfg["ssid"] = ssid
+	cfg["enabled"] = true
+	return cfg, nil
+}
+
+// validateTestTTL checks the --ttl value.
+func validateTestTTL(ttl time.Duration) error {
+	if ttl <= 0 {
+		return fmt.Errorf("--ttl must be positive, e.g. 2h")
+	}
+	if ttl > ephemeral.MaxTTL {
+		return fmt.Errorf("--ttl %s is longer than the %s maximum; put a long-lived SSID in intent instead", ttl, ephemeral.MaxTTL)
+	}
+	return nil
+}
+
+func runWLANTestDeploy(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+	siteName, _ := cmd.Flags().GetString("site")
+	ttl, _ := cmd.Flags().GetDuration("ttl")
+	ssid, _ := cmd.Flags().GetString("ssid")
+	if siteName == "" {
+		return fmt.Errorf("--site is required")
+	}
+	if err := validateTestTTL(ttl); err != nil {
+		return err
+	}
+
+	store, err := apply.LoadTemplateStore(globalConfig)
+	if err != nil {
+		return fmt.Errorf("failed to load templates: %w", err)
+	}
+	label, err := resolveWLANLabel(store, cmdutils.StripQuotes(args[0]))
+	if err != nil {
+		return err
+	}
+	tmpl, _ := store.GetWLANTemplate(label)
+
+	deps := currentDeps()
+	ref, err := deps.ResolveSite(siteName, "")
+	if err != nil {
+		return err
+	}
+	client, err := deps.Client(ref.APILabel)
+	if err != nil {
+		return err
+	}
+	svc := client.WLANs()
+	if svc == nil {
+		return fmt.Errorf("this feature is not available with this API (%s:%s)", ref.APILabel, client.VendorName())
+	}
+	cfg, err := testWLANConfig(tmpl, client.VendorName(), strings.TrimSpace(ssid))
+	if err != nil {
+		return err
+	}
+	ssid = cfg["ssid"].(string)
+
+	existing, err := svc.ListBySite(deps.Ctx, ref.SiteID)
+	if err != nil {
+		return fmt.Errorf("failed to list WLANs: %w", err)
+	}
+	for _, w := range existing {
+		if w.SSID == ssid {
+			return fmt.Errorf("%s already broadcasts '%s'; choose another with --ssid", ref.Name, ssid)
+		}
+	}
+
+	fmt.Printf("%s %s ", i18n.T("wlan_test.confirm_deploy", ssid, label, ref.Name, ttl), i18n.T("prompt.yes_no"))
+	if !confirmPrompt() {
+		fmt.Println(i18n.T("wlan_toggle.cancelled"))
+		return nil
+	}
+
+	now := time.Now()
+	id, err := apply.CreateSiteWLAN(deps.Ctx, client, ref.SiteID, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create test SSID '%s': %w", ssid, err)
+	}
+	rec := ephemeral.WLAN{
+		API:      ref.APILabel,
+		SiteID:   ref.SiteID,
+		Site:     ref.Name,
+		ID:       id,
+		SSID:     ssid,
+		Template: label,
+		Created:  now,
+		Expires:  now.Add(ttl),
+	}
+	if err := ephemeral.Add(rec); err != nil {
+		// Without the record nothing removes it; say so rather than leave it.
+		return fmt.Errorf("created '%s' at %s but could not record it (%w); delete it by hand", ssid, ref.Name, err)
+	}
+	fmt.Printf("%s Broadcasting '%s' at %s until %s\n", symbols.SuccessPrefix(), ssid, ref.Name, rec.Expires.Local().Format("2006-01-02 15:04"))
+	fmt.Println("  'wifimgr wlan test-cleanup' removes it once the TTL has run out")
+	return nil
+}
+
+// selectTestWLANs splits the recorded test SSIDs into those to remove and
+// those to keep: the expired ones, the named one, or all.
+func selectTestWLANs(wlans []ephemeral.WLAN, which string, now time.Time) (remove, keep []ephemeral.WLAN) {
+	for _, w := range wlans {
+		switch {
+		case strings.EqualFold(which, "all"),
+			which != "" && w.SSID == which,
+			which == "" && w.Expired(now):
+			remove = append(remove, w)
+		default:
+			keep = append(keep, w)
+		}
+	}
+	return remove, keep
+}
+
+func runWLANTestCleanup(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+	var which string
+	dryRun := false
+	for _, arg := range args {
+		switch strings.ToLower(arg) {
+		case "dry-run", "dryrun":
+			dryRun = true
+		default:
+			if which != "" {
+				return fmt.Errorf("unexpected argument: %s", arg)
+			}
+			which = cmdutils.StripQuotes(arg)
+		}
+	}
+
+	wlans, err := ephemeral.Load()
+	if err != nil {
+		return err
+	}
+	remove, keep := selectTestWLANs(wlans, which, time.Now())
+	if which != "" && !strings.EqualFold(which, "all") && len(remove) == 0 {
+		return fmt.Errorf("no test SSID '%s' is recorded; see 'wifimgr wlan test-list'", which)
+	}
+	if len(remove) == 0 {
+		fmt.Printf("%s No test SSIDs to remove (%d still within their TTL)\n", symbols.SuccessPrefix(), len(keep))
+		return nil
+	}
+	if dryRun {
+		for _, w := range remove {
+			fmt.Printf("  Would remove '%s' at %s (expires %s)\n", w.SSID, w.Site, w.Expires.Local().Format("2006-01-02 15:04"))
+		}
+		return nil
+	}
+
+	deps := currentDeps()
+	failed := 0
+	for _, w := range remove {
+		if err := removeTestWLAN(deps, w); err != nil {
+			fmt.Printf("%s '%s' at %s: %v\n", symbols.FailurePrefix(), w.SSID, w.Site, err)
+			keep = append(keep, w)
+			failed++
+		}
+	}
+	if err := ephemeral.Save(keep); err != nil {
+		return fmt.Errorf("failed to update the test SSID record: %w", err)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d test SSID(s) could not be removed; they stay recorded for the next run", failed)
+	}
+	return nil
+}
+
+// removeTestWLAN deletes one test SSID. One that is no longer at the site,
+// or whose ID now carries another SSID, is only dropped from the record.
+func removeTestWLAN(deps *Deps, w ephemeral.WLAN) error {
+	client, err := deps.Client(w.API)
+	if err != nil {
+		return err
+	}
+	svc := client.WLANs()
+	if svc == nil {
+		return fmt.Errorf("this feature is not available with this API (%s:%s)", w.API, client.VendorName())
+	}
+	current, err := svc.ListBySite(deps.Ctx, w.SiteID)
+	if err != nil {
+		return fmt.Errorf("failed to list WLANs: %w", err)
+	}
+	if !testWLANPresent(current, w) {
+		fmt.Printf("%s '%s' at %s is already gone\n", symbols.SuccessPrefix(), w.SSID, w.Site)
+		return nil
+	}
+	if err := apply.DeleteSiteWLAN(deps.Ctx, client, w.SiteID, w.ID); err != nil {
+		return err
+	}
+	fmt.Printf("%s Removed '%s' at %s\n", symbols.SuccessPrefix(), w.SSID, w.Site)
+	return nil
+}
+
+// testWLANPresent reports whether the site still has the test SSID under its
+// recorded ID.
+func testWLANPresent(current []*vendors.WLAN, w ephemeral.WLAN) bool {
+	for _, c := range current {
+		if c.ID == w.ID && c.SSID == w.SSID {
+			return true
+		}
+	}
+	return false
+}
+
+func runWLANTestList(cmd *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+	wlans, err := ephemeral.Load()
+	if err != nil {
+		return err
+	}
+	if len(wlans) == 0 {
+		fmt.Println("No test SSIDs recorded")
+		return nil
+	}
+	now := time.Now()
+	rows := make([]formatter.GenericTableData, 0, len(wlans))
+	for _, w := range wlans {
+		left := "expired"
+		if !w.Expired(now) {
+			left = w.Expires.Sub(now).Round(time.Minute).String()
+		}
+		rows = append(rows, formatter.GenericTableData{
+			"site_name": w.Site,
+			"ssid":      w.SSID,
+			"template":  w.Template,
+			"api":       w.API,
+			"expires":   w.Expires.Local().Format("2006-01-02 15:04"),
+			"left":      left,
+		})
+	}
+	printer := formatter.NewGenericTablePrinter(formatter.TableConfig{
+		Title:         "Test SSIDs",
+		Format:        "table",
+		BoldHeaders:   true,
+		ShowSeparator: true,
+		CommandPath:   "wlan.test-list",
+		Columns: []formatter.TableColumn{
+			{Field: "site_name", Title: "Site"},
+			{Field: "ssid", Title: "SSID"},
+			{Field: "template", Title: "Template"},
+			{Field: "api", Title: "API"},
+			{Field: "expires", Title: "Expires"},
+			{Field: "left", Title: "Left"},
+		},
+	}, rows)
+	fmt.Print(printer.Print())
+	return nil
+}
+
+// withoutEphemeralWLANs drops the test SSIDs created by 'wlan test-deploy',
+// so an import taken while one is broadcasting does not write it into intent.
+func withoutEphemeralWLANs(wlans []*vendors.WLAN) []*vendors.WLAN {
+	ids := ephemeral.IDs()
+	if len(ids) == 0 {
+		return wlans
+	}
+	kept := make([]*vendors.WLAN, 0, len(wlans))
+	for _, w := range wlans {
+		if !ids[w.ID] {
+			kept = append(kept, w)
+		}
+	}
+	return kept
+}
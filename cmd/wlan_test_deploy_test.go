@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ravinald/wifimgr/internal/ephemeral"
+)
+
+func TestTestWLANConfig(t *testing.T) {
+	tmpl := map[string]any{"ssid": "Corp", "enabled": false, "meraki:": map[string]any{"ssid": "Corp-MR"}}
+
+	cfg, err := testWLANConfig(tmpl, "mist", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg["ssid"] != "Corp-test" || cfg["enabled"] != true {
+		t.Errorf("mist: ssid=%v enabled=%v, want Corp-test, true", cfg["ssid"], cfg["enabled"])
+	}
+	if tmpl["ssid"] != "Corp" {
+		t.Errorf("template modified: ssid=%v", tmpl["ssid"])
+	}
+
+	cfg, _ = testWLANConfig(tmpl, "meraki", "")
+	if cfg["ssid"] != "Corp-MR-test" {
+		t.Errorf("meraki: ssid=%v, want Corp-MR-test", cfg["ssid"])
+	}
+	cfg, _ = testWLANConfig(tmpl, "mist", "pilot")
+	if cfg["ssid"] != "pilot" {
+		t.Errorf("--ssid: ssid=%v, want pilot", cfg["ssid"])
+	}
+	if _, err := testWLANConfig(map[string]any{}, "mist", ""); err == nil {
+		t.Error("template without ssid: want error")
+	}
+}
+
+func TestValidateTestTTL(t *testing.T) {
+	for _, ttl := range []time.Duration{0, -time.Hour, ephemeral.MaxTTL + time.Minute} {
+		if validateTestTTL(ttl) == nil {
+			t.Errorf("ttl %s: want error", ttl)
+		}
+	}
+	if err := validateTestTTL(2 * time.Hour); err != nil {
+		t.Errorf("ttl 2h: %v", err)
+	}
+}
+
+func TestSelectTestWLANs(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	wlans := []ephemeral.WLAN{
+		{SSID: "a-test", Expires: now.Add(-time.Minute)},
+		{SSID: "b-test", Expires: now.Add(time.Hour)},
+	}
+	ssids := func(ws []ephemeral.WLAN) []string {
+		var out []string
+		for _, w := range ws {
+			out = append(out, w.SSID)
+		}
+		return out
+	}
+
+	remove, keep := selectTestWLANs(wlans, "", now)
+	if len(remove) != 1 || remove[0].SSID != "a-test" || len(keep) != 1 {
+		t.Errorf("expired: remove=%v keep=%v", ssids(remove), ssids(keep))
+	}
+	remove, _ = selectTestWLANs(wlans, "b-test", now)
+	if len(remove) != 1 || remove[0].SSID != "b-test" {
+		t.Errorf("named: remove=%v", ssids(remove))
+	}
+	remove, keep = selectTestWLANs(wlans, "all", now)
+	if len(remove) != 2 || len(keep) != 0 {
+		t.Errorf("all: remove=%v keep=%v", ssids(remove), ssids(keep))
+	}
+}
@@ -0,0 +1,340 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ravinald/wifimgr/cmd/apply"
+	"github.com/ravinald/wifimgr/internal/cmdutils"
+	"github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/i18n"
+	"github.com/ravinald/wifimgr/internal/logging"
+	"github.com/ravinald/wifimgr/internal/symbols"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+const wlanToggleLong = `%s a WLAN at many sites in one operation: the site intent files and
+the live API are both updated, so the change survives the next apply.
+
+The WLAN is named by its template label, or by SSID when exactly one WLAN
+template broadcasts it. Each listed site gets the label added to (disable) or
+removed from (enable) its wlan_disabled list, which overrides the template's
+enabled value at that site only; other sites using the template are untouched.
+Sites that do not use the WLAN are skipped.
+
+Sites are a comma-separated list, a site group defined under site_groups in
+the main config (@name), or "all" for every site that uses the WLAN.
+
+Arguments:
+  label|ssid       Required. WLAN template label or SSID
+  sites <list>     Required. site[,site...], @group, or all
+  diff             Optional. Show the plan without changing anything
+  force            Optional. Skip the confirmation prompt
+
+The API step needs the WLAN to exist at the site already; sites where it is
+missing are reported, and their next apply creates it in the new state.`
+
+// wlanDisableCmd represents the "wlan disable" command
+var wlanDisableCmd = &cobra.Command{
+	Use:   "disable <label|ssid> sites <site[,site...]|@group|all> [diff] [force]",
+	Short: "Disable a WLAN at many sites (intent and API)",
+	Long:  fmt.Sprintf(wlanToggleLong, "Disable"),
+	Example: `  wifimgr wlan disable guest-wifi sites @retail
+  wifimgr wlan disable "Guest WiFi" sites US-SFO-01,US-NYC-02 force
+  wifimgr wlan disable guest-wifi sites all diff`,
+	Args: wlanToggleArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runWLANToggle(cmd, args, false)
+	},
+}
+
+// wlanEnableCmd represents the "wlan enable" command
+var wlanEnableCmd = &cobra.Command{
+	Use:   "enable <label|ssid> sites <site[,site...]|@group|all> [diff] [force]",
+	Short: "Re-enable a WLAN at many sites (intent and API)",
+	Long:  fmt.Sprintf(wlanToggleLong, "Enable"),
+	Example: `  wifimgr wlan enable guest-wifi sites @retail
+  wifimgr wlan enable guest-wifi sites all force`,
+	Args: wlanToggleArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runWLANToggle(cmd, args, true)
+	},
+}
+
+func init() {
+	wlanCmd.AddCommand(wlanDisableCmd)
+	wlanCmd.AddCommand(wlanEnableCmd)
+}
+
+func wlanToggleArgs(_ *cobra.Command, args []string) error {
+	if cmdutils.ContainsHelp(args) {
+		return nil
+	}
+	if len(args) < 1 {
+		return fmt.Errorf("requires a WLAN label or SSID")
+	}
+	return nil
+}
+
+// wlanToggleArgsParsed is the parsed form of the wlan enable/disable
+// arguments.
+type wlanToggleArgsParsed struct {
+	WLAN  string
+	Sites string // raw sites argument: list, @group, or "all"
+	Diff  bool
+	Force bool
+}
+
+func parseWLANToggleArgs(args []string) (*wlanToggleArgsParsed, error) {
+	parsed := &wlanToggleArgsParsed{WLAN: cmdutils.StripQuotes(args[0])}
+	for i := 1; i < len(args); i++ {
+		switch strings.ToLower(args[i]) {
+		case "sites", "site":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("'sites' requires a site list, @group, or all")
+			}
+			parsed.Sites = args[i+1]
+			i++
+		case "diff":
+			parsed.Diff = true
+		case "force":
+			parsed.Force = true
+		default:
+			return nil, fmt.Errorf("unexpected argument: %s", args[i])
+		}
+	}
+	if parsed.Sites == "" {
+		return nil, fmt.Errorf("requires 'sites <site[,site...]|@group|all>'")
+	}
+	return parsed, nil
+}
+
+// resolveWLANLabel returns the WLAN template label for arg: the label itself
+// when defined, else the single WLAN template whose ssid (common or in any
+// vendor block) equals arg.
+func resolveWLANLabel(store *config.TemplateStore, arg string) (string, error) {
+	if _, ok := store.GetWLANTemplate(arg); ok {
+		return arg, nil
+	}
+	var matches []string
+	for _, label := range store.ListTemplates()[config.TemplateKindWLAN] {
+		tmpl, _ := store.GetWLANTemplate(label)
+		for _, ssid := range wlanTemplateSSIDs(tmpl) {
+			if ssid == arg {
+				matches = append(matches, label)
+				break
+			}
+		}
+	}
+	sort.Strings(matches)
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no WLAN template has label or SSID '%s'", arg)
+	case 1:
+		return matches[0], nil
+	}
+	return "", fmt.Errorf("SSID '%s' is broadcast by WLAN templates %s; use the label", arg, strings.Join(matches, ", "))
+}
+
+// wlanTemplateSSIDs lists the SSIDs a WLAN template can expand to: the
+// common ssid plus any vendor-block override.
+func wlanTemplateSSIDs(tmpl map[string]any) []string {
+	var out []string
+	if s, ok := tmpl["ssid"].(string); ok && s != "" {
+		out = append(out, s)
+	}
+	for key, v := range tmpl {
+		if !strings.HasSuffix(key, ":") {
+			continue
+		}
+		if block, ok := v.(map[string]any); ok {
+			if s, ok := block["ssid"].(string); ok && s != "" {
+				out = append(out, s)
+			}
+		}
+	}
+	return out
+}
+
+// wlanToggleSites expands the sites argument; "all" is every site whose
+// intent references the WLAN.
+func wlanToggleSites(arg, label string) ([]string, error) {
+	if !strings.EqualFold(arg, "all") {
+		return cmdutils.ResolveSiteList(arg)
+	}
+	refs, err := config.FindTemplateRefs(templateRefFiles(), globalConfig.Files.ConfigDir, config.TemplateKindWLAN, label)
+	if err != nil {
+		return nil, err
+	}
+	var sites []string
+	seen := make(map[string]bool)
+	for _, r := range refs {
+		if r.Field == config.WLANDisabledField || r.Field == config.WLANOverridesField || seen[r.Site] {
+			continue
+		}
+		seen[r.Site] = true
+		sites = append(sites, r.Site)
+	}
+	if len(sites) == 0 {
+		return nil, fmt.Errorf("no site config uses WLAN '%s'", label)
+	}
+	return sites, nil
+}
+
+func runWLANToggle(cmd *cobra.Command, args []string, enable bool) error {
+	if cmdutils.ContainsHelp(args) {
+		return cmd.Help()
+	}
+	parsed, err := parseWLANToggleArgs(args)
+	if err != nil {
+		return err
+	}
+
+	store, err := apply.LoadTemplateStore(globalConfig)
+	if err != nil {
+		return fmt.Errorf("failed to load templates: %w", err)
+	}
+	label, err := resolveWLANLabel(store, parsed.WLAN)
+	if err != nil {
+		return err
+	}
+	sites, err := wlanToggleSites(parsed.Sites, label)
+	if err != nil {
+		return err
+	}
+	plan, err := config.PlanWLANToggle(templateRefFiles(), globalConfig.Files.ConfigDir, label, sites, enable)
+	if err != nil {
+		return err
+	}
+
+	verb := "Disable"
+	if enable {
+		verb = "Enable"
+	}
+	var targets []config.WLANToggleSite
+	fmt.Printf("%s WLAN '%s':\n", verb, label)
+	for _, s := range plan.Sites {
+		switch {
+		case !s.Used:
+			fmt.Printf("  %s %s: does not use this WLAN, skipped\n", symbols.WarningPrefix(), s.Site)
+			continue
+		case s.Changed:
+			fmt.Printf("  %s (%s): update wlan_disabled and the API\n", s.Site, s.File)
+		default:
+			fmt.Printf("  %s (%s): intent already set, check the API\n", s.Site, s.File)
+		}
+		targets = append(targets, s)
+	}
+	if len(targets) == 0 || parsed.Diff {
+		return nil
+	}
+
+	if !parsed.Force {
+		key := "wlan_toggle.confirm_disable"
+		if enable {
+			key = "wlan_toggle.confirm_enable"
+		}
+		fmt.Printf("%s %s ", i18n.T(key, label, len(targets)), i18n.T("prompt.yes_no"))
+		if !confirmPrompt() {
+			fmt.Println(i18n.T("wlan_toggle.cancelled"))
+			return nil
+		}
+	}
+
+	if err := applyRewriteWithBackups("updating site configs", plan.Files(), plan.Apply); err != nil {
+		return err
+	}
+	logging.Infof("Set WLAN %s %s in intent at %d site(s)", label, enabledWord(enable), len(targets))
+
+	tmpl, _ := store.GetWLANTemplate(label)
+	failed := 0
+	for _, s := range targets {
+		if err := setSiteWLANEnabled(s.Site, tmpl, enable); err != nil {
+			failed++
+			fmt.Printf("%s %s: %v\n", symbols.FailurePrefix(), s.Site, err)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("intent updated at %d site(s) but the API change failed at %d; rerun or apply those sites", len(targets), failed)
+	}
+	return nil
+}
+
+// setSiteWLANEnabled flips the enabled flag of the site's WLAN carrying the
+// template's SSID. The vendor's current WLAN object is sent back with only
+// enabled changed, so nothing else the API holds is reset.
+func setSiteWLANEnabled(siteName string, tmpl map[string]any, enable bool) error {
+	ref, err := cmdutils.ResolveSite(siteName, "")
+	if err != nil {
+		return err
+	}
+	registry := GetAPIRegistry()
+	if registry == nil {
+		return fmt.Errorf("API registry not initialized")
+	}
+	client, err := registry.GetClient(ref.APILabel)
+	if err != nil {
+		return err
+	}
+	svc := client.WLANs()
+	if svc == nil {
+		return fmt.Errorf("this feature is not available with this API (%s:%s)", ref.APILabel, client.VendorName())
+	}
+
+	ssid, _ := config.ExpandForVendor(tmpl, client.VendorName())["ssid"].(string)
+	existing, err := svc.ListBySite(globalContext, ref.SiteID)
+	if err != nil {
+		return fmt.Errorf("failed to list WLANs: %w", err)
+	}
+	var target *vendors.WLAN
+	for _, w := range existing {
+		if w.SSID == ssid {
+			target = w
+			break
+		}
+	}
+	switch {
+	case target == nil:
+		fmt.Printf("%s %s: WLAN '%s' not on the API yet; the next apply creates it in the new state\n", symbols.WarningPrefix(), ref.Name, ssid)
+		return nil
+	case target.SiteID == "" && client.VendorName() == "mist":
+		fmt.Printf("%s %s: '%s' is an org-level WLAN shared by other sites; left unchanged\n", symbols.WarningPrefix(), ref.Name, ssid)
+		return nil
+	case target.Enabled == enable:
+		fmt.Printf("%s %s: '%s' already %s\n", symbols.SuccessPrefix(), ref.Name, ssid, enabledWord(enable))
+		return nil
+	}
+
+	updated := *target
+	updated.Enabled = enable
+	if _, err := svc.Update(globalContext, target.ID, &updated); err != nil {
+		return err
+	}
+	fmt.Printf("%s %s: '%s' %s\n", symbols.SuccessPrefix(), ref.Name, ssid, enabledWord(enable))
+	return nil
+}
+
+func enabledWord(enable bool) string {
+	if enable {
+		return "enabled"
+	}
+	return "disabled"
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ravinald/wifimgr/internal/config"
+)
+
+func TestResolveWLANLabel(t *testing.T) {
+	store := config.NewTemplateStore()
+	store.WLAN["guest-wifi"] = map[string]any{"ssid": "Guest"}
+	store.WLAN["corp"] = map[string]any{"ssid": "Corp", "meraki:": map[string]any{"ssid": "Corp-MR"}}
+	store.WLAN["corp-lab"] = map[string]any{"ssid": "Corp"}
+
+	tests := []struct {
+		arg, want, wantErr string
+	}{
+		{arg: "guest-wifi", want: "guest-wifi"},
+		{arg: "Guest", want: "guest-wifi"},
+		{arg: "Corp-MR", want: "corp"},
+		{arg: "Corp", wantErr: "corp, corp-lab"},
+		{arg: "nope", wantErr: "no WLAN template"},
+	}
+	for _, tt := range tests {
+		got, err := resolveWLANLabel(store, tt.arg)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: err = %v, want containing %q", tt.arg, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("%s: got %q, %v; want %q", tt.arg, got, err, tt.want)
+		}
+	}
+}
+
+func TestParseWLANToggleArgs(t *testing.T) {
+	parsed, err := parseWLANToggleArgs([]string{`"Guest WiFi"`, "sites", "@retail", "force"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if parsed.WLAN != "Guest WiFi" || parsed.Sites != "@retail" || !parsed.Force || parsed.Diff {
+		t.Errorf("parsed = %+v", parsed)
+	}
+	if _, err := parseWLANToggleArgs([]string{"guest"}); err == nil {
+		t.Error("expected error without sites")
+	}
+	if _, err := parseWLANToggleArgs([]string{"guest", "sites", "all", "bogus"}); err == nil {
+		t.Error("expected error for unknown keyword")
+	}
+}
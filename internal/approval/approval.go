@@ -0,0 +1,293 @@
+// Package approval implements the two-person rule for applies to protected
+// sites. An apply to a site matched by approvals.sites does not push: it
+// renders the diff and stores a pending change bundle (the apply command, the
+// diff, and a hash over both). A second operator runs 'wifimgr approve <id>',
+// which re-renders the diff, refuses if it no longer matches the hash, and
+// only then executes. Bundles are never deleted, so the directory doubles as
+// the audit trail of who requested, approved, and applied each change.
+package approval
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/xdg"
+)
+
+// Bundle statuses.
+const (
+	StatusPending  = "pending"
+	StatusApplied  = "applied"
+	StatusFailed   = "failed"
+	StatusRejected = "rejected"
+	StatusStale    = "stale"
+)
+
+// Artifact file names inside a bundle directory.
+const (
+	bundleFile = "bundle.json"
+	diffFile   = "diff.txt"
+)
+
+// Config holds the approvals section of the main config.
+type Config struct {
+	Sites []string // site name globs that need a second operator
+	Dir   string   // where bundles are stored
+}
+
+// LoadConfig reads approvals.* from Viper. It returns nil when no site is
+// protected. A relative dir is taken from files.config_dir; the default is the
+// state directory, so point dir at shared storage when operators work from
+// different hosts. Under a tenant an absolute or default dir gains
+// tenants/<name>, as files.config_dir already names the tenant's directory.
+func LoadConfig() *Config {
+	var sites []string
+	for _, s := range viper.GetStringSlice("approvals.sites") {
+		if s = strings.TrimSpace(s); s != "" {
+			sites = append(sites, s)
+		}
+	}
+	if len(sites) == 0 {
+		return nil
+	}
+
+	dir := xdg.ExpandPath(strings.TrimSpace(viper.GetString("approvals.dir")))
+	if dir != "" && !filepath.IsAbs(dir) {
+		dir = xdg.Resolve(viper.GetString("files.config_dir"), dir)
+	} else {
+		if dir == "" {
+			dir = filepath.Join(xdg.GetStateDir(), "approvals")
+		}
+		if t := config.ActiveTenant(); t != nil {
+			dir = filepath.Join(dir, "tenants", t.Name)
+		}
+	}
+	return &Config{Sites: sites, Dir: dir}
+}
+
+// Protects reports whether applies to site need approval. Patterns are
+// case-insensitive globs on the site name.
+func (c *Config) Protects(site string) bool {
+	if c == nil {
+		return false
+	}
+	name := strings.ToLower(site)
+	for _, pattern := range c.Sites {
+		if ok, _ := path.Match(strings.ToLower(pattern), name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Bundle is one requested change and everything recorded about it.
+type Bundle struct {
+	ID          string    `json:"id"`
+	Site        string    `json:"site"`
+	API         string    `json:"api"`
+	Args        []string  `json:"args"` // apply arguments: site, command, options
+	Force       bool      `json:"force"`
+	Hash        string    `json:"hash"`
+	Status      string    `json:"status"`
+	RequestedBy string    `json:"requested_by"`
+	RequestedAt time.Time `json:"requested_at"`
+	ApprovedBy  string    `json:"approved_by,omitempty"`
+	ApprovedAt  time.Time `json:"approved_at,omitzero"`
+	RejectedBy  string    `json:"rejected_by,omitempty"`
+	FinishedAt  time.Time `json:"finished_at,omitzero"`
+	Error       string    `json:"error,omitempty"`
+
+	// RequestedByAccount and ApprovedByAccount are the OS logins behind the
+	// display names above; the two-person rule compares these.
+	RequestedByAccount string `json:"requested_by_account,omitempty"`
+	ApprovedByAccount  string `json:"approved_by_account,omitempty"`
+
+	// Diff is the rendered plan, stored beside the bundle as diff.txt.
+	Diff string `json:"-"`
+}
+
+// New builds a pending bundle for an apply and its rendered diff. requester
+// is the display name and account the OS login of who asked for it.
+func New(site, api string, args []string, force bool, diff, requester, account string) (*Bundle, error) {
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	diff = Normalize(diff)
+	return &Bundle{
+		ID:          id,
+		Site:        site,
+		API:         api,
+		Args:        args,
+		Force:       force,
+		Hash:        Hash(site, api, args, force, diff),
+		Status:      StatusPending,
+		RequestedBy: requester,
+		RequestedAt: time.Now().UTC(),
+		Diff:        diff,
+
+		RequestedByAccount: account,
+	}, nil
+}
+
+// newID returns a short random bundle ID.
+func newID() (string, error) {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("approval: generate id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;]*[A-Za-z]`)
+
+// Normalize strips color escapes and trailing whitespace from a rendered diff,
+// so the same plan hashes the same on a color terminal and in a pipe.
+func Normalize(diff string) string {
+	lines := strings.Split(ansiEscape.ReplaceAllString(diff, ""), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t\r")
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n") + "\n"
+}
+
+// Hash fingerprints what will be executed: the apply and its normalized diff.
+func Hash(site, api string, args []string, force bool, diff string) string {
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "site=%s\napi=%s\nargs=%s\nforce=%t\n\n", site, api, strings.Join(args, " "), force)
+	_, _ = h.Write([]byte(Normalize(diff)))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Matches reports whether a freshly rendered diff is still the approved plan.
+func (b *Bundle) Matches(diff string) bool {
+	return Hash(b.Site, b.API, b.Args, b.Force, diff) == b.Hash
+}
+
+// CheckApprover enforces the two-person rule: only a pending bundle can be
+// approved, and never from the OS account that requested it. account is the
+// approver's OS login; display names (WIFIMGR_OPERATOR) are not compared,
+// since the requester could set one to anything.
+func (b *Bundle) CheckApprover(account string) error {
+	if b.Status != StatusPending {
+		return fmt.Errorf("change %s is %s, not pending", b.ID, b.Status)
+	}
+	if account == "" {
+		return fmt.Errorf("cannot determine the approving operator's OS account")
+	}
+	requester := b.RequestedByAccount
+	if requester == "" {
+		requester = b.RequestedBy // bundles recorded before accounts were
+	}
+	if strings.EqualFold(account, requester) {
+		return fmt.Errorf("change %s was requested by %s from OS account %s; a different account must approve it", b.ID, b.RequestedBy, requester)
+	}
+	return nil
+}
+
+// Reject closes a pending bundle without applying it. Anyone may reject,
+// including the requester withdrawing their own change.
+func (b *Bundle) Reject(operator string) {
+	b.Status = StatusRejected
+	b.RejectedBy = operator
+	b.FinishedAt = time.Now().UTC()
+}
+
+// Save writes the bundle and its diff under dir/<id>/. Bundles are group
+// writable: the approver updates a bundle another operator created.
+func Save(dir string, b *Bundle) error {
+	bdir := filepath.Join(dir, b.ID)
+	if err := os.MkdirAll(bdir, 0770); err != nil { // #nosec G301 -- shared by the operators' group
+		return fmt.Errorf("approval: create %s: %w", bdir, err)
+	}
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("approval: marshal: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(bdir, bundleFile), append(data, '\n'), 0660); err != nil { // #nosec G306 -- see above
+		return fmt.Errorf("approval: write bundle: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(bdir, diffFile), []byte(b.Diff), 0660); err != nil { // #nosec G306 -- see above
+		return fmt.Errorf("approval: write diff: %w", err)
+	}
+	return nil
+}
+
+// Load reads the bundle with the given ID from dir.
+func Load(dir, id string) (*Bundle, error) {
+	if id == "" || id != filepath.Base(id) || id == "." || id == ".." {
+		return nil, fmt.Errorf("invalid change id %q", id)
+	}
+	bdir := filepath.Join(dir, id)
+	data, err := os.ReadFile(filepath.Join(bdir, bundleFile)) // #nosec G304 -- id is a single path element under the configured dir
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("no change %q in %s", id, dir)
+		}
+		return nil, fmt.Errorf("approval: read %s: %w", id, err)
+	}
+	var b Bundle
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("approval: parse %s: %w", id, err)
+	}
+	diff, err := os.ReadFile(filepath.Join(bdir, diffFile)) // #nosec G304 -- see above
+	if err != nil {
+		return nil, fmt.Errorf("approval: read diff for %s: %w", id, err)
+	}
+	b.Diff = string(diff)
+	return &b, nil
+}
+
+// List returns every bundle in dir, oldest request first. A missing dir
+// yields no bundles.
+func List(dir string) ([]*Bundle, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("approval: read %s: %w", dir, err)
+	}
+	var out []*Bundle
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		b, err := Load(dir, e.Name())
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, b)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].RequestedAt.Before(out[j].RequestedAt) })
+	return out, nil
+}
+
+type approvedKey struct{}
+
+// WithApproved marks ctx as executing the approved bundle id, so apply skips
+// the approval gate for it.
+func WithApproved(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, approvedKey{}, id)
+}
+
+// ApprovedID returns the bundle ID ctx is executing, or "".
+func ApprovedID(ctx context.Context) string {
+	id, _ := ctx.Value(approvedKey{}).(string)
+	return id
+}
@@ -0,0 +1,141 @@
+package approval
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestLoadConfig(t *testing.T) {
+	t.Cleanup(viper.Reset)
+
+	viper.Reset()
+	if c := LoadConfig(); c != nil {
+		t.Fatalf("no approvals.sites: got %+v, want nil", c)
+	}
+
+	viper.Set("files.config_dir", "/etc/wifimgr")
+	viper.Set("approvals.sites", []string{"US-HQ-*", " ", "CA-TOR-01"})
+	viper.Set("approvals.dir", "approvals")
+	c := LoadConfig()
+	if c == nil || len(c.Sites) != 2 || c.Dir != filepath.Join("/etc/wifimgr", "approvals") {
+		t.Fatalf("got %+v", c)
+	}
+	for site, want := range map[string]bool{"us-hq-01": true, "CA-TOR-01": true, "US-LAB-01": false} {
+		if got := c.Protects(site); got != want {
+			t.Errorf("Protects(%q) = %v, want %v", site, got, want)
+		}
+	}
+	if (*Config)(nil).Protects("US-HQ-01") {
+		t.Error("a nil config protects nothing")
+	}
+}
+
+func TestBundle_TwoPersonRule(t *testing.T) {
+	b, err := New("US-HQ-01", "mist-prod", []string{"US-HQ-01", "ap"}, false, "+ name: ap-01\n", "alice", "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Status != StatusPending || len(b.ID) != 8 || b.Hash == "" {
+		t.Fatalf("got %+v", b)
+	}
+
+	if err := b.CheckApprover("alice"); err == nil || !strings.Contains(err.Error(), "different account") {
+		t.Errorf("requester approving: got %v", err)
+	}
+	if err := b.CheckApprover("ALICE"); err == nil {
+		t.Error("the requester differing only in case must still be refused")
+	}
+	if err := b.CheckApprover(""); err == nil {
+		t.Error("an unknown approver must be refused")
+	}
+	if err := b.CheckApprover("bob"); err != nil {
+		t.Errorf("bob: %v", err)
+	}
+
+	// The display name is the requester's to choose: posing as someone else
+	// must not let them approve their own change.
+	spoofed, err := New("US-HQ-01", "mist-prod", []string{"US-HQ-01", "ap"}, false, "+ name: ap-01\n", "bob", "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := spoofed.CheckApprover("alice"); err == nil {
+		t.Error("the requesting account must be refused whatever name it gave")
+	}
+
+	b.Reject("alice")
+	if err := b.CheckApprover("bob"); err == nil || !strings.Contains(err.Error(), "rejected") {
+		t.Errorf("rejected bundle: got %v", err)
+	}
+}
+
+func TestBundle_Matches(t *testing.T) {
+	diff := "\x1b[32m+ name: ap-01\x1b[0m  \n- name: ap-old\n"
+	b, err := New("US-HQ-01", "mist-prod", []string{"US-HQ-01", "ap"}, false, diff, "alice", "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !b.Matches("+ name: ap-01\n- name: ap-old\n\n") {
+		t.Error("the same plan without color or trailing space should match")
+	}
+	if b.Matches("+ name: ap-02\n- name: ap-old\n") {
+		t.Error("a different plan must not match")
+	}
+	b.Force = true
+	if b.Matches(diff) {
+		t.Error("changing force must invalidate the hash")
+	}
+}
+
+func TestSaveLoadList(t *testing.T) {
+	dir := t.TempDir()
+	first, _ := New("US-HQ-01", "mist-prod", []string{"US-HQ-01", "ap"}, false, "+ a\n", "alice", "alice")
+	second, _ := New("US-HQ-02", "mist-prod", []string{"US-HQ-02", "switch"}, true, "+ b\n", "carol", "carol")
+	second.RequestedAt = first.RequestedAt.Add(1)
+	for _, b := range []*Bundle{second, first} {
+		if err := Save(dir, b); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := Load(dir, first.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Diff != "+ a\n" || got.Hash != first.Hash || got.RequestedBy != "alice" {
+		t.Errorf("round trip: got %+v", got)
+	}
+
+	all, err := List(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 || all[0].ID != first.ID || all[1].ID != second.ID {
+		t.Errorf("List order: got %v", all)
+	}
+
+	for _, bad := range []string{"", "..", "../x", "a/b"} {
+		if _, err := Load(dir, bad); err == nil {
+			t.Errorf("Load(%q): want an error", bad)
+		}
+	}
+	if _, err := Load(dir, "deadbeef"); err == nil || !strings.Contains(err.Error(), "no change") {
+		t.Errorf("missing id: got %v", err)
+	}
+	if none, err := List(filepath.Join(dir, "missing")); err != nil || none != nil {
+		t.Errorf("missing dir: got %v, %v", none, err)
+	}
+}
+
+func TestApprovedID(t *testing.T) {
+	ctx := context.Background()
+	if ApprovedID(ctx) != "" {
+		t.Error("plain context should carry no approval")
+	}
+	if got := ApprovedID(WithApproved(ctx, "3fa4c2d1")); got != "3fa4c2d1" {
+		t.Errorf("got %q", got)
+	}
+}
@@ -0,0 +1,83 @@
+// Package bench times repeated operations and summarizes the samples for
+// "wifimgr bench".
+package bench
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Stats summarizes a set of timing samples.
+type Stats struct {
+	N    int
+	Min  time.Duration
+	P50  time.Duration
+	P95  time.Duration
+	Max  time.Duration
+	Mean time.Duration
+}
+
+// Summarize computes Stats over samples (nearest-rank percentiles).
+func Summarize(samples []time.Duration) Stats {
+	if len(samples) == 0 {
+		return Stats{}
+	}
+	sorted := append([]time.Duration(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	var total time.Duration
+	for _, s := range sorted {
+		total += s
+	}
+	return Stats{
+		N:    len(sorted),
+		Min:  sorted[0],
+		P50:  percentile(sorted, 50),
+		P95:  percentile(sorted, 95),
+		Max:  sorted[len(sorted)-1],
+		Mean: total / time.Duration(len(sorted)),
+	}
+}
+
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100 // ceil(p/100 * n)
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// Measure runs fn n times and summarizes the durations. It stops at the
+// first error, returning the samples taken so far.
+func Measure(n int, fn func() error) (Stats, error) {
+	samples := make([]time.Duration, 0, n)
+	for i := 0; i < n; i++ {
+		start := time.Now()
+		if err := fn(); err != nil {
+			return Summarize(samples), err
+		}
+		samples = append(samples, time.Since(start))
+	}
+	return Summarize(samples), nil
+}
+
+// String renders the stats as one report cell: "p50 1.2ms  p95 3.4ms  max 5ms (n=20)".
+func (s Stats) String() string {
+	if s.N == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("p50 %-9s p95 %-9s max %-9s (n=%d)", Round(s.P50), Round(s.P95), Round(s.Max), s.N)
+}
+
+// Round trims a duration to three significant figures for display.
+func Round(d time.Duration) time.Duration {
+	switch {
+	case d >= time.Second:
+		return d.Round(10 * time.Millisecond)
+	case d >= time.Millisecond:
+		return d.Round(10 * time.Microsecond)
+	case d >= time.Microsecond:
+		return d.Round(10 * time.Nanosecond)
+	}
+	return d
+}
@@ -0,0 +1,41 @@
+package bench
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSummarize(t *testing.T) {
+	var samples []time.Duration
+	for i := 20; i >= 1; i-- {
+		samples = append(samples, time.Duration(i)*time.Millisecond)
+	}
+	s := Summarize(samples)
+	if s.N != 20 || s.Min != time.Millisecond || s.Max != 20*time.Millisecond {
+		t.Errorf("min/max = %v/%v n=%d", s.Min, s.Max, s.N)
+	}
+	if s.P50 != 10*time.Millisecond || s.P95 != 19*time.Millisecond {
+		t.Errorf("p50/p95 = %v/%v", s.P50, s.P95)
+	}
+	if s.Mean != 10500*time.Microsecond {
+		t.Errorf("mean = %v", s.Mean)
+	}
+	if Summarize(nil).String() != "-" {
+		t.Error("empty stats should render as -")
+	}
+}
+
+func TestMeasureStopsOnError(t *testing.T) {
+	calls := 0
+	s, err := Measure(5, func() error {
+		calls++
+		if calls == 3 {
+			return errors.New("boom")
+		}
+		return nil
+	})
+	if err == nil || s.N != 2 || calls != 3 {
+		t.Errorf("n=%d calls=%d err=%v", s.N, calls, err)
+	}
+}
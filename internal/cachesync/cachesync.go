@@ -0,0 +1,63 @@
+// Package cachesync copies the cache and config backups between admin hosts,
+// so a standby jump host holds a current copy when the primary is out of
+// reach during an incident.
+//
+// A remote is either an rsync-over-SSH location ([user@]host:path) or an
+// object store URL (s3://bucket/prefix, gs://bucket/prefix). Each local
+// directory lands under its own name on the remote: <remote>/cache and
+// <remote>/backups. Copies only add and overwrite; nothing is deleted on
+// the receiving side, so a mistaken pull or push never loses a file that
+// exists on one side only. A pull keeps a local file newer than the remote
+// copy, and an rsync push likewise keeps a newer file on the host.
+package cachesync
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Names of the replicated directories on the remote.
+const (
+	DirCache   = "cache"
+	DirBackups = "backups"
+)
+
+// Dir is a local directory replicated under Name on the remote.
+type Dir struct {
+	Name string
+	Path string
+}
+
+// Remote is a location the directories are pushed to and pulled from.
+// Push and Pull return the number of files copied.
+type Remote interface {
+	Push(ctx context.Context, dirs []Dir) (int, error)
+	Pull(ctx context.Context, dirs []Dir) (int, error)
+	String() string
+}
+
+// Parse returns the Remote a command-line spec names.
+func Parse(spec string) (Remote, error) {
+	switch {
+	case strings.HasPrefix(spec, "s3://"):
+		return newObjectRemote("s3", strings.TrimPrefix(spec, "s3://"))
+	case strings.HasPrefix(spec, "gs://"):
+		return newObjectRemote("gcs", strings.TrimPrefix(spec, "gs://"))
+	case strings.Contains(spec, "://"):
+		return nil, fmt.Errorf("unsupported remote %q: use [user@]host:path, s3://bucket/prefix, or gs://bucket/prefix", spec)
+	}
+	host, dir, ok := strings.Cut(spec, ":")
+	if !ok || host == "" || strings.ContainsAny(host, "/ ") || strings.HasPrefix(host, "-") {
+		return nil, fmt.Errorf("invalid remote %q: use [user@]host:path, s3://bucket/prefix, or gs://bucket/prefix", spec)
+	}
+	return &rsyncRemote{host: host, dir: dir, run: runRsync}, nil
+}
+
+// skipFile reports whether a file is transient and never copied: lock files
+// and the temp files of an atomic write in progress.
+func skipFile(name string) bool {
+	base := filepath.Base(name)
+	return strings.HasSuffix(base, ".lock") || strings.Contains(base, ".tmp-")
+}
@@ -0,0 +1,256 @@
+package cachesync
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ravinald/wifimgr/internal/remotebackup"
+)
+
+func TestParse(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "a")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "s")
+	tests := []struct {
+		spec    string
+		want    string
+		wantErr bool
+	}{
+		{"ops@jump2:wifimgr", "ops@jump2:wifimgr", false},
+		{"jump2:/srv/wifimgr", "jump2:/srv/wifimgr", false},
+		{"s3://team-bucket", "s3://team-bucket/wifimgr-sync", false},
+		{"gs://team-bucket/standby/", "gs://team-bucket/standby", false},
+		{"s3://", "", true},
+		{"ftp://host/path", "", true},
+		{"/local/path", "", true},
+		{"-e:foo", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			r, err := Parse(tt.spec)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Parse(%q) = %v, want error", tt.spec, r)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.spec, err)
+			}
+			if r.String() != tt.want {
+				t.Errorf("String() = %q, want %q", r.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestRsyncArgs(t *testing.T) {
+	var calls [][]string
+	r := &rsyncRemote{host: "ops@jump2", dir: "wifimgr", run: func(_ context.Context, args ...string) ([]byte, error) {
+		calls = append(calls, args)
+		return []byte("apis/\napis/mist.json\napis/.mist.json.meta\n"), nil
+	}}
+	local := t.TempDir()
+	n, err := r.Push(context.Background(), []Dir{{DirCache, local}, {DirBackups, filepath.Join(local, "missing")}})
+	if err != nil {
+		t.Fatalf("Push: %v", err)
+	}
+	if n != 2 || len(calls) != 1 {
+		t.Fatalf("Push copied %d file(s) in %d call(s), want 2 in 1 (missing dirs skipped)", n, len(calls))
+	}
+	if !slices.Contains(calls[0], "--update") {
+		t.Errorf("push args %v lack --update; an older file would replace a newer one", calls[0])
+	}
+	tail := calls[0][len(calls[0])-3:]
+	if want := []string{"--", local + "/", "ops@jump2:wifimgr/cache/"}; !reflect.DeepEqual(tail, want) {
+		t.Errorf("push args end %v, want %v", tail, want)
+	}
+	for _, arg := range calls[0] {
+		if strings.HasPrefix(arg, "--delete") {
+			t.Errorf("push passes %s", arg)
+		}
+	}
+
+	calls = nil
+	if _, err := r.Pull(context.Background(), []Dir{{DirBackups, local}}); err != nil {
+		t.Fatalf("Pull: %v", err)
+	}
+	tail = calls[0][len(calls[0])-2:]
+	if want := []string{"ops@jump2:wifimgr/backups/", local + "/"}; !reflect.DeepEqual(tail, want) {
+		t.Errorf("pull args end %v, want %v", tail, want)
+	}
+}
+
+// fakeBucket is an in-memory S3 endpoint: PUT, GET of an object, and
+// ListObjectsV2. An object's LastModified is its upload time unless set in
+// modified.
+type fakeBucket struct {
+	mu       sync.Mutex
+	objects  map[string][]byte
+	modified map[string]time.Time
+}
+
+func (f *fakeBucket) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/bucket"), "/")
+	switch {
+	case r.Method == http.MethodPut:
+		f.objects[key], _ = io.ReadAll(r.Body)
+		if f.modified == nil {
+			f.modified = map[string]time.Time{}
+		}
+		f.modified[key] = time.Now().UTC().Truncate(time.Second)
+	case r.URL.Query().Get("list-type") == "2":
+		type content struct {
+			Key          string `xml:"Key"`
+			Size         int    `xml:"Size"`
+			LastModified string `xml:"LastModified,omitempty"`
+		}
+		var out struct {
+			XMLName  xml.Name  `xml:"ListBucketResult"`
+			Contents []content `xml:"Contents"`
+		}
+		for k := range f.objects {
+			if strings.HasPrefix(k, r.URL.Query().Get("prefix")) {
+				c := content{Key: k, Size: len(f.objects[k])}
+				if m, ok := f.modified[k]; ok {
+					c.LastModified = m.Format(time.RFC3339)
+				}
+				out.Contents = append(out.Contents, c)
+			}
+		}
+		_ = xml.NewEncoder(w).Encode(out)
+	default:
+		body, ok := f.objects[key]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(body)
+	}
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, body := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(body), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestObjectPushPull(t *testing.T) {
+	bucket := &fakeBucket{objects: map[string][]byte{}}
+	srv := httptest.NewServer(bucket)
+	defer srv.Close()
+	cfg := &remotebackup.Config{Bucket: "bucket", Prefix: "standby", Endpoint: srv.URL, Region: "us-east-1",
+		AccessKeyID: "a", SecretAccessKey: "s"}
+	remote := &objectRemote{cfg: cfg, store: remotebackup.NewS3Store(cfg)}
+
+	primary := t.TempDir()
+	writeFiles(t, filepath.Join(primary, "cache"), map[string]string{
+		"apis/mist.json":       `{"sites":[]}`,
+		"apis/.mist.json.meta": `{}`,
+		"apis/mist.json.lock":  "",
+	})
+	writeFiles(t, filepath.Join(primary, "backups"), map[string]string{"sites.json.0": `{"version":1}`})
+	dirs := func(root string) []Dir {
+		return []Dir{{DirCache, filepath.Join(root, "cache")}, {DirBackups, filepath.Join(root, "backups")}}
+	}
+
+	n, err := remote.Push(context.Background(), dirs(primary))
+	if err != nil {
+		t.Fatalf("Push: %v", err)
+	}
+	var keys []string
+	for k := range bucket.objects {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	want := []string{"standby/backups/sites.json.0", "standby/cache/apis/.mist.json.meta", "standby/cache/apis/mist.json"}
+	if n != 3 || !reflect.DeepEqual(keys, want) {
+		t.Fatalf("Push copied %d, bucket holds %v, want %v", n, keys, want)
+	}
+
+	standby := t.TempDir()
+	writeFiles(t, filepath.Join(standby, "backups"), map[string]string{"local-only.json.0": "{}"})
+	bucket.objects["standby/cache/../../escape"] = []byte("x")
+	if _, err := remote.Pull(context.Background(), dirs(t.TempDir())); err == nil {
+		t.Error("Pull accepted a key outside the directory")
+	}
+	delete(bucket.objects, "standby/cache/../../escape")
+
+	if n, err = remote.Pull(context.Background(), dirs(standby)); err != nil || n != 3 {
+		t.Fatalf("Pull = %d, %v; want 3 files", n, err)
+	}
+	got, err := os.ReadFile(filepath.Join(standby, "cache", "apis", "mist.json"))
+	if err != nil || string(got) != `{"sites":[]}` {
+		t.Errorf("pulled cache = %q, %v", got, err)
+	}
+	if _, err := os.Stat(filepath.Join(standby, "backups", "local-only.json.0")); err != nil {
+		t.Error("Pull removed a file that exists only locally")
+	}
+}
+
+// TestObjectPullKeepsNewerLocal checks that a pull replaces an older local
+// file but not one modified after the object was uploaded: rotated backup
+// names are positional, so the same name can hold different backups.
+func TestObjectPullKeepsNewerLocal(t *testing.T) {
+	uploaded := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	bucket := &fakeBucket{
+		objects: map[string][]byte{
+			"standby/backups/sites.json.0": []byte("remote-newer"),
+			"standby/backups/sites.json.1": []byte("remote-older"),
+		},
+		modified: map[string]time.Time{
+			"standby/backups/sites.json.0": uploaded,
+			"standby/backups/sites.json.1": uploaded,
+		},
+	}
+	srv := httptest.NewServer(bucket)
+	defer srv.Close()
+	cfg := &remotebackup.Config{Bucket: "bucket", Prefix: "standby", Endpoint: srv.URL, Region: "us-east-1",
+		AccessKeyID: "a", SecretAccessKey: "s"}
+	remote := &objectRemote{cfg: cfg, store: remotebackup.NewS3Store(cfg)}
+
+	local := t.TempDir()
+	writeFiles(t, local, map[string]string{"sites.json.0": "local-older", "sites.json.1": "local-newer"})
+	older, newer := uploaded.Add(-time.Hour), uploaded.Add(time.Hour)
+	if err := os.Chtimes(filepath.Join(local, "sites.json.0"), older, older); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(filepath.Join(local, "sites.json.1"), newer, newer); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := remote.Pull(context.Background(), []Dir{{DirBackups, local}})
+	if err != nil || n != 1 {
+		t.Fatalf("Pull = %d, %v; want 1 file", n, err)
+	}
+	for name, want := range map[string]string{"sites.json.0": "remote-newer", "sites.json.1": "local-newer"} {
+		if got, _ := os.ReadFile(filepath.Join(local, name)); string(got) != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+
+	// The pulled file now carries the upload time: pulling again copies nothing.
+	if n, err := remote.Pull(context.Background(), []Dir{{DirBackups, local}}); err != nil || n != 0 {
+		t.Errorf("second Pull = %d, %v; want 0 files", n, err)
+	}
+}
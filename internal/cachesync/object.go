@@ -0,0 +1,154 @@
+package cachesync
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/ravinald/wifimgr/internal/helpers"
+	"github.com/ravinald/wifimgr/internal/remotebackup"
+)
+
+// defaultObjectPrefix is used when the URL names only a bucket. It is apart
+// from the backup mirror's prefix, whose retention pruning would otherwise
+// delete files that have not changed in a while.
+const defaultObjectPrefix = "wifimgr-sync"
+
+// objectRemote copies to an S3-compatible bucket, one object per file under
+// <prefix>/<dir>/<relative path>. Each push overwrites the previous copy;
+// history is the backup mirror's job (backup.remote).
+type objectRemote struct {
+	cfg   *remotebackup.Config
+	store remotebackup.Store
+}
+
+// newObjectRemote builds a remote for bucket[/prefix], with region, endpoint,
+// and credentials from backup.remote.*.
+func newObjectRemote(provider, rest string) (*objectRemote, error) {
+	bucket, prefix, _ := strings.Cut(rest, "/")
+	if bucket == "" {
+		return nil, fmt.Errorf("remote URL names no bucket")
+	}
+	if prefix = strings.Trim(prefix, "/"); prefix == "" {
+		prefix = defaultObjectPrefix
+	}
+	cfg, err := remotebackup.LoadBucketConfig(provider, bucket, prefix)
+	if err != nil {
+		return nil, err
+	}
+	return &objectRemote{cfg: cfg, store: remotebackup.NewS3Store(cfg)}, nil
+}
+
+func (o *objectRemote) String() string {
+	scheme := "s3"
+	if o.cfg.Provider == remotebackup.ProviderGCS {
+		scheme = "gs"
+	}
+	return fmt.Sprintf("%s://%s/%s", scheme, o.cfg.Bucket, o.cfg.Prefix)
+}
+
+// Push uploads every file under each local directory.
+func (o *objectRemote) Push(ctx context.Context, dirs []Dir) (int, error) {
+	total := 0
+	for _, d := range dirs {
+		err := filepath.WalkDir(d.Path, func(p string, e fs.DirEntry, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) && p == d.Path {
+					return filepath.SkipDir
+				}
+				return err
+			}
+			if e.IsDir() || !e.Type().IsRegular() || skipFile(p) {
+				return nil
+			}
+			rel, err := filepath.Rel(d.Path, p)
+			if err != nil {
+				return err
+			}
+			data, err := os.ReadFile(p) // #nosec G304 -- walking the operator's cache/backup dir
+			if err != nil {
+				return err
+			}
+			if err := o.store.Put(ctx, path.Join(o.cfg.Prefix, d.Name, filepath.ToSlash(rel)), data); err != nil {
+				return err
+			}
+			total++
+			return nil
+		})
+		if err != nil {
+			return total, fmt.Errorf("push %s: %w", d.Name, err)
+		}
+	}
+	return total, nil
+}
+
+// Pull downloads every object under each directory's prefix into the local
+// directory. A local file modified after the object was uploaded is kept, as
+// is one already matching the object; each file written takes the object's
+// upload time as its mtime, so the next pull recognizes it.
+func (o *objectRemote) Pull(ctx context.Context, dirs []Dir) (int, error) {
+	total := 0
+	for _, d := range dirs {
+		prefix := path.Join(o.cfg.Prefix, d.Name) + "/"
+		objects, err := o.store.List(ctx, prefix)
+		if err != nil {
+			return total, fmt.Errorf("pull %s: %w", d.Name, err)
+		}
+		for _, obj := range objects {
+			local, err := localPath(d.Path, strings.TrimPrefix(obj.Key, prefix))
+			if err != nil {
+				return total, fmt.Errorf("pull %s: %w", d.Name, err)
+			}
+			if local == "" || keepLocal(local, obj) {
+				continue
+			}
+			data, err := o.store.Get(ctx, obj.Key)
+			if err != nil {
+				return total, fmt.Errorf("pull %s: %w", d.Name, err)
+			}
+			if err := os.MkdirAll(filepath.Dir(local), 0750); err != nil {
+				return total, fmt.Errorf("failed to create %s: %w", filepath.Dir(local), err)
+			}
+			if err := helpers.WriteFileAtomic(local, data, 0600); err != nil {
+				return total, fmt.Errorf("failed to write %s: %w", local, err)
+			}
+			if !obj.LastModified.IsZero() {
+				_ = os.Chtimes(local, obj.LastModified, obj.LastModified)
+			}
+			total++
+		}
+	}
+	return total, nil
+}
+
+// keepLocal reports whether the local file must not be replaced by obj: it
+// was modified after obj was uploaded, or it already matches obj's upload
+// time and size. Without an upload time the object always wins.
+func keepLocal(local string, obj remotebackup.ObjectInfo) bool {
+	info, err := os.Stat(local)
+	if err != nil || obj.LastModified.IsZero() {
+		return false
+	}
+	if info.ModTime().After(obj.LastModified) {
+		return true
+	}
+	return info.ModTime().Equal(obj.LastModified) && info.Size() == obj.Size
+}
+
+// localPath maps an object's key, relative to its directory prefix, to a
+// path under dir. It returns "" for keys that are not copied and an error
+// for keys that would land outside dir.
+func localPath(dir, rel string) (string, error) {
+	if rel == "" || strings.HasSuffix(rel, "/") || skipFile(rel) {
+		return "", nil
+	}
+	clean := path.Clean(rel)
+	if path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("object %q lies outside the directory", rel)
+	}
+	return filepath.Join(dir, filepath.FromSlash(clean)), nil
+}
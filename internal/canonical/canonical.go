@@ -0,0 +1,205 @@
+// Package canonical normalizes a device's running config and its intent
+// before the two are compared, so differences that exist only in how the
+// vendor API stores a value — a list it reorders, a default it fills in, a
+// field it rewrites — do not read as drift on every diff and apply.
+//
+// Rules are set per API and device type under
+// api.<label>.managed_keys.ignore.<device_type>. Each names a dot-notation
+// path (with "*" wildcards, as in managed_keys) and one of the registered
+// rule kinds:
+//
+//	ignore     drop the field from both sides (a bare string is this)
+//	unordered  compare the list at the path regardless of element order
+//	default    the API fills the field in with value; ignore it when one
+//	           side holds that value and the other does not have the field
+package canonical
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ravinald/wifimgr/internal/keypath"
+)
+
+// Rule kinds.
+const (
+	KindIgnore    = "ignore"
+	KindUnordered = "unordered"
+	KindDefault   = "default"
+)
+
+// Rule is one canonicalization applied to both configs before comparison.
+type Rule struct {
+	Path  string `json:"path"`
+	Kind  string `json:"rule,omitempty"`  // KindIgnore when empty
+	Value any    `json:"value,omitempty"` // the server default, for KindDefault
+}
+
+// canonicalizer applies one rule to both configs in place.
+type canonicalizer func(r Rule, current, desired map[string]any)
+
+// kinds are the registered rule kinds. A new kind is added here with its
+// canonicalizer and listed in the config schema.
+var kinds = map[string]canonicalizer{
+	KindIgnore:    ignoreField,
+	KindUnordered: sortLists,
+	KindDefault:   dropDefault,
+}
+
+// Kinds lists the registered rule kinds, sorted.
+func Kinds() []string {
+	out := make([]string, 0, len(kinds))
+	for k := range kinds {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// ParseRules reads rules as they come from the config: a list whose items
+// are a path string or an object with path, rule, and value.
+func ParseRules(raw any) ([]Rule, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	items, ok := raw.([]any)
+	if !ok {
+		return nil, fmt.Errorf("want a list of rules, got %T", raw)
+	}
+	rules := make([]Rule, 0, len(items))
+	for i, item := range items {
+		var r Rule
+		switch v := item.(type) {
+		case string:
+			r.Path = v
+		case map[string]any:
+			data, err := json.Marshal(v)
+			if err != nil {
+				return nil, fmt.Errorf("rule %d: %w", i+1, err)
+			}
+			if err := json.Unmarshal(data, &r); err != nil {
+				return nil, fmt.Errorf("rule %d: %w", i+1, err)
+			}
+			if _, hasValue := v["value"]; r.Kind == KindDefault && !hasValue {
+				return nil, fmt.Errorf("rule %d (%s): a default rule needs a value", i+1, r.Path)
+			}
+		default:
+			return nil, fmt.Errorf("rule %d: want a path or an object, got %T", i+1, item)
+		}
+		if r.Kind == "" {
+			r.Kind = KindIgnore
+		}
+		if err := keypath.Validate(r.Path); err != nil {
+			return nil, fmt.Errorf("rule %d: %w", i+1, err)
+		}
+		if _, known := kinds[r.Kind]; !known {
+			return nil, fmt.Errorf("rule %d (%s): unknown rule %q (want %s)", i+1, r.Path, r.Kind, strings.Join(Kinds(), ", "))
+		}
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
+
+// Apply returns copies of current and desired with rules applied, in order.
+// The inputs are not modified. Without rules they are returned as they are.
+func Apply(current, desired map[string]any, rules []Rule) (map[string]any, map[string]any) {
+	if len(rules) == 0 {
+		return current, desired
+	}
+	current, desired = deepCopy(current), deepCopy(desired)
+	for _, r := range rules {
+		kinds[r.Kind](r, current, desired)
+	}
+	return current, desired
+}
+
+// ignoreField drops every field matching the rule's path from both sides.
+func ignoreField(r Rule, current, desired map[string]any) {
+	kp := keypath.Parse(r.Path)
+	for _, m := range []map[string]any{current, desired} {
+		for _, path := range keypath.CollectMatchingPaths(m, kp) {
+			keypath.DeleteValueAtPath(m, path)
+		}
+	}
+}
+
+// sortLists sorts every list matching the rule's path on both sides by the
+// JSON encoding of its elements.
+func sortLists(r Rule, current, desired map[string]any) {
+	kp := keypath.Parse(r.Path)
+	for _, m := range []map[string]any{current, desired} {
+		for _, path := range keypath.CollectMatchingPaths(m, kp) {
+			v, _ := keypath.GetValueAtPath(m, path)
+			list, ok := v.([]any)
+			if !ok {
+				continue
+			}
+			sorted := slices.Clone(list)
+			sort.SliceStable(sorted, func(i, j int) bool { return encode(sorted[i]) < encode(sorted[j]) })
+			keypath.SetValueAtPath(m, path, sorted)
+		}
+	}
+}
+
+// dropDefault removes a field holding the rule's value from one side when
+// the other side does not have it.
+func dropDefault(r Rule, current, desired map[string]any) {
+	kp := keypath.Parse(r.Path)
+	want := encode(r.Value)
+	for _, pair := range [][2]map[string]any{{current, desired}, {desired, current}} {
+		side, other := pair[0], pair[1]
+		for _, path := range keypath.CollectMatchingPaths(side, kp) {
+			if _, found := keypath.GetValueAtPath(other, path); found {
+				continue
+			}
+			if v, _ := keypath.GetValueAtPath(side, path); encode(v) == want {
+				deletePruning(side, path)
+			}
+		}
+	}
+}
+
+// deletePruning deletes the value at path and then any parent map left empty
+// by it, so dropping led.enabled does not leave "led": {} on one side only.
+func deletePruning(m map[string]any, path []string) {
+	if !keypath.DeleteValueAtPath(m, path) {
+		return
+	}
+	for n := len(path) - 1; n > 0; n-- {
+		parent, _ := keypath.GetValueAtPath(m, path[:n])
+		if nested, ok := parent.(map[string]any); !ok || len(nested) > 0 {
+			return
+		}
+		keypath.DeleteValueAtPath(m, path[:n])
+	}
+}
+
+// encode renders v as JSON for ordering and equality; map keys come out
+// sorted, and numbers compare by value whatever their Go type.
+func encode(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// deepCopy copies a config through JSON, so nested maps and lists are not
+// shared with the caller.
+func deepCopy(m map[string]any) map[string]any {
+	if m == nil {
+		return nil
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return m
+	}
+	var out map[string]any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return m
+	}
+	return out
+}
@@ -0,0 +1,88 @@
+package canonical
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules([]any{
+		"radio_config.band_24.power",
+		map[string]any{"path": "port_config.*.networks", "rule": "unordered"},
+		map[string]any{"path": "led.enabled", "rule": "default", "value": true},
+	})
+	if err != nil {
+		t.Fatalf("ParseRules() error = %v", err)
+	}
+	want := []Rule{
+		{Path: "radio_config.band_24.power", Kind: KindIgnore},
+		{Path: "port_config.*.networks", Kind: KindUnordered},
+		{Path: "led.enabled", Kind: KindDefault, Value: true},
+	}
+	if !reflect.DeepEqual(rules, want) {
+		t.Errorf("ParseRules() = %+v, want %+v", rules, want)
+	}
+
+	if rules, err := ParseRules(nil); err != nil || rules != nil {
+		t.Errorf("ParseRules(nil) = %v, %v, want nothing", rules, err)
+	}
+
+	bad := map[string]any{
+		"not a list":       "led.enabled",
+		"empty path":       []any{""},
+		"unknown rule":     []any{map[string]any{"path": "led", "rule": "sorted"}},
+		"default no value": []any{map[string]any{"path": "led.enabled", "rule": "default"}},
+		"number item":      []any{42},
+	}
+	for name, raw := range bad {
+		if _, err := ParseRules(raw); err == nil {
+			t.Errorf("%s: ParseRules(%v) should fail", name, raw)
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	current := map[string]any{
+		"name":  "ap-1",
+		"notes": "set by the API",
+		"led":   map[string]any{"enabled": true},
+		"port_config": map[string]any{
+			"eth0": map[string]any{"networks": []any{"voice", "data"}},
+		},
+	}
+	desired := map[string]any{
+		"name": "ap-1",
+		"port_config": map[string]any{
+			"eth0": map[string]any{"networks": []any{"data", "voice"}},
+		},
+	}
+	rules := []Rule{
+		{Path: "notes", Kind: KindIgnore},
+		{Path: "port_config.*.networks", Kind: KindUnordered},
+		{Path: "led.enabled", Kind: KindDefault, Value: true},
+	}
+
+	gotCurrent, gotDesired := Apply(current, desired, rules)
+	if !reflect.DeepEqual(gotCurrent, gotDesired) {
+		t.Errorf("Apply() left a difference:\ncurrent = %v\ndesired = %v", gotCurrent, gotDesired)
+	}
+
+	// The inputs are untouched.
+	if current["notes"] != "set by the API" {
+		t.Error("Apply() modified current")
+	}
+	nets := current["port_config"].(map[string]any)["eth0"].(map[string]any)["networks"].([]any)
+	if nets[0] != "voice" {
+		t.Error("Apply() reordered current's list in place")
+	}
+}
+
+func TestApplyDefaultKeepsRealChange(t *testing.T) {
+	// A default only hides an absent field; an explicit different value is drift.
+	current := map[string]any{"led": map[string]any{"enabled": true}}
+	desired := map[string]any{"led": map[string]any{"enabled": false}}
+	gotCurrent, gotDesired := Apply(current, desired, []Rule{{Path: "led.enabled", Kind: KindDefault, Value: true}})
+	if reflect.DeepEqual(gotCurrent, gotDesired) {
+		t.Error("Apply() hid an explicit change from the default")
+	}
+}
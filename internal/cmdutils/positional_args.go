@@ -32,10 +32,12 @@ type ParsedShowArgs struct {
 	Target        string // API target label (e.g., "mist-prod", "meraki")
 	ESSIDName     string // SSID name filter (from "essid" keyword)
 	SortField     string // Secondary sort field (from "sort" keyword)
+	Where         string // Row filter expression or @saved-filter (from "filter" keyword)
 	Format        string
 	ShowUnmanaged bool   // "all": widen object scope to everything the API has, not just managed
 	Verbosity     string // "", "detail", or "extensive" (field verbosity)
 	NoResolve     bool
+	Uptime        bool // "uptime": add live uptime and last-reboot columns
 	DeviceType    string
 }
 
@@ -46,7 +48,7 @@ func (p *ParsedShowArgs) AllFields() bool {
 }
 
 // ParseShowArgs parses positional arguments for show commands
-// Supports patterns like: [name-or-mac] [site site-name] [target api-label] [json|csv] [all] [no-resolve]
+// Supports patterns like: [name-or-mac] [site site-name] [target api-label] [filter expr|@name] [json|csv] [all] [no-resolve] [uptime]
 func ParseShowArgs(args []string) (*ParsedShowArgs, error) {
 	result := &ParsedShowArgs{
 		Format: "table", // default format
@@ -102,6 +104,16 @@ func ParseShowArgs(args []string) (*ParsedShowArgs, error) {
 			}
 			i++ // Skip the sort field
 
+		case "filter":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("'filter' requires an expression or @saved-filter name")
+			}
+			if result.Where != "" {
+				return nil, fmt.Errorf("filter specified multiple times")
+			}
+			result.Where = StripQuotes(args[i+1])
+			i++ // Skip the expression
+
 		case "format":
 			if i+1 >= len(args) {
 				return nil, fmt.Errorf("'format' requires a format type (json, csv)")
@@ -135,6 +147,9 @@ func ParseShowArgs(args []string) (*ParsedShowArgs, error) {
 		case "no-resolve":
 			result.NoResolve = true
 
+		case "uptime":
+			result.Uptime = true
+
 		case "ap", "aps", "switch", "switches", "sw", "gateway", "gateways", "gw":
 			// Device type for inventory commands
 			if result.DeviceType != "" {
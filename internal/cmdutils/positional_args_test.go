@@ -158,3 +158,20 @@ func TestImportOutputArgsValidate(t *testing.T) {
 		t.Errorf("empty should pass: %v", err)
 	}
 }
+
+func TestParseShowArgsFilterKeyword(t *testing.T) {
+	p, err := ParseShowArgs([]string{"all", "filter", "@offline-aps"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.Where != "@offline-aps" || p.Filter != "" || !p.ShowUnmanaged {
+		t.Errorf("Where=%q Filter=%q ShowUnmanaged=%v", p.Where, p.Filter, p.ShowUnmanaged)
+	}
+
+	if _, err := ParseShowArgs([]string{"filter"}); err == nil {
+		t.Error("expected error for 'filter' without a value")
+	}
+	if _, err := ParseShowArgs([]string{"filter", "type=ap", "filter", "type=switch"}); err == nil {
+		t.Error("expected error for repeated 'filter'")
+	}
+}
@@ -0,0 +1,102 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+*/
+package cmdutils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ReportArgs holds the parsed positional arguments shared by `report` subcommands.
+type ReportArgs struct {
+	SiteName string // `site <name>`, or a bare leading site name
+	Target   string // `target <api-label>`
+	Format   string // "table" (default), "json", or "csv"
+	Within   int    // `within <days>`; 0 when not given
+	All      bool   // `all`: widen from managed devices to everything cached
+}
+
+// ParseReportArgs parses positional args for `report` subcommands.
+//
+// Recognised forms (any order):
+//
+//	[<site-name>] [site <site-name>] [target <api-label>] [within <days>] [all] [format json|csv]
+//
+// A single bare token is taken as the site name so `report power US-LAB-01`
+// reads naturally. Subcommands that don't use a keyword simply ignore it.
+func ParseReportArgs(args []string) (*ReportArgs, error) {
+	result := &ReportArgs{Format: "table"}
+
+	setSite := func(name string) error {
+		if result.SiteName != "" {
+			return fmt.Errorf("site specified multiple times")
+		}
+		result.SiteName = StripQuotes(name)
+		return nil
+	}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch strings.ToLower(arg) {
+		case "site":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("'site' requires a site name")
+			}
+			if err := setSite(args[i+1]); err != nil {
+				return nil, err
+			}
+			i++
+
+		case "target":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("'target' requires an API label")
+			}
+			if result.Target != "" {
+				return nil, fmt.Errorf("target specified multiple times")
+			}
+			result.Target = StripQuotes(args[i+1])
+			i++
+
+		case "within":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("'within' requires a number of days")
+			}
+			days, err := strconv.Atoi(args[i+1])
+			if err != nil || days <= 0 {
+				return nil, fmt.Errorf("invalid 'within' value %q: must be a positive number of days", args[i+1])
+			}
+			result.Within = days
+			i++
+
+		case "format":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("'format' requires a format type (json, csv)")
+			}
+			switch f := strings.ToLower(args[i+1]); f {
+			case "json", "csv":
+				result.Format = f
+			default:
+				return nil, fmt.Errorf("invalid format %q: must be 'json' or 'csv'", args[i+1])
+			}
+			i++
+
+		case "json", "csv":
+			return nil, fmt.Errorf("use 'format %s' instead of bare '%s'", arg, arg)
+
+		case "all":
+			result.All = true
+
+		default:
+			if err := setSite(arg); err != nil {
+				return nil, fmt.Errorf("unexpected argument: %s", arg)
+			}
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,75 @@
+/*
+Copyright © 2025 Ravi Pina <ravi@pina.org>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+*/
+package cmdutils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseReportArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    ReportArgs
+		wantErr string // substring; "" means no error
+	}{
+		{
+			name: "defaults",
+			args: nil,
+			want: ReportArgs{Format: "table"},
+		},
+		{
+			name: "bare site name",
+			args: []string{"US-LAB-01"},
+			want: ReportArgs{SiteName: "US-LAB-01", Format: "table"},
+		},
+		{
+			name: "all keywords",
+			args: []string{"within", "180", "site", "US-LAB-01", "target", "mist-prod", "all", "format", "csv"},
+			want: ReportArgs{SiteName: "US-LAB-01", Target: "mist-prod", Within: 180, All: true, Format: "csv"},
+		},
+		{
+			name:    "site twice",
+			args:    []string{"US-LAB-01", "site", "US-LAB-02"},
+			wantErr: "multiple times",
+		},
+		{
+			name:    "bad within",
+			args:    []string{"within", "soon"},
+			wantErr: "within",
+		},
+		{
+			name:    "bare format",
+			args:    []string{"json"},
+			wantErr: "format json",
+		},
+		{
+			name:    "second bare token",
+			args:    []string{"US-LAB-01", "extra"},
+			wantErr: "unexpected argument",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseReportArgs(tt.args)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want substring %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if *got != tt.want {
+				t.Errorf("got %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package cmdutils
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// ResolveSiteList expands a sites argument: "@name" looks up
+// site_groups.<name> in config, anything else is a comma-separated list of
+// site names. Duplicates are dropped; order is preserved.
+func ResolveSiteList(arg string) ([]string, error) {
+	var names []string
+	if group, isRef := strings.CutPrefix(strings.TrimSpace(arg), "@"); isRef {
+		groups := viper.GetStringMapStringSlice("site_groups")
+		members, ok := groups[strings.ToLower(group)]
+		if !ok {
+			defined := make([]string, 0, len(groups))
+			for n := range groups {
+				if strings.HasPrefix(n, "_") {
+					continue
+				}
+				defined = append(defined, "@"+n)
+			}
+			sort.Strings(defined)
+			if len(defined) == 0 {
+				return nil, fmt.Errorf("unknown site group @%s (no site_groups defined in config)", group)
+			}
+			return nil, fmt.Errorf("unknown site group @%s (available: %s)", group, strings.Join(defined, ", "))
+		}
+		names = members
+	} else {
+		names = strings.Split(arg, ",")
+	}
+
+	seen := make(map[string]bool, len(names))
+	out := make([]string, 0, len(names))
+	for _, n := range names {
+		n = StripQuotes(strings.TrimSpace(n))
+		if n == "" || seen[strings.ToLower(n)] {
+			continue
+		}
+		seen[strings.ToLower(n)] = true
+		out = append(out, n)
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("no sites given in %q", arg)
+	}
+	return out, nil
+}
@@ -0,0 +1,32 @@
+package cmdutils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestResolveSiteList(t *testing.T) {
+	viper.Set("site_groups", map[string]any{
+		"retail": []any{"US-SFO-01", "US-NYC-02", "us-sfo-01"},
+	})
+	defer viper.Set("site_groups", nil)
+
+	got, err := ResolveSiteList("@Retail")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(got, ",") != "US-SFO-01,US-NYC-02" {
+		t.Errorf("group = %v", got)
+	}
+
+	got, err = ResolveSiteList("US-LAB-01, US-LAB-02,")
+	if err != nil || strings.Join(got, ",") != "US-LAB-01,US-LAB-02" {
+		t.Errorf("list = %v, %v", got, err)
+	}
+
+	if _, err := ResolveSiteList("@warehouse"); err == nil || !strings.Contains(err.Error(), "@retail") {
+		t.Errorf("unknown group should list the defined ones, got %v", err)
+	}
+}
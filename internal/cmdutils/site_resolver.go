@@ -37,6 +37,15 @@ func ResolveSite(identifier, apiLabel string) (*SiteRef, error) {
 	if err != nil {
 		return nil, err
 	}
+	return ResolveSiteWith(accessor, identifier, apiLabel)
+}
+
+// ResolveSiteWith is ResolveSite against an explicit cache accessor instead of
+// the global one, for handlers that are handed their dependencies.
+func ResolveSiteWith(accessor *vendors.CacheAccessor, identifier, apiLabel string) (*SiteRef, error) {
+	if accessor == nil {
+		return nil, fmt.Errorf("cache accessor not initialized")
+	}
 
 	// A UUID is already a site ID — resolve straight to its SiteInfo so we can
 	// attach the owning API, guarding an explicit apiLabel if one was given.
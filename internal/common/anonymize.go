@@ -0,0 +1,86 @@
+package common
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"sync"
+)
+
+// anonymization holds the client anonymization switch and the run's key.
+var anonymization struct {
+	sync.RWMutex
+	on  bool
+	key []byte
+}
+
+// ConfigureAnonymization turns client anonymization on or off. While on,
+// AnonymizeMAC and AnonymizeHostname replace client identities with keyed
+// hashes. The key is drawn once per run, so a client keeps the same
+// pseudonym across every row and output of one run, and a pseudonym cannot
+// be matched to one from another run or reversed without the key.
+func ConfigureAnonymization(on bool) {
+	anonymization.Lock()
+	defer anonymization.Unlock()
+	anonymization.on = on
+	if on && anonymization.key == nil {
+		key := make([]byte, 32)
+		_, _ = rand.Read(key) // crypto/rand.Read never returns an error
+		anonymization.key = key
+	}
+}
+
+// AnonymizationEnabled reports whether client anonymization is on.
+func AnonymizationEnabled() bool {
+	anonymization.RLock()
+	defer anonymization.RUnlock()
+	return anonymization.on
+}
+
+// anonymize returns the run-keyed hash of value in the given domain, or
+// ok=false when anonymization is off.
+func anonymize(domain, value string) (sum []byte, ok bool) {
+	anonymization.RLock()
+	defer anonymization.RUnlock()
+	if !anonymization.on {
+		return nil, false
+	}
+	mac := hmac.New(sha256.New, anonymization.key)
+	mac.Write([]byte(domain + ":" + value))
+	return mac.Sum(nil), true
+}
+
+// AnonymizeMAC replaces a client MAC with a pseudonymous one when
+// anonymization is on. The result is a locally administered unicast address
+// in the 12-digit form, so tools that parse MACs still accept it and it
+// cannot be mistaken for a vendor's hardware address. Separators and case
+// in the input do not change the result. Empty input stays empty.
+func AnonymizeMAC(mac string) string {
+	normalized := strings.ToLower(strings.NewReplacer(":", "", "-", "", ".", "").Replace(mac))
+	if normalized == "" {
+		return mac
+	}
+	sum, ok := anonymize("mac", normalized)
+	if !ok {
+		return mac
+	}
+	sum[0] = (sum[0] | 0x02) &^ 0x01
+	return hex.EncodeToString(sum[:6])
+}
+
+// AnonymizeHostname replaces a client hostname with "host-" and ten hex
+// digits of its hash when anonymization is on. Hostnames compare without
+// case. Empty input stays empty.
+func AnonymizeHostname(hostname string) string {
+	normalized := strings.ToLower(strings.TrimSpace(hostname))
+	if normalized == "" {
+		return hostname
+	}
+	sum, ok := anonymize("hostname", normalized)
+	if !ok {
+		return hostname
+	}
+	return "host-" + hex.EncodeToString(sum[:5])
+}
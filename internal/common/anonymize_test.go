@@ -0,0 +1,51 @@
+package common
+
+import (
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestAnonymizeOff(t *testing.T) {
+	ConfigureAnonymization(false)
+	if got := AnonymizeMAC("aa:bb:cc:dd:ee:ff"); got != "aa:bb:cc:dd:ee:ff" {
+		t.Errorf("AnonymizeMAC off = %q", got)
+	}
+	if got := AnonymizeHostname("alice-laptop"); got != "alice-laptop" {
+		t.Errorf("AnonymizeHostname off = %q", got)
+	}
+}
+
+func TestAnonymizeOn(t *testing.T) {
+	ConfigureAnonymization(true)
+	defer ConfigureAnonymization(false)
+
+	mac := AnonymizeMAC("aa:bb:cc:dd:ee:ff")
+	if mac == "aabbccddeeff" || len(mac) != 12 {
+		t.Fatalf("AnonymizeMAC = %q, want a different 12-digit MAC", mac)
+	}
+	if again := AnonymizeMAC("AA-BB-CC-DD-EE-FF"); again != mac {
+		t.Errorf("same MAC in another form = %q, want %q", again, mac)
+	}
+	if other := AnonymizeMAC("aabbccddee00"); other == mac {
+		t.Error("different MACs share a pseudonym")
+	}
+	hw, err := net.ParseMAC(mac[0:2] + ":" + mac[2:4] + ":" + mac[4:6] + ":" + mac[6:8] + ":" + mac[8:10] + ":" + mac[10:12])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hw[0]&0x02 == 0 || hw[0]&0x01 != 0 {
+		t.Errorf("AnonymizeMAC = %q, want a locally administered unicast address", mac)
+	}
+
+	host := AnonymizeHostname("Alice-Laptop")
+	if !strings.HasPrefix(host, "host-") || strings.Contains(strings.ToLower(host), "alice") {
+		t.Errorf("AnonymizeHostname = %q", host)
+	}
+	if again := AnonymizeHostname("alice-laptop"); again != host {
+		t.Errorf("hostname case changed the pseudonym: %q, %q", again, host)
+	}
+	if AnonymizeMAC("") != "" || AnonymizeHostname("") != "" {
+		t.Error("empty input should stay empty")
+	}
+}
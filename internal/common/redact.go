@@ -1,11 +1,18 @@
 package common
 
 import (
+	"bytes"
 	"encoding/json"
+	"path"
 	"strings"
+	"sync"
 )
 
-// sensitiveFields are JSON keys whose values must never reach a debug log.
+// Redacted replaces the value of a sensitive field in every output.
+const Redacted = "[REDACTED]"
+
+// sensitiveFields are field names whose values must never reach a terminal,
+// a debug log, or an exported file.
 var sensitiveFields = map[string]bool{
 	"password":      true,
 	"secret":        true,
@@ -21,6 +28,130 @@ var sensitiveFields = map[string]bool{
 	"credentials":   true,
 	"private_key":   true,
 	"auth":          true,
+	"claim_code":    true,
+	"claimcode":     true,
+	"magic":         true, // Mist's name for a device claim code
+}
+
+// containerFields are sensitive when they hold a bare value (an auth token)
+// but are searched rather than blanked when they hold an object: a WLAN's
+// auth block carries its type and pairwise ciphers next to the psk. Inside a
+// container only the subkeys in containerAllow are shown; anything else (a
+// psk, WEP keys, a vendor field added later) is redacted.
+var containerFields = map[string]bool{
+	"auth": true,
+}
+
+// containerAllow lists the auth subkeys known to carry no secret. Nested
+// objects among them ("enterprise") are still searched with the usual rules.
+var containerAllow = map[string]bool{
+	"type":                     true,
+	"pairwise":                 true,
+	"key_idx":                  true,
+	"enterprise":               true,
+	"radius":                   true,
+	"host":                     true,
+	"port":                     true,
+	"enable_mac_auth":          true,
+	"multi_psk_only":           true,
+	"owe":                      true,
+	"eap_reauth":               true,
+	"private_wlan":             true,
+	"anticlog_threshold":       true,
+	"wep_as_secondary_auth":    true,
+	"enable_beacon_protection": true,
+	"disable_ft":               true,
+}
+
+// sensitiveSuffixes catch compound keys such as radius_secret or
+// admin_password that the exact-match list misses.
+var sensitiveSuffixes = []string{"_secret", "_password", "_token", "_psk", "_passphrase", "_api_key"}
+
+// redactionRules holds the patterns from the redaction config section.
+var redactionRules struct {
+	sync.RWMutex
+	fields []string
+	allow  []string
+}
+
+// ConfigureRedaction extends the built-in sensitive-field list with fields and
+// exempts the names matching allow. Patterns are case-insensitive globs
+// matched against a field's own name ("*_secret", "serial"). Values holding
+// an "enc:" secret are redacted regardless of allow.
+func ConfigureRedaction(fields, allow []string) {
+	redactionRules.Lock()
+	defer redactionRules.Unlock()
+	redactionRules.fields = lowerAll(fields)
+	redactionRules.allow = lowerAll(allow)
+}
+
+func lowerAll(patterns []string) []string {
+	out := make([]string, 0, len(patterns))
+	for _, p := range patterns {
+		if p = strings.ToLower(strings.TrimSpace(p)); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSensitiveField reports whether the value of field name is redacted in
+// output. A dotted path ("auth.psk", "cache.radius.secret") is sensitive when
+// its last segment is, an earlier segment that is not a container is, or it
+// reaches into a container through a subkey not in containerAllow.
+func IsSensitiveField(name string) bool {
+	segments := strings.Split(strings.ToLower(name), ".")
+	for i, seg := range segments {
+		if i < len(segments)-1 && containerFields[seg] {
+			if !isAllowedContainerKey(segments[i+1]) {
+				return true
+			}
+			continue
+		}
+		if isSensitiveKey(seg) {
+			return true
+		}
+	}
+	return false
+}
+
+// isAllowedContainerKey reports whether subkey k of a container may be shown
+// (subject to the usual sensitive-field rules).
+func isAllowedContainerKey(k string) bool {
+	k = strings.ToLower(k)
+	if containerAllow[k] {
+		return true
+	}
+	redactionRules.RLock()
+	defer redactionRules.RUnlock()
+	return matchAny(redactionRules.allow, k)
+}
+
+func isSensitiveKey(k string) bool {
+	k = strings.ToLower(k)
+	redactionRules.RLock()
+	defer redactionRules.RUnlock()
+	if matchAny(redactionRules.allow, k) {
+		return false
+	}
+	if sensitiveFields[k] || matchAny(redactionRules.fields, k) {
+		return true
+	}
+	for _, suffix := range sensitiveSuffixes {
+		if strings.HasSuffix(k, suffix) {
+			return true
+		}
+	}
+	return false
 }
 
 // RedactJSON parses data as JSON and replaces the value of any sensitive field
@@ -33,8 +164,11 @@ func RedactJSON(data []byte) string {
 		return ""
 	}
 
+	// UseNumber keeps large integer IDs exact through the round trip.
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
 	var parsed any
-	if err := json.Unmarshal(data, &parsed); err != nil {
+	if err := dec.Decode(&parsed); err != nil || dec.More() {
 		return "[non-JSON body redacted]"
 	}
 
@@ -45,13 +179,36 @@ func RedactJSON(data []byte) string {
 	return string(result)
 }
 
+// RedactValue returns a copy of a decoded JSON value (map[string]any, []any,
+// scalars) with sensitive fields replaced by "[REDACTED]", using the same
+// rules as RedactJSON. Strings holding an "enc:" encrypted secret are
+// redacted wherever they appear.
+func RedactValue(v any) any {
+	return redactValue(v)
+}
+
+// RedactMap is RedactValue for a map, keeping the map type.
+func RedactMap(m map[string]any) map[string]any {
+	if m == nil {
+		return nil
+	}
+	return redactValue(m).(map[string]any)
+}
+
 func redactValue(v any) any {
 	switch val := v.(type) {
+	case string:
+		if strings.HasPrefix(val, "enc:") {
+			return Redacted
+		}
+		return v
 	case map[string]any:
 		result := make(map[string]any, len(val))
 		for k, child := range val {
-			if sensitiveFields[strings.ToLower(k)] {
-				result[k] = "[REDACTED]"
+			if obj, isObject := child.(map[string]any); isObject && containerFields[strings.ToLower(k)] {
+				result[k] = redactContainer(obj)
+			} else if IsSensitiveField(k) {
+				result[k] = Redacted
 			} else {
 				result[k] = redactValue(child)
 			}
@@ -63,7 +220,28 @@ func redactValue(v any) any {
 			result[i] = redactValue(child)
 		}
 		return result
+	case []map[string]any:
+		result := make([]any, len(val))
+		for i, child := range val {
+			result[i] = redactValue(child)
+		}
+		return result
 	default:
 		return v
 	}
 }
+
+// redactContainer redacts a container object such as a WLAN auth block: a
+// subkey outside containerAllow is blanked whatever it holds, the rest are
+// redacted as usual.
+func redactContainer(m map[string]any) map[string]any {
+	result := make(map[string]any, len(m))
+	for k, child := range m {
+		if !isAllowedContainerKey(k) || IsSensitiveField(k) {
+			result[k] = Redacted
+		} else {
+			result[k] = redactValue(child)
+		}
+	}
+	return result
+}
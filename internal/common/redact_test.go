@@ -10,9 +10,19 @@ func TestRedactJSON(t *testing.T) {
 	}{
 		{"empty", "", ""},
 		{"redacts token", `{"api_token":"nbt_abc.def"}`, `{"api_token":"[REDACTED]"}`},
-		{"redacts nested psk", `{"wlan":{"auth":{"psk":"hunter2"}}}`, `{"wlan":{"auth":"[REDACTED]"}}`},
+		{"redacts nested psk", `{"wlan":{"auth":{"psk":"hunter2","type":"psk"}}}`, `{"wlan":{"auth":{"psk":"[REDACTED]","type":"psk"}}}`},
+		{"redacts bare auth token", `{"auth":"Bearer abc"}`, `{"auth":"[REDACTED]"}`},
+		{"redacts wep keys", `{"auth":{"type":"wep","key_idx":1,"keys":["s3cr3tWEPkey1"]}}`, `{"auth":{"key_idx":1,"keys":"[REDACTED]","type":"wep"}}`},
+		{"redacts 802.1x radius secret", `{"auth":{"type":"eap","pairwise":["wpa2-ccmp"],"enterprise":{"radius":{"host":"10.0.0.5","port":1812,"secret":"r4d1us"}}}}`,
+			`{"auth":{"enterprise":{"radius":{"host":"10.0.0.5","port":1812,"secret":"[REDACTED]"}},"pairwise":["wpa2-ccmp"],"type":"eap"}}`},
+		{"redacts unknown auth subkey", `{"auth":{"type":"psk","vendor_key":"x"}}`, `{"auth":{"type":"psk","vendor_key":"[REDACTED]"}}`},
+		{"redacts claim code", `{"magic":"ABCD-1234","mac":"aabbccddeeff"}`, `{"mac":"aabbccddeeff","magic":"[REDACTED]"}`},
 		{"redacts inside array", `[{"secret":"x"}]`, `[{"secret":"[REDACTED]"}]`},
 		{"keeps non-sensitive", `{"name":"AP-1"}`, `{"name":"AP-1"}`},
+		{"redacts compound key", `{"radius_secret":"s3cret"}`, `{"radius_secret":"[REDACTED]"}`},
+		{"redacts encrypted value", `{"note":"enc:abc123"}`, `{"note":"[REDACTED]"}`},
+		{"keeps large ids exact", `{"id":9007199254740993}`, `{"id":9007199254740993}`},
+		{"trailing data fails closed", `{"a":1} {"psk":"x"}`, "[non-JSON body redacted]"},
 		// A non-JSON body could be an HTML error page hiding a token; fail closed.
 		{"non-json fails closed", `<html>token=nbt_abc</html>`, "[non-JSON body redacted]"},
 	}
@@ -24,3 +34,38 @@ func TestRedactJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestConfigureRedaction(t *testing.T) {
+	ConfigureRedaction([]string{"Serial", "*_community"}, []string{"magic"})
+	t.Cleanup(func() { ConfigureRedaction(nil, nil) })
+
+	got := RedactJSON([]byte(`{"serial":"A1","snmp_community":"public","magic":"ABCD","psk":"x"}`))
+	want := `{"magic":"ABCD","psk":"[REDACTED]","serial":"[REDACTED]","snmp_community":"[REDACTED]"}`
+	if got != want {
+		t.Errorf("RedactJSON = %s, want %s", got, want)
+	}
+	if got := RedactJSON([]byte(`{"magic":"enc:abc"}`)); got != `{"magic":"[REDACTED]"}` {
+		t.Errorf("allow must not expose encrypted values: %s", got)
+	}
+}
+
+func TestIsSensitiveField(t *testing.T) {
+	cases := map[string]bool{
+		"psk":                           true,
+		"auth.psk":                      true,
+		"auth.type":                     false,
+		"auth.keys":                     true,
+		"auth.enterprise.radius.secret": true,
+		"auth.enterprise.radius.host":   false,
+		"auth":                          true,
+		"cache.radius_secret":           true,
+		"credentials.username":          true,
+		"name":                          false,
+		"cache.radio_config.psk":        true,
+	}
+	for field, want := range cases {
+		if got := IsSensitiveField(field); got != want {
+			t.Errorf("IsSensitiveField(%q) = %v, want %v", field, got, want)
+		}
+	}
+}
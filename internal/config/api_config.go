@@ -2,12 +2,15 @@ package config
 
 import (
 	"fmt"
+	"maps"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/spf13/viper"
 
+	"github.com/ravinald/wifimgr/api"
 	"github.com/ravinald/wifimgr/internal/encryption"
 	"github.com/ravinald/wifimgr/internal/logging"
 	"github.com/ravinald/wifimgr/internal/vendors"
@@ -47,6 +50,20 @@ func validAPILabel(label string) bool {
 	return true
 }
 
+// ConfiguredAPILabels returns every label under api.* that holds an API
+// definition, valid or not and whichever tenant it belongs to. Cache pruning
+// uses it, so an API that merely fails to load never loses its cache.
+func ConfiguredAPILabels() []string {
+	var labels []string
+	for label, value := range viper.GetStringMap("api") {
+		if _, ok := value.(map[string]interface{}); ok {
+			labels = append(labels, label)
+		}
+	}
+	slices.Sort(labels)
+	return labels
+}
+
 // BuildAPIConfigsFromViper constructs APIConfig objects from Viper configuration.
 // Config format: api.<label>.* where each label has vendor, url, credentials, etc.
 func BuildAPIConfigsFromViper() (map[string]*vendors.APIConfig, []ValidationWarning) {
@@ -72,6 +89,10 @@ func BuildAPIConfigsFromViper() (map[string]*vendors.APIConfig, []ValidationWarn
 			})
 			continue
 		}
+		if !tenantAllowsAPI(label) {
+			logging.Debugf("Skipping API %q: not assigned to tenant %q", label, ActiveTenant().Name)
+			continue
+		}
 
 		nested, ok := value.(map[string]interface{})
 		if !ok {
@@ -83,7 +104,11 @@ func BuildAPIConfigsFromViper() (map[string]*vendors.APIConfig, []ValidationWarn
 			continue
 		}
 
-		vendor := getStringFromMap(nested, "vendor")
+		preset, vendor, presetWarning := resolveAPIPreset(label, nested)
+		if presetWarning != nil {
+			warnings = append(warnings, *presetWarning)
+			continue
+		}
 		if vendor == "" {
 			warnings = append(warnings, ValidationWarning{
 				Level:   "api",
@@ -105,6 +130,10 @@ func BuildAPIConfigsFromViper() (map[string]*vendors.APIConfig, []ValidationWarn
 
 		syncTypes, syncWarnings := parseSyncTypes(label, nested)
 		warnings = append(warnings, syncWarnings...)
+		pageSizes, pageWarnings := parsePageSizes(label, nested)
+		warnings = append(warnings, pageWarnings...)
+		pagination, paginationWarnings := parsePagination(label, nested)
+		warnings = append(warnings, paginationWarnings...)
 
 		config := &vendors.APIConfig{
 			Label:          label,
@@ -113,11 +142,21 @@ func BuildAPIConfigsFromViper() (map[string]*vendors.APIConfig, []ValidationWarn
 			Credentials:    credentials,
 			RateLimit:      getIntFromMap(nested, "rate_limit"),
 			ResultsLimit:   getIntFromMap(nested, "results_limit"),
+			PageSizes:      pageSizes,
+			Pagination:     pagination,
 			CacheTTL:       getCacheTTLFromMap(nested),
 			ConnectTimeout: resolveConnectTimeout(nested),
+			RefreshTimeout: time.Duration(getIntFromMap(nested, "refresh_timeout")) * time.Second,
 			SyncTypes:      syncTypes,
 		}
 
+		// Sandbox/demo presets fill what the API left unset, then vendor
+		// defaults fill the rest. An explicit read_only wins over the preset.
+		applyAPIPreset(config, preset)
+		if readOnly, ok := nested["read_only"].(bool); ok {
+			config.ReadOnly = readOnly
+		}
+
 		// Apply vendor-specific defaults
 		applyVendorDefaults(config)
 
@@ -202,7 +241,11 @@ func applyVendorDefaults(config *vendors.APIConfig) {
 		// driven sequentially, so rate limiting is handled in the adapter.
 	}
 
+	// Per-API results_limit, else the global api.results_limit, else 100.
 	if config.ResultsLimit == 0 {
+		config.ResultsLimit = viper.GetInt("api.results_limit")
+	}
+	if config.ResultsLimit <= 0 {
 		config.ResultsLimit = 100
 	}
 
@@ -414,6 +457,64 @@ func parseSyncTypes(label string, nested map[string]interface{}) ([]string, []Va
 }
 
 // getIntFromMap safely extracts an int value from a map[string]interface{}
+// parsePageSizes reads the per-endpoint page_sizes map. Unknown endpoints and
+// non-positive sizes are dropped with a warning so a typo can't silently fall
+// back to the default limit.
+func parsePageSizes(label string, nested map[string]interface{}) (map[string]int, []ValidationWarning) {
+	raw, ok := nested["page_sizes"].(map[string]interface{})
+	if !ok {
+		if _, present := nested["page_sizes"]; present {
+			return nil, []ValidationWarning{{
+				Level:   "api",
+				API:     label,
+				Message: fmt.Sprintf("API %q has invalid 'page_sizes' (expected an object of endpoint: size)", label),
+			}}
+		}
+		return nil, nil
+	}
+
+	sizes := make(map[string]int)
+	var warnings []ValidationWarning
+	for _, endpoint := range slices.Sorted(maps.Keys(raw)) {
+		key := strings.ToLower(endpoint)
+		if !slices.Contains(api.PageSizeEndpoints, key) {
+			warnings = append(warnings, ValidationWarning{
+				Level:   "api",
+				API:     label,
+				Message: fmt.Sprintf("API %q page_sizes: unknown endpoint %q (valid: %s)", label, endpoint, strings.Join(api.PageSizeEndpoints, ", ")),
+			})
+			continue
+		}
+		n := getIntFromMap(raw, endpoint)
+		if n < 1 || n > 1000 {
+			warnings = append(warnings, ValidationWarning{
+				Level:   "api",
+				API:     label,
+				Message: fmt.Sprintf("API %q page_sizes.%s must be between 1 and 1000", label, endpoint),
+			})
+			continue
+		}
+		sizes[key] = n
+	}
+	return sizes, warnings
+}
+
+// parsePagination reads the pagination strategy, defaulting to auto.
+func parsePagination(label string, nested map[string]interface{}) (string, []ValidationWarning) {
+	strategy := strings.ToLower(getStringFromMap(nested, "pagination"))
+	if !api.ValidPagination(strategy) {
+		return api.PaginationAuto, []ValidationWarning{{
+			Level:   "api",
+			API:     label,
+			Message: fmt.Sprintf("API %q has invalid 'pagination' %q (expected auto, page, or cursor); using auto", label, strategy),
+		}}
+	}
+	if strategy == "" {
+		strategy = api.PaginationAuto
+	}
+	return strategy, nil
+}
+
 func getIntFromMap(m map[string]interface{}, key string) int {
 	if v, ok := m[key]; ok {
 		switch val := v.(type) {
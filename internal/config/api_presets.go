@@ -0,0 +1,113 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+// APIPreset is a built-in connection profile for a vendor sandbox or demo
+// environment, selected with api.<label>.preset. It supplies the vendor and
+// connection defaults; anything set explicitly on the API still wins.
+type APIPreset struct {
+	Name      string
+	Vendor    string
+	URL       string
+	RateLimit int
+	// ReadOnly marks environments that reject writes: apply runs as diff.
+	ReadOnly bool
+	// Notes describes the environment's known quirks for 'show api'.
+	Notes string
+}
+
+// apiPresets are the known sandbox and demo environments.
+var apiPresets = map[string]APIPreset{
+	"meraki-devnet": {
+		Name:   "meraki-devnet",
+		Vendor: "meraki",
+		URL:    "https://api.meraki.com",
+		// The always-on sandbox org is shared by every DevNet user; stay well
+		// under the per-org budget so other users' traffic does not trip 429s.
+		RateLimit: 5,
+		ReadOnly:  true,
+		Notes:     "Cisco DevNet always-on sandbox: shared org, read-only API key, writes return 403",
+	},
+	"meraki-devnet-reservable": {
+		Name:      "meraki-devnet-reservable",
+		Vendor:    "meraki",
+		URL:       "https://api.meraki.com",
+		RateLimit: 10,
+		Notes:     "Cisco DevNet reservable sandbox: private org for the reservation, writable, wiped when it ends",
+	},
+	"mist-demo": {
+		Name:     "mist-demo",
+		Vendor:   "mist",
+		URL:      "https://api.mist.com",
+		ReadOnly: true,
+		Notes:    "Juniper Mist live demo org (Global 01): observer token, writes return 403",
+	},
+}
+
+// LookupAPIPreset returns the preset with the given name.
+func LookupAPIPreset(name string) (APIPreset, bool) {
+	p, ok := apiPresets[strings.ToLower(name)]
+	return p, ok
+}
+
+// APIPresetNames lists the built-in preset names, sorted.
+func APIPresetNames() []string {
+	names := make([]string, 0, len(apiPresets))
+	for name := range apiPresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// resolveAPIPreset reads api.<label>.preset and fills the vendor from it when
+// the API names none. A preset whose vendor contradicts an explicit vendor,
+// or an unknown preset name, is a warning and the API is skipped.
+func resolveAPIPreset(label string, nested map[string]interface{}) (*APIPreset, string, *ValidationWarning) {
+	vendor := getStringFromMap(nested, "vendor")
+	name := getStringFromMap(nested, "preset")
+	if name == "" {
+		return nil, vendor, nil
+	}
+	preset, ok := LookupAPIPreset(name)
+	if !ok {
+		return nil, vendor, &ValidationWarning{
+			Level:   "api",
+			API:     label,
+			Message: fmt.Sprintf("API %q has unknown preset %q (known: %s)", label, name, strings.Join(APIPresetNames(), ", ")),
+		}
+	}
+	if vendor != "" && vendor != preset.Vendor {
+		return nil, vendor, &ValidationWarning{
+			Level:   "api",
+			API:     label,
+			Message: fmt.Sprintf("API %q sets vendor %q but preset %q is for %s", label, vendor, preset.Name, preset.Vendor),
+		}
+	}
+	return &preset, preset.Vendor, nil
+}
+
+// applyAPIPreset fills the fields the API left unset from its preset. It runs
+// before applyVendorDefaults so preset values take precedence over vendor
+// defaults.
+func applyAPIPreset(config *vendors.APIConfig, preset *APIPreset) {
+	if preset == nil {
+		return
+	}
+	config.Preset = preset.Name
+	if config.URL == "" {
+		config.URL = preset.URL
+	}
+	if config.RateLimit == 0 {
+		config.RateLimit = preset.RateLimit
+	}
+	if preset.ReadOnly {
+		config.ReadOnly = true
+	}
+}
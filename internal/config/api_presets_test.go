@@ -0,0 +1,85 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+func TestResolveAPIPreset(t *testing.T) {
+	cases := []struct {
+		name       string
+		nested     map[string]interface{}
+		wantPreset string
+		wantVendor string
+		wantWarn   bool
+	}{
+		{
+			name:       "no preset keeps vendor",
+			nested:     map[string]interface{}{"vendor": "mist"},
+			wantVendor: "mist",
+		},
+		{
+			name:       "preset supplies vendor",
+			nested:     map[string]interface{}{"preset": "meraki-devnet"},
+			wantPreset: "meraki-devnet",
+			wantVendor: "meraki",
+		},
+		{
+			name:       "preset name is case-insensitive",
+			nested:     map[string]interface{}{"preset": "Mist-Demo", "vendor": "mist"},
+			wantPreset: "mist-demo",
+			wantVendor: "mist",
+		},
+		{
+			name:     "unknown preset warns",
+			nested:   map[string]interface{}{"preset": "mist-sandbox"},
+			wantWarn: true,
+		},
+		{
+			name:     "vendor mismatch warns",
+			nested:   map[string]interface{}{"preset": "meraki-devnet", "vendor": "mist"},
+			wantWarn: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			preset, vendor, warn := resolveAPIPreset("lab", c.nested)
+			if (warn != nil) != c.wantWarn {
+				t.Fatalf("warning = %v, want warning %v", warn, c.wantWarn)
+			}
+			if c.wantWarn {
+				return
+			}
+			if vendor != c.wantVendor {
+				t.Errorf("vendor = %q, want %q", vendor, c.wantVendor)
+			}
+			got := ""
+			if preset != nil {
+				got = preset.Name
+			}
+			if got != c.wantPreset {
+				t.Errorf("preset = %q, want %q", got, c.wantPreset)
+			}
+		})
+	}
+}
+
+func TestApplyAPIPresetKeepsExplicitValues(t *testing.T) {
+	preset, _ := LookupAPIPreset("meraki-devnet")
+
+	cfg := &vendors.APIConfig{Vendor: "meraki", RateLimit: 2}
+	applyAPIPreset(cfg, &preset)
+	applyVendorDefaults(cfg)
+
+	if cfg.URL != "https://api.meraki.com" || cfg.RateLimit != 2 || !cfg.ReadOnly || cfg.Preset != "meraki-devnet" {
+		t.Errorf("config = %+v", cfg)
+	}
+
+	cfg = &vendors.APIConfig{Vendor: "meraki", URL: "https://api.meraki.cn"}
+	applyAPIPreset(cfg, &preset)
+	if cfg.URL != "https://api.meraki.cn" || cfg.RateLimit != preset.RateLimit {
+		t.Errorf("explicit url should win, preset rate limit fill in: %+v", cfg)
+	}
+}
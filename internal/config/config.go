@@ -5,7 +5,8 @@ import (
 	"flag"
 	"fmt"
 	"os"
-	"path/filepath"
+
+	"github.com/ravinald/wifimgr/internal/xdg"
 )
 
 // LoadConfig loads the main configuration from the specified file
@@ -68,7 +69,7 @@ func LoadConfig(filename string) (*Config, error) {
 func LoadSiteConfig(configDir string, filename string) (*SiteConfigFile, error) {
 	fullPath := configDir
 	if filename != "" {
-		fullPath = filepath.Join(configDir, filename)
+		fullPath = xdg.Resolve(configDir, filename)
 	}
 	file, err := os.Open(fullPath) // #nosec G304 -- path from operator-controlled config
 	if err != nil {
@@ -220,7 +221,7 @@ func LoadAllConfigs(mainConfigFile string) (*Config, []*SiteConfigFile, error) {
 
 	// Load each config file
 	for _, configFile := range mainConfig.Files.SiteConfigs {
-		fullPath := filepath.Join(mainConfig.Files.ConfigDir, configFile)
+		fullPath := xdg.Resolve(mainConfig.Files.ConfigDir, configFile)
 
 		// Read the raw file data for line number estimation
 		rawData, err := os.ReadFile(fullPath) // #nosec G304 -- path from operator-controlled config
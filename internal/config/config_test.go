@@ -142,7 +142,8 @@ func TestLoadSiteConfig(t *testing.T) {
 						"address": "123 Test St",
 						"country_code": "US",
 						"timezone": "America/Los_Angeles",
-						"notes": "Test site notes"
+						"notes": "Test site notes",
+						"contact": {"owner": "Jane Doe", "phone": "+1 555 0100", "escalation": "netops-oncall"}
 					},
 					"devices": {
 						"ap": {
@@ -203,6 +204,12 @@ func TestLoadSiteConfig(t *testing.T) {
 	if siteObj.SiteConfig.Name != "Test Site" {
 		t.Errorf("Expected site name 'Test Site', got '%s'", siteObj.SiteConfig.Name)
 	}
+	if got := siteObj.SiteConfig.Contact.String(); got != "Jane Doe, +1 555 0100 (escalate: netops-oncall)" {
+		t.Errorf("Expected contact line, got '%s'", got)
+	}
+	if got := (&SiteContact{Escalation: "netops-oncall"}).String(); got != "(escalate: netops-oncall)" {
+		t.Errorf("Expected escalation-only contact line, got '%s'", got)
+	}
 
 	// Verify the devices
 	if len(siteObj.Devices.APs) != 1 {
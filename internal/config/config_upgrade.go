@@ -0,0 +1,249 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// SiteConfigVersion is the site config schema version this build reads and
+// writes. Bump it together with a new entry in siteConfigUpgrades.
+const SiteConfigVersion = 1
+
+// siteConfigUpgrade is one schema migration step. A step runs on every file
+// whose version is at or below To, so the steps of the current version also
+// sweep up deprecated fields in files already stamped with it; each step must
+// therefore be idempotent. Apply rewrites one parsed site config in place and
+// returns a description of each change.
+type siteConfigUpgrade struct {
+	To    int
+	Name  string
+	Apply func(raw map[string]any) []string
+}
+
+// siteConfigUpgrades lists the migration steps in the order they run.
+var siteConfigUpgrades = []siteConfigUpgrade{
+	{To: 1, Name: "ap-legacy-config", Apply: upgradeAPLegacyConfig},
+	{To: 1, Name: "ap-legacy-vlan-id", Apply: upgradeAPLegacyVLANID},
+}
+
+// ConfigUpgrade is a planned site config upgrade, rewritten in memory and not
+// yet written to disk.
+type ConfigUpgrade struct {
+	Files []ConfigUpgradeFile
+
+	files map[string]renamedFile // full path -> contents
+}
+
+// ConfigUpgradeFile is what the upgrade does to one site config file.
+type ConfigUpgradeFile struct {
+	File    string   `json:"file"` // as listed in config
+	Path    string   `json:"path"` // full path
+	From    int      `json:"from"` // version before the upgrade; 0 when unset
+	To      int      `json:"to"`
+	Changes []string `json:"changes,omitempty"`
+}
+
+// PlanConfigUpgrade prepares upgrading the given site config files to
+// SiteConfigVersion. Files already current and free of deprecated fields are
+// left out of the plan. It is an error for a file to carry a version newer
+// than this build understands.
+func PlanConfigUpgrade(siteFiles []string, configDir string) (*ConfigUpgrade, error) {
+	plan := &ConfigUpgrade{files: make(map[string]renamedFile)}
+	seen := make(map[string]bool)
+	for _, file := range siteFiles {
+		full := resolveConfigPath(configDir, file)
+		if seen[full] {
+			continue
+		}
+		seen[full] = true
+		data, err := os.ReadFile(full) // #nosec G304 -- path from operator-controlled config
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", full, err)
+		}
+		var raw map[string]any
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", full, err)
+		}
+
+		from := 0
+		if v, ok := raw["version"].(float64); ok {
+			from = int(v)
+		}
+		if from > SiteConfigVersion {
+			return nil, fmt.Errorf("%s has version %d; this wifimgr supports up to %d, upgrade wifimgr first", file, from, SiteConfigVersion)
+		}
+
+		var changes []string
+		for _, step := range siteConfigUpgrades {
+			if from <= step.To {
+				changes = append(changes, step.Apply(raw)...)
+			}
+		}
+		if from != SiteConfigVersion {
+			raw["version"] = SiteConfigVersion
+			changes = append([]string{fmt.Sprintf("version %d -> %d", from, SiteConfigVersion)}, changes...)
+		}
+		if len(changes) == 0 {
+			continue
+		}
+
+		out, err := json.MarshalIndent(raw, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %s: %w", full, err)
+		}
+		perm := os.FileMode(0600)
+		if info, err := os.Stat(full); err == nil {
+			perm = info.Mode().Perm()
+		}
+		plan.files[full] = renamedFile{original: data, updated: append(out, '\n'), perm: perm}
+		plan.Files = append(plan.Files, ConfigUpgradeFile{File: file, Path: full, From: from, To: SiteConfigVersion, Changes: changes})
+	}
+	return plan, nil
+}
+
+// Paths returns the full paths the upgrade rewrites, sorted.
+func (u *ConfigUpgrade) Paths() []string {
+	out := make([]string, 0, len(u.files))
+	for path := range u.files {
+		out = append(out, path)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Apply writes every upgraded file. If one write fails, the files already
+// written are restored, so a failed upgrade leaves no mix of versions.
+func (u *ConfigUpgrade) Apply() error {
+	return writeRewrittenFiles(u.files)
+}
+
+// forEachAPEntry calls fn for every AP entry in a parsed site config, sites
+// and MACs in sorted order.
+func forEachAPEntry(raw map[string]any, fn func(siteKey, mac string, entry map[string]any)) {
+	cfg, _ := raw["config"].(map[string]any)
+	sites, _ := cfg["sites"].(map[string]any)
+	for _, siteKey := range sortedKeys(sites) {
+		site, _ := sites[siteKey].(map[string]any)
+		devices, _ := site["devices"].(map[string]any)
+		aps, _ := devices["ap"].(map[string]any)
+		for _, mac := range sortedKeys(aps) {
+			if entry, ok := aps[mac].(map[string]any); ok {
+				fn(siteKey, mac, entry)
+			}
+		}
+	}
+}
+
+// legacyBandFields maps the legacy per-band keys to their radio_config names.
+var legacyBandFields = map[string]string{
+	"disabled":     "disabled",
+	"tx_power":     "power",
+	"channel":      "channel",
+	"bandwidth":    "bandwidth",
+	"antenna_mode": "antenna_mode",
+	"power_min":    "power_min",
+	"power_max":    "power_max",
+}
+
+// upgradeAPLegacyConfig moves the deprecated AP "config" block into
+// radio_config and led. Zero numbers meant "unset" in the legacy struct and
+// are dropped. A value already set in the new structure wins.
+func upgradeAPLegacyConfig(raw map[string]any) []string {
+	var changes []string
+	forEachAPEntry(raw, func(siteKey, mac string, entry map[string]any) {
+		legacy, ok := entry["config"].(map[string]any)
+		if !ok {
+			return
+		}
+		radio, _ := entry["radio_config"].(map[string]any)
+		if radio == nil {
+			radio = make(map[string]any)
+		}
+		var kept []string
+		set := func(m map[string]any, key, path string, v any) {
+			if _, exists := m[key]; exists {
+				kept = append(kept, path)
+				return
+			}
+			m[key] = v
+		}
+
+		for _, key := range sortedKeys(legacy) {
+			v := legacy[key]
+			switch key {
+			case "band_24", "band_5", "band_6":
+				bandLegacy, _ := v.(map[string]any)
+				band, _ := radio[key].(map[string]any)
+				if band == nil {
+					band = make(map[string]any)
+				}
+				for _, old := range sortedKeys(bandLegacy) {
+					newKey, known := legacyBandFields[old]
+					if !known {
+						newKey = old
+					}
+					bv := bandLegacy[old]
+					if n, isNum := bv.(float64); isNum && n == 0 {
+						continue
+					}
+					set(band, newKey, "radio_config."+key+"."+newKey, bv)
+				}
+				if len(band) > 0 {
+					radio[key] = band
+				}
+			case "led_enabled":
+				led, _ := entry["led"].(map[string]any)
+				if led == nil {
+					led = make(map[string]any)
+				}
+				set(led, "enabled", "led.enabled", v)
+				entry["led"] = led
+			default: // band_24_usage, scanning_enabled, indoor_use
+				set(radio, key, "radio_config."+key, v)
+			}
+		}
+		if len(radio) > 0 {
+			entry["radio_config"] = radio
+		}
+		delete(entry, "config")
+
+		change := fmt.Sprintf("site %s ap %s: config -> radio_config/led", siteKey, mac)
+		if len(kept) > 0 {
+			sort.Strings(kept)
+			change += fmt.Sprintf(" (kept existing %v)", kept)
+		}
+		changes = append(changes, change)
+	})
+	return changes
+}
+
+// upgradeAPLegacyVLANID moves the deprecated top-level AP vlan_id into
+// ip_config.vlan_id. An ip_config.vlan_id already set wins.
+func upgradeAPLegacyVLANID(raw map[string]any) []string {
+	var changes []string
+	forEachAPEntry(raw, func(siteKey, mac string, entry map[string]any) {
+		vlan, ok := entry["vlan_id"]
+		if !ok {
+			return
+		}
+		delete(entry, "vlan_id")
+		if n, isNum := vlan.(float64); isNum && n == 0 {
+			changes = append(changes, fmt.Sprintf("site %s ap %s: dropped empty vlan_id", siteKey, mac))
+			return
+		}
+		ip, _ := entry["ip_config"].(map[string]any)
+		if ip == nil {
+			ip = make(map[string]any)
+		}
+		if existing, set := ip["vlan_id"]; set {
+			changes = append(changes, fmt.Sprintf("site %s ap %s: dropped vlan_id %v, ip_config.vlan_id %v kept", siteKey, mac, vlan, existing))
+			return
+		}
+		ip["vlan_id"] = vlan
+		entry["ip_config"] = ip
+		changes = append(changes, fmt.Sprintf("site %s ap %s: vlan_id -> ip_config.vlan_id", siteKey, mac))
+	})
+	return changes
+}
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const legacySiteFixture = `{
+  "config": {
+    "sites": {
+      "lab": {
+        "site_config": {"name": "US-LAB-01"},
+        "devices": {
+          "ap": {
+            "aa0000000001": {
+              "name": "ap-lobby",
+              "vlan_id": 20,
+              "config": {
+                "led_enabled": true,
+                "indoor_use": true,
+                "band_24": {"disabled": true, "tx_power": 0, "channel": 0},
+                "band_5": {"disabled": false, "tx_power": 14, "channel": 36, "bandwidth": 40}
+              },
+              "radio_config": {"band_5": {"channel": 44}}
+            },
+            "aa0000000002": {"name": "ap-hall", "vlan_id": 30, "ip_config": {"vlan_id": 10}}
+          }
+        }
+      }
+    }
+  }
+}`
+
+func TestPlanConfigUpgrade(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "lab.json")
+	if err := os.WriteFile(path, []byte(legacySiteFixture), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	plan, err := PlanConfigUpgrade([]string{"lab.json", "lab.json"}, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Files) != 1 {
+		t.Fatalf("files = %+v, want lab.json once", plan.Files)
+	}
+	f := plan.Files[0]
+	if f.From != 0 || f.To != SiteConfigVersion || len(f.Changes) != 4 {
+		t.Errorf("file = %+v", f)
+	}
+	if !strings.Contains(f.Changes[1], "kept existing [radio_config.band_5.channel]") {
+		t.Errorf("changes = %q, want the existing band_5 channel kept", f.Changes)
+	}
+
+	if before, _ := os.ReadFile(path); string(before) != legacySiteFixture {
+		t.Fatal("plan must not write")
+	}
+	if err := plan.Apply(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := os.ReadFile(path)
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatal(err)
+	}
+	if raw["version"] != float64(1) {
+		t.Errorf("version = %v", raw["version"])
+	}
+	aps := raw["config"].(map[string]any)["sites"].(map[string]any)["lab"].(map[string]any)["devices"].(map[string]any)["ap"].(map[string]any)
+
+	lobby := aps["aa0000000001"].(map[string]any)
+	if _, ok := lobby["config"]; ok {
+		t.Error("legacy config block should be removed")
+	}
+	radio := lobby["radio_config"].(map[string]any)
+	band5 := radio["band_5"].(map[string]any)
+	if band5["channel"] != float64(44) || band5["power"] != float64(14) || band5["bandwidth"] != float64(40) {
+		t.Errorf("band_5 = %v", band5)
+	}
+	band24 := radio["band_24"].(map[string]any)
+	if len(band24) != 1 || band24["disabled"] != true {
+		t.Errorf("band_24 = %v, want only disabled (zero numbers dropped)", band24)
+	}
+	if radio["indoor_use"] != true || lobby["led"].(map[string]any)["enabled"] != true {
+		t.Errorf("lobby = %v", lobby)
+	}
+	if lobby["ip_config"].(map[string]any)["vlan_id"] != float64(20) {
+		t.Errorf("lobby ip_config = %v", lobby["ip_config"])
+	}
+
+	hall := aps["aa0000000002"].(map[string]any)
+	if _, ok := hall["vlan_id"]; ok || hall["ip_config"].(map[string]any)["vlan_id"] != float64(10) {
+		t.Errorf("hall = %v, want ip_config.vlan_id kept and vlan_id dropped", hall)
+	}
+
+	again, err := PlanConfigUpgrade([]string{"lab.json"}, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(again.Files) != 0 {
+		t.Errorf("second upgrade = %+v, want nothing to do", again.Files)
+	}
+}
+
+func TestPlanConfigUpgradeNewerVersion(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "new.json"), []byte(`{"version": 99, "config": {"sites": {}}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := PlanConfigUpgrade([]string{"new.json"}, dir); err == nil || !strings.Contains(err.Error(), "upgrade wifimgr") {
+		t.Errorf("err = %v, want a newer-version error", err)
+	}
+}
@@ -0,0 +1,195 @@
+package config
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ravinald/wifimgr/internal/macaddr"
+)
+
+// DeviceOverride is one row of a device-overrides import: the per-device
+// fields to set on one device entry. An empty field leaves the current value
+// alone, so a sheet only needs the columns it changes.
+type DeviceOverride struct {
+	Line          int // CSV line number, for error messages
+	MAC           string
+	Name          string
+	DeviceProfile string // AP deviceprofile_name
+	RadioProfile  string // AP radio_profile (radio template)
+	Notes         string
+}
+
+// overrideColumns maps accepted CSV header spellings to DeviceOverride
+// fields. Headers are matched case-insensitively with spaces and dashes
+// folded to underscores.
+var overrideColumns = map[string]string{
+	"mac":                "mac",
+	"mac_address":        "mac",
+	"name":               "name",
+	"deviceprofile":      "deviceprofile",
+	"device_profile":     "deviceprofile",
+	"deviceprofile_name": "deviceprofile",
+	"radio_template":     "radio",
+	"radio_profile":      "radio",
+	"radio":              "radio",
+	"notes":              "notes",
+}
+
+// ParseDeviceOverridesCSV reads a device-overrides sheet. The first row is a
+// header naming the columns (mac is required; name, deviceprofile, radio
+// template and notes are optional, in any order). Blank rows are skipped.
+func ParseDeviceOverridesCSV(r io.Reader) ([]DeviceOverride, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("empty CSV: expected a header row")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	index := make(map[string]int, len(header))
+	for i, h := range header {
+		key := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))
+		key = strings.NewReplacer(" ", "_", "-", "_").Replace(key)
+		field, ok := overrideColumns[key]
+		if !ok {
+			return nil, fmt.Errorf("unknown CSV column %q (expected mac, name, deviceprofile, radio template, notes)", h)
+		}
+		if _, dup := index[field]; dup {
+			return nil, fmt.Errorf("CSV column %q given twice", h)
+		}
+		index[field] = i
+	}
+	if _, ok := index["mac"]; !ok {
+		return nil, fmt.Errorf("CSV header has no mac column")
+	}
+
+	cell := func(record []string, field string) string {
+		i, ok := index[field]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var rows []DeviceOverride
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+		rows = append(rows, DeviceOverride{
+			Line:          line,
+			MAC:           cell(record, "mac"),
+			Name:          cell(record, "name"),
+			DeviceProfile: cell(record, "deviceprofile"),
+			RadioProfile:  cell(record, "radio"),
+			Notes:         cell(record, "notes"),
+		})
+	}
+	return rows, nil
+}
+
+// DeviceOverrideChange is one field a device override changes. Old is empty
+// when the field was unset; Added marks an entry for a MAC the site config did
+// not list yet.
+type DeviceOverrideChange struct {
+	MAC   string
+	Field string
+	Old   string
+	New   string
+	Added bool
+}
+
+// fields returns the override's non-empty fields as site-config keys, in a
+// stable order.
+func (o DeviceOverride) fields() [][2]string {
+	var out [][2]string
+	for _, f := range [][2]string{
+		{"name", o.Name},
+		{"deviceprofile_name", o.DeviceProfile},
+		{"radio_profile", o.RadioProfile},
+		{"notes", o.Notes},
+	} {
+		if f[1] != "" {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// MergeSiteDeviceOverrides sets each override's fields on the matching entry
+// of one device type in a site's devices map, adding an entry for a MAC the
+// site does not list yet. MACs are matched normalized, so an existing entry
+// keeps its key spelling and every field the override does not name. With
+// write false the file is left untouched, so the same call previews the
+// merge. Returns the changes in input order.
+func MergeSiteDeviceOverrides(fullPath, siteKey, deviceType string, overrides []DeviceOverride, write bool) ([]DeviceOverrideChange, error) {
+	raw, devices, err := loadRawSiteDevices(fullPath, siteKey)
+	if err != nil {
+		return nil, err
+	}
+	section, _ := devices[deviceType].(map[string]interface{})
+	if section == nil {
+		section = make(map[string]interface{})
+	}
+	keys := make(map[string]string, len(section))
+	for key := range section {
+		keys[macaddr.NormalizeOrEmpty(key)] = key
+	}
+	added := make(map[string]bool)
+
+	var changes []DeviceOverrideChange
+	for _, o := range overrides {
+		mac := macaddr.NormalizeOrEmpty(o.MAC)
+		if mac == "" {
+			return nil, fmt.Errorf("invalid MAC address %q", o.MAC)
+		}
+		key, exists := keys[mac]
+		if !exists {
+			key = mac
+			keys[mac] = key
+			added[key] = true
+			section[key] = map[string]interface{}{}
+		}
+		entry, ok := section[key].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s entry %s in site '%s' is not an object", deviceType, key, siteKey)
+		}
+		for _, f := range o.fields() {
+			old, _ := entry[f[0]].(string)
+			if f[0] == "deviceprofile_name" && old == "" {
+				// deviceprofile_id and deviceprofile_name are mutually
+				// exclusive; a name from the sheet replaces an id.
+				if id, _ := entry["deviceprofile_id"].(string); id != "" {
+					old = "id:" + id
+					delete(entry, "deviceprofile_id")
+				}
+			}
+			if old == f[1] {
+				continue
+			}
+			entry[f[0]] = f[1]
+			changes = append(changes, DeviceOverrideChange{MAC: key, Field: f[0], Old: old, New: f[1], Added: added[key]})
+		}
+	}
+	if len(changes) == 0 || !write {
+		return changes, nil
+	}
+	devices[deviceType] = section
+	return changes, writeRawSiteConfig(fullPath, raw)
+}
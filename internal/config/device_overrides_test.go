@@ -0,0 +1,76 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseDeviceOverridesCSV(t *testing.T) {
+	sheet := "MAC, Name, Radio Template, Notes\n" +
+		"aa:00:00:00:00:01,ap-lobby,lobby-radio,\"ceiling, east\"\n" +
+		",,,\n" +
+		"aa0000000002,,,spare\n"
+	rows, err := ParseDeviceOverridesCSV(strings.NewReader(sheet))
+	if err != nil {
+		t.Fatalf("ParseDeviceOverridesCSV: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("rows = %+v, want 2 (blank row skipped)", rows)
+	}
+	if r := rows[0]; r.Line != 2 || r.Name != "ap-lobby" || r.RadioProfile != "lobby-radio" || r.Notes != "ceiling, east" {
+		t.Errorf("row 0 = %+v", r)
+	}
+	if r := rows[1]; r.Line != 4 || r.Name != "" || r.Notes != "spare" {
+		t.Errorf("row 1 = %+v", r)
+	}
+
+	if _, err := ParseDeviceOverridesCSV(strings.NewReader("name,notes\nx,y\n")); err == nil {
+		t.Error("a sheet without a mac column should fail")
+	}
+	if _, err := ParseDeviceOverridesCSV(strings.NewReader("mac,colour\n")); err == nil {
+		t.Error("an unknown column should fail")
+	}
+}
+
+func TestMergeSiteDeviceOverrides(t *testing.T) {
+	path := writeSiteFixture(t)
+	overrides := []DeviceOverride{
+		{MAC: "AA-00-00-00-00-01", Name: "ap-lobby", DeviceProfile: "lobby"},
+		{MAC: "aa0000000002", Name: "ap-keep"}, // already set: no change
+		{MAC: "aa0000000003", Name: "ap-new", Notes: "spare"},
+	}
+
+	preview, err := MergeSiteDeviceOverrides(path, "US-LAB-01", "ap", overrides, false)
+	if err != nil {
+		t.Fatalf("preview: %v", err)
+	}
+	if len(preview) != 4 {
+		t.Fatalf("preview = %+v, want 4 changes", preview)
+	}
+	if c := preview[0]; c.MAC != "aa:00:00:00:00:01" || c.Field != "name" || c.Old != "ap-gone" || c.Added {
+		t.Errorf("change 0 = %+v", c)
+	}
+	if c := preview[2]; c.MAC != "aa0000000003" || !c.Added {
+		t.Errorf("change 2 = %+v, want a new entry", c)
+	}
+	cfg, _ := LoadSiteConfig(path, "")
+	if len(cfg.Config.Sites["US-LAB-01"].Devices.APs) != 2 {
+		t.Fatal("preview must not write the file")
+	}
+
+	if _, err := MergeSiteDeviceOverrides(path, "US-LAB-01", "ap", overrides, true); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	_, devices, err := loadRawSiteDevices(path, "US-LAB-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	aps := devices["ap"].(map[string]interface{})
+	lobby := aps["aa:00:00:00:00:01"].(map[string]interface{})
+	if lobby["name"] != "ap-lobby" || lobby["deviceprofile_name"] != "lobby" || lobby["serial"] != "S1" {
+		t.Errorf("merged entry should keep unnamed fields, got %v", lobby)
+	}
+	if added, ok := aps["aa0000000003"].(map[string]interface{}); !ok || added["notes"] != "spare" {
+		t.Errorf("new entry = %v", aps["aa0000000003"])
+	}
+}
@@ -0,0 +1,175 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ravinald/wifimgr/internal/macaddr"
+)
+
+// DeviceTagEdit is a planned tag addition or removal across device entries in
+// the site config files, rewritten in memory and not yet written to disk.
+type DeviceTagEdit struct {
+	Tag     string
+	Add     bool
+	Devices []DeviceTagChange
+
+	files map[string]renamedFile // full path -> contents
+}
+
+// DeviceTagChange is one device entry the edit found and what it does to it.
+type DeviceTagChange struct {
+	Site    string   `json:"site"` // site_config.name, else SiteKey
+	SiteKey string   `json:"site_key"`
+	File    string   `json:"file"` // site config file, as listed in config
+	Type    string   `json:"type"`
+	MAC     string   `json:"mac"` // normalized
+	Tags    []string `json:"tags"`
+	Changed bool     `json:"changed"`
+}
+
+// PlanDeviceTags prepares adding tag to (add) or removing it from the tags
+// list of every device entry whose MAC is in macs (normalized). Entries under
+// devices._disabled are left alone. MACs with no entry in any file are simply
+// absent from Devices; the caller decides whether that matters.
+func PlanDeviceTags(siteFiles []string, configDir, tag string, add bool, macs map[string]bool) (*DeviceTagEdit, error) {
+	tag = strings.TrimSpace(tag)
+	if tag == "" {
+		return nil, fmt.Errorf("tag must not be empty")
+	}
+
+	plan := &DeviceTagEdit{Tag: tag, Add: add, files: make(map[string]renamedFile)}
+	seen := make(map[string]bool)
+	for _, file := range siteFiles {
+		full := resolveConfigPath(configDir, file)
+		if seen[full] {
+			continue
+		}
+		seen[full] = true
+		data, err := os.ReadFile(full) // #nosec G304 -- path from operator-controlled config
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", full, err)
+		}
+		var raw map[string]any
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", full, err)
+		}
+
+		cfg, _ := raw["config"].(map[string]any)
+		siteMap, _ := cfg["sites"].(map[string]any)
+		changed := false
+		for _, siteKey := range sortedKeys(siteMap) {
+			site, ok := siteMap[siteKey].(map[string]any)
+			if !ok {
+				continue
+			}
+			name := siteKey
+			if sc, ok := site["site_config"].(map[string]any); ok {
+				if n, ok := sc["name"].(string); ok && n != "" {
+					name = n
+				}
+			}
+			devices, _ := site["devices"].(map[string]any)
+			for _, dtype := range sortedKeys(devices) {
+				if dtype == DisabledDevicesKey {
+					continue
+				}
+				section, _ := devices[dtype].(map[string]any)
+				for _, key := range sortedKeys(section) {
+					mac := macaddr.NormalizeOrEmpty(key)
+					entry, ok := section[key].(map[string]any)
+					if !ok || mac == "" || !macs[mac] {
+						continue
+					}
+					tags, entryChanged := setDeviceTag(entry, tag, add)
+					changed = changed || entryChanged
+					plan.Devices = append(plan.Devices, DeviceTagChange{
+						Site: name, SiteKey: siteKey, File: file, Type: dtype, MAC: mac, Tags: tags, Changed: entryChanged,
+					})
+				}
+			}
+		}
+		if !changed {
+			continue
+		}
+		out, err := json.MarshalIndent(raw, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %s: %w", full, err)
+		}
+		perm := os.FileMode(0600)
+		if info, err := os.Stat(full); err == nil {
+			perm = info.Mode().Perm()
+		}
+		plan.files[full] = renamedFile{original: data, updated: append(out, '\n'), perm: perm}
+	}
+	return plan, nil
+}
+
+// setDeviceTag adds tag to or removes it from a device entry's tags list,
+// dropping the field once empty. Tags compare case-sensitively, as the
+// vendors store them. It returns the resulting list and whether it changed.
+func setDeviceTag(entry map[string]any, tag string, add bool) ([]string, bool) {
+	tags := TagList(entry["tags"])
+	has := slices.Contains(tags, tag)
+	switch {
+	case add && !has:
+		tags = append(tags, tag)
+	case !add && has:
+		tags = slices.DeleteFunc(tags, func(s string) bool { return s == tag })
+	default:
+		return tags, false
+	}
+	if len(tags) == 0 {
+		delete(entry, "tags")
+		return tags, true
+	}
+	list := make([]any, len(tags))
+	for i, t := range tags {
+		list[i] = t
+	}
+	entry["tags"] = list
+	return tags, true
+}
+
+// TagList reads a device tags value: a JSON string array, or the
+// space-separated string Meraki reports device tags as.
+func TagList(v any) []string {
+	var out []string
+	switch list := v.(type) {
+	case []any:
+		for _, item := range list {
+			if s, ok := item.(string); ok && s != "" {
+				out = append(out, s)
+			}
+		}
+	case []string:
+		for _, s := range list {
+			if s != "" {
+				out = append(out, s)
+			}
+		}
+	case string:
+		out = strings.Fields(list)
+	}
+	return out
+}
+
+// Files returns the full paths the edit rewrites, sorted.
+func (e *DeviceTagEdit) Files() []string {
+	out := make([]string, 0, len(e.files))
+	for path := range e.files {
+		out = append(out, path)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Apply writes every rewritten file, restoring the ones already written if a
+// later write fails.
+func (e *DeviceTagEdit) Apply() error {
+	return writeRewrittenFiles(e.files)
+}
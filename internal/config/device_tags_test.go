@@ -0,0 +1,91 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const deviceTagsFixture = `{
+  "version": 1,
+  "config": {
+    "sites": {
+      "sfo": {"site_config": {"name": "US-SFO-01"}, "devices": {
+        "ap": {
+          "aa:00:00:00:00:01": {"name": "ap-lobby", "tags": ["lobby"]},
+          "aa0000000002": {"name": "ap-2"}
+        },
+        "_disabled": {"ap": {"aa0000000003": {"name": "ap-old"}}}
+      }},
+      "nyc": {"site_config": {"name": "US-NYC-02"}, "devices": {"switch": {"aa0000000004": {"name": "sw-1", "tags": ["guest-ok"]}}}}
+    }
+  }
+}`
+
+func TestPlanDeviceTags(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sites.json")
+	if err := os.WriteFile(path, []byte(deviceTagsFixture), 0600); err != nil {
+		t.Fatal(err)
+	}
+	macs := map[string]bool{"aa0000000001": true, "aa0000000002": true, "aa0000000003": true, "aa0000000004": true}
+
+	plan, err := PlanDeviceTags([]string{"sites.json", "sites.json"}, dir, "guest-ok", true, macs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]DeviceTagChange{}
+	for _, c := range plan.Devices {
+		got[c.MAC] = c
+	}
+	if len(got) != 3 {
+		t.Fatalf("devices = %+v, want the disabled entry skipped", plan.Devices)
+	}
+	if c := got["aa0000000001"]; !c.Changed || strings.Join(c.Tags, ",") != "lobby,guest-ok" || c.Site != "US-SFO-01" || c.Type != "ap" {
+		t.Errorf("ap-lobby = %+v", c)
+	}
+	if c := got["aa0000000004"]; c.Changed {
+		t.Errorf("sw-1 already has the tag: %+v", c)
+	}
+	if len(plan.Files()) != 1 {
+		t.Fatalf("files = %v", plan.Files())
+	}
+	if err := plan.Apply(); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), `"guest-ok"`) || !strings.Contains(string(data), `"aa:00:00:00:00:01"`) {
+		t.Errorf("rewritten file should keep MAC keys and carry the tag:\n%s", data)
+	}
+
+	plan, err = PlanDeviceTags([]string{"sites.json"}, dir, "guest-ok", false, map[string]bool{"aa0000000004": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Devices) != 1 || !plan.Devices[0].Changed || len(plan.Devices[0].Tags) != 0 {
+		t.Fatalf("remove = %+v", plan.Devices)
+	}
+	if err := plan.Apply(); err != nil {
+		t.Fatal(err)
+	}
+	data, _ = os.ReadFile(path)
+	if strings.Contains(string(data), `"sw-1",`) {
+		t.Errorf("an emptied tags list should be dropped:\n%s", data)
+	}
+
+	if _, err := PlanDeviceTags([]string{"sites.json"}, dir, " ", true, macs); err == nil {
+		t.Error("an empty tag should be rejected")
+	}
+}
+
+func TestTagList(t *testing.T) {
+	for _, v := range []any{[]any{"a", "", "b"}, []string{"a", "b"}, " a  b "} {
+		if got := strings.Join(TagList(v), ","); got != "a,b" {
+			t.Errorf("TagList(%#v) = %q", v, got)
+		}
+	}
+	if TagList(nil) != nil {
+		t.Error("TagList(nil) should be nil")
+	}
+}
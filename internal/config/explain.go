@@ -0,0 +1,297 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Resolution layers a field of a device's effective config can come from,
+// from lowest to highest precedence.
+const (
+	LayerModel            = "model"
+	LayerDeviceTemplate   = "device_template"
+	LayerSecurityBaseline = "security_baseline"
+	LayerRadioProfile     = "radio_profile"
+	LayerSiteWLAN         = "site wlan"
+	LayerDeviceWLAN       = "device wlan"
+	LayerDevice           = "device"
+	LayerDerived          = "derived" // computed during expansion, e.g. radio bands enabled
+)
+
+// FieldSource says which layer set a field and where that layer is defined.
+type FieldSource struct {
+	Layer  string `json:"layer"`
+	Name   string `json:"name,omitempty"`   // template name or WLAN label
+	Vendor string `json:"vendor,omitempty"` // vendor block the value came from, e.g. "mist:"
+	File   string `json:"file,omitempty"`
+	Line   int    `json:"line,omitempty"` // approximate; 0 when unknown
+}
+
+// ExplainedField is one leaf of a device's effective config and its source.
+type ExplainedField struct {
+	Path   string      `json:"path"`
+	Value  any         `json:"value"`
+	Source FieldSource `json:"source"`
+}
+
+// DeviceLocation is where a device's own entry is declared: the site config
+// file and the JSON key path of the entry in it.
+type DeviceLocation struct {
+	File    string
+	KeyPath []string
+}
+
+// ExplainDeviceConfig expands deviceConfig exactly as ExpandDeviceConfigForModel does
+// and attributes every leaf of the result to the layer that set it: model
+// defaults, device template, security baseline, radio profile, WLAN templates (picked by the
+// site or the device), or the device entry itself. Where a layer won through
+// several, the highest-precedence one that holds the final value is named.
+// Fields are returned sorted by path.
+func ExplainDeviceConfig(
+	deviceConfig map[string]any,
+	model string,
+	siteWLANs []string,
+	templates *TemplateStore,
+	apiLabel string,
+	loc DeviceLocation,
+) ([]ExplainedField, error) {
+	// ExpandDeviceConfig may fill in device maps in place; keep deviceConfig
+	// as declared so derived values are not credited to the device.
+	declared, _ := deepCopy(deviceConfig).(map[string]any)
+	final, err := ExpandDeviceConfigForModel(declared, model, siteWLANs, templates, apiLabel)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &explainer{
+		vendor:    GetVendorFromAPILabel(apiLabel),
+		templates: templates,
+		device:    deviceConfig,
+		loc:       loc,
+		files:     make(map[string][]byte),
+	}
+	if templates == nil {
+		e.templates = NewTemplateStore()
+	}
+	e.collectLayers(model, siteWLANs)
+
+	var fields []ExplainedField
+	for key, value := range final {
+		if key == "wlan" {
+			if wlans, ok := value.([]map[string]any); ok {
+				fields = append(fields, e.explainWLANs(wlans)...)
+				continue
+			}
+		}
+		leaves := make(map[string]any)
+		flattenLeaves(key, value, leaves)
+		for path, v := range leaves {
+			fields = append(fields, ExplainedField{Path: path, Value: v, Source: e.sourceOf(path, v)})
+		}
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Path < fields[j].Path })
+	return fields, nil
+}
+
+// templateLayer is one template that took part in an expansion.
+type templateLayer struct {
+	layer, kind, name string
+	prefix            string         // where the template lands in the result, e.g. "radio_config"
+	raw               map[string]any // as defined, with vendor blocks
+	expanded          map[string]any // vendor block merged in
+}
+
+type explainer struct {
+	vendor    string
+	templates *TemplateStore
+	device    map[string]any
+	loc       DeviceLocation
+
+	layers    []templateLayer // highest precedence first
+	wlanLayer string
+	wlans     []templateLayer
+	files     map[string][]byte
+}
+
+// collectLayers looks up the templates the device references, mirroring the
+// lookups in ExpandDeviceConfigForModel.
+func (e *explainer) collectLayers(model string, siteWLANs []string) {
+	add := func(layer, kind, name, prefix string, raw, expanded map[string]any) {
+		e.layers = append(e.layers, templateLayer{layer: layer, kind: kind, name: name, prefix: prefix, raw: raw, expanded: expanded})
+	}
+
+	if name, ok := e.device["radio_profile"].(string); ok {
+		if t, found := e.templates.GetRadioTemplate(name); found {
+			add(LayerRadioProfile, TemplateKindRadio, name, "radio_config", t, ExpandForVendor(t, e.vendor))
+		}
+	}
+
+	var deviceTemplate map[string]any
+	if name, ok := e.device["device_template"].(string); ok {
+		deviceTemplate, _ = e.templates.GetDeviceTemplate(name)
+	}
+	baselineName, _ := e.device[SecurityBaselineField].(string)
+	if baselineName == "" && deviceTemplate != nil {
+		baselineName, _ = ExpandForVendor(deviceTemplate, e.vendor)[SecurityBaselineField].(string)
+	}
+	if baselineName != "" && e.vendor != "meraki" {
+		if t, found := e.templates.GetSecurityTemplate(baselineName); found {
+			if b, err := parseSecurityBaseline(baselineName, ExpandForVendor(t, e.vendor)); err == nil {
+				add(LayerSecurityBaseline, TemplateKindSecurity, baselineName, "", t, expandSecurityBaselineMist(b))
+			}
+		}
+	}
+
+	if deviceTemplate != nil {
+		name := e.device["device_template"].(string)
+		add(LayerDeviceTemplate, TemplateKindDevice, name, "", deviceTemplate, ExpandForVendor(deviceTemplate, e.vendor))
+	}
+	if t, name, found := e.templates.GetModelTemplate(model); found {
+		add(LayerModel, TemplateKindModel, name, "", t, ExpandForVendor(t, e.vendor))
+	}
+
+	labels := siteWLANs
+	e.wlanLayer = LayerSiteWLAN
+	if deviceWLANs, ok := e.device["wlan"].([]any); ok && len(deviceWLANs) > 0 {
+		labels = toStringSlice(deviceWLANs)
+		e.wlanLayer = LayerDeviceWLAN
+	}
+	// expandWLANs skips labels with no template, so the result lines up with
+	// the labels that were found.
+	for _, label := range labels {
+		if t, found := e.templates.GetWLANTemplate(label); found {
+			e.wlans = append(e.wlans, templateLayer{layer: e.wlanLayer, kind: TemplateKindWLAN, name: label, raw: t, expanded: ExpandForVendor(t, e.vendor)})
+		}
+	}
+}
+
+// sourceOf attributes the leaf at path with the final value v.
+func (e *explainer) sourceOf(path string, v any) FieldSource {
+	segs := strings.Split(path, ".")
+	if !isTemplateReferenceField(segs[0]) {
+		if dv, ok := lookupPath(e.device, segs); ok && reflect.DeepEqual(dv, v) {
+			keyPath := append(append([]string{}, e.loc.KeyPath...), segs...)
+			return FieldSource{Layer: LayerDevice, File: e.loc.File, Line: e.line(e.loc.File, keyPath)}
+		}
+	}
+	for _, l := range e.layers {
+		rel := segs
+		if l.prefix != "" {
+			if segs[0] != l.prefix {
+				continue
+			}
+			rel = segs[1:]
+		}
+		if tv, ok := lookupPath(l.expanded, rel); ok && reflect.DeepEqual(tv, v) {
+			return e.templateSource(l, rel)
+		}
+	}
+	return FieldSource{Layer: LayerDerived}
+}
+
+// explainWLANs attributes each expanded WLAN's fields to its template.
+func (e *explainer) explainWLANs(wlans []map[string]any) []ExplainedField {
+	var fields []ExplainedField
+	for i, w := range wlans {
+		leaves := make(map[string]any)
+		for k, v := range w {
+			flattenLeaves(k, v, leaves)
+		}
+		for path, v := range leaves {
+			src := FieldSource{Layer: e.wlanLayer}
+			if i < len(e.wlans) {
+				src = e.templateSource(e.wlans[i], strings.Split(path, "."))
+			}
+			fields = append(fields, ExplainedField{Path: fmt.Sprintf("wlan[%d].%s", i, path), Value: v, Source: src})
+		}
+	}
+	return fields
+}
+
+// templateSource locates rel inside template l, noting when the value came
+// from the vendor block rather than the common fields.
+func (e *explainer) templateSource(l templateLayer, rel []string) FieldSource {
+	src := FieldSource{Layer: l.layer, Name: l.name, File: e.templates.Source(l.kind, l.name)}
+	keyPath := []string{"templates", l.kind, l.name}
+	if block, ok := l.raw[e.vendor+":"].(map[string]any); ok && e.vendor != "" {
+		if _, found := lookupPath(block, rel); found {
+			src.Vendor = e.vendor + ":"
+			keyPath = append(keyPath, src.Vendor)
+		}
+	}
+	if l.layer != LayerSecurityBaseline {
+		keyPath = append(keyPath, rel...)
+	}
+	src.Line = e.line(src.File, keyPath)
+	return src
+}
+
+// line estimates the line of keyPath in file, reading each file once.
+func (e *explainer) line(file string, keyPath []string) int {
+	if file == "" {
+		return 0
+	}
+	data, ok := e.files[file]
+	if !ok {
+		data, _ = os.ReadFile(file) // #nosec G304 -- template and site config paths from operator-controlled config
+		e.files[file] = data
+	}
+	if len(data) == 0 {
+		return 0
+	}
+	return deepestKeyLine(data, keyPath)
+}
+
+// deepestKeyLine returns the line of the deepest key of keyPath found in
+// order in jsonData, or 0 if not even the first is found. Unlike
+// EstimateLineNumber it matches several keys on one line, so a field inside a
+// compact object points at that object's line instead of the end of the file.
+func deepestKeyLine(jsonData []byte, keyPath []string) int {
+	line, matched := 0, 0
+	for i, text := range strings.Split(string(jsonData), "\n") {
+		for matched < len(keyPath) {
+			idx := strings.Index(text, `"`+keyPath[matched]+`"`)
+			if idx < 0 || !strings.Contains(text[idx:], ":") {
+				break
+			}
+			text = text[idx+len(keyPath[matched])+2:]
+			line = i + 1
+			matched++
+		}
+		if matched == len(keyPath) {
+			break
+		}
+	}
+	return line
+}
+
+// flattenLeaves adds the leaves of v under prefix to out, joining map keys
+// with dots. Lists and empty maps are leaves.
+func flattenLeaves(prefix string, v any, out map[string]any) {
+	m, ok := v.(map[string]any)
+	if !ok || len(m) == 0 {
+		out[prefix] = v
+		return
+	}
+	for k, child := range m {
+		flattenLeaves(prefix+"."+k, child, out)
+	}
+}
+
+// lookupPath returns the value at the dotted path segs inside m.
+func lookupPath(m map[string]any, segs []string) (any, bool) {
+	var cur any = m
+	for _, s := range segs {
+		next, ok := cur.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if cur, ok = next[s]; !ok {
+			return nil, false
+		}
+	}
+	return cur, true
+}
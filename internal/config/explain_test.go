@@ -0,0 +1,147 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const explainTemplates = `{
+  "version": 1,
+  "templates": {
+    "radio": {
+      "lab": {
+        "band_5": {
+          "channel": 36,
+          "power": 10
+        },
+        "mist:": {
+          "band_5": {
+            "power": 12
+          }
+        }
+      }
+    },
+    "wlan": {
+      "corp": {
+        "ssid": "Corp",
+        "vlan_id": 10
+      }
+    },
+    "device": {
+      "std-ap": {
+        "notes": "standard",
+        "led": {
+          "enabled": false
+        }
+      }
+    }
+  }
+}
+`
+
+func TestExplainDeviceConfig(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "templates.json")
+	if err := os.WriteFile(file, []byte(explainTemplates), 0600); err != nil {
+		t.Fatal(err)
+	}
+	store, err := LoadTemplates([]string{file}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	device := map[string]any{
+		"name":            "ap-1",
+		"notes":           "lobby",
+		"device_template": "std-ap",
+		"radio_profile":   "lab",
+	}
+	fields, err := ExplainDeviceConfig(device, "", []string{"corp"}, store, "mist-prod", DeviceLocation{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string]FieldSource, len(fields))
+	for _, f := range fields {
+		got[f.Path] = f.Source
+	}
+	want := map[string]FieldSource{
+		"name":                         {Layer: LayerDevice},
+		"notes":                        {Layer: LayerDevice},
+		"led.enabled":                  {Layer: LayerDeviceTemplate, Name: "std-ap", File: file, Line: 27},
+		"radio_config.band_5.channel":  {Layer: LayerRadioProfile, Name: "lab", File: file, Line: 7},
+		"radio_config.band_5.power":    {Layer: LayerRadioProfile, Name: "lab", Vendor: "mist:", File: file, Line: 12},
+		"radio_config.band_5.disabled": {Layer: LayerDerived},
+		"wlan[0].ssid":                 {Layer: LayerSiteWLAN, Name: "corp", File: file, Line: 19},
+		"wlan[0].vlan_id":              {Layer: LayerSiteWLAN, Name: "corp", File: file, Line: 20},
+	}
+	if len(got) != len(want) {
+		t.Errorf("got %d fields, want %d: %+v", len(got), len(want), fields)
+	}
+	for path, w := range want {
+		if g, ok := got[path]; !ok || g != w {
+			t.Errorf("%s: got %+v, want %+v", path, g, w)
+		}
+	}
+	if _, ok := device["radio_config"]; ok {
+		t.Error("the device config was modified")
+	}
+}
+
+func TestExplainDeviceConfig_DeviceWLANs(t *testing.T) {
+	store := NewTemplateStore()
+	store.WLAN["guest"] = map[string]any{"ssid": "Guest"}
+	store.WLAN["corp"] = map[string]any{"ssid": "Corp"}
+
+	device := map[string]any{"wlan": []any{"guest", "missing"}}
+	fields, err := ExplainDeviceConfig(device, "", []string{"corp"}, store, "", DeviceLocation{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fields) != 1 || fields[0].Path != "wlan[0].ssid" || fields[0].Value != "Guest" {
+		t.Fatalf("fields = %+v", fields)
+	}
+	if src := fields[0].Source; src.Layer != LayerDeviceWLAN || src.Name != "guest" || src.File != "" {
+		t.Errorf("source = %+v", src)
+	}
+}
+
+func TestDeepestKeyLine(t *testing.T) {
+	data := []byte("{\n  \"a\": {\"b\": 1,\n    \"c\": 2\n  }\n}\n")
+	cases := []struct {
+		path []string
+		want int
+	}{
+		{[]string{"a", "b"}, 2},
+		{[]string{"a", "c"}, 3},
+		{[]string{"a", "missing"}, 2},
+		{[]string{"missing"}, 0},
+	}
+	for _, c := range cases {
+		if got := deepestKeyLine(data, c.path); got != c.want {
+			t.Errorf("deepestKeyLine(%v) = %d, want %d", c.path, got, c.want)
+		}
+	}
+}
+
+func TestExplainDeviceConfig_Model(t *testing.T) {
+	store := NewTemplateStore()
+	store.Model["AP12"] = map[string]any{"mesh": map[string]any{"enabled": false}, "led": map[string]any{"enabled": true}}
+
+	device := map[string]any{"led": map[string]any{"enabled": false}}
+	fields, err := ExplainDeviceConfig(device, "ap12", nil, store, "mist-prod", DeviceLocation{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]FieldSource, len(fields))
+	for _, f := range fields {
+		got[f.Path] = f.Source
+	}
+	if src := got["mesh.enabled"]; src.Layer != LayerModel || src.Name != "AP12" {
+		t.Errorf("mesh.enabled source = %+v, want the AP12 model template", src)
+	}
+	if src := got["led.enabled"]; src.Layer != LayerDevice {
+		t.Errorf("led.enabled source = %+v, want the device override", src)
+	}
+}
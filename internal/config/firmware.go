@@ -0,0 +1,159 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/ravinald/wifimgr/internal/macaddr"
+)
+
+// FirmwareField pins firmware versions by device model. It is allowed on a
+// site (applies to every device there) and in a device template (applies to
+// the devices using it, overriding the site). Keys are model names or
+// path.Match globs such as "AP4*"; values are version strings.
+const FirmwareField = "firmware"
+
+// Firmware pin sources, as reported by FirmwarePins.Lookup.
+const (
+	FirmwareSourceSite     = "site"
+	FirmwareSourceTemplate = "template"
+)
+
+// FirmwarePin is the version a device is pinned to and where the pin came from.
+type FirmwarePin struct {
+	Version  string `json:"version"`
+	Source   string `json:"source"`             // FirmwareSourceSite or FirmwareSourceTemplate
+	Template string `json:"template,omitempty"` // device template label for template pins
+	Pattern  string `json:"pattern"`            // the model key that matched
+}
+
+// FirmwarePins holds the firmware pins in effect at one site.
+type FirmwarePins struct {
+	Site    map[string]string // site-level model -> version
+	devices map[string]devicePins
+}
+
+type devicePins struct {
+	template string
+	pins     map[string]string
+}
+
+// LoadFirmwarePins reads the firmware pins for site (matched
+// case-insensitively against site_config.name or the site key) from the
+// given site config files. Device template pins are looked up in templates,
+// which may be nil. It is an error for the site to be missing from every file.
+func LoadFirmwarePins(siteFiles []string, configDir, site string, templates *TemplateStore) (*FirmwarePins, error) {
+	for _, file := range siteFiles {
+		raw, err := readRawConfigFile(resolveConfigPath(configDir, file))
+		if err != nil {
+			return nil, err
+		}
+		cfg, _ := raw["config"].(map[string]any)
+		sites, _ := cfg["sites"].(map[string]any)
+		for _, siteKey := range sortedKeys(sites) {
+			siteObj, ok := sites[siteKey].(map[string]any)
+			if !ok || !siteMatches(siteObj, siteKey, site) {
+				continue
+			}
+			pins, err := parseFirmwarePins(siteObj[FirmwareField], siteKey)
+			if err != nil {
+				return nil, err
+			}
+			result := &FirmwarePins{Site: pins, devices: make(map[string]devicePins)}
+			walkTemplateRefs(map[string]any{"config": map[string]any{"sites": map[string]any{siteKey: siteObj}}},
+				func(ref TemplateRef, _ func(string)) {
+					if ref.Field != "device_template" || templates == nil {
+						return
+					}
+					tmpl, found := templates.GetDeviceTemplate(ref.Label)
+					if !found {
+						return
+					}
+					tpins, err := parseFirmwarePins(tmpl[FirmwareField], "device template "+ref.Label)
+					if err != nil || len(tpins) == 0 {
+						return
+					}
+					result.devices[macaddr.NormalizeOrEmpty(ref.MAC)] = devicePins{template: ref.Label, pins: tpins}
+				})
+			return result, nil
+		}
+	}
+	return nil, fmt.Errorf("site %q not found in site config files", site)
+}
+
+// siteMatches reports whether a site object is the one named by name.
+func siteMatches(siteObj map[string]any, siteKey, name string) bool {
+	if strings.EqualFold(siteKey, name) {
+		return true
+	}
+	sc, _ := siteObj["site_config"].(map[string]any)
+	n, _ := sc["name"].(string)
+	return n != "" && strings.EqualFold(n, name)
+}
+
+// parseFirmwarePins validates a firmware object; owner names it in errors.
+func parseFirmwarePins(v any, owner string) (map[string]string, error) {
+	if v == nil {
+		return nil, nil
+	}
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%s: %s must be an object of model to version", owner, FirmwareField)
+	}
+	pins := make(map[string]string, len(obj))
+	for model, raw := range obj {
+		version, ok := raw.(string)
+		if !ok || version == "" {
+			return nil, fmt.Errorf("%s: %s.%s must be a non-empty version string", owner, FirmwareField, model)
+		}
+		if _, err := path.Match(model, ""); err != nil {
+			return nil, fmt.Errorf("%s: %s: invalid model pattern %q", owner, FirmwareField, model)
+		}
+		pins[model] = version
+	}
+	return pins, nil
+}
+
+// Empty reports whether no pins are declared at the site or in any device
+// template used there.
+func (p *FirmwarePins) Empty() bool {
+	return len(p.Site) == 0 && len(p.devices) == 0
+}
+
+// Lookup returns the pin for a device by MAC and model. A pin from the
+// device's template wins over a site pin; within each, an exact model match
+// (case-insensitive) wins over the longest matching glob.
+func (p *FirmwarePins) Lookup(mac, model string) (FirmwarePin, bool) {
+	if dp, ok := p.devices[macaddr.NormalizeOrEmpty(mac)]; ok {
+		if pattern, version, ok := matchFirmwareModel(dp.pins, model); ok {
+			return FirmwarePin{Version: version, Source: FirmwareSourceTemplate, Template: dp.template, Pattern: pattern}, true
+		}
+	}
+	if pattern, version, ok := matchFirmwareModel(p.Site, model); ok {
+		return FirmwarePin{Version: version, Source: FirmwareSourceSite, Pattern: pattern}, true
+	}
+	return FirmwarePin{}, false
+}
+
+func matchFirmwareModel(pins map[string]string, model string) (pattern, version string, ok bool) {
+	if model == "" {
+		return "", "", false
+	}
+	for key, v := range pins {
+		if strings.EqualFold(key, model) {
+			return key, v, true
+		}
+	}
+	upper := strings.ToUpper(model)
+	for key, v := range pins {
+		matched, _ := path.Match(strings.ToUpper(key), upper)
+		if !matched {
+			continue
+		}
+		if !ok || len(key) > len(pattern) || (len(key) == len(pattern) && key < pattern) {
+			pattern, version, ok = key, v, true
+		}
+	}
+	return pattern, version, ok
+}
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const firmwareFixture = `{
+  "version": 1,
+  "config": {
+    "sites": {
+      "sfo": {
+        "site_config": {"name": "US-SFO-01"},
+        "firmware": {"AP43": "0.14.29313", "AP4*": "0.12.27139", "EX4100*": "22.4R3"},
+        "devices": {
+          "ap": {
+            "aa:00:00:00:00:01": {"name": "ap-lobby", "device_template": "lobby-ap"},
+            "aa:00:00:00:00:02": {"name": "ap-hall"}
+          }
+        }
+      },
+      "nyc": {"site_config": {"name": "US-NYC-02"}}
+    }
+  }
+}`
+
+func writeFirmwareFixture(t *testing.T, content string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "sites.json"), []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestFirmwarePinsLookup(t *testing.T) {
+	dir := writeFirmwareFixture(t, firmwareFixture)
+	templates := NewTemplateStore()
+	templates.Device["lobby-ap"] = map[string]any{"firmware": map[string]any{"AP43": "0.15.0"}}
+
+	pins, err := LoadFirmwarePins([]string{"sites.json"}, dir, "us-sfo-01", templates)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name, mac, model string
+		want             FirmwarePin
+		found            bool
+	}{
+		{"template wins", "aa0000000001", "AP43", FirmwarePin{Version: "0.15.0", Source: FirmwareSourceTemplate, Template: "lobby-ap", Pattern: "AP43"}, true},
+		{"template miss falls back to site", "aa0000000001", "AP45", FirmwarePin{Version: "0.12.27139", Source: FirmwareSourceSite, Pattern: "AP4*"}, true},
+		{"exact beats glob", "aa0000000002", "ap43", FirmwarePin{Version: "0.14.29313", Source: FirmwareSourceSite, Pattern: "AP43"}, true},
+		{"glob", "cc0000000001", "EX4100-48P", FirmwarePin{Version: "22.4R3", Source: FirmwareSourceSite, Pattern: "EX4100*"}, true},
+		{"unpinned model", "cc0000000002", "AP12", FirmwarePin{}, false},
+		{"no model", "cc0000000003", "", FirmwarePin{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := pins.Lookup(tt.mac, tt.model)
+			if ok != tt.found || got != tt.want {
+				t.Errorf("Lookup(%s, %s) = %+v, %v; want %+v, %v", tt.mac, tt.model, got, ok, tt.want, tt.found)
+			}
+		})
+	}
+}
+
+func TestFirmwarePinsEmptyAndMissingSite(t *testing.T) {
+	dir := writeFirmwareFixture(t, firmwareFixture)
+
+	pins, err := LoadFirmwarePins([]string{"sites.json"}, dir, "nyc", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pins.Empty() {
+		t.Errorf("nyc declares no pins: %+v", pins)
+	}
+
+	if _, err := LoadFirmwarePins([]string{"sites.json"}, dir, "US-LAX-01", nil); err == nil {
+		t.Error("expected error for a missing site")
+	}
+}
+
+func TestFirmwarePinsInvalid(t *testing.T) {
+	bad := strings.Replace(firmwareFixture, `"AP43": "0.14.29313"`, `"AP43": 14`, 1)
+	dir := writeFirmwareFixture(t, bad)
+	if _, err := LoadFirmwarePins([]string{"sites.json"}, dir, "sfo", nil); err == nil || !strings.Contains(err.Error(), "firmware.AP43") {
+		t.Errorf("err = %v, want a firmware.AP43 version error", err)
+	}
+}
+
+func TestExpandDeviceConfigDropsFirmware(t *testing.T) {
+	templates := NewTemplateStore()
+	templates.Device["lobby-ap"] = map[string]any{"led": map[string]any{"enabled": true}, "firmware": map[string]any{"AP43": "0.15.0"}}
+
+	got, err := ExpandDeviceConfig(map[string]any{"device_template": "lobby-ap"}, nil, templates, "mist")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := got[FirmwareField]; ok {
+		t.Errorf("firmware pins leaked into device config: %v", got)
+	}
+	if _, ok := got["led"]; !ok {
+		t.Errorf("template config lost: %v", got)
+	}
+}
@@ -94,4 +94,37 @@ func MergeImportTemplates(store *TemplateStore, imp *ImportFile) {
 	for name, tmpl := range imp.Templates.Device {
 		store.Device[name] = tmpl
 	}
+	for name, tmpl := range imp.Templates.AppPolicy {
+		store.AppPolicy[name] = tmpl
+	}
+	for name, tmpl := range imp.Templates.TrafficSteering {
+		store.TrafficSteering[name] = tmpl
+	}
+	for name, tmpl := range imp.Templates.Security {
+		store.Security[name] = tmpl
+	}
+	for name, tmpl := range imp.Templates.Model {
+		store.Model[name] = tmpl
+	}
+}
+
+// RecordImportSources notes path as the file defining each template in imp,
+// so a merged import template can be traced back to it.
+func RecordImportSources(store *TemplateStore, imp *ImportFile, path string) {
+	if store == nil || imp == nil || imp.Templates == nil {
+		return
+	}
+	for kind, templates := range map[string]map[string]map[string]any{
+		TemplateKindRadio:           imp.Templates.Radio,
+		TemplateKindWLAN:            imp.Templates.WLAN,
+		TemplateKindDevice:          imp.Templates.Device,
+		TemplateKindAppPolicy:       imp.Templates.AppPolicy,
+		TemplateKindTrafficSteering: imp.Templates.TrafficSteering,
+		TemplateKindSecurity:        imp.Templates.Security,
+		TemplateKindModel:           imp.Templates.Model,
+	} {
+		for name := range templates {
+			store.SetSource(kind, name, path)
+		}
+	}
 }
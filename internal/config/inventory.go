@@ -49,6 +49,10 @@ type InventoryFile struct {
 			Site map[string]SiteInventory `json:"site"`
 		} `json:"inventory"`
 	} `json:"config"`
+
+	// sources maps a lowercased site name to the files it was merged from;
+	// set by LoadInventory only.
+	sources map[string][]string
 }
 
 // InventoryPath resolves the inventory.json path the way every caller needs it:
@@ -187,12 +191,18 @@ func SaveInventoryFile(path string, f *InventoryFile) error {
 	return nil
 }
 
-// ArmSiteDevices merges the given MACs into a site's allowlist at path, creating
-// the file if absent and leaving every other site untouched. MACs are stored as
-// canonical lowercase bare hex and de-duplicated, so re-running an import is
-// idempotent. A non-empty note is stamped on the site section (see
-// SiteInventory.Note). deviceType slices map to ap/switch/gateway.
+// ArmSiteDevices merges the given MACs into a site's allowlist, creating the
+// file if absent and leaving every other site untouched. path is the primary
+// inventory file; the write goes to whichever inventory file already holds the
+// site (see InventoryFileForSite). MACs are stored as canonical lowercase bare
+// hex and de-duplicated, so re-running an import is idempotent. A non-empty
+// note is stamped on the site section (see SiteInventory.Note). deviceType
+// slices map to ap/switch/gateway.
 func ArmSiteDevices(path, siteName string, aps, switches, gateways []string, note string) error {
+	path, err := InventoryFileForSite(path, siteName)
+	if err != nil {
+		return err
+	}
 	f, err := LoadInventoryFile(path)
 	if err != nil {
 		if !errors.Is(err, os.ErrNotExist) {
@@ -218,8 +228,9 @@ func ArmSiteDevices(path, siteName string, aps, switches, gateways []string, not
 	return SaveInventoryFile(path, f)
 }
 
-// DisarmSiteDevices removes the given MACs from a site's allowlist at path,
-// leaving every other site untouched. MACs are normalized before comparison so
+// DisarmSiteDevices removes the given MACs from a site's allowlist in every
+// inventory file that holds the site (path is the primary file), leaving every
+// other site untouched. MACs are normalized before comparison so
 // callers may pass any spelling. A site whose ap/switch/gateway slices are all
 // empty after removal is pruned — unless it carries a Note (see
 // SiteInventory.Note), which an operator put there deliberately and a prune
@@ -227,6 +238,23 @@ func ArmSiteDevices(path, siteName string, aps, switches, gateways []string, not
 // so nothing can be disarmed. Returns the count of MACs actually removed so the
 // caller can report "already unmanaged".
 func DisarmSiteDevices(path, siteName string, aps, switches, gateways []string) (int, error) {
+	owners, err := inventoryFilesWithSite(path, siteName)
+	if err != nil {
+		return 0, err
+	}
+	total := 0
+	for _, owner := range owners {
+		removed, err := disarmSiteDevicesIn(owner, siteName, aps, switches, gateways)
+		if err != nil {
+			return total, err
+		}
+		total += removed
+	}
+	return total, nil
+}
+
+// disarmSiteDevicesIn is DisarmSiteDevices for a single file.
+func disarmSiteDevicesIn(path, siteName string, aps, switches, gateways []string) (int, error) {
 	f, err := LoadInventoryFile(path)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
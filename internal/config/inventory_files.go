@@ -0,0 +1,158 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// Inventory can be split across files so that regional teams or an acquired
+// org each own theirs: files.inventory is the primary file, and
+// files.inventory_files lists more (paths or globs relative to
+// files.config_dir). Reads merge every file; a site armed in more than one
+// file gets the union of its MACs. Writes go to the file that already holds
+// the site, so a team's edits never land in another team's file; a new site
+// goes to the primary file.
+
+// InventoryFiles returns the inventory files in merge order: primary, then
+// each files.inventory_files entry with its glob matches sorted. Entries that
+// match nothing are skipped, so a pattern can cover files yet to be created.
+func InventoryFiles(primary string) ([]string, error) {
+	var files []string
+	seen := make(map[string]bool)
+	add := func(path string) {
+		if path != "" && !seen[filepath.Clean(path)] {
+			seen[filepath.Clean(path)] = true
+			files = append(files, path)
+		}
+	}
+	add(primary)
+
+	configDir := viper.GetString("files.config_dir")
+	for _, rel := range viper.GetStringSlice("files.inventory_files") {
+		pattern := rel
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(configDir, rel)
+		}
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("inventory: bad pattern in files.inventory_files %q: %w", rel, err)
+		}
+		sort.Strings(matches)
+		for _, m := range matches {
+			add(m)
+		}
+	}
+	return files, nil
+}
+
+// LoadInventory reads every inventory file (see InventoryFiles) and merges
+// them. A missing file is skipped; when none exists the os.ErrNotExist of the
+// primary file is returned, so callers keep treating absence as before. Any
+// other error, including a legacy schema in any file, fails the load.
+func LoadInventory(primary string) (*InventoryFile, error) {
+	files, err := InventoryFiles(primary)
+	if err != nil {
+		return nil, err
+	}
+
+	var merged *InventoryFile
+	var notExist error
+	for _, path := range files {
+		f, err := LoadInventoryFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			if notExist == nil {
+				notExist = err
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if merged == nil {
+			merged = &InventoryFile{Version: f.Version, Metadata: f.Metadata}
+			merged.Config.Inventory.Site = map[string]SiteInventory{}
+		}
+		merged.merge(f, path)
+	}
+	if merged == nil {
+		if notExist == nil {
+			notExist = fmt.Errorf("inventory: no inventory file configured: %w", os.ErrNotExist)
+		}
+		return nil, notExist
+	}
+	return merged, nil
+}
+
+// merge folds the sites of other, read from path, into f, matching site
+// names case-insensitively.
+func (f *InventoryFile) merge(other *InventoryFile, path string) {
+	if f.sources == nil {
+		f.sources = map[string][]string{}
+	}
+	for name, si := range other.Config.Inventory.Site {
+		key, exists := f.siteKey(name)
+		if exists {
+			cur := f.Config.Inventory.Site[key]
+			cur.AP = mergeMACs(cur.AP, si.AP)
+			cur.Switch = mergeMACs(cur.Switch, si.Switch)
+			cur.Gateway = mergeMACs(cur.Gateway, si.Gateway)
+			switch {
+			case cur.Note == "":
+				cur.Note = si.Note
+			case si.Note != "" && si.Note != cur.Note:
+				cur.Note += "; " + si.Note
+			}
+			si = cur
+		}
+		f.Config.Inventory.Site[key] = si
+		f.sources[strings.ToLower(key)] = append(f.sources[strings.ToLower(key)], path)
+	}
+}
+
+// SiteFiles returns the files a site is armed in, for an inventory returned
+// by LoadInventory. It is nil for a single file read with LoadInventoryFile.
+func (f *InventoryFile) SiteFiles(siteName string) []string {
+	if f == nil {
+		return nil
+	}
+	return f.sources[strings.ToLower(siteName)]
+}
+
+// InventoryFileForSite returns the file that writes to a site's allowlist go
+// to: the first inventory file that already holds the site, else primary.
+func InventoryFileForSite(primary, siteName string) (string, error) {
+	owners, err := inventoryFilesWithSite(primary, siteName)
+	if err != nil || len(owners) == 0 {
+		return primary, err
+	}
+	return owners[0], nil
+}
+
+// inventoryFilesWithSite returns the inventory files holding siteName, in
+// merge order.
+func inventoryFilesWithSite(primary, siteName string) ([]string, error) {
+	files, err := InventoryFiles(primary)
+	if err != nil {
+		return nil, err
+	}
+	var owners []string
+	for _, path := range files {
+		f, err := LoadInventoryFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := f.siteKey(siteName); ok {
+			owners = append(owners, path)
+		}
+	}
+	return owners, nil
+}
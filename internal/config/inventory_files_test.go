@@ -0,0 +1,131 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+// setupInventoryFiles writes a primary inventory plus a regional file
+// matched by files.inventory_files, and returns the primary and EMEA paths.
+func setupInventoryFiles(t *testing.T) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "inventory"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	write := func(rel, body string) {
+		if err := os.WriteFile(filepath.Join(dir, rel), []byte(body), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("inventory.json", `{"version": 1, "config": {"inventory": {"site": {
+	  "US-LAB-01": {"ap": ["aabbccddee01"]}
+	}}}}`)
+	write("inventory/emea.json", `{"version": 1, "config": {"inventory": {"site": {
+	  "GB-LON-01": {"ap": ["aabbccddee02"], "_note": "EMEA team"},
+	  "us-lab-01": {"switch": ["aabbccddee03"]}
+	}}}}`)
+	write("inventory/legacy-name.txt", `not an inventory`)
+
+	viper.Set("files.config_dir", dir)
+	viper.Set("files.inventory_files", []string{"inventory/*.json", "missing/*.json"})
+	t.Cleanup(func() {
+		viper.Set("files.config_dir", nil)
+		viper.Set("files.inventory_files", nil)
+	})
+	return filepath.Join(dir, "inventory.json"), filepath.Join(dir, "inventory", "emea.json")
+}
+
+func TestLoadInventory_MergesFiles(t *testing.T) {
+	primary, emea := setupInventoryFiles(t)
+
+	inv, err := LoadInventory(primary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := inv.MACsForSite("GB-LON-01", "ap"); len(got) != 1 || got[0] != "aabbccddee02" {
+		t.Errorf("GB-LON-01 ap = %v", got)
+	}
+	// A site in two files (case-insensitive) gets the union.
+	lab := inv.NormalizedSet([]string{"US-LAB-01"}, "")
+	if !lab["aabbccddee01"] || !lab["aabbccddee03"] || len(lab) != 2 {
+		t.Errorf("US-LAB-01 set = %v, want the union of both files", lab)
+	}
+	if got := inv.SiteFiles("us-lab-01"); len(got) != 2 || got[0] != primary || got[1] != emea {
+		t.Errorf("SiteFiles(us-lab-01) = %v", got)
+	}
+}
+
+func TestLoadInventory_MissingPrimary(t *testing.T) {
+	primary, _ := setupInventoryFiles(t)
+	if err := os.Remove(primary); err != nil {
+		t.Fatal(err)
+	}
+	inv, err := LoadInventory(primary)
+	if err != nil {
+		t.Fatalf("regional files alone should load: %v", err)
+	}
+	if len(inv.SiteNames()) != 2 {
+		t.Errorf("sites = %v", inv.SiteNames())
+	}
+
+	viper.Set("files.inventory_files", nil)
+	if _, err := LoadInventory(primary); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("no files at all: err = %v, want os.ErrNotExist", err)
+	}
+}
+
+func TestArmSiteDevices_RoutesToOwningFile(t *testing.T) {
+	primary, emea := setupInventoryFiles(t)
+
+	if err := ArmSiteDevices(primary, "gb-lon-01", []string{"aa:bb:cc:dd:ee:04"}, nil, nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	regional, err := LoadInventoryFile(emea)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := regional.MACsForSite("GB-LON-01", "ap"); len(got) != 2 {
+		t.Errorf("EMEA GB-LON-01 ap = %v, want the new MAC added there", got)
+	}
+
+	// A new site goes to the primary file.
+	if err := ArmSiteDevices(primary, "JP-TYO-01", []string{"aabbccddee05"}, nil, nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	primaryInv, err := LoadInventoryFile(primary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(primaryInv.MACsForSite("JP-TYO-01", "ap")) != 1 {
+		t.Error("new site not written to the primary file")
+	}
+	if len(primaryInv.MACsForSite("GB-LON-01", "ap")) != 0 {
+		t.Error("GB-LON-01 leaked into the primary file")
+	}
+}
+
+func TestDisarmSiteDevices_AcrossFiles(t *testing.T) {
+	primary, emea := setupInventoryFiles(t)
+
+	removed, err := DisarmSiteDevices(primary, "US-LAB-01", []string{"aabbccddee01"}, []string{"aabbccddee03"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed != 2 {
+		t.Errorf("removed = %d, want 2", removed)
+	}
+	for _, path := range []string{primary, emea} {
+		f, err := LoadInventoryFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(f.NormalizedSet([]string{"US-LAB-01"}, "")) != 0 {
+			t.Errorf("%s still arms devices at US-LAB-01", path)
+		}
+	}
+}
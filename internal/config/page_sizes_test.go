@@ -0,0 +1,65 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePageSizes(t *testing.T) {
+	cases := []struct {
+		name      string
+		nested    map[string]interface{}
+		want      map[string]int
+		wantWarns int
+	}{
+		{
+			name:   "absent yields nil",
+			nested: map[string]interface{}{},
+		},
+		{
+			name: "valid sizes, keys lowercased",
+			nested: map[string]interface{}{"page_sizes": map[string]interface{}{
+				"inventory": float64(1000), "Devices": 200,
+			}},
+			want: map[string]int{"inventory": 1000, "devices": 200},
+		},
+		{
+			name: "unknown endpoint and out-of-range size dropped",
+			nested: map[string]interface{}{"page_sizes": map[string]interface{}{
+				"sites": 50, "clients": 100, "inventory": 5000,
+			}},
+			want:      map[string]int{"sites": 50},
+			wantWarns: 2,
+		},
+		{
+			name:      "wrong type",
+			nested:    map[string]interface{}{"page_sizes": 100},
+			wantWarns: 1,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, warns := parsePageSizes("mist", tc.nested)
+			if len(warns) != tc.wantWarns {
+				t.Errorf("warnings = %v, want %d", warns, tc.wantWarns)
+			}
+			if len(got) != 0 || len(tc.want) != 0 {
+				if !reflect.DeepEqual(got, tc.want) {
+					t.Errorf("sizes = %v, want %v", got, tc.want)
+				}
+			}
+		})
+	}
+}
+
+func TestParsePagination(t *testing.T) {
+	for in, want := range map[string]string{"": "auto", "Page": "page", "cursor": "cursor"} {
+		got, warns := parsePagination("mist", map[string]interface{}{"pagination": in})
+		if got != want || len(warns) != 0 {
+			t.Errorf("parsePagination(%q) = %q, %v; want %q", in, got, warns, want)
+		}
+	}
+	if got, warns := parsePagination("mist", map[string]interface{}{"pagination": "offset"}); got != "auto" || len(warns) != 1 {
+		t.Errorf("invalid strategy = %q, %v; want auto with a warning", got, warns)
+	}
+}
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/ravinald/wifimgr/internal/helpers"
+)
+
+// renamedFile is one file of a planned multi-file rewrite: its contents
+// before and after the change.
+type renamedFile struct {
+	original []byte
+	updated  []byte
+	perm     os.FileMode
+	created  bool // the file does not exist yet; rollback removes it
+}
+
+// ErrRollbackIncomplete is wrapped by a failed multi-file write when some of
+// the files already written could not be restored, so the change is left
+// partly applied.
+var ErrRollbackIncomplete = errors.New("some files already written could not be restored")
+
+// writeRewrittenFiles writes each file in path order, restoring the files
+// already written if a later write fails. The error wraps
+// ErrRollbackIncomplete when a restore fails as well.
+func writeRewrittenFiles(files map[string]renamedFile) error {
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var written []string
+	for _, path := range paths {
+		f := files[path]
+		if err := helpers.WriteFileAtomic(path, f.updated, f.perm); err != nil {
+			restored := true
+			for _, done := range written {
+				orig := files[done]
+				if orig.created {
+					if rerr := os.Remove(done); rerr != nil {
+						err = fmt.Errorf("%w; removing %s also failed: %v", err, done, rerr)
+						restored = false
+					}
+					continue
+				}
+				if rerr := helpers.WriteFileAtomic(done, orig.original, orig.perm); rerr != nil {
+					err = fmt.Errorf("%w; restoring %s also failed: %v", err, done, rerr)
+					restored = false
+				}
+			}
+			if !restored {
+				return fmt.Errorf("%w: %w", ErrRollbackIncomplete, err)
+			}
+			return err
+		}
+		written = append(written, path)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteRewrittenFilesRestoresOnFailure(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "a.json")
+	if err := os.WriteFile(first, []byte("before"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	created := filepath.Join(dir, "b.json")
+	files := map[string]renamedFile{
+		first:   {original: []byte("before"), updated: []byte("after"), perm: 0600},
+		created: {updated: []byte("new"), perm: 0600, created: true},
+		// Sorts last and cannot be written: its directory does not exist.
+		filepath.Join(dir, "missing", "c.json"): {original: []byte("x"), updated: []byte("y"), perm: 0600},
+	}
+
+	err := writeRewrittenFiles(files)
+	if err == nil {
+		t.Fatal("expected the write into a missing directory to fail")
+	}
+	if errors.Is(err, ErrRollbackIncomplete) {
+		t.Errorf("every file was restored, but got %v", err)
+	}
+	if data, _ := os.ReadFile(first); string(data) != "before" {
+		t.Errorf("a.json = %q, want it restored to before", data)
+	}
+	if _, err := os.Stat(created); !os.IsNotExist(err) {
+		t.Errorf("b.json should be removed again, stat err = %v", err)
+	}
+}
@@ -0,0 +1,187 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/ravinald/wifimgr/internal/logging"
+)
+
+// SecurityBaselineField is the switch config field naming a security
+// baseline template.
+const SecurityBaselineField = "security_baseline"
+
+// A security baseline template is vendor-neutral:
+//
+//	"campus-baseline": {
+//	  "access_port_usages":  ["access", "ap"],
+//	  "trusted_port_usages": ["uplink"],
+//	  "bpdu_guard": true,
+//	  "storm_control": {"percentage": 80, "broadcast": true, "multicast": true, "unknown_unicast": true},
+//	  "dhcp_snooping": {"enabled": true, "networks": ["corp"], "arp_inspection": true, "ip_source_guard": false}
+//	}
+//
+// Access port usages get BPDU guard and storm control; trusted port usages
+// are where DHCP servers may answer. storm_control flags select the traffic
+// types that are rate limited. An empty dhcp_snooping.networks means all.
+type securityBaseline struct {
+	accessUsages   []string
+	trustedUsages  []string
+	bpduGuard      bool
+	stormSpecified bool
+	stormPercent   any
+	stormTraffic   map[string]bool
+	snoopingOn     bool
+	snoopNetworks  []string
+	arpInspection  bool
+	ipSourceGuard  bool
+}
+
+func parseSecurityBaseline(name string, tmpl map[string]any) (*securityBaseline, error) {
+	b := &securityBaseline{stormTraffic: map[string]bool{}}
+	if v, ok := tmpl["access_port_usages"].([]any); ok {
+		b.accessUsages = toStringSlice(v)
+	}
+	if v, ok := tmpl["trusted_port_usages"].([]any); ok {
+		b.trustedUsages = toStringSlice(v)
+	}
+	for _, u := range b.trustedUsages {
+		for _, a := range b.accessUsages {
+			if u == a {
+				return nil, fmt.Errorf("security baseline '%s': port usage '%s' cannot be both access and trusted", name, u)
+			}
+		}
+	}
+	b.bpduGuard, _ = tmpl["bpdu_guard"].(bool)
+
+	if storm, ok := tmpl["storm_control"].(map[string]any); ok {
+		b.stormSpecified = true
+		b.stormPercent = storm["percentage"]
+		if p, ok := storm["percentage"].(float64); ok && (p < 1 || p > 100) {
+			return nil, fmt.Errorf("security baseline '%s': storm_control.percentage must be 1-100", name)
+		}
+		for _, t := range []string{"broadcast", "multicast", "unknown_unicast"} {
+			b.stormTraffic[t], _ = storm[t].(bool)
+		}
+	}
+
+	if snoop, ok := tmpl["dhcp_snooping"].(map[string]any); ok {
+		b.snoopingOn, _ = snoop["enabled"].(bool)
+		if v, ok := snoop["networks"].([]any); ok {
+			b.snoopNetworks = toStringSlice(v)
+		}
+		b.arpInspection, _ = snoop["arp_inspection"].(bool)
+		b.ipSourceGuard, _ = snoop["ip_source_guard"].(bool)
+		if b.snoopingOn && len(b.trustedUsages) == 0 {
+			logging.Warnf("Security baseline '%s' enables DHCP snooping with no trusted_port_usages; DHCP from uplinks will be dropped", name)
+		}
+	}
+	return b, nil
+}
+
+// expandSecurityBaselineMist renders a baseline as Mist switch config:
+// dhcp_snooping at the device level and port_usages entries carrying
+// stp_edge (BPDU guard on edge ports), storm_control, and allow_dhcpd.
+func expandSecurityBaselineMist(b *securityBaseline) map[string]any {
+	out := make(map[string]any)
+	usages := make(map[string]any)
+
+	for _, u := range b.accessUsages {
+		usage := make(map[string]any)
+		if b.bpduGuard {
+			usage["stp_edge"] = true
+		}
+		if b.stormSpecified {
+			storm := map[string]any{
+				"no_broadcast":       !b.stormTraffic["broadcast"],
+				"no_multicast":       !b.stormTraffic["multicast"],
+				"no_unknown_unicast": !b.stormTraffic["unknown_unicast"],
+			}
+			if b.stormPercent != nil {
+				storm["percentage"] = b.stormPercent
+			}
+			usage["storm_control"] = storm
+		}
+		if b.snoopingOn {
+			usage["allow_dhcpd"] = false
+		}
+		if len(usage) > 0 {
+			usages[u] = usage
+		}
+	}
+	if b.snoopingOn {
+		for _, u := range b.trustedUsages {
+			usages[u] = map[string]any{"allow_dhcpd": true}
+		}
+		snoop := map[string]any{
+			"enabled":                true,
+			"all_networks":           len(b.snoopNetworks) == 0,
+			"enable_arp_spoof_check": b.arpInspection,
+			"enable_ip_source_guard": b.ipSourceGuard,
+		}
+		if len(b.snoopNetworks) > 0 {
+			networks := make([]any, len(b.snoopNetworks))
+			for i, n := range b.snoopNetworks {
+				networks[i] = n
+			}
+			snoop["networks"] = networks
+		}
+		out["dhcp_snooping"] = snoop
+	}
+	if len(usages) > 0 {
+		out["port_usages"] = usages
+	}
+	return out
+}
+
+// applySecurityBaselineMeraki sets the Meraki equivalents on the ports of an
+// expanded switch config, keyed by each port's usage: access ports get
+// "stp_guard": "bpdu guard" and storm_control_enabled, trusted ports
+// dai_trusted. DHCP snooping becomes dhcp_server_policy (rogue servers
+// blocked). Values the device already sets are kept.
+func applySecurityBaselineMeraki(b *securityBaseline, result map[string]any) {
+	isAccess := make(map[string]bool, len(b.accessUsages))
+	for _, u := range b.accessUsages {
+		isAccess[u] = true
+	}
+	isTrusted := make(map[string]bool, len(b.trustedUsages))
+	for _, u := range b.trustedUsages {
+		isTrusted[u] = true
+	}
+	setDefault := func(m map[string]any, k string, v any) {
+		if _, ok := m[k]; !ok {
+			m[k] = v
+		}
+	}
+
+	ports, _ := result["port_config"].(map[string]any)
+	for _, raw := range ports {
+		port, ok := raw.(map[string]any)
+		if !ok {
+			continue
+		}
+		usage, _ := port["usage"].(string)
+		switch {
+		case isAccess[usage]:
+			if b.bpduGuard {
+				setDefault(port, "stp_guard", "bpdu guard")
+			}
+			if b.stormSpecified {
+				setDefault(port, "storm_control_enabled", true)
+			}
+		case isTrusted[usage] && b.arpInspection:
+			setDefault(port, "dai_trusted", true)
+		}
+	}
+
+	if b.snoopingOn {
+		policy, _ := result["dhcp_server_policy"].(map[string]any)
+		if policy == nil {
+			policy = make(map[string]any)
+			result["dhcp_server_policy"] = policy
+		}
+		setDefault(policy, "default_policy", "block")
+		if b.arpInspection {
+			setDefault(policy, "arp_inspection", map[string]any{"enabled": true})
+		}
+	}
+}
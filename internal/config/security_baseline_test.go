@@ -0,0 +1,115 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func securityStore() *TemplateStore {
+	store := NewTemplateStore()
+	store.Security["campus-baseline"] = map[string]any{
+		"access_port_usages":  []any{"access"},
+		"trusted_port_usages": []any{"uplink"},
+		"bpdu_guard":          true,
+		"storm_control":       map[string]any{"percentage": float64(80), "broadcast": true},
+		"dhcp_snooping":       map[string]any{"enabled": true, "networks": []any{"corp"}, "arp_inspection": true},
+	}
+	return store
+}
+
+func TestExpandDeviceConfig_SecurityBaselineMist(t *testing.T) {
+	deviceConfig := map[string]any{
+		"name":              "idf-sw-01",
+		"security_baseline": "campus-baseline",
+		"port_usages": map[string]any{
+			"access": map[string]any{"stp_edge": false},
+		},
+	}
+
+	result, err := ExpandDeviceConfig(deviceConfig, nil, securityStore(), "mist-prod")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, ok := result["security_baseline"]; ok {
+		t.Error("security_baseline reference should be removed after expansion")
+	}
+
+	usages := result["port_usages"].(map[string]any)
+	access := usages["access"].(map[string]any)
+	if access["stp_edge"] != false {
+		t.Errorf("device stp_edge should override the baseline, got %v", access["stp_edge"])
+	}
+	if access["allow_dhcpd"] != false {
+		t.Errorf("access ports should not allow DHCP servers, got %v", access["allow_dhcpd"])
+	}
+	storm := access["storm_control"].(map[string]any)
+	if storm["no_broadcast"] != false || storm["no_multicast"] != true || storm["percentage"] != float64(80) {
+		t.Errorf("unexpected storm_control: %v", storm)
+	}
+	if usages["uplink"].(map[string]any)["allow_dhcpd"] != true {
+		t.Error("trusted usage should allow DHCP servers")
+	}
+
+	snoop := result["dhcp_snooping"].(map[string]any)
+	if snoop["all_networks"] != false || snoop["enable_arp_spoof_check"] != true {
+		t.Errorf("unexpected dhcp_snooping: %v", snoop)
+	}
+}
+
+func TestExpandDeviceConfig_SecurityBaselineMeraki(t *testing.T) {
+	store := securityStore()
+	store.Device["idf-switch"] = map[string]any{"security_baseline": "campus-baseline"}
+	deviceConfig := map[string]any{
+		"device_template": "idf-switch",
+		"port_config": map[string]any{
+			"1":  map[string]any{"usage": "access"},
+			"2":  map[string]any{"usage": "access", "stp_guard": "root guard"},
+			"48": map[string]any{"usage": "uplink"},
+		},
+	}
+
+	result, err := ExpandDeviceConfig(deviceConfig, nil, store, "meraki-prod")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, ok := result["port_usages"]; ok {
+		t.Error("Meraki expansion should not add port_usages")
+	}
+
+	ports := result["port_config"].(map[string]any)
+	if p := ports["1"].(map[string]any); p["stp_guard"] != "bpdu guard" || p["storm_control_enabled"] != true {
+		t.Errorf("access port 1 = %v", p)
+	}
+	if p := ports["2"].(map[string]any); p["stp_guard"] != "root guard" {
+		t.Errorf("explicit stp_guard should be kept, got %v", p["stp_guard"])
+	}
+	if p := ports["48"].(map[string]any); p["dai_trusted"] != true || p["stp_guard"] != nil {
+		t.Errorf("uplink port 48 = %v", p)
+	}
+	if policy := result["dhcp_server_policy"].(map[string]any); policy["default_policy"] != "block" {
+		t.Errorf("dhcp_server_policy = %v", policy)
+	}
+}
+
+func TestParseSecurityBaseline_Errors(t *testing.T) {
+	cases := map[string]struct {
+		tmpl map[string]any
+		want string
+	}{
+		"usage in both lists": {map[string]any{
+			"access_port_usages":  []any{"access"},
+			"trusted_port_usages": []any{"access"},
+		}, "both access and trusted"},
+		"storm percentage": {map[string]any{
+			"storm_control": map[string]any{"percentage": float64(0)},
+		}, "must be 1-100"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseSecurityBaseline("b", tc.tmpl)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("got %v, want error containing %q", err, tc.want)
+			}
+		})
+	}
+}
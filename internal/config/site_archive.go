@@ -0,0 +1,395 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SiteArchiveDir is the directory, under the config dir, holding the blocks of
+// sites removed from intent: one <site>.json per site.
+const SiteArchiveDir = "archives"
+
+// SiteArchive is the archive file of one site. Entries accumulate: a site
+// archived, restored, and archived again keeps both blocks, oldest first.
+type SiteArchive struct {
+	Site    string             `json:"site"`
+	Entries []SiteArchiveEntry `json:"entries"`
+}
+
+// SiteArchiveEntry is one archived site block and where it came from.
+type SiteArchiveEntry struct {
+	ArchivedAt time.Time      `json:"archived_at"`
+	RestoredAt *time.Time     `json:"restored_at,omitempty"`
+	File       string         `json:"file"`     // site config file, as listed in config
+	SiteKey    string         `json:"site_key"` // key under config.sites
+	Block      map[string]any `json:"block"`
+}
+
+// Latest returns the most recent entry not yet restored, or nil.
+func (a *SiteArchive) Latest() *SiteArchiveEntry {
+	for i := len(a.Entries) - 1; i >= 0; i-- {
+		if a.Entries[i].RestoredAt == nil {
+			return &a.Entries[i]
+		}
+	}
+	return nil
+}
+
+// SiteArchiveChange is a planned archive or restore of one site, rewritten in
+// memory and not yet written to disk.
+type SiteArchiveChange struct {
+	Site    string `json:"site"`     // site_config.name, else SiteKey
+	SiteKey string `json:"site_key"` // key under config.sites
+	File    string `json:"file"`     // site config file, as listed in config
+	Archive string `json:"archive"`  // full path of the archive file
+	Devices int    `json:"devices"`  // devices in the moved block
+
+	files map[string]renamedFile // full path -> contents
+}
+
+// SiteArchivePath returns the archive file of site under configDir. Path
+// separators in the name are replaced so the file stays in SiteArchiveDir.
+func SiteArchivePath(configDir, site string) string {
+	name := strings.NewReplacer("/", "_", "\\", "_").Replace(site)
+	name = strings.TrimLeft(name, ".")
+	return filepath.Join(configDir, SiteArchiveDir, name+".json")
+}
+
+// LoadSiteArchive reads the archive of site. A site never archived returns an
+// empty archive and no error.
+func LoadSiteArchive(configDir, site string) (*SiteArchive, error) {
+	path := SiteArchivePath(configDir, site)
+	data, err := os.ReadFile(path) // #nosec G304 -- path under the operator's config dir
+	if errors.Is(err, os.ErrNotExist) {
+		return &SiteArchive{Site: site}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	archive := &SiteArchive{}
+	if err := json.Unmarshal(data, archive); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return archive, nil
+}
+
+// ListSiteArchives returns every site archive under configDir, sorted by site.
+func ListSiteArchives(configDir string) ([]*SiteArchive, error) {
+	entries, err := os.ReadDir(filepath.Join(configDir, SiteArchiveDir))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var out []*SiteArchive
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		archive, err := LoadSiteArchive(configDir, strings.TrimSuffix(e.Name(), ".json"))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, archive)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Site < out[j].Site })
+	return out, nil
+}
+
+// findSiteArchive returns the archive of site, matching the site name
+// case-insensitively when no file has its exact name.
+func findSiteArchive(configDir, site string) (*SiteArchive, error) {
+	archive, err := LoadSiteArchive(configDir, site)
+	if err != nil || len(archive.Entries) > 0 {
+		return archive, err
+	}
+	all, err := ListSiteArchives(configDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range all {
+		if strings.EqualFold(a.Site, site) {
+			return a, nil
+		}
+	}
+	return archive, nil
+}
+
+// siteBlockRef locates one site block in a parsed site config file.
+type siteBlockRef struct {
+	file    string // as listed in config
+	full    string
+	data    []byte
+	raw     map[string]any
+	sites   map[string]any
+	siteKey string
+	name    string
+}
+
+// loadSiteFiles parses each distinct site config file once, calling fn for
+// every site block. fn returns false to stop.
+func loadSiteFiles(siteFiles []string, configDir string, fn func(ref siteBlockRef) bool) error {
+	seen := make(map[string]bool)
+	for _, file := range siteFiles {
+		full := resolveConfigPath(configDir, file)
+		if seen[full] {
+			continue
+		}
+		seen[full] = true
+		data, err := os.ReadFile(full) // #nosec G304 -- path from operator-controlled config
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", full, err)
+		}
+		var raw map[string]any
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", full, err)
+		}
+		cfg, _ := raw["config"].(map[string]any)
+		sites, _ := cfg["sites"].(map[string]any)
+		for _, siteKey := range sortedKeys(sites) {
+			site, _ := sites[siteKey].(map[string]any)
+			if !fn(siteBlockRef{file: file, full: full, data: data, raw: raw, sites: sites, siteKey: siteKey, name: siteBlockName(siteKey, site)}) {
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+// siteBlockName returns site_config.name, else the site key.
+func siteBlockName(siteKey string, site map[string]any) string {
+	if sc, ok := site["site_config"].(map[string]any); ok {
+		if n, ok := sc["name"].(string); ok && n != "" {
+			return n
+		}
+	}
+	return siteKey
+}
+
+// siteBlockDevices counts the devices in a site block.
+func siteBlockDevices(site map[string]any) int {
+	devices, _ := site["devices"].(map[string]any)
+	n := 0
+	for _, kind := range []string{"ap", "switch", "gateway"} {
+		if m, ok := devices[kind].(map[string]any); ok {
+			n += len(m)
+		}
+	}
+	return n
+}
+
+// marshalSiteFile renders a rewritten file the way the planners write them.
+func marshalSiteFile(full string, original []byte, v any) (renamedFile, error) {
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return renamedFile{}, fmt.Errorf("failed to marshal %s: %w", full, err)
+	}
+	perm := os.FileMode(0600)
+	if info, err := os.Stat(full); err == nil {
+		perm = info.Mode().Perm()
+	}
+	return renamedFile{original: original, updated: append(out, '\n'), perm: perm, created: original == nil}, nil
+}
+
+// planArchiveFile prepares writing archive to its file.
+func planArchiveFile(configDir string, archive *SiteArchive) (string, renamedFile, error) {
+	path := SiteArchivePath(configDir, archive.Site)
+	original, err := os.ReadFile(path) // #nosec G304 -- path under the operator's config dir
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", renamedFile{}, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	f, err := marshalSiteFile(path, original, archive)
+	return path, f, err
+}
+
+// PlanSiteArchive prepares moving the block of site (matched
+// case-insensitively against site_config.name or the site key) out of its
+// site config file and into the site's archive file, stamped with now. It is
+// an error for the site to be missing or to appear in more than one file.
+func PlanSiteArchive(siteFiles []string, configDir, site string, now time.Time) (*SiteArchiveChange, error) {
+	var matches []siteBlockRef
+	err := loadSiteFiles(siteFiles, configDir, func(ref siteBlockRef) bool {
+		if strings.EqualFold(ref.name, site) || strings.EqualFold(ref.siteKey, site) {
+			matches = append(matches, ref)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("site '%s' not found in site configs", site)
+	case 1:
+	default:
+		var where []string
+		for _, m := range matches {
+			where = append(where, m.file+":"+m.siteKey)
+		}
+		return nil, fmt.Errorf("site '%s' is defined more than once (%s); remove the duplicate first", site, strings.Join(where, ", "))
+	}
+	ref := matches[0]
+	block, _ := ref.sites[ref.siteKey].(map[string]any)
+
+	archive, err := LoadSiteArchive(configDir, ref.name)
+	if err != nil {
+		return nil, err
+	}
+	archive.Site = ref.name
+	archive.Entries = append(archive.Entries, SiteArchiveEntry{
+		ArchivedAt: now.UTC(),
+		File:       ref.file,
+		SiteKey:    ref.siteKey,
+		Block:      block,
+	})
+	delete(ref.sites, ref.siteKey)
+
+	change := &SiteArchiveChange{
+		Site:    ref.name,
+		SiteKey: ref.siteKey,
+		File:    ref.file,
+		Devices: siteBlockDevices(block),
+		files:   make(map[string]renamedFile),
+	}
+	if change.files[ref.full], err = marshalSiteFile(ref.full, ref.data, ref.raw); err != nil {
+		return nil, err
+	}
+	path, f, err := planArchiveFile(configDir, archive)
+	if err != nil {
+		return nil, err
+	}
+	change.Archive = path
+	change.files[path] = f
+	return change, nil
+}
+
+// PlanSiteRestore prepares putting the latest unrestored archived block of
+// site back into a site config file: file when given, else the file it was
+// archived from. The archive entry is kept and stamped restored with now. It
+// is an error for the site to be defined in intent already, or for the target
+// file not to be one of siteFiles.
+func PlanSiteRestore(siteFiles []string, configDir, site, file string, now time.Time) (*SiteArchiveChange, error) {
+	archive, err := findSiteArchive(configDir, site)
+	if err != nil {
+		return nil, err
+	}
+	entry := archive.Latest()
+	if entry == nil {
+		return nil, fmt.Errorf("no archived block for site '%s' in %s", site, filepath.Join(configDir, SiteArchiveDir))
+	}
+
+	if file == "" {
+		file = entry.File
+	}
+	var target *siteBlockRef
+	var conflict string
+	err = loadSiteFiles(siteFiles, configDir, func(ref siteBlockRef) bool {
+		if strings.EqualFold(ref.name, archive.Site) || strings.EqualFold(ref.siteKey, archive.Site) {
+			conflict = ref.file + ":" + ref.siteKey
+			return false
+		}
+		if target == nil && ref.full == resolveConfigPath(configDir, file) {
+			r := ref
+			target = &r
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if conflict != "" {
+		return nil, fmt.Errorf("site '%s' is already defined in %s", archive.Site, conflict)
+	}
+	if target == nil {
+		target, err = emptySiteFile(siteFiles, configDir, file)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if _, exists := target.sites[entry.SiteKey]; exists {
+		return nil, fmt.Errorf("site key '%s' is already used in %s", entry.SiteKey, target.file)
+	}
+
+	target.sites[entry.SiteKey] = entry.Block
+	restored := now.UTC()
+	entry.RestoredAt = &restored
+
+	change := &SiteArchiveChange{
+		Site:    archive.Site,
+		SiteKey: entry.SiteKey,
+		File:    target.file,
+		Devices: siteBlockDevices(entry.Block),
+		files:   make(map[string]renamedFile),
+	}
+	if change.files[target.full], err = marshalSiteFile(target.full, target.data, target.raw); err != nil {
+		return nil, err
+	}
+	path, f, err := planArchiveFile(configDir, archive)
+	if err != nil {
+		return nil, err
+	}
+	change.Archive = path
+	change.files[path] = f
+	return change, nil
+}
+
+// emptySiteFile loads a listed site config file that holds no site blocks,
+// creating its config.sites map. loadSiteFiles only visits files with sites.
+func emptySiteFile(siteFiles []string, configDir, file string) (*siteBlockRef, error) {
+	full := resolveConfigPath(configDir, file)
+	listed := false
+	for _, f := range siteFiles {
+		if resolveConfigPath(configDir, f) == full {
+			listed, file = true, f
+			break
+		}
+	}
+	if !listed {
+		return nil, fmt.Errorf("%s is not listed in files.site_configs; choose a target with 'file <name>'", file)
+	}
+	data, err := os.ReadFile(full) // #nosec G304 -- path from operator-controlled config
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", full, err)
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", full, err)
+	}
+	cfg, _ := raw["config"].(map[string]any)
+	if cfg == nil {
+		cfg = make(map[string]any)
+		raw["config"] = cfg
+	}
+	sites, _ := cfg["sites"].(map[string]any)
+	if sites == nil {
+		sites = make(map[string]any)
+		cfg["sites"] = sites
+	}
+	return &siteBlockRef{file: file, full: full, data: data, raw: raw, sites: sites}, nil
+}
+
+// Files returns the full paths the change rewrites, sorted.
+func (c *SiteArchiveChange) Files() []string {
+	out := make([]string, 0, len(c.files))
+	for path := range c.files {
+		out = append(out, path)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Apply writes the site config file and the archive file, restoring the first
+// if the second fails, so a site is never lost from both or kept in both.
+func (c *SiteArchiveChange) Apply() error {
+	if err := os.MkdirAll(filepath.Dir(c.Archive), 0700); err != nil {
+		return fmt.Errorf("failed to create archive directory: %w", err)
+	}
+	return writeRewrittenFiles(c.files)
+}
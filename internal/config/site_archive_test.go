@@ -0,0 +1,119 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const archiveSiteFixture = `{
+  "version": 1,
+  "config": {
+    "sites": {
+      "lab": {
+        "site_config": {"name": "US-LAB-01"},
+        "devices": {"ap": {"aa0000000001": {"name": "ap-1"}, "aa0000000002": {"name": "ap-2"}}}
+      },
+      "hq": {"site_config": {"name": "US-HQ-01"}}
+    }
+  }
+}`
+
+func readSiteKeys(t *testing.T, path string) []string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatal(err)
+	}
+	return sortedKeys(raw["config"].(map[string]any)["sites"].(map[string]any))
+}
+
+func TestSiteArchiveAndRestore(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sites.json")
+	if err := os.WriteFile(path, []byte(archiveSiteFixture), 0600); err != nil {
+		t.Fatal(err)
+	}
+	files := []string{"sites.json"}
+	archivedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	change, err := PlanSiteArchive(files, dir, "us-lab-01", archivedAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if change.Site != "US-LAB-01" || change.SiteKey != "lab" || change.Devices != 2 {
+		t.Errorf("change = %+v", change)
+	}
+	if err := change.Apply(); err != nil {
+		t.Fatal(err)
+	}
+	if got := readSiteKeys(t, path); strings.Join(got, ",") != "hq" {
+		t.Errorf("sites after archive = %v", got)
+	}
+
+	archive, err := LoadSiteArchive(dir, "US-LAB-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	latest := archive.Latest()
+	if latest == nil || !latest.ArchivedAt.Equal(archivedAt) || latest.File != "sites.json" || latest.Block["site_config"] == nil {
+		t.Fatalf("archive = %+v", archive)
+	}
+
+	if _, err := PlanSiteArchive(files, dir, "US-LAB-01", archivedAt); err == nil {
+		t.Error("archiving a site no longer in intent should fail")
+	}
+
+	restore, err := PlanSiteRestore(files, dir, "us-lab-01", "", archivedAt.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := restore.Apply(); err != nil {
+		t.Fatal(err)
+	}
+	if got := readSiteKeys(t, path); strings.Join(got, ",") != "hq,lab" {
+		t.Errorf("sites after restore = %v", got)
+	}
+	archive, _ = LoadSiteArchive(dir, "US-LAB-01")
+	if len(archive.Entries) != 1 || archive.Entries[0].RestoredAt == nil || archive.Latest() != nil {
+		t.Errorf("archive after restore = %+v, want the entry kept and marked restored", archive)
+	}
+
+	if _, err := PlanSiteRestore(files, dir, "US-LAB-01", "", time.Now()); err == nil {
+		t.Error("restoring with nothing left to restore should fail")
+	}
+}
+
+func TestSiteRestoreConflicts(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "sites.json"), []byte(archiveSiteFixture), 0600); err != nil {
+		t.Fatal(err)
+	}
+	files := []string{"sites.json"}
+	change, err := PlanSiteArchive(files, dir, "US-HQ-01", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := change.Apply(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := PlanSiteRestore(files, dir, "US-HQ-01", "other.json", time.Now()); err == nil || !strings.Contains(err.Error(), "not listed") {
+		t.Errorf("err = %v, want an unlisted target refused", err)
+	}
+
+	// The site was re-created in intent after it was archived.
+	if err := os.WriteFile(filepath.Join(dir, "sites.json"), []byte(archiveSiteFixture), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := PlanSiteRestore(files, dir, "US-HQ-01", "", time.Now()); err == nil || !strings.Contains(err.Error(), "already defined") {
+		t.Errorf("err = %v, want a conflict with the site in intent", err)
+	}
+}
@@ -0,0 +1,140 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/ravinald/wifimgr/internal/macaddr"
+)
+
+// DisabledDevicesKey is the devices-level key that holds entries set aside by
+// DisableSiteDevices. JSON has no comment syntax, so "commenting out" a device
+// moves its entry under devices._disabled.<type>; the site loader ignores
+// unknown keys, so disabled entries never reach apply, and moving one back
+// re-enables it unchanged.
+const DisabledDevicesKey = "_disabled"
+
+// DisableSiteDevices moves the given MACs of one device type out of a site's
+// devices map into devices._disabled.<type>. MACs are matched normalized, so
+// any spelling in the file works. Returns how many entries moved; the file is
+// only rewritten when that is non-zero.
+func DisableSiteDevices(fullPath, siteKey, deviceType string, macs []string) (int, error) {
+	raw, devices, err := loadRawSiteDevices(fullPath, siteKey)
+	if err != nil {
+		return 0, err
+	}
+	section, _ := devices[deviceType].(map[string]interface{})
+	if len(section) == 0 {
+		return 0, nil
+	}
+
+	want := make(map[string]bool, len(macs))
+	for _, mac := range macs {
+		if n := macaddr.NormalizeOrEmpty(mac); n != "" {
+			want[n] = true
+		}
+	}
+
+	disabled, _ := devices[DisabledDevicesKey].(map[string]interface{})
+	if disabled == nil {
+		disabled = make(map[string]interface{})
+	}
+	target, _ := disabled[deviceType].(map[string]interface{})
+	if target == nil {
+		target = make(map[string]interface{})
+	}
+
+	moved := 0
+	for key, entry := range section {
+		if !want[macaddr.NormalizeOrEmpty(key)] {
+			continue
+		}
+		target[key] = entry
+		delete(section, key)
+		moved++
+	}
+	if moved == 0 {
+		return 0, nil
+	}
+	disabled[deviceType] = target
+	devices[DisabledDevicesKey] = disabled
+	return moved, writeRawSiteConfig(fullPath, raw)
+}
+
+// AddSiteDevices adds stub entries (MAC -> name) for one device type to a
+// site's devices map. MACs already present in any spelling are left alone, so
+// an existing entry is never overwritten. Returns how many entries were added.
+func AddSiteDevices(fullPath, siteKey, deviceType string, names map[string]string) (int, error) {
+	raw, devices, err := loadRawSiteDevices(fullPath, siteKey)
+	if err != nil {
+		return 0, err
+	}
+	section, _ := devices[deviceType].(map[string]interface{})
+	if section == nil {
+		section = make(map[string]interface{})
+	}
+	present := make(map[string]bool, len(section))
+	for key := range section {
+		present[macaddr.NormalizeOrEmpty(key)] = true
+	}
+
+	added := 0
+	for mac, name := range names {
+		n := macaddr.NormalizeOrEmpty(mac)
+		if n == "" || present[n] {
+			continue
+		}
+		entry := map[string]interface{}{}
+		if name != "" {
+			entry["name"] = name
+		}
+		section[n] = entry
+		present[n] = true
+		added++
+	}
+	if added == 0 {
+		return 0, nil
+	}
+	devices[deviceType] = section
+	return added, writeRawSiteConfig(fullPath, raw)
+}
+
+// loadRawSiteDevices reads a site config file as a generic map, so a rewrite
+// keeps fields the typed SiteConfigObj does not model, and returns the named
+// site's devices object (created if absent).
+func loadRawSiteDevices(fullPath, siteKey string) (map[string]interface{}, map[string]interface{}, error) {
+	data, err := os.ReadFile(fullPath) // #nosec G304 -- path from operator-controlled config
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read site config %s: %w", fullPath, err)
+	}
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse site config %s: %w", fullPath, err)
+	}
+	cfg, _ := raw["config"].(map[string]interface{})
+	sites, _ := cfg["sites"].(map[string]interface{})
+	site, ok := sites[siteKey].(map[string]interface{})
+	if !ok {
+		return nil, nil, fmt.Errorf("site '%s' not found in %s", siteKey, fullPath)
+	}
+	devices, _ := site["devices"].(map[string]interface{})
+	if devices == nil {
+		devices = make(map[string]interface{})
+		site["devices"] = devices
+	}
+	return raw, devices, nil
+}
+
+// writeRawSiteConfig writes a site config back with 2-space indent and the
+// same 0600 permissions as the other config writers.
+func writeRawSiteConfig(fullPath string, raw map[string]interface{}) error {
+	data, err := json.MarshalIndent(raw, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal site config: %w", err)
+	}
+	if err := os.WriteFile(fullPath, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write site config %s: %w", fullPath, err)
+	}
+	return nil
+}
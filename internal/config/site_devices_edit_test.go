@@ -0,0 +1,96 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeSiteFixture(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "site.json")
+	body := `{
+  "version": 1,
+  "config": {
+    "sites": {
+      "US-LAB-01": {
+        "site_config": {"name": "US-LAB-01"},
+        "devices": {
+          "ap": {
+            "aa:00:00:00:00:01": {"name": "ap-gone", "serial": "S1"},
+            "aa0000000002": {"name": "ap-keep"}
+          }
+        }
+      }
+    }
+  }
+}`
+	if err := os.WriteFile(path, []byte(body), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDisableSiteDevices(t *testing.T) {
+	path := writeSiteFixture(t)
+
+	n, err := DisableSiteDevices(path, "US-LAB-01", "ap", []string{"AA-00-00-00-00-01"})
+	if err != nil || n != 1 {
+		t.Fatalf("DisableSiteDevices = %d, %v", n, err)
+	}
+
+	cfg, err := LoadSiteConfig(path, "")
+	if err != nil {
+		t.Fatalf("LoadSiteConfig after disable: %v", err)
+	}
+	aps := cfg.Config.Sites["US-LAB-01"].Devices.APs
+	if len(aps) != 1 {
+		t.Errorf("loader should only see the remaining AP, got %v", aps)
+	}
+
+	_, devices, err := loadRawSiteDevices(path, "US-LAB-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	disabled := devices[DisabledDevicesKey].(map[string]interface{})["ap"].(map[string]interface{})
+	entry, ok := disabled["aa:00:00:00:00:01"].(map[string]interface{})
+	if !ok || entry["serial"] != "S1" {
+		t.Errorf("disabled entry should keep its original key and fields, got %v", disabled)
+	}
+
+	if n, err := DisableSiteDevices(path, "US-LAB-01", "ap", []string{"aa0000000001"}); err != nil || n != 0 {
+		t.Errorf("second disable = %d, %v; want 0, nil", n, err)
+	}
+}
+
+func TestAddSiteDevices(t *testing.T) {
+	path := writeSiteFixture(t)
+
+	n, err := AddSiteDevices(path, "US-LAB-01", "ap", map[string]string{
+		"aa:00:00:00:00:02": "dup",
+		"aa:00:00:00:00:03": "ap-new",
+	})
+	if err != nil || n != 1 {
+		t.Fatalf("AddSiteDevices = %d, %v", n, err)
+	}
+	n, err = AddSiteDevices(path, "US-LAB-01", "switch", map[string]string{"bb0000000001": "sw-new"})
+	if err != nil || n != 1 {
+		t.Fatalf("AddSiteDevices switch = %d, %v", n, err)
+	}
+
+	cfg, err := LoadSiteConfig(path, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	devices := cfg.Config.Sites["US-LAB-01"].Devices
+	if devices.APs["aa0000000003"].Name != "ap-new" || devices.APs["aa0000000002"].Name != "ap-keep" {
+		t.Errorf("APs = %v", devices.APs)
+	}
+	if devices.Switches["bb0000000001"].Name != "sw-new" {
+		t.Errorf("Switches = %v", devices.Switches)
+	}
+
+	if _, err := AddSiteDevices(path, "NOPE", "ap", map[string]string{"cc0000000001": ""}); err == nil {
+		t.Error("expected error for unknown site key")
+	}
+}
@@ -15,6 +15,19 @@ func ExpandDeviceConfig(
 	siteWLANs []string,
 	templates *TemplateStore,
 	apiLabel string,
+) (map[string]any, error) {
+	return ExpandDeviceConfigForModel(deviceConfig, "", siteWLANs, templates, apiLabel)
+}
+
+// ExpandDeviceConfigForModel is ExpandDeviceConfig for a device of a known
+// hardware model: the model template's defaults, if any, are laid down first,
+// so every template and the device itself override them.
+func ExpandDeviceConfigForModel(
+	deviceConfig map[string]any,
+	model string,
+	siteWLANs []string,
+	templates *TemplateStore,
+	apiLabel string,
 ) (map[string]any, error) {
 	if templates == nil || templates.IsEmpty() {
 		// No templates loaded, return copy of device config
@@ -26,6 +39,12 @@ func ExpandDeviceConfig(
 	// Determine vendor from API label (e.g., "mist-prod" → "mist")
 	vendor := GetVendorFromAPILabel(apiLabel)
 
+	// Step 0: Start from the model's defaults
+	if template, name, found := templates.GetModelTemplate(model); found {
+		result = ExpandForVendor(template, vendor)
+		logging.Debugf("Expanded model template '%s' for vendor '%s'", name, vendor)
+	}
+
 	// Step 1: Expand device_template if present
 	if templateName, ok := deviceConfig["device_template"].(string); ok {
 		if template, found := templates.GetDeviceTemplate(templateName); found {
@@ -36,6 +55,33 @@ func ExpandDeviceConfig(
 			logging.Warnf("Device template '%s' not found", templateName)
 		}
 	}
+	// Firmware pins are intent for 'firmware apply', not device config
+	delete(result, FirmwareField)
+
+	// Step 1.5: Expand security_baseline (switches), set on the device or by
+	// its device template. Device-specific values still override it in step 4.
+	var baseline *securityBaseline
+	baselineName, _ := deviceConfig[SecurityBaselineField].(string)
+	if baselineName == "" {
+		baselineName, _ = result[SecurityBaselineField].(string)
+	}
+	delete(result, SecurityBaselineField)
+	if baselineName != "" {
+		template, found := templates.GetSecurityTemplate(baselineName)
+		if !found {
+			logging.Warnf("Security baseline '%s' not found", baselineName)
+		} else {
+			parsed, err := parseSecurityBaseline(baselineName, ExpandForVendor(template, vendor))
+			if err != nil {
+				return nil, err
+			}
+			baseline = parsed
+			if vendor != "meraki" {
+				result = mergeConfigs(result, expandSecurityBaselineMist(baseline))
+			}
+			logging.Debugf("Expanded security_baseline '%s' for vendor '%s'", baselineName, vendor)
+		}
+	}
 
 	// Step 2: Expand radio_profile if present
 	// Radio templates contain band_* fields directly (not wrapped in radio_config)
@@ -95,11 +141,21 @@ func ExpandDeviceConfig(
 		result[k] = v
 	}
 
+	// Meraki baseline settings are per port, so they follow the final port_config
+	if baseline != nil && vendor == "meraki" {
+		applySecurityBaselineMeraki(baseline, result)
+	}
+
 	// Step 5: Ensure radio bands with settings have disabled: false
 	if radioConfig, ok := result["radio_config"].(map[string]any); ok {
 		result["radio_config"] = ensureRadioEnabled(radioConfig)
 	}
 
+	// A template that referenced an attribute the site lacks must not expand
+	if err := templates.Err(); err != nil {
+		return nil, err
+	}
+
 	return result, nil
 }
 
@@ -126,9 +182,10 @@ func expandWLANs(labels []string, templates *TemplateStore, vendor string) ([]ma
 func ExpandForVendor(template map[string]any, vendor string) map[string]any {
 	result := make(map[string]any)
 
-	// Copy non-vendor fields (common fields)
+	// Copy non-vendor fields (common fields). Conditional blocks are left
+	// out: a ForSite view has already merged the ones that match.
 	for k, v := range template {
-		if !isVendorBlock(k) {
+		if !isVendorBlock(k) && !strings.HasPrefix(k, conditionPrefix) {
 			result[k] = deepCopy(v)
 		}
 	}
@@ -152,7 +209,7 @@ func isVendorBlock(key string) bool {
 // These fields contain template names/labels, not actual configuration
 func isTemplateReferenceField(key string) bool {
 	switch key {
-	case "radio_profile", "device_template", "wlan":
+	case "radio_profile", "device_template", "wlan", SecurityBaselineField:
 		// wlan is special: it's a list of WLAN template labels that get expanded
 		// The expanded WLANs are already in result["wlan"] from step 3
 		return true
@@ -519,6 +519,7 @@ func TestIsTemplateReferenceField(t *testing.T) {
 		{"radio_profile", true},
 		{"device_template", true},
 		{"wlan", true}, // wlan contains template labels, gets expanded
+		{"security_baseline", true},
 		{"name", false},
 		{"radio_config", false},
 	}
@@ -532,3 +533,44 @@ func TestIsTemplateReferenceField(t *testing.T) {
 		})
 	}
 }
+
+func TestExpandDeviceConfigForModel(t *testing.T) {
+	store := NewTemplateStore()
+	store.Model["AP45"] = map[string]any{
+		"radio_config": map[string]any{
+			"band_6": map[string]any{"disabled": false},
+			"band_5": map[string]any{"power": 12},
+		},
+		"mesh": map[string]any{"enabled": false},
+	}
+	store.Device["lobby"] = map[string]any{"mesh": map[string]any{"enabled": true}}
+	store.Radio["high-density"] = map[string]any{"band_5": map[string]any{"power": 15}}
+
+	deviceConfig := map[string]any{
+		"device_template": "lobby",
+		"radio_profile":   "high-density",
+	}
+	result, err := ExpandDeviceConfigForModel(deviceConfig, "ap45", nil, store, "mist-prod")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	radio := result["radio_config"].(map[string]any)
+	if band6 := radio["band_6"].(map[string]any); band6["disabled"] != false {
+		t.Errorf("band_6 = %v, want the model's 6 GHz enabled", band6)
+	}
+	if band5 := radio["band_5"].(map[string]any); band5["power"] != 15 {
+		t.Errorf("band_5.power = %v, want the radio profile to override the model", band5["power"])
+	}
+	if mesh := result["mesh"].(map[string]any); mesh["enabled"] != true {
+		t.Errorf("mesh.enabled = %v, want the device template to override the model", mesh["enabled"])
+	}
+
+	other, err := ExpandDeviceConfigForModel(map[string]any{"name": "ap"}, "AP12", nil, store, "mist-prod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := other["radio_config"]; ok {
+		t.Errorf("a model with no template got defaults: %v", other)
+	}
+}
@@ -1,19 +1,38 @@
 package config
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 
 	"github.com/ravinald/wifimgr/internal/logging"
 )
 
-// TemplateStore holds all loaded templates organized by type
+// TemplateStore holds all loaded templates organized by type. Templates
+// read from files stay unparsed until first looked up; the exported maps hold
+// only decoded (or directly assigned) templates until Resolve is called.
 type TemplateStore struct {
-	Radio  map[string]map[string]any // name -> config
-	WLAN   map[string]map[string]any // name -> config
-	Device map[string]map[string]any // name -> config
+	Radio           map[string]map[string]any // name -> config
+	WLAN            map[string]map[string]any // name -> config
+	Device          map[string]map[string]any // name -> config
+	AppPolicy       map[string]map[string]any // name -> gateway service policy
+	TrafficSteering map[string]map[string]any // name -> gateway path preference
+	Security        map[string]map[string]any // name -> switch security baseline
+	Model           map[string]map[string]any // hardware model -> default device settings
+
+	mu      sync.Mutex
+	pending map[string]map[string]json.RawMessage // kind -> name -> body not yet decoded
+	sources map[string]map[string]string          // kind -> name -> file that defines it
+
+	// Set on a ForSite view: templates come from base, rendered for site.
+	base       *TemplateStore
+	site       *SiteAttributes
+	renderErrs map[string]error // kind/name -> render failure
 }
 
 // TemplateFile represents the structure of a template file
@@ -24,17 +43,25 @@ type TemplateFile struct {
 
 // TemplateDefinitions groups templates by type
 type TemplateDefinitions struct {
-	Radio  map[string]map[string]any `json:"radio,omitempty"`
-	WLAN   map[string]map[string]any `json:"wlan,omitempty"`
-	Device map[string]map[string]any `json:"device,omitempty"`
+	Radio           map[string]map[string]any `json:"radio,omitempty"`
+	WLAN            map[string]map[string]any `json:"wlan,omitempty"`
+	Device          map[string]map[string]any `json:"device,omitempty"`
+	AppPolicy       map[string]map[string]any `json:"app_policy,omitempty"`
+	TrafficSteering map[string]map[string]any `json:"traffic_steering,omitempty"`
+	Security        map[string]map[string]any `json:"security,omitempty"`
+	Model           map[string]map[string]any `json:"model,omitempty"`
 }
 
 // NewTemplateStore creates an empty template store
 func NewTemplateStore() *TemplateStore {
 	return &TemplateStore{
-		Radio:  make(map[string]map[string]any),
-		WLAN:   make(map[string]map[string]any),
-		Device: make(map[string]map[string]any),
+		Radio:           make(map[string]map[string]any),
+		WLAN:            make(map[string]map[string]any),
+		Device:          make(map[string]map[string]any),
+		AppPolicy:       make(map[string]map[string]any),
+		TrafficSteering: make(map[string]map[string]any),
+		Security:        make(map[string]map[string]any),
+		Model:           make(map[string]map[string]any),
 	}
 }
 
@@ -60,101 +87,293 @@ func LoadTemplates(paths []string, configDir string) (*TemplateStore, error) {
 		}
 	}
 
-	logging.Debugf("Loaded templates: %d radio, %d wlan, %d device",
-		len(store.Radio), len(store.WLAN), len(store.Device))
+	list := store.ListTemplates()
+	logging.Debugf("Loaded templates: %d radio, %d wlan, %d device, %d app_policy, %d traffic_steering, %d security, %d model",
+		len(list[TemplateKindRadio]), len(list[TemplateKindWLAN]), len(list[TemplateKindDevice]),
+		len(list[TemplateKindAppPolicy]), len(list[TemplateKindTrafficSteering]), len(list[TemplateKindSecurity]),
+		len(list[TemplateKindModel]))
 
 	return store, nil
 }
 
-// loadFromFile loads templates from a single file
+// templateKindNames maps each template kind to the name used in messages.
+var templateKindNames = map[string]string{
+	TemplateKindRadio:           "Radio",
+	TemplateKindWLAN:            "WLAN",
+	TemplateKindDevice:          "Device",
+	TemplateKindAppPolicy:       "App policy",
+	TemplateKindTrafficSteering: "Traffic steering",
+	TemplateKindSecurity:        "Security baseline",
+	TemplateKindModel:           "Model",
+}
+
+// loadFromFile loads templates from a single file. Bodies are kept raw and
+// decoded per label on first lookup.
 func (s *TemplateStore) loadFromFile(filePath string) error {
 	data, err := os.ReadFile(filePath) // #nosec G304 -- paths from operator-controlled config file
 	if err != nil {
 		return fmt.Errorf("failed to read file: %w", err)
 	}
 
-	var templateFile TemplateFile
-	if err := json.Unmarshal(data, &templateFile); err != nil {
-		return fmt.Errorf("failed to parse JSON: %w", err)
+	parsed, err := parseTemplateFile(data)
+	if err != nil {
+		return err
 	}
 
 	// Validate version
-	if templateFile.Version != 1 {
-		logging.Warnf("Template file %s has version %d, expected 1", filePath, templateFile.Version)
+	if parsed.Version != 1 {
+		logging.Warnf("Template file %s has version %d, expected 1", filePath, parsed.Version)
 	}
 
 	// Merge templates into store
-	for name, config := range templateFile.Templates.Radio {
-		if _, exists := s.Radio[name]; exists {
-			logging.Warnf("Radio template '%s' defined multiple times, later definition wins", name)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.pending == nil {
+		s.pending = make(map[string]map[string]json.RawMessage)
+	}
+	for kind, display := range templateKindNames {
+		for name, body := range parsed.Templates[kind] {
+			decoded := s.kindMap(kind)
+			_, exists := decoded[name]
+			if _, raw := s.pending[kind][name]; exists || raw {
+				logging.Warnf("%s template '%s' defined multiple times, later definition wins", display, name)
+			}
+			delete(decoded, name)
+			if s.pending[kind] == nil {
+				s.pending[kind] = make(map[string]json.RawMessage)
+			}
+			s.pending[kind][name] = body
+			s.setSourceLocked(kind, name, filePath)
+			logging.Debugf("Loaded %s template: %s", strings.ToLower(display), name)
 		}
-		s.Radio[name] = config
-		logging.Debugf("Loaded radio template: %s", name)
 	}
 
-	for name, config := range templateFile.Templates.WLAN {
-		if _, exists := s.WLAN[name]; exists {
-			logging.Warnf("WLAN template '%s' defined multiple times, later definition wins", name)
-		}
-		s.WLAN[name] = config
-		logging.Debugf("Loaded WLAN template: %s", name)
+	return nil
+}
+
+// parsedTemplateFile is a template file split into raw per-label bodies.
+type parsedTemplateFile struct {
+	Version   int                                   `json:"version"`
+	Templates map[string]map[string]json.RawMessage `json:"templates"`
+}
+
+// parsedTemplates caches parsed template files by content hash, so a file
+// loaded again in the same run (apply loads templates once per device type
+// and API) is neither re-parsed nor re-validated.
+var parsedTemplates = struct {
+	sync.Mutex
+	files map[[sha256.Size]byte]*parsedTemplateFile
+}{files: make(map[[sha256.Size]byte]*parsedTemplateFile)}
+
+// parseTemplateFile splits a template file into raw bodies, reusing an
+// earlier parse of identical content. Callers must not modify the result.
+func parseTemplateFile(data []byte) (*parsedTemplateFile, error) {
+	sum := sha256.Sum256(data)
+	parsedTemplates.Lock()
+	defer parsedTemplates.Unlock()
+	if parsed, ok := parsedTemplates.files[sum]; ok {
+		return parsed, nil
 	}
 
-	for name, config := range templateFile.Templates.Device {
-		if _, exists := s.Device[name]; exists {
-			logging.Warnf("Device template '%s' defined multiple times, later definition wins", name)
+	var parsed parsedTemplateFile
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+	// Bodies are decoded lazily, so reject anything that would not decode
+	// into a template now rather than at first use.
+	for kind := range templateKindNames {
+		for name, body := range parsed.Templates[kind] {
+			trimmed := bytes.TrimSpace(body)
+			if len(trimmed) == 0 || (trimmed[0] != '{' && !bytes.Equal(trimmed, []byte("null"))) {
+				return nil, fmt.Errorf("failed to parse JSON: %s template '%s' is not an object", kind, name)
+			}
 		}
-		s.Device[name] = config
-		logging.Debugf("Loaded device template: %s", name)
 	}
+	parsedTemplates.files[sum] = &parsed
+	return &parsed, nil
+}
 
+// kindMap returns the exported map holding decoded templates of kind.
+func (s *TemplateStore) kindMap(kind string) map[string]map[string]any {
+	switch kind {
+	case TemplateKindRadio:
+		return s.Radio
+	case TemplateKindWLAN:
+		return s.WLAN
+	case TemplateKindDevice:
+		return s.Device
+	case TemplateKindAppPolicy:
+		return s.AppPolicy
+	case TemplateKindTrafficSteering:
+		return s.TrafficSteering
+	case TemplateKindSecurity:
+		return s.Security
+	case TemplateKindModel:
+		return s.Model
+	}
 	return nil
 }
 
+// lookup returns the named template of kind, decoding it on first use. A
+// template assigned directly to the exported maps (import files merge that
+// way, after template files) wins over a raw one of the same name.
+func (s *TemplateStore) lookup(kind, name string) (map[string]any, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.base != nil {
+		return s.lookupForSiteLocked(kind, name)
+	}
+	decoded := s.kindMap(kind)
+	if t, ok := decoded[name]; ok {
+		return t, true
+	}
+	body, ok := s.pending[kind][name]
+	if !ok {
+		return nil, false
+	}
+	delete(s.pending[kind], name)
+	var t map[string]any
+	if err := json.Unmarshal(body, &t); err != nil {
+		logging.Warnf("%s template '%s' could not be decoded: %v", templateKindNames[kind], name, err)
+		return nil, false
+	}
+	decoded[name] = t
+	return t, true
+}
+
+// Resolve decodes every template not yet looked up into the exported maps,
+// for callers that range over them directly.
+func (s *TemplateStore) Resolve() {
+	if s.base != nil {
+		for kind, names := range s.base.ListTemplates() {
+			for _, name := range names {
+				s.lookup(kind, name)
+			}
+		}
+		return
+	}
+	s.mu.Lock()
+	kinds := make(map[string][]string, len(s.pending))
+	for kind, bodies := range s.pending {
+		for name := range bodies {
+			kinds[kind] = append(kinds[kind], name)
+		}
+	}
+	s.mu.Unlock()
+	for kind, names := range kinds {
+		for _, name := range names {
+			s.lookup(kind, name)
+		}
+	}
+}
+
+// SetSource records file as where the named template of kind is defined.
+func (s *TemplateStore) SetSource(kind, name, file string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.setSourceLocked(kind, name, file)
+}
+
+func (s *TemplateStore) setSourceLocked(kind, name, file string) {
+	if s.sources == nil {
+		s.sources = make(map[string]map[string]string)
+	}
+	if s.sources[kind] == nil {
+		s.sources[kind] = make(map[string]string)
+	}
+	s.sources[kind][name] = file
+}
+
+// Source returns the file defining the named template of kind, or "" for a
+// template that was not loaded from a file.
+func (s *TemplateStore) Source(kind, name string) string {
+	s.mu.Lock()
+	file, base := s.sources[kind][name], s.base
+	s.mu.Unlock()
+	if file == "" && base != nil {
+		return base.Source(kind, name)
+	}
+	return file
+}
+
 // GetRadioTemplate retrieves a radio template by name
 func (s *TemplateStore) GetRadioTemplate(name string) (map[string]any, bool) {
-	t, ok := s.Radio[name]
-	return t, ok
+	return s.lookup(TemplateKindRadio, name)
 }
 
 // GetWLANTemplate retrieves a WLAN template by name
 func (s *TemplateStore) GetWLANTemplate(name string) (map[string]any, bool) {
-	t, ok := s.WLAN[name]
-	return t, ok
+	return s.lookup(TemplateKindWLAN, name)
 }
 
 // GetDeviceTemplate retrieves a device template by name
 func (s *TemplateStore) GetDeviceTemplate(name string) (map[string]any, bool) {
-	t, ok := s.Device[name]
-	return t, ok
+	return s.lookup(TemplateKindDevice, name)
 }
 
-// IsEmpty returns true if no templates are loaded
-func (s *TemplateStore) IsEmpty() bool {
-	return len(s.Radio) == 0 && len(s.WLAN) == 0 && len(s.Device) == 0
+// GetAppPolicyTemplate retrieves a gateway app policy template by name
+func (s *TemplateStore) GetAppPolicyTemplate(name string) (map[string]any, bool) {
+	return s.lookup(TemplateKindAppPolicy, name)
 }
 
-// ListTemplates returns all template names by type
-func (s *TemplateStore) ListTemplates() map[string][]string {
-	result := make(map[string][]string)
+// GetTrafficSteeringTemplate retrieves a gateway traffic steering template by name
+func (s *TemplateStore) GetTrafficSteeringTemplate(name string) (map[string]any, bool) {
+	return s.lookup(TemplateKindTrafficSteering, name)
+}
 
-	radioNames := make([]string, 0, len(s.Radio))
-	for name := range s.Radio {
-		radioNames = append(radioNames, name)
-	}
-	result["radio"] = radioNames
+// GetSecurityTemplate retrieves a switch security baseline template by name
+func (s *TemplateStore) GetSecurityTemplate(name string) (map[string]any, bool) {
+	return s.lookup(TemplateKindSecurity, name)
+}
 
-	wlanNames := make([]string, 0, len(s.WLAN))
-	for name := range s.WLAN {
-		wlanNames = append(wlanNames, name)
+// GetModelTemplate retrieves the default settings for a hardware model. Models
+// match case-insensitively, so "ap45" finds a template keyed "AP45".
+func (s *TemplateStore) GetModelTemplate(model string) (map[string]any, string, bool) {
+	if model == "" {
+		return nil, "", false
+	}
+	if t, ok := s.lookup(TemplateKindModel, model); ok {
+		return t, model, true
+	}
+	for _, name := range s.ListTemplates()[TemplateKindModel] {
+		if strings.EqualFold(name, model) {
+			t, ok := s.lookup(TemplateKindModel, name)
+			return t, name, ok
+		}
 	}
-	result["wlan"] = wlanNames
+	return nil, "", false
+}
 
-	deviceNames := make([]string, 0, len(s.Device))
-	for name := range s.Device {
-		deviceNames = append(deviceNames, name)
+// IsEmpty returns true if no templates are loaded
+func (s *TemplateStore) IsEmpty() bool {
+	for _, names := range s.ListTemplates() {
+		if len(names) > 0 {
+			return false
+		}
 	}
-	result["device"] = deviceNames
+	return true
+}
 
+// ListTemplates returns all template names by type, decoded or not
+func (s *TemplateStore) ListTemplates() map[string][]string {
+	if s.base != nil {
+		return s.base.ListTemplates()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := make(map[string][]string, len(templateKindNames))
+	for kind := range templateKindNames {
+		decoded := s.kindMap(kind)
+		names := make([]string, 0, len(decoded)+len(s.pending[kind]))
+		for name := range decoded {
+			names = append(names, name)
+		}
+		for name := range s.pending[kind] {
+			if _, ok := decoded[name]; !ok {
+				names = append(names, name)
+			}
+		}
+		result[kind] = names
+	}
 	return result
 }
@@ -251,3 +251,117 @@ func TestTemplateStore_IsEmpty(t *testing.T) {
 		t.Error("Expected store with device template to not be empty")
 	}
 }
+
+func TestLoadTemplates_LazyResolution(t *testing.T) {
+	tempDir := t.TempDir()
+	templateFile := filepath.Join(tempDir, "templates.json")
+	content := `{
+  "version": 1,
+  "templates": {
+    "wlan": {
+      "corp": {"ssid": "Corp"},
+      "guest": {"ssid": "Guest"}
+    }
+  }
+}`
+	if err := os.WriteFile(templateFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write template file: %v", err)
+	}
+
+	store, err := LoadTemplates([]string{templateFile}, "")
+	if err != nil {
+		t.Fatalf("Failed to load templates: %v", err)
+	}
+	if len(store.WLAN) != 0 {
+		t.Errorf("Expected no decoded WLAN templates before lookup, got %d", len(store.WLAN))
+	}
+	if store.IsEmpty() || len(store.ListTemplates()["wlan"]) != 2 {
+		t.Errorf("Expected 2 listed WLAN templates, got %v", store.ListTemplates()["wlan"])
+	}
+
+	wlan, found := store.GetWLANTemplate("corp")
+	if !found || wlan["ssid"] != "Corp" {
+		t.Fatalf("Expected corp template with ssid Corp, got %v (found=%v)", wlan, found)
+	}
+	if len(store.WLAN) != 1 {
+		t.Errorf("Expected only the looked-up template decoded, got %d", len(store.WLAN))
+	}
+
+	store.Resolve()
+	if len(store.WLAN) != 2 || store.WLAN["guest"]["ssid"] != "Guest" {
+		t.Errorf("Expected Resolve to decode every template, got %v", store.WLAN)
+	}
+}
+
+func TestLoadTemplates_ParseCache(t *testing.T) {
+	tempDir := t.TempDir()
+	content := []byte(`{"version": 1, "templates": {"radio": {"shared": {"power": 10}}}}`)
+	file1 := filepath.Join(tempDir, "a.json")
+	file2 := filepath.Join(tempDir, "b.json")
+	for _, f := range []string{file1, file2} {
+		if err := os.WriteFile(f, content, 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", f, err)
+		}
+	}
+
+	a, err := parseTemplateFile(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := parseTemplateFile(content); a != b {
+		t.Error("Expected identical content to reuse the cached parse")
+	}
+
+	// Stores built from the cached parse must not share decoded maps.
+	s1, err := LoadTemplates([]string{file1}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s2, err := LoadTemplates([]string{file2}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r1, _ := s1.GetRadioTemplate("shared")
+	r1["power"] = 20.0
+	if r2, _ := s2.GetRadioTemplate("shared"); r2["power"] != 10.0 {
+		t.Errorf("Expected an independent decode, got power=%v", r2["power"])
+	}
+}
+
+func TestLoadTemplates_NonObjectTemplate(t *testing.T) {
+	tempDir := t.TempDir()
+	templateFile := filepath.Join(tempDir, "bad.json")
+	if err := os.WriteFile(templateFile, []byte(`{"version": 1, "templates": {"wlan": {"corp": "Corp"}}}`), 0644); err != nil {
+		t.Fatalf("Failed to write template file: %v", err)
+	}
+	if _, err := LoadTemplates([]string{templateFile}, ""); err == nil {
+		t.Error("Expected error for a template that is not an object")
+	}
+}
+
+func TestTemplateStore_DirectEntryOverridesFile(t *testing.T) {
+	tempDir := t.TempDir()
+	templateFile := filepath.Join(tempDir, "templates.json")
+	if err := os.WriteFile(templateFile, []byte(`{"version": 1, "templates": {"wlan": {"corp": {"ssid": "FromFile"}}}}`), 0644); err != nil {
+		t.Fatalf("Failed to write template file: %v", err)
+	}
+	store, err := LoadTemplates([]string{templateFile}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Import files merge into the exported maps after template files load.
+	MergeImportTemplates(store, &ImportFile{Templates: &TemplateDefinitions{
+		WLAN: map[string]map[string]any{"corp": {"ssid": "FromImport"}},
+	}})
+	if wlan, _ := store.GetWLANTemplate("corp"); wlan["ssid"] != "FromImport" {
+		t.Errorf("Expected the import to win, got %v", wlan["ssid"])
+	}
+	store.Resolve()
+	if store.WLAN["corp"]["ssid"] != "FromImport" {
+		t.Errorf("Expected Resolve to keep the import, got %v", store.WLAN["corp"]["ssid"])
+	}
+	if names := store.ListTemplates()["wlan"]; len(names) != 1 {
+		t.Errorf("Expected one listed WLAN template, got %v", names)
+	}
+}
@@ -0,0 +1,208 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Template kinds, matching the sections of a template file.
+const (
+	TemplateKindWLAN   = "wlan"
+	TemplateKindRadio  = "radio"
+	TemplateKindDevice = "device"
+
+	TemplateKindAppPolicy       = "app_policy"
+	TemplateKindTrafficSteering = "traffic_steering"
+	TemplateKindSecurity        = "security"
+
+	// Model templates are keyed by hardware model and apply to every device
+	// of that model; no site config names them.
+	TemplateKindModel = "model"
+)
+
+// templateKinds lists every template kind in template-file section order.
+var templateKinds = []string{
+	TemplateKindWLAN, TemplateKindRadio, TemplateKindDevice,
+	TemplateKindAppPolicy, TemplateKindTrafficSteering, TemplateKindSecurity,
+}
+
+// TemplateRef is one place a site config names a template.
+type TemplateRef struct {
+	Kind       string `json:"kind"` // one of the TemplateKind constants
+	Label      string `json:"label"`
+	File       string `json:"file"`                  // site config file, as listed in config
+	SiteKey    string `json:"site_key"`              // key under config.sites
+	Site       string `json:"site"`                  // site_config.name, else SiteKey
+	DeviceType string `json:"device_type,omitempty"` // empty for site-level references
+	MAC        string `json:"mac,omitempty"`         // device key as written; empty for site-level references
+	DeviceName string `json:"device_name,omitempty"`
+	Field      string `json:"field"` // referencing field, e.g. "profiles.wlan" or "radio_profile"
+}
+
+// siteTemplateFields are the site-level list fields that name templates.
+var siteTemplateFields = []struct{ path, kind string }{
+	{"profiles.wlan", TemplateKindWLAN},
+	{"profiles.radio", TemplateKindRadio},
+	{"profiles.device", TemplateKindDevice},
+	{"wlan", TemplateKindWLAN},
+	{WLANDisabledField, TemplateKindWLAN},
+	{"wan_edge.app_policies", TemplateKindAppPolicy},
+	{"wan_edge.traffic_steering", TemplateKindTrafficSteering},
+}
+
+// deviceTemplateFields are the device-level fields that name templates; wlan
+// is a list, the others a single label.
+var deviceTemplateFields = []struct{ field, kind string }{
+	{"device_template", TemplateKindDevice},
+	{"radio_profile", TemplateKindRadio},
+	{SecurityBaselineField, TemplateKindSecurity},
+	{"wlan", TemplateKindWLAN},
+}
+
+// FindTemplateRefs lists every reference to label in the given site config
+// files (relative paths resolve against configDir). kind limits the search to
+// one template kind; empty matches all. Entries set aside under
+// devices._disabled are not live and are skipped. Results are ordered by
+// file, site, and device.
+func FindTemplateRefs(files []string, configDir, kind, label string) ([]TemplateRef, error) {
+	var refs []TemplateRef
+	for _, file := range files {
+		raw, err := readRawConfigFile(resolveConfigPath(configDir, file))
+		if err != nil {
+			return nil, err
+		}
+		walkTemplateRefs(raw, func(ref TemplateRef, _ func(string)) {
+			if ref.Label != label || (kind != "" && ref.Kind != kind) {
+				return
+			}
+			ref.File = file
+			refs = append(refs, ref)
+		})
+	}
+	return refs, nil
+}
+
+// walkTemplateRefs calls visit for every template reference in a parsed site
+// config file, in a stable order. set replaces the referenced label in place.
+func walkTemplateRefs(raw map[string]any, visit func(ref TemplateRef, set func(string))) {
+	cfg, _ := raw["config"].(map[string]any)
+	sites, _ := cfg["sites"].(map[string]any)
+	for _, siteKey := range sortedKeys(sites) {
+		site, ok := sites[siteKey].(map[string]any)
+		if !ok {
+			continue
+		}
+		siteName := siteKey
+		if sc, ok := site["site_config"].(map[string]any); ok {
+			if n, ok := sc["name"].(string); ok && n != "" {
+				siteName = n
+			}
+		}
+		base := TemplateRef{SiteKey: siteKey, Site: siteName}
+
+		for _, f := range siteTemplateFields {
+			container := site
+			key := f.path
+			if section, rest, nested := strings.Cut(f.path, "."); nested {
+				container, _ = site[section].(map[string]any)
+				key = rest
+			}
+			ref := base
+			ref.Kind, ref.Field = f.kind, f.path
+			walkLabelList(container, key, ref, visit)
+		}
+
+		// wlan_overrides is keyed by WLAN label; a rename moves the key
+		if overrides, ok := site[WLANOverridesField].(map[string]any); ok {
+			for _, label := range sortedKeys(overrides) {
+				ref := base
+				ref.Kind, ref.Field, ref.Label = TemplateKindWLAN, WLANOverridesField, label
+				old := label
+				visit(ref, func(s string) {
+					overrides[s] = overrides[old]
+					delete(overrides, old)
+				})
+			}
+		}
+
+		devices, _ := site["devices"].(map[string]any)
+		for _, dtype := range sortedKeys(devices) {
+			if dtype == DisabledDevicesKey {
+				continue
+			}
+			section, _ := devices[dtype].(map[string]any)
+			for _, mac := range sortedKeys(section) {
+				dev, ok := section[mac].(map[string]any)
+				if !ok {
+					continue
+				}
+				ref := base
+				ref.DeviceType, ref.MAC = dtype, mac
+				ref.DeviceName, _ = dev["name"].(string)
+				for _, f := range deviceTemplateFields {
+					ref.Kind, ref.Field = f.kind, f.field
+					if f.field == "wlan" {
+						walkLabelList(dev, f.field, ref, visit)
+						continue
+					}
+					if label, ok := dev[f.field].(string); ok && label != "" {
+						ref.Label = label
+						field := f.field
+						visit(ref, func(s string) { dev[field] = s })
+					}
+				}
+			}
+		}
+	}
+}
+
+// walkLabelList visits each string in container[key] when it is a list.
+func walkLabelList(container map[string]any, key string, ref TemplateRef, visit func(TemplateRef, func(string))) {
+	list, ok := container[key].([]any)
+	if !ok {
+		return
+	}
+	for i, v := range list {
+		label, ok := v.(string)
+		if !ok || label == "" {
+			continue
+		}
+		ref.Label = label
+		idx := i
+		visit(ref, func(s string) { list[idx] = s })
+	}
+}
+
+// readRawConfigFile parses a config file as a generic map, so a rewrite keeps
+// fields the typed structs do not model.
+func readRawConfigFile(fullPath string) (map[string]any, error) {
+	data, err := os.ReadFile(fullPath) // #nosec G304 -- path from operator-controlled config
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", fullPath, err)
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", fullPath, err)
+	}
+	return raw, nil
+}
+
+func resolveConfigPath(configDir, file string) string {
+	if !filepath.IsAbs(file) && configDir != "" {
+		return filepath.Join(configDir, file)
+	}
+	return file
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const templateRefsFixture = `{
+  "version": 1,
+  "config": {
+    "sites": {
+      "lab": {
+        "site_config": {"name": "US-LAB-01"},
+        "profiles": {"wlan": ["corp", "guest"], "radio": ["high-density"]},
+        "wlan": ["corp"],
+        "wan_edge": {"gateway_template": "branch-wan", "app_policies": ["guest-internet"], "traffic_steering": ["internet-first"]},
+        "devices": {
+          "ap": {
+            "aa0000000001": {"name": "lab-ap-01", "radio_profile": "high-density", "wlan": ["guest"]},
+            "aa0000000002": {"name": "lab-ap-02", "device_template": "standard-ap"}
+          },
+          "_disabled": {"ap": {"aa0000000003": {"radio_profile": "high-density"}}}
+        }
+      }
+    }
+  }
+}`
+
+func writeTemplateRefsFixture(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "sites.json"), []byte(templateRefsFixture), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestFindTemplateRefs(t *testing.T) {
+	dir := writeTemplateRefsFixture(t)
+
+	tests := []struct {
+		kind, label string
+		want        []string // Field@MAC
+	}{
+		{"", "corp", []string{"profiles.wlan@", "wlan@"}},
+		{"wlan", "guest", []string{"profiles.wlan@", "wlan@aa0000000001"}},
+		{"", "high-density", []string{"profiles.radio@", "radio_profile@aa0000000001"}},
+		{"device", "standard-ap", []string{"device_template@aa0000000002"}},
+		{"app_policy", "guest-internet", []string{"wan_edge.app_policies@"}},
+		{"", "internet-first", []string{"wan_edge.traffic_steering@"}},
+		{"radio", "corp", nil},
+		{"", "unused", nil},
+	}
+	for _, tt := range tests {
+		refs, err := FindTemplateRefs([]string{"sites.json"}, dir, tt.kind, tt.label)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, r := range refs {
+			if r.Site != "US-LAB-01" || r.File != "sites.json" {
+				t.Errorf("%s: unexpected ref location %+v", tt.label, r)
+			}
+			got = append(got, r.Field+"@"+r.MAC)
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%s/%s: got %v, want %v", tt.kind, tt.label, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s/%s: got %v, want %v", tt.kind, tt.label, got, tt.want)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// TemplateRename is a planned template rename: the template definition and
+// every reference to it, rewritten in memory and not yet written to disk.
+type TemplateRename struct {
+	Kind        string
+	Old         string
+	New         string
+	Definitions []string      // files whose templates section defines Old
+	Refs        []TemplateRef // references that will be rewritten
+
+	files map[string]renamedFile // full path -> contents
+}
+
+// PlanTemplateRename prepares renaming a template from oldLabel to newLabel.
+// templateFiles and siteFiles are the configured files.templates and
+// site-config files (imports may appear in both; each file is read once).
+// kind may be empty when oldLabel is defined under exactly one kind. It is an
+// error for oldLabel to be undefined or for newLabel to already exist.
+func PlanTemplateRename(templateFiles, siteFiles []string, configDir, kind, oldLabel, newLabel string) (*TemplateRename, error) {
+	if oldLabel == "" || newLabel == "" {
+		return nil, fmt.Errorf("template labels must not be empty")
+	}
+	if oldLabel == newLabel {
+		return nil, fmt.Errorf("old and new template labels are the same")
+	}
+
+	var paths []string
+	display := make(map[string]string)
+	raws := make(map[string]map[string]any)
+	originals := make(map[string][]byte)
+	for _, file := range append(append([]string{}, templateFiles...), siteFiles...) {
+		full := resolveConfigPath(configDir, file)
+		if _, seen := raws[full]; seen {
+			continue
+		}
+		data, err := os.ReadFile(full) // #nosec G304 -- path from operator-controlled config
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", full, err)
+		}
+		var raw map[string]any
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", full, err)
+		}
+		paths = append(paths, full)
+		display[full] = file
+		raws[full] = raw
+		originals[full] = data
+	}
+
+	defined := make(map[string][]string) // kind -> files defining oldLabel
+	clashes := make(map[string][]string) // kind -> files defining newLabel
+	for _, full := range paths {
+		templates, _ := raws[full]["templates"].(map[string]any)
+		for _, k := range templateKinds {
+			section, _ := templates[k].(map[string]any)
+			if _, ok := section[oldLabel]; ok {
+				defined[k] = append(defined[k], display[full])
+			}
+			if _, ok := section[newLabel]; ok {
+				clashes[k] = append(clashes[k], display[full])
+			}
+		}
+	}
+
+	if kind == "" {
+		switch len(defined) {
+		case 0:
+			return nil, fmt.Errorf("template '%s' is not defined in any template file", oldLabel)
+		case 1:
+			for k := range defined {
+				kind = k
+			}
+		default:
+			kinds := make([]string, 0, len(defined))
+			for k := range defined {
+				kinds = append(kinds, k)
+			}
+			sort.Strings(kinds)
+			return nil, fmt.Errorf("template '%s' is defined as %s; specify the type to rename", oldLabel, strings.Join(kinds, " and "))
+		}
+	} else if len(defined[kind]) == 0 {
+		return nil, fmt.Errorf("%s template '%s' is not defined in any template file", kind, oldLabel)
+	}
+	if len(clashes[kind]) > 0 {
+		return nil, fmt.Errorf("%s template '%s' already exists in %s", kind, newLabel, strings.Join(clashes[kind], ", "))
+	}
+
+	plan := &TemplateRename{
+		Kind:        kind,
+		Old:         oldLabel,
+		New:         newLabel,
+		Definitions: defined[kind],
+		files:       make(map[string]renamedFile),
+	}
+	for _, full := range paths {
+		raw := raws[full]
+		changed := false
+		if templates, ok := raw["templates"].(map[string]any); ok {
+			if section, ok := templates[kind].(map[string]any); ok {
+				if def, ok := section[oldLabel]; ok {
+					section[newLabel] = def
+					delete(section, oldLabel)
+					changed = true
+				}
+			}
+		}
+		walkTemplateRefs(raw, func(ref TemplateRef, set func(string)) {
+			if ref.Kind != kind || ref.Label != oldLabel {
+				return
+			}
+			set(newLabel)
+			ref.File = display[full]
+			plan.Refs = append(plan.Refs, ref)
+			changed = true
+		})
+		if !changed {
+			continue
+		}
+		data, err := renameInPlace(originals[full], raw, oldLabel, newLabel)
+		if err != nil {
+			return nil, fmt.Errorf("failed to rewrite %s: %w", full, err)
+		}
+		perm := os.FileMode(0600)
+		if info, err := os.Stat(full); err == nil {
+			perm = info.Mode().Perm()
+		}
+		plan.files[full] = renamedFile{original: originals[full], updated: data, perm: perm}
+	}
+	return plan, nil
+}
+
+// renameInPlace returns data with the edits a rename made to its decoded form
+// (updated) spliced into the original bytes: keys renamed from oldLabel to
+// newLabel and string values that changed. Everything else, including key
+// order and formatting, is left as written.
+func renameInPlace(data []byte, updated map[string]any, oldLabel, newLabel string) ([]byte, error) {
+	type edit struct {
+		start, end int
+		text       []byte
+	}
+	var edits []edit
+	dec := json.NewDecoder(bytes.NewReader(data))
+
+	// next reads one token and returns its byte span, skipping the separators
+	// the decoder consumes without returning.
+	next := func() (json.Token, int, int, error) {
+		start := int(dec.InputOffset())
+		for start < len(data) && strings.ContainsRune(" \t\r\n,:", rune(data[start])) {
+			start++
+		}
+		tok, err := dec.Token()
+		return tok, start, int(dec.InputOffset()), err
+	}
+	replace := func(start, end int, s string) error {
+		text, err := json.Marshal(s)
+		if err != nil {
+			return err
+		}
+		edits = append(edits, edit{start, end, text})
+		return nil
+	}
+
+	var walk func(node any) error
+	walk = func(node any) error {
+		tok, start, end, err := next()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case json.Delim:
+			switch t {
+			case '{':
+				obj, _ := node.(map[string]any)
+				for dec.More() {
+					tok, start, end, err := next()
+					if err != nil {
+						return err
+					}
+					key, _ := tok.(string)
+					if _, kept := obj[key]; !kept && key == oldLabel {
+						if _, renamed := obj[newLabel]; renamed {
+							if err := replace(start, end, newLabel); err != nil {
+								return err
+							}
+							key = newLabel
+						}
+					}
+					if err := walk(obj[key]); err != nil {
+						return err
+					}
+				}
+			case '[':
+				list, _ := node.([]any)
+				for i := 0; dec.More(); i++ {
+					var item any
+					if i < len(list) {
+						item = list[i]
+					}
+					if err := walk(item); err != nil {
+						return err
+					}
+				}
+			}
+			_, err := dec.Token() // closing delimiter
+			return err
+		case string:
+			if s, ok := node.(string); ok && s != t {
+				return replace(start, end, s)
+			}
+		}
+		return nil
+	}
+	if err := walk(updated); err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	last := 0
+	for _, e := range edits {
+		out.Write(data[last:e.start])
+		out.Write(e.text)
+		last = e.end
+	}
+	out.Write(data[last:])
+
+	// Every edit a rename makes is a key or string value, so the result must
+	// decode to exactly what was planned.
+	var check map[string]any
+	if err := json.Unmarshal(out.Bytes(), &check); err != nil || !reflect.DeepEqual(check, updated) {
+		return nil, fmt.Errorf("edits could not be applied in place")
+	}
+	return out.Bytes(), nil
+}
+
+// Files returns the full paths the rename rewrites, sorted.
+func (r *TemplateRename) Files() []string {
+	out := make([]string, 0, len(r.files))
+	for path := range r.files {
+		out = append(out, path)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Apply writes every rewritten file. Each write is atomic; if one fails, the
+// files already written are restored to their original contents, so a
+// failed rename never leaves references pointing at a label that no longer
+// exists. If a restore fails too, the error wraps ErrRollbackIncomplete.
+func (r *TemplateRename) Apply() error {
+	return writeRewrittenFiles(r.files)
+}
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const templateRenameTemplates = `{
+  "version": 1,
+  "templates": {
+    "wlan": {"corp": {"ssid": "Corp"}, "guest": {"ssid": "Guest"}},
+    "radio": {"guest": {"band_24": {"disabled": true}}}
+  }
+}`
+
+func writeTemplateRenameFixture(t *testing.T) string {
+	t.Helper()
+	dir := writeTemplateRefsFixture(t)
+	if err := os.WriteFile(filepath.Join(dir, "templates.json"), []byte(templateRenameTemplates), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestPlanTemplateRenameRewritesReferences(t *testing.T) {
+	dir := writeTemplateRenameFixture(t)
+	files := []string{"templates.json"}
+	sites := []string{"sites.json"}
+
+	plan, err := PlanTemplateRename(files, sites, dir, "", "corp", "corp-wifi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Kind != TemplateKindWLAN || len(plan.Refs) != 2 || len(plan.Files()) != 2 {
+		t.Fatalf("plan = kind %s, %d refs, files %v", plan.Kind, len(plan.Refs), plan.Files())
+	}
+	// Nothing is written until Apply.
+	if refs, _ := FindTemplateRefs(sites, dir, "", "corp"); len(refs) != 2 {
+		t.Fatalf("plan wrote to disk: %d refs to corp remain", len(refs))
+	}
+	if err := plan.Apply(); err != nil {
+		t.Fatal(err)
+	}
+
+	if refs, _ := FindTemplateRefs(sites, dir, "", "corp"); len(refs) != 0 {
+		t.Errorf("%d references to corp remain", len(refs))
+	}
+	if refs, _ := FindTemplateRefs(sites, dir, "", "corp-wifi"); len(refs) != 2 {
+		t.Errorf("got %d references to corp-wifi, want 2", len(refs))
+	}
+	store, err := LoadTemplates(files, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := store.GetWLANTemplate("corp-wifi"); !ok {
+		t.Error("corp-wifi not defined after rename")
+	}
+	if _, ok := store.GetWLANTemplate("corp"); ok {
+		t.Error("corp still defined after rename")
+	}
+}
+
+func TestPlanTemplateRenameKeepsFormatting(t *testing.T) {
+	dir := writeTemplateRenameFixture(t)
+	plan, err := PlanTemplateRename([]string{"templates.json"}, []string{"sites.json"}, dir, "", "corp", "corp-wifi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := plan.Apply(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the label changes: key order, compact objects and the missing
+	// trailing newline all survive.
+	for name, before := range map[string]string{"templates.json": templateRenameTemplates, "sites.json": templateRefsFixture} {
+		got, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := strings.ReplaceAll(before, `"corp"`, `"corp-wifi"`); string(got) != want {
+			t.Errorf("%s after rename:\n%s\nwant:\n%s", name, got, want)
+		}
+	}
+}
+
+func TestPlanTemplateRenameErrors(t *testing.T) {
+	dir := writeTemplateRenameFixture(t)
+	files := []string{"templates.json"}
+	sites := []string{"sites.json"}
+
+	tests := []struct {
+		kind, old, new, err string
+	}{
+		{"", "guest", "visitor", "specify the type"},
+		{"", "missing", "x", "not defined"},
+		{"radio", "corp", "x", "not defined"},
+		{"wlan", "corp", "guest", "already exists"},
+		{"", "corp", "corp", "the same"},
+	}
+	for _, tt := range tests {
+		_, err := PlanTemplateRename(files, sites, dir, tt.kind, tt.old, tt.new)
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%s %s->%s: err = %v, want %q", tt.kind, tt.old, tt.new, err, tt.err)
+		}
+	}
+
+	// With the kind given, a label shared across kinds renames only that kind.
+	plan, err := PlanTemplateRename(files, sites, dir, "radio", "guest", "visitor")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Refs) != 0 || len(plan.Files()) != 1 {
+		t.Errorf("radio rename touched %d refs, files %v", len(plan.Refs), plan.Files())
+	}
+}
@@ -0,0 +1,286 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+
+	"github.com/ravinald/wifimgr/internal/filterexpr"
+)
+
+// Templates can depend on the site they expand at. Two forms are rendered
+// before vendor blocks are merged:
+//
+//	"${site.country_code}"   a reference, replaced by the site's attribute
+//	"if:site.region=emea"    a conditional block, merged into the object that
+//	                         holds it when its filter expression matches
+//
+// A string that is exactly one reference takes the attribute's value and
+// type, so "vlan_id": "${vars.guest_vlan}" can yield a number; "$${" writes a
+// literal "${". Conditions use the filter language of the show commands
+// (see filterexpr); matching blocks are merged in key order, over the
+// template's own fields. Referencing an attribute the site does not define is
+// an error, so a template never pushes a placeholder.
+
+// Site attribute namespaces.
+const (
+	SiteAttrPrefix  = "site."  // scalar fields of site_config, plus site.name and site.groups
+	VarsAttrPrefix  = "vars."  // the site's "vars" object
+	GroupAttrPrefix = "group." // "yes" or "no" for every site group in site_groups
+)
+
+// conditionPrefix starts the key of a conditional template block.
+const conditionPrefix = "if:"
+
+var siteRefPattern = regexp.MustCompile(`\$?\$\{([^}]*)\}`)
+
+// SiteAttributes are the values templates can reference at one site.
+type SiteAttributes struct {
+	Site   string
+	values map[string]any
+}
+
+// NewSiteAttributes builds the attributes of a site from its site_config and
+// vars. site_groups in the main config contribute site.groups (the groups
+// holding the site, comma-separated) and a group.<name> flag per group.
+func NewSiteAttributes(siteName string, siteConfig, vars map[string]any) SiteAttributes {
+	a := SiteAttributes{Site: siteName, values: map[string]any{}}
+	for k, v := range siteConfig {
+		switch v.(type) {
+		case map[string]any, []any, nil:
+			continue
+		}
+		a.values[SiteAttrPrefix+strings.ToLower(k)] = v
+	}
+	a.values[SiteAttrPrefix+"name"] = siteName
+
+	var member []string
+	for group, sites := range viper.GetStringMapStringSlice("site_groups") {
+		if strings.HasPrefix(group, "_") {
+			continue
+		}
+		in := "no"
+		for _, s := range sites {
+			if strings.EqualFold(strings.TrimSpace(s), siteName) {
+				in = "yes"
+				member = append(member, group)
+				break
+			}
+		}
+		a.values[GroupAttrPrefix+group] = in
+	}
+	sort.Strings(member)
+	a.values[SiteAttrPrefix+"groups"] = strings.Join(member, ",")
+
+	for k, v := range vars {
+		a.values[VarsAttrPrefix+strings.ToLower(k)] = v
+	}
+	return a
+}
+
+// Lookup returns the attribute named key (e.g. "vars.radius_primary").
+func (a SiteAttributes) Lookup(key string) (any, bool) {
+	v, ok := a.values[strings.ToLower(strings.TrimSpace(key))]
+	return v, ok
+}
+
+// missing is the error for an attribute the site does not define.
+func (a SiteAttributes) missing(key string) error {
+	switch {
+	case strings.HasPrefix(key, VarsAttrPrefix):
+		return fmt.Errorf("site %s has no %s (add it to the site's \"vars\")", a.Site, key)
+	case strings.HasPrefix(key, SiteAttrPrefix):
+		return fmt.Errorf("site %s has no %s (add it to the site's \"site_config\")", a.Site, key)
+	case strings.HasPrefix(key, GroupAttrPrefix):
+		return fmt.Errorf("no site group %q in site_groups", strings.TrimPrefix(key, GroupAttrPrefix))
+	}
+	return fmt.Errorf("unknown attribute %q (use site.<field>, vars.<name>, or group.<name>)", key)
+}
+
+// RenderForSite returns a copy of template with its references replaced and
+// its conditional blocks resolved for the site.
+func RenderForSite(template map[string]any, attrs SiteAttributes) (map[string]any, error) {
+	out, err := attrs.render(template, "")
+	if err != nil {
+		return nil, err
+	}
+	return out.(map[string]any), nil
+}
+
+func (a SiteAttributes) render(v any, path string) (any, error) {
+	switch val := v.(type) {
+	case map[string]any:
+		return a.renderMap(val, path)
+	case []any:
+		out := make([]any, len(val))
+		for i, item := range val {
+			r, err := a.render(item, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+			out[i] = r
+		}
+		return out, nil
+	case string:
+		r, err := a.substitute(val)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", fieldPath(path), err)
+		}
+		return r, nil
+	}
+	return v, nil
+}
+
+func (a SiteAttributes) renderMap(m map[string]any, path string) (any, error) {
+	out := make(map[string]any, len(m))
+	var conditions []string
+	for k, v := range m {
+		if strings.HasPrefix(k, conditionPrefix) {
+			conditions = append(conditions, k)
+			continue
+		}
+		r, err := a.render(v, joinPath(path, k))
+		if err != nil {
+			return nil, err
+		}
+		out[k] = r
+	}
+	sort.Strings(conditions)
+	for _, k := range conditions {
+		where := joinPath(path, k)
+		block, ok := m[k].(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%s: a conditional block must be an object", fieldPath(where))
+		}
+		matched, err := a.condition(strings.TrimPrefix(k, conditionPrefix))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", fieldPath(where), err)
+		}
+		if !matched {
+			continue
+		}
+		r, err := a.renderMap(block, path)
+		if err != nil {
+			return nil, err
+		}
+		out = mergeConfigs(out, r.(map[string]any))
+	}
+	return out, nil
+}
+
+// condition evaluates a conditional block's filter expression.
+func (a SiteAttributes) condition(src string) (bool, error) {
+	expr, err := filterexpr.Parse(src)
+	if err != nil {
+		return false, fmt.Errorf("invalid condition %q: %w", src, err)
+	}
+	for _, field := range expr.Fields() {
+		if _, ok := a.values[field]; !ok {
+			return false, fmt.Errorf("condition %q: %w", src, a.missing(field))
+		}
+	}
+	return expr.Match(a.values), nil
+}
+
+// substitute replaces the references in s.
+func (a SiteAttributes) substitute(s string) (any, error) {
+	if !strings.Contains(s, "${") {
+		return s, nil
+	}
+	if m := siteRefPattern.FindStringSubmatch(s); m != nil && m[0] == s && !strings.HasPrefix(s, "$$") {
+		v, ok := a.Lookup(m[1])
+		if !ok {
+			return nil, a.missing(strings.ToLower(strings.TrimSpace(m[1])))
+		}
+		return v, nil
+	}
+	var errs []error
+	out := siteRefPattern.ReplaceAllStringFunc(s, func(ref string) string {
+		if strings.HasPrefix(ref, "$$") {
+			return ref[1:]
+		}
+		key := ref[2 : len(ref)-1]
+		v, ok := a.Lookup(key)
+		if !ok {
+			errs = append(errs, a.missing(strings.ToLower(strings.TrimSpace(key))))
+			return ref
+		}
+		return fmt.Sprint(v)
+	})
+	if len(errs) > 0 {
+		return nil, errs[0]
+	}
+	return out, nil
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func fieldPath(path string) string {
+	if path == "" {
+		return "template"
+	}
+	return path
+}
+
+// ForSite returns a view of the store whose templates are rendered for the
+// site (see RenderForSite) as they are looked up. A template that fails to
+// render is returned as written and its error kept; Err reports it, and the
+// expansion that used it must fail.
+func (s *TemplateStore) ForSite(attrs SiteAttributes) *TemplateStore {
+	view := NewTemplateStore()
+	view.base = s
+	view.site = &attrs
+	return view
+}
+
+// Err returns the render errors of the templates looked up through a ForSite
+// view so far, or nil.
+func (s *TemplateStore) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.renderErrs) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(s.renderErrs))
+	for k := range s.renderErrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	errs := make([]error, 0, len(keys))
+	for _, k := range keys {
+		errs = append(errs, s.renderErrs[k])
+	}
+	return errors.Join(errs...)
+}
+
+// lookupForSiteLocked looks name up in the base store and renders it, once.
+// s.mu is held by the caller.
+func (s *TemplateStore) lookupForSiteLocked(kind, name string) (map[string]any, bool) {
+	decoded := s.kindMap(kind)
+	if t, ok := decoded[name]; ok {
+		return t, true
+	}
+	t, ok := s.base.lookup(kind, name)
+	if !ok {
+		return nil, false
+	}
+	rendered, err := RenderForSite(t, *s.site)
+	if err != nil {
+		if s.renderErrs == nil {
+			s.renderErrs = make(map[string]error)
+		}
+		s.renderErrs[kind+"/"+name] = fmt.Errorf("%s template '%s' at site %s: %w", templateKindNames[kind], name, s.site.Site, err)
+		rendered = t
+	}
+	decoded[name] = rendered
+	return rendered, true
+}
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func testSiteAttributes(t *testing.T) SiteAttributes {
+	t.Helper()
+	viper.Set("site_groups", map[string]any{"emea": []any{"GB-LON-01", "DE-BER-01"}, "retail": []any{"US-NYC-01"}})
+	t.Cleanup(func() { viper.Set("site_groups", nil) })
+	return NewSiteAttributes("GB-LON-01",
+		map[string]any{"country_code": "GB", "timezone": "Europe/London", "latlng": map[string]any{"lat": 51.5}},
+		map[string]any{"radius_primary": "10.20.0.10", "guest_vlan": 120})
+}
+
+func TestRenderForSite(t *testing.T) {
+	attrs := testSiteAttributes(t)
+	template := map[string]any{
+		"ssid":    "Corp-${site.country_code}",
+		"vlan_id": "${vars.guest_vlan}",
+		"note":    "$${not a reference}",
+		"auth": map[string]any{
+			"type":         "eap",
+			"radius_hosts": []any{"10.0.0.10"},
+			"if:group.emea=yes": map[string]any{
+				"radius_hosts": []any{"${vars.radius_primary}"},
+			},
+		},
+		"if:site.country_code=US || site.country_code=CA": map[string]any{"band": "5"},
+		"mist:": map[string]any{"if:site.timezone=Europe/*": map[string]any{"roam_mode": "11r"}},
+	}
+
+	got, err := RenderForSite(template, attrs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"ssid":    "Corp-GB",
+		"vlan_id": 120,
+		"note":    "${not a reference}",
+		"auth": map[string]any{
+			"type":         "eap",
+			"radius_hosts": []any{"10.20.0.10"},
+		},
+		"mist:": map[string]any{"roam_mode": "11r"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RenderForSite =\n%v\nwant\n%v", got, want)
+	}
+	if _, ok := template["ssid"].(string); !ok || template["ssid"] != "Corp-${site.country_code}" {
+		t.Error("the template itself was modified")
+	}
+}
+
+func TestRenderForSiteMissingAttribute(t *testing.T) {
+	attrs := testSiteAttributes(t)
+	tests := map[string]struct {
+		template map[string]any
+		want     string
+	}{
+		"var": {
+			map[string]any{"auth": map[string]any{"radius": "${vars.radius_secondary}"}},
+			`auth.radius: site GB-LON-01 has no vars.radius_secondary (add it to the site's "vars")`,
+		},
+		"site field": {
+			map[string]any{"ssid": "Corp-${site.region}"},
+			"site GB-LON-01 has no site.region",
+		},
+		"condition": {
+			map[string]any{"if:vars.region=emea": map[string]any{"band": "5"}},
+			`condition "vars.region=emea": site GB-LON-01 has no vars.region`,
+		},
+		"group": {
+			map[string]any{"if:group.apac=yes": map[string]any{"band": "5"}},
+			`no site group "apac"`,
+		},
+		"not an object": {
+			map[string]any{"if:site.name=x": "5"},
+			"a conditional block must be an object",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := RenderForSite(tt.template, attrs)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplateStoreForSite(t *testing.T) {
+	attrs := testSiteAttributes(t)
+	store := NewTemplateStore()
+	store.WLAN["corp"] = map[string]any{"ssid": "Corp-${site.country_code}"}
+	store.WLAN["broken"] = map[string]any{"ssid": "${vars.nope}"}
+
+	view := store.ForSite(attrs)
+	expanded, err := ExpandDeviceConfig(map[string]any{"wlan": []any{"corp"}}, nil, view, "mist-prod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wlans := expanded["wlan"].([]map[string]any)
+	if wlans[0]["ssid"] != "Corp-GB" {
+		t.Errorf("ssid = %v", wlans[0]["ssid"])
+	}
+	if store.WLAN["corp"]["ssid"] != "Corp-${site.country_code}" {
+		t.Error("the base store was modified")
+	}
+
+	_, err = ExpandDeviceConfig(map[string]any{"wlan": []any{"broken"}}, nil, view, "mist-prod")
+	if err == nil || !strings.Contains(err.Error(), "WLAN template 'broken' at site GB-LON-01") {
+		t.Errorf("err = %v", err)
+	}
+}
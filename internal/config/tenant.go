@@ -0,0 +1,208 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/spf13/viper"
+
+	"github.com/ravinald/wifimgr/internal/logging"
+	"github.com/ravinald/wifimgr/internal/xdg"
+)
+
+// Tenant scopes a shared install to one customer: the API labels it may use,
+// the config subdirectory holding its site configs, templates, and inventory,
+// and the cache namespace its API caches live under.
+type Tenant struct {
+	Name           string
+	APIs           []string
+	ConfigDir      string
+	CacheNamespace string
+}
+
+var (
+	tenantName   string
+	activeTenant *Tenant
+	tenantMu     sync.RWMutex
+)
+
+// SetTenant records the tenant requested with --tenant (or WIFIMGR_TENANT).
+// It takes effect when the main config is loaded.
+func SetTenant(name string) {
+	tenantMu.Lock()
+	defer tenantMu.Unlock()
+	tenantName = name
+	activeTenant = nil
+}
+
+// ActiveTenant returns the tenant the current run is scoped to, or nil when
+// the config defines no tenants.
+func ActiveTenant() *Tenant {
+	tenantMu.RLock()
+	defer tenantMu.RUnlock()
+	return activeTenant
+}
+
+// tenantAllowsAPI reports whether the active tenant may use the API label.
+// Without a tenant every configured API is available.
+func tenantAllowsAPI(label string) bool {
+	t := ActiveTenant()
+	return t == nil || slices.Contains(t.APIs, label)
+}
+
+// TenantPrefix scopes a location shared by every tenant, such as a bucket
+// key prefix or a remote directory, to the active tenant:
+// "<prefix>/tenants/<name>". Without a tenant prefix is returned unchanged.
+func TenantPrefix(prefix string) string {
+	t := ActiveTenant()
+	if t == nil {
+		return prefix
+	}
+	return path.Join(prefix, "tenants", t.Name)
+}
+
+// LoadTenants reads the tenants section: tenants.<name>.{apis, config_dir,
+// cache_namespace}. config_dir and cache_namespace default to the tenant name.
+func LoadTenants() (map[string]*Tenant, error) {
+	section := viper.GetStringMap("tenants")
+	tenants := make(map[string]*Tenant, len(section))
+	for name, value := range section {
+		if !validAPILabel(name) {
+			return nil, fmt.Errorf("tenant %q has an invalid name (allowed: letters, digits, '.', '_', '-')", name)
+		}
+		nested, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("tenant %q has invalid structure", name)
+		}
+
+		t := &Tenant{Name: name, ConfigDir: name, CacheNamespace: name}
+		if apis, ok := nested["apis"].([]interface{}); ok {
+			for _, a := range apis {
+				if label, ok := a.(string); ok && label != "" {
+					t.APIs = append(t.APIs, label)
+				}
+			}
+		}
+		if len(t.APIs) == 0 {
+			return nil, fmt.Errorf("tenant %q must list at least one API in 'apis'", name)
+		}
+		if dir, ok := nested["config_dir"].(string); ok && dir != "" {
+			t.ConfigDir = dir
+		}
+		// The subdirectory must stay inside files.config_dir, or one tenant
+		// could point at another's configs.
+		if !filepath.IsLocal(t.ConfigDir) {
+			return nil, fmt.Errorf("tenant %q config_dir %q must be a subdirectory of files.config_dir", name, t.ConfigDir)
+		}
+		if ns, ok := nested["cache_namespace"].(string); ok && ns != "" {
+			t.CacheNamespace = ns
+		}
+		if !validAPILabel(t.CacheNamespace) {
+			return nil, fmt.Errorf("tenant %q has an invalid cache_namespace %q", name, t.CacheNamespace)
+		}
+		tenants[name] = t
+	}
+
+	// Two tenants sharing a directory or namespace would see each other's data.
+	dirs := make(map[string]string)
+	namespaces := make(map[string]string)
+	for _, name := range sortedTenantNames(tenants) {
+		t := tenants[name]
+		dir := filepath.Clean(t.ConfigDir)
+		if other, ok := dirs[dir]; ok {
+			return nil, fmt.Errorf("tenants %q and %q share config_dir %q", other, name, t.ConfigDir)
+		}
+		dirs[dir] = name
+		if other, ok := namespaces[t.CacheNamespace]; ok {
+			return nil, fmt.Errorf("tenants %q and %q share cache_namespace %q", other, name, t.CacheNamespace)
+		}
+		namespaces[t.CacheNamespace] = name
+	}
+	return tenants, nil
+}
+
+// applyTenant scopes the loaded config to the tenant set with SetTenant.
+// When the config defines tenants a tenant is required, so a shared install
+// never falls back to reading every customer's configs and caches. The
+// files.* paths are rewritten in place, so every reader of files.config_dir,
+// files.cache_dir, files.cache, files.inventory, and files.backup_dir sees
+// the tenant's paths.
+func applyTenant() error {
+	tenantMu.Lock()
+	defer tenantMu.Unlock()
+	activeTenant = nil
+
+	tenants, err := LoadTenants()
+	if err != nil {
+		return err
+	}
+	if len(tenants) == 0 {
+		if tenantName != "" {
+			return fmt.Errorf("--tenant %q given but the config defines no tenants", tenantName)
+		}
+		return nil
+	}
+	if tenantName == "" {
+		return fmt.Errorf("this config defines tenants; pass --tenant <name> (one of: %s)",
+			strings.Join(sortedTenantNames(tenants), ", "))
+	}
+	t, ok := tenants[tenantName]
+	if !ok {
+		return fmt.Errorf("unknown tenant %q (one of: %s)", tenantName, strings.Join(sortedTenantNames(tenants), ", "))
+	}
+
+	apis := viper.GetStringMap("api")
+	for _, label := range t.APIs {
+		if _, ok := apis[label].(map[string]interface{}); !ok {
+			logging.Warnf("Tenant %q lists API %q, which is not configured", t.Name, label)
+		}
+	}
+
+	configDir := viper.GetString("files.config_dir")
+	if configDir == "" {
+		configDir = xdg.GetConfigDir()
+	}
+	tenantConfigDir := filepath.Join(configDir, t.ConfigDir)
+
+	cacheDir := viper.GetString("files.cache_dir")
+	if cacheDir == "" {
+		cacheDir = xdg.GetCacheDir()
+	}
+	tenantCacheDir := filepath.Join(cacheDir, "tenants", t.CacheNamespace)
+
+	backupDir := viper.GetString("files.backup_dir")
+	if backupDir == "" {
+		backupDir = xdg.GetBackupsDir()
+	}
+	tenantBackupDir := filepath.Join(backupDir, "tenants", t.Name)
+
+	inventory := filepath.Base(viper.GetString("files.inventory"))
+	if inventory == "." || inventory == string(filepath.Separator) {
+		inventory = "inventory.json"
+	}
+
+	viper.Set("files.config_dir", tenantConfigDir)
+	viper.Set("files.cache_dir", tenantCacheDir)
+	viper.Set("files.cache", filepath.Join(tenantCacheDir, "cache.json"))
+	viper.Set("files.inventory", filepath.Join(tenantConfigDir, inventory))
+	viper.Set("files.backup_dir", tenantBackupDir)
+
+	activeTenant = t
+	logging.Debugf("Scoped to tenant %q (APIs: %s, config: %s, cache: %s)",
+		t.Name, strings.Join(t.APIs, ", "), tenantConfigDir, tenantCacheDir)
+	return nil
+}
+
+func sortedTenantNames(tenants map[string]*Tenant) []string {
+	names := make([]string, 0, len(tenants))
+	for name := range tenants {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,147 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func setTenantConfig(t *testing.T, tenants map[string]interface{}) {
+	t.Helper()
+	viper.Reset()
+	t.Cleanup(func() {
+		viper.Reset()
+		SetTenant("")
+	})
+	viper.Set("files.config_dir", "/etc/wifimgr")
+	viper.Set("files.cache_dir", "/var/cache/wifimgr")
+	viper.Set("files.inventory", "/etc/wifimgr/inventory.json")
+	viper.Set("files.backup_dir", "/var/backups/wifimgr")
+	viper.Set("api", map[string]interface{}{
+		"acme-mist":     map[string]interface{}{"vendor": "mist"},
+		"globex-mist":   map[string]interface{}{"vendor": "mist"},
+		"globex-meraki": map[string]interface{}{"vendor": "meraki"},
+	})
+	if tenants != nil {
+		viper.Set("tenants", tenants)
+	}
+}
+
+func TestApplyTenant(t *testing.T) {
+	setTenantConfig(t, map[string]interface{}{
+		"acme":   map[string]interface{}{"apis": []interface{}{"acme-mist"}},
+		"globex": map[string]interface{}{"apis": []interface{}{"globex-mist", "globex-meraki"}, "config_dir": "customers/globex", "cache_namespace": "gx"},
+	})
+
+	SetTenant("globex")
+	if err := applyTenant(); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"files.config_dir": filepath.Join("/etc/wifimgr", "customers/globex"),
+		"files.cache_dir":  filepath.Join("/var/cache/wifimgr", "tenants", "gx"),
+		"files.cache":      filepath.Join("/var/cache/wifimgr", "tenants", "gx", "cache.json"),
+		"files.inventory":  filepath.Join("/etc/wifimgr", "customers/globex", "inventory.json"),
+		"files.backup_dir": filepath.Join("/var/backups/wifimgr", "tenants", "globex"),
+	}
+	for key, w := range want {
+		if got := viper.GetString(key); got != w {
+			t.Errorf("%s = %q, want %q", key, got, w)
+		}
+	}
+	if active := ActiveTenant(); active == nil || active.Name != "globex" {
+		t.Fatalf("ActiveTenant() = %+v", active)
+	}
+
+	configs, _ := BuildAPIConfigsFromViper()
+	if _, ok := configs["acme-mist"]; ok {
+		t.Error("globex can reach acme-mist")
+	}
+	if !tenantAllowsAPI("globex-meraki") || tenantAllowsAPI("acme-mist") {
+		t.Error("tenantAllowsAPI does not follow the tenant's apis")
+	}
+}
+
+// TestApplyTenant_NoSharedState checks that two tenants of one install write
+// nothing to the same place: configs, caches, backups, and remote prefixes.
+func TestApplyTenant_NoSharedState(t *testing.T) {
+	tenants := map[string]interface{}{
+		"acme":   map[string]interface{}{"apis": []interface{}{"acme-mist"}},
+		"globex": map[string]interface{}{"apis": []interface{}{"globex-mist"}},
+	}
+	locations := func(name string, backupDir string) map[string]string {
+		setTenantConfig(t, tenants)
+		viper.Set("files.backup_dir", backupDir)
+		SetTenant(name)
+		if err := applyTenant(); err != nil {
+			t.Fatal(err)
+		}
+		return map[string]string{
+			"config_dir":    viper.GetString("files.config_dir"),
+			"cache_dir":     viper.GetString("files.cache_dir"),
+			"backup_dir":    BackupDir(),
+			"remote_prefix": TenantPrefix("wifimgr"),
+		}
+	}
+
+	// Both with files.backup_dir set and with the XDG default.
+	for _, backupDir := range []string{"/var/backups/wifimgr", ""} {
+		acme, globex := locations("acme", backupDir), locations("globex", backupDir)
+		for key, a := range acme {
+			if g := globex[key]; a == g || strings.HasPrefix(g, a+"/") || strings.HasPrefix(a, g+"/") {
+				t.Errorf("backup_dir %q: acme and globex share %s: %q, %q", backupDir, key, a, g)
+			}
+		}
+	}
+}
+
+func TestApplyTenant_Errors(t *testing.T) {
+	tenants := map[string]interface{}{
+		"acme":   map[string]interface{}{"apis": []interface{}{"acme-mist"}},
+		"globex": map[string]interface{}{"apis": []interface{}{"globex-mist"}},
+	}
+	cases := []struct {
+		name    string
+		tenants map[string]interface{}
+		tenant  string
+		want    string
+	}{
+		{"tenant required", tenants, "", "pass --tenant"},
+		{"unknown tenant", tenants, "initech", `unknown tenant "initech"`},
+		{"no tenants configured", nil, "acme", "defines no tenants"},
+		{"no apis", map[string]interface{}{"acme": map[string]interface{}{}}, "acme", "at least one API"},
+		{"config_dir escapes", map[string]interface{}{
+			"acme": map[string]interface{}{"apis": []interface{}{"acme-mist"}, "config_dir": "../globex"},
+		}, "acme", "subdirectory"},
+		{"shared namespace", map[string]interface{}{
+			"acme":   map[string]interface{}{"apis": []interface{}{"acme-mist"}, "cache_namespace": "shared"},
+			"globex": map[string]interface{}{"apis": []interface{}{"globex-mist"}, "cache_namespace": "shared"},
+		}, "acme", "share cache_namespace"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			setTenantConfig(t, c.tenants)
+			SetTenant(c.tenant)
+			err := applyTenant()
+			if err == nil || !strings.Contains(err.Error(), c.want) {
+				t.Fatalf("got %v, want an error containing %q", err, c.want)
+			}
+			if viper.GetString("files.config_dir") != "/etc/wifimgr" {
+				t.Error("paths were rewritten despite the error")
+			}
+		})
+	}
+}
+
+func TestApplyTenant_NoTenants(t *testing.T) {
+	setTenantConfig(t, nil)
+	SetTenant("")
+	if err := applyTenant(); err != nil {
+		t.Fatal(err)
+	}
+	if ActiveTenant() != nil || viper.GetString("files.config_dir") != "/etc/wifimgr" {
+		t.Error("a config without tenants should be left unscoped")
+	}
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/ravinald/wifimgr/internal/logging"
+)
+
+// DefaultCommandTimeout bounds a command when timeouts.default is not set, so
+// a vendor endpoint that accepts a connection and never answers cannot hold an
+// interactive session forever.
+const DefaultCommandTimeout = 10 * time.Minute
+
+// longRunningCommands are the built-in deadlines of commands that legitimately
+// outlast the default. Zero means no deadline: watch runs until interrupted
+// and edit waits on the operator's editor.
+var longRunningCommands = map[string]time.Duration{
+	"refresh":  time.Hour,
+	"firmware": 2 * time.Hour,
+	"schedule": 2 * time.Hour,
+	"watch":    0,
+	"edit":     0,
+}
+
+// CommandTimeout returns the deadline for a command, given its path without
+// the program name ("firmware apply"). Zero means no deadline.
+//
+// timeouts.commands is searched from the full path down to its first word,
+// so "firmware" covers "firmware apply". Without a match, the built-in
+// long-running deadline applies when it is longer than the default. Otherwise
+// timeouts.default applies, then DefaultCommandTimeout. Durations use Go
+// syntax ("90s", "30m", "2h"); "0" disables the deadline.
+func CommandTimeout(commandPath string) time.Duration {
+	def := DefaultCommandTimeout
+	if s := viper.GetString("timeouts.default"); s != "" {
+		if d, ok := parseTimeout("timeouts.default", s); ok {
+			def = d
+		}
+	}
+
+	configured := viper.GetStringMapString("timeouts.commands")
+	words := strings.Fields(strings.ToLower(commandPath))
+	for n := len(words); n > 0; n-- {
+		key := strings.Join(words[:n], " ")
+		if s, ok := configured[key]; ok {
+			if d, ok := parseTimeout("timeouts.commands."+key, s); ok {
+				return d
+			}
+		}
+	}
+	for n := len(words); n > 0; n-- {
+		if d, ok := longRunningCommands[strings.Join(words[:n], " ")]; ok {
+			if def != 0 && (d == 0 || d > def) {
+				return d
+			}
+			break
+		}
+	}
+	return def
+}
+
+// parseTimeout parses a timeout setting, warning about and ignoring an
+// invalid or negative one.
+func parseTimeout(key, s string) (time.Duration, bool) {
+	d, err := time.ParseDuration(strings.TrimSpace(s))
+	if err != nil || d < 0 {
+		logging.Warnf("Ignoring %s %q: want a duration such as 30m", key, s)
+		return 0, false
+	}
+	return d, true
+}
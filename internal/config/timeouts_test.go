@@ -0,0 +1,46 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func TestCommandTimeout(t *testing.T) {
+	t.Cleanup(func() {
+		viper.Set("timeouts.default", nil)
+		viper.Set("timeouts.commands", nil)
+	})
+
+	cases := []struct {
+		name     string
+		def      string
+		commands map[string]string
+		path     string
+		want     time.Duration
+	}{
+		{"built-in default", "", nil, "show ap", DefaultCommandTimeout},
+		{"configured default", "2m", nil, "show ap", 2 * time.Minute},
+		{"long-running built-in", "", nil, "refresh", time.Hour},
+		{"built-in covers subcommands", "", nil, "firmware apply", 2 * time.Hour},
+		{"watch has no deadline", "", nil, "watch site", 0},
+		{"default longer than built-in wins", "3h", nil, "refresh", 3 * time.Hour},
+		{"disabled default disables built-ins", "0", nil, "refresh", 0},
+		{"per-command override", "", map[string]string{"show ap": "30s"}, "show ap", 30 * time.Second},
+		{"override matches by prefix", "", map[string]string{"firmware": "4h"}, "firmware apply", 4 * time.Hour},
+		{"most specific override wins", "", map[string]string{"firmware": "4h", "firmware apply": "3h"}, "firmware apply", 3 * time.Hour},
+		{"override may disable", "", map[string]string{"show": "0"}, "show ap", 0},
+		{"invalid override ignored", "", map[string]string{"refresh": "soon"}, "refresh", time.Hour},
+		{"invalid default ignored", "-1m", nil, "show ap", DefaultCommandTimeout},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			viper.Set("timeouts.default", c.def)
+			viper.Set("timeouts.commands", c.commands)
+			if got := CommandTimeout(c.path); got != c.want {
+				t.Errorf("CommandTimeout(%q) = %v, want %v", c.path, got, c.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"strings"
+
 	"github.com/ravinald/wifimgr/api"
 	"github.com/ravinald/wifimgr/internal/vendors"
 )
@@ -61,6 +63,9 @@ type ManagedKeys struct {
 	AP      []string `json:"ap,omitempty"`
 	Switch  []string `json:"switch,omitempty"`
 	Gateway []string `json:"gateway,omitempty"`
+	// Ignore holds the diff canonicalization rules per device type; see
+	// package canonical for their forms.
+	Ignore map[string][]any `json:"ignore,omitempty"`
 }
 
 // API represents API configuration settings
@@ -132,11 +137,14 @@ type SiteConfigObjProfiles struct {
 
 // SiteConfigObj represents a site configuration object
 type SiteConfigObj struct {
-	API        string                `json:"api,omitempty"` // API label for multi-vendor support
-	SiteConfig SiteConfig            `json:"site_config"`
-	Profiles   SiteConfigObjProfiles `json:"profiles,omitempty"`
-	WLAN       []string              `json:"wlan,omitempty"` // WLANs to apply to all APs (site-wide default)
-	Devices    Devices               `json:"devices"`
+	API           string                    `json:"api,omitempty"` // API label for multi-vendor support
+	SiteConfig    SiteConfig                `json:"site_config"`
+	Profiles      SiteConfigObjProfiles     `json:"profiles,omitempty"`
+	WLAN          []string                  `json:"wlan,omitempty"`           // WLANs to apply to all APs (site-wide default)
+	WLANDisabled  []string                  `json:"wlan_disabled,omitempty"`  // WLAN labels held disabled at this site
+	WLANOverrides map[string]map[string]any `json:"wlan_overrides,omitempty"` // WLAN label -> fields that differ at this site
+	Firmware      map[string]string         `json:"firmware,omitempty"`       // model -> pinned firmware version
+	Devices       Devices                   `json:"devices"`
 }
 
 // SiteConfigFile represents a site configuration file
@@ -164,7 +172,8 @@ type Display struct {
 	APs       DisplayFormat            `json:"aps"`
 	Inventory DisplayFormat            `json:"inventory"`
 	Commands  map[string]CommandFormat `json:"commands"`
-	Sort      *SortConfig              `json:"sort,omitempty"` // Optional custom sort for search output
+	Sort      *SortConfig              `json:"sort,omitempty"`   // Optional custom sort for search output
+	Locale    string                   `json:"locale,omitempty"` // CLI message language, e.g. "es"; see internal/i18n
 }
 
 // SortConfig lets operators override the default AP/switch name sort used
@@ -203,13 +212,45 @@ type CommandFormat struct {
 
 // SiteConfig represents a site configuration
 type SiteConfig struct {
-	Name        string      `json:"name"`
-	Address     string      `json:"address"`
-	CountryCode string      `json:"country_code"`
-	Timezone    string      `json:"timezone"`
-	Notes       string      `json:"notes"`
-	LatLng      *api.LatLng `json:"latlng"`
-	API         string      `json:"api,omitempty"` // API label for multi-vendor support
+	Name        string       `json:"name"`
+	Address     string       `json:"address"`
+	CountryCode string       `json:"country_code"`
+	Timezone    string       `json:"timezone"`
+	Notes       string       `json:"notes"`
+	LatLng      *api.LatLng  `json:"latlng"`
+	API         string       `json:"api,omitempty"`     // API label for multi-vendor support
+	Contact     *SiteContact `json:"contact,omitempty"` // who to call; intent only, never sent to the vendor
+}
+
+// SiteContact is who to call when a site is down: its owner, a phone number,
+// and the on-call group to escalate to.
+type SiteContact struct {
+	Owner      string `json:"owner,omitempty"`
+	Phone      string `json:"phone,omitempty"`
+	Escalation string `json:"escalation,omitempty"` // on-call group or rotation
+}
+
+// String renders the contact on one line, e.g.
+// "Jane Doe, +1 555 0100 (escalate: netops-oncall)". Empty parts are left out.
+func (c *SiteContact) String() string {
+	if c == nil {
+		return ""
+	}
+	var parts []string
+	if c.Owner != "" {
+		parts = append(parts, c.Owner)
+	}
+	if c.Phone != "" {
+		parts = append(parts, c.Phone)
+	}
+	out := strings.Join(parts, ", ")
+	if c.Escalation != "" {
+		if out != "" {
+			out += " "
+		}
+		out += "(escalate: " + c.Escalation + ")"
+	}
+	return out
 }
 
 // APConfig represents an AP configuration.
@@ -305,11 +346,15 @@ type BandCfg struct {
 
 // IPConfig represents IP configuration for a device
 type IPConfig struct {
-	Type    string   `json:"type,omitempty"`
-	IP      string   `json:"ip,omitempty"`
-	Netmask string   `json:"netmask,omitempty"`
-	Gateway string   `json:"gateway,omitempty"`
-	DNS     []string `json:"dns,omitempty"`
+	Type     string   `json:"type,omitempty"`
+	IP       string   `json:"ip,omitempty"`
+	Netmask  string   `json:"netmask,omitempty"`
+	Gateway  string   `json:"gateway,omitempty"`
+	DNS      []string `json:"dns,omitempty"`
+	Type6    string   `json:"type6,omitempty"` // "autoconf", "dhcp", "static", "disabled"
+	IP6      string   `json:"ip6,omitempty"`
+	Netmask6 string   `json:"netmask6,omitempty"` // e.g. "/64"
+	Gateway6 string   `json:"gateway6,omitempty"`
 }
 
 // MeshConfig represents mesh settings for an AP
@@ -394,6 +439,8 @@ type Network struct {
 	VlanID      int      `json:"vlan_id"`
 	Subnet      string   `json:"subnet,omitempty"`
 	Gateway     string   `json:"gateway,omitempty"`
+	Subnet6     string   `json:"subnet6,omitempty"`
+	Gateway6    string   `json:"gateway6,omitempty"`
 	DHCPEnabled bool     `json:"dhcp_enabled,omitempty"`
 	DHCPRelay   []string `json:"dhcp_relay,omitempty"`
 }
@@ -405,6 +452,8 @@ type OtherIPConfig struct {
 	Netmask   string `json:"netmask"`
 	VlanID    int    `json:"vlan_id,omitempty"`
 	Subnet    string `json:"subnet,omitempty"`
+	IP6       string `json:"ip6,omitempty"`
+	Netmask6  string `json:"netmask6,omitempty"`
 }
 
 // Route represents a static or aggregate route
@@ -10,6 +10,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	"github.com/ravinald/wifimgr/internal/helpers"
 	"github.com/ravinald/wifimgr/internal/xdg"
 )
 
@@ -46,7 +47,7 @@ func GetSiteConfigFullPath(siteName string) (string, bool) {
 		configDir = xdg.GetConfigDir()
 	}
 
-	return filepath.Join(configDir, relativePath), true
+	return xdg.Resolve(configDir, relativePath), true
 }
 
 // GetSiteConfigKey returns the actual key used in the config file for a site name
@@ -136,6 +137,8 @@ func LoadViperConfig(configFile string) error {
 		}
 	}
 
+	expandFilePaths()
+
 	// Validate version
 	version := viper.GetFloat64("version")
 	if version != 1.0 {
@@ -159,6 +162,48 @@ func LoadViperConfig(configFile string) error {
 	return nil
 }
 
+// filePathKeys are the files.* settings that name a single path.
+var filePathKeys = []string{
+	"files.config_dir", "files.cache_dir", "files.cache",
+	"files.inventory", "files.log_file", "files.schemas", "files.backup_dir",
+}
+
+// BackupDir returns the directory for site config backups: files.backup_dir,
+// or backups under the state directory.
+func BackupDir() string {
+	if dir := viper.GetString("files.backup_dir"); dir != "" {
+		return dir
+	}
+	return xdg.GetBackupsDir()
+}
+
+// ConfigureWriteRetry applies files.write_attempts and
+// files.write_retry_delay to the atomic writes of the cache, backups, and
+// state files.
+func ConfigureWriteRetry() {
+	attempts := helpers.DefaultWriteAttempts
+	if viper.IsSet("files.write_attempts") {
+		attempts = viper.GetInt("files.write_attempts")
+	}
+	delay := helpers.DefaultWriteRetryDelay
+	if s := viper.GetString("files.write_retry_delay"); s != "" {
+		if d, ok := parseTimeout("files.write_retry_delay", s); ok {
+			delay = d
+		}
+	}
+	helpers.ConfigureWriteRetry(attempts, delay)
+}
+
+// expandFilePaths expands ~ and environment variables in the files.* paths,
+// so one config works on POSIX and Windows (e.g. "%LOCALAPPDATA%\wifimgr").
+func expandFilePaths() {
+	for _, key := range filePathKeys {
+		if v := viper.GetString(key); v != "" {
+			viper.Set(key, xdg.ExpandPath(v))
+		}
+	}
+}
+
 // LoadAllConfigsViper loads main config via Viper and site configs via existing logic
 func LoadAllConfigsViper(configFile string) ([]*SiteConfigFile, error) {
 	// Load main config using Viper
@@ -167,6 +212,11 @@ func LoadAllConfigsViper(configFile string) ([]*SiteConfigFile, error) {
 		return nil, fmt.Errorf("failed to load main config with Viper: %w", err)
 	}
 
+	// Scope paths to the tenant before any site config is read from them
+	if err := applyTenant(); err != nil {
+		return nil, err
+	}
+
 	// Create duplicate tracker
 	duplicateTracker := NewDuplicateTracker()
 
@@ -185,7 +235,7 @@ func LoadAllConfigsViper(configFile string) ([]*SiteConfigFile, error) {
 	mainVersion := viper.GetInt("version")
 
 	for _, siteConfigFile := range siteConfigFiles {
-		fullPath := filepath.Join(configDir, siteConfigFile)
+		fullPath := xdg.Resolve(configDir, siteConfigFile)
 
 		// Read the raw file data for line number estimation
 		rawData, err := os.ReadFile(fullPath) // #nosec G304 -- path from operator-controlled config
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"sort"
+)
+
+// WLANOverridesField is the site-level map of WLAN template label to the
+// fields that differ at that site (a branch on another VLAN, say). Each
+// override is merged over the template after vendor expansion, so one shared
+// template can carry per-site exceptions without being forked.
+const WLANOverridesField = "wlan_overrides"
+
+// ApplyWLANOverride deep-merges a site's override for one WLAN over its
+// expanded template and returns the result. The override may carry its own
+// mist:/meraki: blocks; they are expanded for vendor the same way as the
+// template's. A nil override returns expanded unchanged.
+func ApplyWLANOverride(expanded, override map[string]any, vendor string) map[string]any {
+	if len(override) == 0 {
+		return expanded
+	}
+	return mergeConfigs(expanded, ExpandForVendor(override, vendor))
+}
+
+// WLANOverridePaths lists the leaf fields an override sets as dotted paths
+// ("vlan_id", "auth.psk", "mist:.roam_mode"), sorted, so lint and diffs can
+// name every per-site exception.
+func WLANOverridePaths(override map[string]any) []string {
+	var paths []string
+	var walk func(prefix string, m map[string]any)
+	walk = func(prefix string, m map[string]any) {
+		for k, v := range m {
+			path := prefix + k
+			if child, ok := v.(map[string]any); ok && len(child) > 0 {
+				walk(path+".", child)
+				continue
+			}
+			paths = append(paths, path)
+		}
+	}
+	walk("", override)
+	sort.Strings(paths)
+	return paths
+}
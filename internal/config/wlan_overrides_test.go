@@ -0,0 +1,63 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestApplyWLANOverride(t *testing.T) {
+	template := map[string]any{
+		"ssid":    "Corp",
+		"vlan_id": 100,
+		"auth":    map[string]any{"type": "psk", "psk": "shared"},
+		"mist:":   map[string]any{"roam_mode": "11r"},
+	}
+	override := map[string]any{
+		"vlan_id": 240,
+		"auth":    map[string]any{"psk": "branch"},
+		"meraki:": map[string]any{"vlan_tagging": true},
+	}
+
+	got := ApplyWLANOverride(ExpandForVendor(template, "mist"), override, "mist")
+	want := map[string]any{
+		"ssid":      "Corp",
+		"vlan_id":   240,
+		"auth":      map[string]any{"type": "psk", "psk": "branch"},
+		"roam_mode": "11r",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("mist = %v, want %v", got, want)
+	}
+	if got := ApplyWLANOverride(ExpandForVendor(template, "meraki"), override, "meraki"); got["vlan_tagging"] != true {
+		t.Errorf("meraki block of the override should apply: %v", got)
+	}
+	if template["vlan_id"] != 100 {
+		t.Error("override must not modify the template")
+	}
+
+	paths := WLANOverridePaths(override)
+	if want := []string{"auth.psk", "meraki:.vlan_tagging", "vlan_id"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("paths = %v, want %v", paths, want)
+	}
+}
+
+func TestWalkTemplateRefs_WLANOverrides(t *testing.T) {
+	raw := map[string]any{"config": map[string]any{"sites": map[string]any{
+		"US-BR-07": map[string]any{
+			WLANOverridesField: map[string]any{"corp": map[string]any{"vlan_id": 240}},
+		},
+	}}}
+
+	var refs []TemplateRef
+	walkTemplateRefs(raw, func(ref TemplateRef, set func(string)) {
+		refs = append(refs, ref)
+		set("corp-v2")
+	})
+	if len(refs) != 1 || refs[0].Field != WLANOverridesField || refs[0].Label != "corp" || refs[0].Kind != TemplateKindWLAN {
+		t.Fatalf("refs = %+v", refs)
+	}
+	overrides := raw["config"].(map[string]any)["sites"].(map[string]any)["US-BR-07"].(map[string]any)[WLANOverridesField].(map[string]any)
+	if _, ok := overrides["corp-v2"]; !ok || len(overrides) != 1 {
+		t.Errorf("rename should move the override key, got %v", overrides)
+	}
+}
@@ -0,0 +1,180 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// WLANDisabledField is the site-level list of WLAN labels held disabled at
+// that site whatever the template's enabled value, so one shared template
+// can be switched off at some sites and left on at the rest.
+const WLANDisabledField = "wlan_disabled"
+
+// WLANToggle is a planned change to the sites holding a WLAN disabled,
+// rewritten in memory and not yet written to disk.
+type WLANToggle struct {
+	Label  string
+	Enable bool
+	Sites  []WLANToggleSite
+
+	files map[string]renamedFile // full path -> contents
+}
+
+// WLANToggleSite is one requested site and what the toggle does to it.
+type WLANToggleSite struct {
+	Site    string `json:"site"`     // site_config.name, else SiteKey
+	SiteKey string `json:"site_key"` // key under config.sites
+	File    string `json:"file"`     // site config file, as listed in config
+	Used    bool   `json:"used"`     // the site references the WLAN
+	Changed bool   `json:"changed"`  // wlan_disabled is rewritten
+}
+
+// PlanWLANToggle prepares enabling or disabling the WLAN template label at
+// the named sites (matched case-insensitively against site_config.name or the
+// site key) in the given site config files. A site that does not reference the
+// WLAN is reported with Used false and left unchanged. It is an error for a
+// requested site to be missing from every file.
+func PlanWLANToggle(siteFiles []string, configDir, label string, sites []string, enable bool) (*WLANToggle, error) {
+	if label == "" {
+		return nil, fmt.Errorf("WLAN label must not be empty")
+	}
+	wanted := make(map[string]bool, len(sites))
+	for _, s := range sites {
+		wanted[strings.ToLower(s)] = true
+	}
+
+	plan := &WLANToggle{Label: label, Enable: enable, files: make(map[string]renamedFile)}
+	found := make(map[string]bool)
+	seen := make(map[string]bool)
+	for _, file := range siteFiles {
+		full := resolveConfigPath(configDir, file)
+		if seen[full] {
+			continue
+		}
+		seen[full] = true
+		data, err := os.ReadFile(full) // #nosec G304 -- path from operator-controlled config
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", full, err)
+		}
+		var raw map[string]any
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", full, err)
+		}
+
+		used := make(map[string]bool) // site key -> references label
+		walkTemplateRefs(raw, func(ref TemplateRef, _ func(string)) {
+			if ref.Kind == TemplateKindWLAN && ref.Label == label &&
+				ref.Field != WLANDisabledField && ref.Field != WLANOverridesField {
+				used[ref.SiteKey] = true
+			}
+		})
+
+		cfg, _ := raw["config"].(map[string]any)
+		siteMap, _ := cfg["sites"].(map[string]any)
+		changed := false
+		for _, siteKey := range sortedKeys(siteMap) {
+			site, ok := siteMap[siteKey].(map[string]any)
+			if !ok {
+				continue
+			}
+			name := siteKey
+			if sc, ok := site["site_config"].(map[string]any); ok {
+				if n, ok := sc["name"].(string); ok && n != "" {
+					name = n
+				}
+			}
+			key := strings.ToLower(name)
+			if !wanted[key] {
+				key = strings.ToLower(siteKey)
+				if !wanted[key] {
+					continue
+				}
+			}
+			found[key] = true
+
+			entry := WLANToggleSite{Site: name, SiteKey: siteKey, File: file, Used: used[siteKey]}
+			if entry.Used {
+				entry.Changed = setWLANDisabled(site, label, !enable)
+				changed = changed || entry.Changed
+			}
+			plan.Sites = append(plan.Sites, entry)
+		}
+		if !changed {
+			continue
+		}
+		out, err := json.MarshalIndent(raw, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %s: %w", full, err)
+		}
+		perm := os.FileMode(0600)
+		if info, err := os.Stat(full); err == nil {
+			perm = info.Mode().Perm()
+		}
+		plan.files[full] = renamedFile{original: data, updated: append(out, '\n'), perm: perm}
+	}
+
+	var missing []string
+	for _, s := range sites {
+		if !found[strings.ToLower(s)] {
+			missing = append(missing, s)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("site(s) not found in site configs: %s", strings.Join(missing, ", "))
+	}
+	return plan, nil
+}
+
+// setWLANDisabled adds label to (disabled) or removes it from the site's
+// wlan_disabled list, dropping the field once empty. It reports whether the
+// list changed.
+func setWLANDisabled(site map[string]any, label string, disabled bool) bool {
+	var labels []string
+	if list, ok := site[WLANDisabledField].([]any); ok {
+		for _, v := range list {
+			if s, ok := v.(string); ok {
+				labels = append(labels, s)
+			}
+		}
+	}
+	has := slices.Contains(labels, label)
+	switch {
+	case disabled && !has:
+		labels = append(labels, label)
+	case !disabled && has:
+		labels = slices.DeleteFunc(labels, func(s string) bool { return s == label })
+	default:
+		return false
+	}
+	if len(labels) == 0 {
+		delete(site, WLANDisabledField)
+		return true
+	}
+	list := make([]any, len(labels))
+	for i, l := range labels {
+		list[i] = l
+	}
+	site[WLANDisabledField] = list
+	return true
+}
+
+// Files returns the full paths the toggle rewrites, sorted.
+func (t *WLANToggle) Files() []string {
+	out := make([]string, 0, len(t.files))
+	for path := range t.files {
+		out = append(out, path)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Apply writes every rewritten file, restoring the ones already written if a
+// later write fails.
+func (t *WLANToggle) Apply() error {
+	return writeRewrittenFiles(t.files)
+}
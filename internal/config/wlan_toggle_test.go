@@ -0,0 +1,122 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const wlanToggleFixture = `{
+  "version": 1,
+  "config": {
+    "sites": {
+      "sfo": {"site_config": {"name": "US-SFO-01"}, "wlan": ["corp", "guest"]},
+      "nyc": {"site_config": {"name": "US-NYC-02"}, "profiles": {"wlan": ["corp"]}, "wlan_disabled": ["guest"]},
+      "lab": {"site_config": {"name": "US-LAB-01"}, "devices": {"ap": {"aa0000000001": {"wlan": ["guest"]}}}}
+    }
+  }
+}`
+
+func writeWLANToggleFixture(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "sites.json"), []byte(wlanToggleFixture), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestPlanWLANToggleDisable(t *testing.T) {
+	dir := writeWLANToggleFixture(t)
+
+	plan, err := PlanWLANToggle([]string{"sites.json"}, dir, "guest", []string{"us-sfo-01", "US-NYC-02", "lab"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]WLANToggleSite{}
+	for _, s := range plan.Sites {
+		got[s.Site] = s
+	}
+	if s := got["US-SFO-01"]; !s.Used || !s.Changed {
+		t.Errorf("sfo = %+v, want used and changed", s)
+	}
+	if s := got["US-NYC-02"]; s.Used || s.Changed {
+		t.Errorf("nyc does not use guest (wlan_disabled is not a use): %+v", s)
+	}
+	if s := got["US-LAB-01"]; !s.Used || !s.Changed {
+		t.Errorf("lab uses guest on a device and should be matched by site key: %+v", s)
+	}
+
+	if err := plan.Apply(); err != nil {
+		t.Fatal(err)
+	}
+	raw, err := readRawConfigFile(filepath.Join(dir, "sites.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sfo := raw["config"].(map[string]any)["sites"].(map[string]any)["sfo"].(map[string]any)
+	if list, _ := sfo[WLANDisabledField].([]any); len(list) != 1 || list[0] != "guest" {
+		t.Errorf("sfo wlan_disabled = %v", sfo[WLANDisabledField])
+	}
+}
+
+func TestPlanWLANToggleEnableRemovesField(t *testing.T) {
+	dir := writeWLANToggleFixture(t)
+
+	plan, err := PlanWLANToggle([]string{"sites.json"}, dir, "corp", []string{"US-NYC-02"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := plan.Apply(); err != nil {
+		t.Fatal(err)
+	}
+	plan, err = PlanWLANToggle([]string{"sites.json"}, dir, "corp", []string{"US-NYC-02"}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Files()) != 1 || !plan.Sites[0].Changed {
+		t.Fatalf("enable should rewrite nyc: %+v", plan.Sites)
+	}
+	if err := plan.Apply(); err != nil {
+		t.Fatal(err)
+	}
+	raw, err := readRawConfigFile(filepath.Join(dir, "sites.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	nyc := raw["config"].(map[string]any)["sites"].(map[string]any)["nyc"].(map[string]any)
+	if list, _ := nyc[WLANDisabledField].([]any); len(list) != 1 || list[0] != "guest" {
+		t.Errorf("enabling corp should leave only guest disabled, got %v", nyc[WLANDisabledField])
+	}
+
+	// Enabling again is a no-op.
+	plan, err = PlanWLANToggle([]string{"sites.json"}, dir, "corp", []string{"US-NYC-02"}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Files()) != 0 || plan.Sites[0].Changed {
+		t.Errorf("second enable should change nothing: %+v", plan.Sites)
+	}
+}
+
+func TestPlanWLANToggleUnknownSite(t *testing.T) {
+	dir := writeWLANToggleFixture(t)
+	_, err := PlanWLANToggle([]string{"sites.json"}, dir, "guest", []string{"US-SFO-01", "US-XXX-99"}, false)
+	if err == nil || !strings.Contains(err.Error(), "US-XXX-99") {
+		t.Errorf("expected missing-site error, got %v", err)
+	}
+}
+
+func TestSetWLANDisabled(t *testing.T) {
+	site := map[string]any{}
+	if !setWLANDisabled(site, "guest", true) || setWLANDisabled(site, "guest", true) {
+		t.Fatal("first disable should change, second should not")
+	}
+	if !setWLANDisabled(site, "guest", false) {
+		t.Fatal("enable should change")
+	}
+	if _, ok := site[WLANDisabledField]; ok {
+		t.Errorf("empty wlan_disabled should be removed: %v", site)
+	}
+}
@@ -0,0 +1,233 @@
+// Package consistency cross-checks device names between the vendor API,
+// NetBox, and DNS, so drift between the three can be listed and converged.
+package consistency
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Device is one cached device to check, named as the vendor API has it.
+type Device struct {
+	MAC    string `json:"mac"`
+	Serial string `json:"serial,omitempty"`
+	Type   string `json:"type"`
+	Site   string `json:"site,omitempty"`
+	API    string `json:"api"`
+	Name   string `json:"name"`
+	IP     string `json:"ip,omitempty"` // management IP from the status cache
+}
+
+// Result is a device with what NetBox and DNS call it. NetBox and DNS are
+// empty when that source has no entry or was not checked; Issues is empty
+// when every checked source agrees.
+type Result struct {
+	Device
+	NetBox string   `json:"netbox,omitempty"`
+	DNS    string   `json:"dns,omitempty"`
+	Issues []string `json:"issues,omitempty"`
+}
+
+// NetBoxLookup returns the NetBox name for d, or "" with a nil error when
+// NetBox has no device for it.
+type NetBoxLookup func(ctx context.Context, d Device) (string, error)
+
+// Resolver is the part of *net.Resolver the DNS check uses.
+type Resolver interface {
+	LookupAddr(ctx context.Context, addr string) ([]string, error)
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// DNSConfig is the consistency.dns section. DNS is checked when Domain or
+// Server is set.
+type DNSConfig struct {
+	Domain string // zone device names live in, e.g. "wifi.example.com"
+	Server string // resolver address (host or host:port); empty uses the system resolver
+}
+
+// LoadDNSConfig reads consistency.dns from viper. It returns nil when the
+// section is not configured.
+func LoadDNSConfig() *DNSConfig {
+	cfg := &DNSConfig{
+		Domain: strings.Trim(strings.ToLower(viper.GetString("consistency.dns.domain")), "."),
+		Server: viper.GetString("consistency.dns.server"),
+	}
+	if cfg.Domain == "" && cfg.Server == "" {
+		return nil
+	}
+	return cfg
+}
+
+// Resolver returns a resolver that queries Server, or the system resolver.
+func (c *DNSConfig) Resolver() Resolver {
+	if c.Server == "" {
+		return net.DefaultResolver
+	}
+	server := c.Server
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "53")
+	}
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			d := net.Dialer{Timeout: 5 * time.Second}
+			return d.DialContext(ctx, network, server)
+		},
+	}
+}
+
+// Checker compares each device's API name against the configured sources.
+// A nil NetBox or DNS skips that source.
+type Checker struct {
+	NetBox      NetBoxLookup
+	DNS         Resolver
+	Domain      string
+	Concurrency int // parallel lookups; 0 means 8
+}
+
+// Check looks every device up and returns the results in input order.
+func (c *Checker) Check(ctx context.Context, devices []Device) []Result {
+	workers := c.Concurrency
+	if workers <= 0 {
+		workers = 8
+	}
+	results := make([]Result, len(devices))
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for i, d := range devices {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, d Device) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = c.checkOne(ctx, d)
+		}(i, d)
+	}
+	wg.Wait()
+	return results
+}
+
+func (c *Checker) checkOne(ctx context.Context, d Device) Result {
+	r := Result{Device: d}
+	if d.Name == "" {
+		r.Issues = append(r.Issues, "unnamed in API")
+	}
+
+	if c.NetBox != nil {
+		name, err := c.NetBox(ctx, d)
+		switch {
+		case err != nil:
+			r.Issues = append(r.Issues, fmt.Sprintf("NetBox lookup failed: %v", err))
+		case name == "":
+			r.Issues = append(r.Issues, "not in NetBox")
+		default:
+			r.NetBox = name
+			if d.Name != "" && !strings.EqualFold(name, d.Name) {
+				r.Issues = append(r.Issues, "NetBox name differs")
+			}
+		}
+	}
+
+	if c.DNS != nil {
+		r.DNS, r.Issues = c.checkDNS(ctx, d, r.Issues)
+	}
+	return r
+}
+
+// checkDNS compares the PTR record of the device's IP with its API name and,
+// when a domain is configured, checks that <name>.<domain> resolves to that
+// IP. It returns the PTR host (domain stripped) and the updated issues.
+func (c *Checker) checkDNS(ctx context.Context, d Device, issues []string) (string, []string) {
+	if d.IP == "" {
+		return "", issues
+	}
+	var ptr string
+	names, err := c.DNS.LookupAddr(ctx, d.IP)
+	if err != nil || len(names) == 0 {
+		issues = append(issues, fmt.Sprintf("no PTR for %s", d.IP))
+	} else {
+		ptr = c.shortName(names[0])
+		if d.Name != "" && ptr != HostLabel(d.Name) {
+			issues = append(issues, "PTR name differs")
+		}
+	}
+
+	if c.Domain != "" && d.Name != "" {
+		fqdn := HostLabel(d.Name) + "." + c.Domain
+		addrs, err := c.DNS.LookupHost(ctx, fqdn)
+		switch {
+		case err != nil || len(addrs) == 0:
+			issues = append(issues, fmt.Sprintf("no A/AAAA for %s", fqdn))
+		case !containsIP(addrs, d.IP):
+			issues = append(issues, fmt.Sprintf("%s resolves to %s", fqdn, strings.Join(addrs, ", ")))
+		}
+	}
+	return ptr, issues
+}
+
+// shortName strips the trailing dot and the configured domain from a PTR
+// target; without a domain only the first label is kept.
+func (c *Checker) shortName(host string) string {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if c.Domain != "" {
+		if short, ok := strings.CutSuffix(host, "."+c.Domain); ok {
+			return short
+		}
+		return host
+	}
+	label, _, _ := strings.Cut(host, ".")
+	return label
+}
+
+func containsIP(addrs []string, ip string) bool {
+	want := net.ParseIP(ip)
+	for _, a := range addrs {
+		if got := net.ParseIP(a); got != nil && got.Equal(want) {
+			return true
+		}
+	}
+	return false
+}
+
+// HostLabel turns a device name into the DNS label it is expected under:
+// lowercased, with every character outside [a-z0-9-] replaced by '-'.
+func HostLabel(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(name)) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('-')
+		}
+	}
+	return strings.Trim(b.String(), "-")
+}
+
+// Mismatches keeps the results with at least one issue, sorted by site,
+// then name, then MAC.
+func Mismatches(results []Result) []Result {
+	var out []Result
+	for _, r := range results {
+		if len(r.Issues) > 0 {
+			out = append(out, r)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.Site != b.Site {
+			return a.Site < b.Site
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.MAC < b.MAC
+	})
+	return out
+}
@@ -0,0 +1,90 @@
+package consistency
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type fakeResolver struct {
+	ptr   map[string][]string
+	hosts map[string][]string
+}
+
+func (f fakeResolver) LookupAddr(_ context.Context, addr string) ([]string, error) {
+	if names, ok := f.ptr[addr]; ok {
+		return names, nil
+	}
+	return nil, errors.New("no such host")
+}
+
+func (f fakeResolver) LookupHost(_ context.Context, host string) ([]string, error) {
+	if addrs, ok := f.hosts[host]; ok {
+		return addrs, nil
+	}
+	return nil, errors.New("no such host")
+}
+
+func TestCheck(t *testing.T) {
+	netboxNames := map[string]string{
+		"aa0000000001": "US-LAB-01-AP-Lobby",
+		"aa0000000002": "ap-old-name",
+	}
+	checker := &Checker{
+		NetBox: func(_ context.Context, d Device) (string, error) {
+			return netboxNames[d.MAC], nil
+		},
+		DNS: fakeResolver{
+			ptr: map[string][]string{
+				"10.0.0.1": {"us-lab-01-ap-lobby.wifi.example.com."},
+				"10.0.0.2": {"ap-old-name.wifi.example.com."},
+			},
+			hosts: map[string][]string{
+				"us-lab-01-ap-lobby.wifi.example.com": {"10.0.0.1"},
+				"us-lab-01-ap-hall.wifi.example.com":  {"10.0.0.9"},
+			},
+		},
+		Domain: "wifi.example.com",
+	}
+
+	results := checker.Check(context.Background(), []Device{
+		{MAC: "aa0000000001", Name: "US-LAB-01-AP-Lobby", IP: "10.0.0.1"},
+		{MAC: "aa0000000002", Name: "US-LAB-01-AP-Hall", IP: "10.0.0.2"},
+		{MAC: "aa0000000003", Name: "", IP: ""},
+	})
+
+	if len(results[0].Issues) != 0 {
+		t.Errorf("matching device reported %v", results[0].Issues)
+	}
+	if results[0].DNS != "us-lab-01-ap-lobby" {
+		t.Errorf("DNS = %q, want the PTR without the domain", results[0].DNS)
+	}
+
+	want := []string{"NetBox name differs", "PTR name differs", "us-lab-01-ap-hall.wifi.example.com resolves to 10.0.0.9"}
+	if !reflect.DeepEqual(results[1].Issues, want) {
+		t.Errorf("hall issues = %v, want %v", results[1].Issues, want)
+	}
+
+	// No IP: DNS is skipped rather than reported.
+	if want := []string{"unnamed in API", "not in NetBox"}; !reflect.DeepEqual(results[2].Issues, want) {
+		t.Errorf("unnamed issues = %v, want %v", results[2].Issues, want)
+	}
+
+	if got := Mismatches(results); len(got) != 2 || got[0].MAC != "aa0000000003" {
+		t.Errorf("Mismatches = %+v", got)
+	}
+}
+
+func TestHostLabel(t *testing.T) {
+	cases := map[string]string{
+		"US-LAB-01-AP-Lobby": "us-lab-01-ap-lobby",
+		"ap lobby_2":         "ap-lobby-2",
+		" -edge- ":           "edge",
+	}
+	for in, want := range cases {
+		if got := HostLabel(in); got != want {
+			t.Errorf("HostLabel(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
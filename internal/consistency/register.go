@@ -0,0 +1,53 @@
+package consistency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/ravinald/wifimgr/internal/integrations"
+)
+
+func init() {
+	integrations.Register(integrations.Integration{
+		Name:         "dns",
+		ConfigKey:    "consistency.dns",
+		EndpointKeys: "consistency.dns.server",
+		Configured:   func() bool { return LoadDNSConfig() != nil },
+		Check:        checkDNS,
+		Hint: func(err error) string {
+			var dnsErr *net.DNSError
+			if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+				return fmt.Sprintf("the server has no zone %s; check consistency.dns.domain", dnsErr.Name)
+			}
+			return ""
+		},
+	})
+}
+
+// checkDNS asks the configured server for the NS records of the zone, or of
+// the root when only a server is set.
+func checkDNS(ctx context.Context) (string, error) {
+	cfg := LoadDNSConfig()
+	if cfg == nil {
+		return "", fmt.Errorf("consistency.dns is not configured")
+	}
+	resolver, ok := cfg.Resolver().(*net.Resolver)
+	if !ok {
+		return "", fmt.Errorf("unexpected resolver type %T", cfg.Resolver())
+	}
+	server := cfg.Server
+	if server == "" {
+		server = "system resolver"
+	}
+	zone := cfg.Domain
+	if zone == "" {
+		zone = "."
+	}
+	ns, err := resolver.LookupNS(ctx, zone)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s answers for %s (%d NS)", server, zone, len(ns)), nil
+}
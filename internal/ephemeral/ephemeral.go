@@ -0,0 +1,98 @@
+// Package ephemeral records the temporary test SSIDs created by
+// 'wlan test-deploy', so they can be removed when their TTL runs out and kept
+// out of imports in the meantime. The record is the tag: vendors have no
+// field to mark a WLAN as temporary.
+package ephemeral
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/ravinald/wifimgr/internal/helpers"
+	"github.com/ravinald/wifimgr/internal/xdg"
+)
+
+// MaxTTL bounds a test SSID's lifetime, so a typo in --ttl cannot leave one
+// broadcasting for months.
+const MaxTTL = 7 * 24 * time.Hour
+
+// WLAN is one test SSID created at a site.
+type WLAN struct {
+	API      string    `json:"api"`
+	SiteID   string    `json:"site_id"`
+	Site     string    `json:"site"`
+	ID       string    `json:"id"` // vendor WLAN ID
+	SSID     string    `json:"ssid"`
+	Template string    `json:"template"`
+	Created  time.Time `json:"created"`
+	Expires  time.Time `json:"expires"`
+}
+
+// Expired reports whether the WLAN's TTL has run out at now.
+func (w WLAN) Expired(now time.Time) bool {
+	return !now.Before(w.Expires)
+}
+
+// statePath is where the test SSIDs are recorded; tests override it.
+var statePath = func() string { return filepath.Join(xdg.GetStateDir(), "ephemeral-wlans.json") }
+
+// Load reads the recorded test SSIDs, soonest to expire first; a missing file
+// is none.
+func Load() ([]WLAN, error) {
+	data, err := os.ReadFile(statePath()) // #nosec G304 -- fixed name under the state dir
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var wlans []WLAN
+	if err := json.Unmarshal(data, &wlans); err != nil {
+		return nil, fmt.Errorf("invalid ephemeral WLAN state %s: %w", statePath(), err)
+	}
+	sort.SliceStable(wlans, func(i, j int) bool { return wlans[i].Expires.Before(wlans[j].Expires) })
+	return wlans, nil
+}
+
+// Save writes the recorded test SSIDs.
+func Save(wlans []WLAN) error {
+	if wlans == nil {
+		wlans = []WLAN{}
+	}
+	data, err := json.MarshalIndent(wlans, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(statePath()), 0o700); err != nil {
+		return err
+	}
+	return helpers.WriteFileAtomic(statePath(), data, 0o600)
+}
+
+// Add records a new test SSID.
+func Add(w WLAN) error {
+	wlans, err := Load()
+	if err != nil {
+		return err
+	}
+	return Save(append(wlans, w))
+}
+
+// IDs returns the vendor IDs of the recorded test SSIDs, for leaving them
+// out of imports. A state that cannot be read yields none.
+func IDs() map[string]bool {
+	wlans, err := Load()
+	if err != nil {
+		return nil
+	}
+	ids := make(map[string]bool, len(wlans))
+	for _, w := range wlans {
+		ids[w.ID] = true
+	}
+	return ids
+}
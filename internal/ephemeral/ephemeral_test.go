@@ -0,0 +1,44 @@
+package ephemeral
+
+import (
+	"testing"
+	"time"
+)
+
+func useTempState(t *testing.T) {
+	t.Helper()
+	orig := statePath
+	path := t.TempDir() + "/ephemeral-wlans.json"
+	statePath = func() string { return path }
+	t.Cleanup(func() { statePath = orig })
+}
+
+func TestAddAndLoad(t *testing.T) {
+	useTempState(t)
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	if wlans, err := Load(); err != nil || len(wlans) != 0 {
+		t.Fatalf("missing state = %v, %v", wlans, err)
+	}
+	for _, w := range []WLAN{
+		{ID: "w-late", SSID: "corp-test", Expires: now.Add(4 * time.Hour)},
+		{ID: "w-soon", SSID: "guest-test", Expires: now.Add(time.Hour)},
+	} {
+		if err := Add(w); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wlans, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(wlans) != 2 || wlans[0].ID != "w-soon" {
+		t.Errorf("want soonest first, got %+v", wlans)
+	}
+	if ids := IDs(); !ids["w-late"] || !ids["w-soon"] {
+		t.Errorf("IDs = %v", ids)
+	}
+	if wlans[0].Expired(now) || !wlans[0].Expired(now.Add(time.Hour)) {
+		t.Error("a WLAN expires exactly at its expiry time")
+	}
+}
@@ -0,0 +1,211 @@
+// Package filterexpr implements the small row-filter language used by the
+// `filter` keyword on show commands, and resolves named filters saved under the
+// top-level "filters" config map.
+//
+// An expression is one or more clauses joined by && and || (&& binds tighter):
+//
+//	type=ap && status!=online
+//	model~AP4 || name=lab-*
+//
+// Operators: = (equals, * wildcards allowed), != (not equals), ~ (contains),
+// !~ (does not contain). Comparisons are case-insensitive; a field missing
+// from the row compares as the empty string.
+//
+// MAC fields ("mac" and names ending in "_mac") compare = and != as MACs,
+// whatever the separators on either side, and accept a prefix or a range:
+//
+//	mac=5c:5b:35:*
+//	mac=5c5b35000010..5c5b35000040
+package filterexpr
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+
+	"github.com/ravinald/wifimgr/internal/macaddr"
+)
+
+// Expr is a parsed filter: an OR of AND-groups of clauses.
+type Expr struct {
+	source string
+	groups [][]clause
+}
+
+type clause struct {
+	field string
+	op    string
+	value string
+	mac   *macaddr.Pattern // set for = and != on a MAC field with a MAC value
+}
+
+// operators in match-priority order: two-character operators are tried first
+// so "!=" is not read as "!" + "=".
+var operators = []string{"!=", "!~", "=", "~"}
+
+// Parse compiles an expression.
+func Parse(s string) (*Expr, error) {
+	src := strings.TrimSpace(s)
+	if src == "" {
+		return nil, fmt.Errorf("empty filter expression")
+	}
+	e := &Expr{source: src}
+	for _, orPart := range strings.Split(src, "||") {
+		var group []clause
+		for _, andPart := range strings.Split(orPart, "&&") {
+			c, err := parseClause(andPart)
+			if err != nil {
+				return nil, fmt.Errorf("filter %q: %w", src, err)
+			}
+			group = append(group, c)
+		}
+		e.groups = append(e.groups, group)
+	}
+	return e, nil
+}
+
+func parseClause(s string) (clause, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return clause{}, fmt.Errorf("empty clause")
+	}
+	for i := 0; i < len(s); i++ {
+		for _, op := range operators {
+			if !strings.HasPrefix(s[i:], op) {
+				continue
+			}
+			field := strings.ToLower(strings.TrimSpace(s[:i]))
+			if field == "" || strings.ContainsAny(field, " \t") {
+				return clause{}, fmt.Errorf("invalid field in %q", s)
+			}
+			value := strings.TrimSpace(s[i+len(op):])
+			value = strings.Trim(value, `"'`)
+			c := clause{field: field, op: op, value: strings.ToLower(value)}
+			if isMACField(field) && (op == "=" || op == "!=") && value != "" {
+				// A malformed range is an error; anything else that is not a
+				// MAC pattern (e.g. "5c:5b:*:01") falls back to a glob.
+				p, err := macaddr.ParsePattern(value)
+				if err != nil && strings.Contains(value, "..") {
+					return clause{}, err
+				}
+				c.mac = p
+			}
+			return c, nil
+		}
+	}
+	return clause{}, fmt.Errorf("clause %q has no operator (use =, !=, ~, !~)", s)
+}
+
+// String returns the source expression.
+func (e *Expr) String() string {
+	return e.source
+}
+
+// Fields returns the fields the expression reads, sorted, each once.
+func (e *Expr) Fields() []string {
+	seen := make(map[string]bool)
+	var fields []string
+	for _, g := range e.groups {
+		for _, c := range g {
+			if !seen[c.field] {
+				seen[c.field] = true
+				fields = append(fields, c.field)
+			}
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// Match reports whether row satisfies the expression.
+func (e *Expr) Match(row map[string]any) bool {
+	for _, g := range e.groups {
+		if matchAll(g, row) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchAll(group []clause, row map[string]any) bool {
+	for _, c := range group {
+		if !c.match(row) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c clause) match(row map[string]any) bool {
+	var actual string
+	if v, ok := row[c.field]; ok && v != nil {
+		actual = strings.ToLower(fmt.Sprint(v))
+	}
+	switch c.op {
+	case "=":
+		if c.mac != nil {
+			return c.mac.Match(actual)
+		}
+		return equalOrGlob(actual, c.value)
+	case "!=":
+		if c.mac != nil {
+			return !c.mac.Match(actual)
+		}
+		return !equalOrGlob(actual, c.value)
+	case "~":
+		return strings.Contains(actual, c.value)
+	case "!~":
+		return !strings.Contains(actual, c.value)
+	}
+	return false
+}
+
+// isMACField reports whether a field holds MAC addresses.
+func isMACField(field string) bool {
+	return field == "mac" || strings.HasSuffix(field, "_mac")
+}
+
+func equalOrGlob(actual, want string) bool {
+	if strings.ContainsAny(want, "*?[") {
+		ok, err := path.Match(want, actual)
+		return err == nil && ok
+	}
+	return actual == want
+}
+
+// Resolve returns the expression text for arg: "@name" looks up
+// filters.<name> in config, anything else is returned unchanged.
+func Resolve(arg string) (string, error) {
+	name, isRef := strings.CutPrefix(strings.TrimSpace(arg), "@")
+	if !isRef {
+		return arg, nil
+	}
+	saved := viper.GetStringMapString("filters")
+	if expr, ok := saved[strings.ToLower(name)]; ok && strings.TrimSpace(expr) != "" {
+		return expr, nil
+	}
+	names := make([]string, 0, len(saved))
+	for n := range saved {
+		if strings.HasPrefix(n, "_") {
+			continue
+		}
+		names = append(names, "@"+n)
+	}
+	sort.Strings(names)
+	if len(names) == 0 {
+		return "", fmt.Errorf("unknown saved filter @%s (no filters defined in config)", name)
+	}
+	return "", fmt.Errorf("unknown saved filter @%s (available: %s)", name, strings.Join(names, ", "))
+}
+
+// Compile resolves a saved-filter reference if needed and parses the result.
+func Compile(arg string) (*Expr, error) {
+	src, err := Resolve(arg)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(src)
+}
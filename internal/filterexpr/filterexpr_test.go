@@ -0,0 +1,125 @@
+package filterexpr
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestMatch(t *testing.T) {
+	row := map[string]any{
+		"name":      "lab-ap-01",
+		"type":      "ap",
+		"status":    "Offline",
+		"model":     "AP45",
+		"site_name": "US-LAB-01",
+		"count":     3,
+	}
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"type=ap", true},
+		{"type=switch", false},
+		{"type=ap && status!=online", true},
+		{"type=ap && status=online", false},
+		{"type=switch || status=offline", true},
+		{"name=lab-*", true},
+		{"name=core-*", false},
+		{"model~ap4", true},
+		{"model!~ap4", false},
+		{"site_name = \"us-lab-01\"", true},
+		{"count=3", true},
+		{"serial=", true},
+		{"serial!=", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			e, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.expr, err)
+			}
+			if got := e.Match(row); got != tt.want {
+				t.Errorf("Match(%q) = %v, want %v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchMAC(t *testing.T) {
+	row := map[string]any{"mac": "5c:5b:35:00:00:2a", "ap_mac": "5c5b35000001", "name": "5c:5b:35"}
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"mac=5c:5b:35:*", true},
+		{"mac=5c5b35*", true},
+		{"mac=5C-5B-36-*", false},
+		{"mac!=5c:5b:35:*", false},
+		{"mac=5c5b.3500.002a", true},
+		{"mac=5c:5b:35:00:00:10..5c:5b:35:00:00:40", true},
+		{"mac=5c:5b:35:00:00:30..5c:5b:35:00:00:40", false},
+		{"ap_mac=5c:5b:35:00:00:01", true},
+		{"mac=5c:5b:*:2a", true}, // not a MAC pattern: plain glob
+		{"name=5c5b35*", false},  // not a MAC field
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			e, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.expr, err)
+			}
+			if got := e.Match(row); got != tt.want {
+				t.Errorf("Match(%q) = %v, want %v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, expr := range []string{"", "type", "=ap", "type=ap &&", "my field=x", "mac=5c5b35000040..5c5b35000010"} {
+		if _, err := Parse(expr); err == nil {
+			t.Errorf("Parse(%q): expected error", expr)
+		}
+	}
+}
+
+func TestFields(t *testing.T) {
+	e, err := Parse("site.country_code=DE || vars.region=emea && site.country_code!=GB")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := e.Fields(), []string{"site.country_code", "vars.region"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Fields() = %v, want %v", got, want)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	viper.Set("filters", map[string]any{
+		"offline-aps":      "type=ap && status!=online",
+		"_comment_filters": "ignored",
+	})
+
+	got, err := Resolve("@offline-aps")
+	if err != nil || got != "type=ap && status!=online" {
+		t.Fatalf("Resolve(@offline-aps) = %q, %v", got, err)
+	}
+
+	if got, err := Resolve("type=ap"); err != nil || got != "type=ap" {
+		t.Errorf("inline expression should pass through, got %q, %v", got, err)
+	}
+
+	_, err = Resolve("@missing")
+	if err == nil {
+		t.Fatal("expected error for unknown saved filter")
+	}
+	if !strings.Contains(err.Error(), "@offline-aps") || strings.Contains(err.Error(), "_comment") {
+		t.Errorf("error should list available filters only: %v", err)
+	}
+}
@@ -0,0 +1,289 @@
+// Package fixtures turns API responses captured from a live org into test
+// fixtures a user can attach to a parsing bug: one file per response plus a
+// manifest recording the request each came from.
+//
+// Secrets are always redacted (common.RedactValue). With sanitization on, the
+// identifying values are also replaced by stable pseudonyms — the same MAC or
+// UUID maps to the same stand-in in every file, so cross-references between
+// payloads (a device's site_id, a status keyed by MAC) still line up.
+package fixtures
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/ravinald/wifimgr/internal/common"
+	"github.com/ravinald/wifimgr/internal/runstats"
+)
+
+// Fixture is one captured response.
+type Fixture struct {
+	Method string
+	Path   string
+	Query  string
+	Status int
+	Body   []byte
+}
+
+// Recorder collects responses handed to it by runstats capture.
+type Recorder struct {
+	mu       sync.Mutex
+	fixtures []Fixture
+}
+
+// Record is a runstats.SetCapture callback.
+func (r *Recorder) Record(ex runstats.Exchange) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fixtures = append(r.fixtures, Fixture{
+		Method: ex.Method,
+		Path:   ex.Path,
+		Query:  ex.Query,
+		Status: ex.Status,
+		Body:   ex.Body,
+	})
+}
+
+// Fixtures returns what has been recorded so far, in request order.
+func (r *Recorder) Fixtures() []Fixture {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Fixture(nil), r.fixtures...)
+}
+
+// siteKeys are the fields that tie an org-wide list entry to a site, across
+// vendors (Mist site_id, Meraki networkId).
+var siteKeys = []string{"site_id", "networkId"}
+
+// TrimToSite drops the entries of a top-level JSON array that carry a site
+// field naming a different site, so an org-wide inventory or status list
+// contributes only the site being exported. Entries without a site field, and
+// bodies that are not arrays, are kept as they are.
+func TrimToSite(body []byte, siteID string) []byte {
+	var list []any
+	if siteID == "" || json.Unmarshal(body, &list) != nil {
+		return body
+	}
+	kept := make([]any, 0, len(list))
+	for _, item := range list {
+		if obj, ok := item.(map[string]any); ok && !belongsTo(obj, siteID) {
+			continue
+		}
+		kept = append(kept, item)
+	}
+	if len(kept) == len(list) {
+		return body
+	}
+	out, err := json.Marshal(kept)
+	if err != nil {
+		return body
+	}
+	return out
+}
+
+func belongsTo(obj map[string]any, siteID string) bool {
+	for _, k := range siteKeys {
+		if v, ok := obj[k].(string); ok && v != "" {
+			return v == siteID
+		}
+	}
+	return true
+}
+
+var (
+	uuidRe     = regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`)
+	macRe      = regexp.MustCompile(`(?i)\b[0-9a-f]{2}([:-])[0-9a-f]{2}(?:[:-][0-9a-f]{2}){4}\b`)
+	bareMACRe  = regexp.MustCompile(`(?i)^[0-9a-f]{12}$`)
+	ipv4Re     = regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b`)
+	testNets   = []string{"192.0.2", "198.51.100", "203.0.113"}
+	zeroedKeys = map[string]bool{"lat": true, "lng": true, "latitude": true, "longitude": true}
+)
+
+// pseudonymKeys are fields whose whole value identifies the customer; they are
+// replaced by "<field>-<n>".
+var pseudonymKeys = map[string]bool{
+	"name": true, "hostname": true, "serial": true, "email": true,
+	"address": true, "notes": true, "ssid": true, "contact": true,
+}
+
+// Sanitizer replaces identifying values with stable pseudonyms. One
+// Sanitizer must be used for every file of an export so the mappings agree.
+type Sanitizer struct {
+	seen map[string]string
+	next map[string]int
+}
+
+// NewSanitizer returns an empty Sanitizer.
+func NewSanitizer() *Sanitizer {
+	return &Sanitizer{seen: make(map[string]string), next: make(map[string]int)}
+}
+
+// pseudonym returns the stand-in for value in class, allocating the next one
+// on first sight.
+func (s *Sanitizer) pseudonym(class, value string, gen func(n int) string) string {
+	key := class + "\x00" + strings.ToLower(value)
+	if p, ok := s.seen[key]; ok {
+		return p
+	}
+	s.next[class]++
+	p := gen(s.next[class])
+	s.seen[key] = p
+	return p
+}
+
+// String pseudonymizes the UUIDs, MACs and IPv4 addresses inside str.
+func (s *Sanitizer) String(str string) string {
+	if bareMACRe.MatchString(str) {
+		return s.pseudonym("mac", str, func(n int) string { return fmt.Sprintf("020000%06x", n) })
+	}
+	str = uuidRe.ReplaceAllStringFunc(str, func(m string) string {
+		return s.pseudonym("uuid", m, func(n int) string { return fmt.Sprintf("00000000-0000-4000-8000-%012d", n) })
+	})
+	str = macRe.ReplaceAllStringFunc(str, func(m string) string {
+		sep := m[2:3]
+		return s.pseudonym("mac", strings.NewReplacer(":", "", "-", "").Replace(m), func(n int) string {
+			return fmt.Sprintf("02%[1]s00%[1]s00%[1]s%02x%[1]s%02x%[1]s%02x", sep, n>>16&0xff, n>>8&0xff, n&0xff)
+		})
+	})
+	return ipv4Re.ReplaceAllStringFunc(str, func(m string) string {
+		return s.pseudonym("ip", m, func(n int) string {
+			return fmt.Sprintf("%s.%d", testNets[(n-1)/254%len(testNets)], (n-1)%254+1)
+		})
+	})
+}
+
+// Value returns a copy of a decoded JSON value with identifying values
+// pseudonymized. Map keys get the same treatment as strings, since some APIs
+// key objects by MAC or ID.
+func (s *Sanitizer) Value(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(t))
+		for k, val := range t {
+			lk := strings.ToLower(k)
+			switch str, isStr := val.(string); {
+			case zeroedKeys[lk]:
+				out[s.String(k)] = 0
+			case pseudonymKeys[lk] && isStr && str != "":
+				out[s.String(k)] = s.pseudonym(lk, str, func(n int) string { return fmt.Sprintf("%s-%d", lk, n) })
+			default:
+				out[s.String(k)] = s.Value(val)
+			}
+		}
+		return out
+	case []any:
+		out := make([]any, len(t))
+		for i, val := range t {
+			out[i] = s.Value(val)
+		}
+		return out
+	case string:
+		return s.String(t)
+	default:
+		return v
+	}
+}
+
+// Clean prepares a response body for writing: secrets redacted, and with s
+// non-nil, identifying values pseudonymized. It returns the indented JSON and
+// true, or — for a body that is not JSON — the body with secret-bearing lines
+// scrubbed and false.
+func Clean(body []byte, s *Sanitizer) ([]byte, bool) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var parsed any
+	if err := dec.Decode(&parsed); err != nil {
+		text := string(body)
+		if s != nil {
+			text = s.String(text)
+		}
+		return []byte(scrubText(text)), false
+	}
+	parsed = common.RedactValue(parsed)
+	if s != nil {
+		parsed = s.Value(parsed)
+	}
+	out, err := json.MarshalIndent(parsed, "", "  ")
+	if err != nil {
+		return body, false
+	}
+	return append(out, '\n'), true
+}
+
+var secretLineRe = regexp.MustCompile(`(?i)(token|password|secret|psk|passphrase|apikey|api_key)(["']?\s*[:=]\s*["']?)[^"'\s,&]+`)
+
+// scrubText blanks key=value and "key": "value" secrets in a non-JSON body.
+func scrubText(text string) string {
+	return secretLineRe.ReplaceAllString(text, "${1}${2}"+common.Redacted)
+}
+
+// ManifestEntry describes one written fixture.
+type ManifestEntry struct {
+	File   string `json:"file"`
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Query  string `json:"query,omitempty"`
+	Status int    `json:"status"`
+}
+
+// Manifest is written next to the fixtures as manifest.json.
+type Manifest struct {
+	Vendor    string          `json:"vendor"`
+	Site      string          `json:"site"`
+	Sanitized bool            `json:"sanitized"`
+	Fixtures  []ManifestEntry `json:"fixtures"`
+	Errors    []string        `json:"errors,omitempty"`
+}
+
+// FileName is the fixture file name for the n-th response (1-based):
+// "NN-<method>-<path slug>.<ext>", with IDs in the path collapsed.
+func FileName(n int, f Fixture, ext string) string {
+	slug := strings.Trim(strings.NewReplacer("/", "-", ":", "").Replace(runstats.NormalizePath(f.Path)), "-")
+	return fmt.Sprintf("%02d-%s-%s.%s", n, strings.ToLower(f.Method), slug, ext)
+}
+
+// Write cleans each fixture, writes it to dir, and writes manifest.json,
+// filling m.Fixtures. siteID trims org-wide lists to the site; s (nil when
+// sanitization is off) pseudonymizes bodies and the recorded paths.
+func Write(dir string, fixtures []Fixture, siteID string, s *Sanitizer, m Manifest) (Manifest, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return m, err
+	}
+	m.Sanitized = s != nil
+	m.Fixtures = nil
+	if s != nil && m.Site != "" {
+		m.Site = s.pseudonym("name", m.Site, func(n int) string { return fmt.Sprintf("name-%d", n) })
+	}
+	if s != nil {
+		for i, e := range m.Errors {
+			m.Errors[i] = s.String(e)
+		}
+	}
+	for i, f := range fixtures {
+		body, isJSON := Clean(TrimToSite(f.Body, siteID), s)
+		ext := "json"
+		if !isJSON {
+			ext = "txt"
+		}
+		name := FileName(i+1, f, ext)
+		if err := os.WriteFile(filepath.Join(dir, name), body, 0644); err != nil {
+			return m, err
+		}
+		path, query := f.Path, f.Query
+		if s != nil {
+			path, query = s.String(path), s.String(query)
+		}
+		m.Fixtures = append(m.Fixtures, ManifestEntry{File: name, Method: f.Method, Path: path, Query: query, Status: f.Status})
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return m, err
+	}
+	return m, os.WriteFile(filepath.Join(dir, "manifest.json"), append(data, '\n'), 0644)
+}
@@ -0,0 +1,105 @@
+package fixtures
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const siteA = "4ac1dcf4-9d8b-4b5a-8e2c-6c2d0a1b2c3d"
+
+func TestTrimToSite(t *testing.T) {
+	body := []byte(`[{"mac":"a","site_id":"` + siteA + `"},{"mac":"b","site_id":"other"},{"mac":"c"},{"serial":"d","networkId":"N_1"}]`)
+	var got []map[string]any
+	if err := json.Unmarshal(TrimToSite(body, siteA), &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0]["mac"] != "a" || got[1]["mac"] != "c" {
+		t.Errorf("trimmed = %v, want entries a and c", got)
+	}
+
+	obj := []byte(`{"site_id":"other"}`)
+	if string(TrimToSite(obj, siteA)) != string(obj) {
+		t.Error("non-array body was changed")
+	}
+}
+
+func TestCleanSanitizes(t *testing.T) {
+	s := NewSanitizer()
+	dev := []byte(`{"id":"00000000-0000-0000-1000-aabbccddeeff","site_id":"` + siteA + `",
+		"name":"ap-lobby","mac":"aabbccddeeff","ip":"10.1.2.3","lat":37.4,
+		"radius_secret":"hunter2","uplink":"AA:BB:CC:DD:EE:FF via 10.1.2.3","channel":36}`)
+	out, isJSON := Clean(dev, s)
+	if !isJSON {
+		t.Fatal("JSON body not recognized")
+	}
+	text := string(out)
+	for _, leaked := range []string{"aabbccddeeff", "AA:BB", "10.1.2.3", "ap-lobby", siteA, "hunter2", "37.4"} {
+		if strings.Contains(text, leaked) {
+			t.Errorf("%q survived sanitization:\n%s", leaked, text)
+		}
+	}
+	var got map[string]any
+	_ = json.Unmarshal(out, &got)
+	if got["channel"] != float64(36) {
+		t.Errorf("channel = %v, want 36 untouched", got["channel"])
+	}
+	if got["ip"] != "192.0.2.1" {
+		t.Errorf("ip = %v, want 192.0.2.1", got["ip"])
+	}
+
+	// The same values map to the same stand-ins in a later file.
+	status, _ := Clean([]byte(`{"`+"aabbccddeeff"+`":{"site_id":"`+siteA+`","name":"ap-lobby"}}`), s)
+	var st map[string]map[string]any
+	_ = json.Unmarshal(status, &st)
+	entry, ok := st[got["mac"].(string)]
+	if !ok {
+		t.Fatalf("status not keyed by the device's pseudonymous MAC: %s", status)
+	}
+	if entry["site_id"] != got["site_id"] || entry["name"] != got["name"] {
+		t.Errorf("pseudonyms differ across files: %v vs %v", entry, got)
+	}
+
+	// Without a sanitizer only secrets are removed.
+	raw, _ := Clean(dev, nil)
+	if !strings.Contains(string(raw), "ap-lobby") || strings.Contains(string(raw), "hunter2") {
+		t.Errorf("unsanitized clean:\n%s", raw)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	dir := t.TempDir()
+	fx := []Fixture{
+		{Method: "GET", Path: "/api/v1/sites/" + siteA, Status: 200, Body: []byte(`{"id":"` + siteA + `","name":"LAB-01"}`)},
+		{Method: "GET", Path: "/api/v1/sites/" + siteA + "/wlans", Status: 502, Body: []byte("<html>bad gateway token=abc123</html>")},
+	}
+	m, err := Write(dir, fx, siteA, NewSanitizer(), Manifest{Vendor: "mist", Site: "LAB-01"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"01-get-api-v1-sites-id.json", "02-get-api-v1-sites-id-wlans.txt"}
+	for i, name := range want {
+		if m.Fixtures[i].File != name {
+			t.Errorf("fixture %d = %s, want %s", i, m.Fixtures[i].File, name)
+		}
+		if strings.Contains(m.Fixtures[i].Path, siteA) {
+			t.Errorf("manifest path %s not sanitized", m.Fixtures[i].Path)
+		}
+	}
+	if m.Site != "name-1" {
+		t.Errorf("manifest site = %q", m.Site)
+	}
+	site, _ := os.ReadFile(filepath.Join(dir, want[0]))
+	if !strings.Contains(string(site), `"name": "name-1"`) {
+		t.Errorf("site name pseudonym differs from the manifest's:\n%s", site)
+	}
+	html, _ := os.ReadFile(filepath.Join(dir, want[1]))
+	if strings.Contains(string(html), "abc123") {
+		t.Errorf("token survived in text body: %s", html)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "manifest.json")); err != nil {
+		t.Error(err)
+	}
+}
@@ -276,6 +276,10 @@ func (m BubbleTableModel) View() string {
 
 // RenderStatic renders the table without interactivity
 func (m *BubbleTableModel) RenderStatic() string {
+	if symbols.Plain() {
+		return m.renderRecords()
+	}
+
 	// Get terminal width directly using golang.org/x/term
 	termWidth := 80 // default fallback
 
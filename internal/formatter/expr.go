@@ -0,0 +1,420 @@
+package formatter
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ColumnExpr is a compiled computed-column expression. A display field config
+// may give a column an "expr" instead of reading its field from the row:
+//
+//	{"field": "uptime_days", "title": "Up (d)", "expr": "round(uptime / 86400, 1)"}
+//	{"field": "where", "title": "Where", "expr": "site_name + ' / ' + name"}
+//
+// Operands are numbers, quoted strings, and field names (dotted paths reach
+// into nested values; cache.* paths read the cached device as a cache.*
+// column does). Operators are + - * / % and parentheses; + concatenates when
+// either side is a string. round(x[, digits]), upper(s), and lower(s) are the
+// only functions. A missing field or a division by zero leaves the cell empty.
+type ColumnExpr struct {
+	src  string
+	root exprNode
+}
+
+// exprLookup resolves a field name referenced by an expression.
+type exprLookup func(path string) (interface{}, bool)
+
+// exprNode is one node of a parsed expression.
+type exprNode interface {
+	eval(lookup exprLookup) (interface{}, bool)
+}
+
+// ParseColumnExpr compiles a computed-column expression.
+func ParseColumnExpr(src string) (*ColumnExpr, error) {
+	tokens, err := tokenizeExpr(src)
+	if err != nil {
+		return nil, fmt.Errorf("expr %q: %w", src, err)
+	}
+	p := &exprParser{tokens: tokens}
+	root, err := p.parseSum()
+	if err == nil && p.pos < len(p.tokens) {
+		err = fmt.Errorf("unexpected %q", p.tokens[p.pos].text)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("expr %q: %w", src, err)
+	}
+	return &ColumnExpr{src: src, root: root}, nil
+}
+
+// String returns the expression source.
+func (e *ColumnExpr) String() string {
+	return e.src
+}
+
+// Eval computes the expression for one row. ok is false when a referenced
+// field is missing or the arithmetic is undefined.
+func (e *ColumnExpr) Eval(lookup func(path string) (interface{}, bool)) (interface{}, bool) {
+	return e.root.eval(lookup)
+}
+
+// Tokens
+
+type exprTokenKind int
+
+const (
+	tokNumber exprTokenKind = iota
+	tokString
+	tokIdent
+	tokOp
+)
+
+type exprToken struct {
+	kind exprTokenKind
+	text string
+	num  float64
+}
+
+func tokenizeExpr(src string) ([]exprToken, error) {
+	var tokens []exprToken
+	runes := []rune(src)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case strings.ContainsRune("+-*/%(),", r):
+			tokens = append(tokens, exprToken{kind: tokOp, text: string(r)})
+			i++
+		case r == '\'' || r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				end++
+			}
+			if end >= len(runes) {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			tokens = append(tokens, exprToken{kind: tokString, text: string(runes[i+1 : end])})
+			i = end + 1
+		case unicode.IsDigit(r) || r == '.':
+			end := i
+			for end < len(runes) && (unicode.IsDigit(runes[end]) || runes[end] == '.') {
+				end++
+			}
+			n, err := strconv.ParseFloat(string(runes[i:end]), 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %q", string(runes[i:end]))
+			}
+			tokens = append(tokens, exprToken{kind: tokNumber, text: string(runes[i:end]), num: n})
+			i = end
+		case unicode.IsLetter(r) || r == '_':
+			end := i
+			for end < len(runes) && (unicode.IsLetter(runes[end]) || unicode.IsDigit(runes[end]) || runes[end] == '_' || runes[end] == '.') {
+				end++
+			}
+			tokens = append(tokens, exprToken{kind: tokIdent, text: string(runes[i:end])})
+			i = end
+		default:
+			return nil, fmt.Errorf("unexpected character %q", r)
+		}
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty expression")
+	}
+	return tokens, nil
+}
+
+// Parser: sum := product (("+"|"-") product)*
+//         product := unary (("*"|"/"|"%") unary)*
+//         unary := "-" unary | primary
+//         primary := number | string | name | name "(" args ")" | "(" sum ")"
+
+type exprParser struct {
+	tokens []exprToken
+	pos    int
+}
+
+func (p *exprParser) peekOp(ops string) (string, bool) {
+	if p.pos < len(p.tokens) && p.tokens[p.pos].kind == tokOp && strings.Contains(ops, p.tokens[p.pos].text) {
+		return p.tokens[p.pos].text, true
+	}
+	return "", false
+}
+
+func (p *exprParser) expectOp(op string) error {
+	if got, ok := p.peekOp(op); ok && got == op {
+		p.pos++
+		return nil
+	}
+	if p.pos >= len(p.tokens) {
+		return fmt.Errorf("expected %q at end of expression", op)
+	}
+	return fmt.Errorf("expected %q, found %q", op, p.tokens[p.pos].text)
+}
+
+func (p *exprParser) parseSum() (exprNode, error) {
+	left, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.peekOp("+-")
+		if !ok {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		left = &exprBinary{op: op, left: left, right: right}
+	}
+}
+
+func (p *exprParser) parseProduct() (exprNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.peekOp("*/%")
+		if !ok {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &exprBinary{op: op, left: left, right: right}
+	}
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	if _, ok := p.peekOp("-"); ok {
+		p.pos++
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &exprBinary{op: "-", left: exprLiteral{value: 0.0}, right: x}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *exprParser) parsePrimary() (exprNode, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	tok := p.tokens[p.pos]
+	p.pos++
+	switch tok.kind {
+	case tokNumber:
+		return exprLiteral{value: tok.num}, nil
+	case tokString:
+		return exprLiteral{value: tok.text}, nil
+	case tokIdent:
+		if _, ok := p.peekOp("("); ok {
+			return p.parseCall(tok.text)
+		}
+		return exprField{path: tok.text}, nil
+	}
+	if tok.text == "(" {
+		inner, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectOp(")"); err != nil {
+			return nil, err
+		}
+		return inner, nil
+	}
+	return nil, fmt.Errorf("unexpected %q", tok.text)
+}
+
+func (p *exprParser) parseCall(name string) (exprNode, error) {
+	fn, ok := exprFuncs[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %q", name)
+	}
+	p.pos++ // "("
+	var args []exprNode
+	if _, closed := p.peekOp(")"); !closed {
+		for {
+			arg, err := p.parseSum()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if _, more := p.peekOp(","); !more {
+				break
+			}
+			p.pos++
+		}
+	}
+	if err := p.expectOp(")"); err != nil {
+		return nil, err
+	}
+	if len(args) < fn.minArgs || len(args) > fn.maxArgs {
+		return nil, fmt.Errorf("%s takes %d to %d argument(s), got %d", name, fn.minArgs, fn.maxArgs, len(args))
+	}
+	return &exprCall{fn: fn, args: args}, nil
+}
+
+// Nodes
+
+type exprLiteral struct{ value interface{} }
+
+func (n exprLiteral) eval(exprLookup) (interface{}, bool) { return n.value, true }
+
+type exprField struct{ path string }
+
+func (n exprField) eval(lookup exprLookup) (interface{}, bool) {
+	v, ok := lookup(n.path)
+	if !ok || v == nil {
+		return nil, false
+	}
+	if s, isStr := v.(string); isStr {
+		return stripDisplayMarkers(s), true
+	}
+	return v, true
+}
+
+type exprBinary struct {
+	op          string
+	left, right exprNode
+}
+
+func (n *exprBinary) eval(lookup exprLookup) (interface{}, bool) {
+	l, ok := n.left.eval(lookup)
+	if !ok {
+		return nil, false
+	}
+	r, ok := n.right.eval(lookup)
+	if !ok {
+		return nil, false
+	}
+	if n.op == "+" {
+		_, ls := l.(string)
+		_, rs := r.(string)
+		if ls || rs {
+			return formatExprValue(l) + formatExprValue(r), true
+		}
+	}
+	a, aok := exprNumber(l)
+	b, bok := exprNumber(r)
+	if !aok || !bok {
+		return nil, false
+	}
+	switch n.op {
+	case "+":
+		return a + b, true
+	case "-":
+		return a - b, true
+	case "*":
+		return a * b, true
+	case "/":
+		if b == 0 {
+			return nil, false
+		}
+		return a / b, true
+	case "%":
+		if b == 0 {
+			return nil, false
+		}
+		return math.Mod(a, b), true
+	}
+	return nil, false
+}
+
+type exprFunc struct {
+	minArgs, maxArgs int
+	call             func(args []interface{}) (interface{}, bool)
+}
+
+var exprFuncs = map[string]*exprFunc{
+	"round": {1, 2, func(args []interface{}) (interface{}, bool) {
+		x, ok := exprNumber(args[0])
+		if !ok {
+			return nil, false
+		}
+		digits := 0.0
+		if len(args) == 2 {
+			if digits, ok = exprNumber(args[1]); !ok {
+				return nil, false
+			}
+		}
+		scale := math.Pow(10, math.Trunc(digits))
+		return math.Round(x*scale) / scale, true
+	}},
+	"upper": {1, 1, func(args []interface{}) (interface{}, bool) {
+		return strings.ToUpper(formatExprValue(args[0])), true
+	}},
+	"lower": {1, 1, func(args []interface{}) (interface{}, bool) {
+		return strings.ToLower(formatExprValue(args[0])), true
+	}},
+}
+
+type exprCall struct {
+	fn   *exprFunc
+	args []exprNode
+}
+
+func (n *exprCall) eval(lookup exprLookup) (interface{}, bool) {
+	values := make([]interface{}, len(n.args))
+	for i, arg := range n.args {
+		v, ok := arg.eval(lookup)
+		if !ok {
+			return nil, false
+		}
+		values[i] = v
+	}
+	return n.fn.call(values)
+}
+
+// exprNumber converts a field value to a number. Numeric strings count, so
+// fields the API reports as strings still take part in arithmetic.
+func exprNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case bool:
+		if n {
+			return 1, true
+		}
+		return 0, true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(n), 64)
+		return f, err == nil
+	case fmt.Stringer:
+		f, err := strconv.ParseFloat(n.String(), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// formatExprValue renders a computed value for a cell; whole numbers print
+// without a decimal point.
+func formatExprValue(v interface{}) string {
+	switch n := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return n
+	case float64:
+		return strconv.FormatFloat(n, 'f', -1, 64)
+	}
+	return fmt.Sprintf("%v", v)
+}
@@ -0,0 +1,90 @@
+package formatter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestColumnExprEval(t *testing.T) {
+	row := map[string]interface{}{
+		"uptime":    float64(302400),
+		"name":      "BOLD_TEXT:ap-lobby",
+		"site_name": "US-LAB-01",
+		"clients":   "12",
+		"zero":      0,
+		"radio":     map[string]interface{}{"band_5": map[string]interface{}{"channel": 36}},
+	}
+	lookup := func(path string) (interface{}, bool) {
+		if v, ok := row[path]; ok {
+			return v, true
+		}
+		return getNestedValue(row, path)
+	}
+
+	tests := []struct {
+		expr string
+		want string
+		ok   bool
+	}{
+		{"uptime / 86400", "3.5", true},
+		{"round(uptime / 86400)", "4", true},
+		{"round(uptime / 7 / 86400, 2)", "0.5", true},
+		{"site_name + ' / ' + name", "US-LAB-01 / ap-lobby", true},
+		{"upper(name) + \"-\" + radio.band_5.channel", "AP-LOBBY-36", true},
+		{"clients * 2 + 1", "25", true},
+		{"-(2 + 3) * 2", "-10", true},
+		{"7 % 4", "3", true},
+		{"uptime / zero", "", false},
+		{"missing + 1", "", false},
+	}
+	for _, tt := range tests {
+		e, err := ParseColumnExpr(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseColumnExpr(%q): %v", tt.expr, err)
+		}
+		v, ok := e.Eval(lookup)
+		if ok != tt.ok || (ok && formatExprValue(v) != tt.want) {
+			t.Errorf("%q = %v, %v; want %q, %v", tt.expr, v, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestParseColumnExprErrors(t *testing.T) {
+	for _, src := range []string{"", "uptime /", "(1 + 2", "1 2", "sqrt(4)", "round()", "'open", "a $ b"} {
+		if _, err := ParseColumnExpr(src); err == nil {
+			t.Errorf("ParseColumnExpr(%q) should fail", src)
+		}
+	}
+}
+
+func TestGenericTablePrinter_ComputedColumns(t *testing.T) {
+	mockCache := &mockCacheAccessor{
+		cachedData: map[string]map[string]interface{}{
+			"aabbccddeeff": {"radio_config": map[string]interface{}{"band_5": map[string]interface{}{"power": 17}}},
+		},
+	}
+	data := []GenericTableData{
+		{"name": "ap-1", "mac": "aabbccddeeff", "uptime": 172800},
+		{"name": "ap-2", "mac": "112233445566"},
+	}
+	printer := NewGenericTablePrinter(TableConfig{Format: "csv", CacheAccess: mockCache}, data)
+	printer.LoadColumnsFromConfig([]interface{}{
+		map[string]interface{}{"field": "name", "title": "Name"},
+		map[string]interface{}{"field": "uptime_days", "title": "Up (d)", "expr": "uptime / 86400"},
+		map[string]interface{}{"field": "pwr", "title": "Pwr", "expr": "cache.radio_config.band_5.power + ' dBm'"},
+		map[string]interface{}{"field": "bad", "title": "Bad", "expr": "uptime +"},
+	})
+	if len(printer.Config.Columns) != 3 {
+		t.Fatalf("columns = %+v, want the invalid expression skipped", printer.Config.Columns)
+	}
+
+	out := printer.Print()
+	for _, want := range []string{"Name,Up (d),Pwr", "ap-1,2,17 dBm", "ap-2,,"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if _, computed := data[0]["uptime_days"]; computed {
+		t.Error("computed values must not be written into the caller's rows")
+	}
+}
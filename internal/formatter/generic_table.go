@@ -6,18 +6,44 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"reflect"
 	"sort"
 	"strconv"
 	"strings"
 
+	"github.com/ravinald/wifimgr/internal/common"
+	"github.com/ravinald/wifimgr/internal/logging"
 	"github.com/ravinald/wifimgr/internal/macaddr"
 )
 
 // MarshalJSONIndent renders data as plain, indented JSON. Structured output
 // (the `format json` interaction) is machine-facing, so it never carries ANSI
 // color — escapes would corrupt `| jq` and redirected files. HTML escaping is
-// disabled so values like `&` and `<` survive verbatim.
+// disabled so values like `&` and `<` survive verbatim. Sensitive fields are
+// redacted (see common.IsSensitiveField).
 func MarshalJSONIndent(data interface{}, prefix, indent string) ([]byte, error) {
+	out, err := encodeJSONIndent(data, prefix, indent)
+	if err != nil {
+		return nil, err
+	}
+
+	// Redact on the decoded form so structs are covered too. Output with
+	// nothing to redact is returned as first encoded, keeping struct field
+	// order.
+	dec := json.NewDecoder(bytes.NewReader(out))
+	dec.UseNumber()
+	var decoded any
+	if err := dec.Decode(&decoded); err != nil {
+		return nil, err
+	}
+	redacted := common.RedactValue(decoded)
+	if reflect.DeepEqual(decoded, redacted) {
+		return out, nil
+	}
+	return encodeJSONIndent(redacted, prefix, indent)
+}
+
+func encodeJSONIndent(data interface{}, prefix, indent string) ([]byte, error) {
 	var buf bytes.Buffer
 	enc := json.NewEncoder(&buf)
 	enc.SetEscapeHTML(false)
@@ -42,6 +68,10 @@ type TableColumn struct {
 	IsBoolField       bool // use special bool formatting
 	IsConnectionField bool // use C/D/? symbols for connection status
 	IsStatusField     bool // format as online/offline/alerting/dormant
+
+	// Expr computes the column from other fields instead of reading Field
+	// from the row; the result is stored under Field. See ColumnExpr.
+	Expr *ColumnExpr
 }
 
 // displayMarkers are table-only emphasis prefixes the command layer prepends to
@@ -268,6 +298,17 @@ func (p *GenericTablePrinter) LoadColumnsFromConfig(configArray interface{}) {
 			}
 		}
 
+		// A computed column carries an expression over the row's fields
+		var expr *ColumnExpr
+		if exprStr, ok := configObj["expr"].(string); ok && exprStr != "" {
+			parsed, err := ParseColumnExpr(exprStr)
+			if err != nil {
+				logging.Warnf("Skipping display column %q: %v", fieldName, err)
+				continue
+			}
+			expr = parsed
+		}
+
 		// Detect boolean fields
 		isBoolField := strings.HasSuffix(strings.ToLower(fieldName), "enabled") ||
 			strings.HasSuffix(strings.ToLower(fieldName), "connected") ||
@@ -290,6 +331,11 @@ func (p *GenericTablePrinter) LoadColumnsFromConfig(configArray interface{}) {
 			IsBoolField:       isBoolField,
 			IsConnectionField: isConnectionField,
 			IsStatusField:     isStatusField,
+			Expr:              expr,
+		}
+		if expr != nil {
+			// Computed values are plain text or numbers, never booleans.
+			column.IsBoolField, column.IsConnectionField, column.IsStatusField = false, false, false
 		}
 
 		// Add to columns list (maintains order from configuration array)
@@ -303,6 +349,8 @@ func (p *GenericTablePrinter) Print() string {
 	if len(p.Data) == 0 {
 		return "No data to display"
 	}
+	p = p.redactedView()
+	p.computeColumns()
 
 	// If no columns are configured, generate default columns from the data structure
 	if len(p.Config.Columns) == 0 {
@@ -519,6 +567,83 @@ func (p *GenericTablePrinter) formatAsJSONWithAllFields() string {
 	return string(jsonData) + "\n"
 }
 
+// computeColumns evaluates every computed column into its rows. It runs on
+// the redacted copy, so the caller's rows are untouched and an expression can
+// only see what the output could show anyway. A row whose expression has no
+// value (missing field, division by zero) gets an empty cell.
+func (p *GenericTablePrinter) computeColumns() {
+	for _, col := range p.Config.Columns {
+		if col.Expr == nil {
+			continue
+		}
+		for _, row := range p.Data {
+			if row == nil {
+				continue
+			}
+			value, ok := col.Expr.Eval(p.exprLookup(row))
+			if !ok {
+				row[col.Field] = ""
+				continue
+			}
+			row[col.Field] = formatExprValue(value)
+		}
+	}
+}
+
+// exprLookup resolves the fields an expression names for one row: cache.*
+// paths read the row's cached device, anything else the row itself, with
+// dotted names reaching into nested values.
+func (p *GenericTablePrinter) exprLookup(row GenericTableData) exprLookup {
+	return func(path string) (interface{}, bool) {
+		if cachePath, isCache := strings.CutPrefix(path, "cache."); isCache {
+			if p.Config.CacheAccess == nil {
+				return nil, false
+			}
+			mac, _ := row["mac"].(string)
+			if mac == "" {
+				return nil, false
+			}
+			cached, found := p.Config.CacheAccess.GetCachedData(mac)
+			if !found {
+				return nil, false
+			}
+			return p.Config.CacheAccess.GetFieldByPath(cached, cachePath)
+		}
+		if v, ok := row[path]; ok {
+			return v, true
+		}
+		return getNestedValue(row, path)
+	}
+}
+
+// redactedView returns a copy of the printer whose rows and cache lookups
+// have sensitive fields replaced, so no output format can show them.
+func (p *GenericTablePrinter) redactedView() *GenericTablePrinter {
+	rows := make([]GenericTableData, len(p.Data))
+	for i, row := range p.Data {
+		rows[i] = GenericTableData(common.RedactMap(map[string]interface{}(row)))
+	}
+	cfg := p.Config
+	if cfg.CacheAccess != nil {
+		cfg.CacheAccess = redactingCacheAccessor{cfg.CacheAccess}
+	}
+	return &GenericTablePrinter{Config: cfg, Data: rows}
+}
+
+// redactingCacheAccessor redacts the raw cache records behind cache.* columns
+// and all-fields JSON.
+type redactingCacheAccessor struct {
+	CacheAccessor
+}
+
+func (r redactingCacheAccessor) GetCachedData(index string) (map[string]interface{}, bool) {
+	data, ok := r.CacheAccessor.GetCachedData(index)
+	if !ok {
+		return nil, false
+	}
+	return common.RedactMap(data), true
+}
+
 // PrintToOutput formats the table and writes it to the provided writer
 func (p *GenericTablePrinter) PrintToOutput(w io.Writer) error {
 	_, err := fmt.Fprint(w, p.Print())
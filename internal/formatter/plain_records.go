@@ -0,0 +1,120 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ravinald/wifimgr/internal/i18n"
+)
+
+// renderRecords renders the table for --plain: one "Title: value" line per
+// field and a blank line between rows, so a screen reader reads each value
+// with its name and nothing wraps on a narrow console. Empty fields are left
+// out, and the one-letter codes of a flags column are replaced by their
+// legend descriptions.
+func (m *BubbleTableModel) renderRecords() string {
+	var out strings.Builder
+	if m.config.Title != "" {
+		out.WriteString(m.config.Title)
+		out.WriteString("\n\n")
+	}
+	legend := make(map[string]string, len(m.config.FlagLegend))
+	for _, f := range m.config.FlagLegend {
+		legend[f.Key] = f.Description
+	}
+
+	for rowIdx, item := range m.data {
+		if rowIdx > 0 {
+			out.WriteString("\n")
+		}
+		for _, col := range m.config.Columns {
+			if col.IsHidden {
+				continue
+			}
+			val, exists := m.cellValue(item, col)
+			if !exists {
+				continue
+			}
+			text := plainCell(val, col)
+			if col.Field == "flags" && len(legend) > 0 {
+				text = expandFlags(text, legend)
+			}
+			if text == "" {
+				continue
+			}
+			title := col.Title
+			if title == "" {
+				title = col.Header
+			}
+			fmt.Fprintf(&out, "%s: %s\n", title, text)
+		}
+	}
+	return out.String()
+}
+
+// cellValue looks a column up in a row, reading cache.* columns from the
+// cached record of the row's MAC.
+func (m *BubbleTableModel) cellValue(item GenericTableData, col TableColumn) (interface{}, bool) {
+	if !strings.HasPrefix(col.Field, "cache.") || m.config.CacheAccess == nil {
+		val, ok := item[col.Field]
+		return val, ok
+	}
+	mac, _ := item["mac"].(string)
+	if mac == "" {
+		return nil, false
+	}
+	cached, found := m.config.CacheAccess.GetCachedData(mac)
+	if !found {
+		return nil, false
+	}
+	return m.config.CacheAccess.GetFieldByPath(cached, strings.TrimPrefix(col.Field, "cache."))
+}
+
+// plainCell renders one value in words: connection and status fields are
+// spelled out ("online", "disconnected") rather than shown as colored
+// letters.
+func plainCell(val interface{}, col TableColumn) string {
+	switch {
+	case col.IsBoolField:
+		b, ok := val.(bool)
+		switch {
+		case !ok:
+			return "unknown"
+		case col.IsConnectionField && b:
+			return "connected"
+		case col.IsConnectionField:
+			return "disconnected"
+		case b:
+			return i18n.T("bool.yes")
+		default:
+			return i18n.T("bool.no")
+		}
+	case col.IsStatusField:
+		return strings.ToLower(fmt.Sprintf("%v", val))
+	case isMACField(col.Field):
+		return formatMACDisplay(fmt.Sprintf("%v", val))
+	case strings.HasPrefix(col.Field, "cache."):
+		return formatNestedValue(val)
+	case val == nil:
+		return ""
+	}
+	return stripDisplayMarkers(fmt.Sprintf("%v", val))
+}
+
+// expandFlags replaces each flag key in a flags cell with its description.
+// A key without a legend entry is kept as is.
+func expandFlags(flags string, legend map[string]string) string {
+	var words []string
+	for _, r := range flags {
+		key := string(r)
+		if key == " " {
+			continue
+		}
+		if desc, ok := legend[key]; ok {
+			words = append(words, desc)
+		} else {
+			words = append(words, key)
+		}
+	}
+	return strings.Join(words, ", ")
+}
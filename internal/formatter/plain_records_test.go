@@ -0,0 +1,61 @@
+package formatter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ravinald/wifimgr/internal/symbols"
+)
+
+func TestRenderStaticPlainRecords(t *testing.T) {
+	symbols.SetPlain(true)
+	defer symbols.SetPlain(false)
+
+	cfg := TableConfig{
+		Title:         "Devices",
+		Format:        "table",
+		ShowSeparator: true,
+		Columns: []TableColumn{
+			{Field: "name", Title: "Name"},
+			{Field: "mac", Title: "MAC"},
+			{Field: "connected", Title: "Conn", IsBoolField: true, IsConnectionField: true},
+			{Field: "status", Title: "Status", IsStatusField: true},
+			{Field: "flags", Title: "Flags"},
+			{Field: "notes", Title: "Notes"},
+		},
+		FlagLegend: []FlagDef{{Key: "M", Description: "managed"}, {Key: "D", Description: "drift"}},
+	}
+	rows := []GenericTableData{
+		{"name": "BOLD_TEXT:ap-01", "mac": "aabbccddeeff", "connected": true, "status": "ONLINE", "flags": "MD"},
+		{"name": "ap-02", "connected": false, "status": "offline"},
+	}
+	got := NewGenericTablePrinter(cfg, rows).Print()
+	want := `Devices
+
+Name: ap-01
+MAC: aa:bb:cc:dd:ee:ff
+Conn: connected
+Status: online
+Flags: managed, drift
+
+Name: ap-02
+Conn: disconnected
+Status: offline
+`
+	if got != want {
+		t.Errorf("plain output:\n%s\nwant:\n%s", got, want)
+	}
+	if strings.ContainsAny(got, "─│\x1b") {
+		t.Errorf("plain output has box drawing or escapes: %q", got)
+	}
+}
+
+func TestExpandFlags(t *testing.T) {
+	legend := map[string]string{"M": "managed", "D": "drift"}
+	if got := expandFlags("M X", legend); got != "managed, X" {
+		t.Errorf("expandFlags = %q, want %q", got, "managed, X")
+	}
+	if got := expandFlags("", legend); got != "" {
+		t.Errorf("expandFlags(empty) = %q", got)
+	}
+}
@@ -0,0 +1,195 @@
+// Package freeze reads a change-freeze calendar and reports which freeze
+// windows cover a site at a given moment, so apply can warn — or refuse — when
+// an operator pushes during a holiday or peak-season freeze.
+//
+// The calendar is a JSON file or an iCalendar (.ics) feed, loaded from a local
+// path or an http(s) URL named by change_freeze.calendar.
+package freeze
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Modes controlling what apply does inside an active window.
+const (
+	ModeWarn  = "warn"
+	ModeBlock = "block"
+)
+
+// fetchTimeout bounds a remote calendar fetch so an unreachable calendar host
+// never stalls apply.
+const fetchTimeout = 10 * time.Second
+
+// Window is one freeze period. A window with no Regions and no Sites applies
+// to every site.
+type Window struct {
+	Name    string    `json:"name"`
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	Regions []string  `json:"regions,omitempty"` // country codes or timezone areas ("US", "Europe")
+	Sites   []string  `json:"sites,omitempty"`   // site name globs ("US-*")
+}
+
+// Site identifies the apply target for window matching.
+type Site struct {
+	Name        string
+	CountryCode string
+	Timezone    string
+}
+
+// Config holds change_freeze.* settings.
+type Config struct {
+	Calendar string
+	Mode     string
+}
+
+// LoadConfig reads change_freeze.* from viper. Returns nil when no calendar is
+// configured.
+func LoadConfig() (*Config, error) {
+	cal := strings.TrimSpace(viper.GetString("change_freeze.calendar"))
+	if cal == "" {
+		return nil, nil
+	}
+	mode := strings.ToLower(strings.TrimSpace(viper.GetString("change_freeze.mode")))
+	if mode == "" {
+		mode = ModeWarn
+	}
+	if mode != ModeWarn && mode != ModeBlock {
+		return nil, fmt.Errorf("change_freeze.mode must be %q or %q, got %q", ModeWarn, ModeBlock, mode)
+	}
+	if !isURL(cal) && !filepath.IsAbs(cal) {
+		if dir := viper.GetString("files.config_dir"); dir != "" {
+			cal = filepath.Join(dir, cal)
+		}
+	}
+	return &Config{Calendar: cal, Mode: mode}, nil
+}
+
+// Load reads and parses the calendar at source (path or URL).
+func Load(ctx context.Context, source string) ([]Window, error) {
+	data, err := read(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Parse decodes calendar data, detecting iCalendar by its BEGIN:VCALENDAR
+// header and treating anything else as JSON.
+func Parse(data []byte) ([]Window, error) {
+	trimmed := strings.TrimSpace(strings.TrimPrefix(string(data), "\ufeff"))
+	if strings.HasPrefix(strings.ToUpper(trimmed), "BEGIN:VCALENDAR") {
+		return ParseICS(trimmed)
+	}
+	return ParseJSON([]byte(trimmed))
+}
+
+// ParseJSON accepts either {"windows": [...]} or a bare array of windows.
+func ParseJSON(data []byte) ([]Window, error) {
+	var windows []Window
+	if strings.HasPrefix(string(data), "[") {
+		if err := json.Unmarshal(data, &windows); err != nil {
+			return nil, fmt.Errorf("invalid freeze calendar: %w", err)
+		}
+	} else {
+		var doc struct {
+			Windows []Window `json:"windows"`
+		}
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("invalid freeze calendar: %w", err)
+		}
+		windows = doc.Windows
+	}
+	for i, w := range windows {
+		if w.Start.IsZero() || w.End.IsZero() {
+			return nil, fmt.Errorf("freeze window %d (%s): start and end are required", i, w.Name)
+		}
+		if !w.End.After(w.Start) {
+			return nil, fmt.Errorf("freeze window %d (%s): end must be after start", i, w.Name)
+		}
+	}
+	return windows, nil
+}
+
+// Active returns the windows covering site at now.
+func Active(windows []Window, site Site, now time.Time) []Window {
+	var out []Window
+	for _, w := range windows {
+		if now.Before(w.Start) || !now.Before(w.End) {
+			continue
+		}
+		if w.Covers(site) {
+			out = append(out, w)
+		}
+	}
+	return out
+}
+
+// Covers reports whether the window's scope includes site. Regions match the
+// site's country code or the area of its IANA timezone ("Europe" matches
+// "Europe/Berlin"); Sites are case-insensitive globs on the site name.
+func (w Window) Covers(site Site) bool {
+	if len(w.Regions) == 0 && len(w.Sites) == 0 {
+		return true
+	}
+	for _, r := range w.Regions {
+		r = strings.TrimSpace(r)
+		if r == "" {
+			continue
+		}
+		if strings.EqualFold(r, site.CountryCode) || strings.EqualFold(r, site.Timezone) {
+			return true
+		}
+		if area, _, ok := strings.Cut(site.Timezone, "/"); ok && strings.EqualFold(r, area) {
+			return true
+		}
+	}
+	name := strings.ToLower(site.Name)
+	for _, pattern := range w.Sites {
+		if ok, _ := path.Match(strings.ToLower(pattern), name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func isURL(s string) bool {
+	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
+}
+
+func read(ctx context.Context, source string) ([]byte, error) {
+	if !isURL(source) {
+		data, err := os.ReadFile(source) // #nosec G304 -- path from operator-controlled config
+		if err != nil {
+			return nil, fmt.Errorf("failed to read freeze calendar: %w", err)
+		}
+		return data, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, fetchTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid freeze calendar URL: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch freeze calendar: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch freeze calendar: HTTP %d", resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 8<<20))
+}
@@ -0,0 +1,143 @@
+package freeze
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func TestParseJSON(t *testing.T) {
+	data := `{"windows": [
+		{"name": "Holiday", "start": "2026-12-15T00:00:00Z", "end": "2027-01-05T00:00:00Z", "regions": ["US"]},
+		{"name": "EU audit", "start": "2026-11-01T00:00:00Z", "end": "2026-11-03T00:00:00Z", "sites": ["EU-*"]}
+	]}`
+	windows, err := Parse([]byte(data))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(windows) != 2 || windows[0].Name != "Holiday" || windows[1].Sites[0] != "EU-*" {
+		t.Fatalf("unexpected windows: %+v", windows)
+	}
+
+	if _, err := Parse([]byte(`[{"name": "x", "start": "2026-01-02T00:00:00Z", "end": "2026-01-01T00:00:00Z"}]`)); err == nil {
+		t.Error("expected error for end before start")
+	}
+	if _, err := Parse([]byte(`[{"name": "x"}]`)); err == nil {
+		t.Error("expected error for missing start/end")
+	}
+}
+
+func TestParseICS(t *testing.T) {
+	data := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n" +
+		"BEGIN:VEVENT\r\nSUMMARY:Black Friday\r\n" +
+		"DTSTART:20261127T000000Z\r\nDTEND:20261130T000000Z\r\n" +
+		"CATEGORIES:US,CA\r\nX-WIFIMGR-SITES:RETAIL-*\r\nEND:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\nSUMMARY:Year-end\r\n  close\r\n" +
+		"DTSTART;TZID=Europe/Berlin:20261224T180000\r\n" +
+		"DTEND;TZID=Europe/Berlin:20261227T080000\r\nEND:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\nSUMMARY:Inventory day\r\nDTSTART;VALUE=DATE:20260301\r\nEND:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+
+	windows, err := Parse([]byte(data))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(windows) != 3 {
+		t.Fatalf("got %d windows, want 3", len(windows))
+	}
+	bf := windows[0]
+	if bf.Name != "Black Friday" || len(bf.Regions) != 2 || bf.Sites[0] != "RETAIL-*" {
+		t.Errorf("Black Friday parsed as %+v", bf)
+	}
+	ye := windows[1]
+	if ye.Name != "Year-end close" {
+		t.Errorf("folded SUMMARY = %q", ye.Name)
+	}
+	if want := time.Date(2026, 12, 24, 17, 0, 0, 0, time.UTC); !ye.Start.Equal(want) {
+		t.Errorf("TZID start = %v, want %v", ye.Start.UTC(), want)
+	}
+	inv := windows[2]
+	if inv.End.Sub(inv.Start) != 24*time.Hour {
+		t.Errorf("all-day event without DTEND should last one day, got %v", inv.End.Sub(inv.Start))
+	}
+}
+
+func TestActive(t *testing.T) {
+	start := time.Date(2026, 12, 15, 0, 0, 0, 0, time.UTC)
+	windows := []Window{
+		{Name: "global", Start: start, End: start.Add(48 * time.Hour)},
+		{Name: "us", Start: start, End: start.Add(48 * time.Hour), Regions: []string{"us"}},
+		{Name: "europe", Start: start, End: start.Add(48 * time.Hour), Regions: []string{"Europe"}},
+		{Name: "lab", Start: start, End: start.Add(48 * time.Hour), Sites: []string{"*-LAB-*"}},
+	}
+	inside := start.Add(time.Hour)
+
+	names := func(ws []Window) map[string]bool {
+		m := map[string]bool{}
+		for _, w := range ws {
+			m[w.Name] = true
+		}
+		return m
+	}
+
+	got := names(Active(windows, Site{Name: "US-SFO-LAB-01", CountryCode: "US", Timezone: "America/Los_Angeles"}, inside))
+	if !got["global"] || !got["us"] || !got["lab"] || got["europe"] {
+		t.Errorf("US lab site matched %v", got)
+	}
+	got = names(Active(windows, Site{Name: "DE-BER-01", CountryCode: "DE", Timezone: "Europe/Berlin"}, inside))
+	if !got["global"] || !got["europe"] || got["us"] || got["lab"] {
+		t.Errorf("Berlin site matched %v", got)
+	}
+	if n := len(Active(windows, Site{Name: "US-SFO-LAB-01"}, start.Add(48*time.Hour))); n != 0 {
+		t.Errorf("window end should be exclusive, got %d active", n)
+	}
+}
+
+func TestLoadConfigAndURL(t *testing.T) {
+	t.Cleanup(viper.Reset)
+
+	viper.Set("change_freeze.calendar", "")
+	if cfg, err := LoadConfig(); err != nil || cfg != nil {
+		t.Fatalf("unset calendar: cfg=%v err=%v", cfg, err)
+	}
+
+	dir := t.TempDir()
+	viper.Set("files.config_dir", dir)
+	viper.Set("change_freeze.calendar", "freeze.json")
+	viper.Set("change_freeze.mode", "BLOCK")
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if cfg.Mode != ModeBlock || cfg.Calendar != filepath.Join(dir, "freeze.json") {
+		t.Errorf("cfg = %+v", cfg)
+	}
+	viper.Set("change_freeze.mode", "sometimes")
+	if _, err := LoadConfig(); err == nil {
+		t.Error("expected error for invalid mode")
+	}
+
+	body := `[{"name": "remote", "start": "2026-01-01T00:00:00Z", "end": "2026-01-02T00:00:00Z"}]`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(body))
+	}))
+	defer srv.Close()
+	windows, err := Load(context.Background(), srv.URL)
+	if err != nil || len(windows) != 1 || windows[0].Name != "remote" {
+		t.Fatalf("Load URL: %v %+v", err, windows)
+	}
+
+	path := filepath.Join(dir, "freeze.json")
+	if err := os.WriteFile(path, []byte(body), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if windows, err := Load(context.Background(), path); err != nil || len(windows) != 1 {
+		t.Fatalf("Load file: %v %+v", err, windows)
+	}
+}
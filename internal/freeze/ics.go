@@ -0,0 +1,128 @@
+package freeze
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ParseICS extracts freeze windows from the VEVENTs of an iCalendar feed.
+//
+// SUMMARY becomes the window name and CATEGORIES its regions. Site globs, which
+// iCalendar has no field for, come from an X-WIFIMGR-SITES property. Recurrence
+// rules are not expanded; publish each freeze as its own event.
+func ParseICS(data string) ([]Window, error) {
+	var windows []Window
+	var cur *Window
+	for _, line := range unfoldICS(data) {
+		name, params, value := splitICSLine(line)
+		switch name {
+		case "BEGIN":
+			if strings.EqualFold(value, "VEVENT") {
+				cur = &Window{}
+			}
+		case "END":
+			if strings.EqualFold(value, "VEVENT") && cur != nil {
+				if cur.Start.IsZero() {
+					return nil, fmt.Errorf("freeze event %q has no DTSTART", cur.Name)
+				}
+				if cur.End.IsZero() {
+					// RFC 5545: an all-day event without DTEND lasts one day.
+					cur.End = cur.Start.AddDate(0, 0, 1)
+				}
+				windows = append(windows, *cur)
+				cur = nil
+			}
+		case "SUMMARY":
+			if cur != nil {
+				cur.Name = unescapeICS(value)
+			}
+		case "DTSTART", "DTEND":
+			if cur == nil {
+				continue
+			}
+			t, err := parseICSTime(value, params)
+			if err != nil {
+				return nil, fmt.Errorf("freeze event %q: %s: %w", cur.Name, name, err)
+			}
+			if name == "DTSTART" {
+				cur.Start = t
+			} else {
+				cur.End = t
+			}
+		case "CATEGORIES":
+			if cur != nil {
+				cur.Regions = append(cur.Regions, splitICSList(value)...)
+			}
+		case "X-WIFIMGR-SITES":
+			if cur != nil {
+				cur.Sites = append(cur.Sites, splitICSList(value)...)
+			}
+		}
+	}
+	return windows, nil
+}
+
+// unfoldICS joins RFC 5545 folded lines (continuations begin with a space or
+// tab) and drops blank lines.
+func unfoldICS(data string) []string {
+	var lines []string
+	for _, raw := range strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n") {
+		if (strings.HasPrefix(raw, " ") || strings.HasPrefix(raw, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += raw[1:]
+			continue
+		}
+		if strings.TrimSpace(raw) != "" {
+			lines = append(lines, raw)
+		}
+	}
+	return lines
+}
+
+// splitICSLine splits "NAME;PARAM=X;PARAM2=Y:value" into its upper-cased name,
+// parameters, and value.
+func splitICSLine(line string) (string, map[string]string, string) {
+	head, value, _ := strings.Cut(line, ":")
+	parts := strings.Split(head, ";")
+	params := make(map[string]string, len(parts)-1)
+	for _, p := range parts[1:] {
+		k, v, _ := strings.Cut(p, "=")
+		params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+	}
+	return strings.ToUpper(parts[0]), params, value
+}
+
+// parseICSTime handles UTC (trailing Z), floating/TZID local, and
+// VALUE=DATE forms. Floating times without a TZID are read as UTC.
+func parseICSTime(value string, params map[string]string) (time.Time, error) {
+	loc := time.UTC
+	if tzid := params["TZID"]; tzid != "" {
+		l, err := time.LoadLocation(tzid)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("unknown TZID %q", tzid)
+		}
+		loc = l
+	}
+	switch {
+	case params["VALUE"] == "DATE" || len(value) == 8:
+		return time.ParseInLocation("20060102", value, loc)
+	case strings.HasSuffix(value, "Z"):
+		return time.Parse("20060102T150405Z", value)
+	default:
+		return time.ParseInLocation("20060102T150405", value, loc)
+	}
+}
+
+func splitICSList(value string) []string {
+	var out []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(unescapeICS(v)); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func unescapeICS(s string) string {
+	return strings.NewReplacer(`\,`, ",", `\;`, ";", `\n`, " ", `\N`, " ", `\\`, `\`).Replace(s)
+}
@@ -0,0 +1,111 @@
+// Package healthcheck pings a healthchecks.io-style monitoring URL around a
+// run, so a cron job that stops running, hangs, or fails raises an alert.
+//
+// Given a base ping URL, Start requests <url>/start, Success requests <url>,
+// and Fail requests <url>/fail. The finishing pings carry a short plain-text
+// summary of the run as the request body. A ping that cannot be delivered is
+// logged and otherwise ignored: monitoring never fails the command itself.
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ravinald/wifimgr/internal/logging"
+)
+
+// pingTimeout bounds each ping so an unreachable monitor cannot hold up a run.
+const pingTimeout = 5 * time.Second
+
+// Pinger sends start, success, and failure pings for one run. A nil Pinger
+// does nothing, so callers need not check whether monitoring is configured.
+type Pinger struct {
+	base   string
+	client *http.Client
+}
+
+// New returns a Pinger for the base ping URL, or nil when rawURL is empty.
+func New(rawURL string) (*Pinger, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	if rawURL == "" {
+		return nil, nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid healthcheck URL %q: must be an http(s) URL", rawURL)
+	}
+	return &Pinger{base: rawURL, client: &http.Client{Timeout: pingTimeout}}, nil
+}
+
+// Start signals that the run has begun.
+func (p *Pinger) Start(ctx context.Context) {
+	p.ping(ctx, "start", "")
+}
+
+// Success signals that the run finished cleanly.
+func (p *Pinger) Success(ctx context.Context, summary string) {
+	p.ping(ctx, "", summary)
+}
+
+// Fail signals that the run failed.
+func (p *Pinger) Fail(ctx context.Context, summary string) {
+	p.ping(ctx, "fail", summary)
+}
+
+// Finish sends Success or Fail depending on err.
+func (p *Pinger) Finish(ctx context.Context, command string, elapsed time.Duration, err error) {
+	elapsed = elapsed.Round(100 * time.Millisecond)
+	if err != nil {
+		p.Fail(ctx, fmt.Sprintf("wifimgr %s failed after %s: %v", command, elapsed, err))
+		return
+	}
+	p.Success(ctx, fmt.Sprintf("wifimgr %s succeeded in %s", command, elapsed))
+}
+
+// ping requests the base URL with suffix appended to its path. The URL is
+// left out of log messages: for most services it is the check's credential.
+func (p *Pinger) ping(ctx context.Context, suffix, body string) {
+	if p == nil {
+		return
+	}
+	kind := suffix
+	if kind == "" {
+		kind = "success"
+	}
+	target, err := url.Parse(p.base)
+	if err != nil {
+		logging.Warnf("healthcheck: %v", err)
+		return
+	}
+	if suffix != "" {
+		target.Path = strings.TrimSuffix(target.Path, "/") + "/" + suffix
+	}
+
+	method := http.MethodGet
+	if body != "" {
+		method = http.MethodPost
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), strings.NewReader(body))
+	if err != nil {
+		logging.Warnf("healthcheck: %v", err)
+		return
+	}
+	if body != "" {
+		req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		logging.Warnf("healthcheck: %s ping failed: %v", kind, err)
+		return
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		logging.Warnf("healthcheck: %s ping returned HTTP %d", kind, resp.StatusCode)
+		return
+	}
+	logging.Debugf("healthcheck: sent %s ping", kind)
+}
@@ -0,0 +1,92 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type ping struct {
+	method, path, body string
+}
+
+func monitor(t *testing.T, status int) (*httptest.Server, func() []ping) {
+	t.Helper()
+	var (
+		mu    sync.Mutex
+		pings []ping
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		pings = append(pings, ping{r.Method, r.URL.Path, string(body)})
+		mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() []ping {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]ping(nil), pings...)
+	}
+}
+
+func TestPingerLifecycle(t *testing.T) {
+	srv, pings := monitor(t, http.StatusOK)
+	p, err := New(srv.URL + "/abc-123/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	p.Start(ctx)
+	p.Finish(ctx, "refresh", 2*time.Second, nil)
+	p.Finish(ctx, "refresh", time.Second, errors.New("boom"))
+
+	got := pings()
+	if len(got) != 3 {
+		t.Fatalf("got %d pings, want 3: %+v", len(got), got)
+	}
+	if got[0].path != "/abc-123/start" || got[0].method != http.MethodGet {
+		t.Errorf("start ping = %+v", got[0])
+	}
+	if got[1].path != "/abc-123/" || !strings.Contains(got[1].body, "refresh succeeded in 2s") {
+		t.Errorf("success ping = %+v", got[1])
+	}
+	if got[2].path != "/abc-123/fail" || !strings.Contains(got[2].body, "failed after 1s: boom") {
+		t.Errorf("fail ping = %+v", got[2])
+	}
+}
+
+func TestPingerIgnoresMonitorErrors(t *testing.T) {
+	srv, pings := monitor(t, http.StatusInternalServerError)
+	p, err := New(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Start(context.Background())
+	if len(pings()) != 1 {
+		t.Errorf("expected the ping to be sent once")
+	}
+}
+
+func TestNew(t *testing.T) {
+	if p, err := New("  "); p != nil || err != nil {
+		t.Errorf("empty URL = %v, %v; want nil, nil", p, err)
+	}
+	for _, bad := range []string{"hc-ping.com/abc", "ftp://example.com/x", "https://"} {
+		if _, err := New(bad); err == nil {
+			t.Errorf("New(%q) = nil error", bad)
+		}
+	}
+
+	// A nil Pinger is a no-op.
+	var p *Pinger
+	p.Start(context.Background())
+	p.Finish(context.Background(), "refresh", 0, nil)
+}
@@ -1,19 +1,90 @@
 package helpers
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sync"
+	"syscall"
+	"time"
 )
 
+// Default write retry policy; see ConfigureWriteRetry.
+const (
+	DefaultWriteAttempts   = 3
+	DefaultWriteRetryDelay = 200 * time.Millisecond
+)
+
+// writeRetry is the retry policy of WriteFileAtomic.
+var writeRetry = struct {
+	sync.RWMutex
+	attempts int
+	delay    time.Duration
+}{attempts: DefaultWriteAttempts, delay: DefaultWriteRetryDelay}
+
+// ConfigureWriteRetry sets how many times WriteFileAtomic attempts a write
+// and the delay before the first retry, doubled before each later one. Home
+// directories on NFS or SMB fail writes now and then with errors that clear
+// on their own (a stale handle, a busy file, a timed-out server); one
+// attempt means no retry. Values below 1 attempt or 0 delay are raised to
+// them.
+func ConfigureWriteRetry(attempts int, delay time.Duration) {
+	writeRetry.Lock()
+	defer writeRetry.Unlock()
+	writeRetry.attempts = max(attempts, 1)
+	writeRetry.delay = max(delay, 0)
+}
+
 // WriteFileAtomic writes data to path via a temp file in the same directory
 // followed by an atomic rename. The rename is atomic on POSIX filesystems; a
 // crash mid-write cannot leave path in a partially-written state. The temp
 // file is removed on error so the directory does not accumulate orphans.
 //
 // The temp file is created with 0600 (os.CreateTemp default). perm is applied
-// via os.Chmod before the rename.
+// via os.Chmod before the rename. The temp file is fsynced before the rename
+// and the directory after it, so the new content survives a crash once
+// WriteFileAtomic returns.
+//
+// On Windows the rename fails while another process (an editor, a virus
+// scanner, a concurrent reader) has the destination open, so it is retried
+// briefly before giving up. A failed write is retried as a whole under the
+// ConfigureWriteRetry policy, except for errors a retry cannot fix: a
+// missing directory, a permission error, a full or read-only filesystem.
 func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	writeRetry.RLock()
+	attempts, delay := writeRetry.attempts, writeRetry.delay
+	writeRetry.RUnlock()
+
+	err := writeAttempt(path, data, perm)
+	for attempt := 2; err != nil && attempt <= attempts && retryableWriteError(err); attempt++ {
+		time.Sleep(delay)
+		delay *= 2
+		err = writeAttempt(path, data, perm)
+		if err != nil && attempt == attempts {
+			return fmt.Errorf("%w (after %d attempts)", err, attempts)
+		}
+	}
+	return err
+}
+
+// writeAttempt is one write; tests replace it to inject failures.
+var writeAttempt = writeFileAtomicOnce
+
+// retryableWriteError reports whether a failed write may succeed if tried
+// again.
+func retryableWriteError(err error) bool {
+	switch {
+	case errors.Is(err, fs.ErrNotExist), errors.Is(err, fs.ErrPermission),
+		errors.Is(err, syscall.ENOSPC), errors.Is(err, syscall.EROFS):
+		return false
+	}
+	return true
+}
+
+func writeFileAtomicOnce(path string, data []byte, perm os.FileMode) error {
 	dir := filepath.Dir(path)
 	base := filepath.Base(path)
 
@@ -49,10 +120,37 @@ func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
 		return fmt.Errorf("atomic write: chmod temp file %s: %w", tmpPath, err)
 	}
 
-	if err := os.Rename(tmpPath, path); err != nil {
+	if err := renameWithRetry(tmpPath, path); err != nil {
 		cleanup()
 		return fmt.Errorf("atomic write: rename %s to %s: %w", tmpPath, path, err)
 	}
+	syncDir(dir)
 
 	return nil
 }
+
+// renameWithRetry is os.Rename, retried with backoff on Windows where
+// sharing violations on the destination are transient.
+func renameWithRetry(from, to string) error {
+	err := os.Rename(from, to)
+	if runtime.GOOS != "windows" {
+		return err
+	}
+	for delay := 10 * time.Millisecond; err != nil && delay <= 320*time.Millisecond; delay *= 2 {
+		time.Sleep(delay)
+		err = os.Rename(from, to)
+	}
+	return err
+}
+
+// syncDir fsyncs a directory so a rename in it is durable. Filesystems and
+// platforms that cannot sync a directory (Windows, some network mounts)
+// report an error, which is ignored: the rename itself has succeeded.
+func syncDir(dir string) {
+	d, err := os.Open(dir) // #nosec G304 -- directory of a path the caller chose
+	if err != nil {
+		return
+	}
+	_ = d.Sync()
+	_ = d.Close()
+}
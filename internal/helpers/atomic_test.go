@@ -126,3 +126,44 @@ func TestWriteFileAtomic_Concurrent(t *testing.T) {
 		}
 	}
 }
+
+func TestWriteFileAtomic_RetriesTransientErrors(t *testing.T) {
+	ConfigureWriteRetry(3, 0)
+	defer ConfigureWriteRetry(DefaultWriteAttempts, DefaultWriteRetryDelay)
+	defer func() { writeAttempt = writeFileAtomicOnce }()
+
+	path := filepath.Join(t.TempDir(), "out.json")
+	calls := 0
+	writeAttempt = func(p string, data []byte, perm os.FileMode) error {
+		calls++
+		if calls < 3 {
+			return errors.New("stale file handle")
+		}
+		return writeFileAtomicOnce(p, data, perm)
+	}
+	if err := WriteFileAtomic(path, []byte("ok"), 0600); err != nil {
+		t.Fatalf("WriteFileAtomic: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("attempts = %d, want 3", calls)
+	}
+
+	calls = 0
+	writeAttempt = func(string, []byte, os.FileMode) error {
+		calls++
+		return errors.New("stale file handle")
+	}
+	err := WriteFileAtomic(path, []byte("ok"), 0600)
+	if err == nil || !strings.Contains(err.Error(), "after 3 attempts") {
+		t.Errorf("err = %v, want giving up after 3 attempts", err)
+	}
+
+	calls = 0
+	writeAttempt = func(string, []byte, os.FileMode) error {
+		calls++
+		return &os.PathError{Op: "open", Path: path, Err: os.ErrPermission}
+	}
+	if err := WriteFileAtomic(path, []byte("ok"), 0600); err == nil || calls != 1 {
+		t.Errorf("permission error: err = %v after %d attempts, want one attempt", err, calls)
+	}
+}
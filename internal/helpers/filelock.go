@@ -0,0 +1,74 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Lock file tuning. A lock older than lockStaleAfter is assumed to belong to
+// a process that died without unlocking and is removed.
+var (
+	lockRetryInterval = 50 * time.Millisecond
+	lockStaleAfter    = 2 * time.Minute
+)
+
+// LockFile takes an advisory cross-process lock on path by creating
+// "<path>.lock" exclusively. It retries until timeout and returns a function
+// that releases the lock. O_EXCL creation behaves the same on POSIX and
+// Windows, so no platform-specific flock/LockFileEx code is needed.
+func LockFile(path string, timeout time.Duration) (func(), error) {
+	lockPath := path + ".lock"
+	deadline := time.Now().Add(timeout)
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600) // #nosec G304 -- lock beside operator-controlled path
+		if err == nil {
+			_, _ = fmt.Fprintf(f, "%d\n", os.Getpid())
+			_ = f.Close()
+			return func() { _ = os.Remove(lockPath) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("lock %s: %w", path, err)
+		}
+		if info, statErr := os.Stat(lockPath); statErr == nil && time.Since(info.ModTime()) > lockStaleAfter {
+			_, _ = BreakLock(lockPath, func(claimed string) bool {
+				info, err := os.Stat(claimed)
+				return err == nil && time.Since(info.ModTime()) > lockStaleAfter
+			})
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("lock %s: held by another wifimgr process (remove %s if none is running)", path, lockPath)
+		}
+		time.Sleep(lockRetryInterval)
+	}
+}
+
+// BreakLock removes the lock file at lockPath if stale still holds for it.
+// Checking and then removing lockPath directly would race with another
+// process that broke the same lock and created a fresh one in between, so
+// the lock is first renamed to a name unique to this process and stale is
+// asked about that file. Only a file this process claimed is ever removed; a
+// fresh lock claimed by mistake is linked back under lockPath (never over a
+// newer one). It reports whether a lock was removed.
+func BreakLock(lockPath string, stale func(claimed string) bool) (bool, error) {
+	claimed := fmt.Sprintf("%s.break-%d-%d", lockPath, os.Getpid(), time.Now().UnixNano())
+	if err := os.Rename(lockPath, claimed); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil // someone else broke or released it first
+		}
+		return false, fmt.Errorf("break lock %s: %w", lockPath, err)
+	}
+	if stale(claimed) {
+		if err := os.Remove(claimed); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return false, fmt.Errorf("break lock %s: %w", lockPath, err)
+		}
+		return true, nil
+	}
+	if err := os.Link(claimed, lockPath); err != nil && !errors.Is(err, os.ErrExist) {
+		return false, fmt.Errorf("restore lock %s (left at %s): %w", lockPath, claimed, err)
+	}
+	_ = os.Remove(claimed)
+	return false, nil
+}
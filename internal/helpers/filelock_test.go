@@ -0,0 +1,77 @@
+package helpers
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLockFile_ExcludesSecondHolder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+
+	unlock, err := LockFile(path, time.Second)
+	if err != nil {
+		t.Fatalf("LockFile: %v", err)
+	}
+	if _, err := LockFile(path, 100*time.Millisecond); err == nil || !strings.Contains(err.Error(), "held by another") {
+		t.Fatalf("second LockFile = %v, want held error", err)
+	}
+
+	unlock()
+	unlock2, err := LockFile(path, time.Second)
+	if err != nil {
+		t.Fatalf("LockFile after unlock: %v", err)
+	}
+	unlock2()
+	if _, err := os.Stat(path + ".lock"); !os.IsNotExist(err) {
+		t.Errorf("lock file should be removed, stat err = %v", err)
+	}
+}
+
+func TestLockFile_BreaksStaleLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	if err := os.WriteFile(path+".lock", []byte("999999\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * lockStaleAfter)
+	if err := os.Chtimes(path+".lock", old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	unlock, err := LockFile(path, 100*time.Millisecond)
+	if err != nil {
+		t.Fatalf("stale lock was not broken: %v", err)
+	}
+	unlock()
+}
+
+func TestBreakLock_KeepsFreshLock(t *testing.T) {
+	lockPath := filepath.Join(t.TempDir(), "cache.json.lock")
+	if err := os.WriteFile(lockPath, []byte("fresh\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// The caller judged an older lock stale, but by now a fresh one is in
+	// its place: it must survive under its original name.
+	removed, err := BreakLock(lockPath, func(string) bool { return false })
+	if err != nil || removed {
+		t.Fatalf("BreakLock = %v, %v; want false, nil", removed, err)
+	}
+	data, err := os.ReadFile(lockPath)
+	if err != nil || string(data) != "fresh\n" {
+		t.Fatalf("fresh lock not restored: %q, %v", data, err)
+	}
+	if leftovers, _ := filepath.Glob(lockPath + ".break-*"); len(leftovers) != 0 {
+		t.Errorf("claimed files left behind: %v", leftovers)
+	}
+}
+
+func TestBreakLock_MissingLock(t *testing.T) {
+	lockPath := filepath.Join(t.TempDir(), "cache.json.lock")
+	removed, err := BreakLock(lockPath, func(string) bool { return true })
+	if err != nil || removed {
+		t.Fatalf("BreakLock = %v, %v; want false, nil", removed, err)
+	}
+}
@@ -0,0 +1,151 @@
+// Package history records each apply as one JSON line in CHANGELOG.jsonl in
+// the config directory, and reads those entries back for 'wifimgr history'.
+// The file is append-only: a line per apply run, never rewritten, so it can be
+// tailed, grepped, or shipped to a log pipeline as-is.
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ravinald/wifimgr/internal/sitehealth"
+	"github.com/ravinald/wifimgr/internal/warnings"
+)
+
+// FileName is the changelog file created in the config directory.
+const FileName = "CHANGELOG.jsonl"
+
+// Results recorded on an entry.
+const (
+	ResultSuccess  = "success"
+	ResultFailed   = "failed"
+	ResultDiverged = "diverged"
+)
+
+// DeviceTypeRawAPI is the device type of an entry recording an 'api put'
+// call rather than an apply.
+const DeviceTypeRawAPI = "api"
+
+// Entry is one apply run against one site and device type, or one raw API
+// write (DeviceTypeRawAPI, with Method and Path set).
+type Entry struct {
+	Timestamp    time.Time `json:"timestamp"`
+	Site         string    `json:"site"`
+	API          string    `json:"api"`
+	DeviceType   string    `json:"device_type"`
+	Assigned     []string  `json:"assigned,omitempty"`
+	Updated      []string  `json:"updated,omitempty"`
+	Unassigned   []string  `json:"unassigned,omitempty"`
+	WLANsChanged int       `json:"wlans_changed"`
+	Method       string    `json:"method,omitempty"`
+	Path         string    `json:"path,omitempty"`
+	Operator     string    `json:"operator"`
+	DurationMS   int64     `json:"duration_ms"`
+	Result       string    `json:"result"`
+	Error        string    `json:"error,omitempty"`
+	// Warnings are the non-fatal issues raised during the run, deduplicated.
+	Warnings []warnings.Warning `json:"warnings,omitempty"`
+	// Health is the site's health before and after the run, when
+	// apply.health_check is on.
+	Health *sitehealth.Comparison `json:"health,omitempty"`
+}
+
+// DevicesChanged returns the number of devices the run touched.
+func (e Entry) DevicesChanged() int {
+	return len(e.Assigned) + len(e.Updated) + len(e.Unassigned)
+}
+
+// Path returns the changelog path for a config directory.
+func Path(configDir string) string {
+	return filepath.Join(configDir, FileName)
+}
+
+// Append writes e as one line to the changelog in configDir, creating the file
+// if needed. Timestamp and Operator are filled in when empty.
+func Append(configDir string, e Entry) error {
+	if e.Timestamp.IsZero() {
+		e.Timestamp = time.Now().UTC()
+	}
+	if e.Operator == "" {
+		e.Operator = Operator()
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("history: marshal: %w", err)
+	}
+	path := Path(configDir)
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600) // #nosec G304 -- path from operator-controlled config
+	if err != nil {
+		return fmt.Errorf("history: open %s: %w", path, err)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("history: write %s: %w", path, err)
+	}
+	return f.Close()
+}
+
+// Read returns the entries in the changelog at path, oldest first, keeping
+// only those for site (matched case-insensitively) when site is non-empty. A
+// missing file yields no entries; a malformed line is an error naming it.
+func Read(path, site string) ([]Entry, error) {
+	f, err := os.Open(path) // #nosec G304 -- path from operator-controlled config
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var out []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal([]byte(text), &e); err != nil {
+			return nil, fmt.Errorf("history: %s line %d: %w", path, n, err)
+		}
+		if site != "" && !strings.EqualFold(e.Site, site) {
+			continue
+		}
+		out = append(out, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("history: read %s: %w", path, err)
+	}
+	return out, nil
+}
+
+// Account returns the OS login running wifimgr, or "" when it cannot be
+// determined. Unlike Operator it cannot be changed from the environment, so
+// it is what checks between people (the two-person rule) compare.
+func Account() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return ""
+}
+
+// Operator names who ran the apply: WIFIMGR_OPERATOR when set (for shared
+// service accounts), else the OS user. It is a display name only.
+func Operator() string {
+	if op := os.Getenv("WIFIMGR_OPERATOR"); op != "" {
+		return op
+	}
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
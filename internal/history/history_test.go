@@ -0,0 +1,68 @@
+package history
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestAppendAndRead(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("WIFIMGR_OPERATOR", "netops")
+
+	if err := Append(dir, Entry{Site: "US-LAB-01", API: "mist-prod", DeviceType: "ap", Updated: []string{"aa0000000001"}, WLANsChanged: 2, DurationMS: 1500, Result: ResultSuccess}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Append(dir, Entry{Site: "US-HQ-01", DeviceType: "switch", Result: ResultFailed, Error: "boom"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Append(dir, Entry{Site: "us-lab-01", DeviceType: "ap", Assigned: []string{"aa0000000002"}, Result: ResultSuccess}); err != nil {
+		t.Fatal(err)
+	}
+
+	all, err := Read(Path(dir), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("got %d entries, want 3", len(all))
+	}
+	if all[0].Operator != "netops" || all[0].Timestamp.IsZero() || all[0].WLANsChanged != 2 {
+		t.Errorf("first entry = %+v", all[0])
+	}
+
+	lab, err := Read(Path(dir), "US-LAB-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lab) != 2 || lab[1].DevicesChanged() != 1 {
+		t.Errorf("site filter = %+v", lab)
+	}
+}
+
+func TestReadMissingAndMalformed(t *testing.T) {
+	dir := t.TempDir()
+	entries, err := Read(Path(dir), "")
+	if err != nil || entries != nil {
+		t.Errorf("missing file = %v, %v; want nil, nil", entries, err)
+	}
+
+	if err := os.WriteFile(Path(dir), []byte("{\"site\":\"A\"}\nnot json\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Read(Path(dir), ""); err == nil {
+		t.Error("expected error for malformed line")
+	}
+}
+
+func TestAppendKeepsGivenTimestamp(t *testing.T) {
+	dir := t.TempDir()
+	ts := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	if err := Append(dir, Entry{Timestamp: ts, Site: "A", Operator: "me"}); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := Read(Path(dir), "A")
+	if len(got) != 1 || !got[0].Timestamp.Equal(ts) || got[0].Operator != "me" {
+		t.Errorf("got %+v", got)
+	}
+}
@@ -0,0 +1,154 @@
+// Package i18n translates user-facing CLI strings: status prefixes, prompts,
+// and warnings. Messages are looked up by key in the active locale's bundle,
+// then in English, then returned as the key itself, so a missing translation
+// never hides a message. Bundles are JSON files embedded from locales/.
+//
+// Structured output (json/csv), log lines, and API field names are not
+// translated.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultLocale is used when no configured or environment locale has a bundle.
+const DefaultLocale = "en"
+
+// EnvLocale overrides every other locale source.
+const EnvLocale = "WIFIMGR_LOCALE"
+
+//go:embed locales/*.json
+var bundleFS embed.FS
+
+var (
+	mu      sync.RWMutex
+	active  = DefaultLocale
+	bundles = loadBundles()
+)
+
+func loadBundles() map[string]map[string]string {
+	out := make(map[string]map[string]string)
+	entries, err := bundleFS.ReadDir("locales")
+	if err != nil {
+		return out
+	}
+	for _, e := range entries {
+		data, err := bundleFS.ReadFile(path.Join("locales", e.Name()))
+		if err != nil {
+			continue
+		}
+		var messages map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			continue
+		}
+		out[strings.TrimSuffix(e.Name(), ".json")] = messages
+	}
+	return out
+}
+
+// normalize reduces a locale tag to its language: "es_ES.UTF-8", "es-MX",
+// and "ES" all become "es". "C" and "POSIX" mean English.
+func normalize(tag string) string {
+	tag = strings.TrimSpace(tag)
+	if i := strings.IndexAny(tag, ".@"); i >= 0 {
+		tag = tag[:i]
+	}
+	if i := strings.IndexAny(tag, "_-"); i >= 0 {
+		tag = tag[:i]
+	}
+	tag = strings.ToLower(tag)
+	if tag == "c" || tag == "posix" {
+		return DefaultLocale
+	}
+	return tag
+}
+
+// SetLocale activates the bundle for tag and returns the locale in effect,
+// which is DefaultLocale when tag has no bundle.
+func SetLocale(tag string) string {
+	lang := normalize(tag)
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := bundles[lang]; !ok {
+		lang = DefaultLocale
+	}
+	active = lang
+	return active
+}
+
+// Configure picks the locale from, in order: WIFIMGR_LOCALE, the configured
+// value (display.locale), then LC_ALL, LC_MESSAGES, and LANG. The first one
+// naming a language with a bundle wins. Returns the locale in effect.
+func Configure(configured string) string {
+	for _, tag := range []string{
+		os.Getenv(EnvLocale),
+		configured,
+		os.Getenv("LC_ALL"),
+		os.Getenv("LC_MESSAGES"),
+		os.Getenv("LANG"),
+	} {
+		if tag == "" {
+			continue
+		}
+		if _, ok := bundles[normalize(tag)]; ok {
+			return SetLocale(tag)
+		}
+	}
+	return SetLocale(DefaultLocale)
+}
+
+// Locale returns the active locale.
+func Locale() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return active
+}
+
+// Available lists the locales with a bundle.
+func Available() []string {
+	out := make([]string, 0, len(bundles))
+	for lang := range bundles {
+		out = append(out, lang)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// T returns the message for key in the active locale, formatted with args
+// as by fmt.Sprintf when any are given.
+func T(key string, args ...any) string {
+	mu.RLock()
+	msg, ok := bundles[active][key]
+	mu.RUnlock()
+	if !ok {
+		if msg, ok = bundles[DefaultLocale][key]; !ok {
+			msg = key
+		}
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// IsYes reports whether answer accepts a y/N prompt in the active locale.
+// English "y" and "yes" are always accepted.
+func IsYes(answer string) bool {
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	if answer == "" {
+		return false
+	}
+	for _, word := range strings.Split(T("answer.yes")+",y,yes", ",") {
+		if answer == strings.TrimSpace(word) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package i18n
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"es_ES.UTF-8": "es",
+		"es-MX":       "es",
+		"ES":          "es",
+		"en_US@euro":  "en",
+		"C":           "en",
+		"POSIX":       "en",
+	}
+	for in, want := range tests {
+		if got := normalize(in); got != want {
+			t.Errorf("normalize(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestConfigurePrecedence(t *testing.T) {
+	defer SetLocale(DefaultLocale)
+
+	t.Setenv(EnvLocale, "")
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "es_ES.UTF-8")
+	if got := Configure(""); got != "es" {
+		t.Errorf("LANG=es_ES: got %q, want es", got)
+	}
+	if got := Configure("en"); got != "en" {
+		t.Errorf("config locale should beat LANG: got %q", got)
+	}
+	t.Setenv(EnvLocale, "es")
+	if got := Configure("en"); got != "es" {
+		t.Errorf("%s should beat config: got %q", EnvLocale, got)
+	}
+	t.Setenv(EnvLocale, "xx")
+	t.Setenv("LANG", "fr_FR.UTF-8")
+	if got := Configure(""); got != DefaultLocale {
+		t.Errorf("unknown locales should fall back: got %q", got)
+	}
+}
+
+func TestT(t *testing.T) {
+	defer SetLocale(DefaultLocale)
+
+	SetLocale("es")
+	if got := T("prefix.warn"); got != "[AVISO]" {
+		t.Errorf("prefix.warn = %q", got)
+	}
+	if got := T("reconcile.match", "US-LAB-01"); got != "El sitio US-LAB-01 coincide con la intención" {
+		t.Errorf("formatted = %q", got)
+	}
+	if got := T("no.such.key"); got != "no.such.key" {
+		t.Errorf("missing key should return the key, got %q", got)
+	}
+	if !IsYes("sí") || !IsYes("y") || IsYes("n") || IsYes("") {
+		t.Error("IsYes should accept localized and English yes only")
+	}
+}
+
+// Every bundle must translate every English key, with the same verbs.
+func TestBundlesComplete(t *testing.T) {
+	en := bundles[DefaultLocale]
+	for lang, messages := range bundles {
+		for key, msg := range en {
+			tr, ok := messages[key]
+			if !ok {
+				t.Errorf("%s: missing %q", lang, key)
+				continue
+			}
+			if countVerbs(tr) != countVerbs(msg) {
+				t.Errorf("%s: %q has different format verbs than English", lang, key)
+			}
+		}
+		for key := range messages {
+			if _, ok := en[key]; !ok {
+				t.Errorf("%s: %q is not an English key", lang, key)
+			}
+		}
+	}
+	if len(Available()) < 2 {
+		t.Errorf("expected at least two bundles, got %v", Available())
+	}
+}
+
+func countVerbs(s string) int {
+	n := 0
+	for i := 0; i < len(s)-1; i++ {
+		if s[i] == '%' {
+			if s[i+1] == '%' {
+				i++
+				continue
+			}
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,310 @@
+// Package integrations is the registry of external systems wifimgr talks to
+// besides the vendor APIs: NetBox, DNS, the remote backup bucket, and so on.
+//
+// An integration registers itself from its package's init with a name, the
+// config section it reads, and whichever hooks it needs. Commands fire the
+// hooks through this package — PostApply after an apply run, PostRefresh
+// after the cache is rewritten, Notify for user-facing events — and never
+// name an integration, so adding one means writing its package and importing
+// it (a blank import in cmd/integrations.go), with no change to any command.
+package integrations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"github.com/spf13/viper"
+
+	"github.com/ravinald/wifimgr/internal/logging"
+	"github.com/ravinald/wifimgr/internal/schemadefs"
+	"github.com/ravinald/wifimgr/internal/sitehealth"
+)
+
+// Integration describes one registered integration. Only Name and ConfigKey
+// are required; a nil hook is skipped.
+type Integration struct {
+	Name      string // short, unique, e.g. "netbox"
+	ConfigKey string // dotted key of its config section, e.g. "backup.remote"
+
+	// Schema is the JSON Schema (draft 7) of the config section. When empty,
+	// the section's schema is taken from the embedded app config schema, if
+	// it has one.
+	Schema json.RawMessage
+
+	// EndpointKeys and CredentialKeys name the config keys that locate and
+	// authenticate the integration, for the hints on a failed check.
+	EndpointKeys   string
+	CredentialKeys string
+
+	// Configured reports whether the integration is set up. Hooks of an
+	// integration that is not configured are not called. nil means always.
+	Configured func() bool
+
+	// Check tests connectivity and credentials without writing anything and
+	// returns a short description of what it reached.
+	Check func(ctx context.Context) (string, error)
+	// Hint optionally explains a failed Check; when it returns "", the
+	// generic hint built from EndpointKeys and CredentialKeys is used.
+	Hint func(err error) string
+
+	PostApply   func(ctx context.Context, ev ApplyEvent) error
+	PostRefresh func(ctx context.Context, ev RefreshEvent) error
+	Notify      func(ctx context.Context, n Notification) error
+}
+
+// ApplyEvent describes one finished apply run for a site and device type.
+// It is sent for runs that changed something or failed, never for diffs.
+type ApplyEvent struct {
+	Site         string
+	API          string
+	DeviceType   string
+	Assigned     []string // device MACs
+	Updated      []string
+	Unassigned   []string
+	WLANsChanged int
+	Err          error // nil when the run succeeded
+	ConfigDir    string
+	// Health is the site's health before and after the run, when
+	// apply.health_check is on.
+	Health *sitehealth.Comparison
+}
+
+// RefreshEvent describes a finished cache refresh.
+type RefreshEvent struct {
+	APIs  []string // API labels refreshed
+	Files []string // cache files written
+}
+
+// Notification kinds.
+const (
+	// NotifyWLANChange is a WLAN change that makes users rejoin; Data is a
+	// notify.Announcement.
+	NotifyWLANChange = "wlan_change"
+	// NotifySLABreach is a site over a capacity or availability threshold;
+	// Data is an sla.Breach.
+	NotifySLABreach = "sla_breach"
+	// NotifyApplyHealth is a site's health before and after an apply; Data
+	// is a sitehealth.Comparison.
+	NotifyApplyHealth = "apply_health"
+)
+
+// Notification is a user-facing event. Subject is a one-line summary any
+// integration can relay; Data carries the kind-specific detail. Details is
+// the part of that detail safe to send off-host (never a passphrase or
+// other secret), for integrations that relay events to other systems.
+type Notification struct {
+	Kind      string
+	Site      string
+	Subject   string
+	Time      time.Time
+	Data      any
+	Details   map[string]any
+	ConfigDir string
+}
+
+var (
+	mu       sync.RWMutex
+	registry = map[string]Integration{}
+)
+
+// Register adds an integration. It panics on a missing name or config key
+// and on a duplicate name, as registration happens in init and either is a
+// programming error.
+func Register(i Integration) {
+	if i.Name == "" || i.ConfigKey == "" {
+		panic("integrations: Register needs a name and a config key")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if _, dup := registry[i.Name]; dup {
+		panic("integrations: Register called twice for " + i.Name)
+	}
+	registry[i.Name] = i
+}
+
+// All returns the registered integrations sorted by name.
+func All() []Integration {
+	mu.RLock()
+	defer mu.RUnlock()
+	out := make([]Integration, 0, len(registry))
+	for _, i := range registry {
+		out = append(out, i)
+	}
+	sort.Slice(out, func(a, b int) bool { return out[a].Name < out[b].Name })
+	return out
+}
+
+// Lookup returns the integration registered under name.
+func Lookup(name string) (Integration, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	i, ok := registry[name]
+	return i, ok
+}
+
+// IsConfigured reports whether i is set up.
+func (i Integration) IsConfigured() bool {
+	return i.Configured == nil || i.Configured()
+}
+
+// Hooks lists the hooks i implements, for display.
+func (i Integration) Hooks() []string {
+	var hooks []string
+	if i.Check != nil {
+		hooks = append(hooks, "check")
+	}
+	if i.PostApply != nil {
+		hooks = append(hooks, "post-apply")
+	}
+	if i.PostRefresh != nil {
+		hooks = append(hooks, "post-refresh")
+	}
+	if i.Notify != nil {
+		hooks = append(hooks, "notify")
+	}
+	return hooks
+}
+
+// PostApply sends ev to every configured integration with a post-apply hook.
+// Hooks are best effort: a failure is logged, never returned, since the
+// apply itself already happened.
+func PostApply(ctx context.Context, ev ApplyEvent) {
+	for _, i := range All() {
+		if i.PostApply != nil && i.IsConfigured() {
+			if err := i.PostApply(ctx, ev); err != nil {
+				logging.Warnf("Integration %s: post-apply hook failed: %v", i.Name, err)
+			}
+		}
+	}
+}
+
+// PostRefresh sends ev to every configured integration with a post-refresh
+// hook. Failures are logged, never returned.
+func PostRefresh(ctx context.Context, ev RefreshEvent) {
+	for _, i := range All() {
+		if i.PostRefresh != nil && i.IsConfigured() {
+			if err := i.PostRefresh(ctx, ev); err != nil {
+				logging.Warnf("Integration %s: post-refresh hook failed: %v", i.Name, err)
+			}
+		}
+	}
+}
+
+// ErrNotHandled is returned by a notify hook for a notification kind it does
+// not handle, so that Notify does not count it as delivered.
+var ErrNotHandled = errors.New("notification kind not handled")
+
+// Notify sends n to every configured integration with a notify hook and
+// returns how many took it. Failures are logged, never returned.
+func Notify(ctx context.Context, n Notification) int {
+	delivered := 0
+	for _, i := range All() {
+		if i.Notify != nil && i.IsConfigured() {
+			err := i.Notify(ctx, n)
+			if errors.Is(err, ErrNotHandled) {
+				continue
+			}
+			if err != nil {
+				logging.Warnf("Integration %s: notify hook failed: %v", i.Name, err)
+				continue
+			}
+			delivered++
+		}
+	}
+	return delivered
+}
+
+// ValidateConfig checks i's config section against its schema. A section
+// that is unset, or an integration without a schema, passes.
+func ValidateConfig(i Integration) error {
+	raw := i.Schema
+	if len(raw) == 0 {
+		raw = appConfigSection(i.ConfigKey)
+	}
+	value := viper.Get(i.ConfigKey)
+	if len(raw) == 0 || value == nil {
+		return nil
+	}
+
+	compiler := jsonschema.NewCompiler()
+	compiler.Draft = jsonschema.Draft7
+	id := "integration://" + i.Name
+	if err := compiler.AddResource(id, strings.NewReader(string(raw))); err != nil {
+		return fmt.Errorf("invalid schema for integration %s: %w", i.Name, err)
+	}
+	schema, err := compiler.Compile(id)
+	if err != nil {
+		return fmt.Errorf("invalid schema for integration %s: %w", i.Name, err)
+	}
+
+	// Round-trip through JSON so viper's map types validate as plain JSON.
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	if err := schema.Validate(withoutComments(doc)); err != nil {
+		return fmt.Errorf("%s: %w", i.ConfigKey, err)
+	}
+	return nil
+}
+
+// withoutComments drops "_comment..." keys, which the sample configs use for
+// annotations the schemas do not declare.
+func withoutComments(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(t))
+		for k, e := range t {
+			if !strings.HasPrefix(k, "_comment") {
+				out[k] = withoutComments(e)
+			}
+		}
+		return out
+	case []any:
+		out := make([]any, len(t))
+		for n, e := range t {
+			out[n] = withoutComments(e)
+		}
+		return out
+	}
+	return v
+}
+
+// appConfigSection returns the schema of a dotted key in the embedded app
+// config schema, or nil when the schema does not describe it.
+func appConfigSection(key string) json.RawMessage {
+	data, err := schemadefs.Read("app-config-schema.json")
+	if err != nil {
+		return nil
+	}
+	var node map[string]json.RawMessage
+	if err := json.Unmarshal(data, &node); err != nil {
+		return nil
+	}
+	var section json.RawMessage
+	for _, part := range strings.Split(key, ".") {
+		var props map[string]json.RawMessage
+		if err := json.Unmarshal(node["properties"], &props); err != nil {
+			return nil
+		}
+		if section = props[part]; section == nil {
+			return nil
+		}
+		node = nil
+		if err := json.Unmarshal(section, &node); err != nil {
+			return nil
+		}
+	}
+	return section
+}
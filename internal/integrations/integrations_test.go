@@ -0,0 +1,132 @@
+package integrations
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+// register adds i for the duration of the test.
+func register(t *testing.T, i Integration) {
+	t.Helper()
+	Register(i)
+	t.Cleanup(func() {
+		mu.Lock()
+		delete(registry, i.Name)
+		mu.Unlock()
+	})
+}
+
+func TestRegisterDuplicatePanics(t *testing.T) {
+	register(t, Integration{Name: "test-dup", ConfigKey: "test_dup"})
+	defer func() {
+		if recover() == nil {
+			t.Error("second Register of the same name did not panic")
+		}
+	}()
+	Register(Integration{Name: "test-dup", ConfigKey: "test_dup"})
+}
+
+func TestAllSortedByName(t *testing.T) {
+	register(t, Integration{Name: "test-b", ConfigKey: "b"})
+	register(t, Integration{Name: "test-a", ConfigKey: "a"})
+
+	var names []string
+	for _, i := range All() {
+		if strings.HasPrefix(i.Name, "test-") {
+			names = append(names, i.Name)
+		}
+	}
+	if want := []string{"test-a", "test-b"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("All() = %v, want %v", names, want)
+	}
+	if _, ok := Lookup("test-a"); !ok {
+		t.Error("Lookup(test-a) found nothing")
+	}
+}
+
+func TestHooksSkipUnconfigured(t *testing.T) {
+	var applied, refreshed []string
+	hooks := func(name string, configured bool) Integration {
+		return Integration{
+			Name:       name,
+			ConfigKey:  name,
+			Configured: func() bool { return configured },
+			PostApply: func(_ context.Context, ev ApplyEvent) error {
+				applied = append(applied, name+":"+ev.Site)
+				return nil
+			},
+			PostRefresh: func(_ context.Context, ev RefreshEvent) error {
+				refreshed = append(refreshed, name)
+				return errors.New("failures are logged, not returned")
+			},
+			Notify: func(_ context.Context, n Notification) error {
+				if n.Kind != NotifyWLANChange {
+					return ErrNotHandled
+				}
+				return nil
+			},
+		}
+	}
+	register(t, hooks("test-on", true))
+	register(t, hooks("test-off", false))
+
+	PostApply(context.Background(), ApplyEvent{Site: "US-LAB-01"})
+	PostRefresh(context.Background(), RefreshEvent{})
+	if want := []string{"test-on:US-LAB-01"}; !reflect.DeepEqual(applied, want) {
+		t.Errorf("post-apply ran for %v, want %v", applied, want)
+	}
+	if want := []string{"test-on"}; !reflect.DeepEqual(refreshed, want) {
+		t.Errorf("post-refresh ran for %v, want %v", refreshed, want)
+	}
+	if n := Notify(context.Background(), Notification{Kind: NotifyWLANChange}); n != 1 {
+		t.Errorf("Notify delivered to %d integrations, want 1", n)
+	}
+	if n := Notify(context.Background(), Notification{Kind: NotifySLABreach}); n != 0 {
+		t.Errorf("Notify counted %d deliveries of an unhandled kind, want 0", n)
+	}
+
+	on, _ := Lookup("test-on")
+	if got, want := on.Hooks(), []string{"post-apply", "post-refresh", "notify"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Hooks() = %v, want %v", got, want)
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	i := Integration{
+		Name:      "test-schema",
+		ConfigKey: "test_schema",
+		Schema: []byte(`{
+			"type": "object",
+			"properties": {"url": {"type": "string", "pattern": "^https://"}},
+			"required": ["url"],
+			"additionalProperties": false
+		}`),
+	}
+	t.Cleanup(func() { viper.Set(i.ConfigKey, nil) })
+
+	if err := ValidateConfig(i); err != nil {
+		t.Errorf("unset section: %v", err)
+	}
+	viper.Set(i.ConfigKey, map[string]any{"url": "https://x.example", "_comment": "ignored"})
+	if err := ValidateConfig(i); err != nil {
+		t.Errorf("valid section: %v", err)
+	}
+	viper.Set(i.ConfigKey, map[string]any{"url": "http://x.example"})
+	if err := ValidateConfig(i); err == nil || !strings.HasPrefix(err.Error(), "test_schema:") {
+		t.Errorf("invalid section err = %v, want one naming test_schema", err)
+	}
+}
+
+func TestAppConfigSection(t *testing.T) {
+	if appConfigSection("apply_lock") == nil {
+		t.Error("no schema for apply_lock in the app config schema")
+	}
+	if appConfigSection("no.such.key") != nil {
+		t.Error("schema found for an undeclared key")
+	}
+}
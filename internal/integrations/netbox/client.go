@@ -14,6 +14,7 @@ import (
 
 	"github.com/ravinald/wifimgr/internal/logging"
 	"github.com/ravinald/wifimgr/internal/macaddr"
+	"github.com/ravinald/wifimgr/internal/runstats"
 )
 
 // BulkBatchSize is the maximum number of items to create/delete in a single bulk API call
@@ -49,7 +50,7 @@ func NewClient(cfg *Config) (*Client, error) {
 		{URL: url},
 	}
 	apiConfig.AddDefaultHeader("Authorization", fmt.Sprintf("Token %s", cfg.GetAPIKey()))
-	apiConfig.HTTPClient = httpClient
+	apiConfig.HTTPClient = runstats.InstrumentClient(httpClient)
 
 	client := netbox.NewAPIClient(apiConfig)
 
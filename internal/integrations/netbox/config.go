@@ -134,7 +134,7 @@ func (c *Config) GetAPIKey() string {
 // Validate checks that required configuration is present
 func (c *Config) Validate() error {
 	if c.URL == "" {
-		return fmt.Errorf("NetBox URL is required (set NETBOX_API_URL or netbox.url in config)")
+		return ErrNoURL
 	}
 	if c.APIKey == "" {
 		return fmt.Errorf("NetBox API key is required (set NETBOX_API_KEY or netbox.credentials.api_key in config)")
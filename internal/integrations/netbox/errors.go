@@ -1,10 +1,15 @@
 package netbox
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 )
 
+// ErrNoURL is returned by LoadConfig when no NetBox URL is set, which callers
+// treat as NetBox not being configured at all.
+var ErrNoURL = errors.New("NetBox URL is required (set NETBOX_API_URL or netbox.url in config)")
+
 // InterfaceTypeError is returned when an invalid interface type is used
 type InterfaceTypeError struct {
 	DeviceName  string   // Device name (if available)
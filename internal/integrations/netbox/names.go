@@ -0,0 +1,63 @@
+package netbox
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/ravinald/wifimgr/internal/logging"
+)
+
+// DeviceNames resolves the NetBox name of vendor devices. Each site's devices
+// are listed once and matched by serial; a device whose serial is not found
+// there (wrong site, or no serial recorded) falls back to the interface MAC
+// lookup the exporter uses.
+type DeviceNames struct {
+	client *Client
+
+	mu    sync.Mutex
+	sites map[string]map[string]string // site slug -> upper serial -> name
+}
+
+// NewDeviceNames returns a resolver backed by client.
+func NewDeviceNames(client *Client) *DeviceNames {
+	return &DeviceNames{client: client, sites: make(map[string]map[string]string)}
+}
+
+// Lookup returns the NetBox name of the device, or "" when NetBox has none.
+func (n *DeviceNames) Lookup(ctx context.Context, siteName, serial, mac string) (string, error) {
+	if serial != "" && siteName != "" {
+		bySerial := n.siteDevices(ctx, n.client.config.GetSiteSlug(siteName))
+		if name, ok := bySerial[strings.ToUpper(serial)]; ok {
+			return name, nil
+		}
+	}
+	device, err := n.client.GetDeviceByMAC(ctx, mac)
+	if err != nil || device == nil {
+		return "", err
+	}
+	return device.Name, nil
+}
+
+// siteDevices lists a site's devices by serial, once per slug.
+func (n *DeviceNames) siteDevices(ctx context.Context, slug string) map[string]string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if bySerial, ok := n.sites[slug]; ok {
+		return bySerial
+	}
+	// NetBox rejects a filter on a slug it does not know; the MAC fallback
+	// still finds devices filed under another site, and surfaces real errors.
+	devices, err := n.client.GetDevicesBySiteAndRole(ctx, slug, "")
+	if err != nil {
+		logging.Debugf("NetBox site %s not listed: %v", slug, err)
+	}
+	bySerial := make(map[string]string, len(devices))
+	for _, d := range devices {
+		if d.Serial != "" {
+			bySerial[strings.ToUpper(d.Serial)] = d.Name
+		}
+	}
+	n.sites[slug] = bySerial
+	return bySerial
+}
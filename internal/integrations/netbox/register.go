@@ -0,0 +1,38 @@
+package netbox
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ravinald/wifimgr/internal/integrations"
+)
+
+func init() {
+	integrations.Register(integrations.Integration{
+		Name:           "netbox",
+		ConfigKey:      "netbox",
+		EndpointKeys:   "netbox.url (NETBOX_API_URL)",
+		CredentialKeys: "netbox.credentials.api_key (NETBOX_API_KEY)",
+		Configured: func() bool {
+			_, err := LoadConfig()
+			return !errors.Is(err, ErrNoURL)
+		},
+		Check: check,
+	})
+}
+
+// check reads the NetBox status endpoint with the configured key.
+func check(ctx context.Context) (string, error) {
+	cfg, err := LoadConfig()
+	if err != nil {
+		return "", err
+	}
+	client, err := NewClient(cfg)
+	if err != nil {
+		return "", err
+	}
+	if err := client.TestConnection(ctx); err != nil {
+		return "", err
+	}
+	return cfg.URL, nil
+}
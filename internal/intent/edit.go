@@ -0,0 +1,180 @@
+package intent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ValidateSiteConfigFile checks an edited site config file before it is
+// written back: it must parse as JSON, still define the site under
+// config.sites, and pass the site-config schema. A syntax error names the line
+// and column so the operator can find it on re-edit. An empty schemaDir skips
+// the schema check.
+func ValidateSiteConfigFile(data []byte, siteKey, schemaDir string) error {
+	var root map[string]any
+	if err := json.Unmarshal(data, &root); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			line, col := lineAndColumn(data, syntaxErr.Offset)
+			return fmt.Errorf("intent: invalid JSON at line %d, column %d: %w", line, col, err)
+		}
+		return fmt.Errorf("intent: invalid JSON: %w", err)
+	}
+
+	config, _ := root["config"].(map[string]any)
+	sites, _ := config["sites"].(map[string]any)
+	if _, ok := sites[siteKey].(map[string]any); !ok {
+		return fmt.Errorf("intent: config.sites.%s is missing; rename or remove a site with the site commands", siteKey)
+	}
+
+	if schemaDir != "" {
+		return validateSiteConfig(root, schemaDir)
+	}
+	return nil
+}
+
+// lineAndColumn converts a json.SyntaxError offset, which counts the
+// offending byte, into the 1-based line and column of that byte.
+func lineAndColumn(data []byte, offset int64) (int, int) {
+	pos := int(min(max(offset-1, 0), int64(len(data))))
+	before := string(data[:pos])
+	line := strings.Count(before, "\n") + 1
+	col := pos - strings.LastIndex(before, "\n")
+	return line, col
+}
+
+// maxDiffCells bounds the line-matching table; past it the changed region is
+// shown as one removal and one addition rather than aligned line by line.
+const maxDiffCells = 4_000_000
+
+// DiffLines returns a unified diff of oldText against newText with the given
+// lines of context, or nil when they are equal. Lines are prefixed "-", "+",
+// or " ", and hunks start with a "@@ -a,b +c,d @@" header.
+func DiffLines(oldText, newText string, context int) []string {
+	if oldText == newText {
+		return nil
+	}
+	a := strings.Split(strings.TrimSuffix(oldText, "\n"), "\n")
+	b := strings.Split(strings.TrimSuffix(newText, "\n"), "\n")
+	ops := diffOps(a, b)
+
+	// Group the edit script into hunks, merging changes closer than
+	// 2*context lines apart.
+	var out []string
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			i++
+			continue
+		}
+		start := max(i-context, 0)
+		end := i
+		for j := i; j < len(ops); j++ {
+			if ops[j].kind != ' ' {
+				end = j
+			} else if j-end > 2*context {
+				break
+			}
+		}
+		end = min(end+context+1, len(ops))
+
+		hunk := ops[start:end]
+		oldStart, newStart := hunk[0].oldLine, hunk[0].newLine
+		oldCount, newCount := 0, 0
+		for _, op := range hunk {
+			if op.kind != '+' {
+				oldCount++
+			}
+			if op.kind != '-' {
+				newCount++
+			}
+		}
+		out = append(out, fmt.Sprintf("@@ -%d,%d +%d,%d @@", oldStart, oldCount, newStart, newCount))
+		for _, op := range hunk {
+			out = append(out, string(op.kind)+op.text)
+		}
+		i = end
+	}
+	return out
+}
+
+// diffOp is one line of an edit script. oldLine and newLine are the 1-based
+// positions the line has (or would have) in each file.
+type diffOp struct {
+	kind             byte // ' ', '-', or '+'
+	text             string
+	oldLine, newLine int
+}
+
+// diffOps aligns a and b by longest common subsequence after trimming the
+// shared prefix and suffix, which is all most edits touch.
+func diffOps(a, b []string) []diffOp {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+
+	ops := make([]diffOp, 0, len(a)+len(b))
+	oldLine, newLine := 1, 1
+	emit := func(kind byte, text string) {
+		ops = append(ops, diffOp{kind: kind, text: text, oldLine: oldLine, newLine: newLine})
+		if kind != '+' {
+			oldLine++
+		}
+		if kind != '-' {
+			newLine++
+		}
+	}
+
+	for _, line := range a[:prefix] {
+		emit(' ', line)
+	}
+	if len(midA)*len(midB) > maxDiffCells {
+		for _, line := range midA {
+			emit('-', line)
+		}
+		for _, line := range midB {
+			emit('+', line)
+		}
+	} else {
+		// lcs[i][j] is the LCS length of midA[i:] and midB[j:].
+		lcs := make([][]int, len(midA)+1)
+		for i := range lcs {
+			lcs[i] = make([]int, len(midB)+1)
+		}
+		for i := len(midA) - 1; i >= 0; i-- {
+			for j := len(midB) - 1; j >= 0; j-- {
+				if midA[i] == midB[j] {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				} else {
+					lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+				}
+			}
+		}
+		i, j := 0, 0
+		for i < len(midA) || j < len(midB) {
+			switch {
+			case i < len(midA) && j < len(midB) && midA[i] == midB[j]:
+				emit(' ', midA[i])
+				i++
+				j++
+			case i < len(midA) && (j == len(midB) || lcs[i+1][j] >= lcs[i][j+1]):
+				emit('-', midA[i])
+				i++
+			default:
+				emit('+', midB[j])
+				j++
+			}
+		}
+	}
+	for _, line := range a[len(a)-suffix:] {
+		emit(' ', line)
+	}
+	return ops
+}
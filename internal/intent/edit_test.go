@@ -0,0 +1,56 @@
+package intent
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateSiteConfigFile(t *testing.T) {
+	// An empty schema dir falls back to the schema embedded in the binary.
+	schemaDir := t.TempDir()
+
+	if err := ValidateSiteConfigFile([]byte(sampleConfig), "us-lab-01", schemaDir); err != nil {
+		t.Fatalf("sample config: %v", err)
+	}
+
+	cases := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"syntax error", strings.Replace(sampleConfig, `"AP-01",`, `"AP-01"`, 1), "line 12, column 15"},
+		{"site removed", strings.Replace(sampleConfig, `"us-lab-01"`, `"us-lab-02"`, 1), "config.sites.us-lab-01 is missing"},
+		{"schema violation", strings.Replace(sampleConfig, `"power": 10`, `"power": 99`, 1), "schema"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := ValidateSiteConfigFile([]byte(c.data), "us-lab-01", schemaDir)
+			if err == nil || !strings.Contains(err.Error(), c.want) {
+				t.Errorf("got %v, want an error containing %q", err, c.want)
+			}
+		})
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	if got := DiffLines("a\nb\n", "a\nb\n", 3); got != nil {
+		t.Errorf("equal texts: got %v", got)
+	}
+
+	oldText := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n"
+	newText := "1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n"
+	want := []string{
+		"@@ -2,3 +2,3 @@", " 2", "-3", "+three", " 4",
+		"@@ -12,1 +12,2 @@", " 12", "+13",
+	}
+	got := DiffLines(oldText, newText, 1)
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	// Nearby changes share one hunk.
+	got = DiffLines("a\nb\nc\nd\n", "A\nb\nC\nd\n", 1)
+	if len(got) == 0 || strings.Count(strings.Join(got, "\n"), "@@ -") != 1 {
+		t.Errorf("want a single hunk, got %v", got)
+	}
+}
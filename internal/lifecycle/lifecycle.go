@@ -0,0 +1,176 @@
+// Package lifecycle enriches inventory with hardware lifecycle dates —
+// end-of-sale, end-of-support and warranty expiry — from an operator-maintained
+// JSON file, so ageing hardware shows up before it becomes an outage.
+//
+// Entries are keyed by model (vendor EoL bulletins are per model) and may be
+// overridden per serial (warranty is per unit). Dates are YYYY-MM-DD.
+package lifecycle
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// dateLayout is the on-disk date format.
+const dateLayout = "2006-01-02"
+
+// Date is a calendar day that round-trips as YYYY-MM-DD.
+type Date struct {
+	time.Time
+}
+
+// UnmarshalJSON parses YYYY-MM-DD; an empty string leaves the date unset.
+func (d *Date) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	if s == "" {
+		d.Time = time.Time{}
+		return nil
+	}
+	t, err := time.Parse(dateLayout, s)
+	if err != nil {
+		return fmt.Errorf("invalid date %q (want YYYY-MM-DD)", s)
+	}
+	d.Time = t
+	return nil
+}
+
+// MarshalJSON writes YYYY-MM-DD, or "" when unset.
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// String returns YYYY-MM-DD, or "" when unset.
+func (d Date) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.Format(dateLayout)
+}
+
+// Record holds the lifecycle dates known for a model or unit.
+type Record struct {
+	EndOfSale    Date `json:"end_of_sale"`
+	EndOfSupport Date `json:"end_of_support"`
+	WarrantyEnd  Date `json:"warranty_end"`
+}
+
+// merge overlays the set fields of o onto r.
+func (r Record) merge(o Record) Record {
+	if !o.EndOfSale.IsZero() {
+		r.EndOfSale = o.EndOfSale
+	}
+	if !o.EndOfSupport.IsZero() {
+		r.EndOfSupport = o.EndOfSupport
+	}
+	if !o.WarrantyEnd.IsZero() {
+		r.WarrantyEnd = o.WarrantyEnd
+	}
+	return r
+}
+
+// Milestone is one upcoming or passed lifecycle date.
+type Milestone struct {
+	Kind string // "end_of_sale", "end_of_support", "warranty_end"
+	Date time.Time
+}
+
+// Next returns the earliest milestone dated today or later and on or before
+// horizon. When every set milestone has passed it returns the most recent
+// one instead, so devices already past their last date are still reported.
+// It returns false when the record has no milestone to report.
+func (r Record) Next(now, horizon time.Time) (Milestone, bool) {
+	y, mo, d := now.Date()
+	today := time.Date(y, mo, d, 0, 0, 0, 0, now.Location())
+
+	var next, last Milestone
+	upcoming, passed := false, false
+	for _, m := range []Milestone{
+		{"end_of_sale", r.EndOfSale.Time},
+		{"end_of_support", r.EndOfSupport.Time},
+		{"warranty_end", r.WarrantyEnd.Time},
+	} {
+		switch {
+		case m.Date.IsZero():
+		case m.Date.Before(today):
+			if !passed || m.Date.After(last.Date) {
+				last, passed = m, true
+			}
+		case !upcoming || m.Date.Before(next.Date):
+			next, upcoming = m, true
+		}
+	}
+	if upcoming {
+		return next, !next.Date.After(horizon)
+	}
+	return last, passed
+}
+
+// DB is a loaded lifecycle file.
+type DB struct {
+	Models  map[string]Record `json:"models"`
+	Serials map[string]Record `json:"serials"`
+}
+
+// Lookup returns the record for a device: the model entry overlaid with any
+// serial entry. Keys match case-insensitively.
+func (db *DB) Lookup(model, serial string) (Record, bool) {
+	if db == nil {
+		return Record{}, false
+	}
+	rec, okModel := lookupFold(db.Models, model)
+	unit, okSerial := lookupFold(db.Serials, serial)
+	if okSerial {
+		rec = rec.merge(unit)
+	}
+	return rec, okModel || okSerial
+}
+
+func lookupFold(m map[string]Record, key string) (Record, bool) {
+	if key == "" {
+		return Record{}, false
+	}
+	if r, ok := m[key]; ok {
+		return r, true
+	}
+	for k, r := range m {
+		if strings.EqualFold(k, key) {
+			return r, true
+		}
+	}
+	return Record{}, false
+}
+
+// Load reads a lifecycle file.
+func Load(path string) (*DB, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- path from operator-controlled config
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lifecycle file: %w", err)
+	}
+	var db DB
+	if err := json.Unmarshal(data, &db); err != nil {
+		return nil, fmt.Errorf("failed to parse lifecycle file %s: %w", path, err)
+	}
+	return &db, nil
+}
+
+// FromConfig loads the file named by files.lifecycle (relative paths resolve
+// against files.config_dir). Returns (nil, nil) when the key is unset.
+func FromConfig() (*DB, error) {
+	path := strings.TrimSpace(viper.GetString("files.lifecycle"))
+	if path == "" {
+		return nil, nil
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(viper.GetString("files.config_dir"), path)
+	}
+	return Load(path)
+}
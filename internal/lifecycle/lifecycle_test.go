@@ -0,0 +1,100 @@
+package lifecycle
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const sampleDB = `{
+  "models":  {"AP41": {"end_of_sale": "2024-06-30", "end_of_support": "2029-06-30"}},
+  "serials": {"a0712345": {"warranty_end": "2026-03-31", "end_of_support": "2028-01-01"}}
+}`
+
+func TestLookup(t *testing.T) {
+	var db DB
+	if err := json.Unmarshal([]byte(sampleDB), &db); err != nil {
+		t.Fatal(err)
+	}
+
+	rec, ok := db.Lookup("ap41", "")
+	if !ok || rec.EndOfSale.String() != "2024-06-30" || !rec.WarrantyEnd.IsZero() {
+		t.Errorf("model lookup = %+v, %v", rec, ok)
+	}
+
+	rec, ok = db.Lookup("AP41", "A0712345")
+	if !ok {
+		t.Fatal("serial lookup missed")
+	}
+	if rec.EndOfSale.String() != "2024-06-30" || rec.EndOfSupport.String() != "2028-01-01" || rec.WarrantyEnd.String() != "2026-03-31" {
+		t.Errorf("serial overlay = %+v", rec)
+	}
+
+	if _, ok := db.Lookup("MR46", "X"); ok {
+		t.Error("unknown model matched")
+	}
+	var nilDB *DB
+	if _, ok := nilDB.Lookup("AP41", ""); ok {
+		t.Error("nil DB should never match")
+	}
+}
+
+func TestNext(t *testing.T) {
+	rec := Record{}
+	_ = json.Unmarshal([]byte(`{"end_of_sale": "2024-06-30", "end_of_support": "2029-06-30", "warranty_end": "2026-03-31"}`), &rec)
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	m, ok := rec.Next(now, now.AddDate(1, 0, 0))
+	if !ok || m.Kind != "warranty_end" {
+		t.Errorf("upcoming milestone should win over a passed one, got %+v %v", m, ok)
+	}
+	if _, ok := rec.Next(now, now.AddDate(0, 1, 0)); ok {
+		t.Error("nothing upcoming falls within one month")
+	}
+
+	later := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	m, ok = rec.Next(later, later.AddDate(1, 0, 0))
+	if !ok || m.Kind != "end_of_support" {
+		t.Errorf("got %+v %v, want the most recent passed milestone end_of_support", m, ok)
+	}
+	if _, ok := (Record{}).Next(now, later); ok {
+		t.Error("empty record should have no milestone")
+	}
+}
+
+func TestDateJSON(t *testing.T) {
+	var rec Record
+	if err := json.Unmarshal([]byte(`{"end_of_sale": "30/06/2024"}`), &rec); err == nil {
+		t.Error("expected error for non-ISO date")
+	}
+	out, err := json.Marshal(Record{EndOfSale: Date{time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"end_of_sale":"2024-06-30","end_of_support":"","warranty_end":""}`; string(out) != want {
+		t.Errorf("marshal = %s, want %s", out, want)
+	}
+}
+
+func TestFromConfig(t *testing.T) {
+	t.Cleanup(viper.Reset)
+
+	if db, err := FromConfig(); err != nil || db != nil {
+		t.Fatalf("unset: db=%v err=%v", db, err)
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "lifecycle.json"), []byte(sampleDB), 0600); err != nil {
+		t.Fatal(err)
+	}
+	viper.Set("files.config_dir", dir)
+	viper.Set("files.lifecycle", "lifecycle.json")
+	db, err := FromConfig()
+	if err != nil || db == nil || len(db.Models) != 1 {
+		t.Fatalf("FromConfig: db=%+v err=%v", db, err)
+	}
+}
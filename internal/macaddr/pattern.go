@@ -0,0 +1,102 @@
+package macaddr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Pattern matches MAC addresses by exact value, prefix, or inclusive range,
+// whatever the separators on either side. Devices from one purchase order
+// share an OUI or a longer prefix, which a Pattern selects in one go.
+//
+// Accepted forms:
+//
+//	"5c:5b:35:00:00:01"                       exact
+//	"5c:5b:35:*", "5c5b35*", "5C-5B-35-*"     prefix (the trailing * is required)
+//	"5c:5b:35:00:00:10..5c:5b:35:00:00:40"    inclusive range of full MACs
+type Pattern struct {
+	prefix  string // normalized hex prefix, for prefix and exact patterns
+	exact   bool
+	lo, hi  uint64 // range bounds, when isRange
+	isRange bool
+}
+
+// IsPattern reports whether s is written as a prefix or range rather than a
+// single MAC.
+func IsPattern(s string) bool {
+	return strings.HasSuffix(strings.TrimSpace(s), "*") || strings.Contains(s, "..")
+}
+
+// ParsePattern compiles a MAC pattern (see Pattern).
+func ParsePattern(s string) (*Pattern, error) {
+	s = strings.TrimSpace(s)
+	if lo, hi, ok := strings.Cut(s, ".."); ok {
+		from, err := macValue(lo)
+		if err != nil {
+			return nil, fmt.Errorf("MAC range %q: start: %w", s, err)
+		}
+		to, err := macValue(hi)
+		if err != nil {
+			return nil, fmt.Errorf("MAC range %q: end: %w", s, err)
+		}
+		if from > to {
+			return nil, fmt.Errorf("MAC range %q: start is after end", s)
+		}
+		return &Pattern{lo: from, hi: to, isRange: true}, nil
+	}
+
+	if body, ok := strings.CutSuffix(s, "*"); ok {
+		prefix := stripSeparators(body)
+		if len(prefix) > 12 || !isHex(prefix) {
+			return nil, fmt.Errorf("MAC prefix %q: %w", s, ErrInvalidMAC)
+		}
+		return &Pattern{prefix: prefix}, nil
+	}
+
+	mac, err := Normalize(s)
+	if err != nil {
+		return nil, fmt.Errorf("MAC %q: %w", s, err)
+	}
+	return &Pattern{prefix: mac, exact: true}, nil
+}
+
+// Match reports whether mac, in any accepted format, is selected by p. An
+// invalid MAC never matches.
+func (p *Pattern) Match(mac string) bool {
+	n := NormalizeOrEmpty(mac)
+	if n == "" {
+		return false
+	}
+	switch {
+	case p.isRange:
+		v, err := strconv.ParseUint(n, 16, 64)
+		return err == nil && v >= p.lo && v <= p.hi
+	case p.exact:
+		return n == p.prefix
+	}
+	return strings.HasPrefix(n, p.prefix)
+}
+
+// macValue returns a full MAC as a number for range comparison.
+func macValue(s string) (uint64, error) {
+	n, err := Normalize(strings.TrimSpace(s))
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(n, 16, 64)
+}
+
+// stripSeparators lowercases s and removes the separators Normalize accepts.
+func stripSeparators(s string) string {
+	return strings.ToLower(strings.NewReplacer(":", "", "-", "", ".", "", " ", "").Replace(s))
+}
+
+func isHex(s string) bool {
+	for _, r := range s {
+		if !strings.ContainsRune("0123456789abcdef", r) {
+			return false
+		}
+	}
+	return true
+}
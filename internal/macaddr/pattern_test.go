@@ -0,0 +1,71 @@
+package macaddr
+
+import "testing"
+
+func TestParsePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		match   []string
+		noMatch []string
+	}{
+		{
+			pattern: "5c:5b:35:*",
+			match:   []string{"5c5b35000001", "5C:5B:35:FF:FF:FF", "5c5b.35aa.bbcc"},
+			noMatch: []string{"5c5b36000001", "not-a-mac"},
+		},
+		{
+			pattern: "5C-5B-35-00-*",
+			match:   []string{"5c:5b:35:00:12:34"},
+			noMatch: []string{"5c:5b:35:01:12:34"},
+		},
+		{
+			pattern: "*",
+			match:   []string{"001122334455"},
+		},
+		{
+			pattern: "5c:5b:35:00:00:10..5c:5b:35:00:00:40",
+			match:   []string{"5c5b35000010", "5c:5b:35:00:00:2a", "5c-5b-35-00-00-40"},
+			noMatch: []string{"5c5b3500000f", "5c5b35000041"},
+		},
+		{
+			pattern: "5c5b.3500.0001",
+			match:   []string{"5c:5b:35:00:00:01"},
+			noMatch: []string{"5c:5b:35:00:00:02"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			p, err := ParsePattern(tt.pattern)
+			if err != nil {
+				t.Fatalf("ParsePattern: %v", err)
+			}
+			for _, mac := range tt.match {
+				if !p.Match(mac) {
+					t.Errorf("%s should match %s", tt.pattern, mac)
+				}
+			}
+			for _, mac := range tt.noMatch {
+				if p.Match(mac) {
+					t.Errorf("%s should not match %s", tt.pattern, mac)
+				}
+			}
+		})
+	}
+}
+
+func TestParsePatternErrors(t *testing.T) {
+	for _, s := range []string{
+		"5c:5b:zz:*",
+		"5c5b35000001aa*",
+		"5c5b35000040..5c5b35000010",
+		"5c5b35..5c5b35000010",
+		"5c:5b:35",
+	} {
+		if _, err := ParsePattern(s); err == nil {
+			t.Errorf("ParsePattern(%q) should fail", s)
+		}
+	}
+	if !IsPattern("5c:5b:35:*") || !IsPattern("a..b") || IsPattern("5c:5b:35:00:00:01") {
+		t.Error("IsPattern misclassified an input")
+	}
+}
@@ -0,0 +1,124 @@
+// Package merge3 merges two edited copies of a JSON document against their
+// common ancestor, so a change on one side does not overwrite an unrelated
+// change on the other. Objects are merged key by key; any other value —
+// strings, numbers, lists — is taken whole from whichever side changed it.
+// A field both sides changed differently is a conflict for the caller to
+// settle.
+package merge3
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ravinald/wifimgr/internal/keypath"
+)
+
+// absent stands for a field a document does not have.
+type absent struct{}
+
+func (absent) String() string { return "(absent)" }
+
+// Absent is the value of a side that does not have the field, in a Conflict.
+var Absent any = absent{}
+
+// Conflict is a field that ours and theirs both changed from base, to
+// different values. Any side may be Absent.
+type Conflict struct {
+	Path   []string
+	Base   any
+	Ours   any
+	Theirs any
+}
+
+// Key is the conflict's path in dot notation.
+func (c Conflict) Key() string {
+	return strings.Join(c.Path, ".")
+}
+
+// Merge returns ours with theirs' changes from base applied, and the fields
+// both changed differently. Conflicting fields hold ours' value in the
+// result; Resolve sets theirs instead. The inputs are not modified.
+func Merge(base, ours, theirs map[string]any) (map[string]any, []Conflict) {
+	var conflicts []Conflict
+	merged := mergeMaps(nil, base, ours, theirs, &conflicts)
+	return merged, conflicts
+}
+
+// Resolve sets c's field in merged to theirs' value, deleting it when theirs
+// does not have it.
+func Resolve(merged map[string]any, c Conflict) {
+	if c.Theirs == Absent {
+		keypath.DeleteValueAtPath(merged, c.Path)
+		return
+	}
+	keypath.SetValueAtPath(merged, c.Path, deepCopy(c.Theirs))
+}
+
+func mergeMaps(path []string, base, ours, theirs map[string]any, conflicts *[]Conflict) map[string]any {
+	keys := make(map[string]bool)
+	for _, m := range []map[string]any{base, ours, theirs} {
+		for k := range m {
+			keys[k] = true
+		}
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	out := make(map[string]any, len(keys))
+	for _, k := range sorted {
+		b, o, t := lookup(base, k), lookup(ours, k), lookup(theirs, k)
+		if v := mergeValue(append(path[:len(path):len(path)], k), b, o, t, conflicts); v != Absent {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+func mergeValue(path []string, base, ours, theirs any, conflicts *[]Conflict) any {
+	switch {
+	case equal(ours, theirs), equal(theirs, base):
+		return deepCopy(ours)
+	case equal(ours, base):
+		return deepCopy(theirs)
+	}
+	om, oursIsMap := ours.(map[string]any)
+	tm, theirsIsMap := theirs.(map[string]any)
+	if oursIsMap && theirsIsMap {
+		bm, _ := base.(map[string]any)
+		return mergeMaps(path, bm, om, tm, conflicts)
+	}
+	*conflicts = append(*conflicts, Conflict{Path: path, Base: base, Ours: ours, Theirs: theirs})
+	return deepCopy(ours)
+}
+
+func lookup(m map[string]any, k string) any {
+	if v, ok := m[k]; ok {
+		return v
+	}
+	return Absent
+}
+
+func equal(a, b any) bool {
+	return reflect.DeepEqual(a, b)
+}
+
+// deepCopy copies a JSON value, so the result shares nothing with the inputs.
+func deepCopy(v any) any {
+	if v == Absent {
+		return v
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return v
+	}
+	return out
+}
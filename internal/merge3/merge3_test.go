@@ -0,0 +1,67 @@
+package merge3
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func doc(t *testing.T, s string) map[string]any {
+	t.Helper()
+	var m map[string]any
+	if err := json.Unmarshal([]byte(s), &m); err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestMerge(t *testing.T) {
+	base := doc(t, `{"ap": {"a": {"name": "ap-a", "tags": ["x"]}, "b": {"name": "ap-b"}, "c": {"name": "ap-c"}}, "notes": "n"}`)
+	// Ours renamed b, dropped c, and added d; theirs renamed a, retagged it,
+	// and changed the notes.
+	ours := doc(t, `{"ap": {"a": {"name": "ap-a", "tags": ["x"]}, "b": {"name": "ap-b2"}, "d": {"name": "ap-d"}}, "notes": "n"}`)
+	theirs := doc(t, `{"ap": {"a": {"name": "ap-a2", "tags": ["y"]}, "b": {"name": "ap-b"}, "c": {"name": "ap-c"}}, "notes": "m"}`)
+
+	merged, conflicts := Merge(base, ours, theirs)
+	want := doc(t, `{"ap": {"a": {"name": "ap-a2", "tags": ["y"]}, "b": {"name": "ap-b2"}, "d": {"name": "ap-d"}}, "notes": "m"}`)
+	if !reflect.DeepEqual(merged, want) {
+		t.Errorf("merged = %v\nwant %v", merged, want)
+	}
+	if len(conflicts) != 0 {
+		t.Errorf("conflicts = %+v", conflicts)
+	}
+
+	theirs["ap"].(map[string]any)["b"] = map[string]any{"name": "ap-b3"}
+	merged, conflicts = Merge(base, ours, theirs)
+	if len(conflicts) != 1 || conflicts[0].Key() != "ap.b.name" || conflicts[0].Ours != "ap-b2" || conflicts[0].Theirs != "ap-b3" {
+		t.Fatalf("conflicts = %+v", conflicts)
+	}
+	if got := merged["ap"].(map[string]any)["b"].(map[string]any)["name"]; got != "ap-b2" {
+		t.Errorf("unresolved conflict holds %v, want ours", got)
+	}
+	Resolve(merged, conflicts[0])
+	if got := merged["ap"].(map[string]any)["b"].(map[string]any)["name"]; got != "ap-b3" {
+		t.Errorf("resolved conflict holds %v, want theirs", got)
+	}
+}
+
+func TestMergeDeleteConflict(t *testing.T) {
+	base := doc(t, `{"ap": {"c": {"name": "ap-c"}}}`)
+	ours := doc(t, `{"ap": {}}`)
+	theirs := doc(t, `{"ap": {"c": {"name": "ap-c2"}}}`)
+
+	merged, conflicts := Merge(base, ours, theirs)
+	if len(conflicts) != 1 || conflicts[0].Ours != Absent || conflicts[0].Key() != "ap.c" {
+		t.Fatalf("conflicts = %+v", conflicts)
+	}
+	if _, ok := merged["ap"].(map[string]any)["c"]; ok {
+		t.Error("unresolved delete conflict should keep ours (absent)")
+	}
+	Resolve(merged, conflicts[0])
+	if _, ok := merged["ap"].(map[string]any)["c"]; !ok {
+		t.Error("resolving to theirs should restore ap.c")
+	}
+	if base["ap"].(map[string]any)["c"].(map[string]any)["name"] != "ap-c" {
+		t.Error("Merge modified its input")
+	}
+}
@@ -0,0 +1,208 @@
+// Package models is a knowledge base of device hardware — radios per band,
+// maximum clients, PoE class and port counts — so validation can tell when
+// intent asks a model for something it cannot do (6 GHz settings on an AP
+// without a 6 GHz radio) and operators can look a model up without a
+// datasheet.
+//
+// The database is embedded from models.json. files.models names an optional
+// operator file in the same format whose entries are added to it, replacing
+// embedded entries of the same name.
+package models
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/spf13/viper"
+)
+
+//go:embed models.json
+var embedded []byte
+
+// Bands, as named in radio_config.
+const (
+	Band24 = "band_24"
+	Band5  = "band_5"
+	Band6  = "band_6"
+)
+
+// Radio is one radio: the bands it can serve and its spatial streams. A
+// flexible radio lists more than one band.
+type Radio struct {
+	Bands   []string `json:"bands"`
+	Streams int      `json:"streams"`
+}
+
+// PortGroup is a run of identical ports.
+type PortGroup struct {
+	Count int    `json:"count"`
+	Speed string `json:"speed"`          // "1G", "2.5G", "10G SFP+"
+	Role  string `json:"role,omitempty"` // uplink, access, wan, lan
+	PoE   string `json:"poe,omitempty"`  // PoE standard the ports source
+}
+
+// Model describes one hardware model.
+type Model struct {
+	Name           string      `json:"name"`
+	Vendor         string      `json:"vendor"`
+	Type           string      `json:"type"`           // ap, switch, gateway
+	WiFi           string      `json:"wifi,omitempty"` // Wi-Fi generation: "6", "6E", "7"
+	Radios         []Radio     `json:"radios,omitempty"`
+	MaxClients     int         `json:"max_clients,omitempty"`
+	PoE            string      `json:"poe,omitempty"` // PoE class the device draws
+	PoEBudgetWatts int         `json:"poe_budget_watts,omitempty"`
+	Ports          []PortGroup `json:"ports,omitempty"`
+	Outdoor        bool        `json:"outdoor,omitempty"`
+}
+
+// SupportsBand reports whether any radio of the model serves band.
+func (m *Model) SupportsBand(band string) bool {
+	for _, r := range m.Radios {
+		for _, b := range r.Bands {
+			if b == band {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RadiosFor returns the number of radios that can serve band.
+func (m *Model) RadiosFor(band string) int {
+	n := 0
+	for _, r := range m.Radios {
+		for _, b := range r.Bands {
+			if b == band {
+				n++
+				break
+			}
+		}
+	}
+	return n
+}
+
+// DB is a set of models keyed by upper-case name.
+type DB struct {
+	models map[string]*Model
+}
+
+type file struct {
+	Models []*Model `json:"models"`
+}
+
+// Parse reads a models file.
+func Parse(data []byte) (*DB, error) {
+	var f file
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+	db := &DB{models: make(map[string]*Model, len(f.Models))}
+	db.add(f.Models)
+	return db, nil
+}
+
+func (db *DB) add(models []*Model) {
+	for _, m := range models {
+		if m != nil && m.Name != "" {
+			db.models[strings.ToUpper(m.Name)] = m
+		}
+	}
+}
+
+// Lookup finds a model by name, ignoring case. A name with a regional or
+// SKU suffix falls back to shorter names, dropping hyphen-separated parts
+// from the right, so "AP43-US" finds AP43 and "AP-515-RW" finds AP-515.
+func (db *DB) Lookup(name string) (*Model, bool) {
+	if db == nil {
+		return nil, false
+	}
+	key := strings.ToUpper(strings.TrimSpace(name))
+	if key == "" {
+		return nil, false
+	}
+	for {
+		if m, ok := db.models[key]; ok {
+			return m, true
+		}
+		i := strings.LastIndex(key, "-")
+		if i <= 0 {
+			return nil, false
+		}
+		key = key[:i]
+	}
+}
+
+// All returns every model, sorted by vendor, type and name.
+func (db *DB) All() []*Model {
+	out := make([]*Model, 0, len(db.models))
+	for _, m := range db.models {
+		out = append(out, m)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.Vendor != b.Vendor {
+			return a.Vendor < b.Vendor
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		return a.Name < b.Name
+	})
+	return out
+}
+
+var (
+	defaultOnce sync.Once
+	defaultDB   *DB
+	defaultErr  error
+)
+
+// Default returns the embedded database with the files.models overlay
+// applied. It is loaded once per run; an unreadable overlay is an error, an
+// unset one is not.
+func Default() (*DB, error) {
+	defaultOnce.Do(func() {
+		defaultDB, defaultErr = Parse(embedded)
+		if defaultErr != nil {
+			defaultErr = fmt.Errorf("embedded models database: %w", defaultErr)
+			return
+		}
+		path := strings.TrimSpace(viper.GetString("files.models"))
+		if path == "" {
+			return
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(viper.GetString("files.config_dir"), path)
+		}
+		data, err := os.ReadFile(path) // #nosec G304 -- path from operator-controlled config
+		if err != nil {
+			defaultErr = fmt.Errorf("failed to read models file: %w", err)
+			return
+		}
+		overlay, err := Parse(data)
+		if err != nil {
+			defaultErr = fmt.Errorf("failed to parse models file %s: %w", path, err)
+			return
+		}
+		for _, m := range overlay.models {
+			defaultDB.models[strings.ToUpper(m.Name)] = m
+		}
+	})
+	return defaultDB, defaultErr
+}
+
+// Lookup finds a model in the default database. A database that fails to
+// load finds nothing.
+func Lookup(name string) (*Model, bool) {
+	db, err := Default()
+	if err != nil {
+		return nil, false
+	}
+	return db.Lookup(name)
+}
@@ -0,0 +1,45 @@
+package models
+
+import "testing"
+
+func TestEmbeddedDatabase(t *testing.T) {
+	db, err := Parse(embedded)
+	if err != nil {
+		t.Fatalf("Parse(embedded): %v", err)
+	}
+	for _, m := range db.All() {
+		if m.Vendor == "" || m.Type == "" {
+			t.Errorf("%s: vendor and type are required", m.Name)
+		}
+		if m.Type == "ap" && (len(m.Radios) == 0 || m.WiFi == "") {
+			t.Errorf("%s: an AP needs radios and a Wi-Fi generation", m.Name)
+		}
+		sixGHz := m.SupportsBand(Band6)
+		if m.Type == "ap" && sixGHz != (m.WiFi == "6E" || m.WiFi == "7") {
+			t.Errorf("%s: Wi-Fi %s disagrees with 6 GHz radio = %v", m.Name, m.WiFi, sixGHz)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	db, err := Parse([]byte(`{"models": [
+		{"name": "AP43", "vendor": "mist", "type": "ap", "radios": [{"bands": ["band_24"]}, {"bands": ["band_5"]}]},
+		{"name": "AP-515", "vendor": "aruba", "type": "ap", "radios": [{"bands": ["band_24", "band_5"]}, {"bands": ["band_5"]}]}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]string{"ap43": "AP43", "AP43-US": "AP43", "AP-515-RW": "AP-515"} {
+		if m, ok := db.Lookup(name); !ok || m.Name != want {
+			t.Errorf("Lookup(%q) = %v, %v; want %s", name, m, ok, want)
+		}
+	}
+	if _, ok := db.Lookup("AP99"); ok {
+		t.Error("Lookup(AP99) found a model")
+	}
+	m, _ := db.Lookup("AP-515")
+	if m.SupportsBand(Band6) || m.RadiosFor(Band5) != 2 || m.RadiosFor(Band24) != 1 {
+		t.Errorf("AP-515 bands: 6 GHz %v, 5 GHz radios %d, 2.4 GHz radios %d",
+			m.SupportsBand(Band6), m.RadiosFor(Band5), m.RadiosFor(Band24))
+	}
+}
@@ -0,0 +1,200 @@
+// Package notify renders end-user announcements for WLAN changes that make
+// people rejoin: a new SSID, a renamed SSID, or a new passphrase. It is the
+// "notify-users" integration: apply sends each change as a notification, and
+// this package writes one HTML file per change for facilities or
+// communications teams to send on. wifimgr itself never mails anyone.
+package notify
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/ravinald/wifimgr/internal/qrcode"
+)
+
+// Kinds of change an announcement is written for.
+const (
+	KindNewSSID = "new_ssid"
+	KindRenamed = "renamed"
+	KindPSK     = "psk"
+)
+
+// DefaultDir is where announcements are written, relative to the config
+// directory, when notify_users.dir is unset.
+const DefaultDir = "notifications"
+
+// qrScale is the PNG size of one QR module, in pixels.
+const qrScale = 6
+
+// Config holds the notify_users section of the main config.
+type Config struct {
+	Enabled  bool   `json:"enabled"`
+	Dir      string `json:"dir"`      // Output directory (default: <config dir>/notifications)
+	Template string `json:"template"` // html/template file; the built-in template when unset
+}
+
+// LoadConfig reads notify_users.* from Viper. It returns (nil, nil) when
+// announcements are not enabled so callers can treat "not configured" as a
+// silent no-op. Relative paths resolve against configDir.
+func LoadConfig(configDir string) (*Config, error) {
+	if !viper.GetBool("notify_users.enabled") {
+		return nil, nil
+	}
+	cfg := &Config{
+		Enabled:  true,
+		Dir:      viper.GetString("notify_users.dir"),
+		Template: viper.GetString("notify_users.template"),
+	}
+	if cfg.Dir == "" {
+		cfg.Dir = DefaultDir
+	}
+	if !filepath.IsAbs(cfg.Dir) {
+		cfg.Dir = filepath.Join(configDir, cfg.Dir)
+	}
+	if cfg.Template != "" && !filepath.IsAbs(cfg.Template) {
+		cfg.Template = filepath.Join(configDir, cfg.Template)
+	}
+	return cfg, nil
+}
+
+// Announcement is one user-visible WLAN change and the template data for it.
+type Announcement struct {
+	Kind         string
+	Site         string
+	SSID         string
+	PreviousSSID string // set for KindRenamed
+	WiFi         qrcode.WiFi
+	Effective    time.Time
+
+	// QRCode is a data: URI of a PNG WiFi-join code, usable as an <img src>.
+	// Render fills it in.
+	QRCode template.URL
+}
+
+// Subject is a one-line summary, e.g. for the email subject.
+func (a Announcement) Subject() string {
+	switch a.Kind {
+	case KindRenamed:
+		return fmt.Sprintf("Wi-Fi network %s is now %s", a.PreviousSSID, a.SSID)
+	case KindPSK:
+		return fmt.Sprintf("New Wi-Fi password for %s", a.SSID)
+	}
+	return fmt.Sprintf("New Wi-Fi network %s", a.SSID)
+}
+
+// Render executes tmpl (the built-in template when empty) for a.
+func Render(tmpl string, a Announcement) ([]byte, error) {
+	if tmpl == "" {
+		tmpl = defaultTemplate
+	}
+	t, err := template.New("announcement").Parse(tmpl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid announcement template: %w", err)
+	}
+
+	code, err := qrcode.Encode(a.WiFi.String())
+	if err != nil {
+		return nil, err
+	}
+	var img bytes.Buffer
+	if err := code.WritePNG(&img, qrScale); err != nil {
+		return nil, err
+	}
+	// #nosec G203 -- the URI is built here from PNG bytes, not user input
+	a.QRCode = template.URL("data:image/png;base64," + base64.StdEncoding.EncodeToString(img.Bytes()))
+
+	var out bytes.Buffer
+	if err := t.Execute(&out, a); err != nil {
+		return nil, fmt.Errorf("failed to render announcement: %w", err)
+	}
+	return out.Bytes(), nil
+}
+
+// Write renders a into cfg.Dir and returns the file's path. The file holds
+// the passphrase, so it is created owner-only.
+func Write(cfg *Config, a Announcement) (string, error) {
+	body, err := renderWith(cfg, a)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(cfg.Dir, 0750); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", cfg.Dir, err)
+	}
+	path := filepath.Join(cfg.Dir, FileName(a))
+	if err := os.WriteFile(path, body, 0600); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return path, nil
+}
+
+// renderWith renders a with cfg's template file, or the built-in template.
+func renderWith(cfg *Config, a Announcement) ([]byte, error) {
+	tmpl := ""
+	if cfg.Template != "" {
+		data, err := os.ReadFile(cfg.Template) // #nosec G304 -- path from operator config
+		if err != nil {
+			return nil, fmt.Errorf("failed to read announcement template: %w", err)
+		}
+		tmpl = string(data)
+	}
+	return Render(tmpl, a)
+}
+
+// FileName names an announcement file: <date>-<site>-<ssid>-<kind>.html.
+func FileName(a Announcement) string {
+	return fmt.Sprintf("%s-%s-%s-%s.html", a.Effective.Format("2006-01-02"), fileSlug(a.Site), fileSlug(a.SSID), a.Kind)
+}
+
+func fileSlug(s string) string {
+	slug := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		}
+		return '_'
+	}, s)
+	if slug == "" {
+		return "_"
+	}
+	return slug
+}
+
+const defaultTemplate = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Subject}}</title>
+</head>
+<body style="font-family: sans-serif; max-width: 36em">
+<h1>{{.Subject}}</h1>
+<p>
+{{- if eq .Kind "renamed"}}
+The Wi-Fi network <strong>{{.PreviousSSID}}</strong> at {{.Site}} has been renamed to <strong>{{.SSID}}</strong>.
+Devices that joined the old name must join the new one.
+{{- else if eq .Kind "psk"}}
+The password of the Wi-Fi network <strong>{{.SSID}}</strong> at {{.Site}} has changed.
+Devices that saved the old password must rejoin with the new one.
+{{- else}}
+A new Wi-Fi network, <strong>{{.SSID}}</strong>, is available at {{.Site}}.
+{{- end}}
+</p>
+<p>Effective: {{.Effective.Format "Monday, 2 January 2006 15:04 MST"}}</p>
+<table>
+<tr><td>Network name</td><td><strong>{{.SSID}}</strong>{{if .WiFi.Hidden}} (hidden; add it by name){{end}}</td></tr>
+{{- if .WiFi.Passphrase}}
+<tr><td>Password</td><td><code>{{.WiFi.Passphrase}}</code></td></tr>
+{{- end}}
+</table>
+<p>Scan with a phone camera to join:</p>
+<p><img src="{{.QRCode}}" alt="QR code to join {{.SSID}}"></p>
+</body>
+</html>
+`
@@ -0,0 +1,90 @@
+package notify
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/ravinald/wifimgr/internal/qrcode"
+)
+
+func testAnnouncement() Announcement {
+	return Announcement{
+		Kind:      KindPSK,
+		Site:      "US-LAB-01",
+		SSID:      "Corp Guest",
+		WiFi:      qrcode.WiFi{SSID: "Corp Guest", Security: "WPA", Passphrase: "<s3cret&>"},
+		Effective: time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestRenderDefault(t *testing.T) {
+	out, err := Render("", testAnnouncement())
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(out)
+	for _, want := range []string{
+		"<title>New Wi-Fi password for Corp Guest</title>",
+		"at US-LAB-01 has changed",
+		"<code>&lt;s3cret&amp;&gt;</code>",
+		`src="data:image/png;base64,`,
+		"Monday, 2 March 2026",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("missing %q in:\n%s", want, html)
+		}
+	}
+}
+
+func TestRenderCustom(t *testing.T) {
+	a := testAnnouncement()
+	a.Kind, a.PreviousSSID = KindRenamed, "Old Guest"
+	out, err := Render("{{.Subject}} from {{.Effective.Format \"2006-01-02\"}}", a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(out), "Wi-Fi network Old Guest is now Corp Guest from 2026-03-02"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if _, err := Render("{{.Nope", a); err == nil {
+		t.Error("expected an error for an invalid template")
+	}
+}
+
+func TestWrite(t *testing.T) {
+	dir := t.TempDir()
+	viper.Reset()
+	defer viper.Reset()
+
+	if cfg, _ := LoadConfig(dir); cfg != nil {
+		t.Fatal("expected nil config when notify_users is not enabled")
+	}
+	viper.Set("notify_users.enabled", true)
+	cfg, err := LoadConfig(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Dir != filepath.Join(dir, DefaultDir) {
+		t.Errorf("Dir = %s, want the default under the config dir", cfg.Dir)
+	}
+
+	path, err := Write(cfg, testAnnouncement())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := filepath.Base(path), "2026-03-02-US-LAB-01-Corp_Guest-psk.html"; got != want {
+		t.Errorf("file = %s, want %s", got, want)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("mode = %v, want 0600", info.Mode().Perm())
+	}
+}
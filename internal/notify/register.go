@@ -0,0 +1,64 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/ravinald/wifimgr/internal/integrations"
+	"github.com/ravinald/wifimgr/internal/qrcode"
+	"github.com/ravinald/wifimgr/internal/symbols"
+)
+
+func init() {
+	integrations.Register(integrations.Integration{
+		Name:         "notify-users",
+		ConfigKey:    "notify_users",
+		EndpointKeys: "notify_users.dir and notify_users.template",
+		Configured:   func() bool { return viper.GetBool("notify_users.enabled") },
+		Check:        check,
+		Notify:       notifyHook,
+	})
+}
+
+// check renders a sample announcement with the configured template, which
+// catches an unreadable or invalid template before an apply needs it.
+func check(_ context.Context) (string, error) {
+	cfg, err := LoadConfig(viper.GetString("files.config_dir"))
+	if err != nil || cfg == nil {
+		return "", err
+	}
+	sample := Announcement{
+		Kind:      KindNewSSID,
+		Site:      "sample",
+		SSID:      "sample",
+		WiFi:      qrcode.WiFi{SSID: "sample", Security: "nopass"},
+		Effective: time.Now(),
+	}
+	if _, err := renderWith(cfg, sample); err != nil {
+		return "", err
+	}
+	return "writing to " + cfg.Dir, nil
+}
+
+// notifyHook writes the announcement of a WLAN change.
+func notifyHook(_ context.Context, n integrations.Notification) error {
+	a, ok := n.Data.(Announcement)
+	if n.Kind != integrations.NotifyWLANChange || !ok {
+		return integrations.ErrNotHandled
+	}
+	cfg, err := LoadConfig(n.ConfigDir)
+	if err != nil || cfg == nil {
+		return err
+	}
+	path, err := Write(cfg, a)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s Failed to write announcement for WLAN '%s': %v\n", symbols.WarningPrefix(), a.SSID, err)
+		return err
+	}
+	fmt.Printf("%s Wrote user announcement %s\n", symbols.SuccessPrefix(), path)
+	return nil
+}
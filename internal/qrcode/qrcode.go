@@ -0,0 +1,464 @@
+// Package qrcode encodes short text as a QR code (ISO/IEC 18004) and renders
+// it for a terminal or as a PNG.
+//
+// Only what wifimgr needs is implemented: byte mode at error correction level
+// M, versions 1 through 10 (up to 213 bytes) — enough for a WiFi-join string
+// with a 32-byte SSID and a 63-character passphrase.
+package qrcode
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"strings"
+)
+
+// quietZone is the light border, in modules, the standard requires around a
+// code for scanners to find it.
+const quietZone = 4
+
+// Code is an encoded QR symbol.
+type Code struct {
+	Version int
+	Size    int
+	dark    [][]bool
+	fn      [][]bool // function patterns, which data and masks skip
+}
+
+// blockSpec is the level-M error correction layout of one version: EC
+// codewords per block, then (count, data codewords) for each block group.
+type blockSpec struct {
+	ec             int
+	g1, g1Data     int
+	g2, g2Data     int
+	alignPositions []int
+}
+
+var levelM = [...]blockSpec{
+	1:  {10, 1, 16, 0, 0, nil},
+	2:  {16, 1, 28, 0, 0, []int{6, 18}},
+	3:  {26, 1, 44, 0, 0, []int{6, 22}},
+	4:  {18, 2, 32, 0, 0, []int{6, 26}},
+	5:  {24, 2, 43, 0, 0, []int{6, 30}},
+	6:  {16, 4, 27, 0, 0, []int{6, 34}},
+	7:  {18, 4, 31, 0, 0, []int{6, 22, 38}},
+	8:  {22, 2, 38, 2, 39, []int{6, 24, 42}},
+	9:  {22, 3, 36, 2, 37, []int{6, 26, 46}},
+	10: {26, 4, 43, 1, 44, []int{6, 28, 50}},
+}
+
+func (b blockSpec) dataCodewords() int { return b.g1*b.g1Data + b.g2*b.g2Data }
+
+// maxVersion is the largest version Encode produces.
+const maxVersion = len(levelM) - 1
+
+// Encode returns the smallest QR code holding text in byte mode.
+func Encode(text string) (*Code, error) {
+	data := []byte(text)
+	version := 0
+	for v := 1; v <= maxVersion; v++ {
+		if 4+countBits(v)+8*len(data) <= 8*levelM[v].dataCodewords() {
+			version = v
+			break
+		}
+	}
+	if version == 0 {
+		return nil, fmt.Errorf("text is %d bytes; a QR code here holds at most %d", len(data), capacity(maxVersion))
+	}
+
+	c := newCode(version)
+	c.drawFunctionPatterns()
+	c.placeData(interleave(version, dataCodewords(version, data)))
+
+	best, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		c.applyMask(mask)
+		c.drawFormat(mask)
+		if p := c.penalty(); bestPenalty < 0 || p < bestPenalty {
+			best, bestPenalty = mask, p
+		}
+		c.applyMask(mask) // XOR again to undo
+	}
+	c.applyMask(best)
+	c.drawFormat(best)
+	return c, nil
+}
+
+func countBits(version int) int {
+	if version <= 9 {
+		return 8
+	}
+	return 16
+}
+
+func capacity(version int) int {
+	return (8*levelM[version].dataCodewords() - 4 - countBits(version)) / 8
+}
+
+func newCode(version int) *Code {
+	size := 17 + 4*version
+	c := &Code{Version: version, Size: size, dark: make([][]bool, size), fn: make([][]bool, size)}
+	for y := range c.dark {
+		c.dark[y] = make([]bool, size)
+		c.fn[y] = make([]bool, size)
+	}
+	return c
+}
+
+// Dark reports whether the module at column x, row y is dark. Coordinates
+// outside the symbol (the quiet zone) are light.
+func (c *Code) Dark(x, y int) bool {
+	return x >= 0 && y >= 0 && x < c.Size && y < c.Size && c.dark[y][x]
+}
+
+func (c *Code) setFunction(x, y int, dark bool) {
+	c.dark[y][x] = dark
+	c.fn[y][x] = true
+}
+
+// dataCodewords builds the data codeword sequence: mode, count, bytes,
+// terminator, and pad codewords.
+func dataCodewords(version int, data []byte) []byte {
+	var bits []bool
+	appendBits := func(v, n int) {
+		for i := n - 1; i >= 0; i-- {
+			bits = append(bits, v>>i&1 == 1)
+		}
+	}
+	appendBits(0x4, 4) // byte mode
+	appendBits(len(data), countBits(version))
+	for _, b := range data {
+		appendBits(int(b), 8)
+	}
+	capBits := 8 * levelM[version].dataCodewords()
+	appendBits(0, min(4, capBits-len(bits)))
+	appendBits(0, (8-len(bits)%8)%8)
+
+	out := make([]byte, 0, capBits/8)
+	for i := 0; i < len(bits); i += 8 {
+		var b byte
+		for j := 0; j < 8; j++ {
+			if bits[i+j] {
+				b |= 1 << (7 - j)
+			}
+		}
+		out = append(out, b)
+	}
+	for pad := byte(0xEC); len(out) < capBits/8; pad ^= 0xEC ^ 0x11 {
+		out = append(out, pad)
+	}
+	return out
+}
+
+// interleave splits data into blocks, appends each block's error correction
+// codewords, and interleaves the result in transmission order.
+func interleave(version int, data []byte) []byte {
+	spec := levelM[version]
+	divisor := rsDivisor(spec.ec)
+	var blocks, ecBlocks [][]byte
+	for i := 0; i < spec.g1+spec.g2; i++ {
+		n := spec.g1Data
+		if i >= spec.g1 {
+			n = spec.g2Data
+		}
+		blocks = append(blocks, data[:n])
+		ecBlocks = append(ecBlocks, rsRemainder(data[:n], divisor))
+		data = data[n:]
+	}
+	var out []byte
+	for i := 0; i < max(spec.g1Data, spec.g2Data); i++ {
+		for _, b := range blocks {
+			if i < len(b) {
+				out = append(out, b[i])
+			}
+		}
+	}
+	for i := 0; i < spec.ec; i++ {
+		for _, b := range ecBlocks {
+			out = append(out, b[i])
+		}
+	}
+	return out
+}
+
+func (c *Code) drawFunctionPatterns() {
+	for i := 0; i < c.Size; i++ {
+		c.setFunction(6, i, i%2 == 0)
+		c.setFunction(i, 6, i%2 == 0)
+	}
+	c.drawFinder(3, 3)
+	c.drawFinder(c.Size-4, 3)
+	c.drawFinder(3, c.Size-4)
+
+	pos := levelM[c.Version].alignPositions
+	for i, x := range pos {
+		for j, y := range pos {
+			// Skip the three corners the finders occupy.
+			if (i == 0 && j == 0) || (i == 0 && j == len(pos)-1) || (i == len(pos)-1 && j == 0) {
+				continue
+			}
+			c.drawAlignment(x, y)
+		}
+	}
+
+	// Reserve the format areas; drawFormat fills them per mask.
+	c.drawFormat(0)
+	c.drawVersion()
+}
+
+// drawFinder draws a finder pattern centred on (cx, cy) with its separator.
+func (c *Code) drawFinder(cx, cy int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			x, y := cx+dx, cy+dy
+			if x < 0 || y < 0 || x >= c.Size || y >= c.Size {
+				continue
+			}
+			d := max(abs(dx), abs(dy))
+			c.setFunction(x, y, d != 2 && d != 4)
+		}
+	}
+}
+
+func (c *Code) drawAlignment(cx, cy int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			c.setFunction(cx+dx, cy+dy, max(abs(dx), abs(dy)) != 1)
+		}
+	}
+}
+
+// drawFormat writes both copies of the format information for mask, plus
+// the always-dark module.
+func (c *Code) drawFormat(mask int) {
+	data := 0<<3 | mask // level M is 00
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+	bit := func(i int) bool { return bits>>i&1 == 1 }
+
+	for i := 0; i <= 5; i++ {
+		c.setFunction(8, i, bit(i))
+	}
+	c.setFunction(8, 7, bit(6))
+	c.setFunction(8, 8, bit(7))
+	c.setFunction(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		c.setFunction(14-i, 8, bit(i))
+	}
+	for i := 0; i < 8; i++ {
+		c.setFunction(c.Size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		c.setFunction(8, c.Size-15+i, bit(i))
+	}
+	c.setFunction(8, c.Size-8, true)
+}
+
+// drawVersion writes the version information blocks versions 7 and up carry.
+func (c *Code) drawVersion() {
+	if c.Version < 7 {
+		return
+	}
+	rem := c.Version
+	for i := 0; i < 12; i++ {
+		rem = rem<<1 ^ (rem>>11)*0x1F25
+	}
+	bits := c.Version<<12 | rem
+	for i := 0; i < 18; i++ {
+		dark := bits>>i&1 == 1
+		a, b := c.Size-11+i%3, i/3
+		c.setFunction(a, b, dark)
+		c.setFunction(b, a, dark)
+	}
+}
+
+// placeData fills the non-function modules with codewords in the standard
+// two-column zigzag, starting bottom right.
+func (c *Code) placeData(codewords []byte) {
+	i := 0
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		upward := (right+1)&2 == 0
+		for vert := 0; vert < c.Size; vert++ {
+			y := vert
+			if upward {
+				y = c.Size - 1 - vert
+			}
+			for j := 0; j < 2; j++ {
+				x := right - j
+				if c.fn[y][x] || i >= len(codewords)*8 {
+					continue
+				}
+				c.dark[y][x] = codewords[i>>3]>>(7-i&7)&1 == 1
+				i++
+			}
+		}
+	}
+}
+
+func maskBit(mask, x, y int) bool {
+	switch mask {
+	case 0:
+		return (x+y)%2 == 0
+	case 1:
+		return y%2 == 0
+	case 2:
+		return x%3 == 0
+	case 3:
+		return (x+y)%3 == 0
+	case 4:
+		return (x/3+y/2)%2 == 0
+	case 5:
+		return x*y%2+x*y%3 == 0
+	case 6:
+		return (x*y%2+x*y%3)%2 == 0
+	default:
+		return ((x+y)%2+x*y%3)%2 == 0
+	}
+}
+
+// applyMask XORs mask into every non-function module; applying it twice
+// restores the original.
+func (c *Code) applyMask(mask int) {
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if !c.fn[y][x] && maskBit(mask, x, y) {
+				c.dark[y][x] = !c.dark[y][x]
+			}
+		}
+	}
+}
+
+// penalty scores the symbol by the standard's four rules; the mask with the
+// lowest score is used.
+func (c *Code) penalty() int {
+	score := 0
+	// Rule 1: runs of five or more same-colour modules in a row or column.
+	line := func(get func(i int) bool) {
+		run := 1
+		for i := 1; i <= c.Size; i++ {
+			if i < c.Size && get(i) == get(i-1) {
+				run++
+				continue
+			}
+			if run >= 5 {
+				score += run - 2
+			}
+			run = 1
+		}
+	}
+	for k := 0; k < c.Size; k++ {
+		line(func(i int) bool { return c.dark[k][i] })
+		line(func(i int) bool { return c.dark[i][k] })
+	}
+	// Rule 2: 2x2 blocks of one colour.
+	for y := 0; y < c.Size-1; y++ {
+		for x := 0; x < c.Size-1; x++ {
+			v := c.dark[y][x]
+			if c.dark[y][x+1] == v && c.dark[y+1][x] == v && c.dark[y+1][x+1] == v {
+				score += 3
+			}
+		}
+	}
+	// Rule 3: finder-like 1:1:3:1:1 patterns with four light modules beside.
+	pattern := []bool{true, false, true, true, true, false, true}
+	finderLike := func(get func(i int) bool) {
+		for i := 0; i+7 <= c.Size; i++ {
+			match := true
+			for j, want := range pattern {
+				if get(i+j) != want {
+					match = false
+					break
+				}
+			}
+			if !match {
+				continue
+			}
+			lightBefore, lightAfter := true, true
+			for j := 1; j <= 4; j++ {
+				lightBefore = lightBefore && (i-j < 0 || !get(i-j))
+				lightAfter = lightAfter && (i+6+j >= c.Size || !get(i+6+j))
+			}
+			if lightBefore || lightAfter {
+				score += 40
+			}
+		}
+	}
+	for k := 0; k < c.Size; k++ {
+		finderLike(func(i int) bool { return c.dark[k][i] })
+		finderLike(func(i int) bool { return c.dark[i][k] })
+	}
+	// Rule 4: deviation of the dark proportion from half.
+	dark := 0
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if c.dark[y][x] {
+				dark++
+			}
+		}
+	}
+	total := c.Size * c.Size
+	score += abs(dark*20-total*10) / total * 10
+	return score
+}
+
+// Terminal renders the code with half-block characters, two module rows per
+// text line, quiet zone included. Light modules are drawn and dark ones left
+// blank, which reads correctly on a dark terminal background; invert swaps
+// that for light backgrounds.
+func (c *Code) Terminal(invert bool) string {
+	lit := func(x, y int) bool { return c.Dark(x, y) == invert }
+	var sb strings.Builder
+	for y := -quietZone; y < c.Size+quietZone; y += 2 {
+		for x := -quietZone; x < c.Size+quietZone; x++ {
+			top, bottom := lit(x, y), lit(x, y+1)
+			if y+1 >= c.Size+quietZone {
+				bottom = false
+			}
+			switch {
+			case top && bottom:
+				sb.WriteRune('█')
+			case top:
+				sb.WriteRune('▀')
+			case bottom:
+				sb.WriteRune('▄')
+			default:
+				sb.WriteByte(' ')
+			}
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// WritePNG writes the code as a black-on-white PNG, scale pixels per module,
+// quiet zone included.
+func (c *Code) WritePNG(w io.Writer, scale int) error {
+	if scale < 1 {
+		scale = 1
+	}
+	side := (c.Size + 2*quietZone) * scale
+	img := image.NewPaletted(image.Rect(0, 0, side, side), color.Palette{color.White, color.Black})
+	for py := 0; py < side; py++ {
+		for px := 0; px < side; px++ {
+			if c.Dark(px/scale-quietZone, py/scale-quietZone) {
+				img.SetColorIndex(px, py, 1)
+			}
+		}
+	}
+	return png.Encode(w, img)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,192 @@
+package qrcode
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestRSRemainderKnownAnswer(t *testing.T) {
+	// "HELLO WORLD" at 1-M, from the standard's worked example.
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	want := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+	if got := rsRemainder(data, rsDivisor(10)); !bytes.Equal(got, want) {
+		t.Errorf("EC codewords = %v, want %v", got, want)
+	}
+}
+
+// readFormat returns the 15 format bits from the copy around the top-left
+// finder.
+func readFormat(c *Code) int {
+	var bits int
+	set := func(i int, dark bool) {
+		if dark {
+			bits |= 1 << i
+		}
+	}
+	for i := 0; i <= 5; i++ {
+		set(i, c.dark[i][8])
+	}
+	set(6, c.dark[7][8])
+	set(7, c.dark[8][8])
+	set(8, c.dark[8][7])
+	for i := 9; i < 15; i++ {
+		set(i, c.dark[8][14-i])
+	}
+	return bits
+}
+
+// TestEncodeRoundTrip reads a symbol back — format, unmasking, codeword
+// order, error correction, and the byte-mode payload.
+func TestEncodeRoundTrip(t *testing.T) {
+	for _, text := range []string{
+		"WIFI:T:nopass;S:guest;;",
+		"WIFI:T:WPA;S:Conference Room 4;P:" + strings.Repeat("x", 63) + ";;",
+		strings.Repeat("é", 100),
+	} {
+		c, err := Encode(text)
+		if err != nil {
+			t.Fatalf("Encode(%d bytes): %v", len(text), err)
+		}
+		if c.Size != 17+4*c.Version || !c.Dark(8, c.Size-8) {
+			t.Fatalf("version %d: bad size or missing dark module", c.Version)
+		}
+
+		format := readFormat(c) ^ 0x5412
+		if format>>13 != 0 {
+			t.Fatalf("format level bits = %02b, want M (00)", format>>13)
+		}
+		mask := format >> 10 & 7
+		check := c.copy()
+		check.drawFormat(mask)
+		if readFormat(check) != readFormat(c) {
+			t.Fatal("format BCH bits do not match the mask")
+		}
+
+		c.applyMask(mask)
+		codewords := c.readData()
+		c.applyMask(mask)
+
+		spec := levelM[c.Version]
+		n := spec.g1 + spec.g2
+		blocks := make([][]byte, n)
+		i := 0
+		for k := 0; k < max(spec.g1Data, spec.g2Data); k++ {
+			for b := 0; b < n; b++ {
+				if b < spec.g1 && k >= spec.g1Data {
+					continue
+				}
+				blocks[b] = append(blocks[b], codewords[i])
+				i++
+			}
+		}
+		var data []byte
+		for _, b := range blocks {
+			ec := make([]byte, spec.ec)
+			for k := range ec {
+				ec[k] = codewords[i+k*n]
+			}
+			i++
+			if !bytes.Equal(rsRemainder(b, rsDivisor(spec.ec)), ec) {
+				t.Fatalf("version %d: EC codewords do not match their block", c.Version)
+			}
+			data = append(data, b...)
+		}
+
+		if data[0]>>4 != 0x4 {
+			t.Fatalf("mode = %x, want byte mode", data[0]>>4)
+		}
+		bitsAt := func(off, n int) int {
+			v := 0
+			for k := 0; k < n; k++ {
+				bit := off + k
+				v = v<<1 | int(data[bit/8]>>(7-bit%8)&1)
+			}
+			return v
+		}
+		count := bitsAt(4, countBits(c.Version))
+		payload := make([]byte, count)
+		for k := range payload {
+			payload[k] = byte(bitsAt(4+countBits(c.Version)+8*k, 8))
+		}
+		if string(payload) != text {
+			t.Errorf("decoded %q, want %q", payload, text)
+		}
+	}
+}
+
+func (c *Code) copy() *Code {
+	cp := newCode(c.Version)
+	for y := range c.dark {
+		copy(cp.dark[y], c.dark[y])
+		copy(cp.fn[y], c.fn[y])
+	}
+	return cp
+}
+
+// readData collects the data modules in placement order.
+func (c *Code) readData() []byte {
+	var out []byte
+	var cur byte
+	n := 0
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		upward := (right+1)&2 == 0
+		for vert := 0; vert < c.Size; vert++ {
+			y := vert
+			if upward {
+				y = c.Size - 1 - vert
+			}
+			for j := 0; j < 2; j++ {
+				if c.fn[y][right-j] {
+					continue
+				}
+				cur <<= 1
+				if c.dark[y][right-j] {
+					cur |= 1
+				}
+				if n++; n%8 == 0 {
+					out = append(out, cur)
+					cur = 0
+				}
+			}
+		}
+	}
+	return out
+}
+
+func TestEncodeTooLong(t *testing.T) {
+	if _, err := Encode(strings.Repeat("a", 214)); err == nil {
+		t.Error("214 bytes encoded; want a capacity error")
+	}
+	if c, err := Encode(strings.Repeat("a", 213)); err != nil || c.Version != 10 {
+		t.Errorf("213 bytes: %v", err)
+	}
+}
+
+func TestRender(t *testing.T) {
+	c, err := Encode("WIFI:T:nopass;S:guest;;")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(c.Terminal(false), "\n"), "\n")
+	side := c.Size + 2*quietZone
+	if len(lines) != (side+1)/2 || len([]rune(lines[0])) != side {
+		t.Errorf("terminal output is %dx%d, want %dx%d", len([]rune(lines[0])), len(lines), side, (side+1)/2)
+	}
+
+	var buf bytes.Buffer
+	if err := c.WritePNG(&buf, 4); err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds().Dx() != side*4 {
+		t.Errorf("PNG width = %d, want %d", img.Bounds().Dx(), side*4)
+	}
+}
@@ -0,0 +1,49 @@
+package qrcode
+
+// gfMul multiplies in GF(2^8) modulo the QR polynomial x^8+x^4+x^3+x^2+1.
+func gfMul(a, b byte) byte {
+	var p byte
+	for i := 7; i >= 0; i-- {
+		carry := p & 0x80
+		p <<= 1
+		if carry != 0 {
+			p ^= 0x1D
+		}
+		if b>>i&1 == 1 {
+			p ^= a
+		}
+	}
+	return p
+}
+
+// rsDivisor returns the coefficients, highest power first and the leading 1
+// omitted, of the Reed-Solomon generator polynomial of the given degree.
+func rsDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+	var root byte = 1
+	for i := 0; i < degree; i++ {
+		for j := range result {
+			result[j] = gfMul(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMul(root, 0x02)
+	}
+	return result
+}
+
+// rsRemainder returns the error correction codewords of data.
+func rsRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i, coef := range divisor {
+			result[i] ^= gfMul(coef, factor)
+		}
+	}
+	return result
+}
@@ -0,0 +1,57 @@
+package qrcode
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WiFi is the content of a WiFi-join QR code.
+type WiFi struct {
+	SSID       string
+	Security   string // "WPA" or "nopass"
+	Passphrase string
+	Hidden     bool
+}
+
+// String renders w in the WIFI: URI format phone cameras recognize.
+func (w WiFi) String() string {
+	esc := strings.NewReplacer(`\`, `\\`, `;`, `\;`, `,`, `\,`, `:`, `\:`, `"`, `\"`)
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "WIFI:T:%s;S:%s;", w.Security, esc.Replace(w.SSID))
+	if w.Passphrase != "" {
+		fmt.Fprintf(&sb, "P:%s;", esc.Replace(w.Passphrase))
+	}
+	if w.Hidden {
+		sb.WriteString("H:true;")
+	}
+	sb.WriteString(";")
+	return sb.String()
+}
+
+// WiFiFromWLAN reads the ssid, hidden flag, and auth of a WLAN config in
+// wifimgr's template form. The passphrase is returned as stored, possibly
+// still enc:. Enterprise auth has no QR equivalent and is an error.
+func WiFiFromWLAN(wlan map[string]any) (WiFi, error) {
+	var w WiFi
+	w.SSID, _ = wlan["ssid"].(string)
+	if w.SSID == "" {
+		return w, fmt.Errorf("template has no ssid")
+	}
+	w.Hidden, _ = wlan["hidden"].(bool)
+
+	auth, _ := wlan["auth"].(map[string]any)
+	authType, _ := auth["type"].(string)
+	switch strings.ToLower(authType) {
+	case "", "open", "owe":
+		w.Security = "nopass"
+	case "psk", "sae", "psk-wpa2-wpa3":
+		w.Security = "WPA"
+		w.Passphrase, _ = auth["psk"].(string)
+		if w.Passphrase == "" {
+			return w, fmt.Errorf("auth type %s has no psk", authType)
+		}
+	default:
+		return w, fmt.Errorf("auth type %s cannot be joined with a QR code", authType)
+	}
+	return w, nil
+}
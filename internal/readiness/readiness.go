@@ -0,0 +1,207 @@
+// Package readiness rolls a site's validation results, drift, security
+// findings, and naming compliance into one 0-100 readiness score with a
+// per-category breakdown.
+//
+// Each category scores 0-100 on its own; the site score is their weighted
+// average. The inputs are gathered by the caller (lint results, the API
+// cache, WLAN templates), so scoring itself is pure.
+package readiness
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ravinald/wifimgr/internal/encryption"
+)
+
+// Category names, in report order.
+const (
+	CategoryValidation = "validation"
+	CategoryDrift      = "drift"
+	CategorySecurity   = "security"
+	CategoryNaming     = "naming"
+)
+
+// weights is each category's share of the site score.
+var weights = map[string]int{
+	CategoryValidation: 35,
+	CategoryDrift:      25,
+	CategorySecurity:   25,
+	CategoryNaming:     15,
+}
+
+// Deductions, in points, for validation issues and security findings.
+const (
+	lintErrorPenalty   = 20
+	lintWarningPenalty = 5
+	openWLANPenalty    = 30
+	clearPSKPenalty    = 30
+	wpa2OnlyPenalty    = 10
+)
+
+// Device is one device in the site's intent, with what the API cache knows
+// about it.
+type Device struct {
+	MAC        string
+	Type       string
+	IntentName string
+	InCache    bool // the API inventory has the device
+	Drifted    bool // the cached config differs from intent
+}
+
+// Input is everything a site is scored on.
+type Input struct {
+	Site          string
+	LintErrors    []string
+	LintWarnings  []string
+	Devices       []Device
+	WLANs         map[string]map[string]any // WLAN label -> template, for the WLANs the site broadcasts
+	NamingPattern *regexp.Regexp            // device names must match; nil only requires a unique name
+}
+
+// Category is one line of the breakdown.
+type Category struct {
+	Name     string   `json:"name"`
+	Weight   int      `json:"weight"`
+	Score    int      `json:"score"`
+	Findings []string `json:"findings,omitempty"`
+}
+
+// Result is a site's readiness.
+type Result struct {
+	Site       string     `json:"site"`
+	Score      int        `json:"score"`
+	Categories []Category `json:"categories"`
+}
+
+// Category returns the named category of r.
+func (r Result) Category(name string) Category {
+	for _, c := range r.Categories {
+		if c.Name == name {
+			return c
+		}
+	}
+	return Category{Name: name}
+}
+
+// Score computes the readiness of one site.
+func Score(in Input) Result {
+	cats := []Category{
+		validation(in),
+		drift(in),
+		security(in),
+		naming(in),
+	}
+	var sum, total int
+	for i := range cats {
+		cats[i].Weight = weights[cats[i].Name]
+		sum += cats[i].Score * cats[i].Weight
+		total += cats[i].Weight
+	}
+	return Result{Site: in.Site, Score: int(math.Round(float64(sum) / float64(total))), Categories: cats}
+}
+
+func deduct(points int) int {
+	return max(0, 100-points)
+}
+
+// ratio scores good out of n, 100 when there is nothing to score.
+func ratio(good, n int) int {
+	if n == 0 {
+		return 100
+	}
+	return int(math.Round(100 * float64(good) / float64(n)))
+}
+
+func validation(in Input) Category {
+	c := Category{Name: CategoryValidation}
+	c.Score = deduct(len(in.LintErrors)*lintErrorPenalty + len(in.LintWarnings)*lintWarningPenalty)
+	for _, e := range in.LintErrors {
+		c.Findings = append(c.Findings, "error: "+e)
+	}
+	for _, w := range in.LintWarnings {
+		c.Findings = append(c.Findings, "warning: "+w)
+	}
+	return c
+}
+
+func drift(in Input) Category {
+	c := Category{Name: CategoryDrift}
+	good := 0
+	for _, d := range in.Devices {
+		switch {
+		case !d.InCache:
+			c.Findings = append(c.Findings, fmt.Sprintf("%s %s: not in the API inventory", d.Type, deviceLabel(d)))
+		case d.Drifted:
+			c.Findings = append(c.Findings, fmt.Sprintf("%s %s: API config differs from intent", d.Type, deviceLabel(d)))
+		default:
+			good++
+		}
+	}
+	c.Score = ratio(good, len(in.Devices))
+	return c
+}
+
+func security(in Input) Category {
+	c := Category{Name: CategorySecurity}
+	labels := make([]string, 0, len(in.WLANs))
+	for label := range in.WLANs {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+
+	points := 0
+	for _, label := range labels {
+		auth, _ := in.WLANs[label]["auth"].(map[string]any)
+		authType, _ := auth["type"].(string)
+		switch strings.ToLower(authType) {
+		case "", "open":
+			points += openWLANPenalty
+			c.Findings = append(c.Findings, fmt.Sprintf("WLAN %s is open (no encryption); consider owe", label))
+		case "psk":
+			points += wpa2OnlyPenalty
+			c.Findings = append(c.Findings, fmt.Sprintf("WLAN %s is WPA2-only; consider psk-wpa2-wpa3 or sae", label))
+		}
+		if psk, _ := auth["psk"].(string); psk != "" && !encryption.IsEncrypted(psk) {
+			points += clearPSKPenalty
+			c.Findings = append(c.Findings, fmt.Sprintf("WLAN %s stores its PSK in clear text; encrypt it with 'wifimgr encrypt'", label))
+		}
+	}
+	c.Score = deduct(points)
+	return c
+}
+
+func naming(in Input) Category {
+	c := Category{Name: CategoryNaming}
+	seen := make(map[string]int)
+	for _, d := range in.Devices {
+		if d.IntentName != "" {
+			seen[strings.ToLower(d.IntentName)]++
+		}
+	}
+	good := 0
+	for _, d := range in.Devices {
+		switch {
+		case d.IntentName == "":
+			c.Findings = append(c.Findings, fmt.Sprintf("%s %s: no name", d.Type, d.MAC))
+		case seen[strings.ToLower(d.IntentName)] > 1:
+			c.Findings = append(c.Findings, fmt.Sprintf("%s %s: name is used more than once", d.Type, d.IntentName))
+		case in.NamingPattern != nil && !in.NamingPattern.MatchString(d.IntentName):
+			c.Findings = append(c.Findings, fmt.Sprintf("%s %s: does not match the naming pattern", d.Type, d.IntentName))
+		default:
+			good++
+		}
+	}
+	c.Score = ratio(good, len(in.Devices))
+	return c
+}
+
+func deviceLabel(d Device) string {
+	if d.IntentName != "" {
+		return d.IntentName
+	}
+	return d.MAC
+}
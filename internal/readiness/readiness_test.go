@@ -0,0 +1,62 @@
+package readiness
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestScorePerfectSite(t *testing.T) {
+	r := Score(Input{
+		Site:    "LAB-01",
+		Devices: []Device{{MAC: "aabbccddeeff", Type: "ap", IntentName: "LAB-01-AP-01", InCache: true}},
+		WLANs: map[string]map[string]any{
+			"corp": {"auth": map[string]any{"type": "sae", "psk": "enc:abc"}},
+		},
+		NamingPattern: regexp.MustCompile(`^[A-Z]{3}-\d{2}-AP-\d{2}$`),
+	})
+	if r.Score != 100 {
+		t.Errorf("score = %d, want 100: %+v", r.Score, r.Categories)
+	}
+}
+
+func TestScoreBreakdown(t *testing.T) {
+	r := Score(Input{
+		Site:         "LAB-02",
+		LintErrors:   []string{"ap-1: bad channel"},
+		LintWarnings: []string{"ap-2: deprecated field"},
+		Devices: []Device{
+			{MAC: "000000000001", Type: "ap", IntentName: "lab-ap-1", InCache: true},
+			{MAC: "000000000002", Type: "ap", IntentName: "lab-ap-1", InCache: true, Drifted: true},
+			{MAC: "000000000003", Type: "ap", InCache: false},
+			{MAC: "000000000004", Type: "switch", IntentName: "core", InCache: true},
+		},
+		WLANs: map[string]map[string]any{
+			"guest": {"auth": map[string]any{"type": "open"}},
+			"corp":  {"auth": map[string]any{"type": "psk", "psk": "hunter22"}},
+		},
+		NamingPattern: regexp.MustCompile(`^lab-`),
+	})
+
+	want := map[string]struct{ score, findings int }{
+		CategoryValidation: {75, 2}, // -20 error, -5 warning
+		CategoryDrift:      {50, 2}, // 2 of 4 in sync
+		CategorySecurity:   {30, 3}, // open -30, WPA2-only -10, clear PSK -30
+		CategoryNaming:     {0, 4},  // duplicate x2, unnamed, pattern miss
+	}
+	for name, w := range want {
+		c := r.Category(name)
+		if c.Score != w.score || len(c.Findings) != w.findings {
+			t.Errorf("%s = %d with %d findings %v, want %d with %d", name, c.Score, len(c.Findings), c.Findings, w.score, w.findings)
+		}
+	}
+	// (75*35 + 50*25 + 30*25 + 0*15) / 100 = 46.25
+	if r.Score != 46 {
+		t.Errorf("score = %d, want 46", r.Score)
+	}
+}
+
+func TestScoreEmptySite(t *testing.T) {
+	if r := Score(Input{Site: "EMPTY"}); r.Score != 100 {
+		t.Errorf("empty site scored %d, want 100", r.Score)
+	}
+}
@@ -13,6 +13,8 @@ import (
 	"time"
 
 	"golang.org/x/term"
+
+	"github.com/ravinald/wifimgr/internal/symbols"
 )
 
 // Reporter receives progress events for a cache refresh. doRefreshAPI drives the
@@ -43,9 +45,10 @@ func New(labels []string, interactive bool) (Reporter, func()) {
 
 // Interactive reports whether stdout can host the live board: a real terminal
 // that isn't the dumb fallback. A pipe, redirect, or TERM=dumb falls back to
-// linear text so captured output stays free of cursor-control escapes.
+// linear text so captured output stays free of cursor-control escapes, and so
+// does --plain, whose repainting spinner rows a screen reader cannot follow.
 func Interactive() bool {
-	if os.Getenv("TERM") == "dumb" {
+	if os.Getenv("TERM") == "dumb" || symbols.Plain() {
 		return false
 	}
 	return term.IsTerminal(int(os.Stdout.Fd())) // #nosec G115 -- fds are small non-negative ints
@@ -0,0 +1,157 @@
+// Package remotebackup mirrors local rollback points (intent config backups and
+// per-API cache snapshots) to an S3-compatible object store, so a laptop or an
+// ephemeral CI runner is never the only holder of a restore point.
+//
+// Amazon S3 and Google Cloud Storage are both reached through the S3 XML API
+// with SigV4 request signing. GCS requires HMAC interoperability keys; the
+// endpoint defaults to storage.googleapis.com when provider is "gcs".
+package remotebackup
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/viper"
+
+	"github.com/ravinald/wifimgr/internal/config"
+)
+
+// Supported providers.
+const (
+	ProviderS3  = "s3"
+	ProviderGCS = "gcs"
+)
+
+// Default values applied when the corresponding key is unset.
+const (
+	DefaultPrefix        = "wifimgr"
+	DefaultRetentionDays = 90
+	defaultS3Region      = "us-east-1"
+	defaultGCSRegion     = "auto"
+	defaultGCSEndpoint   = "https://storage.googleapis.com"
+)
+
+// Config holds the backup.remote section of the main config.
+type Config struct {
+	Enabled         bool   `json:"enabled"`
+	Provider        string `json:"provider"`       // "s3" (default) or "gcs"
+	Bucket          string `json:"bucket"`         // Bucket name (required)
+	Prefix          string `json:"prefix"`         // Object key prefix (default: "wifimgr")
+	Region          string `json:"region"`         // SigV4 region (s3 default: us-east-1, gcs: auto)
+	Endpoint        string `json:"endpoint"`       // Override for S3-compatible stores (MinIO, R2, ...)
+	RetentionDays   int    `json:"retention_days"` // Objects older than this are pruned; 0 keeps the default
+	AccessKeyID     string `json:"-"`
+	SecretAccessKey string `json:"-"` // #nosec G117 -- runtime-only, never persisted
+}
+
+// LoadConfig reads backup.remote.* from Viper. It returns (nil, nil) when the
+// remote target is not enabled so callers can treat "not configured" as a
+// silent no-op. Credentials resolve through config.ResolveCredential (env
+// override, enc: decryption) and fall back to the standard AWS_* variables.
+// Under a tenant the prefix gains "/tenants/<name>", so tenants sharing a
+// bucket neither overwrite nor prune each other's objects.
+func LoadConfig() (*Config, error) {
+	if !viper.GetBool("backup.remote.enabled") {
+		return nil, nil
+	}
+	cfg := readConfig()
+	cfg.Enabled = true
+	cfg, err := finishConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Prefix = config.TenantPrefix(cfg.Prefix)
+	return cfg, nil
+}
+
+// LoadBucketConfig returns the settings for a bucket named outside the config
+// (provider, bucket, and prefix from a URL such as s3://bucket/prefix). Region,
+// endpoint, and credentials still come from backup.remote.*, which need not be
+// enabled.
+func LoadBucketConfig(provider, bucket, prefix string) (*Config, error) {
+	cfg := readConfig()
+	if configured := cfg.Provider; configured != provider && (configured != "" || provider != ProviderS3) {
+		// The configured region/endpoint belong to another provider.
+		cfg.Region, cfg.Endpoint = "", ""
+	}
+	cfg.Provider, cfg.Bucket, cfg.Prefix = provider, bucket, prefix
+	return finishConfig(cfg)
+}
+
+// readConfig reads backup.remote.* without applying defaults.
+func readConfig() *Config {
+	cfg := &Config{
+		Provider:      strings.ToLower(viper.GetString("backup.remote.provider")),
+		Bucket:        viper.GetString("backup.remote.bucket"),
+		Prefix:        viper.GetString("backup.remote.prefix"),
+		Region:        viper.GetString("backup.remote.region"),
+		Endpoint:      viper.GetString("backup.remote.endpoint"),
+		RetentionDays: viper.GetInt("backup.remote.retention_days"),
+	}
+	cfg.AccessKeyID = resolveKey("backup.remote.credentials.access_key_id", "AWS_ACCESS_KEY_ID")
+	cfg.SecretAccessKey = resolveKey("backup.remote.credentials.secret_access_key", "AWS_SECRET_ACCESS_KEY")
+	return cfg
+}
+
+func finishConfig(cfg *Config) (*Config, error) {
+	cfg.applyDefaults()
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// resolveKey resolves a credential from config/env, falling back to a
+// provider-standard environment variable.
+func resolveKey(configPath, fallbackEnv string) string {
+	if v, err := config.ResolveCredential(configPath); err == nil {
+		return v
+	}
+	return os.Getenv(fallbackEnv)
+}
+
+// applyDefaults fills provider-dependent defaults.
+func (c *Config) applyDefaults() {
+	if c.Provider == "" {
+		c.Provider = ProviderS3
+	}
+	if c.Prefix == "" {
+		c.Prefix = DefaultPrefix
+	}
+	c.Prefix = strings.Trim(c.Prefix, "/")
+	if c.RetentionDays <= 0 {
+		c.RetentionDays = DefaultRetentionDays
+	}
+	switch c.Provider {
+	case ProviderGCS:
+		if c.Region == "" {
+			c.Region = defaultGCSRegion
+		}
+		if c.Endpoint == "" {
+			c.Endpoint = defaultGCSEndpoint
+		}
+	default:
+		if c.Region == "" {
+			c.Region = defaultS3Region
+		}
+		if c.Endpoint == "" {
+			c.Endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", c.Region)
+		}
+	}
+	c.Endpoint = strings.TrimRight(c.Endpoint, "/")
+}
+
+// Validate checks that required configuration is present.
+func (c *Config) Validate() error {
+	if c.Provider != ProviderS3 && c.Provider != ProviderGCS {
+		return fmt.Errorf("invalid backup.remote.provider %q: must be 's3' or 'gcs'", c.Provider)
+	}
+	if c.Bucket == "" {
+		return fmt.Errorf("backup.remote.bucket is required when backup.remote.enabled is true")
+	}
+	if c.AccessKeyID == "" || c.SecretAccessKey == "" {
+		return fmt.Errorf("backup.remote credentials are required (backup.remote.credentials.access_key_id/secret_access_key or AWS_ACCESS_KEY_ID/AWS_SECRET_ACCESS_KEY)")
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package remotebackup
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ravinald/wifimgr/internal/logging"
+)
+
+// Kinds of artifacts mirrored to the remote target. Each lands under its own
+// key prefix so retention and listing can be scoped per kind.
+const (
+	KindConfig = "config"
+	KindCache  = "cache"
+)
+
+// mirrorTimeout bounds a single best-effort mirror call so a slow or
+// unreachable bucket never stalls apply or refresh.
+const mirrorTimeout = 60 * time.Second
+
+// Mirror uploads copies of local files to a remote store and prunes objects
+// that have aged out of the retention window.
+type Mirror struct {
+	cfg   *Config
+	store Store
+	now   func() time.Time
+}
+
+// NewMirror returns a Mirror writing to store under cfg's prefix.
+func NewMirror(cfg *Config, store Store) *Mirror {
+	return &Mirror{cfg: cfg, store: store, now: time.Now}
+}
+
+// ObjectKey returns the remote key for a local file of the given kind:
+// <prefix>/<kind>/<basename>/<UTC timestamp>. Each upload is a new object so
+// the bucket holds a history, not just the latest copy.
+func (m *Mirror) ObjectKey(kind, localPath string) string {
+	stamp := m.now().UTC().Format("20060102T150405Z")
+	return path.Join(m.cfg.Prefix, kind, filepath.Base(localPath), stamp)
+}
+
+// Upload copies one local file to the remote store and returns the object key.
+func (m *Mirror) Upload(ctx context.Context, kind, localPath string) (string, error) {
+	data, err := os.ReadFile(localPath) // #nosec G304 -- path from operator-controlled backup/cache dirs
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", localPath, err)
+	}
+	key := m.ObjectKey(kind, localPath)
+	if err := m.store.Put(ctx, key, data); err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+// Prune deletes objects under the configured prefix whose LastModified is
+// older than the retention window. Returns the number of objects removed.
+func (m *Mirror) Prune(ctx context.Context) (int, error) {
+	objects, err := m.store.List(ctx, m.cfg.Prefix+"/")
+	if err != nil {
+		return 0, err
+	}
+	cutoff := m.now().AddDate(0, 0, -m.cfg.RetentionDays)
+	removed := 0
+	for _, obj := range objects {
+		if obj.LastModified.IsZero() || !obj.LastModified.Before(cutoff) {
+			continue
+		}
+		if err := m.store.Delete(ctx, obj.Key); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// FromConfig builds a Mirror from backup.remote.* settings. Returns (nil, nil)
+// when no remote target is enabled.
+func FromConfig() (*Mirror, error) {
+	cfg, err := LoadConfig()
+	if err != nil || cfg == nil {
+		return nil, err
+	}
+	return NewMirror(cfg, NewS3Store(cfg)), nil
+}
+
+// MirrorFiles is the best-effort hook used after a local backup or cache save:
+// it uploads each file, prunes expired objects, and logs (never returns)
+// failures so the local operation that triggered it is not affected. It is a
+// no-op when the remote target is not enabled.
+func MirrorFiles(kind string, localPaths ...string) {
+	m, err := FromConfig()
+	if err != nil {
+		logging.Warnf("Remote backup disabled: %v", err)
+		return
+	}
+	if m == nil || len(localPaths) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), mirrorTimeout)
+	defer cancel()
+
+	var uploaded []string
+	for _, p := range localPaths {
+		key, err := m.Upload(ctx, kind, p)
+		if err != nil {
+			logging.Warnf("Remote backup of %s failed: %v", filepath.Base(p), err)
+			continue
+		}
+		uploaded = append(uploaded, key)
+	}
+	if len(uploaded) > 0 {
+		logging.Infof("Mirrored %d %s file(s) to %s://%s/%s", len(uploaded), kind,
+			m.cfg.Provider, m.cfg.Bucket, strings.Join(uploaded, ", "))
+	}
+
+	if removed, err := m.Prune(ctx); err != nil {
+		logging.Warnf("Remote backup retention pass failed: %v", err)
+	} else if removed > 0 {
+		logging.Infof("Pruned %d remote backup object(s) older than %d days", removed, m.cfg.RetentionDays)
+	}
+}
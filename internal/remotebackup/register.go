@@ -0,0 +1,41 @@
+package remotebackup
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/viper"
+
+	"github.com/ravinald/wifimgr/internal/integrations"
+)
+
+func init() {
+	integrations.Register(integrations.Integration{
+		Name:           "remote-backup",
+		ConfigKey:      "backup.remote",
+		EndpointKeys:   "backup.remote.bucket, region, and endpoint",
+		CredentialKeys: "backup.remote.credentials (AWS_ACCESS_KEY_ID/AWS_SECRET_ACCESS_KEY)",
+		Configured:     func() bool { return viper.GetBool("backup.remote.enabled") },
+		Check:          check,
+		PostRefresh: func(_ context.Context, ev integrations.RefreshEvent) error {
+			MirrorFiles(KindCache, ev.Files...)
+			return nil
+		},
+	})
+}
+
+// check lists the bucket under the configured prefix.
+func check(ctx context.Context) (string, error) {
+	cfg, err := LoadConfig()
+	if err != nil {
+		return "", err
+	}
+	if cfg == nil {
+		return "", fmt.Errorf("backup.remote is not enabled")
+	}
+	objects, err := NewS3Store(cfg).List(ctx, cfg.Prefix+"/")
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s bucket %s, %d object(s) under %s/", cfg.Provider, cfg.Bucket, len(objects), cfg.Prefix), nil
+}
@@ -0,0 +1,156 @@
+package remotebackup
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestConfigDefaults(t *testing.T) {
+	tests := []struct {
+		name         string
+		cfg          Config
+		wantEndpoint string
+		wantRegion   string
+	}{
+		{"s3 default", Config{Bucket: "b"}, "https://s3.us-east-1.amazonaws.com", "us-east-1"},
+		{"s3 region", Config{Bucket: "b", Region: "eu-west-1"}, "https://s3.eu-west-1.amazonaws.com", "eu-west-1"},
+		{"gcs", Config{Bucket: "b", Provider: ProviderGCS}, "https://storage.googleapis.com", "auto"},
+		{"custom endpoint", Config{Bucket: "b", Endpoint: "http://minio:9000/"}, "http://minio:9000", "us-east-1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			cfg.applyDefaults()
+			if cfg.Endpoint != tt.wantEndpoint {
+				t.Errorf("Endpoint = %q, want %q", cfg.Endpoint, tt.wantEndpoint)
+			}
+			if cfg.Region != tt.wantRegion {
+				t.Errorf("Region = %q, want %q", cfg.Region, tt.wantRegion)
+			}
+			if cfg.Prefix != DefaultPrefix || cfg.RetentionDays != DefaultRetentionDays {
+				t.Errorf("Prefix/Retention = %q/%d, want defaults", cfg.Prefix, cfg.RetentionDays)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	base := Config{Provider: ProviderS3, Bucket: "b", AccessKeyID: "a", SecretAccessKey: "s"}
+	if err := base.Validate(); err != nil {
+		t.Fatalf("valid config rejected: %v", err)
+	}
+	noBucket := base
+	noBucket.Bucket = ""
+	if err := noBucket.Validate(); err == nil {
+		t.Error("expected error for missing bucket")
+	}
+	noCreds := base
+	noCreds.SecretAccessKey = ""
+	if err := noCreds.Validate(); err == nil {
+		t.Error("expected error for missing credentials")
+	}
+	badProvider := base
+	badProvider.Provider = "azure"
+	if err := badProvider.Validate(); err == nil {
+		t.Error("expected error for unknown provider")
+	}
+}
+
+// fakeBucket is a tiny in-memory S3 endpoint: PUT, DELETE, and ListObjectsV2.
+type fakeBucket struct {
+	mu       sync.Mutex
+	objects  map[string]time.Time
+	sawAuthz bool
+}
+
+func (f *fakeBucket) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 ") {
+		f.sawAuthz = true
+	}
+	key := strings.TrimPrefix(r.URL.Path, "/bucket")
+	key = strings.TrimPrefix(key, "/")
+	switch r.Method {
+	case http.MethodPut:
+		_, _ = io.ReadAll(r.Body)
+		f.objects[key] = time.Now().UTC()
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodGet:
+		type content struct {
+			Key          string `xml:"Key"`
+			LastModified string `xml:"LastModified"`
+		}
+		var out struct {
+			XMLName  xml.Name  `xml:"ListBucketResult"`
+			Contents []content `xml:"Contents"`
+		}
+		prefix := r.URL.Query().Get("prefix")
+		for k, ts := range f.objects {
+			if strings.HasPrefix(k, prefix) {
+				out.Contents = append(out.Contents, content{Key: k, LastModified: ts.Format(time.RFC3339)})
+			}
+		}
+		_ = xml.NewEncoder(w).Encode(out)
+	}
+}
+
+func TestMirrorUploadAndPrune(t *testing.T) {
+	bucket := &fakeBucket{objects: map[string]time.Time{
+		"wifimgr/config/old.json/20200101T000000Z": time.Now().AddDate(0, 0, -200),
+		"other/keep-me": time.Now().AddDate(0, 0, -200),
+	}}
+	srv := httptest.NewServer(bucket)
+	defer srv.Close()
+
+	cfg := &Config{Bucket: "bucket", Endpoint: srv.URL, AccessKeyID: "a", SecretAccessKey: "s"}
+	cfg.applyDefaults()
+	m := NewMirror(cfg, NewS3Store(cfg))
+	m.now = func() time.Time { return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	local := filepath.Join(t.TempDir(), "site.json.0")
+	if err := os.WriteFile(local, []byte(`{"version":1}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := m.Upload(context.Background(), KindConfig, local)
+	if err != nil {
+		t.Fatalf("Upload: %v", err)
+	}
+	if want := "wifimgr/config/site.json.0/20260102T030405Z"; key != want {
+		t.Errorf("key = %q, want %q", key, want)
+	}
+	if _, ok := bucket.objects[key]; !ok {
+		t.Errorf("object %q not stored", key)
+	}
+	if !bucket.sawAuthz {
+		t.Error("requests were not SigV4 signed")
+	}
+
+	// Prune uses the real clock for the cutoff relative to m.now.
+	m.now = time.Now
+	removed, err := m.Prune(context.Background())
+	if err != nil {
+		t.Fatalf("Prune: %v", err)
+	}
+	if removed != 1 {
+		t.Errorf("removed = %d, want 1", removed)
+	}
+	if _, ok := bucket.objects["other/keep-me"]; !ok {
+		t.Error("object outside prefix was pruned")
+	}
+	if _, ok := bucket.objects[key]; !ok {
+		t.Error("fresh object was pruned")
+	}
+}
@@ -0,0 +1,198 @@
+package remotebackup
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ravinald/wifimgr/internal/sigv4"
+)
+
+// ObjectInfo describes one object returned by a bucket listing.
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
+
+// Store is the minimal object-store surface the mirror needs. The S3 client
+// satisfies it; tests substitute an in-memory fake.
+type Store interface {
+	Put(ctx context.Context, key string, body []byte) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	List(ctx context.Context, prefix string) ([]ObjectInfo, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// s3Client talks to an S3-compatible endpoint using path-style addressing
+// (endpoint/bucket/key), which both AWS and GCS interop accept.
+type s3Client struct {
+	cfg        *Config
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+// NewS3Store returns a Store backed by the configured S3-compatible endpoint.
+func NewS3Store(cfg *Config) Store {
+	return &s3Client{
+		cfg:        cfg,
+		httpClient: &http.Client{Timeout: 60 * time.Second},
+		now:        time.Now,
+	}
+}
+
+// objectURL builds the path-style URL for a key (or the bucket root when key
+// is empty).
+func (c *s3Client) objectURL(key string, query url.Values) (*url.URL, error) {
+	u, err := url.Parse(c.cfg.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid backup.remote.endpoint %q: %w", c.cfg.Endpoint, err)
+	}
+	u.Path = "/" + c.cfg.Bucket
+	if key != "" {
+		u.Path += "/" + key
+	}
+	u.RawPath = "/" + sigv4.URIEncode(c.cfg.Bucket, false)
+	if key != "" {
+		u.RawPath += "/" + sigv4.URIEncode(key, false)
+	}
+	if query != nil {
+		u.RawQuery = sigv4.CanonicalQuery(query)
+	}
+	return u, nil
+}
+
+// Put uploads body under key.
+func (c *s3Client) Put(ctx context.Context, key string, body []byte) error {
+	u, err := c.objectURL(key, nil)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build upload request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.ContentLength = int64(len(body))
+
+	_, err = c.do(req, body)
+	if err != nil {
+		return fmt.Errorf("upload %s: %w", key, err)
+	}
+	return nil
+}
+
+// Get downloads the object at key.
+func (c *s3Client) Get(ctx context.Context, key string) ([]byte, error) {
+	u, err := c.objectURL(key, nil)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build download request: %w", err)
+	}
+	body, err := c.do(req, nil)
+	if err != nil {
+		return nil, fmt.Errorf("download %s: %w", key, err)
+	}
+	return body, nil
+}
+
+// Delete removes the object at key.
+func (c *s3Client) Delete(ctx context.Context, key string) error {
+	u, err := c.objectURL(key, nil)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, u.String(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to build delete request: %w", err)
+	}
+	if _, err := c.do(req, nil); err != nil {
+		return fmt.Errorf("delete %s: %w", key, err)
+	}
+	return nil
+}
+
+// listBucketResult is the subset of the ListObjectsV2 response we consume.
+type listBucketResult struct {
+	Contents []struct {
+		Key          string `xml:"Key"`
+		Size         int64  `xml:"Size"`
+		LastModified string `xml:"LastModified"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// List returns every object whose key starts with prefix, following
+// continuation tokens until the listing is exhausted.
+func (c *s3Client) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+	token := ""
+	for {
+		q := url.Values{}
+		q.Set("list-type", "2")
+		q.Set("prefix", prefix)
+		if token != "" {
+			q.Set("continuation-token", token)
+		}
+		u, err := c.objectURL("", q)
+		if err != nil {
+			return nil, err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build list request: %w", err)
+		}
+		body, err := c.do(req, nil)
+		if err != nil {
+			return nil, fmt.Errorf("list %s: %w", prefix, err)
+		}
+
+		var result listBucketResult
+		if err := xml.Unmarshal(body, &result); err != nil {
+			return nil, fmt.Errorf("failed to parse bucket listing: %w", err)
+		}
+		for _, obj := range result.Contents {
+			modified, _ := time.Parse(time.RFC3339, obj.LastModified)
+			objects = append(objects, ObjectInfo{Key: obj.Key, Size: obj.Size, LastModified: modified})
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return objects, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+// do signs and sends req, returning the response body on 2xx.
+func (c *s3Client) do(req *http.Request, payload []byte) ([]byte, error) {
+	sigv4.Sign(req, payload, sigv4.Credentials{
+		AccessKeyID:     c.cfg.AccessKeyID,
+		SecretAccessKey: c.cfg.SecretAccessKey,
+		Region:          c.cfg.Region,
+		Service:         "s3",
+	}, c.now().UTC())
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
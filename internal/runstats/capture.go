@@ -0,0 +1,61 @@
+package runstats
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"sync/atomic"
+)
+
+// Exchange is one captured API response.
+type Exchange struct {
+	Method string
+	Host   string
+	Path   string // as requested, IDs not collapsed
+	Query  string
+	Status int
+	Body   []byte
+}
+
+var capture atomic.Pointer[func(Exchange)]
+
+// SetCapture hands every API response body to fn, for commands that record
+// real payloads (devtools dump-fixtures); nil turns capture off. Like Enable,
+// it only affects clients built afterwards, and it does not turn on the
+// accounting --stats prints.
+func SetCapture(fn func(Exchange)) {
+	if fn == nil {
+		capture.Store(nil)
+		return
+	}
+	capture.Store(&fn)
+}
+
+// Instrumented reports whether new clients should be wrapped: accounting or
+// capture is on.
+func Instrumented() bool {
+	return Enabled() || capture.Load() != nil
+}
+
+// captureResponse reads resp's body, hands it to the capture callback, and
+// replaces it with a reader over the same bytes.
+func captureResponse(req *http.Request, resp *http.Response) {
+	fn := capture.Load()
+	if fn == nil || resp == nil || resp.Body == nil {
+		return
+	}
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	(*fn)(Exchange{
+		Method: req.Method,
+		Host:   req.URL.Host,
+		Path:   req.URL.Path,
+		Query:  req.URL.RawQuery,
+		Status: resp.StatusCode,
+		Body:   body,
+	})
+}
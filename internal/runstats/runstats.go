@@ -0,0 +1,333 @@
+// Package runstats accounts for the work one command does — API calls per
+// endpoint, bytes on the wire, cache hits and misses, and elapsed phases — so
+// --stats can explain where a slow run spent its time. Recording is off until
+// Enable is called; the hooks are then cheap no-ops.
+package runstats
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	enabled atomic.Bool
+	global  = newRecorder()
+)
+
+// Enable turns recording on for the rest of the process. Call it before API
+// clients are built: InstrumentClient only wraps clients while enabled.
+func Enable() {
+	enabled.Store(true)
+}
+
+// Enabled reports whether recording is on.
+func Enabled() bool {
+	return enabled.Load()
+}
+
+// Endpoint is the accounting for one method and normalized path on one host.
+type Endpoint struct {
+	Host     string        `json:"host"`
+	Method   string        `json:"method"`
+	Path     string        `json:"path"` // IDs, MACs, and serials collapsed to ":id"
+	Calls    int           `json:"calls"`
+	Errors   int           `json:"errors"` // transport errors and HTTP 4xx/5xx
+	BytesOut int64         `json:"bytes_out"`
+	BytesIn  int64         `json:"bytes_in"`
+	Time     time.Duration `json:"time"` // summed time to response headers
+}
+
+// CacheCount is the hits and misses of one kind of cache lookup.
+type CacheCount struct {
+	Kind   string `json:"kind"`
+	Hits   int64  `json:"hits"`
+	Misses int64  `json:"misses"`
+}
+
+// PhaseTime is one named phase of the run.
+type PhaseTime struct {
+	Name     string        `json:"name"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Snapshot is the accounting so far, endpoints ordered by calls (most first).
+type Snapshot struct {
+	Endpoints []Endpoint    `json:"endpoints"`
+	Caches    []CacheCount  `json:"caches"`
+	Phases    []PhaseTime   `json:"phases"`
+	Elapsed   time.Duration `json:"elapsed"`
+}
+
+type recorder struct {
+	mu        sync.Mutex
+	start     time.Time
+	endpoints map[string]*Endpoint
+	caches    map[string]*CacheCount
+	phases    []PhaseTime
+}
+
+func newRecorder() *recorder {
+	return &recorder{
+		start:     time.Now(),
+		endpoints: make(map[string]*Endpoint),
+		caches:    make(map[string]*CacheCount),
+	}
+}
+
+// Reset clears everything recorded and restarts the elapsed clock. Tests use
+// it to start from a clean slate.
+func Reset() {
+	fresh := newRecorder()
+	global.mu.Lock()
+	defer global.mu.Unlock()
+	global.start = fresh.start
+	global.endpoints = fresh.endpoints
+	global.caches = fresh.caches
+	global.phases = nil
+}
+
+// endpoint returns the entry for a request, creating it. Callers hold mu.
+func (r *recorder) endpoint(host, method, path string) *Endpoint {
+	key := host + " " + method + " " + path
+	e, ok := r.endpoints[key]
+	if !ok {
+		e = &Endpoint{Host: host, Method: method, Path: path}
+		r.endpoints[key] = e
+	}
+	return e
+}
+
+// CacheHit records a lookup of kind answered from a cache.
+func CacheHit(kind string) {
+	if Enabled() {
+		global.cache(kind, 1, 0)
+	}
+}
+
+// CacheMiss records a lookup of kind the cache could not answer.
+func CacheMiss(kind string) {
+	if Enabled() {
+		global.cache(kind, 0, 1)
+	}
+}
+
+func (r *recorder) cache(kind string, hits, misses int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.caches[kind]
+	if !ok {
+		c = &CacheCount{Kind: kind}
+		r.caches[kind] = c
+	}
+	c.Hits += hits
+	c.Misses += misses
+}
+
+// Phase starts timing a named phase and returns the function that ends it.
+// Phases are listed in the order they end.
+func Phase(name string) func() {
+	if !Enabled() {
+		return func() {}
+	}
+	start := time.Now()
+	return func() {
+		d := time.Since(start)
+		global.mu.Lock()
+		defer global.mu.Unlock()
+		global.phases = append(global.phases, PhaseTime{Name: name, Duration: d})
+	}
+}
+
+// Snap returns a copy of everything recorded so far.
+func Snap() Snapshot {
+	global.mu.Lock()
+	defer global.mu.Unlock()
+	s := Snapshot{Elapsed: time.Since(global.start)}
+	for _, e := range global.endpoints {
+		s.Endpoints = append(s.Endpoints, *e)
+	}
+	sort.Slice(s.Endpoints, func(i, j int) bool {
+		a, b := s.Endpoints[i], s.Endpoints[j]
+		if a.Calls != b.Calls {
+			return a.Calls > b.Calls
+		}
+		return a.Host+a.Path+a.Method < b.Host+b.Path+b.Method
+	})
+	for _, c := range global.caches {
+		s.Caches = append(s.Caches, *c)
+	}
+	sort.Slice(s.Caches, func(i, j int) bool { return s.Caches[i].Kind < s.Caches[j].Kind })
+	s.Phases = append(s.Phases, global.phases...)
+	return s
+}
+
+// InstrumentClient returns a copy of hc whose transport records every request,
+// or hc itself when neither recording nor capture is on. A nil Transport stands for
+// http.DefaultTransport. Wrap after any option that type-asserts the
+// transport, since the wrapper is not an *http.Transport.
+func InstrumentClient(hc *http.Client) *http.Client {
+	if !Instrumented() || hc == nil {
+		return hc
+	}
+	if _, done := hc.Transport.(*transport); done {
+		return hc
+	}
+	c := *hc
+	c.Transport = RoundTripper(hc.Transport)
+	return &c
+}
+
+// RoundTripper wraps base (nil for http.DefaultTransport) so each request is
+// recorded. Unlike InstrumentClient it wraps even when recording is off; the
+// wrapper then records nothing.
+func RoundTripper(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{base: base}
+}
+
+type transport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !Enabled() {
+		resp, err := t.base.RoundTrip(req)
+		if err == nil {
+			captureResponse(req, resp)
+		}
+		return resp, err
+	}
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	elapsed := time.Since(start)
+
+	global.mu.Lock()
+	e := global.endpoint(req.URL.Host, req.Method, NormalizePath(req.URL.Path))
+	e.Calls++
+	e.Time += elapsed
+	if req.ContentLength > 0 {
+		e.BytesOut += req.ContentLength
+	}
+	if err != nil || resp.StatusCode >= 400 {
+		e.Errors++
+	}
+	global.mu.Unlock()
+
+	if err == nil && resp.Body != nil {
+		captureResponse(req, resp)
+		resp.Body = &countingBody{ReadCloser: resp.Body, e: e}
+	}
+	return resp, err
+}
+
+// countingBody adds the bytes read from a response body to its endpoint.
+type countingBody struct {
+	io.ReadCloser
+	e *Endpoint
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		global.mu.Lock()
+		b.e.BytesIn += int64(n)
+		global.mu.Unlock()
+	}
+	return n, err
+}
+
+// NormalizePath collapses the per-object segments of a URL path (UUIDs, MACs,
+// serials, numeric and vendor IDs) to ":id", so calls for different objects
+// count against one endpoint. A segment is treated as an ID when it is all
+// digits, or is at least 8 characters and contains a digit.
+func NormalizePath(path string) string {
+	segs := strings.Split(path, "/")
+	for i, s := range segs {
+		if isIDSegment(s) {
+			segs[i] = ":id"
+		}
+	}
+	return strings.Join(segs, "/")
+}
+
+func isIDSegment(s string) bool {
+	if s == "" {
+		return false
+	}
+	digits := 0
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			digits++
+		}
+	}
+	return digits == len(s) || (len(s) >= 8 && digits > 0)
+}
+
+// Print writes a human-readable summary of s to w.
+func Print(w io.Writer, s Snapshot) {
+	var calls, errors int
+	var out, in int64
+	for _, e := range s.Endpoints {
+		calls += e.Calls
+		errors += e.Errors
+		out += e.BytesOut
+		in += e.BytesIn
+	}
+
+	_, _ = fmt.Fprintln(w, "\nRun statistics")
+	elapsed := fmt.Sprintf("  Elapsed      %s", roundDuration(s.Elapsed))
+	if len(s.Phases) > 0 {
+		parts := make([]string, 0, len(s.Phases))
+		for _, p := range s.Phases {
+			parts = append(parts, fmt.Sprintf("%s %s", p.Name, roundDuration(p.Duration)))
+		}
+		elapsed += " (" + strings.Join(parts, ", ") + ")"
+	}
+	_, _ = fmt.Fprintln(w, elapsed)
+	_, _ = fmt.Fprintf(w, "  API calls    %d (%d failed)\n", calls, errors)
+	_, _ = fmt.Fprintf(w, "  Transferred  %s sent, %s received\n", FormatBytes(out), FormatBytes(in))
+	for _, c := range s.Caches {
+		_, _ = fmt.Fprintf(w, "  Cache        %s: %d hit(s), %d miss(es)\n", c.Kind, c.Hits, c.Misses)
+	}
+	if len(s.Endpoints) == 0 {
+		return
+	}
+
+	width := len("Endpoint")
+	names := make([]string, len(s.Endpoints))
+	for i, e := range s.Endpoints {
+		names[i] = fmt.Sprintf("%s %s%s", e.Method, e.Host, e.Path)
+		width = max(width, len(names[i]))
+	}
+	_, _ = fmt.Fprintf(w, "\n  %-*s %6s %6s %10s %10s\n", width, "Endpoint", "Calls", "Errors", "Received", "Time")
+	for i, e := range s.Endpoints {
+		_, _ = fmt.Fprintf(w, "  %-*s %6d %6d %10s %10s\n", width, names[i], e.Calls, e.Errors, FormatBytes(e.BytesIn), roundDuration(e.Time))
+	}
+}
+
+// FormatBytes renders n as B, KB, or MB (powers of 1024).
+func FormatBytes(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", n)
+}
+
+func roundDuration(d time.Duration) time.Duration {
+	if d >= time.Second {
+		return d.Round(10 * time.Millisecond)
+	}
+	return d.Round(time.Millisecond)
+}
@@ -0,0 +1,128 @@
+package runstats
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNormalizePath(t *testing.T) {
+	tests := map[string]string{
+		"/api/v1/sites/3f1c2a9e-0b7d-4c1e-9a55-1d2e3f4a5b6c/devices": "/api/v1/sites/:id/devices",
+		"/api/v1/orgs/123456/inventory":                              "/api/v1/orgs/:id/inventory",
+		"/api/v1/networks/L_646829496481105433/wireless/ssids/3":     "/api/v1/networks/:id/wireless/ssids/:id",
+		"/api/v1/devices/Q2XX-ABCD-1234/clients":                     "/api/v1/devices/:id/clients",
+		"/ea/hosts":                                                  "/ea/hosts",
+	}
+	for in, want := range tests {
+		if got := NormalizePath(in); got != want {
+			t.Errorf("NormalizePath(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestInstrumentClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/missing") {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte("0123456789"))
+	}))
+	defer srv.Close()
+
+	enabled.Store(false)
+	hc := &http.Client{}
+	if InstrumentClient(hc) != hc {
+		t.Fatal("disabled InstrumentClient must return the client unchanged")
+	}
+
+	Enable()
+	defer enabled.Store(false)
+	Reset()
+	hc = InstrumentClient(hc)
+	if InstrumentClient(hc) != hc {
+		t.Error("an instrumented client must not be wrapped twice")
+	}
+
+	for _, path := range []string{"/api/v1/sites/12345678/devices", "/api/v1/sites/87654321/devices", "/api/v1/missing"} {
+		resp, err := hc.Post(srv.URL+path, "text/plain", bytes.NewBufferString("abc"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}
+	CacheHit("local cache")
+	CacheHit("local cache")
+	CacheMiss("local cache")
+	Phase("init")()
+
+	s := Snap()
+	if len(s.Endpoints) != 2 {
+		t.Fatalf("endpoints = %+v, want the two device calls folded into one", s.Endpoints)
+	}
+	e := s.Endpoints[0]
+	if e.Path != "/api/v1/sites/:id/devices" || e.Calls != 2 || e.Errors != 0 || e.BytesOut != 6 || e.BytesIn != 20 {
+		t.Errorf("devices endpoint = %+v", e)
+	}
+	if s.Endpoints[1].Errors != 1 {
+		t.Errorf("missing endpoint = %+v, want the 404 counted as an error", s.Endpoints[1])
+	}
+	if len(s.Caches) != 1 || s.Caches[0].Hits != 2 || s.Caches[0].Misses != 1 {
+		t.Errorf("caches = %+v", s.Caches)
+	}
+	if len(s.Phases) != 1 || s.Phases[0].Name != "init" {
+		t.Errorf("phases = %+v", s.Phases)
+	}
+
+	var out bytes.Buffer
+	Print(&out, s)
+	for _, want := range []string{"API calls    3 (1 failed)", "local cache: 2 hit(s), 1 miss(es)", "POST " + strings.TrimPrefix(srv.URL, "http://") + "/api/v1/sites/:id/devices"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("summary missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	for n, want := range map[int64]string{512: "512 B", 2048: "2.0 KB", 3 << 20: "3.0 MB"} {
+		if got := FormatBytes(n); got != want {
+			t.Errorf("FormatBytes(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestCaptureWithoutAccounting(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"id":1}`))
+	}))
+	defer srv.Close()
+
+	enabled.Store(false)
+	var got []Exchange
+	SetCapture(func(ex Exchange) { got = append(got, ex) })
+	defer SetCapture(nil)
+	if !Instrumented() || Enabled() {
+		t.Fatal("capture must instrument clients without enabling accounting")
+	}
+
+	hc := InstrumentClient(&http.Client{})
+	resp, err := hc.Get(srv.URL + "/api/v1/sites/12345678?limit=5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+
+	if string(body) != `{"id":1}` {
+		t.Errorf("caller read %q after capture", body)
+	}
+	if len(got) != 1 || got[0].Path != "/api/v1/sites/12345678" || got[0].Query != "limit=5" ||
+		got[0].Status != 200 || string(got[0].Body) != `{"id":1}` {
+		t.Errorf("captured %+v", got)
+	}
+}
@@ -0,0 +1,280 @@
+// Package schedule fires recurring wifimgr operations at a site-local time of
+// day. Jobs are defined under "schedule" in the main config with a wall-clock
+// time; a job whose command mentions {site} runs once per matching site, in
+// that site's timezone, so "02:00" means 2am local at every site of a
+// multi-region estate.
+//
+// Nothing runs in the background: "wifimgr schedule run", invoked from cron
+// at least as often as the catch-up window, fires every occurrence that has
+// come due since the last run. Fired occurrences are recorded under the state
+// directory so an occurrence never fires twice.
+package schedule
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/ravinald/wifimgr/internal/helpers"
+	"github.com/ravinald/wifimgr/internal/xdg"
+)
+
+// SitePlaceholder in a job command is replaced by the site name.
+const SitePlaceholder = "{site}"
+
+// DefaultWindow is how late an occurrence may still fire. A run that starts
+// more than this long after the scheduled time skips it rather than firing
+// stale work (e.g. after the host was down overnight).
+const DefaultWindow = time.Hour
+
+// Job is one schedule entry from the main config.
+type Job struct {
+	Name     string   `json:"name" mapstructure:"name"`
+	Command  string   `json:"command" mapstructure:"command"`             // wifimgr arguments, e.g. "refresh site {site}"
+	At       string   `json:"at" mapstructure:"at"`                       // "HH:MM" in the site's (or job's) timezone
+	Days     []string `json:"days,omitempty" mapstructure:"days"`         // mon..sun; empty means every day
+	Sites    []string `json:"sites,omitempty" mapstructure:"sites"`       // site name globs; empty means every site
+	Timezone string   `json:"timezone,omitempty" mapstructure:"timezone"` // zone for jobs without {site}, and sites with no known zone
+}
+
+// PerSite reports whether the job runs once per site.
+func (j Job) PerSite() bool { return strings.Contains(j.Command, SitePlaceholder) }
+
+// Site is a candidate site and its timezone from the API cache.
+type Site struct {
+	Name     string
+	Timezone string
+}
+
+// Occurrence is one job at one site (or the job alone when it does not
+// mention {site}), with its schedule resolved in the effective zone.
+type Occurrence struct {
+	Job      string    `json:"job"`
+	Site     string    `json:"site,omitempty"`
+	Zone     string    `json:"zone"`
+	At       string    `json:"at"`
+	Args     []string  `json:"args"`
+	Previous time.Time `json:"previous"` // latest scheduled time at or before now
+	Next     time.Time `json:"next"`     // earliest scheduled time after now
+}
+
+// Key identifies the occurrence in the run state.
+func (o Occurrence) Key() string {
+	if o.Site == "" {
+		return o.Job
+	}
+	return o.Job + "@" + o.Site
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// LoadJobs reads and validates the "schedule" list from viper.
+func LoadJobs() ([]Job, error) {
+	var jobs []Job
+	if err := viper.UnmarshalKey("schedule", &jobs); err != nil {
+		return nil, fmt.Errorf("invalid schedule config: %w", err)
+	}
+	seen := make(map[string]bool, len(jobs))
+	for i, j := range jobs {
+		if err := j.validate(); err != nil {
+			return nil, fmt.Errorf("schedule[%d]: %w", i, err)
+		}
+		if seen[j.Name] {
+			return nil, fmt.Errorf("schedule[%d]: job %q is defined more than once", i, j.Name)
+		}
+		seen[j.Name] = true
+	}
+	return jobs, nil
+}
+
+func (j Job) validate() error {
+	if strings.TrimSpace(j.Name) == "" {
+		return errors.New("name is required")
+	}
+	if len(strings.Fields(j.Command)) == 0 {
+		return fmt.Errorf("job %q: command is required", j.Name)
+	}
+	if _, _, err := j.clock(); err != nil {
+		return fmt.Errorf("job %q: %w", j.Name, err)
+	}
+	if _, err := j.weekdays(); err != nil {
+		return fmt.Errorf("job %q: %w", j.Name, err)
+	}
+	if j.Timezone != "" {
+		if _, err := time.LoadLocation(j.Timezone); err != nil {
+			return fmt.Errorf("job %q: unknown timezone %q", j.Name, j.Timezone)
+		}
+	}
+	for _, g := range j.Sites {
+		if _, err := path.Match(strings.ToLower(g), ""); err != nil {
+			return fmt.Errorf("job %q: invalid site pattern %q", j.Name, g)
+		}
+	}
+	return nil
+}
+
+func (j Job) clock() (hour, minute int, err error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(j.At))
+	if err != nil {
+		return 0, 0, fmt.Errorf("at must be HH:MM (24-hour), got %q", j.At)
+	}
+	return t.Hour(), t.Minute(), nil
+}
+
+// weekdays returns the days the job runs on; nil means every day.
+func (j Job) weekdays() (map[time.Weekday]bool, error) {
+	if len(j.Days) == 0 {
+		return nil, nil
+	}
+	days := make(map[time.Weekday]bool, len(j.Days))
+	for _, d := range j.Days {
+		key := strings.ToLower(strings.TrimSpace(d))
+		if len(key) > 3 {
+			key = key[:3] // "monday" as well as "mon"
+		}
+		wd, ok := weekdays[key]
+		if !ok {
+			return nil, fmt.Errorf("unknown day %q (use mon, tue, ... sun)", d)
+		}
+		days[wd] = true
+	}
+	return days, nil
+}
+
+func (j Job) matchesSite(name string) bool {
+	if len(j.Sites) == 0 {
+		return true
+	}
+	for _, g := range j.Sites {
+		if ok, _ := path.Match(strings.ToLower(g), strings.ToLower(name)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Expand resolves every job against the sites, as of now. Per-site jobs use
+// the site's timezone, else the job's, else the local zone; other jobs use
+// the job's zone, else the local zone. Jobs must already be validated.
+func Expand(jobs []Job, sites []Site, now time.Time) []Occurrence {
+	var occs []Occurrence
+	for _, j := range jobs {
+		if !j.PerSite() {
+			occs = append(occs, j.occurrence("", zone(j.Timezone), now))
+			continue
+		}
+		for _, s := range sites {
+			if !j.matchesSite(s.Name) {
+				continue
+			}
+			loc := zone(j.Timezone)
+			if s.Timezone != "" {
+				if siteLoc, err := time.LoadLocation(s.Timezone); err == nil {
+					loc = siteLoc
+				}
+			}
+			occs = append(occs, j.occurrence(s.Name, loc, now))
+		}
+	}
+	sort.SliceStable(occs, func(a, b int) bool { return occs[a].Next.Before(occs[b].Next) })
+	return occs
+}
+
+// zone loads name, falling back to the local zone when it is empty or unknown.
+func zone(name string) *time.Location {
+	if name == "" {
+		return time.Local
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+func (j Job) occurrence(site string, loc *time.Location, now time.Time) Occurrence {
+	hour, minute, _ := j.clock()
+	days, _ := j.weekdays()
+	args := strings.Fields(j.Command)
+	for i, a := range args {
+		args[i] = strings.ReplaceAll(a, SitePlaceholder, site)
+	}
+	o := Occurrence{Job: j.Name, Site: site, Zone: loc.String(), At: j.At, Args: args}
+
+	// Scan a week either side of today in the zone; time.Date normalizes a
+	// wall time skipped by a DST change to the equivalent instant.
+	local := now.In(loc)
+	for offset := -7; offset <= 8; offset++ {
+		day := time.Date(local.Year(), local.Month(), local.Day()+offset, hour, minute, 0, 0, loc)
+		if days != nil && !days[day.Weekday()] {
+			continue
+		}
+		if !day.After(now) {
+			o.Previous = day
+		} else if o.Next.IsZero() {
+			o.Next = day
+		}
+	}
+	return o
+}
+
+// State maps occurrence keys to the scheduled time last fired.
+type State map[string]time.Time
+
+// Due returns the occurrences whose latest scheduled time has not been fired
+// and is no older than window.
+func Due(occs []Occurrence, state State, now time.Time, window time.Duration) []Occurrence {
+	var due []Occurrence
+	for _, o := range occs {
+		if o.Previous.IsZero() || now.Sub(o.Previous) > window {
+			continue
+		}
+		if last, ok := state[o.Key()]; ok && !o.Previous.After(last) {
+			continue
+		}
+		due = append(due, o)
+	}
+	return due
+}
+
+// statePath is where fired occurrences are recorded; tests override it.
+var statePath = func() string { return filepath.Join(xdg.GetStateDir(), "schedule.json") }
+
+// LoadState reads the run state; a missing file is an empty state.
+func LoadState() (State, error) {
+	data, err := os.ReadFile(statePath()) // #nosec G304 -- fixed name under the state dir
+	if errors.Is(err, os.ErrNotExist) {
+		return State{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	state := State{}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("invalid schedule state %s: %w", statePath(), err)
+	}
+	return state, nil
+}
+
+// SaveState writes the run state.
+func SaveState(state State) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(statePath()), 0o700); err != nil {
+		return err
+	}
+	return helpers.WriteFileAtomic(statePath(), data, 0o600)
+}
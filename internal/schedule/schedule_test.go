@@ -0,0 +1,139 @@
+package schedule
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func TestExpandUsesSiteLocalTime(t *testing.T) {
+	jobs := []Job{{Name: "nightly", Command: "refresh site {site}", At: "02:00"}}
+	sites := []Site{
+		{Name: "US-SFO-LAB", Timezone: "America/Los_Angeles"},
+		{Name: "EU-LON-DC", Timezone: "Europe/London"},
+	}
+	// 2026-01-15 12:00 UTC: 04:00 in San Francisco, 12:00 in London.
+	now := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	occs := Expand(jobs, sites, now)
+	if len(occs) != 2 {
+		t.Fatalf("got %d occurrences, want 2", len(occs))
+	}
+	want := map[string]struct{ prev, next time.Time }{
+		"US-SFO-LAB": {time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC), time.Date(2026, 1, 16, 10, 0, 0, 0, time.UTC)},
+		"EU-LON-DC":  {time.Date(2026, 1, 15, 2, 0, 0, 0, time.UTC), time.Date(2026, 1, 16, 2, 0, 0, 0, time.UTC)},
+	}
+	for _, o := range occs {
+		w := want[o.Site]
+		if !o.Previous.Equal(w.prev) || !o.Next.Equal(w.next) {
+			t.Errorf("%s: previous %s next %s, want %s and %s", o.Site, o.Previous.UTC(), o.Next.UTC(), w.prev, w.next)
+		}
+		if o.Args[2] != o.Site {
+			t.Errorf("%s: args = %v", o.Site, o.Args)
+		}
+	}
+	if occs[0].Site != "EU-LON-DC" {
+		t.Errorf("expected London first (next run sooner), got %s", occs[0].Site)
+	}
+}
+
+func TestExpandFiltersAndFallsBack(t *testing.T) {
+	jobs := []Job{
+		{Name: "us", Command: "refresh site {site}", At: "01:30", Sites: []string{"us-*"}, Timezone: "America/New_York"},
+		{Name: "weekly", Command: "report stale", At: "06:00", Days: []string{"Monday"}, Timezone: "UTC"},
+	}
+	sites := []Site{{Name: "US-NYC-OFFICE"}, {Name: "EU-LON-DC", Timezone: "Europe/London"}}
+	now := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC) // a Thursday
+
+	occs := Expand(jobs, sites, now)
+	if len(occs) != 2 {
+		t.Fatalf("got %d occurrences, want 2: %+v", len(occs), occs)
+	}
+	for _, o := range occs {
+		switch o.Job {
+		case "us":
+			if o.Site != "US-NYC-OFFICE" || o.Zone != "America/New_York" {
+				t.Errorf("us occurrence = %+v, want NYC in the job zone", o)
+			}
+		case "weekly":
+			if o.Site != "" || o.Previous.Weekday() != time.Monday || o.Next.Weekday() != time.Monday {
+				t.Errorf("weekly occurrence = %+v", o)
+			}
+		}
+	}
+}
+
+func TestExpandAcrossDST(t *testing.T) {
+	jobs := []Job{{Name: "n", Command: "refresh site {site}", At: "02:30"}}
+	sites := []Site{{Name: "US-SFO-LAB", Timezone: "America/Los_Angeles"}}
+	// 2026-03-08 is the spring-forward day; 02:30 does not exist locally.
+	now := time.Date(2026, 3, 8, 12, 0, 0, 0, time.UTC)
+	o := Expand(jobs, sites, now)[0]
+	if got := o.Previous.In(zone("America/Los_Angeles")); got.Day() != 8 {
+		t.Errorf("previous = %s, want the skipped run still scheduled on the 8th", got)
+	}
+	if got := o.Next.In(zone("America/Los_Angeles")); got.Day() != 9 || got.Hour() != 2 || got.Minute() != 30 {
+		t.Errorf("next = %s, want 02:30 on the 9th", got)
+	}
+}
+
+func TestDue(t *testing.T) {
+	now := time.Date(2026, 1, 15, 10, 20, 0, 0, time.UTC)
+	prev := time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC)
+	occs := []Occurrence{
+		{Job: "a", Site: "s1", Previous: prev},
+		{Job: "a", Site: "s2", Previous: prev},
+		{Job: "b", Previous: prev.Add(-2 * time.Hour)}, // outside the window
+	}
+	state := State{"a@s2": prev}
+
+	due := Due(occs, state, now, DefaultWindow)
+	if len(due) != 1 || due[0].Key() != "a@s1" {
+		t.Errorf("due = %+v, want only a@s1", due)
+	}
+}
+
+func TestLoadJobsValidates(t *testing.T) {
+	tests := []struct {
+		name string
+		job  map[string]any
+	}{
+		{"missing command", map[string]any{"name": "x", "at": "02:00"}},
+		{"bad time", map[string]any{"name": "x", "command": "refresh all", "at": "2am"}},
+		{"bad day", map[string]any{"name": "x", "command": "refresh all", "at": "02:00", "days": []string{"someday"}}},
+		{"bad zone", map[string]any{"name": "x", "command": "refresh all", "at": "02:00", "timezone": "Mars/Olympus"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			t.Cleanup(viper.Reset)
+			viper.Set("schedule", []map[string]any{tt.job})
+			if _, err := LoadJobs(); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestStateRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	orig := statePath
+	statePath = func() string { return filepath.Join(dir, "sub", "schedule.json") }
+	t.Cleanup(func() { statePath = orig })
+
+	state, err := LoadState()
+	if err != nil || len(state) != 0 {
+		t.Fatalf("missing state = %v, %v; want empty", state, err)
+	}
+	at := time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC)
+	state["a@s1"] = at
+	if err := SaveState(state); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadState()
+	if err != nil || !got["a@s1"].Equal(at) {
+		t.Errorf("reloaded state = %v, %v", got, err)
+	}
+}
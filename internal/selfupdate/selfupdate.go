@@ -0,0 +1,488 @@
+// Package selfupdate finds the newest wifimgr release on a channel, downloads
+// the archive for the running platform, verifies it against the release's
+// signed checksums.txt, and swaps the running binary in place.
+//
+// Releases are published by goreleaser: one archive per platform
+// (wifimgr_<version>_<os>_<arch>.tar.gz, .zip on Windows), checksums.txt with
+// SHA-256 sums, and a keyless cosign signature over checksums.txt
+// (checksums.txt.sig plus the signing certificate checksums.txt.pem).
+package selfupdate
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultFeed is the GitHub releases API for wifimgr. A mirror serving the
+// same JSON (and the assets it links to) can be used instead.
+const DefaultFeed = "https://api.github.com/repos/ravinald/wifimgr/releases"
+
+// Release channels.
+const (
+	ChannelStable = "stable"
+	ChannelBeta   = "beta"
+)
+
+// Signing identity of release builds: the release workflow, run from a tag,
+// authenticated by GitHub Actions OIDC.
+const (
+	signerIdentity = `^https://github\.com/ravinald/wifimgr/\.github/workflows/release\.yml@refs/tags/v`
+	signerIssuer   = "https://token.actions.githubusercontent.com"
+)
+
+const (
+	checksumsAsset  = "checksums.txt"
+	downloadTimeout = 2 * time.Minute
+	downloadLimit   = 200 << 20 // refuse archives over 200 MiB
+)
+
+// ErrCosignMissing is returned by VerifySignature when cosign is not on PATH.
+var ErrCosignMissing = errors.New("cosign not found on PATH")
+
+// Release is one entry of the release feed.
+type Release struct {
+	TagName    string  `json:"tag_name"`
+	Prerelease bool    `json:"prerelease"`
+	Draft      bool    `json:"draft"`
+	Assets     []Asset `json:"assets"`
+}
+
+// Asset is a downloadable file attached to a release.
+type Asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+	Size int64  `json:"size"`
+}
+
+// Asset returns the named asset, or nil.
+func (r *Release) Asset(name string) *Asset {
+	for i := range r.Assets {
+		if r.Assets[i].Name == name {
+			return &r.Assets[i]
+		}
+	}
+	return nil
+}
+
+// Version is the release tag without the leading "v".
+func (r *Release) Version() string {
+	return strings.TrimPrefix(r.TagName, "v")
+}
+
+// FetchReleases reads the release feed.
+func FetchReleases(ctx context.Context, feed string) ([]Release, error) {
+	data, err := download(ctx, feed)
+	if err != nil {
+		return nil, fmt.Errorf("release feed: %w", err)
+	}
+	var releases []Release
+	if err := json.Unmarshal(data, &releases); err != nil {
+		return nil, fmt.Errorf("release feed %s: %w", feed, err)
+	}
+	return releases, nil
+}
+
+// Latest returns the newest release on a channel: stable considers only
+// full releases, beta also considers pre-releases. Drafts are ignored.
+func Latest(releases []Release, channel string) (*Release, error) {
+	if channel != ChannelStable && channel != ChannelBeta {
+		return nil, fmt.Errorf("unknown channel %q (use %s or %s)", channel, ChannelStable, ChannelBeta)
+	}
+	var best *Release
+	for i := range releases {
+		r := &releases[i]
+		if r.Draft || (r.Prerelease && channel == ChannelStable) {
+			continue
+		}
+		if _, ok := parseVersion(r.Version()); !ok {
+			continue
+		}
+		if best == nil || CompareVersions(r.Version(), best.Version()) > 0 {
+			best = r
+		}
+	}
+	if best == nil {
+		return nil, fmt.Errorf("no %s release found", channel)
+	}
+	return best, nil
+}
+
+// ArchiveName is the goreleaser archive name for a version and platform.
+func ArchiveName(version, goos, goarch string) string {
+	ext := ".tar.gz"
+	if goos == "windows" {
+		ext = ".zip"
+	}
+	return fmt.Sprintf("wifimgr_%s_%s_%s%s", strings.TrimPrefix(version, "v"), goos, goarch, ext)
+}
+
+// Download is a release fetched and verified for the running platform.
+type Download struct {
+	Release *Release
+	Archive string
+	Binary  []byte
+	Signed  bool // false when signature checking was skipped
+}
+
+// Fetch downloads the platform archive and checksums.txt for rel, checks the
+// archive's SHA-256, and extracts the wifimgr binary. Unless skipSignature is
+// set, checksums.txt must carry a valid release signature first.
+func Fetch(ctx context.Context, rel *Release, skipSignature bool) (*Download, error) {
+	name := ArchiveName(rel.Version(), runtime.GOOS, runtime.GOARCH)
+	archive := rel.Asset(name)
+	if archive == nil {
+		return nil, fmt.Errorf("release %s has no archive for %s/%s (%s)", rel.TagName, runtime.GOOS, runtime.GOARCH, name)
+	}
+	sums := rel.Asset(checksumsAsset)
+	if sums == nil {
+		return nil, fmt.Errorf("release %s has no %s", rel.TagName, checksumsAsset)
+	}
+
+	sumsData, err := download(ctx, sums.URL)
+	if err != nil {
+		return nil, err
+	}
+	if !skipSignature {
+		if err := verifyReleaseSignature(ctx, rel, sumsData); err != nil {
+			return nil, err
+		}
+	}
+
+	want, ok := ParseChecksums(sumsData)[name]
+	if !ok {
+		return nil, fmt.Errorf("%s does not list %s", checksumsAsset, name)
+	}
+	data, err := download(ctx, archive.URL)
+	if err != nil {
+		return nil, err
+	}
+	if err := VerifyChecksum(data, want); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	bin, err := ExtractBinary(data, name)
+	if err != nil {
+		return nil, err
+	}
+	return &Download{Release: rel, Archive: name, Binary: bin, Signed: !skipSignature}, nil
+}
+
+// verifyReleaseSignature downloads the signature and certificate for
+// checksums.txt and verifies them with cosign.
+func verifyReleaseSignature(ctx context.Context, rel *Release, sums []byte) error {
+	sig := rel.Asset(checksumsAsset + ".sig")
+	cert := rel.Asset(checksumsAsset + ".pem")
+	if sig == nil || cert == nil {
+		return fmt.Errorf("release %s is not signed (no %s.sig/.pem)", rel.TagName, checksumsAsset)
+	}
+
+	dir, err := os.MkdirTemp("", "wifimgr-update-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	files := map[string][]byte{checksumsAsset: sums}
+	for _, a := range []*Asset{sig, cert} {
+		data, err := download(ctx, a.URL)
+		if err != nil {
+			return err
+		}
+		files[a.Name] = data
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0600); err != nil {
+			return err
+		}
+	}
+	return VerifySignature(ctx,
+		filepath.Join(dir, checksumsAsset),
+		filepath.Join(dir, sig.Name),
+		filepath.Join(dir, cert.Name))
+}
+
+// VerifySignature checks a keyless cosign signature over blob, requiring the
+// certificate to name wifimgr's release workflow. It runs cosign, which also
+// validates the certificate chain and the transparency log entry.
+func VerifySignature(ctx context.Context, blob, sig, cert string) error {
+	cosign, err := exec.LookPath("cosign")
+	if err != nil {
+		return ErrCosignMissing
+	}
+	// #nosec G204 -- fixed arguments; paths are our own temp files
+	out, err := exec.CommandContext(ctx, cosign, "verify-blob",
+		"--certificate", cert,
+		"--signature", sig,
+		"--certificate-identity-regexp", signerIdentity,
+		"--certificate-oidc-issuer", signerIssuer,
+		blob).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("signature verification failed: %s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// ParseChecksums reads a sha256sum-format file into name -> hex digest.
+func ParseChecksums(data []byte) map[string]string {
+	sums := make(map[string]string)
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 || len(fields[0]) != sha256.Size*2 {
+			continue
+		}
+		if _, err := hex.DecodeString(fields[0]); err == nil {
+			sums[strings.TrimPrefix(fields[1], "*")] = strings.ToLower(fields[0])
+		}
+	}
+	return sums
+}
+
+// VerifyChecksum compares data's SHA-256 against a hex digest.
+func VerifyChecksum(data []byte, want string) error {
+	sum := sha256.Sum256(data)
+	if got := hex.EncodeToString(sum[:]); got != strings.ToLower(want) {
+		return fmt.Errorf("checksum mismatch: got %s, want %s", got, want)
+	}
+	return nil
+}
+
+// ExtractBinary returns the wifimgr executable from a release archive; the
+// archive format follows its name (.zip or .tar.gz).
+func ExtractBinary(archive []byte, archiveName string) ([]byte, error) {
+	if strings.HasSuffix(archiveName, ".zip") {
+		return extractZip(archive)
+	}
+	return extractTarGz(archive)
+}
+
+func isBinaryName(name string) bool {
+	base := filepath.Base(filepath.ToSlash(name))
+	base = strings.TrimPrefix(base, "./")
+	return base == "wifimgr" || base == "wifimgr.exe"
+}
+
+func extractTarGz(archive []byte) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, fmt.Errorf("open archive: %w", err)
+	}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read archive: %w", err)
+		}
+		if hdr.Typeflag == tar.TypeReg && isBinaryName(hdr.Name) {
+			return io.ReadAll(io.LimitReader(tr, downloadLimit))
+		}
+	}
+	return nil, errors.New("archive does not contain a wifimgr binary")
+}
+
+func extractZip(archive []byte) ([]byte, error) {
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return nil, fmt.Errorf("open archive: %w", err)
+	}
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() || !isBinaryName(f.Name) {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("read archive: %w", err)
+		}
+		defer func() { _ = rc.Close() }()
+		return io.ReadAll(io.LimitReader(rc, downloadLimit))
+	}
+	return nil, errors.New("archive does not contain a wifimgr binary")
+}
+
+// Replace swaps the executable at exe for bin. The new binary is written next
+// to exe and renamed over it, so exe is never partially written. Windows will
+// not overwrite a running executable but does allow renaming it, so there the
+// old binary is first moved to exe+".old" (removed on the next update).
+func Replace(exe string, bin []byte) error {
+	info, err := os.Stat(exe)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(exe)
+	old := exe + ".old"
+	_ = os.Remove(old) // leftover from a previous Windows update
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(exe)+".new-*")
+	if err != nil {
+		return fmt.Errorf("cannot write to %s: %w", dir, err)
+	}
+	tmpPath := tmp.Name()
+	cleanup := func() { _ = os.Remove(tmpPath) } // #nosec G703 -- best-effort cleanup
+	if _, err := tmp.Write(bin); err != nil {
+		_ = tmp.Close()
+		cleanup()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		cleanup()
+		return err
+	}
+	if err := os.Chmod(tmpPath, info.Mode().Perm()|0o111); err != nil {
+		cleanup()
+		return err
+	}
+
+	if runtime.GOOS == "windows" {
+		if err := os.Rename(exe, old); err != nil {
+			cleanup()
+			return fmt.Errorf("move running binary aside: %w", err)
+		}
+	}
+	if err := os.Rename(tmpPath, exe); err != nil {
+		if runtime.GOOS == "windows" {
+			_ = os.Rename(old, exe)
+		}
+		cleanup()
+		return fmt.Errorf("install new binary: %w", err)
+	}
+	return nil
+}
+
+// CompareVersions orders two semantic versions ("1.4.0", "v1.5.0-beta.2"),
+// returning -1, 0, or 1. A pre-release sorts before its release. Versions
+// that do not parse (such as "dev") sort before every release.
+func CompareVersions(a, b string) int {
+	va, okA := parseVersion(a)
+	vb, okB := parseVersion(b)
+	switch {
+	case !okA && !okB:
+		return 0
+	case !okA:
+		return -1
+	case !okB:
+		return 1
+	}
+	for i := 0; i < 3; i++ {
+		if va.core[i] != vb.core[i] {
+			return cmpInt(va.core[i], vb.core[i])
+		}
+	}
+	switch {
+	case va.pre == "" && vb.pre == "":
+		return 0
+	case va.pre == "":
+		return 1
+	case vb.pre == "":
+		return -1
+	}
+	return comparePrerelease(va.pre, vb.pre)
+}
+
+type semver struct {
+	core [3]int
+	pre  string
+}
+
+func parseVersion(v string) (semver, bool) {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexByte(v, '+'); i >= 0 {
+		v = v[:i]
+	}
+	var s semver
+	if i := strings.IndexByte(v, '-'); i >= 0 {
+		v, s.pre = v[:i], v[i+1:]
+	}
+	parts := strings.Split(v, ".")
+	if len(parts) != 3 {
+		return s, false
+	}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return s, false
+		}
+		s.core[i] = n
+	}
+	return s, true
+}
+
+// comparePrerelease compares dot-separated identifiers per semver: numeric
+// identifiers numerically and below alphanumeric ones, shorter lists first.
+func comparePrerelease(a, b string) int {
+	pa, pb := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(pa) && i < len(pb); i++ {
+		na, errA := strconv.Atoi(pa[i])
+		nb, errB := strconv.Atoi(pb[i])
+		switch {
+		case errA == nil && errB == nil:
+			if na != nb {
+				return cmpInt(na, nb)
+			}
+		case errA == nil:
+			return -1
+		case errB == nil:
+			return 1
+		default:
+			if c := strings.Compare(pa[i], pb[i]); c != 0 {
+				return c
+			}
+		}
+	}
+	return cmpInt(len(pa), len(pb))
+}
+
+func cmpInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// download GETs url with a bounded size and timeout.
+func download(ctx context.Context, url string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, downloadTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "wifimgr-selfupdate")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("download %s: %w", url, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download %s: HTTP %d", url, resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, downloadLimit+1))
+	if err != nil {
+		return nil, fmt.Errorf("download %s: %w", url, err)
+	}
+	if len(data) > downloadLimit {
+		return nil, fmt.Errorf("download %s: larger than %d bytes", url, downloadLimit)
+	}
+	return data, nil
+}
@@ -0,0 +1,192 @@
+package selfupdate
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.2.0", "v1.2.0", 0},
+		{"1.10.0", "1.9.3", 1},
+		{"1.2.0-beta.1", "1.2.0", -1},
+		{"1.2.0-beta.2", "1.2.0-beta.10", -1},
+		{"1.2.0-beta", "1.2.0-alpha.3", 1},
+		{"1.2.0-rc.1", "1.2.0-rc.1.1", -1},
+		{"dev", "0.0.1", -1},
+		{"1.0.0+build.5", "1.0.0", 0},
+	}
+	for _, tc := range tests {
+		if got := CompareVersions(tc.a, tc.b); got != tc.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
+
+func TestLatest(t *testing.T) {
+	releases := []Release{
+		{TagName: "v1.3.0"},
+		{TagName: "v1.4.0-beta.1", Prerelease: true},
+		{TagName: "v1.5.0", Draft: true},
+		{TagName: "nightly"},
+		{TagName: "v1.2.9"},
+	}
+	stable, err := Latest(releases, ChannelStable)
+	if err != nil || stable.TagName != "v1.3.0" {
+		t.Errorf("stable = %v, %v", stable, err)
+	}
+	beta, err := Latest(releases, ChannelBeta)
+	if err != nil || beta.TagName != "v1.4.0-beta.1" {
+		t.Errorf("beta = %v, %v", beta, err)
+	}
+	if _, err := Latest(releases, "nightly"); err == nil {
+		t.Error("unknown channel should fail")
+	}
+}
+
+func TestChecksums(t *testing.T) {
+	data := []byte("binary")
+	sum := sha256.Sum256(data)
+	digest := hex.EncodeToString(sum[:])
+	sums := ParseChecksums([]byte(digest + "  wifimgr_1.3.0_linux_amd64.tar.gz\nbogus line\n"))
+	if sums["wifimgr_1.3.0_linux_amd64.tar.gz"] != digest || len(sums) != 1 {
+		t.Fatalf("ParseChecksums = %v", sums)
+	}
+	if err := VerifyChecksum(data, strings.ToUpper(digest)); err != nil {
+		t.Errorf("VerifyChecksum: %v", err)
+	}
+	if err := VerifyChecksum([]byte("tampered"), digest); err == nil {
+		t.Error("tampered data should fail")
+	}
+}
+
+func tarGz(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, body := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0755, Size: int64(len(body)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestExtractBinary(t *testing.T) {
+	archive := tarGz(t, map[string]string{"README.md": "docs", "wifimgr": "ELF"})
+	bin, err := ExtractBinary(archive, "wifimgr_1.3.0_linux_amd64.tar.gz")
+	if err != nil || string(bin) != "ELF" {
+		t.Errorf("tar.gz: %q, %v", bin, err)
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, _ := zw.Create("wifimgr.exe")
+	_, _ = w.Write([]byte("PE"))
+	_ = zw.Close()
+	bin, err = ExtractBinary(buf.Bytes(), "wifimgr_1.3.0_windows_amd64.zip")
+	if err != nil || string(bin) != "PE" {
+		t.Errorf("zip: %q, %v", bin, err)
+	}
+
+	if _, err := ExtractBinary(tarGz(t, map[string]string{"LICENSE": "x"}), "a.tar.gz"); err == nil {
+		t.Error("archive without a binary should fail")
+	}
+}
+
+func TestReplace(t *testing.T) {
+	exe := filepath.Join(t.TempDir(), "wifimgr")
+	if err := os.WriteFile(exe, []byte("old"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := Replace(exe, []byte("new")); err != nil {
+		t.Fatalf("Replace: %v", err)
+	}
+	got, _ := os.ReadFile(exe) // #nosec G304 -- test path under TempDir
+	if string(got) != "new" {
+		t.Errorf("binary = %q", got)
+	}
+	info, _ := os.Stat(exe)
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0o100 == 0 {
+		t.Errorf("new binary is not executable: %v", info.Mode())
+	}
+	entries, _ := os.ReadDir(filepath.Dir(exe))
+	if len(entries) != 1 && runtime.GOOS != "windows" {
+		t.Errorf("temp files left behind: %v", entries)
+	}
+}
+
+func TestFetch(t *testing.T) {
+	name := ArchiveName("1.3.0", runtime.GOOS, runtime.GOARCH)
+	archive := tarGz(t, map[string]string{"wifimgr": "new-binary"})
+	if strings.HasSuffix(name, ".zip") {
+		var buf bytes.Buffer
+		zw := zip.NewWriter(&buf)
+		w, _ := zw.Create("wifimgr.exe")
+		_, _ = w.Write([]byte("new-binary"))
+		_ = zw.Close()
+		archive = buf.Bytes()
+	}
+	sum := sha256.Sum256(archive)
+	checksums := fmt.Sprintf("%s  %s\n", hex.EncodeToString(sum[:]), name)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/" + name:
+			_, _ = w.Write(archive)
+		case "/checksums.txt":
+			_, _ = w.Write([]byte(checksums))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	rel := &Release{TagName: "v1.3.0", Assets: []Asset{
+		{Name: name, URL: srv.URL + "/" + name},
+		{Name: "checksums.txt", URL: srv.URL + "/checksums.txt"},
+	}}
+
+	if _, err := Fetch(context.Background(), rel, false); err == nil || !strings.Contains(err.Error(), "not signed") {
+		t.Errorf("unsigned release should be refused, got %v", err)
+	}
+
+	dl, err := Fetch(context.Background(), rel, true)
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if string(dl.Binary) != "new-binary" || dl.Signed {
+		t.Errorf("download = %q signed=%v", dl.Binary, dl.Signed)
+	}
+
+	archive = append(archive, 0)
+	if _, err := Fetch(context.Background(), rel, true); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("tampered archive should fail, got %v", err)
+	}
+}
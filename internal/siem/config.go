@@ -0,0 +1,109 @@
+// Package siem ships wireless intrusion events — rogue and honeypot APs,
+// spoofing, and frame floods — to a SIEM as CEF or LEEF records over syslog
+// (UDP, TCP, or TLS), so a SOC sees them without a vendor-specific connector.
+package siem
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Record formats.
+const (
+	FormatCEF  = "cef"
+	FormatLEEF = "leef"
+)
+
+// Syslog transports.
+const (
+	TransportUDP = "udp"
+	TransportTCP = "tcp"
+	TransportTLS = "tls"
+)
+
+// DefaultTimeout bounds connecting and each write.
+const DefaultTimeout = 10 * time.Second
+
+// facilities are the syslog facility codes accepted in siem.facility.
+var facilities = map[string]int{
+	"user": 1, "auth": 4, "authpriv": 10,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19,
+	"local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// Config holds the siem section of the main config.
+type Config struct {
+	Address            string // host:port of the syslog receiver
+	Transport          string // TransportUDP, TransportTCP, or TransportTLS
+	Format             string // FormatCEF or FormatLEEF
+	Facility           string
+	CAFile             string // PEM bundle to verify the receiver; empty uses the system roots
+	InsecureSkipVerify bool
+	Timeout            time.Duration
+}
+
+// LoadConfig reads siem.* from Viper. It returns (nil, nil) when no address
+// is set.
+func LoadConfig() (*Config, error) {
+	cfg := &Config{
+		Address:            strings.TrimSpace(viper.GetString("siem.address")),
+		Transport:          strings.ToLower(viper.GetString("siem.transport")),
+		Format:             strings.ToLower(viper.GetString("siem.format")),
+		Facility:           strings.ToLower(viper.GetString("siem.facility")),
+		CAFile:             viper.GetString("siem.ca_file"),
+		InsecureSkipVerify: viper.GetBool("siem.insecure_skip_verify"),
+		Timeout:            viper.GetDuration("siem.timeout"),
+	}
+	if cfg.Address == "" {
+		return nil, nil
+	}
+	cfg.applyDefaults()
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (c *Config) applyDefaults() {
+	if c.Transport == "" {
+		c.Transport = TransportTLS
+	}
+	if c.Format == "" {
+		c.Format = FormatCEF
+	}
+	if c.Facility == "" {
+		c.Facility = "local4"
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = DefaultTimeout
+	}
+}
+
+// Validate checks the section after defaults are applied.
+func (c *Config) Validate() error {
+	if _, _, err := net.SplitHostPort(c.Address); err != nil {
+		return fmt.Errorf("siem.address must be host:port: %w", err)
+	}
+	switch c.Transport {
+	case TransportUDP, TransportTCP, TransportTLS:
+	default:
+		return fmt.Errorf("siem.transport must be udp, tcp, or tls, got %q", c.Transport)
+	}
+	switch c.Format {
+	case FormatCEF, FormatLEEF:
+	default:
+		return fmt.Errorf("siem.format must be cef or leef, got %q", c.Format)
+	}
+	if _, ok := facilities[c.Facility]; !ok {
+		return fmt.Errorf("siem.facility %q is not one of user, auth, authpriv, local0-local7", c.Facility)
+	}
+	if c.Transport != TransportTLS && (c.CAFile != "" || c.InsecureSkipVerify) {
+		return errors.New("siem.ca_file and siem.insecure_skip_verify only apply to the tls transport")
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package siem
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ravinald/wifimgr/internal/common"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+// Record is one security event with the context a SIEM needs to place it.
+type Record struct {
+	Event  *vendors.SecurityEvent
+	Site   string // site name
+	API    string // API label
+	Vendor string
+}
+
+// eventNames are the CEF and LEEF event names by kind.
+var eventNames = map[string]string{
+	vendors.SecurityEventRogue:     "Rogue AP on the wired network",
+	vendors.SecurityEventHoneypot:  "Honeypot AP broadcasting a managed SSID",
+	vendors.SecurityEventSpoof:     "AP spoofing a managed BSSID",
+	vendors.SecurityEventAuthFlood: "Wireless frame flood",
+	vendors.SecurityEventWIDS:      "Wireless intrusion alarm",
+}
+
+// eventName names an event for the record header.
+func eventName(ev *vendors.SecurityEvent) string {
+	if name, ok := eventNames[ev.Kind]; ok {
+		return name
+	}
+	return "Wireless security event"
+}
+
+// Format renders r in the given format ("cef" or "leef"). product is the
+// wifimgr version reported in the header.
+func Format(format string, r Record, product string) string {
+	if format == FormatLEEF {
+		return FormatLEEFRecord(r, product)
+	}
+	return FormatCEFRecord(r, product)
+}
+
+// FormatCEFRecord renders r as an ArcSight CEF:0 record. The signature ID is
+// the event kind and the vendor's own type rides in cs4. The client MAC is
+// pseudonymized when client anonymization is on.
+func FormatCEFRecord(r Record, product string) string {
+	ev := r.Event
+	var ext []string
+	add := func(key, value string) {
+		if value != "" {
+			ext = append(ext, key+"="+cefValue(value))
+		}
+	}
+	if !ev.Timestamp.IsZero() {
+		add("rt", strconv.FormatInt(ev.Timestamp.UnixMilli(), 10))
+	}
+	add("cat", ev.Kind)
+	add("dvcmac", colonMAC(ev.APMAC))
+	add("smac", colonMAC(ev.BSSID))
+	add("dmac", colonMAC(common.AnonymizeMAC(ev.ClientMAC)))
+	if ev.SSID != "" {
+		add("cs1Label", "ssid")
+		add("cs1", ev.SSID)
+	}
+	if r.Site != "" {
+		add("cs2Label", "site")
+		add("cs2", r.Site)
+	}
+	add("cs3Label", "api")
+	add("cs3", r.API)
+	add("cs4Label", "vendorType")
+	add("cs4", r.Vendor+":"+ev.Type)
+	if ev.Channel != 0 {
+		add("cn1Label", "channel")
+		add("cn1", strconv.Itoa(ev.Channel))
+	}
+	if ev.RSSI != 0 {
+		add("cn2Label", "rssi")
+		add("cn2", strconv.Itoa(ev.RSSI))
+	}
+	add("msg", ev.Text)
+
+	return fmt.Sprintf("CEF:0|wifimgr|wifimgr|%s|%s|%s|%d|%s",
+		cefHeader(product), cefHeader(ev.Kind), cefHeader(eventName(ev)), clampSeverity(ev.Severity), strings.Join(ext, " "))
+}
+
+// FormatLEEFRecord renders r as an IBM QRadar LEEF:1.0 record with
+// tab-separated attributes.
+func FormatLEEFRecord(r Record, product string) string {
+	ev := r.Event
+	var attrs []string
+	add := func(key, value string) {
+		if value != "" {
+			attrs = append(attrs, key+"="+leefValue(value))
+		}
+	}
+	if !ev.Timestamp.IsZero() {
+		add("devTime", ev.Timestamp.UTC().Format("Jan 02 2006 15:04:05.000 MST"))
+		add("devTimeFormat", "MMM dd yyyy HH:mm:ss.SSS z")
+	}
+	add("cat", ev.Kind)
+	add("sev", strconv.Itoa(clampSeverity(ev.Severity)))
+	add("srcMAC", colonMAC(ev.BSSID))
+	add("dstMAC", colonMAC(common.AnonymizeMAC(ev.ClientMAC)))
+	add("apMAC", colonMAC(ev.APMAC))
+	add("ssid", ev.SSID)
+	add("site", r.Site)
+	add("api", r.API)
+	add("vendorType", r.Vendor+":"+ev.Type)
+	if ev.Channel != 0 {
+		add("channel", strconv.Itoa(ev.Channel))
+	}
+	if ev.RSSI != 0 {
+		add("rssi", strconv.Itoa(ev.RSSI))
+	}
+	add("msg", ev.Text)
+
+	return fmt.Sprintf("LEEF:1.0|wifimgr|wifimgr|%s|%s|%s",
+		leefHeader(product), leefHeader(ev.Kind), strings.Join(attrs, "\t"))
+}
+
+// clampSeverity keeps a severity within CEF's 0-10.
+func clampSeverity(s int) int {
+	return min(max(s, 0), 10)
+}
+
+// colonMAC renders a normalized MAC as aa:bb:cc:dd:ee:ff, the form SIEM
+// MAC fields parse.
+func colonMAC(mac string) string {
+	if len(mac) != 12 {
+		return mac
+	}
+	parts := make([]string, 6)
+	for i := range parts {
+		parts[i] = mac[i*2 : i*2+2]
+	}
+	return strings.Join(parts, ":")
+}
+
+// cefHeader escapes a CEF header field: backslash and pipe.
+func cefHeader(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return oneLine(s)
+}
+
+// cefValue escapes a CEF extension value: backslash, equals, and newlines.
+func cefValue(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, "=", `\=`)
+	s = strings.ReplaceAll(s, "\r\n", `\n`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return strings.ReplaceAll(s, "\r", `\r`)
+}
+
+// leefHeader escapes a LEEF header field: pipe.
+func leefHeader(s string) string {
+	return oneLine(strings.ReplaceAll(s, "|", `\|`))
+}
+
+// leefValue keeps a LEEF attribute on one line and off the tab delimiter.
+func leefValue(s string) string {
+	return oneLine(strings.ReplaceAll(s, "\t", " "))
+}
+
+// oneLine replaces line breaks, which would split a syslog message.
+func oneLine(s string) string {
+	return strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(s)
+}
@@ -0,0 +1,38 @@
+package siem
+
+import (
+	"context"
+	"errors"
+
+	"github.com/spf13/viper"
+
+	"github.com/ravinald/wifimgr/internal/integrations"
+)
+
+func init() {
+	integrations.Register(integrations.Integration{
+		Name:         "siem",
+		ConfigKey:    "siem",
+		EndpointKeys: "siem.address, siem.transport, siem.ca_file",
+		Configured:   func() bool { return viper.GetString("siem.address") != "" },
+		Check:        check,
+	})
+}
+
+// check connects to the receiver and hangs up without sending a record.
+// Over UDP nothing is exchanged, so only the address is resolved.
+func check(ctx context.Context) (string, error) {
+	cfg, err := LoadConfig()
+	if err != nil {
+		return "", err
+	}
+	if cfg == nil {
+		return "", errors.New("siem.address is not set")
+	}
+	s, err := Dial(ctx, cfg)
+	if err != nil {
+		return "", err
+	}
+	_ = s.Close()
+	return cfg.Describe(), nil
+}
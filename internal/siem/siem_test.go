@@ -0,0 +1,169 @@
+package siem
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+func testRecord() Record {
+	return Record{
+		Event: &vendors.SecurityEvent{
+			Timestamp: time.Date(2024, 6, 1, 12, 0, 0, 500e6, time.UTC),
+			Kind:      vendors.SecurityEventHoneypot,
+			Type:      "honeypot",
+			Severity:  8,
+			APMAC:     "5c5b35000001",
+			BSSID:     "aabbcc000001",
+			SSID:      "Corp=WiFi",
+			Channel:   36,
+			RSSI:      -61,
+			Text:      "seen by\n3 APs",
+		},
+		Site:   "US-LAB-01",
+		API:    "mist-prod",
+		Vendor: "mist",
+	}
+}
+
+func TestFormatCEFRecord(t *testing.T) {
+	got := FormatCEFRecord(testRecord(), "1.2|3")
+	want := `CEF:0|wifimgr|wifimgr|1.2\|3|honeypot|Honeypot AP broadcasting a managed SSID|8|` +
+		`rt=1717243200500 cat=honeypot dvcmac=5c:5b:35:00:00:01 smac=aa:bb:cc:00:00:01 ` +
+		`cs1Label=ssid cs1=Corp\=WiFi cs2Label=site cs2=US-LAB-01 cs3Label=api cs3=mist-prod ` +
+		`cs4Label=vendorType cs4=mist:honeypot cn1Label=channel cn1=36 cn2Label=rssi cn2=-61 msg=seen by\n3 APs`
+	if got != want {
+		t.Errorf("CEF record\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestFormatLEEFRecord(t *testing.T) {
+	got := FormatLEEFRecord(testRecord(), "1.2")
+	if !strings.HasPrefix(got, "LEEF:1.0|wifimgr|wifimgr|1.2|honeypot|") {
+		t.Fatalf("LEEF header = %q", got)
+	}
+	attrs := strings.Split(strings.SplitN(got, "|", 6)[5], "\t")
+	for _, want := range []string{
+		"devTime=Jun 01 2024 12:00:00.500 UTC", "sev=8", "srcMAC=aa:bb:cc:00:00:01",
+		"apMAC=5c:5b:35:00:00:01", "ssid=Corp=WiFi", "site=US-LAB-01", "msg=seen by 3 APs",
+	} {
+		found := false
+		for _, a := range attrs {
+			found = found || a == want
+		}
+		if !found {
+			t.Errorf("LEEF attributes %q lack %q", attrs, want)
+		}
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Cleanup(func() {
+		for _, k := range []string{"siem.address", "siem.transport", "siem.format", "siem.ca_file"} {
+			viper.Set(k, nil)
+		}
+	})
+
+	if cfg, err := LoadConfig(); cfg != nil || err != nil {
+		t.Fatalf("unconfigured = %+v, %v", cfg, err)
+	}
+	viper.Set("siem.address", "siem.example.com:6514")
+	cfg, err := LoadConfig()
+	if err != nil || cfg.Transport != TransportTLS || cfg.Format != FormatCEF || cfg.Facility != "local4" {
+		t.Errorf("defaults = %+v, %v", cfg, err)
+	}
+	for key, value := range map[string]string{
+		"siem.transport": "http",
+		"siem.format":    "json",
+		"siem.address":   "siem.example.com",
+	} {
+		viper.Set(key, value)
+		if _, err := LoadConfig(); err == nil {
+			t.Errorf("%s=%s accepted", key, value)
+		}
+		viper.Set(key, nil)
+		viper.Set("siem.address", "siem.example.com:6514")
+	}
+	viper.Set("siem.transport", "udp")
+	viper.Set("siem.ca_file", "/etc/ca.pem")
+	if _, err := LoadConfig(); err == nil {
+		t.Error("ca_file accepted with udp")
+	}
+}
+
+func TestSenderFramesOverTCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = ln.Close() }()
+	got := make(chan string, 1)
+	go func() {
+		defer close(got)
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		rd := bufio.NewReader(conn)
+		length, err := rd.ReadString(' ')
+		if err != nil {
+			return
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(length))
+		if err != nil {
+			return
+		}
+		msg := make([]byte, n)
+		if _, err := io.ReadFull(rd, msg); err == nil {
+			got <- string(msg)
+		}
+	}()
+
+	cfg := &Config{Address: ln.Addr().String(), Transport: TransportTCP}
+	cfg.applyDefaults()
+	s, err := Dial(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := testRecord()
+	if err := s.Send(r, "1.2"); err != nil {
+		t.Fatal(err)
+	}
+	_ = s.Close()
+
+	msg := <-got
+	// local4 (20) * 8 + error (3) for severity 8
+	if !strings.HasPrefix(msg, "<163>1 2024-06-01T12:00:00.500Z ") ||
+		!strings.HasSuffix(msg, " wifimgr - honeypot - "+FormatCEFRecord(r, "1.2")) {
+		t.Errorf("syslog message = %q", msg)
+	}
+}
+
+func TestStateRoundTrip(t *testing.T) {
+	orig := statePath
+	path := t.TempDir() + "/siem.json"
+	statePath = func() string { return path }
+	t.Cleanup(func() { statePath = orig })
+
+	if state, err := LoadState(); err != nil || len(state) != 0 {
+		t.Fatalf("missing state = %v, %v", state, err)
+	}
+	at := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	if err := SaveState(State{StateKey("mist-prod", "site-1"): at}); err != nil {
+		t.Fatal(err)
+	}
+	state, err := LoadState()
+	if err != nil || !state["mist-prod/site-1"].Equal(at) {
+		t.Errorf("state = %v, %v", state, err)
+	}
+}
@@ -0,0 +1,53 @@
+package siem
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ravinald/wifimgr/internal/helpers"
+	"github.com/ravinald/wifimgr/internal/xdg"
+)
+
+// State records, per API and site ("<api>/<site-id>"), the time of the
+// newest event already shipped, so a scheduled export sends each event once.
+type State map[string]time.Time
+
+// StateKey is the State key of a site.
+func StateKey(apiLabel, siteID string) string {
+	return apiLabel + "/" + siteID
+}
+
+// statePath is where the shipped watermarks are recorded; tests override it.
+var statePath = func() string { return filepath.Join(xdg.GetStateDir(), "siem.json") }
+
+// LoadState reads the export state; a missing file is an empty state.
+func LoadState() (State, error) {
+	data, err := os.ReadFile(statePath()) // #nosec G304 -- fixed name under the state dir
+	if errors.Is(err, os.ErrNotExist) {
+		return State{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	state := State{}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("invalid siem state %s: %w", statePath(), err)
+	}
+	return state, nil
+}
+
+// SaveState writes the export state.
+func SaveState(state State) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(statePath()), 0o700); err != nil {
+		return err
+	}
+	return helpers.WriteFileAtomic(statePath(), data, 0o600)
+}
@@ -0,0 +1,183 @@
+// Package sitehealth captures a site's health — devices connected, wireless
+// clients, and recent alarms — just before and after an apply, so the apply
+// report carries evidence a change was non-disruptive (or the first sign it
+// was not) without anyone opening the vendor dashboard.
+package sitehealth
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/ravinald/wifimgr/internal/logging"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+// DefaultSettle is how long apply waits after its last push before taking
+// the post-apply snapshot, so rebooting or re-joining devices have reported.
+const DefaultSettle = 30 * time.Second
+
+// AlarmWindow is how far back from each snapshot alarms are counted.
+const AlarmWindow = time.Hour
+
+// ClientDropPct is the fall in wireless clients, in percent of the
+// pre-apply count, that marks a comparison as regressed. Client counts move
+// on their own, so small changes are not flagged.
+const ClientDropPct = 25
+
+// Metric names, as listed in Snapshot.Unavailable.
+const (
+	MetricDevices = "devices"
+	MetricClients = "clients"
+	MetricAlarms  = "alarms"
+)
+
+// Config holds the apply.health settings of the main config.
+type Config struct {
+	Settle time.Duration
+}
+
+// LoadConfig reads apply.health_check and apply.health_settle from Viper. It
+// returns nil when the comparison is not enabled.
+func LoadConfig() *Config {
+	if !viper.GetBool("apply.health_check") {
+		return nil
+	}
+	cfg := &Config{Settle: DefaultSettle}
+	if viper.IsSet("apply.health_settle") {
+		if d := viper.GetDuration("apply.health_settle"); d >= 0 {
+			cfg.Settle = d
+		}
+	}
+	return cfg
+}
+
+// Snapshot is a site's health at one moment. A metric the vendor could not
+// report is zero and named in Unavailable.
+type Snapshot struct {
+	Time        time.Time `json:"time"`
+	Devices     int       `json:"devices"`
+	Connected   int       `json:"connected"`
+	Clients     int       `json:"clients"`
+	Alarms      int       `json:"alarms"`
+	Unavailable []string  `json:"unavailable,omitempty"`
+}
+
+// Has reports whether the snapshot carries metric.
+func (s Snapshot) Has(metric string) bool {
+	return !slices.Contains(s.Unavailable, metric)
+}
+
+// Collect takes a snapshot of siteID: device connection state from the
+// site's device list, clients summed from live device stats, and security
+// alarms raised in the AlarmWindow before now. Each metric is best effort.
+func Collect(ctx context.Context, client vendors.Client, siteID string) Snapshot {
+	s := Snapshot{Time: time.Now().UTC()}
+
+	if devices, err := client.Devices().List(ctx, siteID, ""); err != nil {
+		logging.Debugf("sitehealth: device list for %s: %v", siteID, err)
+		s.Unavailable = append(s.Unavailable, MetricDevices)
+	} else {
+		for _, d := range devices {
+			s.Devices++
+			if d.Status == "connected" {
+				s.Connected++
+			}
+		}
+	}
+
+	if svc := client.DeviceStats(); svc == nil {
+		s.Unavailable = append(s.Unavailable, MetricClients)
+	} else if stats, err := svc.ListBySite(ctx, siteID); err != nil {
+		logging.Debugf("sitehealth: device stats for %s: %v", siteID, err)
+		s.Unavailable = append(s.Unavailable, MetricClients)
+	} else {
+		for _, st := range stats {
+			s.Clients += st.Clients
+		}
+	}
+
+	if svc := client.SecurityEvents(); svc == nil {
+		s.Unavailable = append(s.Unavailable, MetricAlarms)
+	} else if events, err := svc.Search(ctx, siteID, s.Time.Add(-AlarmWindow), s.Time); err != nil {
+		logging.Debugf("sitehealth: alarms for %s: %v", siteID, err)
+		s.Unavailable = append(s.Unavailable, MetricAlarms)
+	} else {
+		s.Alarms = len(events)
+	}
+	return s
+}
+
+// Comparison is a site's health before and after an apply.
+type Comparison struct {
+	Before Snapshot `json:"before"`
+	After  Snapshot `json:"after"`
+}
+
+// both reports whether both snapshots carry metric.
+func (c Comparison) both(metric string) bool {
+	return c.Before.Has(metric) && c.After.Has(metric)
+}
+
+// Regressions describes each way the site got worse: fewer devices
+// connected, more alarms, or clients down by ClientDropPct or more. Metrics
+// missing from either snapshot are not compared.
+func (c Comparison) Regressions() []string {
+	var out []string
+	if c.both(MetricDevices) && c.After.Connected < c.Before.Connected {
+		out = append(out, fmt.Sprintf("%d fewer device(s) connected", c.Before.Connected-c.After.Connected))
+	}
+	if c.both(MetricClients) && c.Before.Clients > 0 &&
+		(c.Before.Clients-c.After.Clients)*100 >= c.Before.Clients*ClientDropPct {
+		out = append(out, fmt.Sprintf("clients fell from %d to %d", c.Before.Clients, c.After.Clients))
+	}
+	if c.both(MetricAlarms) && c.After.Alarms > c.Before.Alarms {
+		out = append(out, fmt.Sprintf("%d new alarm(s)", c.After.Alarms-c.Before.Alarms))
+	}
+	return out
+}
+
+// Devices renders the connected devices as "before -> after", e.g.
+// "12/12 -> 11/12", or "n/a" when either snapshot lacks them.
+func (c Comparison) Devices() string {
+	if !c.both(MetricDevices) {
+		return "n/a"
+	}
+	return fmt.Sprintf("%d/%d -> %d/%d", c.Before.Connected, c.Before.Devices, c.After.Connected, c.After.Devices)
+}
+
+// Clients renders the client counts as "before -> after".
+func (c Comparison) Clients() string {
+	return c.change(MetricClients, c.Before.Clients, c.After.Clients)
+}
+
+// Alarms renders the alarm counts as "before -> after".
+func (c Comparison) Alarms() string {
+	return c.change(MetricAlarms, c.Before.Alarms, c.After.Alarms)
+}
+
+func (c Comparison) change(metric string, before, after int) string {
+	if !c.both(metric) {
+		return "n/a"
+	}
+	return fmt.Sprintf("%d -> %d", before, after)
+}
+
+// Details returns the comparison as flat key/values for notifications.
+func (c Comparison) Details() map[string]any {
+	d := map[string]any{"regressed": len(c.Regressions()) > 0}
+	if c.both(MetricDevices) {
+		d["connected_before"], d["connected_after"] = c.Before.Connected, c.After.Connected
+		d["devices_before"], d["devices_after"] = c.Before.Devices, c.After.Devices
+	}
+	if c.both(MetricClients) {
+		d["clients_before"], d["clients_after"] = c.Before.Clients, c.After.Clients
+	}
+	if c.both(MetricAlarms) {
+		d["alarms_before"], d["alarms_after"] = c.Before.Alarms, c.After.Alarms
+	}
+	return d
+}
@@ -0,0 +1,107 @@
+package sitehealth
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+type healthClient struct {
+	*vendors.MockClient
+	stats  statsService
+	events eventsService
+}
+
+func (c *healthClient) DeviceStats() vendors.DeviceStatsService       { return c.stats }
+func (c *healthClient) SecurityEvents() vendors.SecurityEventsService { return c.events }
+
+type statsService []*vendors.DeviceStats
+
+func (s statsService) ListBySite(_ context.Context, _ string) ([]*vendors.DeviceStats, error) {
+	return s, nil
+}
+
+type eventsService []*vendors.SecurityEvent
+
+func (e eventsService) Search(_ context.Context, _ string, _, _ time.Time) ([]*vendors.SecurityEvent, error) {
+	return e, nil
+}
+
+func TestCollect(t *testing.T) {
+	devices := vendors.NewMockDevicesService()
+	devices.Devices[1].Status = "disconnected"
+	mock := vendors.NewMockClientWithAllServices("mist", "org-1")
+	mock.SetDevicesService(devices)
+	client := &healthClient{
+		MockClient: mock,
+		stats:      statsService{{MAC: "a", Clients: 7}, {MAC: "b", Clients: 5}},
+		events:     eventsService{{Kind: vendors.SecurityEventRogue}},
+	}
+
+	s := Collect(context.Background(), client, "site-001")
+	if s.Devices != 2 || s.Connected != 1 || s.Clients != 12 || s.Alarms != 1 {
+		t.Errorf("snapshot = %+v, want 2 devices, 1 connected, 12 clients, 1 alarm", s)
+	}
+	if len(s.Unavailable) != 0 {
+		t.Errorf("unavailable = %v, want none", s.Unavailable)
+	}
+
+	// The plain mock has neither device stats nor security events.
+	s = Collect(context.Background(), mock, "site-001")
+	if !slices.Equal(s.Unavailable, []string{MetricClients, MetricAlarms}) {
+		t.Errorf("unavailable = %v, want clients and alarms", s.Unavailable)
+	}
+}
+
+func TestRegressions(t *testing.T) {
+	before := Snapshot{Devices: 10, Connected: 10, Clients: 100, Alarms: 2}
+	tests := []struct {
+		name  string
+		after Snapshot
+		want  int
+	}{
+		{"unchanged", before, 0},
+		{"client churn", Snapshot{Devices: 10, Connected: 10, Clients: 80, Alarms: 2}, 0},
+		{"device dropped", Snapshot{Devices: 10, Connected: 9, Clients: 100, Alarms: 2}, 1},
+		{"clients fell", Snapshot{Devices: 10, Connected: 10, Clients: 75, Alarms: 2}, 1},
+		{"new alarms", Snapshot{Devices: 10, Connected: 10, Clients: 100, Alarms: 3}, 1},
+		{"alarms unavailable", Snapshot{Devices: 10, Connected: 10, Clients: 100, Alarms: 0, Unavailable: []string{MetricAlarms}}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Comparison{Before: before, After: tt.after}
+			if got := c.Regressions(); len(got) != tt.want {
+				t.Errorf("Regressions() = %v, want %d", got, tt.want)
+			}
+		})
+	}
+
+	c := Comparison{Before: before, After: Snapshot{Devices: 10, Connected: 9, Unavailable: []string{MetricClients}}}
+	if got := c.Devices(); got != "10/10 -> 9/10" {
+		t.Errorf("Devices() = %q", got)
+	}
+	if got := c.Clients(); got != "n/a" {
+		t.Errorf("Clients() = %q, want n/a", got)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	if LoadConfig() != nil {
+		t.Error("LoadConfig() without apply.health_check should be nil")
+	}
+	viper.Set("apply.health_check", true)
+	if cfg := LoadConfig(); cfg == nil || cfg.Settle != DefaultSettle {
+		t.Errorf("LoadConfig() = %+v, want default settle", cfg)
+	}
+	viper.Set("apply.health_settle", "0s")
+	if cfg := LoadConfig(); cfg == nil || cfg.Settle != 0 {
+		t.Errorf("LoadConfig() = %+v, want no settle", cfg)
+	}
+}
@@ -0,0 +1,138 @@
+// Package sitelock serializes applies to a site across operators. Before apply
+// pushes to a site it takes a lease on a lock named after the site, held in a
+// backend every operator can reach: a directory on shared storage, a Redis
+// server, or a DynamoDB table. A second apply to the same site is refused with
+// the current holder's name; a lease whose TTL has run out (the holder crashed
+// or lost its connection) is taken over by the next apply.
+package sitelock
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/xdg"
+)
+
+// Supported backends.
+const (
+	BackendFile     = "file"
+	BackendRedis    = "redis"
+	BackendDynamoDB = "dynamodb"
+)
+
+// DefaultTTL is how long a lease stays valid. It must outlast the longest
+// apply: a lease that expires mid-apply can be taken over.
+const DefaultTTL = 30 * time.Minute
+
+// Config holds the apply_lock section of the main config.
+type Config struct {
+	Backend string
+	TTL     time.Duration
+
+	// Path is the lock directory for the file backend. Point it at storage
+	// every operator mounts (NFS, SMB); the default only guards one host.
+	Path string
+
+	RedisAddress  string
+	RedisPassword string `json:"-"` // #nosec G117 -- runtime-only, never persisted
+	RedisDB       int
+	RedisTLS      bool
+
+	DynamoTable     string
+	DynamoRegion    string
+	DynamoEndpoint  string
+	AccessKeyID     string `json:"-"`
+	SecretAccessKey string `json:"-"` // #nosec G117 -- runtime-only, never persisted
+}
+
+// LoadConfig reads apply_lock.* from Viper. It returns (nil, nil) when locking
+// is not enabled.
+func LoadConfig() (*Config, error) {
+	if !viper.GetBool("apply_lock.enabled") {
+		return nil, nil
+	}
+
+	cfg := &Config{
+		Backend:        strings.ToLower(viper.GetString("apply_lock.backend")),
+		TTL:            viper.GetDuration("apply_lock.ttl"),
+		Path:           viper.GetString("apply_lock.path"),
+		RedisAddress:   viper.GetString("apply_lock.redis.address"),
+		RedisDB:        viper.GetInt("apply_lock.redis.db"),
+		RedisTLS:       viper.GetBool("apply_lock.redis.tls"),
+		DynamoTable:    viper.GetString("apply_lock.dynamodb.table"),
+		DynamoRegion:   viper.GetString("apply_lock.dynamodb.region"),
+		DynamoEndpoint: viper.GetString("apply_lock.dynamodb.endpoint"),
+	}
+	if v, err := config.ResolveCredential("apply_lock.redis.password"); err == nil {
+		cfg.RedisPassword = v
+	}
+	cfg.AccessKeyID = resolveKey("apply_lock.dynamodb.credentials.access_key_id", "AWS_ACCESS_KEY_ID")
+	cfg.SecretAccessKey = resolveKey("apply_lock.dynamodb.credentials.secret_access_key", "AWS_SECRET_ACCESS_KEY")
+
+	cfg.applyDefaults()
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// resolveKey resolves a credential from config/env, falling back to a
+// provider-standard environment variable.
+func resolveKey(configPath, fallbackEnv string) string {
+	if v, err := config.ResolveCredential(configPath); err == nil {
+		return v
+	}
+	return os.Getenv(fallbackEnv)
+}
+
+// applyDefaults fills backend-dependent defaults.
+func (c *Config) applyDefaults() {
+	if c.Backend == "" {
+		c.Backend = BackendFile
+	}
+	if c.TTL <= 0 {
+		c.TTL = DefaultTTL
+	}
+	switch c.Backend {
+	case BackendFile:
+		if c.Path == "" {
+			c.Path = filepath.Join(xdg.GetStateDir(), "locks")
+		}
+		c.Path = xdg.ExpandPath(c.Path)
+	case BackendDynamoDB:
+		if c.DynamoRegion == "" {
+			c.DynamoRegion = "us-east-1"
+		}
+		if c.DynamoEndpoint == "" {
+			c.DynamoEndpoint = fmt.Sprintf("https://dynamodb.%s.amazonaws.com", c.DynamoRegion)
+		}
+		c.DynamoEndpoint = strings.TrimRight(c.DynamoEndpoint, "/")
+	}
+}
+
+// Validate checks that the selected backend is fully configured.
+func (c *Config) Validate() error {
+	switch c.Backend {
+	case BackendFile:
+	case BackendRedis:
+		if c.RedisAddress == "" {
+			return fmt.Errorf("apply_lock.redis.address is required for the redis backend")
+		}
+	case BackendDynamoDB:
+		if c.DynamoTable == "" {
+			return fmt.Errorf("apply_lock.dynamodb.table is required for the dynamodb backend")
+		}
+		if c.AccessKeyID == "" || c.SecretAccessKey == "" {
+			return fmt.Errorf("apply_lock.dynamodb credentials are required (apply_lock.dynamodb.credentials.access_key_id/secret_access_key or AWS_ACCESS_KEY_ID/AWS_SECRET_ACCESS_KEY)")
+		}
+	default:
+		return fmt.Errorf("invalid apply_lock.backend %q: must be 'file', 'redis' or 'dynamodb'", c.Backend)
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+package sitelock
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ravinald/wifimgr/internal/sigv4"
+)
+
+// DynamoDBBackend stores locks as items in a table whose partition key is the
+// string attribute "lock_key". Acquire is a conditional PutItem that succeeds
+// when the key is absent or its expires_at has passed. Enabling DynamoDB TTL
+// on expires_at cleans up abandoned items but is not required.
+type DynamoDBBackend struct {
+	cfg        *Config
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+// NewDynamoDBBackend returns a backend for cfg's table.
+func NewDynamoDBBackend(cfg *Config) *DynamoDBBackend {
+	return &DynamoDBBackend{
+		cfg:        cfg,
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+		now:        time.Now,
+	}
+}
+
+// dynamoAttr is a DynamoDB attribute value of type S or N.
+type dynamoAttr struct {
+	S string `json:"S,omitempty"`
+	N string `json:"N,omitempty"`
+}
+
+// dynamoConditionFailed is the error type DynamoDB returns when a condition
+// expression rejects a write.
+const dynamoConditionFailed = "ConditionalCheckFailedException"
+
+// Create writes the item unless a live lock exists.
+func (b *DynamoDBBackend) Create(ctx context.Context, key string, rec Record, now time.Time) (*Record, error) {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return nil, err
+	}
+	req := map[string]any{
+		"TableName": b.cfg.DynamoTable,
+		"Item": map[string]dynamoAttr{
+			"lock_key":   {S: key},
+			"token":      {S: rec.Token},
+			"expires_at": {N: strconv.FormatInt(rec.ExpiresAt.Unix(), 10)},
+			"record":     {S: string(data)},
+		},
+		"ConditionExpression":       "attribute_not_exists(lock_key) OR expires_at <= :now",
+		"ExpressionAttributeValues": map[string]dynamoAttr{":now": {N: strconv.FormatInt(now.Unix(), 10)}},
+	}
+	conflict, _, err := b.call(ctx, "PutItem", req)
+	if err != nil || !conflict {
+		return nil, err
+	}
+	held, err := b.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if held == nil {
+		return nil, fmt.Errorf("lock %s changed hands during acquire; retry", key)
+	}
+	return held, nil
+}
+
+// Get reads the item with a consistent read; no item is no lock.
+func (b *DynamoDBBackend) Get(ctx context.Context, key string) (*Record, error) {
+	req := map[string]any{
+		"TableName":      b.cfg.DynamoTable,
+		"Key":            map[string]dynamoAttr{"lock_key": {S: key}},
+		"ConsistentRead": true,
+	}
+	_, body, err := b.call(ctx, "GetItem", req)
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Item map[string]dynamoAttr `json:"Item"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("dynamodb GetItem: %w", err)
+	}
+	if resp.Item == nil {
+		return nil, nil
+	}
+	var rec Record
+	if err := json.Unmarshal([]byte(resp.Item["record"].S), &rec); err != nil {
+		return nil, fmt.Errorf("corrupt lock %s: %w", key, err)
+	}
+	return &rec, nil
+}
+
+// Delete removes the item, conditioned on the token when one is given. A
+// failed condition means the lease was taken over; that is not an error.
+func (b *DynamoDBBackend) Delete(ctx context.Context, key, token string) error {
+	req := map[string]any{
+		"TableName": b.cfg.DynamoTable,
+		"Key":       map[string]dynamoAttr{"lock_key": {S: key}},
+	}
+	if token != "" {
+		req["ConditionExpression"] = "#t = :token"
+		req["ExpressionAttributeNames"] = map[string]string{"#t": "token"}
+		req["ExpressionAttributeValues"] = map[string]dynamoAttr{":token": {S: token}}
+	}
+	_, _, err := b.call(ctx, "DeleteItem", req)
+	return err
+}
+
+// Renew overwrites the item, conditioned on it still holding rec's token.
+func (b *DynamoDBBackend) Renew(ctx context.Context, key string, rec Record, _ time.Time) (bool, error) {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return false, err
+	}
+	req := map[string]any{
+		"TableName": b.cfg.DynamoTable,
+		"Item": map[string]dynamoAttr{
+			"lock_key":   {S: key},
+			"token":      {S: rec.Token},
+			"expires_at": {N: strconv.FormatInt(rec.ExpiresAt.Unix(), 10)},
+			"record":     {S: string(data)},
+		},
+		"ConditionExpression":       "#t = :token",
+		"ExpressionAttributeNames":  map[string]string{"#t": "token"},
+		"ExpressionAttributeValues": map[string]dynamoAttr{":token": {S: rec.Token}},
+	}
+	conflict, _, err := b.call(ctx, "PutItem", req)
+	if err != nil {
+		return false, err
+	}
+	return !conflict, nil
+}
+
+// call sends one signed DynamoDB JSON API request. A write rejected by its
+// condition expression is reported as conflict rather than as an error.
+func (b *DynamoDBBackend) call(ctx context.Context, op string, payload any) (conflict bool, body []byte, err error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return false, nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.cfg.DynamoEndpoint+"/", bytes.NewReader(data))
+	if err != nil {
+		return false, nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.0")
+	req.Header.Set("X-Amz-Target", "DynamoDB_20120810."+op)
+	sigv4.Sign(req, data, sigv4.Credentials{
+		AccessKeyID:     b.cfg.AccessKeyID,
+		SecretAccessKey: b.cfg.SecretAccessKey,
+		Region:          b.cfg.DynamoRegion,
+		Service:         "dynamodb",
+	}, b.now().UTC())
+
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return false, nil, fmt.Errorf("dynamodb %s: %w", op, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return false, nil, fmt.Errorf("dynamodb %s: failed to read response: %w", op, err)
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+		return false, body, nil
+	}
+
+	var awsErr struct {
+		Type    string `json:"__type"`
+		Message string `json:"message"`
+	}
+	if resp.StatusCode == http.StatusBadRequest && json.Unmarshal(body, &awsErr) == nil && awsErr.Type != "" {
+		// "com.amazonaws.dynamodb.v20120810#ConditionalCheckFailedException"
+		t := awsErr.Type[strings.LastIndex(awsErr.Type, "#")+1:]
+		if t == dynamoConditionFailed {
+			return true, body, nil
+		}
+		return false, nil, fmt.Errorf("dynamodb %s: %s: %s", op, t, awsErr.Message)
+	}
+	return false, nil, fmt.Errorf("dynamodb %s: HTTP %d: %s", op, resp.StatusCode, strings.TrimSpace(string(body)))
+}
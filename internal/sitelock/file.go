@@ -0,0 +1,132 @@
+package sitelock
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ravinald/wifimgr/internal/helpers"
+)
+
+// FileBackend keeps one JSON file per lock in a directory. Creation uses
+// O_EXCL, which NFSv3+ and SMB honor, so the directory can live on shared
+// storage.
+type FileBackend struct {
+	dir string
+}
+
+// NewFileBackend returns a backend rooted at dir, creating it if needed.
+func NewFileBackend(dir string) (*FileBackend, error) {
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, fmt.Errorf("failed to create lock directory %s: %w", dir, err)
+	}
+	return &FileBackend{dir: dir}, nil
+}
+
+func (b *FileBackend) path(key string) string {
+	return filepath.Join(b.dir, key+".lock")
+}
+
+// Create writes the lock file exclusively. A stale file is removed (only if
+// it is still the one that was read) and creation retried once.
+func (b *FileBackend) Create(ctx context.Context, key string, rec Record, now time.Time) (*Record, error) {
+	data, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	for attempt := 0; attempt < 2; attempt++ {
+		f, err := os.OpenFile(b.path(key), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if err == nil {
+			_, werr := f.Write(data)
+			cerr := f.Close()
+			if werr != nil || cerr != nil {
+				_ = os.Remove(b.path(key))
+				return nil, errors.Join(werr, cerr)
+			}
+			return nil, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+
+		existing, err := b.Get(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		if existing == nil {
+			continue // released between the open and the read
+		}
+		if !existing.Stale(now) {
+			return existing, nil
+		}
+		if err := b.Delete(ctx, key, existing.Token); err != nil {
+			return nil, err
+		}
+	}
+	// Another operator re-created the lock while the stale one was removed.
+	existing, err := b.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, fmt.Errorf("lock file %s keeps changing; retry", b.path(key))
+	}
+	return existing, nil
+}
+
+// Get reads the lock file; a missing file is no lock.
+func (b *FileBackend) Get(_ context.Context, key string) (*Record, error) {
+	return readRecord(b.path(key))
+}
+
+func readRecord(path string) (*Record, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var rec Record
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("corrupt lock file %s: %w", path, err)
+	}
+	return &rec, nil
+}
+
+// Delete removes the lock file when token matches (or token is empty). The
+// token is checked on the file after it has been claimed by rename, so a
+// lock another operator re-created in the meantime is never removed.
+func (b *FileBackend) Delete(_ context.Context, key, token string) error {
+	if token == "" {
+		if err := os.Remove(b.path(key)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	_, err := helpers.BreakLock(b.path(key), func(claimed string) bool {
+		rec, err := readRecord(claimed)
+		return err == nil && rec != nil && rec.Token == token
+	})
+	return err
+}
+
+// Renew rewrites the lock file in place while it still holds rec's token.
+func (b *FileBackend) Renew(_ context.Context, key string, rec Record, _ time.Time) (bool, error) {
+	current, err := readRecord(b.path(key))
+	if err != nil || current == nil || current.Token != rec.Token {
+		return false, err
+	}
+	data, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return false, err
+	}
+	if err := helpers.WriteFileAtomic(b.path(key), data, 0600); err != nil {
+		return false, err
+	}
+	return true, nil
+}
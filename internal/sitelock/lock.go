@@ -0,0 +1,269 @@
+package sitelock
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ravinald/wifimgr/internal/history"
+)
+
+// Record describes who holds a site lock. It is stored as JSON in every
+// backend.
+type Record struct {
+	Site       string    `json:"site"`
+	Owner      string    `json:"owner"` // operator@host
+	PID        int       `json:"pid"`
+	Command    string    `json:"command,omitempty"`
+	Token      string    `json:"token"` // identifies this lease for release
+	AcquiredAt time.Time `json:"acquired_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// Stale reports whether the lease has run past its TTL at now.
+func (r *Record) Stale(now time.Time) bool {
+	return !now.Before(r.ExpiresAt)
+}
+
+// String renders the holder for messages: "alice@laptop (pid 4242, apply ap) since 14:05:09 UTC".
+func (r *Record) String() string {
+	s := fmt.Sprintf("%s (pid %d", r.Owner, r.PID)
+	if r.Command != "" {
+		s += ", " + r.Command
+	}
+	return s + ") since " + r.AcquiredAt.Local().Format("15:04:05 MST")
+}
+
+// Backend stores lock records. Create must be atomic: of two callers racing
+// on the same key, at most one succeeds.
+type Backend interface {
+	// Create stores rec under key when no lock exists or the existing one is
+	// stale at now. When a live lock is in the way it returns that lock and
+	// stores nothing.
+	Create(ctx context.Context, key string, rec Record, now time.Time) (held *Record, err error)
+	// Get returns the lock under key, or nil when there is none.
+	Get(ctx context.Context, key string) (*Record, error)
+	// Delete removes the lock under key if its token matches; an empty token
+	// removes it unconditionally.
+	Delete(ctx context.Context, key, token string) error
+	// Renew replaces the lock under key with rec while it still carries
+	// rec.Token. It reports false, storing nothing, when the lease has been
+	// taken over or broken.
+	Renew(ctx context.Context, key string, rec Record, now time.Time) (bool, error)
+}
+
+// ErrLeaseLost is returned by Lease.Renew when the lock now belongs to
+// someone else or was broken.
+var ErrLeaseLost = errors.New("lease was taken over or broken")
+
+// HeldError is returned by Acquire when another operator holds the site.
+type HeldError struct {
+	Holder Record
+}
+
+func (e *HeldError) Error() string {
+	return fmt.Sprintf("site %s is locked by %s; retry when that apply finishes, or run 'wifimgr lock break %s' if it is abandoned",
+		e.Holder.Site, e.Holder.String(), e.Holder.Site)
+}
+
+// Locker acquires per-site leases from a Backend.
+type Locker struct {
+	backend Backend
+	ttl     time.Duration
+	now     func() time.Time
+}
+
+// NewLocker returns a Locker over backend with leases of the given TTL.
+func NewLocker(backend Backend, ttl time.Duration) *Locker {
+	return &Locker{backend: backend, ttl: ttl, now: time.Now}
+}
+
+// New builds the Locker for cfg's backend.
+func New(cfg *Config) (*Locker, error) {
+	var backend Backend
+	switch cfg.Backend {
+	case BackendFile:
+		b, err := NewFileBackend(cfg.Path)
+		if err != nil {
+			return nil, err
+		}
+		backend = b
+	case BackendRedis:
+		backend = NewRedisBackend(cfg.RedisAddress, cfg.RedisPassword, cfg.RedisDB, cfg.RedisTLS)
+	case BackendDynamoDB:
+		backend = NewDynamoDBBackend(cfg)
+	default:
+		return nil, fmt.Errorf("unknown apply_lock.backend %q", cfg.Backend)
+	}
+	return NewLocker(backend, cfg.TTL), nil
+}
+
+// FromConfig returns the configured Locker, or nil when locking is disabled.
+func FromConfig() (*Locker, error) {
+	cfg, err := LoadConfig()
+	if err != nil || cfg == nil {
+		return nil, err
+	}
+	return New(cfg)
+}
+
+// Lease is a held site lock.
+type Lease struct {
+	Record
+	// TookOver is the stale lock this lease replaced, if any.
+	TookOver *Record
+
+	locker *Locker
+	key    string
+}
+
+// Release gives the lock back. Releasing a lease that has since been taken
+// over leaves the new holder's lock alone.
+func (l *Lease) Release(ctx context.Context) error {
+	return l.locker.backend.Delete(ctx, l.key, l.Token)
+}
+
+// Renew pushes the lease's expiry a full TTL past now. It fails with
+// ErrLeaseLost once the lock has been taken over or broken.
+func (l *Lease) Renew(ctx context.Context) error {
+	now := l.locker.now().UTC()
+	rec := l.Record
+	rec.ExpiresAt = now.Add(l.locker.ttl)
+	ok, err := l.locker.backend.Renew(ctx, l.key, rec, now)
+	if err != nil {
+		return fmt.Errorf("failed to renew lock on %s: %w", l.Site, err)
+	}
+	if !ok {
+		return fmt.Errorf("lock on %s: %w", l.Site, ErrLeaseLost)
+	}
+	l.ExpiresAt = rec.ExpiresAt
+	return nil
+}
+
+// KeepAlive renews the lease every third of its TTL until ctx is done or the
+// returned stop func is called, so a long apply never looks stale to other
+// operators. Renewal errors go to onErr; renewal ends once the lease is
+// lost. stop waits for the renewer to exit.
+func (l *Lease) KeepAlive(ctx context.Context, onErr func(error)) (stop func()) {
+	quit := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(l.locker.ttl / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-quit:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := l.Renew(ctx); err != nil {
+					onErr(err)
+					if errors.Is(err, ErrLeaseLost) {
+						return
+					}
+				}
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(quit) })
+		<-done
+	}
+}
+
+// Acquire takes the lock for site on behalf of command. A live lock held by
+// anyone else yields a *HeldError; a stale one is taken over and reported in
+// Lease.TookOver.
+func (l *Locker) Acquire(ctx context.Context, site, command string) (*Lease, error) {
+	key := Key(site)
+	now := l.now().UTC()
+
+	prior, err := l.backend.Get(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lock for %s: %w", site, err)
+	}
+	if prior != nil && !prior.Stale(now) {
+		return nil, &HeldError{Holder: *prior}
+	}
+
+	rec := Record{
+		Site:       site,
+		Owner:      owner(),
+		PID:        os.Getpid(),
+		Command:    command,
+		Token:      newToken(),
+		AcquiredAt: now,
+		ExpiresAt:  now.Add(l.ttl),
+	}
+	held, err := l.backend.Create(ctx, key, rec, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock %s: %w", site, err)
+	}
+	if held != nil {
+		// Lost a race with another operator between Get and Create.
+		return nil, &HeldError{Holder: *held}
+	}
+	return &Lease{Record: rec, TookOver: prior, locker: l, key: key}, nil
+}
+
+// Status returns the current lock on site, or nil when it is free. A stale
+// lock is still returned; check Record.Stale.
+func (l *Locker) Status(ctx context.Context, site string) (*Record, error) {
+	return l.backend.Get(ctx, Key(site))
+}
+
+// Break removes the lock on site regardless of owner and returns what was
+// removed (nil when it was free or unreadable). An unreadable lock is removed
+// too, so a corrupt lock file can be cleared.
+func (l *Locker) Break(ctx context.Context, site string) (*Record, error) {
+	key := Key(site)
+	rec, _ := l.backend.Get(ctx, key)
+	if err := l.backend.Delete(ctx, key, ""); err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+// Now reports the Locker's clock, for staleness checks by callers.
+func (l *Locker) Now() time.Time { return l.now().UTC() }
+
+// unsafeKeyChars are replaced in lock keys so a key is a valid file name,
+// Redis key and DynamoDB string on every platform.
+var unsafeKeyChars = regexp.MustCompile(`[^a-z0-9._-]+`)
+
+// Key is the backend key for site: site names are case-insensitive, so
+// "US-LAB-01" and "us-lab-01" share a lock.
+func Key(site string) string {
+	return "site-" + unsafeKeyChars.ReplaceAllString(strings.ToLower(strings.TrimSpace(site)), "_")
+}
+
+// owner names the lock holder as operator@host.
+func owner() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "unknown-host"
+	}
+	return history.Operator() + "@" + host
+}
+
+func newToken() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// IsHeld reports whether err is a *HeldError.
+func IsHeld(err error) bool {
+	var held *HeldError
+	return errors.As(err, &held)
+}
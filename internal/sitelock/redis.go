@@ -0,0 +1,203 @@
+package sitelock
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// redisKeyPrefix namespaces lock keys in a shared Redis.
+const redisKeyPrefix = "wifimgr:lock:"
+
+// redisTimeout bounds each lock operation, dial included.
+const redisTimeout = 10 * time.Second
+
+// releaseScript deletes the key only while it still holds the caller's token,
+// so releasing a lease that was taken over cannot drop the new holder's lock.
+const releaseScript = `local v = redis.call('GET', KEYS[1])
+if v and cjson.decode(v).token == ARGV[1] then return redis.call('DEL', KEYS[1]) end
+return 0`
+
+// renewScript replaces the key and its expiry only while it still holds the
+// caller's token.
+const renewScript = `local v = redis.call('GET', KEYS[1])
+if v and cjson.decode(v).token == ARGV[1] then return redis.call('SET', KEYS[1], ARGV[2], 'PX', ARGV[3]) end
+return false`
+
+// RedisBackend stores locks as Redis string keys with a TTL, so a stale lock
+// expires on its own. It speaks RESP directly over one short-lived connection
+// per operation.
+type RedisBackend struct {
+	address  string
+	password string
+	db       int
+	useTLS   bool
+}
+
+// NewRedisBackend returns a backend for the Redis server at address
+// (host:port).
+func NewRedisBackend(address, password string, db int, useTLS bool) *RedisBackend {
+	return &RedisBackend{address: address, password: password, db: db, useTLS: useTLS}
+}
+
+// Create sets the key with NX and a PX expiry matching the lease.
+func (b *RedisBackend) Create(ctx context.Context, key string, rec Record, now time.Time) (*Record, error) {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return nil, err
+	}
+	ttl := rec.ExpiresAt.Sub(now).Milliseconds()
+	if ttl < 1 {
+		ttl = 1
+	}
+	reply, err := b.do(ctx, "SET", redisKeyPrefix+key, string(data), "NX", "PX", strconv.FormatInt(ttl, 10))
+	if err != nil {
+		return nil, err
+	}
+	if reply != nil {
+		return nil, nil // OK
+	}
+	held, err := b.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if held == nil {
+		return nil, fmt.Errorf("lock %s changed hands during acquire; retry", key)
+	}
+	return held, nil
+}
+
+// Get reads the key; a missing key is no lock.
+func (b *RedisBackend) Get(ctx context.Context, key string) (*Record, error) {
+	reply, err := b.do(ctx, "GET", redisKeyPrefix+key)
+	if err != nil || reply == nil {
+		return nil, err
+	}
+	var rec Record
+	if err := json.Unmarshal([]byte(*reply), &rec); err != nil {
+		return nil, fmt.Errorf("corrupt lock %s: %w", key, err)
+	}
+	return &rec, nil
+}
+
+// Delete removes the key, checking the token server-side when one is given.
+func (b *RedisBackend) Delete(ctx context.Context, key, token string) error {
+	if token == "" {
+		_, err := b.do(ctx, "DEL", redisKeyPrefix+key)
+		return err
+	}
+	_, err := b.do(ctx, "EVAL", releaseScript, "1", redisKeyPrefix+key, token)
+	return err
+}
+
+// Renew resets the value and TTL, checking the token server-side.
+func (b *RedisBackend) Renew(ctx context.Context, key string, rec Record, now time.Time) (bool, error) {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return false, err
+	}
+	ttl := rec.ExpiresAt.Sub(now).Milliseconds()
+	if ttl < 1 {
+		ttl = 1
+	}
+	reply, err := b.do(ctx, "EVAL", renewScript, "1", redisKeyPrefix+key, rec.Token, string(data), strconv.FormatInt(ttl, 10))
+	if err != nil {
+		return false, err
+	}
+	return reply != nil, nil
+}
+
+// do runs one command on a fresh connection (after AUTH/SELECT as
+// configured) and returns its reply: nil for a null bulk string.
+func (b *RedisBackend) do(ctx context.Context, args ...string) (*string, error) {
+	ctx, cancel := context.WithTimeout(ctx, redisTimeout)
+	defer cancel()
+
+	dialer := &net.Dialer{}
+	var conn net.Conn
+	var err error
+	if b.useTLS {
+		host, _, _ := net.SplitHostPort(b.address)
+		td := &tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}}
+		conn, err = td.DialContext(ctx, "tcp", b.address)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", b.address)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("redis %s: %w", b.address, err)
+	}
+	defer func() { _ = conn.Close() }()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	r := bufio.NewReader(conn)
+	if b.password != "" {
+		if _, err := redisCommand(conn, r, "AUTH", b.password); err != nil {
+			return nil, fmt.Errorf("redis AUTH: %w", err)
+		}
+	}
+	if b.db != 0 {
+		if _, err := redisCommand(conn, r, "SELECT", strconv.Itoa(b.db)); err != nil {
+			return nil, fmt.Errorf("redis SELECT %d: %w", b.db, err)
+		}
+	}
+	return redisCommand(conn, r, args...)
+}
+
+// redisCommand writes args as a RESP array and reads one reply.
+func redisCommand(w io.Writer, r *bufio.Reader, args ...string) (*string, error) {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "*%d\r\n", len(args))
+	for _, a := range args {
+		fmt.Fprintf(&sb, "$%d\r\n%s\r\n", len(a), a)
+	}
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return nil, err
+	}
+	return readRedisReply(r)
+}
+
+// readRedisReply parses the RESP reply types lock commands produce: simple
+// string, error, integer and bulk string.
+func readRedisReply(r *bufio.Reader) (*string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimRight(line, "\r\n")
+	if line == "" {
+		return nil, errors.New("empty redis reply")
+	}
+	switch line[0] {
+	case '+', ':':
+		s := line[1:]
+		return &s, nil
+	case '-':
+		return nil, errors.New(line[1:])
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("bad redis bulk length %q", line)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		s := string(buf[:n])
+		return &s, nil
+	default:
+		return nil, fmt.Errorf("unexpected redis reply %q", line)
+	}
+}
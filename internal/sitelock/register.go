@@ -0,0 +1,44 @@
+package sitelock
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/viper"
+
+	"github.com/ravinald/wifimgr/internal/integrations"
+)
+
+// probeSite is the site whose lock the check reads. No real site is expected
+// to have this name, so the read finds nothing and changes nothing.
+const probeSite = "wifimgr-integrations-status"
+
+func init() {
+	integrations.Register(integrations.Integration{
+		Name:           "apply-lock",
+		ConfigKey:      "apply_lock",
+		EndpointKeys:   "apply_lock.redis.address or apply_lock.dynamodb.table, region, and endpoint",
+		CredentialKeys: "apply_lock.redis.password or apply_lock.dynamodb.credentials",
+		Configured:     func() bool { return viper.GetBool("apply_lock.enabled") },
+		Check:          check,
+	})
+}
+
+// check reads a probe lock from the configured backend.
+func check(ctx context.Context) (string, error) {
+	cfg, err := LoadConfig()
+	if err != nil {
+		return "", err
+	}
+	if cfg == nil {
+		return "", fmt.Errorf("apply_lock is not enabled")
+	}
+	locker, err := New(cfg)
+	if err != nil {
+		return "", err
+	}
+	if _, err := locker.Status(ctx, probeSite); err != nil {
+		return "", err
+	}
+	return cfg.Backend + " backend", nil
+}
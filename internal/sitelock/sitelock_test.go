@@ -0,0 +1,243 @@
+package sitelock
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestLocker(t *testing.T, now *time.Time) *Locker {
+	t.Helper()
+	backend, err := NewFileBackend(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := NewLocker(backend, 10*time.Minute)
+	l.now = func() time.Time { return *now }
+	return l
+}
+
+func TestFileLockContention(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	l := newTestLocker(t, &now)
+
+	lease, err := l.Acquire(ctx, "US-LAB-01", "apply ap")
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	if lease.TookOver != nil {
+		t.Errorf("fresh lock reported a takeover: %+v", lease.TookOver)
+	}
+
+	// Site names are case-insensitive: this is the same lock.
+	_, err = l.Acquire(ctx, "us-lab-01", "apply switch")
+	if !IsHeld(err) {
+		t.Fatalf("second acquire err = %v, want HeldError", err)
+	}
+	if !strings.Contains(err.Error(), lease.Owner) || !strings.Contains(err.Error(), "apply ap") {
+		t.Errorf("error should name the holder: %v", err)
+	}
+
+	if err := lease.Release(ctx); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+	if _, err := l.Acquire(ctx, "US-LAB-01", "apply switch"); err != nil {
+		t.Errorf("acquire after release: %v", err)
+	}
+}
+
+func TestFileLockStaleTakeover(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	l := newTestLocker(t, &now)
+
+	first, err := l.Acquire(ctx, "US-LAB-01", "apply ap")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now = now.Add(11 * time.Minute)
+	second, err := l.Acquire(ctx, "US-LAB-01", "apply ap")
+	if err != nil {
+		t.Fatalf("stale lock should be taken over: %v", err)
+	}
+	if second.TookOver == nil || second.TookOver.Token != first.Token {
+		t.Errorf("TookOver = %+v, want the first lease", second.TookOver)
+	}
+
+	// The crashed holder coming back must not release the new lease.
+	if err := first.Release(ctx); err != nil {
+		t.Fatal(err)
+	}
+	rec, err := l.Status(ctx, "US-LAB-01")
+	if err != nil || rec == nil || rec.Token != second.Token {
+		t.Errorf("Status after stale release = %+v, %v; want the second lease", rec, err)
+	}
+
+	removed, err := l.Break(ctx, "US-LAB-01")
+	if err != nil || removed == nil || removed.Token != second.Token {
+		t.Errorf("Break = %+v, %v", removed, err)
+	}
+	if rec, _ := l.Status(ctx, "US-LAB-01"); rec != nil {
+		t.Errorf("lock still present after Break: %+v", rec)
+	}
+}
+
+func TestFileLockRenew(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	l := newTestLocker(t, &now)
+
+	lease, err := l.Acquire(ctx, "US-LAB-01", "apply ap")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A renewed lease outlives its original TTL: a long apply keeps the site.
+	now = now.Add(8 * time.Minute)
+	if err := lease.Renew(ctx); err != nil {
+		t.Fatalf("Renew: %v", err)
+	}
+	now = now.Add(8 * time.Minute)
+	if _, err := l.Acquire(ctx, "US-LAB-01", "apply switch"); !IsHeld(err) {
+		t.Fatalf("acquire of a renewed lease err = %v, want HeldError", err)
+	}
+
+	// Once taken over, renewing must not overwrite the new holder.
+	now = now.Add(11 * time.Minute)
+	second, err := l.Acquire(ctx, "US-LAB-01", "apply switch")
+	if err != nil {
+		t.Fatalf("stale lock should be taken over: %v", err)
+	}
+	if err := lease.Renew(ctx); !errors.Is(err, ErrLeaseLost) {
+		t.Fatalf("Renew after takeover err = %v, want ErrLeaseLost", err)
+	}
+	if rec, _ := l.Status(ctx, "US-LAB-01"); rec == nil || rec.Token != second.Token {
+		t.Errorf("Status = %+v, want the second lease", rec)
+	}
+}
+
+func TestLeaseKeepAlive(t *testing.T) {
+	backend, err := NewFileBackend(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := NewLocker(backend, 60*time.Millisecond)
+	ctx := context.Background()
+
+	lease, err := l.Acquire(ctx, "US-LAB-01", "apply ap")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stop := lease.KeepAlive(ctx, func(err error) { t.Errorf("renew: %v", err) })
+	time.Sleep(150 * time.Millisecond)
+	stop()
+
+	rec, err := l.Status(ctx, "US-LAB-01")
+	if err != nil || rec == nil {
+		t.Fatalf("Status = %+v, %v", rec, err)
+	}
+	if !rec.ExpiresAt.After(lease.AcquiredAt.Add(60 * time.Millisecond)) {
+		t.Errorf("lease was not renewed: expires %s, acquired %s", rec.ExpiresAt, lease.AcquiredAt)
+	}
+}
+
+func TestKey(t *testing.T) {
+	if got := Key("US LAB/01"); got != "site-us_lab_01" {
+		t.Errorf("Key = %q", got)
+	}
+}
+
+func TestReadRedisReply(t *testing.T) {
+	cases := []struct {
+		in      string
+		want    *string
+		wantErr bool
+	}{
+		{"+OK\r\n", strPtr("OK"), false},
+		{":1\r\n", strPtr("1"), false},
+		{"$5\r\nhello\r\n", strPtr("hello"), false},
+		{"$-1\r\n", nil, false},
+		{"-WRONGPASS invalid password\r\n", nil, true},
+	}
+	for _, c := range cases {
+		got, err := readRedisReply(bufio.NewReader(strings.NewReader(c.in)))
+		if (err != nil) != c.wantErr {
+			t.Errorf("%q: err = %v", c.in, err)
+			continue
+		}
+		if (got == nil) != (c.want == nil) || (got != nil && *got != *c.want) {
+			t.Errorf("%q: got %v, want %v", c.in, got, c.want)
+		}
+	}
+}
+
+func strPtr(s string) *string { return &s }
+
+// TestDynamoDBHeld checks that a failed PutItem condition surfaces the
+// current holder read back with GetItem.
+func TestDynamoDBHeld(t *testing.T) {
+	holder := Record{Site: "US-LAB-01", Owner: "bob@ops", PID: 7, Token: "t1",
+		AcquiredAt: time.Now().UTC(), ExpiresAt: time.Now().UTC().Add(time.Hour)}
+	holderJSON, _ := json.Marshal(holder)
+
+	var targets []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		target := r.Header.Get("X-Amz-Target")
+		targets = append(targets, target)
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 ") {
+			t.Errorf("%s: request not signed", target)
+		}
+		body, _ := io.ReadAll(r.Body)
+		switch target {
+		case "DynamoDB_20120810.PutItem":
+			if !strings.Contains(string(body), "attribute_not_exists(lock_key)") {
+				t.Errorf("PutItem without condition: %s", body)
+			}
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = io.WriteString(w, `{"__type":"com.amazonaws.dynamodb.v20120810#ConditionalCheckFailedException","message":"The conditional request failed"}`)
+		case "DynamoDB_20120810.GetItem":
+			item := map[string]any{"Item": map[string]dynamoAttr{"lock_key": {S: "site-us-lab-01"}, "record": {S: string(holderJSON)}}}
+			_ = json.NewEncoder(w).Encode(item)
+		default:
+			t.Errorf("unexpected target %s", target)
+		}
+	}))
+	defer srv.Close()
+
+	cfg := &Config{Backend: BackendDynamoDB, DynamoTable: "locks", DynamoEndpoint: srv.URL,
+		DynamoRegion: "us-east-1", AccessKeyID: "AK", SecretAccessKey: "SK"}
+	held, err := NewDynamoDBBackend(cfg).Create(context.Background(), "site-us-lab-01",
+		Record{Token: "t2", ExpiresAt: time.Now().Add(time.Hour)}, time.Now())
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if held == nil || held.Owner != "bob@ops" {
+		t.Errorf("held = %+v, want bob@ops", held)
+	}
+	if len(targets) != 2 {
+		t.Errorf("requests = %v, want PutItem then GetItem", targets)
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	cfg := &Config{}
+	cfg.applyDefaults()
+	if cfg.Backend != BackendFile || cfg.TTL != DefaultTTL || cfg.Path == "" {
+		t.Errorf("defaults = %+v", cfg)
+	}
+	if err := (&Config{Backend: BackendRedis}).Validate(); err == nil {
+		t.Error("redis without address should fail validation")
+	}
+	if err := (&Config{Backend: "etcd"}).Validate(); err == nil {
+		t.Error("unknown backend should fail validation")
+	}
+}
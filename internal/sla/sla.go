@@ -0,0 +1,202 @@
+// Package sla evaluates per-site capacity and availability thresholds: the
+// most wireless clients any one AP should carry, and the share of a site's
+// APs that must be online. Thresholds are set under "sla" in the main config,
+// with defaults for every site and overrides matched by site name glob.
+//
+// The package only evaluates; "wifimgr report sla" gathers the samples and
+// sends a notification per breach, and running that from cron every few
+// minutes is what makes the alerting proactive.
+package sla
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// Metrics a Breach can report.
+const (
+	MetricClientsPerAP = "clients_per_ap"
+	MetricAPsOnline    = "aps_online_pct"
+)
+
+// Thresholds are the limits for one site. A zero field is not checked.
+type Thresholds struct {
+	MaxClientsPerAP int     `json:"max_clients_per_ap,omitempty" mapstructure:"max_clients_per_ap"`
+	MinAPsOnlinePct float64 `json:"min_aps_online_pct,omitempty" mapstructure:"min_aps_online_pct"`
+}
+
+// IsZero reports whether no threshold is set.
+func (t Thresholds) IsZero() bool {
+	return t.MaxClientsPerAP == 0 && t.MinAPsOnlinePct == 0
+}
+
+// Override replaces the default thresholds at the sites matching Sites.
+// Fields left at zero keep the default.
+type Override struct {
+	Sites      []string `json:"sites" mapstructure:"sites"` // site name globs, case-insensitive
+	Thresholds `mapstructure:",squash"`
+}
+
+// Config holds the sla section of the main config.
+type Config struct {
+	Defaults  Thresholds
+	Overrides []Override
+}
+
+// LoadConfig reads sla.* from Viper. It returns (nil, nil) when the section
+// is absent.
+func LoadConfig() (*Config, error) {
+	if !viper.IsSet("sla") {
+		return nil, nil
+	}
+	cfg := &Config{}
+	if err := viper.UnmarshalKey("sla", &cfg.Defaults); err != nil {
+		return nil, fmt.Errorf("invalid sla config: %w", err)
+	}
+	if err := viper.UnmarshalKey("sla.overrides", &cfg.Overrides); err != nil {
+		return nil, fmt.Errorf("invalid sla.overrides: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Validate checks threshold ranges and override site patterns.
+func (c *Config) Validate() error {
+	if err := c.Defaults.validate(); err != nil {
+		return fmt.Errorf("sla: %w", err)
+	}
+	for i, o := range c.Overrides {
+		if len(o.Sites) == 0 {
+			return fmt.Errorf("sla.overrides[%d]: sites is required", i)
+		}
+		for _, g := range o.Sites {
+			if _, err := path.Match(strings.ToLower(g), ""); err != nil {
+				return fmt.Errorf("sla.overrides[%d]: invalid site pattern %q", i, g)
+			}
+		}
+		if err := o.validate(); err != nil {
+			return fmt.Errorf("sla.overrides[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (t Thresholds) validate() error {
+	if t.MaxClientsPerAP < 0 {
+		return errors.New("max_clients_per_ap must not be negative")
+	}
+	if t.MinAPsOnlinePct < 0 || t.MinAPsOnlinePct > 100 {
+		return errors.New("min_aps_online_pct must be between 0 and 100")
+	}
+	return nil
+}
+
+// For returns the thresholds that apply to site: the defaults, with the
+// fields of the first matching override laid over them.
+func (c *Config) For(site string) Thresholds {
+	t := c.Defaults
+	name := strings.ToLower(site)
+	for _, o := range c.Overrides {
+		if !o.matches(name) {
+			continue
+		}
+		if o.MaxClientsPerAP != 0 {
+			t.MaxClientsPerAP = o.MaxClientsPerAP
+		}
+		if o.MinAPsOnlinePct != 0 {
+			t.MinAPsOnlinePct = o.MinAPsOnlinePct
+		}
+		break
+	}
+	return t
+}
+
+func (o Override) matches(lowerName string) bool {
+	for _, g := range o.Sites {
+		if ok, _ := path.Match(strings.ToLower(g), lowerName); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// APSample is one AP's state at evaluation time.
+type APSample struct {
+	Name    string
+	MAC     string
+	Online  bool
+	Clients int
+}
+
+// Breach is one threshold a site is over (or under).
+type Breach struct {
+	Site      string  `json:"site"`
+	Metric    string  `json:"metric"`
+	Device    string  `json:"device,omitempty"` // the AP, for per-AP metrics
+	MAC       string  `json:"mac,omitempty"`
+	Value     float64 `json:"value"`
+	Threshold float64 `json:"threshold"`
+	Message   string  `json:"message"`
+}
+
+// Evaluate checks the APs of one site against t and returns the breaches,
+// overloaded APs first, busiest first. A site with no APs breaches nothing.
+func Evaluate(site string, t Thresholds, aps []APSample) []Breach {
+	if len(aps) == 0 {
+		return nil
+	}
+	var breaches []Breach
+
+	if t.MaxClientsPerAP > 0 {
+		var over []APSample
+		for _, ap := range aps {
+			if ap.Clients > t.MaxClientsPerAP {
+				over = append(over, ap)
+			}
+		}
+		sort.SliceStable(over, func(i, j int) bool { return over[i].Clients > over[j].Clients })
+		for _, ap := range over {
+			name := ap.Name
+			if name == "" {
+				name = ap.MAC
+			}
+			breaches = append(breaches, Breach{
+				Site:      site,
+				Metric:    MetricClientsPerAP,
+				Device:    ap.Name,
+				MAC:       ap.MAC,
+				Value:     float64(ap.Clients),
+				Threshold: float64(t.MaxClientsPerAP),
+				Message:   fmt.Sprintf("%s has %d clients (max %d)", name, ap.Clients, t.MaxClientsPerAP),
+			})
+		}
+	}
+
+	if t.MinAPsOnlinePct > 0 {
+		online := 0
+		for _, ap := range aps {
+			if ap.Online {
+				online++
+			}
+		}
+		pct := float64(online) * 100 / float64(len(aps))
+		if pct < t.MinAPsOnlinePct {
+			breaches = append(breaches, Breach{
+				Site:      site,
+				Metric:    MetricAPsOnline,
+				Value:     pct,
+				Threshold: t.MinAPsOnlinePct,
+				Message: fmt.Sprintf("%d of %d APs online (%.0f%%, min %.0f%%)",
+					online, len(aps), pct, t.MinAPsOnlinePct),
+			})
+		}
+	}
+	return breaches
+}
@@ -0,0 +1,84 @@
+package sla
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestLoadConfigAndFor(t *testing.T) {
+	t.Cleanup(viper.Reset)
+	if cfg, err := LoadConfig(); cfg != nil || err != nil {
+		t.Fatalf("absent section: cfg = %v, err = %v", cfg, err)
+	}
+
+	viper.Set("sla", map[string]any{
+		"max_clients_per_ap": 40,
+		"min_aps_online_pct": 90,
+		"overrides": []any{
+			map[string]any{"sites": []any{"US-NYC-*"}, "max_clients_per_ap": 60},
+			map[string]any{"sites": []any{"us-*"}, "min_aps_online_pct": 75},
+		},
+	})
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]Thresholds{
+		"GB-LON-01": {MaxClientsPerAP: 40, MinAPsOnlinePct: 90},
+		"us-nyc-02": {MaxClientsPerAP: 60, MinAPsOnlinePct: 90}, // first match only
+		"US-SFO-01": {MaxClientsPerAP: 40, MinAPsOnlinePct: 75},
+	}
+	for site, want := range tests {
+		if got := cfg.For(site); got != want {
+			t.Errorf("For(%s) = %+v, want %+v", site, got, want)
+		}
+	}
+}
+
+func TestLoadConfigRejectsBadValues(t *testing.T) {
+	t.Cleanup(viper.Reset)
+	for _, section := range []map[string]any{
+		{"min_aps_online_pct": 120},
+		{"max_clients_per_ap": -1},
+		{"overrides": []any{map[string]any{"max_clients_per_ap": 10}}},
+		{"overrides": []any{map[string]any{"sites": []any{"["}}}},
+	} {
+		viper.Set("sla", section)
+		if _, err := LoadConfig(); err == nil {
+			t.Errorf("LoadConfig accepted %v", section)
+		}
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	aps := []APSample{
+		{Name: "AP-01", MAC: "aabbccddee01", Online: true, Clients: 12},
+		{Name: "AP-02", MAC: "aabbccddee02", Online: true, Clients: 45},
+		{Name: "AP-03", MAC: "aabbccddee03", Online: true, Clients: 52},
+		{Name: "AP-04", MAC: "aabbccddee04", Online: false},
+	}
+
+	breaches := Evaluate("US-LAB-01", Thresholds{MaxClientsPerAP: 40, MinAPsOnlinePct: 80}, aps)
+	if len(breaches) != 3 {
+		t.Fatalf("got %d breaches, want 3: %+v", len(breaches), breaches)
+	}
+	if breaches[0].Device != "AP-03" || breaches[1].Device != "AP-02" {
+		t.Errorf("overloaded APs not busiest first: %+v", breaches[:2])
+	}
+	avail := breaches[2]
+	if avail.Metric != MetricAPsOnline || avail.Value != 75 || avail.Threshold != 80 {
+		t.Errorf("availability breach = %+v", avail)
+	}
+
+	if got := Evaluate("US-LAB-01", Thresholds{MaxClientsPerAP: 60, MinAPsOnlinePct: 75}, aps); len(got) != 0 {
+		t.Errorf("within thresholds: %+v", got)
+	}
+	if got := Evaluate("US-LAB-01", Thresholds{}, aps); len(got) != 0 {
+		t.Errorf("no thresholds set: %+v", got)
+	}
+	if got := Evaluate("US-LAB-01", Thresholds{MinAPsOnlinePct: 100}, nil); len(got) != 0 {
+		t.Errorf("site without APs: %+v", got)
+	}
+}
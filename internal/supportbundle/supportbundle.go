@@ -0,0 +1,311 @@
+// Package supportbundle gathers what a maintainer needs to diagnose a bug
+// report — version info, sanitized config, cache metadata, and recent logs —
+// into a tar.gz the operator reviews before attaching it to an issue.
+//
+// Secrets never enter the bundle: config files are parsed and passed through
+// common.RedactValue (a file that does not parse is left out rather than
+// copied raw), cache files contribute only their meta block, and log lines
+// are scrubbed of key=value and "key": "value" secrets.
+package supportbundle
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/ravinald/wifimgr/internal/common"
+	"github.com/ravinald/wifimgr/internal/vendors"
+	"github.com/ravinald/wifimgr/internal/xdg"
+)
+
+// DefaultLogLines is how much of the log file is included.
+const DefaultLogLines = 2000
+
+// Entry is one file in the bundle.
+type Entry struct {
+	Name        string // path inside the archive
+	Description string
+	Data        []byte
+}
+
+// Skipped records something that was looked for but left out, and why.
+type Skipped struct {
+	Source string
+	Reason string
+}
+
+// BuildInfo is the running binary's version metadata.
+type BuildInfo struct {
+	Version   string `json:"version"`
+	GitCommit string `json:"git_commit"`
+	BuildTime string `json:"build_time"`
+}
+
+// Options controls collection.
+type Options struct {
+	Build    BuildInfo
+	LogLines int
+	// ConfigError is a failure loading the main config, recorded in the
+	// bundle since it is often the bug being reported.
+	ConfigError error
+}
+
+// Collect gathers bundle entries from the loaded configuration. Sources that
+// are missing or unreadable are reported in skipped, not returned as errors.
+func Collect(opts Options) (entries []Entry, skipped []Skipped) {
+	if opts.LogLines <= 0 {
+		opts.LogLines = DefaultLogLines
+	}
+	skip := func(source, format string, args ...any) {
+		skipped = append(skipped, Skipped{Source: source, Reason: fmt.Sprintf(format, args...)})
+	}
+
+	entries = append(entries, versionEntry(opts))
+
+	if main := viper.ConfigFileUsed(); main != "" {
+		if e, err := configEntry("config/"+filepath.Base(main), main, "main config, secrets redacted"); err != nil {
+			skip(main, "%v", err)
+		} else {
+			entries = append(entries, e)
+		}
+	} else {
+		skip("main config", "no config file loaded")
+	}
+
+	configDir := viper.GetString("files.config_dir")
+	for _, key := range []string{"files.site_configs", "files.templates", "files.device_profiles"} {
+		for _, rel := range viper.GetStringSlice(key) {
+			path := xdg.Resolve(configDir, rel)
+			name := rel
+			if filepath.IsAbs(xdg.ExpandPath(rel)) {
+				name = filepath.Base(path)
+			}
+			e, err := configEntry("config/"+filepath.ToSlash(filepath.Clean(name)), path,
+				strings.TrimPrefix(key, "files.")+" file, secrets redacted")
+			if err != nil {
+				skip(path, "%v", err)
+				continue
+			}
+			entries = append(entries, e)
+		}
+	}
+
+	if e, err := cacheMetaEntry(viper.GetString("files.cache_dir")); err != nil {
+		skip("cache metadata", "%v", err)
+	} else {
+		entries = append(entries, e)
+	}
+
+	logFile := viper.GetString("files.log_file")
+	if logFile == "" {
+		logFile = xdg.GetLogFile()
+	}
+	if e, err := logEntry(logFile, opts.LogLines); err != nil {
+		skip(logFile, "%v", err)
+	} else {
+		entries = append(entries, e)
+	}
+
+	return entries, skipped
+}
+
+func versionEntry(opts Options) Entry {
+	var envNames []string
+	for _, kv := range os.Environ() {
+		if name, _, _ := strings.Cut(kv, "="); strings.HasPrefix(name, "WIFIMGR_") {
+			envNames = append(envNames, name)
+		}
+	}
+	sort.Strings(envNames)
+	info := map[string]any{
+		"build":      opts.Build,
+		"go":         runtime.Version(),
+		"os":         runtime.GOOS,
+		"arch":       runtime.GOARCH,
+		"created":    time.Now().UTC().Format(time.RFC3339),
+		"config_dir": viper.GetString("files.config_dir"),
+		"cache_dir":  viper.GetString("files.cache_dir"),
+		"env_set":    envNames, // names only; values may be secrets
+	}
+	if opts.ConfigError != nil {
+		info["config_error"] = opts.ConfigError.Error()
+	}
+	data, _ := json.MarshalIndent(info, "", "  ")
+	return Entry{Name: "version.json", Description: "wifimgr version, platform, and WIFIMGR_* variable names", Data: data}
+}
+
+// configEntry reads a JSON config file and returns it redacted. Files that
+// do not parse are refused: without a parse there is no safe redaction.
+func configEntry(name, path, description string) (Entry, error) {
+	raw, err := os.ReadFile(path) // #nosec G304 -- paths from operator-controlled config
+	if err != nil {
+		return Entry{}, err
+	}
+	data, err := Sanitize(raw)
+	if err != nil {
+		return Entry{}, fmt.Errorf("not included, does not parse as JSON: %v", err)
+	}
+	return Entry{Name: name, Description: description, Data: data}, nil
+}
+
+// Sanitize parses JSON config data and returns it indented with secrets
+// redacted.
+func Sanitize(raw []byte) ([]byte, error) {
+	var parsed any
+	if err := json.Unmarshal(raw, &parsed); err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(common.RedactValue(parsed), "", "  ")
+}
+
+// cacheMetaEntry summarizes each per-API cache file: its meta block (vendor,
+// refresh times, item counts, last error), size, and modification time.
+func cacheMetaEntry(cacheDir string) (Entry, error) {
+	if cacheDir == "" {
+		cacheDir = xdg.GetCacheDir()
+	}
+	files, err := filepath.Glob(filepath.Join(cacheDir, "apis", "*.json"))
+	if err != nil {
+		return Entry{}, err
+	}
+	if len(files) == 0 {
+		return Entry{}, fmt.Errorf("no API cache files in %s", filepath.Join(cacheDir, "apis"))
+	}
+	type cacheSummary struct {
+		File     string               `json:"file"`
+		Size     int64                `json:"size"`
+		Modified time.Time            `json:"modified"`
+		Version  int                  `json:"version"`
+		APILabel string               `json:"api_label"`
+		Meta     vendors.APICacheMeta `json:"meta"`
+		Error    string               `json:"error,omitempty"`
+	}
+	summaries := make([]cacheSummary, 0, len(files))
+	for _, f := range files {
+		s := cacheSummary{File: filepath.Base(f)}
+		if info, err := os.Stat(f); err == nil {
+			s.Size, s.Modified = info.Size(), info.ModTime().UTC()
+		}
+		raw, err := os.ReadFile(f) // #nosec G304 -- cache files under the configured cache dir
+		if err == nil {
+			var header struct {
+				Version  int                  `json:"version"`
+				APILabel string               `json:"api_label"`
+				Meta     vendors.APICacheMeta `json:"meta"`
+			}
+			if err = json.Unmarshal(raw, &header); err == nil {
+				s.Version, s.APILabel, s.Meta = header.Version, header.APILabel, header.Meta
+			}
+		}
+		if err != nil {
+			s.Error = err.Error()
+		}
+		summaries = append(summaries, s)
+	}
+	data, _ := json.MarshalIndent(summaries, "", "  ")
+	return Entry{Name: "cache/meta.json", Description: "per-API cache refresh times, item counts, last errors (no cached data)", Data: data}, nil
+}
+
+// logSecret matches key=value and "key": "value" pairs whose key names a
+// secret.
+var logSecret = regexp.MustCompile(`(?i)("?[a-z_]*(?:password|secret|token|api_key|apikey|psk|passphrase)"?\s*[:=]\s*)("[^"]*"|[^\s,}]+)`)
+
+// ScrubLogLine redacts secrets and enc: values from one log line.
+func ScrubLogLine(line string) string {
+	line = logSecret.ReplaceAllString(line, `${1}[REDACTED]`)
+	return encValue.ReplaceAllString(line, "[REDACTED]")
+}
+
+var encValue = regexp.MustCompile(`enc:[A-Za-z0-9+/=_-]+`)
+
+// logEntry returns the last n lines of the log file, scrubbed.
+func logEntry(path string, n int) (Entry, error) {
+	f, err := os.Open(path) // #nosec G304 -- log path from operator-controlled config
+	if err != nil {
+		return Entry{}, err
+	}
+	defer func() { _ = f.Close() }()
+
+	ring := make([]string, 0, n)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if len(ring) == n {
+			ring = ring[1:]
+		}
+		ring = append(ring, ScrubLogLine(scanner.Text()))
+	}
+	if err := scanner.Err(); err != nil {
+		return Entry{}, err
+	}
+	var buf bytes.Buffer
+	for _, l := range ring {
+		buf.WriteString(l)
+		buf.WriteByte('\n')
+	}
+	return Entry{
+		Name:        "logs/" + filepath.Base(path),
+		Description: fmt.Sprintf("last %d log lines, secrets scrubbed", len(ring)),
+		Data:        buf.Bytes(),
+	}, nil
+}
+
+func manifestEntry(entries []Entry, skipped []Skipped) Entry {
+	var buf bytes.Buffer
+	buf.WriteString("wifimgr support bundle\n\nIncluded:\n")
+	for _, e := range entries {
+		fmt.Fprintf(&buf, "  %-40s %8d bytes  %s\n", e.Name, len(e.Data), e.Description)
+	}
+	if len(skipped) > 0 {
+		buf.WriteString("\nNot included:\n")
+		for _, s := range skipped {
+			fmt.Fprintf(&buf, "  %s: %s\n", s.Source, s.Reason)
+		}
+	}
+	return Entry{Name: "MANIFEST.txt", Description: "list of included and skipped items", Data: buf.Bytes()}
+}
+
+// Write saves entries as a gzip-compressed tarball at path, readable only by
+// the owner, adding a MANIFEST.txt of what was included and skipped.
+func Write(path string, entries []Entry, skipped []Skipped) error {
+	kept := make([]Entry, 0, len(entries))
+	for _, e := range entries {
+		if e.Name != "MANIFEST.txt" {
+			kept = append(kept, e)
+		}
+	}
+	kept = append(kept, manifestEntry(kept, skipped))
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	now := time.Now()
+	for _, e := range kept {
+		hdr := &tar.Header{Name: "wifimgr-support/" + e.Name, Mode: 0600, Size: int64(len(e.Data)), ModTime: now}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(e.Data); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0600)
+}
@@ -0,0 +1,135 @@
+package supportbundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func writeFile(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCollect(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+	dir := t.TempDir()
+
+	main := filepath.Join(dir, "wifimgr-config.json")
+	writeFile(t, main, `{"version": 1, "api": {"mist-prod": {"credentials": {"org_id": "o1", "api_token": "tok-123"}}}}`)
+	writeFile(t, filepath.Join(dir, "sites", "lab.json"), `{"config": {"sites": {"LAB": {"wlan": [{"ssid": "corp", "radius_secret": "s3cret", "psk": "enc:QUJD"}]}}}}`)
+	writeFile(t, filepath.Join(dir, "sites", "broken.json"), `{"psk": "plaintext`)
+	writeFile(t, filepath.Join(dir, "cache", "apis", "mist-prod.json"), `{"version": 1, "api_label": "mist-prod", "meta": {"vendor": "mist", "last_error": "boom"}, "inventory": {"ap": {"aabbccddeeff": {"name": "AP-SECRET-NAME"}}}}`)
+	writeFile(t, filepath.Join(dir, "wifimgr.log"), "line one\nlevel=debug api_token=tok-123 msg=x\n{\"password\": \"hunter2\"}\n")
+
+	viper.SetConfigFile(main)
+	if err := viper.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+	viper.Set("files.config_dir", dir)
+	viper.Set("files.site_configs", []string{"sites/lab.json", "sites/broken.json"})
+	viper.Set("files.cache_dir", filepath.Join(dir, "cache"))
+	viper.Set("files.log_file", filepath.Join(dir, "wifimgr.log"))
+
+	entries, skipped := Collect(Options{Build: BuildInfo{Version: "1.2.3"}})
+	byName := make(map[string]string)
+	for _, e := range entries {
+		byName[e.Name] = string(e.Data)
+	}
+
+	all := strings.Join(func() []string {
+		var out []string
+		for _, v := range byName {
+			out = append(out, v)
+		}
+		return out
+	}(), "\n")
+	for _, secret := range []string{"tok-123", "s3cret", "enc:QUJD", "hunter2", "plaintext", "AP-SECRET-NAME"} {
+		if strings.Contains(all, secret) {
+			t.Errorf("bundle leaks %q", secret)
+		}
+	}
+
+	for _, name := range []string{"version.json", "config/wifimgr-config.json", "config/sites/lab.json", "cache/meta.json", "logs/wifimgr.log"} {
+		if _, ok := byName[name]; !ok {
+			t.Errorf("missing %s; have %v", name, entries)
+		}
+	}
+	if !strings.Contains(byName["cache/meta.json"], `"last_error": "boom"`) {
+		t.Errorf("cache meta = %s", byName["cache/meta.json"])
+	}
+	if !strings.Contains(byName["version.json"], `"version": "1.2.3"`) {
+		t.Errorf("version.json = %s", byName["version.json"])
+	}
+	if len(skipped) != 1 || !strings.Contains(skipped[0].Reason, "does not parse") {
+		t.Errorf("skipped = %+v", skipped)
+	}
+}
+
+func TestScrubLogLine(t *testing.T) {
+	tests := map[string]string{
+		`level=info api_key=abc123 msg=ok`:        `level=info api_key=[REDACTED] msg=ok`,
+		`{"radius_secret": "x y z", "name": "a"}`: `{"radius_secret": [REDACTED], "name": "a"}`,
+		`token: enc:QUJDRA==`:                     `token: [REDACTED]`,
+		`loaded psk enc:QUJD from site`:           `loaded psk [REDACTED] from site`,
+		`nothing to see`:                          `nothing to see`,
+	}
+	for in, want := range tests {
+		if got := ScrubLogLine(in); got != want {
+			t.Errorf("ScrubLogLine(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	entries := []Entry{{Name: "version.json", Data: []byte("{}")}, {Name: "MANIFEST.txt", Data: []byte("stale")}}
+	if err := Write(path, entries, []Skipped{{Source: "logs/wifimgr.log", Reason: "excluded during review"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(path) // #nosec G304 -- test path under TempDir
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+	var names []string
+	var manifest string
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, hdr.Name)
+		if strings.HasSuffix(hdr.Name, "MANIFEST.txt") {
+			data, _ := io.ReadAll(tr)
+			manifest = string(data)
+		}
+	}
+	if len(names) != 2 || names[0] != "wifimgr-support/version.json" {
+		t.Errorf("names = %v", names)
+	}
+	if !strings.Contains(manifest, "excluded during review") || strings.Contains(manifest, "stale") {
+		t.Errorf("manifest = %q", manifest)
+	}
+}
@@ -2,10 +2,13 @@ package symbols
 
 import (
 	"os"
+	"strings"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/muesli/termenv"
 	"golang.org/x/term"
+
+	"github.com/ravinald/wifimgr/internal/i18n"
 )
 
 // ConfigureColor applies the color policy once at startup. Styled output is
@@ -24,6 +27,35 @@ func ConfigureColor(noColorFlag bool) {
 	}
 }
 
+// plain is the --plain output mode; see SetPlain.
+var plain bool
+
+// SetPlain turns the screen-reader friendly output mode on or off. Plain
+// output carries no color, spells out signals that tables otherwise convey
+// by color or a one-letter symbol ("connected" rather than a green "C"),
+// and renders tables as "Title: value" records rather than wide columns.
+// Plain implies no color.
+func SetPlain(on bool) {
+	plain = on
+	if on {
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+}
+
+// Plain reports whether the plain output mode is on.
+func Plain() bool {
+	return plain
+}
+
+// Rule returns a horizontal rule of width n for hand-drawn tables: a box
+// drawing line, or hyphens in plain mode.
+func Rule(n int) string {
+	if plain {
+		return strings.Repeat("-", n)
+	}
+	return strings.Repeat("─", n)
+}
+
 var (
 	// greenStyle creates a bold green style
 	greenStyle = lipgloss.NewStyle().
@@ -83,27 +115,33 @@ func isTerminal() bool {
 
 // SuccessPrefix returns a colored success indicator for status messages.
 func SuccessPrefix() string {
-	return GreenText("[OK]")
+	return GreenText(i18n.T("prefix.ok"))
 }
 
 // FailurePrefix returns a colored failure indicator for status messages.
 func FailurePrefix() string {
-	return RedText("[FAIL]")
+	return RedText(i18n.T("prefix.fail"))
 }
 
 // ErrorPrefix returns a colored error indicator for status messages.
 func ErrorPrefix() string {
-	return RedText("[ERROR]")
+	return RedText(i18n.T("prefix.error"))
 }
 
 // WarningPrefix returns a colored warning indicator for status messages.
 func WarningPrefix() string {
-	return YellowText("[WARN]")
+	return YellowText(i18n.T("prefix.warn"))
 }
 
 // FormatBooleanValue formats a boolean value based on whether it's a connection field
 func FormatBooleanValue(value bool, isConnectionField bool) string {
 	if isConnectionField {
+		if plain {
+			if value {
+				return "connected"
+			}
+			return "disconnected"
+		}
 		// Use colored C/D for connection fields
 		if value {
 			return GreenText("C")
@@ -113,9 +151,9 @@ func FormatBooleanValue(value bool, isConnectionField bool) string {
 	} else {
 		// Use plain Yes/No for non-connection boolean fields
 		if value {
-			return "Yes"
+			return i18n.T("bool.yes")
 		} else {
-			return "No"
+			return i18n.T("bool.no")
 		}
 	}
 }
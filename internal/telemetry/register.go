@@ -0,0 +1,38 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/ravinald/wifimgr/internal/integrations"
+)
+
+func init() {
+	integrations.Register(integrations.Integration{
+		Name:         "telemetry",
+		ConfigKey:    "telemetry",
+		EndpointKeys: "telemetry.endpoint (" + endpointEnvVar + ")",
+		Configured:   func() bool { return Endpoint() != "" },
+		Check:        check,
+	})
+}
+
+// check sends a HEAD request to the upload endpoint. Uploads are POSTs, so
+// a 404 or 405 still shows the server is up.
+func check(ctx context.Context) (string, error) {
+	endpoint := Endpoint()
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, endpoint, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode >= 500 {
+		return "", fmt.Errorf("HTTP %d from %s", resp.StatusCode, endpoint)
+	}
+	return endpoint, nil
+}
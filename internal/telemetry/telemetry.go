@@ -0,0 +1,298 @@
+// Package telemetry records opt-in, anonymous usage events: which command ran,
+// how long it took, and the category of any error. Arguments, site and device
+// names, API labels, and config values are never recorded.
+//
+// Telemetry is off until the operator runs "wifimgr telemetry on". Events are
+// appended to a local spool under the state directory and uploaded in batches
+// to telemetry.endpoint when one is configured; without an endpoint they stay
+// local (capped at maxSpooled). WIFIMGR_TELEMETRY=off or DO_NOT_TRACK=1
+// disables recording regardless of the saved choice.
+package telemetry
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/ravinald/wifimgr/internal/helpers"
+	"github.com/ravinald/wifimgr/internal/vendors"
+	"github.com/ravinald/wifimgr/internal/xdg"
+)
+
+const (
+	// batchSize is the number of spooled events that triggers an upload.
+	batchSize = 50
+	// maxSpooled caps the spool; the oldest events are dropped beyond it.
+	maxSpooled     = 1000
+	uploadTimeout  = 3 * time.Second
+	spoolLockWait  = time.Second
+	settingsFile   = "settings.json"
+	spoolFile      = "spool.jsonl"
+	endpointEnvVar = "WIFIMGR_TELEMETRY_ENDPOINT"
+)
+
+// Event is one recorded command run.
+type Event struct {
+	Command    string    `json:"command"`         // command path, e.g. "show api status"
+	DurationMs int64     `json:"duration_ms"`     // wall time of the run
+	Error      string    `json:"error,omitempty"` // error category, empty on success
+	Version    string    `json:"version"`
+	OS         string    `json:"os"`
+	Arch       string    `json:"arch"`
+	Hour       time.Time `json:"hour"` // start time truncated to the hour
+}
+
+type settings struct {
+	Enabled bool      `json:"enabled"`
+	Changed time.Time `json:"changed"`
+}
+
+// dir is the telemetry state directory; tests point it at a temp dir.
+var dir = func() string { return filepath.Join(xdg.GetStateDir(), "telemetry") }
+
+// Dir returns where settings and the spool are kept.
+func Dir() string { return dir() }
+
+// envDisabled reports whether the environment opts out.
+func envDisabled() bool {
+	switch strings.ToLower(os.Getenv("WIFIMGR_TELEMETRY")) {
+	case "0", "off", "false", "no":
+		return true
+	}
+	return os.Getenv("DO_NOT_TRACK") == "1"
+}
+
+// Enabled reports whether events are recorded: the operator opted in and the
+// environment does not opt out.
+func Enabled() bool {
+	if envDisabled() {
+		return false
+	}
+	s, _ := loadSettings()
+	return s.Enabled
+}
+
+// EnvDisabled reports whether WIFIMGR_TELEMETRY or DO_NOT_TRACK overrides the
+// saved choice.
+func EnvDisabled() bool { return envDisabled() }
+
+// SetEnabled saves the operator's choice. Turning telemetry off also deletes
+// any spooled events.
+func SetEnabled(on bool) error {
+	if err := xdg.EnsureDir(dir()); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(settings{Enabled: on, Changed: time.Now().UTC()}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := helpers.WriteFileAtomic(filepath.Join(dir(), settingsFile), data, 0600); err != nil {
+		return fmt.Errorf("save telemetry setting: %w", err)
+	}
+	if !on {
+		if err := os.Remove(filepath.Join(dir(), spoolFile)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("clear telemetry spool: %w", err)
+		}
+	}
+	return nil
+}
+
+func loadSettings() (settings, error) {
+	var s settings
+	data, err := os.ReadFile(filepath.Join(dir(), settingsFile)) // #nosec G304 -- fixed name under the state dir
+	if err != nil {
+		return s, err
+	}
+	err = json.Unmarshal(data, &s)
+	return s, err
+}
+
+// Endpoint returns the upload URL: telemetry.endpoint from the config, else
+// WIFIMGR_TELEMETRY_ENDPOINT. Empty means events stay local.
+func Endpoint() string {
+	if e := viper.GetString("telemetry.endpoint"); e != "" {
+		return e
+	}
+	return os.Getenv(endpointEnvVar)
+}
+
+// NewEvent builds an event for a finished command.
+func NewEvent(command, version string, start time.Time, err error) Event {
+	return Event{
+		Command:    command,
+		DurationMs: time.Since(start).Milliseconds(),
+		Error:      Category(err),
+		Version:    version,
+		OS:         runtime.GOOS,
+		Arch:       runtime.GOARCH,
+		Hour:       start.UTC().Truncate(time.Hour),
+	}
+}
+
+// Category maps an error to a coarse, identifier-free class. Error text is
+// never recorded since it routinely names sites, devices, and paths.
+func Category(err error) string {
+	if err == nil {
+		return ""
+	}
+	var (
+		authErr        *vendors.AuthError
+		rateErr        *vendors.RateLimitError
+		serverErr      *vendors.ServerError
+		transportErr   *vendors.TransportError
+		notFoundErr    *vendors.NotFoundError
+		siteErr        *vendors.SiteNotFoundError
+		deviceErr      *vendors.DeviceNotFoundError
+		apiErr         *vendors.APINotFoundError
+		apiConfigErr   *vendors.InvalidAPIConfigError
+		capabilityErr  *vendors.CapabilityNotSupportedError
+		validationErr  *vendors.ConfigValidationError
+		fieldErr       *vendors.FieldMappingError
+		pathErr        *os.PathError
+		duplicateErr   *vendors.DuplicateSiteError
+		macCollideErr  *vendors.MACCollisionError
+		syntaxErr      *json.SyntaxError
+		unmarshalTyErr *json.UnmarshalTypeError
+	)
+	switch {
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.As(err, &authErr):
+		return "auth"
+	case errors.As(err, &rateErr):
+		return "rate_limit"
+	case errors.As(err, &serverErr):
+		return "server"
+	case errors.As(err, &transportErr):
+		return "transport"
+	case errors.As(err, &notFoundErr), errors.As(err, &siteErr), errors.As(err, &deviceErr):
+		return "not_found"
+	case errors.As(err, &apiErr), errors.As(err, &apiConfigErr):
+		return "api_config"
+	case errors.As(err, &capabilityErr):
+		return "unsupported"
+	case errors.As(err, &validationErr), errors.As(err, &fieldErr), errors.As(err, &duplicateErr), errors.As(err, &macCollideErr):
+		return "validation"
+	case errors.As(err, &syntaxErr), errors.As(err, &unmarshalTyErr):
+		return "config_parse"
+	case errors.As(err, &pathErr):
+		return "filesystem"
+	}
+	return "other"
+}
+
+// Record appends ev to the spool when telemetry is enabled. Failures are
+// returned for debug logging only; telemetry must never fail a command.
+func Record(ev Event) error {
+	if !Enabled() {
+		return nil
+	}
+	if err := xdg.EnsureDir(dir()); err != nil {
+		return err
+	}
+	path := filepath.Join(dir(), spoolFile)
+	unlock, err := helpers.LockFile(path, spoolLockWait)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	events, _ := readSpool(path)
+	events = append(events, ev)
+	if len(events) > maxSpooled {
+		events = events[len(events)-maxSpooled:]
+	}
+	return writeSpool(path, events)
+}
+
+// Pending returns the number of spooled events.
+func Pending() int {
+	events, _ := readSpool(filepath.Join(dir(), spoolFile))
+	return len(events)
+}
+
+// Flush uploads the spool to the endpoint once it holds batchSize events, or
+// any time force is set. The spool is cleared only after a 2xx response.
+func Flush(ctx context.Context, force bool) error {
+	endpoint := Endpoint()
+	if endpoint == "" || !Enabled() {
+		return nil
+	}
+	path := filepath.Join(dir(), spoolFile)
+	unlock, err := helpers.LockFile(path, spoolLockWait)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	events, err := readSpool(path)
+	if err != nil || len(events) == 0 || (!force && len(events) < batchSize) {
+		return err
+	}
+	body, err := json.Marshal(map[string]any{"events": events})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, uploadTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("telemetry upload: %w", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("telemetry upload: HTTP %d", resp.StatusCode)
+	}
+	return os.Remove(path)
+}
+
+func readSpool(path string) ([]Event, error) {
+	f, err := os.Open(path) // #nosec G304 -- fixed name under the state dir
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var events []Event
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var ev Event
+		if json.Unmarshal(scanner.Bytes(), &ev) == nil {
+			events = append(events, ev)
+		}
+	}
+	return events, scanner.Err()
+}
+
+func writeSpool(path string, events []Event) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, ev := range events {
+		if err := enc.Encode(ev); err != nil {
+			return err
+		}
+	}
+	return helpers.WriteFileAtomic(path, buf.Bytes(), 0600)
+}
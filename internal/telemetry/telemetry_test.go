@@ -0,0 +1,132 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+func useTempDir(t *testing.T) string {
+	t.Helper()
+	tmp := t.TempDir()
+	orig := dir
+	dir = func() string { return tmp }
+	t.Cleanup(func() { dir = orig })
+	t.Setenv("WIFIMGR_TELEMETRY", "")
+	t.Setenv("DO_NOT_TRACK", "")
+	t.Setenv(endpointEnvVar, "")
+	return tmp
+}
+
+func TestRecordRequiresOptIn(t *testing.T) {
+	useTempDir(t)
+	ev := NewEvent("show api status", "1.2.0", time.Now(), nil)
+
+	if err := Record(ev); err != nil || Pending() != 0 {
+		t.Fatalf("recorded while off: err=%v pending=%d", err, Pending())
+	}
+	if err := SetEnabled(true); err != nil {
+		t.Fatal(err)
+	}
+	_ = Record(ev)
+	_ = Record(ev)
+	if Pending() != 2 {
+		t.Errorf("pending = %d, want 2", Pending())
+	}
+
+	t.Setenv("DO_NOT_TRACK", "1")
+	if Enabled() {
+		t.Error("DO_NOT_TRACK should override the saved choice")
+	}
+	t.Setenv("DO_NOT_TRACK", "")
+
+	if err := SetEnabled(false); err != nil {
+		t.Fatal(err)
+	}
+	if Pending() != 0 {
+		t.Errorf("turning off should clear the spool, pending = %d", Pending())
+	}
+}
+
+func TestRecordCapsSpool(t *testing.T) {
+	tmp := useTempDir(t)
+	_ = SetEnabled(true)
+	events := make([]Event, maxSpooled)
+	if err := writeSpool(filepath.Join(tmp, spoolFile), events); err != nil {
+		t.Fatal(err)
+	}
+	_ = Record(Event{Command: "refresh"})
+	got, _ := readSpool(filepath.Join(tmp, spoolFile))
+	if len(got) != maxSpooled || got[len(got)-1].Command != "refresh" {
+		t.Errorf("spool len = %d, last = %+v", len(got), got[len(got)-1])
+	}
+}
+
+func TestFlush(t *testing.T) {
+	tmp := useTempDir(t)
+	_ = SetEnabled(true)
+
+	var received []Event
+	status := http.StatusAccepted
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct{ Events []Event }
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		received = append(received, body.Events...)
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+	viper.Set("telemetry.endpoint", srv.URL)
+	defer viper.Set("telemetry.endpoint", "")
+
+	_ = Record(Event{Command: "search wireless"})
+	if err := Flush(context.Background(), false); err != nil || len(received) != 0 {
+		t.Fatalf("partial batch uploaded: err=%v received=%d", err, len(received))
+	}
+
+	status = http.StatusInternalServerError
+	if err := Flush(context.Background(), true); err == nil || Pending() != 1 {
+		t.Errorf("failed upload should keep the spool: err=%v pending=%d", err, Pending())
+	}
+
+	status = http.StatusAccepted
+	received = nil
+	if err := Flush(context.Background(), true); err != nil {
+		t.Fatal(err)
+	}
+	if len(received) != 1 || received[0].Command != "search wireless" {
+		t.Errorf("received = %+v", received)
+	}
+	if _, err := os.Stat(filepath.Join(tmp, spoolFile)); !os.IsNotExist(err) {
+		t.Error("spool should be removed after upload")
+	}
+}
+
+func TestCategory(t *testing.T) {
+	tests := map[string]error{
+		"":            nil,
+		"canceled":    fmt.Errorf("refresh: %w", context.Canceled),
+		"auth":        fmt.Errorf("wrapped: %w", &vendors.AuthError{APILabel: "mist-prod", Status: 401}),
+		"not_found":   &vendors.SiteNotFoundError{},
+		"rate_limit":  &vendors.RateLimitError{},
+		"filesystem":  &os.PathError{Op: "open", Path: "/secret/site.json", Err: os.ErrNotExist},
+		"other":       errors.New("site US-LAB-01 has no APs"),
+		"api_config":  &vendors.APINotFoundError{},
+		"unsupported": &vendors.CapabilityNotSupportedError{},
+	}
+	for want, err := range tests {
+		if got := Category(err); got != want {
+			t.Errorf("Category(%v) = %q, want %q", err, got, want)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package validation
 import (
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 
 	"github.com/ravinald/wifimgr/internal/config"
@@ -102,8 +103,19 @@ func (l *ConfigLinter) LintSite(siteName string, siteConfig *config.SiteConfigOb
 				deviceModel = vars
 			}
 		}
-		issues = l.validateRadioConfig(configMap, targetVendor, deviceModel)
+		if deviceModel == "" && l.cacheAccessor != nil {
+			if item, err := l.cacheAccessor.GetAPByMAC(mac); err == nil && item != nil {
+				deviceModel = item.Model
+			}
+		}
+		issues = l.validateRadioConfig(configMap, targetVendor, deviceModel, siteConfig.SiteConfig.CountryCode)
 		result.addIssues(mac, deviceName, issues)
+
+		// Validate IPv6 management addressing
+		if apConfig.APDeviceConfig != nil && apConfig.APDeviceConfig.IPConfig != nil {
+			issues = ValidateIPv6Config(apConfig.APDeviceConfig.IPConfig.ToMap(), "ip_config.")
+			result.addIssues(mac, deviceName, issues)
+		}
 	}
 
 	// Lint switch configurations
@@ -121,6 +133,9 @@ func (l *ConfigLinter) LintSite(siteName string, siteConfig *config.SiteConfigOb
 
 		issues = l.validateRanges(configMap, "switch")
 		result.addIssues(mac, switchConfig.Name, issues)
+
+		issues = validateSwitchIPv6(switchConfig)
+		result.addIssues(mac, switchConfig.Name, issues)
 	}
 
 	// Lint gateway configurations
@@ -140,6 +155,9 @@ func (l *ConfigLinter) LintSite(siteName string, siteConfig *config.SiteConfigOb
 	// Validate WLAN assignment references
 	l.validateWLANReferences(siteConfig, result)
 
+	// Validate device names are unique
+	l.validateDuplicateNames(siteName, siteConfig, result)
+
 	return result, nil
 }
 
@@ -302,17 +320,53 @@ func (l *ConfigLinter) validateRanges(configMap map[string]any, deviceType strin
 	return issues
 }
 
-// validateRadioConfig validates radio configuration using the RadioValidator.
-func (l *ConfigLinter) validateRadioConfig(configMap map[string]any, targetVendor, deviceModel string) []LintIssue {
+// validateRadioConfig validates radio configuration using the RadioValidator,
+// checking 6 GHz settings against the site's country code.
+func (l *ConfigLinter) validateRadioConfig(configMap map[string]any, targetVendor, deviceModel, countryCode string) []LintIssue {
 	radioConfig, ok := configMap["radio_config"].(map[string]any)
 	if !ok || radioConfig == nil {
 		return nil
 	}
 
-	validator := NewRadioValidator(targetVendor, deviceModel)
+	validator := NewRadioValidator(targetVendor, deviceModel).WithCountryCode(countryCode)
 	return validator.ValidateRadioConfig(radioConfig)
 }
 
+// validateSwitchIPv6 checks the IPv6 fields of a switch: its ip_config and
+// oob_ip_config, the address of each other_ip_configs entry, and each
+// network's subnet6 and gateway6.
+func validateSwitchIPv6(sw config.SwitchConfig) []LintIssue {
+	var issues []LintIssue
+	for field, ipc := range map[string]config.IPConfig{"ip_config.": sw.IPConfig, "oob_ip_config.": sw.OobIPConfig} {
+		issues = append(issues, ValidateIPv6Config(nonEmpty(map[string]string{
+			"type6": ipc.Type6, "ip6": ipc.IP6, "netmask6": ipc.Netmask6, "gateway6": ipc.Gateway6,
+		}), field)...)
+	}
+	for i, other := range sw.OtherIPConfigs {
+		issues = append(issues, ValidateIPv6Config(nonEmpty(map[string]string{
+			"ip6": other.IP6, "netmask6": other.Netmask6,
+		}), fmt.Sprintf("other_ip_configs.%d.", i))...)
+	}
+	for _, network := range sw.Networks {
+		issues = append(issues, validateNetworkIPv6(nonEmpty(map[string]string{
+			"subnet6": network.Subnet6, "gateway6": network.Gateway6,
+		}), "networks."+network.Name+".")...)
+	}
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].Field < issues[j].Field })
+	return issues
+}
+
+// nonEmpty returns the set fields of m as a config map.
+func nonEmpty(m map[string]string) map[string]any {
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		if v != "" {
+			out[k] = v
+		}
+	}
+	return out
+}
+
 // validateDeprecatedFields checks for deprecated AP configuration fields.
 func (l *ConfigLinter) validateDeprecatedFields(apConfig config.APConfig) []LintIssue {
 	var issues []LintIssue
@@ -321,7 +375,7 @@ func (l *ConfigLinter) validateDeprecatedFields(apConfig config.APConfig) []Lint
 		issues = append(issues, LintIssue{
 			Field:      "config",
 			Message:    "Legacy 'config' field is deprecated",
-			Suggestion: "Migrate to radio_config structure",
+			Suggestion: "Migrate to radio_config structure; 'wifimgr config upgrade' does this",
 		})
 	}
 
@@ -329,7 +383,7 @@ func (l *ConfigLinter) validateDeprecatedFields(apConfig config.APConfig) []Lint
 		issues = append(issues, LintIssue{
 			Field:      "vlan_id",
 			Message:    "Top-level 'vlan_id' field is deprecated",
-			Suggestion: "Use ip_config.vlan_id instead",
+			Suggestion: "Use ip_config.vlan_id instead; 'wifimgr config upgrade' moves it",
 		})
 	}
 
@@ -376,6 +430,29 @@ func (l *ConfigLinter) validateWLANReferences(siteConfig *config.SiteConfigObj,
 		}
 	}
 
+	// Per-site WLAN exceptions are legal but easy to forget, so each one is a
+	// warning naming the fields it changes
+	for _, label := range sortedOverrideLabels(siteConfig.WLANOverrides) {
+		field := config.WLANOverridesField + "." + label
+		if !profileSet[label] {
+			result.Warnings = append(result.Warnings, LintIssue{
+				Field:      field,
+				Message:    fmt.Sprintf("Override for WLAN '%s' has no effect: the WLAN is not declared in profiles.wlan", label),
+				Suggestion: fmt.Sprintf("Remove %s or add '%s' to profiles.wlan", field, label),
+			})
+			continue
+		}
+		paths := config.WLANOverridePaths(siteConfig.WLANOverrides[label])
+		if len(paths) == 0 {
+			continue
+		}
+		result.Warnings = append(result.Warnings, LintIssue{
+			Field:      field,
+			Message:    fmt.Sprintf("WLAN '%s' differs from its template at this site: %s", label, strings.Join(paths, ", ")),
+			Suggestion: "Keep per-site exceptions few; move a change shared by several sites into its own template",
+		})
+	}
+
 	// Check profile WLAN entries have corresponding templates
 	if l.templateStore != nil {
 		for _, label := range siteConfig.Profiles.WLAN {
@@ -390,6 +467,74 @@ func (l *ConfigLinter) validateWLANReferences(siteConfig *config.SiteConfigObj,
 	}
 }
 
+// validateDuplicateNames flags device names the site declares more than once
+// (an error: name lookups resolve to only one of them) and names already held
+// by a different device elsewhere in the cache (a warning).
+func (l *ConfigLinter) validateDuplicateNames(siteName string, siteConfig *config.SiteConfigObj, result *LintResult) {
+	var devices []NamedDevice
+	for mac, ap := range siteConfig.Devices.APs {
+		if ap.APDeviceConfig != nil {
+			devices = append(devices, NamedDevice{MAC: mac, Name: ap.APDeviceConfig.Name, Type: "ap", Site: siteName})
+		}
+	}
+	for mac, sw := range siteConfig.Devices.Switches {
+		devices = append(devices, NamedDevice{MAC: mac, Name: sw.Name, Type: "switch", Site: siteName})
+	}
+	for mac, gw := range siteConfig.Devices.WanEdge {
+		devices = append(devices, NamedDevice{MAC: mac, Name: gw.Name, Type: "gateway", Site: siteName})
+	}
+
+	declared := make(map[string]bool, len(devices))
+	for _, d := range devices {
+		declared[vendors.NormalizeMAC(d.MAC)] = true
+	}
+	for _, dup := range FindDuplicateNames(devices) {
+		for _, d := range dup.Devices[1:] {
+			result.Errors = append(result.Errors, LintIssue{
+				DeviceMAC:  d.MAC,
+				DeviceName: d.Name,
+				Field:      "name",
+				Message:    fmt.Sprintf("Name '%s' is also used by %s %s at this site", d.Name, dup.Devices[0].Type, dup.Devices[0].MAC),
+				Suggestion: fmt.Sprintf("Rename to '%s'", d.Suggested),
+			})
+		}
+	}
+
+	if l.cacheAccessor == nil {
+		return
+	}
+	for _, d := range devices {
+		if d.Name == "" {
+			continue
+		}
+		other, err := l.cacheAccessor.GetDeviceByName(d.Name)
+		if err != nil || declared[vendors.NormalizeMAC(other.MAC)] {
+			continue
+		}
+		where := other.SiteName
+		if where == "" {
+			where = "unassigned"
+		}
+		result.Warnings = append(result.Warnings, LintIssue{
+			DeviceMAC:  d.MAC,
+			DeviceName: d.Name,
+			Field:      "name",
+			Message:    fmt.Sprintf("Name '%s' is already used by %s %s (%s)", d.Name, other.Type, other.MAC, where),
+			Suggestion: "Choose a name unique across the org; see 'wifimgr report duplicate-names'",
+		})
+	}
+}
+
+// sortedOverrideLabels returns the wlan_overrides labels in a stable order.
+func sortedOverrideLabels(overrides map[string]map[string]any) []string {
+	labels := make([]string, 0, len(overrides))
+	for label := range overrides {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	return labels
+}
+
 // addIssues adds issues to the result, categorizing them as warnings or errors.
 func (r *LintResult) addIssues(mac, deviceName string, issues []LintIssue) {
 	for _, issue := range issues {
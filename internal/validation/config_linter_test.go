@@ -210,3 +210,29 @@ func findSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestValidateWLANOverrides(t *testing.T) {
+	linter := NewConfigLinter(nil)
+	site := &config.SiteConfigObj{
+		Profiles: config.SiteConfigObjProfiles{WLAN: []string{"corp"}},
+		WLANOverrides: map[string]map[string]any{
+			"corp":  {"vlan_id": 240},
+			"guest": {"enabled": false},
+		},
+	}
+	result := &LintResult{}
+	linter.validateWLANReferences(site, result)
+
+	if len(result.Errors) != 0 {
+		t.Errorf("overrides should only warn, got errors %v", result.Errors)
+	}
+	if len(result.Warnings) != 2 {
+		t.Fatalf("warnings = %+v, want 2", result.Warnings)
+	}
+	if w := result.Warnings[0]; w.Field != "wlan_overrides.corp" || !contains(w.Message, "vlan_id") {
+		t.Errorf("corp warning = %+v", w)
+	}
+	if w := result.Warnings[1]; w.Field != "wlan_overrides.guest" || !contains(w.Message, "no effect") {
+		t.Errorf("guest warning = %+v", w)
+	}
+}
@@ -0,0 +1,83 @@
+package validation
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Duplicate name scopes.
+const (
+	DuplicateScopeSite = "site" // every device sharing the name is at one site
+	DuplicateScopeOrg  = "org"  // the name is shared across sites (or APIs)
+)
+
+// NamedDevice is a device considered for duplicate-name detection.
+type NamedDevice struct {
+	MAC       string `json:"mac"`
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	Site      string `json:"site,omitempty"`
+	API       string `json:"api,omitempty"`
+	Suggested string `json:"suggested,omitempty"` // proposed rename; empty for the device that keeps the name
+}
+
+// DuplicateName is a name held by more than one device.
+type DuplicateName struct {
+	Name    string        `json:"name"`
+	Scope   string        `json:"scope"`
+	Devices []NamedDevice `json:"devices"`
+}
+
+// FindDuplicateNames returns every non-empty name held by two or more
+// devices, sorted by name. Name lookups (GetDeviceByName, device arguments
+// given by name) resolve to only one of them, so each group suggests a
+// numeric suffix for all but the first device by site and MAC, skipping
+// names already in use.
+func FindDuplicateNames(devices []NamedDevice) []DuplicateName {
+	byName := make(map[string][]NamedDevice)
+	taken := make(map[string]bool)
+	for _, d := range devices {
+		if d.Name == "" {
+			continue
+		}
+		byName[d.Name] = append(byName[d.Name], d)
+		taken[d.Name] = true
+	}
+
+	names := make([]string, 0, len(byName))
+	for name, group := range byName {
+		if len(group) > 1 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	dups := make([]DuplicateName, 0, len(names))
+	for _, name := range names {
+		group := byName[name]
+		sort.Slice(group, func(i, j int) bool {
+			if group[i].Site != group[j].Site {
+				return group[i].Site < group[j].Site
+			}
+			return group[i].MAC < group[j].MAC
+		})
+		scope := DuplicateScopeSite
+		next := 2
+		for i := range group {
+			if group[i].Site != group[0].Site || group[i].API != group[0].API {
+				scope = DuplicateScopeOrg
+			}
+			if i == 0 {
+				continue
+			}
+			for taken[fmt.Sprintf("%s-%d", name, next)] {
+				next++
+			}
+			group[i].Suggested = fmt.Sprintf("%s-%d", name, next)
+			taken[group[i].Suggested] = true
+			next++
+		}
+		dups = append(dups, DuplicateName{Name: name, Scope: scope, Devices: group})
+	}
+	return dups
+}
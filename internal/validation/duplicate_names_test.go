@@ -0,0 +1,71 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+func TestFindDuplicateNames(t *testing.T) {
+	devices := []NamedDevice{
+		{MAC: "aa0000000003", Name: "ap-lobby", Type: "ap", Site: "US-LAB-01", API: "mist"},
+		{MAC: "aa0000000001", Name: "ap-lobby", Type: "ap", Site: "US-LAB-01", API: "mist"},
+		{MAC: "aa0000000002", Name: "ap-lobby-2", Type: "ap", Site: "US-LAB-01", API: "mist"},
+		{MAC: "aa0000000004", Name: "core", Type: "switch", Site: "US-HQ-01", API: "mist"},
+		{MAC: "aa0000000005", Name: "core", Type: "switch", Site: "US-LAB-01", API: "mist"},
+		{MAC: "aa0000000006", Name: "", Type: "ap", Site: "US-LAB-01", API: "mist"},
+		{MAC: "aa0000000007", Name: "", Type: "ap", Site: "US-LAB-01", API: "mist"},
+		{MAC: "aa0000000008", Name: "unique", Type: "ap", Site: "US-LAB-01", API: "mist"},
+	}
+
+	dups := FindDuplicateNames(devices)
+	if len(dups) != 2 {
+		t.Fatalf("got %d duplicate names, want 2 (empty names ignored): %+v", len(dups), dups)
+	}
+
+	lobby := dups[0]
+	if lobby.Name != "ap-lobby" || lobby.Scope != DuplicateScopeSite {
+		t.Errorf("first group = %q scope %q, want ap-lobby in site scope", lobby.Name, lobby.Scope)
+	}
+	if lobby.Devices[0].MAC != "aa0000000001" || lobby.Devices[0].Suggested != "" {
+		t.Errorf("lowest MAC should keep the name, got %+v", lobby.Devices[0])
+	}
+	if got := lobby.Devices[1].Suggested; got != "ap-lobby-3" {
+		t.Errorf("suggested = %q, want ap-lobby-3 (ap-lobby-2 is taken)", got)
+	}
+
+	core := dups[1]
+	if core.Scope != DuplicateScopeOrg {
+		t.Errorf("core scope = %q, want org", core.Scope)
+	}
+	if core.Devices[0].Site != "US-HQ-01" || core.Devices[1].Suggested != "core-2" {
+		t.Errorf("core devices = %+v", core.Devices)
+	}
+}
+
+func TestFindDuplicateNames_None(t *testing.T) {
+	dups := FindDuplicateNames([]NamedDevice{{MAC: "aa0000000001", Name: "a"}, {MAC: "aa0000000002", Name: "b"}})
+	if dups == nil || len(dups) != 0 {
+		t.Errorf("want an empty, non-nil result, got %#v", dups)
+	}
+}
+
+func TestLintSite_DuplicateNames(t *testing.T) {
+	siteConfig := &config.SiteConfigObj{}
+	siteConfig.Devices.APs = map[string]config.APConfig{
+		"aa0000000001": {APDeviceConfig: &vendors.APDeviceConfig{Name: "ap-1"}},
+		"aa0000000002": {APDeviceConfig: &vendors.APDeviceConfig{Name: "ap-1"}},
+	}
+
+	result := &LintResult{}
+	NewConfigLinter(nil).validateDuplicateNames("US-LAB-01", siteConfig, result)
+	if len(result.Errors) != 1 {
+		t.Fatalf("got %d errors, want 1: %+v", len(result.Errors), result.Errors)
+	}
+	issue := result.Errors[0]
+	if issue.DeviceMAC != "aa0000000002" || !strings.Contains(issue.Suggestion, "ap-1-2") {
+		t.Errorf("issue = %+v", issue)
+	}
+}
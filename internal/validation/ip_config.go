@@ -0,0 +1,278 @@
+package validation
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ValidateIPConfig checks a device ip_config block. A static config needs an
+// address, a netmask, and a gateway inside the resulting subnet; DNS servers
+// must be IP addresses; vlan_id must be a valid VLAN. The IPv6 fields are
+// checked by ValidateIPv6Config. A wrong static IP can strand a device, so
+// these are errors rather than warnings.
+func ValidateIPConfig(ipConfig map[string]any) []LintIssue {
+	var issues []LintIssue
+	add := func(field, msg, suggestion string) {
+		issues = append(issues, LintIssue{Field: "ip_config." + field, Message: msg, Suggestion: suggestion})
+	}
+
+	ipType, _ := ipConfig["type"].(string)
+	switch ipType {
+	case "":
+		// Type comes from a template or the vendor default (dhcp)
+	case "dhcp":
+		for _, k := range []string{"ip", "netmask", "gateway"} {
+			if _, ok := ipConfig[k]; ok {
+				add(k, fmt.Sprintf("%s is ignored when type is dhcp", k), `Set "type": "static" or remove the field`)
+			}
+		}
+	case "static":
+		ip := parseIPField(ipConfig, "ip", add)
+		mask := parseNetmask(ipConfig, add)
+		gw := parseIPField(ipConfig, "gateway", add)
+		if ip != nil && mask != nil && gw != nil {
+			subnet := &net.IPNet{IP: ip.Mask(mask), Mask: mask}
+			if !subnet.Contains(gw) {
+				add("gateway", fmt.Sprintf("gateway %s is outside %s", gw, subnet), "Check the netmask and gateway")
+			}
+			if ip.Equal(gw) {
+				add("ip", "ip and gateway are the same address", "")
+			}
+			if ip.Equal(subnet.IP) {
+				add("ip", fmt.Sprintf("ip %s is the network address of %s", ip, subnet), "")
+			}
+		}
+	default:
+		add("type", fmt.Sprintf("invalid type %q", ipType), `Use "dhcp" or "static"`)
+	}
+
+	if raw, ok := ipConfig["dns"]; ok {
+		list, ok := raw.([]any)
+		if !ok {
+			add("dns", "dns must be a list of IP addresses", "")
+		}
+		for _, v := range list {
+			if s, _ := v.(string); net.ParseIP(s) == nil {
+				add("dns", fmt.Sprintf("invalid DNS server %v", v), "")
+			}
+		}
+	}
+
+	if raw, ok := ipConfig["vlan_id"]; ok {
+		if id, ok := raw.(float64); !ok || id < 0 || id > 4094 || id != float64(int(id)) {
+			add("vlan_id", fmt.Sprintf("invalid vlan_id %v", raw), "Use 0 (untagged) through 4094")
+		}
+	}
+
+	return append(issues, ValidateIPv6Config(ipConfig, "ip_config.")...)
+}
+
+// ValidateIPv6Config checks the IPv6 half of an ip_config block, naming
+// fields under prefix. A static type6 needs ip6 and netmask6; gateway6, when
+// set, must be link-local or inside the resulting prefix. Without type6 the
+// fields present are still checked for form.
+func ValidateIPv6Config(ipConfig map[string]any, prefix string) []LintIssue {
+	var issues []LintIssue
+	add := func(field, msg, suggestion string) {
+		issues = append(issues, LintIssue{Field: prefix + field, Message: msg, Suggestion: suggestion})
+	}
+
+	type6, _ := ipConfig["type6"].(string)
+	switch type6 {
+	case "", "static":
+	case "autoconf", "dhcp", "disabled":
+		for _, k := range []string{"ip6", "netmask6", "gateway6"} {
+			if _, ok := ipConfig[k]; ok {
+				add(k, fmt.Sprintf("%s is ignored when type6 is %s", k, type6), `Set "type6": "static" or remove the field`)
+			}
+		}
+		return issues
+	default:
+		add("type6", fmt.Sprintf("invalid type6 %q", type6), `Use "autoconf", "dhcp", "static", or "disabled"`)
+		return issues
+	}
+
+	static := type6 == "static"
+	var addr netip.Addr
+	if s, _ := ipConfig["ip6"].(string); s != "" {
+		if a, err := parseIPv6(s); err != nil {
+			add("ip6", err.Error(), "")
+		} else if a.IsLinkLocalUnicast() || a.IsMulticast() || a.IsUnspecified() {
+			add("ip6", fmt.Sprintf("ip6 %s is not a global or unique local address", a), "")
+		} else {
+			addr = a
+		}
+	} else if static {
+		add("ip6", "ip6 is required when type6 is static", "")
+	}
+
+	bits := -1
+	if raw, ok := ipConfig["netmask6"]; ok {
+		if n, err := parsePrefixLen6(raw); err != nil {
+			add("netmask6", err.Error(), `Use a prefix length, e.g. "/64"`)
+		} else {
+			bits = n
+		}
+	} else if static {
+		add("netmask6", "netmask6 is required when type6 is static", `e.g. "/64"`)
+	}
+
+	if s, _ := ipConfig["gateway6"].(string); s != "" {
+		gw, err := parseIPv6(s)
+		switch {
+		case err != nil:
+			add("gateway6", err.Error(), "")
+		case addr.IsValid() && gw == addr:
+			add("ip6", "ip6 and gateway6 are the same address", "")
+		case addr.IsValid() && bits >= 0 && !gw.IsLinkLocalUnicast():
+			if p := netip.PrefixFrom(addr, bits).Masked(); !p.Contains(gw) {
+				add("gateway6", fmt.Sprintf("gateway6 %s is outside %s", gw, p), "Use a link-local gateway or check netmask6")
+			}
+		}
+	}
+	return issues
+}
+
+// ValidateGatewayIPv6 checks the IPv6 settings of a gateway config: the
+// ip_config of each port_config entry and the subnet6 and gateway6 of each
+// network.
+func ValidateGatewayIPv6(cfg map[string]any) []LintIssue {
+	var issues []LintIssue
+
+	ports, _ := cfg["port_config"].(map[string]any)
+	names := make([]string, 0, len(ports))
+	for name := range ports {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		port, _ := ports[name].(map[string]any)
+		if ipc, ok := port["ip_config"].(map[string]any); ok {
+			issues = append(issues, ValidateIPv6Config(ipc, "port_config."+name+".ip_config.")...)
+		}
+	}
+
+	networks, _ := cfg["networks"].([]any)
+	for i, raw := range networks {
+		network, _ := raw.(map[string]any)
+		label := strconv.Itoa(i)
+		if name, _ := network["name"].(string); name != "" {
+			label = name
+		}
+		issues = append(issues, validateNetworkIPv6(network, "networks."+label+".")...)
+	}
+	return issues
+}
+
+// validateNetworkIPv6 checks a network's subnet6 and gateway6.
+func validateNetworkIPv6(network map[string]any, prefix string) []LintIssue {
+	var issues []LintIssue
+	add := func(field, msg, suggestion string) {
+		issues = append(issues, LintIssue{Field: prefix + field, Message: msg, Suggestion: suggestion})
+	}
+
+	var subnet netip.Prefix
+	if s, _ := network["subnet6"].(string); s != "" {
+		if p, err := ParseIPv6Prefix(s); err != nil {
+			add("subnet6", err.Error(), `e.g. "2001:db8:10::/64"`)
+		} else {
+			subnet = p
+		}
+	}
+	if s, _ := network["gateway6"].(string); s != "" {
+		gw, err := parseIPv6(s)
+		switch {
+		case err != nil:
+			add("gateway6", err.Error(), "")
+		case !subnet.IsValid():
+			add("gateway6", "gateway6 is set without subnet6", "Add the network's subnet6")
+		case !subnet.Contains(gw):
+			add("gateway6", fmt.Sprintf("gateway6 %s is outside %s", gw, subnet), "")
+		case gw == subnet.Addr():
+			add("gateway6", fmt.Sprintf("gateway6 %s is the subnet-router anycast address of %s", gw, subnet), "")
+		}
+	}
+	return issues
+}
+
+// ParseIPv6Prefix parses an IPv6 prefix in CIDR form. A prefix with host bits
+// set is an error that names the network it was probably meant to be.
+func ParseIPv6Prefix(s string) (netip.Prefix, error) {
+	p, err := netip.ParsePrefix(s)
+	if err != nil || !p.Addr().Is6() || p.Addr().Is4In6() {
+		return netip.Prefix{}, fmt.Errorf("invalid IPv6 prefix %q", s)
+	}
+	if m := p.Masked(); m != p {
+		return netip.Prefix{}, fmt.Errorf("prefix %s has host bits set; the network is %s", p, m)
+	}
+	return p, nil
+}
+
+// parseIPv6 parses an IPv6 address, rejecting IPv4 and zoned addresses.
+func parseIPv6(s string) (netip.Addr, error) {
+	a, err := netip.ParseAddr(s)
+	if err != nil || !a.Is6() || a.Is4In6() || a.Zone() != "" {
+		return netip.Addr{}, fmt.Errorf("invalid IPv6 address %q", s)
+	}
+	return a, nil
+}
+
+// parsePrefixLen6 reads an IPv6 prefix length written "/64", "64", or as a
+// number.
+func parsePrefixLen6(raw any) (int, error) {
+	var n int
+	switch v := raw.(type) {
+	case float64:
+		n = int(v)
+		if v != float64(n) {
+			return 0, fmt.Errorf("invalid netmask6 %v", raw)
+		}
+	case string:
+		var err error
+		if n, err = strconv.Atoi(strings.TrimPrefix(v, "/")); err != nil {
+			return 0, fmt.Errorf("invalid netmask6 %q", v)
+		}
+	default:
+		return 0, fmt.Errorf("invalid netmask6 %v", raw)
+	}
+	if n < 1 || n > 128 {
+		return 0, fmt.Errorf("netmask6 /%d is out of range", n)
+	}
+	return n, nil
+}
+
+func parseIPField(ipConfig map[string]any, field string, add func(field, msg, suggestion string)) net.IP {
+	s, _ := ipConfig[field].(string)
+	if s == "" {
+		add(field, fmt.Sprintf("%s is required for a static ip_config", field), "")
+		return nil
+	}
+	ip := net.ParseIP(s).To4()
+	if ip == nil {
+		add(field, fmt.Sprintf("invalid IPv4 address %q", s), "")
+	}
+	return ip
+}
+
+func parseNetmask(ipConfig map[string]any, add func(field, msg, suggestion string)) net.IPMask {
+	s, _ := ipConfig["netmask"].(string)
+	if s == "" {
+		add("netmask", "netmask is required for a static ip_config", "")
+		return nil
+	}
+	ip := net.ParseIP(s).To4()
+	if ip == nil {
+		add("netmask", fmt.Sprintf("invalid netmask %q", s), `Use dotted form, e.g. "255.255.255.0"`)
+		return nil
+	}
+	mask := net.IPMask(ip)
+	if ones, bits := mask.Size(); bits == 0 || ones == 0 {
+		add("netmask", fmt.Sprintf("invalid netmask %q", s), `Use dotted form, e.g. "255.255.255.0"`)
+		return nil
+	}
+	return mask
+}
@@ -0,0 +1,132 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateIPConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		config map[string]any
+		want   []string // substrings of expected messages, in order
+	}{
+		{"dhcp", map[string]any{"type": "dhcp", "vlan_id": float64(100)}, nil},
+		{"type from template", map[string]any{"vlan_id": float64(10)}, nil},
+		{"valid static", map[string]any{
+			"type": "static", "ip": "10.0.1.10", "netmask": "255.255.255.0",
+			"gateway": "10.0.1.1", "dns": []any{"10.0.0.53"},
+		}, nil},
+		{"static missing fields", map[string]any{"type": "static", "ip": "10.0.1.10"}, []string{
+			"netmask is required", "gateway is required",
+		}},
+		{"gateway outside subnet", map[string]any{
+			"type": "static", "ip": "10.0.1.10", "netmask": "255.255.255.0", "gateway": "10.0.2.1",
+		}, []string{"outside 10.0.1.0/24"}},
+		{"network address", map[string]any{
+			"type": "static", "ip": "10.0.1.0", "netmask": "255.255.255.0", "gateway": "10.0.1.1",
+		}, []string{"network address"}},
+		{"bad values", map[string]any{
+			"type": "static", "ip": "10.0.1.300", "netmask": "255.0.255.0", "gateway": "10.0.1.1",
+			"dns": []any{"dns.example.com"}, "vlan_id": float64(5000),
+		}, []string{"invalid IPv4 address", "invalid netmask", "invalid DNS server", "invalid vlan_id"}},
+		{"dhcp with address", map[string]any{"type": "dhcp", "ip": "10.0.1.10"}, []string{"ignored when type is dhcp"}},
+		{"unknown type", map[string]any{"type": "bootp"}, []string{"invalid type"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := ValidateIPConfig(tt.config)
+			if len(issues) != len(tt.want) {
+				t.Fatalf("got %d issues %+v, want %d", len(issues), issues, len(tt.want))
+			}
+			for i, w := range tt.want {
+				if !strings.Contains(issues[i].Message, w) {
+					t.Errorf("issue %d: %q does not contain %q", i, issues[i].Message, w)
+				}
+				if !strings.HasPrefix(issues[i].Field, "ip_config.") {
+					t.Errorf("issue %d: field %q should be under ip_config", i, issues[i].Field)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateIPv6Config(t *testing.T) {
+	tests := []struct {
+		name   string
+		config map[string]any
+		want   []string
+	}{
+		{"autoconf", map[string]any{"type6": "autoconf"}, nil},
+		{"no v6", map[string]any{"type": "dhcp"}, nil},
+		{"valid static", map[string]any{
+			"type6": "static", "ip6": "2001:db8:10::20", "netmask6": "/64", "gateway6": "2001:db8:10::1",
+		}, nil},
+		{"link-local gateway", map[string]any{
+			"type6": "static", "ip6": "2001:db8:10::20", "netmask6": "64", "gateway6": "fe80::1",
+		}, nil},
+		{"static missing fields", map[string]any{"type6": "static"}, []string{
+			"ip6 is required", "netmask6 is required",
+		}},
+		{"gateway outside prefix", map[string]any{
+			"type6": "static", "ip6": "2001:db8:10::20", "netmask6": "/64", "gateway6": "2001:db8:11::1",
+		}, []string{"outside 2001:db8:10::/64"}},
+		{"bad values", map[string]any{
+			"type6": "static", "ip6": "10.0.1.10", "netmask6": "/129", "gateway6": "fe80::1%eth0",
+		}, []string{"invalid IPv6 address", "out of range", "invalid IPv6 address"}},
+		{"link-local address", map[string]any{"type6": "static", "ip6": "fe80::20", "netmask6": "/64"}, []string{
+			"not a global or unique local",
+		}},
+		{"autoconf with address", map[string]any{"type6": "autoconf", "ip6": "2001:db8::20"}, []string{"ignored when type6 is autoconf"}},
+		{"unknown type6", map[string]any{"type6": "slaac"}, []string{"invalid type6"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := ValidateIPConfig(tt.config)
+			if len(issues) != len(tt.want) {
+				t.Fatalf("got %d issues %+v, want %d", len(issues), issues, len(tt.want))
+			}
+			for i, w := range tt.want {
+				if !strings.Contains(issues[i].Message, w) {
+					t.Errorf("issue %d: %q does not contain %q", i, issues[i].Message, w)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateGatewayIPv6(t *testing.T) {
+	cfg := map[string]any{
+		"port_config": map[string]any{
+			"ge-0/0/0": map[string]any{"ip_config": map[string]any{"type6": "dhcp"}},
+			"ge-0/0/1": map[string]any{"ip_config": map[string]any{"type6": "static", "ip6": "2001:db8::2"}},
+		},
+		"networks": []any{
+			map[string]any{"name": "corp", "subnet6": "2001:db8:10::/64", "gateway6": "2001:db8:10::1"},
+			map[string]any{"name": "guest", "subnet6": "2001:db8:20::1/64"},
+			map[string]any{"name": "iot", "gateway6": "2001:db8:30::1"},
+		},
+	}
+	var fields []string
+	for _, issue := range ValidateGatewayIPv6(cfg) {
+		fields = append(fields, issue.Field)
+	}
+	want := []string{"port_config.ge-0/0/1.ip_config.netmask6", "networks.guest.subnet6", "networks.iot.gateway6"}
+	if strings.Join(fields, ",") != strings.Join(want, ",") {
+		t.Errorf("issues on %v, want %v", fields, want)
+	}
+}
+
+func TestParseIPv6Prefix(t *testing.T) {
+	if _, err := ParseIPv6Prefix("2001:db8:10::/64"); err != nil {
+		t.Errorf("valid prefix: %v", err)
+	}
+	if _, err := ParseIPv6Prefix("2001:db8:10::1/64"); err == nil || !strings.Contains(err.Error(), "2001:db8:10::/64") {
+		t.Errorf("host bits: err = %v, want the network named", err)
+	}
+	for _, s := range []string{"10.0.0.0/8", "2001:db8::", "::ffff:10.0.0.0/104"} {
+		if _, err := ParseIPv6Prefix(s); err == nil {
+			t.Errorf("ParseIPv6Prefix(%q) should fail", s)
+		}
+	}
+}
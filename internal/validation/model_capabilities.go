@@ -0,0 +1,66 @@
+package validation
+
+import (
+	"fmt"
+
+	"github.com/ravinald/wifimgr/internal/models"
+)
+
+// bandNames are the radio_config band keys in display form.
+var bandNames = map[string]string{
+	models.Band24: "2.4 GHz",
+	models.Band5:  "5 GHz",
+	models.Band6:  "6 GHz",
+}
+
+// validateModelBands checks radio_config against the model's radios from
+// the models database: settings for a band the model has no radio for are
+// ignored by the AP, which usually means intent was written for another
+// model. Unknown models are not checked.
+func (v *RadioValidator) validateModelBands(rc map[string]any) []LintIssue {
+	model, ok := models.Lookup(v.deviceModel)
+	if !ok || model.Type != "ap" {
+		return nil
+	}
+
+	var issues []LintIssue
+	for _, band := range []string{models.Band24, models.Band5, models.Band6} {
+		settings, ok := rc[band].(map[string]any)
+		if !ok || bandDisabled(settings) || model.SupportsBand(band) {
+			continue
+		}
+		issues = append(issues, LintIssue{
+			Field:      "radio_config." + band,
+			Message:    fmt.Sprintf("%s has no %s radio; these settings have no effect", modelLabel(model), bandNames[band]),
+			Suggestion: fmt.Sprintf("Remove radio_config.%s or set disabled: true", band),
+		})
+	}
+
+	if dual, ok := rc["band_dual"].(map[string]any); ok {
+		if mode, ok := getIntValue(dual, "radio_mode"); ok {
+			band := GetBandForRadioMode(mode)
+			if band != "" && !model.SupportsBand(band) {
+				issues = append(issues, LintIssue{
+					Field:      "radio_config.band_dual.radio_mode",
+					Message:    fmt.Sprintf("radio_mode %d needs a %s radio, which %s does not have", mode, bandNames[band], modelLabel(model)),
+					Suggestion: "Choose a radio_mode for a band the model supports",
+				})
+			}
+		}
+	}
+	return issues
+}
+
+// bandDisabled reports whether a band's settings turn the band off.
+func bandDisabled(settings map[string]any) bool {
+	disabled, _ := settings["disabled"].(bool)
+	return disabled
+}
+
+// modelLabel names a model with its Wi-Fi generation, e.g. "AP43 (Wi-Fi 6)".
+func modelLabel(m *models.Model) string {
+	if m.WiFi == "" {
+		return m.Name
+	}
+	return fmt.Sprintf("%s (Wi-Fi %s)", m.Name, m.WiFi)
+}
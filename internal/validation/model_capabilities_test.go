@@ -0,0 +1,36 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateModelBands(t *testing.T) {
+	rc := map[string]any{
+		"band_5":    map[string]any{"channel": 36},
+		"band_6":    map[string]any{"channel": 37},
+		"band_dual": map[string]any{"radio_mode": 6},
+	}
+
+	issues := NewRadioValidator("mist", "AP43-US").validateModelBands(rc)
+	if len(issues) != 2 {
+		t.Fatalf("AP43: got %d issues, want 2: %+v", len(issues), issues)
+	}
+	if issues[0].Field != "radio_config.band_6" || !strings.Contains(issues[0].Message, "AP43 (Wi-Fi 6) has no 6 GHz radio") {
+		t.Errorf("band_6 issue = %+v", issues[0])
+	}
+	if issues[1].Field != "radio_config.band_dual.radio_mode" {
+		t.Errorf("band_dual issue = %+v", issues[1])
+	}
+
+	if issues := NewRadioValidator("mist", "AP45").validateModelBands(rc); len(issues) != 0 {
+		t.Errorf("AP45 (6E): got %+v, want none", issues)
+	}
+	if issues := NewRadioValidator("mist", "unknown-model").validateModelBands(rc); len(issues) != 0 {
+		t.Errorf("unknown model: got %+v, want none", issues)
+	}
+	disabled := map[string]any{"band_6": map[string]any{"disabled": true}}
+	if issues := NewRadioValidator("mist", "AP43").validateModelBands(disabled); len(issues) != 0 {
+		t.Errorf("disabled band_6: got %+v, want none", issues)
+	}
+}
@@ -9,6 +9,7 @@ import (
 type RadioValidator struct {
 	deviceModel string
 	vendor      string
+	countryCode string // site country_code; enables 6 GHz regulatory checks
 }
 
 // NewRadioValidator creates a new radio validator.
@@ -19,6 +20,13 @@ func NewRadioValidator(vendor, deviceModel string) *RadioValidator {
 	}
 }
 
+// WithCountryCode sets the site country code the 6 GHz settings are checked
+// against, and returns the validator.
+func (v *RadioValidator) WithCountryCode(countryCode string) *RadioValidator {
+	v.countryCode = countryCode
+	return v
+}
+
 // ValidateRadioConfig validates the entire radio_config block.
 func (v *RadioValidator) ValidateRadioConfig(rc map[string]any) []LintIssue {
 	if rc == nil {
@@ -58,6 +66,12 @@ func (v *RadioValidator) ValidateRadioConfig(rc map[string]any) []LintIssue {
 		}
 	}
 
+	if v.countryCode != "" {
+		issues = append(issues, ValidateRadioRegulatory(rc, v.countryCode)...)
+	}
+
+	issues = append(issues, v.validateModelBands(rc)...)
+
 	return issues
 }
 
@@ -202,6 +216,47 @@ func (v *RadioValidator) validateBandWithRules(fieldPrefix string, band map[stri
 		}
 	}
 
+	// Validate power limits
+	powerMin, hasMin := getIntValue(band, "power_min")
+	powerMax, hasMax := getIntValue(band, "power_max")
+	for _, f := range []struct {
+		name  string
+		value int
+		set   bool
+	}{{"power_min", powerMin, hasMin}, {"power_max", powerMax, hasMax}} {
+		if f.set && !IsValidPower(f.value) {
+			issues = append(issues, LintIssue{
+				Field:      prefix + "." + f.name,
+				Message:    fmt.Sprintf("%s %d is out of range [%d-%d] dBm", f.name, f.value, PowerRange.Min, PowerRange.Max),
+				Suggestion: fmt.Sprintf("Set %s between %d and %d dBm", f.name, PowerRange.Min, PowerRange.Max),
+			})
+		}
+	}
+	if hasMin && hasMax && powerMin > powerMax {
+		issues = append(issues, LintIssue{
+			Field:      prefix + ".power_min",
+			Message:    fmt.Sprintf("power_min %d is above power_max %d", powerMin, powerMax),
+			Suggestion: "Set power_min at or below power_max",
+		})
+	}
+
+	// Validate the allowed channel list
+	if channels, ok := band["channels"].([]any); ok {
+		var invalid []int
+		for _, c := range channels {
+			if ch, ok := toInt(c); ok && !IsValidChannel(bandType, ch) {
+				invalid = append(invalid, ch)
+			}
+		}
+		if len(invalid) > 0 {
+			issues = append(issues, LintIssue{
+				Field:      prefix + ".channels",
+				Message:    fmt.Sprintf("channels %v are not valid for %s", invalid, bandType),
+				Suggestion: fmt.Sprintf("Valid channels: %v (first 10 shown)", truncateSlice(GetValidChannels(bandType), 10)),
+			})
+		}
+	}
+
 	return issues
 }
 
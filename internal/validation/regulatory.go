@@ -0,0 +1,281 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ChannelRange is a run of channels a regulatory domain permits on one band,
+// with the transmit power ceiling (EIRP, dBm) that applies to them.
+type ChannelRange struct {
+	First    int
+	Last     int
+	MaxPower int
+}
+
+// RegulatoryDomain is the shipped radio rules of one regulatory domain: the
+// channels each band may use and the power ceiling on them. The table is a
+// summary for catching intent a vendor would reject or an inspector would
+// flag, not a substitute for the vendor's own country tables.
+type RegulatoryDomain struct {
+	Name   string // e.g. "FCC", "ETSI"
+	Band24 []ChannelRange
+	Band5  []ChannelRange
+	Band6  []ChannelRange // low-power indoor; empty when 6 GHz is not permitted
+	AFC    bool           // 6 GHz standard-power operation under an AFC system permitted
+}
+
+var (
+	domainFCC = RegulatoryDomain{
+		Name:   "FCC",
+		Band24: []ChannelRange{{1, 11, 30}},
+		Band5:  []ChannelRange{{36, 48, 30}, {52, 64, 24}, {100, 144, 24}, {149, 177, 30}},
+		Band6:  []ChannelRange{{1, 233, 30}},
+		AFC:    true,
+	}
+	domainISED = RegulatoryDomain{
+		Name:   "ISED",
+		Band24: []ChannelRange{{1, 11, 30}},
+		Band5:  []ChannelRange{{36, 48, 23}, {52, 64, 24}, {100, 144, 24}, {149, 165, 30}},
+		Band6:  []ChannelRange{{1, 233, 30}},
+		AFC:    true,
+	}
+	domainETSI = RegulatoryDomain{
+		Name:   "ETSI",
+		Band24: []ChannelRange{{1, 13, 20}},
+		Band5:  []ChannelRange{{36, 48, 23}, {52, 64, 23}, {100, 140, 30}},
+		Band6:  []ChannelRange{{1, 93, 23}},
+	}
+	domainOfcom = RegulatoryDomain{
+		Name:   "Ofcom",
+		Band24: []ChannelRange{{1, 13, 20}},
+		Band5:  []ChannelRange{{36, 48, 23}, {52, 64, 23}, {100, 140, 30}, {149, 165, 23}},
+		Band6:  []ChannelRange{{1, 93, 24}},
+	}
+	domainMIC = RegulatoryDomain{
+		Name:   "MIC",
+		Band24: []ChannelRange{{1, 13, 20}},
+		Band5:  []ChannelRange{{36, 48, 23}, {52, 64, 23}, {100, 144, 23}},
+		Band6:  []ChannelRange{{1, 93, 23}},
+	}
+	domainACMA = RegulatoryDomain{
+		Name:   "ACMA",
+		Band24: []ChannelRange{{1, 13, 30}},
+		Band5:  []ChannelRange{{36, 48, 23}, {52, 64, 23}, {100, 116, 30}, {132, 144, 30}, {149, 165, 30}},
+		Band6:  []ChannelRange{{1, 93, 24}},
+	}
+	domainMSIT = RegulatoryDomain{
+		Name:   "MSIT",
+		Band24: []ChannelRange{{1, 13, 23}},
+		Band5:  []ChannelRange{{36, 48, 23}, {52, 64, 23}, {100, 144, 23}, {149, 165, 23}},
+		Band6:  []ChannelRange{{1, 233, 24}},
+	}
+	domainAnatel = RegulatoryDomain{
+		Name:   "Anatel",
+		Band24: []ChannelRange{{1, 13, 30}},
+		Band5:  []ChannelRange{{36, 48, 23}, {52, 64, 23}, {100, 140, 30}, {149, 165, 30}},
+		Band6:  []ChannelRange{{1, 233, 30}},
+	}
+	domainSRRC = RegulatoryDomain{
+		Name:   "SRRC",
+		Band24: []ChannelRange{{1, 13, 20}},
+		Band5:  []ChannelRange{{36, 48, 23}, {52, 64, 23}, {149, 165, 30}},
+	}
+)
+
+// regulatoryByCountry maps ISO 3166-1 alpha-2 country codes to their domain.
+// Countries not listed are not checked.
+var regulatoryByCountry = map[string]RegulatoryDomain{
+	"US": domainFCC, "PR": domainFCC, "GU": domainFCC, "VI": domainFCC,
+	"CA": domainISED,
+	"GB": domainOfcom,
+	"JP": domainMIC,
+	"AU": domainACMA, "NZ": domainACMA,
+	"KR": domainMSIT,
+	"BR": domainAnatel,
+	"CN": domainSRRC,
+	"AT": domainETSI, "BE": domainETSI, "BG": domainETSI, "CH": domainETSI, "CY": domainETSI,
+	"CZ": domainETSI, "DE": domainETSI, "DK": domainETSI, "EE": domainETSI, "ES": domainETSI,
+	"FI": domainETSI, "FR": domainETSI, "GR": domainETSI, "HR": domainETSI, "HU": domainETSI,
+	"IE": domainETSI, "IS": domainETSI, "IT": domainETSI, "LI": domainETSI, "LT": domainETSI,
+	"LU": domainETSI, "LV": domainETSI, "MT": domainETSI, "NL": domainETSI, "NO": domainETSI,
+	"PL": domainETSI, "PT": domainETSI, "RO": domainETSI, "SE": domainETSI, "SI": domainETSI,
+	"SK": domainETSI,
+}
+
+// RegulatoryDomainFor returns the regulatory domain for a site country code,
+// and false when the country is not in the table.
+func RegulatoryDomainFor(countryCode string) (RegulatoryDomain, bool) {
+	domain, ok := regulatoryByCountry[strings.ToUpper(strings.TrimSpace(countryCode))]
+	return domain, ok
+}
+
+// ValidateRadioRegulatory checks the channels and power levels of a
+// radio_config block against the regulatory domain of countryCode: every band,
+// the Mist 5 GHz-on-2.4 radio, and a band_dual radio in the band its
+// radio_mode selects. Countries without an entry are not checked.
+func ValidateRadioRegulatory(rc map[string]any, countryCode string) []LintIssue {
+	domain, ok := RegulatoryDomainFor(countryCode)
+	if !ok || rc == nil {
+		return nil
+	}
+	check := regulatoryCheck{country: strings.ToUpper(strings.TrimSpace(countryCode)), domain: domain}
+
+	var issues []LintIssue
+	if band, ok := rc["band_24"].(map[string]any); ok {
+		issues = append(issues, check.band("radio_config.band_24", band, "band_24")...)
+	}
+	if band, ok := rc["band_5"].(map[string]any); ok {
+		issues = append(issues, check.band("radio_config.band_5", band, "band_5")...)
+	}
+	if band, ok := rc["band_5_on_24_radio"].(map[string]any); ok {
+		issues = append(issues, check.band("radio_config.band_5_on_24_radio", band, "band_5")...)
+	}
+	if band, ok := rc["band_6"].(map[string]any); ok {
+		issues = append(issues, check.band("radio_config.band_6", band, "band_6")...)
+	}
+	if band, ok := rc["band_dual"].(map[string]any); ok {
+		if mode, ok := getIntValue(band, "radio_mode"); ok {
+			if bandType := GetBandForRadioMode(mode); bandType != "" {
+				issues = append(issues, check.band("radio_config.band_dual", band, bandType)...)
+			}
+		}
+	}
+	return issues
+}
+
+// regulatoryCheck validates bands against one country's domain.
+type regulatoryCheck struct {
+	country string
+	domain  RegulatoryDomain
+}
+
+// bandLabels names the bands in messages.
+var bandLabels = map[string]string{"band_24": "2.4 GHz", "band_5": "5 GHz", "band_6": "6 GHz"}
+
+// band checks one radio's channel, preferred channels and power against the
+// ranges the domain permits on bandType.
+func (c regulatoryCheck) band(prefix string, band map[string]any, bandType string) []LintIssue {
+	if disabled, _ := band["disabled"].(bool); disabled {
+		return nil
+	}
+	label := bandLabels[bandType]
+	ranges := c.ranges(bandType)
+	if len(ranges) == 0 {
+		return []LintIssue{{
+			Field:      prefix,
+			Message:    fmt.Sprintf("%s operation is not permitted in %s (%s)", label, c.country, c.domain.Name),
+			Suggestion: "Set disabled: true",
+		}}
+	}
+
+	var issues []LintIssue
+	afc := false
+	if bandType == "band_6" {
+		afc, _ = band["standard_power"].(bool)
+		if afc && !c.domain.AFC {
+			issues = append(issues, LintIssue{
+				Field:      prefix + ".standard_power",
+				Message:    fmt.Sprintf("AFC standard-power operation is not permitted in %s (%s)", c.country, c.domain.Name),
+				Suggestion: "Remove standard_power or set it to false",
+			})
+			afc = false
+		}
+	}
+
+	// The power ceiling is the channel's when one is pinned, else the band's highest.
+	limit := 0
+	for _, r := range ranges {
+		limit = max(limit, r.MaxPower)
+	}
+	if channel, ok := getIntValue(band, "channel"); ok && channel != 0 {
+		if r, ok := findChannelRange(ranges, channel); ok {
+			limit = r.MaxPower
+		} else {
+			issues = append(issues, LintIssue{
+				Field:      prefix + ".channel",
+				Message:    fmt.Sprintf("%s channel %d is not permitted in %s (%s)", label, channel, c.country, c.domain.Name),
+				Suggestion: fmt.Sprintf("Use a channel in %s", describeRanges(ranges)),
+			})
+		}
+	}
+	if channels, ok := band["channels"].([]any); ok {
+		var outside []int
+		for _, v := range channels {
+			if ch, ok := toInt(v); ok {
+				if _, ok := findChannelRange(ranges, ch); !ok {
+					outside = append(outside, ch)
+				}
+			}
+		}
+		if len(outside) > 0 {
+			issues = append(issues, LintIssue{
+				Field:      prefix + ".channels",
+				Message:    fmt.Sprintf("%s channels %v are not permitted in %s (%s)", label, outside, c.country, c.domain.Name),
+				Suggestion: fmt.Sprintf("Keep channels in %s", describeRanges(ranges)),
+			})
+		}
+	}
+
+	// Standard power under AFC lifts the low-power indoor ceiling.
+	if afc {
+		return issues
+	}
+	for _, field := range []string{"power", "power_max"} {
+		if power, ok := getIntValue(band, field); ok && power > limit {
+			issues = append(issues, LintIssue{
+				Field:      prefix + "." + field,
+				Message:    fmt.Sprintf("%s %d dBm exceeds the %d dBm %s limit in %s (%s)", field, power, limit, label, c.country, c.domain.Name),
+				Suggestion: fmt.Sprintf("Set %s to %d or less", field, limit),
+			})
+		}
+	}
+	return issues
+}
+
+// ranges returns the domain's permitted channel ranges for bandType.
+func (c regulatoryCheck) ranges(bandType string) []ChannelRange {
+	switch bandType {
+	case "band_24":
+		return c.domain.Band24
+	case "band_5":
+		return c.domain.Band5
+	case "band_6":
+		return c.domain.Band6
+	default:
+		return nil
+	}
+}
+
+// findChannelRange returns the range holding channel.
+func findChannelRange(ranges []ChannelRange, channel int) (ChannelRange, bool) {
+	for _, r := range ranges {
+		if channel >= r.First && channel <= r.Last {
+			return r, true
+		}
+	}
+	return ChannelRange{}, false
+}
+
+// describeRanges renders ranges for a suggestion, e.g. "36-48, 52-64".
+func describeRanges(ranges []ChannelRange) string {
+	parts := make([]string, len(ranges))
+	for i, r := range ranges {
+		parts[i] = fmt.Sprintf("%d-%d", r.First, r.Last)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// toInt converts a JSON number to int.
+func toInt(v any) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case float64:
+		return int(n), true
+	case int64:
+		return int(n), true
+	default:
+		return 0, false
+	}
+}
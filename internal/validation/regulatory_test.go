@@ -0,0 +1,182 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRegulatoryDomainFor(t *testing.T) {
+	domain, ok := RegulatoryDomainFor(" us ")
+	if !ok || domain.Name != "FCC" || !domain.AFC {
+		t.Errorf("US: got %+v, %v", domain, ok)
+	}
+	domain, ok = RegulatoryDomainFor("FR")
+	if !ok || domain.Name != "ETSI" || domain.AFC || domain.Band6[len(domain.Band6)-1].Last != 93 {
+		t.Errorf("FR: got %+v, %v", domain, ok)
+	}
+	if _, ok := RegulatoryDomainFor("ZZ"); ok {
+		t.Error("unknown country should not have a domain")
+	}
+}
+
+func TestValidateRadioRegulatory(t *testing.T) {
+	tests := []struct {
+		name       string
+		country    string
+		config     map[string]any
+		wantFields []string
+	}{
+		{
+			name:    "AFC with full power in the US",
+			country: "US",
+			config: map[string]any{"band_6": map[string]any{
+				"standard_power": true, "power_max": float64(30), "channels": []any{float64(37), float64(197)},
+			}},
+		},
+		{
+			name:    "AFC not permitted in the EU",
+			country: "DE",
+			config: map[string]any{"band_6": map[string]any{
+				"standard_power": true, "power": float64(20),
+			}},
+			wantFields: []string{"radio_config.band_6.standard_power"},
+		},
+		{
+			name:    "low-power indoor limit without AFC",
+			country: "DE",
+			config: map[string]any{"band_6": map[string]any{
+				"power": float64(25), "power_max": float64(24),
+			}},
+			wantFields: []string{"radio_config.band_6.power", "radio_config.band_6.power_max"},
+		},
+		{
+			name:    "AFC not permitted does not lift the power limit",
+			country: "GB",
+			config: map[string]any{"band_6": map[string]any{
+				"standard_power": true, "power_max": float64(28),
+			}},
+			wantFields: []string{"radio_config.band_6.standard_power", "radio_config.band_6.power_max"},
+		},
+		{
+			name:    "upper band channels outside ETSI",
+			country: "FR",
+			config: map[string]any{"band_6": map[string]any{
+				"channel": float64(149), "channels": []any{float64(37), float64(101)},
+			}},
+			wantFields: []string{"radio_config.band_6.channel", "radio_config.band_6.channels"},
+		},
+		{
+			name:       "6 GHz not permitted",
+			country:    "CN",
+			config:     map[string]any{"band_6": map[string]any{"channel": float64(37)}},
+			wantFields: []string{"radio_config.band_6"},
+		},
+		{
+			name:    "disabled radio is not checked",
+			country: "CN",
+			config:  map[string]any{"band_6": map[string]any{"disabled": true, "channel": float64(37)}},
+		},
+		{
+			name:    "flex radio in 6 GHz mode",
+			country: "JP",
+			config: map[string]any{"band_dual": map[string]any{
+				"radio_mode": float64(6), "channel": float64(165),
+			}},
+			wantFields: []string{"radio_config.band_dual.channel"},
+		},
+		{
+			name:    "flex radio in 5 GHz mode",
+			country: "JP",
+			config: map[string]any{"band_dual": map[string]any{
+				"radio_mode": float64(5), "channel": float64(36),
+			}},
+		},
+		{
+			name:    "2.4 GHz channel 13 outside the US",
+			country: "US",
+			config: map[string]any{
+				"band_24": map[string]any{"channel": float64(13)},
+			},
+			wantFields: []string{"radio_config.band_24.channel"},
+		},
+		{
+			name:    "2.4 GHz channel 13 and 5 GHz DFS in the EU",
+			country: "DE",
+			config: map[string]any{
+				"band_24": map[string]any{"channel": float64(13), "power": float64(20)},
+				"band_5":  map[string]any{"channel": float64(100), "power": float64(30)},
+			},
+		},
+		{
+			name:    "5 GHz UNII-3 not permitted in the EU",
+			country: "DE",
+			config: map[string]any{
+				"band_5": map[string]any{"channel": float64(149), "channels": []any{float64(36), float64(161)}},
+			},
+			wantFields: []string{"radio_config.band_5.channel", "radio_config.band_5.channels"},
+		},
+		{
+			name:    "power ceiling follows the pinned channel",
+			country: "US",
+			config: map[string]any{
+				"band_5": map[string]any{"channel": float64(52), "power": float64(28)},
+			},
+			wantFields: []string{"radio_config.band_5.power"},
+		},
+		{
+			name:    "auto channel uses the band's highest ceiling",
+			country: "US",
+			config: map[string]any{
+				"band_5": map[string]any{"channel": float64(0), "power": float64(28)},
+			},
+		},
+		{
+			name:    "Mist 5 GHz on the 2.4 GHz radio",
+			country: "JP",
+			config: map[string]any{
+				"band_5_on_24_radio": map[string]any{"channel": float64(149)},
+			},
+			wantFields: []string{"radio_config.band_5_on_24_radio.channel"},
+		},
+		{
+			name:    "country not in the table",
+			country: "",
+			config:  map[string]any{"band_6": map[string]any{"standard_power": true}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := ValidateRadioRegulatory(tt.config, tt.country)
+			var fields []string
+			for _, issue := range issues {
+				fields = append(fields, issue.Field)
+			}
+			if strings.Join(fields, ",") != strings.Join(tt.wantFields, ",") {
+				t.Errorf("got issues on %v, want %v: %v", fields, tt.wantFields, issues)
+			}
+		})
+	}
+}
+
+func TestRadioValidator_PowerLimitsAndChannels(t *testing.T) {
+	issues := NewRadioValidator("mist", "").ValidateRadioConfig(map[string]any{
+		"band_6": map[string]any{
+			"power_min": float64(20),
+			"power_max": float64(10),
+			"channels":  []any{float64(37), float64(38)},
+		},
+	})
+	if len(issues) != 2 {
+		t.Fatalf("want power_min above power_max and invalid channel 38, got %v", issues)
+	}
+
+	// The regulatory check only runs with a country code
+	rc := map[string]any{"band_6": map[string]any{"standard_power": true}}
+	if issues := NewRadioValidator("mist", "").ValidateRadioConfig(rc); len(issues) != 0 {
+		t.Errorf("no country: got %v", issues)
+	}
+	if issues := NewRadioValidator("mist", "").WithCountryCode("GB").ValidateRadioConfig(rc); len(issues) != 1 {
+		t.Errorf("GB: want 1 issue, got %v", issues)
+	}
+}
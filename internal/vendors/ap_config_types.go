@@ -127,7 +127,8 @@ type RadioBandConfig struct {
 	AntennaMode     *string  `json:"antenna_mode,omitempty"`
 	AntGain         *float64 `json:"ant_gain,omitempty"`
 	AllowRRMDisable *bool    `json:"allow_rrm_disable,omitempty"`
-	Preamble        *string  `json:"preamble,omitempty"` // "short", "long", "auto"
+	Preamble        *string  `json:"preamble,omitempty"`       // "short", "long", "auto"
+	StandardPower   *bool    `json:"standard_power,omitempty"` // 6 GHz AFC standard-power operation
 
 	// Vendor extensions
 	Mist   map[string]any `json:"mist,omitempty"`
@@ -145,6 +146,13 @@ type IPConfig struct {
 	VlanID    *int     `json:"vlan_id,omitempty"`
 	Mtu       *int     `json:"mtu,omitempty"`
 
+	// IPv6, alongside IPv4 (dual stack). autoconf takes the address and
+	// gateway from router advertisements (SLAAC).
+	Type6    *string `json:"type6,omitempty"`    // "autoconf", "dhcp", "static", "disabled"
+	IP6      *string `json:"ip6,omitempty"`      // static only
+	Netmask6 *string `json:"netmask6,omitempty"` // prefix length, e.g. "/64"
+	Gateway6 *string `json:"gateway6,omitempty"`
+
 	// Vendor extensions
 	Mist   map[string]any `json:"mist,omitempty"`
 	Meraki map[string]any `json:"meraki,omitempty"`
@@ -281,7 +289,8 @@ type LEDConfig struct {
 }
 
 // Validate checks APDeviceConfig for configuration errors.
-// Returns an error if mutually exclusive fields are both set.
+// Returns an error if mutually exclusive fields are both set or the floor-plan
+// placement is incomplete.
 func (c *APDeviceConfig) Validate() error {
 	if c == nil {
 		return nil
@@ -302,6 +311,10 @@ func (c *APDeviceConfig) Validate() error {
 		}
 	}
 
+	if err := c.validatePlacement(); err != nil {
+		return err
+	}
+
 	// Validate nested configs
 	if c.RadioConfig != nil {
 		if err := c.RadioConfig.Validate(); err != nil {
@@ -335,6 +348,10 @@ func (c *APDeviceConfig) ValidateForVendor(vendor string) []error {
 		})
 	}
 
+	if err := c.validatePlacement(); err != nil {
+		errors = append(errors, err)
+	}
+
 	// Vendor-specific field validation
 	if vendor == "meraki" {
 		if c.RadioConfig != nil {
@@ -622,6 +639,9 @@ func (c *RadioBandConfig) ToMap() map[string]any {
 	if c.Preamble != nil {
 		result["preamble"] = *c.Preamble
 	}
+	if c.StandardPower != nil {
+		result["standard_power"] = *c.StandardPower
+	}
 
 	// Merge Mist extensions
 	if len(c.Mist) > 0 {
@@ -665,6 +685,18 @@ func (c *IPConfig) ToMap() map[string]any {
 	if c.Mtu != nil {
 		result["mtu"] = *c.Mtu
 	}
+	if c.Type6 != nil {
+		result["type6"] = *c.Type6
+	}
+	if c.IP6 != nil {
+		result["ip6"] = *c.IP6
+	}
+	if c.Netmask6 != nil {
+		result["netmask6"] = *c.Netmask6
+	}
+	if c.Gateway6 != nil {
+		result["gateway6"] = *c.Gateway6
+	}
 
 	// Merge extensions
 	if len(c.Mist) > 0 {
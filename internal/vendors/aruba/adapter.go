@@ -51,10 +51,17 @@ func (a *Adapter) Statuses() vendors.StatusesService {
 // Unsupported services. Instant's device-local API exposes no org inventory
 // claim, client search, device profiles, org templates, BSSID listing, or the
 // per-client band supplement Meraki needs.
-func (a *Adapter) Search() vendors.SearchService             { return nil }
-func (a *Adapter) Profiles() vendors.ProfilesService         { return nil }
-func (a *Adapter) Templates() vendors.TemplatesService       { return nil }
-func (a *Adapter) BSSIDs() vendors.BSSIDsService             { return nil }
-func (a *Adapter) ClientDetail() vendors.ClientDetailService { return nil }
+func (a *Adapter) Search() vendors.SearchService                 { return nil }
+func (a *Adapter) Profiles() vendors.ProfilesService             { return nil }
+func (a *Adapter) Templates() vendors.TemplatesService           { return nil }
+func (a *Adapter) BSSIDs() vendors.BSSIDsService                 { return nil }
+func (a *Adapter) ClientDetail() vendors.ClientDetailService     { return nil }
+func (a *Adapter) DeviceStats() vendors.DeviceStatsService       { return nil }
+func (a *Adapter) Maps() vendors.MapsService                     { return nil }
+func (a *Adapter) ClientEvents() vendors.ClientEventsService     { return nil }
+func (a *Adapter) DeviceEvents() vendors.DeviceEventsService     { return nil }
+func (a *Adapter) Firmware() vendors.FirmwareService             { return nil }
+func (a *Adapter) Usage() vendors.UsageService                   { return nil }
+func (a *Adapter) SecurityEvents() vendors.SecurityEventsService { return nil }
 
 var _ vendors.Client = (*Adapter)(nil)
@@ -26,6 +26,7 @@ import (
 	"time"
 
 	"github.com/ravinald/wifimgr/internal/logging"
+	"github.com/ravinald/wifimgr/internal/runstats"
 	"github.com/ravinald/wifimgr/internal/vendors"
 )
 
@@ -132,6 +133,7 @@ func NewClient(user, passwd, baseURL string, opts ...ClientOption) *Client {
 	for _, opt := range opts {
 		opt(c)
 	}
+	c.httpClient = runstats.InstrumentClient(c.httpClient)
 
 	return c
 }
@@ -1,9 +1,12 @@
 package vendors
 
 import (
+	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/ravinald/wifimgr/internal/logging"
+	"github.com/ravinald/wifimgr/internal/runstats"
 )
 
 // Global cache accessor for cross-package access
@@ -34,8 +37,21 @@ type CacheAccessor struct {
 	manager *CacheManager
 	indexes *CacheIndexes
 	mu      sync.RWMutex
+
+	// scope is how much of the caches the indexes hold; see LoadSite. sites
+	// lists the sites loaded under scopeSites (site ID -> API label).
+	scope atomic.Int32
+	sites map[string]string
 }
 
+// Index scopes. The zero value is scopeFull, so an accessor built by
+// NewCacheAccessor behaves as it always has.
+const (
+	scopeFull  int32 = iota // every API cache indexed
+	scopeNone               // nothing loaded yet; the first lookup loads everything
+	scopeSites              // every API's core plus the sites passed to LoadSite
+)
+
 // CacheIndexes holds pre-built indexes for O(1) lookups across all APIs.
 type CacheIndexes struct {
 	// Sites - aggregated from all APIs
@@ -84,6 +100,87 @@ func NewCacheAccessor(manager *CacheManager) *CacheAccessor {
 	return ca
 }
 
+// NewLazyCacheAccessor creates a cache accessor that reads nothing until it is
+// used. The first lookup indexes every API cache, as NewCacheAccessor does,
+// unless LoadSite narrowed the accessor to one site first.
+func NewLazyCacheAccessor(manager *CacheManager) *CacheAccessor {
+	ca := &CacheAccessor{
+		manager: manager,
+		indexes: newCacheIndexes(),
+	}
+	ca.scope.Store(scopeNone)
+	return ca
+}
+
+// ensureIndexed loads every API cache if nothing has been loaded yet. Every
+// lookup calls it before taking the read lock.
+func (ca *CacheAccessor) ensureIndexed() {
+	if ca.scope.Load() == scopeNone {
+		ca.RebuildIndexes()
+	}
+}
+
+// LoadSite narrows a lazy accessor to one site so a single-site operation
+// doesn't deserialize the whole org. It indexes every API's core cache
+// (sites, inventory, templates, profiles, org WLANs) and the per-site data of
+// each site whose ID or name matches nameOrID; all same-named sites are
+// loaded, so duplicate-name checks still see them. Lookups of another site's
+// configs, status, or BSSIDs then miss. Further calls add sites. On an
+// accessor that already holds everything, LoadSite does nothing.
+func (ca *CacheAccessor) LoadSite(nameOrID string) {
+	ca.mu.Lock()
+	defer ca.mu.Unlock()
+
+	if ca.scope.Load() == scopeFull || ca.manager == nil || ca.manager.registry == nil {
+		return
+	}
+	if ca.scope.Load() == scopeNone {
+		ca.sites = make(map[string]string)
+		ca.indexScopedLocked()
+		ca.scope.Store(scopeSites)
+	}
+
+	for _, site := range ca.indexes.SitesByID {
+		if site.ID != nameOrID && !strings.EqualFold(site.Name, nameOrID) {
+			continue
+		}
+		if _, loaded := ca.sites[site.ID]; loaded {
+			continue
+		}
+		ca.sites[site.ID] = site.SourceAPI
+		ca.indexSiteLocked(site.SourceAPI, site.ID)
+	}
+}
+
+// indexScopedLocked indexes every API's core cache and the sites already
+// loaded. The caller holds mu for writing.
+func (ca *CacheAccessor) indexScopedLocked() {
+	ca.indexes = newCacheIndexes()
+	for _, apiLabel := range ca.manager.registry.GetAllLabels() {
+		core, err := ca.manager.GetCoreCache(apiLabel)
+		if err != nil {
+			logging.Debugf("[cache-accessor] Failed to load core cache for %s: %v", apiLabel, err)
+			continue
+		}
+		ca.indexAPICache(core, apiLabel)
+	}
+	for siteID, apiLabel := range ca.sites {
+		ca.indexSiteLocked(apiLabel, siteID)
+	}
+}
+
+// indexSiteLocked indexes one site's per-site data. The caller holds mu for
+// writing.
+func (ca *CacheAccessor) indexSiteLocked(apiLabel, siteID string) {
+	site, err := ca.manager.GetSiteCache(apiLabel, siteID)
+	if err != nil {
+		logging.Debugf("[cache-accessor] Failed to load site %s from %s: %v", siteID, apiLabel, err)
+		return
+	}
+	ca.indexAPICache(site, apiLabel)
+	logging.Debugf("[cache-accessor] Loaded site %s from %s", siteID, apiLabel)
+}
+
 // newCacheIndexes creates empty cache indexes.
 func newCacheIndexes() *CacheIndexes {
 	return &CacheIndexes{
@@ -109,12 +206,18 @@ func newCacheIndexes() *CacheIndexes {
 	}
 }
 
-// RebuildIndexes rebuilds all indexes from the cache files.
+// RebuildIndexes rebuilds all indexes from the cache files. An accessor
+// narrowed by LoadSite stays narrowed: its cores and loaded sites are re-read.
 func (ca *CacheAccessor) RebuildIndexes() {
 	ca.mu.Lock()
 	defer ca.mu.Unlock()
 
+	if ca.scope.Load() == scopeSites {
+		ca.indexScopedLocked()
+		return
+	}
 	ca.indexes = newCacheIndexes()
+	ca.scope.Store(scopeFull)
 
 	if ca.manager == nil || ca.manager.registry == nil {
 		return
@@ -290,6 +393,15 @@ func (ca *CacheAccessor) indexAPICache(cache *APICache, apiLabel string) {
 	}
 }
 
+// countLookup records a keyed cache lookup for --stats.
+func countLookup(found bool) {
+	if found {
+		runstats.CacheHit("local cache")
+	} else {
+		runstats.CacheMiss("local cache")
+	}
+}
+
 // GetManager returns the underlying cache manager.
 func (ca *CacheAccessor) GetManager() *CacheManager {
 	return ca.manager
@@ -304,6 +416,7 @@ func (ca *CacheAccessor) IsInitialized() bool {
 
 // GetStats returns statistics about the cached data.
 func (ca *CacheAccessor) GetStats() map[string]int {
+	ca.ensureIndexed()
 	ca.mu.RLock()
 	defer ca.mu.RUnlock()
 
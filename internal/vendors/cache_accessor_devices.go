@@ -6,11 +6,13 @@ import "fmt"
 
 // GetAPConfigByMAC returns an AP config by its MAC address.
 func (ca *CacheAccessor) GetAPConfigByMAC(mac string) (*APConfig, error) {
+	ca.ensureIndexed()
 	ca.mu.RLock()
 	defer ca.mu.RUnlock()
 
 	normalizedMAC := NormalizeMAC(mac)
 	cfg, ok := ca.indexes.APConfigsByMAC[normalizedMAC]
+	countLookup(ok)
 	if !ok {
 		return nil, fmt.Errorf("AP config not found: %s", mac)
 	}
@@ -19,11 +21,13 @@ func (ca *CacheAccessor) GetAPConfigByMAC(mac string) (*APConfig, error) {
 
 // GetSwitchConfigByMAC returns a switch config by its MAC address.
 func (ca *CacheAccessor) GetSwitchConfigByMAC(mac string) (*SwitchConfig, error) {
+	ca.ensureIndexed()
 	ca.mu.RLock()
 	defer ca.mu.RUnlock()
 
 	normalizedMAC := NormalizeMAC(mac)
 	cfg, ok := ca.indexes.SwitchConfigsByMAC[normalizedMAC]
+	countLookup(ok)
 	if !ok {
 		return nil, fmt.Errorf("switch config not found: %s", mac)
 	}
@@ -32,11 +36,13 @@ func (ca *CacheAccessor) GetSwitchConfigByMAC(mac string) (*SwitchConfig, error)
 
 // GetGatewayConfigByMAC returns a gateway config by its MAC address.
 func (ca *CacheAccessor) GetGatewayConfigByMAC(mac string) (*GatewayConfig, error) {
+	ca.ensureIndexed()
 	ca.mu.RLock()
 	defer ca.mu.RUnlock()
 
 	normalizedMAC := NormalizeMAC(mac)
 	cfg, ok := ca.indexes.GatewayConfigsByMAC[normalizedMAC]
+	countLookup(ok)
 	if !ok {
 		return nil, fmt.Errorf("gateway config not found: %s", mac)
 	}
@@ -45,6 +51,7 @@ func (ca *CacheAccessor) GetGatewayConfigByMAC(mac string) (*GatewayConfig, erro
 
 // GetAllAPConfigs returns all AP configs from all APIs.
 func (ca *CacheAccessor) GetAllAPConfigs() []*APConfig {
+	ca.ensureIndexed()
 	ca.mu.RLock()
 	defer ca.mu.RUnlock()
 
@@ -57,6 +64,7 @@ func (ca *CacheAccessor) GetAllAPConfigs() []*APConfig {
 
 // GetAllSwitchConfigs returns all switch configs from all APIs.
 func (ca *CacheAccessor) GetAllSwitchConfigs() []*SwitchConfig {
+	ca.ensureIndexed()
 	ca.mu.RLock()
 	defer ca.mu.RUnlock()
 
@@ -69,6 +77,7 @@ func (ca *CacheAccessor) GetAllSwitchConfigs() []*SwitchConfig {
 
 // GetAllGatewayConfigs returns all gateway configs from all APIs.
 func (ca *CacheAccessor) GetAllGatewayConfigs() []*GatewayConfig {
+	ca.ensureIndexed()
 	ca.mu.RLock()
 	defer ca.mu.RUnlock()
 
@@ -83,11 +92,13 @@ func (ca *CacheAccessor) GetAllGatewayConfigs() []*GatewayConfig {
 
 // GetDeviceStatus returns device status by MAC address.
 func (ca *CacheAccessor) GetDeviceStatus(mac string) (*DeviceStatus, error) {
+	ca.ensureIndexed()
 	ca.mu.RLock()
 	defer ca.mu.RUnlock()
 
 	normalizedMAC := NormalizeMAC(mac)
 	status, ok := ca.indexes.DeviceStatusByMAC[normalizedMAC]
+	countLookup(ok)
 	if !ok {
 		return nil, fmt.Errorf("device status not found: %s", mac)
 	}
@@ -98,10 +109,12 @@ func (ca *CacheAccessor) GetDeviceStatus(mac string) (*DeviceStatus, error) {
 
 // GetWLANByID returns a WLAN by its ID.
 func (ca *CacheAccessor) GetWLANByID(id string) (*WLAN, error) {
+	ca.ensureIndexed()
 	ca.mu.RLock()
 	defer ca.mu.RUnlock()
 
 	wlan, ok := ca.indexes.WLANsByID[id]
+	countLookup(ok)
 	if !ok {
 		return nil, fmt.Errorf("WLAN not found: %s", id)
 	}
@@ -111,10 +124,12 @@ func (ca *CacheAccessor) GetWLANByID(id string) (*WLAN, error) {
 // GetWLANsBySSID returns all WLANs with the given SSID name.
 // Multiple WLANs can share the same SSID (different sites/networks).
 func (ca *CacheAccessor) GetWLANsBySSID(ssid string) []*WLAN {
+	ca.ensureIndexed()
 	ca.mu.RLock()
 	defer ca.mu.RUnlock()
 
 	wlans, ok := ca.indexes.WLANsBySSID[ssid]
+	countLookup(ok)
 	if !ok {
 		return nil
 	}
@@ -126,6 +141,7 @@ func (ca *CacheAccessor) GetWLANsBySSID(ssid string) []*WLAN {
 
 // GetAllWLANs returns all WLANs from all APIs.
 func (ca *CacheAccessor) GetAllWLANs() []*WLAN {
+	ca.ensureIndexed()
 	ca.mu.RLock()
 	defer ca.mu.RUnlock()
 
@@ -138,6 +154,7 @@ func (ca *CacheAccessor) GetAllWLANs() []*WLAN {
 
 // GetWLANsBySite returns all WLANs for a specific site/network.
 func (ca *CacheAccessor) GetWLANsBySite(siteID string) []*WLAN {
+	ca.ensureIndexed()
 	ca.mu.RLock()
 	defer ca.mu.RUnlock()
 
@@ -152,6 +169,7 @@ func (ca *CacheAccessor) GetWLANsBySite(siteID string) []*WLAN {
 
 // GetWLANsByVendor returns all WLANs from a specific vendor.
 func (ca *CacheAccessor) GetWLANsByVendor(vendor string) []*WLAN {
+	ca.ensureIndexed()
 	ca.mu.RLock()
 	defer ca.mu.RUnlock()
 
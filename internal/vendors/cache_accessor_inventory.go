@@ -6,11 +6,13 @@ import "fmt"
 
 // GetDeviceByMAC returns an inventory item by its MAC address.
 func (ca *CacheAccessor) GetDeviceByMAC(mac string) (*InventoryItem, error) {
+	ca.ensureIndexed()
 	ca.mu.RLock()
 	defer ca.mu.RUnlock()
 
 	normalizedMAC := NormalizeMAC(mac)
 	item, ok := ca.indexes.DevicesByMAC[normalizedMAC]
+	countLookup(ok)
 	if !ok {
 		return nil, fmt.Errorf("device not found: %s", mac)
 	}
@@ -19,10 +21,12 @@ func (ca *CacheAccessor) GetDeviceByMAC(mac string) (*InventoryItem, error) {
 
 // GetDeviceByName returns an inventory item by its name.
 func (ca *CacheAccessor) GetDeviceByName(name string) (*InventoryItem, error) {
+	ca.ensureIndexed()
 	ca.mu.RLock()
 	defer ca.mu.RUnlock()
 
 	item, ok := ca.indexes.DevicesByName[name]
+	countLookup(ok)
 	if !ok {
 		return nil, fmt.Errorf("device not found: %s", name)
 	}
@@ -31,6 +35,7 @@ func (ca *CacheAccessor) GetDeviceByName(name string) (*InventoryItem, error) {
 
 // GetAllDevices returns all inventory items from all APIs.
 func (ca *CacheAccessor) GetAllDevices() []*InventoryItem {
+	ca.ensureIndexed()
 	ca.mu.RLock()
 	defer ca.mu.RUnlock()
 
@@ -81,6 +86,7 @@ func (ca *CacheAccessor) GetGatewayByMAC(mac string) (*InventoryItem, error) {
 
 // GetAllAPs returns all AP inventory items from all APIs.
 func (ca *CacheAccessor) GetAllAPs() []*InventoryItem {
+	ca.ensureIndexed()
 	ca.mu.RLock()
 	defer ca.mu.RUnlock()
 
@@ -95,6 +101,7 @@ func (ca *CacheAccessor) GetAllAPs() []*InventoryItem {
 
 // GetAllSwitches returns all switch inventory items from all APIs.
 func (ca *CacheAccessor) GetAllSwitches() []*InventoryItem {
+	ca.ensureIndexed()
 	ca.mu.RLock()
 	defer ca.mu.RUnlock()
 
@@ -109,6 +116,7 @@ func (ca *CacheAccessor) GetAllSwitches() []*InventoryItem {
 
 // GetAllGateways returns all gateway inventory items from all APIs.
 func (ca *CacheAccessor) GetAllGateways() []*InventoryItem {
+	ca.ensureIndexed()
 	ca.mu.RLock()
 	defer ca.mu.RUnlock()
 
@@ -124,6 +132,7 @@ func (ca *CacheAccessor) GetAllGateways() []*InventoryItem {
 // GetDevicesBySite returns all inventory items for a specific site and device type.
 // deviceType can be "ap", "switch", "gateway", or empty for all types.
 func (ca *CacheAccessor) GetDevicesBySite(siteID string, deviceType string) []*InventoryItem {
+	ca.ensureIndexed()
 	ca.mu.RLock()
 	defer ca.mu.RUnlock()
 
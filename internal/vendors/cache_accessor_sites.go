@@ -6,10 +6,12 @@ import "fmt"
 
 // GetSiteByID returns a site by its ID.
 func (ca *CacheAccessor) GetSiteByID(id string) (*SiteInfo, error) {
+	ca.ensureIndexed()
 	ca.mu.RLock()
 	defer ca.mu.RUnlock()
 
 	site, ok := ca.indexes.SitesByID[id]
+	countLookup(ok)
 	if !ok {
 		return nil, fmt.Errorf("site not found: %s", id)
 	}
@@ -20,10 +22,12 @@ func (ca *CacheAccessor) GetSiteByID(id string) (*SiteInfo, error) {
 // includes up to three "did you mean?" suggestions within 3 edits of the
 // requested name.
 func (ca *CacheAccessor) GetSiteByName(name string) (*SiteInfo, error) {
+	ca.ensureIndexed()
 	ca.mu.RLock()
 	defer ca.mu.RUnlock()
 
 	site, ok := ca.indexes.SitesByName[name]
+	countLookup(ok)
 	if !ok {
 		candidates := make([]string, 0, len(ca.indexes.SitesByName))
 		for n := range ca.indexes.SitesByName {
@@ -39,11 +43,13 @@ func (ca *CacheAccessor) GetSiteByName(name string) (*SiteInfo, error) {
 // miss (either name or API mismatch) the error includes "did you mean?"
 // suggestions from the requested API's sites.
 func (ca *CacheAccessor) GetSiteByNameAndAPI(name, apiLabel string) (*SiteInfo, error) {
+	ca.ensureIndexed()
 	ca.mu.RLock()
 	defer ca.mu.RUnlock()
 
 	// First check if the site exists at all
 	site, ok := ca.indexes.SitesByName[name]
+	countLookup(ok)
 	if !ok {
 		candidates := make([]string, 0, len(ca.indexes.SitesByName))
 		for n, s := range ca.indexes.SitesByName {
@@ -65,6 +71,7 @@ func (ca *CacheAccessor) GetSiteByNameAndAPI(name, apiLabel string) (*SiteInfo,
 
 // GetAllSites returns all sites from all APIs.
 func (ca *CacheAccessor) GetAllSites() []*SiteInfo {
+	ca.ensureIndexed()
 	ca.mu.RLock()
 	defer ca.mu.RUnlock()
 
@@ -91,10 +98,12 @@ func (ca *CacheAccessor) GetNetworkByName(name string) (*SiteInfo, error) {
 
 // GetRFTemplateByID returns an RF template by its ID.
 func (ca *CacheAccessor) GetRFTemplateByID(id string) (*RFTemplate, error) {
+	ca.ensureIndexed()
 	ca.mu.RLock()
 	defer ca.mu.RUnlock()
 
 	tmpl, ok := ca.indexes.RFTemplatesByID[id]
+	countLookup(ok)
 	if !ok {
 		return nil, fmt.Errorf("RF template not found: %s", id)
 	}
@@ -103,10 +112,12 @@ func (ca *CacheAccessor) GetRFTemplateByID(id string) (*RFTemplate, error) {
 
 // GetRFTemplateByName returns an RF template by its name.
 func (ca *CacheAccessor) GetRFTemplateByName(name string) (*RFTemplate, error) {
+	ca.ensureIndexed()
 	ca.mu.RLock()
 	defer ca.mu.RUnlock()
 
 	tmpl, ok := ca.indexes.RFTemplatesByName[name]
+	countLookup(ok)
 	if !ok {
 		return nil, fmt.Errorf("RF template not found: %s", name)
 	}
@@ -115,6 +126,7 @@ func (ca *CacheAccessor) GetRFTemplateByName(name string) (*RFTemplate, error) {
 
 // GetAllRFTemplates returns all RF templates from all APIs.
 func (ca *CacheAccessor) GetAllRFTemplates() []*RFTemplate {
+	ca.ensureIndexed()
 	ca.mu.RLock()
 	defer ca.mu.RUnlock()
 
@@ -127,10 +139,12 @@ func (ca *CacheAccessor) GetAllRFTemplates() []*RFTemplate {
 
 // GetGWTemplateByID returns a Gateway template by its ID.
 func (ca *CacheAccessor) GetGWTemplateByID(id string) (*GatewayTemplate, error) {
+	ca.ensureIndexed()
 	ca.mu.RLock()
 	defer ca.mu.RUnlock()
 
 	tmpl, ok := ca.indexes.GWTemplatesByID[id]
+	countLookup(ok)
 	if !ok {
 		return nil, fmt.Errorf("gateway template not found: %s", id)
 	}
@@ -139,10 +153,12 @@ func (ca *CacheAccessor) GetGWTemplateByID(id string) (*GatewayTemplate, error)
 
 // GetGWTemplateByName returns a Gateway template by its name.
 func (ca *CacheAccessor) GetGWTemplateByName(name string) (*GatewayTemplate, error) {
+	ca.ensureIndexed()
 	ca.mu.RLock()
 	defer ca.mu.RUnlock()
 
 	tmpl, ok := ca.indexes.GWTemplatesByName[name]
+	countLookup(ok)
 	if !ok {
 		return nil, fmt.Errorf("gateway template not found: %s", name)
 	}
@@ -151,10 +167,12 @@ func (ca *CacheAccessor) GetGWTemplateByName(name string) (*GatewayTemplate, err
 
 // GetWLANTemplateByID returns a WLAN template by its ID.
 func (ca *CacheAccessor) GetWLANTemplateByID(id string) (*WLANTemplate, error) {
+	ca.ensureIndexed()
 	ca.mu.RLock()
 	defer ca.mu.RUnlock()
 
 	tmpl, ok := ca.indexes.WLANTemplatesByID[id]
+	countLookup(ok)
 	if !ok {
 		return nil, fmt.Errorf("WLAN template not found: %s", id)
 	}
@@ -163,10 +181,12 @@ func (ca *CacheAccessor) GetWLANTemplateByID(id string) (*WLANTemplate, error) {
 
 // GetWLANTemplateByName returns a WLAN template by its name.
 func (ca *CacheAccessor) GetWLANTemplateByName(name string) (*WLANTemplate, error) {
+	ca.ensureIndexed()
 	ca.mu.RLock()
 	defer ca.mu.RUnlock()
 
 	tmpl, ok := ca.indexes.WLANTemplatesByName[name]
+	countLookup(ok)
 	if !ok {
 		return nil, fmt.Errorf("WLAN template not found: %s", name)
 	}
@@ -177,10 +197,12 @@ func (ca *CacheAccessor) GetWLANTemplateByName(name string) (*WLANTemplate, erro
 
 // GetDeviceProfileByID returns a device profile by its ID.
 func (ca *CacheAccessor) GetDeviceProfileByID(id string) (*DeviceProfile, error) {
+	ca.ensureIndexed()
 	ca.mu.RLock()
 	defer ca.mu.RUnlock()
 
 	profile, ok := ca.indexes.DeviceProfilesByID[id]
+	countLookup(ok)
 	if !ok {
 		return nil, fmt.Errorf("device profile not found: %s", id)
 	}
@@ -189,10 +211,12 @@ func (ca *CacheAccessor) GetDeviceProfileByID(id string) (*DeviceProfile, error)
 
 // GetDeviceProfileByName returns a device profile by its name.
 func (ca *CacheAccessor) GetDeviceProfileByName(name string) (*DeviceProfile, error) {
+	ca.ensureIndexed()
 	ca.mu.RLock()
 	defer ca.mu.RUnlock()
 
 	profile, ok := ca.indexes.DeviceProfilesByName[name]
+	countLookup(ok)
 	if !ok {
 		return nil, fmt.Errorf("device profile not found: %s", name)
 	}
@@ -201,6 +225,7 @@ func (ca *CacheAccessor) GetDeviceProfileByName(name string) (*DeviceProfile, er
 
 // GetAllDeviceProfiles returns all device profiles from all APIs.
 func (ca *CacheAccessor) GetAllDeviceProfiles() []*DeviceProfile {
+	ca.ensureIndexed()
 	ca.mu.RLock()
 	defer ca.mu.RUnlock()
 
@@ -0,0 +1,223 @@
+package vendors
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// LegacyCacheFile is the single-org Mist cache written before per-API caches.
+// Nothing reads it any more, so an upgraded install that still has one sees
+// empty results until it is migrated or refreshed.
+const LegacyCacheFile = "cache.json"
+
+// LegacyMigration reports one migration of the legacy cache.
+type LegacyMigration struct {
+	Path     string // legacy file that was removed
+	APILabel string // API whose cache received the contents
+	OrgID    string // org recorded in the legacy file, if any
+	Sites    int
+	Devices  int
+	Profiles int
+
+	// Converted is false when the API already had a cache of its own; the
+	// legacy contents are older than it and are dropped rather than merged.
+	Converted bool
+}
+
+// legacyCache is the on-disk shape of the legacy cache: Mist API objects
+// keyed by ID, and inventory keyed by device type then MAC.
+type legacyCache struct {
+	Version int    `json:"version"`
+	OrgID   string `json:"org_id"`
+	Cache   struct {
+		Sites          map[string]legacySite              `json:"sites"`
+		Inventory      map[string]map[string]legacyDevice `json:"inventory"`
+		DeviceProfiles map[string]legacyProfile           `json:"device_profiles"`
+	} `json:"cache"`
+}
+
+type legacySite struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Timezone    string `json:"timezone"`
+	Address     string `json:"address"`
+	CountryCode string `json:"country_code"`
+	Notes       string `json:"notes"`
+	Latlng      *struct {
+		Lat float64 `json:"lat"`
+		Lng float64 `json:"lng"`
+	} `json:"latlng"`
+}
+
+type legacyDevice struct {
+	ID     string `json:"id"`
+	MAC    string `json:"mac"`
+	Serial string `json:"serial"`
+	Model  string `json:"model"`
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	SiteID string `json:"site_id"`
+}
+
+type legacyProfile struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	OrgID   string `json:"org_id"`
+	ForSite bool   `json:"for_site"`
+	SiteID  string `json:"site_id"`
+}
+
+// LegacyCachePath returns where the legacy cache lives in this cache directory.
+func (c *CacheManager) LegacyCachePath() string {
+	return filepath.Join(c.cacheDir, LegacyCacheFile)
+}
+
+// ReadLegacyCacheOrgID returns the org recorded in the legacy cache at path,
+// or "" when the file does not record one.
+func ReadLegacyCacheOrgID(path string) (string, error) {
+	legacy, _, err := readLegacyCache(path)
+	if err != nil {
+		return "", err
+	}
+	return legacy.OrgID, nil
+}
+
+// MigrateLegacyCache converts the legacy cache at path into the cache of
+// apiLabel and removes it, along with its .meta file. When apiLabel already
+// has a cache, that cache is newer and is kept; the legacy file is only
+// removed. Migrated objects carry the legacy file's modification time, so
+// freshness checks still see them as old until the next refresh.
+func (c *CacheManager) MigrateLegacyCache(path, apiLabel string) (*LegacyMigration, error) {
+	legacy, modTime, err := readLegacyCache(path)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &LegacyMigration{Path: path, APILabel: apiLabel, OrgID: legacy.OrgID}
+	if !c.CacheExists(apiLabel) {
+		orgID := legacy.OrgID
+		if c.registry != nil {
+			if id, err := c.registry.GetOrgID(apiLabel); err == nil && id != "" {
+				orgID = id
+			}
+		}
+		cache := convertLegacyCache(legacy, apiLabel, orgID, modTime)
+		if err := c.SaveAPICache(cache); err != nil {
+			return nil, fmt.Errorf("failed to save migrated cache: %w", err)
+		}
+		if err := c.RebuildIndex(); err != nil {
+			return nil, err
+		}
+		m.Converted = true
+		m.Sites = len(cache.Sites.Info)
+		m.Devices = len(cache.Inventory.AP) + len(cache.Inventory.Switch) + len(cache.Inventory.Gateway)
+		m.Profiles = len(cache.Profiles.Devices)
+	}
+
+	for _, p := range []string{path, path + ".meta"} {
+		if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+			return m, fmt.Errorf("failed to remove %s: %w", p, err)
+		}
+	}
+	return m, nil
+}
+
+// readLegacyCache parses the legacy cache and returns its modification time.
+func readLegacyCache(path string) (*legacyCache, time.Time, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	data, err := os.ReadFile(path) // #nosec G304 -- path from operator-controlled config
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to read legacy cache: %w", err)
+	}
+	legacy := &legacyCache{}
+	if err := json.Unmarshal(data, legacy); err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to parse legacy cache %s: %w", path, err)
+	}
+	return legacy, info.ModTime().UTC(), nil
+}
+
+// convertLegacyCache builds a per-API cache from the legacy contents, in the
+// order refresh would write them.
+func convertLegacyCache(legacy *legacyCache, apiLabel, orgID string, refreshed time.Time) *APICache {
+	cache := NewAPICache(apiLabel, "mist", orgID)
+	cache.Meta.LastRefresh = refreshed
+	meta := ObjectMeta{RefreshedAt: refreshed}
+
+	for id, s := range legacy.Cache.Sites {
+		site := SiteInfo{
+			ObjectMeta:  meta,
+			ID:          s.ID,
+			Name:        s.Name,
+			Timezone:    s.Timezone,
+			Address:     s.Address,
+			CountryCode: s.CountryCode,
+			Notes:       s.Notes,
+		}
+		if site.ID == "" {
+			site.ID = id
+		}
+		if s.Latlng != nil {
+			site.Latitude, site.Longitude = s.Latlng.Lat, s.Latlng.Lng
+		}
+		cache.Sites.Info = append(cache.Sites.Info, site)
+	}
+	sort.Slice(cache.Sites.Info, func(a, b int) bool { return cache.Sites.Info[a].Name < cache.Sites.Info[b].Name })
+
+	byType := map[string]map[string]*InventoryItem{
+		"ap":      cache.Inventory.AP,
+		"switch":  cache.Inventory.Switch,
+		"gateway": cache.Inventory.Gateway,
+	}
+	for deviceType, devices := range legacy.Cache.Inventory {
+		target, ok := byType[deviceType]
+		if !ok {
+			continue
+		}
+		for key, d := range devices {
+			mac := NormalizeMAC(d.MAC)
+			if mac == "" {
+				mac = NormalizeMAC(key)
+			}
+			if mac == "" {
+				continue
+			}
+			target[mac] = &InventoryItem{
+				ObjectMeta: meta,
+				ID:         d.ID,
+				MAC:        mac,
+				Serial:     d.Serial,
+				Model:      d.Model,
+				Name:       d.Name,
+				Type:       deviceType,
+				SiteID:     d.SiteID,
+			}
+		}
+	}
+
+	for id, p := range legacy.Cache.DeviceProfiles {
+		profile := DeviceProfile{
+			ObjectMeta: meta,
+			ID:         p.ID,
+			Name:       p.Name,
+			Type:       p.Type,
+			OrgID:      p.OrgID,
+			ForSite:    p.ForSite,
+			SiteID:     p.SiteID,
+		}
+		if profile.ID == "" {
+			profile.ID = id
+		}
+		cache.Profiles.Devices = append(cache.Profiles.Devices, profile)
+	}
+	sort.Slice(cache.Profiles.Devices, func(a, b int) bool { return cache.Profiles.Devices[a].Name < cache.Profiles.Devices[b].Name })
+
+	return cache
+}
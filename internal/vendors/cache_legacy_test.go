@@ -0,0 +1,116 @@
+package vendors
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const legacyCacheJSON = `{
+  "version": 1,
+  "org_id": "org-1",
+  "cache": {
+    "sites": {
+      "site-001": {"id": "site-001", "name": "US-LAB-01", "timezone": "America/Los_Angeles", "latlng": {"lat": 37.4, "lng": -122.1}}
+    },
+    "inventory": {
+      "ap": {"aa:bb:cc:dd:ee:01": {"id": "d1", "mac": "aa:bb:cc:dd:ee:01", "model": "AP43", "name": "lab-ap-01", "site_id": "site-001"}},
+      "switch": {"aabbccddee02": {"id": "d2", "model": "EX2300", "name": "lab-sw-01"}},
+      "gateway": {}
+    },
+    "device_profiles": {
+      "dp-1": {"name": "lobby", "type": "ap"}
+    }
+  }
+}`
+
+func TestMigrateLegacyCache(t *testing.T) {
+	cm := NewCacheManager(t.TempDir(), NewAPIClientRegistry())
+	if err := cm.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	path := cm.LegacyCachePath()
+	if err := os.WriteFile(path, []byte(legacyCacheJSON), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path+".meta", []byte("{}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	if orgID, err := ReadLegacyCacheOrgID(path); err != nil || orgID != "org-1" {
+		t.Fatalf("ReadLegacyCacheOrgID = %q, %v", orgID, err)
+	}
+
+	m, err := cm.MigrateLegacyCache(path, "mist-prod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !m.Converted || m.Sites != 1 || m.Devices != 2 || m.Profiles != 1 {
+		t.Errorf("migration = %+v, want 1 site, 2 devices, 1 profile converted", m)
+	}
+	for _, p := range []string{path, path + ".meta"} {
+		if _, err := os.Stat(p); !os.IsNotExist(err) {
+			t.Errorf("%s still exists", p)
+		}
+	}
+
+	cache, err := cm.GetAPICache("mist-prod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cache.Meta.Vendor != "mist" || cache.Meta.OrgID != "org-1" || !cache.Meta.LastRefresh.Equal(old) {
+		t.Errorf("meta = %+v, want mist org-1 refreshed at the legacy file's time", cache.Meta)
+	}
+	if cache.SiteIndex.ByName["US-LAB-01"] != "site-001" || cache.Sites.Info[0].Latitude != 37.4 {
+		t.Errorf("sites = %+v", cache.Sites.Info)
+	}
+	ap := cache.Inventory.AP["aabbccddee01"]
+	if ap == nil || ap.ID != "d1" || ap.Type != "ap" || ap.SiteName != "US-LAB-01" {
+		t.Errorf("ap = %+v", ap)
+	}
+	if sw := cache.Inventory.Switch["aabbccddee02"]; sw == nil || sw.MAC != "aabbccddee02" {
+		t.Errorf("switch keyed by MAC only = %+v", sw)
+	}
+	if len(cache.Profiles.Devices) != 1 || cache.Profiles.Devices[0].ID != "dp-1" {
+		t.Errorf("profiles = %+v", cache.Profiles.Devices)
+	}
+}
+
+func TestMigrateLegacyCacheKeepsNewerCache(t *testing.T) {
+	cm := NewCacheManager(t.TempDir(), NewAPIClientRegistry())
+	if err := cm.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	current := NewAPICache("mist-prod", "mist", "org-1")
+	current.Sites.Info = []SiteInfo{{ID: "site-002", Name: "US-SFO-01"}}
+	if err := cm.SaveAPICache(current); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "cache.json")
+	if err := os.WriteFile(path, []byte(legacyCacheJSON), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := cm.MigrateLegacyCache(path, "mist-prod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Converted {
+		t.Error("legacy contents were converted over an existing cache")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("legacy cache was not removed")
+	}
+	cache, err := cm.GetAPICache("mist-prod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cache.Sites.Info) != 1 || cache.Sites.Info[0].ID != "site-002" {
+		t.Errorf("existing cache changed: %+v", cache.Sites.Info)
+	}
+}
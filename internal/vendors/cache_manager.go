@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"sync"
 	"time"
 
@@ -15,6 +16,10 @@ import (
 	"github.com/ravinald/wifimgr/internal/refreshui"
 )
 
+// cacheLockTimeout bounds how long a save waits for another process holding
+// the same API's cache lock.
+const cacheLockTimeout = 30 * time.Second
+
 // CacheManager manages per-API cache files and the cross-API index.
 type CacheManager struct {
 	cacheDir string
@@ -35,7 +40,7 @@ type CacheManager struct {
 	secretPw     string
 	secretPwErr  error
 
-	// refresh-all tuning, applied by SetRefreshTuning. Zero values mean
+	// refresh tuning, applied by SetRefreshTuning. Zero values mean
 	// defaults: a bounded fan-out and no per-API deadline.
 	refreshConcurrency int
 	refreshTimeout     time.Duration
@@ -48,8 +53,8 @@ const defaultRefreshConcurrency = 8
 
 // SetRefreshTuning sets the refresh-all fan-out cap and the per-API timeout.
 // concurrency <= 0 keeps the default cap; timeout <= 0 leaves per-API refreshes
-// unbounded (so a legitimately large org isn't cut off mid-fetch). The command
-// layer wires these from config.
+// unbounded (so a legitimately large org isn't cut off mid-fetch). An API's own
+// RefreshTimeout takes precedence. The command layer wires these from config.
 func (c *CacheManager) SetRefreshTuning(concurrency int, timeout time.Duration) {
 	c.refreshConcurrency = concurrency
 	c.refreshTimeout = timeout
@@ -71,13 +76,18 @@ func (c *CacheManager) refreshLimit(n int) int {
 	return limit
 }
 
-// refreshCtx derives the per-API context: a timeout-bounded child when a refresh
-// timeout is configured, else the parent with a no-op cancel.
-func (c *CacheManager) refreshCtx(parent context.Context) (context.Context, context.CancelFunc) {
-	if c.refreshTimeout <= 0 {
+// refreshCtx derives the per-API context: a timeout-bounded child when the API
+// or the cache manager has a refresh timeout, else the parent with a no-op
+// cancel.
+func (c *CacheManager) refreshCtx(parent context.Context, apiLabel string) (context.Context, context.CancelFunc) {
+	timeout := c.refreshTimeout
+	if cfg, err := c.registry.GetConfig(apiLabel); err == nil && cfg.RefreshTimeout > 0 {
+		timeout = cfg.RefreshTimeout
+	}
+	if timeout <= 0 {
 		return parent, func() {}
 	}
-	return context.WithTimeout(parent, c.refreshTimeout)
+	return context.WithTimeout(parent, timeout)
 }
 
 // secretPassword resolves the password used to encrypt WLAN secrets in the
@@ -242,6 +252,15 @@ func (c *CacheManager) saveAPICacheLocked(cache *APICache) error {
 		return fmt.Errorf("failed to marshal cache: %w", err)
 	}
 
+	// The per-label mutex only covers this process; the lock file keeps a
+	// concurrent wifimgr (cron refresh plus an interactive run) from
+	// interleaving the cache and metadata writes.
+	unlock, err := helpers.LockFile(cachePath, cacheLockTimeout)
+	if err != nil {
+		return fmt.Errorf("failed to lock cache: %w", err)
+	}
+	defer unlock()
+
 	if err := helpers.WriteFileAtomic(cachePath, data, 0600); err != nil {
 		return fmt.Errorf("failed to write cache: %w", err)
 	}
@@ -252,14 +271,63 @@ func (c *CacheManager) saveAPICacheLocked(cache *APICache) error {
 		// Don't fail the save operation if metadata creation fails
 	}
 
+	// Shards are a read optimization; stale ones are ignored by readers, so a
+	// failure here costs single-site operations a full read, nothing more.
+	if err := c.writeShardsLocked(cache, c.Generation(cache.APILabel)); err != nil {
+		logging.Warnf("Failed to write site shards for %s: %v", cache.APILabel, err)
+	}
+
 	return nil
 }
 
+// Generation returns the write stamp of an API's cache file: its modification
+// time in nanoseconds, or 0 when there is no cache yet. Saves replace the file
+// atomically, so the stamp advances with every save by any wifimgr process.
+// In-memory caches in front of the file use it to notice they are stale.
+func (c *CacheManager) Generation(apiLabel string) uint64 {
+	info, err := os.Stat(c.getAPICachePath(apiLabel))
+	if err != nil {
+		return 0
+	}
+	ns := info.ModTime().UnixNano()
+	if ns < 0 {
+		return 0
+	}
+	return uint64(ns)
+}
+
 // getAPICachePath returns the path for an API's cache file.
 func (c *CacheManager) getAPICachePath(apiLabel string) string {
 	return filepath.Join(c.cacheDir, "apis", apiLabel+".json")
 }
 
+// CacheFilePath returns the on-disk path of an API's cache file. Callers that
+// copy the file elsewhere (remote backup mirroring) use it instead of
+// re-deriving the layout.
+func (c *CacheManager) CacheFilePath(apiLabel string) string {
+	return c.getAPICachePath(apiLabel)
+}
+
+// CacheFilePaths returns the cache file of every API cached on disk, sorted
+// by label, whether or not the API is still configured.
+func (c *CacheManager) CacheFilePaths() ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(c.cacheDir, "apis"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read apis directory: %w", err)
+	}
+	var paths []string
+	for _, entry := range entries {
+		if label := cacheEntryLabel(entry.Name()); !entry.IsDir() && label != "" && entry.Name() == label+".json" {
+			paths = append(paths, c.getAPICachePath(label))
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
 // getAPICacheMetaPath returns the path for an API's cache metadata file.
 // Uses dotfile format: .apiLabel.json.meta
 func (c *CacheManager) getAPICacheMetaPath(apiLabel string) string {
@@ -104,7 +104,7 @@ func (c *CacheManager) saveIndex() error {
 		return fmt.Errorf("failed to marshal index: %w", err)
 	}
 
-	if err := os.WriteFile(indexPath, data, 0600); err != nil {
+	if err := helpers.WriteFileAtomic(indexPath, data, 0600); err != nil {
 		return fmt.Errorf("failed to write index: %w", err)
 	}
 
@@ -161,9 +161,10 @@ func (c *CacheManager) GetSiteAPIs(siteName string) []string {
 
 // GetSiteIDByName returns the site ID for a given site name in a specific API.
 // On miss, the returned SiteNotFoundError includes up to three close-match
-// suggestions drawn from the same API's site names.
+// suggestions drawn from the same API's site names. Only the core cache is
+// read, so resolving a name doesn't pay for every site's configs.
 func (c *CacheManager) GetSiteIDByName(apiLabel, siteName string) (string, error) {
-	cache, err := c.GetAPICache(apiLabel)
+	cache, err := c.GetCoreCache(apiLabel)
 	if err != nil {
 		return "", err
 	}
@@ -2,6 +2,7 @@ package vendors
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"sync"
@@ -103,11 +104,16 @@ func (c *CacheManager) RefreshAPISite(ctx context.Context, apiLabel, siteID stri
 // LastError) so status and the cache footer can show it; the last successful
 // LastRefresh is left intact. Without this, a hard failure returns before any
 // save and the failure leaves no trace the UI can read.
+//
+// The refresh runs under the API's refresh timeout, if any; see refreshCtx.
 func (c *CacheManager) RefreshAPIWithOptions(ctx context.Context, apiLabel string, opts RefreshOptions) error {
 	lock := c.labelLock(apiLabel)
 	lock.Lock()
 	defer lock.Unlock()
 
+	ctx, cancel := c.refreshCtx(ctx, apiLabel)
+	defer cancel()
+
 	err := c.doRefreshAPI(ctx, apiLabel, opts)
 	if err != nil {
 		c.recordRefreshFailureLocked(apiLabel, err)
@@ -166,6 +172,29 @@ func (c *CacheManager) doRefreshAPI(ctx context.Context, apiLabel string, opts R
 		}
 	}
 
+	// stopIfCanceled aborts before anything is saved. The stages ahead of the
+	// device configs each replace a whole section, so saving a pass cut short
+	// there would swap good cached data for empty maps.
+	stopIfCanceled := func(stage string) error {
+		if err := ctx.Err(); err != nil {
+			return &InterruptedError{APILabel: apiLabel, Op: "refresh (" + stage + ")", Outcome: "prior cache kept", Err: err}
+		}
+		return nil
+	}
+
+	// priorCache returns the cache being replaced (empty if there is none),
+	// loading it on first use.
+	priorCache := func() *APICache {
+		if existingCache == nil {
+			if prior, err := c.GetAPICache(apiLabel); err == nil {
+				existingCache = prior
+			} else {
+				existingCache = NewAPICache(apiLabel, config.Vendor, "")
+			}
+		}
+		return existingCache
+	}
+
 	// Create new cache
 	cache := NewAPICache(apiLabel, config.Vendor, config.Credentials["org_id"])
 	cache.Meta.LastRefresh = startTime
@@ -183,6 +212,9 @@ func (c *CacheManager) doRefreshAPI(ctx context.Context, apiLabel string, opts R
 		sites, err := sitesSvc.List(ctx)
 		if err != nil {
 			report.StageResult(apiLabel, "error")
+			if ierr := stopIfCanceled("sites"); ierr != nil {
+				return ierr
+			}
 			logging.Debugf("[cache] Failed to fetch sites for %s: %v", apiLabel, err)
 			return fmt.Errorf("failed to fetch sites: %w", err)
 		}
@@ -255,6 +287,10 @@ func (c *CacheManager) doRefreshAPI(ctx context.Context, apiLabel string, opts R
 		}
 	}
 
+	if err := stopIfCanceled("inventory"); err != nil {
+		return err
+	}
+
 	// Fetch device statuses. Skipped entirely for a site-only sync — statuses
 	// describe devices we aren't collecting.
 	if config.SyncsAnyDevice() {
@@ -275,6 +311,10 @@ func (c *CacheManager) doRefreshAPI(ctx context.Context, apiLabel string, opts R
 		}
 	}
 
+	if err := stopIfCanceled("device statuses"); err != nil {
+		return err
+	}
+
 	// Fetch BSSIDs (if supported). BSSIDs are AP-scoped, so skip when APs aren't synced.
 	if bssidSvc := client.BSSIDs(); bssidSvc != nil && config.ShouldSync("ap") {
 		report.Stage(apiLabel, "Fetching BSSIDs")
@@ -308,6 +348,10 @@ func (c *CacheManager) doRefreshAPI(ctx context.Context, apiLabel string, opts R
 		}
 	}
 
+	if err := stopIfCanceled("BSSIDs"); err != nil {
+		return err
+	}
+
 	// Fetch templates (if supported)
 	if tmplSvc := client.Templates(); tmplSvc != nil {
 		report.Stage(apiLabel, "Fetching templates")
@@ -339,6 +383,10 @@ func (c *CacheManager) doRefreshAPI(ctx context.Context, apiLabel string, opts R
 		report.StageResult(apiLabel, fmt.Sprintf("%d RF, %d GW, %d WLAN", rfCount, gwCount, wlanCount))
 	}
 
+	if err := stopIfCanceled("templates"); err != nil {
+		return err
+	}
+
 	// Fetch profiles (if supported)
 	if profSvc := client.Profiles(); profSvc != nil {
 		report.Stage(apiLabel, "Fetching device profiles")
@@ -352,6 +400,10 @@ func (c *CacheManager) doRefreshAPI(ctx context.Context, apiLabel string, opts R
 		}
 	}
 
+	if err := stopIfCanceled("device profiles"); err != nil {
+		return err
+	}
+
 	// Fetch WLANs (if supported)
 	if wlanSvc := client.WLANs(); wlanSvc != nil {
 		report.Stage(apiLabel, "Fetching WLANs")
@@ -388,7 +440,16 @@ func (c *CacheManager) doRefreshAPI(ctx context.Context, apiLabel string, opts R
 		}
 	}
 
-	// Fetch device configs (if supported and enabled)
+	if err := stopIfCanceled("WLANs"); err != nil {
+		return err
+	}
+
+	// Fetch device configs (if supported and enabled). These are fetched one by
+	// one and can take minutes; on cancellation the configs fetched so far are
+	// kept and the rest carried forward from the prior cache, so the saved cache
+	// is never worse than the one it replaces.
+	var pendingConfigs []string
+	configsDone, configsTotal := 0, 0
 	if shouldFetchConfigs {
 		if cfgSvc := client.Configs(); cfgSvc != nil {
 			logging.Debugf("[cache] Fetching device configs for %s", apiLabel)
@@ -417,6 +478,16 @@ func (c *CacheManager) doRefreshAPI(ctx context.Context, apiLabel string, opts R
 						}
 						continue
 					}
+					configsTotal++
+					if ctx.Err() != nil {
+						pendingConfigs = append(pendingConfigs, mac)
+						if old, ok := priorCache().Configs.AP[mac]; ok && old != nil {
+							cache.Configs.AP[mac] = old
+							apCarriedCount++
+						}
+						continue
+					}
+					configsDone++
 					cfg, err := cfgSvc.GetAPConfig(ctx, item.SiteID, item.ID)
 					if err == nil && cfg != nil {
 						cache.Configs.AP[mac] = cfg
@@ -455,6 +526,16 @@ func (c *CacheManager) doRefreshAPI(ctx context.Context, apiLabel string, opts R
 						}
 						continue
 					}
+					configsTotal++
+					if ctx.Err() != nil {
+						pendingConfigs = append(pendingConfigs, mac)
+						if old, ok := priorCache().Configs.Switch[mac]; ok && old != nil {
+							cache.Configs.Switch[mac] = old
+							switchCarriedCount++
+						}
+						continue
+					}
+					configsDone++
 					cfg, err := cfgSvc.GetSwitchConfig(ctx, item.SiteID, item.ID)
 					if err == nil && cfg != nil {
 						cache.Configs.Switch[mac] = cfg
@@ -493,6 +574,16 @@ func (c *CacheManager) doRefreshAPI(ctx context.Context, apiLabel string, opts R
 						}
 						continue
 					}
+					configsTotal++
+					if ctx.Err() != nil {
+						pendingConfigs = append(pendingConfigs, mac)
+						if old, ok := priorCache().Configs.Gateway[mac]; ok && old != nil {
+							cache.Configs.Gateway[mac] = old
+							gatewayCarriedCount++
+						}
+						continue
+					}
+					configsDone++
 					cfg, err := cfgSvc.GetGatewayConfig(ctx, item.SiteID, item.ID)
 					if err == nil && cfg != nil {
 						cache.Configs.Gateway[mac] = cfg
@@ -531,13 +622,29 @@ func (c *CacheManager) doRefreshAPI(ctx context.Context, apiLabel string, opts R
 
 	logging.Debugf("[cache] Saved cache for %s", apiLabel)
 
+	var interrupted error
+	if len(pendingConfigs) > 0 {
+		interrupted = &InterruptedError{
+			APILabel: apiLabel,
+			Op:       "refresh (device configs)",
+			Done:     configsDone,
+			Total:    configsTotal,
+			Pending:  pendingConfigs,
+			Outcome:  "fetched configs saved, the rest kept from the prior cache",
+			Err:      ctx.Err(),
+		}
+	}
+
 	// Rebuild the cross-API index — unless the caller batches it. Refresh-all
 	// sets SkipIndexRebuild and rebuilds once after every API saves, instead of
 	// rebuilding (and re-reporting every MAC collision) once per API.
 	if opts.SkipIndexRebuild {
-		return nil
+		return interrupted
+	}
+	if err := c.RebuildIndex(); err != nil {
+		return err
 	}
-	return c.RebuildIndex()
+	return interrupted
 }
 
 // RefreshAllAPIs refreshes all API caches in parallel, reporting progress to
@@ -577,13 +684,10 @@ func (c *CacheManager) refreshAllAPIs(ctx context.Context, report refreshui.Repo
 			defer wg.Done()
 			defer func() { <-sem }()
 
-			apiCtx, cancel := c.refreshCtx(ctx)
-			defer cancel()
-
 			opts := optsFor(apiLabel)
 			opts.Reporter = report
 			opts.SkipIndexRebuild = true // batch the rebuild once, below
-			if err := c.RefreshAPIWithOptions(apiCtx, apiLabel, opts); err != nil {
+			if err := c.RefreshAPIWithOptions(ctx, apiLabel, opts); err != nil {
 				report.APIError(apiLabel, err)
 				mu.Lock()
 				errors[apiLabel] = err
@@ -627,6 +731,9 @@ func classifyRefreshError(refreshErr error) string {
 	if refreshErr == nil {
 		return ""
 	}
+	if errors.Is(refreshErr, context.Canceled) {
+		return "interrupted"
+	}
 	msg := strings.ToLower(refreshErr.Error())
 	switch {
 	case strings.Contains(msg, "deadline exceeded"),
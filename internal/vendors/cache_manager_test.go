@@ -569,6 +569,29 @@ func TestCacheManager_RefreshAllAPIs(t *testing.T) {
 	}
 }
 
+func TestCacheManager_RefreshCtx(t *testing.T) {
+	registry := NewAPIClientRegistry()
+	registry.RegisterClient("slow", NewMockClient("mock", "1"), &APIConfig{Label: "slow", RefreshTimeout: time.Hour})
+	registry.RegisterClient("plain", NewMockClient("mock", "2"), &APIConfig{Label: "plain"})
+	cm := NewCacheManager(t.TempDir(), registry)
+
+	ctx, cancel := cm.refreshCtx(context.Background(), "plain")
+	cancel()
+	if _, ok := ctx.Deadline(); ok {
+		t.Error("refresh without any timeout has a deadline")
+	}
+
+	cm.SetRefreshTuning(0, time.Minute)
+	for label, want := range map[string]time.Duration{"plain": time.Minute, "slow": time.Hour} {
+		ctx, cancel := cm.refreshCtx(context.Background(), label)
+		deadline, ok := ctx.Deadline()
+		cancel()
+		if left := time.Until(deadline); !ok || left > want || left < want-time.Second {
+			t.Errorf("%s: deadline in %v, want %v", label, left, want)
+		}
+	}
+}
+
 // Helper to check error type
 func isDeviceNotFoundError(err error, target **DeviceNotFoundError) bool {
 	if e, ok := err.(*DeviceNotFoundError); ok {
@@ -639,3 +662,33 @@ func TestCacheManager_GetSiteIDByName_Duplicate(t *testing.T) {
 		t.Error("expected miss for absent site, got nil")
 	}
 }
+
+func TestCacheManager_Generation(t *testing.T) {
+	tmpDir := t.TempDir()
+	cm := NewCacheManager(tmpDir, NewAPIClientRegistry())
+	if err := cm.Initialize(); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+
+	if gen := cm.Generation("test-api"); gen != 0 {
+		t.Errorf("generation without a cache = %d, want 0", gen)
+	}
+
+	cache := NewAPICache("test-api", "mist", "org-123")
+	if err := cm.SaveAPICache(cache); err != nil {
+		t.Fatalf("SaveAPICache failed: %v", err)
+	}
+	first := cm.Generation("test-api")
+	if first == 0 {
+		t.Fatal("generation should be set after a save")
+	}
+
+	// Force a distinct stamp even on filesystems with coarse timestamps.
+	later := time.Now().Add(time.Second)
+	if err := os.Chtimes(cm.CacheFilePath("test-api"), later, later); err != nil {
+		t.Fatal(err)
+	}
+	if cm.Generation("test-api") <= first {
+		t.Error("generation should advance when the cache file is rewritten")
+	}
+}
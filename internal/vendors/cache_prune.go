@@ -0,0 +1,134 @@
+package vendors
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// OrphanedCache is the cache of an API label that is no longer configured.
+type OrphanedCache struct {
+	APILabel string
+	Paths    []string // cache file, metadata, shard directory, lock file
+	Bytes    int64
+}
+
+// FindOrphanedCaches lists the API caches on disk whose label is not in
+// configured, sorted by label. A label is found from its cache file or from
+// leftover metadata or shards of one.
+func (c *CacheManager) FindOrphanedCaches(configured []string) ([]OrphanedCache, error) {
+	known := make(map[string]bool, len(configured))
+	for _, label := range configured {
+		known[label] = true
+	}
+
+	apisDir := filepath.Join(c.cacheDir, "apis")
+	entries, err := os.ReadDir(apisDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read apis directory: %w", err)
+	}
+
+	labels := make(map[string]bool)
+	for _, entry := range entries {
+		if label := cacheEntryLabel(entry.Name()); label != "" && !known[label] {
+			labels[label] = true
+		}
+	}
+
+	orphans := make([]OrphanedCache, 0, len(labels))
+	for label := range labels {
+		o := OrphanedCache{APILabel: label}
+		for _, path := range c.apiCachePaths(label) {
+			size, err := pathSize(path)
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to size %s: %w", path, err)
+			}
+			o.Paths = append(o.Paths, path)
+			o.Bytes += size
+		}
+		orphans = append(orphans, o)
+	}
+	sort.Slice(orphans, func(a, b int) bool { return orphans[a].APILabel < orphans[b].APILabel })
+	return orphans, nil
+}
+
+// PruneCaches deletes the given orphaned caches and rebuilds the cross-API
+// index without them. It returns the bytes reclaimed.
+func (c *CacheManager) PruneCaches(orphans []OrphanedCache) (int64, error) {
+	var reclaimed int64
+	for _, o := range orphans {
+		lock := c.labelLock(o.APILabel)
+		lock.Lock()
+		for _, path := range o.Paths {
+			if err := os.RemoveAll(path); err != nil {
+				lock.Unlock()
+				return reclaimed, fmt.Errorf("failed to remove %s: %w", path, err)
+			}
+		}
+		lock.Unlock()
+		reclaimed += o.Bytes
+	}
+	if len(orphans) > 0 {
+		if err := c.RebuildIndex(); err != nil {
+			return reclaimed, err
+		}
+	}
+	return reclaimed, nil
+}
+
+// apiCachePaths returns every path the cache of apiLabel may occupy.
+func (c *CacheManager) apiCachePaths(apiLabel string) []string {
+	cachePath := c.getAPICachePath(apiLabel)
+	return []string{
+		cachePath,
+		c.getAPICacheMetaPath(apiLabel),
+		c.getShardDir(apiLabel),
+		cachePath + ".lock",
+	}
+}
+
+// cacheEntryLabel returns the API label an entry of the apis directory
+// belongs to, or "" for anything else.
+func cacheEntryLabel(name string) string {
+	switch {
+	case strings.HasPrefix(name, ".") && strings.HasSuffix(name, ".json.meta"):
+		return strings.TrimSuffix(strings.TrimPrefix(name, "."), ".json.meta")
+	case strings.HasPrefix(name, ".") && strings.HasSuffix(name, ".shards"):
+		return strings.TrimSuffix(strings.TrimPrefix(name, "."), ".shards")
+	case strings.HasSuffix(name, ".json.lock"):
+		return strings.TrimSuffix(name, ".json.lock")
+	case strings.HasSuffix(name, ".json") && !strings.HasPrefix(name, "."):
+		return strings.TrimSuffix(name, ".json")
+	}
+	return ""
+}
+
+// pathSize returns the size of a file, or the total size of the files under
+// a directory.
+func pathSize(path string) (int64, error) {
+	var total int64
+	err := filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		total += info.Size()
+		return nil
+	})
+	return total, err
+}
@@ -0,0 +1,101 @@
+package vendors
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindAndPruneOrphanedCaches(t *testing.T) {
+	cm := NewCacheManager(t.TempDir(), NewAPIClientRegistry())
+	if err := cm.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	for _, label := range []string{"mist-prod", "mist-old"} {
+		cache := NewAPICache(label, "mist", "org-"+label)
+		cache.Sites.Info = []SiteInfo{{ID: "site-001", Name: "US-LAB-01"}}
+		cache.Inventory.AP["aabbccddee01"+label] = &InventoryItem{MAC: "aabbccddee01", Type: "ap", SiteID: "site-001"}
+		if err := cm.SaveAPICache(cache); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Metadata left behind by a cache file deleted by hand.
+	stray := filepath.Join(cm.cacheDir, "apis", ".meraki-gone.json.meta")
+	if err := os.WriteFile(stray, []byte("{}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	orphans, err := cm.FindOrphanedCaches([]string{"mist-prod"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(orphans) != 2 || orphans[0].APILabel != "meraki-gone" || orphans[1].APILabel != "mist-old" {
+		t.Fatalf("orphans = %+v, want meraki-gone and mist-old", orphans)
+	}
+	old := orphans[1]
+	if len(old.Paths) != 3 || old.Bytes == 0 {
+		t.Errorf("mist-old = %+v, want cache, metadata, and shards with a size", old)
+	}
+
+	reclaimed, err := cm.PruneCaches(orphans)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reclaimed != orphans[0].Bytes+old.Bytes {
+		t.Errorf("reclaimed %d bytes, want %d", reclaimed, orphans[0].Bytes+old.Bytes)
+	}
+	for _, o := range orphans {
+		for _, path := range o.Paths {
+			if _, err := os.Stat(path); !os.IsNotExist(err) {
+				t.Errorf("%s still exists", path)
+			}
+		}
+	}
+	if !cm.CacheExists("mist-prod") {
+		t.Error("configured cache was removed")
+	}
+	if left, _ := cm.FindOrphanedCaches([]string{"mist-prod"}); len(left) != 0 {
+		t.Errorf("orphans after prune = %+v", left)
+	}
+}
+
+func TestCacheEntryLabel(t *testing.T) {
+	tests := map[string]string{
+		"mist-prod.json":            "mist-prod",
+		".mist-prod.json.meta":      "mist-prod",
+		".mist-prod.shards":         "mist-prod",
+		"mist-prod.json.lock":       "mist-prod",
+		"notes.txt":                 "",
+		".mist-prod.json.tmp12345":  "",
+		"mist-prod.json.tmp-backup": "",
+	}
+	for name, want := range tests {
+		if got := cacheEntryLabel(name); got != want {
+			t.Errorf("cacheEntryLabel(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestCacheFilePaths(t *testing.T) {
+	cm := NewCacheManager(t.TempDir(), NewAPIClientRegistry())
+	if paths, err := cm.CacheFilePaths(); err != nil || len(paths) != 0 {
+		t.Fatalf("CacheFilePaths() before init = %v, %v; want none", paths, err)
+	}
+	if err := cm.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	for _, label := range []string{"mist-prod", "meraki-lab"} {
+		if err := cm.SaveAPICache(NewAPICache(label, "mist", "org-"+label)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	paths, err := cm.CacheFilePaths()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{cm.CacheFilePath("meraki-lab"), cm.CacheFilePath("mist-prod")}
+	if len(paths) != 2 || paths[0] != want[0] || paths[1] != want[1] {
+		t.Errorf("CacheFilePaths() = %v, want %v", paths, want)
+	}
+}
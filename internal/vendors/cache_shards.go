@@ -0,0 +1,250 @@
+package vendors
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ravinald/wifimgr/internal/logging"
+)
+
+// Site shards let a single-site operation read its slice of an API cache
+// without deserializing the whole org. Every save writes, next to the cache
+// file:
+//
+//	apis/.<label>.shards/core.json         org-wide data: meta, sites, site index,
+//	                                       inventory, templates, profiles, org WLANs
+//	apis/.<label>.shards/sites/<id>.json   one site's configs, status, WLANs,
+//	                                       BSSIDs, and client detail
+//
+// Shards are derived data. Each carries the generation of the cache file it
+// was cut from; a shard whose generation no longer matches (another writer
+// replaced the cache, or it was written by hand) is ignored and the reader
+// falls back to the full cache file, which stays the source of truth.
+
+// errShardUnavailable reports a missing, stale, or unreadable shard.
+var errShardUnavailable = errors.New("cache shard unavailable")
+
+// cacheShard is the on-disk form of a core or site shard.
+type cacheShard struct {
+	Generation uint64    `json:"generation"`
+	Cache      *APICache `json:"cache"`
+}
+
+// getShardDir returns the directory holding an API's shards.
+func (c *CacheManager) getShardDir(apiLabel string) string {
+	return filepath.Join(c.cacheDir, "apis", "."+apiLabel+".shards")
+}
+
+// getSiteShardPath returns the path of one site's shard, or "" when the site
+// ID cannot be used as a file name.
+func (c *CacheManager) getSiteShardPath(apiLabel, siteID string) string {
+	if siteID == "" || siteID != filepath.Base(siteID) || strings.HasPrefix(siteID, ".") {
+		return ""
+	}
+	return filepath.Join(c.getShardDir(apiLabel), "sites", siteID+".json")
+}
+
+// splitAPICache partitions a cache into its org-wide core and per-site
+// slices. Objects that name no site (org WLANs, configs without a site ID,
+// status for devices not in inventory) stay in the core. Slices share object
+// pointers with cache.
+func splitAPICache(cache *APICache) (*APICache, map[string]*APICache) {
+	core := &APICache{
+		Version:   cache.Version,
+		APILabel:  cache.APILabel,
+		Meta:      cache.Meta,
+		SiteIndex: cache.SiteIndex,
+		Sites:     cache.Sites,
+		Inventory: cache.Inventory,
+		Templates: cache.Templates,
+		Profiles:  cache.Profiles,
+	}
+	sites := make(map[string]*APICache, len(cache.Sites.Info))
+	for _, s := range cache.Sites.Info {
+		if s.ID != "" {
+			sites[s.ID] = newSiteSlice(cache)
+		}
+	}
+	slice := func(siteID string) *APICache {
+		if siteID == "" {
+			return core
+		}
+		if s, ok := sites[siteID]; ok {
+			return s
+		}
+		return core
+	}
+
+	deviceSite := make(map[string]string)
+	for _, inv := range []map[string]*InventoryItem{cache.Inventory.AP, cache.Inventory.Switch, cache.Inventory.Gateway} {
+		for mac, item := range inv {
+			if item != nil {
+				deviceSite[NormalizeMAC(mac)] = item.SiteID
+			}
+		}
+	}
+
+	for mac, cfg := range cache.Configs.AP {
+		s := slice(cfg.SiteID)
+		if s.Configs.AP == nil {
+			s.Configs.AP = make(map[string]*APConfig)
+		}
+		s.Configs.AP[mac] = cfg
+	}
+	for mac, cfg := range cache.Configs.Switch {
+		s := slice(cfg.SiteID)
+		if s.Configs.Switch == nil {
+			s.Configs.Switch = make(map[string]*SwitchConfig)
+		}
+		s.Configs.Switch[mac] = cfg
+	}
+	for mac, cfg := range cache.Configs.Gateway {
+		s := slice(cfg.SiteID)
+		if s.Configs.Gateway == nil {
+			s.Configs.Gateway = make(map[string]*GatewayConfig)
+		}
+		s.Configs.Gateway[mac] = cfg
+	}
+	for mac, status := range cache.DeviceStatus {
+		s := slice(deviceSite[NormalizeMAC(mac)])
+		if s.DeviceStatus == nil {
+			s.DeviceStatus = make(map[string]*DeviceStatus)
+		}
+		s.DeviceStatus[mac] = status
+	}
+	for id, wlan := range cache.WLANs {
+		s := slice(wlan.SiteID)
+		if s.WLANs == nil {
+			s.WLANs = make(map[string]*WLAN)
+		}
+		s.WLANs[id] = wlan
+	}
+	for bssid, entry := range cache.BSSIDs {
+		s := slice(entry.SiteID)
+		if s.BSSIDs == nil {
+			s.BSSIDs = make(map[string]*BSSIDEntry)
+		}
+		s.BSSIDs[bssid] = entry
+	}
+	for mac, detail := range cache.ClientDetail {
+		s := slice(detail.SiteID)
+		if s.ClientDetail == nil {
+			s.ClientDetail = make(map[string]*ClientDetail)
+		}
+		s.ClientDetail[mac] = detail
+	}
+	return core, sites
+}
+
+// newSiteSlice returns an empty per-site slice carrying cache's identity.
+func newSiteSlice(cache *APICache) *APICache {
+	return &APICache{Version: cache.Version, APILabel: cache.APILabel, Meta: cache.Meta}
+}
+
+// writeShardsLocked cuts cache into shards stamped with generation and
+// removes the shards of sites no longer in the cache. The caller holds the
+// per-label mutex and the cache file lock. Shards are written in place rather
+// than atomically: a torn shard fails to parse and its reader falls back to
+// the full cache, so the fsync per file is not worth paying on a large org.
+func (c *CacheManager) writeShardsLocked(cache *APICache, generation uint64) error {
+	sitesDir := filepath.Join(c.getShardDir(cache.APILabel), "sites")
+	if err := os.MkdirAll(sitesDir, 0700); err != nil {
+		return fmt.Errorf("failed to create shard directory: %w", err)
+	}
+
+	write := func(path string, slice *APICache) error {
+		data, err := json.Marshal(cacheShard{Generation: generation, Cache: slice})
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(path, data, 0600) // #nosec G306 -- same 0600 as the cache file
+	}
+
+	core, sites := splitAPICache(cache)
+	if err := write(filepath.Join(c.getShardDir(cache.APILabel), "core.json"), core); err != nil {
+		return fmt.Errorf("failed to write core shard: %w", err)
+	}
+	keep := make(map[string]bool, len(sites))
+	for siteID, slice := range sites {
+		path := c.getSiteShardPath(cache.APILabel, siteID)
+		if path == "" {
+			continue
+		}
+		if err := write(path, slice); err != nil {
+			return fmt.Errorf("failed to write shard for site %s: %w", siteID, err)
+		}
+		keep[filepath.Base(path)] = true
+	}
+
+	entries, err := os.ReadDir(sitesDir)
+	if err != nil {
+		return nil
+	}
+	for _, e := range entries {
+		if !keep[e.Name()] {
+			_ = os.Remove(filepath.Join(sitesDir, e.Name())) // #nosec G703 -- best-effort cleanup of a deleted site's shard
+		}
+	}
+	return nil
+}
+
+// readShard loads one shard, returning errShardUnavailable unless it exists,
+// parses, and matches the cache file's current generation.
+func (c *CacheManager) readShard(apiLabel, path string) (*APICache, error) {
+	if path == "" {
+		return nil, errShardUnavailable
+	}
+	data, err := os.ReadFile(path) // #nosec G304 -- path built from the cache dir and a site ID checked by getSiteShardPath
+	if err != nil {
+		return nil, errShardUnavailable
+	}
+	var shard cacheShard
+	if err := json.Unmarshal(data, &shard); err != nil || shard.Cache == nil {
+		return nil, errShardUnavailable
+	}
+	if gen := c.Generation(apiLabel); gen == 0 || shard.Generation != gen {
+		return nil, errShardUnavailable
+	}
+	return shard.Cache, nil
+}
+
+// GetCoreCache returns an API's org-wide data — sites, site index, inventory,
+// templates, profiles, and org-level WLANs — without any per-site configs,
+// status, or BSSIDs. It reads the core shard and falls back to slicing the
+// full cache file when the shard is unavailable.
+func (c *CacheManager) GetCoreCache(apiLabel string) (*APICache, error) {
+	if core, err := c.readShard(apiLabel, filepath.Join(c.getShardDir(apiLabel), "core.json")); err == nil {
+		return core, nil
+	}
+	logging.Debugf("[cache] No current core shard for %s, reading full cache", apiLabel)
+	cache, err := c.GetAPICache(apiLabel)
+	if err != nil {
+		return nil, err
+	}
+	core, _ := splitAPICache(cache)
+	return core, nil
+}
+
+// GetSiteCache returns one site's configs, device status, WLANs, BSSIDs, and
+// client detail. It reads the site's shard and falls back to slicing the full
+// cache file when the shard is unavailable, so the result is the same either
+// way; only the cost differs.
+func (c *CacheManager) GetSiteCache(apiLabel, siteID string) (*APICache, error) {
+	if site, err := c.readShard(apiLabel, c.getSiteShardPath(apiLabel, siteID)); err == nil {
+		return site, nil
+	}
+	logging.Debugf("[cache] No current shard for site %s in %s, reading full cache", siteID, apiLabel)
+	cache, err := c.GetAPICache(apiLabel)
+	if err != nil {
+		return nil, err
+	}
+	_, sites := splitAPICache(cache)
+	if site, ok := sites[siteID]; ok {
+		return site, nil
+	}
+	return newSiteSlice(cache), nil
+}
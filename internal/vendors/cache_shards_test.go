@@ -0,0 +1,117 @@
+package vendors
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+// newShardTestManager saves a two-site cache for "test-api" and returns its
+// manager.
+func newShardTestManager(t *testing.T) *CacheManager {
+	t.Helper()
+	registry := NewAPIClientRegistry()
+	registry.RegisterFactory("mock", func(config *APIConfig) (Client, error) {
+		return NewMockClientWithAllServices(config.Vendor, "org-123"), nil
+	})
+	registry.InitializeClients(map[string]*APIConfig{
+		"test-api": {Label: "test-api", Vendor: "mock", Credentials: map[string]string{"org_id": "org-123"}},
+	})
+
+	cm := NewCacheManager(t.TempDir(), registry)
+	if err := cm.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+
+	cache := NewAPICache("test-api", "mock", "org-123")
+	cache.Sites.Info = []SiteInfo{{ID: "site-001", Name: "US-LAB-01"}, {ID: "site-002", Name: "US-LAB-02"}}
+	cache.Inventory.AP["aabbccddee01"] = &InventoryItem{MAC: "aabbccddee01", Name: "ap-1", Type: "ap", SiteID: "site-001"}
+	cache.Inventory.AP["aabbccddee02"] = &InventoryItem{MAC: "aabbccddee02", Name: "ap-2", Type: "ap", SiteID: "site-002"}
+	cache.Configs.AP["aabbccddee01"] = &APConfig{MAC: "aabbccddee01", SiteID: "site-001", Name: "ap-1"}
+	cache.Configs.AP["aabbccddee02"] = &APConfig{MAC: "aabbccddee02", SiteID: "site-002", Name: "ap-2"}
+	cache.DeviceStatus = map[string]*DeviceStatus{"aabbccddee02": {Status: "online"}}
+	cache.WLANs = map[string]*WLAN{
+		"org-wlan":  {ID: "org-wlan", SSID: "corp"},
+		"site-wlan": {ID: "site-wlan", SSID: "guest", SiteID: "site-002"},
+	}
+	if err := cm.SaveAPICache(cache); err != nil {
+		t.Fatal(err)
+	}
+	return cm
+}
+
+func TestGetSiteCache(t *testing.T) {
+	cm := newShardTestManager(t)
+
+	core, err := cm.GetCoreCache("test-api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(core.Inventory.AP) != 2 || len(core.Configs.AP) != 0 || len(core.WLANs) != 1 || core.SiteIndex.ByName["US-LAB-02"] != "site-002" {
+		t.Errorf("core = inventory %d, configs %d, wlans %v", len(core.Inventory.AP), len(core.Configs.AP), core.WLANs)
+	}
+
+	site, err := cm.GetSiteCache("test-api", "site-002")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(site.Configs.AP) != 1 || site.Configs.AP["aabbccddee02"] == nil || site.DeviceStatus["aabbccddee02"] == nil || site.WLANs["site-wlan"] == nil {
+		t.Errorf("site-002 = configs %v, status %v, wlans %v", site.Configs.AP, site.DeviceStatus, site.WLANs)
+	}
+
+	// Replace the cache file behind the shards' back: they are now stale and
+	// reads fall back to the full file.
+	data, err := os.ReadFile(cm.CacheFilePath("test-api"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.WriteFile(cm.CacheFilePath("test-api"), data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(cm.CacheFilePath("test-api"), later, later); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cm.readShard("test-api", cm.getSiteShardPath("test-api", "site-002")); err != errShardUnavailable {
+		t.Errorf("readShard err = %v, want stale shard rejected", err)
+	}
+	site, err = cm.GetSiteCache("test-api", "site-002")
+	if err != nil || len(site.Configs.AP) != 1 {
+		t.Errorf("fallback site-002 = %v, %v", site, err)
+	}
+}
+
+func TestCacheAccessorLoadSite(t *testing.T) {
+	cm := newShardTestManager(t)
+	ca := NewLazyCacheAccessor(cm)
+	ca.LoadSite("us-lab-01")
+
+	if _, err := ca.GetAPConfigByMAC("aabbccddee01"); err != nil {
+		t.Errorf("site-001 config: %v", err)
+	}
+	if _, err := ca.GetAPConfigByMAC("aabbccddee02"); err == nil {
+		t.Error("site-002 config should not be loaded")
+	}
+	if len(ca.GetAllAPs()) != 2 {
+		t.Errorf("inventory = %d APs, want the whole org", len(ca.GetAllAPs()))
+	}
+
+	ca.RebuildIndexes()
+	if _, err := ca.GetAPConfigByMAC("aabbccddee02"); err == nil {
+		t.Error("rebuild should keep the accessor narrowed")
+	}
+
+	ca.LoadSite("site-002")
+	if _, err := ca.GetAPConfigByMAC("aabbccddee02"); err != nil {
+		t.Errorf("site-002 config after LoadSite: %v", err)
+	}
+
+	lazy := NewLazyCacheAccessor(cm)
+	if _, err := lazy.GetAPConfigByMAC("aabbccddee02"); err != nil {
+		t.Errorf("unnarrowed lazy accessor should load everything: %v", err)
+	}
+	lazy.LoadSite("US-LAB-01")
+	if _, err := lazy.GetAPConfigByMAC("aabbccddee02"); err != nil {
+		t.Errorf("LoadSite must not narrow a fully loaded accessor: %v", err)
+	}
+}
@@ -4,7 +4,11 @@
 // vendor-specific APIs (Mist, Meraki, etc.) behind a common interface.
 package vendors
 
-import "context"
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
 
 // Client is the vendor-agnostic interface for multi-vendor operations.
 // Services return nil if the vendor does not support that capability.
@@ -23,6 +27,13 @@ type Client interface {
 	WLANs() WLANsService
 	BSSIDs() BSSIDsService
 	ClientDetail() ClientDetailService
+	DeviceStats() DeviceStatsService
+	Maps() MapsService
+	ClientEvents() ClientEventsService
+	DeviceEvents() DeviceEventsService
+	Firmware() FirmwareService
+	Usage() UsageService
+	SecurityEvents() SecurityEventsService
 
 	// Metadata
 	VendorName() string
@@ -162,6 +173,54 @@ type ClientDetailService interface {
 	FetchSiteClientDetail(ctx context.Context, siteID string) ([]*ClientDetail, error)
 }
 
+// DeviceStatsService provides live per-device statistics for one site —
+// power source and budget and eth0 uplink state for APs, PoE draw for switches. Fetched on demand by
+// reports rather than cached, since the numbers are only meaningful fresh.
+type DeviceStatsService interface {
+	ListBySite(ctx context.Context, siteID string) ([]*DeviceStats, error)
+}
+
+// MapsService lists the floor plans defined for a site. Paired with the AP
+// placement carried on DeviceStats it lets reports group APs by area.
+type MapsService interface {
+	ListBySite(ctx context.Context, siteID string) ([]*SiteMap, error)
+}
+
+// ClientEventsService returns the connection events a vendor recorded for a
+// client — association, authentication, DHCP, ARP and DNS outcomes — for
+// troubleshooting. Queried live; events are never cached.
+type ClientEventsService interface {
+	Search(ctx context.Context, mac string, start, end time.Time) ([]*ClientEvent, error)
+}
+
+// DeviceEventsService returns the restarts a vendor recorded for the devices
+// at a site, for spotting devices that keep rebooting. Queried live; events
+// are never cached.
+type DeviceEventsService interface {
+	Reboots(ctx context.Context, siteID string, start, end time.Time) ([]*DeviceReboot, error)
+}
+
+// FirmwareService starts firmware upgrades. Upgrade asks the vendor to move
+// the given devices at one site to version; it returns once the request is
+// accepted; the upgrade and reboot then run on the vendor side.
+type FirmwareService interface {
+	Upgrade(ctx context.Context, siteID string, deviceIDs []string, version string) error
+}
+
+// UsageService reports how many clients a site served and how much traffic it
+// carried over a time window, for billing and chargeback exports. Queried
+// live from the vendor's historical stats; never cached.
+type UsageService interface {
+	SiteUsage(ctx context.Context, siteID string, start, end time.Time) (*SiteUsage, error)
+}
+
+// SecurityEventsService returns the wireless intrusion events a site
+// recorded: rogue and honeypot APs, spoofing, and frame floods. Queried live;
+// events are never cached.
+type SecurityEventsService interface {
+	Search(ctx context.Context, siteID string, start, end time.Time) ([]*SecurityEvent, error)
+}
+
 // LegacyClientAccessor provides access to the underlying legacy client.
 // This interface is implemented by vendor adapters that wrap legacy clients.
 // Use this when you need vendor-specific functionality not available in the
@@ -178,3 +237,21 @@ type LegacyClientAccessor interface {
 	// Returns nil if no legacy client is available.
 	LegacyClient() any
 }
+
+// RawAPI is implemented by vendor adapters that can make requests against
+// endpoints wifimgr does not model, for the "api" command. Paths are relative
+// to the API root of the configured base URL.
+//
+// Example:
+//
+//	if raw, ok := client.(vendors.RawAPI); ok {
+//	    body, err := raw.RawGet(ctx, "/orgs/"+client.OrgID()+"/alarmtemplates")
+//	}
+type RawAPI interface {
+	// RawGet GETs path and returns the JSON response. List responses are
+	// paged through and returned whole.
+	RawGet(ctx context.Context, path string) (json.RawMessage, error)
+
+	// RawPut PUTs the JSON body to path and returns the response.
+	RawPut(ctx context.Context, path string, body json.RawMessage) (json.RawMessage, error)
+}
@@ -2,6 +2,7 @@ package vendors
 
 import (
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -227,6 +228,60 @@ func (e *CapabilityNotSupportedError) UserMessage() string {
 	return msg
 }
 
+// InterruptedError reports an operation stopped by cancellation (Ctrl-C or a
+// refresh timeout) partway through. Done/Total count the items that completed,
+// Pending names the ones that did not, and Outcome says what was kept, so the
+// operator knows exactly where the run stopped. Unwraps to the context error.
+type InterruptedError struct {
+	APILabel string
+	Op       string // what was running, e.g. "refresh (AP configs)"
+	Done     int
+	Total    int
+	Pending  []string
+	Outcome  string // e.g. "prior cache kept"
+	Err      error
+}
+
+func (e *InterruptedError) Error() string {
+	msg := fmt.Sprintf("%s interrupted", e.Op)
+	if e.APILabel != "" {
+		msg = e.APILabel + ": " + msg
+	}
+	if e.Total > 0 {
+		msg += fmt.Sprintf(" after %d/%d", e.Done, e.Total)
+	}
+	return fmt.Sprintf("%s: %v", msg, e.Err)
+}
+
+func (e *InterruptedError) Unwrap() error { return e.Err }
+
+// maxPendingShown caps how many pending items UserMessage lists.
+const maxPendingShown = 10
+
+func (e *InterruptedError) UserMessage() string {
+	msg := fmt.Sprintf("%s interrupted", e.Op)
+	if e.APILabel != "" {
+		msg = e.APILabel + ": " + msg
+	}
+	if e.Total > 0 {
+		msg += fmt.Sprintf(": %d of %d completed", e.Done, e.Total)
+	}
+	if e.Outcome != "" {
+		msg += "; " + e.Outcome
+	}
+	if len(e.Pending) > 0 {
+		shown := e.Pending
+		if len(shown) > maxPendingShown {
+			shown = shown[:maxPendingShown]
+		}
+		msg += fmt.Sprintf("\nNot completed (%d): %s", len(e.Pending), strings.Join(shown, ", "))
+		if len(e.Pending) > maxPendingShown {
+			msg += fmt.Sprintf(", ... and %d more", len(e.Pending)-maxPendingShown)
+		}
+	}
+	return msg
+}
+
 // MACCollisionError indicates the same MAC address exists in multiple APIs.
 // This should not happen in practice but is detected during cache building.
 type MACCollisionError struct {
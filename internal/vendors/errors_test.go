@@ -1,6 +1,9 @@
 package vendors
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"strings"
 	"testing"
 )
@@ -390,6 +393,38 @@ func TestConfigValidationError_Error(t *testing.T) {
 	}
 }
 
+func TestInterruptedError_UserMessage(t *testing.T) {
+	pending := make([]string, 12)
+	for i := range pending {
+		pending[i] = fmt.Sprintf("mac-%02d", i)
+	}
+	err := &InterruptedError{
+		APILabel: "mist-prod",
+		Op:       "refresh (device configs)",
+		Done:     3,
+		Total:    15,
+		Pending:  pending,
+		Outcome:  "prior cache kept",
+		Err:      context.Canceled,
+	}
+
+	if got, want := err.Error(), "mist-prod: refresh (device configs) interrupted after 3/15: context canceled"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Error("InterruptedError should unwrap to context.Canceled")
+	}
+	msg := err.UserMessage()
+	for _, want := range []string{"3 of 15 completed", "prior cache kept", "Not completed (12): mac-00", "mac-09, ... and 2 more"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("UserMessage() missing %q:\n%s", want, msg)
+		}
+	}
+	if strings.Contains(msg, "mac-10") {
+		t.Errorf("UserMessage() should cap the pending list:\n%s", msg)
+	}
+}
+
 // Test that errors implement the error interface
 func TestErrorsImplementErrorInterface(t *testing.T) {
 	var _ error = &SiteNotFoundError{}
@@ -403,4 +438,5 @@ func TestErrorsImplementErrorInterface(t *testing.T) {
 	var _ error = &UnexpectedFieldWarning{}
 	var _ error = &MissingFieldWarning{}
 	var _ error = &ConfigValidationError{}
+	var _ error = &InterruptedError{}
 }
@@ -9,6 +9,7 @@ import (
 	meraki "github.com/meraki/dashboard-api-go/v5/sdk"
 
 	"github.com/ravinald/wifimgr/internal/logging"
+	"github.com/ravinald/wifimgr/internal/runstats"
 	"github.com/ravinald/wifimgr/internal/vendors"
 )
 
@@ -96,6 +97,9 @@ func NewAdapter(apiKey, baseURL, orgID string, opts ...AdapterOption) (vendors.C
 	restyClient := dashboard.RestyClient()
 	if restyClient != nil {
 		restyClient.SetLogger(&noopLogger{})
+		if runstats.Instrumented() {
+			restyClient.SetTransport(runstats.RoundTripper(restyClient.GetClient().Transport))
+		}
 	}
 
 	// Create rate limiter: 10 req/sec with 10 burst capacity
@@ -264,5 +268,52 @@ func (a *Adapter) ClientDetail() vendors.ClientDetailService {
 	}
 }
 
+// DeviceStats returns nil. Meraki reports PoE per switch port and has no
+// AP power-mode field, so there is no org-level stats call to normalize.
+func (a *Adapter) DeviceStats() vendors.DeviceStatsService {
+	return nil
+}
+
+// Maps returns nil. Meraki floor plans carry no per-AP pixel placement in the
+// device stats wifimgr reads.
+func (a *Adapter) Maps() vendors.MapsService {
+	return nil
+}
+
+// ClientEvents returns nil. Meraki's per-client event history is read only
+// for connected band (see ClientDetail) and is not normalized for
+// troubleshooting.
+func (a *Adapter) ClientEvents() vendors.ClientEventsService {
+	return nil
+}
+
+// DeviceEvents returns nil. Meraki records reboots in the network event log
+// with per-product event types that are not normalized here.
+func (a *Adapter) DeviceEvents() vendors.DeviceEventsService {
+	return nil
+}
+
+// Firmware returns nil. Meraki schedules firmware per network and product
+// through upgrade windows, not per device.
+func (a *Adapter) Firmware() vendors.FirmwareService {
+	return nil
+}
+
+// Usage returns the UsageService backed by the network clients overview.
+func (a *Adapter) Usage() vendors.UsageService {
+	return &usageService{
+		dashboard:      a.dashboard,
+		rateLimiter:    a.rateLimiter,
+		retryConfig:    a.retryConfig,
+		suppressOutput: a.suppressOutput,
+	}
+}
+
+// SecurityEvents returns nil. Air Marshal lists the SSIDs seen over a
+// timespan rather than timestamped events, and floods are not exposed.
+func (a *Adapter) SecurityEvents() vendors.SecurityEventsService {
+	return nil
+}
+
 // Ensure Adapter implements vendors.Client at compile time.
 var _ vendors.Client = (*Adapter)(nil)
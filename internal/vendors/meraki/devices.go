@@ -336,6 +336,12 @@ func buildDeviceFieldUpdate(config map[string]interface{}) (*meraki.RequestDevic
 		request.FloorPlanID = floorPlanID
 		has = true
 	}
+	// The SDK drops an empty tags list (omitempty), so tags can be replaced
+	// but not cleared through this request.
+	if tags := extractStringSlice(config["tags"]); len(tags) > 0 {
+		request.Tags = tags
+		has = true
+	}
 
 	return request, has
 }
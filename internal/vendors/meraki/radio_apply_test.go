@@ -1,6 +1,7 @@
 package meraki
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -215,4 +216,7 @@ func TestBuildDeviceFieldUpdate_RadioOnlySkipsDevicePut(t *testing.T) {
 	if _, has := buildDeviceFieldUpdate(map[string]any{"name": "ap-1"}); !has {
 		t.Error("a name change should trigger a device-attributes PUT")
 	}
+	if req, has := buildDeviceFieldUpdate(map[string]any{"tags": []any{"lobby", "guest"}}); !has || strings.Join(req.Tags, " ") != "lobby guest" {
+		t.Errorf("tags should be sent as a list, got %v (has=%v)", req.Tags, has)
+	}
 }
@@ -0,0 +1,114 @@
+package meraki
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// RawGet GETs an unmodeled Dashboard endpoint; see vendors.RawAPI. The
+// /api/v1 prefix is optional. A list response is paged by following the
+// Link header's next URL.
+func (a *Adapter) RawGet(ctx context.Context, path string) (json.RawMessage, error) {
+	path = dashboardPath(path)
+
+	var all []json.RawMessage
+	for page := 1; path != ""; page++ {
+		resp, err := a.rawRequest(ctx, http.MethodGet, path, nil)
+		if err != nil {
+			return nil, err
+		}
+		body := bytes.TrimSpace(resp.Body())
+		if page == 1 && (len(body) == 0 || body[0] != '[') {
+			return body, nil
+		}
+		var items []json.RawMessage
+		if err := json.Unmarshal(body, &items); err != nil {
+			return nil, fmt.Errorf("failed to parse page %d: %w", page, err)
+		}
+		all = append(all, items...)
+		path = nextLink(resp.Header().Get("Link"))
+	}
+	if all == nil {
+		all = []json.RawMessage{}
+	}
+	return json.Marshal(all)
+}
+
+// RawPut PUTs the JSON body to an unmodeled Dashboard endpoint; see
+// vendors.RawAPI.
+func (a *Adapter) RawPut(ctx context.Context, path string, body json.RawMessage) (json.RawMessage, error) {
+	resp, err := a.rawRequest(ctx, http.MethodPut, dashboardPath(path), body)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.TrimSpace(resp.Body()), nil
+}
+
+// rawRequest sends one request through the SDK's authenticated HTTP client,
+// under the adapter's rate limit and 429 retry.
+func (a *Adapter) rawRequest(ctx context.Context, method, path string, body []byte) (*resty.Response, error) {
+	retryState := NewRetryState(a.retryConfig)
+	for {
+		if a.rateLimiter != nil {
+			if err := a.rateLimiter.Acquire(ctx); err != nil {
+				return nil, fmt.Errorf("rate limit acquire failed: %w", err)
+			}
+		}
+
+		req := a.dashboard.RestyClient().R().
+			SetContext(ctx).
+			SetHeader("Accept", "application/json")
+		if body != nil {
+			req.SetHeader("Content-Type", "application/json").SetBody(body)
+		}
+		resp, err := req.Execute(method, path)
+		err = ClassifyError("", method+" "+path, resp, err)
+		if err == nil {
+			return resp, nil
+		}
+		if !retryState.ShouldRetry(err) {
+			if resp != nil && len(resp.Body()) > 0 {
+				return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(string(resp.Body())))
+			}
+			return nil, err
+		}
+		var raw *http.Response
+		if resp != nil {
+			raw = resp.RawResponse
+		}
+		if waitErr := retryState.WaitBeforeRetry(ctx, raw); waitErr != nil {
+			return nil, fmt.Errorf("retry wait failed: %w", waitErr)
+		}
+	}
+}
+
+// dashboardPath roots path under /api/v1 unless it already names an API
+// version.
+func dashboardPath(path string) string {
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	if !strings.HasPrefix(path, "/api/") {
+		path = "/api/v1" + path
+	}
+	return path
+}
+
+// nextLink returns the rel=next URL of a Link header, or "".
+func nextLink(header string) string {
+	for _, part := range strings.Split(header, ",") {
+		target, params, ok := strings.Cut(part, ";")
+		if !ok || !strings.Contains(strings.ReplaceAll(params, `"`, ""), "rel=next") {
+			continue
+		}
+		target = strings.TrimSpace(target)
+		return strings.TrimSuffix(strings.TrimPrefix(target, "<"), ">")
+	}
+	return ""
+}
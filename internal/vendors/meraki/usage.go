@@ -0,0 +1,89 @@
+package meraki
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	meraki "github.com/meraki/dashboard-api-go/v5/sdk"
+
+	"github.com/ravinald/wifimgr/internal/logging"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+// usageLookback is how far back the clients overview reaches; Meraki rejects
+// an older t0.
+const usageLookback = 31 * 24 * time.Hour
+
+// usageService implements vendors.UsageService for Meraki using the network
+// clients overview.
+type usageService struct {
+	dashboard      *meraki.Client
+	rateLimiter    *RateLimiter
+	retryConfig    *RetryConfig
+	suppressOutput bool
+}
+
+// SiteUsage returns the network's client count and traffic between start and
+// end. Meraki reports the average usage per client, so the total is that
+// average times the client count, with no up/down split.
+func (s *usageService) SiteUsage(ctx context.Context, siteID string, start, end time.Time) (*vendors.SiteUsage, error) {
+	if time.Since(start) > usageLookback {
+		return nil, fmt.Errorf("meraki keeps client usage for 31 days; %s is out of reach", start.Format("2006-01-02"))
+	}
+	logging.Debugf("[meraki] Fetching clients overview for network %s", siteID)
+
+	params := &meraki.GetNetworkClientsOverviewQueryParams{
+		T0: start.UTC().Format(time.RFC3339),
+		T1: end.UTC().Format(time.RFC3339),
+	}
+
+	retryState := NewRetryState(s.retryConfig)
+	var overview *meraki.ResponseNetworksGetNetworkClientsOverview
+	var err error
+	for {
+		if s.rateLimiter != nil {
+			if err := s.rateLimiter.Acquire(ctx); err != nil {
+				return nil, fmt.Errorf("rate limit acquire failed: %w", err)
+			}
+		}
+
+		if s.suppressOutput {
+			restore := suppressStdout()
+			overview, _, err = s.dashboard.Networks.GetNetworkClientsOverview(siteID, params)
+			restore()
+		} else {
+			overview, _, err = s.dashboard.Networks.GetNetworkClientsOverview(siteID, params)
+		}
+		if err == nil {
+			break
+		}
+
+		if !retryState.ShouldRetry(err) {
+			return nil, fmt.Errorf("failed to get clients overview: %w", err)
+		}
+		if waitErr := retryState.WaitBeforeRetry(ctx, nil); waitErr != nil {
+			return nil, fmt.Errorf("retry wait failed: %w", waitErr)
+		}
+	}
+
+	return convertSiteUsage(siteID, start, end, overview), nil
+}
+
+// convertSiteUsage maps a clients overview; usage is in kilobytes.
+func convertSiteUsage(siteID string, start, end time.Time, overview *meraki.ResponseNetworksGetNetworkClientsOverview) *vendors.SiteUsage {
+	usage := &vendors.SiteUsage{SiteID: siteID, Start: start, End: end}
+	if overview == nil {
+		return usage
+	}
+	if overview.Counts != nil && overview.Counts.Total != nil {
+		usage.Clients = *overview.Counts.Total
+	}
+	if overview.Usages != nil && overview.Usages.Average != nil {
+		usage.TotalBytes = int64(*overview.Usages.Average) * int64(usage.Clients) * 1024
+	}
+	return usage
+}
+
+// Ensure usageService implements vendors.UsageService at compile time.
+var _ vendors.UsageService = (*usageService)(nil)
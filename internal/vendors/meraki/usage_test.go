@@ -0,0 +1,41 @@
+package meraki
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	meraki "github.com/meraki/dashboard-api-go/v5/sdk"
+)
+
+func TestConvertSiteUsage(t *testing.T) {
+	start := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+	total, average := 40, 2048 // KB per client
+	overview := &meraki.ResponseNetworksGetNetworkClientsOverview{
+		Counts: &meraki.ResponseNetworksGetNetworkClientsOverviewCounts{Total: &total},
+		Usages: &meraki.ResponseNetworksGetNetworkClientsOverviewUsages{Average: &average},
+	}
+
+	usage := convertSiteUsage("L_1", start, end, overview)
+	if usage.Clients != 40 {
+		t.Errorf("Clients = %d, want 40", usage.Clients)
+	}
+	if want := int64(40 * 2048 * 1024); usage.TotalBytes != want || usage.DownBytes != 0 || usage.UpBytes != 0 {
+		t.Errorf("bytes: got %+v, want total %d and no split", usage, want)
+	}
+
+	if empty := convertSiteUsage("L_1", start, end, nil); empty.Clients != 0 || empty.TotalBytes != 0 || empty.SiteID != "L_1" {
+		t.Errorf("nil overview: got %+v", empty)
+	}
+}
+
+func TestSiteUsage_Lookback(t *testing.T) {
+	s := &usageService{}
+	start := time.Now().AddDate(0, -3, 0)
+	_, err := s.SiteUsage(context.Background(), "L_1", start, start.AddDate(0, 1, 0))
+	if err == nil || !strings.Contains(err.Error(), "31 days") {
+		t.Errorf("got %v, want the 31-day lookback error", err)
+	}
+}
@@ -1,6 +1,9 @@
 package mist
 
 import (
+	"context"
+	"encoding/json"
+
 	"github.com/ravinald/wifimgr/api"
 	"github.com/ravinald/wifimgr/internal/vendors"
 )
@@ -91,6 +94,47 @@ func (a *Adapter) ClientDetail() vendors.ClientDetailService {
 	return nil
 }
 
+// DeviceStats returns the DeviceStatsService backed by the site device
+// stats endpoints (power source/budget for APs, PoE draw for switches).
+func (a *Adapter) DeviceStats() vendors.DeviceStatsService {
+	return &deviceStatsService{client: a.legacy}
+}
+
+// Maps returns the MapsService backed by the site maps endpoint.
+func (a *Adapter) Maps() vendors.MapsService {
+	return &mapsService{client: a.legacy}
+}
+
+// ClientEvents returns the ClientEventsService backed by the org client
+// events search.
+func (a *Adapter) ClientEvents() vendors.ClientEventsService {
+	return &clientEventsService{client: a.legacy, orgID: a.orgID}
+}
+
+// DeviceEvents returns the DeviceEventsService backed by the site device
+// events search.
+func (a *Adapter) DeviceEvents() vendors.DeviceEventsService {
+	return &deviceEventsService{client: a.legacy}
+}
+
+// Firmware returns the FirmwareService backed by the site device upgrade
+// endpoint.
+func (a *Adapter) Firmware() vendors.FirmwareService {
+	return &firmwareService{client: a.legacy}
+}
+
+// Usage returns the UsageService backed by the site client sessions search
+// and the site bytes insight.
+func (a *Adapter) Usage() vendors.UsageService {
+	return &usageService{client: a.legacy}
+}
+
+// SecurityEvents returns the SecurityEventsService backed by the site rogue
+// events search and the site security alarms.
+func (a *Adapter) SecurityEvents() vendors.SecurityEventsService {
+	return &securityEventsService{client: a.legacy}
+}
+
 // LegacyClient returns the underlying api.Client for advanced operations.
 // This should only be used when vendor-specific functionality is required.
 // Implements vendors.LegacyClientAccessor.
@@ -103,3 +147,13 @@ var _ vendors.Client = (*Adapter)(nil)
 
 // Ensure Adapter implements vendors.LegacyClientAccessor at compile time.
 var _ vendors.LegacyClientAccessor = (*Adapter)(nil)
+
+// RawGet GETs an unmodeled Mist endpoint; see vendors.RawAPI.
+func (a *Adapter) RawGet(ctx context.Context, path string) (json.RawMessage, error) {
+	return a.legacy.GetRaw(ctx, path)
+}
+
+// RawPut PUTs to an unmodeled Mist endpoint; see vendors.RawAPI.
+func (a *Adapter) RawPut(ctx context.Context, path string, body json.RawMessage) (json.RawMessage, error) {
+	return a.legacy.PutRaw(ctx, path, body)
+}
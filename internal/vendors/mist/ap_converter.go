@@ -307,6 +307,18 @@ func parseIPConfig(data map[string]any) *vendors.IPConfig {
 		v := int(vlan)
 		cfg.VlanID = &v
 	}
+	if typ6, ok := data["type6"].(string); ok {
+		cfg.Type6 = &typ6
+	}
+	if ip6, ok := data["ip6"].(string); ok {
+		cfg.IP6 = &ip6
+	}
+	if netmask6, ok := data["netmask6"].(string); ok {
+		cfg.Netmask6 = &netmask6
+	}
+	if gateway6, ok := data["gateway6"].(string); ok {
+		cfg.Gateway6 = &gateway6
+	}
 
 	return cfg
 }
@@ -281,6 +281,9 @@ func exportRadioBandConfig(raw map[string]any) *vendors.RadioBandConfig {
 	if v, ok := raw["preamble"].(string); ok {
 		bc.Preamble = &v
 	}
+	if v, ok := raw["standard_power"].(bool); ok {
+		bc.StandardPower = &v
+	}
 
 	return bc
 }
@@ -352,6 +355,18 @@ func exportIPConfig(raw map[string]any) *vendors.IPConfig {
 		mtu := int(v)
 		ic.Mtu = &mtu
 	}
+	if v, ok := raw["type6"].(string); ok {
+		ic.Type6 = &v
+	}
+	if v, ok := raw["ip6"].(string); ok {
+		ic.IP6 = &v
+	}
+	if v, ok := raw["netmask6"].(string); ok {
+		ic.Netmask6 = &v
+	}
+	if v, ok := raw["gateway6"].(string); ok {
+		ic.Gateway6 = &v
+	}
 
 	return ic
 }
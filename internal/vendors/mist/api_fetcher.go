@@ -190,7 +190,7 @@ func (m *MistAPIFetcher) RefreshDataType(ctx context.Context, dataType string) (
 
 	case "inventory-ap":
 		// Clear inventory cache to force fresh API fetch
-		api.ClearCache("inventory-ap")
+		m.client.ClearCache("inventory-ap")
 		inventory, err := m.client.GetInventory(ctx, m.orgID, "ap")
 		if err != nil {
 			return nil, fmt.Errorf("failed to fetch AP inventory: %w", err)
@@ -203,7 +203,7 @@ func (m *MistAPIFetcher) RefreshDataType(ctx context.Context, dataType string) (
 
 	case "inventory-switch":
 		// Clear inventory cache to force fresh API fetch
-		api.ClearCache("inventory-switch")
+		m.client.ClearCache("inventory-switch")
 		inventory, err := m.client.GetInventory(ctx, m.orgID, "switch")
 		if err != nil {
 			return nil, fmt.Errorf("failed to fetch switch inventory: %w", err)
@@ -216,7 +216,7 @@ func (m *MistAPIFetcher) RefreshDataType(ctx context.Context, dataType string) (
 
 	case "inventory-gateway":
 		// Clear inventory cache to force fresh API fetch
-		api.ClearCache("inventory-gateway")
+		m.client.ClearCache("inventory-gateway")
 		inventory, err := m.client.GetInventory(ctx, m.orgID, "gateway")
 		if err != nil {
 			return nil, fmt.Errorf("failed to fetch gateway inventory: %w", err)
@@ -357,7 +357,7 @@ func (m *MistAPIFetcher) GetSupportedDataTypes() []string {
 // fetchDeviceConfigs fetches configurations for all devices with non-null site_id
 func (m *MistAPIFetcher) fetchDeviceConfigs(ctx context.Context) (*FetchResult, error) {
 	// Clear the device configs cache to force fresh API fetch (not inventory)
-	api.ClearCache("configs")
+	m.client.ClearCache("configs")
 
 	// Group devices by site to use bulk fetch per site
 	siteDevices := make(map[string]bool) // Track unique sites with devices
@@ -401,7 +401,7 @@ func (m *MistAPIFetcher) fetchDeviceConfigs(ctx context.Context) (*FetchResult,
 
 	for siteID := range siteDevices {
 		// Clear config cache for this site before fetching to ensure fresh data
-		api.ClearCacheForSite(siteID, "configs")
+		m.client.ClearCacheForSite(siteID, "configs")
 
 		// Fetch each device type separately to properly capture device-specific fields
 		for _, deviceType := range deviceTypes {
@@ -0,0 +1,55 @@
+package mist
+
+import (
+	"context"
+	"time"
+
+	"github.com/ravinald/wifimgr/api"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+// clientEventsService implements vendors.ClientEventsService for Mist using
+// the org client events search.
+type clientEventsService struct {
+	client api.Client
+	orgID  string
+}
+
+// Search returns the events recorded for the client between start and end,
+// oldest first as Mist returns them.
+func (s *clientEventsService) Search(ctx context.Context, mac string, start, end time.Time) ([]*vendors.ClientEvent, error) {
+	raw, err := s.client.SearchClientEvents(ctx, s.orgID, vendors.NormalizeMAC(mac), start, end)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*vendors.ClientEvent, 0, len(raw))
+	for _, r := range raw {
+		out = append(out, convertClientEvent(r))
+	}
+	return out, nil
+}
+
+// convertClientEvent maps a Mist client event record. Band is reported as a
+// string ("24", "5", "6") on this endpoint.
+func convertClientEvent(raw map[string]interface{}) *vendors.ClientEvent {
+	ev := &vendors.ClientEvent{}
+	ev.Type, _ = raw["type"].(string)
+	mac, _ := raw["mac"].(string)
+	ev.MAC = vendors.NormalizeMAC(mac)
+	ap, _ := raw["ap"].(string)
+	ev.APMAC = vendors.NormalizeMAC(ap)
+	ev.SiteID, _ = raw["site_id"].(string)
+	ev.SSID, _ = raw["ssid"].(string)
+	ev.WLANID, _ = raw["wlan_id"].(string)
+	ev.Band, _ = raw["band"].(string)
+	ev.ReasonCode = intFromMap(raw, "reason_code")
+	ev.Text, _ = raw["text"].(string)
+	if ts := floatFromMap(raw, "timestamp"); ts > 0 {
+		sec := int64(ts)
+		ev.Timestamp = time.Unix(sec, int64((ts-float64(sec))*1e9)).UTC()
+	}
+	return ev
+}
+
+// Ensure clientEventsService implements vendors.ClientEventsService at compile time.
+var _ vendors.ClientEventsService = (*clientEventsService)(nil)
@@ -0,0 +1,56 @@
+package mist
+
+import (
+	"context"
+	"time"
+
+	"github.com/ravinald/wifimgr/api"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+// rebootEventTypes are the Mist device event codes recorded when an AP,
+// switch, or gateway restarts, whether on its own or on request.
+var rebootEventTypes = []string{
+	"AP_RESTARTED", "AP_RESTART_BY_USER",
+	"SW_RESTARTED", "SW_RESTART_BY_USER",
+	"GW_REBOOTED", "GW_REBOOT_BY_USER",
+}
+
+// deviceEventsService implements vendors.DeviceEventsService for Mist using
+// the site device events search.
+type deviceEventsService struct {
+	client api.Client
+}
+
+// Reboots returns the restarts recorded at the site between start and end.
+func (s *deviceEventsService) Reboots(ctx context.Context, siteID string, start, end time.Time) ([]*vendors.DeviceReboot, error) {
+	raw, err := s.client.SearchSiteDeviceEvents(ctx, siteID, rebootEventTypes, start, end)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*vendors.DeviceReboot, 0, len(raw))
+	for _, r := range raw {
+		out = append(out, convertDeviceReboot(r, siteID))
+	}
+	return out, nil
+}
+
+// convertDeviceReboot maps a Mist device event record. The cause is in
+// "reason" when Mist classifies it and otherwise only in the free-form "text".
+func convertDeviceReboot(raw map[string]interface{}, siteID string) *vendors.DeviceReboot {
+	ev := &vendors.DeviceReboot{SiteID: siteID}
+	ev.Type, _ = raw["type"].(string)
+	mac, _ := raw["mac"].(string)
+	ev.MAC = vendors.NormalizeMAC(mac)
+	if ev.Reason, _ = raw["reason"].(string); ev.Reason == "" {
+		ev.Reason, _ = raw["text"].(string)
+	}
+	if ts := floatFromMap(raw, "timestamp"); ts > 0 {
+		sec := int64(ts)
+		ev.Timestamp = time.Unix(sec, int64((ts-float64(sec))*1e9)).UTC()
+	}
+	return ev
+}
+
+// Ensure deviceEventsService implements vendors.DeviceEventsService at compile time.
+var _ vendors.DeviceEventsService = (*deviceEventsService)(nil)
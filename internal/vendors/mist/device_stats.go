@@ -0,0 +1,106 @@
+package mist
+
+import (
+	"context"
+
+	"github.com/ravinald/wifimgr/api"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+// deviceStatsService implements vendors.DeviceStatsService for Mist using the
+// site device stats endpoints.
+type deviceStatsService struct {
+	client api.Client
+}
+
+// ListBySite returns power and uplink stats for every AP and switch at the site.
+func (s *deviceStatsService) ListBySite(ctx context.Context, siteID string) ([]*vendors.DeviceStats, error) {
+	apStats, err := s.client.GetAPStats(ctx, siteID)
+	if err != nil {
+		return nil, err
+	}
+	switchStats, err := s.client.GetSwitchStats(ctx, siteID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*vendors.DeviceStats, 0, len(apStats)+len(switchStats))
+	for _, raw := range apStats {
+		out = append(out, convertAPStats(raw, siteID))
+	}
+	for _, raw := range switchStats {
+		out = append(out, convertSwitchStats(raw, siteID))
+	}
+	return out, nil
+}
+
+// convertAPStats maps the power_* fields and port_stat.eth0 of a Mist AP
+// stats record.
+func convertAPStats(raw map[string]interface{}, siteID string) *vendors.DeviceStats {
+	st := baseStats(raw, "ap", siteID)
+	st.PowerSource, _ = raw["power_src"].(string)
+	st.PowerConstrained, _ = raw["power_constrained"].(bool)
+	st.PowerOpMode, _ = raw["power_opmode"].(string)
+	st.PowerBudgetMW = intFromMap(raw, "power_budget")
+	st.MapID, _ = raw["map_id"].(string)
+	st.X = floatFromMap(raw, "x")
+	st.Y = floatFromMap(raw, "y")
+	st.Clients = intFromMap(raw, "num_clients")
+
+	if ports, ok := raw["port_stat"].(map[string]interface{}); ok {
+		if eth0, ok := ports["eth0"].(map[string]interface{}); ok {
+			st.Eth0Up, _ = eth0["up"].(bool)
+			st.Eth0FullDuplex, _ = eth0["full_duplex"].(bool)
+			st.Eth0SpeedMbps = intFromMap(eth0, "speed")
+			st.Eth0RxPackets = int64(floatFromMap(eth0, "rx_pkts"))
+			st.Eth0TxPackets = int64(floatFromMap(eth0, "tx_pkts"))
+			st.Eth0RxErrors = int64(floatFromMap(eth0, "rx_errors"))
+			st.Eth0TxErrors = int64(floatFromMap(eth0, "tx_errors"))
+		}
+	}
+	return st
+}
+
+// convertSwitchStats sums module_stat[].poe across virtual-chassis members.
+func convertSwitchStats(raw map[string]interface{}, siteID string) *vendors.DeviceStats {
+	st := baseStats(raw, "switch", siteID)
+	modules, _ := raw["module_stat"].([]interface{})
+	for _, m := range modules {
+		mod, ok := m.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		poe, ok := mod["poe"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		st.PoEDrawWatts += floatFromMap(poe, "power_draw")
+		st.PoEMaxWatts += floatFromMap(poe, "max_power")
+	}
+	return st
+}
+
+func baseStats(raw map[string]interface{}, deviceType, siteID string) *vendors.DeviceStats {
+	st := &vendors.DeviceStats{Type: deviceType, SiteID: siteID}
+	mac, _ := raw["mac"].(string)
+	st.MAC = vendors.NormalizeMAC(mac)
+	st.Name, _ = raw["name"].(string)
+	st.Model, _ = raw["model"].(string)
+	st.Version, _ = raw["version"].(string)
+	st.Uptime = int64(floatFromMap(raw, "uptime"))
+	return st
+}
+
+// floatFromMap safely extracts a float value from a map.
+func floatFromMap(m map[string]interface{}, key string) float64 {
+	switch n := m[key].(type) {
+	case float64:
+		return n
+	case int:
+		return float64(n)
+	}
+	return 0
+}
+
+// Ensure deviceStatsService implements vendors.DeviceStatsService at compile time.
+var _ vendors.DeviceStatsService = (*deviceStatsService)(nil)
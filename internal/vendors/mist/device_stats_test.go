@@ -0,0 +1,75 @@
+package mist
+
+import "testing"
+
+func TestConvertAPStats(t *testing.T) {
+	raw := map[string]interface{}{
+		"mac":               "5C:5B:35:00:00:01",
+		"name":              "ap-01",
+		"model":             "AP45",
+		"power_src":         "PoE 802.3af",
+		"power_constrained": true,
+		"power_opmode":      "6GHz radio disabled",
+		"power_budget":      float64(-1200),
+		"uptime":            float64(86400),
+		"port_stat": map[string]interface{}{
+			"eth0": map[string]interface{}{
+				"up": true, "speed": float64(100), "full_duplex": true,
+				"rx_pkts": float64(5000), "tx_pkts": float64(4000),
+				"rx_errors": float64(12), "tx_errors": float64(3),
+			},
+		},
+	}
+
+	st := convertAPStats(raw, "site-1")
+	if st.MAC != "5c5b35000001" || st.Type != "ap" || st.SiteID != "site-1" {
+		t.Errorf("identity = %+v", st)
+	}
+	if !st.PowerConstrained || st.PowerBudgetMW != -1200 || st.PowerSource != "PoE 802.3af" {
+		t.Errorf("power = %+v", st)
+	}
+	if st.Uptime != 86400 {
+		t.Errorf("uptime = %d, want 86400", st.Uptime)
+	}
+	if !st.Eth0Up || st.Eth0SpeedMbps != 100 || st.Eth0RxErrors != 12 || st.Eth0TxPackets != 4000 {
+		t.Errorf("eth0 = %+v", st)
+	}
+}
+
+func TestConvertSwitchStats(t *testing.T) {
+	raw := map[string]interface{}{
+		"mac": "aa:bb:cc:00:00:01",
+		"module_stat": []interface{}{
+			map[string]interface{}{"poe": map[string]interface{}{"power_draw": 40.5, "max_power": float64(370)}},
+			map[string]interface{}{"poe": map[string]interface{}{"power_draw": 9.5, "max_power": float64(370)}},
+			map[string]interface{}{"fpc_idx": float64(2)},
+		},
+	}
+	st := convertSwitchStats(raw, "site-1")
+	if st.PoEDrawWatts != 50 || st.PoEMaxWatts != 740 {
+		t.Errorf("PoE = %.1f/%.1f, want 50/740", st.PoEDrawWatts, st.PoEMaxWatts)
+	}
+}
+
+func TestConvertDeviceReboot(t *testing.T) {
+	ev := convertDeviceReboot(map[string]interface{}{
+		"type":      "AP_RESTARTED",
+		"mac":       "5C:5B:35:00:00:01",
+		"text":      "reboot reason: power cycle",
+		"timestamp": 1700000000.5,
+	}, "site-1")
+	if ev.MAC != "5c5b35000001" || ev.Type != "AP_RESTARTED" || ev.SiteID != "site-1" {
+		t.Errorf("identity = %+v", ev)
+	}
+	if ev.Reason != "reboot reason: power cycle" {
+		t.Errorf("reason = %q, want the text fallback", ev.Reason)
+	}
+	if ev.Timestamp.Unix() != 1700000000 {
+		t.Errorf("timestamp = %v", ev.Timestamp)
+	}
+
+	ev = convertDeviceReboot(map[string]interface{}{"reason": "upgrade", "text": "ignored"}, "site-1")
+	if ev.Reason != "upgrade" {
+		t.Errorf("reason = %q, want upgrade", ev.Reason)
+	}
+}
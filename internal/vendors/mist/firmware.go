@@ -0,0 +1,22 @@
+package mist
+
+import (
+	"context"
+
+	"github.com/ravinald/wifimgr/api"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+// firmwareService implements vendors.FirmwareService for Mist using the site
+// device upgrade endpoint.
+type firmwareService struct {
+	client api.Client
+}
+
+// Upgrade requests the upgrade of deviceIDs at siteID to version.
+func (s *firmwareService) Upgrade(ctx context.Context, siteID string, deviceIDs []string, version string) error {
+	return s.client.UpgradeDevices(ctx, siteID, deviceIDs, version)
+}
+
+// Ensure firmwareService implements vendors.FirmwareService at compile time.
+var _ vendors.FirmwareService = (*firmwareService)(nil)
@@ -0,0 +1,375 @@
+package mist
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// junosIgnoredKeys are switch config keys that identify or annotate the
+// device rather than configure it, so have no Junos equivalent to render.
+var junosIgnoredKeys = map[string]bool{
+	"id": true, "mac": true, "serial": true, "model": true, "type": true,
+	"site_id": true, "org_id": true, "map_id": true, "name": true, "notes": true,
+	"tags": true, "role": true, "vars": true, "magic": true, "managed": true,
+	"disable_auto_config": true, "deviceprofile_id": true, "deviceprofile_name": true,
+	"created_at": true, "modified_at": true, "x": true, "y": true,
+}
+
+// junosRenderedKeys are the keys RenderJunosSet translates.
+var junosRenderedKeys = map[string]bool{
+	"networks": true, "port_usages": true, "port_config": true, "ip_config": true,
+	"other_ip_configs": true, "dns_servers": true, "dns_suffix": true, "ntp_servers": true,
+	"extra_routes": true, "stp_config": true, "dhcp_snooping": true, "additional_config_cmds": true,
+}
+
+// RenderJunosSet renders a Mist switch config as Junos set commands, for
+// review and archiving. It is best effort: Mist builds the real config from
+// more than the device object (site settings, its own defaults), so the
+// output approximates what the switch runs rather than reproducing it.
+// Comments ("# ...") mark references the config cannot resolve. skipped
+// lists the top-level keys that are not rendered.
+func RenderJunosSet(cfg map[string]any) (lines, skipped []string) {
+	r := &junosRenderer{cfg: cfg, networks: mapField(cfg, "networks")}
+
+	if name, _ := cfg["name"].(string); name != "" {
+		r.add("set system host-name %s", junosQuote(name))
+	}
+	for _, s := range stringList(cfg["dns_servers"]) {
+		r.add("set system name-server %s", s)
+	}
+	for _, s := range stringList(cfg["dns_suffix"]) {
+		r.add("set system domain-search %s", s)
+	}
+	for _, s := range stringList(cfg["ntp_servers"]) {
+		r.add("set system ntp server %s", s)
+	}
+
+	for _, name := range sortedKeys(r.networks) {
+		net, _ := r.networks[name].(map[string]any)
+		if id, ok := vlanID(net["vlan_id"]); ok {
+			r.add("set vlans %s vlan-id %d", name, id)
+		} else {
+			r.add("# network %s has no numeric vlan_id", name)
+		}
+	}
+
+	r.renderIRB("", mapField(cfg, "ip_config"), true)
+	others := mapField(cfg, "other_ip_configs")
+	for _, network := range sortedKeys(others) {
+		oc, _ := others[network].(map[string]any)
+		r.renderIRB(network, oc, false)
+	}
+
+	r.renderPorts()
+
+	routes := mapField(cfg, "extra_routes")
+	for _, prefix := range sortedKeys(routes) {
+		route, _ := routes[prefix].(map[string]any)
+		for _, via := range stringList(route["via"]) {
+			r.add("set routing-options static route %s next-hop %s", prefix, via)
+		}
+	}
+	if prio, ok := mapField(cfg, "stp_config")["bridge_priority"]; ok {
+		r.add("set protocols rstp bridge-priority %v", prio)
+	}
+	if snoop := mapField(cfg, "dhcp_snooping"); snoop["enabled"] == true {
+		networks := stringList(snoop["networks"])
+		if snoop["all_networks"] == true {
+			networks = sortedKeys(r.networks)
+		}
+		for _, n := range networks {
+			r.add("set vlans %s forwarding-options dhcp-security", n)
+			if snoop["enable_arp_spoof_check"] == true {
+				r.add("set vlans %s forwarding-options dhcp-security arp-inspection", n)
+			}
+		}
+	}
+	for _, c := range stringList(cfg["additional_config_cmds"]) {
+		if c = strings.TrimSpace(c); c != "" {
+			r.lines = append(r.lines, c)
+		}
+	}
+
+	for _, k := range sortedKeys(cfg) {
+		if !junosIgnoredKeys[k] && !junosRenderedKeys[k] {
+			skipped = append(skipped, k)
+		}
+	}
+	return r.lines, skipped
+}
+
+type junosRenderer struct {
+	cfg      map[string]any
+	networks map[string]any
+	lines    []string
+}
+
+func (r *junosRenderer) add(format string, args ...any) {
+	r.lines = append(r.lines, fmt.Sprintf(format, args...))
+}
+
+// renderIRB renders a layer-3 interface on a network's VLAN. An empty
+// network is the default VLAN (irb.0), where Mist puts management unless
+// ip_config names a network.
+func (r *junosRenderer) renderIRB(network string, ipc map[string]any, management bool) {
+	if len(ipc) == 0 {
+		return
+	}
+	if n, _ := ipc["network"].(string); n != "" {
+		network = n
+	}
+	unit, vlan := 0, "default"
+	if network != "" {
+		net, _ := r.networks[network].(map[string]any)
+		id, ok := vlanID(net["vlan_id"])
+		if !ok {
+			r.add("# ip config on network %s: network not defined in this config", network)
+			return
+		}
+		unit, vlan = id, network
+	}
+
+	switch ipc["type"] {
+	case "dhcp":
+		r.add("set interfaces irb unit %d family inet dhcp", unit)
+	default:
+		ip, _ := ipc["ip"].(string)
+		if ip == "" {
+			return
+		}
+		prefix, ok := prefixLength(ipc["netmask"])
+		if !ok {
+			r.add("# %s: netmask %v not understood", ip, ipc["netmask"])
+			return
+		}
+		r.add("set interfaces irb unit %d family inet address %s/%d", unit, ip, prefix)
+		if gw, _ := ipc["gateway"].(string); gw != "" && management {
+			r.add("set routing-options static route 0.0.0.0/0 next-hop %s", gw)
+		}
+	}
+	r.add("set vlans %s l3-interface irb.%d", vlan, unit)
+}
+
+// renderPorts renders port_config through the port usages it references.
+// Ports bundled into a LAG are made members of aeN, and the usage is applied
+// to the bundle.
+func (r *junosRenderer) renderPorts() {
+	usages := mapField(r.cfg, "port_usages")
+	ports := mapField(r.cfg, "port_config")
+	configured := make(map[string]bool)
+	maxAE := -1
+
+	for _, key := range sortedKeys(ports) {
+		pc, _ := ports[key].(map[string]any)
+		for _, ifname := range expandInterfaceRange(key) {
+			target := ifname
+			if pc["aggregated"] == true {
+				idx, ok := vlanID(pc["ae_idx"])
+				if !ok {
+					r.add("# %s: aggregated without an ae_idx", ifname)
+					continue
+				}
+				target = fmt.Sprintf("ae%d", idx)
+				maxAE = max(maxAE, idx)
+				r.add("set interfaces %s ether-options 802.3ad %s", ifname, target)
+				if configured[target] {
+					continue
+				}
+				if pc["disable_lacp"] != true {
+					r.add("set interfaces %s aggregated-ether-options lacp active", target)
+				}
+			}
+			configured[target] = true
+			r.renderPort(target, pc, usages)
+		}
+	}
+	if maxAE >= 0 {
+		r.add("set chassis aggregated-devices ethernet device-count %d", maxAE+1)
+	}
+}
+
+func (r *junosRenderer) renderPort(ifname string, pc map[string]any, usages map[string]any) {
+	usageName, _ := pc["usage"].(string)
+	var usage map[string]any
+	switch usageName {
+	case "disabled":
+		r.add("set interfaces %s disable", ifname)
+		return
+	case "", "default":
+		usage = map[string]any{"mode": "access", "port_network": "default"}
+	default:
+		var ok bool
+		if usage, ok = usages[usageName].(map[string]any); !ok {
+			r.add("# %s: port usage %s is not defined in this config", ifname, usageName)
+			return
+		}
+	}
+	// Port-level fields override the usage's.
+	field := func(k string) any {
+		if v, ok := pc[k]; ok {
+			return v
+		}
+		return usage[k]
+	}
+
+	if d, _ := field("description").(string); d != "" {
+		r.add("set interfaces %s description %s", ifname, junosQuote(d))
+	}
+	if field("disabled") == true {
+		r.add("set interfaces %s disable", ifname)
+	}
+	if mtu, ok := vlanID(field("mtu")); ok {
+		r.add("set interfaces %s mtu %d", ifname, mtu)
+	}
+	if speed, _ := field("speed").(string); speed != "" && speed != "auto" {
+		r.add("set interfaces %s speed %s", ifname, speed)
+	}
+
+	eth := fmt.Sprintf("set interfaces %s unit 0 family ethernet-switching", ifname)
+	portNetwork, _ := field("port_network").(string)
+	if field("mode") == "trunk" {
+		r.add("%s interface-mode trunk", eth)
+		members := stringList(field("networks"))
+		if field("all_networks") == true {
+			members = []string{"all"}
+		} else if portNetwork != "" && !contains(members, portNetwork) {
+			members = append([]string{portNetwork}, members...)
+		}
+		for _, m := range members {
+			r.add("%s vlan members %s", eth, m)
+		}
+		if portNetwork != "" {
+			net, _ := r.networks[portNetwork].(map[string]any)
+			if id, ok := vlanID(net["vlan_id"]); ok {
+				r.add("set interfaces %s native-vlan-id %d", ifname, id)
+			}
+		}
+	} else {
+		r.add("%s interface-mode access", eth)
+		if portNetwork != "" {
+			r.add("%s vlan members %s", eth, portNetwork)
+		}
+	}
+
+	if field("poe_disabled") == true {
+		r.add("set poe interface %s disable", ifname)
+	}
+	if field("stp_edge") == true {
+		r.add("set protocols rstp interface %s edge", ifname)
+	}
+}
+
+// expandInterfaceRange expands a Mist port_config key — a comma-separated
+// list whose entries may end in a port range ("ge-0/0/0-3") — into
+// interface names.
+func expandInterfaceRange(key string) []string {
+	var out []string
+	for _, part := range strings.Split(key, ",") {
+		part = strings.TrimSpace(part)
+		slash := strings.LastIndex(part, "/")
+		dash := strings.LastIndex(part, "-")
+		if slash < 0 || dash < slash {
+			out = append(out, part)
+			continue
+		}
+		from, err1 := strconv.Atoi(part[slash+1 : dash])
+		to, err2 := strconv.Atoi(part[dash+1:])
+		if err1 != nil || err2 != nil || to < from {
+			out = append(out, part)
+			continue
+		}
+		for p := from; p <= to; p++ {
+			out = append(out, fmt.Sprintf("%s%d", part[:slash+1], p))
+		}
+	}
+	return out
+}
+
+// prefixLength reads a netmask given as dotted quad, "/24", or 24.
+func prefixLength(v any) (int, bool) {
+	switch t := v.(type) {
+	case float64:
+		return int(t), t >= 0 && t <= 32
+	case int:
+		return t, t >= 0 && t <= 32
+	case string:
+		s := strings.TrimPrefix(t, "/")
+		if n, err := strconv.Atoi(s); err == nil && n >= 0 && n <= 32 {
+			return n, true
+		}
+		if ip := net.ParseIP(s).To4(); ip != nil {
+			ones, bits := net.IPMask(ip).Size()
+			return ones, bits == 32
+		}
+	}
+	return 0, false
+}
+
+// vlanID reads a whole number stored as a JSON number or a numeric string.
+func vlanID(v any) (int, bool) {
+	switch t := v.(type) {
+	case float64:
+		return int(t), t == float64(int(t))
+	case int:
+		return t, true
+	case string:
+		n, err := strconv.Atoi(t)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+func mapField(m map[string]any, key string) map[string]any {
+	v, _ := m[key].(map[string]any)
+	return v
+}
+
+func stringList(v any) []string {
+	switch t := v.(type) {
+	case string:
+		if t == "" {
+			return nil
+		}
+		return []string{t}
+	case []string:
+		return t
+	case []any:
+		out := make([]string, 0, len(t))
+		for _, e := range t {
+			if s, ok := e.(string); ok && s != "" {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func contains(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
+
+// junosQuote quotes a value containing spaces or quote characters.
+func junosQuote(s string) string {
+	if !strings.ContainsAny(s, " \t\"';") {
+		return s
+	}
+	return strconv.Quote(s)
+}
@@ -0,0 +1,117 @@
+package mist
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRenderJunosSet(t *testing.T) {
+	cfg := map[string]any{
+		"name":        "sw-lab-01",
+		"notes":       "closet A",
+		"dns_servers": []any{"10.0.0.53"},
+		"ntp_servers": []any{"pool.ntp.org"},
+		"networks": map[string]any{
+			"corp":  map[string]any{"vlan_id": float64(10)},
+			"guest": map[string]any{"vlan_id": "20"},
+			"mgmt":  map[string]any{"vlan_id": float64(99)},
+		},
+		"ip_config": map[string]any{
+			"type": "static", "ip": "10.0.99.2", "netmask": "255.255.255.0",
+			"gateway": "10.0.99.1", "network": "mgmt",
+		},
+		"port_usages": map[string]any{
+			"ap": map[string]any{
+				"mode": "trunk", "port_network": "mgmt", "networks": []any{"corp", "guest"},
+				"stp_edge": true, "description": "AP uplink",
+			},
+			"desk": map[string]any{"mode": "access", "port_network": "corp", "poe_disabled": true},
+		},
+		"port_config": map[string]any{
+			"ge-0/0/0-1":        map[string]any{"usage": "ap"},
+			"ge-0/0/2,ge-0/0/5": map[string]any{"usage": "desk", "description": "desk ports"},
+			"xe-0/2/0-1":        map[string]any{"usage": "ap", "aggregated": true, "ae_idx": float64(0)},
+			"ge-0/0/7":          map[string]any{"usage": "phone"},
+		},
+		"extra_routes":           map[string]any{"10.50.0.0/16": map[string]any{"via": "10.0.99.254"}},
+		"additional_config_cmds": []any{"set system login message hello"},
+		"snmp_config":            map[string]any{"enabled": true},
+	}
+
+	lines, skipped := RenderJunosSet(cfg)
+	got := strings.Join(lines, "\n")
+
+	for _, want := range []string{
+		"set system host-name sw-lab-01",
+		"set system name-server 10.0.0.53",
+		"set system ntp server pool.ntp.org",
+		"set vlans corp vlan-id 10",
+		"set vlans guest vlan-id 20",
+		"set interfaces irb unit 99 family inet address 10.0.99.2/24",
+		"set routing-options static route 0.0.0.0/0 next-hop 10.0.99.1",
+		"set vlans mgmt l3-interface irb.99",
+		"set interfaces ge-0/0/1 unit 0 family ethernet-switching interface-mode trunk",
+		"set interfaces ge-0/0/1 unit 0 family ethernet-switching vlan members mgmt",
+		"set interfaces ge-0/0/1 native-vlan-id 99",
+		`set interfaces ge-0/0/1 description "AP uplink"`,
+		"set protocols rstp interface ge-0/0/0 edge",
+		`set interfaces ge-0/0/5 description "desk ports"`,
+		"set interfaces ge-0/0/5 unit 0 family ethernet-switching vlan members corp",
+		"set poe interface ge-0/0/2 disable",
+		"set interfaces xe-0/2/0 ether-options 802.3ad ae0",
+		"set interfaces xe-0/2/1 ether-options 802.3ad ae0",
+		"set interfaces ae0 aggregated-ether-options lacp active",
+		"set interfaces ae0 unit 0 family ethernet-switching interface-mode trunk",
+		"set chassis aggregated-devices ethernet device-count 1",
+		"# ge-0/0/7: port usage phone is not defined in this config",
+		"set routing-options static route 10.50.0.0/16 next-hop 10.0.99.254",
+		"set system login message hello",
+	} {
+		if !strings.Contains(got, want+"\n") && !strings.HasSuffix(got, want) {
+			t.Errorf("missing line %q in:\n%s", want, got)
+		}
+	}
+	if strings.Count(got, "set interfaces ae0 unit 0 family ethernet-switching interface-mode trunk") != 1 {
+		t.Errorf("LAG usage rendered more than once:\n%s", got)
+	}
+	if strings.Contains(got, "xe-0/2/0 unit 0") {
+		t.Errorf("LAG member got its own switching config:\n%s", got)
+	}
+	if !reflect.DeepEqual(skipped, []string{"snmp_config"}) {
+		t.Errorf("skipped = %v, want [snmp_config]", skipped)
+	}
+
+	again, _ := RenderJunosSet(cfg)
+	if !reflect.DeepEqual(lines, again) {
+		t.Error("rendering is not deterministic")
+	}
+}
+
+func TestExpandInterfaceRange(t *testing.T) {
+	tests := []struct {
+		key  string
+		want []string
+	}{
+		{"ge-0/0/3", []string{"ge-0/0/3"}},
+		{"ge-0/0/0-2", []string{"ge-0/0/0", "ge-0/0/1", "ge-0/0/2"}},
+		{"ge-0/0/1, ge-1/0/4-5", []string{"ge-0/0/1", "ge-1/0/4", "ge-1/0/5"}},
+		{"ge-0/0/5-2", []string{"ge-0/0/5-2"}},
+	}
+	for _, tt := range tests {
+		if got := expandInterfaceRange(tt.key); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("expandInterfaceRange(%q) = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestPrefixLength(t *testing.T) {
+	for in, want := range map[any]int{"255.255.255.0": 24, "/22": 22, float64(30): 30, "255.255.0.0": 16} {
+		if got, ok := prefixLength(in); !ok || got != want {
+			t.Errorf("prefixLength(%v) = %d, %v; want %d", in, got, ok, want)
+		}
+	}
+	if _, ok := prefixLength("bogus"); ok {
+		t.Error("prefixLength accepted a bogus netmask")
+	}
+}
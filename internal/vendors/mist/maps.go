@@ -0,0 +1,36 @@
+package mist
+
+import (
+	"context"
+
+	"github.com/ravinald/wifimgr/api"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+// mapsService implements vendors.MapsService for Mist using the site maps
+// endpoint.
+type mapsService struct {
+	client api.Client
+}
+
+// ListBySite returns every floor plan defined for the site.
+func (s *mapsService) ListBySite(ctx context.Context, siteID string) ([]*vendors.SiteMap, error) {
+	raw, err := s.client.GetSiteMaps(ctx, siteID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*vendors.SiteMap, 0, len(raw))
+	for _, m := range raw {
+		sm := &vendors.SiteMap{SiteID: siteID}
+		sm.ID, _ = m["id"].(string)
+		sm.Name, _ = m["name"].(string)
+		sm.Width = intFromMap(m, "width")
+		sm.Height = intFromMap(m, "height")
+		sm.PPM = floatFromMap(m, "ppm")
+		out = append(out, sm)
+	}
+	return out, nil
+}
+
+// Ensure mapsService implements vendors.MapsService at compile time.
+var _ vendors.MapsService = (*mapsService)(nil)
@@ -0,0 +1,144 @@
+package mist
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ravinald/wifimgr/api"
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+// securityEventsService implements vendors.SecurityEventsService for Mist:
+// rogue, honeypot, and spoofed APs from the site rogue events search, floods
+// and other intrusion alarms from the site's security alarms.
+type securityEventsService struct {
+	client api.Client
+}
+
+// Search returns the site's security events between start and end, oldest
+// first.
+func (s *securityEventsService) Search(ctx context.Context, siteID string, start, end time.Time) ([]*vendors.SecurityEvent, error) {
+	rogues, err := s.client.SearchSiteRogueEvents(ctx, siteID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	alarms, err := s.client.SearchSiteAlarms(ctx, siteID, "security", start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []*vendors.SecurityEvent
+	for _, r := range rogues {
+		if ev := convertRogueEvent(r, siteID); ev != nil {
+			out = append(out, ev)
+		}
+	}
+	for _, a := range alarms {
+		if ev := convertSecurityAlarm(a, siteID); ev != nil {
+			out = append(out, ev)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Timestamp.Before(out[j].Timestamp) })
+	return out, nil
+}
+
+// convertRogueEvent maps a Mist rogue event. Neighbor APs ("others") that
+// were never seen on the LAN are not threats and yield nil.
+func convertRogueEvent(raw map[string]interface{}, siteID string) *vendors.SecurityEvent {
+	ev := &vendors.SecurityEvent{SiteID: siteID}
+	ev.Type, _ = raw["type"].(string)
+	onLAN, _ := raw["seen_on_lan"].(bool)
+	switch {
+	case ev.Type == "honeypot":
+		ev.Kind, ev.Severity = vendors.SecurityEventHoneypot, 8
+	case ev.Type == "spoof":
+		ev.Kind, ev.Severity = vendors.SecurityEventSpoof, 8
+	case ev.Type == "lan" || onLAN:
+		ev.Kind, ev.Severity = vendors.SecurityEventRogue, 7
+	default:
+		return nil
+	}
+	ap, _ := raw["ap_mac"].(string)
+	ev.APMAC = vendors.NormalizeMAC(ap)
+	bssid, _ := raw["bssid"].(string)
+	ev.BSSID = vendors.NormalizeMAC(bssid)
+	ev.SSID, _ = raw["ssid"].(string)
+	ev.Channel = intFromMap(raw, "channel")
+	ev.RSSI = intFromMap(raw, "rssi")
+	ev.Timestamp = mistTimestamp(raw)
+	return ev
+}
+
+// convertSecurityAlarm maps a Mist security alarm. Rogue, honeypot, and
+// spoofing alarms repeat what the rogue events already report and yield
+// nil.
+func convertSecurityAlarm(raw map[string]interface{}, siteID string) *vendors.SecurityEvent {
+	ev := &vendors.SecurityEvent{SiteID: siteID}
+	ev.Type, _ = raw["type"].(string)
+	switch t := strings.ToLower(ev.Type); {
+	case strings.Contains(t, "rogue"), strings.Contains(t, "honeypot"), strings.Contains(t, "spoof"):
+		return nil
+	case strings.Contains(t, "flood"):
+		ev.Kind = vendors.SecurityEventAuthFlood
+	default:
+		ev.Kind = vendors.SecurityEventWIDS
+	}
+	severity, _ := raw["severity"].(string)
+	ev.Severity = alarmSeverity(severity)
+	if aps := stringsFromMap(raw, "aps"); len(aps) > 0 {
+		ev.APMAC = vendors.NormalizeMAC(aps[0])
+	}
+	if bssids := stringsFromMap(raw, "bssids"); len(bssids) > 0 {
+		ev.BSSID = vendors.NormalizeMAC(bssids[0])
+	}
+	if ssids := stringsFromMap(raw, "ssids"); len(ssids) > 0 {
+		ev.SSID = ssids[0]
+	}
+	if macs := stringsFromMap(raw, "macs"); len(macs) > 0 {
+		ev.ClientMAC = vendors.NormalizeMAC(macs[0])
+	}
+	ev.Text, _ = raw["reason"].(string)
+	ev.Timestamp = mistTimestamp(raw)
+	return ev
+}
+
+// alarmSeverity maps a Mist alarm severity onto the 0-10 CEF scale.
+func alarmSeverity(s string) int {
+	switch strings.ToLower(s) {
+	case "critical":
+		return 9
+	case "major":
+		return 7
+	case "minor", "warn":
+		return 5
+	default:
+		return 3
+	}
+}
+
+// stringsFromMap returns the string entries of a list field.
+func stringsFromMap(m map[string]interface{}, key string) []string {
+	list, _ := m[key].([]interface{})
+	out := make([]string, 0, len(list))
+	for _, v := range list {
+		if s, ok := v.(string); ok && s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// mistTimestamp converts a record's fractional epoch "timestamp".
+func mistTimestamp(raw map[string]interface{}) time.Time {
+	ts := floatFromMap(raw, "timestamp")
+	if ts <= 0 {
+		return time.Time{}
+	}
+	sec := int64(ts)
+	return time.Unix(sec, int64((ts-float64(sec))*1e9)).UTC()
+}
+
+// Ensure securityEventsService implements vendors.SecurityEventsService at compile time.
+var _ vendors.SecurityEventsService = (*securityEventsService)(nil)
@@ -0,0 +1,48 @@
+package mist
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ravinald/wifimgr/internal/vendors"
+)
+
+func TestConvertRogueEvent(t *testing.T) {
+	ev := convertRogueEvent(map[string]interface{}{
+		"type": "honeypot", "timestamp": float64(1717200000.5), "bssid": "AA:BB:CC:00:00:01",
+		"ssid": "Corp", "ap_mac": "5c5b35000001", "channel": float64(36), "rssi": float64(-61),
+	}, "site-1")
+	want := &vendors.SecurityEvent{
+		Timestamp: time.Unix(1717200000, 5e8).UTC(), Kind: vendors.SecurityEventHoneypot, Type: "honeypot",
+		Severity: 8, SiteID: "site-1", APMAC: "5c5b35000001", BSSID: "aabbcc000001", SSID: "Corp",
+		Channel: 36, RSSI: -61,
+	}
+	if *ev != *want {
+		t.Errorf("honeypot = %+v, want %+v", ev, want)
+	}
+
+	if ev := convertRogueEvent(map[string]interface{}{"type": "others", "seen_on_lan": true}, "site-1"); ev == nil || ev.Kind != vendors.SecurityEventRogue {
+		t.Errorf("neighbor seen on the LAN = %+v, want a rogue", ev)
+	}
+	if ev := convertRogueEvent(map[string]interface{}{"type": "others"}, "site-1"); ev != nil {
+		t.Errorf("plain neighbor = %+v, want nil", ev)
+	}
+}
+
+func TestConvertSecurityAlarm(t *testing.T) {
+	ev := convertSecurityAlarm(map[string]interface{}{
+		"type": "deauth_flood", "severity": "critical", "timestamp": float64(1717200000),
+		"aps": []interface{}{"5c5b35000001"}, "bssids": []interface{}{"aa:bb:cc:00:00:02"},
+		"macs": []interface{}{"11:22:33:44:55:66"},
+	}, "site-1")
+	if ev == nil || ev.Kind != vendors.SecurityEventAuthFlood || ev.Severity != 9 ||
+		ev.APMAC != "5c5b35000001" || ev.BSSID != "aabbcc000002" || ev.ClientMAC != "112233445566" {
+		t.Errorf("flood = %+v", ev)
+	}
+	if ev := convertSecurityAlarm(map[string]interface{}{"type": "adhoc_network"}, "site-1"); ev == nil || ev.Kind != vendors.SecurityEventWIDS || ev.Severity != 3 {
+		t.Errorf("other alarm = %+v", ev)
+	}
+	if ev := convertSecurityAlarm(map[string]interface{}{"type": "rogue_ap"}, "site-1"); ev != nil {
+		t.Errorf("rogue alarm = %+v, want nil (covered by rogue events)", ev)
+	}
+}
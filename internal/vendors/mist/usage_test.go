@@ -0,0 +1,37 @@
+package mist
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConvertSiteUsage(t *testing.T) {
+	start := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+	sessions := []map[string]interface{}{
+		{"mac": "aa:bb:cc:00:00:01"},
+		{"mac": "aabbcc000001"}, // same client, second session
+		{"mac": "aa-bb-cc-00-00-02"},
+		{"ap": "5c5b35000001"}, // no client MAC
+	}
+	bytes := map[string]interface{}{
+		"rt":       []interface{}{float64(1717200000), float64(1717286400), float64(1717372800)},
+		"tx_bytes": []interface{}{float64(1000), nil, float64(500)},
+		"rx_bytes": []interface{}{float64(200), nil, float64(100)},
+	}
+
+	usage := convertSiteUsage("site-1", start, end, sessions, bytes)
+	if usage.SiteID != "site-1" || !usage.Start.Equal(start) || !usage.End.Equal(end) {
+		t.Errorf("window: got %+v", usage)
+	}
+	if usage.Clients != 2 {
+		t.Errorf("Clients = %d, want 2", usage.Clients)
+	}
+	if usage.DownBytes != 1500 || usage.UpBytes != 300 || usage.TotalBytes != 1800 {
+		t.Errorf("bytes: got down %d up %d total %d", usage.DownBytes, usage.UpBytes, usage.TotalBytes)
+	}
+
+	if empty := convertSiteUsage("site-1", start, end, nil, nil); empty.Clients != 0 || empty.TotalBytes != 0 {
+		t.Errorf("no data: got %+v", empty)
+	}
+}
@@ -58,19 +58,26 @@ func NewMockClientWithAllServices(vendor, orgID string) *MockClient {
 	}
 }
 
-func (m *MockClient) Sites() SitesService               { return m.sitesService }
-func (m *MockClient) Inventory() InventoryService       { return m.inventoryService }
-func (m *MockClient) Devices() DevicesService           { return m.devicesService }
-func (m *MockClient) Search() SearchService             { return m.searchService }
-func (m *MockClient) Profiles() ProfilesService         { return m.profilesService }
-func (m *MockClient) Templates() TemplatesService       { return m.templatesService }
-func (m *MockClient) Configs() ConfigsService           { return m.configsService }
-func (m *MockClient) Statuses() StatusesService         { return m.statusesService }
-func (m *MockClient) WLANs() WLANsService               { return m.wlansService }
-func (m *MockClient) BSSIDs() BSSIDsService             { return m.bssidsService }
-func (m *MockClient) ClientDetail() ClientDetailService { return nil }
-func (m *MockClient) VendorName() string                { return m.vendor }
-func (m *MockClient) OrgID() string                     { return m.orgID }
+func (m *MockClient) Sites() SitesService                   { return m.sitesService }
+func (m *MockClient) Inventory() InventoryService           { return m.inventoryService }
+func (m *MockClient) Devices() DevicesService               { return m.devicesService }
+func (m *MockClient) Search() SearchService                 { return m.searchService }
+func (m *MockClient) Profiles() ProfilesService             { return m.profilesService }
+func (m *MockClient) Templates() TemplatesService           { return m.templatesService }
+func (m *MockClient) Configs() ConfigsService               { return m.configsService }
+func (m *MockClient) Statuses() StatusesService             { return m.statusesService }
+func (m *MockClient) WLANs() WLANsService                   { return m.wlansService }
+func (m *MockClient) BSSIDs() BSSIDsService                 { return m.bssidsService }
+func (m *MockClient) ClientDetail() ClientDetailService     { return nil }
+func (m *MockClient) DeviceStats() DeviceStatsService       { return nil }
+func (m *MockClient) Maps() MapsService                     { return nil }
+func (m *MockClient) ClientEvents() ClientEventsService     { return nil }
+func (m *MockClient) DeviceEvents() DeviceEventsService     { return nil }
+func (m *MockClient) Usage() UsageService                   { return nil }
+func (m *MockClient) SecurityEvents() SecurityEventsService { return nil }
+func (m *MockClient) Firmware() FirmwareService             { return nil }
+func (m *MockClient) VendorName() string                    { return m.vendor }
+func (m *MockClient) OrgID() string                         { return m.orgID }
 
 // SetSitesService sets a custom sites service for testing.
 func (m *MockClient) SetSitesService(svc SitesService) { m.sitesService = svc }
@@ -78,9 +85,15 @@ func (m *MockClient) SetSitesService(svc SitesService) { m.sitesService = svc }
 // SetInventoryService sets a custom inventory service for testing.
 func (m *MockClient) SetInventoryService(svc InventoryService) { m.inventoryService = svc }
 
+// SetDevicesService sets a custom devices service for testing.
+func (m *MockClient) SetDevicesService(svc DevicesService) { m.devicesService = svc }
+
 // SetSearchService sets a custom search service for testing.
 func (m *MockClient) SetSearchService(svc SearchService) { m.searchService = svc }
 
+// SetConfigsService sets a custom configs service for testing.
+func (m *MockClient) SetConfigsService(svc ConfigsService) { m.configsService = svc }
+
 // MockSitesService is a mock implementation of SitesService.
 type MockSitesService struct {
 	Sites     []*SiteInfo
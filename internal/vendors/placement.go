@@ -0,0 +1,106 @@
+package vendors
+
+import (
+	"fmt"
+	"math"
+)
+
+// PlacementTolerance is how far, in map pixels, an AP's current x or y may be
+// from its intent before it counts as moved. Floor-plan editors round dragged
+// positions, so an exact comparison would report drift nobody caused.
+const PlacementTolerance = 0.5
+
+// APPlacement is where an AP sits on a floor plan: the map and its position
+// on the map in pixels from the top-left corner. An empty MapID or a nil
+// coordinate means that part is not set.
+type APPlacement struct {
+	MapID   string   `json:"map_id,omitempty"`
+	MapName string   `json:"map_name,omitempty"`
+	X       *float64 `json:"x,omitempty"`
+	Y       *float64 `json:"y,omitempty"`
+}
+
+// IsZero reports whether nothing about the placement is set.
+func (p APPlacement) IsZero() bool {
+	return p.MapID == "" && p.MapName == "" && p.X == nil && p.Y == nil
+}
+
+// Placement returns the floor-plan placement of the intent.
+func (c *APDeviceConfig) Placement() APPlacement {
+	if c == nil {
+		return APPlacement{}
+	}
+	return APPlacement{MapID: c.MapID, MapName: c.MapName, X: c.X, Y: c.Y}
+}
+
+// PlacementFromConfig reads map_id, x, and y from a device config map, such
+// as a cached AP config.
+func PlacementFromConfig(cfg map[string]any) APPlacement {
+	var p APPlacement
+	p.MapID, _ = cfg["map_id"].(string)
+	p.MapName, _ = cfg["map_name"].(string)
+	if x, ok := cfg["x"].(float64); ok {
+		p.X = &x
+	}
+	if y, ok := cfg["y"].(float64); ok {
+		p.Y = &y
+	}
+	return p
+}
+
+// PlacementDiff is one placement field where the API differs from intent.
+type PlacementDiff struct {
+	Field   string // "map_id", "x", or "y"
+	Intent  string
+	Current string
+}
+
+// PlacementDrift compares an intended placement with the current one and
+// returns the fields that differ. Only fields set in intent are compared, so
+// an intent without a placement never drifts. A map given by name is compared
+// only when the caller has resolved it to MapID; coordinates within
+// PlacementTolerance are equal.
+func PlacementDrift(intent, current APPlacement) []PlacementDiff {
+	var diffs []PlacementDiff
+	if intent.MapID != "" && intent.MapID != current.MapID {
+		diffs = append(diffs, PlacementDiff{Field: "map_id", Intent: intent.MapID, Current: current.MapID})
+	}
+	coord := func(field string, want, got *float64) {
+		if want == nil {
+			return
+		}
+		if got == nil || math.Abs(*want-*got) > PlacementTolerance {
+			diffs = append(diffs, PlacementDiff{Field: field, Intent: formatCoord(want), Current: formatCoord(got)})
+		}
+	}
+	coord("x", intent.X, current.X)
+	coord("y", intent.Y, current.Y)
+	return diffs
+}
+
+// formatCoord renders a coordinate for a drift row; unset is empty.
+func formatCoord(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprintf("%g", *v)
+}
+
+// validatePlacement checks that x and y are given together, are not
+// negative, and are on a map; a position without a map means nothing to the
+// vendor.
+func (c *APDeviceConfig) validatePlacement() error {
+	if (c.X == nil) != (c.Y == nil) {
+		return &ConfigValidationError{Field: "x/y", Message: "x and y must be set together"}
+	}
+	if c.X == nil {
+		return nil
+	}
+	if *c.X < 0 || *c.Y < 0 {
+		return &ConfigValidationError{Field: "x/y", Message: "x and y are pixels from the map's top-left corner and cannot be negative"}
+	}
+	if c.MapID == "" && c.MapName == "" {
+		return &ConfigValidationError{Field: "x/y", Message: "x and y need map_id or map_name to place the AP on"}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package vendors
+
+import "testing"
+
+func ptrFloat(v float64) *float64 { return &v }
+
+func TestPlacementDrift(t *testing.T) {
+	intent := APPlacement{MapID: "map-1", X: ptrFloat(120), Y: ptrFloat(80)}
+
+	if diffs := PlacementDrift(intent, APPlacement{MapID: "map-1", X: ptrFloat(120.3), Y: ptrFloat(79.8)}); len(diffs) != 0 {
+		t.Errorf("within tolerance should not drift: %+v", diffs)
+	}
+
+	diffs := PlacementDrift(intent, APPlacement{MapID: "map-2", X: ptrFloat(120), Y: ptrFloat(95)})
+	if len(diffs) != 2 || diffs[0].Field != "map_id" || diffs[1].Field != "y" || diffs[1].Intent != "80" || diffs[1].Current != "95" {
+		t.Errorf("moved AP: %+v", diffs)
+	}
+
+	// A replacement AP not yet placed drifts on every field.
+	if diffs := PlacementDrift(intent, APPlacement{}); len(diffs) != 3 || diffs[1].Current != "" {
+		t.Errorf("unplaced AP: %+v", diffs)
+	}
+
+	// No placement in intent means nothing to compare.
+	if diffs := PlacementDrift(APPlacement{}, APPlacement{MapID: "map-1", X: ptrFloat(1), Y: ptrFloat(1)}); len(diffs) != 0 {
+		t.Errorf("empty intent: %+v", diffs)
+	}
+}
+
+func TestPlacementFromConfig(t *testing.T) {
+	p := PlacementFromConfig(map[string]any{"map_id": "map-1", "x": 10.5, "y": 20.0})
+	if p.MapID != "map-1" || p.X == nil || *p.X != 10.5 || p.Y == nil || *p.Y != 20 {
+		t.Errorf("got %+v", p)
+	}
+	if !PlacementFromConfig(map[string]any{"name": "AP-01"}).IsZero() {
+		t.Error("a config without placement should be zero")
+	}
+}
+
+func TestValidatePlacement(t *testing.T) {
+	cases := []struct {
+		name    string
+		cfg     APDeviceConfig
+		wantErr bool
+	}{
+		{"placed", APDeviceConfig{MapID: "map-1", X: ptrFloat(1), Y: ptrFloat(2)}, false},
+		{"placed by map name", APDeviceConfig{MapName: "Floor 2", X: ptrFloat(1), Y: ptrFloat(2)}, false},
+		{"map only", APDeviceConfig{MapID: "map-1"}, false},
+		{"x without y", APDeviceConfig{MapID: "map-1", X: ptrFloat(1)}, true},
+		{"negative", APDeviceConfig{MapID: "map-1", X: ptrFloat(-1), Y: ptrFloat(2)}, true},
+		{"no map", APDeviceConfig{X: ptrFloat(1), Y: ptrFloat(2)}, true},
+	}
+	for _, tc := range cases {
+		if err := tc.cfg.Validate(); (err != nil) != tc.wantErr {
+			t.Errorf("%s: err = %v, want error %v", tc.name, err, tc.wantErr)
+		}
+	}
+}
@@ -368,6 +368,9 @@ func (t *RadioTranslator) parseBandConfig(data map[string]any) *RadioBandConfig
 	if preamble, ok := data["preamble"].(string); ok {
 		cfg.Preamble = &preamble
 	}
+	if standardPower, ok := data["standard_power"].(bool); ok {
+		cfg.StandardPower = &standardPower
+	}
 
 	return cfg
 }
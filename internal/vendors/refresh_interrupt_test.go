@@ -0,0 +1,134 @@
+package vendors
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// cancelingConfigs cancels the refresh context after the first AP config
+// fetch, simulating Ctrl-C partway through the per-device config loop.
+type cancelingConfigs struct {
+	*MockConfigsService
+	cancel  context.CancelFunc
+	fetched int
+}
+
+func (c *cancelingConfigs) GetAPConfig(ctx context.Context, siteID, deviceID string) (*APConfig, error) {
+	c.fetched++
+	c.cancel()
+	return c.MockConfigsService.GetAPConfig(ctx, siteID, deviceID)
+}
+
+// newInterruptTestManager returns a cache manager whose "test-api" has two
+// APs, with a completed refresh whose AP configs carry a sentinel.
+func newInterruptTestManager(t *testing.T, configs ConfigsService) *CacheManager {
+	t.Helper()
+	inventory := &MockInventoryService{
+		Items: []*InventoryItem{
+			{ID: "dev-1", MAC: "aabbccddee01", Type: "ap", SiteID: "site-001"},
+			{ID: "dev-2", MAC: "aabbccddee02", Type: "ap", SiteID: "site-001"},
+		},
+		itemsByMAC: map[string]*InventoryItem{},
+		bySerial:   map[string]*InventoryItem{},
+	}
+	useConfigs := false
+	registry := NewAPIClientRegistry()
+	registry.RegisterFactory("mock", func(config *APIConfig) (Client, error) {
+		mc := NewMockClientWithAllServices(config.Vendor, config.Credentials["org_id"])
+		mc.SetInventoryService(inventory)
+		if useConfigs {
+			mc.SetConfigsService(configs)
+		}
+		return mc, nil
+	})
+	registry.InitializeClients(map[string]*APIConfig{
+		"test-api": {Label: "test-api", Vendor: "mock", Credentials: map[string]string{"org_id": "org-123"}, SyncTypes: []string{"ap"}},
+	})
+	cm := NewCacheManager(t.TempDir(), registry)
+	if err := cm.Initialize(); err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+	if err := cm.RefreshAPI(context.Background(), "test-api"); err != nil {
+		t.Fatalf("initial RefreshAPI: %v", err)
+	}
+	cache, err := cm.GetAPICache("test-api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, cfg := range cache.Configs.AP {
+		cfg.Config["sentinel"] = "prior"
+	}
+	if err := cm.SaveAPICache(cache); err != nil {
+		t.Fatal(err)
+	}
+
+	// Swap in the test's configs service for the refresh under test.
+	useConfigs = true
+	registry.InitializeClients(map[string]*APIConfig{
+		"test-api": {Label: "test-api", Vendor: "mock", Credentials: map[string]string{"org_id": "org-123"}, SyncTypes: []string{"ap"}},
+	})
+	return cm
+}
+
+func TestRefreshInterruptedDuringConfigsSavesPartial(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	configs := &cancelingConfigs{MockConfigsService: NewMockConfigsService(), cancel: cancel}
+	cm := newInterruptTestManager(t, configs)
+
+	err := cm.RefreshAPI(ctx, "test-api")
+	var intErr *InterruptedError
+	if !errors.As(err, &intErr) || !errors.Is(err, context.Canceled) {
+		t.Fatalf("RefreshAPI error = %v, want InterruptedError wrapping context.Canceled", err)
+	}
+	if configs.fetched != 1 || intErr.Done != 1 || intErr.Total != 2 || len(intErr.Pending) != 1 {
+		t.Errorf("fetched %d, Done/Total %d/%d, Pending %v; want 1 fetch, 1/2, one pending", configs.fetched, intErr.Done, intErr.Total, intErr.Pending)
+	}
+
+	got, err := cm.GetAPICache("test-api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Inventory.AP) != 2 {
+		t.Errorf("inventory has %d APs, want 2", len(got.Inventory.AP))
+	}
+	fresh, carried := 0, 0
+	for _, cfg := range got.Configs.AP {
+		if cfg.Config["sentinel"] == "prior" {
+			carried++
+		} else {
+			fresh++
+		}
+	}
+	if fresh != 1 || carried != 1 {
+		t.Errorf("configs: %d fresh, %d carried from prior cache; want 1 and 1", fresh, carried)
+	}
+	if got.Meta.LastError != "interrupted" {
+		t.Errorf("LastError = %q, want interrupted", got.Meta.LastError)
+	}
+}
+
+func TestRefreshInterruptedBeforeConfigsKeepsCache(t *testing.T) {
+	cm := newInterruptTestManager(t, NewMockConfigsService())
+	before, err := cm.GetAPICache("test-api")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = cm.RefreshAPI(ctx, "test-api")
+	var intErr *InterruptedError
+	if !errors.As(err, &intErr) || intErr.Outcome != "prior cache kept" {
+		t.Fatalf("RefreshAPI error = %v, want InterruptedError keeping the prior cache", err)
+	}
+
+	got, err := cm.GetAPICache("test-api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Meta.LastRefresh.Equal(before.Meta.LastRefresh) || len(got.Configs.AP) != 2 {
+		t.Errorf("prior cache was replaced: LastRefresh %v -> %v, %d configs", before.Meta.LastRefresh, got.Meta.LastRefresh, len(got.Configs.AP))
+	}
+}
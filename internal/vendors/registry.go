@@ -36,15 +36,31 @@ type APIConfig struct {
 	Credentials  map[string]string
 	RateLimit    int
 	ResultsLimit int
-	CacheTTL     int // Cache TTL in seconds. 0 = never expire (on-demand only), -1 = use default (86400)
+	// PageSizes overrides ResultsLimit for individual endpoints, keyed by
+	// endpoint name ("inventory", "devices", ...). Vendors ignore keys they
+	// don't paginate.
+	PageSizes map[string]int
+	// Pagination selects the pagination strategy: "auto" (default), "page",
+	// or "cursor".
+	Pagination string
+	CacheTTL   int // Cache TTL in seconds. 0 = never expire (on-demand only), -1 = use default (86400)
 	// ConnectTimeout bounds connection establishment (TCP dial + TLS handshake),
 	// not the overall request — so a dead host fails fast without capping slow
 	// but working responses. Vendor clients apply it to their transport.
 	ConnectTimeout time.Duration
+	// RefreshTimeout bounds a whole refresh of this API, through its
+	// context. Zero leaves it to the cache manager's api.refresh_timeout.
+	RefreshTimeout time.Duration
 	// SyncTypes lists the device types this API collects: any of "ap", "switch",
 	// "gateway". Empty means site attributes only — no device inventory, configs,
 	// statuses, or BSSIDs are fetched. Normalized lowercase and deduped at load.
 	SyncTypes []string
+	// Preset names the built-in sandbox/demo profile the API was configured
+	// from, if any.
+	Preset string
+	// ReadOnly marks an API whose environment rejects writes; apply shows
+	// the diff instead of pushing.
+	ReadOnly bool
 }
 
 // ShouldSync reports whether deviceType ("ap"/"switch"/"gateway") is collected
@@ -125,6 +141,44 @@ func (r *APIClientRegistry) InitializeClients(apiConfigs map[string]*APIConfig)
 	return initErrors
 }
 
+// NewClient builds a fresh client for a registered API label from its
+// original config, separate from the one the registry holds. Commands that
+// need a client set up differently from the shared one — e.g. with response
+// capture on — use it.
+func (r *APIClientRegistry) NewClient(apiLabel string) (Client, error) {
+	r.mu.RLock()
+	config, ok := r.configs[apiLabel]
+	var factory ClientFactory
+	if ok {
+		factory = r.factories[config.Vendor]
+	}
+	r.mu.RUnlock()
+	if !ok {
+		return nil, &APINotFoundError{APILabel: apiLabel}
+	}
+	if factory == nil {
+		return nil, fmt.Errorf("API %q: unsupported vendor %q", apiLabel, config.Vendor)
+	}
+	return factory(config)
+}
+
+// RegisterClient adds an already-constructed client under the given label,
+// bypassing the vendor factories. Tests use it to wire fake clients; a nil
+// config records just the label and the client's vendor and org.
+func (r *APIClientRegistry) RegisterClient(label string, client Client, config *APIConfig) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if config == nil {
+		config = &APIConfig{
+			Label:       label,
+			Vendor:      client.VendorName(),
+			Credentials: map[string]string{"org_id": client.OrgID()},
+		}
+	}
+	r.configs[label] = config
+	r.clients[label] = client
+}
+
 // GetClient returns the client for a specific API label.
 func (r *APIClientRegistry) GetClient(apiLabel string) (Client, error) {
 	r.mu.RLock()
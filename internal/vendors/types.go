@@ -266,6 +266,129 @@ type DeviceStatus struct {
 	PublicIP string `json:"public_ip,omitempty"`
 }
 
+// DeviceStats is a point-in-time statistics sample for one device. Fields a
+// vendor does not report are left zero.
+type DeviceStats struct {
+	MAC    string `json:"mac"` // normalized
+	Name   string `json:"name,omitempty"`
+	Type   string `json:"type"` // "ap", "switch", "gateway"
+	Model  string `json:"model,omitempty"`
+	SiteID string `json:"site_id"`
+
+	// Version is the running firmware version.
+	Version string `json:"version,omitempty"`
+
+	// AP power: the source the AP negotiated ("PoE 802.3at", "DC"), whether it
+	// is running constrained for lack of budget, and the vendor's description
+	// of the reduced operating mode.
+	PowerSource      string `json:"power_source,omitempty"`
+	PowerConstrained bool   `json:"power_constrained,omitempty"`
+	PowerOpMode      string `json:"power_opmode,omitempty"`
+	PowerBudgetMW    int    `json:"power_budget_mw,omitempty"` // remaining budget; negative is a deficit
+
+	// Switch PoE, summed across members/modules.
+	PoEDrawWatts float64 `json:"poe_draw_watts,omitempty"`
+	PoEMaxWatts  float64 `json:"poe_max_watts,omitempty"`
+
+	// AP wired uplink (eth0) as negotiated, with cumulative counters.
+	Eth0Up         bool  `json:"eth0_up,omitempty"`
+	Eth0SpeedMbps  int   `json:"eth0_speed_mbps,omitempty"`
+	Eth0FullDuplex bool  `json:"eth0_full_duplex,omitempty"`
+	Eth0RxPackets  int64 `json:"eth0_rx_pkts,omitempty"`
+	Eth0TxPackets  int64 `json:"eth0_tx_pkts,omitempty"`
+	Eth0RxErrors   int64 `json:"eth0_rx_errors,omitempty"`
+	Eth0TxErrors   int64 `json:"eth0_tx_errors,omitempty"`
+
+	// AP floor-plan placement (map pixels) and currently associated
+	// wireless clients.
+	MapID   string  `json:"map_id,omitempty"`
+	X       float64 `json:"x,omitempty"`
+	Y       float64 `json:"y,omitempty"`
+	Clients int     `json:"num_clients,omitempty"`
+
+	// Uptime is the seconds since the device last booted; 0 when offline or
+	// not reported.
+	Uptime int64 `json:"uptime,omitempty"`
+}
+
+// DeviceReboot is one restart a vendor recorded for a device. Reason is the
+// vendor's description of the cause ("power cycle", "upgrade") when it gives
+// one.
+type DeviceReboot struct {
+	Timestamp time.Time `json:"timestamp"`
+	MAC       string    `json:"mac"` // normalized
+	SiteID    string    `json:"site_id,omitempty"`
+	Type      string    `json:"type"` // vendor event code, e.g. "AP_RESTARTED"
+	Reason    string    `json:"reason,omitempty"`
+}
+
+// Security event kinds.
+const (
+	SecurityEventRogue     = "rogue"      // unknown AP, seen on the wired LAN
+	SecurityEventHoneypot  = "honeypot"   // unknown AP broadcasting one of our SSIDs
+	SecurityEventSpoof     = "spoof"      // unknown AP using one of our BSSIDs
+	SecurityEventAuthFlood = "auth_flood" // flood of auth, assoc, deauth, or EAPOL frames
+	SecurityEventWIDS      = "wids"       // any other wireless intrusion alarm
+)
+
+// SecurityEvent is one wireless intrusion detection event at a site. Type is
+// the vendor's own code ("honeypot", "deauth_flood"); Kind normalizes it to
+// one of the SecurityEvent constants. Severity is 0-10, as in CEF.
+type SecurityEvent struct {
+	Timestamp time.Time `json:"timestamp"`
+	Kind      string    `json:"kind"`
+	Type      string    `json:"type"`
+	Severity  int       `json:"severity"`
+	SiteID    string    `json:"site_id,omitempty"`
+	APMAC     string    `json:"ap_mac,omitempty"` // our AP that heard it, normalized
+	BSSID     string    `json:"bssid,omitempty"`  // the offending radio, normalized
+	SSID      string    `json:"ssid,omitempty"`
+	ClientMAC string    `json:"client_mac,omitempty"` // normalized
+	Channel   int       `json:"channel,omitempty"`
+	RSSI      int       `json:"rssi,omitempty"`
+	Text      string    `json:"text,omitempty"`
+}
+
+// ClientEvent is one connection event recorded for a client. Type is the
+// vendor's event code (e.g. "CLIENT_AUTH_FAILURE"); Text is its free-form
+// description when the vendor supplies one.
+type ClientEvent struct {
+	Timestamp  time.Time `json:"timestamp"`
+	Type       string    `json:"type"`
+	MAC        string    `json:"mac"`    // client, normalized
+	APMAC      string    `json:"ap_mac"` // normalized
+	SiteID     string    `json:"site_id,omitempty"`
+	SSID       string    `json:"ssid,omitempty"`
+	WLANID     string    `json:"wlan_id,omitempty"`
+	Band       string    `json:"band,omitempty"`
+	ReasonCode int       `json:"reason_code,omitempty"`
+	Text       string    `json:"text,omitempty"`
+}
+
+// SiteUsage is a site's client count and traffic over a time window.
+// TotalBytes is the site's total; DownBytes (to clients) and UpBytes (from
+// clients) split it and are zero when the vendor reports only a total.
+type SiteUsage struct {
+	SiteID     string    `json:"site_id"`
+	Start      time.Time `json:"start"`
+	End        time.Time `json:"end"`
+	Clients    int       `json:"clients"` // distinct clients seen
+	DownBytes  int64     `json:"down_bytes"`
+	UpBytes    int64     `json:"up_bytes"`
+	TotalBytes int64     `json:"total_bytes"`
+}
+
+// SiteMap is a floor plan defined for a site. Width and Height are in pixels;
+// PPM is the vendor's pixels-per-meter scale, zero when not calibrated.
+type SiteMap struct {
+	ID     string  `json:"id"`
+	Name   string  `json:"name"`
+	SiteID string  `json:"site_id"`
+	Width  int     `json:"width,omitempty"`
+	Height int     `json:"height,omitempty"`
+	PPM    float64 `json:"ppm,omitempty"`
+}
+
 // BSSIDEntry represents a single BSSID and its associated AP, SSID, and radio details.
 type BSSIDEntry struct {
 	ObjectMeta            // per-object cache freshness + apply state
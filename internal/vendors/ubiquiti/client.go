@@ -12,6 +12,7 @@ import (
 
 	"github.com/ravinald/wifimgr/internal/common"
 	"github.com/ravinald/wifimgr/internal/logging"
+	"github.com/ravinald/wifimgr/internal/runstats"
 )
 
 // Client is the HTTP client for the Ubiquiti Site Manager API.
@@ -66,6 +67,7 @@ func NewClient(apiKey, baseURL string, opts ...ClientOption) *Client {
 	for _, opt := range opts {
 		opt(c)
 	}
+	c.httpClient = runstats.InstrumentClient(c.httpClient)
 
 	return c
 }
@@ -0,0 +1,158 @@
+// Package warnings collects the non-fatal issues found during a run so they
+// can be shown together at the end instead of interleaved with progress
+// output. Identical warnings are merged and counted; the report groups them
+// by category in the order each category first appeared.
+//
+// A Collector travels on the context (NewContext / FromContext). Code that
+// runs without one still works: Add on a nil Collector prints the warning
+// immediately, as before.
+package warnings
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/ravinald/wifimgr/internal/logging"
+	"github.com/ravinald/wifimgr/internal/symbols"
+)
+
+// Warning is one distinct issue. Count is how many times it was reported.
+type Warning struct {
+	Category string `json:"category"`
+	Subject  string `json:"subject,omitempty"`
+	Message  string `json:"message"`
+	Count    int    `json:"count"`
+}
+
+// Collector accumulates warnings for one run. It is safe for concurrent use.
+type Collector struct {
+	mu    sync.Mutex
+	items []Warning
+	index map[Warning]int // key has Count zero
+}
+
+// New returns an empty Collector.
+func New() *Collector {
+	return &Collector{index: make(map[Warning]int)}
+}
+
+// Add records a warning under category about subject (a device MAC, WLAN,
+// template, or empty for the run as a whole). Every call is also written to
+// the log, so the log keeps the full sequence.
+func (c *Collector) Add(category, subject, format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	if subject != "" {
+		logging.Warnf("%s: %s: %s", category, subject, msg)
+	} else {
+		logging.Warnf("%s: %s", category, msg)
+	}
+	if c == nil {
+		if subject != "" {
+			fmt.Printf("%s %s: %s\n", symbols.WarningPrefix(), subject, msg)
+		} else {
+			fmt.Printf("%s %s\n", symbols.WarningPrefix(), msg)
+		}
+		return
+	}
+
+	key := Warning{Category: category, Subject: subject, Message: msg}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if i, ok := c.index[key]; ok {
+		c.items[i].Count++
+		return
+	}
+	c.index[key] = len(c.items)
+	key.Count = 1
+	c.items = append(c.items, key)
+}
+
+// Len returns the number of distinct warnings.
+func (c *Collector) Len() int {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.items)
+}
+
+// Since returns the distinct warnings first reported after the collector
+// held mark of them (a value from Len), grouped by category. Since(0) is
+// every warning.
+func (c *Collector) Since(mark int) []Warning {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if mark < 0 || mark > len(c.items) {
+		mark = len(c.items)
+	}
+	return group(c.items[mark:])
+}
+
+// group orders warnings by category, keeping first-seen order both across
+// and within categories.
+func group(items []Warning) []Warning {
+	if len(items) == 0 {
+		return nil
+	}
+	var order []string
+	byCategory := make(map[string][]Warning)
+	for _, w := range items {
+		if _, ok := byCategory[w.Category]; !ok {
+			order = append(order, w.Category)
+		}
+		byCategory[w.Category] = append(byCategory[w.Category], w)
+	}
+	out := make([]Warning, 0, len(items))
+	for _, cat := range order {
+		out = append(out, byCategory[cat]...)
+	}
+	return out
+}
+
+// Render writes the grouped warnings section to w. Nothing is written when
+// there are no warnings.
+func (c *Collector) Render(w io.Writer) {
+	items := c.Since(0)
+	if len(items) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintf(w, "\n%s %d warning(s):\n", symbols.WarningPrefix(), len(items))
+	category := ""
+	for _, item := range items {
+		if item.Category != category {
+			category = item.Category
+			_, _ = fmt.Fprintf(w, "  %s\n", category)
+		}
+		line := item.Message
+		if item.Subject != "" {
+			line = item.Subject + ": " + line
+		}
+		if item.Count > 1 {
+			line = fmt.Sprintf("%s (x%d)", line, item.Count)
+		}
+		_, _ = fmt.Fprintf(w, "    - %s\n", line)
+	}
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying c.
+func NewContext(ctx context.Context, c *Collector) context.Context {
+	return context.WithValue(ctx, contextKey{}, c)
+}
+
+// FromContext returns the Collector on ctx, or nil when there is none; a nil
+// Collector prints warnings as they are added.
+func FromContext(ctx context.Context) *Collector {
+	if ctx == nil {
+		return nil
+	}
+	c, _ := ctx.Value(contextKey{}).(*Collector)
+	return c
+}
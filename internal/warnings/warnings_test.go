@@ -0,0 +1,90 @@
+package warnings
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestCollectorDedupAndGroup(t *testing.T) {
+	c := New()
+	c.Add("Inventory", "ap aa0000000001", "not in local inventory file")
+	c.Add("WLANs", "corp", "WLAN template not found")
+	c.Add("Inventory", "ap aa0000000002", "not found in API inventory")
+	c.Add("Inventory", "ap aa0000000001", "not in local inventory file")
+
+	if c.Len() != 3 {
+		t.Fatalf("Len = %d, want 3 distinct warnings", c.Len())
+	}
+	got := c.Since(0)
+	wantOrder := []string{"ap aa0000000001", "ap aa0000000002", "corp"}
+	for i, w := range got {
+		if w.Subject != wantOrder[i] {
+			t.Errorf("item %d subject = %q, want %q", i, w.Subject, wantOrder[i])
+		}
+	}
+	if got[0].Count != 2 {
+		t.Errorf("duplicate count = %d, want 2", got[0].Count)
+	}
+
+	var buf bytes.Buffer
+	c.Render(&buf)
+	out := buf.String()
+	for _, want := range []string{"3 warning(s)", "  Inventory\n", "ap aa0000000001: not in local inventory file (x2)", "  WLANs\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("render missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Index(out, "WLANs") < strings.Index(out, "aa0000000002") {
+		t.Errorf("Inventory warnings should be grouped before WLANs:\n%s", out)
+	}
+}
+
+func TestCollectorSince(t *testing.T) {
+	c := New()
+	c.Add("Backup", "", "failed to update file hashes")
+	mark := c.Len()
+	c.Add("Backup", "", "failed to update file hashes") // repeat, not new
+	c.Add("Verify", "", "post-apply verify failed")
+
+	got := c.Since(mark)
+	if len(got) != 1 || got[0].Category != "Verify" {
+		t.Fatalf("Since(%d) = %+v, want only the Verify warning", mark, got)
+	}
+}
+
+func TestWarningJSON(t *testing.T) {
+	c := New()
+	c.Add("WLANs", "", "no WLAN templates could be expanded")
+	data, err := json.Marshal(c.Since(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[{"category":"WLANs","message":"no WLAN templates could be expanded","count":1}]`
+	if string(data) != want {
+		t.Errorf("json = %s, want %s", data, want)
+	}
+}
+
+func TestNilCollector(t *testing.T) {
+	c := FromContext(context.Background())
+	if c != nil {
+		t.Fatal("expected no collector on a bare context")
+	}
+	c.Add("Inventory", "", "printed immediately") // must not panic
+	if c.Len() != 0 || c.Since(0) != nil {
+		t.Error("nil collector should report no warnings")
+	}
+	var buf bytes.Buffer
+	c.Render(&buf)
+	if buf.Len() != 0 {
+		t.Errorf("nil collector rendered %q", buf.String())
+	}
+
+	stored := New()
+	if FromContext(NewContext(context.Background(), stored)) != stored {
+		t.Error("FromContext did not return the stored collector")
+	}
+}
@@ -0,0 +1,63 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/viper"
+
+	"github.com/ravinald/wifimgr/internal/integrations"
+)
+
+func init() {
+	integrations.Register(integrations.Integration{
+		Name:           "webhook",
+		ConfigKey:      "webhook",
+		EndpointKeys:   "webhook.url",
+		CredentialKeys: "webhook.token",
+		Configured:     func() bool { return viper.GetString("webhook.url") != "" },
+		Check:          check,
+		Notify:         notifyHook,
+	})
+}
+
+// check sends a HEAD request to the webhook. Deliveries are POSTs, so a 404
+// or 405 still shows the server is up; nothing is posted to the channel.
+func check(ctx context.Context) (string, error) {
+	cfg, err := LoadConfig()
+	if err != nil {
+		return "", err
+	}
+	if cfg == nil {
+		return "", errNotConfigured
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, cfg.URL, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", hideURL(err, cfg.URL)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode >= 500 {
+		return "", fmt.Errorf("HTTP %d from %s", resp.StatusCode, redactedURL(cfg.URL))
+	}
+	return redactedURL(cfg.URL), nil
+}
+
+// notifyHook relays n when its kind is selected by webhook.kinds.
+func notifyHook(ctx context.Context, n integrations.Notification) error {
+	cfg, err := LoadConfig()
+	if err != nil {
+		return err
+	}
+	if cfg == nil {
+		return errNotConfigured
+	}
+	if !cfg.Wants(n.Kind) {
+		return integrations.ErrNotHandled
+	}
+	return Send(ctx, cfg, n)
+}
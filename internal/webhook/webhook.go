@@ -0,0 +1,138 @@
+// Package webhook relays wifimgr notifications to an HTTP endpoint — a chat
+// incoming webhook, an alert manager, or a small relay of your own. Each
+// notification is POSTed as one JSON object with its kind, site, subject,
+// time, and the relay-safe details; Data, which may hold secrets such as a
+// WLAN passphrase, is never sent.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/ravinald/wifimgr/internal/config"
+	"github.com/ravinald/wifimgr/internal/integrations"
+)
+
+// DefaultTimeout bounds one delivery.
+const DefaultTimeout = 10 * time.Second
+
+// Config holds the webhook section of the main config.
+type Config struct {
+	URL     string
+	Token   string   `json:"-"` // #nosec G117 -- runtime-only, never persisted
+	Kinds   []string // notification kinds to send; empty means all
+	Timeout time.Duration
+}
+
+// LoadConfig reads webhook.* from Viper. It returns (nil, nil) when no URL is
+// set.
+func LoadConfig() (*Config, error) {
+	cfg := &Config{
+		URL:     strings.TrimSpace(viper.GetString("webhook.url")),
+		Kinds:   viper.GetStringSlice("webhook.kinds"),
+		Timeout: viper.GetDuration("webhook.timeout"),
+	}
+	if cfg.URL == "" {
+		return nil, nil
+	}
+	if v, err := config.ResolveCredential("webhook.token"); err == nil {
+		cfg.Token = v
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+	u, err := url.Parse(cfg.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, errors.New("webhook.url must be an http or https URL")
+	}
+	return cfg, nil
+}
+
+// Wants reports whether notifications of kind are sent.
+func (c *Config) Wants(kind string) bool {
+	if len(c.Kinds) == 0 {
+		return true
+	}
+	for _, k := range c.Kinds {
+		if strings.EqualFold(k, kind) {
+			return true
+		}
+	}
+	return false
+}
+
+// Payload is the JSON body of one delivery.
+type Payload struct {
+	Kind    string         `json:"kind"`
+	Site    string         `json:"site,omitempty"`
+	Subject string         `json:"subject"`
+	Time    time.Time      `json:"time"`
+	Details map[string]any `json:"details,omitempty"`
+}
+
+// Send POSTs n to the webhook. Any status other than 2xx is an error.
+func Send(ctx context.Context, cfg *Config, n integrations.Notification) error {
+	body, err := json.Marshal(Payload{
+		Kind:    n.Kind,
+		Site:    n.Site,
+		Subject: n.Subject,
+		Time:    n.Time,
+		Details: n.Details,
+	})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if cfg.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+cfg.Token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return hideURL(err, cfg.URL)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("HTTP %d from %s", resp.StatusCode, redactedURL(cfg.URL))
+	}
+	return nil
+}
+
+// redactedURL drops the path and query, which chat webhooks use as the
+// secret, from a URL shown in an error.
+func redactedURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "webhook"
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// hideURL replaces the full URL net/http puts in a request error with the
+// redacted one.
+func hideURL(err error, raw string) error {
+	var ue *url.Error
+	if errors.As(err, &ue) {
+		return fmt.Errorf("%s %s: %w", ue.Op, redactedURL(raw), ue.Err)
+	}
+	return err
+}
+
+// errNotConfigured is returned by the hook when the section vanished
+// between the Configured check and delivery.
+var errNotConfigured = errors.New("webhook.url is not set")
@@ -0,0 +1,86 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/ravinald/wifimgr/internal/integrations"
+)
+
+func TestNotifyHookPostsDetails(t *testing.T) {
+	var got Payload
+	var auth string
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		auth = r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Error(err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	t.Cleanup(viper.Reset)
+	viper.Set("webhook.url", srv.URL+"/hooks/secret-path")
+	viper.Set("webhook.token", "s3cret")
+	viper.Set("webhook.kinds", []string{integrations.NotifySLABreach})
+
+	n := integrations.Notification{
+		Kind:    integrations.NotifySLABreach,
+		Site:    "US-LAB-01",
+		Subject: "US-LAB-01: AP-01 has 52 clients (max 40)",
+		Time:    time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC),
+		Data:    struct{ Secret string }{"never sent"},
+		Details: map[string]any{"metric": "clients_per_ap"},
+	}
+	if err := notifyHook(context.Background(), n); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 || got.Site != "US-LAB-01" || got.Details["metric"] != "clients_per_ap" {
+		t.Errorf("payload = %+v after %d call(s)", got, calls)
+	}
+	if auth != "Bearer s3cret" {
+		t.Errorf("Authorization = %q", auth)
+	}
+
+	n.Kind = integrations.NotifyWLANChange
+	if err := notifyHook(context.Background(), n); !errors.Is(err, integrations.ErrNotHandled) {
+		t.Fatalf("unselected kind: err = %v", err)
+	}
+	if calls != 1 {
+		t.Error("a kind not in webhook.kinds was sent")
+	}
+}
+
+func TestSendErrorHidesURLPath(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	cfg := &Config{URL: srv.URL + "/hooks/secret-path", Timeout: time.Second}
+	err := Send(context.Background(), cfg, integrations.Notification{Kind: "test"})
+	if err == nil || !strings.Contains(err.Error(), "403") || strings.Contains(err.Error(), "secret-path") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Cleanup(viper.Reset)
+	if cfg, err := LoadConfig(); cfg != nil || err != nil {
+		t.Fatalf("no url: cfg = %v, err = %v", cfg, err)
+	}
+	viper.Set("webhook.url", "ftp://example.com/x")
+	if _, err := LoadConfig(); err == nil {
+		t.Error("non-HTTP URL accepted")
+	}
+}
@@ -1,20 +1,46 @@
 // Package xdg provides XDG Base Directory Specification support for wifimgr.
 // See https://specifications.freedesktop.org/basedir-spec/latest/
+//
+// On Windows, when the XDG variables are unset, config lives under
+// %APPDATA%\wifimgr (roaming) and cache, state, and data under
+// %LOCALAPPDATA%\wifimgr.
 package xdg
 
 import (
 	"os"
 	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
 )
 
 const appName = "wifimgr"
 
+// goos is runtime.GOOS; tests swap it to exercise the Windows layout.
+var goos = runtime.GOOS
+
+// windowsDir returns %envVar%\wifimgr[\sub] on Windows when envVar is set.
+func windowsDir(envVar string, sub ...string) (string, bool) {
+	if goos != "windows" {
+		return "", false
+	}
+	base := os.Getenv(envVar)
+	if base == "" {
+		return "", false
+	}
+	return filepath.Join(append([]string{base, appName}, sub...)...), true
+}
+
 // GetConfigDir returns the configuration directory for wifimgr.
-// Respects $XDG_CONFIG_HOME, defaults to ~/.config/wifimgr
+// Respects $XDG_CONFIG_HOME, then %APPDATA% on Windows; defaults to
+// ~/.config/wifimgr
 func GetConfigDir() string {
 	if xdgConfigHome := os.Getenv("XDG_CONFIG_HOME"); xdgConfigHome != "" {
 		return filepath.Join(xdgConfigHome, appName)
 	}
+	if dir, ok := windowsDir("APPDATA"); ok {
+		return dir
+	}
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		return filepath.Join(".", "config")
@@ -23,11 +49,15 @@ func GetConfigDir() string {
 }
 
 // GetCacheDir returns the cache directory for wifimgr.
-// Respects $XDG_CACHE_HOME, defaults to ~/.cache/wifimgr
+// Respects $XDG_CACHE_HOME, then %LOCALAPPDATA%\wifimgr\cache on Windows;
+// defaults to ~/.cache/wifimgr
 func GetCacheDir() string {
 	if xdgCacheHome := os.Getenv("XDG_CACHE_HOME"); xdgCacheHome != "" {
 		return filepath.Join(xdgCacheHome, appName)
 	}
+	if dir, ok := windowsDir("LOCALAPPDATA", "cache"); ok {
+		return dir
+	}
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		return filepath.Join(".", "cache")
@@ -36,12 +66,16 @@ func GetCacheDir() string {
 }
 
 // GetStateDir returns the state directory for wifimgr.
-// Respects $XDG_STATE_HOME, defaults to ~/.local/state/wifimgr
+// Respects $XDG_STATE_HOME, then %LOCALAPPDATA%\wifimgr\state on Windows;
+// defaults to ~/.local/state/wifimgr
 // Used for logs and backups.
 func GetStateDir() string {
 	if xdgStateHome := os.Getenv("XDG_STATE_HOME"); xdgStateHome != "" {
 		return filepath.Join(xdgStateHome, appName)
 	}
+	if dir, ok := windowsDir("LOCALAPPDATA", "state"); ok {
+		return dir
+	}
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		return filepath.Join(".", "state")
@@ -50,12 +84,16 @@ func GetStateDir() string {
 }
 
 // GetDataDir returns the data directory for wifimgr.
-// Respects $XDG_DATA_HOME, defaults to ~/.local/share/wifimgr
+// Respects $XDG_DATA_HOME, then %LOCALAPPDATA%\wifimgr\data on Windows;
+// defaults to ~/.local/share/wifimgr
 // Used for schemas and other read-only data.
 func GetDataDir() string {
 	if xdgDataHome := os.Getenv("XDG_DATA_HOME"); xdgDataHome != "" {
 		return filepath.Join(xdgDataHome, appName)
 	}
+	if dir, ok := windowsDir("LOCALAPPDATA", "data"); ok {
+		return dir
+	}
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		return filepath.Join(".", "data")
@@ -133,3 +171,41 @@ func FindEnvFile() string {
 
 	return ""
 }
+
+// windowsEnvRef matches a %VAR% reference.
+var windowsEnvRef = regexp.MustCompile(`%([A-Za-z_][A-Za-z0-9_()]*)%`)
+
+// ExpandPath expands a leading ~ to the home directory and environment
+// variable references ($VAR, ${VAR}, and on Windows %VAR%) in a configured
+// path, then cleans it. Both / and \ are accepted after ~ on Windows.
+func ExpandPath(p string) string {
+	if p == "" {
+		return p
+	}
+	if goos == "windows" {
+		p = windowsEnvRef.ReplaceAllStringFunc(p, func(ref string) string {
+			if v, ok := os.LookupEnv(strings.Trim(ref, "%")); ok {
+				return v
+			}
+			return ref
+		})
+	}
+	p = os.ExpandEnv(p)
+	if p == "~" || strings.HasPrefix(p, "~/") || (goos == "windows" && strings.HasPrefix(p, `~\`)) {
+		if home, err := os.UserHomeDir(); err == nil && home != "" {
+			p = filepath.Join(home, p[1:])
+		}
+	}
+	return filepath.Clean(p)
+}
+
+// Resolve returns a configured path, expanded, and joined to base unless it
+// is already absolute. Use it for paths that config files give relative to
+// the config directory, so "C:\..." and "/..." both work as given.
+func Resolve(base, p string) string {
+	p = ExpandPath(p)
+	if p == "" || filepath.IsAbs(p) || base == "" {
+		return p
+	}
+	return filepath.Join(base, p)
+}
@@ -296,3 +296,63 @@ func TestFindEnvFile(t *testing.T) {
 		}
 	})
 }
+
+func TestWindowsLayout(t *testing.T) {
+	orig := goos
+	goos = "windows"
+	defer func() { goos = orig }()
+
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("XDG_CACHE_HOME", "")
+	t.Setenv("XDG_STATE_HOME", "")
+	t.Setenv("XDG_DATA_HOME", "")
+	t.Setenv("APPDATA", "/users/op/AppData/Roaming")
+	t.Setenv("LOCALAPPDATA", "/users/op/AppData/Local")
+
+	tests := map[string]struct {
+		got, want string
+	}{
+		"config": {GetConfigDir(), filepath.Join("/users/op/AppData/Roaming", "wifimgr")},
+		"cache":  {GetCacheDir(), filepath.Join("/users/op/AppData/Local", "wifimgr", "cache")},
+		"state":  {GetStateDir(), filepath.Join("/users/op/AppData/Local", "wifimgr", "state")},
+		"data":   {GetDataDir(), filepath.Join("/users/op/AppData/Local", "wifimgr", "data")},
+	}
+	for name, tc := range tests {
+		if tc.got != tc.want {
+			t.Errorf("%s dir = %q, want %q", name, tc.got, tc.want)
+		}
+	}
+
+	t.Setenv("XDG_CACHE_HOME", "/tmp/xdg-cache")
+	if got := GetCacheDir(); got != filepath.Join("/tmp/xdg-cache", "wifimgr") {
+		t.Errorf("XDG_CACHE_HOME should win on Windows, got %q", got)
+	}
+
+	if got := ExpandPath("%LOCALAPPDATA%/wifimgr"); got != filepath.Join("/users/op/AppData/Local", "wifimgr") {
+		t.Errorf("ExpandPath(%%LOCALAPPDATA%%) = %q", got)
+	}
+}
+
+func TestExpandPathAndResolve(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+	t.Setenv("WIFIMGR_TEST_DIR", "/srv/wifimgr")
+
+	tests := map[string]struct {
+		got, want string
+	}{
+		"tilde":         {ExpandPath("~/.config/wifimgr"), filepath.Join(home, ".config", "wifimgr")},
+		"env":           {ExpandPath("$WIFIMGR_TEST_DIR/cache"), filepath.Join("/srv/wifimgr", "cache")},
+		"tilde not mid": {ExpandPath("/a/~/b"), filepath.Clean("/a/~/b")},
+		"relative":      {Resolve("/etc/wifimgr", "sites/us-sfo.json"), filepath.Join("/etc/wifimgr", "sites", "us-sfo.json")},
+		"absolute":      {Resolve("/etc/wifimgr", "/srv/sites/us-sfo.json"), filepath.Clean("/srv/sites/us-sfo.json")},
+		"tilde site":    {Resolve("/etc/wifimgr", "~/sites/a.json"), filepath.Join(home, "sites", "a.json")},
+	}
+	for name, tc := range tests {
+		if tc.got != tc.want {
+			t.Errorf("%s: got %q, want %q", name, tc.got, tc.want)
+		}
+	}
+}